@@ -0,0 +1,348 @@
+// Command loadgen drives realistic mixed traffic (search, item lookups,
+// material resolution and wishlist mutations) against a running API instance
+// and reports latency percentiles per operation. It is intended for capacity
+// planning and soak tests, e.g. against a server started with DEMO_MODE=true:
+//
+//	go run ./cmd/loadgen -target http://localhost:8080 -concurrency 32 -duration 2m
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+var defaultSearchTerms = "prime,excalibur,braton,forma,orokin,rifle,mod,arcane,neuroptics,systems,chassis,plastids,ferrite"
+
+type options struct {
+	target      string
+	token       string
+	concurrency int
+	duration    time.Duration
+	requests    int64
+	timeout     time.Duration
+	mix         map[string]int
+	terms       []string
+	sampleSize  int
+}
+
+func main() {
+	opts, err := parseOptions(os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "loadgen:", err)
+		os.Exit(2)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	if err := run(ctx, opts, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "loadgen:", err)
+		os.Exit(1)
+	}
+}
+
+func parseOptions(args []string) (*options, error) {
+	fs := flag.NewFlagSet("loadgen", flag.ContinueOnError)
+	opts := &options{}
+	var mix, terms string
+
+	fs.StringVar(&opts.target, "target", "http://localhost:8080", "base URL of the API server")
+	fs.StringVar(&opts.token, "token", os.Getenv("LOADGEN_TOKEN"), "bearer token for wishlist endpoints (defaults to $LOADGEN_TOKEN; not needed in demo mode)")
+	fs.IntVar(&opts.concurrency, "concurrency", 8, "number of concurrent workers")
+	fs.DurationVar(&opts.duration, "duration", 30*time.Second, "how long to generate load")
+	fs.Int64Var(&opts.requests, "requests", 0, "stop after this many requests (0 = until -duration elapses)")
+	fs.DurationVar(&opts.timeout, "timeout", 10*time.Second, "per-request timeout")
+	fs.StringVar(&mix, "mix", "search=50,item=20,materials=20,mutate=10", "relative weights of search, item, materials and mutate operations")
+	fs.StringVar(&terms, "terms", defaultSearchTerms, "comma-separated search terms")
+	fs.IntVar(&opts.sampleSize, "sample", 200, "number of item uniqueNames to sample for lookups and mutations")
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if opts.concurrency < 1 {
+		return nil, fmt.Errorf("-concurrency must be at least 1")
+	}
+	opts.target = strings.TrimRight(opts.target, "/")
+
+	var err error
+	if opts.mix, err = parseMix(mix); err != nil {
+		return nil, err
+	}
+	for _, term := range strings.Split(terms, ",") {
+		if term = strings.TrimSpace(term); term != "" {
+			opts.terms = append(opts.terms, term)
+		}
+	}
+	if len(opts.terms) == 0 {
+		return nil, fmt.Errorf("-terms must not be empty")
+	}
+	return opts, nil
+}
+
+var knownOps = map[string]bool{"search": true, "item": true, "materials": true, "mutate": true}
+
+// parseMix parses "op=weight,..." into weights, rejecting unknown operations.
+func parseMix(raw string) (map[string]int, error) {
+	mix := make(map[string]int)
+	total := 0
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, weightStr, ok := strings.Cut(part, "=")
+		if !ok || !knownOps[name] {
+			return nil, fmt.Errorf("invalid -mix entry %q (ops: search, item, materials, mutate)", part)
+		}
+		weight, err := strconv.Atoi(weightStr)
+		if err != nil || weight < 0 {
+			return nil, fmt.Errorf("invalid weight in -mix entry %q", part)
+		}
+		mix[name] = weight
+		total += weight
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("-mix must contain at least one positive weight")
+	}
+	return mix, nil
+}
+
+type client struct {
+	http   *http.Client
+	target string
+	token  string
+}
+
+// do sends a request and drains the body so connections are reused.
+func (c *client) do(ctx context.Context, method, path string, body interface{}) (int, []byte, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return 0, nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.target+path, reader)
+	if err != nil {
+		return 0, nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	return resp.StatusCode, data, err
+}
+
+// escapeUniqueName escapes each segment of a uniqueName for use in a URL path,
+// keeping the separating slashes.
+func escapeUniqueName(uniqueName string) string {
+	segments := strings.Split(uniqueName, "/")
+	for i := range segments {
+		segments[i] = url.PathEscape(segments[i])
+	}
+	return strings.Join(segments, "/")
+}
+
+// sampleUniqueNames collects item uniqueNames from search results so lookups
+// and mutations target items that exist on the server.
+func sampleUniqueNames(ctx context.Context, c *client, terms []string, size int) ([]string, error) {
+	seen := make(map[string]bool)
+	var names []string
+
+	for _, term := range terms {
+		status, data, err := c.do(ctx, http.MethodGet, "/api/v1/items/search?limit=100&q="+url.QueryEscape(term), nil)
+		if err != nil {
+			return nil, err
+		}
+		if status != http.StatusOK {
+			return nil, fmt.Errorf("search for %q returned status %d", term, status)
+		}
+
+		var result struct {
+			Items []struct {
+				UniqueName string `json:"uniqueName"`
+			} `json:"items"`
+		}
+		if err := json.Unmarshal(data, &result); err != nil {
+			return nil, fmt.Errorf("decode search results: %w", err)
+		}
+		for _, item := range result.Items {
+			if !seen[item.UniqueName] {
+				seen[item.UniqueName] = true
+				names = append(names, item.UniqueName)
+			}
+		}
+		if len(names) >= size {
+			return names[:size], nil
+		}
+	}
+
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no items found for search terms %v", terms)
+	}
+	return names, nil
+}
+
+// worker holds per-worker state. Mutations walk a small add -> update ->
+// remove cycle over the worker's own slice of the sample, so concurrent
+// workers rarely conflict on the same wishlist entry.
+type worker struct {
+	id     int
+	client *client
+	rec    *recorder
+	rnd    *rand.Rand
+	opts   *options
+	names  []string
+	owned  []string
+	cursor int
+	step   int
+}
+
+func (w *worker) pickOp(ops []string, weights []int, total int) string {
+	n := w.rnd.Intn(total)
+	for i, weight := range weights {
+		if n < weight {
+			return ops[i]
+		}
+		n -= weight
+	}
+	return ops[len(ops)-1]
+}
+
+func (w *worker) timed(ctx context.Context, op, method, path string, body interface{}) {
+	reqCtx, cancel := context.WithTimeout(ctx, w.opts.timeout)
+	defer cancel()
+
+	start := time.Now()
+	status, _, err := w.client.do(reqCtx, method, path, body)
+	if err != nil && ctx.Err() != nil {
+		// The run ended mid-request; don't count it.
+		return
+	}
+	w.rec.record(op, time.Since(start), status, err)
+}
+
+func (w *worker) runOp(ctx context.Context, op string) {
+	switch op {
+	case "search":
+		term := w.opts.terms[w.rnd.Intn(len(w.opts.terms))]
+		w.timed(ctx, "search", http.MethodGet, "/api/v1/items/search?limit=20&q="+url.QueryEscape(term), nil)
+	case "item":
+		name := w.names[w.rnd.Intn(len(w.names))]
+		w.timed(ctx, "item", http.MethodGet, "/api/v1/items"+escapeUniqueName(name), nil)
+	case "materials":
+		w.timed(ctx, "materials", http.MethodGet, "/api/v1/wishlist/materials", nil)
+	case "mutate":
+		w.mutate(ctx)
+	}
+}
+
+func (w *worker) mutate(ctx context.Context) {
+	switch w.step {
+	case 0:
+		name := w.names[(w.id+w.cursor*w.opts.concurrency)%len(w.names)]
+		w.cursor++
+		w.owned = append(w.owned, name)
+		w.timed(ctx, "add", http.MethodPost, "/api/v1/wishlist", map[string]interface{}{"uniqueName": name, "quantity": 1})
+	case 1:
+		name := w.owned[len(w.owned)-1]
+		w.timed(ctx, "update", http.MethodPatch, "/api/v1/wishlist"+escapeUniqueName(name), map[string]int{"quantity": 1 + w.rnd.Intn(5)})
+	case 2:
+		name := w.owned[len(w.owned)-1]
+		w.owned = w.owned[:len(w.owned)-1]
+		w.timed(ctx, "remove", http.MethodDelete, "/api/v1/wishlist"+escapeUniqueName(name), nil)
+	}
+	w.step = (w.step + 1) % 3
+}
+
+func run(ctx context.Context, opts *options, out io.Writer) error {
+	c := &client{
+		http: &http.Client{
+			Transport: &http.Transport{
+				MaxIdleConns:        opts.concurrency,
+				MaxIdleConnsPerHost: opts.concurrency,
+			},
+		},
+		target: opts.target,
+		token:  opts.token,
+	}
+
+	fmt.Fprintf(out, "sampling items from %s ...\n", opts.target)
+	names, err := sampleUniqueNames(ctx, c, opts.terms, opts.sampleSize)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "sampled %d items; running %d workers for %s\n\n", len(names), opts.concurrency, opts.duration)
+
+	var ops []string
+	var weights []int
+	total := 0
+	for _, op := range []string{"search", "item", "materials", "mutate"} {
+		if weight := opts.mix[op]; weight > 0 {
+			ops = append(ops, op)
+			weights = append(weights, weight)
+			total += weight
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, opts.duration)
+	defer cancel()
+
+	rec := newRecorder()
+	var issued atomic.Int64
+	var wg sync.WaitGroup
+	start := time.Now()
+
+	for i := 0; i < opts.concurrency; i++ {
+		w := &worker{
+			id:     i,
+			client: c,
+			rec:    rec,
+			rnd:    rand.New(rand.NewSource(time.Now().UnixNano() + int64(i))),
+			opts:   opts,
+			names:  names,
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				if opts.requests > 0 && issued.Add(1) > opts.requests {
+					return
+				}
+				w.runOp(ctx, w.pickOp(ops, weights, total))
+			}
+		}()
+	}
+
+	wg.Wait()
+	rec.report(out, time.Since(start))
+	return nil
+}
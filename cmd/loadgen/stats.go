@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// opStats collects latencies and status codes for one operation.
+type opStats struct {
+	latencies []time.Duration
+	statuses  map[int]int
+	errors    int
+}
+
+// recorder is shared by all workers.
+type recorder struct {
+	mu  sync.Mutex
+	ops map[string]*opStats
+}
+
+func newRecorder() *recorder {
+	return &recorder{ops: make(map[string]*opStats)}
+}
+
+// record stores one request outcome. status is 0 when the request failed
+// before a response was received.
+func (r *recorder) record(op string, latency time.Duration, status int, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.ops[op]
+	if !ok {
+		s = &opStats{statuses: make(map[int]int)}
+		r.ops[op] = s
+	}
+	s.latencies = append(s.latencies, latency)
+	if err != nil {
+		s.errors++
+		return
+	}
+	s.statuses[status]++
+}
+
+// percentile returns the nearest-rank percentile (0-100) of sorted durations.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(p/100*float64(len(sorted)) + 0.5)
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(sorted) {
+		rank = len(sorted)
+	}
+	return sorted[rank-1]
+}
+
+// report writes a per-operation latency table followed by status code counts.
+func (r *recorder) report(w io.Writer, elapsed time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	names := make([]string, 0, len(r.ops))
+	total := 0
+	for name, s := range r.ops {
+		names = append(names, name)
+		total += len(s.latencies)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintf(w, "%d requests in %s (%.1f req/s)\n\n", total, elapsed.Round(time.Millisecond), float64(total)/elapsed.Seconds())
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "op\tcount\terrors\tp50\tp90\tp95\tp99\tmax\t")
+	for _, name := range names {
+		s := r.ops[name]
+		sorted := append([]time.Duration(nil), s.latencies...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%s\t%s\t%s\t%s\t%s\t\n",
+			name, len(sorted), s.errors,
+			formatLatency(percentile(sorted, 50)),
+			formatLatency(percentile(sorted, 90)),
+			formatLatency(percentile(sorted, 95)),
+			formatLatency(percentile(sorted, 99)),
+			formatLatency(percentile(sorted, 100)),
+		)
+	}
+	tw.Flush()
+
+	fmt.Fprintln(w, "\nstatus codes:")
+	for _, name := range names {
+		s := r.ops[name]
+		codes := make([]int, 0, len(s.statuses))
+		for code := range s.statuses {
+			codes = append(codes, code)
+		}
+		sort.Ints(codes)
+		fmt.Fprintf(w, "  %s:", name)
+		for _, code := range codes {
+			fmt.Fprintf(w, " %d=%d", code, s.statuses[code])
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+func formatLatency(d time.Duration) string {
+	return fmt.Sprintf("%.1fms", float64(d)/float64(time.Millisecond))
+}
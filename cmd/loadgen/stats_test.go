@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPercentile(t *testing.T) {
+	var sorted []time.Duration
+	for i := 1; i <= 100; i++ {
+		sorted = append(sorted, time.Duration(i)*time.Millisecond)
+	}
+
+	tests := []struct {
+		p        float64
+		expected time.Duration
+	}{
+		{p: 50, expected: 50 * time.Millisecond},
+		{p: 90, expected: 90 * time.Millisecond},
+		{p: 99, expected: 99 * time.Millisecond},
+		{p: 100, expected: 100 * time.Millisecond},
+		{p: 0, expected: 1 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		if got := percentile(sorted, tt.p); got != tt.expected {
+			t.Errorf("p%.0f: expected %s, got %s", tt.p, tt.expected, got)
+		}
+	}
+
+	if got := percentile(nil, 50); got != 0 {
+		t.Errorf("expected 0 for empty input, got %s", got)
+	}
+}
+
+func TestRecorder_Report(t *testing.T) {
+	rec := newRecorder()
+	rec.record("search", 10*time.Millisecond, 200, nil)
+	rec.record("search", 30*time.Millisecond, 200, nil)
+	rec.record("search", 20*time.Millisecond, 503, nil)
+	rec.record("materials", 5*time.Millisecond, 0, errors.New("connection refused"))
+
+	var buf bytes.Buffer
+	rec.report(&buf, time.Second)
+	out := buf.String()
+
+	for _, want := range []string{"4 requests", "search: 200=2 503=1", "30.0ms"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected report to contain %q, got:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+// Command migrate-items consolidates the per-category item collections that
+// cmd/sync writes into the single items collection read under
+// ITEM_STORAGE_LAYOUT=unified. It copies every category, tagged with its
+// collection name, and creates the unified indexes; the old collections are
+// left in place, so servers can be switched over one at a time:
+//
+//	go run ./cmd/migrate-items -mongo-uri mongodb://localhost:27017
+//
+// Re-running it replaces the copies it made before. Once every server reads
+// the unified layout, run cmd/sync with -layout unified from then on.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/graytonio/warframe-wishlist/internal/database"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+)
+
+type options struct {
+	mongoURI string
+	database string
+	dryRun   bool
+}
+
+func main() {
+	opts, err := parseOptions(os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "migrate-items:", err)
+		os.Exit(2)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	if err := run(ctx, opts, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "migrate-items:", err)
+		os.Exit(1)
+	}
+}
+
+func parseOptions(args []string) (*options, error) {
+	fs := flag.NewFlagSet("migrate-items", flag.ContinueOnError)
+	opts := &options{}
+
+	fs.StringVar(&opts.mongoURI, "mongo-uri", getEnv("MONGO_URI", "mongodb://localhost:27017"), "MongoDB connection URI (defaults to $MONGO_URI)")
+	fs.StringVar(&opts.database, "database", getEnv("MONGO_DATABASE", "warframe"), "MongoDB database name (defaults to $MONGO_DATABASE)")
+	fs.BoolVar(&opts.dryRun, "dry-run", false, "report the item counts without copying")
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+	return opts, nil
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func run(ctx context.Context, opts *options, out io.Writer) error {
+	db, err := database.NewMongoDB(opts.mongoURI, opts.database)
+	if err != nil {
+		return fmt.Errorf("connect to MongoDB: %w", err)
+	}
+	defer db.Close()
+
+	fmt.Fprintf(out, "Database: %s\n", opts.database)
+	if opts.dryRun {
+		fmt.Fprintln(out, "DRY RUN MODE - no changes will be made")
+	}
+	fmt.Fprintln(out)
+
+	stats, err := repository.ConsolidateItems(ctx, db, opts.dryRun)
+	report(out, stats, opts.dryRun)
+	if err != nil {
+		return err
+	}
+	return checkCounts(stats, opts.dryRun)
+}
+
+// report prints each category's item counts and their totals.
+func report(out io.Writer, stats []repository.ConsolidationStats, dryRun bool) {
+	column := repository.UnifiedItemsCollection
+	if dryRun {
+		column += " (now)"
+	}
+	fmt.Fprintf(out, "%-16s %8s %14s\n", "collection", "items", column)
+	source, unified := 0, 0
+	for _, stat := range stats {
+		fmt.Fprintf(out, "%-16s %8d %14d\n", stat.Category, stat.Source, stat.Unified)
+		source += stat.Source
+		unified += stat.Unified
+	}
+	fmt.Fprintf(out, "\nCollections: %d, items: %d, in %s: %d\n", len(stats), source, repository.UnifiedItemsCollection, unified)
+}
+
+// checkCounts fails a migration that left a category with fewer items than
+// its collection. More is fine: items archived upstream stay in both.
+func checkCounts(stats []repository.ConsolidationStats, dryRun bool) error {
+	if dryRun {
+		return nil
+	}
+	var short []string
+	for _, stat := range stats {
+		if stat.Unified < stat.Source {
+			short = append(short, fmt.Sprintf("%s (%d of %d)", stat.Category, stat.Unified, stat.Source))
+		}
+	}
+	if len(short) > 0 {
+		return fmt.Errorf("items missing after the copy: %s", strings.Join(short, ", "))
+	}
+	return nil
+}
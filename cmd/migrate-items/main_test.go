@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+)
+
+func TestParseOptions(t *testing.T) {
+	opts, err := parseOptions([]string{"-database", "staging", "-dry-run"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts.database != "staging" || !opts.dryRun {
+		t.Errorf("unexpected options %+v", opts)
+	}
+
+	if _, err := parseOptions([]string{"extra"}); err == nil {
+		t.Error("expected error for unexpected arguments")
+	}
+}
+
+func TestReport(t *testing.T) {
+	var out bytes.Buffer
+	report(&out, []repository.ConsolidationStats{
+		{Category: "warframes", Source: 3, Unified: 3},
+		{Category: "relics", Source: 2, Unified: 2},
+	}, false)
+
+	if !strings.Contains(out.String(), "Collections: 2, items: 5, in items: 5") {
+		t.Errorf("expected totals, got:\n%s", out.String())
+	}
+}
+
+func TestCheckCounts(t *testing.T) {
+	stats := []repository.ConsolidationStats{
+		{Category: "warframes", Source: 3, Unified: 4},
+		{Category: "relics", Source: 2, Unified: 1},
+	}
+
+	err := checkCounts(stats, false)
+	if err == nil || !strings.Contains(err.Error(), "relics (1 of 2)") || strings.Contains(err.Error(), "warframes") {
+		t.Errorf("expected only relics to be reported short, got %v", err)
+	}
+	if err := checkCounts(stats, true); err != nil {
+		t.Errorf("expected a dry run to pass, got %v", err)
+	}
+}
@@ -2,107 +2,1229 @@ package main
 
 import (
 	"context"
+	"flag"
 	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	chimiddleware "github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
+	"github.com/graytonio/warframe-wishlist/internal/audit"
+	"github.com/graytonio/warframe-wishlist/internal/buildinfo"
+	"github.com/graytonio/warframe-wishlist/internal/cdn"
 	"github.com/graytonio/warframe-wishlist/internal/config"
 	"github.com/graytonio/warframe-wishlist/internal/database"
+	"github.com/graytonio/warframe-wishlist/internal/deprecation"
 	"github.com/graytonio/warframe-wishlist/internal/handlers"
+	"github.com/graytonio/warframe-wishlist/internal/hooks"
+	"github.com/graytonio/warframe-wishlist/internal/ids"
 	"github.com/graytonio/warframe-wishlist/internal/middleware"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/notify"
+	"github.com/graytonio/warframe-wishlist/internal/realtime"
+	"github.com/graytonio/warframe-wishlist/internal/redis"
 	"github.com/graytonio/warframe-wishlist/internal/repository"
+	"github.com/graytonio/warframe-wishlist/internal/repository/memory"
 	"github.com/graytonio/warframe-wishlist/internal/services"
+	"github.com/graytonio/warframe-wishlist/internal/storage"
 	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"github.com/graytonio/warframe-wishlist/pkg/response"
 )
 
 func main() {
+	selfTestFlag := flag.Bool("selftest", false, "check config, MongoDB, indexes and JWT keys, then exit")
+	flag.Parse()
+
 	cfg := config.Load()
 
+	if *selfTestFlag {
+		if !newSelfTest(cfg).Run(context.Background(), os.Stdout) {
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Initialize logger with configured level (debug mode inferred from level)
 	logger.Init(cfg.LogLevel)
+	if cfg.LogAnonymize {
+		logger.EnableAnonymization(cfg.LogAnonymizeKey)
+	}
 
 	ctx := context.Background()
+	build := buildinfo.Get()
 	logger.Info(ctx, "starting warframe-wishlist API server",
+		"version", build.Version,
+		"commit", build.Commit,
 		"logLevel", cfg.LogLevel,
+		"anonymized", cfg.LogAnonymize,
 	)
+	if cfg.LogAnonymize && cfg.LogAnonymizeKey == "" {
+		logger.Warn(ctx, "LOG_ANONYMIZE is on without LOG_ANONYMIZE_KEY; hashed user IDs can be recomputed from known IDs")
+	}
+
+	idGenerator, err := ids.Parse(cfg.IDStrategy)
+	if err != nil {
+		logger.Error(ctx, "invalid ID_STRATEGY", "error", err)
+		os.Exit(1)
+	}
+
+	if cfg.AuditSink != "" {
+		sink, err := audit.NewSink(audit.SinkConfig{
+			Type:          cfg.AuditSink,
+			SyslogNetwork: cfg.AuditSyslogNetwork,
+			SyslogAddress: cfg.AuditSyslogAddress,
+			HTTPURL:       cfg.AuditHTTPURL,
+			HTTPToken:     cfg.AuditHTTPToken,
+		})
+		if err != nil {
+			logger.Error(ctx, "invalid audit configuration", "error", err)
+			os.Exit(1)
+		}
+		forwarder := audit.NewForwarder(sink, cfg.AuditBufferSize, cfg.AuditBatchSize, time.Duration(cfg.AuditFlushIntervalSeconds)*time.Second)
+		audit.SetDefault(forwarder)
+		defer func() {
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			if err := forwarder.Close(shutdownCtx); err != nil {
+				logger.Error(ctx, "failed to flush audit events", "error", err)
+			}
+		}()
+		logger.Info(ctx, "audit forwarding enabled", "sink", cfg.AuditSink)
+	}
+
+	if cfg.HooksWebhookURL != "" {
+		if u, err := url.Parse(cfg.HooksWebhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			logger.Error(ctx, "invalid HOOKS_WEBHOOK_URL; it must be an absolute http or https URL")
+			os.Exit(1)
+		}
+		bridge := hooks.NewWebhookBridge(cfg.HooksWebhookURL, cfg.HooksWebhookSecret)
+		bridge.SetIDGenerator(idGenerator)
+		hooks.Register("webhook-bridge", hooks.AllEvents, bridge.Hook)
+		go bridge.Run(ctx)
+		logger.Info(ctx, "extension hook webhook bridge enabled")
+	}
 
-	logger.Debug(ctx, "connecting to MongoDB", "uri", cfg.MongoURI, "database", cfg.MongoDatabase)
-	db, err := database.NewMongoDB(cfg.MongoURI, cfg.MongoDatabase)
+	trustedProxies, err := middleware.ParseTrustedProxies(cfg.TrustedProxies)
 	if err != nil {
-		logger.Error(ctx, "failed to connect to MongoDB", "error", err)
+		logger.Error(ctx, "invalid TRUSTED_PROXIES", "error", err)
 		os.Exit(1)
 	}
-	defer db.Close()
 
-	logger.Info(ctx, "connected to MongoDB")
+	// A read region's database is a replica that cannot take writes, so it
+	// forwards API writes to the primary region and skips every background
+	// job that writes; the primary runs those for all regions.
+	var regionForwarder *middleware.RegionForwarder
+	if cfg.PrimaryRegionURL != "" {
+		primaryURL, err := url.Parse(cfg.PrimaryRegionURL)
+		if err != nil || primaryURL.Scheme == "" || primaryURL.Host == "" {
+			logger.Error(ctx, "PRIMARY_REGION_URL must be an absolute URL", "url", cfg.PrimaryRegionURL)
+			os.Exit(1)
+		}
+		if cfg.KioskMode {
+			logger.Warn(ctx, "PRIMARY_REGION_URL is ignored in kiosk mode")
+		} else {
+			regionForwarder = middleware.NewRegionForwarder(cfg.Region, primaryURL, time.Duration(cfg.RegionStickySeconds)*time.Second)
+			logger.Info(ctx, "read region, forwarding writes to the primary region", "region", cfg.Region, "primary", primaryURL.Host, "stickySeconds", cfg.RegionStickySeconds)
+		}
+	}
+	writesLocally := !cfg.KioskMode && regionForwarder == nil
+
+	var (
+		itemRepo         repository.ItemRepositoryInterface
+		wishlistRepo     repository.WishlistRepositoryInterface
+		popularity       repository.PopularityRepositoryInterface
+		ownedBPRepo      repository.OwnedBlueprintsRepositoryInterface
+		ownedMatRepo     repository.OwnedMaterialsRepositoryInterface
+		masteryRepo      repository.MasteryRepositoryInterface
+		settingsRepo     repository.SettingsRepositoryInterface
+		userTraceRepo    repository.UserTraceRepositoryInterface
+		householdRepo    repository.HouseholdRepositoryInterface
+		giftClaimRepo    repository.GiftClaimRepositoryInterface
+		idempotencyRepo  repository.IdempotencyRepositoryInterface
+		shareLinkRepo    repository.ShareLinkRepositoryInterface
+		reportRepo       repository.ShareLinkReportRepositoryInterface
+		importAliasRepo  repository.ImportAliasRepositoryInterface
+		customItemRepo   repository.CustomItemRepositoryInterface
+		foundryRepo      repository.FoundryRepositoryInterface
+		templateRepo     repository.WishlistTemplateRepositoryInterface
+		notificationRepo repository.NotificationRepositoryInterface
+		integrationRepo  repository.IntegrationRepositoryInterface
+		workspaceRepo    repository.WorkspaceRepositoryInterface
+		contributionRepo repository.WorkspaceContributionRepositoryInterface
+		activityRepo     repository.ActivityRepositoryInterface
+		itemCatalog      repository.ItemCatalogInterface
+		relicCatalog     repository.RelicCatalogInterface
+		itemExport       repository.ItemExportInterface
+		itemGraph        repository.ItemGraphInterface
+		itemGraphRebuild func(ctx context.Context) error
+		itemChangeRepo   repository.ItemChangeRepositoryInterface
+		syncStatusRepo   repository.SyncStatusRepositoryInterface
+		itemSyncer       repository.ItemSyncerInterface
+		searchStats      *repository.SearchStats
+		dbTopology       handlers.DatabaseTopology
+	)
+
+	searchScope, err := repository.ParseSearchScope(cfg.ItemSearchExcludedCollections)
+	if err != nil {
+		logger.Error(ctx, "invalid ITEM_SEARCH_EXCLUDED_COLLECTIONS", "error", err)
+		os.Exit(1)
+	}
+
+	itemLayout, err := repository.ParseItemLayout(cfg.ItemStorageLayout)
+	if err != nil {
+		logger.Error(ctx, "invalid ITEM_STORAGE_LAYOUT", "error", err)
+		os.Exit(1)
+	}
+
+	deprecations, err := deprecation.NewTracker(cfg.DeprecationsAnnounced)
+	if err != nil {
+		logger.Error(ctx, "invalid DEPRECATIONS_ANNOUNCED", "error", err)
+		os.Exit(1)
+	}
+
+	starterItems, err := services.ParseStarterItems(cfg.OnboardingStarterItems)
+	if err != nil {
+		logger.Error(ctx, "invalid ONBOARDING_STARTER_ITEMS", "error", err)
+		os.Exit(1)
+	}
+
+	var faultInjector *middleware.FaultInjector
+	if cfg.FaultInjectionRules != "" {
+		if cfg.Production() {
+			logger.Error(ctx, "FAULT_INJECTION_RULES is not allowed in production; set APP_ENV to a non-production environment")
+			os.Exit(1)
+		}
+		faultRules, err := middleware.ParseFaultRules(cfg.FaultInjectionRules)
+		if err != nil {
+			logger.Error(ctx, "invalid FAULT_INJECTION_RULES", "error", err)
+			os.Exit(1)
+		}
+		logger.Warn(ctx, "fault injection enabled", "environment", cfg.Environment, "rules", len(faultRules))
+		faultInjector = middleware.NewFaultInjector(faultRules)
+	}
+
+	if cfg.DemoMode {
+		logger.Info(ctx, "demo mode enabled, using in-memory repositories", "dataDir", cfg.DemoDataDir, "userID", cfg.DemoUserID)
+		memItemRepo := memory.NewItemRepository()
+		memItemRepo.SetSearchScope(searchScope)
+		count, err := memory.LoadItemsFromDir(memItemRepo, cfg.DemoDataDir)
+		if err != nil {
+			logger.Error(ctx, "failed to load demo item data", "error", err)
+			os.Exit(1)
+		}
+		logger.Info(ctx, "loaded demo item data", "itemCount", count)
+
+		memWishlistRepo := memory.NewWishlistRepository()
+		itemRepo = memItemRepo
+		itemCatalog = memItemRepo
+		relicCatalog = memItemRepo
+		itemExport = memItemRepo
+		itemGraph = memItemRepo
+		wishlistRepo = memWishlistRepo
+		popularity = memWishlistRepo
+		ownedBPRepo = memory.NewOwnedBlueprintsRepository()
+		ownedMatRepo = memory.NewOwnedMaterialsRepository()
+		masteryRepo = memory.NewMasteryRepository()
+		settingsRepo = memory.NewSettingsRepository()
+		userTraceRepo = memory.NewUserTraceRepository()
+		householdRepo = memory.NewHouseholdRepository()
+		giftClaimRepo = memory.NewGiftClaimRepository()
+		idempotencyRepo = memory.NewIdempotencyRepository()
+		shareLinkRepo = memory.NewShareLinkRepository()
+		reportRepo = memory.NewShareLinkReportRepository()
+		importAliasRepo = memory.NewImportAliasRepository()
+		customItemRepo = memory.NewCustomItemRepository()
+		foundryRepo = memory.NewFoundryRepository()
+		templateRepo = memory.NewWishlistTemplateRepository()
+		notificationRepo = memory.NewNotificationRepository()
+		integrationRepo = memory.NewIntegrationRepository()
+		workspaceRepo = memory.NewWorkspaceRepository()
+		contributionRepo = memory.NewWorkspaceContributionRepository()
+		activityRepo = memory.NewActivityRepository()
+		itemChangeRepo = memory.NewItemChangeRepository()
+		syncStatusRepo = memory.NewSyncStatusRepository()
+	} else {
+		logger.Debug(ctx, "connecting to MongoDB", "uri", cfg.MongoURI, "database", cfg.MongoDatabase)
+		db, err := database.NewMongoDB(cfg.MongoURI, cfg.MongoDatabase)
+		if err != nil {
+			logger.Error(ctx, "failed to connect to MongoDB", "error", err)
+			os.Exit(1)
+		}
+		defer db.Close()
+		dbTopology = db
+
+		logger.Info(ctx, "connected to MongoDB")
+
+		logger.Debug(ctx, "initializing repositories")
+		mongoWishlistRepo := repository.NewWishlistRepository(db)
+		mongoItemRepo := repository.NewItemRepository(db)
+		mongoItemRepo.SetLayout(itemLayout)
+		mongoItemRepo.SetSearchScope(searchScope)
+		mongoItemRepo.SetSearchTimeout(time.Duration(cfg.ItemSearchTimeoutMs) * time.Millisecond)
+		if cfg.ItemSearchStatsSize > 0 && itemLayout == repository.ItemLayoutCollections {
+			searchStats = repository.NewSearchStats(cfg.ItemSearchStatsSize, time.Duration(cfg.ItemSearchStatsTTLSeconds)*time.Second)
+			mongoItemRepo.SetSearchStats(searchStats)
+		}
+		itemRepo = mongoItemRepo
+		itemCatalog = mongoItemRepo
+		relicCatalog = mongoItemRepo
+		itemExport = mongoItemRepo
+		itemGraph = mongoItemRepo
+		itemGraphRebuild = mongoItemRepo.RebuildItemGraph
+		wishlistRepo = mongoWishlistRepo
+		popularity = mongoWishlistRepo
+		ownedBPRepo = repository.NewOwnedBlueprintsRepository(db)
+		ownedMatRepo = repository.NewOwnedMaterialsRepository(db)
+		mongoMasteryRepo := repository.NewMasteryRepository(db)
+		masteryRepo = mongoMasteryRepo
+		settingsRepo = repository.NewSettingsRepository(db)
+		userTraceRepo = repository.NewUserTraceRepository(db)
+		householdRepo = repository.NewHouseholdRepository(db)
+		mongoGiftClaimRepo := repository.NewGiftClaimRepository(db)
+		giftClaimRepo = mongoGiftClaimRepo
+		mongoIdempotencyRepo := repository.NewIdempotencyRepository(db)
+		idempotencyRepo = mongoIdempotencyRepo
+		mongoShareLinkRepo := repository.NewShareLinkRepository(db)
+		shareLinkRepo = mongoShareLinkRepo
+		mongoReportRepo := repository.NewShareLinkReportRepository(db)
+		reportRepo = mongoReportRepo
+		importAliasRepo = repository.NewImportAliasRepository(db)
+		mongoCustomItemRepo := repository.NewCustomItemRepository(db)
+		customItemRepo = mongoCustomItemRepo
+		mongoFoundryRepo := repository.NewFoundryRepository(db)
+		foundryRepo = mongoFoundryRepo
+		mongoTemplateRepo := repository.NewWishlistTemplateRepository(db)
+		templateRepo = mongoTemplateRepo
+		mongoNotificationRepo := repository.NewNotificationRepository(db)
+		notificationRepo = mongoNotificationRepo
+		mongoIntegrationRepo := repository.NewIntegrationRepository(db)
+		integrationRepo = mongoIntegrationRepo
+		mongoWorkspaceRepo := repository.NewWorkspaceRepository(db)
+		workspaceRepo = mongoWorkspaceRepo
+		mongoContributionRepo := repository.NewWorkspaceContributionRepository(db)
+		contributionRepo = mongoContributionRepo
+		mongoActivityRepo := repository.NewActivityRepository(db)
+		activityRepo = mongoActivityRepo
+		itemChangeRepo = repository.NewItemChangeRepository(db)
+		syncStatusRepo = repository.NewSyncStatusRepository(db)
+		mongoItemSyncer := repository.NewItemSyncer(db)
+		mongoItemSyncer.SetLayout(itemLayout)
+		itemSyncer = mongoItemSyncer
+
+		if cfg.SchemaMigrationEnabled && writesLocally {
+			migrator := repository.NewSchemaMigrator(db)
+			go func() {
+				if _, err := migrator.Run(ctx); err != nil {
+					logger.Error(ctx, "background schema migration failed", "error", err)
+				}
+			}()
+		}
+
+		// Search needs the text indexes; cmd/sync creates them too, this covers
+		// databases imported before they existed.
+		if writesLocally {
+			go func() {
+				if err := mongoItemRepo.EnsureSearchIndexes(ctx); err != nil {
+					logger.Error(ctx, "failed to create item search indexes", "error", err)
+				}
+			}()
+			// The unique index keeps an item to one claim and the TTL index
+			// removes lapsed claims.
+			go func() {
+				if err := mongoGiftClaimRepo.EnsureIndexes(ctx); err != nil {
+					logger.Error(ctx, "failed to create gift claim indexes", "error", err)
+				}
+			}()
+			// The unique index keeps a key to one request and the TTL index
+			// removes lapsed keys.
+			go func() {
+				if err := mongoIdempotencyRepo.EnsureIndexes(ctx); err != nil {
+					logger.Error(ctx, "failed to create idempotency indexes", "error", err)
+				}
+			}()
+			go func() {
+				if err := mongoShareLinkRepo.EnsureIndexes(ctx); err != nil {
+					logger.Error(ctx, "failed to create share link indexes", "error", err)
+				}
+			}()
+			go func() {
+				if err := mongoReportRepo.EnsureIndexes(ctx); err != nil {
+					logger.Error(ctx, "failed to create share link report indexes", "error", err)
+				}
+			}()
+			go func() {
+				if err := mongoCustomItemRepo.EnsureIndexes(ctx); err != nil {
+					logger.Error(ctx, "failed to create custom item indexes", "error", err)
+				}
+			}()
+			go func() {
+				if err := mongoFoundryRepo.EnsureIndexes(ctx); err != nil {
+					logger.Error(ctx, "failed to create foundry indexes", "error", err)
+				}
+			}()
+			go func() {
+				if err := mongoTemplateRepo.EnsureIndexes(ctx); err != nil {
+					logger.Error(ctx, "failed to create wishlist template indexes", "error", err)
+				}
+			}()
+			go func() {
+				if err := mongoMasteryRepo.EnsureIndexes(ctx); err != nil {
+					logger.Error(ctx, "failed to create mastery indexes", "error", err)
+				}
+			}()
+			// The unique index sends each event to a channel once and the TTL
+			// index expires the delivery log.
+			go func() {
+				if err := mongoNotificationRepo.EnsureIndexes(ctx); err != nil {
+					logger.Error(ctx, "failed to create notification indexes", "error", err)
+				}
+			}()
+			go func() {
+				if err := mongoIntegrationRepo.EnsureIndexes(ctx); err != nil {
+					logger.Error(ctx, "failed to create integration indexes", "error", err)
+				}
+			}()
+			go func() {
+				if err := mongoWorkspaceRepo.EnsureIndexes(ctx); err != nil {
+					logger.Error(ctx, "failed to create workspace indexes", "error", err)
+				}
+			}()
+			go func() {
+				if err := mongoContributionRepo.EnsureIndexes(ctx); err != nil {
+					logger.Error(ctx, "failed to create workspace contribution indexes", "error", err)
+				}
+			}()
+			// The TTL index expires the activity log.
+			go func() {
+				if err := mongoActivityRepo.EnsureIndexes(ctx); err != nil {
+					logger.Error(ctx, "failed to create activity indexes", "error", err)
+				}
+			}()
+		}
+	}
+
+	// Every change to a wishlist or owned blueprints is recorded in the
+	// owner's activity log, whichever service makes it.
+	wishlistRepo = repository.NewActivityWishlistRepository(wishlistRepo, activityRepo)
+	ownedBPRepo = repository.NewActivityOwnedBlueprintsRepository(ownedBPRepo, activityRepo)
+
+	materialLimits := services.MaterialLimits{
+		MaxDepth:     cfg.MaterialsMaxDepth,
+		MaxMaterials: cfg.MaterialsMaxDistinct,
+		MaxDuration:  time.Duration(cfg.MaterialsMaxResolveMs) * time.Millisecond,
+	}
+
+	// Kiosk mode serves preloaded public wishlists instead of user data. They
+	// live in their own in-memory repository, keyed by wishlist ID, so the
+	// material resolver works on them unchanged; they also drive the cache
+	// pre-warm since they are what kiosk visitors browse.
+	var publicWishlistHandler *handlers.PublicWishlistHandler
+	prewarmSource := popularity
+	if cfg.KioskMode {
+		var publicWishlists []models.PublicWishlist
+		if cfg.KioskWishlistsFile != "" {
+			var err error
+			publicWishlists, err = memory.LoadPublicWishlists(cfg.KioskWishlistsFile)
+			if err != nil {
+				logger.Error(ctx, "failed to load kiosk public wishlists", "error", err)
+				os.Exit(1)
+			}
+		}
+
+		publicWishlistRepo := memory.NewWishlistRepository()
+		for _, wl := range publicWishlists {
+			if err := publicWishlistRepo.Create(ctx, &models.Wishlist{UserID: wl.ID, Items: wl.Items}); err != nil {
+				logger.Error(ctx, "failed to store kiosk public wishlist", "id", wl.ID, "error", err)
+				os.Exit(1)
+			}
+		}
+		prewarmSource = publicWishlistRepo
+
+		kioskResolver := services.NewMaterialResolver(itemRepo, publicWishlistRepo, nil, nil)
+		kioskResolver.SetLimits(materialLimits)
+		if cfg.MaterialsGraphLookup {
+			kioskResolver.SetItemGraph(itemGraph)
+		}
+		publicWishlistService := services.NewPublicWishlistService(publicWishlists, kioskResolver)
+		publicWishlistHandler = handlers.NewPublicWishlistHandler(publicWishlistService)
+		logger.Info(ctx, "kiosk mode enabled, API is read-only", "publicWishlists", len(publicWishlists))
+	}
+
+	dataSyncService := services.NewDataSyncService(cfg.DataVersion)
+
+	// Item change detection writes fingerprints and changes, so kiosk
+	// instances and read regions only serve the feed. It reads the uncached
+	// catalog and is registered first so the feed is current before the CDN
+	// purge.
+	itemChangeService := services.NewItemChangeService(itemCatalog, itemChangeRepo, dataSyncService.Version)
+	// Integrations are sent the changes as they are recorded, so only where
+	// they are detected.
+	integrationService := services.NewIntegrationService(integrationRepo, cfg.NotificationsAllowPrivateURLs)
+	integrationService.SetIDGenerator(idGenerator)
+	integrationsRunning := cfg.IntegrationsDispatchSeconds > 0 && writesLocally
+	if integrationsRunning {
+		itemChangeService.SetPublisher(integrationService)
+	}
+	if writesLocally {
+		go func() {
+			if err := itemChangeService.EnsureBaseline(ctx); err != nil {
+				logger.Error(ctx, "item change baseline failed", "error", err)
+			}
+		}()
+		dataSyncService.OnSync("item-changes", func(ctx context.Context) error {
+			_, err := itemChangeService.Detect(ctx)
+			return err
+		})
+	}
+
+	// The item graph is derived from the item collections, so only instances
+	// that write rebuild it; read regions get it replicated. Resolutions fall
+	// back to level lookups until it exists.
+	if cfg.MaterialsGraphLookup && writesLocally && itemGraphRebuild != nil {
+		go func() {
+			if err := itemGraphRebuild(ctx); err != nil {
+				logger.Error(ctx, "item graph build failed", "error", err)
+			}
+		}()
+		dataSyncService.OnSync("item-graph", itemGraphRebuild)
+	}
 
-	logger.Debug(ctx, "initializing repositories")
-	itemRepo := repository.NewItemRepository(db)
-	wishlistRepo := repository.NewWishlistRepository(db)
-	ownedBPRepo := repository.NewOwnedBlueprintsRepository(db)
+	// Words that matched nothing in a collection may match new items.
+	if searchStats != nil {
+		dataSyncService.OnSync("item-search-stats", func(ctx context.Context) error {
+			searchStats.Reset()
+			return nil
+		})
+	}
+
+	// Registered before the in-memory cache's hook so re-warming reads fresh
+	// data.
+	if cfg.RedisURL != "" {
+		redisClient, err := redis.NewClient(cfg.RedisURL)
+		if err != nil {
+			logger.Error(ctx, "invalid REDIS_URL", "error", err)
+			os.Exit(1)
+		}
+		defer redisClient.Close()
+		if err := redisClient.Ping(ctx); err != nil {
+			logger.Warn(ctx, "redis unavailable; item lookups fall through until it answers", "error", err)
+		}
+		redisItemRepo := repository.NewRedisItemRepository(itemRepo, redisClient, time.Duration(cfg.ItemRedisCacheTTLSeconds)*time.Second)
+		itemRepo = redisItemRepo
+		logger.Info(ctx, "redis item cache enabled", "ttlSeconds", cfg.ItemRedisCacheTTLSeconds)
+		dataSyncService.OnSync("item-redis-cache", redisItemRepo.Invalidate)
+	}
+
+	if cfg.ItemCacheTTLSeconds > 0 {
+		itemCache := repository.NewCachedItemRepository(itemRepo, time.Duration(cfg.ItemCacheTTLSeconds)*time.Second)
+		itemRepo = itemCache
+		logger.Info(ctx, "item cache enabled", "ttlSeconds", cfg.ItemCacheTTLSeconds, "prewarmCount", cfg.ItemCachePrewarmCount)
+
+		if cfg.ItemCachePrewarmCount > 0 {
+			warmer := services.NewItemCacheWarmer(prewarmSource, itemCache, cfg.ItemCachePrewarmCount)
+			go func() {
+				if _, err := warmer.Warm(ctx); err != nil {
+					logger.Error(ctx, "item cache pre-warm failed", "error", err)
+				}
+			}()
+			dataSyncService.OnSync("item-cache", func(ctx context.Context) error {
+				itemCache.Purge()
+				_, err := warmer.Warm(ctx)
+				return err
+			})
+		} else {
+			dataSyncService.OnSync("item-cache", func(ctx context.Context) error {
+				itemCache.Purge()
+				return nil
+			})
+		}
+	}
+
+	// Registered after the item cache hook so the CDN refetches from fresh data.
+	if cfg.CDNPurgeProvider != "" {
+		purger, err := cdn.NewPurger(cfg.CDNPurgeProvider, cfg.CDNPurgeServiceID, cfg.CDNPurgeToken)
+		if err != nil {
+			logger.Error(ctx, "invalid CDN purge configuration", "error", err)
+			os.Exit(1)
+		}
+		logger.Info(ctx, "CDN purge after data sync enabled", "provider", cfg.CDNPurgeProvider)
+		dataSyncService.OnSync("cdn-purge", func(ctx context.Context) error {
+			return purger.PurgeKeys(ctx, []string{cdn.AllItemsKey})
+		})
+	}
+
+	if cfg.AggregateExportIntervalHours > 0 {
+		store, err := storage.New(storage.Config{
+			Backend:         cfg.AggregateExportBackend,
+			Dir:             cfg.AggregateExportDir,
+			Endpoint:        cfg.AggregateExportEndpoint,
+			Region:          cfg.AggregateExportRegion,
+			Bucket:          cfg.AggregateExportBucket,
+			AccessKeyID:     cfg.AggregateExportAccessKeyID,
+			SecretAccessKey: cfg.AggregateExportSecretKey,
+		})
+		if err != nil {
+			logger.Error(ctx, "invalid aggregate export configuration", "error", err)
+			os.Exit(1)
+		}
+		logger.Info(ctx, "aggregate dataset export enabled", "backend", cfg.AggregateExportBackend, "intervalHours", cfg.AggregateExportIntervalHours)
+		exporter := services.NewAggregateExporter(popularity, store, cfg.AggregateExportPrefix, cfg.AggregateExportTopItems, cfg.AggregateExportMinUsers)
+		go exporter.Run(ctx, time.Duration(cfg.AggregateExportIntervalHours)*time.Hour)
+	}
 
 	logger.Debug(ctx, "initializing services")
-	itemService := services.NewItemService(itemRepo)
-	wishlistService := services.NewWishlistService(wishlistRepo, itemRepo)
+	var itemService services.ItemServiceInterface = services.NewItemService(itemRepo)
+	if cfg.RequestDedup {
+		dedupedItemService := services.NewDedupedItemService(itemService)
+		itemService = dedupedItemService
+		// Registered after the item cache hook, so lookups started before the
+		// purge are not joined after it.
+		dataSyncService.OnSync("item-dedup", func(ctx context.Context) error {
+			dedupedItemService.Forget()
+			return nil
+		})
+	}
+	baseWishlistService := services.NewWishlistService(wishlistRepo, itemRepo)
+	baseWishlistService.SetSettingsRepository(settingsRepo)
+	baseWishlistService.SetCustomItemRepository(customItemRepo)
+	baseWishlistService.SetMaxItems(cfg.UserMaxWishlistItems)
+	baseWishlistService.SetMasteryRepository(masteryRepo)
+	baseWishlistService.SetHooks(hooks.Default)
+	var wishlistService services.WishlistServiceInterface = baseWishlistService
+	var householdHandler *handlers.HouseholdHandler
+	if cfg.HouseholdApprovalsEnabled {
+		logger.Info(ctx, "household approvals enabled")
+		approvalWishlistService := services.NewApprovalWishlistService(baseWishlistService, householdRepo, itemRepo)
+		approvalWishlistService.SetSettingsRepository(settingsRepo)
+		approvalWishlistService.SetCustomItemRepository(customItemRepo)
+		wishlistService = approvalWishlistService
+		householdHandler = handlers.NewHouseholdHandler(services.NewHouseholdService(householdRepo, baseWishlistService))
+	}
+	// Import resolves names against the uncached catalog and rebuilds its
+	// name index lazily after each sync.
+	wishlistImportService := services.NewWishlistImportService(itemCatalog, wishlistService)
+	wishlistImportService.SetAliasRepository(importAliasRepo)
+	dataSyncService.OnSync("wishlist-import", func(ctx context.Context) error {
+		wishlistImportService.Invalidate()
+		return nil
+	})
+	// Autocomplete keeps its own prefix index of the uncached catalog, built
+	// at startup and rebuilt after each sync.
+	itemAutocompleteService := services.NewItemAutocompleteService(itemCatalog)
+	go func() {
+		if err := itemAutocompleteService.Rebuild(ctx); err != nil {
+			logger.Error(ctx, "item autocomplete index build failed", "error", err)
+		}
+	}()
+	dataSyncService.OnSync("item-autocomplete", itemAutocompleteService.Rebuild)
+	// The mastery summary counts against an index of the buildable items,
+	// rebuilt lazily after each sync like the import name index.
+	masteryService := services.NewMasteryService(masteryRepo, itemRepo, itemCatalog)
+	dataSyncService.OnSync("mastery", func(ctx context.Context) error {
+		masteryService.Invalidate()
+		return nil
+	})
+	giftClaimHandler := handlers.NewGiftClaimHandler(services.NewGiftClaimService(giftClaimRepo, wishlistRepo, settingsRepo))
 	ownedBPService := services.NewOwnedBlueprintsService(ownedBPRepo, itemRepo)
-	materialResolver := services.NewMaterialResolver(itemRepo, wishlistRepo, ownedBPRepo)
+	ownedBPService.SetMaxBlueprints(cfg.UserMaxOwnedBlueprints)
+	ownedMatService := services.NewOwnedMaterialsService(ownedMatRepo)
+	// Inventory imports sort items by an index of the recipes, rebuilt
+	// lazily after each sync like the mastery index.
+	profileImportService := services.NewProfileImportService(ownedBPService, ownedMatService, itemRepo, itemCatalog)
+	dataSyncService.OnSync("profile-import", func(ctx context.Context) error {
+		profileImportService.Invalidate()
+		return nil
+	})
+	baseMaterialResolver := services.NewMaterialResolver(itemRepo, wishlistRepo, ownedBPRepo, ownedMatRepo)
+	baseMaterialResolver.SetLimits(materialLimits)
+	baseMaterialResolver.SetCustomItemRepository(customItemRepo)
+	baseMaterialResolver.SetSettingsRepository(settingsRepo)
+	if cfg.MaterialsGraphLookup {
+		baseMaterialResolver.SetItemGraph(itemGraph)
+		logger.Info(ctx, "materials graph lookup enabled")
+	}
+	customItemService := services.NewCustomItemService(customItemRepo, itemRepo)
+	settingsService := services.NewSettingsService(settingsRepo)
+	var vapid *notify.VAPID
+	if cfg.VAPIDPublicKey != "" || cfg.VAPIDPrivateKey != "" {
+		vapid, err = notify.ParseVAPID(cfg.VAPIDPublicKey, cfg.VAPIDPrivateKey, cfg.VAPIDSubject)
+		if err != nil {
+			logger.Error(ctx, "invalid VAPID_PUBLIC_KEY, VAPID_PRIVATE_KEY or VAPID_SUBJECT", "error", err)
+			os.Exit(1)
+		}
+	}
+	if cfg.NotificationsAllowPrivateURLs && cfg.Production() {
+		logger.Error(ctx, "NOTIFICATIONS_ALLOW_PRIVATE_URLS is not allowed in production; set APP_ENV to a non-production environment")
+		os.Exit(1)
+	}
+	notificationService := services.NewNotificationService(notificationRepo, vapid, cfg.NotificationsAllowPrivateURLs)
+	notificationService.SetIDGenerator(idGenerator)
+	var materialResolver services.MaterialResolverInterface = baseMaterialResolver
+	var materialsCache services.MaterialsCache
+	if cfg.MaterialsCacheSize > 0 {
+		lruMaterialsCache := services.NewLRUMaterialsCache(cfg.MaterialsCacheSize, time.Duration(cfg.MaterialsCacheTTLSeconds)*time.Second)
+		materialResolver = services.NewCachedMaterialResolver(materialResolver, wishlistRepo, lruMaterialsCache)
+		materialsCache = lruMaterialsCache
+		logger.Info(ctx, "materials cache enabled", "size", cfg.MaterialsCacheSize, "ttlSeconds", cfg.MaterialsCacheTTLSeconds)
+	}
+	// Deduplication wraps the cache so concurrent misses share a resolution,
+	// and takes its invalidations so no request joins one started before a
+	// change.
+	if cfg.RequestDedup {
+		dedupedMaterialResolver := services.NewDedupedMaterialResolver(materialResolver)
+		materialResolver = dedupedMaterialResolver
+		materialsCache = dedupedMaterialResolver.InvalidatingCache(materialsCache)
+		logger.Info(ctx, "request deduplication enabled")
+	}
+	// Deliveries are claimed with a lease, but polling on one instance keeps
+	// the per-user event checks from running everywhere.
+	notificationsRunning := cfg.NotificationsPollSeconds > 0 && writesLocally
+	// Milestone checks learn which users changed from the cache
+	// invalidations, which every wishlist and progress change makes.
+	var milestoneTracker *services.MilestoneTracker
+	if notificationsRunning {
+		milestoneTracker = services.NewMilestoneTracker(notificationService, materialResolver, wishlistRepo, ownedBPRepo, itemRepo, settingsRepo)
+		materialsCache = milestoneTracker.InvalidatingCache(materialsCache)
+	}
+	if materialsCache != nil {
+		baseWishlistService.SetMaterialsCache(materialsCache)
+		ownedBPService.SetMaterialsCache(materialsCache)
+		ownedMatService.SetMaterialsCache(materialsCache)
+		customItemService.SetMaterialsCache(materialsCache)
+		settingsService.SetMaterialsCache(materialsCache)
+		// Recipes change with the item data, so every response is stale
+		// after a sync.
+		dataSyncService.OnSync("materials-cache", func(ctx context.Context) error {
+			materialsCache.Purge(ctx)
+			return nil
+		})
+	}
+	// The refresh worker writes the item collections, so it needs MongoDB and
+	// never runs on kiosk instances. It starts after every data sync hook is
+	// registered since its runs notify them.
+	itemRefreshRunning := false
+	itemRefreshService := services.NewItemRefreshService(itemSyncer, syncStatusRepo, dataSyncService, cfg.ItemRefreshSource, time.Duration(cfg.ItemRefreshIntervalMinutes)*time.Minute)
+	if cfg.ItemRefreshIntervalMinutes > 0 {
+		switch {
+		case cfg.KioskMode:
+			logger.Warn(ctx, "ITEM_REFRESH_INTERVAL_MINUTES is ignored in kiosk mode")
+		case regionForwarder != nil:
+			logger.Warn(ctx, "ITEM_REFRESH_INTERVAL_MINUTES is ignored in a read region")
+		case itemSyncer == nil:
+			logger.Warn(ctx, "ITEM_REFRESH_INTERVAL_MINUTES is ignored without MongoDB")
+		default:
+			logger.Info(ctx, "scheduled item refresh enabled", "intervalMinutes", cfg.ItemRefreshIntervalMinutes)
+			go itemRefreshService.Run(ctx)
+			itemRefreshRunning = true
+		}
+	}
+
+	userTraceService := services.NewUserTraceService(userTraceRepo)
 
 	logger.Debug(ctx, "initializing handlers")
 	healthHandler := handlers.NewHealthHandler()
+	if dbTopology != nil {
+		healthHandler.SetDatabase(dbTopology)
+	}
 	itemHandler := handlers.NewItemHandler(itemService)
-	wishlistHandler := handlers.NewWishlistHandler(wishlistService, materialResolver)
+	itemAutocompleteHandler := handlers.NewItemAutocompleteHandler(itemAutocompleteService)
+	itemChangesHandler := handlers.NewItemChangesHandler(itemChangeService)
+	itemExportHandler := handlers.NewItemExportHandler(services.NewItemExportService(itemExport), dataSyncService.Version)
+	wishlistHandler := handlers.NewWishlistHandler(wishlistService, services.NewHookedMaterialResolver(materialResolver, hooks.Default))
+	wishlistHandler.SetQuantitySuggester(services.NewQuantitySuggester(wishlistRepo, itemRepo))
+	graphQLHandler := handlers.NewGraphQLHandler(itemService, wishlistService, ownedBPService, materialResolver)
+	usageService := services.NewUsageService(services.UsageRepositories{
+		Wishlists:         wishlistRepo,
+		OwnedBlueprints:   ownedBPRepo,
+		CustomItems:       customItemRepo,
+		Foundry:           foundryRepo,
+		ShareLinks:        shareLinkRepo,
+		Notifications:     notificationRepo,
+		Workspaces:        workspaceRepo,
+		WorkspaceActivity: contributionRepo,
+	}, services.UsageLimits{
+		MaxWishlistItems:   cfg.UserMaxWishlistItems,
+		MaxOwnedBlueprints: cfg.UserMaxOwnedBlueprints,
+		WarningPercent:     cfg.UsageWarningPercent,
+	})
+	usageHandler := handlers.NewUsageHandler(usageService)
+	wishlistHandler.SetUsageService(usageService)
+	wishlistHandler.SetDeprecations(deprecations)
+	wishlistVersions := services.NewWishlistVersionService(wishlistRepo, ownedBPRepo, ownedMatRepo, dataSyncService.Version)
+	wishlistVersions.SetCustomItemRepository(customItemRepo)
+	wishlistVersions.SetSettingsRepository(settingsRepo)
+	wishlistHandler.SetVersions(wishlistVersions)
+	// Read regions and kiosks do not write, so they only flag new users.
+	if !writesLocally && len(starterItems) > 0 {
+		logger.Warn(ctx, "ONBOARDING_STARTER_ITEMS is ignored on instances that do not write")
+		starterItems = nil
+	}
+	onboardingService := services.NewOnboardingService(wishlistRepo, settingsRepo, itemRepo, starterItems)
+	onboardingService.SetMaterialsCache(materialsCache)
+	wishlistHandler.SetOnboarding(onboardingService)
+	farmingPlanHandler := handlers.NewFarmingPlanHandler(services.NewFarmingPlanner(materialResolver, itemRepo))
+	relicResolver := services.NewRelicResolver(wishlistRepo, itemRepo, relicCatalog)
+	relicHandler := handlers.NewRelicHandler(relicResolver)
+	researchHandler := handlers.NewResearchHandler(services.NewResearchCalculator(itemRepo))
+	// The world state is read-only and held in memory, so every instance
+	// polls its own copy. Without polling the opportunities list stays empty.
+	worldStateService := services.NewWorldStateService(cfg.WorldStateSource, time.Duration(cfg.WorldStatePollSeconds)*time.Second)
+	if cfg.WorldStatePollSeconds > 0 {
+		go worldStateService.Run(ctx)
+		logger.Info(ctx, "world state polling enabled", "intervalSeconds", cfg.WorldStatePollSeconds)
+	}
+	opportunityFinder := services.NewOpportunityFinder(worldStateService, materialResolver, relicResolver, itemRepo)
+	opportunityHandler := handlers.NewOpportunityHandler(opportunityFinder)
+	shareLinkService := services.NewShareLinkService(shareLinkRepo, wishlistRepo, materialResolver)
+	shareLinkService.SetIDGenerator(idGenerator)
+	shareLinkHandler := handlers.NewShareLinkHandler(shareLinkService)
+	wishlistImportHandler := handlers.NewWishlistImportHandler(wishlistImportService)
+	wishlistTemplateService := services.NewWishlistTemplateService(templateRepo, itemRepo, wishlistImportService)
+	wishlistTemplateService.SetCustomItemRepository(customItemRepo)
+	wishlistTemplateHandler := handlers.NewWishlistTemplateHandler(wishlistTemplateService)
+	wishlistTransferService := services.NewWishlistTransferService(wishlistService, ownedBPService, itemRepo)
+	wishlistTransferService.SetCustomItemRepository(customItemRepo)
+	wishlistTransferService.SetMaterialResolver(materialResolver)
+	wishlistTransferHandler := handlers.NewWishlistTransferHandler(wishlistTransferService)
+	customItemHandler := handlers.NewCustomItemHandler(customItemService)
+	workspaceService := services.NewWorkspaceService(workspaceRepo, contributionRepo, itemRepo)
+	workspaceService.SetMaterialLimits(materialLimits)
+	workspaceHandler := handlers.NewWorkspaceHandler(workspaceService)
+	// Live updates are fanned out in memory, so members only see the changes
+	// made through the instance they are connected to. Read regions forward
+	// writes elsewhere and so never publish any.
+	var liveHub *realtime.Hub
+	var workspaceLiveHandler *handlers.WorkspaceLiveHandler
+	if cfg.LiveMaxConnections > 0 && writesLocally {
+		liveHub = realtime.NewHub(cfg.LiveMaxConnections)
+		workspaceService.SetPublisher(liveHub)
+		workspaceLiveHandler = handlers.NewWorkspaceLiveHandler(workspaceService, liveHub)
+		logger.Info(ctx, "workspace live updates enabled", "maxConnections", cfg.LiveMaxConnections)
+	}
 	ownedBPHandler := handlers.NewOwnedBlueprintsHandler(ownedBPService)
+	ownedBPHandler.SetUsageService(usageService)
+	ownedMatHandler := handlers.NewOwnedMaterialsHandler(ownedMatService)
+	masteryHandler := handlers.NewMasteryHandler(masteryService)
+	profileImportHandler := handlers.NewProfileImportHandler(profileImportService)
+	activityHandler := handlers.NewActivityHandler(services.NewActivityService(activityRepo))
+	settingsHandler := handlers.NewSettingsHandler(settingsService)
+	foundryService := services.NewFoundryService(foundryRepo, itemRepo)
+	foundryHandler := handlers.NewFoundryHandler(foundryService)
+	dashboardHandler := handlers.NewDashboardHandler(services.NewDashboardService(wishlistService, materialResolver, foundryService, opportunityFinder))
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
+	if notificationsRunning {
+		watcher := services.NewNotificationWatcher(notificationService, foundryService, opportunityFinder, time.Duration(cfg.NotificationsPollSeconds)*time.Second)
+		watcher.SetMilestoneTracker(milestoneTracker)
+		watcher.SetSettingsRepository(settingsRepo)
+		go watcher.Run(ctx)
+		logger.Info(ctx, "notifications enabled", "intervalSeconds", cfg.NotificationsPollSeconds, "webPush", vapid != nil)
+	}
+	if integrationsRunning {
+		go integrationService.Run(ctx, time.Duration(cfg.IntegrationsDispatchSeconds)*time.Second)
+		logger.Info(ctx, "integration webhooks enabled", "intervalSeconds", cfg.IntegrationsDispatchSeconds)
+	}
+	integrationHandler := handlers.NewIntegrationHandler(integrationService)
+	dataSyncHandler := handlers.NewDataSyncHandler(dataSyncService, cfg.DataSyncToken)
+	userTraceHandler := handlers.NewUserTraceHandler(userTraceService)
+	itemRefreshHandler := handlers.NewItemRefreshHandler(itemRefreshService)
+	deprecationHandler := handlers.NewDeprecationHandler(deprecations)
+	moderationHandler := handlers.NewModerationHandler(
+		services.NewModerationService(reportRepo, shareLinkRepo, shareLinkService),
+		services.NewImportAliasService(importAliasRepo),
+	)
+	adminHandler := handlers.NewAdminHandler(usageService, userTraceService, wishlistService, itemRefreshService, dataSyncService)
+	metaHandler := handlers.NewMetaHandler(build, map[string]bool{
+		"demoMode":             cfg.DemoMode,
+		"kioskMode":            cfg.KioskMode,
+		"readRegion":           regionForwarder != nil,
+		"householdApprovals":   householdHandler != nil,
+		"itemCache":            cfg.ItemCacheTTLSeconds > 0,
+		"redisItemCache":       cfg.RedisURL != "",
+		"materialsCache":       cfg.MaterialsCacheSize > 0,
+		"materialsGraphLookup": cfg.MaterialsGraphLookup,
+		"requestDedup":         cfg.RequestDedup,
+		"idempotencyKeys":      cfg.IdempotencyTTLSeconds > 0,
+		"dataSyncWebhook":      cfg.DataSyncToken != "",
+		"scheduledItemRefresh": itemRefreshRunning,
+		"cdnPurge":             cfg.CDNPurgeProvider != "",
+		"aggregateExport":      cfg.AggregateExportIntervalHours > 0,
+		"faultInjection":       faultInjector != nil,
+		"worldState":           cfg.WorldStatePollSeconds > 0,
+		"notifications":        notificationsRunning,
+		"webPush":              vapid != nil,
+		"liveWorkspaces":       liveHub != nil,
+		"integrations":         integrationsRunning,
+		"starterWishlist":      len(starterItems) > 0,
+	})
 
-	authMiddleware := middleware.NewAuthMiddleware(cfg.SupabaseJWTPublicKey)
+	var authMiddleware *middleware.AuthMiddleware
+	switch {
+	case cfg.DemoMode:
+		authMiddleware = middleware.NewDemoAuthMiddleware(cfg.DemoUserID)
+	case cfg.KioskMode:
+		// Kiosk instances serve no authenticated routes.
+		authMiddleware = middleware.NewAuthMiddleware(nil)
+	default:
+		if cfg.SupabaseJWKSURL == "" {
+			logger.Error(ctx, "SUPABASE_URL or SUPABASE_JWKS_URL is required to verify tokens")
+			os.Exit(1)
+		}
+		jwks := middleware.NewJWKS(cfg.SupabaseJWKSURL, time.Duration(cfg.JWKSRefreshSeconds)*time.Second)
+		go func() {
+			if err := jwks.Refresh(ctx); err != nil {
+				logger.Error(ctx, "initial JWKS fetch failed; retrying on first request", "url", cfg.SupabaseJWKSURL, "error", err)
+			}
+		}()
+		authMiddleware = middleware.NewAuthMiddleware(jwks)
+	}
+	authMiddleware.SetTracer(userTraceService)
+
+	// Retried wishlist and blueprint changes sent with an Idempotency-Key get
+	// the first attempt's response.
+	idempotent := func(next http.Handler) http.Handler { return next }
+	if cfg.IdempotencyTTLSeconds > 0 {
+		idempotent = middleware.NewIdempotency(idempotencyRepo, time.Duration(cfg.IdempotencyTTLSeconds)*time.Second).Middleware
+	}
 
 	r := chi.NewRouter()
+	openAPIHandler := handlers.NewOpenAPIHandler(r, build.Version, authMiddleware.Authenticate)
 
 	// Middleware stack
-	r.Use(chimiddleware.RequestID)      // Generate request IDs
+	r.Use(middleware.RequestID(idGenerator))           // Generate request IDs
+	r.Use(response.RequestIDs(chimiddleware.GetReqID)) // Request IDs on responses and error bodies
+	if len(trustedProxies) > 0 {
+		r.Use(middleware.ClientIP(trustedProxies)) // Client address from trusted proxies' headers
+	}
 	r.Use(middleware.LoggingMiddleware) // Custom structured logging
 	r.Use(chimiddleware.Recoverer)      // Recover from panics
+	r.Use(response.Pretty)              // Indent JSON bodies on ?pretty=1
+	r.Use(response.Negotiate)           // msgpack/CBOR bodies via Accept
+	r.Use(response.Localize)            // Error messages via Accept-Language
+	r.Use(response.Warnings)            // Warnings, e.g. deprecations, in JSON bodies
+	if cfg.Region != "" {
+		r.Use(middleware.RegionName(cfg.Region))
+	}
+
+	staffTokens := middleware.StaffTokens{Admin: cfg.AdminToken, Moderator: cfg.ModeratorToken}
+	if cfg.LoadShedMaxInFlight > 0 {
+		logger.Info(ctx, "load shedding enabled", "maxInFlight", cfg.LoadShedMaxInFlight, "latencyTargetMs", cfg.LoadShedLatencyTargetMs)
+		loadShedder := middleware.NewLoadShedder(cfg.LoadShedMaxInFlight, time.Duration(cfg.LoadShedLatencyTargetMs)*time.Millisecond)
+		// Staff keep priority too, so the admin tools work during a spike.
+		loadShedder.SetAuthenticator(func(r *http.Request) (*http.Request, bool) {
+			if staffTokens.Role(r) != middleware.RoleNone {
+				return r, true
+			}
+			return authMiddleware.Verify(r)
+		})
+		loadShedder.Exempt("/health", "/ready")
+		r.Use(loadShedder.Middleware)
+	}
 
 	allowedOrigins := strings.Split(cfg.AllowedOrigins, ",")
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   allowedOrigins,
 		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-Request-ID"},
-		ExposedHeaders:   []string{"Link"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-Request-ID", "If-None-Match", deprecation.ClientIDHeader, middleware.IdempotencyKeyHeader},
+		ExposedHeaders:   []string{"Link", "ETag", response.RequestIDHeader, middleware.RegionHeader, middleware.FaultHeader, handlers.UsageWarningHeader, handlers.OnboardingHeader, response.WarningHeader, deprecation.DeprecationHeader, deprecation.SunsetHeader, middleware.IdempotentReplayedHeader},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
 
 	r.Get("/health", healthHandler.Health)
+	r.Get("/ready", healthHandler.Ready)
+
+	if cfg.DataSyncToken != "" {
+		r.Post("/internal/data-sync", dataSyncHandler.Notify)
+	}
+
+	// Admins reach every staff route; moderators only the moderation tools.
+	// Either role is granted by its staff token or by a user's JWT claiming
+	// it.
+	requireAdmin := middleware.RequireRole(staffTokens, authMiddleware, middleware.RoleAdmin)
+	if !cfg.KioskMode {
+		r.Route("/internal/users", func(r chi.Router) {
+			if regionForwarder != nil {
+				r.Use(regionForwarder.Middleware)
+			}
+			r.Use(requireAdmin)
+			r.Get("/traces", userTraceHandler.ListTraces)
+			r.Get("/{userID}/trace", userTraceHandler.GetTrace)
+			r.Put("/{userID}/trace", userTraceHandler.EnableTrace)
+			r.Delete("/{userID}/trace", userTraceHandler.DisableTrace)
+		})
+		r.Route("/internal/integrations", func(r chi.Router) {
+			if regionForwarder != nil {
+				r.Use(regionForwarder.Middleware)
+			}
+			r.Use(requireAdmin)
+			r.Get("/", integrationHandler.ListIntegrations)
+			r.Post("/", integrationHandler.CreateIntegration)
+			r.Delete("/{integrationID}", integrationHandler.DeleteIntegration)
+			r.Get("/{integrationID}/deliveries", integrationHandler.ListDeliveries)
+		})
+		r.Route("/internal/moderation", func(r chi.Router) {
+			if regionForwarder != nil {
+				r.Use(regionForwarder.Middleware)
+			}
+			r.Use(middleware.RequireRole(staffTokens, authMiddleware, middleware.RoleModerator))
+			r.Get("/reports", moderationHandler.ListReports)
+			r.Post("/reports/{token}/dismiss", moderationHandler.DismissReport)
+			r.Delete("/share-links/{token}", moderationHandler.RevokeShareLink)
+			r.Get("/import-aliases", moderationHandler.ListImportAliases)
+			r.Put("/import-aliases/{word}", moderationHandler.SetImportAlias)
+			r.Delete("/import-aliases/{word}", moderationHandler.DeleteImportAlias)
+		})
+	}
 
 	r.Route("/api/v1", func(r chi.Router) {
+		if cfg.KioskMode {
+			r.Use(middleware.ReadOnly)
+		}
+		if regionForwarder != nil {
+			r.Use(regionForwarder.Middleware)
+		}
+		if faultInjector != nil {
+			r.Use(faultInjector.Middleware)
+		}
+
+		r.Get("/meta/version", metaHandler.Version)
+		r.Get("/openapi.json", openAPIHandler.Spec)
+		r.Get("/docs", openAPIHandler.Docs)
+
 		r.Route("/items", func(r chi.Router) {
+			r.Use(middleware.SurrogateKeys(dataSyncService.Version))
 			r.Get("/search", itemHandler.Search)
+			r.Get("/autocomplete", itemAutocompleteHandler.Autocomplete)
 			r.Get("/blueprints/reusable", itemHandler.SearchReusableBlueprints)
+			r.Get("/changes", itemChangesHandler.List)
+			r.Get("/export", itemExportHandler.Export)
 			r.Get("/*", itemHandler.GetByUniqueName)
 		})
 
+		if cfg.KioskMode {
+			r.Route("/public-wishlists", func(r chi.Router) {
+				r.Get("/", publicWishlistHandler.List)
+				r.Get("/{id}", publicWishlistHandler.Get)
+				r.Get("/{id}/materials", publicWishlistHandler.GetMaterials)
+			})
+			return
+		}
+
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(requireAdmin)
+			r.Get("/sync/status", itemRefreshHandler.Status)
+			r.Get("/deprecations", deprecationHandler.Usage)
+
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.AuditAdminActions)
+				r.Get("/users/{userID}", adminHandler.GetUser)
+				r.Get("/users/{userID}/wishlist", adminHandler.GetUserWishlist)
+				r.Post("/sync", adminHandler.TriggerSync)
+				r.Post("/cache/flush", adminHandler.FlushCaches)
+			})
+		})
+
+		r.Route("/dojo", func(r chi.Router) {
+			r.Get("/clan-tiers", researchHandler.ListClanTiers)
+			r.Post("/research-cost", researchHandler.CalculateResearchCost)
+		})
+
+		r.Route("/dashboard", func(r chi.Router) {
+			r.Use(authMiddleware.Authenticate)
+			r.Get("/", dashboardHandler.GetDashboard)
+		})
+
 		r.Route("/wishlist", func(r chi.Router) {
 			r.Use(authMiddleware.Authenticate)
+			r.Use(idempotent)
 			r.Get("/", wishlistHandler.GetWishlist)
 			r.Post("/", wishlistHandler.AddItem)
 			r.Get("/materials", wishlistHandler.GetMaterials)
+			r.Get("/materials/export", wishlistHandler.ExportMaterials)
+			r.Get("/farming-plan", farmingPlanHandler.GetFarmingPlan)
+			r.Get("/relics", relicHandler.GetRelicRequirements)
+			r.Get("/opportunities", opportunityHandler.GetOpportunities)
+			r.Post("/import/text", wishlistImportHandler.PreviewText)
+			r.Post("/import/text/confirm", wishlistImportHandler.ConfirmImport)
+			r.Get("/export", wishlistTransferHandler.Export)
+			r.Post("/import", wishlistTransferHandler.Import)
+			r.Post("/merge", wishlistTransferHandler.Merge)
+			r.Route("/templates", func(r chi.Router) {
+				r.Get("/", wishlistTemplateHandler.ListTemplates)
+				r.Post("/", wishlistTemplateHandler.CreateTemplate)
+				r.Delete("/{templateID}", wishlistTemplateHandler.DeleteTemplate)
+				r.Post("/{templateID}/apply", wishlistTemplateHandler.ApplyTemplate)
+			})
+			r.Put("/links/*", wishlistHandler.SetItemLinks)
+			r.Put("/recipe/*", wishlistHandler.SetItemRecipe)
+			r.Put("/progress/*", wishlistHandler.SetItemProgress)
 			r.Delete("/*", wishlistHandler.RemoveItem)
 			r.Patch("/*", wishlistHandler.UpdateQuantity)
 		})
 
+		r.Route("/custom-items", func(r chi.Router) {
+			r.Use(authMiddleware.Authenticate)
+			r.Get("/", customItemHandler.List)
+			r.Get("/search", customItemHandler.Search)
+			r.Post("/", customItemHandler.Create)
+			r.Put("/*", customItemHandler.Update)
+			r.Delete("/*", customItemHandler.Delete)
+		})
+
+		r.Route("/workspaces", func(r chi.Router) {
+			r.Use(authMiddleware.Authenticate)
+			r.Get("/", workspaceHandler.List)
+			r.Post("/", workspaceHandler.Create)
+			r.Route("/{workspaceID}", func(r chi.Router) {
+				r.Get("/", workspaceHandler.Get)
+				r.Put("/", workspaceHandler.Update)
+				r.Delete("/", workspaceHandler.Delete)
+				r.Get("/materials", workspaceHandler.GetMaterials)
+				r.Post("/material-contributions", workspaceHandler.LogMaterialContribution)
+				r.Get("/activity", workspaceHandler.GetActivity)
+				r.Post("/members", workspaceHandler.AddMember)
+				r.Put("/members/{memberID}", workspaceHandler.SetMemberRole)
+				r.Delete("/members/{memberID}", workspaceHandler.RemoveMember)
+				r.Post("/items", workspaceHandler.AddItem)
+				r.Patch("/items/*", workspaceHandler.UpdateItemQuantity)
+				r.Delete("/items/*", workspaceHandler.RemoveItem)
+				r.Put("/contributions/*", workspaceHandler.SetContribution)
+				if workspaceLiveHandler != nil {
+					r.Get("/live", workspaceLiveHandler.Live)
+				}
+			})
+		})
+
+		r.Route("/share-links", func(r chi.Router) {
+			r.Use(authMiddleware.Authenticate)
+			r.Get("/", shareLinkHandler.ListLinks)
+			r.Post("/", shareLinkHandler.CreateLink)
+			r.Delete("/{linkID}", shareLinkHandler.DeleteLink)
+		})
+
+		// Anyone holding a share link's token may read through it.
+		r.Route("/shared/{token}", func(r chi.Router) {
+			r.Get("/", shareLinkHandler.GetView)
+			r.Get("/materials", shareLinkHandler.GetMaterials)
+			r.Post("/report", moderationHandler.ReportShareLink)
+		})
+
+		r.Route("/users/{userID}/wishlist", func(r chi.Router) {
+			r.Use(authMiddleware.Authenticate)
+			r.Get("/", giftClaimHandler.GetSharedWishlist)
+			r.Post("/claims", giftClaimHandler.Claim)
+			r.Delete("/claims/*", giftClaimHandler.RevokeClaim)
+		})
+
+		r.Route("/gift-claims", func(r chi.Router) {
+			r.Use(authMiddleware.Authenticate)
+			r.Get("/", giftClaimHandler.ListClaims)
+		})
+
 		r.Route("/profile/blueprints", func(r chi.Router) {
 			r.Use(authMiddleware.Authenticate)
+			r.Use(idempotent)
 			r.Get("/", ownedBPHandler.GetOwnedBlueprints)
 			r.Post("/", ownedBPHandler.AddBlueprint)
 			r.Post("/bulk", ownedBPHandler.BulkAddBlueprints)
 			r.Delete("/", ownedBPHandler.ClearAllBlueprints)
+			r.Put("/*", ownedBPHandler.SetBlueprintQuantity)
 			r.Delete("/*", ownedBPHandler.RemoveBlueprint)
 		})
+
+		r.Route("/profile/components", func(r chi.Router) {
+			r.Use(authMiddleware.Authenticate)
+			r.Get("/", ownedBPHandler.GetOwnedComponents)
+			r.Delete("/", ownedBPHandler.ClearAllComponents)
+			r.Put("/*", ownedBPHandler.SetComponentCount)
+			r.Delete("/*", ownedBPHandler.RemoveComponent)
+		})
+
+		r.Route("/profile/materials", func(r chi.Router) {
+			r.Use(authMiddleware.Authenticate)
+			r.Get("/", ownedMatHandler.GetOwnedMaterials)
+			r.Put("/", ownedMatHandler.SetMaterialCounts)
+			r.Delete("/", ownedMatHandler.ClearAllMaterials)
+			r.Put("/*", ownedMatHandler.SetMaterialCount)
+			r.Delete("/*", ownedMatHandler.RemoveMaterial)
+		})
+
+		r.Route("/profile/import", func(r chi.Router) {
+			r.Use(authMiddleware.Authenticate)
+			r.Post("/", profileImportHandler.Import)
+		})
+
+		r.Route("/profile/mastery", func(r chi.Router) {
+			r.Use(authMiddleware.Authenticate)
+			r.Get("/", masteryHandler.GetProfile)
+			r.Put("/", masteryHandler.SetStatuses)
+			r.Delete("/", masteryHandler.ClearAll)
+			r.Put("/*", masteryHandler.SetStatus)
+			r.Delete("/*", masteryHandler.RemoveItem)
+		})
+
+		r.Route("/profile/summary", func(r chi.Router) {
+			r.Use(authMiddleware.Authenticate)
+			r.Get("/", masteryHandler.GetSummary)
+		})
+
+		r.Route("/profile/settings", func(r chi.Router) {
+			r.Use(authMiddleware.Authenticate)
+			r.Get("/", settingsHandler.GetSettings)
+			r.Patch("/", settingsHandler.UpdateSettings)
+		})
+
+		r.Route("/profile/usage", func(r chi.Router) {
+			r.Use(authMiddleware.Authenticate)
+			r.Get("/", usageHandler.GetUsage)
+		})
+
+		r.Route("/profile/activity", func(r chi.Router) {
+			r.Use(authMiddleware.Authenticate)
+			r.Get("/", activityHandler.ListActivity)
+		})
+
+		r.Route("/profile/foundry", func(r chi.Router) {
+			r.Use(authMiddleware.Authenticate)
+			r.Get("/", foundryHandler.GetFoundry)
+			r.Post("/", foundryHandler.StartBuild)
+			r.Delete("/{buildID}", foundryHandler.RemoveBuild)
+		})
+
+		r.Route("/graphql", func(r chi.Router) {
+			r.Use(authMiddleware.Authenticate)
+			r.Post("/", graphQLHandler.Query)
+			r.Get("/schema", graphQLHandler.Schema)
+		})
+
+		r.Route("/notifications", func(r chi.Router) {
+			r.Use(authMiddleware.Authenticate)
+			r.Get("/push-key", notificationHandler.GetPushKey)
+			r.Get("/channels", notificationHandler.ListChannels)
+			r.Post("/channels", notificationHandler.CreateChannel)
+			r.Delete("/channels/{channelID}", notificationHandler.DeleteChannel)
+			r.Get("/deliveries", notificationHandler.ListDeliveries)
+		})
+
+		if householdHandler != nil {
+			r.Route("/household", func(r chi.Router) {
+				r.Use(authMiddleware.Authenticate)
+				r.Get("/", householdHandler.GetHousehold)
+				r.Put("/manager", householdHandler.RequestManager)
+				r.Delete("/manager", householdHandler.LeaveManager)
+				r.Post("/members/{memberID}/accept", householdHandler.AcceptMember)
+				r.Patch("/members/{memberID}", householdHandler.UpdateMember)
+				r.Delete("/members/{memberID}", householdHandler.RemoveMember)
+				r.Get("/approvals", householdHandler.ListApprovals)
+				r.Post("/approvals/{changeID}/approve", householdHandler.Approve)
+				r.Post("/approvals/{changeID}/reject", householdHandler.Reject)
+			})
+		}
 	})
 
 	addr := ":" + cfg.ServerPort
@@ -113,6 +1235,11 @@ func main() {
 		Addr:    addr,
 		Handler: r,
 	}
+	// Shutdown does not wait for hijacked connections, so live clients are
+	// told to reconnect elsewhere.
+	if liveHub != nil {
+		server.RegisterOnShutdown(liveHub.Close)
+	}
 
 	// Handle shutdown signals
 	go func() {
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/audit"
+	"github.com/graytonio/warframe-wishlist/internal/cdn"
+	"github.com/graytonio/warframe-wishlist/internal/config"
+	"github.com/graytonio/warframe-wishlist/internal/database"
+	"github.com/graytonio/warframe-wishlist/internal/ids"
+	"github.com/graytonio/warframe-wishlist/internal/middleware"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+	"github.com/graytonio/warframe-wishlist/internal/repository/memory"
+	"github.com/graytonio/warframe-wishlist/internal/storage"
+)
+
+// selfTestTimeout bounds the whole self-test, so a hanging dependency fails
+// it instead of stalling a deploy.
+const selfTestTimeout = 30 * time.Second
+
+// errSkipped marks a check that does not apply to the configured mode.
+var errSkipped = errors.New("skipped")
+
+// selfTest checks the configuration and every dependency the server needs
+// before serving, as run by -selftest. Each check returns a short detail
+// for the report; a failing check does not stop the others.
+type selfTest struct {
+	cfg *config.Config
+	db  *database.MongoDB
+
+	// connect and newKeySet are replaced in tests.
+	connect   func(uri, database string) (*database.MongoDB, error)
+	newKeySet func(url string) interface{ Refresh(context.Context) error }
+}
+
+func newSelfTest(cfg *config.Config) *selfTest {
+	return &selfTest{
+		cfg:     cfg,
+		connect: database.NewMongoDB,
+		newKeySet: func(url string) interface{ Refresh(context.Context) error } {
+			return middleware.NewJWKS(url, time.Duration(cfg.JWKSRefreshSeconds)*time.Second)
+		},
+	}
+}
+
+// Run writes the report to out and reports whether every check passed or
+// was skipped.
+func (s *selfTest) Run(ctx context.Context, out io.Writer) bool {
+	ctx, cancel := context.WithTimeout(ctx, selfTestTimeout)
+	defer cancel()
+	defer func() {
+		if s.db != nil {
+			s.db.Close()
+		}
+	}()
+
+	checks := []struct {
+		name string
+		run  func(context.Context) (string, error)
+	}{
+		{"config", s.checkConfig},
+		{"demo data", s.checkDemoData},
+		{"kiosk wishlists", s.checkKioskWishlists},
+		{"mongodb", s.checkMongo},
+		{"indexes", s.checkIndexes},
+		{"jwt keys", s.checkKeys},
+	}
+
+	passed, failed, skipped := 0, 0, 0
+	fmt.Fprintln(out, "warframe-wishlist self-test")
+	for _, check := range checks {
+		detail, err := check.run(ctx)
+		status := "ok"
+		switch {
+		case errors.Is(err, errSkipped):
+			status = "skip"
+			skipped++
+		case err != nil:
+			status, detail = "FAIL", err.Error()
+			failed++
+		default:
+			passed++
+		}
+		fmt.Fprintf(out, "  %-4s  %-15s %s\n", status, check.name, detail)
+	}
+	fmt.Fprintf(out, "%d passed, %d failed, %d skipped\n", passed, failed, skipped)
+	return failed == 0
+}
+
+// checkConfig validates the settings main would otherwise reject one at a
+// time at startup, reporting every problem at once.
+func (s *selfTest) checkConfig(ctx context.Context) (string, error) {
+	cfg := s.cfg
+	var problems []string
+
+	if cfg.PrimaryRegionURL != "" {
+		u, err := url.Parse(cfg.PrimaryRegionURL)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			problems = append(problems, "PRIMARY_REGION_URL must be an absolute URL")
+		}
+	}
+	if !cfg.DemoMode && !cfg.KioskMode && cfg.SupabaseJWKSURL == "" {
+		problems = append(problems, "SUPABASE_URL or SUPABASE_JWKS_URL is required to verify tokens")
+	}
+	if _, err := repository.ParseSearchScope(cfg.ItemSearchExcludedCollections); err != nil {
+		problems = append(problems, "ITEM_SEARCH_EXCLUDED_COLLECTIONS: "+err.Error())
+	}
+	if _, err := repository.ParseItemLayout(cfg.ItemStorageLayout); err != nil {
+		problems = append(problems, "ITEM_STORAGE_LAYOUT: "+err.Error())
+	}
+	if cfg.ModeratorToken != "" && cfg.ModeratorToken == cfg.AdminToken {
+		problems = append(problems, "MODERATOR_TOKEN must differ from ADMIN_TOKEN")
+	}
+	if _, err := ids.Parse(cfg.IDStrategy); err != nil {
+		problems = append(problems, "ID_STRATEGY: "+err.Error())
+	}
+	if cfg.FaultInjectionRules != "" {
+		if cfg.Production() {
+			problems = append(problems, "FAULT_INJECTION_RULES is not allowed in production (set APP_ENV)")
+		}
+		if _, err := middleware.ParseFaultRules(cfg.FaultInjectionRules); err != nil {
+			problems = append(problems, "FAULT_INJECTION_RULES: "+err.Error())
+		}
+	}
+	if cfg.AuditSink != "" {
+		if _, err := audit.NewSink(audit.SinkConfig{
+			Type:          cfg.AuditSink,
+			SyslogNetwork: cfg.AuditSyslogNetwork,
+			SyslogAddress: cfg.AuditSyslogAddress,
+			HTTPURL:       cfg.AuditHTTPURL,
+			HTTPToken:     cfg.AuditHTTPToken,
+		}); err != nil {
+			problems = append(problems, "audit: "+err.Error())
+		}
+	}
+	if cfg.CDNPurgeProvider != "" {
+		if _, err := cdn.NewPurger(cfg.CDNPurgeProvider, cfg.CDNPurgeServiceID, cfg.CDNPurgeToken); err != nil {
+			problems = append(problems, "CDN purge: "+err.Error())
+		}
+	}
+	if cfg.AggregateExportIntervalHours > 0 {
+		if _, err := storage.New(storage.Config{
+			Backend:         cfg.AggregateExportBackend,
+			Dir:             cfg.AggregateExportDir,
+			Endpoint:        cfg.AggregateExportEndpoint,
+			Region:          cfg.AggregateExportRegion,
+			Bucket:          cfg.AggregateExportBucket,
+			AccessKeyID:     cfg.AggregateExportAccessKeyID,
+			SecretAccessKey: cfg.AggregateExportSecretKey,
+		}); err != nil {
+			problems = append(problems, "aggregate export: "+err.Error())
+		}
+	}
+
+	if len(problems) > 0 {
+		return "", errors.New(strings.Join(problems, "; "))
+	}
+	return s.mode(), nil
+}
+
+func (s *selfTest) mode() string {
+	switch {
+	case s.cfg.DemoMode:
+		return "demo mode"
+	case s.cfg.KioskMode:
+		return "kiosk mode"
+	}
+	mode := "primary region"
+	if s.cfg.PrimaryRegionURL != "" {
+		mode = "read region"
+	}
+	if s.cfg.Region != "" {
+		mode += " " + s.cfg.Region
+	}
+	return mode
+}
+
+func (s *selfTest) checkDemoData(ctx context.Context) (string, error) {
+	if !s.cfg.DemoMode {
+		return "not in demo mode", errSkipped
+	}
+	count, err := memory.LoadItemsFromDir(memory.NewItemRepository(), s.cfg.DemoDataDir)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d items in %s", count, s.cfg.DemoDataDir), nil
+}
+
+func (s *selfTest) checkKioskWishlists(ctx context.Context) (string, error) {
+	if !s.cfg.KioskMode || s.cfg.KioskWishlistsFile == "" {
+		return "no KIOSK_WISHLISTS_FILE", errSkipped
+	}
+	wishlists, err := memory.LoadPublicWishlists(s.cfg.KioskWishlistsFile)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d public wishlists", len(wishlists)), nil
+}
+
+func (s *selfTest) checkMongo(ctx context.Context) (string, error) {
+	if s.cfg.DemoMode {
+		return "demo mode uses in-memory data", errSkipped
+	}
+	db, err := s.connect(s.cfg.MongoURI, s.cfg.MongoDatabase)
+	if err != nil {
+		return "", fmt.Errorf("connecting to %s: %w", redactURI(s.cfg.MongoURI), err)
+	}
+	s.db = db
+	return fmt.Sprintf("connected to %s, database %s", redactURI(s.cfg.MongoURI), s.cfg.MongoDatabase), nil
+}
+
+func (s *selfTest) checkIndexes(ctx context.Context) (string, error) {
+	if s.db == nil {
+		return "no database connection", errSkipped
+	}
+	// An invalid layout is reported by the config check.
+	layout, _ := repository.ParseItemLayout(s.cfg.ItemStorageLayout)
+	missing, err := repository.MissingIndexes(ctx, s.db, layout)
+	if err != nil {
+		return "", err
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("missing %s (run cmd/sync or cmd/migrate-items for item indexes; the server creates the rest on a primary)", strings.Join(missing, ", "))
+	}
+	return "all required indexes present", nil
+}
+
+func (s *selfTest) checkKeys(ctx context.Context) (string, error) {
+	if s.cfg.DemoMode || s.cfg.KioskMode {
+		return "no authenticated routes", errSkipped
+	}
+	if s.cfg.SupabaseJWKSURL == "" {
+		return "", errors.New("no JWKS URL configured")
+	}
+	if err := s.newKeySet(s.cfg.SupabaseJWKSURL).Refresh(ctx); err != nil {
+		return "", fmt.Errorf("fetching %s: %w", s.cfg.SupabaseJWKSURL, err)
+	}
+	return "signing keys parsed from " + s.cfg.SupabaseJWKSURL, nil
+}
+
+// redactURI drops the password from a connection URI for the report.
+func redactURI(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.User == nil {
+		return uri
+	}
+	if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), "xxxxx")
+	}
+	return u.String()
+}
@@ -0,0 +1,194 @@
+// Command sync populates the item collections read by ItemRepository from the
+// WFCD warframe-items JSON export. It downloads and validates every category
+// file before writing anything, then upserts each into its collection,
+// archives items that were removed upstream and creates the uniqueName index.
+// With -webhook it notifies the API afterwards so caches are refreshed:
+//
+//	go run ./cmd/sync -mongo-uri mongodb://localhost:27017 -webhook http://localhost:8080/internal/data-sync
+//
+// -source may also be a local directory of data files, such as ./json.
+// -layout unified writes the single items collection instead of one
+// collection per category; see cmd/migrate-items.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/database"
+	"github.com/graytonio/warframe-wishlist/internal/itemsource"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+)
+
+type options struct {
+	source   string
+	mongoURI string
+	database string
+	dryRun   bool
+	timeout  time.Duration
+	webhook  string
+	token    string
+	version  string
+	layout   repository.ItemLayout
+}
+
+func main() {
+	opts, err := parseOptions(os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "sync:", err)
+		os.Exit(2)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	if err := run(ctx, opts, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "sync:", err)
+		os.Exit(1)
+	}
+}
+
+func parseOptions(args []string) (*options, error) {
+	fs := flag.NewFlagSet("sync", flag.ContinueOnError)
+	opts := &options{}
+
+	fs.StringVar(&opts.source, "source", itemsource.DefaultSource, "base URL or local directory of the WFCD data files")
+	fs.StringVar(&opts.mongoURI, "mongo-uri", getEnv("MONGO_URI", "mongodb://localhost:27017"), "MongoDB connection URI (defaults to $MONGO_URI)")
+	fs.StringVar(&opts.database, "database", getEnv("MONGO_DATABASE", "warframe"), "MongoDB database name (defaults to $MONGO_DATABASE)")
+	fs.BoolVar(&opts.dryRun, "dry-run", false, "report what would change without writing")
+	fs.DurationVar(&opts.timeout, "timeout", 2*time.Minute, "timeout for each download")
+	fs.StringVar(&opts.webhook, "webhook", os.Getenv("DATA_SYNC_WEBHOOK_URL"), "data sync webhook to notify after a successful sync (defaults to $DATA_SYNC_WEBHOOK_URL)")
+	fs.StringVar(&opts.token, "token", os.Getenv("DATA_SYNC_TOKEN"), "bearer token for -webhook (defaults to $DATA_SYNC_TOKEN)")
+	fs.StringVar(&opts.version, "version", "", "data version sent to -webhook (the API defaults to the sync time)")
+	layout := fs.String("layout", getEnv("ITEM_STORAGE_LAYOUT", string(repository.ItemLayoutCollections)), "item storage layout, collections or unified (defaults to $ITEM_STORAGE_LAYOUT)")
+
+	err := fs.Parse(args)
+	if err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+	if opts.source == "" {
+		return nil, fmt.Errorf("-source must not be empty")
+	}
+	if opts.timeout <= 0 {
+		return nil, fmt.Errorf("-timeout must be positive")
+	}
+	if opts.layout, err = repository.ParseItemLayout(*layout); err != nil {
+		return nil, fmt.Errorf("-layout: %w", err)
+	}
+	return opts, nil
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func run(ctx context.Context, opts *options, out io.Writer) error {
+	client := &http.Client{Timeout: opts.timeout}
+
+	fmt.Fprintf(out, "Source: %s\n", opts.source)
+	datasets, err := itemsource.Load(ctx, client, opts.source)
+	if err != nil {
+		return err
+	}
+
+	db, err := database.NewMongoDB(opts.mongoURI, opts.database)
+	if err != nil {
+		return fmt.Errorf("connect to MongoDB: %w", err)
+	}
+	defer db.Close()
+
+	fmt.Fprintf(out, "Database: %s (%s layout)\n", opts.database, opts.layout)
+	if opts.dryRun {
+		fmt.Fprintln(out, "DRY RUN MODE - no changes will be made")
+	}
+	fmt.Fprintln(out)
+
+	syncer := repository.NewItemSyncer(db)
+	syncer.SetLayout(opts.layout)
+	var total models.ItemSyncStats
+	failed := 0
+	for _, ds := range datasets {
+		stats, err := syncDataset(ctx, syncer, ds, opts.dryRun)
+		if err != nil {
+			fmt.Fprintf(out, "%-22s -> %-16s ERROR: %v\n", ds.File, ds.Collection, err)
+			failed++
+			continue
+		}
+		fmt.Fprintf(out, "%-22s -> %-16s inserted=%d updated=%d archived=%d unchanged=%d\n",
+			ds.File, ds.Collection, stats.Inserted, stats.Updated, stats.Archived, stats.Unchanged)
+		total.Add(stats)
+	}
+
+	fmt.Fprintf(out, "\nCollections: %d, inserted: %d, updated: %d, archived: %d, unchanged: %d\n",
+		len(datasets), total.Inserted, total.Updated, total.Archived, total.Unchanged)
+	if failed > 0 {
+		return fmt.Errorf("%d of %d collections failed to sync", failed, len(datasets))
+	}
+	if opts.dryRun || opts.webhook == "" {
+		return nil
+	}
+
+	if err := notify(ctx, client, opts.webhook, opts.token, opts.version); err != nil {
+		return fmt.Errorf("notify data sync webhook: %w", err)
+	}
+	fmt.Fprintln(out, "Notified data sync webhook")
+	return nil
+}
+
+func syncDataset(ctx context.Context, syncer *repository.ItemSyncer, ds itemsource.Dataset, dryRun bool) (models.ItemSyncStats, error) {
+	if !dryRun {
+		if err := syncer.EnsureIndexes(ctx, ds.Collection); err != nil {
+			return models.ItemSyncStats{}, err
+		}
+	}
+	return syncer.SyncCollection(ctx, ds.Collection, ds.Items, dryRun)
+}
+
+// notify tells the API the item data changed, like sync.sh does.
+func notify(ctx context.Context, client *http.Client, webhook, token, version string) error {
+	var body io.Reader
+	if version != "" {
+		data, err := json.Marshal(map[string]string{"version": version})
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook, body)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+)
+
+func TestNotify(t *testing.T) {
+	var gotAuth, gotBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	if err := notify(context.Background(), server.Client(), server.URL, "secret", "2026.10"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotAuth != "Bearer secret" {
+		t.Errorf("expected bearer token, got %q", gotAuth)
+	}
+	if gotBody != `{"version":"2026.10"}` {
+		t.Errorf("unexpected body %q", gotBody)
+	}
+}
+
+func TestNotify_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	err := notify(context.Background(), server.Client(), server.URL, "wrong", "")
+	if err == nil || !strings.Contains(err.Error(), "status 401") {
+		t.Errorf("expected status 401 error, got %v", err)
+	}
+}
+
+func TestParseOptions(t *testing.T) {
+	opts, err := parseOptions([]string{"-source", "json", "-dry-run"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts.source != "json" || !opts.dryRun || opts.layout != repository.ItemLayoutCollections {
+		t.Errorf("unexpected options %+v", opts)
+	}
+
+	opts, err = parseOptions([]string{"-layout", "unified"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts.layout != repository.ItemLayoutUnified {
+		t.Errorf("expected the unified layout, got %q", opts.layout)
+	}
+	if _, err := parseOptions([]string{"-layout", "sharded"}); err == nil {
+		t.Error("expected error for an unknown -layout")
+	}
+
+	if _, err := parseOptions([]string{"-timeout", "0s"}); err == nil {
+		t.Error("expected error for non-positive -timeout")
+	}
+	if _, err := parseOptions([]string{"extra"}); err == nil {
+		t.Error("expected error for unexpected arguments")
+	}
+}
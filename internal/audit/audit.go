@@ -0,0 +1,99 @@
+// Package audit records security-relevant events (authentication failures,
+// administrative actions) and forwards them to an external SIEM. Recording
+// never blocks the request path: events are buffered and dropped, with a
+// count, when the sink cannot keep up.
+package audit
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+)
+
+// Event types.
+const (
+	TypeAuthFailure = "auth.failure"
+	TypeDataSync    = "admin.data_sync"
+	TypeUserTrace   = "admin.user_trace"
+	TypeIntegration = "admin.integration"
+	TypeShareLink   = "moderation.share_link"
+	TypeImportAlias = "moderation.import_alias"
+	TypeAdminAPI    = "admin.api"
+)
+
+// Outcomes.
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+type Event struct {
+	Time    time.Time `json:"time"`
+	Type    string    `json:"type"`
+	Outcome string    `json:"outcome"`
+	Reason  string    `json:"reason,omitempty"`
+	UserID  string    `json:"userId,omitempty"`
+	// ActorID is the user who acted, when not UserID: the admin using the
+	// admin API on another user's data.
+	ActorID    string `json:"actorId,omitempty"`
+	RequestID  string `json:"requestId,omitempty"`
+	RemoteAddr string `json:"remoteAddr,omitempty"`
+	Method     string `json:"method,omitempty"`
+	Path       string `json:"path,omitempty"`
+}
+
+// Recorder accepts audit events. Implementations must not block.
+type Recorder interface {
+	Record(event Event)
+}
+
+type recorderHolder struct {
+	recorder Recorder
+}
+
+var defaultRecorder atomic.Pointer[recorderHolder]
+
+// SetDefault installs the process-wide recorder used by Record. A nil
+// recorder disables auditing.
+func SetDefault(r Recorder) {
+	defaultRecorder.Store(&recorderHolder{recorder: r})
+}
+
+// Record stamps event with the current time and request ID (when unset) and
+// hands it to the default recorder. With log anonymization on, the user and
+// actor IDs are hashed and the remote address dropped first. It is a no-op
+// until SetDefault is called.
+func Record(ctx context.Context, event Event) {
+	holder := defaultRecorder.Load()
+	if holder == nil || holder.recorder == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+	if event.RequestID == "" {
+		event.RequestID = logger.GetRequestID(ctx)
+	}
+	if logger.Anonymizing() {
+		event.UserID = logger.HashID(event.UserID)
+		event.ActorID = logger.HashID(event.ActorID)
+		event.RemoteAddr = ""
+	}
+	holder.recorder.Record(event)
+}
+
+// RecordRequest records an event for r, filling in the request details.
+func RecordRequest(r *http.Request, eventType, outcome, reason, userID string) {
+	Record(r.Context(), Event{
+		Type:       eventType,
+		Outcome:    outcome,
+		Reason:     reason,
+		UserID:     userID,
+		RemoteAddr: r.RemoteAddr,
+		Method:     r.Method,
+		Path:       r.URL.Path,
+	})
+}
@@ -0,0 +1,79 @@
+package audit
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+)
+
+type captureRecorder struct {
+	events []Event
+}
+
+func (r *captureRecorder) Record(event Event) {
+	r.events = append(r.events, event)
+}
+
+func TestRecordRequest(t *testing.T) {
+	recorder := &captureRecorder{}
+	SetDefault(recorder)
+	defer SetDefault(nil)
+
+	req := httptest.NewRequest("GET", "/api/v1/wishlist", nil)
+	RecordRequest(req, TypeAuthFailure, OutcomeFailure, "invalid token", "")
+
+	if len(recorder.events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(recorder.events))
+	}
+	event := recorder.events[0]
+	if event.Type != TypeAuthFailure || event.Outcome != OutcomeFailure || event.Reason != "invalid token" {
+		t.Errorf("unexpected event %+v", event)
+	}
+	if event.Path != "/api/v1/wishlist" || event.Method != "GET" || event.RemoteAddr == "" {
+		t.Errorf("expected request details, got %+v", event)
+	}
+	if event.Time.IsZero() {
+		t.Error("expected event time to be set")
+	}
+}
+
+func TestRecord_NoDefault(t *testing.T) {
+	SetDefault(nil)
+	// Must not panic without a recorder.
+	RecordRequest(httptest.NewRequest("GET", "/", nil), TypeAuthFailure, OutcomeFailure, "", "")
+}
+
+func TestRecordRequest_Anonymized(t *testing.T) {
+	recorder := &captureRecorder{}
+	SetDefault(recorder)
+	defer SetDefault(nil)
+	logger.EnableAnonymization("secret")
+	defer logger.DisableAnonymization()
+
+	RecordRequest(httptest.NewRequest("PUT", "/internal/users/user-123/trace", nil), TypeUserTrace, OutcomeSuccess, "trace disabled", "user-123")
+
+	event := recorder.events[0]
+	if event.UserID != logger.HashID("user-123") || event.UserID == "user-123" {
+		t.Errorf("expected a hashed user ID, got %q", event.UserID)
+	}
+	if event.RemoteAddr != "" {
+		t.Errorf("expected the remote address to be dropped, got %q", event.RemoteAddr)
+	}
+}
+
+func TestRecord_AnonymizedActor(t *testing.T) {
+	recorder := &captureRecorder{}
+	SetDefault(recorder)
+	defer SetDefault(nil)
+	logger.EnableAnonymization("secret")
+	defer logger.DisableAnonymization()
+
+	Record(context.Background(), Event{Type: TypeAdminAPI, UserID: "user-123", ActorID: "admin-1"})
+
+	event := recorder.events[0]
+	if event.ActorID != logger.HashID("admin-1") || event.ActorID == "admin-1" {
+		t.Errorf("expected a hashed actor ID, got %q", event.ActorID)
+	}
+}
@@ -0,0 +1,174 @@
+package audit
+
+import (
+	"context"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+)
+
+// Sink delivers a batch of events to an external system.
+type Sink interface {
+	Send(ctx context.Context, events []Event) error
+}
+
+const (
+	sendAttempts = 3
+	sendTimeout  = 10 * time.Second
+)
+
+// initialBackoff is the delay before the first retry; it doubles per attempt.
+var initialBackoff = 200 * time.Millisecond
+
+// Forwarder buffers events and delivers them to a Sink in batches from a
+// single background goroutine. When the buffer is full new events are dropped
+// rather than blocking callers; failed batches are retried with backoff and
+// then dropped. Dropped events are counted and reported in the logs.
+type Forwarder struct {
+	sink          Sink
+	events        chan Event
+	batchSize     int
+	flushInterval time.Duration
+
+	sent     atomic.Int64
+	dropped  atomic.Int64
+	reported int64
+
+	closeOnce sync.Once
+	closed    atomic.Bool
+	stop      chan struct{}
+	finished  chan struct{}
+}
+
+func NewForwarder(sink Sink, bufferSize, batchSize int, flushInterval time.Duration) *Forwarder {
+	if bufferSize <= 0 {
+		bufferSize = 1000
+	}
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+	if flushInterval <= 0 {
+		flushInterval = 5 * time.Second
+	}
+	f := &Forwarder{
+		sink:          sink,
+		events:        make(chan Event, bufferSize),
+		batchSize:     batchSize,
+		flushInterval: flushInterval,
+		stop:          make(chan struct{}),
+		finished:      make(chan struct{}),
+	}
+	go f.run()
+	return f
+}
+
+// Record enqueues event without blocking, dropping it if the buffer is full
+// or the forwarder is closed.
+func (f *Forwarder) Record(event Event) {
+	if f.closed.Load() {
+		f.dropped.Add(1)
+		return
+	}
+	select {
+	case f.events <- event:
+	default:
+		f.dropped.Add(1)
+	}
+}
+
+// Sent returns the number of events delivered to the sink.
+func (f *Forwarder) Sent() int64 { return f.sent.Load() }
+
+// Dropped returns the number of events lost to a full buffer or failed sends.
+func (f *Forwarder) Dropped() int64 { return f.dropped.Load() }
+
+// Close stops accepting events, flushes what is buffered and closes the sink
+// if it is an io.Closer, giving up waiting when ctx is done.
+func (f *Forwarder) Close(ctx context.Context) error {
+	f.closeOnce.Do(func() {
+		f.closed.Store(true)
+		close(f.stop)
+	})
+	select {
+	case <-f.finished:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (f *Forwarder) run() {
+	defer close(f.finished)
+
+	ticker := time.NewTicker(f.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]Event, 0, f.batchSize)
+	for {
+		select {
+		case event := <-f.events:
+			batch = append(batch, event)
+			if len(batch) >= f.batchSize {
+				batch = f.flush(batch)
+			}
+		case <-ticker.C:
+			batch = f.flush(batch)
+		case <-f.stop:
+			for {
+				select {
+				case event := <-f.events:
+					batch = append(batch, event)
+					if len(batch) >= f.batchSize {
+						batch = f.flush(batch)
+					}
+				default:
+					f.flush(batch)
+					if closer, ok := f.sink.(io.Closer); ok {
+						closer.Close()
+					}
+					return
+				}
+			}
+		}
+	}
+}
+
+// flush sends batch and returns it emptied for reuse.
+func (f *Forwarder) flush(batch []Event) []Event {
+	if len(batch) > 0 {
+		if err := f.send(batch); err != nil {
+			f.dropped.Add(int64(len(batch)))
+			logger.Error(context.Background(), "audit: dropping batch after failed delivery", "events", len(batch), "error", err)
+		} else {
+			f.sent.Add(int64(len(batch)))
+		}
+	}
+
+	if dropped := f.dropped.Load(); dropped != f.reported {
+		logger.Warn(context.Background(), "audit: events dropped", "dropped", dropped-f.reported, "totalDropped", dropped)
+		f.reported = dropped
+	}
+	return batch[:0]
+}
+
+func (f *Forwarder) send(batch []Event) error {
+	backoff := initialBackoff
+	var err error
+	for attempt := 1; attempt <= sendAttempts; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+		err = f.sink.Send(ctx, batch)
+		cancel()
+		if err == nil {
+			return nil
+		}
+		if attempt < sendAttempts {
+			logger.Warn(context.Background(), "audit: delivery failed, retrying", "attempt", attempt, "error", err)
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+	}
+	return err
+}
@@ -0,0 +1,151 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordingSink struct {
+	mu      sync.Mutex
+	batches [][]Event
+	fail    int
+	block   chan struct{}
+	closed  bool
+}
+
+func (s *recordingSink) Send(ctx context.Context, events []Event) error {
+	if s.block != nil {
+		<-s.block
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fail > 0 {
+		s.fail--
+		return errors.New("collector unavailable")
+	}
+	s.batches = append(s.batches, append([]Event(nil), events...))
+	return nil
+}
+
+func (s *recordingSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
+
+func (s *recordingSink) batchSizes() []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sizes := make([]int, len(s.batches))
+	for i, b := range s.batches {
+		sizes[i] = len(b)
+	}
+	return sizes
+}
+
+func closeForwarder(t *testing.T, f *Forwarder) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := f.Close(ctx); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+}
+
+func TestForwarder_BatchesBySize(t *testing.T) {
+	sink := &recordingSink{}
+	f := NewForwarder(sink, 100, 2, time.Hour)
+
+	for i := 0; i < 5; i++ {
+		f.Record(Event{Type: TypeAuthFailure})
+	}
+	closeForwarder(t, f)
+
+	sizes := sink.batchSizes()
+	if len(sizes) != 3 || sizes[0] != 2 || sizes[1] != 2 || sizes[2] != 1 {
+		t.Errorf("expected batches [2 2 1], got %v", sizes)
+	}
+	if f.Sent() != 5 || f.Dropped() != 0 {
+		t.Errorf("expected 5 sent and 0 dropped, got %d and %d", f.Sent(), f.Dropped())
+	}
+	if !sink.closed {
+		t.Error("expected sink to be closed")
+	}
+}
+
+func TestForwarder_FlushesOnInterval(t *testing.T) {
+	sink := &recordingSink{}
+	f := NewForwarder(sink, 100, 100, 10*time.Millisecond)
+	defer closeForwarder(t, f)
+
+	f.Record(Event{Type: TypeAuthFailure})
+
+	deadline := time.Now().Add(2 * time.Second)
+	for f.Sent() != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("expected event to be flushed on interval")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestForwarder_DropsWhenBufferFull(t *testing.T) {
+	sink := &recordingSink{block: make(chan struct{})}
+	f := NewForwarder(sink, 2, 1, time.Hour)
+
+	// The first event is picked up and blocks in Send; two more fill the
+	// buffer and the rest are dropped.
+	f.Record(Event{Type: TypeAuthFailure})
+	time.Sleep(20 * time.Millisecond)
+	for i := 0; i < 5; i++ {
+		f.Record(Event{Type: TypeAuthFailure})
+	}
+
+	if f.Dropped() != 3 {
+		t.Errorf("expected 3 dropped events, got %d", f.Dropped())
+	}
+
+	close(sink.block)
+	closeForwarder(t, f)
+	if f.Sent() != 3 {
+		t.Errorf("expected 3 sent events, got %d", f.Sent())
+	}
+}
+
+func TestForwarder_RetriesThenDrops(t *testing.T) {
+	previous := initialBackoff
+	initialBackoff = time.Millisecond
+	defer func() { initialBackoff = previous }()
+
+	sink := &recordingSink{fail: 2}
+	f := NewForwarder(sink, 10, 1, time.Hour)
+	f.Record(Event{Type: TypeAuthFailure})
+	closeForwarder(t, f)
+
+	if f.Sent() != 1 || f.Dropped() != 0 {
+		t.Errorf("expected delivery on third attempt, got sent=%d dropped=%d", f.Sent(), f.Dropped())
+	}
+
+	sink = &recordingSink{fail: sendAttempts}
+	f = NewForwarder(sink, 10, 1, time.Hour)
+	f.Record(Event{Type: TypeAuthFailure})
+	closeForwarder(t, f)
+
+	if f.Sent() != 0 || f.Dropped() != 1 {
+		t.Errorf("expected batch to be dropped after %d attempts, got sent=%d dropped=%d", sendAttempts, f.Sent(), f.Dropped())
+	}
+}
+
+func TestForwarder_RecordAfterClose(t *testing.T) {
+	f := NewForwarder(&recordingSink{}, 10, 10, time.Hour)
+	closeForwarder(t, f)
+
+	f.Record(Event{Type: TypeAuthFailure})
+	if f.Dropped() != 1 {
+		t.Errorf("expected event recorded after close to be dropped, got %d", f.Dropped())
+	}
+}
@@ -0,0 +1,39 @@
+package audit
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrUnknownSink = errors.New("unknown audit sink")
+
+// SinkConfig selects and configures a Sink.
+type SinkConfig struct {
+	Type          string // "syslog" or "http"
+	SyslogNetwork string // "udp" or "tcp"
+	SyslogAddress string
+	HTTPURL       string
+	HTTPToken     string
+}
+
+func NewSink(cfg SinkConfig) (Sink, error) {
+	switch strings.ToLower(cfg.Type) {
+	case "syslog":
+		if cfg.SyslogAddress == "" {
+			return nil, errors.New("syslog audit sink requires an address")
+		}
+		network := cfg.SyslogNetwork
+		if network == "" {
+			network = "udp"
+		}
+		return NewSyslogSink(network, cfg.SyslogAddress, "warframe-wishlist"), nil
+	case "http":
+		if cfg.HTTPURL == "" {
+			return nil, errors.New("http audit sink requires a URL")
+		}
+		return NewHTTPSink(cfg.HTTPURL, cfg.HTTPToken), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownSink, cfg.Type)
+	}
+}
@@ -0,0 +1,153 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHTTPSink_Send(t *testing.T) {
+	var got []Event
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	events := []Event{{Type: TypeAuthFailure, Outcome: OutcomeFailure}, {Type: TypeDataSync, Outcome: OutcomeSuccess}}
+	if err := NewHTTPSink(server.URL, "secret").Send(context.Background(), events); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if auth != "Bearer secret" {
+		t.Errorf("unexpected Authorization header %q", auth)
+	}
+	if len(got) != 2 || got[1].Type != TypeDataSync {
+		t.Errorf("unexpected events received: %+v", got)
+	}
+}
+
+func TestHTTPSink_Send_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	if err := NewHTTPSink(server.URL, "").Send(context.Background(), []Event{{}}); err == nil {
+		t.Error("expected error for 503 response")
+	}
+}
+
+func TestSyslogSink_UDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("udp unavailable: %v", err)
+	}
+	defer conn.Close()
+
+	sink := NewSyslogSink("udp", conn.LocalAddr().String(), "warframe-wishlist")
+	defer sink.Close()
+
+	event := Event{Time: time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC), Type: TypeAuthFailure, Outcome: OutcomeFailure}
+	if err := sink.Send(context.Background(), []Event{event}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	buf := make([]byte, 4096)
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("failed to read datagram: %v", err)
+	}
+
+	msg := string(buf[:n])
+	// authpriv (10) * 8 + warning (4) = 84
+	if !strings.HasPrefix(msg, "<84>1 2026-10-17T12:00:00Z ") {
+		t.Errorf("unexpected syslog header: %q", msg)
+	}
+	if !strings.Contains(msg, " warframe-wishlist ") || !strings.Contains(msg, " auth.failure - {") {
+		t.Errorf("expected app name, msgid and JSON body, got %q", msg)
+	}
+}
+
+func TestSyslogSink_TCPOctetCounting(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("tcp unavailable: %v", err)
+	}
+	defer listener.Close()
+
+	received := make(chan []string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		var msgs []string
+		for i := 0; i < 2; i++ {
+			lengthStr, err := reader.ReadString(' ')
+			if err != nil {
+				break
+			}
+			length, _ := strconv.Atoi(strings.TrimSpace(lengthStr))
+			msg := make([]byte, length)
+			if _, err := io.ReadFull(reader, msg); err != nil {
+				break
+			}
+			msgs = append(msgs, string(msg))
+		}
+		received <- msgs
+	}()
+
+	sink := NewSyslogSink("tcp", listener.Addr().String(), "warframe-wishlist")
+	defer sink.Close()
+
+	events := []Event{
+		{Time: time.Now(), Type: TypeDataSync, Outcome: OutcomeSuccess},
+		{Time: time.Now(), Type: TypeAuthFailure, Outcome: OutcomeFailure},
+	}
+	if err := sink.Send(context.Background(), events); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	select {
+	case msgs := <-received:
+		if len(msgs) != 2 {
+			t.Fatalf("expected 2 framed messages, got %d", len(msgs))
+		}
+		// authpriv (10) * 8 + notice (5) = 85
+		if !strings.HasPrefix(msgs[0], "<85>1 ") || !strings.HasPrefix(msgs[1], "<84>1 ") {
+			t.Errorf("unexpected messages: %q", msgs)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for messages")
+	}
+}
+
+func TestNewSink(t *testing.T) {
+	if _, err := NewSink(SinkConfig{Type: "syslog", SyslogAddress: "127.0.0.1:514"}); err != nil {
+		t.Errorf("syslog: unexpected error: %v", err)
+	}
+	if _, err := NewSink(SinkConfig{Type: "HTTP", HTTPURL: "https://siem.example.com/ingest"}); err != nil {
+		t.Errorf("http: unexpected error: %v", err)
+	}
+	if _, err := NewSink(SinkConfig{Type: "syslog"}); err == nil {
+		t.Error("expected error for syslog without address")
+	}
+	if _, err := NewSink(SinkConfig{Type: "kafka"}); !errors.Is(err, ErrUnknownSink) {
+		t.Errorf("expected ErrUnknownSink, got %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+// Package buildinfo describes the running binary. Release builds stamp it
+// with ldflags:
+//
+//	go build -ldflags "-X github.com/graytonio/warframe-wishlist/internal/buildinfo.Version=v1.4.0 \
+//	  -X github.com/graytonio/warframe-wishlist/internal/buildinfo.Commit=$(git rev-parse HEAD) \
+//	  -X github.com/graytonio/warframe-wishlist/internal/buildinfo.Date=$(date -u +%Y-%m-%dT%H:%M:%SZ)" ./cmd/server
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+	"time"
+)
+
+// Set with -ldflags -X. Date is RFC 3339.
+var (
+	Version = "dev"
+	Commit  = ""
+	Date    = ""
+)
+
+type Info struct {
+	Version string
+	Commit  string
+	// Date is when the binary was built, or zero if unknown.
+	Date      time.Time
+	GoVersion string
+	// Modified reports a build from a working tree with uncommitted changes,
+	// when the Go toolchain recorded it.
+	Modified bool
+}
+
+// Get returns the stamped build information. Unstamped commit and date fall
+// back to the VCS revision and commit time the Go toolchain records when
+// building from a checkout.
+func Get() Info {
+	info := Info{Version: Version, Commit: Commit, GoVersion: runtime.Version()}
+	date := Date
+	if build, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range build.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = setting.Value
+				}
+			case "vcs.time":
+				if date == "" {
+					date = setting.Value
+				}
+			case "vcs.modified":
+				info.Modified = setting.Value == "true"
+			}
+		}
+	}
+	if t, err := time.Parse(time.RFC3339, date); err == nil {
+		info.Date = t.UTC()
+	}
+	return info
+}
@@ -0,0 +1,33 @@
+package buildinfo
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestGet_UsesStampedValues(t *testing.T) {
+	defer func(version, commit, date string) { Version, Commit, Date = version, commit, date }(Version, Commit, Date)
+	Version, Commit, Date = "v1.4.0", "abc123", "2026-10-01T12:00:00Z"
+
+	info := Get()
+
+	if info.Version != "v1.4.0" || info.Commit != "abc123" {
+		t.Errorf("expected stamped version and commit, got %+v", info)
+	}
+	if !info.Date.Equal(time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected stamped date, got %v", info.Date)
+	}
+	if info.GoVersion != runtime.Version() {
+		t.Errorf("expected go version %s, got %s", runtime.Version(), info.GoVersion)
+	}
+}
+
+func TestGet_IgnoresMalformedDate(t *testing.T) {
+	defer func(date string) { Date = date }(Date)
+	Date = "yesterday"
+
+	if info := Get(); !info.Date.IsZero() {
+		t.Errorf("expected zero date, got %v", info.Date)
+	}
+}
@@ -0,0 +1,70 @@
+// Package cdn emits surrogate keys for caching proxies and purges them after
+// a data sync. Keys are written to both Surrogate-Key (Fastly, space separated)
+// and Cache-Tag (Cloudflare, comma separated) so either provider can cache item
+// responses and invalidate them precisely.
+package cdn
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	SurrogateKeyHeader = "Surrogate-Key"
+	CacheTagHeader     = "Cache-Tag"
+
+	// AllItemsKey tags every response built from item data, so one purge
+	// invalidates everything after a sync regardless of which instance
+	// served the response or what data version it reported.
+	AllItemsKey = "items"
+)
+
+// ItemKey returns the surrogate key for a single item.
+func ItemKey(uniqueName string) string {
+	return "item:" + escapeKey(uniqueName)
+}
+
+// DataVersionKey returns the surrogate key for responses built from a given
+// data sync.
+func DataVersionKey(version string) string {
+	return "data:" + escapeKey(version)
+}
+
+// AddKeys appends keys to both surrogate key headers, skipping keys already
+// present. Must be called before the response header is written.
+func AddKeys(h http.Header, keys ...string) {
+	existing := strings.Fields(h.Get(SurrogateKeyHeader))
+	seen := make(map[string]bool, len(existing)+len(keys))
+	for _, key := range existing {
+		seen[key] = true
+	}
+	for _, key := range keys {
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		existing = append(existing, key)
+	}
+	if len(existing) == 0 {
+		return
+	}
+	h.Set(SurrogateKeyHeader, strings.Join(existing, " "))
+	h.Set(CacheTagHeader, strings.Join(existing, ","))
+}
+
+// escapeKey percent-encodes anything that is not printable ASCII, as well as
+// the space and comma separators and the percent sign itself, so a key always
+// survives both header formats unchanged.
+func escapeKey(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c <= ' ' || c >= 0x7f || c == ',' || c == '%' {
+			fmt.Fprintf(&b, "%%%02X", c)
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
@@ -0,0 +1,50 @@
+package cdn
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestItemKey(t *testing.T) {
+	tests := []struct {
+		uniqueName string
+		expected   string
+	}{
+		{"/Lotus/Powersuits/Ninja/Ninja", "item:/Lotus/Powersuits/Ninja/Ninja"},
+		{"/Lotus/With Space", "item:/Lotus/With%20Space"},
+		{"/Lotus/A,B", "item:/Lotus/A%2CB"},
+		{"/Lotus/100%", "item:/Lotus/100%25"},
+		{"/Lotus/Café", "item:/Lotus/Caf%C3%A9"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.uniqueName, func(t *testing.T) {
+			if key := ItemKey(tt.uniqueName); key != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, key)
+			}
+		})
+	}
+}
+
+func TestAddKeys(t *testing.T) {
+	h := http.Header{}
+
+	AddKeys(h, AllItemsKey, DataVersionKey("v1"))
+	AddKeys(h, ItemKey("/Lotus/A"), AllItemsKey, "")
+
+	if got := h.Get(SurrogateKeyHeader); got != "items data:v1 item:/Lotus/A" {
+		t.Errorf("unexpected %s header: %q", SurrogateKeyHeader, got)
+	}
+	if got := h.Get(CacheTagHeader); got != "items,data:v1,item:/Lotus/A" {
+		t.Errorf("unexpected %s header: %q", CacheTagHeader, got)
+	}
+}
+
+func TestAddKeys_NoKeys(t *testing.T) {
+	h := http.Header{}
+	AddKeys(h)
+
+	if len(h) != 0 {
+		t.Errorf("expected no headers, got %v", h)
+	}
+}
@@ -0,0 +1,119 @@
+package cdn
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Purger invalidates cached responses by surrogate key.
+type Purger interface {
+	PurgeKeys(ctx context.Context, keys []string) error
+}
+
+var ErrUnknownProvider = errors.New("unknown CDN purge provider")
+
+const (
+	fastlyAPIURL     = "https://api.fastly.com"
+	cloudflareAPIURL = "https://api.cloudflare.com/client/v4"
+
+	// Provider limits on keys per purge request.
+	fastlyMaxKeys     = 256
+	cloudflareMaxTags = 30
+)
+
+// NewPurger returns the purger for provider ("fastly" or "cloudflare"). target
+// is the Fastly service ID or the Cloudflare zone ID.
+func NewPurger(provider, target, token string) (Purger, error) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	switch strings.ToLower(provider) {
+	case "fastly":
+		return &FastlyPurger{baseURL: fastlyAPIURL, serviceID: target, token: token, client: client}, nil
+	case "cloudflare":
+		return &CloudflarePurger{baseURL: cloudflareAPIURL, zoneID: target, token: token, client: client}, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownProvider, provider)
+	}
+}
+
+// FastlyPurger purges via the Fastly surrogate key API.
+type FastlyPurger struct {
+	baseURL   string
+	serviceID string
+	token     string
+	client    *http.Client
+}
+
+func (p *FastlyPurger) PurgeKeys(ctx context.Context, keys []string) error {
+	for _, batch := range batches(keys, fastlyMaxKeys) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/service/"+p.serviceID+"/purge", nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Fastly-Key", p.token)
+		req.Header.Set(SurrogateKeyHeader, strings.Join(batch, " "))
+		if err := send(p.client, req); err != nil {
+			return fmt.Errorf("fastly purge: %w", err)
+		}
+	}
+	return nil
+}
+
+// CloudflarePurger purges via the Cloudflare cache tag API.
+type CloudflarePurger struct {
+	baseURL string
+	zoneID  string
+	token   string
+	client  *http.Client
+}
+
+func (p *CloudflarePurger) PurgeKeys(ctx context.Context, keys []string) error {
+	for _, batch := range batches(keys, cloudflareMaxTags) {
+		body, err := json.Marshal(map[string][]string{"tags": batch})
+		if err != nil {
+			return err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/zones/"+p.zoneID+"/purge_cache", bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+p.token)
+		req.Header.Set("Content-Type", "application/json")
+		if err := send(p.client, req); err != nil {
+			return fmt.Errorf("cloudflare purge: %w", err)
+		}
+	}
+	return nil
+}
+
+func send(client *http.Client, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+func batches(keys []string, size int) [][]string {
+	var out [][]string
+	for len(keys) > size {
+		out = append(out, keys[:size])
+		keys = keys[size:]
+	}
+	if len(keys) > 0 {
+		out = append(out, keys)
+	}
+	return out
+}
@@ -0,0 +1,107 @@
+package cdn
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNewPurger(t *testing.T) {
+	for _, provider := range []string{"fastly", "Cloudflare"} {
+		if _, err := NewPurger(provider, "id", "token"); err != nil {
+			t.Errorf("%s: unexpected error: %v", provider, err)
+		}
+	}
+
+	if _, err := NewPurger("akamai", "id", "token"); !errors.Is(err, ErrUnknownProvider) {
+		t.Errorf("expected ErrUnknownProvider, got %v", err)
+	}
+}
+
+func TestFastlyPurger_PurgeKeys(t *testing.T) {
+	var requests []*http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	keys := make([]string, fastlyMaxKeys+1)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("item:/Lotus/%d", i)
+	}
+
+	purger := &FastlyPurger{baseURL: server.URL, serviceID: "svc", token: "secret", client: server.Client()}
+	if err := purger.PurgeKeys(context.Background(), keys); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 batched requests, got %d", len(requests))
+	}
+	for _, r := range requests {
+		if r.Method != http.MethodPost || r.URL.Path != "/service/svc/purge" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if r.Header.Get("Fastly-Key") != "secret" {
+			t.Errorf("expected Fastly-Key header, got %q", r.Header.Get("Fastly-Key"))
+		}
+	}
+	if n := len(strings.Fields(requests[0].Header.Get(SurrogateKeyHeader))); n != fastlyMaxKeys {
+		t.Errorf("expected %d keys in first batch, got %d", fastlyMaxKeys, n)
+	}
+	if got := requests[1].Header.Get(SurrogateKeyHeader); got != keys[fastlyMaxKeys] {
+		t.Errorf("expected last key in second batch, got %q", got)
+	}
+}
+
+func TestCloudflarePurger_PurgeKeys(t *testing.T) {
+	var bodies []map[string][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/zones/zone/purge_cache" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("unexpected Authorization header %q", r.Header.Get("Authorization"))
+		}
+		var body map[string][]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("failed to decode body: %v", err)
+		}
+		bodies = append(bodies, body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	purger := &CloudflarePurger{baseURL: server.URL, zoneID: "zone", token: "secret", client: server.Client()}
+	if err := purger.PurgeKeys(context.Background(), []string{AllItemsKey, "data:v1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(bodies) != 1 || strings.Join(bodies[0]["tags"], ",") != "items,data:v1" {
+		t.Errorf("unexpected purge bodies: %v", bodies)
+	}
+}
+
+func TestPurger_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad token", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	purgers := []Purger{
+		&FastlyPurger{baseURL: server.URL, serviceID: "svc", client: server.Client()},
+		&CloudflarePurger{baseURL: server.URL, zoneID: "zone", client: server.Client()},
+	}
+	for _, purger := range purgers {
+		err := purger.PurgeKeys(context.Background(), []string{AllItemsKey})
+		if err == nil || !strings.Contains(err.Error(), "403") {
+			t.Errorf("%T: expected status error, got %v", purger, err)
+		}
+	}
+}
@@ -1,58 +1,328 @@
 package config
 
 import (
-	"context"
-	"crypto/ecdsa"
 	"os"
 	"strconv"
-
-	"github.com/graytonio/warframe-wishlist/pkg/logger"
-	"github.com/lestrrat-go/jwx/jwk"
+	"strings"
 )
 
 type Config struct {
-	ServerPort           string
-	MongoURI             string
-	MongoDatabase        string
-	SupabaseURL          string
-	SupabaseJWTPublicKey *ecdsa.PublicKey
-	AllowedOrigins       string
-	LogLevel             string
+	ServerPort    string
+	MongoURI      string
+	MongoDatabase string
+	SupabaseURL   string
+	// SupabaseJWKSURL is the key set tokens are verified against, by default
+	// the project's /auth/v1/.well-known/jwks.json. Keys are refetched every
+	// JWKSRefreshSeconds, and early when a token names an unknown kid.
+	SupabaseJWKSURL    string
+	JWKSRefreshSeconds int
+	AllowedOrigins     string
+	// TrustedProxies lists the load balancers and proxies (comma-separated
+	// CIDRs or addresses) whose X-Forwarded-For and X-Real-IP headers name
+	// the client; empty trusts none and logs the connecting address.
+	TrustedProxies string
+	LogLevel       string
+	// LogAnonymize hashes user IDs (with LogAnonymizeKey) and drops remote
+	// addresses in logs and audit events.
+	LogAnonymize    bool
+	LogAnonymizeKey string
+	// IDStrategy picks how request IDs, webhook delivery IDs, share tokens
+	// and webhook secrets are made: "ulid" (sortable by creation) or
+	// "random".
+	IDStrategy string
+	// LoadShedMaxInFlight caps concurrent requests; 0 disables load shedding.
+	LoadShedMaxInFlight     int
+	LoadShedLatencyTargetMs int
+	// SchemaMigrationEnabled runs the background user document migration at startup.
+	SchemaMigrationEnabled bool
+	// DemoMode serves items from local JSON files with in-memory user data and
+	// authenticates every request as DemoUserID. No MongoDB or JWT key needed.
+	DemoMode    bool
+	DemoDataDir string
+	DemoUserID  string
+	// ItemCacheTTLSeconds is how long item lookups stay cached; 0 disables the cache.
+	ItemCacheTTLSeconds int
+	// RedisURL (redis:// or rediss://) enables an item lookup cache shared by
+	// every instance, below the in-memory cache; entries live for
+	// ItemRedisCacheTTLSeconds or until the next data sync.
+	RedisURL                 string
+	ItemRedisCacheTTLSeconds int
+	// ItemCachePrewarmCount is how many of the most wishlisted items (with their
+	// recipe trees) are loaded into the cache at startup and after each data sync.
+	ItemCachePrewarmCount int
+	// ItemStorageLayout is how the item data is stored: "collections", one
+	// collection per category, or "unified", the single items collection
+	// that cmd/migrate-items consolidates them into.
+	ItemStorageLayout string
+	// ItemSearchExcludedCollections lists the item collections (comma
+	// separated, or "none") that search leaves out unless asked for by
+	// category. Their items stay resolvable by uniqueName.
+	ItemSearchExcludedCollections string
+	// ItemSearchTimeoutMs bounds a whole item search across collections;
+	// collections not reached in time are left out of the results.
+	ItemSearchTimeoutMs int
+	// ItemSearchStatsSize is how many query words search remembers the
+	// per-collection hits of, to skip the collections a query cannot match;
+	// 0 disables it. Each word is relearned after ItemSearchStatsTTLSeconds.
+	ItemSearchStatsSize       int
+	ItemSearchStatsTTLSeconds int
+	// MaterialsCacheSize is how many users' resolved materials responses are
+	// kept in memory; 0 disables the cache. Entries live for
+	// MaterialsCacheTTLSeconds.
+	MaterialsCacheSize       int
+	MaterialsCacheTTLSeconds int
+	// MaterialsMaxDepth, MaterialsMaxDistinct and MaterialsMaxResolveMs bound
+	// a single materials resolution (recipe levels, distinct materials and
+	// wall time); hitting one returns a partial result marked truncated. 0
+	// disables that limit.
+	MaterialsMaxDepth     int
+	MaterialsMaxDistinct  int
+	MaterialsMaxResolveMs int
+	// MaterialsGraphLookup fetches each resolution's recipe tree with one
+	// $graphLookup over the item_graph collection instead of one lookup per
+	// recipe level. The graph is rebuilt after every data sync.
+	MaterialsGraphLookup bool
+	// RequestDedup shares one computation between identical concurrent
+	// materials and item detail requests.
+	RequestDedup bool
+	// IdempotencyTTLSeconds is how long the response to a wishlist or
+	// blueprint change sent with an Idempotency-Key is kept for retries; 0
+	// ignores the header.
+	IdempotencyTTLSeconds int
+	// DataSyncToken authenticates the post-sync webhook; empty disables the route.
+	DataSyncToken string
+	// AdminToken grants the admin role, which reaches every staff route,
+	// such as per-user tracing under /internal/users, as an admin's JWT
+	// does. Empty grants it to JWTs only.
+	AdminToken string
+	// ModeratorToken grants the moderator role: the moderation routes under
+	// /internal/moderation, but none of the admin routes. Empty grants it to
+	// JWTs only.
+	ModeratorToken string
+	// DeprecationsAnnounced lists the deprecations (comma-separated IDs) whose
+	// uses get Deprecation headers and warnings and are counted per client.
+	DeprecationsAnnounced string
+	// DataVersion is the item data version reported until the first sync webhook.
+	DataVersion string
+	// ItemRefreshIntervalMinutes schedules a background re-sync of the item
+	// collections from ItemRefreshSource (the WFCD export by default); 0
+	// disables it. Enable it on a single instance.
+	ItemRefreshIntervalMinutes int
+	ItemRefreshSource          string
+	// WorldStatePollSeconds polls the game's world state from
+	// WorldStateSource (the official PC API by default) for the
+	// opportunities endpoint; 0 disables it.
+	WorldStatePollSeconds int
+	WorldStateSource      string
+	// NotificationsPollSeconds checks subscribed users for finished foundry
+	// builds and new opportunities, and sends due notifications; 0 disables
+	// it. Enable it on a single instance. The VAPID key pair (base64url, as
+	// web-push tools print it) and subject enable Web Push channels.
+	// NotificationsAllowPrivateURLs lets channels use plain http and private
+	// addresses, for local development only.
+	NotificationsPollSeconds      int
+	VAPIDPublicKey                string
+	VAPIDPrivateKey               string
+	VAPIDSubject                  string
+	NotificationsAllowPrivateURLs bool
+	// IntegrationsDispatchSeconds sends due item change webhooks to the
+	// integrations registered through the admin routes; 0 disables them.
+	// NotificationsAllowPrivateURLs applies to their URLs too.
+	IntegrationsDispatchSeconds int
+	// HooksWebhookURL forwards every extension hook event to an external
+	// extension as a webhook signed with HooksWebhookSecret; empty disables
+	// the bridge.
+	HooksWebhookURL    string
+	HooksWebhookSecret string
+	// LiveMaxConnections caps the open workspace live connections on this
+	// instance; 0 disables the live endpoint.
+	LiveMaxConnections int
+	// UserMaxWishlistItems and UserMaxOwnedBlueprints cap what one user can
+	// store; 0 leaves them uncapped. Mutation responses carry a usage warning
+	// once a user reaches UsageWarningPercent of a cap.
+	UserMaxWishlistItems   int
+	UserMaxOwnedBlueprints int
+	UsageWarningPercent    int
+	// OnboardingStarterItems seeds the wishlist of each new user (see
+	// services.ParseStarterItems); empty leaves new wishlists empty.
+	OnboardingStarterItems string
+	// CDNPurgeProvider ("fastly" or "cloudflare") enables surrogate key purges
+	// after each data sync. CDNPurgeServiceID is the Fastly service ID or the
+	// Cloudflare zone ID.
+	CDNPurgeProvider  string
+	CDNPurgeServiceID string
+	CDNPurgeToken     string
+	// AggregateExportIntervalHours schedules the anonymized aggregate dataset
+	// export; 0 disables it. The remaining AggregateExport fields select the
+	// storage backend ("file" or "s3") and its settings.
+	AggregateExportIntervalHours int
+	AggregateExportBackend       string
+	AggregateExportDir           string
+	AggregateExportEndpoint      string
+	AggregateExportRegion        string
+	AggregateExportBucket        string
+	AggregateExportAccessKeyID   string
+	AggregateExportSecretKey     string
+	AggregateExportPrefix        string
+	AggregateExportTopItems      int
+	// AggregateExportMinUsers withholds any figure based on fewer users.
+	AggregateExportMinUsers int
+	// AuditSink ("syslog" or "http") forwards security events to a SIEM; empty
+	// disables audit forwarding.
+	AuditSink                 string
+	AuditSyslogNetwork        string
+	AuditSyslogAddress        string
+	AuditHTTPURL              string
+	AuditHTTPToken            string
+	AuditBufferSize           int
+	AuditBatchSize            int
+	AuditFlushIntervalSeconds int
+	// HouseholdApprovalsEnabled lets accounts link to a manager account that
+	// must approve wishlist additions above a per-member quantity threshold.
+	HouseholdApprovalsEnabled bool
+	// KioskMode serves only item search/detail and the public wishlists in
+	// KioskWishlistsFile, without authentication, and rejects all writes.
+	KioskMode          bool
+	KioskWishlistsFile string
+	// Region names this instance's region, reported on every response.
+	// Setting PrimaryRegionURL makes it a read region: its MONGO_URI points at
+	// a nearby replica (readPreference=nearest), reads are served locally and
+	// writes are forwarded to the primary region's API. A client's reads are
+	// forwarded too for RegionStickySeconds after each of its writes, so it
+	// does not read its own change back from a lagging replica.
+	Region              string
+	PrimaryRegionURL    string
+	RegionStickySeconds int
+	// Environment names the deployment (APP_ENV); anything other than
+	// "production" allows staging-only features such as fault injection.
+	Environment string
+	// FaultInjectionRules delays and fails a share of API requests per route
+	// (see middleware.ParseFaultRules); refused in production.
+	FaultInjectionRules string
 }
 
 func Load() *Config {
+	demoMode := getEnvBool("DEMO_MODE", false)
+	kioskMode := getEnvBool("KIOSK_MODE", false)
+
 	return &Config{
-		ServerPort:           getEnv("SERVER_PORT", "8080"),
-		MongoURI:             getEnv("MONGO_URI", "mongodb://localhost:27017"),
-		MongoDatabase:        getEnv("MONGO_DATABASE", "warframe"),
-		SupabaseURL:          getEnv("SUPABASE_URL", ""),
-		SupabaseJWTPublicKey: parseJWTPublicKey(getEnv("SUPABASE_JWT_PUBLIC_KEY", "")),
-		AllowedOrigins:       getEnv("ALLOWED_ORIGINS", "http://localhost:3000"),
-		LogLevel:             getEnv("LOG_LEVEL", "info"),
-	}
-}
+		ServerPort:               getEnv("SERVER_PORT", "8080"),
+		MongoURI:                 getEnv("MONGO_URI", "mongodb://localhost:27017"),
+		MongoDatabase:            getEnv("MONGO_DATABASE", "warframe"),
+		SupabaseURL:              getEnv("SUPABASE_URL", ""),
+		SupabaseJWKSURL:          getEnv("SUPABASE_JWKS_URL", defaultJWKSURL(getEnv("SUPABASE_URL", ""))),
+		JWKSRefreshSeconds:       getEnvInt("JWKS_REFRESH_SECONDS", 600),
+		AllowedOrigins:           getEnv("ALLOWED_ORIGINS", "http://localhost:3000"),
+		TrustedProxies:           getEnv("TRUSTED_PROXIES", ""),
+		LogLevel:                 getEnv("LOG_LEVEL", "info"),
+		LogAnonymize:             getEnvBool("LOG_ANONYMIZE", false),
+		LogAnonymizeKey:          getEnv("LOG_ANONYMIZE_KEY", ""),
+		IDStrategy:               getEnv("ID_STRATEGY", "ulid"),
+		LoadShedMaxInFlight:      getEnvInt("LOAD_SHED_MAX_IN_FLIGHT", 0),
+		LoadShedLatencyTargetMs:  getEnvInt("LOAD_SHED_LATENCY_TARGET_MS", 500),
+		SchemaMigrationEnabled:   getEnvBool("SCHEMA_MIGRATION_ENABLED", true),
+		DemoMode:                 demoMode,
+		DemoDataDir:              getEnv("DEMO_DATA_DIR", "json"),
+		DemoUserID:               getEnv("DEMO_USER_ID", "demo-user"),
+		ItemCacheTTLSeconds:      getEnvInt("ITEM_CACHE_TTL_SECONDS", 900),
+		RedisURL:                 getEnv("REDIS_URL", ""),
+		ItemRedisCacheTTLSeconds: getEnvInt("ITEM_REDIS_CACHE_TTL_SECONDS", 86400),
+		ItemCachePrewarmCount:    getEnvInt("ITEM_CACHE_PREWARM_COUNT", 500),
+		MaterialsCacheSize:       getEnvInt("MATERIALS_CACHE_SIZE", 1000),
+		MaterialsCacheTTLSeconds: getEnvInt("MATERIALS_CACHE_TTL_SECONDS", 300),
+		MaterialsMaxDepth:        getEnvInt("MATERIALS_MAX_DEPTH", 32),
+		MaterialsMaxDistinct:     getEnvInt("MATERIALS_MAX_DISTINCT", 5000),
+		MaterialsMaxResolveMs:    getEnvInt("MATERIALS_MAX_RESOLVE_MS", 5000),
+		MaterialsGraphLookup:     getEnvBool("MATERIALS_GRAPH_LOOKUP", false),
+		RequestDedup:             getEnvBool("REQUEST_DEDUP", true),
+		IdempotencyTTLSeconds:    getEnvInt("IDEMPOTENCY_TTL_SECONDS", 86400),
+		DataSyncToken:            getEnv("DATA_SYNC_TOKEN", ""),
+		AdminToken:               getEnv("ADMIN_TOKEN", ""),
+		ModeratorToken:           getEnv("MODERATOR_TOKEN", ""),
+		DeprecationsAnnounced:    getEnv("DEPRECATIONS_ANNOUNCED", ""),
+		DataVersion:              getEnv("DATA_VERSION", ""),
+		CDNPurgeProvider:         getEnv("CDN_PURGE_PROVIDER", ""),
+		CDNPurgeServiceID:        getEnv("CDN_PURGE_SERVICE_ID", ""),
+		CDNPurgeToken:            getEnv("CDN_PURGE_TOKEN", ""),
 
-func parseJWTPublicKey(publicKey string) *ecdsa.PublicKey {
-	key, err := jwk.ParseKey([]byte(publicKey))
-	if err != nil {
-		logger.Error(context.Background(), "failed to parse JWT public key", "error", err)
-		panic(err)
-	}
+		ItemStorageLayout:             getEnv("ITEM_STORAGE_LAYOUT", "collections"),
+		ItemSearchExcludedCollections: getEnv("ITEM_SEARCH_EXCLUDED_COLLECTIONS", "node,enemy"),
+		ItemSearchTimeoutMs:           getEnvInt("ITEM_SEARCH_TIMEOUT_MS", 5000),
+		ItemSearchStatsSize:           getEnvInt("ITEM_SEARCH_STATS_SIZE", 10000),
+		ItemSearchStatsTTLSeconds:     getEnvInt("ITEM_SEARCH_STATS_TTL_SECONDS", 3600),
 
-	var raw interface{}
-	err = key.Raw(&raw)
-	if err != nil {
-		logger.Error(context.Background(), "failed to get raw key: %v", err)
-		panic(err)
-	}
+		ItemRefreshIntervalMinutes: getEnvInt("ITEM_REFRESH_INTERVAL_MINUTES", 0),
+		ItemRefreshSource:          getEnv("ITEM_REFRESH_SOURCE", ""),
+
+		WorldStatePollSeconds: getEnvInt("WORLDSTATE_POLL_SECONDS", 0),
+		WorldStateSource:      getEnv("WORLDSTATE_SOURCE", ""),
+
+		NotificationsPollSeconds:      getEnvInt("NOTIFICATIONS_POLL_SECONDS", 0),
+		VAPIDPublicKey:                getEnv("VAPID_PUBLIC_KEY", ""),
+		VAPIDPrivateKey:               getEnv("VAPID_PRIVATE_KEY", ""),
+		VAPIDSubject:                  getEnv("VAPID_SUBJECT", ""),
+		NotificationsAllowPrivateURLs: getEnvBool("NOTIFICATIONS_ALLOW_PRIVATE_URLS", false),
+
+		IntegrationsDispatchSeconds: getEnvInt("INTEGRATIONS_DISPATCH_SECONDS", 30),
+
+		HooksWebhookURL:    getEnv("HOOKS_WEBHOOK_URL", ""),
+		HooksWebhookSecret: getEnv("HOOKS_WEBHOOK_SECRET", ""),
+
+		LiveMaxConnections: getEnvInt("LIVE_MAX_CONNECTIONS", 1000),
+
+		UserMaxWishlistItems:   getEnvInt("USER_MAX_WISHLIST_ITEMS", 2000),
+		UserMaxOwnedBlueprints: getEnvInt("USER_MAX_OWNED_BLUEPRINTS", 2000),
+		UsageWarningPercent:    getEnvInt("USAGE_WARNING_PERCENT", 80),
+
+		OnboardingStarterItems: getEnv("ONBOARDING_STARTER_ITEMS", ""),
+
+		AggregateExportIntervalHours: getEnvInt("AGGREGATE_EXPORT_INTERVAL_HOURS", 0),
+		AggregateExportBackend:       getEnv("AGGREGATE_EXPORT_BACKEND", "file"),
+		AggregateExportDir:           getEnv("AGGREGATE_EXPORT_DIR", "exports"),
+		AggregateExportEndpoint:      getEnv("AGGREGATE_EXPORT_ENDPOINT", ""),
+		AggregateExportRegion:        getEnv("AGGREGATE_EXPORT_REGION", "us-east-1"),
+		AggregateExportBucket:        getEnv("AGGREGATE_EXPORT_BUCKET", ""),
+		AggregateExportAccessKeyID:   getEnv("AGGREGATE_EXPORT_ACCESS_KEY_ID", ""),
+		AggregateExportSecretKey:     getEnv("AGGREGATE_EXPORT_SECRET_ACCESS_KEY", ""),
+		AggregateExportPrefix:        getEnv("AGGREGATE_EXPORT_PREFIX", "aggregates"),
+		AggregateExportTopItems:      getEnvInt("AGGREGATE_EXPORT_TOP_ITEMS", 1000),
+		AggregateExportMinUsers:      getEnvInt("AGGREGATE_EXPORT_MIN_USERS", 5),
 
-	public, ok := raw.(*ecdsa.PublicKey)
-	if !ok {
-		logger.Error(context.Background(), "failed to cast raw key to *ecdsa.PublicKey")
-		panic("failed to cast raw key to *ecdsa.PublicKey")
+		AuditSink:                 getEnv("AUDIT_SINK", ""),
+		AuditSyslogNetwork:        getEnv("AUDIT_SYSLOG_NETWORK", "udp"),
+		AuditSyslogAddress:        getEnv("AUDIT_SYSLOG_ADDRESS", ""),
+		AuditHTTPURL:              getEnv("AUDIT_HTTP_URL", ""),
+		AuditHTTPToken:            getEnv("AUDIT_HTTP_TOKEN", ""),
+		AuditBufferSize:           getEnvInt("AUDIT_BUFFER_SIZE", 1000),
+		AuditBatchSize:            getEnvInt("AUDIT_BATCH_SIZE", 100),
+		AuditFlushIntervalSeconds: getEnvInt("AUDIT_FLUSH_INTERVAL_SECONDS", 5),
+
+		HouseholdApprovalsEnabled: getEnvBool("HOUSEHOLD_APPROVALS_ENABLED", false),
+
+		KioskMode:          kioskMode,
+		KioskWishlistsFile: getEnv("KIOSK_WISHLISTS_FILE", ""),
+
+		Region:              getEnv("REGION", ""),
+		PrimaryRegionURL:    getEnv("PRIMARY_REGION_URL", ""),
+		RegionStickySeconds: getEnvInt("REGION_STICKY_SECONDS", 10),
+
+		Environment:         getEnv("APP_ENV", "production"),
+		FaultInjectionRules: getEnv("FAULT_INJECTION_RULES", ""),
 	}
+}
+
+// Production reports whether this is a production deployment. An unset
+// environment counts as production.
+func (c *Config) Production() bool {
+	return c.Environment == "" || strings.EqualFold(c.Environment, "production")
+}
 
-	return public
+// defaultJWKSURL is where Supabase publishes a project's signing keys.
+func defaultJWKSURL(supabaseURL string) string {
+	if supabaseURL == "" {
+		return ""
+	}
+	return strings.TrimSuffix(supabaseURL, "/") + "/auth/v1/.well-known/jwks.json"
 }
 
 func getEnv(key, defaultValue string) string {
@@ -70,3 +340,12 @@ func getEnvInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
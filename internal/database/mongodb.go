@@ -2,8 +2,11 @@ package database
 
 import (
 	"context"
+	"sync"
 	"time"
 
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"go.mongodb.org/mongo-driver/event"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -11,13 +14,35 @@ import (
 type MongoDB struct {
 	Client   *mongo.Client
 	Database *mongo.Database
+
+	mu       sync.RWMutex
+	topology Topology
+}
+
+// Topology is the driver's latest view of the deployment. Writable is false
+// while a replica set has no primary, such as during an election.
+type Topology struct {
+	Kind      string
+	Writable  bool
+	Servers   int
+	ChangedAt time.Time
 }
 
 func NewMongoDB(uri, database string) (*MongoDB, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	clientOptions := options.Client().ApplyURI(uri)
+	m := &MongoDB{}
+	// Retryable reads and writes are the driver default; they are set
+	// explicitly because a failover relies on them: an operation interrupted
+	// by a primary stepping down is retried once against the new primary.
+	clientOptions := options.Client().
+		ApplyURI(uri).
+		SetRetryWrites(true).
+		SetRetryReads(true).
+		SetServerMonitor(&event.ServerMonitor{
+			TopologyDescriptionChanged: m.topologyChanged,
+		})
 	client, err := mongo.Connect(ctx, clientOptions)
 	if err != nil {
 		return nil, err
@@ -27,10 +52,9 @@ func NewMongoDB(uri, database string) (*MongoDB, error) {
 		return nil, err
 	}
 
-	return &MongoDB{
-		Client:   client,
-		Database: client.Database(database),
-	}, nil
+	m.Client = client
+	m.Database = client.Database(database)
+	return m, nil
 }
 
 func (m *MongoDB) Close() error {
@@ -42,3 +66,34 @@ func (m *MongoDB) Close() error {
 func (m *MongoDB) Collection(name string) *mongo.Collection {
 	return m.Database.Collection(name)
 }
+
+// Topology returns the latest topology reported by the driver.
+func (m *MongoDB) Topology() Topology {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.topology
+}
+
+func (m *MongoDB) topologyChanged(e *event.TopologyDescriptionChangedEvent) {
+	topology := Topology{
+		Kind:      e.NewDescription.Kind.String(),
+		Writable:  e.NewDescription.HasWritableServer(),
+		Servers:   len(e.NewDescription.Servers),
+		ChangedAt: time.Now(),
+	}
+
+	m.mu.Lock()
+	previous := m.topology
+	m.topology = topology
+	m.mu.Unlock()
+
+	ctx := context.Background()
+	switch {
+	case previous.Writable && !topology.Writable:
+		logger.Warn(ctx, "database: MongoDB lost its writable server, waiting for failover", "topology", topology.Kind, "servers", topology.Servers)
+	case !previous.Writable && topology.Writable && !previous.ChangedAt.IsZero():
+		logger.Info(ctx, "database: MongoDB writable server available", "topology", topology.Kind, "servers", topology.Servers)
+	case previous.Kind != topology.Kind:
+		logger.Debug(ctx, "database: MongoDB topology changed", "topology", topology.Kind, "servers", topology.Servers)
+	}
+}
@@ -0,0 +1,39 @@
+package database
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo/description"
+)
+
+func topologyEvent(kind description.TopologyKind, servers ...description.ServerKind) *event.TopologyDescriptionChangedEvent {
+	topology := description.Topology{Kind: kind}
+	for _, server := range servers {
+		topology.Servers = append(topology.Servers, description.Server{Kind: server})
+	}
+	return &event.TopologyDescriptionChangedEvent{NewDescription: topology}
+}
+
+func TestMongoDB_TracksTopology(t *testing.T) {
+	m := &MongoDB{}
+	if m.Topology().Writable {
+		t.Fatal("expected no writable server before the first event")
+	}
+
+	m.topologyChanged(topologyEvent(description.ReplicaSetWithPrimary, description.RSPrimary, description.RSSecondary, description.RSSecondary))
+	topology := m.Topology()
+	if !topology.Writable || topology.Kind != "ReplicaSetWithPrimary" || topology.Servers != 3 || topology.ChangedAt.IsZero() {
+		t.Errorf("unexpected topology with a primary: %+v", topology)
+	}
+
+	m.topologyChanged(topologyEvent(description.ReplicaSetNoPrimary, description.RSSecondary, description.RSSecondary, description.Unknown))
+	if topology := m.Topology(); topology.Writable || topology.Kind != "ReplicaSetNoPrimary" {
+		t.Errorf("expected no writable server during an election, got %+v", topology)
+	}
+
+	m.topologyChanged(topologyEvent(description.Single, description.Standalone))
+	if topology := m.Topology(); !topology.Writable {
+		t.Errorf("expected a standalone server to be writable, got %+v", topology)
+	}
+}
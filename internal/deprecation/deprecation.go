@@ -0,0 +1,180 @@
+// Package deprecation warns clients that use fields and parameters slated
+// for removal, and counts which clients still do. A deprecation is only
+// announced once it is listed in configuration, so a notice can ship ahead
+// of its replacement.
+package deprecation
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/pkg/response"
+)
+
+const (
+	// ClientIDHeader identifies the calling client, e.g. "wfw-android/2.3",
+	// so uses can be counted per client.
+	ClientIDHeader = "X-Client-ID"
+
+	// DeprecationHeader carries the deprecation date of the request's
+	// deprecated use as "@<unix seconds>" (RFC 9745).
+	DeprecationHeader = "Deprecation"
+	// SunsetHeader carries the date the deprecated use stops working as an
+	// HTTP date (RFC 8594).
+	SunsetHeader = "Sunset"
+
+	// UnknownClient counts requests without a usable ClientIDHeader.
+	UnknownClient = "unknown"
+	// OtherClients counts the clients beyond maxClients.
+	OtherClients = "other"
+
+	maxClientIDLength = 64
+	// maxClients bounds the clients tracked per deprecation, since client IDs
+	// are chosen by callers.
+	maxClients = 500
+)
+
+// Deprecation is a field or parameter slated for removal.
+type Deprecation struct {
+	ID      string
+	Message string
+	Since   time.Time
+	Sunset  time.Time
+}
+
+// WishlistBareItems is GET /wishlist without ?expand=items, whose items carry
+// only their uniqueName.
+var WishlistBareItems = Deprecation{
+	ID:      "wishlist.bareItems",
+	Message: "wishlist items without item details are deprecated; request ?expand=items",
+	Since:   time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC),
+}
+
+// catalog lists every deprecation by ID.
+var catalog = map[string]Deprecation{
+	WishlistBareItems.ID: WishlistBareItems,
+}
+
+// Tracker announces deprecations and counts their uses per client. A nil
+// Tracker announces nothing.
+type Tracker struct {
+	announced map[string]bool
+
+	mu   sync.Mutex
+	uses map[string]map[string]*clientUse
+}
+
+type clientUse struct {
+	count    int64
+	lastSeen time.Time
+}
+
+// NewTracker returns a Tracker announcing the deprecations whose IDs are
+// listed, comma separated, in announced. It fails on an ID that names no
+// deprecation.
+func NewTracker(announced string) (*Tracker, error) {
+	t := &Tracker{
+		announced: make(map[string]bool),
+		uses:      make(map[string]map[string]*clientUse),
+	}
+	for _, id := range strings.Split(announced, ",") {
+		if id = strings.TrimSpace(id); id == "" {
+			continue
+		}
+		if _, ok := catalog[id]; !ok {
+			return nil, fmt.Errorf("unknown deprecation %q", id)
+		}
+		t.announced[id] = true
+	}
+	return t, nil
+}
+
+// Use records that r used d and, when d is announced, warns the client with
+// the Deprecation and Sunset headers and a response.Warning. It must be
+// called before the response is written.
+func (t *Tracker) Use(w http.ResponseWriter, r *http.Request, d Deprecation) {
+	if t == nil || !t.announced[d.ID] {
+		return
+	}
+	w.Header().Set(DeprecationHeader, "@"+strconv.FormatInt(d.Since.Unix(), 10))
+	if !d.Sunset.IsZero() {
+		w.Header().Set(SunsetHeader, d.Sunset.UTC().Format(http.TimeFormat))
+	}
+	response.Warn(w, response.Warning{Code: response.CodeDeprecated, ID: d.ID, Message: d.Message})
+	t.record(d.ID, ClientID(r), time.Now())
+}
+
+func (t *Tracker) record(id, client string, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	clients := t.uses[id]
+	if clients == nil {
+		clients = make(map[string]*clientUse)
+		t.uses[id] = clients
+	}
+	use, ok := clients[client]
+	if !ok {
+		if len(clients) >= maxClients {
+			client = OtherClients
+		}
+		if use = clients[client]; use == nil {
+			use = &clientUse{}
+			clients[client] = use
+		}
+	}
+	use.count++
+	use.lastSeen = now
+}
+
+// Usage reports the uses of each announced deprecation, by ID, with its
+// clients busiest first.
+func (t *Tracker) Usage() []models.DeprecationUsage {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	usage := make([]models.DeprecationUsage, 0, len(t.announced))
+	for id := range t.announced {
+		d := catalog[id]
+		u := models.DeprecationUsage{ID: d.ID, Message: d.Message, Since: d.Since, Sunset: d.Sunset, Clients: []models.DeprecationClient{}}
+		for client, use := range t.uses[id] {
+			u.Total += use.count
+			u.Clients = append(u.Clients, models.DeprecationClient{ClientID: client, Count: use.count, LastSeen: use.lastSeen})
+		}
+		sort.Slice(u.Clients, func(i, j int) bool {
+			if u.Clients[i].Count != u.Clients[j].Count {
+				return u.Clients[i].Count > u.Clients[j].Count
+			}
+			return u.Clients[i].ClientID < u.Clients[j].ClientID
+		})
+		usage = append(usage, u)
+	}
+	sort.Slice(usage, func(i, j int) bool { return usage[i].ID < usage[j].ID })
+	return usage
+}
+
+// ClientID returns r's ClientIDHeader, or UnknownClient if it is missing,
+// too long or holds anything but letters, digits and "._-/".
+func ClientID(r *http.Request) string {
+	id := r.Header.Get(ClientIDHeader)
+	if id == "" || len(id) > maxClientIDLength {
+		return UnknownClient
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '.', c == '_', c == '-', c == '/':
+		default:
+			return UnknownClient
+		}
+	}
+	return id
+}
@@ -0,0 +1,110 @@
+package deprecation
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/pkg/response"
+)
+
+func TestNewTracker(t *testing.T) {
+	if _, err := NewTracker(" wishlist.bareItems, "); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := NewTracker("wishlist.bareItems,items.legacy"); err == nil {
+		t.Error("expected an error for an unknown deprecation")
+	}
+}
+
+func TestTracker_Use(t *testing.T) {
+	sunset := Deprecation{ID: WishlistBareItems.ID, Message: WishlistBareItems.Message, Since: WishlistBareItems.Since, Sunset: time.Date(2027, 4, 1, 0, 0, 0, 0, time.UTC)}
+	tracker, err := NewTracker(WishlistBareItems.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	handler := response.Warnings(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tracker.Use(w, r, sunset)
+		response.JSON(w, http.StatusOK, map[string]int{"count": 2})
+	}))
+
+	for _, client := range []string{"wfw-web/1.0", "wfw-android/2.3", "wfw-android/2.3", "", "bad client!"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/wishlist", nil)
+		if client != "" {
+			req.Header.Set(ClientIDHeader, client)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		if got := rr.Header().Get(DeprecationHeader); got != fmt.Sprintf("@%d", WishlistBareItems.Since.Unix()) {
+			t.Errorf("unexpected %s header: %q", DeprecationHeader, got)
+		}
+		if got := rr.Header().Get(SunsetHeader); got != "Thu, 01 Apr 2027 00:00:00 GMT" {
+			t.Errorf("unexpected %s header: %q", SunsetHeader, got)
+		}
+		var body struct {
+			Warnings []response.Warning `json:"warnings"`
+		}
+		if err := json.NewDecoder(rr.Body).Decode(&body); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if len(body.Warnings) != 1 || body.Warnings[0].ID != WishlistBareItems.ID || body.Warnings[0].Code != response.CodeDeprecated {
+			t.Errorf("expected a deprecation warning, got %+v", body.Warnings)
+		}
+	}
+
+	usage := tracker.Usage()
+	if len(usage) != 1 || usage[0].ID != WishlistBareItems.ID || usage[0].Total != 5 {
+		t.Fatalf("expected 5 uses of %s, got %+v", WishlistBareItems.ID, usage)
+	}
+	var clients []string
+	for _, client := range usage[0].Clients {
+		clients = append(clients, fmt.Sprintf("%s=%d", client.ClientID, client.Count))
+	}
+	if fmt.Sprint(clients) != "[unknown=2 wfw-android/2.3=2 wfw-web/1.0=1]" {
+		t.Errorf("unexpected clients %v", clients)
+	}
+}
+
+func TestTracker_NotAnnounced(t *testing.T) {
+	var nilTracker *Tracker
+	tracker, err := NewTracker("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, tr := range []*Tracker{nilTracker, tracker} {
+		rr := httptest.NewRecorder()
+		tr.Use(rr, httptest.NewRequest(http.MethodGet, "/api/v1/wishlist", nil), WishlistBareItems)
+
+		if len(rr.Header()) != 0 {
+			t.Errorf("expected no headers, got %v", rr.Header())
+		}
+		if usage := tr.Usage(); len(usage) != 0 {
+			t.Errorf("expected no usage, got %+v", usage)
+		}
+	}
+}
+
+func TestTracker_BoundsClients(t *testing.T) {
+	tracker, err := NewTracker(WishlistBareItems.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Now()
+	for i := 0; i < maxClients+10; i++ {
+		tracker.record(WishlistBareItems.ID, fmt.Sprintf("client-%d", i), now)
+	}
+	tracker.record(WishlistBareItems.ID, "client-0", now)
+
+	usage := tracker.Usage()[0]
+	if len(usage.Clients) != maxClients+1 || usage.Total != maxClients+11 {
+		t.Fatalf("expected %d clients and %d uses, got %d and %d", maxClients+1, maxClients+11, len(usage.Clients), usage.Total)
+	}
+	if usage.Clients[0].ClientID != OtherClients || usage.Clients[0].Count != 10 {
+		t.Errorf("expected the overflow counted as %q, got %+v", OtherClients, usage.Clients[0])
+	}
+}
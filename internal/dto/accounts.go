@@ -0,0 +1,403 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type OwnedBlueprints struct {
+	ID         primitive.ObjectID `json:"id"`
+	UserID     string             `json:"userId"`
+	Blueprints []OwnedBlueprint   `json:"blueprints"`
+	Components []OwnedComponent   `json:"components"`
+	CreatedAt  time.Time          `json:"createdAt"`
+	UpdatedAt  time.Time          `json:"updatedAt"`
+}
+
+type OwnedBlueprint struct {
+	UniqueName string    `json:"uniqueName"`
+	Quantity   int       `json:"quantity"`
+	AddedAt    time.Time `json:"addedAt"`
+}
+
+// OwnedComponents lists the crafted components a user already owns.
+type OwnedComponents struct {
+	UserID     string           `json:"userId"`
+	Components []OwnedComponent `json:"components"`
+}
+
+type OwnedComponent struct {
+	UniqueName string    `json:"uniqueName"`
+	Count      int       `json:"count"`
+	AddedAt    time.Time `json:"addedAt"`
+}
+
+// UserUsage is how much a user stores against the per-user caps.
+type UserUsage struct {
+	UserID    string          `json:"userId"`
+	Resources []ResourceUsage `json:"resources"`
+}
+
+// ResourceUsage is one capped or counted resource; limit is 0 when the
+// resource is uncapped, and warning is set once used nears the limit.
+type ResourceUsage struct {
+	Resource string `json:"resource"`
+	Used     int    `json:"used"`
+	Limit    int    `json:"limit"`
+	Warning  bool   `json:"warning"`
+}
+
+// AdminUser is what the API stores for a user, as the admin API reports it.
+// Accounts live in Supabase, so an ID the API has no data for reports zero
+// usage rather than an error.
+type AdminUser struct {
+	UserID    string          `json:"userId"`
+	Resources []ResourceUsage `json:"resources"`
+	// Trace is null unless support is tracing the user's requests.
+	Trace *UserTrace `json:"trace"`
+}
+
+type OwnedMaterials struct {
+	UserID    string          `json:"userId"`
+	Materials []OwnedMaterial `json:"materials"`
+}
+
+type OwnedMaterial struct {
+	UniqueName string    `json:"uniqueName"`
+	Count      int       `json:"count"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+type MasteryProfile struct {
+	UserID  string         `json:"userId"`
+	Entries []MasteryEntry `json:"entries"`
+}
+
+type MasteryEntry struct {
+	UniqueName string    `json:"uniqueName"`
+	Status     string    `json:"status"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+type MasterySummary struct {
+	UserID          string                   `json:"userId"`
+	Buildable       int                      `json:"buildable"`
+	Built           int                      `json:"built"`
+	Mastered        int                      `json:"mastered"`
+	MasteredPercent float64                  `json:"masteredPercent"`
+	Categories      []MasteryCategorySummary `json:"categories"`
+}
+
+type MasteryCategorySummary struct {
+	Collection string `json:"collection"`
+	Buildable  int    `json:"buildable"`
+	Built      int    `json:"built"`
+	Mastered   int    `json:"mastered"`
+}
+
+type UserSettings struct {
+	ID                  primitive.ObjectID    `json:"id"`
+	UserID              string                `json:"userId"`
+	TimeZone            string                `json:"timeZone"`
+	DefaultQuantities   []DefaultQuantityRule `json:"defaultQuantities"`
+	PublicWishlist      bool                  `json:"publicWishlist"`
+	MutedMilestones     []string              `json:"mutedMilestones"`
+	MaterialsRounding   string                `json:"materialsRounding"`
+	SkipStarterWishlist bool                  `json:"skipStarterWishlist"`
+	DigestTime          string                `json:"digestTime"`
+	CreatedAt           time.Time             `json:"createdAt"`
+	UpdatedAt           time.Time             `json:"updatedAt"`
+}
+
+// DefaultQuantityRule is the quantity items of a category, and of a type
+// within it when type is set, are added with when no quantity is given. A
+// rule with a type takes precedence over one for the whole category.
+type DefaultQuantityRule struct {
+	Category string `json:"category"`
+	Type     string `json:"type"`
+	Quantity int    `json:"quantity"`
+}
+
+// UserTrace is a support-enabled tracing window for one user.
+type UserTrace struct {
+	UserID    string    `json:"userId"`
+	Until     time.Time `json:"until"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type HouseholdLink struct {
+	ID                primitive.ObjectID `json:"id"`
+	ManagerID         string             `json:"managerId"`
+	MemberID          string             `json:"memberId"`
+	QuantityThreshold int                `json:"quantityThreshold"`
+	Status            string             `json:"status"`
+	CreatedAt         time.Time          `json:"createdAt"`
+	UpdatedAt         time.Time          `json:"updatedAt"`
+}
+
+type PendingChange struct {
+	ID         primitive.ObjectID `json:"id"`
+	MemberID   string             `json:"memberId"`
+	ManagerID  string             `json:"managerId"`
+	Type       string             `json:"type"`
+	UniqueName string             `json:"uniqueName"`
+	Quantity   int                `json:"quantity"`
+	Status     string             `json:"status"`
+	CreatedAt  time.Time          `json:"createdAt"`
+	DecidedAt  *time.Time         `json:"decidedAt"`
+}
+
+// Household is the caller's view of their links. Manager is null when the
+// caller has not requested one.
+type Household struct {
+	Manager *HouseholdLink  `json:"manager"`
+	Members []HouseholdLink `json:"members"`
+}
+
+type HouseholdApprovals struct {
+	ToReview  []PendingChange `json:"toReview"`
+	Requested []PendingChange `json:"requested"`
+}
+
+func NewOwnedBlueprints(owned *models.OwnedBlueprints) *OwnedBlueprints {
+	if owned == nil {
+		return nil
+	}
+	return &OwnedBlueprints{
+		ID:         owned.ID,
+		UserID:     owned.UserID,
+		Blueprints: convert(owned.Blueprints, NewOwnedBlueprint),
+		Components: convert(owned.Components, NewOwnedComponent),
+		CreatedAt:  owned.CreatedAt,
+		UpdatedAt:  owned.UpdatedAt,
+	}
+}
+
+func NewOwnedBlueprint(bp models.OwnedBlueprint) OwnedBlueprint {
+	return OwnedBlueprint{
+		UniqueName: bp.UniqueName,
+		Quantity:   bp.Quantity,
+		AddedAt:    bp.AddedAt,
+	}
+}
+
+func NewOwnedComponents(owned *models.OwnedBlueprints) *OwnedComponents {
+	if owned == nil {
+		return nil
+	}
+	return &OwnedComponents{
+		UserID:     owned.UserID,
+		Components: convert(owned.Components, NewOwnedComponent),
+	}
+}
+
+func NewOwnedComponent(component models.OwnedComponent) OwnedComponent {
+	return OwnedComponent{
+		UniqueName: component.UniqueName,
+		Count:      component.Count,
+		AddedAt:    component.AddedAt,
+	}
+}
+
+func NewUserUsage(usage *models.UserUsage) *UserUsage {
+	if usage == nil {
+		return nil
+	}
+	return &UserUsage{
+		UserID:    usage.UserID,
+		Resources: convert(usage.Resources, NewResourceUsage),
+	}
+}
+
+func NewAdminUser(usage *models.UserUsage, trace *models.UserTrace) *AdminUser {
+	if usage == nil {
+		return nil
+	}
+	return &AdminUser{
+		UserID:    usage.UserID,
+		Resources: convert(usage.Resources, NewResourceUsage),
+		Trace:     NewUserTrace(trace),
+	}
+}
+
+func NewResourceUsage(usage models.ResourceUsage) ResourceUsage {
+	return ResourceUsage{
+		Resource: usage.Resource,
+		Used:     usage.Used,
+		Limit:    usage.Limit,
+		Warning:  usage.Warning,
+	}
+}
+
+func NewUserSettings(settings *models.UserSettings) *UserSettings {
+	if settings == nil {
+		return nil
+	}
+	return &UserSettings{
+		ID:                  settings.ID,
+		UserID:              settings.UserID,
+		TimeZone:            settings.TimeZone,
+		DefaultQuantities:   convert(settings.DefaultQuantities, NewDefaultQuantityRule),
+		PublicWishlist:      settings.PublicWishlist,
+		MutedMilestones:     append([]string{}, settings.MutedMilestones...),
+		MaterialsRounding:   settings.MaterialsRounding,
+		SkipStarterWishlist: settings.SkipStarterWishlist,
+		DigestTime:          settings.DigestTime,
+		CreatedAt:           settings.CreatedAt,
+		UpdatedAt:           settings.UpdatedAt,
+	}
+}
+
+func NewDefaultQuantityRule(rule models.DefaultQuantityRule) DefaultQuantityRule {
+	return DefaultQuantityRule{
+		Category: rule.Category,
+		Type:     rule.Type,
+		Quantity: rule.Quantity,
+	}
+}
+
+func (r DefaultQuantityRule) ToModel() models.DefaultQuantityRule {
+	return models.DefaultQuantityRule{
+		Category: r.Category,
+		Type:     r.Type,
+		Quantity: r.Quantity,
+	}
+}
+
+func NewHouseholdLink(link *models.HouseholdLink) *HouseholdLink {
+	if link == nil {
+		return nil
+	}
+	result := householdLink(*link)
+	return &result
+}
+
+func NewPendingChange(change *models.PendingChange) *PendingChange {
+	if change == nil {
+		return nil
+	}
+	result := pendingChange(*change)
+	return &result
+}
+
+func NewHousehold(household *models.Household) *Household {
+	if household == nil {
+		return nil
+	}
+	return &Household{
+		Manager: NewHouseholdLink(household.Manager),
+		Members: convert(household.Members, householdLink),
+	}
+}
+
+func NewHouseholdApprovals(approvals *models.HouseholdApprovals) *HouseholdApprovals {
+	if approvals == nil {
+		return nil
+	}
+	return &HouseholdApprovals{
+		ToReview:  convert(approvals.ToReview, pendingChange),
+		Requested: convert(approvals.Requested, pendingChange),
+	}
+}
+
+func householdLink(link models.HouseholdLink) HouseholdLink {
+	return HouseholdLink{
+		ID:                link.ID,
+		ManagerID:         link.ManagerID,
+		MemberID:          link.MemberID,
+		QuantityThreshold: link.QuantityThreshold,
+		Status:            link.Status,
+		CreatedAt:         link.CreatedAt,
+		UpdatedAt:         link.UpdatedAt,
+	}
+}
+
+func pendingChange(change models.PendingChange) PendingChange {
+	return PendingChange{
+		ID:         change.ID,
+		MemberID:   change.MemberID,
+		ManagerID:  change.ManagerID,
+		Type:       change.Type,
+		UniqueName: change.UniqueName,
+		Quantity:   change.Quantity,
+		Status:     change.Status,
+		CreatedAt:  change.CreatedAt,
+		DecidedAt:  change.DecidedAt,
+	}
+}
+
+func NewOwnedMaterials(owned *models.OwnedMaterials) *OwnedMaterials {
+	if owned == nil {
+		return nil
+	}
+	return &OwnedMaterials{
+		UserID:    owned.UserID,
+		Materials: convert(owned.Materials, NewOwnedMaterial),
+	}
+}
+
+func NewOwnedMaterial(m models.OwnedMaterial) OwnedMaterial {
+	return OwnedMaterial{
+		UniqueName: m.UniqueName,
+		Count:      m.Count,
+		UpdatedAt:  m.UpdatedAt,
+	}
+}
+
+func NewMasteryProfile(profile *models.MasteryProfile) *MasteryProfile {
+	if profile == nil {
+		return nil
+	}
+	return &MasteryProfile{
+		UserID:  profile.UserID,
+		Entries: convert(profile.Entries, NewMasteryEntry),
+	}
+}
+
+func NewMasteryEntry(e models.MasteryEntry) MasteryEntry {
+	return MasteryEntry{
+		UniqueName: e.UniqueName,
+		Status:     e.Status,
+		UpdatedAt:  e.UpdatedAt,
+	}
+}
+
+func NewMasterySummary(summary *models.MasterySummary) *MasterySummary {
+	if summary == nil {
+		return nil
+	}
+	return &MasterySummary{
+		UserID:          summary.UserID,
+		Buildable:       summary.Buildable,
+		Built:           summary.Built,
+		Mastered:        summary.Mastered,
+		MasteredPercent: summary.MasteredPercent,
+		Categories: convert(summary.Categories, func(c models.MasteryCategorySummary) MasteryCategorySummary {
+			return MasteryCategorySummary{Collection: c.Collection, Buildable: c.Buildable, Built: c.Built, Mastered: c.Mastered}
+		}),
+	}
+}
+
+func NewUserTrace(trace *models.UserTrace) *UserTrace {
+	if trace == nil {
+		return nil
+	}
+	result := userTrace(*trace)
+	return &result
+}
+
+func NewUserTraces(traces []models.UserTrace) []UserTrace {
+	return convert(traces, userTrace)
+}
+
+func userTrace(trace models.UserTrace) UserTrace {
+	return UserTrace{
+		UserID:    trace.UserID,
+		Until:     trace.Until,
+		Reason:    trace.Reason,
+		CreatedAt: trace.CreatedAt,
+	}
+}
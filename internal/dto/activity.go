@@ -0,0 +1,59 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+// ActivityEntry is one change the user made to their wishlist, owned
+// blueprints or owned components. Payload describes the change, such as the
+// item and its new quantity.
+type ActivityEntry struct {
+	ID        string          `json:"id"`
+	Target    string          `json:"target"`
+	Action    string          `json:"action"`
+	Payload   json.RawMessage `json:"payload"`
+	RequestID string          `json:"requestId"`
+	CreatedAt time.Time       `json:"createdAt"`
+}
+
+// ActivityResponse is one page of the user's activity, newest first. Count
+// is the number of entries on the page and Total the number across all
+// pages.
+type ActivityResponse struct {
+	Entries []ActivityEntry `json:"entries"`
+	Count   int             `json:"count"`
+	Total   int             `json:"total"`
+	Limit   int             `json:"limit"`
+	Offset  int             `json:"offset"`
+}
+
+func NewActivityResponse(page *models.ActivityPage) *ActivityResponse {
+	if page == nil {
+		return nil
+	}
+	return &ActivityResponse{
+		Entries: convert(page.Entries, activityEntry),
+		Count:   len(page.Entries),
+		Total:   page.Total,
+		Limit:   page.Limit,
+		Offset:  page.Offset,
+	}
+}
+
+func activityEntry(e models.ActivityEntry) ActivityEntry {
+	entry := ActivityEntry{
+		ID:        e.ID.Hex(),
+		Target:    e.Target,
+		Action:    e.Action,
+		Payload:   json.RawMessage(e.Payload),
+		RequestID: e.RequestID,
+		CreatedAt: e.CreatedAt,
+	}
+	if !json.Valid(entry.Payload) {
+		entry.Payload = json.RawMessage("null")
+	}
+	return entry
+}
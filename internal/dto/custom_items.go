@@ -0,0 +1,64 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+// CustomItem is a private item a user defined. Category is always "Custom".
+type CustomItem struct {
+	UniqueName  string                `json:"uniqueName"`
+	Name        string                `json:"name"`
+	Description string                `json:"description"`
+	Category    string                `json:"category"`
+	BuildPrice  int                   `json:"buildPrice"`
+	Components  []CustomItemComponent `json:"components"`
+	CreatedAt   time.Time             `json:"createdAt"`
+	UpdatedAt   time.Time             `json:"updatedAt"`
+}
+
+type CustomItemComponent struct {
+	UniqueName string `json:"uniqueName"`
+	Name       string `json:"name"`
+	ItemCount  int    `json:"itemCount"`
+	ImageName  string `json:"imageName"`
+}
+
+type CustomItems struct {
+	Items []CustomItem `json:"items"`
+}
+
+func NewCustomItem(item *models.CustomItem) *CustomItem {
+	if item == nil {
+		return nil
+	}
+	result := customItem(*item)
+	return &result
+}
+
+func NewCustomItems(items []models.CustomItem) *CustomItems {
+	return &CustomItems{Items: convert(items, customItem)}
+}
+
+func customItem(item models.CustomItem) CustomItem {
+	return CustomItem{
+		UniqueName:  item.UniqueName,
+		Name:        item.Name,
+		Description: item.Description,
+		Category:    models.CustomItemCategory,
+		BuildPrice:  item.BuildPrice,
+		Components:  convert(item.Components, customItemComponent),
+		CreatedAt:   item.CreatedAt,
+		UpdatedAt:   item.UpdatedAt,
+	}
+}
+
+func customItemComponent(c models.Component) CustomItemComponent {
+	return CustomItemComponent{
+		UniqueName: c.UniqueName,
+		Name:       c.Name,
+		ItemCount:  c.ItemCount,
+		ImageName:  c.ImageName,
+	}
+}
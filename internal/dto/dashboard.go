@@ -0,0 +1,57 @@
+package dto
+
+import "github.com/graytonio/warframe-wishlist/internal/models"
+
+// Dashboard is the app's home screen in one response. topItems are the
+// unfinished items closest to completion and alerts the active alerts
+// rewarding what the wishlist needs. degraded lists the sections whose
+// source failed; they are then empty.
+type Dashboard struct {
+	Wishlist  WishlistSummary        `json:"wishlist"`
+	TopItems  []ExpandedWishlistItem `json:"topItems"`
+	Materials MaterialHighlights     `json:"materials"`
+	Foundry   Foundry                `json:"foundry"`
+	Alerts    []Opportunity          `json:"alerts"`
+	Degraded  []DegradedSection      `json:"degraded"`
+}
+
+// WishlistSummary counts the wishlist's items; completion is their average
+// completion percentage.
+type WishlistSummary struct {
+	Items      int `json:"items"`
+	Quantity   int `json:"quantity"`
+	Completed  int `json:"completed"`
+	Completion int `json:"completion"`
+}
+
+// MaterialHighlights are the outstanding materials needing the most, out of
+// outstanding in all, with the wishlist's credit and rush totals.
+type MaterialHighlights struct {
+	Highlights      []MaterialRequirement `json:"highlights"`
+	Outstanding     int                   `json:"outstanding"`
+	Credits         Amount                `json:"credits"`
+	RushCost        Amount                `json:"rushCost"`
+	Truncated       bool                  `json:"truncated"`
+	TruncatedReason string                `json:"truncatedReason"`
+}
+
+func NewDashboard(dashboard *models.Dashboard) *Dashboard {
+	if dashboard == nil {
+		return nil
+	}
+	return &Dashboard{
+		Wishlist: WishlistSummary(dashboard.Wishlist),
+		TopItems: convert(dashboard.TopItems, newExpandedWishlistItem),
+		Materials: MaterialHighlights{
+			Highlights:      convert(dashboard.Materials.Highlights, NewMaterialRequirement),
+			Outstanding:     dashboard.Materials.Outstanding,
+			Credits:         NewAmount(dashboard.Materials.TotalCredits, UnitCredits),
+			RushCost:        NewAmount(dashboard.Materials.RushPlatinum, UnitPlatinum),
+			Truncated:       dashboard.Materials.Truncated,
+			TruncatedReason: dashboard.Materials.TruncatedReason,
+		},
+		Foundry:  *NewFoundry(&dashboard.Foundry),
+		Alerts:   convert(dashboard.Alerts, newOpportunity),
+		Degraded: degraded(dashboard.Degradation),
+	}
+}
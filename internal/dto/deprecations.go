@@ -0,0 +1,49 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+// Deprecations reports the uses of each announced deprecation since this
+// instance started; counts are not shared between instances.
+type Deprecations struct {
+	Deprecations []DeprecationUsage `json:"deprecations"`
+}
+
+// DeprecationUsage is one deprecation's uses. sunset is null until removal
+// is scheduled.
+type DeprecationUsage struct {
+	ID      string              `json:"id"`
+	Message string              `json:"message"`
+	Since   time.Time           `json:"since"`
+	Sunset  *time.Time          `json:"sunset"`
+	Total   int64               `json:"total"`
+	Clients []DeprecationClient `json:"clients"`
+}
+
+type DeprecationClient struct {
+	ClientID string    `json:"clientId"`
+	Count    int64     `json:"count"`
+	LastSeen time.Time `json:"lastSeen"`
+}
+
+func NewDeprecations(usage []models.DeprecationUsage) Deprecations {
+	result := Deprecations{Deprecations: make([]DeprecationUsage, len(usage))}
+	for i, u := range usage {
+		d := DeprecationUsage{
+			ID:      u.ID,
+			Message: u.Message,
+			Since:   u.Since,
+			Sunset:  optionalTime(u.Sunset),
+			Total:   u.Total,
+			Clients: make([]DeprecationClient, len(u.Clients)),
+		}
+		for j, c := range u.Clients {
+			d.Clients[j] = DeprecationClient{ClientID: c.ClientID, Count: c.Count, LastSeen: c.LastSeen}
+		}
+		result.Deprecations[i] = d
+	}
+	return result
+}
@@ -0,0 +1,63 @@
+// Package dto defines the JSON shapes the API accepts and returns, kept
+// separate from the bson models so that which fields appear in a response is a
+// deliberate choice rather than a side effect of storage tags. Every mapping
+// copies fields explicitly; a new field on a model is not exposed until it is
+// added here.
+//
+// Field presence rules, applied to every response type:
+//   - Every field is always present. Nothing uses omitempty, so false, 0 and ""
+//     are sent as such (e.g. consumeOnBuild: false).
+//   - Lists are [] when empty, never null or missing.
+//   - Optional objects and timestamps that are not set are null
+//     (e.g. archivedAt, decidedAt, buildCost).
+//
+// For compatibility every key the models used to produce keeps its name and
+// value; the only difference from the old responses is that keys which used
+// to be omitted for zero values are now always present.
+package dto
+
+import (
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+// list returns items, or an empty non-nil slice so it encodes as [].
+func list[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
+
+// convert maps every element of items with fn, always returning a non-nil
+// slice.
+func convert[S, T any](items []S, fn func(S) T) []T {
+	result := make([]T, len(items))
+	for i, item := range items {
+		result[i] = fn(item)
+	}
+	return result
+}
+
+// optionalTime returns nil for the zero time so it encodes as null.
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// DegradedSection names a part of a response that could not be fully
+// populated.
+type DegradedSection struct {
+	Section string `json:"section"`
+	Reason  string `json:"reason"`
+}
+
+// degraded returns the degraded sections of d, always as a non-nil slice.
+func degraded(d models.Degradation) []DegradedSection {
+	return convert(d.Degraded, func(section models.DegradedSection) DegradedSection {
+		return DegradedSection{Section: section.Section, Reason: section.Reason}
+	})
+}
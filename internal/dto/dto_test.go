@@ -0,0 +1,330 @@
+package dto
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestISO8601Duration(t *testing.T) {
+	tests := []struct {
+		seconds  int
+		expected string
+	}{
+		{0, "PT0S"},
+		{-5, "PT0S"},
+		{45, "PT45S"},
+		{60, "PT1M"},
+		{3600, "PT1H"},
+		{43200, "PT12H"},
+		{86400, "P1D"},
+		{259200, "P3D"},
+		{90061, "P1DT1H1M1S"},
+	}
+
+	for _, tt := range tests {
+		if got := ISO8601Duration(tt.seconds); got != tt.expected {
+			t.Errorf("ISO8601Duration(%d): expected '%s', got '%s'", tt.seconds, tt.expected, got)
+		}
+	}
+}
+
+func TestNewItemDetail(t *testing.T) {
+	item := &models.Item{
+		UniqueName:         "/Lotus/Ash",
+		Name:               "Ash",
+		BuildPrice:         25000,
+		BuildTime:          259200,
+		SkipBuildTimePrice: 50,
+	}
+
+	detail := NewItemDetail(item)
+
+	body, err := json.Marshal(detail)
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	if decoded["buildTime"].(float64) != 259200 {
+		t.Errorf("expected raw buildTime to be preserved, got %v", decoded["buildTime"])
+	}
+
+	duration := decoded["buildDuration"].(map[string]interface{})
+	if duration["iso8601"] != "P3D" {
+		t.Errorf("expected iso8601 'P3D', got %v", duration["iso8601"])
+	}
+
+	buildCost := decoded["buildCost"].(map[string]interface{})
+	if buildCost["unit"] != UnitCredits || buildCost["value"].(float64) != 25000 {
+		t.Errorf("unexpected buildCost %v", buildCost)
+	}
+
+	rushCost := decoded["rushCost"].(map[string]interface{})
+	if rushCost["unit"] != UnitPlatinum || rushCost["value"].(float64) != 50 {
+		t.Errorf("unexpected rushCost %v", rushCost)
+	}
+}
+
+func TestNewItemDetail_OmitsZeroValues(t *testing.T) {
+	detail := NewItemDetail(&models.Item{UniqueName: "/Lotus/Ferrite", Name: "Ferrite"})
+
+	if detail.BuildDuration != nil || detail.BuildCost != nil || detail.RushCost != nil {
+		t.Errorf("expected unit metadata to be omitted for zero values, got %+v", detail)
+	}
+
+	if NewItemDetail(nil) != nil {
+		t.Error("expected nil detail for nil item")
+	}
+}
+
+func TestNewMaterialsSummary(t *testing.T) {
+	summary := NewMaterialsSummary(&models.MaterialsResponse{
+		Materials:    []models.MaterialRequirement{{UniqueName: "/Lotus/Ferrite", TotalCount: 100}},
+		TotalCredits: 15000,
+		RushPlatinum: 35,
+	})
+
+	if summary.Credits.Value != 15000 || summary.Credits.Unit != UnitCredits {
+		t.Errorf("unexpected credits amount %+v", summary.Credits)
+	}
+	if summary.RushPlatinum != 35 || summary.RushCost.Value != 35 || summary.RushCost.Unit != UnitPlatinum {
+		t.Errorf("unexpected rush platinum %d / %+v", summary.RushPlatinum, summary.RushCost)
+	}
+	if summary.TotalCredits != 15000 {
+		t.Errorf("expected raw totalCredits to be preserved, got %d", summary.TotalCredits)
+	}
+	if summary.Truncated || summary.TruncatedReason != "" {
+		t.Errorf("expected a complete summary, got truncated %q", summary.TruncatedReason)
+	}
+	truncated := NewMaterialsSummary(&models.MaterialsResponse{Truncated: true, TruncatedReason: models.TruncatedTime})
+	if !truncated.Truncated || truncated.TruncatedReason != models.TruncatedTime {
+		t.Errorf("expected the truncation to be carried over, got %v %q", truncated.Truncated, truncated.TruncatedReason)
+	}
+	if NewMaterialsSummary(nil) != nil {
+		t.Error("expected nil summary for nil materials")
+	}
+}
+
+// assertCompatible checks that every field of the legacy JSON is present in
+// current with the same value, so existing clients keep working.
+func assertCompatible(t *testing.T, path string, legacy, current interface{}) {
+	t.Helper()
+	switch l := legacy.(type) {
+	case map[string]interface{}:
+		c, ok := current.(map[string]interface{})
+		if !ok {
+			t.Errorf("%s: expected an object, got %#v", path, current)
+			return
+		}
+		for k, v := range l {
+			cv, ok := c[k]
+			if !ok {
+				t.Errorf("%s.%s: missing from new response", path, k)
+				continue
+			}
+			assertCompatible(t, path+"."+k, v, cv)
+		}
+	case []interface{}:
+		c, ok := current.([]interface{})
+		if !ok || len(c) != len(l) {
+			t.Errorf("%s: expected %d elements, got %#v", path, len(l), current)
+			return
+		}
+		for i := range l {
+			assertCompatible(t, fmt.Sprintf("%s.%d", path, i), l[i], c[i])
+		}
+	default:
+		if legacy != current {
+			t.Errorf("%s: expected %#v, got %#v", path, legacy, current)
+		}
+	}
+}
+
+func decodeJSON(t *testing.T, v interface{}) interface{} {
+	t.Helper()
+	body, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+	return decoded
+}
+
+func TestResponsesAreCompatibleWithModels(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	id := primitive.NewObjectID()
+	drop := models.Drop{Location: "Void", Type: "Relic", Rarity: "Rare", Chance: 0.02}
+	item := &models.Item{
+		ID: id, UniqueName: "/Lotus/Ash", Name: "Ash", Description: "Ninja", Type: "Warframe", Category: "Warframes",
+		ImageName: "ash.png", Tradable: true, IsPrime: true, MasteryReq: 8, BuildPrice: 25000, BuildTime: 259200,
+		SkipBuildTimePrice: 50, BuildQuantity: 1, ConsumeOnBuild: true, Drops: []models.Drop{drop},
+		Components: []models.Component{{
+			UniqueName: "/Lotus/AshChassis", Name: "Chassis", ItemCount: 1, IsPrime: true, Description: "Part",
+			ImageName: "chassis.png", Tradable: true, Drops: []models.Drop{drop}, HasOwnPage: true,
+			Components: []models.Component{{UniqueName: "/Lotus/Ferrite", Name: "Ferrite", ItemCount: 100}},
+		}},
+		WikiaThumbnail: "thumb.png", WikiaURL: "https://wiki/Ash", Archived: true, ArchivedAt: &now, Collection: "warframes",
+	}
+	item.MarkDegraded(models.SectionComponentPages, "unavailable")
+	searchResult := models.ItemSearchResult{UniqueName: "/Lotus/Ash", Name: "Ash", Description: "Ninja", Category: "Warframes", ImageName: "ash.png", Archived: true, Collection: "warframes"}
+	link := models.SourceLink{URL: "https://example.com/", Title: "Guide", Host: "example.com"}
+	wishlistItem := models.WishlistItem{UniqueName: "/Lotus/Ash", Quantity: 2, AddedAt: now, Links: []models.SourceLink{link},
+		Progress: []models.ComponentProgress{{UniqueName: "/Lotus/AshChassis", Status: models.ComponentBuilt, Count: 1}}, Completion: 25}
+	materials := &models.MaterialsResponse{
+		Materials:    []models.MaterialRequirement{{UniqueName: "/Lotus/Ferrite", Name: "Ferrite", TotalCount: 100, ImageName: "ferrite.png", Description: "Metal"}},
+		TotalCredits: 15000,
+	}
+	materials.MarkDegraded(models.SectionOwnedBlueprints, "unavailable")
+	householdLink := models.HouseholdLink{ID: id, ManagerID: "manager", MemberID: "member", QuantityThreshold: 5, Status: models.HouseholdLinkActive, CreatedAt: now, UpdatedAt: now}
+	change := models.PendingChange{ID: id, MemberID: "member", ManagerID: "manager", Type: models.ChangeTypeAddItem, UniqueName: "/Lotus/Ash", Quantity: 9, Status: models.ChangeStatusApproved, CreatedAt: now, DecidedAt: &now}
+
+	tests := []struct {
+		name    string
+		legacy  interface{}
+		current interface{}
+	}{
+		{name: "item", legacy: item, current: NewItemDetail(item)},
+		{name: "sparse item", legacy: &models.Item{UniqueName: "/Lotus/Ferrite"}, current: NewItemDetail(&models.Item{UniqueName: "/Lotus/Ferrite"})},
+		{name: "search", legacy: map[string]interface{}{"items": []models.ItemSearchResult{searchResult}, "count": 1, "total": 7}, current: NewItemSearchResponse(&models.ItemSearchPage{Items: []models.ItemSearchResult{searchResult}, Total: 7})},
+		{name: "item changes", legacy: &models.ItemChangesResponse{Since: now, Count: 1, Changes: []models.ItemChange{{ID: id, UniqueName: "/Lotus/Ash", Name: "Ash", Collection: "warframes", Kinds: []string{models.ItemChangeRecipe}, DataVersion: "v1", ChangedAt: now}}},
+			current: NewItemChangesResponse(&models.ItemChangesResponse{Since: now, Count: 1, Changes: []models.ItemChange{{ID: id, UniqueName: "/Lotus/Ash", Name: "Ash", Collection: "warframes", Kinds: []string{models.ItemChangeRecipe}, DataVersion: "v1", ChangedAt: now}}})},
+		{name: "wishlist", legacy: &models.Wishlist{ID: id, UserID: "user", Items: []models.WishlistItem{wishlistItem}, CreatedAt: now, UpdatedAt: now}, current: NewWishlist(&models.Wishlist{ID: id, UserID: "user", Items: []models.WishlistItem{wishlistItem}, CreatedAt: now, UpdatedAt: now})},
+		{name: "expanded wishlist", legacy: &models.ExpandedWishlist{UserID: "user", Items: []models.ExpandedWishlistItem{{WishlistItem: wishlistItem, Item: &searchResult}, {WishlistItem: wishlistItem}}, CreatedAt: now, UpdatedAt: now},
+			current: NewExpandedWishlist(&models.ExpandedWishlist{UserID: "user", Items: []models.ExpandedWishlistItem{{WishlistItem: wishlistItem, Item: &searchResult}, {WishlistItem: wishlistItem}}, CreatedAt: now, UpdatedAt: now})},
+		{name: "item links", legacy: map[string]interface{}{"uniqueName": "/Lotus/Ash", "links": []models.SourceLink{link}}, current: NewItemLinks("/Lotus/Ash", []models.SourceLink{link})},
+		{name: "materials", legacy: materials, current: NewMaterialsSummary(materials)},
+		{name: "public wishlist", legacy: []models.PublicWishlist{{ID: "primes", Name: "Primes", Description: "Farm", Items: []models.WishlistItem{wishlistItem}}}, current: NewPublicWishlists([]models.PublicWishlist{{ID: "primes", Name: "Primes", Description: "Farm", Items: []models.WishlistItem{wishlistItem}}})},
+		{name: "owned blueprints", legacy: &models.OwnedBlueprints{ID: id, UserID: "user", Blueprints: []models.OwnedBlueprint{{UniqueName: "/Lotus/Ash", AddedAt: now}}, Components: []models.OwnedComponent{{UniqueName: "/Lotus/AshChassis", Count: 1, AddedAt: now}}, CreatedAt: now, UpdatedAt: now},
+			current: NewOwnedBlueprints(&models.OwnedBlueprints{ID: id, UserID: "user", Blueprints: []models.OwnedBlueprint{{UniqueName: "/Lotus/Ash", AddedAt: now}}, Components: []models.OwnedComponent{{UniqueName: "/Lotus/AshChassis", Count: 1, AddedAt: now}}, CreatedAt: now, UpdatedAt: now})},
+		{name: "usage", legacy: &models.UserUsage{UserID: "user", Resources: []models.ResourceUsage{{Resource: models.UsageWishlistItems, Used: 1900, Limit: 2000, Warning: true}}},
+			current: NewUserUsage(&models.UserUsage{UserID: "user", Resources: []models.ResourceUsage{{Resource: models.UsageWishlistItems, Used: 1900, Limit: 2000, Warning: true}}})},
+		{name: "owned materials", legacy: &models.OwnedMaterials{UserID: "user", Materials: []models.OwnedMaterial{{UserID: "user", UniqueName: "/Lotus/Ferrite", Count: 500, UpdatedAt: now}}},
+			current: NewOwnedMaterials(&models.OwnedMaterials{UserID: "user", Materials: []models.OwnedMaterial{{UserID: "user", UniqueName: "/Lotus/Ferrite", Count: 500, UpdatedAt: now}}})},
+		{name: "mastery profile", legacy: &models.MasteryProfile{UserID: "user", Entries: []models.MasteryEntry{{UserID: "user", UniqueName: "/Lotus/Excalibur", Status: models.MasteryStatusBuilt, UpdatedAt: now}}},
+			current: NewMasteryProfile(&models.MasteryProfile{UserID: "user", Entries: []models.MasteryEntry{{UserID: "user", UniqueName: "/Lotus/Excalibur", Status: models.MasteryStatusBuilt, UpdatedAt: now}}})},
+		{name: "mastery summary", legacy: &models.MasterySummary{UserID: "user", Buildable: 4, Built: 2, Mastered: 1, MasteredPercent: 25, Categories: []models.MasteryCategorySummary{{Collection: "warframes", Buildable: 4, Built: 2, Mastered: 1}}},
+			current: NewMasterySummary(&models.MasterySummary{UserID: "user", Buildable: 4, Built: 2, Mastered: 1, MasteredPercent: 25, Categories: []models.MasteryCategorySummary{{Collection: "warframes", Buildable: 4, Built: 2, Mastered: 1}}})},
+		{name: "settings", legacy: &models.UserSettings{ID: id, UserID: "user", TimeZone: "Europe/Berlin", CreatedAt: now, UpdatedAt: now}, current: NewUserSettings(&models.UserSettings{ID: id, UserID: "user", TimeZone: "Europe/Berlin", CreatedAt: now, UpdatedAt: now})},
+		{name: "household", legacy: &models.Household{Manager: &householdLink, Members: []models.HouseholdLink{householdLink}}, current: NewHousehold(&models.Household{Manager: &householdLink, Members: []models.HouseholdLink{householdLink}})},
+		{name: "approvals", legacy: &models.HouseholdApprovals{ToReview: []models.PendingChange{change}, Requested: []models.PendingChange{change}}, current: NewHouseholdApprovals(&models.HouseholdApprovals{ToReview: []models.PendingChange{change}, Requested: []models.PendingChange{change}})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertCompatible(t, tt.name, decodeJSON(t, tt.legacy), decodeJSON(t, tt.current))
+		})
+	}
+}
+
+func TestNilModelsProduceNilResponses(t *testing.T) {
+	if NewWishlist(nil) != nil || NewExpandedWishlist(nil) != nil || NewPublicWishlist(nil) != nil ||
+		NewOwnedBlueprints(nil) != nil || NewUserSettings(nil) != nil || NewHousehold(nil) != nil ||
+		NewHouseholdApprovals(nil) != nil || NewHouseholdLink(nil) != nil || NewPendingChange(nil) != nil ||
+		NewItemChangesResponse(nil) != nil || NewOwnedMaterials(nil) != nil || NewItemRefreshStatus(nil) != nil ||
+		NewSyncRun(nil) != nil || NewFarmingPlan(nil) != nil || NewItemSearchResponse(nil) != nil || NewItemStats(nil) != nil ||
+		NewGiftClaim(nil) != nil || NewSharedWishlist(nil) != nil || NewRelicRequirements(nil) != nil || NewOpportunities(nil) != nil ||
+		NewQuantitySuggestion(nil) != nil || NewResearchCost(nil) != nil || NewCreatedNotificationChannel(nil) != nil || NewCreatedIntegration(nil) != nil ||
+		NewShareLink(nil) != nil || NewShareLinkView(nil) != nil || NewShareLinkReport(nil) != nil || NewImportAlias(nil) != nil ||
+		NewWishlistExport(nil) != nil || NewWishlistDocumentImportResult(nil) != nil ||
+		NewCustomItem(nil) != nil || NewWorkspace(nil) != nil || NewItemProgress(nil) != nil ||
+		NewWorkspaceMaterials(nil) != nil || NewWorkspaceActivity(nil) != nil || NewWorkspaceEvent(nil) != nil ||
+		NewFoundry(nil) != nil || NewFoundryBuild(nil) != nil || NewOwnedComponents(nil) != nil || NewUserUsage(nil) != nil ||
+		NewMasteryProfile(nil) != nil || NewMasterySummary(nil) != nil || NewProfileImportResult(nil) != nil || NewAdminUser(nil, nil) != nil {
+		t.Error("expected nil models to produce nil responses")
+	}
+}
+
+func TestNewItemStats(t *testing.T) {
+	stats := NewItemStats(&models.Item{
+		WeaponStats: models.WeaponStats{
+			TotalDamage: 70,
+			Damage:      map[string]float64{"total": 70, "impact": 26, "slash": 44, "heat": 0},
+			Disposition: 3,
+		},
+	})
+
+	if stats.Frame != nil {
+		t.Errorf("expected no frame stats, got %+v", stats.Frame)
+	}
+	if stats.Weapon == nil || stats.Weapon.Disposition != 3 || stats.Weapon.TotalDamage != 70 {
+		t.Fatalf("unexpected weapon stats %+v", stats.Weapon)
+	}
+	if _, ok := stats.Weapon.Damage["heat"]; ok || len(stats.Weapon.Damage) != 3 {
+		t.Errorf("expected only the damage types dealt, got %v", stats.Weapon.Damage)
+	}
+
+	empty := NewItemStats(&models.Item{Name: "Ferrite"})
+	if empty.Frame != nil || empty.Weapon != nil {
+		t.Errorf("expected no stats for a resource, got %+v", empty)
+	}
+}
+
+func TestNewItemRefreshStatus(t *testing.T) {
+	status := NewItemRefreshStatus(&models.ItemRefreshStatus{
+		Interval: 6 * time.Hour,
+		Last: &models.SyncStatus{
+			Name:      models.SyncStatusItems,
+			LastError: "download failed",
+			Stats:     models.ItemSyncStats{Inserted: 1, Archived: 2},
+		},
+	})
+
+	fields := decodeJSON(t, status).(map[string]interface{})
+	if fields["nextRunAt"] != nil || fields["intervalSeconds"] != float64(21600) {
+		t.Errorf("expected null nextRunAt and a 6h interval, got %v", fields)
+	}
+	lastRun := fields["lastRun"].(map[string]interface{})
+	if lastRun["lastSuccessAt"] != nil || lastRun["error"] != "download failed" {
+		t.Errorf("expected a failed run without a success, got %v", lastRun)
+	}
+	if failed, ok := lastRun["failedCollections"].([]interface{}); !ok || len(failed) != 0 {
+		t.Errorf("expected failedCollections to be [], got %v", lastRun["failedCollections"])
+	}
+}
+
+func TestWishlistExportRoundTrip(t *testing.T) {
+	doc := &models.WishlistExport{
+		Format:     models.WishlistExportFormat,
+		Version:    models.WishlistExportVersion,
+		ExportedAt: time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+		Items: []models.WishlistExportItem{{
+			UniqueName: "/Lotus/Forma",
+			Quantity:   3,
+			RecipeID:   "alt",
+			Links:      []models.SourceLinkRequest{{URL: "https://example.com/build", Title: "Build"}},
+		}},
+		OwnedBlueprints: []string{"/Lotus/FormaBlueprint"},
+	}
+
+	data, err := json.Marshal(NewWishlistExport(doc))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var decoded WishlistExport
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	got := decoded.ToModel()
+	if got.Format != doc.Format || got.Version != doc.Version || !got.ExportedAt.Equal(doc.ExportedAt) {
+		t.Errorf("header changed in round trip: %+v", got)
+	}
+	if len(got.Items) != 1 || got.Items[0].RecipeID != "alt" || got.Items[0].Links[0] != doc.Items[0].Links[0] {
+		t.Errorf("items changed in round trip: %+v", got.Items)
+	}
+	if len(got.OwnedBlueprints) != 1 || got.OwnedBlueprints[0] != "/Lotus/FormaBlueprint" {
+		t.Errorf("blueprints changed in round trip: %v", got.OwnedBlueprints)
+	}
+}
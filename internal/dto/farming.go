@@ -0,0 +1,56 @@
+package dto
+
+import "github.com/graytonio/warframe-wishlist/internal/models"
+
+type FarmingMaterial struct {
+	UniqueName string  `json:"uniqueName"`
+	Name       string  `json:"name"`
+	Remaining  int     `json:"remaining"`
+	Chance     float64 `json:"chance"`
+	Rarity     string  `json:"rarity"`
+	Best       bool    `json:"best"`
+}
+
+type FarmingLocation struct {
+	Location  string            `json:"location"`
+	Materials []FarmingMaterial `json:"materials"`
+	BestFor   int               `json:"bestFor"`
+}
+
+// FarmingPlan ranks where to farm the wishlist's outstanding materials;
+// unlocated are the materials no drop table covers.
+type FarmingPlan struct {
+	Locations       []FarmingLocation     `json:"locations"`
+	Unlocated       []MaterialRequirement `json:"unlocated"`
+	Truncated       bool                  `json:"truncated"`
+	TruncatedReason string                `json:"truncatedReason"`
+	Degraded        []DegradedSection     `json:"degraded"`
+}
+
+func NewFarmingPlan(plan *models.FarmingPlan) *FarmingPlan {
+	if plan == nil {
+		return nil
+	}
+	return &FarmingPlan{
+		Locations: convert(plan.Locations, func(location models.FarmingLocation) FarmingLocation {
+			return FarmingLocation{
+				Location: location.Location,
+				Materials: convert(location.Materials, func(m models.FarmingMaterial) FarmingMaterial {
+					return FarmingMaterial{
+						UniqueName: m.UniqueName,
+						Name:       m.Name,
+						Remaining:  m.Remaining,
+						Chance:     m.Chance,
+						Rarity:     m.Rarity,
+						Best:       m.Best,
+					}
+				}),
+				BestFor: location.BestFor,
+			}
+		}),
+		Unlocated:       convert(plan.Unlocated, NewMaterialRequirement),
+		Truncated:       plan.Truncated,
+		TruncatedReason: plan.TruncatedReason,
+		Degraded:        degraded(plan.Degradation),
+	}
+}
@@ -0,0 +1,58 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+// Foundry is what a user has building, the soonest to finish first.
+// Finished counts the builds waiting to be claimed.
+type Foundry struct {
+	Builds   []FoundryBuild `json:"builds"`
+	Finished int            `json:"finished"`
+}
+
+// FoundryBuild is a build as of the request: Remaining is zero and Finished
+// true once ReadyAt has passed.
+type FoundryBuild struct {
+	ID         string    `json:"id"`
+	UniqueName string    `json:"uniqueName"`
+	Name       string    `json:"name"`
+	BuildTime  Duration  `json:"buildTime"`
+	StartedAt  time.Time `json:"startedAt"`
+	ReadyAt    time.Time `json:"readyAt"`
+	Remaining  Duration  `json:"remaining"`
+	Finished   bool      `json:"finished"`
+}
+
+func NewFoundry(f *models.Foundry) *Foundry {
+	if f == nil {
+		return nil
+	}
+	return &Foundry{
+		Builds:   convert(f.Builds, foundryBuild),
+		Finished: f.Finished,
+	}
+}
+
+func NewFoundryBuild(b *models.FoundryBuildStatus) *FoundryBuild {
+	if b == nil {
+		return nil
+	}
+	result := foundryBuild(*b)
+	return &result
+}
+
+func foundryBuild(b models.FoundryBuildStatus) FoundryBuild {
+	return FoundryBuild{
+		ID:         b.ID.Hex(),
+		UniqueName: b.UniqueName,
+		Name:       b.Name,
+		BuildTime:  NewDuration(b.BuildTime),
+		StartedAt:  b.StartedAt,
+		ReadyAt:    b.ReadyAt,
+		Remaining:  NewDuration(b.RemainingSeconds),
+		Finished:   b.Finished,
+	}
+}
@@ -0,0 +1,49 @@
+package dto
+
+// Link is a HAL link. Method is the HTTP method to follow it with, so
+// generic clients can tell an update link from a remove link.
+type Link struct {
+	Href   string `json:"href"`
+	Method string `json:"method"`
+}
+
+// Links maps link relations to links. HAL responses carry it as _links.
+type Links map[string]Link
+
+// HALWishlistItem is a wishlist item with links to its item detail and to
+// updating and removing it.
+type HALWishlistItem struct {
+	WishlistItem
+	Hypermedia Links `json:"_links"`
+}
+
+type HALWishlist struct {
+	Wishlist
+	Items      []HALWishlistItem `json:"items"`
+	Hypermedia Links             `json:"_links"`
+}
+
+type HALExpandedWishlistItem struct {
+	ExpandedWishlistItem
+	Hypermedia Links `json:"_links"`
+}
+
+type HALExpandedWishlist struct {
+	ExpandedWishlist
+	Items      []HALExpandedWishlistItem `json:"items"`
+	Hypermedia Links                     `json:"_links"`
+}
+
+// HALItemSummary is a search result with a link to its item detail.
+type HALItemSummary struct {
+	ItemSummary
+	Hypermedia Links `json:"_links"`
+}
+
+// HALItemSearchResponse is a page of search results with links to itself
+// and, where they exist, the next and previous pages.
+type HALItemSearchResponse struct {
+	ItemSearchResponse
+	Items      []HALItemSummary `json:"items"`
+	Hypermedia Links            `json:"_links"`
+}
@@ -0,0 +1,263 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+type ImportCandidate struct {
+	UniqueName string `json:"uniqueName"`
+	Name       string `json:"name"`
+	ImageName  string `json:"imageName"`
+}
+
+// ImportMatch is a line resolved to one item. MatchType is "exact", "alias"
+// or "fuzzy"; clients should ask the user to check fuzzy matches.
+type ImportMatch struct {
+	Line      int             `json:"line"`
+	Input     string          `json:"input"`
+	Quantity  int             `json:"quantity"`
+	MatchType string          `json:"matchType"`
+	Item      ImportCandidate `json:"item"`
+}
+
+type ImportAmbiguous struct {
+	Line       int               `json:"line"`
+	Input      string            `json:"input"`
+	Quantity   int               `json:"quantity"`
+	Candidates []ImportCandidate `json:"candidates"`
+}
+
+type ImportUnmatched struct {
+	Line  int    `json:"line"`
+	Input string `json:"input"`
+}
+
+type ImportPreview struct {
+	Matched   []ImportMatch     `json:"matched"`
+	Ambiguous []ImportAmbiguous `json:"ambiguous"`
+	Unmatched []ImportUnmatched `json:"unmatched"`
+}
+
+// ImportItemResult is the outcome of one confirmed item. PendingChange is
+// null unless the status is "pendingApproval".
+type ImportItemResult struct {
+	UniqueName    string         `json:"uniqueName"`
+	Quantity      int            `json:"quantity"`
+	Status        string         `json:"status"`
+	PendingChange *PendingChange `json:"pendingChange"`
+}
+
+type ImportConfirmResult struct {
+	Results []ImportItemResult `json:"results"`
+}
+
+func NewImportPreview(preview *models.ImportPreview) *ImportPreview {
+	if preview == nil {
+		return nil
+	}
+	return &ImportPreview{
+		Matched: convert(preview.Matched, func(match models.ImportMatch) ImportMatch {
+			return ImportMatch{
+				Line:      match.Line,
+				Input:     match.Input,
+				Quantity:  match.Quantity,
+				MatchType: match.MatchType,
+				Item:      importCandidate(match.Item),
+			}
+		}),
+		Ambiguous: convert(preview.Ambiguous, func(ambiguous models.ImportAmbiguous) ImportAmbiguous {
+			return ImportAmbiguous{
+				Line:       ambiguous.Line,
+				Input:      ambiguous.Input,
+				Quantity:   ambiguous.Quantity,
+				Candidates: convert(ambiguous.Candidates, importCandidate),
+			}
+		}),
+		Unmatched: convert(preview.Unmatched, func(unmatched models.ImportUnmatched) ImportUnmatched {
+			return ImportUnmatched{Line: unmatched.Line, Input: unmatched.Input}
+		}),
+	}
+}
+
+func NewImportConfirmResult(result *models.ImportConfirmResult) *ImportConfirmResult {
+	if result == nil {
+		return nil
+	}
+	return &ImportConfirmResult{
+		Results: convert(result.Results, importItemResult),
+	}
+}
+
+func importItemResult(item models.ImportItemResult) ImportItemResult {
+	return ImportItemResult{
+		UniqueName:    item.UniqueName,
+		Quantity:      item.Quantity,
+		Status:        item.Status,
+		PendingChange: NewPendingChange(item.PendingChange),
+	}
+}
+
+func importCandidate(candidate models.ImportCandidate) ImportCandidate {
+	return ImportCandidate{
+		UniqueName: candidate.UniqueName,
+		Name:       candidate.Name,
+		ImageName:  candidate.ImageName,
+	}
+}
+
+// WishlistExport is the portable document returned by the export endpoint
+// and accepted by the import endpoint.
+type WishlistExport struct {
+	Format          string               `json:"format"`
+	Version         int                  `json:"version"`
+	ExportedAt      time.Time            `json:"exportedAt"`
+	Items           []WishlistExportItem `json:"items"`
+	OwnedBlueprints []string             `json:"ownedBlueprints"`
+}
+
+type WishlistExportItem struct {
+	UniqueName string              `json:"uniqueName"`
+	Quantity   int                 `json:"quantity"`
+	RecipeID   string              `json:"recipeId"`
+	Links      []SourceLinkRequest `json:"links"`
+}
+
+type BlueprintImportResult struct {
+	UniqueName string `json:"uniqueName"`
+	Status     string `json:"status"`
+}
+
+// WishlistDocumentImportResult reports each item and blueprint of an
+// imported document, plus those removed in replace mode. When DryRun is true
+// nothing was written.
+type WishlistDocumentImportResult struct {
+	Mode       string                  `json:"mode"`
+	DryRun     bool                    `json:"dryRun"`
+	Items      []ImportItemResult      `json:"items"`
+	Blueprints []BlueprintImportResult `json:"blueprints"`
+}
+
+func NewWishlistExport(doc *models.WishlistExport) *WishlistExport {
+	if doc == nil {
+		return nil
+	}
+	return &WishlistExport{
+		Format:     doc.Format,
+		Version:    doc.Version,
+		ExportedAt: doc.ExportedAt,
+		Items: convert(doc.Items, func(item models.WishlistExportItem) WishlistExportItem {
+			return WishlistExportItem{
+				UniqueName: item.UniqueName,
+				Quantity:   item.Quantity,
+				RecipeID:   item.RecipeID,
+				Links: convert(item.Links, func(link models.SourceLinkRequest) SourceLinkRequest {
+					return SourceLinkRequest{URL: link.URL, Title: link.Title}
+				}),
+			}
+		}),
+		OwnedBlueprints: list(doc.OwnedBlueprints),
+	}
+}
+
+func (d WishlistExport) ToModel() models.WishlistExport {
+	return models.WishlistExport{
+		Format:     d.Format,
+		Version:    d.Version,
+		ExportedAt: d.ExportedAt,
+		Items: convert(d.Items, func(item WishlistExportItem) models.WishlistExportItem {
+			return models.WishlistExportItem{
+				UniqueName: item.UniqueName,
+				Quantity:   item.Quantity,
+				RecipeID:   item.RecipeID,
+				Links:      UpdateItemLinksRequest{Links: item.Links}.ToModel(),
+			}
+		}),
+		OwnedBlueprints: list(d.OwnedBlueprints),
+	}
+}
+
+func NewWishlistDocumentImportResult(result *models.WishlistDocumentImportResult) *WishlistDocumentImportResult {
+	if result == nil {
+		return nil
+	}
+	return &WishlistDocumentImportResult{
+		Mode:   result.Mode,
+		DryRun: result.DryRun,
+		Items:  convert(result.Items, importItemResult),
+		Blueprints: convert(result.Blueprints, func(bp models.BlueprintImportResult) BlueprintImportResult {
+			return BlueprintImportResult{UniqueName: bp.UniqueName, Status: bp.Status}
+		}),
+	}
+}
+
+// WishlistMergeResult reports a merge: the status of each item the request
+// named, the items both the client and the wishlist changed (kept, with the
+// larger quantity) and the merged wishlist, whose updatedAt is the revision
+// to merge against next.
+type WishlistMergeResult struct {
+	Items     []ImportItemResult `json:"items"`
+	Conflicts []string           `json:"conflicts"`
+	Wishlist  *Wishlist          `json:"wishlist"`
+}
+
+func NewWishlistMergeResult(result *models.WishlistMergeResult) *WishlistMergeResult {
+	if result == nil {
+		return nil
+	}
+	return &WishlistMergeResult{
+		Items:     convert(result.Items, importItemResult),
+		Conflicts: list(result.Conflicts),
+		Wishlist:  NewWishlist(result.Wishlist),
+	}
+}
+
+// ProfileImportResult summarises an inventory import. When DryRun is true
+// nothing was written.
+type ProfileImportResult struct {
+	Format     string               `json:"format"`
+	Mode       string               `json:"mode"`
+	DryRun     bool                 `json:"dryRun"`
+	Blueprints ProfileImportTally   `json:"blueprints"`
+	Components ProfileImportTally   `json:"components"`
+	Materials  ProfileImportTally   `json:"materials"`
+	Skipped    ProfileImportSkipped `json:"skipped"`
+}
+
+type ProfileImportTally struct {
+	Added     int `json:"added"`
+	Updated   int `json:"updated"`
+	Unchanged int `json:"unchanged"`
+	Removed   int `json:"removed"`
+}
+
+type ProfileImportSkipped struct {
+	NotFound    int `json:"notFound"`
+	NotReusable int `json:"notReusable"`
+	Unused      int `json:"unused"`
+	Invalid     int `json:"invalid"`
+}
+
+func NewProfileImportResult(result *models.ProfileImportResult) *ProfileImportResult {
+	if result == nil {
+		return nil
+	}
+	tally := func(t models.ProfileImportTally) ProfileImportTally {
+		return ProfileImportTally{Added: t.Added, Updated: t.Updated, Unchanged: t.Unchanged, Removed: t.Removed}
+	}
+	return &ProfileImportResult{
+		Format:     result.Format,
+		Mode:       result.Mode,
+		DryRun:     result.DryRun,
+		Blueprints: tally(result.Blueprints),
+		Components: tally(result.Components),
+		Materials:  tally(result.Materials),
+		Skipped: ProfileImportSkipped{
+			NotFound:    result.Skipped.NotFound,
+			NotReusable: result.Skipped.NotReusable,
+			Unused:      result.Skipped.Unused,
+			Invalid:     result.Skipped.Invalid,
+		},
+	}
+}
@@ -0,0 +1,95 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+// Integration is a registered downstream tool sent item data changes. Its
+// signing secret is not returned.
+type Integration struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// CreatedIntegration is a newly registered integration. Secret signs its
+// webhooks and is shown only this once.
+type CreatedIntegration struct {
+	Integration
+	Secret string `json:"secret"`
+}
+
+// IntegrationDelivery is one webhook sent, or still to be sent, to an
+// integration. NextAttemptAt is set while it is pending; Payload is the JSON
+// body sent.
+type IntegrationDelivery struct {
+	ID             string          `json:"id"`
+	DeliveryID     string          `json:"deliveryId"`
+	IntegrationID  string          `json:"integrationId"`
+	Event          string          `json:"event"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	ResponseStatus int             `json:"responseStatus"`
+	LastError      string          `json:"lastError"`
+	Payload        json.RawMessage `json:"payload"`
+	CreatedAt      time.Time       `json:"createdAt"`
+	NextAttemptAt  *time.Time      `json:"nextAttemptAt"`
+	DeliveredAt    *time.Time      `json:"deliveredAt"`
+}
+
+func NewIntegrations(integrations []models.Integration) []Integration {
+	return convert(integrations, integration)
+}
+
+func NewCreatedIntegration(i *models.Integration) *CreatedIntegration {
+	if i == nil {
+		return nil
+	}
+	return &CreatedIntegration{Integration: integration(*i), Secret: i.Secret}
+}
+
+func integration(i models.Integration) Integration {
+	events := i.Events
+	if events == nil {
+		events = []string{}
+	}
+	return Integration{
+		ID:        i.ID.Hex(),
+		Name:      i.Name,
+		URL:       i.URL,
+		Events:    events,
+		CreatedAt: i.CreatedAt,
+	}
+}
+
+func NewIntegrationDeliveries(deliveries []models.IntegrationDelivery) []IntegrationDelivery {
+	return convert(deliveries, integrationDelivery)
+}
+
+func integrationDelivery(d models.IntegrationDelivery) IntegrationDelivery {
+	delivery := IntegrationDelivery{
+		ID:             d.ID.Hex(),
+		DeliveryID:     d.WebhookID(),
+		IntegrationID:  d.IntegrationID.Hex(),
+		Event:          d.Event,
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		ResponseStatus: d.ResponseStatus,
+		LastError:      d.LastError,
+		Payload:        json.RawMessage(d.Payload),
+		CreatedAt:      d.CreatedAt,
+		DeliveredAt:    d.DeliveredAt,
+	}
+	if !json.Valid(delivery.Payload) {
+		delivery.Payload = json.RawMessage("null")
+	}
+	if d.Status == models.DeliveryPending {
+		delivery.NextAttemptAt = optionalTime(d.NextAttemptAt)
+	}
+	return delivery
+}
@@ -0,0 +1,506 @@
+package dto
+
+import (
+	"maps"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ItemDetail is the API representation of a single item. It carries every raw
+// field of models.Item and adds unit metadata for the numeric ones. The stats
+// are only set when requested with ?include=stats and are null otherwise.
+type ItemDetail struct {
+	ID                 primitive.ObjectID `json:"id"`
+	UniqueName         string             `json:"uniqueName"`
+	Name               string             `json:"name"`
+	Description        string             `json:"description"`
+	Type               string             `json:"type"`
+	Category           string             `json:"category"`
+	ImageName          string             `json:"imageName"`
+	Tradable           bool               `json:"tradable"`
+	IsPrime            bool               `json:"isPrime"`
+	MasteryReq         int                `json:"masteryReq"`
+	BuildPrice         int                `json:"buildPrice"`
+	BuildTime          int                `json:"buildTime"`
+	SkipBuildTimePrice int                `json:"skipBuildTimePrice"`
+	BuildQuantity      int                `json:"buildQuantity"`
+	ConsumeOnBuild     bool               `json:"consumeOnBuild"`
+	Components         []Component        `json:"components"`
+	AlternateRecipes   []Recipe           `json:"alternateRecipes"`
+	Drops              []Drop             `json:"drops"`
+	WikiaThumbnail     string             `json:"wikiaThumbnail"`
+	WikiaURL           string             `json:"wikiaUrl"`
+	Archived           bool               `json:"archived"`
+	ArchivedAt         *time.Time         `json:"archivedAt"`
+	Collection         string             `json:"_collection"`
+	Degraded           []DegradedSection  `json:"degraded"`
+	BuildDuration      *Duration          `json:"buildDuration"`
+	BuildCost          *Amount            `json:"buildCost"`
+	RushCost           *Amount            `json:"rushCost"`
+	Stats              *ItemStats         `json:"stats"`
+}
+
+// ItemStats are an item's base stats. Frame is null for items other than
+// warframes, archwings and companions, and Weapon for items that deal no
+// damage.
+type ItemStats struct {
+	Frame  *FrameStats  `json:"frame"`
+	Weapon *WeaponStats `json:"weapon"`
+}
+
+type FrameStats struct {
+	Health             float64   `json:"health"`
+	Shield             float64   `json:"shield"`
+	Armor              float64   `json:"armor"`
+	Power              float64   `json:"power"`
+	SprintSpeed        float64   `json:"sprintSpeed"`
+	PassiveDescription string    `json:"passiveDescription"`
+	Abilities          []Ability `json:"abilities"`
+}
+
+type Ability struct {
+	UniqueName  string `json:"uniqueName"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	ImageName   string `json:"imageName"`
+}
+
+// WeaponStats are a weapon's unmodded stats. Damage holds the total and each
+// damage type the weapon deals per shot; criticalChance and procChance are
+// fractions. Disposition is the riven disposition from 1 to 5.
+type WeaponStats struct {
+	TotalDamage        float64            `json:"totalDamage"`
+	Damage             map[string]float64 `json:"damage"`
+	CriticalChance     float64            `json:"criticalChance"`
+	CriticalMultiplier float64            `json:"criticalMultiplier"`
+	ProcChance         float64            `json:"procChance"`
+	FireRate           float64            `json:"fireRate"`
+	Multishot          float64            `json:"multishot"`
+	Accuracy           float64            `json:"accuracy"`
+	MagazineSize       int                `json:"magazineSize"`
+	ReloadTime         float64            `json:"reloadTime"`
+	Range              float64            `json:"range"`
+	Trigger            string             `json:"trigger"`
+	Noise              string             `json:"noise"`
+	Disposition        int                `json:"disposition"`
+	OmegaAttenuation   float64            `json:"omegaAttenuation"`
+}
+
+type Component struct {
+	UniqueName  string      `json:"uniqueName"`
+	Name        string      `json:"name"`
+	ItemCount   int         `json:"itemCount"`
+	IsPrime     bool        `json:"isPrime"`
+	Description string      `json:"description"`
+	ImageName   string      `json:"imageName"`
+	Tradable    bool        `json:"tradable"`
+	Drops       []Drop      `json:"drops"`
+	Components  []Component `json:"components"`
+	HasOwnPage  bool        `json:"hasOwnPage"`
+}
+
+// Recipe is an alternate way to craft an item, selectable per wishlist item.
+type Recipe struct {
+	ID            string      `json:"id"`
+	Name          string      `json:"name"`
+	BuildPrice    int         `json:"buildPrice"`
+	BuildTime     int         `json:"buildTime"`
+	BuildQuantity int         `json:"buildQuantity"`
+	Components    []Component `json:"components"`
+}
+
+type Drop struct {
+	Location string  `json:"location"`
+	Type     string  `json:"type"`
+	Rarity   string  `json:"rarity"`
+	Chance   float64 `json:"chance"`
+}
+
+// ItemSummary is the short form of an item used in search results and
+// expanded wishlists.
+type ItemSummary struct {
+	UniqueName  string `json:"uniqueName"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Category    string `json:"category"`
+	ImageName   string `json:"imageName"`
+	Archived    bool   `json:"archived"`
+	Collection  string `json:"_collection"`
+}
+
+// ItemSearchResponse is one page of search results. Count is the number of
+// items on the page and Total the number of matches across all pages.
+type ItemSearchResponse struct {
+	Items []ItemSummary `json:"items"`
+	Count int           `json:"count"`
+	Total int           `json:"total"`
+}
+
+// ItemSuggestion is an item name offered by autocomplete.
+type ItemSuggestion struct {
+	UniqueName string `json:"uniqueName"`
+	Name       string `json:"name"`
+	Category   string `json:"category"`
+	ImageName  string `json:"imageName"`
+}
+
+type ItemAutocompleteResponse struct {
+	Suggestions []ItemSuggestion `json:"suggestions"`
+	Count       int              `json:"count"`
+}
+
+type ItemChange struct {
+	ID          primitive.ObjectID `json:"id"`
+	UniqueName  string             `json:"uniqueName"`
+	Name        string             `json:"name"`
+	Collection  string             `json:"collection"`
+	Kinds       []string           `json:"kinds"`
+	DataVersion string             `json:"dataVersion"`
+	ChangedAt   time.Time          `json:"changedAt"`
+}
+
+type ItemChangesResponse struct {
+	Since   time.Time    `json:"since"`
+	Changes []ItemChange `json:"changes"`
+	Count   int          `json:"count"`
+}
+
+// RecipeTree is the crafting tree of an item as nodes and edges for graph
+// rendering. A node's truncated field names why its components are left out
+// (cycle, depth, size or unavailable) and is empty otherwise.
+type RecipeTree struct {
+	Root         string            `json:"root"`
+	Nodes        []RecipeNode      `json:"nodes"`
+	Edges        []RecipeEdge      `json:"edges"`
+	TotalCredits int               `json:"totalCredits"`
+	Truncated    bool              `json:"truncated"`
+	Degraded     []DegradedSection `json:"degraded"`
+	Credits      Amount            `json:"credits"`
+}
+
+type RecipeNode struct {
+	ID            string `json:"id"`
+	UniqueName    string `json:"uniqueName"`
+	Name          string `json:"name"`
+	ImageName     string `json:"imageName"`
+	Depth         int    `json:"depth"`
+	Quantity      int    `json:"quantity"`
+	Crafts        int    `json:"crafts"`
+	BuildQuantity int    `json:"buildQuantity"`
+	BuildPrice    int    `json:"buildPrice"`
+	BuildTime     int    `json:"buildTime"`
+	HasOwnPage    bool   `json:"hasOwnPage"`
+	Truncated     string `json:"truncated"`
+}
+
+type RecipeEdge struct {
+	From      string `json:"from"`
+	To        string `json:"to"`
+	ItemCount int    `json:"itemCount"`
+}
+
+// ItemRefreshStatus reports the scheduled item refresh worker. nextRunAt is
+// null while a run is in progress or when this instance does not run the
+// worker; lastRun is null until a run has been recorded.
+type ItemRefreshStatus struct {
+	Enabled         bool       `json:"enabled"`
+	Running         bool       `json:"running"`
+	IntervalSeconds int        `json:"intervalSeconds"`
+	NextRunAt       *time.Time `json:"nextRunAt"`
+	LastRun         *SyncRun   `json:"lastRun"`
+}
+
+// SyncRun is the most recent item data sync. lastSuccessAt is null if no run
+// has succeeded, and error is empty when the run succeeded.
+type SyncRun struct {
+	Source            string        `json:"source"`
+	StartedAt         time.Time     `json:"startedAt"`
+	FinishedAt        time.Time     `json:"finishedAt"`
+	DurationMs        int64         `json:"durationMs"`
+	LastSuccessAt     *time.Time    `json:"lastSuccessAt"`
+	Error             string        `json:"error"`
+	FailedCollections []string      `json:"failedCollections"`
+	Version           string        `json:"version"`
+	Stats             ItemSyncStats `json:"stats"`
+}
+
+type ItemSyncStats struct {
+	Inserted  int `json:"inserted"`
+	Updated   int `json:"updated"`
+	Unchanged int `json:"unchanged"`
+	Archived  int `json:"archived"`
+}
+
+func NewItemDetail(item *models.Item) *ItemDetail {
+	if item == nil {
+		return nil
+	}
+
+	detail := &ItemDetail{
+		ID:                 item.ID,
+		UniqueName:         item.UniqueName,
+		Name:               item.Name,
+		Description:        item.Description,
+		Type:               item.Type,
+		Category:           item.Category,
+		ImageName:          item.ImageName,
+		Tradable:           item.Tradable,
+		IsPrime:            item.IsPrime,
+		MasteryReq:         item.MasteryReq,
+		BuildPrice:         item.BuildPrice,
+		BuildTime:          item.BuildTime,
+		SkipBuildTimePrice: item.SkipBuildTimePrice,
+		BuildQuantity:      item.BuildQuantity,
+		ConsumeOnBuild:     item.ConsumeOnBuild,
+		Components:         convert(item.Components, NewComponent),
+		AlternateRecipes:   convert(item.AlternateRecipes, NewRecipe),
+		Drops:              convert(item.Drops, NewDrop),
+		WikiaThumbnail:     item.WikiaThumbnail,
+		WikiaURL:           item.WikiaURL,
+		Archived:           item.Archived,
+		ArchivedAt:         item.ArchivedAt,
+		Collection:         item.Collection,
+		Degraded:           degraded(item.Degradation),
+	}
+	if item.BuildTime > 0 {
+		d := NewDuration(item.BuildTime)
+		detail.BuildDuration = &d
+	}
+	if item.BuildPrice > 0 {
+		a := NewAmount(item.BuildPrice, UnitCredits)
+		detail.BuildCost = &a
+	}
+	if item.SkipBuildTimePrice > 0 {
+		a := NewAmount(item.SkipBuildTimePrice, UnitPlatinum)
+		detail.RushCost = &a
+	}
+	return detail
+}
+
+func NewItemStats(item *models.Item) *ItemStats {
+	if item == nil {
+		return nil
+	}
+
+	stats := &ItemStats{}
+	if f := item.FrameStats; !f.IsZero() {
+		stats.Frame = &FrameStats{
+			Health:             f.Health,
+			Shield:             f.Shield,
+			Armor:              f.Armor,
+			Power:              f.Power,
+			SprintSpeed:        f.SprintSpeed,
+			PassiveDescription: f.PassiveDescription,
+			Abilities:          convert(f.Abilities, NewAbility),
+		}
+	}
+	if w := item.WeaponStats; !w.IsZero() {
+		damage := maps.Clone(w.Damage)
+		if damage == nil {
+			damage = map[string]float64{}
+		}
+		// The item data lists every damage type; only those dealt are kept.
+		maps.DeleteFunc(damage, func(_ string, amount float64) bool { return amount == 0 })
+		stats.Weapon = &WeaponStats{
+			TotalDamage:        w.TotalDamage,
+			Damage:             damage,
+			CriticalChance:     w.CriticalChance,
+			CriticalMultiplier: w.CriticalMultiplier,
+			ProcChance:         w.ProcChance,
+			FireRate:           w.FireRate,
+			Multishot:          w.Multishot,
+			Accuracy:           w.Accuracy,
+			MagazineSize:       w.MagazineSize,
+			ReloadTime:         w.ReloadTime,
+			Range:              w.Range,
+			Trigger:            w.Trigger,
+			Noise:              w.Noise,
+			Disposition:        w.Disposition,
+			OmegaAttenuation:   w.OmegaAttenuation,
+		}
+	}
+	return stats
+}
+
+func NewAbility(a models.Ability) Ability {
+	return Ability{
+		UniqueName:  a.UniqueName,
+		Name:        a.Name,
+		Description: a.Description,
+		ImageName:   a.ImageName,
+	}
+}
+
+func NewComponent(c models.Component) Component {
+	return Component{
+		UniqueName:  c.UniqueName,
+		Name:        c.Name,
+		ItemCount:   c.ItemCount,
+		IsPrime:     c.IsPrime,
+		Description: c.Description,
+		ImageName:   c.ImageName,
+		Tradable:    c.Tradable,
+		Drops:       convert(c.Drops, NewDrop),
+		Components:  convert(c.Components, NewComponent),
+		HasOwnPage:  c.HasOwnPage,
+	}
+}
+
+func NewRecipe(r models.Recipe) Recipe {
+	return Recipe{
+		ID:            r.ID,
+		Name:          r.Name,
+		BuildPrice:    r.BuildPrice,
+		BuildTime:     r.BuildTime,
+		BuildQuantity: r.BuildQuantity,
+		Components:    convert(r.Components, NewComponent),
+	}
+}
+
+func NewDrop(d models.Drop) Drop {
+	return Drop{
+		Location: d.Location,
+		Type:     d.Type,
+		Rarity:   d.Rarity,
+		Chance:   d.Chance,
+	}
+}
+
+func NewItemSummary(item models.ItemSearchResult) ItemSummary {
+	return ItemSummary{
+		UniqueName:  item.UniqueName,
+		Name:        item.Name,
+		Description: item.Description,
+		Category:    item.Category,
+		ImageName:   item.ImageName,
+		Archived:    item.Archived,
+		Collection:  item.Collection,
+	}
+}
+
+func NewItemSearchResponse(page *models.ItemSearchPage) *ItemSearchResponse {
+	if page == nil {
+		return nil
+	}
+	return &ItemSearchResponse{
+		Items: convert(page.Items, NewItemSummary),
+		Count: len(page.Items),
+		Total: page.Total,
+	}
+}
+
+func NewItemSuggestion(s models.ItemSuggestion) ItemSuggestion {
+	return ItemSuggestion{
+		UniqueName: s.UniqueName,
+		Name:       s.Name,
+		Category:   s.Category,
+		ImageName:  s.ImageName,
+	}
+}
+
+func NewItemAutocompleteResponse(suggestions []models.ItemSuggestion) *ItemAutocompleteResponse {
+	return &ItemAutocompleteResponse{
+		Suggestions: convert(suggestions, NewItemSuggestion),
+		Count:       len(suggestions),
+	}
+}
+
+func NewItemChange(change models.ItemChange) ItemChange {
+	return ItemChange{
+		ID:          change.ID,
+		UniqueName:  change.UniqueName,
+		Name:        change.Name,
+		Collection:  change.Collection,
+		Kinds:       list(change.Kinds),
+		DataVersion: change.DataVersion,
+		ChangedAt:   change.ChangedAt,
+	}
+}
+
+func NewItemChangesResponse(changes *models.ItemChangesResponse) *ItemChangesResponse {
+	if changes == nil {
+		return nil
+	}
+	return &ItemChangesResponse{
+		Since:   changes.Since,
+		Changes: convert(changes.Changes, NewItemChange),
+		Count:   changes.Count,
+	}
+}
+
+func NewRecipeTree(tree *models.RecipeTree) *RecipeTree {
+	if tree == nil {
+		return nil
+	}
+	return &RecipeTree{
+		Root:         tree.Root,
+		Nodes:        convert(tree.Nodes, NewRecipeNode),
+		Edges:        convert(tree.Edges, NewRecipeEdge),
+		TotalCredits: tree.TotalCredits,
+		Truncated:    tree.Truncated,
+		Degraded:     degraded(tree.Degradation),
+		Credits:      NewAmount(tree.TotalCredits, UnitCredits),
+	}
+}
+
+func NewRecipeNode(n models.RecipeNode) RecipeNode {
+	return RecipeNode{
+		ID:            n.ID,
+		UniqueName:    n.UniqueName,
+		Name:          n.Name,
+		ImageName:     n.ImageName,
+		Depth:         n.Depth,
+		Quantity:      n.Quantity,
+		Crafts:        n.Crafts,
+		BuildQuantity: n.BuildQuantity,
+		BuildPrice:    n.BuildPrice,
+		BuildTime:     n.BuildTime,
+		HasOwnPage:    n.HasOwnPage,
+		Truncated:     n.Truncated,
+	}
+}
+
+func NewRecipeEdge(e models.RecipeEdge) RecipeEdge {
+	return RecipeEdge{
+		From:      e.From,
+		To:        e.To,
+		ItemCount: e.ItemCount,
+	}
+}
+
+func NewItemRefreshStatus(status *models.ItemRefreshStatus) *ItemRefreshStatus {
+	if status == nil {
+		return nil
+	}
+	return &ItemRefreshStatus{
+		Enabled:         status.Enabled,
+		Running:         status.Running,
+		IntervalSeconds: int(status.Interval / time.Second),
+		NextRunAt:       optionalTime(status.NextRunAt),
+		LastRun:         NewSyncRun(status.Last),
+	}
+}
+
+func NewSyncRun(status *models.SyncStatus) *SyncRun {
+	if status == nil {
+		return nil
+	}
+	return &SyncRun{
+		Source:            status.Source,
+		StartedAt:         status.LastStartedAt,
+		FinishedAt:        status.LastFinishedAt,
+		DurationMs:        status.DurationMs,
+		LastSuccessAt:     optionalTime(status.LastSuccessAt),
+		Error:             status.LastError,
+		FailedCollections: list(status.FailedCollections),
+		Version:           status.Version,
+		Stats: ItemSyncStats{
+			Inserted:  status.Stats.Inserted,
+			Updated:   status.Stats.Updated,
+			Unchanged: status.Stats.Unchanged,
+			Archived:  status.Stats.Archived,
+		},
+	}
+}
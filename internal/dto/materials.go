@@ -0,0 +1,67 @@
+package dto
+
+import "github.com/graytonio/warframe-wishlist/internal/models"
+
+// MaterialsSummary is the API representation of the aggregated materials for a
+// wishlist, with the credit and rush totals also exposed as unit-tagged
+// amounts. Truncated marks a partial result cut short by a resolver safety
+// limit; TruncatedReason is then "depth", "materials" or "time". Extensions
+// holds what extension hooks added, by hook name.
+type MaterialsSummary struct {
+	Materials       []MaterialRequirement `json:"materials"`
+	TotalCredits    int                   `json:"totalCredits"`
+	RushPlatinum    int                   `json:"rushPlatinum"`
+	Truncated       bool                  `json:"truncated"`
+	TruncatedReason string                `json:"truncatedReason"`
+	Degraded        []DegradedSection     `json:"degraded"`
+	Extensions      map[string]any        `json:"extensions"`
+	Credits         Amount                `json:"credits"`
+	RushCost        Amount                `json:"rushCost"`
+}
+
+type MaterialRequirement struct {
+	UniqueName  string `json:"uniqueName"`
+	Name        string `json:"name"`
+	TotalCount  int    `json:"totalCount"`
+	Owned       int    `json:"owned"`
+	Remaining   int    `json:"remaining"`
+	ImageName   string `json:"imageName"`
+	Description string `json:"description"`
+}
+
+func NewMaterialsSummary(materials *models.MaterialsResponse) *MaterialsSummary {
+	if materials == nil {
+		return nil
+	}
+	return &MaterialsSummary{
+		Materials:       convert(materials.Materials, NewMaterialRequirement),
+		TotalCredits:    materials.TotalCredits,
+		RushPlatinum:    materials.RushPlatinum,
+		Truncated:       materials.Truncated,
+		TruncatedReason: materials.TruncatedReason,
+		Degraded:        degraded(materials.Degradation),
+		Extensions:      extensions(materials.Extensions),
+		Credits:         NewAmount(materials.TotalCredits, UnitCredits),
+		RushCost:        NewAmount(materials.RushPlatinum, UnitPlatinum),
+	}
+}
+
+func NewMaterialRequirement(m models.MaterialRequirement) MaterialRequirement {
+	return MaterialRequirement{
+		UniqueName:  m.UniqueName,
+		Name:        m.Name,
+		TotalCount:  m.TotalCount,
+		Owned:       m.Owned,
+		Remaining:   m.Remaining,
+		ImageName:   m.ImageName,
+		Description: m.Description,
+	}
+}
+
+// extensions returns e, or an empty map so the field is always an object.
+func extensions(e map[string]any) map[string]any {
+	if e == nil {
+		return map[string]any{}
+	}
+	return e
+}
@@ -0,0 +1,33 @@
+package dto
+
+import (
+	"maps"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/buildinfo"
+)
+
+// BuildVersion identifies the server build, for bug reports, and the
+// optional features this instance serves, for clients to gate on. buildDate
+// is null when the build was not stamped with one.
+type BuildVersion struct {
+	Version   string          `json:"version"`
+	Commit    string          `json:"commit"`
+	BuildDate *time.Time      `json:"buildDate"`
+	GoVersion string          `json:"goVersion"`
+	Modified  bool            `json:"modified"`
+	Features  map[string]bool `json:"features"`
+}
+
+func NewBuildVersion(info buildinfo.Info, features map[string]bool) *BuildVersion {
+	copied := make(map[string]bool, len(features))
+	maps.Copy(copied, features)
+	return &BuildVersion{
+		Version:   info.Version,
+		Commit:    info.Commit,
+		BuildDate: optionalTime(info.Date),
+		GoVersion: info.GoVersion,
+		Modified:  info.Modified,
+		Features:  copied,
+	}
+}
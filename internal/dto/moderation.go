@@ -0,0 +1,78 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ShareLinkReport is a moderation queue entry. resolvedAt is null while the
+// report is open.
+type ShareLinkReport struct {
+	ID             primitive.ObjectID `json:"id"`
+	Token          string             `json:"token"`
+	OwnerID        string             `json:"ownerId"`
+	Reasons        []string           `json:"reasons"`
+	Count          int                `json:"count"`
+	Status         string             `json:"status"`
+	CreatedAt      time.Time          `json:"createdAt"`
+	LastReportedAt time.Time          `json:"lastReportedAt"`
+	ResolvedAt     *time.Time         `json:"resolvedAt"`
+}
+
+// ImportAlias is a word the wishlist import expands. builtIn aliases ship
+// with the server; updatedAt is null for them.
+type ImportAlias struct {
+	Word      string     `json:"word"`
+	Expansion string     `json:"expansion"`
+	BuiltIn   bool       `json:"builtIn"`
+	UpdatedAt *time.Time `json:"updatedAt"`
+}
+
+func NewShareLinkReport(report *models.ShareLinkReport) *ShareLinkReport {
+	if report == nil {
+		return nil
+	}
+	result := shareLinkReport(*report)
+	return &result
+}
+
+func NewShareLinkReports(reports []models.ShareLinkReport) []ShareLinkReport {
+	return convert(reports, shareLinkReport)
+}
+
+func NewImportAlias(alias *models.ImportAlias) *ImportAlias {
+	if alias == nil {
+		return nil
+	}
+	result := importAlias(*alias)
+	return &result
+}
+
+func NewImportAliases(aliases []models.ImportAlias) []ImportAlias {
+	return convert(aliases, importAlias)
+}
+
+func shareLinkReport(report models.ShareLinkReport) ShareLinkReport {
+	return ShareLinkReport{
+		ID:             report.ID,
+		Token:          report.Token,
+		OwnerID:        report.OwnerID,
+		Reasons:        list(report.Reasons),
+		Count:          report.Count,
+		Status:         report.Status,
+		CreatedAt:      report.CreatedAt,
+		LastReportedAt: report.LastReportedAt,
+		ResolvedAt:     report.ResolvedAt,
+	}
+}
+
+func importAlias(alias models.ImportAlias) ImportAlias {
+	return ImportAlias{
+		Word:      alias.Word,
+		Expansion: alias.Expansion,
+		BuiltIn:   alias.BuiltIn,
+		UpdatedAt: optionalTime(alias.UpdatedAt),
+	}
+}
@@ -0,0 +1,106 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+// NotificationChannel is a registered webhook or push subscription. Neither
+// its signing secret nor its push keys are returned.
+type NotificationChannel struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// CreatedNotificationChannel is a newly registered channel. Secret signs a
+// webhook's requests and is shown only this once; it is empty for push.
+type CreatedNotificationChannel struct {
+	NotificationChannel
+	Secret string `json:"secret"`
+}
+
+// NotificationDelivery is one notification sent, or still to be sent, to a
+// channel. NextAttemptAt is set while it is pending; Payload is the JSON
+// body sent.
+type NotificationDelivery struct {
+	ID             string          `json:"id"`
+	DeliveryID     string          `json:"deliveryId"`
+	ChannelID      string          `json:"channelId"`
+	Event          string          `json:"event"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	ResponseStatus int             `json:"responseStatus"`
+	LastError      string          `json:"lastError"`
+	Payload        json.RawMessage `json:"payload"`
+	CreatedAt      time.Time       `json:"createdAt"`
+	NextAttemptAt  *time.Time      `json:"nextAttemptAt"`
+	DeliveredAt    *time.Time      `json:"deliveredAt"`
+}
+
+// PushKey is the VAPID public key to pass as applicationServerKey when
+// subscribing to push; Enabled is false when the server has none.
+type PushKey struct {
+	PublicKey string `json:"publicKey"`
+	Enabled   bool   `json:"enabled"`
+}
+
+func NewNotificationChannels(channels []models.NotificationChannel) []NotificationChannel {
+	return convert(channels, notificationChannel)
+}
+
+func NewCreatedNotificationChannel(c *models.NotificationChannel) *CreatedNotificationChannel {
+	if c == nil {
+		return nil
+	}
+	return &CreatedNotificationChannel{NotificationChannel: notificationChannel(*c), Secret: c.Secret}
+}
+
+func notificationChannel(c models.NotificationChannel) NotificationChannel {
+	events := c.Events
+	if events == nil {
+		events = []string{}
+	}
+	return NotificationChannel{
+		ID:        c.ID.Hex(),
+		Type:      c.Type,
+		URL:       c.URL,
+		Events:    events,
+		CreatedAt: c.CreatedAt,
+	}
+}
+
+func NewNotificationDeliveries(deliveries []models.NotificationDelivery) []NotificationDelivery {
+	return convert(deliveries, notificationDelivery)
+}
+
+func notificationDelivery(d models.NotificationDelivery) NotificationDelivery {
+	delivery := NotificationDelivery{
+		ID:             d.ID.Hex(),
+		DeliveryID:     d.WebhookID(),
+		ChannelID:      d.ChannelID.Hex(),
+		Event:          d.Event,
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		ResponseStatus: d.ResponseStatus,
+		LastError:      d.LastError,
+		Payload:        json.RawMessage(d.Payload),
+		CreatedAt:      d.CreatedAt,
+		DeliveredAt:    d.DeliveredAt,
+	}
+	if !json.Valid(delivery.Payload) {
+		delivery.Payload = json.RawMessage("null")
+	}
+	if d.Status == models.DeliveryPending {
+		delivery.NextAttemptAt = optionalTime(d.NextAttemptAt)
+	}
+	return delivery
+}
+
+func NewPushKey(publicKey string) PushKey {
+	return PushKey{PublicKey: publicKey, Enabled: publicKey != ""}
+}
@@ -0,0 +1,76 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+type WorldStateReward struct {
+	UniqueName string `json:"uniqueName"`
+	Count      int    `json:"count"`
+}
+
+type OpportunityMaterial struct {
+	UniqueName string `json:"uniqueName"`
+	Name       string `json:"name"`
+	Count      int    `json:"count"`
+	Remaining  int    `json:"remaining"`
+}
+
+// Opportunity is an active alert, invasion or fissure; materials and relics
+// are what it offers that the wishlist needs, rewards everything it offers.
+type Opportunity struct {
+	ID          string                `json:"id"`
+	Kind        string                `json:"kind"`
+	Node        string                `json:"node"`
+	NodeName    string                `json:"nodeName"`
+	MissionType string                `json:"missionType"`
+	RelicTier   string                `json:"relicTier"`
+	SteelPath   bool                  `json:"steelPath"`
+	Activation  *time.Time            `json:"activation"`
+	Expiry      *time.Time            `json:"expiry"`
+	Rewards     []WorldStateReward    `json:"rewards"`
+	Materials   []OpportunityMaterial `json:"materials"`
+	Relics      []string              `json:"relics"`
+}
+
+// Opportunities lists the active events worth running for the wishlist;
+// fetchedAt is null until the world state has been fetched.
+type Opportunities struct {
+	Opportunities   []Opportunity     `json:"opportunities"`
+	FetchedAt       *time.Time        `json:"fetchedAt"`
+	Truncated       bool              `json:"truncated"`
+	TruncatedReason string            `json:"truncatedReason"`
+	Degraded        []DegradedSection `json:"degraded"`
+}
+
+func NewOpportunities(opportunities *models.Opportunities) *Opportunities {
+	if opportunities == nil {
+		return nil
+	}
+	return &Opportunities{
+		Opportunities:   convert(opportunities.Opportunities, newOpportunity),
+		FetchedAt:       optionalTime(opportunities.FetchedAt),
+		Truncated:       opportunities.Truncated,
+		TruncatedReason: opportunities.TruncatedReason,
+		Degraded:        degraded(opportunities.Degradation),
+	}
+}
+
+func newOpportunity(o models.Opportunity) Opportunity {
+	return Opportunity{
+		ID:          o.ID,
+		Kind:        o.Kind,
+		Node:        o.Node,
+		NodeName:    o.NodeName,
+		MissionType: o.MissionType,
+		RelicTier:   o.RelicTier,
+		SteelPath:   o.SteelPath,
+		Activation:  optionalTime(o.Activation),
+		Expiry:      optionalTime(o.Expiry),
+		Rewards:     convert(o.Rewards, func(r models.WorldStateReward) WorldStateReward { return WorldStateReward(r) }),
+		Materials:   convert(o.Materials, func(m models.OpportunityMaterial) OpportunityMaterial { return OpportunityMaterial(m) }),
+		Relics:      convert(o.Relics, func(name string) string { return name }),
+	}
+}
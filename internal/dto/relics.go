@@ -0,0 +1,75 @@
+package dto
+
+import "github.com/graytonio/warframe-wishlist/internal/models"
+
+type PrimePart struct {
+	UniqueName     string `json:"uniqueName"`
+	Name           string `json:"name"`
+	ItemUniqueName string `json:"itemUniqueName"`
+	ItemName       string `json:"itemName"`
+	Needed         int    `json:"needed"`
+}
+
+// RelicChances are a part's drop chances per refinement; 0 where the relic
+// does not list it.
+type RelicChances struct {
+	Intact      float64 `json:"intact"`
+	Exceptional float64 `json:"exceptional"`
+	Flawless    float64 `json:"flawless"`
+	Radiant     float64 `json:"radiant"`
+}
+
+type RelicPart struct {
+	PrimePart
+	Rarity  string       `json:"rarity"`
+	Chances RelicChances `json:"chances"`
+}
+
+type RelicRequirement struct {
+	Name       string      `json:"name"`
+	Tier       string      `json:"tier"`
+	UniqueName string      `json:"uniqueName"`
+	ImageName  string      `json:"imageName"`
+	Parts      []RelicPart `json:"parts"`
+}
+
+// RelicRequirements lists the relics to open for the wishlist's Prime parts;
+// unavailable are the parts no relic drops.
+type RelicRequirements struct {
+	Relics      []RelicRequirement `json:"relics"`
+	Unavailable []PrimePart        `json:"unavailable"`
+}
+
+func NewPrimePart(part models.PrimePart) PrimePart {
+	return PrimePart{
+		UniqueName:     part.UniqueName,
+		Name:           part.Name,
+		ItemUniqueName: part.ItemUniqueName,
+		ItemName:       part.ItemName,
+		Needed:         part.Needed,
+	}
+}
+
+func NewRelicRequirements(requirements *models.RelicRequirements) *RelicRequirements {
+	if requirements == nil {
+		return nil
+	}
+	return &RelicRequirements{
+		Relics: convert(requirements.Relics, func(relic models.RelicRequirement) RelicRequirement {
+			return RelicRequirement{
+				Name:       relic.Name,
+				Tier:       relic.Tier,
+				UniqueName: relic.UniqueName,
+				ImageName:  relic.ImageName,
+				Parts: convert(relic.Parts, func(part models.RelicPart) RelicPart {
+					return RelicPart{
+						PrimePart: NewPrimePart(part.PrimePart),
+						Rarity:    part.Rarity,
+						Chances:   RelicChances(part.Chances),
+					}
+				}),
+			}
+		}),
+		Unavailable: convert(requirements.Unavailable, NewPrimePart),
+	}
+}
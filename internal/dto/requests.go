@@ -0,0 +1,467 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+// Request bodies accepted by the API. Handlers decode into these and pass the
+// result of ToModel to the services, so the wire format can only change here.
+// Handlers that decode with decodeAndValidate also reject bodies breaking the
+// validate tags (see pkg/validate); the services still check everything else.
+
+// AddItemRequest adds an item to the wishlist; a zero quantity uses the
+// user's default for the item.
+type AddItemRequest struct {
+	UniqueName string `json:"uniqueName" validate:"required" code:"unique_name_required"`
+	Quantity   int    `json:"quantity" validate:"min=0" code:"invalid_quantity"`
+	AllowOwned bool   `json:"allowOwned,omitempty"`
+}
+
+func (r AddItemRequest) ToModel() models.AddItemRequest {
+	return models.AddItemRequest{
+		UniqueName: r.UniqueName,
+		Quantity:   r.Quantity,
+		AllowOwned: r.AllowOwned,
+	}
+}
+
+// ImportTextRequest carries pasted item names, one per line.
+type ImportTextRequest struct {
+	Text string `json:"text"`
+}
+
+// ImportConfirmRequest lists the items to add after an import preview.
+type ImportConfirmRequest struct {
+	Items []AddItemRequest `json:"items"`
+}
+
+func (r ImportConfirmRequest) ToModel() models.ImportConfirmRequest {
+	return models.ImportConfirmRequest{
+		Items: convert(r.Items, AddItemRequest.ToModel),
+	}
+}
+
+// WishlistMergeRequest is an edit of the wishlist made against
+// baseRevision, the updatedAt of the wishlist the client edited. base lists
+// the items it had then and items the items the client wants.
+type WishlistMergeRequest struct {
+	BaseRevision *time.Time          `json:"baseRevision"`
+	Base         []WishlistMergeItem `json:"base"`
+	Items        []WishlistMergeItem `json:"items"`
+}
+
+type WishlistMergeItem struct {
+	UniqueName string `json:"uniqueName"`
+	Quantity   int    `json:"quantity"`
+}
+
+func (r WishlistMergeRequest) ToModel() models.WishlistMergeRequest {
+	req := models.WishlistMergeRequest{
+		Base:  convert(r.Base, WishlistMergeItem.ToModel),
+		Items: convert(r.Items, WishlistMergeItem.ToModel),
+	}
+	if r.BaseRevision != nil {
+		req.BaseRevision = *r.BaseRevision
+	}
+	return req
+}
+
+func (i WishlistMergeItem) ToModel() models.WishlistMergeItem {
+	return models.WishlistMergeItem{UniqueName: i.UniqueName, Quantity: i.Quantity}
+}
+
+// UpdateQuantityRequest sets a wishlist item's quantity. Workspace
+// contributions reuse it, where zero is allowed, so they decode it without
+// validating.
+type UpdateQuantityRequest struct {
+	Quantity int `json:"quantity" validate:"min=1" code:"invalid_quantity"`
+}
+
+type SourceLinkRequest struct {
+	URL   string `json:"url"`
+	Title string `json:"title"`
+}
+
+// UpdateItemLinksRequest replaces every source link on a wishlist item.
+type UpdateItemLinksRequest struct {
+	Links []SourceLinkRequest `json:"links"`
+}
+
+func (r UpdateItemLinksRequest) ToModel() []models.SourceLinkRequest {
+	return convert(r.Links, func(link SourceLinkRequest) models.SourceLinkRequest {
+		return models.SourceLinkRequest{URL: link.URL, Title: link.Title}
+	})
+}
+
+// SetItemRecipeRequest selects a wishlist item's preferred recipe; an empty
+// recipeId restores the default recipe.
+type SetItemRecipeRequest struct {
+	RecipeID string `json:"recipeId"`
+}
+
+type ComponentProgressRequest struct {
+	UniqueName string `json:"uniqueName"`
+	Status     string `json:"status"`
+	Count      int    `json:"count"`
+}
+
+// UpdateItemProgressRequest replaces the component progress on a wishlist
+// item; an empty list clears it.
+type UpdateItemProgressRequest struct {
+	Components []ComponentProgressRequest `json:"components"`
+}
+
+func (r UpdateItemProgressRequest) ToModel() []models.ComponentProgressRequest {
+	return convert(r.Components, func(c ComponentProgressRequest) models.ComponentProgressRequest {
+		return models.ComponentProgressRequest{UniqueName: c.UniqueName, Status: c.Status, Count: c.Count}
+	})
+}
+
+type AddBlueprintRequest struct {
+	UniqueName string `json:"uniqueName" validate:"required" code:"unique_name_required"`
+}
+
+func (r AddBlueprintRequest) ToModel() models.AddBlueprintRequest {
+	return models.AddBlueprintRequest{UniqueName: r.UniqueName}
+}
+
+// BulkAddBlueprintsRequest adds several owned blueprints; an empty list does
+// nothing.
+type BulkAddBlueprintsRequest struct {
+	UniqueNames []string `json:"uniqueNames" validate:"dive,required" code:"unique_name_required"`
+}
+
+func (r BulkAddBlueprintsRequest) ToModel() models.BulkAddBlueprintsRequest {
+	return models.BulkAddBlueprintsRequest{UniqueNames: r.UniqueNames}
+}
+
+type OwnedMaterialCount struct {
+	UniqueName string `json:"uniqueName"`
+	Count      int    `json:"count"`
+}
+
+type SetOwnedMaterialsRequest struct {
+	Materials []OwnedMaterialCount `json:"materials"`
+}
+
+func (r SetOwnedMaterialsRequest) ToModel() models.SetOwnedMaterialsRequest {
+	return models.SetOwnedMaterialsRequest{
+		Materials: convert(r.Materials, func(m OwnedMaterialCount) models.OwnedMaterialCount {
+			return models.OwnedMaterialCount{UniqueName: m.UniqueName, Count: m.Count}
+		}),
+	}
+}
+
+type MasteryStatus struct {
+	UniqueName string `json:"uniqueName"`
+	Status     string `json:"status"`
+}
+
+// SetMasteryRequest sets the status of each listed item; an empty status
+// removes it.
+type SetMasteryRequest struct {
+	Items []MasteryStatus `json:"items"`
+}
+
+func (r SetMasteryRequest) ToModel() models.SetMasteryRequest {
+	return models.SetMasteryRequest{
+		Items: convert(r.Items, func(m MasteryStatus) models.MasteryStatus {
+			return models.MasteryStatus{UniqueName: m.UniqueName, Status: m.Status}
+		}),
+	}
+}
+
+type SetMasteryStatusRequest struct {
+	Status string `json:"status"`
+}
+
+type SetOwnedMaterialCountRequest struct {
+	Count int `json:"count"`
+}
+
+// SetOwnedBlueprintQuantityRequest sets how many copies of a blueprint the
+// user has; 0 removes it.
+type SetOwnedBlueprintQuantityRequest struct {
+	Quantity int `json:"quantity" validate:"min=0" code:"invalid_blueprint_quantity"`
+}
+
+type SetOwnedComponentCountRequest struct {
+	Count int `json:"count"`
+}
+
+// UpdateSettingsRequest is a partial update; a missing field is left
+// unchanged. defaultQuantities and mutedMilestones replace the whole list, so
+// an empty list clears them.
+type UpdateSettingsRequest struct {
+	TimeZone            *string                `json:"timeZone"`
+	DefaultQuantities   *[]DefaultQuantityRule `json:"defaultQuantities"`
+	PublicWishlist      *bool                  `json:"publicWishlist"`
+	MutedMilestones     *[]string              `json:"mutedMilestones"`
+	MaterialsRounding   *string                `json:"materialsRounding"`
+	SkipStarterWishlist *bool                  `json:"skipStarterWishlist"`
+	DigestTime          *string                `json:"digestTime"`
+}
+
+func (r UpdateSettingsRequest) ToModel() models.UpdateSettingsRequest {
+	req := models.UpdateSettingsRequest{TimeZone: r.TimeZone, PublicWishlist: r.PublicWishlist, MutedMilestones: r.MutedMilestones, MaterialsRounding: r.MaterialsRounding, SkipStarterWishlist: r.SkipStarterWishlist, DigestTime: r.DigestTime}
+	if r.DefaultQuantities != nil {
+		rules := convert(*r.DefaultQuantities, DefaultQuantityRule.ToModel)
+		req.DefaultQuantities = &rules
+	}
+	return req
+}
+
+type RequestManagerRequest struct {
+	ManagerUserID     string `json:"managerUserId"`
+	QuantityThreshold int    `json:"quantityThreshold"`
+}
+
+func (r RequestManagerRequest) ToModel() models.RequestManagerRequest {
+	return models.RequestManagerRequest{
+		ManagerUserID:     r.ManagerUserID,
+		QuantityThreshold: r.QuantityThreshold,
+	}
+}
+
+type CreateShareLinkRequest struct {
+	Label       string               `json:"label"`
+	Permissions ShareLinkPermissions `json:"permissions"`
+}
+
+func (r CreateShareLinkRequest) ToModel() models.CreateShareLinkRequest {
+	return models.CreateShareLinkRequest{Label: r.Label, Permissions: r.Permissions.ToModel()}
+}
+
+// ReportShareLinkRequest reports a share link to moderators; reason is one
+// of spam, abuse or other.
+type ReportShareLinkRequest struct {
+	Reason string `json:"reason"`
+}
+
+func (r ReportShareLinkRequest) ToModel() models.ReportShareLinkRequest {
+	return models.ReportShareLinkRequest{Reason: r.Reason}
+}
+
+// ImportAliasRequest sets what a word expands to in wishlist imports.
+type ImportAliasRequest struct {
+	Expansion string `json:"expansion"`
+}
+
+// ClaimGiftRequest claims an item on another user's public wishlist; a
+// missing days selects the default claim length.
+type ClaimGiftRequest struct {
+	UniqueName string `json:"uniqueName"`
+	Anonymous  bool   `json:"anonymous"`
+	Days       int    `json:"days"`
+}
+
+func (r ClaimGiftRequest) ToModel() models.ClaimGiftRequest {
+	return models.ClaimGiftRequest{UniqueName: r.UniqueName, Anonymous: r.Anonymous, Days: r.Days}
+}
+
+// UpdateHouseholdMemberRequest is a partial update; a missing field is left
+// unchanged.
+type UpdateHouseholdMemberRequest struct {
+	QuantityThreshold *int `json:"quantityThreshold"`
+}
+
+func (r UpdateHouseholdMemberRequest) ToModel() models.UpdateHouseholdMemberRequest {
+	return models.UpdateHouseholdMemberRequest{QuantityThreshold: r.QuantityThreshold}
+}
+
+// EnableUserTraceRequest starts tracing a user; a missing durationMinutes
+// selects the default window.
+type EnableUserTraceRequest struct {
+	DurationMinutes int    `json:"durationMinutes"`
+	Reason          string `json:"reason"`
+}
+
+func (r EnableUserTraceRequest) ToModel() models.EnableUserTraceRequest {
+	return models.EnableUserTraceRequest{DurationMinutes: r.DurationMinutes, Reason: r.Reason}
+}
+
+// CustomItemRequest creates or replaces a custom item. Component names may be
+// left out for game and custom items; free-form materials need one.
+type CustomItemRequest struct {
+	Name        string                       `json:"name"`
+	Description string                       `json:"description"`
+	BuildPrice  int                          `json:"buildPrice"`
+	Components  []CustomItemComponentRequest `json:"components"`
+}
+
+type CustomItemComponentRequest struct {
+	UniqueName string `json:"uniqueName"`
+	Name       string `json:"name"`
+	ItemCount  int    `json:"itemCount"`
+}
+
+func (r CustomItemRequest) ToModel() models.CustomItemRequest {
+	return models.CustomItemRequest{
+		Name:        r.Name,
+		Description: r.Description,
+		BuildPrice:  r.BuildPrice,
+		Components: convert(r.Components, func(c CustomItemComponentRequest) models.CustomItemComponentRequest {
+			return models.CustomItemComponentRequest{UniqueName: c.UniqueName, Name: c.Name, ItemCount: c.ItemCount}
+		}),
+	}
+}
+
+// FoundryBuildRequest starts tracking a foundry build; a missing startedAt
+// means now.
+type FoundryBuildRequest struct {
+	UniqueName string     `json:"uniqueName"`
+	StartedAt  *time.Time `json:"startedAt"`
+}
+
+func (r FoundryBuildRequest) ToModel() models.FoundryBuildRequest {
+	return models.FoundryBuildRequest{UniqueName: r.UniqueName, StartedAt: r.StartedAt}
+}
+
+// WishlistTemplateRequest creates a wishlist template. Item quantities are
+// products of whole numbers and variable names, e.g. "2*forma"; a missing
+// quantity means 1 and a variable's missing max means 9999.
+type WishlistTemplateRequest struct {
+	Name      string                    `json:"name"`
+	Variables []TemplateVariableRequest `json:"variables"`
+	Items     []TemplateItemRequest     `json:"items"`
+}
+
+type TemplateVariableRequest struct {
+	Name    string `json:"name"`
+	Label   string `json:"label"`
+	Default *int   `json:"default"`
+	Min     int    `json:"min"`
+	Max     int    `json:"max"`
+}
+
+type TemplateItemRequest struct {
+	UniqueName string `json:"uniqueName"`
+	Quantity   string `json:"quantity"`
+}
+
+func (r WishlistTemplateRequest) ToModel() models.WishlistTemplateRequest {
+	return models.WishlistTemplateRequest{
+		Name: r.Name,
+		Variables: convert(r.Variables, func(v TemplateVariableRequest) models.TemplateVariable {
+			return models.TemplateVariable{Name: v.Name, Label: v.Label, Default: v.Default, Min: v.Min, Max: v.Max}
+		}),
+		Items: convert(r.Items, func(i TemplateItemRequest) models.TemplateItem {
+			return models.TemplateItem{UniqueName: i.UniqueName, Quantity: i.Quantity}
+		}),
+	}
+}
+
+// ApplyTemplateRequest gives the values of a template's variables; those
+// left out take their defaults.
+type ApplyTemplateRequest struct {
+	Variables map[string]int `json:"variables"`
+}
+
+// ResearchCostRequest selects the items to total research costs for; a
+// missing tier means ghost.
+type ResearchCostRequest struct {
+	Tier  string                `json:"tier"`
+	Items []ResearchItemRequest `json:"items"`
+}
+
+type ResearchItemRequest struct {
+	UniqueName string `json:"uniqueName"`
+	Lab        string `json:"lab"`
+}
+
+func (r ResearchCostRequest) ToModel() models.ResearchCostRequest {
+	return models.ResearchCostRequest{
+		Tier: r.Tier,
+		Items: convert(r.Items, func(i ResearchItemRequest) models.ResearchItemRequest {
+			return models.ResearchItemRequest(i)
+		}),
+	}
+}
+
+// NotificationChannelRequest registers a webhook URL, or a push
+// subscription as the browser's PushSubscription serializes it. Missing
+// events mean all of them.
+type NotificationChannelRequest struct {
+	Type   string          `json:"type"`
+	URL    string          `json:"url"`
+	Keys   PushKeysRequest `json:"keys"`
+	Events []string        `json:"events"`
+}
+
+type PushKeysRequest struct {
+	P256dh string `json:"p256dh"`
+	Auth   string `json:"auth"`
+}
+
+func (r NotificationChannelRequest) ToModel() models.NotificationChannelRequest {
+	return models.NotificationChannelRequest{
+		Type:   r.Type,
+		URL:    r.URL,
+		Keys:   models.PushKeys{P256dh: r.Keys.P256dh, Auth: r.Keys.Auth},
+		Events: r.Events,
+	}
+}
+
+// IntegrationRequest registers an integration's webhook URL. Missing events
+// mean all of them.
+type IntegrationRequest struct {
+	Name   string   `json:"name"`
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+}
+
+func (r IntegrationRequest) ToModel() models.IntegrationRequest {
+	return models.IntegrationRequest{Name: r.Name, URL: r.URL, Events: r.Events}
+}
+
+// WorkspaceRequest creates a workspace or replaces its name and description.
+type WorkspaceRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+func (r WorkspaceRequest) ToModel() models.WorkspaceRequest {
+	return models.WorkspaceRequest{Name: r.Name, Description: r.Description}
+}
+
+type WorkspaceMemberRequest struct {
+	UserID string `json:"userId"`
+	Role   string `json:"role"`
+}
+
+func (r WorkspaceMemberRequest) ToModel() models.WorkspaceMemberRequest {
+	return models.WorkspaceMemberRequest{UserID: r.UserID, Role: r.Role}
+}
+
+type WorkspaceMemberRoleRequest struct {
+	Role string `json:"role"`
+}
+
+// WorkspaceItemRequest adds an item to a workspace; a missing quantity
+// selects 1.
+type WorkspaceItemRequest struct {
+	UniqueName string `json:"uniqueName"`
+	Quantity   int    `json:"quantity"`
+}
+
+func (r WorkspaceItemRequest) ToModel() models.WorkspaceItemRequest {
+	return models.WorkspaceItemRequest{UniqueName: r.UniqueName, Quantity: r.Quantity}
+}
+
+// WorkspaceMaterialContributionRequest logs a contribution toward a
+// workspace material; a negative quantity corrects an earlier one.
+type WorkspaceMaterialContributionRequest struct {
+	UniqueName string `json:"uniqueName"`
+	Quantity   int    `json:"quantity"`
+}
+
+func (r WorkspaceMaterialContributionRequest) ToModel() models.WorkspaceMaterialContributionRequest {
+	return models.WorkspaceMaterialContributionRequest{UniqueName: r.UniqueName, Quantity: r.Quantity}
+}
+
+// DataSyncRequest is the optional body of the post-sync webhook.
+type DataSyncRequest struct {
+	Version string `json:"version"`
+}
@@ -0,0 +1,286 @@
+package dto
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+func TestRequestsMapToModels(t *testing.T) {
+	timeZone := "Europe/Berlin"
+	threshold := 5
+	foundryStart := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		body     string
+		decode   func(data []byte) (interface{}, error)
+		expected interface{}
+	}{
+		{
+			name: "add item",
+			body: `{"uniqueName":"/Lotus/Ash","quantity":2}`,
+			decode: func(data []byte) (interface{}, error) {
+				var req AddItemRequest
+				err := json.Unmarshal(data, &req)
+				return req.ToModel(), err
+			},
+			expected: models.AddItemRequest{UniqueName: "/Lotus/Ash", Quantity: 2},
+		},
+		{
+			name: "item links",
+			body: `{"links":[{"url":"https://example.com","title":"Guide"}]}`,
+			decode: func(data []byte) (interface{}, error) {
+				var req UpdateItemLinksRequest
+				err := json.Unmarshal(data, &req)
+				return req.ToModel(), err
+			},
+			expected: []models.SourceLinkRequest{{URL: "https://example.com", Title: "Guide"}},
+		},
+		{
+			name: "item progress",
+			body: `{"components":[{"uniqueName":"/Lotus/AshChassis","status":"built","count":1}]}`,
+			decode: func(data []byte) (interface{}, error) {
+				var req UpdateItemProgressRequest
+				err := json.Unmarshal(data, &req)
+				return req.ToModel(), err
+			},
+			expected: []models.ComponentProgressRequest{{UniqueName: "/Lotus/AshChassis", Status: models.ComponentBuilt, Count: 1}},
+		},
+		{
+			name: "add blueprint",
+			body: `{"uniqueName":"/Lotus/Bp"}`,
+			decode: func(data []byte) (interface{}, error) {
+				var req AddBlueprintRequest
+				err := json.Unmarshal(data, &req)
+				return req.ToModel(), err
+			},
+			expected: models.AddBlueprintRequest{UniqueName: "/Lotus/Bp"},
+		},
+		{
+			name: "bulk add blueprints",
+			body: `{"uniqueNames":["/Lotus/A","/Lotus/B"]}`,
+			decode: func(data []byte) (interface{}, error) {
+				var req BulkAddBlueprintsRequest
+				err := json.Unmarshal(data, &req)
+				return req.ToModel(), err
+			},
+			expected: models.BulkAddBlueprintsRequest{UniqueNames: []string{"/Lotus/A", "/Lotus/B"}},
+		},
+		{
+			name: "set owned materials",
+			body: `{"materials":[{"uniqueName":"/Lotus/Ferrite","count":500}]}`,
+			decode: func(data []byte) (interface{}, error) {
+				var req SetOwnedMaterialsRequest
+				err := json.Unmarshal(data, &req)
+				return req.ToModel(), err
+			},
+			expected: models.SetOwnedMaterialsRequest{Materials: []models.OwnedMaterialCount{{UniqueName: "/Lotus/Ferrite", Count: 500}}},
+		},
+		{
+			name: "set mastery",
+			body: `{"items":[{"uniqueName":"/Lotus/Excalibur","status":"mastered"}]}`,
+			decode: func(data []byte) (interface{}, error) {
+				var req SetMasteryRequest
+				err := json.Unmarshal(data, &req)
+				return req.ToModel(), err
+			},
+			expected: models.SetMasteryRequest{Items: []models.MasteryStatus{{UniqueName: "/Lotus/Excalibur", Status: models.MasteryStatusMastered}}},
+		},
+		{
+			name: "update settings",
+			body: `{"timeZone":"Europe/Berlin"}`,
+			decode: func(data []byte) (interface{}, error) {
+				var req UpdateSettingsRequest
+				err := json.Unmarshal(data, &req)
+				return req.ToModel(), err
+			},
+			expected: models.UpdateSettingsRequest{TimeZone: &timeZone},
+		},
+		{
+			name: "empty settings update leaves time zone unset",
+			body: `{}`,
+			decode: func(data []byte) (interface{}, error) {
+				var req UpdateSettingsRequest
+				err := json.Unmarshal(data, &req)
+				return req.ToModel(), err
+			},
+			expected: models.UpdateSettingsRequest{},
+		},
+		{
+			name: "import confirm",
+			body: `{"items":[{"uniqueName":"/Lotus/Forma","quantity":3}]}`,
+			decode: func(data []byte) (interface{}, error) {
+				var req ImportConfirmRequest
+				err := json.Unmarshal(data, &req)
+				return req.ToModel(), err
+			},
+			expected: models.ImportConfirmRequest{Items: []models.AddItemRequest{{UniqueName: "/Lotus/Forma", Quantity: 3}}},
+		},
+		{
+			name: "request manager",
+			body: `{"managerUserId":"manager","quantityThreshold":5}`,
+			decode: func(data []byte) (interface{}, error) {
+				var req RequestManagerRequest
+				err := json.Unmarshal(data, &req)
+				return req.ToModel(), err
+			},
+			expected: models.RequestManagerRequest{ManagerUserID: "manager", QuantityThreshold: 5},
+		},
+		{
+			name: "update household member",
+			body: `{"quantityThreshold":5}`,
+			decode: func(data []byte) (interface{}, error) {
+				var req UpdateHouseholdMemberRequest
+				err := json.Unmarshal(data, &req)
+				return req.ToModel(), err
+			},
+			expected: models.UpdateHouseholdMemberRequest{QuantityThreshold: &threshold},
+		},
+		{
+			name: "custom item",
+			body: `{"name":"Dojo Garden","description":"Decoration","buildPrice":5000,"components":[{"uniqueName":"/Lotus/Ferrite","itemCount":1000},{"uniqueName":"/Goals/Hours","name":"Hours","itemCount":3}]}`,
+			decode: func(data []byte) (interface{}, error) {
+				var req CustomItemRequest
+				err := json.Unmarshal(data, &req)
+				return req.ToModel(), err
+			},
+			expected: models.CustomItemRequest{Name: "Dojo Garden", Description: "Decoration", BuildPrice: 5000, Components: []models.CustomItemComponentRequest{
+				{UniqueName: "/Lotus/Ferrite", ItemCount: 1000},
+				{UniqueName: "/Goals/Hours", Name: "Hours", ItemCount: 3},
+			}},
+		},
+		{
+			name: "workspace member",
+			body: `{"userId":"user-2","role":"editor"}`,
+			decode: func(data []byte) (interface{}, error) {
+				var req WorkspaceMemberRequest
+				err := json.Unmarshal(data, &req)
+				return req.ToModel(), err
+			},
+			expected: models.WorkspaceMemberRequest{UserID: "user-2", Role: "editor"},
+		},
+		{
+			name: "workspace item",
+			body: `{"uniqueName":"/Lotus/Forma","quantity":10}`,
+			decode: func(data []byte) (interface{}, error) {
+				var req WorkspaceItemRequest
+				err := json.Unmarshal(data, &req)
+				return req.ToModel(), err
+			},
+			expected: models.WorkspaceItemRequest{UniqueName: "/Lotus/Forma", Quantity: 10},
+		},
+		{
+			name: "foundry build",
+			body: `{"uniqueName":"/Lotus/Forma","startedAt":"2026-10-01T12:00:00Z"}`,
+			decode: func(data []byte) (interface{}, error) {
+				var req FoundryBuildRequest
+				err := json.Unmarshal(data, &req)
+				return req.ToModel(), err
+			},
+			expected: models.FoundryBuildRequest{UniqueName: "/Lotus/Forma", StartedAt: &foundryStart},
+		},
+		{
+			name: "research cost",
+			body: `{"tier":"storm","items":[{"uniqueName":"/Lotus/Ogris","lab":"Chem Lab"}]}`,
+			decode: func(data []byte) (interface{}, error) {
+				var req ResearchCostRequest
+				err := json.Unmarshal(data, &req)
+				return req.ToModel(), err
+			},
+			expected: models.ResearchCostRequest{Tier: "storm", Items: []models.ResearchItemRequest{{UniqueName: "/Lotus/Ogris", Lab: "Chem Lab"}}},
+		},
+		{
+			name: "notification channel",
+			body: `{"type":"push","url":"https://push.example.com/abc","keys":{"p256dh":"BPk","auth":"c2Vj"},"events":["foundry.finished"]}`,
+			decode: func(data []byte) (interface{}, error) {
+				var req NotificationChannelRequest
+				err := json.Unmarshal(data, &req)
+				return req.ToModel(), err
+			},
+			expected: models.NotificationChannelRequest{
+				Type: "push", URL: "https://push.example.com/abc",
+				Keys: models.PushKeys{P256dh: "BPk", Auth: "c2Vj"}, Events: []string{"foundry.finished"},
+			},
+		},
+		{
+			name: "workspace material contribution",
+			body: `{"uniqueName":"/Lotus/Alloy","quantity":-20}`,
+			decode: func(data []byte) (interface{}, error) {
+				var req WorkspaceMaterialContributionRequest
+				err := json.Unmarshal(data, &req)
+				return req.ToModel(), err
+			},
+			expected: models.WorkspaceMaterialContributionRequest{UniqueName: "/Lotus/Alloy", Quantity: -20},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.decode([]byte(tt.body))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}
+
+// TestTypesCarryNoStorageTags guards the split between storage and API
+// models: every field of an API type is named by a json tag and none of them
+// carry bson tags.
+func TestTypesCarryNoStorageTags(t *testing.T) {
+	types := []interface{}{
+		ItemDetail{}, ItemStats{}, FrameStats{}, Ability{}, WeaponStats{}, Component{}, Recipe{}, Drop{}, ItemSummary{}, ItemSearchResponse{}, ItemSuggestion{}, ItemAutocompleteResponse{}, ItemChange{}, ItemChangesResponse{},
+		RecipeTree{}, RecipeNode{}, RecipeEdge{}, ItemRefreshStatus{}, SyncRun{}, ItemSyncStats{},
+		Wishlist{}, WishlistItem{}, SourceLink{}, ItemLinks{}, ItemRecipe{}, ItemProgress{}, ComponentProgress{}, ExpandedWishlist{}, ExpandedWishlistItem{}, PublicWishlist{},
+		GiftClaim{}, SharedWishlist{}, SharedWishlistItem{},
+		ShareLink{}, ShareLinkPermissions{}, ShareLinkView{}, ShareLinkViewItem{}, ShareLinkReport{}, ImportAlias{},
+		CustomItem{}, CustomItemComponent{}, CustomItems{}, Foundry{}, FoundryBuild{},
+		Workspace{}, WorkspaceMember{}, WorkspaceItem{}, WorkspaceContribution{},
+		WorkspaceMaterials{}, WorkspaceMaterial{}, MemberContribution{}, WorkspaceActivity{}, WorkspaceEvent{}, WorkspacePresence{},
+		MaterialsSummary{}, MaterialRequirement{}, DegradedSection{}, Amount{}, Duration{},
+		OwnedBlueprints{}, OwnedBlueprint{}, OwnedComponents{}, OwnedComponent{}, UserUsage{}, ResourceUsage{}, OwnedMaterials{}, OwnedMaterial{}, MasteryProfile{}, MasteryEntry{}, MasterySummary{}, MasteryCategorySummary{}, UserSettings{}, DefaultQuantityRule{},
+		HouseholdLink{}, PendingChange{}, Household{}, HouseholdApprovals{}, UserTrace{},
+		ImportCandidate{}, ImportMatch{}, ImportAmbiguous{}, ImportUnmatched{}, ImportPreview{},
+		ImportItemResult{}, ImportConfirmResult{},
+		WishlistExport{}, WishlistExportItem{}, BlueprintImportResult{}, WishlistDocumentImportResult{}, ProfileImportResult{}, ProfileImportTally{}, ProfileImportSkipped{},
+		Link{}, HALWishlist{}, HALWishlistItem{}, HALExpandedWishlist{}, HALExpandedWishlistItem{},
+		HALItemSearchResponse{}, HALItemSummary{}, BuildVersion{}, ValidationProblems{}, ValidationProblem{},
+		FarmingPlan{}, FarmingLocation{}, FarmingMaterial{},
+		RelicRequirements{}, RelicRequirement{}, RelicPart{}, RelicChances{}, PrimePart{},
+		Opportunities{}, Opportunity{}, OpportunityMaterial{}, WorldStateReward{},
+		AddedItem{}, QuantitySuggestion{}, QuantityUsage{},
+		ClanTier{}, ResearchCost{}, ResearchLabCost{}, ResearchItemCost{}, ResearchMaterial{},
+		NotificationChannel{}, CreatedNotificationChannel{}, NotificationDelivery{}, PushKey{},
+		Integration{}, CreatedIntegration{}, IntegrationDelivery{},
+		AddItemRequest{}, UpdateQuantityRequest{}, SourceLinkRequest{}, UpdateItemLinksRequest{}, SetItemRecipeRequest{},
+		ComponentProgressRequest{}, UpdateItemProgressRequest{},
+		AddBlueprintRequest{}, BulkAddBlueprintsRequest{}, OwnedMaterialCount{}, SetOwnedMaterialsRequest{}, MasteryStatus{}, SetMasteryRequest{}, SetMasteryStatusRequest{},
+		SetOwnedMaterialCountRequest{}, SetOwnedComponentCountRequest{}, UpdateSettingsRequest{}, RequestManagerRequest{},
+		UpdateHouseholdMemberRequest{}, DataSyncRequest{}, ImportTextRequest{}, ImportConfirmRequest{},
+		EnableUserTraceRequest{}, ClaimGiftRequest{}, CreateShareLinkRequest{}, ReportShareLinkRequest{}, ImportAliasRequest{},
+		CustomItemRequest{}, CustomItemComponentRequest{}, FoundryBuildRequest{}, ResearchCostRequest{}, ResearchItemRequest{},
+		NotificationChannelRequest{}, PushKeysRequest{}, IntegrationRequest{},
+		WorkspaceRequest{}, WorkspaceMemberRequest{}, WorkspaceMemberRoleRequest{}, WorkspaceItemRequest{},
+		WorkspaceMaterialContributionRequest{},
+	}
+
+	for _, v := range types {
+		typ := reflect.TypeOf(v)
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			if _, ok := field.Tag.Lookup("bson"); ok {
+				t.Errorf("%s.%s has a bson tag", typ.Name(), field.Name)
+			}
+			if _, ok := field.Tag.Lookup("json"); !ok && !field.Anonymous {
+				t.Errorf("%s.%s has no json tag", typ.Name(), field.Name)
+			}
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package dto
+
+import "github.com/graytonio/warframe-wishlist/internal/models"
+
+type ClanTier struct {
+	Name       string `json:"name"`
+	Multiplier int    `json:"multiplier"`
+}
+
+type ResearchMaterial struct {
+	UniqueName string `json:"uniqueName"`
+	Name       string `json:"name"`
+	ImageName  string `json:"imageName"`
+	Count      int    `json:"count"`
+}
+
+type ResearchItemCost struct {
+	UniqueName string             `json:"uniqueName"`
+	Name       string             `json:"name"`
+	Lab        string             `json:"lab"`
+	Credits    int                `json:"credits"`
+	Materials  []ResearchMaterial `json:"materials"`
+}
+
+type ResearchLabCost struct {
+	Lab       string             `json:"lab"`
+	Items     []ResearchItemCost `json:"items"`
+	Credits   int                `json:"credits"`
+	Materials []ResearchMaterial `json:"materials"`
+}
+
+// ResearchCost is the research cost of the requested items at one clan
+// tier, by lab and in total; notFound lists items missing from the item
+// data.
+type ResearchCost struct {
+	Tier       string             `json:"tier"`
+	Multiplier int                `json:"multiplier"`
+	Labs       []ResearchLabCost  `json:"labs"`
+	Credits    int                `json:"credits"`
+	Materials  []ResearchMaterial `json:"materials"`
+	NotFound   []string           `json:"notFound"`
+}
+
+func NewClanTiers(tiers []models.ClanTier) []ClanTier {
+	return convert(tiers, func(t models.ClanTier) ClanTier { return ClanTier(t) })
+}
+
+func NewResearchCost(cost *models.ResearchCost) *ResearchCost {
+	if cost == nil {
+		return nil
+	}
+	return &ResearchCost{
+		Tier:       cost.Tier,
+		Multiplier: cost.Multiplier,
+		Labs: convert(cost.Labs, func(l models.ResearchLabCost) ResearchLabCost {
+			return ResearchLabCost{
+				Lab:       l.Lab,
+				Items:     convert(l.Items, researchItemCost),
+				Credits:   l.Credits,
+				Materials: convert(l.Materials, researchMaterial),
+			}
+		}),
+		Credits:   cost.Credits,
+		Materials: convert(cost.Materials, researchMaterial),
+		NotFound:  convert(cost.NotFound, func(uniqueName string) string { return uniqueName }),
+	}
+}
+
+func researchItemCost(c models.ResearchItemCost) ResearchItemCost {
+	return ResearchItemCost{
+		UniqueName: c.UniqueName,
+		Name:       c.Name,
+		Lab:        c.Lab,
+		Credits:    c.Credits,
+		Materials:  convert(c.Materials, researchMaterial),
+	}
+}
+
+func researchMaterial(m models.ResearchMaterial) ResearchMaterial {
+	return ResearchMaterial(m)
+}
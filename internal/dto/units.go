@@ -0,0 +1,69 @@
+package dto
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Unit identifiers attached to numeric values so clients can pick localized
+// labels and plural forms without guessing what a bare number means.
+const (
+	UnitCredits  = "credits"
+	UnitPlatinum = "platinum"
+	UnitSeconds  = "seconds"
+)
+
+// Amount is a raw count paired with its unit.
+type Amount struct {
+	Value int    `json:"value"`
+	Unit  string `json:"unit"`
+}
+
+// Duration is a raw second count paired with its ISO-8601 representation.
+type Duration struct {
+	Seconds int    `json:"seconds"`
+	ISO8601 string `json:"iso8601"`
+}
+
+func NewAmount(value int, unit string) Amount {
+	return Amount{Value: value, Unit: unit}
+}
+
+func NewDuration(seconds int) Duration {
+	return Duration{Seconds: seconds, ISO8601: ISO8601Duration(seconds)}
+}
+
+// ISO8601Duration formats a number of seconds as an ISO-8601 duration
+// (e.g. 43200 -> "PT12H", 90061 -> "P1DT1H1M1S"). Negative values are
+// clamped to zero.
+func ISO8601Duration(seconds int) string {
+	if seconds <= 0 {
+		return "PT0S"
+	}
+
+	days := seconds / 86400
+	seconds %= 86400
+	hours := seconds / 3600
+	seconds %= 3600
+	minutes := seconds / 60
+	seconds %= 60
+
+	var b strings.Builder
+	b.WriteString("P")
+	if days > 0 {
+		b.WriteString(strconv.Itoa(days) + "D")
+	}
+	if hours > 0 || minutes > 0 || seconds > 0 {
+		b.WriteString("T")
+		if hours > 0 {
+			b.WriteString(strconv.Itoa(hours) + "H")
+		}
+		if minutes > 0 {
+			b.WriteString(strconv.Itoa(minutes) + "M")
+		}
+		if seconds > 0 {
+			b.WriteString(strconv.Itoa(seconds) + "S")
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,29 @@
+package dto
+
+import "github.com/graytonio/warframe-wishlist/internal/models"
+
+// ValidationProblems is the detail of a 422 response: every entry of the
+// request that was rejected, and why.
+type ValidationProblems struct {
+	Problems []ValidationProblem `json:"problems"`
+}
+
+// ValidationProblem names the field at fault, e.g. "materials[2].count", and
+// the uniqueName of its entry when it has one.
+type ValidationProblem struct {
+	Field      string `json:"field"`
+	UniqueName string `json:"uniqueName,omitempty"`
+	Reason     string `json:"reason"`
+}
+
+func NewValidationProblems(problems []models.ValidationProblem) *ValidationProblems {
+	return &ValidationProblems{Problems: convert(problems, NewValidationProblem)}
+}
+
+func NewValidationProblem(problem models.ValidationProblem) ValidationProblem {
+	return ValidationProblem{
+		Field:      problem.Field,
+		UniqueName: problem.UniqueName,
+		Reason:     problem.Reason,
+	}
+}
@@ -0,0 +1,63 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+// WishlistTemplate is a reusable set of wishlist items. Item quantities may
+// name the template's variables, whose values are given when it is applied.
+type WishlistTemplate struct {
+	ID        string             `json:"id"`
+	Name      string             `json:"name"`
+	Variables []TemplateVariable `json:"variables"`
+	Items     []TemplateItem     `json:"items"`
+	CreatedAt time.Time          `json:"createdAt"`
+}
+
+// TemplateVariable is a value asked for when a template is applied; one
+// without a default must be given.
+type TemplateVariable struct {
+	Name    string `json:"name"`
+	Label   string `json:"label,omitempty"`
+	Default *int   `json:"default"`
+	Min     int    `json:"min"`
+	Max     int    `json:"max"`
+}
+
+// WishlistTemplates lists a user's templates, oldest first.
+type WishlistTemplates struct {
+	Templates []WishlistTemplate `json:"templates"`
+}
+
+type TemplateItem struct {
+	UniqueName string `json:"uniqueName"`
+	Quantity   string `json:"quantity"`
+}
+
+func NewWishlistTemplates(templates []models.WishlistTemplate) *WishlistTemplates {
+	return &WishlistTemplates{Templates: convert(templates, wishlistTemplate)}
+}
+
+func NewWishlistTemplate(t *models.WishlistTemplate) *WishlistTemplate {
+	if t == nil {
+		return nil
+	}
+	result := wishlistTemplate(*t)
+	return &result
+}
+
+func wishlistTemplate(t models.WishlistTemplate) WishlistTemplate {
+	return WishlistTemplate{
+		ID:   t.ID.Hex(),
+		Name: t.Name,
+		Variables: convert(t.Variables, func(v models.TemplateVariable) TemplateVariable {
+			return TemplateVariable{Name: v.Name, Label: v.Label, Default: v.Default, Min: v.Min, Max: v.Max}
+		}),
+		Items: convert(t.Items, func(i models.TemplateItem) TemplateItem {
+			return TemplateItem{UniqueName: i.UniqueName, Quantity: i.Quantity}
+		}),
+		CreatedAt: t.CreatedAt,
+	}
+}
@@ -0,0 +1,383 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Wishlist struct {
+	ID        primitive.ObjectID `json:"id"`
+	UserID    string             `json:"userId"`
+	Items     []WishlistItem     `json:"items"`
+	CreatedAt time.Time          `json:"createdAt"`
+	UpdatedAt time.Time          `json:"updatedAt"`
+}
+
+type WishlistItem struct {
+	UniqueName string              `json:"uniqueName"`
+	Quantity   int                 `json:"quantity"`
+	AddedAt    time.Time           `json:"addedAt"`
+	Links      []SourceLink        `json:"links"`
+	RecipeID   string              `json:"recipeId"`
+	Progress   []ComponentProgress `json:"progress"`
+	// Completion is the percentage of the item's components done.
+	Completion int `json:"completion"`
+}
+
+type ComponentProgress struct {
+	UniqueName string `json:"uniqueName"`
+	Status     string `json:"status"`
+	Count      int    `json:"count"`
+}
+
+type SourceLink struct {
+	URL   string `json:"url"`
+	Title string `json:"title"`
+	Host  string `json:"host"`
+}
+
+// ItemLinks is the response to replacing a wishlist item's source links.
+type ItemLinks struct {
+	UniqueName string       `json:"uniqueName"`
+	Links      []SourceLink `json:"links"`
+}
+
+// ItemProgress is the response to replacing a wishlist item's component
+// progress.
+type ItemProgress struct {
+	UniqueName string              `json:"uniqueName"`
+	Progress   []ComponentProgress `json:"progress"`
+	Completion int                 `json:"completion"`
+}
+
+// ItemRecipe is the response to selecting a wishlist item's recipe.
+type ItemRecipe struct {
+	UniqueName string `json:"uniqueName"`
+	RecipeID   string `json:"recipeId"`
+}
+
+type QuantityUsage struct {
+	UniqueName string `json:"uniqueName"`
+	Name       string `json:"name"`
+	Count      int    `json:"count"`
+}
+
+// QuantitySuggestion is advisory: how many of the added item the rest of the
+// wishlist's recipes use.
+type QuantitySuggestion struct {
+	UniqueName string          `json:"uniqueName"`
+	Suggested  int             `json:"suggested"`
+	UsedBy     []QuantityUsage `json:"usedBy"`
+}
+
+// AddedItem is the response to adding a wishlist item. Suggestion is null
+// when no other wishlist item uses the added one.
+type AddedItem struct {
+	Message    string              `json:"message"`
+	Suggestion *QuantitySuggestion `json:"suggestion"`
+}
+
+// ExpandedWishlistItem is a wishlist item with its item summary attached.
+// Item is null when the item no longer exists in the game data.
+type ExpandedWishlistItem struct {
+	WishlistItem
+	Item *ItemSummary `json:"item"`
+}
+
+type ExpandedWishlist struct {
+	UserID    string                 `json:"userId"`
+	Items     []ExpandedWishlistItem `json:"items"`
+	CreatedAt time.Time              `json:"createdAt"`
+	UpdatedAt time.Time              `json:"updatedAt"`
+}
+
+type PublicWishlist struct {
+	ID          string         `json:"id"`
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Items       []WishlistItem `json:"items"`
+}
+
+// GiftClaim marks an item on another user's public wishlist as being bought
+// by claimerId until expiresAt.
+type GiftClaim struct {
+	ID         primitive.ObjectID `json:"id"`
+	OwnerID    string             `json:"ownerId"`
+	UniqueName string             `json:"uniqueName"`
+	ClaimerID  string             `json:"claimerId"`
+	Anonymous  bool               `json:"anonymous"`
+	CreatedAt  time.Time          `json:"createdAt"`
+	ExpiresAt  time.Time          `json:"expiresAt"`
+}
+
+// SharedWishlistItem is an item on another user's public wishlist. Claim is
+// null when the item is unclaimed or claimed anonymously by someone else;
+// claimed tells the two apart.
+type SharedWishlistItem struct {
+	UniqueName string     `json:"uniqueName"`
+	Quantity   int        `json:"quantity"`
+	AddedAt    time.Time  `json:"addedAt"`
+	Claimed    bool       `json:"claimed"`
+	Claim      *GiftClaim `json:"claim"`
+}
+
+type SharedWishlist struct {
+	OwnerID string               `json:"ownerId"`
+	Items   []SharedWishlistItem `json:"items"`
+}
+
+// ShareLinkPermissions is what a share link hides.
+type ShareLinkPermissions struct {
+	HideQuantities bool `json:"hideQuantities"`
+	HideLinks      bool `json:"hideLinks"`
+	HideMaterials  bool `json:"hideMaterials"`
+}
+
+type ShareLink struct {
+	ID          primitive.ObjectID   `json:"id"`
+	Token       string               `json:"token"`
+	Label       string               `json:"label"`
+	Permissions ShareLinkPermissions `json:"permissions"`
+	CreatedAt   time.Time            `json:"createdAt"`
+}
+
+// ShareLinkViewItem is a wishlist item seen through a share link; quantity
+// and links are null when the link hides them.
+type ShareLinkViewItem struct {
+	UniqueName string       `json:"uniqueName"`
+	Quantity   *int         `json:"quantity"`
+	AddedAt    time.Time    `json:"addedAt"`
+	Links      []SourceLink `json:"links"`
+}
+
+// ShareLinkView is a wishlist seen through a share link. updatedAt is null
+// when the owner has no wishlist yet.
+type ShareLinkView struct {
+	Permissions ShareLinkPermissions `json:"permissions"`
+	Items       []ShareLinkViewItem  `json:"items"`
+	UpdatedAt   *time.Time           `json:"updatedAt"`
+}
+
+func NewWishlist(wishlist *models.Wishlist) *Wishlist {
+	if wishlist == nil {
+		return nil
+	}
+	return &Wishlist{
+		ID:        wishlist.ID,
+		UserID:    wishlist.UserID,
+		Items:     convert(wishlist.Items, NewWishlistItem),
+		CreatedAt: wishlist.CreatedAt,
+		UpdatedAt: wishlist.UpdatedAt,
+	}
+}
+
+func NewWishlistItem(item models.WishlistItem) WishlistItem {
+	return WishlistItem{
+		UniqueName: item.UniqueName,
+		Quantity:   item.Quantity,
+		AddedAt:    item.AddedAt,
+		Links:      convert(item.Links, NewSourceLink),
+		RecipeID:   item.RecipeID,
+		Progress:   convert(item.Progress, NewComponentProgress),
+		Completion: item.Completion,
+	}
+}
+
+func NewComponentProgress(progress models.ComponentProgress) ComponentProgress {
+	return ComponentProgress{
+		UniqueName: progress.UniqueName,
+		Status:     progress.Status,
+		Count:      progress.Count,
+	}
+}
+
+func NewSourceLink(link models.SourceLink) SourceLink {
+	return SourceLink{
+		URL:   link.URL,
+		Title: link.Title,
+		Host:  link.Host,
+	}
+}
+
+func NewItemLinks(uniqueName string, links []models.SourceLink) *ItemLinks {
+	return &ItemLinks{
+		UniqueName: uniqueName,
+		Links:      convert(links, NewSourceLink),
+	}
+}
+
+func NewItemProgress(item *models.WishlistItem) *ItemProgress {
+	if item == nil {
+		return nil
+	}
+	return &ItemProgress{
+		UniqueName: item.UniqueName,
+		Progress:   convert(item.Progress, NewComponentProgress),
+		Completion: item.Completion,
+	}
+}
+
+func NewQuantitySuggestion(suggestion *models.QuantitySuggestion) *QuantitySuggestion {
+	if suggestion == nil {
+		return nil
+	}
+	return &QuantitySuggestion{
+		UniqueName: suggestion.UniqueName,
+		Suggested:  suggestion.Suggested,
+		UsedBy:     convert(suggestion.UsedBy, func(u models.QuantityUsage) QuantityUsage { return QuantityUsage(u) }),
+	}
+}
+
+func NewItemRecipe(uniqueName, recipeID string) *ItemRecipe {
+	return &ItemRecipe{
+		UniqueName: uniqueName,
+		RecipeID:   recipeID,
+	}
+}
+
+func NewExpandedWishlist(wishlist *models.ExpandedWishlist) *ExpandedWishlist {
+	if wishlist == nil {
+		return nil
+	}
+	return &ExpandedWishlist{
+		UserID:    wishlist.UserID,
+		Items:     convert(wishlist.Items, newExpandedWishlistItem),
+		CreatedAt: wishlist.CreatedAt,
+		UpdatedAt: wishlist.UpdatedAt,
+	}
+}
+
+func newExpandedWishlistItem(item models.ExpandedWishlistItem) ExpandedWishlistItem {
+	expanded := ExpandedWishlistItem{WishlistItem: NewWishlistItem(item.WishlistItem)}
+	if item.Item != nil {
+		summary := NewItemSummary(*item.Item)
+		expanded.Item = &summary
+	}
+	return expanded
+}
+
+func NewPublicWishlist(wishlist *models.PublicWishlist) *PublicWishlist {
+	if wishlist == nil {
+		return nil
+	}
+	return &PublicWishlist{
+		ID:          wishlist.ID,
+		Name:        wishlist.Name,
+		Description: wishlist.Description,
+		Items:       convert(wishlist.Items, NewWishlistItem),
+	}
+}
+
+func NewPublicWishlists(wishlists []models.PublicWishlist) []PublicWishlist {
+	return convert(wishlists, func(w models.PublicWishlist) PublicWishlist {
+		return *NewPublicWishlist(&w)
+	})
+}
+
+func NewGiftClaim(claim *models.GiftClaim) *GiftClaim {
+	if claim == nil {
+		return nil
+	}
+	result := giftClaim(*claim)
+	return &result
+}
+
+func NewGiftClaims(claims []models.GiftClaim) []GiftClaim {
+	return convert(claims, giftClaim)
+}
+
+func NewSharedWishlist(wishlist *models.SharedWishlist) *SharedWishlist {
+	if wishlist == nil {
+		return nil
+	}
+	return &SharedWishlist{
+		OwnerID: wishlist.OwnerID,
+		Items:   convert(wishlist.Items, sharedWishlistItem),
+	}
+}
+
+func giftClaim(claim models.GiftClaim) GiftClaim {
+	return GiftClaim{
+		ID:         claim.ID,
+		OwnerID:    claim.OwnerID,
+		UniqueName: claim.UniqueName,
+		ClaimerID:  claim.ClaimerID,
+		Anonymous:  claim.Anonymous,
+		CreatedAt:  claim.CreatedAt,
+		ExpiresAt:  claim.ExpiresAt,
+	}
+}
+
+func sharedWishlistItem(item models.SharedWishlistItem) SharedWishlistItem {
+	return SharedWishlistItem{
+		UniqueName: item.UniqueName,
+		Quantity:   item.Quantity,
+		AddedAt:    item.AddedAt,
+		Claimed:    item.Claimed,
+		Claim:      NewGiftClaim(item.Claim),
+	}
+}
+
+func NewShareLink(link *models.ShareLink) *ShareLink {
+	if link == nil {
+		return nil
+	}
+	result := shareLink(*link)
+	return &result
+}
+
+func NewShareLinks(links []models.ShareLink) []ShareLink {
+	return convert(links, shareLink)
+}
+
+func NewShareLinkView(view *models.ShareLinkView) *ShareLinkView {
+	if view == nil {
+		return nil
+	}
+	return &ShareLinkView{
+		Permissions: shareLinkPermissions(view.Permissions),
+		Items:       convert(view.Items, shareLinkViewItem),
+		UpdatedAt:   optionalTime(view.UpdatedAt),
+	}
+}
+
+func shareLink(link models.ShareLink) ShareLink {
+	return ShareLink{
+		ID:          link.ID,
+		Token:       link.Token,
+		Label:       link.Label,
+		Permissions: shareLinkPermissions(link.Permissions),
+		CreatedAt:   link.CreatedAt,
+	}
+}
+
+func shareLinkPermissions(permissions models.ShareLinkPermissions) ShareLinkPermissions {
+	return ShareLinkPermissions{
+		HideQuantities: permissions.HideQuantities,
+		HideLinks:      permissions.HideLinks,
+		HideMaterials:  permissions.HideMaterials,
+	}
+}
+
+func (p ShareLinkPermissions) ToModel() models.ShareLinkPermissions {
+	return models.ShareLinkPermissions{
+		HideQuantities: p.HideQuantities,
+		HideLinks:      p.HideLinks,
+		HideMaterials:  p.HideMaterials,
+	}
+}
+
+func shareLinkViewItem(item models.ShareLinkViewItem) ShareLinkViewItem {
+	result := ShareLinkViewItem{
+		UniqueName: item.UniqueName,
+		Quantity:   item.Quantity,
+		AddedAt:    item.AddedAt,
+	}
+	// Hidden links stay null; visible ones are always a list.
+	if item.Links != nil {
+		result.Links = convert(item.Links, NewSourceLink)
+	}
+	return result
+}
@@ -0,0 +1,221 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+// Workspace is a wishlist shared by a clan. Each item carries what the
+// members have contributed and what is still needed.
+type Workspace struct {
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Members     []WorkspaceMember `json:"members"`
+	Items       []WorkspaceItem   `json:"items"`
+	Version     int64             `json:"version"`
+	CreatedAt   time.Time         `json:"createdAt"`
+	UpdatedAt   time.Time         `json:"updatedAt"`
+}
+
+type WorkspaceMember struct {
+	UserID  string    `json:"userId"`
+	Role    string    `json:"role"`
+	AddedAt time.Time `json:"addedAt"`
+}
+
+type WorkspaceItem struct {
+	UniqueName    string                  `json:"uniqueName"`
+	Quantity      int                     `json:"quantity"`
+	Contributed   int                     `json:"contributed"`
+	Remaining     int                     `json:"remaining"`
+	AddedBy       string                  `json:"addedBy"`
+	AddedAt       time.Time               `json:"addedAt"`
+	Contributions []WorkspaceContribution `json:"contributions"`
+}
+
+type WorkspaceContribution struct {
+	UserID    string    `json:"userId"`
+	Quantity  int       `json:"quantity"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func NewWorkspace(w *models.Workspace) *Workspace {
+	if w == nil {
+		return nil
+	}
+	result := workspace(*w)
+	return &result
+}
+
+func NewWorkspaces(workspaces []models.Workspace) []Workspace {
+	return convert(workspaces, workspace)
+}
+
+func workspace(w models.Workspace) Workspace {
+	return Workspace{
+		ID:          w.ID.Hex(),
+		Name:        w.Name,
+		Description: w.Description,
+		Members:     convert(w.Members, workspaceMember),
+		Items:       convert(w.Items, workspaceItem),
+		Version:     w.Version,
+		CreatedAt:   w.CreatedAt,
+		UpdatedAt:   w.UpdatedAt,
+	}
+}
+
+func workspaceMember(m models.WorkspaceMember) WorkspaceMember {
+	return WorkspaceMember{UserID: m.UserID, Role: m.Role, AddedAt: m.AddedAt}
+}
+
+func workspaceItem(item models.WorkspaceItem) WorkspaceItem {
+	return WorkspaceItem{
+		UniqueName:  item.UniqueName,
+		Quantity:    item.Quantity,
+		Contributed: item.Contributed(),
+		Remaining:   item.Remaining(),
+		AddedBy:     item.AddedBy,
+		AddedAt:     item.AddedAt,
+		Contributions: convert(item.Contributions, func(c models.WorkspaceContribution) WorkspaceContribution {
+			return WorkspaceContribution{UserID: c.UserID, Quantity: c.Quantity, UpdatedAt: c.UpdatedAt}
+		}),
+	}
+}
+
+// WorkspaceMaterials is what a workspace's items still need once the
+// members' material contributions are taken off. Members totals each
+// member's material contributions.
+type WorkspaceMaterials struct {
+	Materials       []WorkspaceMaterial  `json:"materials"`
+	Members         []MemberContribution `json:"members"`
+	TotalCredits    int                  `json:"totalCredits"`
+	RushPlatinum    int                  `json:"rushPlatinum"`
+	Truncated       bool                 `json:"truncated"`
+	TruncatedReason string               `json:"truncatedReason"`
+	Degraded        []DegradedSection    `json:"degraded"`
+	Credits         Amount               `json:"credits"`
+	RushCost        Amount               `json:"rushCost"`
+}
+
+type WorkspaceMaterial struct {
+	MaterialRequirement
+	Contributed   int                  `json:"contributed"`
+	Contributions []MemberContribution `json:"contributions"`
+}
+
+type MemberContribution struct {
+	UserID   string `json:"userId"`
+	Quantity int    `json:"quantity"`
+}
+
+// WorkspaceActivity is one entry of a workspace's activity feed: a material
+// contribution, or a change to a member's contribution to an item. Negative
+// quantities are corrections.
+type WorkspaceActivity struct {
+	ID         string    `json:"id"`
+	UserID     string    `json:"userId"`
+	Kind       string    `json:"kind"`
+	UniqueName string    `json:"uniqueName"`
+	Quantity   int       `json:"quantity"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+func NewWorkspaceMaterials(m *models.WorkspaceMaterials) *WorkspaceMaterials {
+	if m == nil {
+		return nil
+	}
+	return &WorkspaceMaterials{
+		Materials: convert(m.Materials, func(material models.WorkspaceMaterial) WorkspaceMaterial {
+			return WorkspaceMaterial{
+				MaterialRequirement: NewMaterialRequirement(material.MaterialRequirement),
+				Contributed:         material.Contributed,
+				Contributions:       convert(material.Contributions, memberContribution),
+			}
+		}),
+		Members:         convert(m.Members, memberContribution),
+		TotalCredits:    m.TotalCredits,
+		RushPlatinum:    m.RushPlatinum,
+		Truncated:       m.Truncated,
+		TruncatedReason: m.TruncatedReason,
+		Degraded:        degraded(m.Degradation),
+		Credits:         NewAmount(m.TotalCredits, UnitCredits),
+		RushCost:        NewAmount(m.RushPlatinum, UnitPlatinum),
+	}
+}
+
+func NewWorkspaceActivity(e *models.WorkspaceContributionEvent) *WorkspaceActivity {
+	if e == nil {
+		return nil
+	}
+	result := workspaceActivity(*e)
+	return &result
+}
+
+func NewWorkspaceActivities(events []models.WorkspaceContributionEvent) []WorkspaceActivity {
+	return convert(events, workspaceActivity)
+}
+
+func workspaceActivity(e models.WorkspaceContributionEvent) WorkspaceActivity {
+	return WorkspaceActivity{
+		ID:         e.ID.Hex(),
+		UserID:     e.UserID,
+		Kind:       e.Kind,
+		UniqueName: e.UniqueName,
+		Quantity:   e.Quantity,
+		CreatedAt:  e.CreatedAt,
+	}
+}
+
+func memberContribution(c models.MemberContribution) MemberContribution {
+	return MemberContribution{UserID: c.UserID, Quantity: c.Quantity}
+}
+
+// PresenceEventType is the type of the WorkspacePresence messages sent over a
+// workspace's live connection.
+const PresenceEventType = "presence"
+
+// WorkspaceEvent is a live change sent over a workspace's WebSocket. Item
+// is null for events that are not about an item, and for removed items.
+type WorkspaceEvent struct {
+	Type       string         `json:"type"`
+	UserID     string         `json:"userId"`
+	UniqueName string         `json:"uniqueName"`
+	Item       *WorkspaceItem `json:"item"`
+	MemberID   string         `json:"memberId"`
+	Version    int64          `json:"version"`
+	OccurredAt time.Time      `json:"occurredAt"`
+}
+
+// WorkspacePresence lists the members viewing a workspace live.
+type WorkspacePresence struct {
+	Type  string   `json:"type"`
+	Users []string `json:"users"`
+}
+
+func NewWorkspaceEvent(e *models.WorkspaceEvent) *WorkspaceEvent {
+	if e == nil {
+		return nil
+	}
+	result := &WorkspaceEvent{
+		Type:       e.Type,
+		UserID:     e.UserID,
+		UniqueName: e.UniqueName,
+		MemberID:   e.MemberID,
+		Version:    e.Version,
+		OccurredAt: e.OccurredAt,
+	}
+	if e.Item != nil {
+		item := workspaceItem(*e.Item)
+		result.Item = &item
+	}
+	return result
+}
+
+func NewWorkspacePresence(users []string) *WorkspacePresence {
+	if users == nil {
+		users = []string{}
+	}
+	return &WorkspacePresence{Type: PresenceEventType, Users: users}
+}
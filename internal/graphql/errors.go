@@ -0,0 +1,40 @@
+package graphql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Error is an entry of a response's errors: where in the query it arose
+// and, for field errors, the path of the field in the response.
+type Error struct {
+	Message   string     `json:"message"`
+	Locations []Location `json:"locations,omitempty"`
+	Path      []any      `json:"path,omitempty"`
+}
+
+func (e *Error) Error() string { return e.Message }
+
+// Location is a 1-based line and column in the query.
+type Location struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+func newError(source string, pos int, message string) *Error {
+	return &Error{Message: message, Locations: []Location{location(source, pos)}}
+}
+
+func location(source string, pos int) Location {
+	if pos > len(source) {
+		pos = len(source)
+	}
+	before := source[:pos]
+	line := strings.Count(before, "\n") + 1
+	column := pos - strings.LastIndexByte(before, '\n')
+	return Location{Line: line, Column: column}
+}
+
+func errorf(source string, pos int, format string, args ...any) *Error {
+	return newError(source, pos, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,536 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Request is a GraphQL request as clients post it.
+type Request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// Response is the result of a request. Data is nil when the request failed
+// before execution, or when a non-null root field could not be resolved.
+type Response struct {
+	Data   any
+	Errors []*Error
+	// executed is false when the request failed before execution; the
+	// response then has no data entry.
+	executed bool
+}
+
+func (r *Response) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	if r.executed {
+		data, err := json.Marshal(r.Data)
+		if err != nil {
+			return nil, err
+		}
+		b.WriteString(`"data":`)
+		b.Write(data)
+	}
+	if len(r.Errors) > 0 {
+		errs, err := json.Marshal(r.Errors)
+		if err != nil {
+			return nil, err
+		}
+		if r.executed {
+			b.WriteByte(',')
+		}
+		b.WriteString(`"errors":`)
+		b.Write(errs)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// Execute runs the query of req. Syntax and validation errors fail the
+// whole request; errors resolving a field null that field (and its parents
+// up to the nearest nullable one) and are listed with the field's path.
+// Fields are resolved one at a time, so resolvers may share per-request
+// state without locking.
+func (s *Schema) Execute(ctx context.Context, req Request) *Response {
+	doc, err := parse(req.Query)
+	if err != nil {
+		return &Response{Errors: []*Error{err}}
+	}
+	op, err := selectOperation(doc, req.OperationName)
+	if err != nil {
+		return &Response{Errors: []*Error{err}}
+	}
+	if op.kind != "query" {
+		return &Response{Errors: []*Error{errorf(req.Query, op.pos, "Schema is not configured to execute %s operation.", op.kind)}}
+	}
+	if errs := s.validate(req.Query, doc, op); len(errs) > 0 {
+		return &Response{Errors: errs}
+	}
+	variables, errs := s.coerceVariables(req.Query, op, req.Variables)
+	if len(errs) > 0 {
+		return &Response{Errors: errs}
+	}
+
+	e := &execution{ctx: ctx, source: req.Query, doc: doc, variables: variables}
+	resp := &Response{executed: true}
+	if data, ok := e.selectionSet(s.query, nil, op.selectionSet, nil); ok {
+		resp.Data = data
+	}
+	resp.Errors = e.errors
+	return resp
+}
+
+func selectOperation(doc *document, name string) (*operation, *Error) {
+	if name == "" {
+		switch len(doc.operations) {
+		case 0:
+			return nil, &Error{Message: "Must provide an operation."}
+		case 1:
+			return doc.operations[0], nil
+		}
+		return nil, &Error{Message: "Must provide operation name if query contains multiple operations."}
+	}
+	for _, op := range doc.operations {
+		if op.name == name {
+			return op, nil
+		}
+	}
+	return nil, &Error{Message: fmt.Sprintf("Unknown operation named %q.", name)}
+}
+
+// coerceVariables checks the request's variables against the operation's
+// definitions. They are kept as given and coerced with the arguments they
+// fill; variables neither given nor defaulted are left out, so those
+// arguments fall back to their own defaults.
+func (s *Schema) coerceVariables(source string, op *operation, given map[string]any) (map[string]any, []*Error) {
+	variables := make(map[string]any)
+	var errs []*Error
+	for _, def := range op.variables {
+		t := s.inputType(def.typ)
+		v, ok := given[def.name]
+		if !ok {
+			if def.defaultValue != nil {
+				if _, err := coerceInput(t, def.defaultValue); err != nil {
+					errs = append(errs, errorf(source, def.pos, "Variable \"$%s\" has invalid default value: %s", def.name, err))
+					continue
+				}
+				variables[def.name] = def.defaultValue
+			} else if def.typ.nonNull {
+				errs = append(errs, errorf(source, def.pos, "Variable \"$%s\" of required type \"%s\" was not provided.", def.name, def.typ))
+			}
+			continue
+		}
+		if _, err := coerceInput(t, v); err != nil {
+			errs = append(errs, errorf(source, def.pos, "Variable \"$%s\" got invalid value: %s", def.name, err))
+			continue
+		}
+		variables[def.name] = v
+	}
+	return variables, errs
+}
+
+// inputType resolves a variable's declared type; validation has checked
+// that its named type is a scalar of the schema.
+func (s *Schema) inputType(ref *typeRef) Type {
+	var t Type
+	if ref.elem != nil {
+		t = NewList(s.inputType(ref.elem))
+	} else {
+		t = s.types[ref.name]
+	}
+	if ref.nonNull {
+		t = NewNonNull(t)
+	}
+	return t
+}
+
+// coerceInput converts a literal or JSON value to the Go value of type t.
+// Variables in literals must already be substituted.
+func coerceInput(t Type, v any) (any, error) {
+	if nn, ok := t.(*NonNull); ok {
+		if v == nil {
+			return nil, fmt.Errorf("expected non-nullable type %s not to be null", t)
+		}
+		return coerceInput(nn.Of, v)
+	}
+	if v == nil {
+		return nil, nil
+	}
+	switch t := t.(type) {
+	case *List:
+		items, ok := v.([]any)
+		if !ok {
+			// A single value is a list of one.
+			item, err := coerceInput(t.Of, v)
+			if err != nil {
+				return nil, err
+			}
+			return []any{item}, nil
+		}
+		out := make([]any, len(items))
+		for i, item := range items {
+			coerced, err := coerceInput(t.Of, item)
+			if err != nil {
+				return nil, fmt.Errorf("at index %d: %w", i, err)
+			}
+			out[i] = coerced
+		}
+		return out, nil
+	case *Scalar:
+		if _, ok := v.(enumValue); ok {
+			return nil, fmt.Errorf("%s cannot represent an enum value: %s", t.Name, describe(v))
+		}
+		if _, ok := v.(objectValue); ok {
+			return nil, fmt.Errorf("%s cannot represent an object value", t.Name)
+		}
+		return t.Parse(v)
+	}
+	return nil, fmt.Errorf("cannot coerce to %s", t)
+}
+
+// substitute replaces the variables in a literal with their values. An
+// absent variable is reported as not found.
+func substitute(v value, variables map[string]any) (any, bool) {
+	switch v := v.(type) {
+	case variable:
+		value, ok := variables[string(v)]
+		return value, ok
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			// An absent variable in a list is null.
+			out[i], _ = substitute(item, variables)
+		}
+		return out, true
+	}
+	return v, true
+}
+
+type execution struct {
+	ctx       context.Context
+	source    string
+	doc       *document
+	variables map[string]any
+	errors    []*Error
+}
+
+func (e *execution) addError(pos int, path []any, message string) {
+	err := newError(e.source, pos, message)
+	err.Path = slices.Clone(path)
+	e.errors = append(e.errors, err)
+}
+
+// selectionSet resolves the selected fields of source, an object of type t.
+// It reports false when a non-null field is null, making the object null.
+func (e *execution) selectionSet(t *Object, source any, selections []selection, path []any) (*orderedMap, bool) {
+	var keys []string
+	grouped := make(map[string][]*field)
+	e.collectFields(t, selections, &keys, grouped, make(map[string]bool))
+
+	result := &orderedMap{values: make(map[string]any, len(keys))}
+	for _, key := range keys {
+		fields := grouped[key]
+		fieldPath := append(slices.Clip(path), key)
+		if fields[0].name == "__typename" {
+			result.set(key, t.Name)
+			continue
+		}
+		v, ok := e.field(t.field(fields[0].name), source, fields, fieldPath)
+		if !ok {
+			return nil, false
+		}
+		result.set(key, v)
+	}
+	return result, true
+}
+
+// collectFields groups the fields selected on t by response key, in the
+// order they first appear, following fragments and skipping fields
+// excluded by @skip or @include.
+func (e *execution) collectFields(t *Object, selections []selection, keys *[]string, grouped map[string][]*field, visited map[string]bool) {
+	for _, sel := range selections {
+		switch sel := sel.(type) {
+		case *field:
+			if !e.included(sel.directives) {
+				continue
+			}
+			key := sel.responseKey()
+			if _, ok := grouped[key]; !ok {
+				*keys = append(*keys, key)
+			}
+			grouped[key] = append(grouped[key], sel)
+		case *fragmentSpread:
+			if !e.included(sel.directives) || visited[sel.name] {
+				continue
+			}
+			visited[sel.name] = true
+			if frag := e.doc.fragments[sel.name]; frag != nil && frag.typeCondition == t.Name {
+				e.collectFields(t, frag.selectionSet, keys, grouped, visited)
+			}
+		case *inlineFragment:
+			if !e.included(sel.directives) || sel.typeCondition != "" && sel.typeCondition != t.Name {
+				continue
+			}
+			e.collectFields(t, sel.selectionSet, keys, grouped, visited)
+		}
+	}
+}
+
+func (e *execution) included(directives []*directive) bool {
+	for _, d := range directives {
+		condition := false
+		for _, arg := range d.arguments {
+			if arg.name == "if" {
+				v, _ := substitute(arg.value, e.variables)
+				condition, _ = v.(bool)
+			}
+		}
+		if d.name == "skip" && condition || d.name == "include" && !condition {
+			return false
+		}
+	}
+	return true
+}
+
+func (e *execution) field(def *Field, source any, fields []*field, path []any) (any, bool) {
+	f := fields[0]
+	args, err := e.argumentValues(def, f)
+	if err != nil {
+		e.addError(f.pos, path, err.Error())
+		return nil, !isNonNull(def.Type)
+	}
+
+	var resolved any
+	if def.Resolve != nil {
+		resolved, err = e.resolve(def, source, args)
+		if err != nil {
+			e.addError(f.pos, path, err.Error())
+			return nil, !isNonNull(def.Type)
+		}
+	} else {
+		resolved = defaultResolve(source, def.Name)
+	}
+	return e.complete(def.Type, fields, resolved, path)
+}
+
+// resolve calls def's resolver, turning a panic into a field error so one
+// bad field cannot fail the whole request.
+func (e *execution) resolve(def *Field, source any, args map[string]any) (v any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("internal error resolving %s", def.Name)
+		}
+	}()
+	return def.Resolve(ResolveParams{Context: e.ctx, Source: source, Args: args})
+}
+
+func (e *execution) argumentValues(def *Field, f *field) (map[string]any, error) {
+	args := make(map[string]any, len(def.Args))
+	for _, argDef := range def.Args {
+		var raw any
+		present := false
+		for _, arg := range f.arguments {
+			if arg.name == argDef.Name {
+				raw, present = substitute(arg.value, e.variables)
+			}
+		}
+		if !present {
+			if argDef.Default != nil {
+				args[argDef.Name] = argDef.Default
+			} else if isNonNull(argDef.Type) {
+				return nil, fmt.Errorf("Argument %q of required type %q was not provided.", argDef.Name, argDef.Type)
+			}
+			continue
+		}
+		coerced, err := coerceInput(argDef.Type, raw)
+		if err != nil {
+			return nil, fmt.Errorf("Argument %q has invalid value: %s", argDef.Name, err)
+		}
+		args[argDef.Name] = coerced
+	}
+	return args, nil
+}
+
+// complete converts a resolved value to its response form. It reports
+// false when a non-null value is null, for the caller to propagate.
+func (e *execution) complete(t Type, fields []*field, v any, path []any) (any, bool) {
+	if nn, ok := t.(*NonNull); ok {
+		c, ok := e.completeNullable(nn.Of, fields, v, path)
+		if !ok {
+			return nil, false
+		}
+		if c == nil {
+			e.addError(fields[0].pos, path, fmt.Sprintf("Cannot return null for non-nullable field %s.", fields[0].name))
+			return nil, false
+		}
+		return c, true
+	}
+	c, ok := e.completeNullable(t, fields, v, path)
+	if !ok {
+		return nil, true
+	}
+	return c, true
+}
+
+func (e *execution) completeNullable(t Type, fields []*field, v any, path []any) (any, bool) {
+	if isNil(v) {
+		return nil, true
+	}
+	switch t := t.(type) {
+	case *List:
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			e.addError(fields[0].pos, path, fmt.Sprintf("Expected a list for field %s.", fields[0].name))
+			return nil, false
+		}
+		items := make([]any, rv.Len())
+		for i := range items {
+			c, ok := e.complete(t.Of, fields, rv.Index(i).Interface(), append(slices.Clip(path), i))
+			if !ok {
+				return nil, false
+			}
+			items[i] = c
+		}
+		return items, true
+	case *Scalar:
+		for rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer; rv = rv.Elem() {
+			v = rv.Elem().Interface()
+		}
+		c, err := t.Serialize(v)
+		if err != nil {
+			e.addError(fields[0].pos, path, err.Error())
+			return nil, false
+		}
+		return c, true
+	case *Object:
+		var selections []selection
+		for _, f := range fields {
+			selections = append(selections, f.selectionSet...)
+		}
+		m, ok := e.selectionSet(t, v, selections, path)
+		if !ok {
+			return nil, false
+		}
+		return m, true
+	}
+	return nil, false
+}
+
+func isNonNull(t Type) bool {
+	_, ok := t.(*NonNull)
+	return ok
+}
+
+// isNil reports whether v is null in the response. Nil slices are empty
+// lists, as Go code uses them.
+func isNil(v any) bool {
+	if v == nil {
+		return true
+	}
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Interface, reflect.Func:
+		return rv.IsNil()
+	}
+	return false
+}
+
+// defaultResolve reads field name of source: the struct field whose json
+// name it is, or the map entry.
+func defaultResolve(source any, name string) any {
+	rv := reflect.ValueOf(source)
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil
+		}
+		if v := rv.MapIndex(reflect.ValueOf(name).Convert(rv.Type().Key())); v.IsValid() {
+			return v.Interface()
+		}
+	case reflect.Struct:
+		if index, ok := jsonFields(rv.Type())[name]; ok {
+			if v, err := rv.FieldByIndexErr(index); err == nil {
+				return v.Interface()
+			}
+		}
+	}
+	return nil
+}
+
+var jsonFieldCache sync.Map // reflect.Type -> map[string][]int
+
+// jsonFields maps the json names of t's fields, embedded ones included, to
+// their indexes.
+func jsonFields(t reflect.Type) map[string][]int {
+	if cached, ok := jsonFieldCache.Load(t); ok {
+		return cached.(map[string][]int)
+	}
+	fields := make(map[string][]int)
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() || f.Anonymous && f.Tag.Get("json") == "" {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if _, ok := fields[name]; !ok {
+			fields[name] = f.Index
+		}
+	}
+	jsonFieldCache.Store(t, fields)
+	return fields
+}
+
+// orderedMap is a response object, which keeps its fields in the order the
+// query selected them.
+type orderedMap struct {
+	keys   []string
+	values map[string]any
+}
+
+func (m *orderedMap) set(key string, v any) {
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = v
+}
+
+func (m *orderedMap) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, err
+		}
+		b.Write(k)
+		b.WriteByte(':')
+		b.Write(v)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
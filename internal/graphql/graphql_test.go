@@ -0,0 +1,355 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testCharacter struct {
+	ID      string     `json:"id"`
+	Name    string     `json:"name"`
+	Nick    *string    `json:"nick,omitempty"`
+	Born    time.Time  `json:"born"`
+	Friends []string   `json:"-"`
+	Died    *time.Time `json:"died"`
+}
+
+func testSchema(t *testing.T) *Schema {
+	t.Helper()
+	nick := "Rhi"
+	characters := map[string]*testCharacter{
+		"1": {ID: "1", Name: "Rhino", Nick: &nick, Born: time.Date(2013, 3, 25, 0, 0, 0, 0, time.UTC), Friends: []string{"2", "3"}},
+		"2": {ID: "2", Name: "Volt", Friends: []string{"1"}},
+		"3": {ID: "3", Name: "Excalibur"},
+	}
+	lookup := func(id any) any {
+		if c, ok := characters[id.(string)]; ok {
+			return c
+		}
+		return nil
+	}
+
+	character := &Object{Name: "Character", Description: "A playable frame."}
+	character.Fields = []*Field{
+		{Name: "id", Type: NewNonNull(ID)},
+		{Name: "name", Type: NewNonNull(String)},
+		{Name: "nick", Type: String},
+		{Name: "born", Type: NewNonNull(DateTime)},
+		{Name: "died", Type: DateTime},
+		{Name: "friends", Type: NewNonNull(NewList(NewNonNull(character))), Resolve: func(p ResolveParams) (any, error) {
+			var friends []any
+			for _, id := range p.Source.(*testCharacter).Friends {
+				friends = append(friends, lookup(id))
+			}
+			return friends, nil
+		}},
+		{Name: "broken", Type: NewNonNull(String), Resolve: func(p ResolveParams) (any, error) {
+			return nil, errors.New("failed to get broken")
+		}},
+		{Name: "missing", Type: NewNonNull(String), Resolve: func(p ResolveParams) (any, error) {
+			return nil, nil
+		}},
+	}
+	query := &Object{Name: "Query", Fields: []*Field{
+		{
+			Name: "character",
+			Type: character,
+			Args: []*Argument{{Name: "id", Type: NewNonNull(ID)}},
+			Resolve: func(p ResolveParams) (any, error) {
+				return lookup(p.Args["id"]), nil
+			},
+		},
+		{
+			Name: "characters",
+			Type: NewNonNull(NewList(NewNonNull(character))),
+			Args: []*Argument{{Name: "ids", Type: NewList(NewNonNull(ID))}, {Name: "limit", Type: Int, Default: 2}},
+			Resolve: func(p ResolveParams) (any, error) {
+				var out []any
+				ids, _ := p.Args["ids"].([]any)
+				if ids == nil {
+					ids = []any{"1", "2", "3"}
+				}
+				for _, id := range ids {
+					if len(out) < p.Args["limit"].(int) {
+						out = append(out, lookup(id))
+					}
+				}
+				return out, nil
+			},
+		},
+		{Name: "greeting", Type: String, Args: []*Argument{{Name: "name", Type: String}}, Resolve: func(p ResolveParams) (any, error) {
+			name, ok := p.Args["name"].(string)
+			if !ok {
+				return "hello", nil
+			}
+			return "hello " + name, nil
+		}},
+		{Name: "panics", Type: String, Resolve: func(p ResolveParams) (any, error) {
+			panic("boom")
+		}},
+	}}
+
+	schema, err := NewSchema(query)
+	if err != nil {
+		t.Fatalf("NewSchema: %v", err)
+	}
+	return schema
+}
+
+func execute(t *testing.T, schema *Schema, query string, variables map[string]any) string {
+	t.Helper()
+	out, err := json.Marshal(schema.Execute(context.Background(), Request{Query: query, Variables: variables}))
+	if err != nil {
+		t.Fatalf("failed to encode response: %v", err)
+	}
+	return string(out)
+}
+
+func TestExecute(t *testing.T) {
+	schema := testSchema(t)
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]any
+		want      string
+	}{
+		{
+			name:  "fields in query order",
+			query: `{ character(id: "1") { name id nick born died } }`,
+			want:  `{"data":{"character":{"name":"Rhino","id":"1","nick":"Rhi","born":"2013-03-25T00:00:00Z","died":null}}}`,
+		},
+		{
+			name:  "aliases and typename",
+			query: `query { a: character(id: 1) { __typename name } b: character(id: "2") { who: name } }`,
+			want:  `{"data":{"a":{"__typename":"Character","name":"Rhino"},"b":{"who":"Volt"}}}`,
+		},
+		{
+			name:  "nested lists",
+			query: `{ character(id: "1") { friends { name friends { id } } } }`,
+			want:  `{"data":{"character":{"friends":[{"name":"Volt","friends":[{"id":"1"}]},{"name":"Excalibur","friends":[]}]}}}`,
+		},
+		{
+			name:  "missing object is null",
+			query: `{ character(id: "9") { name } }`,
+			want:  `{"data":{"character":null}}`,
+		},
+		{
+			name:  "argument default",
+			query: `{ characters { id } }`,
+			want:  `{"data":{"characters":[{"id":"1"},{"id":"2"}]}}`,
+		},
+		{
+			name:  "list argument from a single value",
+			query: `{ characters(ids: "3", limit: 5) { name } }`,
+			want:  `{"data":{"characters":[{"name":"Excalibur"}]}}`,
+		},
+		{
+			name:      "variables",
+			query:     `query Get($id: ID!, $limit: Int = 1, $ids: [ID!]) { character(id: $id) { name } characters(limit: $limit, ids: $ids) { id } }`,
+			variables: map[string]any{"id": "2", "ids": []any{"3", "1"}},
+			want:      `{"data":{"character":{"name":"Volt"},"characters":[{"id":"3"}]}}`,
+		},
+		{
+			name:  "absent variable falls back to argument default",
+			query: `query ($limit: Int) { characters(limit: $limit) { id } }`,
+			want:  `{"data":{"characters":[{"id":"1"},{"id":"2"}]}}`,
+		},
+		{
+			name:  "fragments",
+			query: `{ character(id: "1") { ...Names ... on Character { id } ... { nick } } } fragment Names on Character { name friends { ...Friend } } fragment Friend on Character { name }`,
+			want:  `{"data":{"character":{"name":"Rhino","friends":[{"name":"Volt"},{"name":"Excalibur"}],"id":"1","nick":"Rhi"}}}`,
+		},
+		{
+			name:      "skip and include",
+			query:     `query ($yes: Boolean!) { character(id: "1") { name @skip(if: $yes) id @include(if: $yes) nick @include(if: false) } }`,
+			variables: map[string]any{"yes": true},
+			want:      `{"data":{"character":{"id":"1"}}}`,
+		},
+		{
+			name:  "same field selected twice is merged",
+			query: `{ character(id: "1") { friends { name } friends { id } } }`,
+			want:  `{"data":{"character":{"friends":[{"name":"Volt","id":"2"},{"name":"Excalibur","id":"3"}]}}}`,
+		},
+		{
+			name:  "block string argument",
+			query: "{ greeting(name: \"\"\"\n    Lotus\n  \"\"\") }",
+			want:  `{"data":{"greeting":"hello Lotus"}}`,
+		},
+		{
+			name:  "escaped string argument",
+			query: `{ greeting(name: "Tenno \"Baro\"") }`,
+			want:  `{"data":{"greeting":"hello Tenno \"Baro\""}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := execute(t, schema, tt.query, tt.variables)
+			if got != tt.want {
+				t.Errorf("got  %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecute_FieldErrors(t *testing.T) {
+	schema := testSchema(t)
+
+	t.Run("resolver error nulls the nearest nullable parent", func(t *testing.T) {
+		got := execute(t, schema, `{ greeting character(id: "1") { name broken } }`, nil)
+		want := `{"data":{"greeting":"hello","character":null},"errors":[{"message":"failed to get broken","locations":[{"line":1,"column":38}],"path":["character","broken"]}]}`
+		if got != want {
+			t.Errorf("got  %s\nwant %s", got, want)
+		}
+	})
+
+	t.Run("null for a non-null field", func(t *testing.T) {
+		got := execute(t, schema, `{ characters(limit: 1) { missing } }`, nil)
+		want := `{"data":null,"errors":[{"message":"Cannot return null for non-nullable field missing.","locations":[{"line":1,"column":26}],"path":["characters",0,"missing"]}]}`
+		if got != want {
+			t.Errorf("got  %s\nwant %s", got, want)
+		}
+	})
+
+	t.Run("panicking resolver", func(t *testing.T) {
+		got := execute(t, schema, `{ panics greeting }`, nil)
+		want := `{"data":{"panics":null,"greeting":"hello"},"errors":[{"message":"internal error resolving panics","locations":[{"line":1,"column":3}],"path":["panics"]}]}`
+		if got != want {
+			t.Errorf("got  %s\nwant %s", got, want)
+		}
+	})
+}
+
+func TestExecute_RequestErrors(t *testing.T) {
+	schema := testSchema(t)
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]any
+		want      string
+	}{
+		{"syntax", `{ character(id: "1") { name }`, nil, `Syntax Error: Expected Name, found <EOF>.`},
+		{"unterminated string", `{ greeting(name: "x) }`, nil, `Syntax Error: Unterminated string.`},
+		{"empty", ``, nil, `Syntax Error: Unexpected <EOF>.`},
+		{"unknown field", `{ character(id: "1") { power } }`, nil, `Cannot query field "power" on type "Character".`},
+		{"unknown argument", `{ greeting(nam: "x") }`, nil, `Unknown argument "nam" on field "Query.greeting".`},
+		{"missing argument", `{ character { name } }`, nil, `Field "character" argument "id" of type "ID!" is required, but it was not provided.`},
+		{"bad literal", `{ characters(limit: "two") { id } }`, nil, `Argument "limit" has invalid value: Int cannot represent non 32-bit signed integer value: "two"`},
+		{"missing subfields", `{ character(id: "1") }`, nil, `Field "character" of type "Character" must have a selection of subfields.`},
+		{"subfields on a leaf", `{ greeting { length } }`, nil, `Field "greeting" must not have a selection since type "String" has no subfields.`},
+		{"unknown fragment", `{ ...Nope }`, nil, `Unknown fragment "Nope".`},
+		{"fragment on wrong type", `{ ...C } fragment C on Character { name }`, nil, `Fragment cannot be spread here as objects of type "Query" can never be of type "Character".`},
+		{"fragment cycle", `{ character(id: "1") { ...A } } fragment A on Character { ...B } fragment B on Character { ...A }`, nil, `Cannot spread fragment "A" within itself.`},
+		{"conflicting aliases", `{ character(id: "1") { x: name x: id } }`, nil, `Fields "x" conflict because "name" and "id" are different fields.`},
+		{"undefined variable", `{ character(id: $id) { name } }`, nil, `Variable "$id" is not defined.`},
+		{"unused variable", `query ($id: ID) { greeting }`, nil, `Variable "$id" is never used.`},
+		{"nullable variable in non-null position", `query ($id: ID) { character(id: $id) { name } }`, nil, `Variable "$id" of type "ID" used in position expecting type "ID!".`},
+		{"missing variable", `query ($id: ID!) { character(id: $id) { name } }`, nil, `Variable "$id" of required type "ID!" was not provided.`},
+		{"bad variable", `query ($n: Int) { characters(limit: $n) { id } }`, map[string]any{"n": 1.5}, `Variable "$n" got invalid value: Int cannot represent non 32-bit signed integer value: 1.5`},
+		{"unknown directive", `{ greeting @deprecated }`, nil, `Unknown directive "@deprecated".`},
+		{"mutation", `mutation { greeting }`, nil, `Schema is not configured to execute mutation operation.`},
+		{"several anonymous", `query A { greeting } query B { greeting }`, nil, `Must provide operation name if query contains multiple operations.`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := schema.Execute(context.Background(), Request{Query: tt.query, Variables: tt.variables})
+			if len(resp.Errors) == 0 || resp.Errors[0].Message != tt.want {
+				t.Fatalf("expected error %q, got %+v", tt.want, resp.Errors)
+			}
+			out, _ := json.Marshal(resp)
+			if strings.Contains(string(out), `"data"`) {
+				t.Errorf("expected no data entry for a request error, got %s", out)
+			}
+		})
+	}
+}
+
+func TestExecute_ErrorLocation(t *testing.T) {
+	resp := testSchema(t).Execute(context.Background(), Request{Query: "{\n  character(id: \"1\") {\n    power\n  }\n}"})
+	if len(resp.Errors) != 1 || len(resp.Errors[0].Locations) != 1 {
+		t.Fatalf("expected one located error, got %+v", resp.Errors)
+	}
+	if loc := resp.Errors[0].Locations[0]; loc.Line != 3 || loc.Column != 5 {
+		t.Errorf("expected line 3 column 5, got %+v", loc)
+	}
+}
+
+func TestExecute_MaxDepth(t *testing.T) {
+	schema := testSchema(t)
+	schema.SetMaxDepth(4)
+
+	if got := execute(t, schema, `{ character(id: "1") { friends { friends { id } } } }`, nil); strings.Contains(got, "errors") {
+		t.Errorf("expected 4 levels to be allowed, got %s", got)
+	}
+	resp := schema.Execute(context.Background(), Request{Query: `{ character(id: "1") { friends { friends { friends { id } } } } }`})
+	if len(resp.Errors) != 1 || !strings.Contains(resp.Errors[0].Message, "nested too deeply") {
+		t.Errorf("expected a depth error, got %+v", resp.Errors)
+	}
+	// Fragments count towards depth.
+	resp = schema.Execute(context.Background(), Request{Query: `{ character(id: "1") { ...F } } fragment F on Character { friends { friends { friends { id } } } }`})
+	if len(resp.Errors) != 1 || !strings.Contains(resp.Errors[0].Message, "nested too deeply") {
+		t.Errorf("expected a depth error through a fragment, got %+v", resp.Errors)
+	}
+}
+
+func TestExecute_OperationName(t *testing.T) {
+	schema := testSchema(t)
+	query := `query A { greeting } query B { greeting(name: "B") }`
+
+	resp := schema.Execute(context.Background(), Request{Query: query, OperationName: "B"})
+	out, _ := json.Marshal(resp)
+	if string(out) != `{"data":{"greeting":"hello B"}}` {
+		t.Errorf("unexpected response %s", out)
+	}
+	resp = schema.Execute(context.Background(), Request{Query: query, OperationName: "C"})
+	if len(resp.Errors) != 1 || resp.Errors[0].Message != `Unknown operation named "C".` {
+		t.Errorf("unexpected errors %+v", resp.Errors)
+	}
+}
+
+func TestNewSchema_Invalid(t *testing.T) {
+	a := &Object{Name: "Thing", Fields: []*Field{{Name: "id", Type: ID}}}
+	b := &Object{Name: "Thing", Fields: []*Field{{Name: "name", Type: String}}}
+	tests := []struct {
+		name  string
+		query *Object
+	}{
+		{"nil query", nil},
+		{"no fields", &Object{Name: "Query"}},
+		{"duplicate type names", &Object{Name: "Query", Fields: []*Field{{Name: "a", Type: a}, {Name: "b", Type: b}}}},
+		{"duplicate fields", &Object{Name: "Query", Fields: []*Field{{Name: "a", Type: String}, {Name: "a", Type: Int}}}},
+		{"field without type", &Object{Name: "Query", Fields: []*Field{{Name: "a"}}}},
+		{"invalid field name", &Object{Name: "Query", Fields: []*Field{{Name: "a-b", Type: String}}}},
+		{"object argument", &Object{Name: "Query", Fields: []*Field{{Name: "a", Type: String, Args: []*Argument{{Name: "x", Type: a}}}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewSchema(tt.query); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestSchema_String(t *testing.T) {
+	got := testSchema(t).String()
+	for _, want := range []string{
+		"type Query {\n  character(id: ID!): Character\n  characters(ids: [ID!], limit: Int = 2): [Character!]!\n",
+		"\"A playable frame.\"\ntype Character {\n  id: ID!\n",
+		"  friends: [Character!]!\n",
+		"\"An RFC 3339 timestamp.\"\nscalar DateTime\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected SDL to contain %q, got:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "scalar String") {
+		t.Error("expected built-in scalars to be left out")
+	}
+	if !strings.HasPrefix(got, "type Query {") {
+		t.Errorf("expected the query type first, got:\n%s", got)
+	}
+}
@@ -0,0 +1,612 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// The parsed form of a query document. Only executable definitions are
+// supported; a document defining types is rejected.
+
+type document struct {
+	operations []*operation
+	fragments  map[string]*fragment
+}
+
+type operation struct {
+	kind         string // "query", "mutation" or "subscription"
+	name         string
+	variables    []*variableDefinition
+	directives   []*directive
+	selectionSet []selection
+	pos          int
+}
+
+type variableDefinition struct {
+	name         string
+	typ          *typeRef
+	defaultValue value
+	pos          int
+}
+
+// typeRef is a type as written in a variable definition, e.g. [String!]!.
+type typeRef struct {
+	name    string
+	elem    *typeRef
+	nonNull bool
+}
+
+func (t *typeRef) String() string {
+	s := t.name
+	if t.elem != nil {
+		s = "[" + t.elem.String() + "]"
+	}
+	if t.nonNull {
+		s += "!"
+	}
+	return s
+}
+
+type selection interface {
+	position() int
+}
+
+type field struct {
+	alias        string
+	name         string
+	arguments    []*argument
+	directives   []*directive
+	selectionSet []selection
+	pos          int
+}
+
+// responseKey is the name the field's value is returned under.
+func (f *field) responseKey() string {
+	if f.alias != "" {
+		return f.alias
+	}
+	return f.name
+}
+
+type fragmentSpread struct {
+	name       string
+	directives []*directive
+	pos        int
+}
+
+type inlineFragment struct {
+	typeCondition string
+	directives    []*directive
+	selectionSet  []selection
+	pos           int
+}
+
+func (f *field) position() int          { return f.pos }
+func (f *fragmentSpread) position() int { return f.pos }
+func (f *inlineFragment) position() int { return f.pos }
+
+type fragment struct {
+	name          string
+	typeCondition string
+	directives    []*directive
+	selectionSet  []selection
+	pos           int
+}
+
+type directive struct {
+	name      string
+	arguments []*argument
+	pos       int
+}
+
+type argument struct {
+	name  string
+	value value
+	pos   int
+}
+
+// value is an input value literal: variable, int64, float64, string, bool,
+// nil, enumValue, []value or objectValue.
+type value any
+
+type variable string
+
+type enumValue string
+
+type objectValue []*argument
+
+// syntaxError is raised by the parser and recovered by parse.
+type syntaxError struct {
+	message string
+	pos     int
+}
+
+func (e *syntaxError) Error() string { return e.message }
+
+func parse(source string) (doc *document, err *Error) {
+	defer func() {
+		if r := recover(); r != nil {
+			e, ok := r.(*syntaxError)
+			if !ok {
+				panic(r)
+			}
+			err = newError(source, e.pos, "Syntax Error: "+e.message)
+		}
+	}()
+
+	p := &parser{lex: lexer{src: source}}
+	p.advance()
+	doc = &document{fragments: make(map[string]*fragment)}
+	if p.tok.kind == tokenEOF {
+		p.fail(p.tok.pos, "Unexpected <EOF>.")
+	}
+	for p.tok.kind != tokenEOF {
+		switch {
+		case p.peek("{"):
+			doc.operations = append(doc.operations, &operation{kind: "query", pos: p.tok.pos, selectionSet: p.selectionSet()})
+		case p.peekName("query"), p.peekName("mutation"), p.peekName("subscription"):
+			doc.operations = append(doc.operations, p.operation())
+		case p.peekName("fragment"):
+			f := p.fragment()
+			if _, ok := doc.fragments[f.name]; ok {
+				p.fail(f.pos, fmt.Sprintf("There can be only one fragment named %q.", f.name))
+			}
+			doc.fragments[f.name] = f
+		default:
+			p.fail(p.tok.pos, "Unexpected "+p.tok.describe()+".")
+		}
+	}
+	return doc, nil
+}
+
+type parser struct {
+	lex lexer
+	tok token
+}
+
+func (p *parser) fail(pos int, message string) {
+	panic(&syntaxError{message: message, pos: pos})
+}
+
+func (p *parser) advance() {
+	tok, err := p.lex.next()
+	if err != nil {
+		panic(err)
+	}
+	p.tok = tok
+}
+
+func (p *parser) peek(punctuator string) bool {
+	return p.tok.kind == tokenPunctuator && p.tok.value == punctuator
+}
+
+func (p *parser) peekName(name string) bool {
+	return p.tok.kind == tokenName && p.tok.value == name
+}
+
+// skip consumes punctuator if it is next and reports whether it was.
+func (p *parser) skip(punctuator string) bool {
+	if p.peek(punctuator) {
+		p.advance()
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(punctuator string) {
+	if !p.skip(punctuator) {
+		p.fail(p.tok.pos, fmt.Sprintf("Expected %q, found %s.", punctuator, p.tok.describe()))
+	}
+}
+
+func (p *parser) name() string {
+	if p.tok.kind != tokenName {
+		p.fail(p.tok.pos, "Expected Name, found "+p.tok.describe()+".")
+	}
+	name := p.tok.value
+	p.advance()
+	return name
+}
+
+func (p *parser) operation() *operation {
+	op := &operation{pos: p.tok.pos, kind: p.name()}
+	if p.tok.kind == tokenName {
+		op.name = p.name()
+	}
+	if p.skip("(") {
+		for !p.skip(")") {
+			def := &variableDefinition{pos: p.tok.pos}
+			p.expect("$")
+			def.name = p.name()
+			p.expect(":")
+			def.typ = p.typeRef()
+			if p.skip("=") {
+				def.defaultValue = p.value(true)
+			}
+			op.variables = append(op.variables, def)
+		}
+	}
+	op.directives = p.directives()
+	op.selectionSet = p.selectionSet()
+	return op
+}
+
+func (p *parser) fragment() *fragment {
+	f := &fragment{pos: p.tok.pos}
+	p.advance()
+	f.name = p.name()
+	if f.name == "on" {
+		p.fail(f.pos, `Unexpected Name "on".`)
+	}
+	if !p.peekName("on") {
+		p.fail(p.tok.pos, `Expected "on", found `+p.tok.describe()+".")
+	}
+	p.advance()
+	f.typeCondition = p.name()
+	f.directives = p.directives()
+	f.selectionSet = p.selectionSet()
+	return f
+}
+
+func (p *parser) typeRef() *typeRef {
+	t := &typeRef{}
+	if p.skip("[") {
+		t.elem = p.typeRef()
+		p.expect("]")
+	} else {
+		t.name = p.name()
+	}
+	t.nonNull = p.skip("!")
+	return t
+}
+
+func (p *parser) selectionSet() []selection {
+	p.expect("{")
+	var selections []selection
+	for !p.skip("}") {
+		selections = append(selections, p.selection())
+	}
+	if len(selections) == 0 {
+		p.fail(p.tok.pos, "Expected Name, found \"}\".")
+	}
+	return selections
+}
+
+func (p *parser) selection() selection {
+	pos := p.tok.pos
+	if !p.skip("...") {
+		f := &field{pos: pos, name: p.name()}
+		if p.skip(":") {
+			f.alias, f.name = f.name, p.name()
+		}
+		f.arguments = p.arguments(false)
+		f.directives = p.directives()
+		if p.peek("{") {
+			f.selectionSet = p.selectionSet()
+		}
+		return f
+	}
+
+	if p.tok.kind == tokenName && p.tok.value != "on" {
+		return &fragmentSpread{pos: pos, name: p.name(), directives: p.directives()}
+	}
+	inline := &inlineFragment{pos: pos}
+	if p.peekName("on") {
+		p.advance()
+		inline.typeCondition = p.name()
+	}
+	inline.directives = p.directives()
+	inline.selectionSet = p.selectionSet()
+	return inline
+}
+
+func (p *parser) arguments(constant bool) []*argument {
+	if !p.skip("(") {
+		return nil
+	}
+	var args []*argument
+	for !p.skip(")") {
+		arg := &argument{pos: p.tok.pos, name: p.name()}
+		p.expect(":")
+		arg.value = p.value(constant)
+		args = append(args, arg)
+	}
+	return args
+}
+
+func (p *parser) directives() []*directive {
+	var directives []*directive
+	for p.peek("@") {
+		d := &directive{pos: p.tok.pos}
+		p.advance()
+		d.name = p.name()
+		d.arguments = p.arguments(false)
+		directives = append(directives, d)
+	}
+	return directives
+}
+
+// value parses an input value; variables are not allowed in constant ones
+// such as variable defaults.
+func (p *parser) value(constant bool) value {
+	tok := p.tok
+	switch tok.kind {
+	case tokenInt:
+		p.advance()
+		n, err := strconv.ParseInt(tok.value, 10, 64)
+		if err != nil {
+			p.fail(tok.pos, "Int cannot represent "+tok.value+".")
+		}
+		return n
+	case tokenFloat:
+		p.advance()
+		f, err := strconv.ParseFloat(tok.value, 64)
+		if err != nil {
+			p.fail(tok.pos, "Float cannot represent "+tok.value+".")
+		}
+		return f
+	case tokenString:
+		p.advance()
+		return tok.value
+	case tokenName:
+		p.advance()
+		switch tok.value {
+		case "true":
+			return true
+		case "false":
+			return false
+		case "null":
+			return nil
+		}
+		return enumValue(tok.value)
+	}
+
+	switch {
+	case p.peek("$") && !constant:
+		p.advance()
+		return variable(p.name())
+	case p.skip("["):
+		list := []value{}
+		for !p.skip("]") {
+			list = append(list, p.value(constant))
+		}
+		return list
+	case p.skip("{"):
+		object := objectValue{}
+		for !p.skip("}") {
+			field := &argument{pos: p.tok.pos, name: p.name()}
+			p.expect(":")
+			field.value = p.value(constant)
+			object = append(object, field)
+		}
+		return object
+	}
+	p.fail(tok.pos, "Unexpected "+tok.describe()+".")
+	return nil
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenPunctuator
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+func (t token) describe() string {
+	switch t.kind {
+	case tokenEOF:
+		return "<EOF>"
+	case tokenName:
+		return fmt.Sprintf("Name %q", t.value)
+	case tokenInt, tokenFloat:
+		return "number " + t.value
+	case tokenString:
+		return "String"
+	}
+	return strconv.Quote(t.value)
+}
+
+type lexer struct {
+	src string
+	pos int
+}
+
+func (l *lexer) next() (token, error) {
+	l.skipIgnored()
+	if l.pos >= len(l.src) {
+		return token{kind: tokenEOF, pos: l.pos}, nil
+	}
+
+	start := l.pos
+	c := l.src[l.pos]
+	switch {
+	case strings.IndexByte("!$&()[]{}:=@|", c) >= 0:
+		l.pos++
+		return token{kind: tokenPunctuator, value: string(c), pos: start}, nil
+	case c == '.':
+		if strings.HasPrefix(l.src[l.pos:], "...") {
+			l.pos += 3
+			return token{kind: tokenPunctuator, value: "...", pos: start}, nil
+		}
+	case c == '_' || isLetter(c):
+		for l.pos < len(l.src) && (l.src[l.pos] == '_' || isLetter(l.src[l.pos]) || isDigit(l.src[l.pos])) {
+			l.pos++
+		}
+		return token{kind: tokenName, value: l.src[start:l.pos], pos: start}, nil
+	case c == '-' || isDigit(c):
+		return l.number()
+	case c == '"':
+		if strings.HasPrefix(l.src[l.pos:], `"""`) {
+			return l.blockString()
+		}
+		return l.string()
+	}
+	r, _ := utf8.DecodeRuneInString(l.src[l.pos:])
+	return token{}, &syntaxError{message: fmt.Sprintf("Unexpected character %q.", r), pos: start}
+}
+
+// skipIgnored skips white space, line terminators, commas, comments and a
+// byte order mark.
+func (l *lexer) skipIgnored() {
+	for l.pos < len(l.src) {
+		switch c := l.src[l.pos]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			l.pos++
+		case c == '#':
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' && l.src[l.pos] != '\r' {
+				l.pos++
+			}
+		case strings.HasPrefix(l.src[l.pos:], "\ufeff"):
+			l.pos += len("\ufeff")
+		default:
+			return
+		}
+	}
+}
+
+func (l *lexer) number() (token, error) {
+	start := l.pos
+	kind := tokenInt
+	if l.src[l.pos] == '-' {
+		l.pos++
+	}
+	if l.pos+1 < len(l.src) && l.src[l.pos] == '0' && isDigit(l.src[l.pos+1]) {
+		return token{}, &syntaxError{message: "Invalid number, unexpected digit after 0.", pos: l.pos + 1}
+	}
+	digits := func() error {
+		if l.pos >= len(l.src) || !isDigit(l.src[l.pos]) {
+			return &syntaxError{message: "Invalid number, expected digit.", pos: l.pos}
+		}
+		for l.pos < len(l.src) && isDigit(l.src[l.pos]) {
+			l.pos++
+		}
+		return nil
+	}
+	if err := digits(); err != nil {
+		return token{}, err
+	}
+	if l.pos < len(l.src) && l.src[l.pos] == '.' {
+		kind = tokenFloat
+		l.pos++
+		if err := digits(); err != nil {
+			return token{}, err
+		}
+	}
+	if l.pos < len(l.src) && (l.src[l.pos] == 'e' || l.src[l.pos] == 'E') {
+		kind = tokenFloat
+		l.pos++
+		if l.pos < len(l.src) && (l.src[l.pos] == '+' || l.src[l.pos] == '-') {
+			l.pos++
+		}
+		if err := digits(); err != nil {
+			return token{}, err
+		}
+	}
+	if l.pos < len(l.src) && (l.src[l.pos] == '_' || l.src[l.pos] == '.' || isLetter(l.src[l.pos])) {
+		return token{}, &syntaxError{message: "Invalid number, expected digit.", pos: l.pos}
+	}
+	return token{kind: kind, value: l.src[start:l.pos], pos: start}, nil
+}
+
+func (l *lexer) string() (token, error) {
+	start := l.pos
+	l.pos++
+	var b strings.Builder
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch {
+		case c == '"':
+			l.pos++
+			return token{kind: tokenString, value: b.String(), pos: start}, nil
+		case c == '\n' || c == '\r':
+			return token{}, &syntaxError{message: "Unterminated string.", pos: start}
+		case c == '\\':
+			if l.pos+1 >= len(l.src) {
+				return token{}, &syntaxError{message: "Unterminated string.", pos: start}
+			}
+			escape := l.src[l.pos+1]
+			if replacement, ok := simpleEscapes[escape]; ok {
+				b.WriteByte(replacement)
+				l.pos += 2
+				continue
+			}
+			if escape != 'u' || l.pos+6 > len(l.src) {
+				return token{}, &syntaxError{message: "Invalid character escape sequence.", pos: l.pos}
+			}
+			code, err := strconv.ParseUint(l.src[l.pos+2:l.pos+6], 16, 32)
+			if err != nil {
+				return token{}, &syntaxError{message: "Invalid character escape sequence.", pos: l.pos}
+			}
+			b.WriteRune(rune(code))
+			l.pos += 6
+		default:
+			b.WriteByte(c)
+			l.pos++
+		}
+	}
+	return token{}, &syntaxError{message: "Unterminated string.", pos: start}
+}
+
+var simpleEscapes = map[byte]byte{'"': '"', '\\': '\\', '/': '/', 'b': '\b', 'f': '\f', 'n': '\n', 'r': '\r', 't': '\t'}
+
+// blockString reads a """block string""", removing the indentation common
+// to its lines and its leading and trailing blank lines.
+func (l *lexer) blockString() (token, error) {
+	start := l.pos
+	l.pos += 3
+	end := strings.Index(l.src[l.pos:], `"""`)
+	for end > 0 && l.src[l.pos+end-1] == '\\' {
+		next := strings.Index(l.src[l.pos+end+3:], `"""`)
+		if next < 0 {
+			end = -1
+			break
+		}
+		end += 3 + next
+	}
+	if end < 0 {
+		return token{}, &syntaxError{message: "Unterminated string.", pos: start}
+	}
+	raw := strings.ReplaceAll(l.src[l.pos:l.pos+end], `\"""`, `"""`)
+	l.pos += end + 3
+
+	lines := strings.Split(strings.ReplaceAll(raw, "\r\n", "\n"), "\n")
+	indent := -1
+	for _, line := range lines[1:] {
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed != "" && (indent < 0 || len(line)-len(trimmed) < indent) {
+			indent = len(line) - len(trimmed)
+		}
+	}
+	if indent > 0 {
+		for i := 1; i < len(lines); i++ {
+			if len(lines[i]) >= indent {
+				lines[i] = lines[i][indent:]
+			} else {
+				lines[i] = strings.TrimLeft(lines[i], " \t")
+			}
+		}
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return token{kind: tokenString, value: strings.Join(lines, "\n"), pos: start}, nil
+}
+
+func isLetter(c byte) bool { return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' }
+
+func isDigit(c byte) bool { return '0' <= c && c <= '9' }
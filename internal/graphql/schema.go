@@ -0,0 +1,361 @@
+// Package graphql executes GraphQL queries against a schema of Go-defined
+// object types. It covers what the API's read-only graph needs without an
+// external dependency: queries with variables, aliases, fragments and the
+// @skip and @include directives. Mutations, subscriptions, interfaces,
+// unions, enums, input objects and introspection beyond __typename are not
+// supported; the schema is published as SDL instead.
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"time"
+)
+
+// Type is the type of a field or argument: a *Scalar, an *Object, or a
+// *List or *NonNull wrapping one of them.
+type Type interface {
+	String() string
+}
+
+// Scalar is a leaf type. Serialize converts a resolved Go value to its JSON
+// form; Parse converts an argument or variable (decoded from JSON, so numbers
+// may be float64) to the Go value resolvers receive.
+type Scalar struct {
+	Name        string
+	Description string
+	Serialize   func(v any) (any, error)
+	Parse       func(v any) (any, error)
+}
+
+func (s *Scalar) String() string { return s.Name }
+
+// Object is an output type with fields. Fields without a Resolve read the
+// source value: the struct field with that json name, or the map key.
+type Object struct {
+	Name        string
+	Description string
+	Fields      []*Field
+}
+
+func (o *Object) String() string { return o.Name }
+
+func (o *Object) field(name string) *Field {
+	for _, f := range o.Fields {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+type Field struct {
+	Name        string
+	Description string
+	Type        Type
+	Args        []*Argument
+	Resolve     ResolveFunc
+}
+
+func (f *Field) arg(name string) *Argument {
+	for _, a := range f.Args {
+		if a.Name == name {
+			return a
+		}
+	}
+	return nil
+}
+
+// Argument is a field argument. Default is used when the query omits it and
+// must already be in its parsed form.
+type Argument struct {
+	Name        string
+	Description string
+	Type        Type
+	Default     any
+}
+
+// List is a list of Of.
+type List struct{ Of Type }
+
+func (l *List) String() string { return "[" + l.Of.String() + "]" }
+
+// NonNull is Of that can never be null.
+type NonNull struct{ Of Type }
+
+func (n *NonNull) String() string { return n.Of.String() + "!" }
+
+func NewList(of Type) *List { return &List{Of: of} }
+
+func NewNonNull(of Type) *NonNull { return &NonNull{Of: of} }
+
+// ResolveFunc returns the value of a field of source. A returned error is
+// reported to the client with the field's path and the field is null, so
+// its message must be fit for clients.
+type ResolveFunc func(p ResolveParams) (any, error)
+
+type ResolveParams struct {
+	Context context.Context
+	Source  any
+	Args    map[string]any
+}
+
+// DefaultMaxDepth limits how deeply queries may nest fields, so a query
+// cannot walk recursive types without bound.
+const DefaultMaxDepth = 12
+
+// Schema is a queryable schema. It supports queries only; mutations and
+// subscriptions are rejected, and of introspection only __typename is
+// answered (the schema itself is published as SDL by String).
+type Schema struct {
+	query    *Object
+	types    map[string]Type
+	maxDepth int
+}
+
+var nameRE = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`)
+
+// NewSchema checks the types reachable from query: names must be valid and
+// unique, and every field and argument must have a type.
+func NewSchema(query *Object) (*Schema, error) {
+	if query == nil {
+		return nil, errors.New("graphql: schema needs a query type")
+	}
+	s := &Schema{query: query, types: make(map[string]Type), maxDepth: DefaultMaxDepth}
+	// Built-in scalars are usable by variables even if no field has them.
+	for _, scalar := range []*Scalar{String, Int, Float, Boolean, ID} {
+		s.types[scalar.Name] = scalar
+	}
+	if err := s.collect(query); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// SetMaxDepth changes how deeply queries may nest fields.
+func (s *Schema) SetMaxDepth(depth int) {
+	s.maxDepth = depth
+}
+
+func (s *Schema) collect(t Type) error {
+	switch t := t.(type) {
+	case *List:
+		return s.collect(t.Of)
+	case *NonNull:
+		if _, ok := t.Of.(*NonNull); ok {
+			return fmt.Errorf("graphql: %s is non-null twice", t)
+		}
+		return s.collect(t.Of)
+	case *Scalar:
+		return s.register(t.Name, t)
+	case *Object:
+		if existing, ok := s.types[t.Name]; ok {
+			if existing != Type(t) {
+				return fmt.Errorf("graphql: two types named %s", t.Name)
+			}
+			return nil
+		}
+		if err := s.register(t.Name, t); err != nil {
+			return err
+		}
+		if len(t.Fields) == 0 {
+			return fmt.Errorf("graphql: type %s has no fields", t.Name)
+		}
+		seen := make(map[string]bool)
+		for _, f := range t.Fields {
+			if !nameRE.MatchString(f.Name) || seen[f.Name] {
+				return fmt.Errorf("graphql: invalid or duplicate field %s.%s", t.Name, f.Name)
+			}
+			seen[f.Name] = true
+			if f.Type == nil {
+				return fmt.Errorf("graphql: field %s.%s has no type", t.Name, f.Name)
+			}
+			if err := s.collect(f.Type); err != nil {
+				return err
+			}
+			for _, a := range f.Args {
+				if a.Type == nil || !nameRE.MatchString(a.Name) {
+					return fmt.Errorf("graphql: invalid argument %s of %s.%s", a.Name, t.Name, f.Name)
+				}
+				if _, ok := namedType(a.Type).(*Scalar); !ok {
+					return fmt.Errorf("graphql: argument %s of %s.%s is not a scalar", a.Name, t.Name, f.Name)
+				}
+				if err := s.collect(a.Type); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	case nil:
+		return errors.New("graphql: nil type")
+	}
+	return fmt.Errorf("graphql: unsupported type %T", t)
+}
+
+func (s *Schema) register(name string, t Type) error {
+	if !nameRE.MatchString(name) {
+		return fmt.Errorf("graphql: invalid type name %q", name)
+	}
+	if existing, ok := s.types[name]; ok && existing != t {
+		return fmt.Errorf("graphql: two types named %s", name)
+	}
+	s.types[name] = t
+	return nil
+}
+
+// namedType strips List and NonNull from t.
+func namedType(t Type) Type {
+	for {
+		switch w := t.(type) {
+		case *List:
+			t = w.Of
+		case *NonNull:
+			t = w.Of
+		default:
+			return t
+		}
+	}
+}
+
+// The built-in scalars, plus DateTime for RFC 3339 timestamps.
+var (
+	String = &Scalar{
+		Name:      "String",
+		Serialize: serializeString,
+		Parse: func(v any) (any, error) {
+			if s, ok := v.(string); ok {
+				return s, nil
+			}
+			return nil, fmt.Errorf("String cannot represent a non string value: %s", describe(v))
+		},
+	}
+	Int = &Scalar{
+		Name: "Int",
+		Serialize: func(v any) (any, error) {
+			rv := reflect.ValueOf(v)
+			switch rv.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				if n := rv.Int(); n >= math.MinInt32 && n <= math.MaxInt32 {
+					return n, nil
+				}
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+				if n := rv.Uint(); n <= math.MaxInt32 {
+					return int64(n), nil
+				}
+			}
+			return nil, fmt.Errorf("Int cannot represent %s", describe(v))
+		},
+		Parse: func(v any) (any, error) {
+			switch n := v.(type) {
+			case int64:
+				if n >= math.MinInt32 && n <= math.MaxInt32 {
+					return int(n), nil
+				}
+			case float64:
+				if n == math.Trunc(n) && n >= math.MinInt32 && n <= math.MaxInt32 {
+					return int(n), nil
+				}
+			}
+			return nil, fmt.Errorf("Int cannot represent non 32-bit signed integer value: %s", describe(v))
+		},
+	}
+	Float = &Scalar{
+		Name: "Float",
+		Serialize: func(v any) (any, error) {
+			rv := reflect.ValueOf(v)
+			switch rv.Kind() {
+			case reflect.Float32, reflect.Float64:
+				if f := rv.Float(); !math.IsInf(f, 0) && !math.IsNaN(f) {
+					return f, nil
+				}
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				return float64(rv.Int()), nil
+			}
+			return nil, fmt.Errorf("Float cannot represent %s", describe(v))
+		},
+		Parse: func(v any) (any, error) {
+			switch n := v.(type) {
+			case int64:
+				return float64(n), nil
+			case float64:
+				return n, nil
+			}
+			return nil, fmt.Errorf("Float cannot represent non numeric value: %s", describe(v))
+		},
+	}
+	Boolean = &Scalar{
+		Name: "Boolean",
+		Serialize: func(v any) (any, error) {
+			if rv := reflect.ValueOf(v); rv.Kind() == reflect.Bool {
+				return rv.Bool(), nil
+			}
+			return nil, fmt.Errorf("Boolean cannot represent %s", describe(v))
+		},
+		Parse: func(v any) (any, error) {
+			if b, ok := v.(bool); ok {
+				return b, nil
+			}
+			return nil, fmt.Errorf("Boolean cannot represent a non boolean value: %s", describe(v))
+		},
+	}
+	ID = &Scalar{
+		Name:      "ID",
+		Serialize: serializeString,
+		Parse: func(v any) (any, error) {
+			switch id := v.(type) {
+			case string:
+				return id, nil
+			case int64:
+				return fmt.Sprint(id), nil
+			case float64:
+				if id == math.Trunc(id) {
+					return fmt.Sprint(int64(id)), nil
+				}
+			}
+			return nil, fmt.Errorf("ID cannot represent value: %s", describe(v))
+		},
+	}
+	DateTime = &Scalar{
+		Name:        "DateTime",
+		Description: "An RFC 3339 timestamp.",
+		Serialize: func(v any) (any, error) {
+			if t, ok := v.(time.Time); ok {
+				return t.Format(time.RFC3339Nano), nil
+			}
+			return nil, fmt.Errorf("DateTime cannot represent %s", describe(v))
+		},
+		Parse: func(v any) (any, error) {
+			if s, ok := v.(string); ok {
+				if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+					return t, nil
+				}
+			}
+			return nil, fmt.Errorf("DateTime cannot represent %s", describe(v))
+		},
+	}
+)
+
+func serializeString(v any) (any, error) {
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.String {
+		return rv.String(), nil
+	}
+	return nil, fmt.Errorf("String cannot represent %s", describe(v))
+}
+
+func describe(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return fmt.Sprintf("%q", v)
+	case enumValue:
+		return string(v)
+	case []any, objectValue, map[string]any:
+		return "a list or object"
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,83 @@
+package graphql
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var builtinScalars = map[*Scalar]bool{String: true, Int: true, Float: true, Boolean: true, ID: true}
+
+// String writes the schema in the GraphQL schema definition language, for
+// clients to generate code from: the query type first, then the other
+// types by name.
+func (s *Schema) String() string {
+	names := make([]string, 0, len(s.types))
+	for name := range s.types {
+		if name != s.query.Name {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	writeObject(&b, s.query)
+	for _, name := range names {
+		switch t := s.types[name].(type) {
+		case *Object:
+			b.WriteByte('\n')
+			writeObject(&b, t)
+		case *Scalar:
+			if builtinScalars[t] {
+				continue
+			}
+			b.WriteByte('\n')
+			writeDescription(&b, "", t.Description)
+			fmt.Fprintf(&b, "scalar %s\n", t.Name)
+		}
+	}
+	return b.String()
+}
+
+func writeObject(b *strings.Builder, o *Object) {
+	writeDescription(b, "", o.Description)
+	fmt.Fprintf(b, "type %s {\n", o.Name)
+	for _, f := range o.Fields {
+		writeDescription(b, "  ", f.Description)
+		b.WriteString("  " + f.Name)
+		if len(f.Args) > 0 {
+			args := make([]string, len(f.Args))
+			for i, a := range f.Args {
+				args[i] = a.Name + ": " + a.Type.String()
+				if a.Default != nil {
+					args[i] += " = " + literal(a.Default)
+				}
+			}
+			b.WriteString("(" + strings.Join(args, ", ") + ")")
+		}
+		b.WriteString(": " + f.Type.String() + "\n")
+	}
+	b.WriteString("}\n")
+}
+
+func writeDescription(b *strings.Builder, indent, description string) {
+	if description != "" {
+		b.WriteString(indent + strconv.Quote(description) + "\n")
+	}
+}
+
+// literal writes a parsed argument default as a GraphQL value.
+func literal(v any) string {
+	switch v := v.(type) {
+	case string:
+		return strconv.Quote(v)
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = literal(item)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,260 @@
+package graphql
+
+import "fmt"
+
+// validate checks an operation against the schema before it runs: fields,
+// arguments, fragments, directives and variables must exist and fit, and
+// fields may not nest deeper than the schema allows.
+func (s *Schema) validate(source string, doc *document, op *operation) []*Error {
+	v := &validator{
+		schema:    s,
+		source:    source,
+		doc:       doc,
+		variables: make(map[string]*variableDefinition),
+		used:      make(map[string]bool),
+		spreading: make(map[string]bool),
+	}
+	for _, def := range op.variables {
+		if _, ok := v.variables[def.name]; ok {
+			v.fail(def.pos, "There can be only one variable named \"$%s\".", def.name)
+		}
+		v.variables[def.name] = def
+		if _, ok := namedType(s.inputType(def.typ)).(*Scalar); !ok {
+			v.fail(def.pos, "Variable \"$%s\" cannot be non-input type \"%s\".", def.name, def.typ)
+		}
+	}
+	v.directives(op.directives, false)
+	v.selections(s.query, op.selectionSet, 1, make(map[string]*field))
+	for _, def := range op.variables {
+		if !v.used[def.name] {
+			v.fail(def.pos, "Variable \"$%s\" is never used.", def.name)
+		}
+	}
+	return v.errors
+}
+
+type validator struct {
+	schema    *Schema
+	source    string
+	doc       *document
+	variables map[string]*variableDefinition
+	used      map[string]bool
+	// spreading holds the fragments being expanded, to catch cycles.
+	spreading map[string]bool
+	tooDeep   bool
+	errors    []*Error
+}
+
+func (v *validator) fail(pos int, format string, args ...any) {
+	v.errors = append(v.errors, errorf(v.source, pos, format, args...))
+}
+
+// selections checks a selection set of type t. keys maps the response keys
+// of the set, fragments included, to the first field selected under each.
+func (v *validator) selections(t *Object, selections []selection, depth int, keys map[string]*field) {
+	for _, sel := range selections {
+		switch sel := sel.(type) {
+		case *field:
+			if first, ok := keys[sel.responseKey()]; ok && first.name != sel.name {
+				v.fail(sel.pos, "Fields %q conflict because %q and %q are different fields.", sel.responseKey(), first.name, sel.name)
+				continue
+			}
+			keys[sel.responseKey()] = sel
+			v.field(t, sel, depth)
+		case *fragmentSpread:
+			v.directives(sel.directives, true)
+			frag := v.doc.fragments[sel.name]
+			if frag == nil {
+				v.fail(sel.pos, "Unknown fragment %q.", sel.name)
+				continue
+			}
+			if v.spreading[sel.name] {
+				v.fail(sel.pos, "Cannot spread fragment %q within itself.", sel.name)
+				continue
+			}
+			if !v.typeCondition(t, frag.typeCondition, frag.pos) {
+				continue
+			}
+			v.spreading[sel.name] = true
+			v.selections(t, frag.selectionSet, depth, keys)
+			delete(v.spreading, sel.name)
+		case *inlineFragment:
+			v.directives(sel.directives, true)
+			if sel.typeCondition == "" || v.typeCondition(t, sel.typeCondition, sel.pos) {
+				v.selections(t, sel.selectionSet, depth, keys)
+			}
+		}
+	}
+}
+
+// typeCondition reports whether a fragment on condition applies to t. Every
+// type is an object, so only t itself does.
+func (v *validator) typeCondition(t *Object, condition string, pos int) bool {
+	switch other := v.schema.types[condition].(type) {
+	case nil:
+		v.fail(pos, "Unknown type %q.", condition)
+		return false
+	case *Object:
+		if other != t {
+			v.fail(pos, "Fragment cannot be spread here as objects of type %q can never be of type %q.", t.Name, condition)
+			return false
+		}
+		return true
+	}
+	v.fail(pos, "Fragment cannot condition on non composite type %q.", condition)
+	return false
+}
+
+func (v *validator) field(t *Object, f *field, depth int) {
+	v.directives(f.directives, true)
+	if f.name == "__typename" {
+		if len(f.arguments) > 0 || len(f.selectionSet) > 0 {
+			v.fail(f.pos, "Field \"__typename\" takes no arguments or subfields.")
+		}
+		return
+	}
+	def := t.field(f.name)
+	if def == nil {
+		v.fail(f.pos, "Cannot query field %q on type %q.", f.name, t.Name)
+		return
+	}
+
+	for _, arg := range f.arguments {
+		argDef := def.arg(arg.name)
+		if argDef == nil {
+			v.fail(arg.pos, "Unknown argument %q on field \"%s.%s\".", arg.name, t.Name, f.name)
+			continue
+		}
+		v.value(argDef.Type, argDef.Default != nil, arg, fmt.Sprintf("Argument %q", arg.name))
+	}
+	for _, argDef := range def.Args {
+		if isNonNull(argDef.Type) && argDef.Default == nil && !hasArgument(f.arguments, argDef.Name) {
+			v.fail(f.pos, "Field %q argument %q of type %q is required, but it was not provided.", f.name, argDef.Name, argDef.Type)
+		}
+	}
+
+	switch named := namedType(def.Type).(type) {
+	case *Object:
+		if len(f.selectionSet) == 0 {
+			v.fail(f.pos, "Field %q of type %q must have a selection of subfields.", f.name, def.Type)
+			return
+		}
+		if depth >= v.schema.maxDepth {
+			if !v.tooDeep {
+				v.tooDeep = true
+				v.fail(f.pos, "Query is nested too deeply; at most %d levels of fields are allowed.", v.schema.maxDepth)
+			}
+			return
+		}
+		v.selections(named, f.selectionSet, depth+1, make(map[string]*field))
+	default:
+		if len(f.selectionSet) > 0 {
+			v.fail(f.pos, "Field %q must not have a selection since type %q has no subfields.", f.name, def.Type)
+		}
+	}
+}
+
+// directives checks @skip and @include, the only directives there are,
+// which only apply to fields and fragments.
+func (v *validator) directives(directives []*directive, allowed bool) {
+	for _, d := range directives {
+		if d.name != "skip" && d.name != "include" {
+			v.fail(d.pos, "Unknown directive \"@%s\".", d.name)
+			continue
+		}
+		if !allowed {
+			v.fail(d.pos, "Directive \"@%s\" may not be used on operations.", d.name)
+			continue
+		}
+		if len(d.arguments) != 1 || d.arguments[0].name != "if" {
+			v.fail(d.pos, "Directive \"@%s\" takes exactly one argument \"if\" of type \"Boolean!\".", d.name)
+			continue
+		}
+		v.value(NewNonNull(Boolean), false, d.arguments[0], "Argument \"if\"")
+	}
+}
+
+// value checks an argument's literal, or the variable it names, against the
+// argument's type.
+func (v *validator) value(t Type, hasDefault bool, arg *argument, what string) {
+	if name, ok := arg.value.(variable); ok {
+		def := v.variables[string(name)]
+		if def == nil {
+			v.fail(arg.pos, "Variable \"$%s\" is not defined.", name)
+			return
+		}
+		v.used[string(name)] = true
+		if !variableFits(def, t, hasDefault) {
+			v.fail(arg.pos, "Variable \"$%s\" of type \"%s\" used in position expecting type \"%s\".", name, def.typ, t)
+		}
+		return
+	}
+	if v.listVariables(arg.value, arg.pos) {
+		// Lists holding variables are checked as they run.
+		return
+	}
+	if _, err := coerceInput(t, arg.value); err != nil {
+		v.fail(arg.pos, "%s has invalid value: %s", what, err)
+	}
+}
+
+// listVariables marks the variables inside a list literal used and reports
+// whether there were any.
+func (v *validator) listVariables(val value, pos int) bool {
+	items, ok := val.([]any)
+	if !ok {
+		return false
+	}
+	found := false
+	for _, item := range items {
+		if name, ok := item.(variable); ok {
+			found = true
+			if v.variables[string(name)] == nil {
+				v.fail(pos, "Variable \"$%s\" is not defined.", name)
+			}
+			v.used[string(name)] = true
+		} else if v.listVariables(item, pos) {
+			found = true
+		}
+	}
+	return found
+}
+
+// variableFits reports whether a variable of the defined type may fill a
+// position of type t. A nullable variable fits a non-null position only
+// when either has a default to fall back on.
+func variableFits(def *variableDefinition, t Type, positionHasDefault bool) bool {
+	ref := def.typ
+	if nn, ok := t.(*NonNull); ok {
+		if !ref.nonNull && def.defaultValue == nil && !positionHasDefault {
+			return false
+		}
+		t = nn.Of
+	}
+	return refFits(&typeRef{name: ref.name, elem: ref.elem}, t)
+}
+
+func refFits(ref *typeRef, t Type) bool {
+	if nn, ok := t.(*NonNull); ok {
+		if !ref.nonNull {
+			return false
+		}
+		t = nn.Of
+	}
+	switch t := t.(type) {
+	case *List:
+		return ref.elem != nil && refFits(ref.elem, t.Of)
+	case *Scalar:
+		return ref.elem == nil && ref.name == t.Name
+	}
+	return false
+}
+
+func hasArgument(args []*argument, name string) bool {
+	for _, arg := range args {
+		if arg.name == name {
+			return true
+		}
+	}
+	return false
+}
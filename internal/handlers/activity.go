@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+	"github.com/graytonio/warframe-wishlist/internal/middleware"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"github.com/graytonio/warframe-wishlist/pkg/response"
+)
+
+// ActivityHandler serves the caller's activity log: the changes made to their
+// wishlist and owned blueprints.
+type ActivityHandler struct {
+	activityService services.ActivityServiceInterface
+}
+
+func NewActivityHandler(activityService services.ActivityServiceInterface) *ActivityHandler {
+	return &ActivityHandler{activityService: activityService}
+}
+
+func (h *ActivityHandler) ListActivity(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+	logger.Debug(ctx, "handler: ListActivity called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: ListActivity - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	offset, _ := strconv.Atoi(query.Get("offset"))
+	page, err := h.activityService.ListActivity(ctx, userID, offset, limit)
+	if err != nil {
+		logger.Error(ctx, "handler: ListActivity - failed to list activity", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to list activity")
+		return
+	}
+
+	logger.Info(ctx, "handler: ListActivity - success", "count", len(page.Entries), "total", page.Total)
+	response.JSON(w, http.StatusOK, dto.NewActivityResponse(page))
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestActivityHandler_ListActivity(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		query          string
+		mockError      error
+		expectedStatus int
+		expectedOffset int
+		expectedLimit  int
+	}{
+		{name: "success", userID: "user-123", query: "?offset=20&limit=10", expectedStatus: http.StatusOK, expectedOffset: 20, expectedLimit: 10},
+		{name: "defaults", userID: "user-123", expectedStatus: http.StatusOK},
+		{name: "unauthorized - no user ID", userID: "", expectedStatus: http.StatusUnauthorized},
+		{name: "service error", userID: "user-123", mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotOffset, gotLimit int
+			handler := NewActivityHandler(&mocks.MockActivityService{
+				ListActivityFunc: func(ctx context.Context, userID string, offset, limit int) (*models.ActivityPage, error) {
+					gotOffset, gotLimit = offset, limit
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return &models.ActivityPage{
+						Entries: []models.ActivityEntry{{
+							ID:        primitive.NewObjectID(),
+							UserID:    userID,
+							Target:    models.ActivityTargetWishlist,
+							Action:    models.ActivityAdd,
+							Payload:   `{"uniqueName":"/Lotus/Soma","quantity":1}`,
+							RequestID: "req-1",
+							CreatedAt: time.Now(),
+						}},
+						Total:  21,
+						Limit:  limit,
+						Offset: offset,
+					}, nil
+				},
+			})
+			r := chi.NewRouter()
+			r.With(withTestUser(tt.userID)).Get("/api/v1/profile/activity", handler.ListActivity)
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/profile/activity"+tt.query, nil))
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			if gotOffset != tt.expectedOffset || gotLimit != tt.expectedLimit {
+				t.Errorf("expected offset %d limit %d, got %d %d", tt.expectedOffset, tt.expectedLimit, gotOffset, gotLimit)
+			}
+			var body struct {
+				Entries []struct {
+					Action  string `json:"action"`
+					Payload struct {
+						UniqueName string `json:"uniqueName"`
+					} `json:"payload"`
+					RequestID string `json:"requestId"`
+				} `json:"entries"`
+				Count int `json:"count"`
+				Total int `json:"total"`
+			}
+			json.NewDecoder(rec.Body).Decode(&body)
+			if body.Count != 1 || body.Total != 21 || len(body.Entries) != 1 {
+				t.Fatalf("unexpected body %+v", body)
+			}
+			if entry := body.Entries[0]; entry.Action != models.ActivityAdd || entry.Payload.UniqueName != "/Lotus/Soma" || entry.RequestID != "req-1" {
+				t.Errorf("expected the payload as JSON, got %+v", entry)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"github.com/graytonio/warframe-wishlist/pkg/response"
+)
+
+// AdminHandler serves the admin API under /api/v1/admin. The admin role is
+// checked by middleware.RequireRole and every action audited by
+// middleware.AuditAdminActions where it is mounted.
+type AdminHandler struct {
+	usageService    services.UsageServiceInterface
+	traceService    services.UserTraceServiceInterface
+	wishlistService services.WishlistServiceInterface
+	refreshService  services.ItemRefreshServiceInterface
+	dataSync        services.DataSyncServiceInterface
+}
+
+func NewAdminHandler(usageService services.UsageServiceInterface, traceService services.UserTraceServiceInterface, wishlistService services.WishlistServiceInterface, refreshService services.ItemRefreshServiceInterface, dataSync services.DataSyncServiceInterface) *AdminHandler {
+	return &AdminHandler{
+		usageService:    usageService,
+		traceService:    traceService,
+		wishlistService: wishlistService,
+		refreshService:  refreshService,
+		dataSync:        dataSync,
+	}
+}
+
+// GetUser looks a user up by ID: what they store against the per-user caps
+// and whether support is tracing them.
+func (h *AdminHandler) GetUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: AdminGetUser called")
+
+	userID := chi.URLParam(r, "userID")
+	usage, err := h.usageService.GetUsage(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "handler: AdminGetUser - failed to get usage", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to get user")
+		return
+	}
+
+	trace, err := h.traceService.GetTrace(ctx, userID)
+	if err != nil && !errors.Is(err, services.ErrUserTraceNotFound) {
+		logger.Error(ctx, "handler: AdminGetUser - failed to get trace", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to get user")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, dto.NewAdminUser(usage, trace))
+}
+
+// GetUserWishlist returns a user's wishlist expanded with item details and
+// completion, as the user sees it with ?expand=items.
+func (h *AdminHandler) GetUserWishlist(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: AdminGetUserWishlist called")
+
+	wishlist, err := h.wishlistService.GetExpandedWishlist(ctx, chi.URLParam(r, "userID"))
+	if err != nil {
+		logger.Error(ctx, "handler: AdminGetUserWishlist - failed to get wishlist", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to get wishlist")
+		return
+	}
+
+	logger.Info(ctx, "handler: AdminGetUserWishlist - success", "itemCount", len(wishlist.Items))
+	response.JSON(w, http.StatusOK, dto.NewExpandedWishlist(wishlist))
+}
+
+// TriggerSync starts an item refresh from the WFCD export now. The refresh
+// runs in the background; GET /admin/sync/status reports its progress.
+func (h *AdminHandler) TriggerSync(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: AdminTriggerSync called")
+
+	if err := h.refreshService.Start(ctx); err != nil {
+		switch {
+		case errors.Is(err, services.ErrItemRefreshRunning):
+			rejected(w, http.StatusConflict, err)
+		case errors.Is(err, services.ErrItemRefreshUnavailable):
+			rejected(w, http.StatusServiceUnavailable, err)
+		default:
+			logger.Error(ctx, "handler: AdminTriggerSync - failed to start refresh", "error", err)
+			response.Error(w, http.StatusInternalServerError, "failed to start item refresh")
+		}
+		return
+	}
+
+	logger.Info(ctx, "handler: AdminTriggerSync - started")
+	response.JSON(w, http.StatusAccepted, map[string]string{
+		"message": "item refresh started",
+	})
+}
+
+// FlushCaches runs the data sync hooks at the current data version, purging
+// and re-warming every cache of the item data. Like the data sync webhook,
+// the hooks run in the background.
+func (h *AdminHandler) FlushCaches(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: AdminFlushCaches called")
+
+	go func(ctx context.Context) {
+		if err := h.dataSync.Flush(ctx); err != nil {
+			logger.Error(ctx, "handler: AdminFlushCaches - sync hooks failed", "error", err)
+		}
+	}(context.WithoutCancel(ctx))
+
+	logger.Info(ctx, "handler: AdminFlushCaches - accepted", "version", h.dataSync.Version())
+	response.JSON(w, http.StatusAccepted, map[string]string{
+		"message": "cache flush started",
+	})
+}
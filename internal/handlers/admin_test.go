@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+)
+
+func newAdminRouter(handler *AdminHandler) chi.Router {
+	r := chi.NewRouter()
+	r.Get("/api/v1/admin/users/{userID}", handler.GetUser)
+	r.Get("/api/v1/admin/users/{userID}/wishlist", handler.GetUserWishlist)
+	r.Post("/api/v1/admin/sync", handler.TriggerSync)
+	r.Post("/api/v1/admin/cache/flush", handler.FlushCaches)
+	return r
+}
+
+func TestAdminHandler_GetUser(t *testing.T) {
+	tests := []struct {
+		name           string
+		traceError     error
+		usageError     error
+		expectedStatus int
+		expectTrace    bool
+	}{
+		{name: "traced user", expectedStatus: http.StatusOK, expectTrace: true},
+		{name: "untraced user", traceError: services.ErrUserTraceNotFound, expectedStatus: http.StatusOK},
+		{name: "usage error", usageError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+		{name: "trace error", traceError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usage := &mocks.MockUsageService{
+				GetUsageFunc: func(ctx context.Context, userID string) (*models.UserUsage, error) {
+					if tt.usageError != nil {
+						return nil, tt.usageError
+					}
+					return &models.UserUsage{UserID: userID, Resources: []models.ResourceUsage{{Resource: "wishlistItems", Used: 3, Limit: 500}}}, nil
+				},
+			}
+			traces := &mocks.MockUserTraceService{
+				GetTraceFunc: func(ctx context.Context, userID string) (*models.UserTrace, error) {
+					if tt.traceError != nil {
+						return nil, tt.traceError
+					}
+					return &models.UserTrace{UserID: userID, Until: time.Now().Add(time.Hour), Reason: "ticket 42"}, nil
+				},
+			}
+			handler := NewAdminHandler(usage, traces, &mocks.MockWishlistService{}, &mocks.MockItemRefreshService{}, &mocks.MockDataSyncService{})
+
+			rec := httptest.NewRecorder()
+			newAdminRouter(handler).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/admin/users/user-123", nil))
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if rec.Code != http.StatusOK {
+				return
+			}
+			var user dto.AdminUser
+			if err := json.NewDecoder(rec.Body).Decode(&user); err != nil {
+				t.Fatal(err)
+			}
+			if user.UserID != "user-123" || len(user.Resources) != 1 || user.Resources[0].Used != 3 {
+				t.Errorf("unexpected user %+v", user)
+			}
+			if (user.Trace != nil) != tt.expectTrace {
+				t.Errorf("expected trace %v, got %+v", tt.expectTrace, user.Trace)
+			}
+		})
+	}
+}
+
+func TestAdminHandler_GetUserWishlist(t *testing.T) {
+	var gotUserID string
+	wishlists := &mocks.MockWishlistService{
+		GetExpandedWishlistFunc: func(ctx context.Context, userID string) (*models.ExpandedWishlist, error) {
+			gotUserID = userID
+			return &models.ExpandedWishlist{UserID: userID, Items: []models.ExpandedWishlistItem{{WishlistItem: models.WishlistItem{UniqueName: "/Lotus/Forma", Quantity: 2}}}}, nil
+		},
+	}
+	handler := NewAdminHandler(&mocks.MockUsageService{}, &mocks.MockUserTraceService{}, wishlists, &mocks.MockItemRefreshService{}, &mocks.MockDataSyncService{})
+
+	rec := httptest.NewRecorder()
+	newAdminRouter(handler).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/admin/users/user-123/wishlist", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if gotUserID != "user-123" {
+		t.Errorf("expected the user from the path, got %q", gotUserID)
+	}
+	var wishlist dto.ExpandedWishlist
+	if err := json.NewDecoder(rec.Body).Decode(&wishlist); err != nil {
+		t.Fatal(err)
+	}
+	if len(wishlist.Items) != 1 || wishlist.Items[0].UniqueName != "/Lotus/Forma" {
+		t.Errorf("unexpected wishlist %+v", wishlist)
+	}
+}
+
+func TestAdminHandler_TriggerSync(t *testing.T) {
+	tests := []struct {
+		name           string
+		startError     error
+		expectedStatus int
+		expectedCode   string
+	}{
+		{name: "started", expectedStatus: http.StatusAccepted},
+		{name: "already running", startError: services.ErrItemRefreshRunning, expectedStatus: http.StatusConflict, expectedCode: "item_refresh_running"},
+		{name: "no database", startError: services.ErrItemRefreshUnavailable, expectedStatus: http.StatusServiceUnavailable, expectedCode: "item_refresh_unavailable"},
+		{name: "unexpected error", startError: errors.New("boom"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			refresh := &mocks.MockItemRefreshService{
+				StartFunc: func(ctx context.Context) error { return tt.startError },
+			}
+			handler := NewAdminHandler(&mocks.MockUsageService{}, &mocks.MockUserTraceService{}, &mocks.MockWishlistService{}, refresh, &mocks.MockDataSyncService{})
+
+			rec := httptest.NewRecorder()
+			newAdminRouter(handler).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/admin/sync", nil))
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if tt.expectedCode != "" && !strings.Contains(rec.Body.String(), `"code":"`+tt.expectedCode+`"`) {
+				t.Errorf("expected code %s, got %s", tt.expectedCode, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestAdminHandler_FlushCaches(t *testing.T) {
+	flushed := make(chan struct{})
+	dataSync := &mocks.MockDataSyncService{
+		FlushFunc: func(ctx context.Context) error {
+			close(flushed)
+			return nil
+		},
+	}
+	handler := NewAdminHandler(&mocks.MockUsageService{}, &mocks.MockUserTraceService{}, &mocks.MockWishlistService{}, &mocks.MockItemRefreshService{}, dataSync)
+
+	rec := httptest.NewRecorder()
+	newAdminRouter(handler).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/admin/cache/flush", nil))
+
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected status 202, got %d: %s", rec.Code, rec.Body.String())
+	}
+	select {
+	case <-flushed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the caches to be flushed")
+	}
+}
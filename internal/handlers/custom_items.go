@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+	"github.com/graytonio/warframe-wishlist/internal/middleware"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"github.com/graytonio/warframe-wishlist/pkg/response"
+)
+
+type CustomItemHandler struct {
+	customItemService services.CustomItemServiceInterface
+}
+
+func NewCustomItemHandler(customItemService services.CustomItemServiceInterface) *CustomItemHandler {
+	return &CustomItemHandler{customItemService: customItemService}
+}
+
+func (h *CustomItemHandler) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: ListCustomItems called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: ListCustomItems - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	items, err := h.customItemService.List(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "handler: ListCustomItems - failed to list custom items", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to list custom items")
+		return
+	}
+
+	logger.Info(ctx, "handler: ListCustomItems - success", "count", len(items))
+	response.JSON(w, http.StatusOK, dto.NewCustomItems(items))
+}
+
+// Search searches the caller's custom items and answers in the shape of the
+// item search, every result in the "Custom" category. Unlike the item search
+// it is private to the caller, so it is never cached by the CDN.
+func (h *CustomItemHandler) Search(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+	logger.Debug(ctx, "handler: SearchCustomItems called", "query", query.Get("q"))
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: SearchCustomItems - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	offset, _ := strconv.Atoi(query.Get("offset"))
+	page, err := h.customItemService.Search(ctx, userID, models.SearchParams{
+		Query:  query.Get("q"),
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		logger.Error(ctx, "handler: SearchCustomItems - failed to search custom items", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to search custom items")
+		return
+	}
+
+	logger.Info(ctx, "handler: SearchCustomItems - success", "resultCount", len(page.Items), "total", page.Total)
+	response.JSON(w, http.StatusOK, dto.NewItemSearchResponse(page))
+}
+
+func (h *CustomItemHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: CreateCustomItem called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: CreateCustomItem - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	var req dto.CustomItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn(ctx, "handler: CreateCustomItem - invalid request body", "error", err)
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	item, err := h.customItemService.Create(ctx, userID, req.ToModel())
+	if err != nil {
+		writeCustomItemError(w, r, "CreateCustomItem", err, "failed to create custom item")
+		return
+	}
+
+	logger.Info(ctx, "handler: CreateCustomItem - success", "uniqueName", item.UniqueName)
+	response.JSON(w, http.StatusCreated, dto.NewCustomItem(item))
+}
+
+func (h *CustomItemHandler) Update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: UpdateCustomItem called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: UpdateCustomItem - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	uniqueName, err := uniqueNameParam(r)
+	if err != nil {
+		logger.Warn(ctx, "handler: UpdateCustomItem - invalid uniqueName", "error", err)
+		rejected(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var req dto.CustomItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn(ctx, "handler: UpdateCustomItem - invalid request body", "error", err)
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	item, err := h.customItemService.Update(ctx, userID, uniqueName, req.ToModel())
+	if err != nil {
+		writeCustomItemError(w, r, "UpdateCustomItem", err, "failed to update custom item")
+		return
+	}
+
+	logger.Info(ctx, "handler: UpdateCustomItem - success", "uniqueName", uniqueName)
+	response.JSON(w, http.StatusOK, dto.NewCustomItem(item))
+}
+
+func (h *CustomItemHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: DeleteCustomItem called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: DeleteCustomItem - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	uniqueName, err := uniqueNameParam(r)
+	if err != nil {
+		logger.Warn(ctx, "handler: DeleteCustomItem - invalid uniqueName", "error", err)
+		rejected(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := h.customItemService.Delete(ctx, userID, uniqueName); err != nil {
+		writeCustomItemError(w, r, "DeleteCustomItem", err, "failed to delete custom item")
+		return
+	}
+
+	logger.Info(ctx, "handler: DeleteCustomItem - success", "uniqueName", uniqueName)
+	response.JSON(w, http.StatusOK, map[string]string{
+		"message": "custom item deleted",
+	})
+}
+
+func writeCustomItemError(w http.ResponseWriter, r *http.Request, name string, err error, fallback string) {
+	ctx := r.Context()
+
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrInvalidCustomItem):
+		status = http.StatusBadRequest
+	case errors.Is(err, services.ErrCustomItemNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrTooManyCustomItems):
+		status = http.StatusConflict
+	}
+
+	if status == http.StatusInternalServerError {
+		logger.Error(ctx, "handler: "+name+" - "+fallback, "error", err)
+		response.Error(w, status, fallback)
+		return
+	}
+	logger.Warn(ctx, "handler: "+name+" - request rejected", "error", err)
+	rejected(w, status, err)
+}
@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/graytonio/warframe-wishlist/internal/middleware"
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+)
+
+// newCustomItemRouter mounts the custom item routes as main does, with
+// userID injected in place of the auth middleware.
+func newCustomItemRouter(service services.CustomItemServiceInterface, userID string) http.Handler {
+	handler := NewCustomItemHandler(service)
+	r := chi.NewRouter()
+	r.Route("/api/v1/custom-items", func(r chi.Router) {
+		r.Use(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ctx := context.WithValue(r.Context(), middleware.UserIDKey, userID)
+				next.ServeHTTP(w, r.WithContext(ctx))
+			})
+		})
+		r.Get("/", handler.List)
+		r.Get("/search", handler.Search)
+		r.Post("/", handler.Create)
+		r.Put("/*", handler.Update)
+		r.Delete("/*", handler.Delete)
+	})
+	return r
+}
+
+func TestCustomItemHandler_List(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "success", userID: "user-123", expectedStatus: http.StatusOK},
+		{name: "unauthorized - no user ID", userID: "", expectedStatus: http.StatusUnauthorized},
+		{name: "service error", userID: "user-123", mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.MockCustomItemService{
+				ListFunc: func(ctx context.Context, userID string) ([]models.CustomItem, error) {
+					return []models.CustomItem{{UniqueName: "/Custom/abc", Name: "Dojo Garden"}}, tt.mockError
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/custom-items", nil)
+			rec := httptest.NewRecorder()
+			newCustomItemRouter(service, tt.userID).ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestCustomItemHandler_Search(t *testing.T) {
+	var gotParams models.SearchParams
+	service := &mocks.MockCustomItemService{
+		SearchFunc: func(ctx context.Context, userID string, params models.SearchParams) (*models.ItemSearchPage, error) {
+			gotParams = params
+			return &models.ItemSearchPage{
+				Items: []models.ItemSearchResult{{UniqueName: "/Custom/abc", Name: "Dojo Garden", Category: models.CustomItemCategory}},
+				Total: 3,
+			}, nil
+		},
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/custom-items/search?q=dojo&limit=1&offset=2", nil)
+	rec := httptest.NewRecorder()
+	newCustomItemRouter(service, "user-123").ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if gotParams.Query != "dojo" || gotParams.Limit != 1 || gotParams.Offset != 2 {
+		t.Errorf("expected the query parameters to be passed through, got %+v", gotParams)
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	items, _ := body["items"].([]interface{})
+	if len(items) != 1 || items[0].(map[string]interface{})["category"] != models.CustomItemCategory {
+		t.Errorf("expected one custom result, got %v", body["items"])
+	}
+}
+
+func TestCustomItemHandler_Create(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		body           string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "success", userID: "user-123", body: `{"name":"Dojo Garden","components":[{"uniqueName":"/Lotus/Forma","itemCount":2}]}`, expectedStatus: http.StatusCreated},
+		{name: "unauthorized - no user ID", userID: "", body: `{}`, expectedStatus: http.StatusUnauthorized},
+		{name: "invalid body", userID: "user-123", body: `{`, expectedStatus: http.StatusBadRequest},
+		{name: "invalid item", userID: "user-123", body: `{}`, mockError: fmt.Errorf("%w: name is required", services.ErrInvalidCustomItem), expectedStatus: http.StatusBadRequest},
+		{name: "too many items", userID: "user-123", body: `{"name":"x"}`, mockError: services.ErrTooManyCustomItems, expectedStatus: http.StatusConflict},
+		{name: "service error", userID: "user-123", body: `{"name":"x"}`, mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotReq models.CustomItemRequest
+			service := &mocks.MockCustomItemService{
+				CreateFunc: func(ctx context.Context, userID string, req models.CustomItemRequest) (*models.CustomItem, error) {
+					gotReq = req
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return &models.CustomItem{UserID: userID, UniqueName: "/Custom/abc", Name: req.Name}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/custom-items", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			newCustomItemRouter(service, tt.userID).ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if tt.expectedStatus == http.StatusCreated && (len(gotReq.Components) != 1 || gotReq.Components[0].ItemCount != 2) {
+				t.Errorf("expected the components to be passed through, got %+v", gotReq)
+			}
+		})
+	}
+}
+
+func TestCustomItemHandler_Update(t *testing.T) {
+	tests := []struct {
+		name           string
+		target         string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "success", target: "/api/v1/custom-items/Custom/abc", expectedStatus: http.StatusOK},
+		{name: "not found", target: "/api/v1/custom-items/Custom/abc", mockError: services.ErrCustomItemNotFound, expectedStatus: http.StatusNotFound},
+		{name: "invalid item", target: "/api/v1/custom-items/Custom/abc", mockError: services.ErrInvalidCustomItem, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotUniqueName string
+			service := &mocks.MockCustomItemService{
+				UpdateFunc: func(ctx context.Context, userID, uniqueName string, req models.CustomItemRequest) (*models.CustomItem, error) {
+					gotUniqueName = uniqueName
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return &models.CustomItem{UserID: userID, UniqueName: uniqueName, Name: req.Name}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPut, tt.target, strings.NewReader(`{"name":"Dojo Garden II"}`))
+			rec := httptest.NewRecorder()
+			newCustomItemRouter(service, "user-123").ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if gotUniqueName != "/Custom/abc" {
+				t.Errorf("expected uniqueName /Custom/abc, got %q", gotUniqueName)
+			}
+		})
+	}
+}
+
+func TestCustomItemHandler_Delete(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "success", userID: "user-123", expectedStatus: http.StatusOK},
+		{name: "unauthorized - no user ID", userID: "", expectedStatus: http.StatusUnauthorized},
+		{name: "not found", userID: "user-123", mockError: services.ErrCustomItemNotFound, expectedStatus: http.StatusNotFound},
+		{name: "service error", userID: "user-123", mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.MockCustomItemService{
+				DeleteFunc: func(ctx context.Context, userID, uniqueName string) error {
+					return tt.mockError
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/custom-items/Custom/abc", nil)
+			rec := httptest.NewRecorder()
+			newCustomItemRouter(service, tt.userID).ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
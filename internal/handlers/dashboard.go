@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+	"github.com/graytonio/warframe-wishlist/internal/middleware"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"github.com/graytonio/warframe-wishlist/pkg/response"
+)
+
+type DashboardHandler struct {
+	dashboardService services.DashboardServiceInterface
+}
+
+func NewDashboardHandler(dashboardService services.DashboardServiceInterface) *DashboardHandler {
+	return &DashboardHandler{dashboardService: dashboardService}
+}
+
+// GetDashboard returns the caller's home screen: a wishlist summary, top
+// items, material highlights, foundry timers and alerts.
+func (h *DashboardHandler) GetDashboard(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: GetDashboard called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: GetDashboard - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	dashboard, err := h.dashboardService.GetDashboard(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "handler: GetDashboard - failed to build dashboard", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to build dashboard")
+		return
+	}
+
+	logger.Info(ctx, "handler: GetDashboard - success", "itemCount", dashboard.Wishlist.Items, "degraded", dashboard.IsDegraded())
+	response.JSON(w, http.StatusOK, dto.NewDashboard(dashboard))
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+func TestDashboardHandler_GetDashboard(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "success", userID: "user-123", expectedStatus: http.StatusOK},
+		{name: "unauthorized - no user ID", userID: "", expectedStatus: http.StatusUnauthorized},
+		{name: "service error", userID: "user-123", mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.MockDashboardService{
+				GetDashboardFunc: func(ctx context.Context, userID string) (*models.Dashboard, error) {
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					dashboard := &models.Dashboard{
+						Wishlist:  models.WishlistSummary{Items: 2, Quantity: 3, Completion: 25},
+						TopItems:  []models.ExpandedWishlistItem{{WishlistItem: models.WishlistItem{UniqueName: "/Lotus/Forma", Quantity: 2}}},
+						Materials: models.MaterialHighlights{Highlights: []models.MaterialRequirement{{Name: "Alloy Plate", Remaining: 200}}, Outstanding: 1, TotalCredits: 15000},
+						Foundry:   models.Foundry{Builds: []models.FoundryBuildStatus{}},
+						Alerts:    []models.Opportunity{{WorldStateEvent: models.WorldStateEvent{ID: "alert-1", Kind: models.WorldStateAlert}}},
+					}
+					dashboard.MarkDegraded(models.SectionDashboardFoundry, "foundry unavailable")
+					return dashboard, nil
+				},
+			}
+			handler := NewDashboardHandler(service)
+
+			req := createAuthenticatedRequest(http.MethodGet, "/api/v1/dashboard", nil, tt.userID)
+			rec := httptest.NewRecorder()
+			handler.GetDashboard(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var body dto.Dashboard
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if body.Wishlist.Items != 2 || len(body.TopItems) != 1 || body.TopItems[0].UniqueName != "/Lotus/Forma" {
+				t.Errorf("unexpected wishlist %+v %+v", body.Wishlist, body.TopItems)
+			}
+			if len(body.Materials.Highlights) != 1 || body.Materials.Credits.Value != 15000 {
+				t.Errorf("unexpected materials %+v", body.Materials)
+			}
+			if body.Foundry.Builds == nil || len(body.Alerts) != 1 || body.Alerts[0].ID != "alert-1" {
+				t.Errorf("unexpected foundry or alerts %+v %+v", body.Foundry, body.Alerts)
+			}
+			if len(body.Degraded) != 1 || body.Degraded[0].Section != models.SectionDashboardFoundry {
+				t.Errorf("expected the degraded foundry, got %+v", body.Degraded)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/audit"
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"github.com/graytonio/warframe-wishlist/pkg/response"
+)
+
+type DataSyncHandler struct {
+	dataSyncService services.DataSyncServiceInterface
+	token           string
+}
+
+// NewDataSyncHandler returns the handler for the post-sync webhook. Callers
+// must present token as a bearer token.
+func NewDataSyncHandler(dataSyncService services.DataSyncServiceInterface, token string) *DataSyncHandler {
+	return &DataSyncHandler{
+		dataSyncService: dataSyncService,
+		token:           token,
+	}
+}
+
+// Notify is called by sync.sh once the WFCD data has been written. The body may
+// name the new data version; without one the current UTC time is used. Hooks
+// run in the background so the sync script is not held up by cache warming.
+func (h *DataSyncHandler) Notify(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: DataSyncNotify called")
+
+	if !validBearerToken(r, h.token) {
+		logger.Warn(ctx, "handler: DataSyncNotify - invalid sync token")
+		audit.RecordRequest(r, audit.TypeAuthFailure, audit.OutcomeFailure, "invalid sync token", "")
+		response.ErrorCode(w, http.StatusUnauthorized, response.CodeInvalidSyncToken, "invalid sync token")
+		return
+	}
+
+	var req dto.DataSyncRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		logger.Warn(ctx, "handler: DataSyncNotify - invalid request body", "error", err)
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	version := strings.TrimSpace(req.Version)
+	if version == "" {
+		version = time.Now().UTC().Format(services.DataVersionLayout)
+	}
+
+	go func(ctx context.Context) {
+		if err := h.dataSyncService.NotifySynced(ctx, version); err != nil {
+			logger.Error(ctx, "handler: DataSyncNotify - sync hooks failed", "error", err)
+		}
+	}(context.WithoutCancel(ctx))
+
+	logger.Info(ctx, "handler: DataSyncNotify - accepted", "version", version)
+	audit.RecordRequest(r, audit.TypeDataSync, audit.OutcomeSuccess, "data version "+version, "")
+	response.JSON(w, http.StatusAccepted, map[string]string{
+		"message": "data sync accepted",
+	})
+}
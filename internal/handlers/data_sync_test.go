@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type mockDataSyncService struct {
+	versionFunc      func() string
+	notifySyncedFunc func(ctx context.Context, version string) error
+	flushFunc        func(ctx context.Context) error
+}
+
+func (m *mockDataSyncService) Version() string {
+	if m.versionFunc != nil {
+		return m.versionFunc()
+	}
+	return ""
+}
+
+func (m *mockDataSyncService) NotifySynced(ctx context.Context, version string) error {
+	if m.notifySyncedFunc != nil {
+		return m.notifySyncedFunc(ctx, version)
+	}
+	return nil
+}
+
+func (m *mockDataSyncService) Flush(ctx context.Context) error {
+	if m.flushFunc != nil {
+		return m.flushFunc(ctx)
+	}
+	return nil
+}
+
+func TestDataSyncHandler_Notify(t *testing.T) {
+	tests := []struct {
+		name            string
+		token           string
+		authHeader      string
+		body            string
+		expectedStatus  int
+		expectNotify    bool
+		expectedVersion string
+	}{
+		{
+			name:           "valid token",
+			token:          "sync-secret",
+			authHeader:     "Bearer sync-secret",
+			expectedStatus: http.StatusAccepted,
+			expectNotify:   true,
+		},
+		{
+			name:            "valid token with version",
+			token:           "sync-secret",
+			authHeader:      "Bearer sync-secret",
+			body:            `{"version":"2026-10-17"}`,
+			expectedStatus:  http.StatusAccepted,
+			expectNotify:    true,
+			expectedVersion: "2026-10-17",
+		},
+		{
+			name:           "invalid body",
+			token:          "sync-secret",
+			authHeader:     "Bearer sync-secret",
+			body:           `{"version":`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing header",
+			token:          "sync-secret",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "wrong token",
+			token:          "sync-secret",
+			authHeader:     "Bearer nope",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "wrong scheme",
+			token:          "sync-secret",
+			authHeader:     "Basic sync-secret",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "unconfigured token rejects empty bearer",
+			token:          "",
+			authHeader:     "Bearer ",
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notified := make(chan string, 1)
+			mockService := &mockDataSyncService{
+				notifySyncedFunc: func(ctx context.Context, version string) error {
+					notified <- version
+					return nil
+				},
+			}
+
+			handler := NewDataSyncHandler(mockService, tt.token)
+			req := httptest.NewRequest(http.MethodPost, "/internal/data-sync", strings.NewReader(tt.body))
+			if tt.authHeader != "" {
+				req.Header.Set("Authorization", tt.authHeader)
+			}
+			rr := httptest.NewRecorder()
+
+			handler.Notify(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+
+			select {
+			case version := <-notified:
+				if !tt.expectNotify {
+					t.Error("expected sync hooks not to run")
+				}
+				if tt.expectedVersion != "" && version != tt.expectedVersion {
+					t.Errorf("expected version %q, got %q", tt.expectedVersion, version)
+				}
+				if version == "" {
+					t.Error("expected a non-empty data version")
+				}
+			case <-time.After(100 * time.Millisecond):
+				if tt.expectNotify {
+					t.Error("expected sync hooks to run")
+				}
+			}
+		})
+	}
+}
+
+func TestDataSyncHandler_Notify_SurvivesRequestCancellation(t *testing.T) {
+	done := make(chan error, 1)
+	mockService := &mockDataSyncService{
+		notifySyncedFunc: func(ctx context.Context, version string) error {
+			done <- ctx.Err()
+			return nil
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req := httptest.NewRequest(http.MethodPost, "/internal/data-sync", nil).WithContext(ctx)
+	req.Header.Set("Authorization", "Bearer sync-secret")
+
+	NewDataSyncHandler(mockService, "sync-secret").Notify(httptest.NewRecorder(), req)
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("expected hook context to outlive the request, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected sync hooks to run")
+	}
+}
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/graytonio/warframe-wishlist/internal/deprecation"
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"github.com/graytonio/warframe-wishlist/pkg/response"
+)
+
+// DeprecationHandler serves the admin route reporting which clients still
+// use deprecated fields and parameters. main restricts it to admins.
+type DeprecationHandler struct {
+	tracker *deprecation.Tracker
+}
+
+// NewDeprecationHandler returns the handler for the deprecation usage route.
+func NewDeprecationHandler(tracker *deprecation.Tracker) *DeprecationHandler {
+	return &DeprecationHandler{
+		tracker: tracker,
+	}
+}
+
+func (h *DeprecationHandler) Usage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: DeprecationUsage called")
+
+	response.JSON(w, http.StatusOK, dto.NewDeprecations(h.tracker.Usage()))
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/deprecation"
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+)
+
+func TestDeprecationHandler_Usage(t *testing.T) {
+	tracker, err := deprecation.NewTracker(deprecation.WishlistBareItems.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	use := httptest.NewRequest(http.MethodGet, "/api/v1/wishlist", nil)
+	use.Header.Set(deprecation.ClientIDHeader, "wfw-web/1.0")
+	tracker.Use(httptest.NewRecorder(), use, deprecation.WishlistBareItems)
+	handler := NewDeprecationHandler(tracker)
+
+	rec := httptest.NewRecorder()
+	handler.Usage(rec, httptest.NewRequest(http.MethodGet, "/api/v1/admin/deprecations", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	var body dto.Deprecations
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(body.Deprecations) != 1 || body.Deprecations[0].Total != 1 || body.Deprecations[0].Sunset != nil {
+		t.Fatalf("unexpected deprecations %+v", body.Deprecations)
+	}
+	if clients := body.Deprecations[0].Clients; len(clients) != 1 || clients[0].ClientID != "wfw-web/1.0" || clients[0].Count != 1 {
+		t.Errorf("unexpected clients %+v", clients)
+	}
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/graytonio/warframe-wishlist/internal/inventory"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/realtime"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+	"github.com/graytonio/warframe-wishlist/pkg/response"
+)
+
+// errorCodes gives each error whose message handlers pass on to clients its
+// code. The first entry the error matches wins.
+var errorCodes = []struct {
+	err  error
+	code response.Code
+}{
+	{models.ErrUniqueNameRequired, response.CodeUniqueNameRequired},
+	{models.ErrInvalidUniqueName, response.CodeInvalidUniqueName},
+	{inventory.ErrUnsupportedFormat, response.CodeUnsupportedFormat},
+	{inventory.ErrInvalidInventory, response.CodeInvalidInventory},
+	{realtime.ErrNotWebSocket, response.CodeNotWebSocket},
+
+	{services.ErrItemNotFound, response.CodeItemNotFound},
+	{services.ErrUnknownCategory, response.CodeUnknownCategory},
+	{services.ErrItemNotInWishlist, response.CodeItemNotInWishlist},
+	{services.ErrItemAlreadyInWishlist, response.CodeItemAlreadyInWishlist},
+	{services.ErrItemAlreadyOwned, response.CodeItemAlreadyOwned},
+	{services.ErrInvalidQuantity, response.CodeInvalidQuantity},
+	{services.ErrRecipeNotFound, response.CodeRecipeNotFound},
+	{services.ErrWishlistFull, response.CodeWishlistFull},
+	{services.ErrInvalidProgress, response.CodeInvalidProgress},
+	{services.ErrInvalidLink, response.CodeInvalidLink},
+	{services.ErrTooManyLinks, response.CodeTooManyLinks},
+	{services.ErrApprovalRequired, response.CodeApprovalRequired},
+	{services.ErrPublicWishlistNotFound, response.CodePublicWishlistNotFound},
+	{services.ErrItemRefreshRunning, response.CodeItemRefreshRunning},
+	{services.ErrItemRefreshUnavailable, response.CodeItemRefreshUnavailable},
+	{services.ErrInvalidResearchRequest, response.CodeInvalidResearchRequest},
+	{services.ErrInvalidTimeZone, response.CodeInvalidTimeZone},
+	{services.ErrInvalidDefaultQuantities, response.CodeInvalidDefaultQuantities},
+	{services.ErrInvalidMilestones, response.CodeInvalidMilestones},
+	{services.ErrInvalidMaterialsRounding, response.CodeInvalidMaterialsRounding},
+
+	{services.ErrBlueprintNotFound, response.CodeBlueprintNotFound},
+	{services.ErrBlueprintNotReusable, response.CodeBlueprintNotReusable},
+	{services.ErrBlueprintAlreadyOwned, response.CodeBlueprintAlreadyOwned},
+	{services.ErrBlueprintNotOwned, response.CodeBlueprintNotOwned},
+	{services.ErrInvalidBlueprintQuantity, response.CodeInvalidBlueprintQuantity},
+	{services.ErrTooManyBlueprints, response.CodeTooManyBlueprints},
+	{services.ErrComponentNotOwned, response.CodeComponentNotOwned},
+	{services.ErrInvalidComponentCount, response.CodeInvalidComponentCount},
+	{services.ErrMaterialNotOwned, response.CodeMaterialNotOwned},
+	{services.ErrInvalidMaterialCount, response.CodeInvalidMaterialCount},
+	{services.ErrTooManyMaterials, response.CodeTooManyMaterials},
+	{services.ErrItemNotInProfile, response.CodeItemNotInMasteryProfile},
+	{services.ErrInvalidMasteryStatus, response.CodeInvalidMasteryStatus},
+	{services.ErrTooManyMasteryItems, response.CodeTooManyMasteryItems},
+	{services.ErrInvalidFoundryBuild, response.CodeInvalidFoundryBuild},
+	{services.ErrFoundryBuildNotFound, response.CodeFoundryBuildNotFound},
+	{services.ErrTooManyFoundryBuilds, response.CodeTooManyFoundryBuilds},
+	{services.ErrInvalidWishlistTemplate, response.CodeInvalidWishlistTemplate},
+	{services.ErrInvalidTemplateVariables, response.CodeInvalidTemplateVariables},
+	{services.ErrWishlistTemplateNotFound, response.CodeWishlistTemplateNotFound},
+	{services.ErrTooManyWishlistTemplates, response.CodeTooManyWishlistTemplates},
+	{services.ErrInvalidCustomItem, response.CodeInvalidCustomItem},
+	{services.ErrCustomItemNotFound, response.CodeCustomItemNotFound},
+	{services.ErrTooManyCustomItems, response.CodeTooManyCustomItems},
+	{services.ErrInventoryTooLarge, response.CodeInventoryTooLarge},
+	{services.ErrImportEmpty, response.CodeImportEmpty},
+	{services.ErrImportTooLarge, response.CodeImportTooLarge},
+	{services.ErrInvalidExportDocument, response.CodeInvalidExportDocument},
+	{services.ErrUnsupportedExportVersion, response.CodeUnsupportedExportVersion},
+	{services.ErrInvalidImportMode, response.CodeInvalidImportMode},
+	{services.ErrExportTooLarge, response.CodeExportTooLarge},
+	{services.ErrMergeTooLarge, response.CodeMergeTooLarge},
+	{services.ErrWorkspaceMaterialNotNeeded, response.CodeWorkspaceMaterialNotNeeded},
+
+	{services.ErrShareLinkNotFound, response.CodeShareLinkNotFound},
+	{services.ErrInvalidShareLinkLabel, response.CodeInvalidShareLinkLabel},
+	{services.ErrTooManyShareLinks, response.CodeTooManyShareLinks},
+	{services.ErrSharedMaterialsHidden, response.CodeSharedMaterialsHidden},
+	{services.ErrWishlistNotShared, response.CodeWishlistNotShared},
+	{services.ErrCannotClaimOwnItem, response.CodeCannotClaimOwnItem},
+	{services.ErrInvalidClaimDays, response.CodeInvalidClaimDays},
+	{services.ErrItemAlreadyClaimed, response.CodeItemAlreadyClaimed},
+	{services.ErrClaimNotFound, response.CodeClaimNotFound},
+	{services.ErrCannotManageSelf, response.CodeCannotManageSelf},
+	{services.ErrInvalidThreshold, response.CodeInvalidThreshold},
+	{services.ErrAlreadyLinked, response.CodeAlreadyLinked},
+	{services.ErrLinkNotFound, response.CodeHouseholdLinkNotFound},
+	{services.ErrLinkActive, response.CodeHouseholdLinkActive},
+	{services.ErrChangeNotFound, response.CodeChangeNotFound},
+	{services.ErrChangeAlreadyDecided, response.CodeChangeAlreadyDecided},
+
+	{services.ErrWorkspaceNotFound, response.CodeWorkspaceNotFound},
+	{services.ErrWorkspaceForbidden, response.CodeWorkspaceForbidden},
+	{services.ErrInvalidWorkspace, response.CodeInvalidWorkspace},
+	{services.ErrWorkspaceConflict, response.CodeWorkspaceConflict},
+	{services.ErrTooManyWorkspaces, response.CodeTooManyWorkspaces},
+	{services.ErrWorkspaceMemberExists, response.CodeWorkspaceMemberExists},
+	{services.ErrWorkspaceMemberNotFound, response.CodeWorkspaceMemberNotFound},
+	{services.ErrTooManyWorkspaceMembers, response.CodeTooManyWorkspaceMembers},
+	{services.ErrLastWorkspaceAdmin, response.CodeLastWorkspaceAdmin},
+	{services.ErrWorkspaceItemExists, response.CodeWorkspaceItemExists},
+	{services.ErrWorkspaceItemNotFound, response.CodeWorkspaceItemNotFound},
+	{services.ErrTooManyWorkspaceItems, response.CodeTooManyWorkspaceItems},
+
+	{services.ErrNotificationChannelNotFound, response.CodeNotificationChannelNotFound},
+	{services.ErrInvalidNotificationChannel, response.CodeInvalidNotificationChannel},
+	{services.ErrTooManyNotificationChannels, response.CodeTooManyNotificationChannels},
+	{services.ErrPushNotConfigured, response.CodePushNotConfigured},
+	{services.ErrIntegrationNotFound, response.CodeIntegrationNotFound},
+	{services.ErrInvalidIntegration, response.CodeInvalidIntegration},
+	{services.ErrTooManyIntegrations, response.CodeTooManyIntegrations},
+	{services.ErrUserTraceNotFound, response.CodeUserTraceNotFound},
+	{services.ErrInvalidTraceDuration, response.CodeInvalidTraceDuration},
+	{services.ErrTraceReasonTooLong, response.CodeTraceReasonTooLong},
+}
+
+// errorCode returns err's code, or "" to have it coded by its status.
+func errorCode(err error) response.Code {
+	for _, entry := range errorCodes {
+		if errors.Is(err, entry.err) {
+			return entry.code
+		}
+	}
+	return ""
+}
+
+// rejected writes err, a service's reason for turning the request down, with
+// its code.
+func rejected(w http.ResponseWriter, statusCode int, err error) {
+	response.ErrorCode(w, statusCode, errorCode(err), err.Error())
+}
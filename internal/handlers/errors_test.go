@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/services"
+	"github.com/graytonio/warframe-wishlist/pkg/response"
+)
+
+func TestErrorCodes_Unique(t *testing.T) {
+	seen := map[response.Code]error{}
+	for _, entry := range errorCodes {
+		if other, ok := seen[entry.code]; ok {
+			t.Errorf("code %q is used by %q and %q", entry.code, other, entry.err)
+		}
+		seen[entry.code] = entry.err
+	}
+}
+
+func TestRejected(t *testing.T) {
+	tests := []struct {
+		name            string
+		err             error
+		acceptLanguage  string
+		expectedCode    response.Code
+		expectedMessage string
+	}{
+		{name: "coded", err: services.ErrWorkspaceNotFound, expectedCode: response.CodeWorkspaceNotFound, expectedMessage: "workspace not found"},
+		{name: "wrapped", err: fmt.Errorf("%w: bad url", services.ErrInvalidIntegration), expectedCode: response.CodeInvalidIntegration, expectedMessage: "invalid integration: bad url"},
+		{name: "catalogued", err: services.ErrItemNotFound, acceptLanguage: "de", expectedCode: response.CodeItemNotFound, expectedMessage: "Gegenstand nicht gefunden"},
+		{name: "uncoded", err: errors.New("something else"), expectedCode: "bad_request", expectedMessage: "something else"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Accept-Language", tt.acceptLanguage)
+			rec := httptest.NewRecorder()
+			response.Localize(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				rejected(w, http.StatusBadRequest, tt.err)
+			})).ServeHTTP(rec, req)
+
+			var body response.ErrorResponse
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatalf("failed to decode body: %v", err)
+			}
+			if rec.Code != http.StatusBadRequest || body.Code != tt.expectedCode || body.Message != tt.expectedMessage {
+				t.Errorf("expected %q %q, got %d %+v", tt.expectedCode, tt.expectedMessage, rec.Code, body)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+	"github.com/graytonio/warframe-wishlist/internal/middleware"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"github.com/graytonio/warframe-wishlist/pkg/response"
+)
+
+type FarmingPlanHandler struct {
+	farmingPlanner services.FarmingPlannerInterface
+}
+
+func NewFarmingPlanHandler(farmingPlanner services.FarmingPlannerInterface) *FarmingPlanHandler {
+	return &FarmingPlanHandler{farmingPlanner: farmingPlanner}
+}
+
+// GetFarmingPlan ranks the locations dropping the caller's outstanding
+// materials; ?limit caps how many are returned.
+func (h *FarmingPlanHandler) GetFarmingPlan(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: GetFarmingPlan called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: GetFarmingPlan - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	plan, err := h.farmingPlanner.GetFarmingPlan(ctx, userID, limit)
+	if err != nil {
+		logger.Error(ctx, "handler: GetFarmingPlan - failed to build farming plan", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to build farming plan")
+		return
+	}
+
+	logger.Info(ctx, "handler: GetFarmingPlan - success", "locationCount", len(plan.Locations), "unlocatedCount", len(plan.Unlocated))
+	response.JSON(w, http.StatusOK, dto.NewFarmingPlan(plan))
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+func TestFarmingPlanHandler_GetFarmingPlan(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		target         string
+		mockError      error
+		expectedStatus int
+		expectedLimit  int
+	}{
+		{name: "success", userID: "user-123", target: "/api/v1/wishlist/farming-plan", expectedStatus: http.StatusOK},
+		{name: "limit", userID: "user-123", target: "/api/v1/wishlist/farming-plan?limit=5", expectedStatus: http.StatusOK, expectedLimit: 5},
+		{name: "unauthorized - no user ID", userID: "", target: "/api/v1/wishlist/farming-plan", expectedStatus: http.StatusUnauthorized},
+		{name: "service error", userID: "user-123", target: "/api/v1/wishlist/farming-plan", mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotLimit int
+			handler := NewFarmingPlanHandler(&mocks.MockFarmingPlanner{
+				GetFarmingPlanFunc: func(ctx context.Context, userID string, limit int) (*models.FarmingPlan, error) {
+					gotLimit = limit
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return &models.FarmingPlan{
+						Locations: []models.FarmingLocation{{
+							Location:  "Venus/Kiliken",
+							Materials: []models.FarmingMaterial{{UniqueName: "/Lotus/Alloy", Name: "Alloy Plate", Remaining: 500, Chance: 0.3, Best: true}},
+							BestFor:   1,
+						}},
+						Unlocated: []models.MaterialRequirement{{UniqueName: "/Lotus/Cell", Name: "Orokin Cell", Remaining: 2}},
+					}, nil
+				},
+			})
+
+			req := createAuthenticatedRequest(http.MethodGet, tt.target, nil, tt.userID)
+			rec := httptest.NewRecorder()
+			handler.GetFarmingPlan(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			if gotLimit != tt.expectedLimit {
+				t.Errorf("expected limit %d, got %d", tt.expectedLimit, gotLimit)
+			}
+			var body dto.FarmingPlan
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if len(body.Locations) != 1 || body.Locations[0].Materials[0].Chance != 0.3 || !body.Locations[0].Materials[0].Best {
+				t.Errorf("unexpected locations %+v", body.Locations)
+			}
+			if len(body.Unlocated) != 1 || body.Unlocated[0].UniqueName != "/Lotus/Cell" {
+				t.Errorf("unexpected unlocated %+v", body.Unlocated)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+	"github.com/graytonio/warframe-wishlist/internal/middleware"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"github.com/graytonio/warframe-wishlist/pkg/response"
+)
+
+type FoundryHandler struct {
+	foundryService services.FoundryServiceInterface
+}
+
+func NewFoundryHandler(foundryService services.FoundryServiceInterface) *FoundryHandler {
+	return &FoundryHandler{foundryService: foundryService}
+}
+
+// GetFoundry lists the caller's builds with the time each still needs.
+func (h *FoundryHandler) GetFoundry(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: GetFoundry called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: GetFoundry - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	foundry, err := h.foundryService.GetFoundry(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "handler: GetFoundry - failed to get foundry", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to get foundry")
+		return
+	}
+
+	logger.Info(ctx, "handler: GetFoundry - success", "count", len(foundry.Builds), "finished", foundry.Finished)
+	response.JSON(w, http.StatusOK, dto.NewFoundry(foundry))
+}
+
+func (h *FoundryHandler) StartBuild(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: StartFoundryBuild called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: StartFoundryBuild - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	var req dto.FoundryBuildRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn(ctx, "handler: StartFoundryBuild - invalid request body", "error", err)
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	uniqueName, err := models.CanonicalUniqueName(req.UniqueName)
+	if err != nil {
+		logger.Warn(ctx, "handler: StartFoundryBuild - invalid uniqueName", "error", err)
+		rejected(w, http.StatusBadRequest, err)
+		return
+	}
+	req.UniqueName = uniqueName
+
+	build, err := h.foundryService.StartBuild(ctx, userID, req.ToModel())
+	if err != nil {
+		writeFoundryError(w, r, "StartFoundryBuild", err, "failed to start build")
+		return
+	}
+
+	logger.Info(ctx, "handler: StartFoundryBuild - success", "uniqueName", uniqueName)
+	response.JSON(w, http.StatusCreated, dto.NewFoundryBuild(build))
+}
+
+// RemoveBuild stops tracking a build, once claimed or cancelled.
+func (h *FoundryHandler) RemoveBuild(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: RemoveFoundryBuild called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: RemoveFoundryBuild - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	buildID := chi.URLParam(r, "buildID")
+	if err := h.foundryService.RemoveBuild(ctx, userID, buildID); err != nil {
+		writeFoundryError(w, r, "RemoveFoundryBuild", err, "failed to remove build")
+		return
+	}
+
+	logger.Info(ctx, "handler: RemoveFoundryBuild - success", "id", buildID)
+	response.JSON(w, http.StatusOK, map[string]string{
+		"message": "build removed",
+	})
+}
+
+func writeFoundryError(w http.ResponseWriter, r *http.Request, name string, err error, fallback string) {
+	ctx := r.Context()
+
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrInvalidFoundryBuild):
+		status = http.StatusBadRequest
+	case errors.Is(err, services.ErrFoundryBuildNotFound), errors.Is(err, services.ErrItemNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrTooManyFoundryBuilds):
+		status = http.StatusConflict
+	}
+
+	if status == http.StatusInternalServerError {
+		logger.Error(ctx, "handler: "+name+" - "+fallback, "error", err)
+		response.Error(w, status, fallback)
+		return
+	}
+	logger.Warn(ctx, "handler: "+name+" - request rejected", "error", err)
+	rejected(w, status, err)
+}
@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/graytonio/warframe-wishlist/internal/middleware"
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+)
+
+// newFoundryRouter mounts the foundry routes as main does, with userID
+// injected in place of the auth middleware.
+func newFoundryRouter(service services.FoundryServiceInterface, userID string) http.Handler {
+	handler := NewFoundryHandler(service)
+	r := chi.NewRouter()
+	r.Route("/api/v1/profile/foundry", func(r chi.Router) {
+		r.Use(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ctx := context.WithValue(r.Context(), middleware.UserIDKey, userID)
+				next.ServeHTTP(w, r.WithContext(ctx))
+			})
+		})
+		r.Get("/", handler.GetFoundry)
+		r.Post("/", handler.StartBuild)
+		r.Delete("/{buildID}", handler.RemoveBuild)
+	})
+	return r
+}
+
+func TestFoundryHandler_GetFoundry(t *testing.T) {
+	startedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		userID         string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "success", userID: "user-123", expectedStatus: http.StatusOK},
+		{name: "unauthorized - no user ID", userID: "", expectedStatus: http.StatusUnauthorized},
+		{name: "service error", userID: "user-123", mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.MockFoundryService{
+				GetFoundryFunc: func(ctx context.Context, userID string) (*models.Foundry, error) {
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					build := models.FoundryBuild{UniqueName: "/Lotus/Forma", Name: "Forma", BuildTime: 86400, StartedAt: startedAt}
+					return &models.Foundry{
+						Builds:   []models.FoundryBuildStatus{models.NewFoundryBuildStatus(build, startedAt.Add(23*time.Hour))},
+						Finished: 0,
+					}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/profile/foundry", nil)
+			rec := httptest.NewRecorder()
+			newFoundryRouter(service, tt.userID).ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			var body struct {
+				Builds []struct {
+					Remaining struct {
+						Seconds int `json:"seconds"`
+					} `json:"remaining"`
+					ReadyAt  time.Time `json:"readyAt"`
+					Finished bool      `json:"finished"`
+				} `json:"builds"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if len(body.Builds) != 1 || body.Builds[0].Remaining.Seconds != 3600 || body.Builds[0].Finished {
+				t.Errorf("expected one build with an hour to go, got %+v", body.Builds)
+			}
+			if !body.Builds[0].ReadyAt.Equal(startedAt.Add(24 * time.Hour)) {
+				t.Errorf("expected readyAt a day after the start, got %v", body.Builds[0].ReadyAt)
+			}
+		})
+	}
+}
+
+func TestFoundryHandler_StartBuild(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "started now", body: `{"uniqueName":"/Lotus/Forma"}`, expectedStatus: http.StatusCreated},
+		{name: "started earlier", body: `{"uniqueName":"/Lotus/Forma","startedAt":"2026-10-01T12:00:00Z"}`, expectedStatus: http.StatusCreated},
+		{name: "invalid body", body: `{`, expectedStatus: http.StatusBadRequest},
+		{name: "missing uniqueName", body: `{}`, expectedStatus: http.StatusBadRequest},
+		{name: "not buildable", body: `{"uniqueName":"/Lotus/Ferrite"}`, mockError: services.ErrInvalidFoundryBuild, expectedStatus: http.StatusBadRequest},
+		{name: "unknown item", body: `{"uniqueName":"/Lotus/Missing"}`, mockError: services.ErrItemNotFound, expectedStatus: http.StatusNotFound},
+		{name: "too many builds", body: `{"uniqueName":"/Lotus/Forma"}`, mockError: services.ErrTooManyFoundryBuilds, expectedStatus: http.StatusConflict},
+		{name: "service error", body: `{"uniqueName":"/Lotus/Forma"}`, mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got models.FoundryBuildRequest
+			service := &mocks.MockFoundryService{
+				StartBuildFunc: func(ctx context.Context, userID string, req models.FoundryBuildRequest) (*models.FoundryBuildStatus, error) {
+					got = req
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return &models.FoundryBuildStatus{FoundryBuild: models.FoundryBuild{UserID: userID, UniqueName: req.UniqueName}}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/profile/foundry", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			newFoundryRouter(service, "user-123").ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if tt.name == "started earlier" && (got.StartedAt == nil || !got.StartedAt.Equal(time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC))) {
+				t.Errorf("expected startedAt to be passed through, got %v", got.StartedAt)
+			}
+			if tt.name == "started now" && got.StartedAt != nil {
+				t.Errorf("expected no startedAt, got %v", got.StartedAt)
+			}
+		})
+	}
+}
+
+func TestFoundryHandler_RemoveBuild(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "removed", expectedStatus: http.StatusOK},
+		{name: "not found", mockError: services.ErrFoundryBuildNotFound, expectedStatus: http.StatusNotFound},
+		{name: "service error", mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotID string
+			service := &mocks.MockFoundryService{
+				RemoveBuildFunc: func(ctx context.Context, userID, id string) error {
+					gotID = id
+					return tt.mockError
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/profile/foundry/build-1", nil)
+			rec := httptest.NewRecorder()
+			newFoundryRouter(service, "user-123").ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if gotID != "build-1" {
+				t.Errorf("expected build-1, got %q", gotID)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+	"github.com/graytonio/warframe-wishlist/internal/middleware"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"github.com/graytonio/warframe-wishlist/pkg/response"
+)
+
+type GiftClaimHandler struct {
+	giftClaimService services.GiftClaimServiceInterface
+}
+
+func NewGiftClaimHandler(giftClaimService services.GiftClaimServiceInterface) *GiftClaimHandler {
+	return &GiftClaimHandler{giftClaimService: giftClaimService}
+}
+
+func (h *GiftClaimHandler) GetSharedWishlist(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: GetSharedWishlist called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: GetSharedWishlist - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	wishlist, err := h.giftClaimService.GetSharedWishlist(ctx, userID, chi.URLParam(r, "userID"))
+	if err != nil {
+		writeGiftClaimError(w, r, "GetSharedWishlist", err, "failed to get wishlist")
+		return
+	}
+
+	logger.Info(ctx, "handler: GetSharedWishlist - success", "itemCount", len(wishlist.Items))
+	response.JSON(w, http.StatusOK, dto.NewSharedWishlist(wishlist))
+}
+
+func (h *GiftClaimHandler) Claim(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: Claim called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: Claim - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	var req dto.ClaimGiftRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn(ctx, "handler: Claim - invalid request body", "error", err)
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	claim, err := h.giftClaimService.Claim(ctx, userID, chi.URLParam(r, "userID"), req.ToModel())
+	if err != nil {
+		writeGiftClaimError(w, r, "Claim", err, "failed to claim item")
+		return
+	}
+
+	logger.Info(ctx, "handler: Claim - success", "uniqueName", claim.UniqueName)
+	response.JSON(w, http.StatusCreated, dto.NewGiftClaim(claim))
+}
+
+func (h *GiftClaimHandler) RevokeClaim(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: RevokeClaim called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: RevokeClaim - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	uniqueName, err := uniqueNameParam(r)
+	if err != nil {
+		logger.Warn(ctx, "handler: RevokeClaim - invalid uniqueName", "error", err)
+		rejected(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := h.giftClaimService.RevokeClaim(ctx, userID, chi.URLParam(r, "userID"), uniqueName); err != nil {
+		writeGiftClaimError(w, r, "RevokeClaim", err, "failed to revoke claim")
+		return
+	}
+
+	logger.Info(ctx, "handler: RevokeClaim - success", "uniqueName", uniqueName)
+	response.JSON(w, http.StatusOK, map[string]string{
+		"message": "claim revoked",
+	})
+}
+
+func (h *GiftClaimHandler) ListClaims(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: ListClaims called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: ListClaims - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	claims, err := h.giftClaimService.ListClaims(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "handler: ListClaims - failed to list claims", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to list claims")
+		return
+	}
+
+	logger.Info(ctx, "handler: ListClaims - success", "count", len(claims))
+	response.JSON(w, http.StatusOK, dto.NewGiftClaims(claims))
+}
+
+func writeGiftClaimError(w http.ResponseWriter, r *http.Request, name string, err error, fallback string) {
+	ctx := r.Context()
+
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrCannotClaimOwnItem), errors.Is(err, services.ErrInvalidClaimDays),
+		errors.Is(err, models.ErrUniqueNameRequired), errors.Is(err, models.ErrInvalidUniqueName):
+		status = http.StatusBadRequest
+	case errors.Is(err, services.ErrWishlistNotShared), errors.Is(err, services.ErrItemNotInWishlist),
+		errors.Is(err, services.ErrClaimNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrItemAlreadyClaimed):
+		status = http.StatusConflict
+	}
+
+	if status == http.StatusInternalServerError {
+		logger.Error(ctx, "handler: "+name+" - "+fallback, "error", err)
+		response.Error(w, status, fallback)
+		return
+	}
+	logger.Warn(ctx, "handler: "+name+" - request rejected", "error", err)
+	rejected(w, status, err)
+}
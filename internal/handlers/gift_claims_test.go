@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+	"github.com/graytonio/warframe-wishlist/internal/middleware"
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+)
+
+// newGiftClaimRouter mounts the gift claim routes as main does, injecting
+// userID in place of the auth middleware.
+func newGiftClaimRouter(service services.GiftClaimServiceInterface, userID string) http.Handler {
+	handler := NewGiftClaimHandler(service)
+
+	r := chi.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), middleware.UserIDKey, userID)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	})
+	r.Get("/users/{userID}/wishlist", handler.GetSharedWishlist)
+	r.Post("/users/{userID}/wishlist/claims", handler.Claim)
+	r.Delete("/users/{userID}/wishlist/claims/*", handler.RevokeClaim)
+	r.Get("/gift-claims", handler.ListClaims)
+	return r
+}
+
+func TestGiftClaimHandler_Unauthorized(t *testing.T) {
+	router := newGiftClaimRouter(&mocks.MockGiftClaimService{}, "")
+
+	routes := []struct{ method, path string }{
+		{http.MethodGet, "/users/owner/wishlist"},
+		{http.MethodPost, "/users/owner/wishlist/claims"},
+		{http.MethodDelete, "/users/owner/wishlist/claims/Lotus/Forma"},
+		{http.MethodGet, "/gift-claims"},
+	}
+
+	for _, route := range routes {
+		t.Run(route.method+" "+route.path, func(t *testing.T) {
+			req := httptest.NewRequest(route.method, route.path, bytes.NewReader([]byte("{}")))
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusUnauthorized {
+				t.Errorf("expected status %d, got %d", http.StatusUnauthorized, rec.Code)
+			}
+		})
+	}
+}
+
+func TestGiftClaimHandler_GetSharedWishlist(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "success", expectedStatus: http.StatusOK},
+		{name: "not shared", mockError: services.ErrWishlistNotShared, expectedStatus: http.StatusNotFound},
+		{name: "service error", mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotViewer, gotOwner string
+			service := &mocks.MockGiftClaimService{
+				GetSharedWishlistFunc: func(ctx context.Context, viewerID, ownerID string) (*models.SharedWishlist, error) {
+					gotViewer, gotOwner = viewerID, ownerID
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return &models.SharedWishlist{OwnerID: ownerID, Items: []models.SharedWishlistItem{
+						{UniqueName: "/Lotus/Forma", Quantity: 2, Claimed: true},
+					}}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/users/owner/wishlist", nil)
+			rec := httptest.NewRecorder()
+			newGiftClaimRouter(service, "user-123").ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if gotViewer != "user-123" || gotOwner != "owner" {
+				t.Errorf("expected viewer user-123 and owner owner, got %q and %q", gotViewer, gotOwner)
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var body dto.SharedWishlist
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if len(body.Items) != 1 || !body.Items[0].Claimed || body.Items[0].Claim != nil {
+				t.Errorf("unexpected response %+v", body)
+			}
+		})
+	}
+}
+
+func TestGiftClaimHandler_Claim(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "success", body: `{"uniqueName":"/Lotus/Forma","anonymous":true,"days":7}`, expectedStatus: http.StatusCreated},
+		{name: "invalid body", body: `{`, expectedStatus: http.StatusBadRequest},
+		{name: "own item", body: `{"uniqueName":"/Lotus/Forma"}`, mockError: services.ErrCannotClaimOwnItem, expectedStatus: http.StatusBadRequest},
+		{name: "invalid days", body: `{"uniqueName":"/Lotus/Forma","days":365}`, mockError: services.ErrInvalidClaimDays, expectedStatus: http.StatusBadRequest},
+		{name: "missing uniqueName", body: `{}`, mockError: models.ErrUniqueNameRequired, expectedStatus: http.StatusBadRequest},
+		{name: "not shared", body: `{"uniqueName":"/Lotus/Forma"}`, mockError: services.ErrWishlistNotShared, expectedStatus: http.StatusNotFound},
+		{name: "not in wishlist", body: `{"uniqueName":"/Lotus/Forma"}`, mockError: services.ErrItemNotInWishlist, expectedStatus: http.StatusNotFound},
+		{name: "already claimed", body: `{"uniqueName":"/Lotus/Forma"}`, mockError: services.ErrItemAlreadyClaimed, expectedStatus: http.StatusConflict},
+		{name: "service error", body: `{"uniqueName":"/Lotus/Forma"}`, mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotReq models.ClaimGiftRequest
+			service := &mocks.MockGiftClaimService{
+				ClaimFunc: func(ctx context.Context, claimerID, ownerID string, req models.ClaimGiftRequest) (*models.GiftClaim, error) {
+					gotReq = req
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return &models.GiftClaim{OwnerID: ownerID, UniqueName: req.UniqueName, ClaimerID: claimerID, Anonymous: req.Anonymous}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/users/owner/wishlist/claims", bytes.NewReader([]byte(tt.body)))
+			rec := httptest.NewRecorder()
+			newGiftClaimRouter(service, "user-123").ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if tt.expectedStatus == http.StatusCreated && (gotReq.UniqueName != "/Lotus/Forma" || !gotReq.Anonymous || gotReq.Days != 7) {
+				t.Errorf("unexpected request passed to service: %+v", gotReq)
+			}
+		})
+	}
+}
+
+func TestGiftClaimHandler_RevokeClaim(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "success", path: "/users/owner/wishlist/claims/Lotus/Forma", expectedStatus: http.StatusOK},
+		{name: "escaped uniqueName", path: "/users/owner/wishlist/claims/%2FLotus%2FForma", expectedStatus: http.StatusOK},
+		{name: "invalid uniqueName", path: "/users/owner/wishlist/claims/Lotus/../Forma", expectedStatus: http.StatusBadRequest},
+		{name: "not found", path: "/users/owner/wishlist/claims/Lotus/Forma", mockError: services.ErrClaimNotFound, expectedStatus: http.StatusNotFound},
+		{name: "service error", path: "/users/owner/wishlist/claims/Lotus/Forma", mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotCaller, gotOwner, gotUniqueName string
+			service := &mocks.MockGiftClaimService{
+				RevokeClaimFunc: func(ctx context.Context, callerID, ownerID, uniqueName string) error {
+					gotCaller, gotOwner, gotUniqueName = callerID, ownerID, uniqueName
+					return tt.mockError
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodDelete, tt.path, nil)
+			rec := httptest.NewRecorder()
+			newGiftClaimRouter(service, "user-123").ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if tt.expectedStatus == http.StatusOK && (gotCaller != "user-123" || gotOwner != "owner" || gotUniqueName != "/Lotus/Forma") {
+				t.Errorf("unexpected arguments %q, %q, %q", gotCaller, gotOwner, gotUniqueName)
+			}
+		})
+	}
+}
+
+func TestGiftClaimHandler_ListClaims(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "success", expectedStatus: http.StatusOK},
+		{name: "service error", mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.MockGiftClaimService{
+				ListClaimsFunc: func(ctx context.Context, claimerID string) ([]models.GiftClaim, error) {
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return []models.GiftClaim{{OwnerID: "owner", UniqueName: "/Lotus/Forma", ClaimerID: claimerID}}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/gift-claims", nil)
+			rec := httptest.NewRecorder()
+			newGiftClaimRouter(service, "user-123").ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var body []dto.GiftClaim
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if len(body) != 1 || body[0].ClaimerID != "user-123" {
+				t.Errorf("unexpected response %+v", body)
+			}
+		})
+	}
+}
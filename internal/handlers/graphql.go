@@ -0,0 +1,419 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+	"github.com/graytonio/warframe-wishlist/internal/graphql"
+	"github.com/graytonio/warframe-wishlist/internal/middleware"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"github.com/graytonio/warframe-wishlist/pkg/response"
+)
+
+// GraphQLHandler serves a read-only graph of items, the wishlist, owned
+// blueprints and resolved materials, so a client can fetch an item with its
+// components, drops and wishlist status in one request. Each request loads
+// the wishlist, owned blueprints and every item at most once, however many
+// fields refer to them.
+type GraphQLHandler struct {
+	itemService            services.ItemServiceInterface
+	wishlistService        services.WishlistServiceInterface
+	ownedBlueprintsService services.OwnedBlueprintsServiceInterface
+	materialResolver       services.MaterialResolverInterface
+	schema                 *graphql.Schema
+}
+
+func NewGraphQLHandler(itemService services.ItemServiceInterface, wishlistService services.WishlistServiceInterface, ownedBlueprintsService services.OwnedBlueprintsServiceInterface, materialResolver services.MaterialResolverInterface) *GraphQLHandler {
+	h := &GraphQLHandler{
+		itemService:            itemService,
+		wishlistService:        wishlistService,
+		ownedBlueprintsService: ownedBlueprintsService,
+		materialResolver:       materialResolver,
+	}
+	schema, err := graphql.NewSchema(graphQLQuery())
+	if err != nil {
+		// The schema is static, so this is a programming error.
+		panic(err)
+	}
+	h.schema = schema
+	return h
+}
+
+// Query executes a GraphQL request. Like other GraphQL servers it answers
+// 200 whenever the body is a request, with any errors in the body's errors.
+func (h *GraphQLHandler) Query(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: GraphQL called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: GraphQL - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	var req graphql.Request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn(ctx, "handler: GraphQL - invalid request body", "error", err)
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	ctx = context.WithValue(ctx, graphQLLoaderKey{}, &graphQLLoader{h: h, userID: userID, items: make(map[string]*models.Item)})
+	resp := h.schema.Execute(ctx, req)
+
+	logger.Info(ctx, "handler: GraphQL - success", "operationName", req.OperationName, "errorCount", len(resp.Errors))
+	response.JSON(w, http.StatusOK, resp)
+}
+
+// Schema serves the schema in the GraphQL schema definition language.
+func (h *GraphQLHandler) Schema(w http.ResponseWriter, r *http.Request) {
+	logger.Debug(r.Context(), "handler: GraphQLSchema called")
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(h.schema.String()))
+}
+
+type graphQLLoaderKey struct{}
+
+// graphQLLoader fetches what a request's resolvers need, once per request.
+// Fields resolve one at a time, so it needs no locking.
+type graphQLLoader struct {
+	h      *GraphQLHandler
+	userID string
+
+	items    map[string]*models.Item
+	wishlist *models.Wishlist
+	owned    *models.OwnedBlueprints
+}
+
+func loaderFrom(ctx context.Context) *graphQLLoader {
+	return ctx.Value(graphQLLoaderKey{}).(*graphQLLoader)
+}
+
+// item returns the item named uniqueName, or nil when there is none.
+func (l *graphQLLoader) item(ctx context.Context, uniqueName string) (*models.Item, error) {
+	if item, ok := l.items[uniqueName]; ok {
+		return item, nil
+	}
+	item, err := l.h.itemService.GetByUniqueName(ctx, uniqueName)
+	if err != nil {
+		logger.Error(ctx, "handler: GraphQL - failed to get item", "error", err, "uniqueName", uniqueName)
+		return nil, graphQLError("failed to get item")
+	}
+	l.items[uniqueName] = item
+	return item, nil
+}
+
+func (l *graphQLLoader) getWishlist(ctx context.Context) (*models.Wishlist, error) {
+	if l.wishlist == nil {
+		wishlist, err := l.h.wishlistService.GetWishlist(ctx, l.userID)
+		if err != nil {
+			logger.Error(ctx, "handler: GraphQL - failed to get wishlist", "error", err)
+			return nil, graphQLError("failed to get wishlist")
+		}
+		l.wishlist = wishlist
+	}
+	return l.wishlist, nil
+}
+
+func (l *graphQLLoader) getOwned(ctx context.Context) (*models.OwnedBlueprints, error) {
+	if l.owned == nil {
+		owned, err := l.h.ownedBlueprintsService.GetOwnedBlueprints(ctx, l.userID)
+		if err != nil {
+			logger.Error(ctx, "handler: GraphQL - failed to get owned blueprints", "error", err)
+			return nil, graphQLError("failed to get owned blueprints")
+		}
+		l.owned = owned
+	}
+	return l.owned, nil
+}
+
+// graphQLError is a resolver error whose message clients see; the cause is
+// logged where it happens.
+type graphQLError string
+
+func (e graphQLError) Error() string { return string(e) }
+
+// itemField resolves an item from the uniqueName its source carries.
+func itemField(itemType *graphql.Object, uniqueName func(source any) string) *graphql.Field {
+	return &graphql.Field{
+		Name:        "item",
+		Description: "The full item; null for names not in the item data, such as custom items.",
+		Type:        itemType,
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			return dto.NewItemDetail(mustItem(loaderFrom(p.Context).item(p.Context, uniqueName(p.Source)))), nil
+		},
+	}
+}
+
+// mustItem drops the error of a failed item lookup; the lookup is logged
+// and the item reads as missing.
+func mustItem(item *models.Item, err error) *models.Item {
+	if err != nil {
+		return nil
+	}
+	return item
+}
+
+func nonNullList(of graphql.Type) graphql.Type {
+	return graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(of)))
+}
+
+// graphQLQuery defines the schema. Objects read the json fields of the dto
+// types; resolvers add the links between them.
+func graphQLQuery() *graphql.Object {
+	nonNullString := graphql.NewNonNull(graphql.String)
+	nonNullInt := graphql.NewNonNull(graphql.Int)
+	nonNullBoolean := graphql.NewNonNull(graphql.Boolean)
+	nonNullDateTime := graphql.NewNonNull(graphql.DateTime)
+
+	// Declared up front, as the types refer to each other.
+	itemType := &graphql.Object{Name: "Item"}
+	componentType := &graphql.Object{Name: "Component"}
+	wishlistItemType := &graphql.Object{Name: "WishlistItem"}
+
+	drop := &graphql.Object{Name: "Drop", Description: "Where an item or component drops.", Fields: []*graphql.Field{
+		{Name: "location", Type: nonNullString},
+		{Name: "type", Type: nonNullString},
+		{Name: "rarity", Type: nonNullString},
+		{Name: "chance", Type: graphql.NewNonNull(graphql.Float)},
+	}}
+
+	recipe := &graphql.Object{Name: "Recipe", Description: "An alternate way to craft an item.", Fields: []*graphql.Field{
+		{Name: "id", Type: graphql.NewNonNull(graphql.ID)},
+		{Name: "name", Type: nonNullString},
+		{Name: "buildPrice", Type: nonNullInt},
+		{Name: "buildTime", Type: nonNullInt},
+		{Name: "buildQuantity", Type: nonNullInt},
+		{Name: "components", Type: nonNullList(componentType)},
+	}}
+
+	wishlistEntry := &graphql.Field{
+		Name:        "wishlist",
+		Description: "The item's wishlist entry; null when it is not on the wishlist.",
+		Type:        wishlistItemType,
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			wishlist, err := loaderFrom(p.Context).getWishlist(p.Context)
+			if err != nil {
+				return nil, err
+			}
+			uniqueName := p.Source.(*dto.ItemDetail).UniqueName
+			for _, item := range wishlist.Items {
+				if item.UniqueName == uniqueName {
+					return dto.NewWishlistItem(item), nil
+				}
+			}
+			return nil, nil
+		},
+	}
+
+	itemType.Description = "An item of the game's item data."
+	itemType.Fields = []*graphql.Field{
+		{Name: "uniqueName", Type: nonNullString},
+		{Name: "name", Type: nonNullString},
+		{Name: "description", Type: nonNullString},
+		{Name: "type", Type: nonNullString},
+		{Name: "category", Type: nonNullString},
+		{Name: "imageName", Type: nonNullString},
+		{Name: "tradable", Type: nonNullBoolean},
+		{Name: "isPrime", Type: nonNullBoolean},
+		{Name: "masteryReq", Type: nonNullInt},
+		{Name: "buildPrice", Type: nonNullInt},
+		{Name: "buildTime", Type: nonNullInt},
+		{Name: "buildQuantity", Type: nonNullInt},
+		{Name: "wikiaUrl", Type: nonNullString},
+		{Name: "archived", Type: nonNullBoolean},
+		{Name: "archivedAt", Type: graphql.DateTime},
+		{Name: "components", Type: nonNullList(componentType)},
+		{Name: "alternateRecipes", Type: nonNullList(recipe)},
+		{Name: "drops", Type: nonNullList(drop)},
+		wishlistEntry,
+		{
+			Name:        "blueprintOwned",
+			Description: "Whether the user owns the item's blueprint.",
+			Type:        nonNullBoolean,
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				owned, err := loaderFrom(p.Context).getOwned(p.Context)
+				if err != nil {
+					return nil, err
+				}
+				uniqueName := p.Source.(*dto.ItemDetail).UniqueName
+				for _, bp := range owned.Blueprints {
+					if bp.UniqueName == uniqueName {
+						return true, nil
+					}
+				}
+				return false, nil
+			},
+		},
+	}
+
+	componentType.Description = "A part an item is crafted from."
+	componentType.Fields = []*graphql.Field{
+		{Name: "uniqueName", Type: nonNullString},
+		{Name: "name", Type: nonNullString},
+		{Name: "itemCount", Type: nonNullInt},
+		{Name: "isPrime", Type: nonNullBoolean},
+		{Name: "description", Type: nonNullString},
+		{Name: "imageName", Type: nonNullString},
+		{Name: "tradable", Type: nonNullBoolean},
+		{Name: "hasOwnPage", Type: nonNullBoolean},
+		{Name: "drops", Type: nonNullList(drop)},
+		{Name: "components", Type: nonNullList(componentType)},
+		{
+			Name:        "owned",
+			Description: "How many of the component the user has crafted.",
+			Type:        nonNullInt,
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				owned, err := loaderFrom(p.Context).getOwned(p.Context)
+				if err != nil {
+					return nil, err
+				}
+				uniqueName := p.Source.(dto.Component).UniqueName
+				for _, component := range owned.Components {
+					if component.UniqueName == uniqueName {
+						return component.Count, nil
+					}
+				}
+				return 0, nil
+			},
+		},
+		itemField(itemType, func(source any) string { return source.(dto.Component).UniqueName }),
+	}
+
+	wishlistItemType.Fields = []*graphql.Field{
+		{Name: "uniqueName", Type: nonNullString},
+		{Name: "quantity", Type: nonNullInt},
+		{Name: "addedAt", Type: nonNullDateTime},
+		{Name: "recipeId", Type: nonNullString},
+		{Name: "completion", Type: nonNullInt, Description: "Percentage of the item's components done."},
+		itemField(itemType, func(source any) string { return source.(dto.WishlistItem).UniqueName }),
+	}
+	wishlist := &graphql.Object{Name: "Wishlist", Fields: []*graphql.Field{
+		{Name: "items", Type: nonNullList(wishlistItemType)},
+		{Name: "createdAt", Type: nonNullDateTime},
+		{Name: "updatedAt", Type: nonNullDateTime},
+	}}
+
+	ownedBlueprint := &graphql.Object{Name: "OwnedBlueprint", Fields: []*graphql.Field{
+		{Name: "uniqueName", Type: nonNullString},
+		{Name: "quantity", Type: nonNullInt},
+		{Name: "addedAt", Type: nonNullDateTime},
+		itemField(itemType, func(source any) string { return source.(dto.OwnedBlueprint).UniqueName }),
+	}}
+	ownedComponent := &graphql.Object{Name: "OwnedComponent", Fields: []*graphql.Field{
+		{Name: "uniqueName", Type: nonNullString},
+		{Name: "count", Type: nonNullInt},
+		{Name: "addedAt", Type: nonNullDateTime},
+	}}
+	ownedBlueprints := &graphql.Object{Name: "OwnedBlueprints", Fields: []*graphql.Field{
+		{Name: "blueprints", Type: nonNullList(ownedBlueprint)},
+		{Name: "components", Type: nonNullList(ownedComponent)},
+	}}
+
+	materialRequirement := &graphql.Object{Name: "MaterialRequirement", Fields: []*graphql.Field{
+		{Name: "uniqueName", Type: nonNullString},
+		{Name: "name", Type: nonNullString},
+		{Name: "totalCount", Type: nonNullInt},
+		{Name: "owned", Type: nonNullInt},
+		{Name: "remaining", Type: nonNullInt},
+		{Name: "imageName", Type: nonNullString},
+		{Name: "description", Type: nonNullString},
+		itemField(itemType, func(source any) string { return source.(dto.MaterialRequirement).UniqueName }),
+	}}
+	materials := &graphql.Object{Name: "Materials", Description: "The raw materials the wishlist needs.", Fields: []*graphql.Field{
+		{Name: "materials", Type: nonNullList(materialRequirement)},
+		{Name: "totalCredits", Type: nonNullInt},
+		{Name: "rushPlatinum", Type: nonNullInt},
+		{Name: "truncated", Type: nonNullBoolean},
+		{Name: "truncatedReason", Type: nonNullString},
+	}}
+
+	itemSummary := &graphql.Object{Name: "ItemSummary", Fields: []*graphql.Field{
+		{Name: "uniqueName", Type: nonNullString},
+		{Name: "name", Type: nonNullString},
+		{Name: "description", Type: nonNullString},
+		{Name: "category", Type: nonNullString},
+		{Name: "imageName", Type: nonNullString},
+		{Name: "archived", Type: nonNullBoolean},
+		itemField(itemType, func(source any) string { return source.(dto.ItemSummary).UniqueName }),
+	}}
+	itemSearch := &graphql.Object{Name: "ItemSearch", Fields: []*graphql.Field{
+		{Name: "items", Type: nonNullList(itemSummary)},
+		{Name: "count", Type: nonNullInt},
+		{Name: "total", Type: nonNullInt},
+	}}
+
+	return &graphql.Object{Name: "Query", Fields: []*graphql.Field{
+		{
+			Name:        "item",
+			Description: "The item named uniqueName; null when there is none.",
+			Type:        itemType,
+			Args:        []*graphql.Argument{{Name: "uniqueName", Type: nonNullString}},
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				item, err := loaderFrom(p.Context).item(p.Context, p.Args["uniqueName"].(string))
+				return dto.NewItemDetail(item), err
+			},
+		},
+		{
+			Name:        "searchItems",
+			Description: "Items matching query, as GET /api/v1/items/search.",
+			Type:        graphql.NewNonNull(itemSearch),
+			Args: []*graphql.Argument{
+				{Name: "query", Type: graphql.String},
+				{Name: "category", Type: graphql.String},
+				{Name: "limit", Type: graphql.Int},
+				{Name: "offset", Type: graphql.Int},
+				{Name: "includeArchived", Type: graphql.Boolean, Default: false},
+			},
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				params := models.SearchParams{IncludeArchived: p.Args["includeArchived"].(bool)}
+				params.Query, _ = p.Args["query"].(string)
+				params.Category, _ = p.Args["category"].(string)
+				params.Limit, _ = p.Args["limit"].(int)
+				params.Offset, _ = p.Args["offset"].(int)
+				page, err := loaderFrom(p.Context).h.itemService.Search(p.Context, params)
+				if err != nil {
+					logger.Error(p.Context, "handler: GraphQL - failed to search items", "error", err)
+					return nil, graphQLError("failed to search items")
+				}
+				return dto.NewItemSearchResponse(page), nil
+			},
+		},
+		{
+			Name: "wishlist",
+			Type: graphql.NewNonNull(wishlist),
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				wishlist, err := loaderFrom(p.Context).getWishlist(p.Context)
+				return dto.NewWishlist(wishlist), err
+			},
+		},
+		{
+			Name: "ownedBlueprints",
+			Type: graphql.NewNonNull(ownedBlueprints),
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				owned, err := loaderFrom(p.Context).getOwned(p.Context)
+				return dto.NewOwnedBlueprints(owned), err
+			},
+		},
+		{
+			Name:        "materials",
+			Description: "The wishlist's resolved materials, as GET /api/v1/wishlist/materials.",
+			Type:        graphql.NewNonNull(materials),
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				l := loaderFrom(p.Context)
+				resolved, err := l.h.materialResolver.GetMaterials(p.Context, l.userID)
+				if err != nil {
+					logger.Error(p.Context, "handler: GraphQL - failed to get materials", "error", err)
+					return nil, graphQLError("failed to get materials")
+				}
+				return dto.NewMaterialsSummary(resolved), nil
+			},
+		},
+	}}
+}
@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+func newGraphQLTestHandler(wishlistCalls *int) *GraphQLHandler {
+	items := map[string]*models.Item{
+		"/Lotus/Powersuits/Volt/Volt": {
+			UniqueName: "/Lotus/Powersuits/Volt/Volt",
+			Name:       "Volt",
+			Category:   "Warframes",
+			Components: []models.Component{
+				{UniqueName: "/Lotus/Types/Recipes/VoltChassis", Name: "Chassis", ItemCount: 1},
+				{UniqueName: "/Lotus/Types/Items/MiscItems/Morphic", Name: "Morphics", ItemCount: 1},
+			},
+			Drops: []models.Drop{{Location: "Market", Type: "Blueprint", Rarity: "Common", Chance: 1}},
+		},
+		"/Lotus/Types/Items/MiscItems/Morphic": {UniqueName: "/Lotus/Types/Items/MiscItems/Morphic", Name: "Morphics"},
+	}
+	return NewGraphQLHandler(
+		&mocks.MockItemService{
+			GetByUniqueNameFunc: func(ctx context.Context, uniqueName string) (*models.Item, error) {
+				if uniqueName == "/broken" {
+					return nil, errors.New("database error")
+				}
+				return items[uniqueName], nil
+			},
+		},
+		&mocks.MockWishlistService{
+			GetWishlistFunc: func(ctx context.Context, userID string) (*models.Wishlist, error) {
+				if wishlistCalls != nil {
+					*wishlistCalls++
+				}
+				return &models.Wishlist{UserID: userID, Items: []models.WishlistItem{
+					{UniqueName: "/Lotus/Powersuits/Volt/Volt", Quantity: 2},
+				}}, nil
+			},
+		},
+		&mocks.MockOwnedBlueprintsService{
+			GetOwnedBlueprintsFunc: func(ctx context.Context, userID string) (*models.OwnedBlueprints, error) {
+				return &models.OwnedBlueprints{
+					UserID:     userID,
+					Blueprints: []models.OwnedBlueprint{{UniqueName: "/Lotus/Powersuits/Volt/Volt"}},
+					Components: []models.OwnedComponent{{UniqueName: "/Lotus/Types/Recipes/VoltChassis", Count: 1}},
+				}, nil
+			},
+		},
+		&mocks.MockMaterialResolver{
+			GetMaterialsFunc: func(ctx context.Context, userID string) (*models.MaterialsResponse, error) {
+				return nil, errors.New("resolver error")
+			},
+		},
+	)
+}
+
+func serveGraphQL(t *testing.T, handler *GraphQLHandler, userID, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	r := chi.NewRouter()
+	r.With(withTestUser(userID)).Post("/api/v1/graphql", handler.Query)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/graphql", strings.NewReader(body)))
+	return rec
+}
+
+func TestGraphQLHandler_Query(t *testing.T) {
+	wishlistCalls := 0
+	handler := newGraphQLTestHandler(&wishlistCalls)
+
+	query := `{
+		item(uniqueName: "/Lotus/Powersuits/Volt/Volt") {
+			name
+			blueprintOwned
+			wishlist { quantity }
+			drops { location chance }
+			components { name owned item { name wishlist { quantity } } }
+		}
+		wishlist { items { uniqueName } }
+	}`
+	body, _ := json.Marshal(map[string]string{"query": query})
+	rec := serveGraphQL(t, handler, "user-123", string(body))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	want := `{"data":{"item":{"name":"Volt","blueprintOwned":true,"wishlist":{"quantity":2},` +
+		`"drops":[{"location":"Market","chance":1}],` +
+		`"components":[{"name":"Chassis","owned":1,"item":null},{"name":"Morphics","owned":0,"item":{"name":"Morphics","wishlist":null}}]},` +
+		`"wishlist":{"items":[{"uniqueName":"/Lotus/Powersuits/Volt/Volt"}]}}}`
+	if got := strings.TrimSpace(rec.Body.String()); got != want {
+		t.Errorf("unexpected body:\n got  %s\n want %s", got, want)
+	}
+	if wishlistCalls != 1 {
+		t.Errorf("expected the wishlist to load once, loaded %d times", wishlistCalls)
+	}
+}
+
+func TestGraphQLHandler_Query_Errors(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		body           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "unauthorized - no user ID",
+			userID:         "",
+			body:           `{"query":"{ wishlist { items { uniqueName } } }"}`,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "invalid request body",
+			userID:         "user-123",
+			body:           `not json`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid query",
+			userID:         "user-123",
+			body:           `{"query":"{ wishlist { secret } }"}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"errors":[{"message":"Cannot query field \"secret\" on type \"Wishlist\".","locations":[{"line":1,"column":14}]}]}`,
+		},
+		{
+			name:           "service error",
+			userID:         "user-123",
+			body:           `{"query":"{ item(uniqueName: \"/broken\") { name } materials { totalCredits } }"}`,
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"data":null,"errors":[{"message":"failed to get item","locations":[{"line":1,"column":3}],"path":["item"]},` +
+				`{"message":"failed to get materials","locations":[{"line":1,"column":40}],"path":["materials"]}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveGraphQL(t, newGraphQLTestHandler(nil), tt.userID, tt.body)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if tt.expectedBody != "" {
+				if got := strings.TrimSpace(rec.Body.String()); got != tt.expectedBody {
+					t.Errorf("unexpected body:\n got  %s\n want %s", got, tt.expectedBody)
+				}
+			}
+		})
+	}
+}
+
+func TestGraphQLHandler_Schema(t *testing.T) {
+	handler := newGraphQLTestHandler(nil)
+	r := chi.NewRouter()
+	r.Get("/api/v1/graphql/schema", handler.Schema)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/graphql/schema", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("expected text/plain, got %q", ct)
+	}
+	for _, want := range []string{"type Query {", "item(uniqueName: String!): Item", "type Item {", "wishlist: WishlistItem", "scalar DateTime"} {
+		if !strings.Contains(rec.Body.String(), want) {
+			t.Errorf("expected schema to contain %q", want)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/pkg/response"
+)
+
+// apiBase is where the API routes are mounted; HAL links are built on it.
+const apiBase = "/api/v1"
+
+// wantsHAL reports whether the request asks for HAL _links, with an Accept
+// header preferring application/hal+json.
+func wantsHAL(r *http.Request) bool {
+	return prefersMediaType(r.Header.Get("Accept"), response.ContentTypeHAL)
+}
+
+// uniqueNamePath escapes each segment of uniqueName for use after a route
+// prefix, keeping the slashes that separate them.
+func uniqueNamePath(uniqueName string) string {
+	segments := strings.Split(uniqueName, "/")
+	for i := range segments {
+		segments[i] = url.PathEscape(segments[i])
+	}
+	return strings.Join(segments, "/")
+}
+
+func selfLink(r *http.Request) dto.Links {
+	return dto.Links{"self": {Href: r.URL.RequestURI(), Method: http.MethodGet}}
+}
+
+func itemLink(uniqueName string) dto.Link {
+	return dto.Link{Href: apiBase + "/items" + uniqueNamePath(uniqueName), Method: http.MethodGet}
+}
+
+func wishlistItemLinks(uniqueName string) dto.Links {
+	path := apiBase + "/wishlist" + uniqueNamePath(uniqueName)
+	return dto.Links{
+		"item":   itemLink(uniqueName),
+		"update": {Href: path, Method: http.MethodPatch},
+		"remove": {Href: path, Method: http.MethodDelete},
+	}
+}
+
+func halWishlist(r *http.Request, wishlist *dto.Wishlist) *dto.HALWishlist {
+	if wishlist == nil {
+		return nil
+	}
+	hal := &dto.HALWishlist{
+		Wishlist:   *wishlist,
+		Items:      make([]dto.HALWishlistItem, len(wishlist.Items)),
+		Hypermedia: selfLink(r),
+	}
+	for i, item := range wishlist.Items {
+		hal.Items[i] = dto.HALWishlistItem{WishlistItem: item, Hypermedia: wishlistItemLinks(item.UniqueName)}
+	}
+	return hal
+}
+
+func halExpandedWishlist(r *http.Request, wishlist *dto.ExpandedWishlist) *dto.HALExpandedWishlist {
+	if wishlist == nil {
+		return nil
+	}
+	hal := &dto.HALExpandedWishlist{
+		ExpandedWishlist: *wishlist,
+		Items:            make([]dto.HALExpandedWishlistItem, len(wishlist.Items)),
+		Hypermedia:       selfLink(r),
+	}
+	for i, item := range wishlist.Items {
+		hal.Items[i] = dto.HALExpandedWishlistItem{ExpandedWishlistItem: item, Hypermedia: wishlistItemLinks(item.UniqueName)}
+	}
+	return hal
+}
+
+// halItemSearch links a search page to its neighbours by rewriting the
+// offset of the request's own query; params must be normalized.
+func halItemSearch(r *http.Request, params models.SearchParams, page *dto.ItemSearchResponse) *dto.HALItemSearchResponse {
+	if page == nil {
+		return nil
+	}
+	hal := &dto.HALItemSearchResponse{
+		ItemSearchResponse: *page,
+		Items:              make([]dto.HALItemSummary, len(page.Items)),
+		Hypermedia:         selfLink(r),
+	}
+	for i, item := range page.Items {
+		hal.Items[i] = dto.HALItemSummary{ItemSummary: item, Hypermedia: dto.Links{"self": itemLink(item.UniqueName)}}
+	}
+
+	pageLink := func(offset int) dto.Link {
+		query := r.URL.Query()
+		query.Set("offset", strconv.Itoa(offset))
+		return dto.Link{Href: r.URL.Path + "?" + query.Encode(), Method: http.MethodGet}
+	}
+	if params.Offset+params.Limit < page.Total {
+		hal.Hypermedia["next"] = pageLink(params.Offset + params.Limit)
+	}
+	if params.Offset > 0 {
+		hal.Hypermedia["prev"] = pageLink(max(params.Offset-params.Limit, 0))
+	}
+	return hal
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/pkg/response"
+)
+
+func TestWishlistHandler_GetWishlist_HAL(t *testing.T) {
+	mockService := &mockWishlistService{
+		getWishlistFunc: func(ctx context.Context, userID string) (*models.Wishlist, error) {
+			return &models.Wishlist{UserID: userID, Items: []models.WishlistItem{{UniqueName: "/Lotus/Powersuits/Ninja/Ninja", Quantity: 1}}}, nil
+		},
+	}
+	handler := NewWishlistHandler(mockService, &mockMaterialResolver{})
+
+	tests := []struct {
+		name        string
+		accept      string
+		expectLinks bool
+	}{
+		{name: "hal", accept: "application/hal+json", expectLinks: true},
+		{name: "hal preferred over json", accept: "application/hal+json, application/json;q=0.9", expectLinks: true},
+		{name: "json preferred", accept: "application/json, application/hal+json;q=0.5", expectLinks: false},
+		{name: "no accept header", accept: "", expectLinks: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := createAuthenticatedRequest(http.MethodGet, "/api/v1/wishlist", nil, "user-123")
+			req.Header.Set("Accept", tt.accept)
+			rec := httptest.NewRecorder()
+
+			handler.GetWishlist(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d", rec.Code)
+			}
+			var body dto.HALWishlist
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if !tt.expectLinks {
+				if body.Hypermedia != nil || body.Items[0].Hypermedia != nil {
+					t.Errorf("expected no _links, got %+v", body)
+				}
+				if ct := rec.Header().Get("Content-Type"); ct != response.ContentTypeJSON {
+					t.Errorf("expected %s, got %q", response.ContentTypeJSON, ct)
+				}
+				return
+			}
+
+			if ct := rec.Header().Get("Content-Type"); ct != response.ContentTypeHAL {
+				t.Errorf("expected %s, got %q", response.ContentTypeHAL, ct)
+			}
+			if body.Hypermedia["self"].Href != "/api/v1/wishlist" {
+				t.Errorf("unexpected self link %+v", body.Hypermedia)
+			}
+			links := body.Items[0].Hypermedia
+			expected := dto.Links{
+				"item":   {Href: "/api/v1/items/Lotus/Powersuits/Ninja/Ninja", Method: http.MethodGet},
+				"update": {Href: "/api/v1/wishlist/Lotus/Powersuits/Ninja/Ninja", Method: http.MethodPatch},
+				"remove": {Href: "/api/v1/wishlist/Lotus/Powersuits/Ninja/Ninja", Method: http.MethodDelete},
+			}
+			for rel, link := range expected {
+				if links[rel] != link {
+					t.Errorf("expected %s link %+v, got %+v", rel, link, links[rel])
+				}
+			}
+		})
+	}
+}
+
+func TestItemHandler_Search_HAL(t *testing.T) {
+	mockService := &mockItemService{
+		searchFunc: func(ctx context.Context, params models.SearchParams) (*models.ItemSearchPage, error) {
+			return &models.ItemSearchPage{
+				Items: []models.ItemSearchResult{{UniqueName: "/Lotus/Types/Recipes/Ash Blueprint", Name: "Ash Blueprint"}},
+				Total: 5,
+			}, nil
+		},
+	}
+	handler := NewItemHandler(mockService)
+
+	tests := []struct {
+		name         string
+		query        string
+		expectedNext string
+		expectedPrev string
+	}{
+		{name: "first page", query: "?q=ash&limit=2", expectedNext: "/api/v1/items/search?limit=2&offset=2&q=ash"},
+		{name: "middle page", query: "?q=ash&limit=2&offset=2", expectedNext: "/api/v1/items/search?limit=2&offset=4&q=ash", expectedPrev: "/api/v1/items/search?limit=2&offset=0&q=ash"},
+		{name: "last page", query: "?q=ash&limit=2&offset=4", expectedPrev: "/api/v1/items/search?limit=2&offset=2&q=ash"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/items/search"+tt.query, nil)
+			req.Header.Set("Accept", "application/hal+json")
+			rec := httptest.NewRecorder()
+
+			handler.Search(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d", rec.Code)
+			}
+			var body dto.HALItemSearchResponse
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if body.Hypermedia["self"].Href != "/api/v1/items/search"+tt.query {
+				t.Errorf("unexpected self link %+v", body.Hypermedia["self"])
+			}
+			if body.Hypermedia["next"].Href != tt.expectedNext {
+				t.Errorf("expected next %q, got %q", tt.expectedNext, body.Hypermedia["next"].Href)
+			}
+			if body.Hypermedia["prev"].Href != tt.expectedPrev {
+				t.Errorf("expected prev %q, got %q", tt.expectedPrev, body.Hypermedia["prev"].Href)
+			}
+			if href := body.Items[0].Hypermedia["self"].Href; href != "/api/v1/items/Lotus/Types/Recipes/Ash%20Blueprint" {
+				t.Errorf("unexpected item link %q", href)
+			}
+		})
+	}
+}
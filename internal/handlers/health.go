@@ -3,16 +3,32 @@ package handlers
 import (
 	"net/http"
 
+	"github.com/graytonio/warframe-wishlist/internal/database"
 	"github.com/graytonio/warframe-wishlist/pkg/logger"
 	"github.com/graytonio/warframe-wishlist/pkg/response"
 )
 
-type HealthHandler struct{}
+// DatabaseTopology reports the database deployment's state for readiness.
+type DatabaseTopology interface {
+	Topology() database.Topology
+}
+
+type HealthHandler struct {
+	// database is nil when the server runs without MongoDB, as in demo and
+	// kiosk mode.
+	database DatabaseTopology
+}
 
 func NewHealthHandler() *HealthHandler {
 	return &HealthHandler{}
 }
 
+// SetDatabase makes readiness depend on the database having a writable
+// server.
+func (h *HealthHandler) SetDatabase(database DatabaseTopology) {
+	h.database = database
+}
+
 func (h *HealthHandler) Health(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	logger.Debug(ctx, "handler: Health called")
@@ -20,3 +36,34 @@ func (h *HealthHandler) Health(w http.ResponseWriter, r *http.Request) {
 		"status": "ok",
 	})
 }
+
+// Ready reports whether the instance can serve requests. While a replica set
+// elects a new primary it returns 503 with the topology, so load balancers
+// hold traffic back instead of passing on failing writes.
+func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: Ready called")
+
+	if h.database == nil {
+		response.JSON(w, http.StatusOK, map[string]any{
+			"status": "ready",
+		})
+		return
+	}
+
+	topology := h.database.Topology()
+	status, code := "ready", http.StatusOK
+	if !topology.Writable {
+		logger.Warn(ctx, "handler: Ready - database has no writable server", "topology", topology.Kind)
+		status, code = "unavailable", http.StatusServiceUnavailable
+	}
+	response.JSON(w, code, map[string]any{
+		"status": status,
+		"database": map[string]any{
+			"topology":  topology.Kind,
+			"writable":  topology.Writable,
+			"servers":   topology.Servers,
+			"changedAt": topology.ChangedAt,
+		},
+	})
+}
@@ -5,6 +5,8 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/database"
 )
 
 func TestHealthHandler_Health(t *testing.T) {
@@ -42,3 +44,61 @@ func TestHealthHandler_Health_ContentType(t *testing.T) {
 		t.Errorf("expected Content-Type 'application/json', got '%s'", contentType)
 	}
 }
+
+type staticTopology database.Topology
+
+func (t staticTopology) Topology() database.Topology {
+	return database.Topology(t)
+}
+
+func TestHealthHandler_Ready(t *testing.T) {
+	tests := []struct {
+		name           string
+		topology       DatabaseTopology
+		expectedStatus int
+		expectedState  string
+	}{
+		{name: "without database", expectedStatus: http.StatusOK, expectedState: "ready"},
+		{name: "writable primary", topology: staticTopology{Kind: "ReplicaSetWithPrimary", Writable: true, Servers: 3}, expectedStatus: http.StatusOK, expectedState: "ready"},
+		{name: "election in progress", topology: staticTopology{Kind: "ReplicaSetNoPrimary", Servers: 3}, expectedStatus: http.StatusServiceUnavailable, expectedState: "unavailable"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewHealthHandler()
+			if tt.topology != nil {
+				handler.SetDatabase(tt.topology)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/ready", nil)
+			rec := httptest.NewRecorder()
+			handler.Ready(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			var body struct {
+				Status   string `json:"status"`
+				Database *struct {
+					Topology string `json:"topology"`
+					Writable bool   `json:"writable"`
+				} `json:"database"`
+			}
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if body.Status != tt.expectedState {
+				t.Errorf("expected status %q, got %q", tt.expectedState, body.Status)
+			}
+			if tt.topology == nil {
+				if body.Database != nil {
+					t.Errorf("expected no database section, got %+v", body.Database)
+				}
+				return
+			}
+			if body.Database == nil || body.Database.Topology != tt.topology.Topology().Kind {
+				t.Errorf("expected the topology in the response, got %+v", body.Database)
+			}
+		})
+	}
+}
@@ -0,0 +1,254 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+	"github.com/graytonio/warframe-wishlist/internal/middleware"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"github.com/graytonio/warframe-wishlist/pkg/response"
+)
+
+type HouseholdHandler struct {
+	householdService services.HouseholdServiceInterface
+}
+
+func NewHouseholdHandler(householdService services.HouseholdServiceInterface) *HouseholdHandler {
+	return &HouseholdHandler{householdService: householdService}
+}
+
+func (h *HouseholdHandler) GetHousehold(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: GetHousehold called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: GetHousehold - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	household, err := h.householdService.GetHousehold(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "handler: GetHousehold - failed to get household", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to get household")
+		return
+	}
+
+	logger.Info(ctx, "handler: GetHousehold - success", "memberCount", len(household.Members))
+	response.JSON(w, http.StatusOK, dto.NewHousehold(household))
+}
+
+func (h *HouseholdHandler) RequestManager(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: RequestManager called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: RequestManager - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	var req dto.RequestManagerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn(ctx, "handler: RequestManager - invalid request body", "error", err)
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if req.ManagerUserID == "" {
+		logger.Warn(ctx, "handler: RequestManager - managerUserId is required")
+		response.ErrorCode(w, http.StatusBadRequest, response.CodeManagerUserIDRequired, "managerUserId is required")
+		return
+	}
+
+	link, err := h.householdService.RequestManager(ctx, userID, req.ToModel())
+	if err != nil {
+		writeHouseholdError(w, r, "RequestManager", err, "failed to request manager")
+		return
+	}
+
+	logger.Info(ctx, "handler: RequestManager - success", "managerID", req.ManagerUserID)
+	response.JSON(w, http.StatusCreated, dto.NewHouseholdLink(link))
+}
+
+func (h *HouseholdHandler) LeaveManager(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: LeaveManager called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: LeaveManager - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	if err := h.householdService.LeaveManager(ctx, userID); err != nil {
+		writeHouseholdError(w, r, "LeaveManager", err, "failed to leave manager")
+		return
+	}
+
+	logger.Info(ctx, "handler: LeaveManager - success")
+	response.JSON(w, http.StatusOK, map[string]string{
+		"message": "manager request withdrawn",
+	})
+}
+
+func (h *HouseholdHandler) AcceptMember(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: AcceptMember called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: AcceptMember - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	memberID := chi.URLParam(r, "memberID")
+	link, err := h.householdService.AcceptMember(ctx, userID, memberID)
+	if err != nil {
+		writeHouseholdError(w, r, "AcceptMember", err, "failed to accept member")
+		return
+	}
+
+	logger.Info(ctx, "handler: AcceptMember - success", "memberID", memberID)
+	response.JSON(w, http.StatusOK, dto.NewHouseholdLink(link))
+}
+
+func (h *HouseholdHandler) UpdateMember(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: UpdateMember called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: UpdateMember - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	var req dto.UpdateHouseholdMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn(ctx, "handler: UpdateMember - invalid request body", "error", err)
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	memberID := chi.URLParam(r, "memberID")
+	link, err := h.householdService.UpdateMember(ctx, userID, memberID, req.ToModel())
+	if err != nil {
+		writeHouseholdError(w, r, "UpdateMember", err, "failed to update member")
+		return
+	}
+
+	logger.Info(ctx, "handler: UpdateMember - success", "memberID", memberID)
+	response.JSON(w, http.StatusOK, dto.NewHouseholdLink(link))
+}
+
+func (h *HouseholdHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: RemoveMember called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: RemoveMember - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	memberID := chi.URLParam(r, "memberID")
+	if err := h.householdService.RemoveMember(ctx, userID, memberID); err != nil {
+		writeHouseholdError(w, r, "RemoveMember", err, "failed to remove member")
+		return
+	}
+
+	logger.Info(ctx, "handler: RemoveMember - success", "memberID", memberID)
+	response.JSON(w, http.StatusOK, map[string]string{
+		"message": "member removed",
+	})
+}
+
+func (h *HouseholdHandler) ListApprovals(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: ListApprovals called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: ListApprovals - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	approvals, err := h.householdService.ListApprovals(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "handler: ListApprovals - failed to list approvals", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to list approvals")
+		return
+	}
+
+	logger.Info(ctx, "handler: ListApprovals - success", "toReview", len(approvals.ToReview), "requested", len(approvals.Requested))
+	response.JSON(w, http.StatusOK, dto.NewHouseholdApprovals(approvals))
+}
+
+func (h *HouseholdHandler) Approve(w http.ResponseWriter, r *http.Request) {
+	h.decide(w, r, "Approve", h.householdService.Approve)
+}
+
+func (h *HouseholdHandler) Reject(w http.ResponseWriter, r *http.Request) {
+	h.decide(w, r, "Reject", h.householdService.Reject)
+}
+
+func (h *HouseholdHandler) decide(w http.ResponseWriter, r *http.Request, name string, decide func(ctx context.Context, managerID, changeID string) (*models.PendingChange, error)) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: "+name+" called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: "+name+" - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	changeID := chi.URLParam(r, "changeID")
+	change, err := decide(ctx, userID, changeID)
+	if err != nil {
+		writeHouseholdError(w, r, name, err, "failed to decide change")
+		return
+	}
+
+	logger.Info(ctx, "handler: "+name+" - success", "changeID", changeID)
+	response.JSON(w, http.StatusOK, dto.NewPendingChange(change))
+}
+
+// writeHouseholdError maps household service errors to responses, falling
+// back to a 500 with fallback as the message.
+func writeHouseholdError(w http.ResponseWriter, r *http.Request, name string, err error, fallback string) {
+	ctx := r.Context()
+
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrCannotManageSelf), errors.Is(err, services.ErrInvalidThreshold):
+		status = http.StatusBadRequest
+	case errors.Is(err, services.ErrLinkNotFound), errors.Is(err, services.ErrChangeNotFound), errors.Is(err, services.ErrItemNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrAlreadyLinked), errors.Is(err, services.ErrLinkActive),
+		errors.Is(err, services.ErrChangeAlreadyDecided), errors.Is(err, services.ErrItemNotInWishlist),
+		errors.Is(err, services.ErrWishlistFull):
+		status = http.StatusConflict
+	}
+
+	if status == http.StatusInternalServerError {
+		logger.Error(ctx, "handler: "+name+" - "+fallback, "error", err)
+		response.Error(w, status, fallback)
+		return
+	}
+	logger.Warn(ctx, "handler: "+name+" - request rejected", "error", err)
+	rejected(w, status, err)
+}
@@ -0,0 +1,348 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/graytonio/warframe-wishlist/internal/middleware"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+)
+
+type mockHouseholdService struct {
+	getHouseholdFunc   func(ctx context.Context, userID string) (*models.Household, error)
+	requestManagerFunc func(ctx context.Context, memberID string, req models.RequestManagerRequest) (*models.HouseholdLink, error)
+	leaveManagerFunc   func(ctx context.Context, memberID string) error
+	acceptMemberFunc   func(ctx context.Context, managerID, memberID string) (*models.HouseholdLink, error)
+	updateMemberFunc   func(ctx context.Context, managerID, memberID string, req models.UpdateHouseholdMemberRequest) (*models.HouseholdLink, error)
+	removeMemberFunc   func(ctx context.Context, managerID, memberID string) error
+	listApprovalsFunc  func(ctx context.Context, userID string) (*models.HouseholdApprovals, error)
+	approveFunc        func(ctx context.Context, managerID, changeID string) (*models.PendingChange, error)
+	rejectFunc         func(ctx context.Context, managerID, changeID string) (*models.PendingChange, error)
+}
+
+func (m *mockHouseholdService) GetHousehold(ctx context.Context, userID string) (*models.Household, error) {
+	if m.getHouseholdFunc != nil {
+		return m.getHouseholdFunc(ctx, userID)
+	}
+	return &models.Household{Members: []models.HouseholdLink{}}, nil
+}
+
+func (m *mockHouseholdService) RequestManager(ctx context.Context, memberID string, req models.RequestManagerRequest) (*models.HouseholdLink, error) {
+	if m.requestManagerFunc != nil {
+		return m.requestManagerFunc(ctx, memberID, req)
+	}
+	return &models.HouseholdLink{}, nil
+}
+
+func (m *mockHouseholdService) LeaveManager(ctx context.Context, memberID string) error {
+	if m.leaveManagerFunc != nil {
+		return m.leaveManagerFunc(ctx, memberID)
+	}
+	return nil
+}
+
+func (m *mockHouseholdService) AcceptMember(ctx context.Context, managerID, memberID string) (*models.HouseholdLink, error) {
+	if m.acceptMemberFunc != nil {
+		return m.acceptMemberFunc(ctx, managerID, memberID)
+	}
+	return &models.HouseholdLink{}, nil
+}
+
+func (m *mockHouseholdService) UpdateMember(ctx context.Context, managerID, memberID string, req models.UpdateHouseholdMemberRequest) (*models.HouseholdLink, error) {
+	if m.updateMemberFunc != nil {
+		return m.updateMemberFunc(ctx, managerID, memberID, req)
+	}
+	return &models.HouseholdLink{}, nil
+}
+
+func (m *mockHouseholdService) RemoveMember(ctx context.Context, managerID, memberID string) error {
+	if m.removeMemberFunc != nil {
+		return m.removeMemberFunc(ctx, managerID, memberID)
+	}
+	return nil
+}
+
+func (m *mockHouseholdService) ListApprovals(ctx context.Context, userID string) (*models.HouseholdApprovals, error) {
+	if m.listApprovalsFunc != nil {
+		return m.listApprovalsFunc(ctx, userID)
+	}
+	return &models.HouseholdApprovals{ToReview: []models.PendingChange{}, Requested: []models.PendingChange{}}, nil
+}
+
+func (m *mockHouseholdService) Approve(ctx context.Context, managerID, changeID string) (*models.PendingChange, error) {
+	if m.approveFunc != nil {
+		return m.approveFunc(ctx, managerID, changeID)
+	}
+	return &models.PendingChange{}, nil
+}
+
+func (m *mockHouseholdService) Reject(ctx context.Context, managerID, changeID string) (*models.PendingChange, error) {
+	if m.rejectFunc != nil {
+		return m.rejectFunc(ctx, managerID, changeID)
+	}
+	return &models.PendingChange{}, nil
+}
+
+// newHouseholdRouter mounts the household routes as main does, injecting
+// userID in place of the auth middleware.
+func newHouseholdRouter(service services.HouseholdServiceInterface, userID string) http.Handler {
+	handler := NewHouseholdHandler(service)
+
+	r := chi.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), middleware.UserIDKey, userID)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	})
+	r.Get("/", handler.GetHousehold)
+	r.Put("/manager", handler.RequestManager)
+	r.Delete("/manager", handler.LeaveManager)
+	r.Post("/members/{memberID}/accept", handler.AcceptMember)
+	r.Patch("/members/{memberID}", handler.UpdateMember)
+	r.Delete("/members/{memberID}", handler.RemoveMember)
+	r.Get("/approvals", handler.ListApprovals)
+	r.Post("/approvals/{changeID}/approve", handler.Approve)
+	r.Post("/approvals/{changeID}/reject", handler.Reject)
+	return r
+}
+
+func TestHouseholdHandler_Unauthorized(t *testing.T) {
+	router := newHouseholdRouter(&mockHouseholdService{}, "")
+
+	routes := []struct{ method, path string }{
+		{http.MethodGet, "/"},
+		{http.MethodPut, "/manager"},
+		{http.MethodDelete, "/manager"},
+		{http.MethodPost, "/members/member-1/accept"},
+		{http.MethodPatch, "/members/member-1"},
+		{http.MethodDelete, "/members/member-1"},
+		{http.MethodGet, "/approvals"},
+		{http.MethodPost, "/approvals/abc/approve"},
+		{http.MethodPost, "/approvals/abc/reject"},
+	}
+
+	for _, route := range routes {
+		t.Run(route.method+" "+route.path, func(t *testing.T) {
+			req := httptest.NewRequest(route.method, route.path, bytes.NewReader([]byte("{}")))
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusUnauthorized {
+				t.Errorf("expected status %d, got %d", http.StatusUnauthorized, rec.Code)
+			}
+		})
+	}
+}
+
+func TestHouseholdHandler_RequestManager(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "success", body: `{"managerUserId":"manager","quantityThreshold":5}`, expectedStatus: http.StatusCreated},
+		{name: "invalid body", body: `{`, expectedStatus: http.StatusBadRequest},
+		{name: "missing manager", body: `{"quantityThreshold":5}`, expectedStatus: http.StatusBadRequest},
+		{name: "cannot manage self", body: `{"managerUserId":"user-123"}`, mockError: services.ErrCannotManageSelf, expectedStatus: http.StatusBadRequest},
+		{name: "invalid threshold", body: `{"managerUserId":"manager","quantityThreshold":-1}`, mockError: services.ErrInvalidThreshold, expectedStatus: http.StatusBadRequest},
+		{name: "already linked", body: `{"managerUserId":"manager"}`, mockError: services.ErrAlreadyLinked, expectedStatus: http.StatusConflict},
+		{name: "service error", body: `{"managerUserId":"manager"}`, mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotReq models.RequestManagerRequest
+			service := &mockHouseholdService{
+				requestManagerFunc: func(ctx context.Context, memberID string, req models.RequestManagerRequest) (*models.HouseholdLink, error) {
+					gotReq = req
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return &models.HouseholdLink{ManagerID: req.ManagerUserID, MemberID: memberID, Status: models.HouseholdLinkPending}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPut, "/manager", bytes.NewReader([]byte(tt.body)))
+			rec := httptest.NewRecorder()
+			newHouseholdRouter(service, "user-123").ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if tt.expectedStatus == http.StatusCreated && (gotReq.ManagerUserID != "manager" || gotReq.QuantityThreshold != 5) {
+				t.Errorf("unexpected request passed to service: %+v", gotReq)
+			}
+		})
+	}
+}
+
+func TestHouseholdHandler_LeaveManager(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "success", expectedStatus: http.StatusOK},
+		{name: "no link", mockError: services.ErrLinkNotFound, expectedStatus: http.StatusNotFound},
+		{name: "active link", mockError: services.ErrLinkActive, expectedStatus: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mockHouseholdService{
+				leaveManagerFunc: func(ctx context.Context, memberID string) error {
+					return tt.mockError
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodDelete, "/manager", nil)
+			rec := httptest.NewRecorder()
+			newHouseholdRouter(service, "user-123").ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+		})
+	}
+}
+
+func TestHouseholdHandler_Members(t *testing.T) {
+	var calls []string
+	service := &mockHouseholdService{
+		acceptMemberFunc: func(ctx context.Context, managerID, memberID string) (*models.HouseholdLink, error) {
+			calls = append(calls, "accept:"+managerID+":"+memberID)
+			return &models.HouseholdLink{MemberID: memberID, Status: models.HouseholdLinkActive}, nil
+		},
+		updateMemberFunc: func(ctx context.Context, managerID, memberID string, req models.UpdateHouseholdMemberRequest) (*models.HouseholdLink, error) {
+			if req.QuantityThreshold == nil || *req.QuantityThreshold != 4 {
+				t.Errorf("expected threshold 4, got %v", req.QuantityThreshold)
+			}
+			calls = append(calls, "update:"+managerID+":"+memberID)
+			return &models.HouseholdLink{MemberID: memberID, QuantityThreshold: 4}, nil
+		},
+		removeMemberFunc: func(ctx context.Context, managerID, memberID string) error {
+			calls = append(calls, "remove:"+managerID+":"+memberID)
+			return services.ErrLinkNotFound
+		},
+	}
+	router := newHouseholdRouter(service, "manager")
+
+	requests := []struct {
+		method, path, body string
+		expectedStatus     int
+	}{
+		{http.MethodPost, "/members/kid/accept", "", http.StatusOK},
+		{http.MethodPatch, "/members/kid", `{"quantityThreshold":4}`, http.StatusOK},
+		{http.MethodPatch, "/members/kid", `{`, http.StatusBadRequest},
+		{http.MethodDelete, "/members/kid", "", http.StatusNotFound},
+	}
+	for _, r := range requests {
+		req := httptest.NewRequest(r.method, r.path, bytes.NewReader([]byte(r.body)))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != r.expectedStatus {
+			t.Errorf("%s %s: expected status %d, got %d", r.method, r.path, r.expectedStatus, rec.Code)
+		}
+	}
+
+	expected := []string{"accept:manager:kid", "update:manager:kid", "remove:manager:kid"}
+	if len(calls) != len(expected) {
+		t.Fatalf("expected calls %v, got %v", expected, calls)
+	}
+	for i := range expected {
+		if calls[i] != expected[i] {
+			t.Errorf("expected call %q, got %q", expected[i], calls[i])
+		}
+	}
+}
+
+func TestHouseholdHandler_ListApprovals(t *testing.T) {
+	service := &mockHouseholdService{
+		listApprovalsFunc: func(ctx context.Context, userID string) (*models.HouseholdApprovals, error) {
+			return &models.HouseholdApprovals{
+				ToReview:  []models.PendingChange{{MemberID: "kid", UniqueName: "/Lotus/Forma", Quantity: 10}},
+				Requested: []models.PendingChange{},
+			}, nil
+		},
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/approvals", nil)
+	rec := httptest.NewRecorder()
+	newHouseholdRouter(service, "manager").ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	var body models.HouseholdApprovals
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(body.ToReview) != 1 || body.ToReview[0].UniqueName != "/Lotus/Forma" {
+		t.Errorf("unexpected approvals: %+v", body)
+	}
+}
+
+func TestHouseholdHandler_Decide(t *testing.T) {
+	tests := []struct {
+		name                string
+		path                string
+		mockError           error
+		expectedStatus      int
+		expectedStatusField string
+	}{
+		{name: "approve", path: "/approvals/abc/approve", expectedStatus: http.StatusOK, expectedStatusField: models.ChangeStatusApproved},
+		{name: "reject", path: "/approvals/abc/reject", expectedStatus: http.StatusOK, expectedStatusField: models.ChangeStatusRejected},
+		{name: "not found", path: "/approvals/abc/approve", mockError: services.ErrChangeNotFound, expectedStatus: http.StatusNotFound},
+		{name: "already decided", path: "/approvals/abc/reject", mockError: services.ErrChangeAlreadyDecided, expectedStatus: http.StatusConflict},
+		{name: "item removed meanwhile", path: "/approvals/abc/approve", mockError: services.ErrItemNotInWishlist, expectedStatus: http.StatusConflict},
+		{name: "service error", path: "/approvals/abc/approve", mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decide := func(status string) func(ctx context.Context, managerID, changeID string) (*models.PendingChange, error) {
+				return func(ctx context.Context, managerID, changeID string) (*models.PendingChange, error) {
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					if managerID != "manager" || changeID != "abc" {
+						t.Errorf("unexpected arguments: managerID=%q changeID=%q", managerID, changeID)
+					}
+					return &models.PendingChange{Status: status}, nil
+				}
+			}
+			service := &mockHouseholdService{
+				approveFunc: decide(models.ChangeStatusApproved),
+				rejectFunc:  decide(models.ChangeStatusRejected),
+			}
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, nil)
+			rec := httptest.NewRecorder()
+			newHouseholdRouter(service, "manager").ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if tt.expectedStatusField == "" {
+				return
+			}
+			var change models.PendingChange
+			if err := json.Unmarshal(rec.Body.Bytes(), &change); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if change.Status != tt.expectedStatusField {
+				t.Errorf("expected change status %q, got %q", tt.expectedStatusField, change.Status)
+			}
+		})
+	}
+}
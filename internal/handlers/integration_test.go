@@ -0,0 +1,278 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+	"github.com/graytonio/warframe-wishlist/internal/middleware"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository/memory"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+)
+
+// newIntegrationRouter wires real services and handlers on top of the
+// in-memory repositories, using the same routes as cmd/server.
+func newIntegrationRouter(t *testing.T) http.Handler {
+	t.Helper()
+
+	itemRepo := memory.NewItemRepository()
+	itemRepo.Add("warframes", models.Item{
+		UniqueName: "/Lotus/Powersuits/Excalibur/Excalibur",
+		Name:       "Excalibur",
+		BuildPrice: 25000,
+		Components: []models.Component{
+			{UniqueName: "/Lotus/Types/Items/MiscItems/Ferrite", Name: "Ferrite", ItemCount: 100},
+			{UniqueName: "/Lotus/Types/Items/MiscItems/Plastids", Name: "Plastids", ItemCount: 50},
+		},
+	})
+	itemRepo.Add("resources",
+		models.Item{UniqueName: "/Lotus/Types/Items/MiscItems/Ferrite", Name: "Ferrite"},
+		models.Item{UniqueName: "/Lotus/Types/Items/MiscItems/Plastids", Name: "Plastids"},
+	)
+	wishlistRepo := memory.NewWishlistRepository()
+	ownedBPRepo := memory.NewOwnedBlueprintsRepository()
+	ownedMatRepo := memory.NewOwnedMaterialsRepository()
+
+	wishlistService := services.NewWishlistService(wishlistRepo, itemRepo)
+	itemHandler := NewItemHandler(services.NewItemService(itemRepo))
+	wishlistHandler := NewWishlistHandler(
+		wishlistService,
+		services.NewMaterialResolver(itemRepo, wishlistRepo, ownedBPRepo, ownedMatRepo),
+	)
+	importHandler := NewWishlistImportHandler(services.NewWishlistImportService(itemRepo, wishlistService))
+	ownedMatHandler := NewOwnedMaterialsHandler(services.NewOwnedMaterialsService(ownedMatRepo))
+	authMiddleware := middleware.NewDemoAuthMiddleware("user-123")
+
+	r := chi.NewRouter()
+	r.Route("/api/v1", func(r chi.Router) {
+		r.Route("/items", func(r chi.Router) {
+			r.Get("/search", itemHandler.Search)
+			r.Get("/*", itemHandler.GetByUniqueName)
+		})
+		r.Route("/wishlist", func(r chi.Router) {
+			r.Use(authMiddleware.Authenticate)
+			r.Get("/", wishlistHandler.GetWishlist)
+			r.Post("/", wishlistHandler.AddItem)
+			r.Get("/materials", wishlistHandler.GetMaterials)
+			r.Post("/import/text", importHandler.PreviewText)
+			r.Post("/import/text/confirm", importHandler.ConfirmImport)
+			r.Delete("/*", wishlistHandler.RemoveItem)
+			r.Patch("/*", wishlistHandler.UpdateQuantity)
+		})
+		r.Route("/profile/materials", func(r chi.Router) {
+			r.Use(authMiddleware.Authenticate)
+			r.Get("/", ownedMatHandler.GetOwnedMaterials)
+			r.Put("/", ownedMatHandler.SetMaterialCounts)
+			r.Delete("/", ownedMatHandler.ClearAllMaterials)
+			r.Put("/*", ownedMatHandler.SetMaterialCount)
+			r.Delete("/*", ownedMatHandler.RemoveMaterial)
+		})
+	})
+	return r
+}
+
+func doIntegrationRequest(t *testing.T, router http.Handler, method, url string, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var reader *bytes.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("failed to marshal body: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req := httptest.NewRequest(method, url, reader).WithContext(context.Background())
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestIntegration_WishlistMaterialsFlow(t *testing.T) {
+	router := newIntegrationRouter(t)
+	excalibur := "/Lotus/Powersuits/Excalibur/Excalibur"
+
+	rec := doIntegrationRequest(t, router, http.MethodPost, "/api/v1/wishlist", dto.AddItemRequest{UniqueName: excalibur})
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("add item: expected status %d, got %d: %s", http.StatusCreated, rec.Code, rec.Body.String())
+	}
+
+	rec = doIntegrationRequest(t, router, http.MethodPatch, "/api/v1/wishlist"+excalibur, dto.UpdateQuantityRequest{Quantity: 2})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("update quantity: expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	rec = doIntegrationRequest(t, router, http.MethodGet, "/api/v1/wishlist/materials", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("get materials: expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	var materials dto.MaterialsSummary
+	if err := json.Unmarshal(rec.Body.Bytes(), &materials); err != nil {
+		t.Fatalf("failed to decode materials: %v", err)
+	}
+	counts := make(map[string]int)
+	for _, m := range materials.Materials {
+		counts[m.Name] = m.TotalCount
+	}
+	if counts["Ferrite"] != 200 || counts["Plastids"] != 100 {
+		t.Errorf("unexpected material counts: %v", counts)
+	}
+	if materials.TotalCredits != 50000 {
+		t.Errorf("expected 50000 credits, got %d", materials.TotalCredits)
+	}
+
+	rec = doIntegrationRequest(t, router, http.MethodDelete, "/api/v1/wishlist"+excalibur, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("remove item: expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	rec = doIntegrationRequest(t, router, http.MethodGet, "/api/v1/wishlist", nil)
+	var wishlist dto.Wishlist
+	if err := json.Unmarshal(rec.Body.Bytes(), &wishlist); err != nil {
+		t.Fatalf("failed to decode wishlist: %v", err)
+	}
+	if len(wishlist.Items) != 0 {
+		t.Errorf("expected empty wishlist, got %d items", len(wishlist.Items))
+	}
+}
+
+func TestIntegration_OwnedMaterialsReduceRemaining(t *testing.T) {
+	router := newIntegrationRouter(t)
+	excalibur := "/Lotus/Powersuits/Excalibur/Excalibur"
+
+	rec := doIntegrationRequest(t, router, http.MethodPost, "/api/v1/wishlist", dto.AddItemRequest{UniqueName: excalibur})
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("add item: expected status %d, got %d: %s", http.StatusCreated, rec.Code, rec.Body.String())
+	}
+
+	rec = doIntegrationRequest(t, router, http.MethodPut, "/api/v1/profile/materials", dto.SetOwnedMaterialsRequest{
+		Materials: []dto.OwnedMaterialCount{
+			{UniqueName: "/Lotus/Types/Items/MiscItems/Ferrite", Count: 30},
+			{UniqueName: "/Lotus/Types/Items/MiscItems/Plastids", Count: 80},
+		},
+	})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("set materials: expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	rec = doIntegrationRequest(t, router, http.MethodGet, "/api/v1/wishlist/materials", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("get materials: expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	var materials dto.MaterialsSummary
+	if err := json.Unmarshal(rec.Body.Bytes(), &materials); err != nil {
+		t.Fatalf("failed to decode materials: %v", err)
+	}
+	byName := make(map[string]dto.MaterialRequirement)
+	for _, m := range materials.Materials {
+		byName[m.Name] = m
+	}
+	if ferrite := byName["Ferrite"]; ferrite.TotalCount != 100 || ferrite.Owned != 30 || ferrite.Remaining != 70 {
+		t.Errorf("unexpected Ferrite requirement: %+v", ferrite)
+	}
+	if plastids := byName["Plastids"]; plastids.TotalCount != 50 || plastids.Owned != 80 || plastids.Remaining != 0 {
+		t.Errorf("unexpected Plastids requirement: %+v", plastids)
+	}
+
+	rec = doIntegrationRequest(t, router, http.MethodDelete, "/api/v1/profile/materials/Lotus/Types/Items/MiscItems/Ferrite", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("remove material: expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	rec = doIntegrationRequest(t, router, http.MethodGet, "/api/v1/profile/materials", nil)
+	var owned dto.OwnedMaterials
+	if err := json.Unmarshal(rec.Body.Bytes(), &owned); err != nil {
+		t.Fatalf("failed to decode owned materials: %v", err)
+	}
+	if len(owned.Materials) != 1 || owned.Materials[0].UniqueName != "/Lotus/Types/Items/MiscItems/Plastids" {
+		t.Errorf("expected only Plastids to remain, got %+v", owned.Materials)
+	}
+}
+
+func TestIntegration_ImportPastedList(t *testing.T) {
+	router := newIntegrationRouter(t)
+
+	rec := doIntegrationRequest(t, router, http.MethodPost, "/api/v1/wishlist/import/text", dto.ImportTextRequest{
+		Text: "- Excalibur BP\n- 3x Ferrtie\n- Glaive Prime",
+	})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("preview: expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	var preview dto.ImportPreview
+	if err := json.Unmarshal(rec.Body.Bytes(), &preview); err != nil {
+		t.Fatalf("failed to decode preview: %v", err)
+	}
+	if len(preview.Matched) != 2 || len(preview.Unmatched) != 1 || preview.Unmatched[0].Input != "Glaive Prime" {
+		t.Fatalf("unexpected preview: %+v", preview)
+	}
+
+	rec = doIntegrationRequest(t, router, http.MethodGet, "/api/v1/wishlist", nil)
+	var wishlist dto.Wishlist
+	if err := json.Unmarshal(rec.Body.Bytes(), &wishlist); err != nil {
+		t.Fatalf("failed to decode wishlist: %v", err)
+	}
+	if len(wishlist.Items) != 0 {
+		t.Fatalf("preview must not change the wishlist, got %+v", wishlist.Items)
+	}
+
+	var confirm dto.ImportConfirmRequest
+	for _, match := range preview.Matched {
+		confirm.Items = append(confirm.Items, dto.AddItemRequest{UniqueName: match.Item.UniqueName, Quantity: match.Quantity})
+	}
+	rec = doIntegrationRequest(t, router, http.MethodPost, "/api/v1/wishlist/import/text/confirm", confirm)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("confirm: expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	rec = doIntegrationRequest(t, router, http.MethodGet, "/api/v1/wishlist", nil)
+	if err := json.Unmarshal(rec.Body.Bytes(), &wishlist); err != nil {
+		t.Fatalf("failed to decode wishlist: %v", err)
+	}
+	quantities := make(map[string]int)
+	for _, item := range wishlist.Items {
+		quantities[item.UniqueName] = item.Quantity
+	}
+	if quantities["/Lotus/Powersuits/Excalibur/Excalibur"] != 1 || quantities["/Lotus/Types/Items/MiscItems/Ferrite"] != 3 {
+		t.Errorf("unexpected wishlist after import: %v", quantities)
+	}
+}
+
+func TestIntegration_ItemLookup(t *testing.T) {
+	router := newIntegrationRouter(t)
+
+	rec := doIntegrationRequest(t, router, http.MethodGet, "/api/v1/items/search?q=excal", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("search: expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	rec = doIntegrationRequest(t, router, http.MethodGet, "/api/v1/items/Lotus/Powersuits/Excalibur/Excalibur", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("get item: expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	var item dto.ItemDetail
+	if err := json.Unmarshal(rec.Body.Bytes(), &item); err != nil {
+		t.Fatalf("failed to decode item: %v", err)
+	}
+	for _, c := range item.Components {
+		if !c.HasOwnPage {
+			t.Errorf("expected component '%s' to have its own page", c.Name)
+		}
+	}
+
+	rec = doIntegrationRequest(t, router, http.MethodGet, "/api/v1/items/Lotus/Missing", nil)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("missing item: expected status %d, got %d", http.StatusNotFound, rec.Code)
+	}
+}
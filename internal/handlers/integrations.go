@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/graytonio/warframe-wishlist/internal/audit"
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"github.com/graytonio/warframe-wishlist/pkg/response"
+)
+
+// IntegrationHandler serves the internal routes that register the
+// integrations sent item data changes. They are internal routes that main
+// restricts to admins.
+type IntegrationHandler struct {
+	integrationService services.IntegrationServiceInterface
+}
+
+// NewIntegrationHandler returns the handler for the integration routes.
+func NewIntegrationHandler(integrationService services.IntegrationServiceInterface) *IntegrationHandler {
+	return &IntegrationHandler{
+		integrationService: integrationService,
+	}
+}
+
+func (h *IntegrationHandler) ListIntegrations(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: ListIntegrations called")
+
+	integrations, err := h.integrationService.ListIntegrations(ctx)
+	if err != nil {
+		logger.Error(ctx, "handler: ListIntegrations - failed to list integrations", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to list integrations")
+		return
+	}
+
+	logger.Info(ctx, "handler: ListIntegrations - success", "count", len(integrations))
+	response.JSON(w, http.StatusOK, dto.NewIntegrations(integrations))
+}
+
+// CreateIntegration registers an integration. Its signing secret is in the
+// response, and only there.
+func (h *IntegrationHandler) CreateIntegration(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: CreateIntegration called")
+
+	var req dto.IntegrationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn(ctx, "handler: CreateIntegration - invalid request body", "error", err)
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	integration, err := h.integrationService.CreateIntegration(ctx, req.ToModel())
+	if err != nil {
+		writeIntegrationError(w, r, "CreateIntegration", err, "failed to create integration")
+		return
+	}
+
+	logger.Info(ctx, "handler: CreateIntegration - success", "id", integration.ID.Hex(), "name", integration.Name)
+	audit.RecordRequest(r, audit.TypeIntegration, audit.OutcomeSuccess, "integration "+integration.Name+" created", "")
+	response.JSON(w, http.StatusCreated, dto.NewCreatedIntegration(integration))
+}
+
+func (h *IntegrationHandler) DeleteIntegration(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: DeleteIntegration called")
+
+	id := chi.URLParam(r, "integrationID")
+	if err := h.integrationService.DeleteIntegration(ctx, id); err != nil {
+		writeIntegrationError(w, r, "DeleteIntegration", err, "failed to delete integration")
+		return
+	}
+
+	logger.Info(ctx, "handler: DeleteIntegration - success", "id", id)
+	audit.RecordRequest(r, audit.TypeIntegration, audit.OutcomeSuccess, "integration "+id+" deleted", "")
+	response.JSON(w, http.StatusOK, map[string]string{
+		"message": "integration deleted",
+	})
+}
+
+// ListDeliveries lists an integration's latest deliveries, newest first.
+func (h *IntegrationHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: ListIntegrationDeliveries called")
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	deliveries, err := h.integrationService.ListDeliveries(ctx, chi.URLParam(r, "integrationID"), limit)
+	if err != nil {
+		writeIntegrationError(w, r, "ListIntegrationDeliveries", err, "failed to list integration deliveries")
+		return
+	}
+
+	logger.Info(ctx, "handler: ListIntegrationDeliveries - success", "count", len(deliveries))
+	response.JSON(w, http.StatusOK, dto.NewIntegrationDeliveries(deliveries))
+}
+
+func writeIntegrationError(w http.ResponseWriter, r *http.Request, name string, err error, fallback string) {
+	ctx := r.Context()
+
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrInvalidIntegration):
+		status = http.StatusBadRequest
+	case errors.Is(err, services.ErrIntegrationNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrTooManyIntegrations):
+		status = http.StatusConflict
+	}
+
+	if status == http.StatusInternalServerError {
+		logger.Error(ctx, "handler: "+name+" - "+fallback, "error", err)
+		response.Error(w, status, fallback)
+		return
+	}
+	logger.Warn(ctx, "handler: "+name+" - request rejected", "error", err)
+	rejected(w, status, err)
+}
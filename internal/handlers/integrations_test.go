@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// newIntegrationsRouter mounts the handler on the cmd/server routes.
+func newIntegrationsRouter(service services.IntegrationServiceInterface) http.Handler {
+	handler := NewIntegrationHandler(service)
+	r := chi.NewRouter()
+	r.Get("/internal/integrations", handler.ListIntegrations)
+	r.Post("/internal/integrations", handler.CreateIntegration)
+	r.Delete("/internal/integrations/{integrationID}", handler.DeleteIntegration)
+	r.Get("/internal/integrations/{integrationID}/deliveries", handler.ListDeliveries)
+	return r
+}
+
+func serveIntegration(service services.IntegrationServiceInterface, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	newIntegrationsRouter(service).ServeHTTP(rec, req)
+	return rec
+}
+
+func TestIntegrationHandler_CreateIntegration(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "created", body: `{"name":"wiki","url":"https://wiki.example.com/hook","events":["item.added"]}`, expectedStatus: http.StatusCreated},
+		{name: "invalid body", body: `{`, expectedStatus: http.StatusBadRequest},
+		{name: "invalid integration", body: `{}`, mockError: services.ErrInvalidIntegration, expectedStatus: http.StatusBadRequest},
+		{name: "too many", body: `{}`, mockError: services.ErrTooManyIntegrations, expectedStatus: http.StatusConflict},
+		{name: "service error", body: `{}`, mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotReq models.IntegrationRequest
+			service := &mocks.MockIntegrationService{
+				CreateIntegrationFunc: func(ctx context.Context, req models.IntegrationRequest) (*models.Integration, error) {
+					gotReq = req
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return &models.Integration{ID: primitive.NewObjectID(), Name: req.Name, URL: req.URL, Secret: "s3cret", Events: req.Events}, nil
+				},
+			}
+
+			rec := serveIntegration(service, http.MethodPost, "/internal/integrations", tt.body)
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if tt.expectedStatus != http.StatusCreated {
+				return
+			}
+			var created dto.CreatedIntegration
+			if err := json.NewDecoder(rec.Body).Decode(&created); err != nil {
+				t.Fatal(err)
+			}
+			if created.Secret != "s3cret" || created.Name != "wiki" || gotReq.URL != "https://wiki.example.com/hook" || len(created.Events) != 1 {
+				t.Errorf("unexpected response %+v for request %+v", created, gotReq)
+			}
+		})
+	}
+}
+
+func TestIntegrationHandler_ListIntegrations_OmitsSecret(t *testing.T) {
+	service := &mocks.MockIntegrationService{
+		ListIntegrationsFunc: func(ctx context.Context) ([]models.Integration, error) {
+			return []models.Integration{{ID: primitive.NewObjectID(), Name: "wiki", URL: "https://wiki.example.com/hook", Secret: "s3cret"}}, nil
+		},
+	}
+
+	rec := serveIntegration(service, http.MethodGet, "/internal/integrations", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	if body := rec.Body.String(); strings.Contains(body, "s3cret") || !strings.Contains(body, `"events":[]`) {
+		t.Errorf("expected no secret and empty events, got %s", body)
+	}
+}
+
+func TestIntegrationHandler_DeleteAndListDeliveries(t *testing.T) {
+	var gotID string
+	var gotLimit int
+	service := &mocks.MockIntegrationService{
+		DeleteIntegrationFunc: func(ctx context.Context, id string) error {
+			if id == "missing" {
+				return services.ErrIntegrationNotFound
+			}
+			return nil
+		},
+		ListDeliveriesFunc: func(ctx context.Context, id string, limit int) ([]models.IntegrationDelivery, error) {
+			gotID, gotLimit = id, limit
+			if id == "missing" {
+				return nil, services.ErrIntegrationNotFound
+			}
+			return []models.IntegrationDelivery{{Event: models.IntegrationItemAdded, Status: models.DeliveryDelivered, Payload: `{"event":"item.added"}`}}, nil
+		},
+	}
+
+	if rec := serveIntegration(service, http.MethodDelete, "/internal/integrations/abc", ""); rec.Code != http.StatusOK {
+		t.Errorf("expected status %d deleting, got %d", http.StatusOK, rec.Code)
+	}
+	if rec := serveIntegration(service, http.MethodDelete, "/internal/integrations/missing", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("expected status %d deleting a missing integration, got %d", http.StatusNotFound, rec.Code)
+	}
+
+	rec := serveIntegration(service, http.MethodGet, "/internal/integrations/abc/deliveries?limit=5", "")
+	if rec.Code != http.StatusOK || gotID != "abc" || gotLimit != 5 {
+		t.Fatalf("expected status %d for abc with limit 5, got %d for %q with %d", http.StatusOK, rec.Code, gotID, gotLimit)
+	}
+	if !strings.Contains(rec.Body.String(), `"payload":{"event":"item.added"}`) {
+		t.Errorf("expected the payload inline, got %s", rec.Body.String())
+	}
+	if rec := serveIntegration(service, http.MethodGet, "/internal/integrations/missing/deliveries", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("expected status %d for a missing integration, got %d", http.StatusNotFound, rec.Code)
+	}
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"github.com/graytonio/warframe-wishlist/pkg/response"
+)
+
+// ItemAutocompleteHandler serves name suggestions as the user types.
+type ItemAutocompleteHandler struct {
+	autocompleteService services.ItemAutocompleteServiceInterface
+}
+
+func NewItemAutocompleteHandler(autocompleteService services.ItemAutocompleteServiceInterface) *ItemAutocompleteHandler {
+	return &ItemAutocompleteHandler{autocompleteService: autocompleteService}
+}
+
+func (h *ItemAutocompleteHandler) Autocomplete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	q := query.Get("q")
+	limit, _ := strconv.Atoi(query.Get("limit"))
+
+	logger.Debug(ctx, "handler: Autocomplete called", "query", q, "limit", limit)
+
+	suggestions, err := h.autocompleteService.Suggest(ctx, q, limit)
+	if err != nil {
+		logger.Error(ctx, "handler: Autocomplete - failed to suggest items", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to suggest items")
+		return
+	}
+
+	logger.Debug(ctx, "handler: Autocomplete - success", "count", len(suggestions))
+	response.JSON(w, http.StatusOK, dto.NewItemAutocompleteResponse(suggestions))
+}
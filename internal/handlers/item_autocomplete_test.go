@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+func TestItemAutocompleteHandler_Autocomplete(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		mockError      error
+		expectedStatus int
+		expectedQuery  string
+		expectedLimit  int
+	}{
+		{name: "query", url: "/api/v1/items/autocomplete?q=rhi", expectedStatus: http.StatusOK, expectedQuery: "rhi"},
+		{name: "query and limit", url: "/api/v1/items/autocomplete?q=rhi&limit=3", expectedStatus: http.StatusOK, expectedQuery: "rhi", expectedLimit: 3},
+		{name: "service error", url: "/api/v1/items/autocomplete?q=rhi", mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError, expectedQuery: "rhi"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotQuery string
+			var gotLimit int
+			service := &mocks.MockItemAutocompleteService{
+				SuggestFunc: func(ctx context.Context, query string, limit int) ([]models.ItemSuggestion, error) {
+					gotQuery, gotLimit = query, limit
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return []models.ItemSuggestion{{UniqueName: "/Lotus/Powersuits/Rhino/Rhino", Name: "Rhino", Category: "Warframes", ImageName: "rhino.png"}}, nil
+				},
+			}
+			handler := NewItemAutocompleteHandler(service)
+
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			rec := httptest.NewRecorder()
+
+			handler.Autocomplete(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if gotQuery != tt.expectedQuery || gotLimit != tt.expectedLimit {
+				t.Errorf("expected q=%q limit=%d, got q=%q limit=%d", tt.expectedQuery, tt.expectedLimit, gotQuery, gotLimit)
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var body dto.ItemAutocompleteResponse
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			expected := dto.ItemSuggestion{UniqueName: "/Lotus/Powersuits/Rhino/Rhino", Name: "Rhino", Category: "Warframes", ImageName: "rhino.png"}
+			if body.Count != 1 || len(body.Suggestions) != 1 || body.Suggestions[0] != expected {
+				t.Errorf("unexpected body: %+v", body)
+			}
+		})
+	}
+}
+
+func TestItemAutocompleteHandler_NoSuggestions(t *testing.T) {
+	handler := NewItemAutocompleteHandler(&mocks.MockItemAutocompleteService{})
+
+	rec := httptest.NewRecorder()
+	handler.Autocomplete(rec, httptest.NewRequest(http.MethodGet, "/api/v1/items/autocomplete", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+	if body := rec.Body.String(); body != "{\"suggestions\":[],\"count\":0}\n" {
+		t.Errorf("expected an empty suggestions array, got %s", body)
+	}
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/cdn"
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"github.com/graytonio/warframe-wishlist/pkg/response"
+)
+
+// ItemChangesHandler serves the feed of items changed by recent data syncs.
+type ItemChangesHandler struct {
+	itemChangeService services.ItemChangeServiceInterface
+}
+
+func NewItemChangesHandler(itemChangeService services.ItemChangeServiceInterface) *ItemChangesHandler {
+	return &ItemChangesHandler{itemChangeService: itemChangeService}
+}
+
+func (h *ItemChangesHandler) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	var since time.Time
+	if raw := query.Get("since"); raw != "" {
+		var err error
+		since, err = time.Parse(time.RFC3339, raw)
+		if err != nil {
+			logger.Warn(ctx, "handler: ListItemChanges - invalid since", "since", raw, "error", err)
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidSince, "since must be an RFC 3339 timestamp")
+			return
+		}
+	}
+	limit, _ := strconv.Atoi(query.Get("limit"))
+
+	logger.Debug(ctx, "handler: ListItemChanges called", "since", since, "limit", limit)
+
+	changes, err := h.itemChangeService.ListChanges(ctx, since, limit)
+	if err != nil {
+		logger.Error(ctx, "handler: ListItemChanges - failed to list item changes", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to list item changes")
+		return
+	}
+
+	keys := make([]string, len(changes.Changes))
+	for i, change := range changes.Changes {
+		keys[i] = cdn.ItemKey(change.UniqueName)
+	}
+	cdn.AddKeys(w.Header(), keys...)
+
+	logger.Info(ctx, "handler: ListItemChanges - success", "count", changes.Count)
+	response.JSON(w, http.StatusOK, dto.NewItemChangesResponse(changes))
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+func TestItemChangesHandler_List(t *testing.T) {
+	since := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		url            string
+		mockError      error
+		expectedStatus int
+		expectedSince  time.Time
+		expectedLimit  int
+	}{
+		{name: "defaults", url: "/api/v1/items/changes", expectedStatus: http.StatusOK},
+		{name: "since and limit", url: "/api/v1/items/changes?since=2026-03-01T00:00:00Z&limit=5", expectedStatus: http.StatusOK, expectedSince: since, expectedLimit: 5},
+		{name: "invalid since", url: "/api/v1/items/changes?since=yesterday", expectedStatus: http.StatusBadRequest},
+		{name: "service error", url: "/api/v1/items/changes", mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotSince time.Time
+			var gotLimit int
+			service := &mocks.MockItemChangeService{
+				ListChangesFunc: func(ctx context.Context, since time.Time, limit int) (*models.ItemChangesResponse, error) {
+					gotSince, gotLimit = since, limit
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return &models.ItemChangesResponse{
+						Since:   since,
+						Changes: []models.ItemChange{{UniqueName: "/Lotus/Alpha", Kinds: []string{models.ItemChangeRecipe}}},
+						Count:   1,
+					}, nil
+				},
+			}
+			handler := NewItemChangesHandler(service)
+
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			rec := httptest.NewRecorder()
+
+			handler.List(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			if !gotSince.Equal(tt.expectedSince) || gotLimit != tt.expectedLimit {
+				t.Errorf("expected since=%v limit=%d, got since=%v limit=%d", tt.expectedSince, tt.expectedLimit, gotSince, gotLimit)
+			}
+
+			var body models.ItemChangesResponse
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if body.Count != 1 || body.Changes[0].UniqueName != "/Lotus/Alpha" {
+				t.Errorf("unexpected body: %+v", body)
+			}
+			if keys := rec.Header().Get("Surrogate-Key"); !strings.Contains(keys, "item:/Lotus/Alpha") {
+				t.Errorf("expected item surrogate key, got %q", keys)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"github.com/graytonio/warframe-wishlist/pkg/response"
+)
+
+// exportRangeUnit is the Range unit of item exports. Ranges count items, one
+// per NDJSON line, rather than bytes, so a client resumes after the last
+// complete line it received.
+const exportRangeUnit = "items"
+
+// exportFlushEvery is how many items are written between flushes, so clients
+// receive a long export as it is read.
+const exportFlushEvery = 100
+
+// ItemExportHandler streams whole item categories as NDJSON, for clients
+// mirroring the item data.
+type ItemExportHandler struct {
+	exportService services.ItemExportServiceInterface
+	// version returns the item data version. It tags exports, so a resumed
+	// download is never stitched together across a data sync.
+	version func() string
+}
+
+func NewItemExportHandler(exportService services.ItemExportServiceInterface, version func() string) *ItemExportHandler {
+	return &ItemExportHandler{exportService: exportService, version: version}
+}
+
+// Export writes every item of ?category= as one item detail per line, in
+// uniqueName order. A Range of items (items=500- resumes after 500 lines) is
+// served as 206, unless an If-Range names another data version.
+func (h *ItemExportHandler) Export(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	category := r.URL.Query().Get("category")
+
+	logger.Debug(ctx, "handler: ExportItems called", "category", category, "range", r.Header.Get("Range"))
+
+	total, err := h.exportService.CountCategory(ctx, category)
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownCategory) {
+			logger.Warn(ctx, "handler: ExportItems - unknown category", "category", category)
+			rejected(w, http.StatusBadRequest, err)
+			return
+		}
+		logger.Error(ctx, "handler: ExportItems - failed to count items", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to export items")
+		return
+	}
+
+	etag := ""
+	if version := h.version(); version != "" {
+		etag = strconv.Quote(version + ":" + category)
+		w.Header().Set("ETag", etag)
+	}
+	w.Header().Set("Accept-Ranges", exportRangeUnit)
+
+	status, skip, limit := http.StatusOK, 0, 0
+	if raw := r.Header.Get("Range"); raw != "" && ifRangeMatches(r.Header.Get("If-Range"), etag) {
+		if first, last, ok := parseItemsRange(raw, total); ok {
+			if first >= total {
+				logger.Warn(ctx, "handler: ExportItems - range not satisfiable", "range", raw, "total", total)
+				w.Header().Set("Content-Range", fmt.Sprintf("%s */%d", exportRangeUnit, total))
+				response.Error(w, http.StatusRequestedRangeNotSatisfiable, "range not satisfiable")
+				return
+			}
+			status, skip, limit = http.StatusPartialContent, first, last-first+1
+			w.Header().Set("Content-Range", fmt.Sprintf("%s %d-%d/%d", exportRangeUnit, first, last, total))
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.ndjson"`, category))
+	w.WriteHeader(status)
+
+	encoder := json.NewEncoder(w)
+	controller := http.NewResponseController(w)
+	written := 0
+	err = h.exportService.ExportCategory(ctx, category, skip, limit, func(item *models.Item) error {
+		if err := encoder.Encode(dto.NewItemDetail(item)); err != nil {
+			return err
+		}
+		if written++; written%exportFlushEvery == 0 {
+			controller.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		// The status is already sent; the client sees the export end early
+		// and can resume from the last line it received.
+		logger.Error(ctx, "handler: ExportItems - export cut short", "category", category, "written", written, "error", err)
+		return
+	}
+
+	logger.Info(ctx, "handler: ExportItems - success", "category", category, "written", written, "status", status)
+}
+
+// parseItemsRange parses a single items range ("items=10-19", "items=10-" or
+// the last n, "items=-5") against total items, clamping its end to the last
+// item. ok is false for anything else, including byte ranges and several
+// ranges, which are served in full. A start past the end is returned as is
+// for the caller to reject.
+func parseItemsRange(raw string, total int) (first, last int, ok bool) {
+	spec, found := strings.CutPrefix(raw, exportRangeUnit+"=")
+	if !found || strings.Contains(spec, ",") {
+		return 0, 0, false
+	}
+	start, end, found := strings.Cut(strings.TrimSpace(spec), "-")
+	if !found {
+		return 0, 0, false
+	}
+
+	if start == "" {
+		n, err := strconv.Atoi(end)
+		if err != nil || n <= 0 {
+			return 0, 0, false
+		}
+		return max(total-n, 0), total - 1, total > 0
+	}
+
+	first, err := strconv.Atoi(start)
+	if err != nil || first < 0 {
+		return 0, 0, false
+	}
+	last = total - 1
+	if end != "" {
+		if last, err = strconv.Atoi(end); err != nil || last < first {
+			return 0, 0, false
+		}
+		last = min(last, total-1)
+	}
+	return first, last, true
+}
+
+// ifRangeMatches reports whether a Range is served given the request's
+// If-Range: always without one, and otherwise only when it names the current
+// export, etag. Dates are not matched, since exports have no modification
+// time.
+func ifRangeMatches(ifRange, etag string) bool {
+	return ifRange == "" || (etag != "" && ifRange == etag)
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+)
+
+// newExportService fakes a mods category of count items.
+func newExportService(count int) *mocks.MockItemExportService {
+	return &mocks.MockItemExportService{
+		CountCategoryFunc: func(ctx context.Context, category string) (int, error) {
+			if category != "mods" {
+				return 0, services.ErrUnknownCategory
+			}
+			return count, nil
+		},
+		ExportCategoryFunc: func(ctx context.Context, category string, skip, limit int, fn func(item *models.Item) error) error {
+			for i := skip; i < count && (limit == 0 || i < skip+limit); i++ {
+				if err := fn(&models.Item{UniqueName: fmt.Sprintf("/Lotus/Mods/%d", i), Name: fmt.Sprintf("Mod %d", i)}); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func TestItemExportHandler_Export(t *testing.T) {
+	const etag = `"20261017T000000Z:mods"`
+
+	tests := []struct {
+		name                 string
+		category             string
+		rangeHeader          string
+		ifRange              string
+		expectedStatus       int
+		expectedContentRange string
+		expectedNames        []string
+	}{
+		{name: "whole category", category: "mods", expectedStatus: http.StatusOK, expectedNames: []string{"Mod 0", "Mod 1", "Mod 2", "Mod 3", "Mod 4"}},
+		{name: "resume from an item", category: "mods", rangeHeader: "items=3-", expectedStatus: http.StatusPartialContent, expectedContentRange: "items 3-4/5", expectedNames: []string{"Mod 3", "Mod 4"}},
+		{name: "bounded range", category: "mods", rangeHeader: "items=1-2", expectedStatus: http.StatusPartialContent, expectedContentRange: "items 1-2/5", expectedNames: []string{"Mod 1", "Mod 2"}},
+		{name: "range end past the last item", category: "mods", rangeHeader: "items=4-99", expectedStatus: http.StatusPartialContent, expectedContentRange: "items 4-4/5", expectedNames: []string{"Mod 4"}},
+		{name: "last items", category: "mods", rangeHeader: "items=-2", expectedStatus: http.StatusPartialContent, expectedContentRange: "items 3-4/5", expectedNames: []string{"Mod 3", "Mod 4"}},
+		{name: "range past the end", category: "mods", rangeHeader: "items=5-", expectedStatus: http.StatusRequestedRangeNotSatisfiable, expectedContentRange: "items */5"},
+		{name: "byte range is ignored", category: "mods", rangeHeader: "bytes=0-10", expectedStatus: http.StatusOK, expectedNames: []string{"Mod 0", "Mod 1", "Mod 2", "Mod 3", "Mod 4"}},
+		{name: "several ranges are ignored", category: "mods", rangeHeader: "items=0-1,3-4", expectedStatus: http.StatusOK, expectedNames: []string{"Mod 0", "Mod 1", "Mod 2", "Mod 3", "Mod 4"}},
+		{name: "matching if-range", category: "mods", rangeHeader: "items=4-", ifRange: etag, expectedStatus: http.StatusPartialContent, expectedContentRange: "items 4-4/5", expectedNames: []string{"Mod 4"}},
+		{name: "stale if-range restarts the export", category: "mods", rangeHeader: "items=4-", ifRange: `"20260101T000000Z:mods"`, expectedStatus: http.StatusOK, expectedNames: []string{"Mod 0", "Mod 1", "Mod 2", "Mod 3", "Mod 4"}},
+		{name: "unknown category", category: "planets", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewItemExportHandler(newExportService(5), func() string { return "20261017T000000Z" })
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/items/export?category="+tt.category, nil)
+			if tt.rangeHeader != "" {
+				req.Header.Set("Range", tt.rangeHeader)
+			}
+			if tt.ifRange != "" {
+				req.Header.Set("If-Range", tt.ifRange)
+			}
+			rec := httptest.NewRecorder()
+
+			handler.Export(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if got := rec.Header().Get("Content-Range"); got != tt.expectedContentRange {
+				t.Errorf("expected Content-Range %q, got %q", tt.expectedContentRange, got)
+			}
+			if tt.expectedStatus == http.StatusBadRequest {
+				if !strings.Contains(rec.Body.String(), `"code":"unknown_category"`) {
+					t.Errorf("expected unknown_category, got %s", rec.Body.String())
+				}
+				return
+			}
+			if got := rec.Header().Get("Accept-Ranges"); got != "items" {
+				t.Errorf("expected Accept-Ranges items, got %q", got)
+			}
+			if got := rec.Header().Get("ETag"); got != etag {
+				t.Errorf("expected ETag %s, got %s", etag, got)
+			}
+			if tt.expectedNames == nil {
+				return
+			}
+			if got := rec.Header().Get("Content-Type"); got != "application/x-ndjson" {
+				t.Errorf("expected NDJSON, got %q", got)
+			}
+
+			var names []string
+			scanner := bufio.NewScanner(rec.Body)
+			for scanner.Scan() {
+				var item struct {
+					Name string `json:"name"`
+				}
+				if err := json.Unmarshal(scanner.Bytes(), &item); err != nil {
+					t.Fatalf("line %q is not JSON: %v", scanner.Text(), err)
+				}
+				names = append(names, item.Name)
+			}
+			if strings.Join(names, ",") != strings.Join(tt.expectedNames, ",") {
+				t.Errorf("expected %v, got %v", tt.expectedNames, names)
+			}
+		})
+	}
+}
+
+func TestItemExportHandler_Export_WithoutDataVersion(t *testing.T) {
+	handler := NewItemExportHandler(newExportService(5), func() string { return "" })
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/items/export?category=mods", nil)
+	req.Header.Set("Range", "items=4-")
+	req.Header.Set("If-Range", `"20261017T000000Z:mods"`)
+	rec := httptest.NewRecorder()
+
+	handler.Export(rec, req)
+
+	// Without a data version no If-Range can be confirmed current.
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") != "" {
+		t.Errorf("expected a full export without an ETag, got %d %q", rec.Code, rec.Header().Get("ETag"))
+	}
+}
+
+func TestItemExportHandler_Export_ServiceError(t *testing.T) {
+	service := newExportService(5)
+	service.CountCategoryFunc = func(ctx context.Context, category string) (int, error) {
+		return 0, errors.New("database error")
+	}
+	handler := NewItemExportHandler(service, func() string { return "" })
+
+	rec := httptest.NewRecorder()
+	handler.Export(rec, httptest.NewRequest(http.MethodGet, "/api/v1/items/export?category=mods", nil))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, rec.Code)
+	}
+}
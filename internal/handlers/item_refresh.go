@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"github.com/graytonio/warframe-wishlist/pkg/response"
+)
+
+// ItemRefreshHandler serves the admin route reporting the scheduled item
+// refresh. main restricts it to admins.
+type ItemRefreshHandler struct {
+	refreshService services.ItemRefreshServiceInterface
+}
+
+// NewItemRefreshHandler returns the handler for the item refresh status
+// route.
+func NewItemRefreshHandler(refreshService services.ItemRefreshServiceInterface) *ItemRefreshHandler {
+	return &ItemRefreshHandler{
+		refreshService: refreshService,
+	}
+}
+
+func (h *ItemRefreshHandler) Status(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: SyncStatus called")
+
+	status, err := h.refreshService.Status(ctx)
+	if err != nil {
+		logger.Error(ctx, "handler: SyncStatus - failed to get sync status", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to get sync status")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, dto.NewItemRefreshStatus(status))
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+func TestItemRefreshHandler_Status(t *testing.T) {
+	lastSuccess := time.Date(2026, 10, 17, 6, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name           string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "success", expectedStatus: http.StatusOK},
+		{name: "service error", mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.MockItemRefreshService{
+				StatusFunc: func(ctx context.Context) (*models.ItemRefreshStatus, error) {
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return &models.ItemRefreshStatus{
+						Enabled:   true,
+						Interval:  6 * time.Hour,
+						NextRunAt: lastSuccess.Add(6 * time.Hour),
+						Last: &models.SyncStatus{
+							Name:          models.SyncStatusItems,
+							LastSuccessAt: lastSuccess,
+							Version:       "20261017T060000Z",
+							Stats:         models.ItemSyncStats{Updated: 3},
+						},
+					}, nil
+				},
+			}
+			handler := NewItemRefreshHandler(service)
+
+			rec := httptest.NewRecorder()
+			handler.Status(rec, httptest.NewRequest(http.MethodGet, "/api/v1/admin/sync/status", nil))
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var body dto.ItemRefreshStatus
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if !body.Enabled || body.IntervalSeconds != 21600 || body.NextRunAt == nil {
+				t.Errorf("unexpected worker state %+v", body)
+			}
+			if body.LastRun == nil || body.LastRun.Version != "20261017T060000Z" || body.LastRun.Stats.Updated != 3 || !body.LastRun.LastSuccessAt.Equal(lastSuccess) {
+				t.Errorf("unexpected last run %+v", body.LastRun)
+			}
+		})
+	}
+}
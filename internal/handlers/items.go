@@ -3,8 +3,11 @@ package handlers
 import (
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/graytonio/warframe-wishlist/internal/cdn"
+	"github.com/graytonio/warframe-wishlist/internal/dto"
 	"github.com/graytonio/warframe-wishlist/internal/models"
 	"github.com/graytonio/warframe-wishlist/internal/services"
 	"github.com/graytonio/warframe-wishlist/pkg/logger"
@@ -26,44 +29,66 @@ func (h *ItemHandler) Search(w http.ResponseWriter, r *http.Request) {
 	limit, _ := strconv.Atoi(query.Get("limit"))
 	offset, _ := strconv.Atoi(query.Get("offset"))
 
+	includeArchived, _ := strconv.ParseBool(query.Get("includeArchived"))
+
 	params := models.SearchParams{
-		Query:    query.Get("q"),
-		Category: query.Get("category"),
-		Limit:    limit,
-		Offset:   offset,
+		Query:           query.Get("q"),
+		Category:        query.Get("category"),
+		Limit:           limit,
+		Offset:          offset,
+		IncludeArchived: includeArchived,
 	}
 
-	logger.Debug(ctx, "handler: Search called", "query", params.Query, "category", params.Category, "limit", params.Limit, "offset", params.Offset)
+	logger.Debug(ctx, "handler: Search called", "query", params.Query, "category", params.Category, "limit", params.Limit, "offset", params.Offset, "includeArchived", params.IncludeArchived)
 
-	items, err := h.itemService.Search(ctx, params)
+	page, err := h.itemService.Search(ctx, params)
 	if err != nil {
 		logger.Error(ctx, "handler: Search - failed to search items", "error", err)
 		response.Error(w, http.StatusInternalServerError, "failed to search items")
 		return
 	}
 
-	logger.Info(ctx, "handler: Search - success", "resultCount", len(items))
-	response.JSON(w, http.StatusOK, map[string]interface{}{
-		"items": items,
-		"count": len(items),
-	})
+	logger.Info(ctx, "handler: Search - success", "resultCount", len(page.Items), "total", page.Total)
+	addSearchResultKeys(w, page.Items)
+	if wantsHAL(r) {
+		response.HAL(w, http.StatusOK, halItemSearch(r, params.Normalized(), dto.NewItemSearchResponse(page)))
+		return
+	}
+	response.JSON(w, http.StatusOK, dto.NewItemSearchResponse(page))
 }
 
+// recipeTreeSuffix ends an item path to request its recipe tree. Chi cannot
+// route on a suffix after a wildcard, so GetByUniqueName dispatches on it; no
+// uniqueName ends in it.
+const recipeTreeSuffix = "/recipe-tree"
+
 func (h *ItemHandler) GetByUniqueName(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	if strings.HasSuffix(chi.URLParam(r, "*"), recipeTreeSuffix) {
+		h.GetRecipeTree(w, r)
+		return
+	}
+
 	// Use wildcard param to capture full path including slashes (e.g., /Lotus/Types/Items/...)
-	uniqueName := chi.URLParam(r, "*")
-	if uniqueName == "" {
-		logger.Warn(ctx, "handler: GetByUniqueName - uniqueName is required")
-		response.Error(w, http.StatusBadRequest, "uniqueName is required")
+	uniqueName, err := uniqueNameParam(r)
+	if err != nil {
+		logger.Warn(ctx, "handler: GetByUniqueName - invalid uniqueName", "error", err)
+		rejected(w, http.StatusBadRequest, err)
+		return
+	}
+
+	includes, unknown := parseItemIncludes(r.URL.Query().Get("include"))
+	if unknown != "" {
+		logger.Warn(ctx, "handler: GetByUniqueName - unknown include", "include", unknown)
+		response.ErrorCode(w, http.StatusBadRequest, response.CodeUnknownInclude, "unknown include: "+unknown)
 		return
 	}
 
-	// Add leading slash to the uniqueName
-	uniqueName = "/" + uniqueName
+	logger.Debug(ctx, "handler: GetByUniqueName called", "uniqueName", uniqueName, "include", r.URL.Query().Get("include"))
 
-	logger.Debug(ctx, "handler: GetByUniqueName called", "uniqueName", uniqueName)
+	// Tag 404s too, so an item added by a later sync is purged from negative caches.
+	cdn.AddKeys(w.Header(), cdn.ItemKey(uniqueName))
 
 	item, err := h.itemService.GetByUniqueName(ctx, uniqueName)
 	if err != nil {
@@ -79,7 +104,67 @@ func (h *ItemHandler) GetByUniqueName(w http.ResponseWriter, r *http.Request) {
 	}
 
 	logger.Info(ctx, "handler: GetByUniqueName - success", "uniqueName", uniqueName, "itemName", item.Name)
-	response.JSON(w, http.StatusOK, item)
+	detail := dto.NewItemDetail(item)
+	if includes[includeStats] {
+		detail.Stats = dto.NewItemStats(item)
+	}
+	response.JSON(w, http.StatusOK, detail)
+}
+
+// includeStats adds the item's frame and weapon stats to item detail.
+const includeStats = "stats"
+
+// parseItemIncludes parses the comma-separated optional sections of item
+// detail. An unknown section is returned as the second value so the caller
+// can report it.
+func parseItemIncludes(raw string) (map[string]bool, string) {
+	includes := make(map[string]bool)
+	for _, key := range strings.Split(raw, ",") {
+		key = strings.TrimSpace(key)
+		switch key {
+		case "":
+		case includeStats:
+			includes[key] = true
+		default:
+			return nil, key
+		}
+	}
+	return includes, ""
+}
+
+// GetRecipeTree serves /items/{uniqueName}/recipe-tree, the item's full
+// crafting tree as nodes and edges.
+func (h *ItemHandler) GetRecipeTree(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	uniqueName, err := uniqueNameParamWithSuffix(r, recipeTreeSuffix)
+	if err != nil {
+		logger.Warn(ctx, "handler: GetRecipeTree - invalid uniqueName", "error", err)
+		rejected(w, http.StatusBadRequest, err)
+		return
+	}
+
+	logger.Debug(ctx, "handler: GetRecipeTree called", "uniqueName", uniqueName)
+
+	// Only the root is tagged: a data sync purges every tree through the data
+	// version key, and tagging every node could overflow the header.
+	cdn.AddKeys(w.Header(), cdn.ItemKey(uniqueName))
+
+	tree, err := h.itemService.GetRecipeTree(ctx, uniqueName)
+	if err != nil {
+		logger.Error(ctx, "handler: GetRecipeTree - failed to get recipe tree", "error", err, "uniqueName", uniqueName)
+		response.Error(w, http.StatusInternalServerError, "failed to get recipe tree")
+		return
+	}
+
+	if tree == nil {
+		logger.Warn(ctx, "handler: GetRecipeTree - item not found", "uniqueName", uniqueName)
+		response.Error(w, http.StatusNotFound, "item not found")
+		return
+	}
+
+	logger.Info(ctx, "handler: GetRecipeTree - success", "uniqueName", uniqueName, "nodeCount", len(tree.Nodes), "truncated", tree.Truncated)
+	response.JSON(w, http.StatusOK, dto.NewRecipeTree(tree))
 }
 
 func (h *ItemHandler) SearchReusableBlueprints(w http.ResponseWriter, r *http.Request) {
@@ -103,8 +188,16 @@ func (h *ItemHandler) SearchReusableBlueprints(w http.ResponseWriter, r *http.Re
 	}
 
 	logger.Info(ctx, "handler: SearchReusableBlueprints - success", "resultCount", len(items))
-	response.JSON(w, http.StatusOK, map[string]interface{}{
-		"items": items,
-		"count": len(items),
-	})
+	addSearchResultKeys(w, items)
+	response.JSON(w, http.StatusOK, dto.NewItemSearchResponse(&models.ItemSearchPage{Items: items, Total: len(items)}))
+}
+
+// addSearchResultKeys tags a search response with each result's item key so
+// changing any listed item invalidates the cached search.
+func addSearchResultKeys(w http.ResponseWriter, items []models.ItemSearchResult) {
+	keys := make([]string, len(items))
+	for i, item := range items {
+		keys[i] = cdn.ItemKey(item.UniqueName)
+	}
+	cdn.AddKeys(w.Header(), keys...)
 }
@@ -9,16 +9,18 @@ import (
 	"testing"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/graytonio/warframe-wishlist/internal/cdn"
 	"github.com/graytonio/warframe-wishlist/internal/models"
 )
 
 type mockItemService struct {
-	searchFunc                   func(ctx context.Context, params models.SearchParams) ([]models.ItemSearchResult, error)
+	searchFunc                   func(ctx context.Context, params models.SearchParams) (*models.ItemSearchPage, error)
 	getByUniqueNameFunc          func(ctx context.Context, uniqueName string) (*models.Item, error)
 	searchReusableBlueprintsFunc func(ctx context.Context, query string, limit int) ([]models.ItemSearchResult, error)
+	getRecipeTreeFunc            func(ctx context.Context, uniqueName string) (*models.RecipeTree, error)
 }
 
-func (m *mockItemService) Search(ctx context.Context, params models.SearchParams) ([]models.ItemSearchResult, error) {
+func (m *mockItemService) Search(ctx context.Context, params models.SearchParams) (*models.ItemSearchPage, error) {
 	if m.searchFunc != nil {
 		return m.searchFunc(ctx, params)
 	}
@@ -39,6 +41,13 @@ func (m *mockItemService) SearchReusableBlueprints(ctx context.Context, query st
 	return nil, nil
 }
 
+func (m *mockItemService) GetRecipeTree(ctx context.Context, uniqueName string) (*models.RecipeTree, error) {
+	if m.getRecipeTreeFunc != nil {
+		return m.getRecipeTreeFunc(ctx, uniqueName)
+	}
+	return nil, nil
+}
+
 func TestItemHandler_Search(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -89,8 +98,11 @@ func TestItemHandler_Search(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockService := &mockItemService{
-				searchFunc: func(ctx context.Context, params models.SearchParams) ([]models.ItemSearchResult, error) {
-					return tt.mockReturn, tt.mockError
+				searchFunc: func(ctx context.Context, params models.SearchParams) (*models.ItemSearchPage, error) {
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return &models.ItemSearchPage{Items: tt.mockReturn, Total: len(tt.mockReturn)}, nil
 				},
 			}
 
@@ -182,9 +194,9 @@ func TestItemHandler_Search_ParsesQueryParams(t *testing.T) {
 	var capturedParams models.SearchParams
 
 	mockService := &mockItemService{
-		searchFunc: func(ctx context.Context, params models.SearchParams) ([]models.ItemSearchResult, error) {
+		searchFunc: func(ctx context.Context, params models.SearchParams) (*models.ItemSearchPage, error) {
 			capturedParams = params
-			return []models.ItemSearchResult{}, nil
+			return &models.ItemSearchPage{}, nil
 		},
 	}
 
@@ -208,6 +220,65 @@ func TestItemHandler_Search_ParsesQueryParams(t *testing.T) {
 	}
 }
 
+func TestItemHandler_Search_ReportsTotal(t *testing.T) {
+	mockService := &mockItemService{
+		searchFunc: func(ctx context.Context, params models.SearchParams) (*models.ItemSearchPage, error) {
+			return &models.ItemSearchPage{Items: []models.ItemSearchResult{{UniqueName: "/Lotus/Ash", Name: "Ash"}}, Total: 42}, nil
+		},
+	}
+
+	handler := NewItemHandler(mockService)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/items/search?q=ash&limit=1", nil)
+	rec := httptest.NewRecorder()
+
+	handler.Search(rec, req)
+
+	var response struct {
+		Count int `json:"count"`
+		Total int `json:"total"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if response.Count != 1 || response.Total != 42 {
+		t.Errorf("expected count 1 of 42, got %d of %d", response.Count, response.Total)
+	}
+}
+
+func TestItemHandler_Search_IncludeArchived(t *testing.T) {
+	tests := []struct {
+		queryParams string
+		expected    bool
+	}{
+		{queryParams: "?q=test", expected: false},
+		{queryParams: "?q=test&includeArchived=true", expected: true},
+		{queryParams: "?q=test&includeArchived=1", expected: true},
+		{queryParams: "?q=test&includeArchived=nope", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.queryParams, func(t *testing.T) {
+			var capturedParams models.SearchParams
+			mockService := &mockItemService{
+				searchFunc: func(ctx context.Context, params models.SearchParams) (*models.ItemSearchPage, error) {
+					capturedParams = params
+					return &models.ItemSearchPage{}, nil
+				},
+			}
+
+			handler := NewItemHandler(mockService)
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/items/search"+tt.queryParams, nil)
+			rec := httptest.NewRecorder()
+
+			handler.Search(rec, req)
+
+			if capturedParams.IncludeArchived != tt.expected {
+				t.Errorf("expected includeArchived %v, got %v", tt.expected, capturedParams.IncludeArchived)
+			}
+		})
+	}
+}
+
 func TestItemHandler_GetByUniqueName_EmptyParam(t *testing.T) {
 	mockService := &mockItemService{}
 	handler := NewItemHandler(mockService)
@@ -221,3 +292,287 @@ func TestItemHandler_GetByUniqueName_EmptyParam(t *testing.T) {
 		t.Errorf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
 	}
 }
+
+func TestItemHandler_GetByUniqueName_DegradedResponse(t *testing.T) {
+	mockService := &mockItemService{
+		getByUniqueNameFunc: func(ctx context.Context, uniqueName string) (*models.Item, error) {
+			item := &models.Item{UniqueName: uniqueName, Name: "Ash"}
+			item.MarkDegraded(models.SectionComponentPages, "component page lookup unavailable")
+			return item, nil
+		},
+	}
+
+	handler := NewItemHandler(mockService)
+
+	r := chi.NewRouter()
+	r.Get("/api/v1/items/*", handler.GetByUniqueName)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/items/Lotus/Ash", nil)
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	var response struct {
+		Name     string                   `json:"name"`
+		Degraded []models.DegradedSection `json:"degraded"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if response.Name != "Ash" {
+		t.Errorf("expected core item data, got name '%s'", response.Name)
+	}
+	if len(response.Degraded) != 1 || response.Degraded[0].Section != models.SectionComponentPages {
+		t.Errorf("expected degraded component pages section, got %+v", response.Degraded)
+	}
+}
+
+func TestItemHandler_GetByUniqueName_IncludeStats(t *testing.T) {
+	mockService := &mockItemService{
+		getByUniqueNameFunc: func(ctx context.Context, uniqueName string) (*models.Item, error) {
+			return &models.Item{
+				UniqueName: uniqueName,
+				Name:       "Ash",
+				FrameStats: models.FrameStats{
+					Health:    455,
+					Abilities: []models.Ability{{UniqueName: "/Lotus/Abilities/Shuriken", Name: "Shuriken"}},
+				},
+			}, nil
+		},
+	}
+
+	handler := NewItemHandler(mockService)
+	r := chi.NewRouter()
+	r.Get("/api/v1/items/*", handler.GetByUniqueName)
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectStats    bool
+	}{
+		{name: "stats omitted by default", query: "", expectedStatus: http.StatusOK},
+		{name: "stats included", query: "?include=stats", expectedStatus: http.StatusOK, expectStats: true},
+		{name: "unknown include", query: "?include=stats,prices", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/items/Lotus/Ash"+tt.query, nil)
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if rec.Code != http.StatusOK {
+				return
+			}
+
+			var response struct {
+				Stats *struct {
+					Frame *struct {
+						Health    float64          `json:"health"`
+						Abilities []models.Ability `json:"abilities"`
+					} `json:"frame"`
+					Weapon json.RawMessage `json:"weapon"`
+				} `json:"stats"`
+			}
+			if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if !tt.expectStats {
+				if response.Stats != nil {
+					t.Errorf("expected null stats, got %+v", response.Stats)
+				}
+				return
+			}
+			if response.Stats == nil || response.Stats.Frame == nil || response.Stats.Frame.Health != 455 || len(response.Stats.Frame.Abilities) != 1 {
+				t.Fatalf("expected frame stats, got %+v", response.Stats)
+			}
+			if string(response.Stats.Weapon) != "null" {
+				t.Errorf("expected null weapon stats, got %s", response.Stats.Weapon)
+			}
+		})
+	}
+}
+
+func TestItemHandler_SurrogateKeys(t *testing.T) {
+	mockService := &mockItemService{
+		searchFunc: func(ctx context.Context, params models.SearchParams) (*models.ItemSearchPage, error) {
+			return &models.ItemSearchPage{Items: []models.ItemSearchResult{{UniqueName: "/Lotus/Ash"}, {UniqueName: "/Lotus/Ember"}}, Total: 2}, nil
+		},
+		getByUniqueNameFunc: func(ctx context.Context, uniqueName string) (*models.Item, error) {
+			return nil, nil
+		},
+	}
+
+	handler := NewItemHandler(mockService)
+
+	r := chi.NewRouter()
+	r.Get("/api/v1/items/search", handler.Search)
+	r.Get("/api/v1/items/*", handler.GetByUniqueName)
+
+	tests := []struct {
+		name     string
+		url      string
+		expected string
+	}{
+		{name: "search tags each result", url: "/api/v1/items/search?q=a", expected: "item:/Lotus/Ash item:/Lotus/Ember"},
+		{name: "not found is tagged", url: "/api/v1/items/Lotus/Missing", expected: "item:/Lotus/Missing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.url, nil))
+
+			if got := rec.Header().Get(cdn.SurrogateKeyHeader); got != tt.expected {
+				t.Errorf("expected surrogate keys %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestItemHandler_GetRecipeTree(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		mockReturn     *models.RecipeTree
+		mockError      error
+		expectedStatus int
+		expectedName   string
+	}{
+		{
+			name: "tree found",
+			url:  "/api/v1/items/Lotus/Ash/recipe-tree",
+			mockReturn: &models.RecipeTree{
+				Root:         "n0",
+				Nodes:        []models.RecipeNode{{ID: "n0", UniqueName: "/Lotus/Ash", Crafts: 1}, {ID: "n1", UniqueName: "/Lotus/Ferrite", Quantity: 100}},
+				Edges:        []models.RecipeEdge{{From: "n0", To: "n1", ItemCount: 100}},
+				TotalCredits: 25000,
+			},
+			expectedStatus: http.StatusOK,
+			expectedName:   "/Lotus/Ash",
+		},
+		{
+			name:           "escaped uniqueName",
+			url:            "/api/v1/items/%2FLotus%2FAsh/recipe-tree",
+			mockReturn:     &models.RecipeTree{Root: "n0", Nodes: []models.RecipeNode{{ID: "n0"}}},
+			expectedStatus: http.StatusOK,
+			expectedName:   "/Lotus/Ash",
+		},
+		{
+			name:           "item not found",
+			url:            "/api/v1/items/Lotus/Missing/recipe-tree",
+			expectedStatus: http.StatusNotFound,
+			expectedName:   "/Lotus/Missing",
+		},
+		{
+			name:           "service error",
+			url:            "/api/v1/items/Lotus/Ash/recipe-tree",
+			mockError:      errors.New("database error"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedName:   "/Lotus/Ash",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotName string
+			mockService := &mockItemService{
+				getByUniqueNameFunc: func(ctx context.Context, uniqueName string) (*models.Item, error) {
+					t.Errorf("expected the recipe tree to be requested, got item lookup for %s", uniqueName)
+					return nil, nil
+				},
+				getRecipeTreeFunc: func(ctx context.Context, uniqueName string) (*models.RecipeTree, error) {
+					gotName = uniqueName
+					return tt.mockReturn, tt.mockError
+				},
+			}
+
+			handler := NewItemHandler(mockService)
+
+			r := chi.NewRouter()
+			r.Get("/api/v1/items/*", handler.GetByUniqueName)
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.url, nil))
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if gotName != tt.expectedName {
+				t.Errorf("expected uniqueName %q, got %q", tt.expectedName, gotName)
+			}
+		})
+	}
+}
+
+func TestItemHandler_GetRecipeTree_Response(t *testing.T) {
+	mockService := &mockItemService{
+		getRecipeTreeFunc: func(ctx context.Context, uniqueName string) (*models.RecipeTree, error) {
+			tree := &models.RecipeTree{
+				Root: "n0",
+				Nodes: []models.RecipeNode{
+					{ID: "n0", UniqueName: uniqueName, Name: "Loop", Crafts: 1},
+					{ID: "n1", UniqueName: uniqueName, Name: "Loop", Quantity: 1, Crafts: 1, Truncated: models.RecipeTruncatedCycle},
+				},
+				Edges:     []models.RecipeEdge{{From: "n0", To: "n1", ItemCount: 1}},
+				Truncated: true,
+			}
+			tree.MarkDegraded(models.SectionRecipeTree, "some components could not be looked up")
+			return tree, nil
+		},
+	}
+
+	handler := NewItemHandler(mockService)
+
+	r := chi.NewRouter()
+	r.Get("/api/v1/items/*", handler.GetByUniqueName)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/items/Lotus/Loop/recipe-tree", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	if got := rec.Header().Get(cdn.SurrogateKeyHeader); got != "item:/Lotus/Loop" {
+		t.Errorf("expected root surrogate key, got %q", got)
+	}
+
+	var response struct {
+		Root  string `json:"root"`
+		Nodes []struct {
+			ID        string `json:"id"`
+			Truncated string `json:"truncated"`
+		} `json:"nodes"`
+		Edges []struct {
+			From      string `json:"from"`
+			To        string `json:"to"`
+			ItemCount int    `json:"itemCount"`
+		} `json:"edges"`
+		Truncated bool                     `json:"truncated"`
+		Degraded  []models.DegradedSection `json:"degraded"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if response.Root != "n0" || len(response.Nodes) != 2 || len(response.Edges) != 1 {
+		t.Fatalf("unexpected tree %+v", response)
+	}
+	if response.Nodes[1].Truncated != models.RecipeTruncatedCycle || !response.Truncated {
+		t.Errorf("expected cycle truncation marker, got %+v", response)
+	}
+	if response.Edges[0].From != "n0" || response.Edges[0].To != "n1" || response.Edges[0].ItemCount != 1 {
+		t.Errorf("unexpected edge %+v", response.Edges[0])
+	}
+	if len(response.Degraded) != 1 || response.Degraded[0].Section != models.SectionRecipeTree {
+		t.Errorf("expected degraded recipe tree section, got %+v", response.Degraded)
+	}
+}
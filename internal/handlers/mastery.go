@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+	"github.com/graytonio/warframe-wishlist/internal/middleware"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"github.com/graytonio/warframe-wishlist/pkg/response"
+)
+
+type MasteryHandler struct {
+	masteryService services.MasteryServiceInterface
+}
+
+func NewMasteryHandler(masteryService services.MasteryServiceInterface) *MasteryHandler {
+	return &MasteryHandler{
+		masteryService: masteryService,
+	}
+}
+
+func (h *MasteryHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: GetMasteryProfile called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: GetMasteryProfile - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	profile, err := h.masteryService.GetProfile(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "handler: GetMasteryProfile - failed to get mastery profile", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to get mastery profile")
+		return
+	}
+
+	entryCount := 0
+	if profile != nil {
+		entryCount = len(profile.Entries)
+	}
+	logger.Info(ctx, "handler: GetMasteryProfile - success", "entryCount", entryCount)
+	response.JSON(w, http.StatusOK, dto.NewMasteryProfile(profile))
+}
+
+func (h *MasteryHandler) SetStatuses(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: SetMasteryStatuses called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: SetMasteryStatuses - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	var req dto.SetMasteryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn(ctx, "handler: SetMasteryStatuses - invalid request body", "error", err)
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	err := h.masteryService.SetStatuses(ctx, userID, req.ToModel())
+	if err != nil {
+		if validationFailed(w, r, "SetMasteryStatuses", err) {
+			return
+		}
+		if errors.Is(err, services.ErrInvalidMasteryStatus) || errors.Is(err, services.ErrTooManyMasteryItems) ||
+			errors.Is(err, models.ErrUniqueNameRequired) || errors.Is(err, models.ErrInvalidUniqueName) {
+			logger.Warn(ctx, "handler: SetMasteryStatuses - invalid items", "error", err)
+			rejected(w, http.StatusBadRequest, err)
+			return
+		}
+		if errors.Is(err, services.ErrItemNotFound) {
+			logger.Warn(ctx, "handler: SetMasteryStatuses - item not found", "error", err)
+			rejected(w, http.StatusNotFound, err)
+			return
+		}
+		logger.Error(ctx, "handler: SetMasteryStatuses - failed to set mastery statuses", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to set mastery statuses")
+		return
+	}
+
+	logger.Info(ctx, "handler: SetMasteryStatuses - success", "count", len(req.Items))
+	response.JSON(w, http.StatusOK, map[string]string{
+		"message": "mastery profile updated",
+	})
+}
+
+func (h *MasteryHandler) SetStatus(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: SetMasteryStatus called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: SetMasteryStatus - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	uniqueName, err := uniqueNameParam(r)
+	if err != nil {
+		logger.Warn(ctx, "handler: SetMasteryStatus - invalid uniqueName", "error", err)
+		rejected(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var req dto.SetMasteryStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn(ctx, "handler: SetMasteryStatus - invalid request body", "error", err)
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	err = h.masteryService.SetStatus(ctx, userID, uniqueName, req.Status)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidMasteryStatus) {
+			logger.Warn(ctx, "handler: SetMasteryStatus - invalid status", "status", req.Status)
+			rejected(w, http.StatusBadRequest, err)
+			return
+		}
+		if errors.Is(err, services.ErrItemNotFound) {
+			logger.Warn(ctx, "handler: SetMasteryStatus - item not found", "uniqueName", uniqueName)
+			response.Error(w, http.StatusNotFound, "item not found")
+			return
+		}
+		logger.Error(ctx, "handler: SetMasteryStatus - failed to set mastery status", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to set mastery status")
+		return
+	}
+
+	logger.Info(ctx, "handler: SetMasteryStatus - success", "uniqueName", uniqueName, "status", req.Status)
+	response.JSON(w, http.StatusOK, map[string]string{
+		"message": "mastery status updated",
+	})
+}
+
+func (h *MasteryHandler) RemoveItem(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: RemoveMasteryItem called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: RemoveMasteryItem - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	uniqueName, err := uniqueNameParam(r)
+	if err != nil {
+		logger.Warn(ctx, "handler: RemoveMasteryItem - invalid uniqueName", "error", err)
+		rejected(w, http.StatusBadRequest, err)
+		return
+	}
+
+	err = h.masteryService.RemoveItem(ctx, userID, uniqueName)
+	if err != nil {
+		if errors.Is(err, services.ErrItemNotInProfile) {
+			logger.Warn(ctx, "handler: RemoveMasteryItem - item not in profile", "uniqueName", uniqueName)
+			response.ErrorCode(w, http.StatusNotFound, response.CodeItemNotInMasteryProfile, "item not in mastery profile")
+			return
+		}
+		logger.Error(ctx, "handler: RemoveMasteryItem - failed to remove item", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to remove item from mastery profile")
+		return
+	}
+
+	logger.Info(ctx, "handler: RemoveMasteryItem - success", "uniqueName", uniqueName)
+	response.JSON(w, http.StatusOK, map[string]string{
+		"message": "item removed from mastery profile",
+	})
+}
+
+func (h *MasteryHandler) ClearAll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: ClearMasteryProfile called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: ClearMasteryProfile - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	if err := h.masteryService.ClearAll(ctx, userID); err != nil {
+		logger.Error(ctx, "handler: ClearMasteryProfile - failed to clear mastery profile", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to clear mastery profile")
+		return
+	}
+
+	logger.Info(ctx, "handler: ClearMasteryProfile - success")
+	response.JSON(w, http.StatusOK, map[string]string{
+		"message": "mastery profile cleared",
+	})
+}
+
+func (h *MasteryHandler) GetSummary(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: GetProfileSummary called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: GetProfileSummary - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	summary, err := h.masteryService.GetSummary(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "handler: GetProfileSummary - failed to get profile summary", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to get profile summary")
+		return
+	}
+
+	logger.Info(ctx, "handler: GetProfileSummary - success")
+	response.JSON(w, http.StatusOK, dto.NewMasterySummary(summary))
+}
+
+// alreadyOwned writes a 409 carrying the mastery entry when err reports that
+// the item being added is in the user's mastery profile. The client can
+// retry with allowOwned to add it anyway.
+func alreadyOwned(w http.ResponseWriter, r *http.Request, err error) bool {
+	var ownedErr *services.ItemAlreadyOwnedError
+	if !errors.As(err, &ownedErr) {
+		return false
+	}
+
+	logger.Warn(r.Context(), "handler: item already owned", "uniqueName", ownedErr.Entry.UniqueName, "status", ownedErr.Entry.Status)
+	response.ErrorWithDetail(w, http.StatusConflict, "item already owned", dto.NewMasteryEntry(*ownedErr.Entry))
+	return true
+}
@@ -0,0 +1,306 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+)
+
+func TestMasteryHandler_GetProfile(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "success", userID: "user-123", expectedStatus: http.StatusOK},
+		{name: "unauthorized - no user ID", userID: "", expectedStatus: http.StatusUnauthorized},
+		{name: "service error", userID: "user-123", mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewMasteryHandler(&mocks.MockMasteryService{
+				GetProfileFunc: func(ctx context.Context, userID string) (*models.MasteryProfile, error) {
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return &models.MasteryProfile{UserID: userID, Entries: []models.MasteryEntry{
+						{UserID: userID, UniqueName: "/Lotus/Excalibur", Status: models.MasteryStatusMastered},
+					}}, nil
+				},
+			})
+			r := chi.NewRouter()
+			r.With(withTestUser(tt.userID)).Get("/api/v1/profile/mastery", handler.GetProfile)
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/profile/mastery", nil))
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			var body struct {
+				Entries []struct {
+					UniqueName string `json:"uniqueName"`
+					Status     string `json:"status"`
+				} `json:"entries"`
+			}
+			json.NewDecoder(rec.Body).Decode(&body)
+			if len(body.Entries) != 1 || body.Entries[0].Status != models.MasteryStatusMastered {
+				t.Errorf("unexpected body %+v", body)
+			}
+		})
+	}
+}
+
+func TestMasteryHandler_SetStatuses(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		body           string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "success", userID: "user-123", body: `{"items":[{"uniqueName":"/Lotus/Excalibur","status":"built"}]}`, expectedStatus: http.StatusOK},
+		{name: "unauthorized - no user ID", userID: "", body: `{}`, expectedStatus: http.StatusUnauthorized},
+		{name: "invalid body", userID: "user-123", body: `{`, expectedStatus: http.StatusBadRequest},
+		{name: "invalid status", userID: "user-123", body: `{}`, mockError: services.ErrInvalidMasteryStatus, expectedStatus: http.StatusBadRequest},
+		{name: "too many items", userID: "user-123", body: `{}`, mockError: services.ErrTooManyMasteryItems, expectedStatus: http.StatusBadRequest},
+		{name: "item not found", userID: "user-123", body: `{}`, mockError: fmt.Errorf("%w: /Lotus/Nope", services.ErrItemNotFound), expectedStatus: http.StatusNotFound},
+		{name: "validation problems", userID: "user-123", body: `{}`, mockError: newTestValidationError(), expectedStatus: http.StatusUnprocessableEntity},
+		{name: "service error", userID: "user-123", body: `{}`, mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got models.SetMasteryRequest
+			handler := NewMasteryHandler(&mocks.MockMasteryService{
+				SetStatusesFunc: func(ctx context.Context, userID string, req models.SetMasteryRequest) error {
+					got = req
+					return tt.mockError
+				},
+			})
+			r := chi.NewRouter()
+			r.With(withTestUser(tt.userID)).Put("/api/v1/profile/mastery", handler.SetStatuses)
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/api/v1/profile/mastery", strings.NewReader(tt.body)))
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if tt.name == "success" && (len(got.Items) != 1 || got.Items[0].Status != models.MasteryStatusBuilt) {
+				t.Errorf("expected the request passed through, got %+v", got)
+			}
+		})
+	}
+}
+
+func TestMasteryHandler_SetStatus(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		body           string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "success", userID: "user-123", body: `{"status":"mastered"}`, expectedStatus: http.StatusOK},
+		{name: "unauthorized - no user ID", userID: "", body: `{}`, expectedStatus: http.StatusUnauthorized},
+		{name: "invalid body", userID: "user-123", body: `{`, expectedStatus: http.StatusBadRequest},
+		{name: "invalid status", userID: "user-123", body: `{"status":"owned"}`, mockError: services.ErrInvalidMasteryStatus, expectedStatus: http.StatusBadRequest},
+		{name: "item not found", userID: "user-123", body: `{"status":"built"}`, mockError: services.ErrItemNotFound, expectedStatus: http.StatusNotFound},
+		{name: "service error", userID: "user-123", body: `{"status":"built"}`, mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotName string
+			handler := NewMasteryHandler(&mocks.MockMasteryService{
+				SetStatusFunc: func(ctx context.Context, userID, uniqueName, status string) error {
+					gotName = uniqueName
+					return tt.mockError
+				},
+			})
+			r := chi.NewRouter()
+			r.With(withTestUser(tt.userID)).Put("/api/v1/profile/mastery/*", handler.SetStatus)
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/api/v1/profile/mastery/Lotus/Powersuits/Excalibur", strings.NewReader(tt.body)))
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if tt.name == "success" && gotName != "/Lotus/Powersuits/Excalibur" {
+				t.Errorf("expected the uniqueName from the path, got %q", gotName)
+			}
+		})
+	}
+}
+
+func TestMasteryHandler_RemoveItem(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "success", userID: "user-123", expectedStatus: http.StatusOK},
+		{name: "unauthorized - no user ID", userID: "", expectedStatus: http.StatusUnauthorized},
+		{name: "item not in profile", userID: "user-123", mockError: services.ErrItemNotInProfile, expectedStatus: http.StatusNotFound},
+		{name: "service error", userID: "user-123", mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewMasteryHandler(&mocks.MockMasteryService{
+				RemoveItemFunc: func(ctx context.Context, userID, uniqueName string) error {
+					return tt.mockError
+				},
+			})
+			r := chi.NewRouter()
+			r.With(withTestUser(tt.userID)).Delete("/api/v1/profile/mastery/*", handler.RemoveItem)
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/api/v1/profile/mastery/Lotus/Powersuits/Excalibur", nil))
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestMasteryHandler_ClearAll(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "success", userID: "user-123", expectedStatus: http.StatusOK},
+		{name: "unauthorized - no user ID", userID: "", expectedStatus: http.StatusUnauthorized},
+		{name: "service error", userID: "user-123", mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewMasteryHandler(&mocks.MockMasteryService{
+				ClearAllFunc: func(ctx context.Context, userID string) error {
+					return tt.mockError
+				},
+			})
+			r := chi.NewRouter()
+			r.With(withTestUser(tt.userID)).Delete("/api/v1/profile/mastery", handler.ClearAll)
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/api/v1/profile/mastery", nil))
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestMasteryHandler_GetSummary(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "success", userID: "user-123", expectedStatus: http.StatusOK},
+		{name: "unauthorized - no user ID", userID: "", expectedStatus: http.StatusUnauthorized},
+		{name: "service error", userID: "user-123", mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewMasteryHandler(&mocks.MockMasteryService{
+				GetSummaryFunc: func(ctx context.Context, userID string) (*models.MasterySummary, error) {
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return &models.MasterySummary{UserID: userID, Buildable: 4, Built: 2, Mastered: 1, MasteredPercent: 25,
+						Categories: []models.MasteryCategorySummary{{Collection: "warframes", Buildable: 4, Built: 2, Mastered: 1}}}, nil
+				},
+			})
+			r := chi.NewRouter()
+			r.With(withTestUser(tt.userID)).Get("/api/v1/profile/summary", handler.GetSummary)
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/profile/summary", nil))
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			var body struct {
+				MasteredPercent float64 `json:"masteredPercent"`
+				Categories      []struct {
+					Collection string `json:"collection"`
+				} `json:"categories"`
+			}
+			json.NewDecoder(rec.Body).Decode(&body)
+			if body.MasteredPercent != 25 || len(body.Categories) != 1 || body.Categories[0].Collection != "warframes" {
+				t.Errorf("unexpected body %+v", body)
+			}
+		})
+	}
+}
+
+func TestWishlistHandler_AddItem_AlreadyOwned(t *testing.T) {
+	var got models.AddItemRequest
+	handler := NewWishlistHandler(&mocks.MockWishlistService{
+		AddItemFunc: func(ctx context.Context, userID string, req models.AddItemRequest) error {
+			got = req
+			if req.AllowOwned {
+				return nil
+			}
+			entry := &models.MasteryEntry{UserID: userID, UniqueName: req.UniqueName, Status: models.MasteryStatusBuilt, UpdatedAt: time.Now()}
+			return &services.ItemAlreadyOwnedError{Entry: entry}
+		},
+	}, &mocks.MockMaterialResolver{})
+	r := chi.NewRouter()
+	r.With(withTestUser("user-123")).Post("/api/v1/wishlist", handler.AddItem)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/wishlist", strings.NewReader(`{"uniqueName": "/Lotus/Excalibur"}`)))
+
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var body struct {
+		Code   string `json:"code"`
+		Detail struct {
+			UniqueName string `json:"uniqueName"`
+			Status     string `json:"status"`
+		} `json:"detail"`
+	}
+	json.NewDecoder(rec.Body).Decode(&body)
+	if body.Code != "item_already_owned" || body.Detail.UniqueName != "/Lotus/Excalibur" || body.Detail.Status != models.MasteryStatusBuilt {
+		t.Errorf("expected the mastery entry as detail, got %+v", body)
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/wishlist", strings.NewReader(`{"uniqueName": "/Lotus/Excalibur", "allowOwned": true}`)))
+	if rec.Code != http.StatusCreated || !got.AllowOwned {
+		t.Errorf("expected allowOwned to add the item, got %d (request %+v)", rec.Code, got)
+	}
+}
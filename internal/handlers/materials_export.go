@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"mime"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/graytonio/warframe-wishlist/internal/middleware"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"github.com/graytonio/warframe-wishlist/pkg/response"
+)
+
+// itemImageBaseURL serves item images by imageName, as the web client's
+// getItemImageUrl does.
+const itemImageBaseURL = "https://cdn.warframestat.us/img/"
+
+// utf8BOM is prepended to CSV exports so spreadsheet applications (Excel in
+// particular) detect the file as UTF-8 instead of the system code page.
+const utf8BOM = "\ufeff"
+
+// materialColumn describes a column that can be selected for the materials export.
+type materialColumn struct {
+	header string
+	value  func(m models.MaterialRequirement) string
+}
+
+var materialColumns = map[string]materialColumn{
+	"uniqueName": {
+		header: "Unique Name",
+		value:  func(m models.MaterialRequirement) string { return m.UniqueName },
+	},
+	"name": {
+		header: "Name",
+		value:  func(m models.MaterialRequirement) string { return m.Name },
+	},
+	"totalCount": {
+		header: "Required",
+		value:  func(m models.MaterialRequirement) string { return strconv.Itoa(m.TotalCount) },
+	},
+	"owned": {
+		header: "Owned",
+		value:  func(m models.MaterialRequirement) string { return strconv.Itoa(m.Owned) },
+	},
+	"remaining": {
+		header: "Remaining",
+		value:  func(m models.MaterialRequirement) string { return strconv.Itoa(m.Remaining) },
+	},
+	"imageName": {
+		header: "Image",
+		value:  func(m models.MaterialRequirement) string { return m.ImageName },
+	},
+	"imageUrl": {
+		header: "Image URL",
+		value: func(m models.MaterialRequirement) string {
+			if m.ImageName == "" {
+				return ""
+			}
+			return itemImageBaseURL + url.PathEscape(m.ImageName)
+		},
+	},
+	"description": {
+		header: "Description",
+		value:  func(m models.MaterialRequirement) string { return m.Description },
+	},
+}
+
+var defaultMaterialColumns = []string{"name", "totalCount"}
+
+// shoppingListColumns are the columns of GET /wishlist/materials as CSV.
+var shoppingListColumns = []string{"name", "totalCount", "imageUrl"}
+
+// parseMaterialColumns parses a comma-separated list of column keys, falling back
+// to defaults when the list is empty. Unknown keys are returned as the second
+// value so the caller can report them.
+func parseMaterialColumns(raw string, defaults []string) ([]string, string) {
+	if strings.TrimSpace(raw) == "" {
+		return defaults, ""
+	}
+
+	columns := []string{}
+	seen := make(map[string]bool)
+	for _, key := range strings.Split(raw, ",") {
+		key = strings.TrimSpace(key)
+		if key == "" || seen[key] {
+			continue
+		}
+		if _, ok := materialColumns[key]; !ok {
+			return nil, key
+		}
+		seen[key] = true
+		columns = append(columns, key)
+	}
+
+	if len(columns) == 0 {
+		return defaults, ""
+	}
+	return columns, ""
+}
+
+func (h *WishlistHandler) ExportMaterials(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: ExportMaterials called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: ExportMaterials - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	query := r.URL.Query()
+	format := query.Get("format")
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" {
+		logger.Warn(ctx, "handler: ExportMaterials - unsupported format", "format", format)
+		response.ErrorCode(w, http.StatusBadRequest, response.CodeUnsupportedFormat, "unsupported export format: "+format)
+		return
+	}
+
+	columns, unknown := parseMaterialColumns(query.Get("columns"), defaultMaterialColumns)
+	if unknown != "" {
+		logger.Warn(ctx, "handler: ExportMaterials - unknown column", "column", unknown)
+		response.ErrorCode(w, http.StatusBadRequest, response.CodeUnknownColumn, "unknown column: "+unknown)
+		return
+	}
+
+	logger.Debug(ctx, "handler: ExportMaterials - resolving materials", "columns", columns)
+	materials, err := h.materialResolver.GetMaterials(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "handler: ExportMaterials - failed to get materials", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to get materials")
+		return
+	}
+
+	writeMaterialsCSV(w, r, "ExportMaterials", materials, columns)
+}
+
+// writeMaterialsCSV writes materials sorted by name as a CSV download with
+// the given columns. A UTF-8 BOM leads the file unless the bom query
+// parameter is "false" or "0". A CSV has nowhere to carry the truncated flag,
+// so a partial result sets X-Materials-Truncated to the limit that was hit.
+func writeMaterialsCSV(w http.ResponseWriter, r *http.Request, name string, materials *models.MaterialsResponse, columns []string) {
+	ctx := r.Context()
+	bom := r.URL.Query().Get("bom")
+	includeBOM := bom != "false" && bom != "0"
+
+	rows := []models.MaterialRequirement{}
+	if materials != nil {
+		rows = append(rows, materials.Materials...)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Name != rows[j].Name {
+			return rows[i].Name < rows[j].Name
+		}
+		return rows[i].UniqueName < rows[j].UniqueName
+	})
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="wishlist-materials.csv"`)
+	if materials != nil && materials.Truncated {
+		w.Header().Set("X-Materials-Truncated", materials.TruncatedReason)
+	}
+	w.WriteHeader(http.StatusOK)
+
+	if includeBOM {
+		w.Write([]byte(utf8BOM))
+	}
+
+	writer := csv.NewWriter(w)
+	header := make([]string, len(columns))
+	for i, key := range columns {
+		header[i] = materialColumns[key].header
+	}
+	writer.Write(header)
+
+	record := make([]string, len(columns))
+	for _, mat := range rows {
+		for i, key := range columns {
+			record[i] = materialColumns[key].value(mat)
+		}
+		writer.Write(record)
+	}
+	writer.Flush()
+
+	if err := writer.Error(); err != nil {
+		logger.Error(ctx, "handler: "+name+" - failed to write csv", "error", err)
+		return
+	}
+
+	logger.Info(ctx, "handler: "+name+" - success", "rowCount", len(rows), "columnCount", len(columns))
+}
+
+// prefersCSV reports whether the Accept header ranks text/csv above every
+// other type it lists.
+func prefersCSV(accept string) bool {
+	return prefersMediaType(accept, "text/csv")
+}
+
+// prefersMediaType reports whether the Accept header ranks mediaType above
+// every other type it lists. Ties go to the type listed first, as in
+// response.Negotiate.
+func prefersMediaType(accept, mediaType string) bool {
+	preferredQ, bestQ := 0.0, 0.0
+	for _, part := range strings.Split(accept, ",") {
+		listed, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		if q <= bestQ {
+			continue
+		}
+		bestQ = q
+		if listed == mediaType {
+			preferredQ = q
+		}
+	}
+	return preferredQ > 0 && preferredQ == bestQ
+}
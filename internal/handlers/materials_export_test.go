@@ -0,0 +1,287 @@
+package handlers
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+func TestWishlistHandler_ExportMaterials(t *testing.T) {
+	materials := &models.MaterialsResponse{
+		Materials: []models.MaterialRequirement{
+			{UniqueName: "/Lotus/Plastids", Name: "Plastids", TotalCount: 300, Owned: 100, Remaining: 200, ImageName: "plastids.png"},
+			{UniqueName: "/Lotus/Ferrite", Name: "Ferrite", TotalCount: 1000, Remaining: 1000, ImageName: "ferrite.png"},
+		},
+		TotalCredits: 25000,
+	}
+
+	tests := []struct {
+		name           string
+		userID         string
+		query          string
+		mockReturn     *models.MaterialsResponse
+		mockError      error
+		expectedStatus int
+		expectedRows   [][]string
+		expectBOM      bool
+	}{
+		{
+			name:           "default columns",
+			userID:         "user-123",
+			query:          "?format=csv",
+			mockReturn:     materials,
+			expectedStatus: http.StatusOK,
+			expectedRows: [][]string{
+				{"Name", "Required"},
+				{"Ferrite", "1000"},
+				{"Plastids", "300"},
+			},
+			expectBOM: true,
+		},
+		{
+			name:           "selected columns without BOM",
+			userID:         "user-123",
+			query:          "?format=csv&columns=uniqueName,totalCount,imageName&bom=false",
+			mockReturn:     materials,
+			expectedStatus: http.StatusOK,
+			expectedRows: [][]string{
+				{"Unique Name", "Required", "Image"},
+				{"/Lotus/Ferrite", "1000", "ferrite.png"},
+				{"/Lotus/Plastids", "300", "plastids.png"},
+			},
+			expectBOM: false,
+		},
+		{
+			name:           "owned and remaining columns",
+			userID:         "user-123",
+			query:          "?columns=name,totalCount,owned,remaining",
+			mockReturn:     materials,
+			expectedStatus: http.StatusOK,
+			expectedRows: [][]string{
+				{"Name", "Required", "Owned", "Remaining"},
+				{"Ferrite", "1000", "0", "1000"},
+				{"Plastids", "300", "100", "200"},
+			},
+			expectBOM: true,
+		},
+		{
+			name:           "empty materials",
+			userID:         "user-123",
+			query:          "",
+			mockReturn:     &models.MaterialsResponse{Materials: []models.MaterialRequirement{}},
+			expectedStatus: http.StatusOK,
+			expectedRows: [][]string{
+				{"Name", "Required"},
+			},
+			expectBOM: true,
+		},
+		{
+			name:           "unknown column",
+			userID:         "user-123",
+			query:          "?columns=name,bogus",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unsupported format",
+			userID:         "user-123",
+			query:          "?format=xlsx",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unauthorized - no user ID",
+			userID:         "",
+			query:          "?format=csv",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "service error",
+			userID:         "user-123",
+			query:          "?format=csv",
+			mockError:      errors.New("database error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mockWishlistService{}
+			mockResolver := &mockMaterialResolver{
+				getMaterialsFunc: func(ctx context.Context, userID string) (*models.MaterialsResponse, error) {
+					return tt.mockReturn, tt.mockError
+				},
+			}
+
+			handler := NewWishlistHandler(mockService, mockResolver)
+
+			req := createAuthenticatedRequest(http.MethodGet, "/api/v1/wishlist/materials/export"+tt.query, nil, tt.userID)
+			rec := httptest.NewRecorder()
+
+			handler.ExportMaterials(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			if ct := rec.Header().Get("Content-Type"); ct != "text/csv; charset=utf-8" {
+				t.Errorf("expected CSV content type, got '%s'", ct)
+			}
+			if cd := rec.Header().Get("Content-Disposition"); !strings.HasPrefix(cd, "attachment;") {
+				t.Errorf("expected attachment content disposition, got '%s'", cd)
+			}
+
+			body := rec.Body.String()
+			hasBOM := strings.HasPrefix(body, utf8BOM)
+			if hasBOM != tt.expectBOM {
+				t.Errorf("expected BOM %v, got %v", tt.expectBOM, hasBOM)
+			}
+
+			rows, err := csv.NewReader(strings.NewReader(strings.TrimPrefix(body, utf8BOM))).ReadAll()
+			if err != nil {
+				t.Fatalf("failed to parse csv: %v", err)
+			}
+			if len(rows) != len(tt.expectedRows) {
+				t.Fatalf("expected %d rows, got %d", len(tt.expectedRows), len(rows))
+			}
+			for i := range rows {
+				if strings.Join(rows[i], "|") != strings.Join(tt.expectedRows[i], "|") {
+					t.Errorf("row %d: expected %v, got %v", i, tt.expectedRows[i], rows[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWishlistHandler_GetMaterialsCSV(t *testing.T) {
+	materials := &models.MaterialsResponse{
+		Materials: []models.MaterialRequirement{
+			{UniqueName: "/Lotus/Plastids", Name: "Plastids", TotalCount: 300, ImageName: "plastids.png"},
+			{UniqueName: "/Lotus/Argon", Name: "Argon Crystal", TotalCount: 2},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		query          string
+		accept         string
+		expectedStatus int
+		expectCSV      bool
+		expectedRows   [][]string
+	}{
+		{
+			name:           "format parameter",
+			query:          "?format=csv&bom=false",
+			expectedStatus: http.StatusOK,
+			expectCSV:      true,
+			expectedRows: [][]string{
+				{"Name", "Required", "Image URL"},
+				{"Argon Crystal", "2", ""},
+				{"Plastids", "300", "https://cdn.warframestat.us/img/plastids.png"},
+			},
+		},
+		{
+			name:           "selected columns",
+			query:          "?format=csv&columns=name,remaining&bom=false",
+			expectedStatus: http.StatusOK,
+			expectCSV:      true,
+			expectedRows:   [][]string{{"Name", "Remaining"}, {"Argon Crystal", "0"}, {"Plastids", "0"}},
+		},
+		{name: "accept header", query: "?bom=false", accept: "text/csv", expectedStatus: http.StatusOK, expectCSV: true},
+		{name: "accept header preferring JSON", accept: "application/json, text/csv;q=0.5", expectedStatus: http.StatusOK},
+		{name: "format wins over accept", query: "?format=json", accept: "text/csv", expectedStatus: http.StatusOK},
+		{name: "no preference", expectedStatus: http.StatusOK},
+		{name: "unsupported format", query: "?format=xlsx", expectedStatus: http.StatusBadRequest},
+		{name: "unknown column", query: "?format=csv&columns=bogus", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockResolver := &mockMaterialResolver{
+				getMaterialsFunc: func(ctx context.Context, userID string) (*models.MaterialsResponse, error) {
+					return materials, nil
+				},
+			}
+			handler := NewWishlistHandler(&mockWishlistService{}, mockResolver)
+
+			req := createAuthenticatedRequest(http.MethodGet, "/api/v1/wishlist/materials"+tt.query, nil, "user-123")
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			rec := httptest.NewRecorder()
+			handler.GetMaterials(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			isCSV := strings.HasPrefix(rec.Header().Get("Content-Type"), "text/csv")
+			if isCSV != tt.expectCSV {
+				t.Fatalf("expected CSV %v, got Content-Type %q", tt.expectCSV, rec.Header().Get("Content-Type"))
+			}
+			if !isCSV || tt.expectedRows == nil {
+				return
+			}
+			rows, err := csv.NewReader(rec.Body).ReadAll()
+			if err != nil {
+				t.Fatalf("failed to parse csv: %v", err)
+			}
+			if len(rows) != len(tt.expectedRows) {
+				t.Fatalf("expected %d rows, got %v", len(tt.expectedRows), rows)
+			}
+			for i := range rows {
+				if strings.Join(rows[i], "|") != strings.Join(tt.expectedRows[i], "|") {
+					t.Errorf("row %d: expected %v, got %v", i, tt.expectedRows[i], rows[i])
+				}
+			}
+		})
+	}
+}
+
+func TestPrefersCSV(t *testing.T) {
+	tests := []struct {
+		accept   string
+		expected bool
+	}{
+		{"", false},
+		{"text/csv", true},
+		{"text/csv, application/json", true},
+		{"application/json, text/csv", false},
+		{"application/json;q=0.5, text/csv", true},
+		{"text/html,application/xhtml+xml,*/*;q=0.8", false},
+		{"text/csv;q=0", false},
+	}
+
+	for _, tt := range tests {
+		if got := prefersCSV(tt.accept); got != tt.expected {
+			t.Errorf("prefersCSV(%q) = %v, want %v", tt.accept, got, tt.expected)
+		}
+	}
+}
+
+func TestWishlistHandler_GetMaterialsCSV_Truncated(t *testing.T) {
+	mockResolver := &mockMaterialResolver{
+		getMaterialsFunc: func(ctx context.Context, userID string) (*models.MaterialsResponse, error) {
+			return &models.MaterialsResponse{Materials: []models.MaterialRequirement{}, Truncated: true, TruncatedReason: models.TruncatedDepth}, nil
+		},
+	}
+	handler := NewWishlistHandler(&mockWishlistService{}, mockResolver)
+
+	req := createAuthenticatedRequest(http.MethodGet, "/api/v1/wishlist/materials?format=csv", nil, "user-123")
+	rec := httptest.NewRecorder()
+	handler.GetMaterials(rec, req)
+
+	if got := rec.Header().Get("X-Materials-Truncated"); got != models.TruncatedDepth {
+		t.Errorf("expected X-Materials-Truncated %q, got %q", models.TruncatedDepth, got)
+	}
+}
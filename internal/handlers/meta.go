@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/graytonio/warframe-wishlist/internal/buildinfo"
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"github.com/graytonio/warframe-wishlist/pkg/response"
+)
+
+// MetaHandler describes the running server.
+type MetaHandler struct {
+	version *dto.BuildVersion
+}
+
+// NewMetaHandler reports info and features, which maps each optional
+// feature to whether this instance serves it.
+func NewMetaHandler(info buildinfo.Info, features map[string]bool) *MetaHandler {
+	return &MetaHandler{version: dto.NewBuildVersion(info, features)}
+}
+
+func (h *MetaHandler) Version(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: Version called")
+	response.JSON(w, http.StatusOK, h.version)
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/buildinfo"
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+)
+
+func TestMetaHandler_Version(t *testing.T) {
+	info := buildinfo.Info{Version: "v1.4.0", Commit: "abc123", Date: time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC), GoVersion: "go1.25.0"}
+	features := map[string]bool{"kioskMode": false, "householdApprovals": true}
+	handler := NewMetaHandler(info, features)
+	// Later changes to the caller's map must not leak into responses.
+	features["kioskMode"] = true
+
+	rec := httptest.NewRecorder()
+	handler.Version(rec, httptest.NewRequest(http.MethodGet, "/api/v1/meta/version", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+	var body dto.BuildVersion
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if body.Version != "v1.4.0" || body.Commit != "abc123" || body.GoVersion != "go1.25.0" {
+		t.Errorf("unexpected version %+v", body)
+	}
+	if body.BuildDate == nil || !body.BuildDate.Equal(info.Date) {
+		t.Errorf("expected build date %v, got %v", info.Date, body.BuildDate)
+	}
+	if len(body.Features) != 2 || !body.Features["householdApprovals"] || body.Features["kioskMode"] {
+		t.Errorf("unexpected features %v", body.Features)
+	}
+}
@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/graytonio/warframe-wishlist/internal/audit"
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"github.com/graytonio/warframe-wishlist/pkg/response"
+)
+
+// ModerationHandler serves the moderation tools under /internal/moderation:
+// the queue of reported share links and the import alias dictionary. Staff
+// tokens are checked by middleware.RequireRole where they are mounted, so
+// moderators and admins both reach them. ReportShareLink is the public way
+// into the queue.
+type ModerationHandler struct {
+	moderationService  services.ModerationServiceInterface
+	importAliasService services.ImportAliasServiceInterface
+}
+
+func NewModerationHandler(moderationService services.ModerationServiceInterface, importAliasService services.ImportAliasServiceInterface) *ModerationHandler {
+	return &ModerationHandler{
+		moderationService:  moderationService,
+		importAliasService: importAliasService,
+	}
+}
+
+// ReportShareLink queues a share link for moderation. Anyone holding the
+// token may report it, and the response says nothing about earlier reports.
+func (h *ModerationHandler) ReportShareLink(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: ReportShareLink called")
+
+	var req dto.ReportShareLinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn(ctx, "handler: ReportShareLink - invalid request body", "error", err)
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if _, err := h.moderationService.ReportShareLink(ctx, chi.URLParam(r, "token"), req.ToModel()); err != nil {
+		writeModerationError(w, r, "ReportShareLink", err, "failed to report share link")
+		return
+	}
+
+	logger.Info(ctx, "handler: ReportShareLink - success")
+	response.JSON(w, http.StatusAccepted, map[string]string{
+		"message": "share link reported",
+	})
+}
+
+// ListReports lists the moderation queue, oldest first. ?status= selects
+// actioned or dismissed reports instead of open ones.
+func (h *ModerationHandler) ListReports(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: ListReports called")
+
+	reports, err := h.moderationService.ListReports(ctx, r.URL.Query().Get("status"))
+	if err != nil {
+		writeModerationError(w, r, "ListReports", err, "failed to list reports")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, dto.NewShareLinkReports(reports))
+}
+
+// DismissReport closes a share link's open report, leaving the link.
+func (h *ModerationHandler) DismissReport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: DismissReport called")
+
+	if err := h.moderationService.DismissReport(ctx, chi.URLParam(r, "token")); err != nil {
+		writeModerationError(w, r, "DismissReport", err, "failed to dismiss report")
+		return
+	}
+
+	logger.Info(ctx, "handler: DismissReport - success")
+	audit.RecordRequest(r, audit.TypeShareLink, audit.OutcomeSuccess, "share link report dismissed", "")
+	response.JSON(w, http.StatusOK, map[string]string{
+		"message": "report dismissed",
+	})
+}
+
+// RevokeShareLink deletes a share link by its token, whoever owns it, and
+// actions its open report.
+func (h *ModerationHandler) RevokeShareLink(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: RevokeShareLink called")
+
+	link, err := h.moderationService.RevokeShareLink(ctx, chi.URLParam(r, "token"))
+	if err != nil {
+		writeModerationError(w, r, "RevokeShareLink", err, "failed to revoke share link")
+		return
+	}
+
+	logger.Info(ctx, "handler: RevokeShareLink - success", "linkID", link.ID.Hex())
+	audit.RecordRequest(r, audit.TypeShareLink, audit.OutcomeSuccess, "share link revoked", link.UserID)
+	response.JSON(w, http.StatusOK, map[string]string{
+		"message": "share link revoked",
+	})
+}
+
+// ListImportAliases lists every alias the wishlist import expands,
+// built-in ones included.
+func (h *ModerationHandler) ListImportAliases(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: ListImportAliases called")
+
+	aliases, err := h.importAliasService.List(ctx)
+	if err != nil {
+		writeModerationError(w, r, "ListImportAliases", err, "failed to list import aliases")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, dto.NewImportAliases(aliases))
+}
+
+// SetImportAlias creates or replaces the alias of {word}.
+func (h *ModerationHandler) SetImportAlias(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: SetImportAlias called")
+
+	var req dto.ImportAliasRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn(ctx, "handler: SetImportAlias - invalid request body", "error", err)
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	alias, err := h.importAliasService.Set(ctx, chi.URLParam(r, "word"), req.Expansion)
+	if err != nil {
+		writeModerationError(w, r, "SetImportAlias", err, "failed to set import alias")
+		return
+	}
+
+	logger.Info(ctx, "handler: SetImportAlias - success", "word", alias.Word)
+	audit.RecordRequest(r, audit.TypeImportAlias, audit.OutcomeSuccess, "import alias set", "")
+	response.JSON(w, http.StatusOK, dto.NewImportAlias(alias))
+}
+
+// DeleteImportAlias removes the stored alias of {word}, restoring the
+// built-in one if there is one.
+func (h *ModerationHandler) DeleteImportAlias(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: DeleteImportAlias called")
+
+	if err := h.importAliasService.Delete(ctx, chi.URLParam(r, "word")); err != nil {
+		writeModerationError(w, r, "DeleteImportAlias", err, "failed to delete import alias")
+		return
+	}
+
+	logger.Info(ctx, "handler: DeleteImportAlias - success")
+	audit.RecordRequest(r, audit.TypeImportAlias, audit.OutcomeSuccess, "import alias deleted", "")
+	response.JSON(w, http.StatusOK, map[string]string{
+		"message": "import alias deleted",
+	})
+}
+
+func writeModerationError(w http.ResponseWriter, r *http.Request, name string, err error, fallback string) {
+	ctx := r.Context()
+
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrInvalidReportReason),
+		errors.Is(err, services.ErrInvalidReportStatus),
+		errors.Is(err, services.ErrInvalidImportAliasWord),
+		errors.Is(err, services.ErrInvalidImportAliasExpansion):
+		status = http.StatusBadRequest
+	case errors.Is(err, services.ErrShareLinkNotFound),
+		errors.Is(err, services.ErrReportNotFound),
+		errors.Is(err, services.ErrImportAliasNotFound):
+		status = http.StatusNotFound
+	}
+
+	if status == http.StatusInternalServerError {
+		logger.Error(ctx, "handler: "+name+" - "+fallback, "error", err)
+		response.Error(w, status, fallback)
+		return
+	}
+	logger.Warn(ctx, "handler: "+name+" - request rejected", "error", err)
+	rejected(w, status, err)
+}
@@ -0,0 +1,273 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+)
+
+func TestModerationHandler_ReportShareLink(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		mockError      error
+		expectedStatus int
+		expectedReason string
+	}{
+		{name: "reported", body: `{"reason":"spam"}`, expectedStatus: http.StatusAccepted, expectedReason: "spam"},
+		{name: "invalid body", body: `{`, expectedStatus: http.StatusBadRequest},
+		{name: "invalid reason", body: `{"reason":"rude"}`, mockError: services.ErrInvalidReportReason, expectedStatus: http.StatusBadRequest, expectedReason: "rude"},
+		{name: "unknown token", body: `{"reason":"abuse"}`, mockError: services.ErrShareLinkNotFound, expectedStatus: http.StatusNotFound, expectedReason: "abuse"},
+		{name: "service error", body: `{"reason":"other"}`, mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError, expectedReason: "other"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotToken, gotReason string
+			service := &mocks.MockModerationService{
+				ReportShareLinkFunc: func(ctx context.Context, token string, req models.ReportShareLinkRequest) (*models.ShareLinkReport, error) {
+					gotToken, gotReason = token, req.Reason
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return &models.ShareLinkReport{Token: token, OwnerID: "owner", Count: 3}, nil
+				},
+			}
+			handler := NewModerationHandler(service, &mocks.MockImportAliasService{})
+			r := chi.NewRouter()
+			r.Post("/api/v1/shared/{token}/report", handler.ReportShareLink)
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/shared/abc123/report", strings.NewReader(tt.body)))
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if gotReason != tt.expectedReason {
+				t.Errorf("expected reason %q, got %q", tt.expectedReason, gotReason)
+			}
+			if tt.expectedReason != "" && gotToken != "abc123" {
+				t.Errorf("expected the token from the path, got %q", gotToken)
+			}
+			if strings.Contains(rec.Body.String(), "owner") {
+				t.Errorf("expected the response not to reveal the link's owner, got %s", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestModerationHandler_ListReports(t *testing.T) {
+	tests := []struct {
+		name                string
+		query               string
+		mockError           error
+		expectedStatus      int
+		expectedStatusParam string
+	}{
+		{name: "open by default", expectedStatus: http.StatusOK},
+		{name: "by status", query: "?status=dismissed", expectedStatus: http.StatusOK, expectedStatusParam: "dismissed"},
+		{name: "invalid status", query: "?status=pending", mockError: services.ErrInvalidReportStatus, expectedStatus: http.StatusBadRequest, expectedStatusParam: "pending"},
+		{name: "service error", mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotStatus string
+			service := &mocks.MockModerationService{
+				ListReportsFunc: func(ctx context.Context, status string) ([]models.ShareLinkReport, error) {
+					gotStatus = status
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return []models.ShareLinkReport{{Token: "abc123", Reasons: []string{"spam"}, Count: 2, Status: models.ReportStatusOpen}}, nil
+				},
+			}
+			handler := NewModerationHandler(service, &mocks.MockImportAliasService{})
+
+			rec := httptest.NewRecorder()
+			handler.ListReports(rec, httptest.NewRequest(http.MethodGet, "/internal/moderation/reports"+tt.query, nil))
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if gotStatus != tt.expectedStatusParam {
+				t.Errorf("expected status %q, got %q", tt.expectedStatusParam, gotStatus)
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			var reports []dto.ShareLinkReport
+			if err := json.Unmarshal(rec.Body.Bytes(), &reports); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if len(reports) != 1 || reports[0].Token != "abc123" || reports[0].Count != 2 {
+				t.Errorf("unexpected reports %+v", reports)
+			}
+		})
+	}
+}
+
+func TestModerationHandler_DismissReport(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "dismissed", expectedStatus: http.StatusOK},
+		{name: "no open report", mockError: services.ErrReportNotFound, expectedStatus: http.StatusNotFound},
+		{name: "service error", mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotToken string
+			service := &mocks.MockModerationService{
+				DismissReportFunc: func(ctx context.Context, token string) error {
+					gotToken = token
+					return tt.mockError
+				},
+			}
+			handler := NewModerationHandler(service, &mocks.MockImportAliasService{})
+			r := chi.NewRouter()
+			r.Post("/internal/moderation/reports/{token}/dismiss", handler.DismissReport)
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/internal/moderation/reports/abc123/dismiss", nil))
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if gotToken != "abc123" {
+				t.Errorf("expected the token from the path, got %q", gotToken)
+			}
+		})
+	}
+}
+
+func TestModerationHandler_RevokeShareLink(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "revoked", expectedStatus: http.StatusOK},
+		{name: "unknown token", mockError: services.ErrShareLinkNotFound, expectedStatus: http.StatusNotFound},
+		{name: "service error", mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotToken string
+			service := &mocks.MockModerationService{
+				RevokeShareLinkFunc: func(ctx context.Context, token string) (*models.ShareLink, error) {
+					gotToken = token
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return &models.ShareLink{UserID: "owner", Token: token}, nil
+				},
+			}
+			handler := NewModerationHandler(service, &mocks.MockImportAliasService{})
+			r := chi.NewRouter()
+			r.Delete("/internal/moderation/share-links/{token}", handler.RevokeShareLink)
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/internal/moderation/share-links/abc123", nil))
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if gotToken != "abc123" {
+				t.Errorf("expected the token from the path, got %q", gotToken)
+			}
+		})
+	}
+}
+
+func TestModerationHandler_SetImportAlias(t *testing.T) {
+	tests := []struct {
+		name              string
+		body              string
+		mockError         error
+		expectedStatus    int
+		expectedExpansion string
+	}{
+		{name: "set", body: `{"expansion":"chassis"}`, expectedStatus: http.StatusOK, expectedExpansion: "chassis"},
+		{name: "invalid body", body: `{`, expectedStatus: http.StatusBadRequest},
+		{name: "invalid expansion", body: `{"expansion":""}`, mockError: services.ErrInvalidImportAliasExpansion, expectedStatus: http.StatusBadRequest},
+		{name: "service error", body: `{"expansion":"chassis"}`, mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError, expectedExpansion: "chassis"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotWord, gotExpansion string
+			service := &mocks.MockImportAliasService{
+				SetFunc: func(ctx context.Context, word, expansion string) (*models.ImportAlias, error) {
+					gotWord, gotExpansion = word, expansion
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return &models.ImportAlias{Word: word, Expansion: expansion}, nil
+				},
+			}
+			handler := NewModerationHandler(&mocks.MockModerationService{}, service)
+			r := chi.NewRouter()
+			r.Put("/internal/moderation/import-aliases/{word}", handler.SetImportAlias)
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/internal/moderation/import-aliases/chas", strings.NewReader(tt.body)))
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if gotExpansion != tt.expectedExpansion {
+				t.Errorf("expected expansion %q, got %q", tt.expectedExpansion, gotExpansion)
+			}
+			if tt.body != `{` && gotWord != "chas" {
+				t.Errorf("expected the word from the path, got %q", gotWord)
+			}
+		})
+	}
+}
+
+func TestModerationHandler_DeleteImportAlias(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "deleted", expectedStatus: http.StatusOK},
+		{name: "unknown word", mockError: services.ErrImportAliasNotFound, expectedStatus: http.StatusNotFound},
+		{name: "service error", mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.MockImportAliasService{
+				DeleteFunc: func(ctx context.Context, word string) error {
+					return tt.mockError
+				},
+			}
+			handler := NewModerationHandler(&mocks.MockModerationService{}, service)
+			r := chi.NewRouter()
+			r.Delete("/internal/moderation/import-aliases/{word}", handler.DeleteImportAlias)
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/internal/moderation/import-aliases/chas", nil))
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+	"github.com/graytonio/warframe-wishlist/internal/middleware"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"github.com/graytonio/warframe-wishlist/pkg/response"
+)
+
+type NotificationHandler struct {
+	notificationService services.NotificationServiceInterface
+}
+
+func NewNotificationHandler(notificationService services.NotificationServiceInterface) *NotificationHandler {
+	return &NotificationHandler{notificationService: notificationService}
+}
+
+// GetPushKey returns the key browsers subscribe to push with.
+func (h *NotificationHandler) GetPushKey(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: GetPushKey called")
+
+	response.JSON(w, http.StatusOK, dto.NewPushKey(h.notificationService.PushKey()))
+}
+
+func (h *NotificationHandler) ListChannels(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: ListNotificationChannels called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: ListNotificationChannels - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	channels, err := h.notificationService.ListChannels(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "handler: ListNotificationChannels - failed to list channels", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to list notification channels")
+		return
+	}
+
+	logger.Info(ctx, "handler: ListNotificationChannels - success", "count", len(channels))
+	response.JSON(w, http.StatusOK, dto.NewNotificationChannels(channels))
+}
+
+// CreateChannel registers a webhook or push subscription. A webhook's
+// signing secret is in the response, and only there.
+func (h *NotificationHandler) CreateChannel(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: CreateNotificationChannel called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: CreateNotificationChannel - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	var req dto.NotificationChannelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn(ctx, "handler: CreateNotificationChannel - invalid request body", "error", err)
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	channel, err := h.notificationService.CreateChannel(ctx, userID, req.ToModel())
+	if err != nil {
+		writeNotificationError(w, r, "CreateNotificationChannel", err, "failed to create notification channel")
+		return
+	}
+
+	logger.Info(ctx, "handler: CreateNotificationChannel - success", "id", channel.ID.Hex(), "type", channel.Type)
+	response.JSON(w, http.StatusCreated, dto.NewCreatedNotificationChannel(channel))
+}
+
+func (h *NotificationHandler) DeleteChannel(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: DeleteNotificationChannel called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: DeleteNotificationChannel - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	channelID := chi.URLParam(r, "channelID")
+	if err := h.notificationService.DeleteChannel(ctx, userID, channelID); err != nil {
+		writeNotificationError(w, r, "DeleteNotificationChannel", err, "failed to delete notification channel")
+		return
+	}
+
+	logger.Info(ctx, "handler: DeleteNotificationChannel - success", "id", channelID)
+	response.JSON(w, http.StatusOK, map[string]string{
+		"message": "notification channel deleted",
+	})
+}
+
+// ListDeliveries lists the caller's latest deliveries, newest first.
+func (h *NotificationHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: ListNotificationDeliveries called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: ListNotificationDeliveries - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	deliveries, err := h.notificationService.ListDeliveries(ctx, userID, limit)
+	if err != nil {
+		logger.Error(ctx, "handler: ListNotificationDeliveries - failed to list deliveries", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to list notification deliveries")
+		return
+	}
+
+	logger.Info(ctx, "handler: ListNotificationDeliveries - success", "count", len(deliveries))
+	response.JSON(w, http.StatusOK, dto.NewNotificationDeliveries(deliveries))
+}
+
+func writeNotificationError(w http.ResponseWriter, r *http.Request, name string, err error, fallback string) {
+	ctx := r.Context()
+
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrInvalidNotificationChannel), errors.Is(err, services.ErrPushNotConfigured):
+		status = http.StatusBadRequest
+	case errors.Is(err, services.ErrNotificationChannelNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrTooManyNotificationChannels):
+		status = http.StatusConflict
+	}
+
+	if status == http.StatusInternalServerError {
+		logger.Error(ctx, "handler: "+name+" - "+fallback, "error", err)
+		response.Error(w, status, fallback)
+		return
+	}
+	logger.Warn(ctx, "handler: "+name+" - request rejected", "error", err)
+	rejected(w, status, err)
+}
@@ -0,0 +1,223 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/graytonio/warframe-wishlist/internal/middleware"
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// newNotificationRouter mounts the notification routes as main does, with
+// userID injected in place of the auth middleware.
+func newNotificationRouter(service services.NotificationServiceInterface, userID string) http.Handler {
+	handler := NewNotificationHandler(service)
+	r := chi.NewRouter()
+	r.Route("/api/v1/notifications", func(r chi.Router) {
+		r.Use(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ctx := context.WithValue(r.Context(), middleware.UserIDKey, userID)
+				next.ServeHTTP(w, r.WithContext(ctx))
+			})
+		})
+		r.Get("/push-key", handler.GetPushKey)
+		r.Get("/channels", handler.ListChannels)
+		r.Post("/channels", handler.CreateChannel)
+		r.Delete("/channels/{channelID}", handler.DeleteChannel)
+		r.Get("/deliveries", handler.ListDeliveries)
+	})
+	return r
+}
+
+func TestNotificationHandler_GetPushKey(t *testing.T) {
+	for key, enabled := range map[string]bool{"BPublicKey": true, "": false} {
+		service := &mocks.MockNotificationService{PushKeyFunc: func() string { return key }}
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/notifications/push-key", nil)
+		rec := httptest.NewRecorder()
+		newNotificationRouter(service, "user-123").ServeHTTP(rec, req)
+
+		var body struct {
+			PublicKey string `json:"publicKey"`
+			Enabled   bool   `json:"enabled"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || rec.Code != http.StatusOK {
+			t.Fatalf("unexpected response %d: %s", rec.Code, rec.Body.String())
+		}
+		if body.PublicKey != key || body.Enabled != enabled {
+			t.Errorf("expected key %q enabled %v, got %+v", key, enabled, body)
+		}
+	}
+}
+
+func TestNotificationHandler_ListChannels(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "success", userID: "user-123", expectedStatus: http.StatusOK},
+		{name: "unauthorized - no user ID", userID: "", expectedStatus: http.StatusUnauthorized},
+		{name: "service error", userID: "user-123", mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.MockNotificationService{
+				ListChannelsFunc: func(ctx context.Context, userID string) ([]models.NotificationChannel, error) {
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return []models.NotificationChannel{{ID: primitive.NewObjectID(), UserID: userID, Type: models.NotificationWebhook, URL: "https://example.com/hook", Secret: "secret"}}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/notifications/channels", nil)
+			rec := httptest.NewRecorder()
+			newNotificationRouter(service, tt.userID).ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if tt.expectedStatus == http.StatusOK && strings.Contains(rec.Body.String(), "secret") {
+				t.Errorf("expected the secret to be left out of the list, got %s", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestNotificationHandler_CreateChannel(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "webhook", body: `{"type":"webhook","url":"https://example.com/hook"}`, expectedStatus: http.StatusCreated},
+		{name: "invalid body", body: `{`, expectedStatus: http.StatusBadRequest},
+		{name: "invalid channel", body: `{"type":"email"}`, mockError: services.ErrInvalidNotificationChannel, expectedStatus: http.StatusBadRequest},
+		{name: "push not configured", body: `{"type":"push","url":"https://push.example.com"}`, mockError: services.ErrPushNotConfigured, expectedStatus: http.StatusBadRequest},
+		{name: "too many channels", body: `{"type":"webhook","url":"https://example.com/hook"}`, mockError: services.ErrTooManyNotificationChannels, expectedStatus: http.StatusConflict},
+		{name: "service error", body: `{"type":"webhook","url":"https://example.com/hook"}`, mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.MockNotificationService{
+				CreateChannelFunc: func(ctx context.Context, userID string, req models.NotificationChannelRequest) (*models.NotificationChannel, error) {
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return &models.NotificationChannel{ID: primitive.NewObjectID(), UserID: userID, Type: req.Type, URL: req.URL, Secret: "s3cret", CreatedAt: time.Now()}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/notifications/channels", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			newNotificationRouter(service, "user-123").ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if tt.expectedStatus != http.StatusCreated {
+				return
+			}
+			var body struct {
+				ID     string   `json:"id"`
+				Secret string   `json:"secret"`
+				Events []string `json:"events"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if body.ID == "" || body.Secret != "s3cret" || body.Events == nil {
+				t.Errorf("expected the new channel with its secret, got %s", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestNotificationHandler_DeleteChannel(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "success", expectedStatus: http.StatusOK},
+		{name: "not found", mockError: services.ErrNotificationChannelNotFound, expectedStatus: http.StatusNotFound},
+		{name: "service error", mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotID string
+			service := &mocks.MockNotificationService{
+				DeleteChannelFunc: func(ctx context.Context, userID, id string) error {
+					gotID = id
+					return tt.mockError
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/notifications/channels/abc123", nil)
+			rec := httptest.NewRecorder()
+			newNotificationRouter(service, "user-123").ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if gotID != "abc123" {
+				t.Errorf("expected the channel ID from the path, got %q", gotID)
+			}
+		})
+	}
+}
+
+func TestNotificationHandler_ListDeliveries(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	var gotLimit int
+	service := &mocks.MockNotificationService{
+		ListDeliveriesFunc: func(ctx context.Context, userID string, limit int) ([]models.NotificationDelivery, error) {
+			gotLimit = limit
+			return []models.NotificationDelivery{
+				{ID: primitive.NewObjectID(), Event: models.NotificationFoundryFinished, Status: models.DeliveryPending, Attempts: 1,
+					LastError: "status 503", Payload: `{"title":"Ash is ready"}`, NextAttemptAt: now, CreatedAt: now},
+			}, nil
+		},
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/notifications/deliveries?limit=5", nil)
+	rec := httptest.NewRecorder()
+	newNotificationRouter(service, "user-123").ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK || gotLimit != 5 {
+		t.Fatalf("expected 200 with limit 5, got %d (limit %d): %s", rec.Code, gotLimit, rec.Body.String())
+	}
+	var body []struct {
+		Payload       map[string]string `json:"payload"`
+		NextAttemptAt *time.Time        `json:"nextAttemptAt"`
+		DeliveredAt   *time.Time        `json:"deliveredAt"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(body) != 1 || body[0].Payload["title"] != "Ash is ready" || body[0].NextAttemptAt == nil || body[0].DeliveredAt != nil {
+		t.Errorf("expected the pending delivery with its payload inline, got %s", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	newNotificationRouter(service, "").ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/notifications/deliveries", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 without a user, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,314 @@
+package handlers
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+	"github.com/graytonio/warframe-wishlist/internal/graphql"
+	"github.com/graytonio/warframe-wishlist/internal/openapi"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"github.com/graytonio/warframe-wishlist/pkg/response"
+)
+
+// APIPrefix is the path every documented route starts with.
+const APIPrefix = "/api/v1"
+
+// OpenAPIHandler serves the OpenAPI document of the mounted API and a
+// Swagger UI page for it. The document is built from the router on first
+// request, once every route is mounted, so it always lists exactly the
+// routes this instance serves.
+type OpenAPIHandler struct {
+	routes  chi.Routes
+	builder *openapi.Builder
+
+	once sync.Once
+	doc  *openapi.Document
+	err  error
+}
+
+// NewOpenAPIHandler documents the routes under APIPrefix. Routes behind auth
+// are marked as needing a bearer token.
+func NewOpenAPIHandler(routes chi.Routes, version string, auth func(http.Handler) http.Handler) *OpenAPIHandler {
+	return &OpenAPIHandler{
+		routes:  routes,
+		builder: openapi.NewBuilder("Warframe Wishlist API", version, apiSpecs, auth, response.ErrorResponse{}),
+	}
+}
+
+func (h *OpenAPIHandler) Spec(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: OpenAPISpec called")
+
+	h.once.Do(func() {
+		var undocumented, unused []string
+		h.doc, undocumented, unused, h.err = h.builder.Build(h.routes, APIPrefix)
+		if len(undocumented) > 0 {
+			logger.Warn(ctx, "handler: OpenAPISpec - routes missing from apiSpecs", "routes", undocumented)
+		}
+		// Routes behind disabled features (household approvals, kiosk
+		// mode) are not mounted, so their specs go unused.
+		if len(unused) > 0 {
+			logger.Debug(ctx, "handler: OpenAPISpec - specs for routes not mounted", "routes", unused)
+		}
+	})
+	if h.err != nil {
+		logger.Error(ctx, "handler: OpenAPISpec - failed to build document", "error", h.err)
+		response.Error(w, http.StatusInternalServerError, "failed to build API document")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, h.doc)
+}
+
+func (h *OpenAPIHandler) Docs(w http.ResponseWriter, r *http.Request) {
+	logger.Debug(r.Context(), "handler: OpenAPIDocs called")
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(openapi.SwaggerUI)
+}
+
+var (
+	limitQuery  = openapi.Query{Name: "limit", Type: "integer", Description: "Maximum results"}
+	dryRunQuery = openapi.Query{Name: "dryRun", Type: "boolean", Description: "Report what would change without changing anything"}
+	modeQuery   = openapi.Query{Name: "mode", Description: "merge (default) or replace"}
+)
+
+// apiSpecs describes the routes in the API document, keyed by method and
+// chi route pattern. A route missing here is still listed, without bodies;
+// the server warns about such routes when it first builds the document.
+var apiSpecs = map[string]openapi.Spec{
+	"GET /api/v1/openapi.json": {Summary: "This document", Response: map[string]any{}},
+	"GET /api/v1/docs":         {Summary: "Swagger UI for this document", ContentTypes: []string{"text/html"}},
+	"GET /api/v1/meta/version": {Summary: "Server build and optional features", Response: dto.BuildVersion{}},
+
+	"GET /api/v1/items/search": {
+		Summary: "Search items",
+		Query: []openapi.Query{
+			{Name: "q", Description: "Name to search for"},
+			{Name: "category", Description: "Item category"},
+			limitQuery,
+			{Name: "offset", Type: "integer", Description: "Results to skip"},
+			{Name: "includeArchived", Type: "boolean", Description: "Include items removed upstream"},
+		},
+		Response: dto.ItemSearchResponse{},
+	},
+	"GET /api/v1/items/autocomplete": {
+		Summary:  "Suggest items as a name is typed",
+		Query:    []openapi.Query{{Name: "q", Description: "Name prefix"}, limitQuery},
+		Response: dto.ItemAutocompleteResponse{},
+	},
+	"GET /api/v1/items/blueprints/reusable": {
+		Summary:  "Search reusable blueprints",
+		Query:    []openapi.Query{{Name: "q", Description: "Name to search for"}, limitQuery},
+		Response: dto.ItemSearchResponse{},
+	},
+	"GET /api/v1/items/changes": {
+		Summary:  "Item data changes feed",
+		Query:    []openapi.Query{{Name: "since", Description: "RFC 3339 timestamp"}, limitQuery},
+		Response: dto.ItemChangesResponse{},
+	},
+	"GET /api/v1/items/export": {
+		Summary:      "Stream every item of a category as NDJSON; resumable with Range: items=N-",
+		Query:        []openapi.Query{{Name: "category", Description: "Item category, e.g. mods"}},
+		ContentTypes: []string{"application/x-ndjson"},
+	},
+	"GET /api/v1/items/*": {
+		Summary:  "Item details",
+		Query:    []openapi.Query{{Name: "include", Description: "Comma-separated extras, e.g. stats"}},
+		Response: dto.ItemDetail{},
+	},
+
+	"GET /api/v1/public-wishlists/":               {Summary: "List public wishlists", Response: []dto.PublicWishlist{}},
+	"GET /api/v1/public-wishlists/{id}":           {Summary: "Get a public wishlist", Response: dto.PublicWishlist{}},
+	"GET /api/v1/public-wishlists/{id}/materials": {Summary: "Materials of a public wishlist", Response: dto.MaterialsSummary{}},
+
+	"GET /api/v1/admin/sync/status":  {Summary: "Scheduled item refresh status", Response: dto.ItemRefreshStatus{}},
+	"GET /api/v1/admin/deprecations": {Summary: "Clients still using deprecated fields", Response: dto.Deprecations{}},
+
+	"GET /api/v1/admin/users/{userID}":          {Summary: "Look up a user's stored data (admin role)", Response: dto.AdminUser{}},
+	"GET /api/v1/admin/users/{userID}/wishlist": {Summary: "Inspect a user's expanded wishlist (admin role)", Response: dto.ExpandedWishlist{}},
+	"POST /api/v1/admin/sync":                   {Summary: "Start an item refresh now (admin role)", Response: openapi.Message{}, Status: http.StatusAccepted},
+	"POST /api/v1/admin/cache/flush":            {Summary: "Purge and re-warm the item data caches (admin role)", Response: openapi.Message{}, Status: http.StatusAccepted},
+
+	"GET /api/v1/dojo/clan-tiers":     {Summary: "List clan tiers", Response: []dto.ClanTier{}},
+	"POST /api/v1/dojo/research-cost": {Summary: "Calculate dojo research cost", Request: dto.ResearchCostRequest{}, Response: dto.ResearchCost{}},
+
+	"GET /api/v1/dashboard/": {Summary: "Home screen: wishlist summary, top items, material highlights, foundry and alerts", Response: dto.Dashboard{}},
+
+	"GET /api/v1/wishlist/": {
+		Summary:  "Get the wishlist",
+		Query:    []openapi.Query{{Name: "expand", Description: "items to embed item details"}},
+		Response: dto.Wishlist{},
+	},
+	"POST /api/v1/wishlist/": {Summary: "Add an item", Request: dto.AddItemRequest{}, Response: dto.AddedItem{}, Status: http.StatusCreated},
+	"GET /api/v1/wishlist/materials": {
+		Summary:      "Materials needed for the wishlist",
+		Query:        []openapi.Query{{Name: "format", Description: "json or csv"}},
+		Response:     dto.MaterialsSummary{},
+		ContentTypes: []string{"text/csv"},
+	},
+	"GET /api/v1/wishlist/materials/export": {
+		Summary: "Export the materials",
+		Query: []openapi.Query{
+			{Name: "format", Description: "csv"},
+			{Name: "columns", Description: "Comma-separated columns"},
+			{Name: "bom", Type: "boolean", Description: "Start with a UTF-8 byte order mark"},
+		},
+		ContentTypes: []string{"text/csv"},
+	},
+	"GET /api/v1/wishlist/farming-plan":  {Summary: "Where to farm the missing materials", Query: []openapi.Query{limitQuery}, Response: dto.FarmingPlan{}},
+	"GET /api/v1/wishlist/relics":        {Summary: "Relics dropping the wishlist's prime parts", Response: dto.RelicRequirements{}},
+	"GET /api/v1/wishlist/opportunities": {Summary: "Current world state rewards the wishlist needs", Response: dto.Opportunities{}},
+	"POST /api/v1/wishlist/import/text":  {Summary: "Preview a text import", Request: dto.ImportTextRequest{}, Response: dto.ImportPreview{}},
+	"POST /api/v1/wishlist/import/text/confirm": {
+		Summary:  "Confirm a text import",
+		Request:  dto.ImportConfirmRequest{},
+		Response: dto.ImportConfirmResult{},
+	},
+	"GET /api/v1/wishlist/templates/":                    {Summary: "List wishlist templates", Response: dto.WishlistTemplates{}},
+	"POST /api/v1/wishlist/templates/":                   {Summary: "Create a wishlist template", Request: dto.WishlistTemplateRequest{}, Response: dto.WishlistTemplate{}, Status: http.StatusCreated},
+	"DELETE /api/v1/wishlist/templates/{templateID}":     {Summary: "Delete a wishlist template", Response: openapi.Message{}},
+	"POST /api/v1/wishlist/templates/{templateID}/apply": {Summary: "Add a template's items to the wishlist", Request: dto.ApplyTemplateRequest{}, Response: dto.ImportConfirmResult{}},
+	"GET /api/v1/wishlist/export": {
+		Summary:      "Export the wishlist",
+		Query:        []openapi.Query{{Name: "format", Description: "json or pdf"}},
+		Response:     dto.WishlistExport{},
+		ContentTypes: []string{"application/pdf"},
+	},
+	"POST /api/v1/wishlist/import": {
+		Summary:  "Import a wishlist export",
+		Query:    []openapi.Query{modeQuery, dryRunQuery},
+		Request:  dto.WishlistExport{},
+		Response: dto.WishlistDocumentImportResult{},
+	},
+	"POST /api/v1/wishlist/merge": {
+		Summary:  "Merge an edit made against an earlier revision",
+		Request:  dto.WishlistMergeRequest{},
+		Response: dto.WishlistMergeResult{},
+	},
+	"PUT /api/v1/wishlist/links/*":    {Summary: "Set an item's source links", Request: dto.UpdateItemLinksRequest{}, Response: dto.ItemLinks{}},
+	"PUT /api/v1/wishlist/recipe/*":   {Summary: "Pick an item's recipe", Request: dto.SetItemRecipeRequest{}, Response: dto.ItemRecipe{}},
+	"PUT /api/v1/wishlist/progress/*": {Summary: "Set an item's component progress", Request: dto.UpdateItemProgressRequest{}, Response: dto.ItemProgress{}},
+	"DELETE /api/v1/wishlist/*":       {Summary: "Remove an item", Response: openapi.Message{}},
+	"PATCH /api/v1/wishlist/*":        {Summary: "Change an item's quantity", Request: dto.UpdateQuantityRequest{}, Response: openapi.Message{}},
+
+	"GET /api/v1/custom-items/": {Summary: "List custom items", Response: dto.CustomItems{}},
+	"GET /api/v1/custom-items/search": {
+		Summary:  "Search custom items",
+		Query:    []openapi.Query{{Name: "q", Description: "Name to search for"}, limitQuery},
+		Response: dto.ItemSearchResponse{},
+	},
+	"POST /api/v1/custom-items/":    {Summary: "Define a custom item", Request: dto.CustomItemRequest{}, Response: dto.CustomItem{}, Status: http.StatusCreated},
+	"PUT /api/v1/custom-items/*":    {Summary: "Update a custom item", Request: dto.CustomItemRequest{}, Response: dto.CustomItem{}},
+	"DELETE /api/v1/custom-items/*": {Summary: "Delete a custom item", Response: openapi.Message{}},
+
+	"GET /api/v1/workspaces/":                        {Summary: "List workspaces", Response: []dto.Workspace{}},
+	"POST /api/v1/workspaces/":                       {Summary: "Create a workspace", Request: dto.WorkspaceRequest{}, Response: dto.Workspace{}, Status: http.StatusCreated},
+	"GET /api/v1/workspaces/{workspaceID}/":          {Summary: "Get a workspace", Response: dto.Workspace{}},
+	"PUT /api/v1/workspaces/{workspaceID}/":          {Summary: "Rename or describe a workspace", Request: dto.WorkspaceRequest{}, Response: dto.Workspace{}},
+	"DELETE /api/v1/workspaces/{workspaceID}/":       {Summary: "Delete a workspace", Response: openapi.Message{}},
+	"GET /api/v1/workspaces/{workspaceID}/materials": {Summary: "Materials of a workspace", Response: dto.WorkspaceMaterials{}},
+	"POST /api/v1/workspaces/{workspaceID}/material-contributions": {
+		Summary:  "Log a material contribution",
+		Request:  dto.WorkspaceMaterialContributionRequest{},
+		Response: dto.WorkspaceActivity{},
+		Status:   http.StatusCreated,
+	},
+	"GET /api/v1/workspaces/{workspaceID}/activity": {Summary: "Workspace contribution log", Query: []openapi.Query{limitQuery}, Response: []dto.WorkspaceActivity{}},
+	"POST /api/v1/workspaces/{workspaceID}/members": {
+		Summary:  "Add a member",
+		Request:  dto.WorkspaceMemberRequest{},
+		Response: dto.Workspace{},
+		Status:   http.StatusCreated,
+	},
+	"PUT /api/v1/workspaces/{workspaceID}/members/{memberID}":    {Summary: "Change a member's role", Request: dto.WorkspaceMemberRoleRequest{}, Response: dto.Workspace{}},
+	"DELETE /api/v1/workspaces/{workspaceID}/members/{memberID}": {Summary: "Remove a member", Response: dto.Workspace{}},
+	"POST /api/v1/workspaces/{workspaceID}/items": {
+		Summary:  "Add an item",
+		Request:  dto.WorkspaceItemRequest{},
+		Response: dto.Workspace{},
+		Status:   http.StatusCreated,
+	},
+	"PATCH /api/v1/workspaces/{workspaceID}/items/*":       {Summary: "Change an item's quantity", Request: dto.UpdateQuantityRequest{}, Response: dto.Workspace{}},
+	"DELETE /api/v1/workspaces/{workspaceID}/items/*":      {Summary: "Remove an item", Response: dto.Workspace{}},
+	"PUT /api/v1/workspaces/{workspaceID}/contributions/*": {Summary: "Set your contribution to an item", Request: dto.UpdateQuantityRequest{}, Response: dto.Workspace{}},
+	"GET /api/v1/workspaces/{workspaceID}/live":            {Summary: "WebSocket of live workspace changes", Status: http.StatusSwitchingProtocols},
+
+	"GET /api/v1/share-links/":             {Summary: "List share links", Response: []dto.ShareLink{}},
+	"POST /api/v1/share-links/":            {Summary: "Create a share link", Request: dto.CreateShareLinkRequest{}, Response: dto.ShareLink{}, Status: http.StatusCreated},
+	"DELETE /api/v1/share-links/{linkID}":  {Summary: "Delete a share link", Response: openapi.Message{}},
+	"GET /api/v1/shared/{token}/":          {Summary: "The wishlist behind a share link", Response: dto.ShareLinkView{}},
+	"GET /api/v1/shared/{token}/materials": {Summary: "Materials of the wishlist behind a share link", Response: dto.MaterialsSummary{}},
+	"POST /api/v1/shared/{token}/report":   {Summary: "Report a share link to moderators", Request: dto.ReportShareLinkRequest{}, Response: openapi.Message{}, Status: http.StatusAccepted},
+
+	"GET /api/v1/users/{userID}/wishlist/":            {Summary: "Another user's public wishlist with gift claims", Response: dto.SharedWishlist{}},
+	"POST /api/v1/users/{userID}/wishlist/claims":     {Summary: "Claim an item as a gift", Request: dto.ClaimGiftRequest{}, Response: dto.GiftClaim{}, Status: http.StatusCreated},
+	"DELETE /api/v1/users/{userID}/wishlist/claims/*": {Summary: "Revoke a gift claim", Response: openapi.Message{}},
+	"GET /api/v1/gift-claims/":                        {Summary: "List your gift claims", Response: []dto.GiftClaim{}},
+
+	"GET /api/v1/profile/blueprints/":      {Summary: "List owned blueprints", Response: dto.OwnedBlueprints{}},
+	"POST /api/v1/profile/blueprints/":     {Summary: "Add an owned blueprint", Request: dto.AddBlueprintRequest{}, Response: openapi.Message{}, Status: http.StatusCreated},
+	"POST /api/v1/profile/blueprints/bulk": {Summary: "Add several owned blueprints", Request: dto.BulkAddBlueprintsRequest{}, Response: openapi.Message{}, Status: http.StatusCreated},
+	"DELETE /api/v1/profile/blueprints/":   {Summary: "Clear owned blueprints", Response: openapi.Message{}},
+	"PUT /api/v1/profile/blueprints/*":     {Summary: "Set an owned blueprint quantity", Request: dto.SetOwnedBlueprintQuantityRequest{}, Response: openapi.Message{}},
+	"DELETE /api/v1/profile/blueprints/*":  {Summary: "Remove an owned blueprint", Response: openapi.Message{}},
+
+	"GET /api/v1/profile/components/":     {Summary: "List owned components", Response: dto.OwnedComponents{}},
+	"DELETE /api/v1/profile/components/":  {Summary: "Clear owned components", Response: openapi.Message{}},
+	"PUT /api/v1/profile/components/*":    {Summary: "Set an owned component count", Request: dto.SetOwnedComponentCountRequest{}, Response: openapi.Message{}},
+	"DELETE /api/v1/profile/components/*": {Summary: "Remove an owned component", Response: openapi.Message{}},
+
+	"GET /api/v1/profile/materials/":     {Summary: "List owned materials", Response: dto.OwnedMaterials{}},
+	"PUT /api/v1/profile/materials/":     {Summary: "Set several material counts", Request: dto.SetOwnedMaterialsRequest{}, Response: openapi.Message{}},
+	"DELETE /api/v1/profile/materials/":  {Summary: "Clear owned materials", Response: openapi.Message{}},
+	"PUT /api/v1/profile/materials/*":    {Summary: "Set a material count", Request: dto.SetOwnedMaterialCountRequest{}, Response: openapi.Message{}},
+	"DELETE /api/v1/profile/materials/*": {Summary: "Remove an owned material", Response: openapi.Message{}},
+
+	"POST /api/v1/profile/import/": {
+		Summary:  "Import owned items from an inventory export",
+		Query:    []openapi.Query{{Name: "format", Description: "Export format, game by default"}, modeQuery, dryRunQuery},
+		Request:  map[string]any{},
+		Response: dto.ProfileImportResult{},
+	},
+
+	"GET /api/v1/profile/mastery/":     {Summary: "Get the mastery profile", Response: dto.MasteryProfile{}},
+	"PUT /api/v1/profile/mastery/":     {Summary: "Set several mastery statuses", Request: dto.SetMasteryRequest{}, Response: openapi.Message{}},
+	"DELETE /api/v1/profile/mastery/":  {Summary: "Clear the mastery profile", Response: openapi.Message{}},
+	"PUT /api/v1/profile/mastery/*":    {Summary: "Set a mastery status", Request: dto.SetMasteryStatusRequest{}, Response: openapi.Message{}},
+	"DELETE /api/v1/profile/mastery/*": {Summary: "Remove an item from the mastery profile", Response: openapi.Message{}},
+	"GET /api/v1/profile/summary/":     {Summary: "Mastery progress by category", Response: dto.MasterySummary{}},
+
+	"GET /api/v1/profile/settings/":   {Summary: "Get settings", Response: dto.UserSettings{}},
+	"PATCH /api/v1/profile/settings/": {Summary: "Update settings", Request: dto.UpdateSettingsRequest{}, Response: dto.UserSettings{}},
+	"GET /api/v1/profile/usage/":      {Summary: "Usage against the per-user caps", Response: dto.UserUsage{}},
+	"GET /api/v1/profile/activity/": {
+		Summary:  "Recent wishlist and blueprint changes",
+		Query:    []openapi.Query{limitQuery, {Name: "offset", Type: "integer", Description: "Entries to skip"}},
+		Response: dto.ActivityResponse{},
+	},
+
+	"GET /api/v1/profile/foundry/":             {Summary: "List foundry builds", Response: dto.Foundry{}},
+	"POST /api/v1/profile/foundry/":            {Summary: "Start tracking a build", Request: dto.FoundryBuildRequest{}, Response: dto.FoundryBuild{}, Status: http.StatusCreated},
+	"DELETE /api/v1/profile/foundry/{buildID}": {Summary: "Stop tracking a build", Response: openapi.Message{}},
+
+	"GET /api/v1/notifications/push-key":                {Summary: "Web push public key", Response: dto.PushKey{}},
+	"GET /api/v1/notifications/channels":                {Summary: "List notification channels", Response: []dto.NotificationChannel{}},
+	"POST /api/v1/notifications/channels":               {Summary: "Register a notification channel", Request: dto.NotificationChannelRequest{}, Response: dto.CreatedNotificationChannel{}, Status: http.StatusCreated},
+	"DELETE /api/v1/notifications/channels/{channelID}": {Summary: "Delete a notification channel", Response: openapi.Message{}},
+	"GET /api/v1/notifications/deliveries":              {Summary: "Notification delivery log", Query: []openapi.Query{limitQuery}, Response: []dto.NotificationDelivery{}},
+
+	"POST /api/v1/graphql/":      {Summary: "Run a GraphQL query", Request: graphql.Request{}, Response: map[string]any{}},
+	"GET /api/v1/graphql/schema": {Summary: "The GraphQL schema", ContentTypes: []string{"text/plain"}},
+
+	"GET /api/v1/household/":                              {Summary: "Get the household", Response: dto.Household{}},
+	"PUT /api/v1/household/manager":                       {Summary: "Ask to be managed", Request: dto.RequestManagerRequest{}, Response: dto.HouseholdLink{}, Status: http.StatusCreated},
+	"DELETE /api/v1/household/manager":                    {Summary: "Leave your manager", Response: openapi.Message{}},
+	"POST /api/v1/household/members/{memberID}/accept":    {Summary: "Accept a member", Response: dto.HouseholdLink{}},
+	"PATCH /api/v1/household/members/{memberID}":          {Summary: "Change a member's approval threshold", Request: dto.UpdateHouseholdMemberRequest{}, Response: dto.HouseholdLink{}},
+	"DELETE /api/v1/household/members/{memberID}":         {Summary: "Remove a member", Response: openapi.Message{}},
+	"GET /api/v1/household/approvals":                     {Summary: "List pending changes", Response: dto.HouseholdApprovals{}},
+	"POST /api/v1/household/approvals/{changeID}/approve": {Summary: "Approve a pending change", Response: dto.PendingChange{}},
+	"POST /api/v1/household/approvals/{changeID}/reject":  {Summary: "Reject a pending change", Response: dto.PendingChange{}},
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/graytonio/warframe-wishlist/internal/buildinfo"
+)
+
+func TestOpenAPIHandler_Spec(t *testing.T) {
+	authenticate := func(next http.Handler) http.Handler { return next }
+	r := chi.NewRouter()
+	handler := NewOpenAPIHandler(r, "v1.2.3", authenticate)
+	meta := NewMetaHandler(buildinfo.Info{Version: "v1.2.3"}, nil)
+	r.Route(APIPrefix, func(r chi.Router) {
+		r.Get("/meta/version", meta.Version)
+		r.Get("/openapi.json", handler.Spec)
+		r.Get("/docs", handler.Docs)
+	})
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/openapi.json", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var doc struct {
+		OpenAPI string `json:"openapi"`
+		Info    struct {
+			Version string `json:"version"`
+		} `json:"info"`
+		Paths map[string]map[string]struct {
+			OperationID string `json:"operationId"`
+			Summary     string `json:"summary"`
+		} `json:"paths"`
+		Components struct {
+			Schemas map[string]json.RawMessage `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&doc); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.") || doc.Info.Version != "v1.2.3" {
+		t.Errorf("unexpected header %q %q", doc.OpenAPI, doc.Info.Version)
+	}
+	if len(doc.Paths) != 3 {
+		t.Errorf("expected the 3 mounted routes, got %v", doc.Paths)
+	}
+	version := doc.Paths["/api/v1/meta/version"]["get"]
+	if version.OperationID != "metaVersion" || version.Summary == "" {
+		t.Errorf("unexpected version operation %+v", version)
+	}
+	if doc.Components.Schemas["BuildVersion"] == nil || doc.Components.Schemas["ErrorResponse"] == nil {
+		t.Errorf("expected BuildVersion and ErrorResponse schemas, got %v", doc.Components.Schemas)
+	}
+}
+
+func TestOpenAPIHandler_Docs(t *testing.T) {
+	handler := NewOpenAPIHandler(chi.NewRouter(), "dev", nil)
+
+	rec := httptest.NewRecorder()
+	handler.Docs(rec, httptest.NewRequest(http.MethodGet, "/api/v1/docs", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+		t.Errorf("expected an HTML page, got %q", ct)
+	}
+	if !strings.Contains(rec.Body.String(), "openapi.json") {
+		t.Error("expected the page to load openapi.json")
+	}
+}
+
+// Keys that are not "METHOD /api/v1/..." chi patterns never match a route,
+// so their specs would silently go unused.
+func TestAPISpecs_KeysAreRoutePatterns(t *testing.T) {
+	key := regexp.MustCompile(`^(GET|POST|PUT|PATCH|DELETE) /api/v1/[A-Za-z0-9{}/.*_-]*$`)
+	for k, spec := range apiSpecs {
+		if !key.MatchString(k) {
+			t.Errorf("spec key %q is not a method and route pattern under %s", k, APIPrefix)
+		}
+		if spec.Summary == "" {
+			t.Errorf("spec %q has no summary", k)
+		}
+		if strings.Contains(k, "*") && !strings.HasSuffix(k, "/*") {
+			t.Errorf("spec key %q has a wildcard before its end", k)
+		}
+	}
+}
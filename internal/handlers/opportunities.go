@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+	"github.com/graytonio/warframe-wishlist/internal/middleware"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"github.com/graytonio/warframe-wishlist/pkg/response"
+)
+
+type OpportunityHandler struct {
+	opportunityFinder services.OpportunityFinderInterface
+}
+
+func NewOpportunityHandler(opportunityFinder services.OpportunityFinderInterface) *OpportunityHandler {
+	return &OpportunityHandler{opportunityFinder: opportunityFinder}
+}
+
+// GetOpportunities lists the active alerts, invasions and fissures rewarding
+// what the caller's wishlist still needs.
+func (h *OpportunityHandler) GetOpportunities(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: GetOpportunities called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: GetOpportunities - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	opportunities, err := h.opportunityFinder.GetOpportunities(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "handler: GetOpportunities - failed to find opportunities", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to find opportunities")
+		return
+	}
+
+	logger.Info(ctx, "handler: GetOpportunities - success", "opportunityCount", len(opportunities.Opportunities))
+	response.JSON(w, http.StatusOK, dto.NewOpportunities(opportunities))
+}
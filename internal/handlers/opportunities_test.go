@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+func TestOpportunityHandler_GetOpportunities(t *testing.T) {
+	fetchedAt := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name           string
+		userID         string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "success", userID: "user-123", expectedStatus: http.StatusOK},
+		{name: "unauthorized - no user ID", userID: "", expectedStatus: http.StatusUnauthorized},
+		{name: "service error", userID: "user-123", mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewOpportunityHandler(&mocks.MockOpportunityFinder{
+				GetOpportunitiesFunc: func(ctx context.Context, userID string) (*models.Opportunities, error) {
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return &models.Opportunities{
+						Opportunities: []models.Opportunity{{
+							WorldStateEvent: models.WorldStateEvent{
+								ID:      "invasion1",
+								Kind:    models.WorldStateInvasion,
+								Node:    "SolNode64",
+								Rewards: []models.WorldStateReward{{UniqueName: "/Lotus/Types/Items/Research/ChemComponent", Count: 3}},
+							},
+							NodeName:  "Unda",
+							Materials: []models.OpportunityMaterial{{UniqueName: "/Lotus/Types/Items/Research/ChemComponent", Name: "Detonite Injector", Count: 3, Remaining: 5}},
+							Relics:    []string{},
+						}},
+						FetchedAt: fetchedAt,
+					}, nil
+				},
+			})
+
+			req := createAuthenticatedRequest(http.MethodGet, "/api/v1/wishlist/opportunities", nil, tt.userID)
+			rec := httptest.NewRecorder()
+			handler.GetOpportunities(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var body map[string]interface{}
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if body["fetchedAt"] != "2026-05-01T12:00:00Z" {
+				t.Errorf("unexpected fetchedAt %v", body["fetchedAt"])
+			}
+			opportunities := body["opportunities"].([]interface{})
+			if len(opportunities) != 1 {
+				t.Fatalf("expected 1 opportunity, got %v", opportunities)
+			}
+			opportunity := opportunities[0].(map[string]interface{})
+			if opportunity["kind"] != "invasion" || opportunity["nodeName"] != "Unda" || opportunity["expiry"] != nil {
+				t.Errorf("unexpected opportunity %v", opportunity)
+			}
+			material := opportunity["materials"].([]interface{})[0].(map[string]interface{})
+			if material["name"] != "Detonite Injector" || material["count"] != 3.0 || material["remaining"] != 5.0 {
+				t.Errorf("unexpected material %v", material)
+			}
+		})
+	}
+}
@@ -5,7 +5,7 @@ import (
 	"errors"
 	"net/http"
 
-	"github.com/go-chi/chi/v5"
+	"github.com/graytonio/warframe-wishlist/internal/dto"
 	"github.com/graytonio/warframe-wishlist/internal/middleware"
 	"github.com/graytonio/warframe-wishlist/internal/models"
 	"github.com/graytonio/warframe-wishlist/internal/services"
@@ -15,6 +15,9 @@ import (
 
 type OwnedBlueprintsHandler struct {
 	ownedBPService services.OwnedBlueprintsServiceInterface
+	// usageService is optional; with it adding blueprints warns when the
+	// user nears the blueprint cap.
+	usageService services.UsageServiceInterface
 }
 
 func NewOwnedBlueprintsHandler(ownedBPService services.OwnedBlueprintsServiceInterface) *OwnedBlueprintsHandler {
@@ -23,6 +26,12 @@ func NewOwnedBlueprintsHandler(ownedBPService services.OwnedBlueprintsServiceInt
 	}
 }
 
+// SetUsageService makes AddBlueprint and BulkAddBlueprints set
+// UsageWarningHeader as the user nears the blueprint cap.
+func (h *OwnedBlueprintsHandler) SetUsageService(usageService services.UsageServiceInterface) {
+	h.usageService = usageService
+}
+
 func (h *OwnedBlueprintsHandler) GetOwnedBlueprints(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	logger.Debug(ctx, "handler: GetOwnedBlueprints called")
@@ -47,7 +56,7 @@ func (h *OwnedBlueprintsHandler) GetOwnedBlueprints(w http.ResponseWriter, r *ht
 		blueprintCount = len(ownedBP.Blueprints)
 	}
 	logger.Info(ctx, "handler: GetOwnedBlueprints - success", "blueprintCount", blueprintCount)
-	response.JSON(w, http.StatusOK, ownedBP)
+	response.JSON(w, http.StatusOK, dto.NewOwnedBlueprints(ownedBP))
 }
 
 func (h *OwnedBlueprintsHandler) AddBlueprint(w http.ResponseWriter, r *http.Request) {
@@ -61,35 +70,32 @@ func (h *OwnedBlueprintsHandler) AddBlueprint(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	var req models.AddBlueprintRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		logger.Warn(ctx, "handler: AddBlueprint - invalid request body", "error", err)
-		response.Error(w, http.StatusBadRequest, "invalid request body")
-		return
-	}
-
-	if req.UniqueName == "" {
-		logger.Warn(ctx, "handler: AddBlueprint - uniqueName is required")
-		response.Error(w, http.StatusBadRequest, "uniqueName is required")
+	var req dto.AddBlueprintRequest
+	if !decodeAndValidate(w, r, "AddBlueprint", &req) {
 		return
 	}
 
 	logger.Debug(ctx, "handler: AddBlueprint - adding blueprint", "uniqueName", req.UniqueName)
-	err := h.ownedBPService.AddBlueprint(ctx, userID, req)
+	err := h.ownedBPService.AddBlueprint(ctx, userID, req.ToModel())
 	if err != nil {
 		if errors.Is(err, services.ErrBlueprintNotFound) {
 			logger.Warn(ctx, "handler: AddBlueprint - blueprint not found", "uniqueName", req.UniqueName)
-			response.Error(w, http.StatusNotFound, "blueprint not found")
+			response.ErrorCode(w, http.StatusNotFound, response.CodeBlueprintNotFound, "blueprint not found")
 			return
 		}
 		if errors.Is(err, services.ErrBlueprintNotReusable) {
 			logger.Warn(ctx, "handler: AddBlueprint - blueprint not reusable", "uniqueName", req.UniqueName)
-			response.Error(w, http.StatusBadRequest, "blueprint is not reusable (consumeOnBuild is true)")
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeBlueprintNotReusable, "blueprint is not reusable (consumeOnBuild is true)")
 			return
 		}
 		if errors.Is(err, services.ErrBlueprintAlreadyOwned) {
 			logger.Warn(ctx, "handler: AddBlueprint - blueprint already owned", "uniqueName", req.UniqueName)
-			response.Error(w, http.StatusConflict, "blueprint already owned")
+			response.ErrorCode(w, http.StatusConflict, response.CodeBlueprintAlreadyOwned, "blueprint already owned")
+			return
+		}
+		if errors.Is(err, services.ErrTooManyBlueprints) {
+			logger.Warn(ctx, "handler: AddBlueprint - too many blueprints", "error", err)
+			rejected(w, http.StatusConflict, err)
 			return
 		}
 		logger.Error(ctx, "handler: AddBlueprint - failed to add blueprint", "error", err)
@@ -98,6 +104,7 @@ func (h *OwnedBlueprintsHandler) AddBlueprint(w http.ResponseWriter, r *http.Req
 	}
 
 	logger.Info(ctx, "handler: AddBlueprint - success", "uniqueName", req.UniqueName)
+	setUsageWarnings(w, r, h.usageService, userID, models.UsageOwnedBlueprints, models.UsageOwnedPartsBytes)
 	response.JSON(w, http.StatusCreated, map[string]string{
 		"message": "blueprint added",
 	})
@@ -115,22 +122,19 @@ func (h *OwnedBlueprintsHandler) RemoveBlueprint(w http.ResponseWriter, r *http.
 	}
 
 	// Use wildcard param to capture full path including slashes (e.g., /Lotus/Types/Items/...)
-	uniqueName := chi.URLParam(r, "*")
-	if uniqueName == "" {
-		logger.Warn(ctx, "handler: RemoveBlueprint - uniqueName is required")
-		response.Error(w, http.StatusBadRequest, "uniqueName is required")
+	uniqueName, err := uniqueNameParam(r)
+	if err != nil {
+		logger.Warn(ctx, "handler: RemoveBlueprint - invalid uniqueName", "error", err)
+		rejected(w, http.StatusBadRequest, err)
 		return
 	}
 
-	// Add leading slash to the uniqueName
-	uniqueName = "/" + uniqueName
-
 	logger.Debug(ctx, "handler: RemoveBlueprint - removing blueprint", "uniqueName", uniqueName)
-	err := h.ownedBPService.RemoveBlueprint(ctx, userID, uniqueName)
+	err = h.ownedBPService.RemoveBlueprint(ctx, userID, uniqueName)
 	if err != nil {
 		if errors.Is(err, services.ErrBlueprintNotOwned) {
 			logger.Warn(ctx, "handler: RemoveBlueprint - blueprint not owned", "uniqueName", uniqueName)
-			response.Error(w, http.StatusNotFound, "blueprint not owned")
+			response.ErrorCode(w, http.StatusNotFound, response.CodeBlueprintNotOwned, "blueprint not owned")
 			return
 		}
 		logger.Error(ctx, "handler: RemoveBlueprint - failed to remove blueprint", "error", err)
@@ -155,22 +159,26 @@ func (h *OwnedBlueprintsHandler) BulkAddBlueprints(w http.ResponseWriter, r *htt
 		return
 	}
 
-	var req models.BulkAddBlueprintsRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		logger.Warn(ctx, "handler: BulkAddBlueprints - invalid request body", "error", err)
-		response.Error(w, http.StatusBadRequest, "invalid request body")
+	var req dto.BulkAddBlueprintsRequest
+	if !decodeAndValidate(w, r, "BulkAddBlueprints", &req) {
 		return
 	}
 
 	logger.Debug(ctx, "handler: BulkAddBlueprints - bulk adding blueprints", "count", len(req.UniqueNames))
-	err := h.ownedBPService.BulkAddBlueprints(ctx, userID, req)
+	err := h.ownedBPService.BulkAddBlueprints(ctx, userID, req.ToModel())
 	if err != nil {
+		if errors.Is(err, services.ErrTooManyBlueprints) {
+			logger.Warn(ctx, "handler: BulkAddBlueprints - too many blueprints", "error", err)
+			rejected(w, http.StatusConflict, err)
+			return
+		}
 		logger.Error(ctx, "handler: BulkAddBlueprints - failed to bulk add blueprints", "error", err)
 		response.Error(w, http.StatusInternalServerError, "failed to bulk add blueprints")
 		return
 	}
 
 	logger.Info(ctx, "handler: BulkAddBlueprints - success", "count", len(req.UniqueNames))
+	setUsageWarnings(w, r, h.usageService, userID, models.UsageOwnedBlueprints, models.UsageOwnedPartsBytes)
 	response.JSON(w, http.StatusCreated, map[string]string{
 		"message": "blueprints added",
 	})
@@ -200,3 +208,178 @@ func (h *OwnedBlueprintsHandler) ClearAllBlueprints(w http.ResponseWriter, r *ht
 		"message": "all blueprints cleared",
 	})
 }
+
+func (h *OwnedBlueprintsHandler) GetOwnedComponents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: GetOwnedComponents called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: GetOwnedComponents - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	owned, err := h.ownedBPService.GetOwnedBlueprints(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "handler: GetOwnedComponents - failed to get owned components", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to get owned components")
+		return
+	}
+	if owned == nil {
+		owned = &models.OwnedBlueprints{UserID: userID}
+	}
+
+	logger.Info(ctx, "handler: GetOwnedComponents - success", "componentCount", len(owned.Components))
+	response.JSON(w, http.StatusOK, dto.NewOwnedComponents(owned))
+}
+
+func (h *OwnedBlueprintsHandler) SetBlueprintQuantity(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: SetBlueprintQuantity called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: SetBlueprintQuantity - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	uniqueName, err := uniqueNameParam(r)
+	if err != nil {
+		logger.Warn(ctx, "handler: SetBlueprintQuantity - invalid uniqueName", "error", err)
+		rejected(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var req dto.SetOwnedBlueprintQuantityRequest
+	if !decodeAndValidate(w, r, "SetBlueprintQuantity", &req) {
+		return
+	}
+
+	err = h.ownedBPService.SetBlueprintQuantity(ctx, userID, uniqueName, req.Quantity)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidBlueprintQuantity) {
+			logger.Warn(ctx, "handler: SetBlueprintQuantity - invalid quantity", "quantity", req.Quantity)
+			rejected(w, http.StatusBadRequest, err)
+			return
+		}
+		if errors.Is(err, services.ErrTooManyBlueprints) {
+			logger.Warn(ctx, "handler: SetBlueprintQuantity - too many blueprints", "error", err)
+			rejected(w, http.StatusConflict, err)
+			return
+		}
+		logger.Error(ctx, "handler: SetBlueprintQuantity - failed to set blueprint quantity", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to set blueprint quantity")
+		return
+	}
+
+	logger.Info(ctx, "handler: SetBlueprintQuantity - success", "uniqueName", uniqueName, "quantity", req.Quantity)
+	setUsageWarnings(w, r, h.usageService, userID, models.UsageOwnedBlueprints, models.UsageOwnedPartsBytes)
+	response.JSON(w, http.StatusOK, map[string]string{
+		"message": "blueprint updated",
+	})
+}
+
+func (h *OwnedBlueprintsHandler) SetComponentCount(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: SetComponentCount called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: SetComponentCount - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	uniqueName, err := uniqueNameParam(r)
+	if err != nil {
+		logger.Warn(ctx, "handler: SetComponentCount - invalid uniqueName", "error", err)
+		rejected(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var req dto.SetOwnedComponentCountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn(ctx, "handler: SetComponentCount - invalid request body", "error", err)
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	err = h.ownedBPService.SetComponentCount(ctx, userID, uniqueName, req.Count)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidComponentCount) {
+			logger.Warn(ctx, "handler: SetComponentCount - invalid count", "count", req.Count)
+			rejected(w, http.StatusBadRequest, err)
+			return
+		}
+		logger.Error(ctx, "handler: SetComponentCount - failed to set component count", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to set component count")
+		return
+	}
+
+	logger.Info(ctx, "handler: SetComponentCount - success", "uniqueName", uniqueName, "count", req.Count)
+	response.JSON(w, http.StatusOK, map[string]string{
+		"message": "component updated",
+	})
+}
+
+func (h *OwnedBlueprintsHandler) RemoveComponent(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: RemoveComponent called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: RemoveComponent - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	uniqueName, err := uniqueNameParam(r)
+	if err != nil {
+		logger.Warn(ctx, "handler: RemoveComponent - invalid uniqueName", "error", err)
+		rejected(w, http.StatusBadRequest, err)
+		return
+	}
+
+	err = h.ownedBPService.RemoveComponent(ctx, userID, uniqueName)
+	if err != nil {
+		if errors.Is(err, services.ErrComponentNotOwned) {
+			logger.Warn(ctx, "handler: RemoveComponent - component not owned", "uniqueName", uniqueName)
+			response.ErrorCode(w, http.StatusNotFound, response.CodeComponentNotOwned, "component not owned")
+			return
+		}
+		logger.Error(ctx, "handler: RemoveComponent - failed to remove component", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to remove component")
+		return
+	}
+
+	logger.Info(ctx, "handler: RemoveComponent - success", "uniqueName", uniqueName)
+	response.JSON(w, http.StatusOK, map[string]string{
+		"message": "component removed",
+	})
+}
+
+func (h *OwnedBlueprintsHandler) ClearAllComponents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: ClearAllComponents called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: ClearAllComponents - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	err := h.ownedBPService.ClearAllComponents(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "handler: ClearAllComponents - failed to clear components", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to clear components")
+		return
+	}
+
+	logger.Info(ctx, "handler: ClearAllComponents - success")
+	response.JSON(w, http.StatusOK, map[string]string{
+		"message": "all components cleared",
+	})
+}
@@ -11,17 +11,22 @@ import (
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/graytonio/warframe-wishlist/internal/dto"
 	"github.com/graytonio/warframe-wishlist/internal/middleware"
 	"github.com/graytonio/warframe-wishlist/internal/models"
 	"github.com/graytonio/warframe-wishlist/internal/services"
 )
 
 type mockOwnedBlueprintsService struct {
-	getOwnedBlueprintsFunc func(ctx context.Context, userID string) (*models.OwnedBlueprints, error)
-	addBlueprintFunc       func(ctx context.Context, userID string, req models.AddBlueprintRequest) error
-	removeBlueprintFunc    func(ctx context.Context, userID, uniqueName string) error
-	bulkAddBlueprintsFunc  func(ctx context.Context, userID string, req models.BulkAddBlueprintsRequest) error
-	clearAllBlueprintsFunc func(ctx context.Context, userID string) error
+	getOwnedBlueprintsFunc   func(ctx context.Context, userID string) (*models.OwnedBlueprints, error)
+	addBlueprintFunc         func(ctx context.Context, userID string, req models.AddBlueprintRequest) error
+	removeBlueprintFunc      func(ctx context.Context, userID, uniqueName string) error
+	bulkAddBlueprintsFunc    func(ctx context.Context, userID string, req models.BulkAddBlueprintsRequest) error
+	clearAllBlueprintsFunc   func(ctx context.Context, userID string) error
+	setBlueprintQuantityFunc func(ctx context.Context, userID, uniqueName string, quantity int) error
+	setComponentCountFunc    func(ctx context.Context, userID, uniqueName string, count int) error
+	removeComponentFunc      func(ctx context.Context, userID, uniqueName string) error
+	clearAllComponentsFunc   func(ctx context.Context, userID string) error
 }
 
 func (m *mockOwnedBlueprintsService) GetOwnedBlueprints(ctx context.Context, userID string) (*models.OwnedBlueprints, error) {
@@ -59,6 +64,34 @@ func (m *mockOwnedBlueprintsService) ClearAllBlueprints(ctx context.Context, use
 	return nil
 }
 
+func (m *mockOwnedBlueprintsService) SetBlueprintQuantity(ctx context.Context, userID, uniqueName string, quantity int) error {
+	if m.setBlueprintQuantityFunc != nil {
+		return m.setBlueprintQuantityFunc(ctx, userID, uniqueName, quantity)
+	}
+	return nil
+}
+
+func (m *mockOwnedBlueprintsService) SetComponentCount(ctx context.Context, userID, uniqueName string, count int) error {
+	if m.setComponentCountFunc != nil {
+		return m.setComponentCountFunc(ctx, userID, uniqueName, count)
+	}
+	return nil
+}
+
+func (m *mockOwnedBlueprintsService) RemoveComponent(ctx context.Context, userID, uniqueName string) error {
+	if m.removeComponentFunc != nil {
+		return m.removeComponentFunc(ctx, userID, uniqueName)
+	}
+	return nil
+}
+
+func (m *mockOwnedBlueprintsService) ClearAllComponents(ctx context.Context, userID string) error {
+	if m.clearAllComponentsFunc != nil {
+		return m.clearAllComponentsFunc(ctx, userID)
+	}
+	return nil
+}
+
 func createAuthenticatedOwnedBPRequest(method, url string, body []byte, userID string) *http.Request {
 	var req *http.Request
 	if body != nil {
@@ -132,14 +165,14 @@ func TestOwnedBlueprintsHandler_AddBlueprint(t *testing.T) {
 	tests := []struct {
 		name           string
 		userID         string
-		requestBody    models.AddBlueprintRequest
+		requestBody    dto.AddBlueprintRequest
 		mockError      error
 		expectedStatus int
 	}{
 		{
 			name:   "successful add blueprint",
 			userID: "user-123",
-			requestBody: models.AddBlueprintRequest{
+			requestBody: dto.AddBlueprintRequest{
 				UniqueName: "/Lotus/Blueprint1",
 			},
 			mockError:      nil,
@@ -148,35 +181,35 @@ func TestOwnedBlueprintsHandler_AddBlueprint(t *testing.T) {
 		{
 			name:           "unauthorized - no user ID",
 			userID:         "",
-			requestBody:    models.AddBlueprintRequest{UniqueName: "/Lotus/Blueprint1"},
+			requestBody:    dto.AddBlueprintRequest{UniqueName: "/Lotus/Blueprint1"},
 			mockError:      nil,
 			expectedStatus: http.StatusUnauthorized,
 		},
 		{
 			name:           "blueprint not found",
 			userID:         "user-123",
-			requestBody:    models.AddBlueprintRequest{UniqueName: "/Lotus/Nonexistent"},
+			requestBody:    dto.AddBlueprintRequest{UniqueName: "/Lotus/Nonexistent"},
 			mockError:      services.ErrBlueprintNotFound,
 			expectedStatus: http.StatusNotFound,
 		},
 		{
 			name:           "blueprint not reusable",
 			userID:         "user-123",
-			requestBody:    models.AddBlueprintRequest{UniqueName: "/Lotus/Consumable"},
+			requestBody:    dto.AddBlueprintRequest{UniqueName: "/Lotus/Consumable"},
 			mockError:      services.ErrBlueprintNotReusable,
 			expectedStatus: http.StatusBadRequest,
 		},
 		{
 			name:           "blueprint already owned",
 			userID:         "user-123",
-			requestBody:    models.AddBlueprintRequest{UniqueName: "/Lotus/Blueprint1"},
+			requestBody:    dto.AddBlueprintRequest{UniqueName: "/Lotus/Blueprint1"},
 			mockError:      services.ErrBlueprintAlreadyOwned,
 			expectedStatus: http.StatusConflict,
 		},
 		{
 			name:           "missing uniqueName",
 			userID:         "user-123",
-			requestBody:    models.AddBlueprintRequest{UniqueName: ""},
+			requestBody:    dto.AddBlueprintRequest{UniqueName: ""},
 			mockError:      nil,
 			expectedStatus: http.StatusBadRequest,
 		},
@@ -269,14 +302,14 @@ func TestOwnedBlueprintsHandler_BulkAddBlueprints(t *testing.T) {
 	tests := []struct {
 		name           string
 		userID         string
-		requestBody    models.BulkAddBlueprintsRequest
+		requestBody    dto.BulkAddBlueprintsRequest
 		mockError      error
 		expectedStatus int
 	}{
 		{
 			name:   "successful bulk add",
 			userID: "user-123",
-			requestBody: models.BulkAddBlueprintsRequest{
+			requestBody: dto.BulkAddBlueprintsRequest{
 				UniqueNames: []string{"/Lotus/Blueprint1", "/Lotus/Blueprint2"},
 			},
 			mockError:      nil,
@@ -285,14 +318,14 @@ func TestOwnedBlueprintsHandler_BulkAddBlueprints(t *testing.T) {
 		{
 			name:           "unauthorized - no user ID",
 			userID:         "",
-			requestBody:    models.BulkAddBlueprintsRequest{UniqueNames: []string{"/Lotus/Blueprint1"}},
+			requestBody:    dto.BulkAddBlueprintsRequest{UniqueNames: []string{"/Lotus/Blueprint1"}},
 			mockError:      nil,
 			expectedStatus: http.StatusUnauthorized,
 		},
 		{
 			name:           "service error",
 			userID:         "user-123",
-			requestBody:    models.BulkAddBlueprintsRequest{UniqueNames: []string{"/Lotus/Blueprint1"}},
+			requestBody:    dto.BulkAddBlueprintsRequest{UniqueNames: []string{"/Lotus/Blueprint1"}},
 			mockError:      errors.New("database error"),
 			expectedStatus: http.StatusInternalServerError,
 		},
@@ -426,3 +459,186 @@ func TestOwnedBlueprintsHandler_GetOwnedBlueprints_ReturnsCorrectData(t *testing
 		t.Errorf("expected %d blueprints, got %d", len(expectedOwnedBP.Blueprints), len(response.Blueprints))
 	}
 }
+
+func TestOwnedBlueprintsHandler_SetBlueprintQuantity(t *testing.T) {
+	tests := []struct {
+		name             string
+		userID           string
+		body             string
+		mockError        error
+		expectedStatus   int
+		expectedQuantity int
+	}{
+		{name: "successful set", userID: "user-123", body: `{"quantity": 4}`, expectedStatus: http.StatusOK, expectedQuantity: 4},
+		{name: "unauthorized - no user ID", userID: "", body: `{"quantity": 4}`, expectedStatus: http.StatusUnauthorized},
+		{name: "invalid body", userID: "user-123", body: `{`, expectedStatus: http.StatusBadRequest},
+		{name: "negative quantity", userID: "user-123", body: `{"quantity": -1}`, mockError: errors.New("service should not be called"), expectedStatus: http.StatusBadRequest},
+		{name: "reusable blueprint owned twice", userID: "user-123", body: `{"quantity": 2}`, mockError: services.ErrInvalidBlueprintQuantity, expectedStatus: http.StatusBadRequest},
+		{name: "too many blueprints", userID: "user-123", body: `{"quantity": 1}`, mockError: services.ErrTooManyBlueprints, expectedStatus: http.StatusConflict},
+		{name: "service error", userID: "user-123", body: `{"quantity": 1}`, mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotName string
+			var gotQuantity int
+			mockService := &mockOwnedBlueprintsService{
+				setBlueprintQuantityFunc: func(ctx context.Context, userID, uniqueName string, quantity int) error {
+					gotName, gotQuantity = uniqueName, quantity
+					return tt.mockError
+				},
+			}
+
+			handler := NewOwnedBlueprintsHandler(mockService)
+
+			r := chi.NewRouter()
+			r.Put("/api/v1/profile/blueprints/*", func(w http.ResponseWriter, r *http.Request) {
+				ctx := context.WithValue(r.Context(), middleware.UserIDKey, tt.userID)
+				handler.SetBlueprintQuantity(w, r.WithContext(ctx))
+			})
+
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/profile/blueprints/Lotus/Types/Recipes/Components/FormaBlueprint", bytes.NewReader([]byte(tt.body)))
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if tt.expectedStatus == http.StatusOK && (gotName != "/Lotus/Types/Recipes/Components/FormaBlueprint" || gotQuantity != tt.expectedQuantity) {
+				t.Errorf("expected the Forma blueprint set to %d, got %q %d", tt.expectedQuantity, gotName, gotQuantity)
+			}
+		})
+	}
+}
+
+func TestOwnedBlueprintsHandler_SetComponentCount(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		body           string
+		mockError      error
+		expectedStatus int
+		expectedCount  int
+	}{
+		{name: "successful set", userID: "user-123", body: `{"count": 2}`, expectedStatus: http.StatusOK, expectedCount: 2},
+		{name: "unauthorized - no user ID", userID: "", body: `{"count": 2}`, expectedStatus: http.StatusUnauthorized},
+		{name: "invalid body", userID: "user-123", body: `{`, expectedStatus: http.StatusBadRequest},
+		{name: "invalid count", userID: "user-123", body: `{"count": -1}`, mockError: services.ErrInvalidComponentCount, expectedStatus: http.StatusBadRequest},
+		{name: "service error", userID: "user-123", body: `{"count": 1}`, mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotName string
+			var gotCount int
+			mockService := &mockOwnedBlueprintsService{
+				setComponentCountFunc: func(ctx context.Context, userID, uniqueName string, count int) error {
+					gotName, gotCount = uniqueName, count
+					return tt.mockError
+				},
+			}
+
+			handler := NewOwnedBlueprintsHandler(mockService)
+
+			r := chi.NewRouter()
+			r.Put("/api/v1/profile/components/*", func(w http.ResponseWriter, r *http.Request) {
+				ctx := context.WithValue(r.Context(), middleware.UserIDKey, tt.userID)
+				handler.SetComponentCount(w, r.WithContext(ctx))
+			})
+
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/profile/components/Lotus/Types/Recipes/AshChassis", bytes.NewReader([]byte(tt.body)))
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if tt.expectedStatus == http.StatusOK && (gotName != "/Lotus/Types/Recipes/AshChassis" || gotCount != tt.expectedCount) {
+				t.Errorf("expected the chassis set to %d, got %q %d", tt.expectedCount, gotName, gotCount)
+			}
+		})
+	}
+}
+
+func TestOwnedBlueprintsHandler_RemoveComponent(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "successful remove", userID: "user-123", expectedStatus: http.StatusOK},
+		{name: "unauthorized - no user ID", userID: "", expectedStatus: http.StatusUnauthorized},
+		{name: "component not owned", userID: "user-123", mockError: services.ErrComponentNotOwned, expectedStatus: http.StatusNotFound},
+		{name: "service error", userID: "user-123", mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mockOwnedBlueprintsService{
+				removeComponentFunc: func(ctx context.Context, userID, uniqueName string) error {
+					return tt.mockError
+				},
+			}
+
+			handler := NewOwnedBlueprintsHandler(mockService)
+
+			r := chi.NewRouter()
+			r.Delete("/api/v1/profile/components/*", func(w http.ResponseWriter, r *http.Request) {
+				ctx := context.WithValue(r.Context(), middleware.UserIDKey, tt.userID)
+				handler.RemoveComponent(w, r.WithContext(ctx))
+			})
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/profile/components/Lotus/Types/Recipes/AshChassis", nil)
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+		})
+	}
+}
+
+func TestOwnedBlueprintsHandler_ComponentsListAndClear(t *testing.T) {
+	cleared := false
+	mockService := &mockOwnedBlueprintsService{
+		getOwnedBlueprintsFunc: func(ctx context.Context, userID string) (*models.OwnedBlueprints, error) {
+			return &models.OwnedBlueprints{
+				UserID:     userID,
+				Blueprints: []models.OwnedBlueprint{{UniqueName: "/Lotus/Blueprint1"}},
+				Components: []models.OwnedComponent{{UniqueName: "/Lotus/AshChassis", Count: 2}},
+			}, nil
+		},
+		clearAllComponentsFunc: func(ctx context.Context, userID string) error {
+			cleared = true
+			return nil
+		},
+	}
+	handler := NewOwnedBlueprintsHandler(mockService)
+
+	rec := httptest.NewRecorder()
+	handler.GetOwnedComponents(rec, createAuthenticatedOwnedBPRequest(http.MethodGet, "/api/v1/profile/components", nil, "user-123"))
+	var body dto.OwnedComponents
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if rec.Code != http.StatusOK || len(body.Components) != 1 || body.Components[0].Count != 2 {
+		t.Errorf("expected the owned chassis, got %d %+v", rec.Code, body)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ClearAllComponents(rec, createAuthenticatedOwnedBPRequest(http.MethodDelete, "/api/v1/profile/components", nil, "user-123"))
+	if rec.Code != http.StatusOK || !cleared {
+		t.Errorf("expected the components cleared, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ClearAllComponents(rec, createAuthenticatedOwnedBPRequest(http.MethodDelete, "/api/v1/profile/components", nil, ""))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, rec.Code)
+	}
+}
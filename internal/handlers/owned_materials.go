@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+	"github.com/graytonio/warframe-wishlist/internal/middleware"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"github.com/graytonio/warframe-wishlist/pkg/response"
+)
+
+type OwnedMaterialsHandler struct {
+	ownedMaterialsService services.OwnedMaterialsServiceInterface
+}
+
+func NewOwnedMaterialsHandler(ownedMaterialsService services.OwnedMaterialsServiceInterface) *OwnedMaterialsHandler {
+	return &OwnedMaterialsHandler{
+		ownedMaterialsService: ownedMaterialsService,
+	}
+}
+
+func (h *OwnedMaterialsHandler) GetOwnedMaterials(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: GetOwnedMaterials called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: GetOwnedMaterials - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	owned, err := h.ownedMaterialsService.GetOwnedMaterials(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "handler: GetOwnedMaterials - failed to get owned materials", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to get owned materials")
+		return
+	}
+
+	materialCount := 0
+	if owned != nil {
+		materialCount = len(owned.Materials)
+	}
+	logger.Info(ctx, "handler: GetOwnedMaterials - success", "materialCount", materialCount)
+	response.JSON(w, http.StatusOK, dto.NewOwnedMaterials(owned))
+}
+
+func (h *OwnedMaterialsHandler) SetMaterialCounts(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: SetMaterialCounts called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: SetMaterialCounts - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	var req dto.SetOwnedMaterialsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn(ctx, "handler: SetMaterialCounts - invalid request body", "error", err)
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	err := h.ownedMaterialsService.SetMaterialCounts(ctx, userID, req.ToModel())
+	if err != nil {
+		if validationFailed(w, r, "SetMaterialCounts", err) {
+			return
+		}
+		if errors.Is(err, services.ErrInvalidMaterialCount) || errors.Is(err, services.ErrTooManyMaterials) ||
+			errors.Is(err, models.ErrUniqueNameRequired) || errors.Is(err, models.ErrInvalidUniqueName) {
+			logger.Warn(ctx, "handler: SetMaterialCounts - invalid materials", "error", err)
+			rejected(w, http.StatusBadRequest, err)
+			return
+		}
+		logger.Error(ctx, "handler: SetMaterialCounts - failed to set material counts", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to set material counts")
+		return
+	}
+
+	logger.Info(ctx, "handler: SetMaterialCounts - success", "count", len(req.Materials))
+	response.JSON(w, http.StatusOK, map[string]string{
+		"message": "materials updated",
+	})
+}
+
+func (h *OwnedMaterialsHandler) SetMaterialCount(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: SetMaterialCount called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: SetMaterialCount - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	uniqueName, err := uniqueNameParam(r)
+	if err != nil {
+		logger.Warn(ctx, "handler: SetMaterialCount - invalid uniqueName", "error", err)
+		rejected(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var req dto.SetOwnedMaterialCountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn(ctx, "handler: SetMaterialCount - invalid request body", "error", err)
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	err = h.ownedMaterialsService.SetMaterialCount(ctx, userID, uniqueName, req.Count)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidMaterialCount) {
+			logger.Warn(ctx, "handler: SetMaterialCount - invalid count", "count", req.Count)
+			rejected(w, http.StatusBadRequest, err)
+			return
+		}
+		logger.Error(ctx, "handler: SetMaterialCount - failed to set material count", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to set material count")
+		return
+	}
+
+	logger.Info(ctx, "handler: SetMaterialCount - success", "uniqueName", uniqueName, "count", req.Count)
+	response.JSON(w, http.StatusOK, map[string]string{
+		"message": "material updated",
+	})
+}
+
+func (h *OwnedMaterialsHandler) RemoveMaterial(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: RemoveMaterial called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: RemoveMaterial - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	uniqueName, err := uniqueNameParam(r)
+	if err != nil {
+		logger.Warn(ctx, "handler: RemoveMaterial - invalid uniqueName", "error", err)
+		rejected(w, http.StatusBadRequest, err)
+		return
+	}
+
+	err = h.ownedMaterialsService.RemoveMaterial(ctx, userID, uniqueName)
+	if err != nil {
+		if errors.Is(err, services.ErrMaterialNotOwned) {
+			logger.Warn(ctx, "handler: RemoveMaterial - material not owned", "uniqueName", uniqueName)
+			response.ErrorCode(w, http.StatusNotFound, response.CodeMaterialNotOwned, "material not owned")
+			return
+		}
+		logger.Error(ctx, "handler: RemoveMaterial - failed to remove material", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to remove material")
+		return
+	}
+
+	logger.Info(ctx, "handler: RemoveMaterial - success", "uniqueName", uniqueName)
+	response.JSON(w, http.StatusOK, map[string]string{
+		"message": "material removed",
+	})
+}
+
+func (h *OwnedMaterialsHandler) ClearAllMaterials(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: ClearAllMaterials called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: ClearAllMaterials - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	err := h.ownedMaterialsService.ClearAllMaterials(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "handler: ClearAllMaterials - failed to clear materials", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to clear materials")
+		return
+	}
+
+	logger.Info(ctx, "handler: ClearAllMaterials - success")
+	response.JSON(w, http.StatusOK, map[string]string{
+		"message": "all materials cleared",
+	})
+}
@@ -0,0 +1,270 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/graytonio/warframe-wishlist/internal/middleware"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+)
+
+type mockOwnedMaterialsService struct {
+	getOwnedMaterialsFunc func(ctx context.Context, userID string) (*models.OwnedMaterials, error)
+	setMaterialCountFunc  func(ctx context.Context, userID, uniqueName string, count int) error
+	setMaterialCountsFunc func(ctx context.Context, userID string, req models.SetOwnedMaterialsRequest) error
+	removeMaterialFunc    func(ctx context.Context, userID, uniqueName string) error
+	clearAllMaterialsFunc func(ctx context.Context, userID string) error
+}
+
+func (m *mockOwnedMaterialsService) GetOwnedMaterials(ctx context.Context, userID string) (*models.OwnedMaterials, error) {
+	if m.getOwnedMaterialsFunc != nil {
+		return m.getOwnedMaterialsFunc(ctx, userID)
+	}
+	return &models.OwnedMaterials{UserID: userID, Materials: []models.OwnedMaterial{}}, nil
+}
+
+func (m *mockOwnedMaterialsService) SetMaterialCount(ctx context.Context, userID, uniqueName string, count int) error {
+	if m.setMaterialCountFunc != nil {
+		return m.setMaterialCountFunc(ctx, userID, uniqueName, count)
+	}
+	return nil
+}
+
+func (m *mockOwnedMaterialsService) SetMaterialCounts(ctx context.Context, userID string, req models.SetOwnedMaterialsRequest) error {
+	if m.setMaterialCountsFunc != nil {
+		return m.setMaterialCountsFunc(ctx, userID, req)
+	}
+	return nil
+}
+
+func (m *mockOwnedMaterialsService) RemoveMaterial(ctx context.Context, userID, uniqueName string) error {
+	if m.removeMaterialFunc != nil {
+		return m.removeMaterialFunc(ctx, userID, uniqueName)
+	}
+	return nil
+}
+
+func (m *mockOwnedMaterialsService) ClearAllMaterials(ctx context.Context, userID string) error {
+	if m.clearAllMaterialsFunc != nil {
+		return m.clearAllMaterialsFunc(ctx, userID)
+	}
+	return nil
+}
+
+// newOwnedMaterialsRouter mounts the handler on the cmd/server routes with
+// userID injected in place of the auth middleware.
+func newOwnedMaterialsRouter(service services.OwnedMaterialsServiceInterface, userID string) http.Handler {
+	handler := NewOwnedMaterialsHandler(service)
+	r := chi.NewRouter()
+	r.Route("/api/v1/profile/materials", func(r chi.Router) {
+		r.Use(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ctx := context.WithValue(r.Context(), middleware.UserIDKey, userID)
+				next.ServeHTTP(w, r.WithContext(ctx))
+			})
+		})
+		r.Get("/", handler.GetOwnedMaterials)
+		r.Put("/", handler.SetMaterialCounts)
+		r.Delete("/", handler.ClearAllMaterials)
+		r.Put("/*", handler.SetMaterialCount)
+		r.Delete("/*", handler.RemoveMaterial)
+	})
+	return r
+}
+
+func TestOwnedMaterialsHandler_GetOwnedMaterials(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "successful get owned materials", userID: "user-123", expectedStatus: http.StatusOK},
+		{name: "unauthorized - no user ID", userID: "", expectedStatus: http.StatusUnauthorized},
+		{name: "service error", userID: "user-123", mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mockOwnedMaterialsService{
+				getOwnedMaterialsFunc: func(ctx context.Context, userID string) (*models.OwnedMaterials, error) {
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return &models.OwnedMaterials{
+						UserID:    userID,
+						Materials: []models.OwnedMaterial{{UniqueName: "/Lotus/Ferrite", Count: 500}},
+					}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/profile/materials/", nil)
+			rec := httptest.NewRecorder()
+			newOwnedMaterialsRouter(mockService, tt.userID).ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var owned models.OwnedMaterials
+			if err := json.NewDecoder(rec.Body).Decode(&owned); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if len(owned.Materials) != 1 || owned.Materials[0].Count != 500 {
+				t.Errorf("unexpected inventory: %+v", owned)
+			}
+		})
+	}
+}
+
+func TestOwnedMaterialsHandler_SetMaterialCounts(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		body           string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "successful bulk set", userID: "user-123", body: `{"materials":[{"uniqueName":"/Lotus/Ferrite","count":10}]}`, expectedStatus: http.StatusOK},
+		{name: "unauthorized - no user ID", userID: "", body: `{}`, expectedStatus: http.StatusUnauthorized},
+		{name: "invalid body", userID: "user-123", body: `{`, expectedStatus: http.StatusBadRequest},
+		{name: "invalid count", userID: "user-123", body: `{}`, mockError: services.ErrInvalidMaterialCount, expectedStatus: http.StatusBadRequest},
+		{name: "too many materials", userID: "user-123", body: `{}`, mockError: services.ErrTooManyMaterials, expectedStatus: http.StatusBadRequest},
+		{name: "invalid uniqueName", userID: "user-123", body: `{}`, mockError: models.ErrInvalidUniqueName, expectedStatus: http.StatusBadRequest},
+		{name: "validation problems", userID: "user-123", body: `{}`, mockError: newTestValidationError(), expectedStatus: http.StatusUnprocessableEntity},
+		{name: "service error", userID: "user-123", body: `{}`, mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mockOwnedMaterialsService{
+				setMaterialCountsFunc: func(ctx context.Context, userID string, req models.SetOwnedMaterialsRequest) error {
+					return tt.mockError
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/profile/materials/", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			newOwnedMaterialsRouter(mockService, tt.userID).ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+		})
+	}
+}
+
+func TestOwnedMaterialsHandler_SetMaterialCount(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		path           string
+		body           string
+		mockError      error
+		expectedStatus int
+		expectedName   string
+	}{
+		{name: "successful set", userID: "user-123", path: "Lotus/Ferrite", body: `{"count":25}`, expectedStatus: http.StatusOK, expectedName: "/Lotus/Ferrite"},
+		{name: "unauthorized - no user ID", userID: "", path: "Lotus/Ferrite", body: `{"count":25}`, expectedStatus: http.StatusUnauthorized},
+		{name: "invalid uniqueName", userID: "user-123", path: "Lotus/%2E%2E/Ferrite", body: `{"count":25}`, expectedStatus: http.StatusBadRequest},
+		{name: "invalid body", userID: "user-123", path: "Lotus/Ferrite", body: `{"count":"many"}`, expectedStatus: http.StatusBadRequest},
+		{name: "invalid count", userID: "user-123", path: "Lotus/Ferrite", body: `{"count":-1}`, mockError: services.ErrInvalidMaterialCount, expectedStatus: http.StatusBadRequest},
+		{name: "service error", userID: "user-123", path: "Lotus/Ferrite", body: `{"count":25}`, mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotName string
+			mockService := &mockOwnedMaterialsService{
+				setMaterialCountFunc: func(ctx context.Context, userID, uniqueName string, count int) error {
+					gotName = uniqueName
+					return tt.mockError
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/profile/materials/"+tt.path, strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			newOwnedMaterialsRouter(mockService, tt.userID).ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if tt.expectedName != "" && gotName != tt.expectedName {
+				t.Errorf("expected uniqueName '%s', got '%s'", tt.expectedName, gotName)
+			}
+		})
+	}
+}
+
+func TestOwnedMaterialsHandler_RemoveMaterial(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "successful remove", userID: "user-123", expectedStatus: http.StatusOK},
+		{name: "unauthorized - no user ID", userID: "", expectedStatus: http.StatusUnauthorized},
+		{name: "material not owned", userID: "user-123", mockError: services.ErrMaterialNotOwned, expectedStatus: http.StatusNotFound},
+		{name: "service error", userID: "user-123", mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mockOwnedMaterialsService{
+				removeMaterialFunc: func(ctx context.Context, userID, uniqueName string) error {
+					return tt.mockError
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/profile/materials/Lotus/Ferrite", nil)
+			rec := httptest.NewRecorder()
+			newOwnedMaterialsRouter(mockService, tt.userID).ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+		})
+	}
+}
+
+func TestOwnedMaterialsHandler_ClearAllMaterials(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "successful clear", userID: "user-123", expectedStatus: http.StatusOK},
+		{name: "unauthorized - no user ID", userID: "", expectedStatus: http.StatusUnauthorized},
+		{name: "service error", userID: "user-123", mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mockOwnedMaterialsService{
+				clearAllMaterialsFunc: func(ctx context.Context, userID string) error {
+					return tt.mockError
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/profile/materials/", nil)
+			rec := httptest.NewRecorder()
+			newOwnedMaterialsRouter(mockService, tt.userID).ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+		})
+	}
+}
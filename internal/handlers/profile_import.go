@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+	"github.com/graytonio/warframe-wishlist/internal/inventory"
+	"github.com/graytonio/warframe-wishlist/internal/middleware"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"github.com/graytonio/warframe-wishlist/pkg/response"
+)
+
+// maxInventorySize bounds an uploaded inventory export; a full game
+// inventory is a few megabytes.
+const maxInventorySize = 32 << 20
+
+type ProfileImportHandler struct {
+	profileImportService services.ProfileImportServiceInterface
+}
+
+func NewProfileImportHandler(profileImportService services.ProfileImportServiceInterface) *ProfileImportHandler {
+	return &ProfileImportHandler{
+		profileImportService: profileImportService,
+	}
+}
+
+// Import reads an inventory export sent as the request body into the user's
+// owned blueprints, components and materials. The format query parameter
+// picks the export's adapter (default "game"); mode and dryRun work as for a
+// wishlist import.
+func (h *ProfileImportHandler) Import(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: ImportProfile called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: ImportProfile - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	dryRun := false
+	if raw := r.URL.Query().Get("dryRun"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			logger.Warn(ctx, "handler: ImportProfile - invalid dryRun", "dryRun", raw)
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidDryRun, "dryRun must be true or false")
+			return
+		}
+		dryRun = parsed
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxInventorySize+1))
+	if err != nil {
+		logger.Warn(ctx, "handler: ImportProfile - failed to read request body", "error", err)
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if len(data) > maxInventorySize {
+		logger.Warn(ctx, "handler: ImportProfile - inventory too large")
+		response.ErrorCode(w, http.StatusRequestEntityTooLarge, response.CodeInventoryTooLarge, fmt.Sprintf("inventory must be at most %d MB", maxInventorySize>>20))
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = inventory.FormatGame
+	}
+	inv, err := inventory.Parse(format, data)
+	if err != nil {
+		logger.Warn(ctx, "handler: ImportProfile - unreadable inventory", "format", format, "error", err)
+		rejected(w, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := h.profileImportService.Import(ctx, userID, models.ProfileImportRequest{
+		Format:    format,
+		Mode:      r.URL.Query().Get("mode"),
+		DryRun:    dryRun,
+		Inventory: *inv,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidImportMode), errors.Is(err, services.ErrInventoryTooLarge):
+			logger.Warn(ctx, "handler: ImportProfile - request rejected", "error", err)
+			rejected(w, http.StatusBadRequest, err)
+		case errors.Is(err, services.ErrTooManyBlueprints):
+			logger.Warn(ctx, "handler: ImportProfile - request rejected", "error", err)
+			rejected(w, http.StatusConflict, err)
+		default:
+			logger.Error(ctx, "handler: ImportProfile - failed to import profile", "error", err)
+			response.Error(w, http.StatusInternalServerError, "failed to import profile")
+		}
+		return
+	}
+
+	logger.Info(ctx, "handler: ImportProfile - success", "format", result.Format, "mode", result.Mode, "dryRun", result.DryRun)
+	response.JSON(w, http.StatusOK, dto.NewProfileImportResult(result))
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+)
+
+const testGameInventory = `{"Recipes": [{"ItemType": "/Lotus/Types/Recipes/WarframeRecipes/RhinoBlueprint", "ItemCount": 1}], "MiscItems": [{"ItemType": "/Lotus/Types/Items/MiscItems/Ferrite", "ItemCount": 500}]}`
+
+func TestProfileImportHandler_Import(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		query          string
+		body           string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "success", userID: "user-123", query: "?mode=replace&dryRun=true", body: testGameInventory, expectedStatus: http.StatusOK},
+		{name: "unauthorized - no user ID", userID: "", body: testGameInventory, expectedStatus: http.StatusUnauthorized},
+		{name: "invalid dryRun", userID: "user-123", query: "?dryRun=maybe", body: testGameInventory, expectedStatus: http.StatusBadRequest},
+		{name: "unsupported format", userID: "user-123", query: "?format=csv", body: testGameInventory, expectedStatus: http.StatusBadRequest},
+		{name: "not an inventory", userID: "user-123", body: `{"format":"warframe-wishlist"}`, expectedStatus: http.StatusBadRequest},
+		{name: "invalid mode", userID: "user-123", body: testGameInventory, mockError: services.ErrInvalidImportMode, expectedStatus: http.StatusBadRequest},
+		{name: "blueprint cap", userID: "user-123", body: testGameInventory, mockError: fmt.Errorf("%w: at most 1 blueprints", services.ErrTooManyBlueprints), expectedStatus: http.StatusConflict},
+		{name: "service error", userID: "user-123", body: testGameInventory, mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got models.ProfileImportRequest
+			handler := NewProfileImportHandler(&mocks.MockProfileImportService{
+				ImportFunc: func(ctx context.Context, userID string, req models.ProfileImportRequest) (*models.ProfileImportResult, error) {
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					got = req
+					return &models.ProfileImportResult{
+						Format:     req.Format,
+						Mode:       req.Mode,
+						DryRun:     req.DryRun,
+						Blueprints: models.ProfileImportTally{Added: len(req.Inventory.Blueprints)},
+						Materials:  models.ProfileImportTally{Added: len(req.Inventory.Items)},
+					}, nil
+				},
+			})
+			r := chi.NewRouter()
+			r.With(withTestUser(tt.userID)).Post("/api/v1/profile/import", handler.Import)
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/profile/import"+tt.query, strings.NewReader(tt.body)))
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			if got.Format != "game" || got.Mode != models.ImportModeReplace || !got.DryRun {
+				t.Errorf("unexpected request %+v", got)
+			}
+			if len(got.Inventory.Items) != 1 || got.Inventory.Items[0].Count != 500 {
+				t.Errorf("expected the inventory parsed, got %+v", got.Inventory)
+			}
+			var body struct {
+				Format     string `json:"format"`
+				Blueprints struct {
+					Added int `json:"added"`
+				} `json:"blueprints"`
+			}
+			json.NewDecoder(rec.Body).Decode(&body)
+			if body.Format != "game" || body.Blueprints.Added != 1 {
+				t.Errorf("unexpected body %s", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestProfileImportHandler_Import_TooLarge(t *testing.T) {
+	handler := NewProfileImportHandler(&mocks.MockProfileImportService{})
+	r := chi.NewRouter()
+	r.With(withTestUser("user-123")).Post("/api/v1/profile/import", handler.Import)
+
+	body := strings.NewReader(strings.Repeat(" ", maxInventorySize+1))
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/profile/import", body))
+
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected status 413, got %d", rec.Code)
+	}
+}
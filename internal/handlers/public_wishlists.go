@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"github.com/graytonio/warframe-wishlist/pkg/response"
+)
+
+// PublicWishlistHandler serves the kiosk mode's preloaded wishlists. Its
+// routes are unauthenticated.
+type PublicWishlistHandler struct {
+	publicWishlistService services.PublicWishlistServiceInterface
+}
+
+func NewPublicWishlistHandler(publicWishlistService services.PublicWishlistServiceInterface) *PublicWishlistHandler {
+	return &PublicWishlistHandler{publicWishlistService: publicWishlistService}
+}
+
+func (h *PublicWishlistHandler) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: ListPublicWishlists called")
+
+	wishlists, err := h.publicWishlistService.List(ctx)
+	if err != nil {
+		logger.Error(ctx, "handler: ListPublicWishlists - failed to list public wishlists", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to list public wishlists")
+		return
+	}
+
+	logger.Info(ctx, "handler: ListPublicWishlists - success", "count", len(wishlists))
+	response.JSON(w, http.StatusOK, dto.NewPublicWishlists(wishlists))
+}
+
+func (h *PublicWishlistHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: GetPublicWishlist called")
+
+	id := chi.URLParam(r, "id")
+	wishlist, err := h.publicWishlistService.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, services.ErrPublicWishlistNotFound) {
+			logger.Warn(ctx, "handler: GetPublicWishlist - not found", "id", id)
+			response.Error(w, http.StatusNotFound, "public wishlist not found")
+			return
+		}
+		logger.Error(ctx, "handler: GetPublicWishlist - failed to get public wishlist", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to get public wishlist")
+		return
+	}
+
+	logger.Info(ctx, "handler: GetPublicWishlist - success", "id", id, "itemCount", len(wishlist.Items))
+	response.JSON(w, http.StatusOK, dto.NewPublicWishlist(wishlist))
+}
+
+func (h *PublicWishlistHandler) GetMaterials(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: GetPublicWishlistMaterials called")
+
+	id := chi.URLParam(r, "id")
+	materials, err := h.publicWishlistService.GetMaterials(ctx, id)
+	if err != nil {
+		if errors.Is(err, services.ErrPublicWishlistNotFound) {
+			logger.Warn(ctx, "handler: GetPublicWishlistMaterials - not found", "id", id)
+			response.Error(w, http.StatusNotFound, "public wishlist not found")
+			return
+		}
+		logger.Error(ctx, "handler: GetPublicWishlistMaterials - failed to get materials", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to get materials")
+		return
+	}
+
+	logger.Info(ctx, "handler: GetPublicWishlistMaterials - success", "id", id, "materialCount", len(materials.Materials))
+	response.JSON(w, http.StatusOK, dto.NewMaterialsSummary(materials))
+}
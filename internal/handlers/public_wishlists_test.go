@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+)
+
+type mockPublicWishlistService struct {
+	listFunc         func(ctx context.Context) ([]models.PublicWishlist, error)
+	getFunc          func(ctx context.Context, id string) (*models.PublicWishlist, error)
+	getMaterialsFunc func(ctx context.Context, id string) (*models.MaterialsResponse, error)
+}
+
+func (m *mockPublicWishlistService) List(ctx context.Context) ([]models.PublicWishlist, error) {
+	if m.listFunc != nil {
+		return m.listFunc(ctx)
+	}
+	return []models.PublicWishlist{}, nil
+}
+
+func (m *mockPublicWishlistService) Get(ctx context.Context, id string) (*models.PublicWishlist, error) {
+	if m.getFunc != nil {
+		return m.getFunc(ctx, id)
+	}
+	return nil, services.ErrPublicWishlistNotFound
+}
+
+func (m *mockPublicWishlistService) GetMaterials(ctx context.Context, id string) (*models.MaterialsResponse, error) {
+	if m.getMaterialsFunc != nil {
+		return m.getMaterialsFunc(ctx, id)
+	}
+	return nil, services.ErrPublicWishlistNotFound
+}
+
+func newPublicWishlistRouter(service services.PublicWishlistServiceInterface) http.Handler {
+	handler := NewPublicWishlistHandler(service)
+	r := chi.NewRouter()
+	r.Get("/", handler.List)
+	r.Get("/{id}", handler.Get)
+	r.Get("/{id}/materials", handler.GetMaterials)
+	return r
+}
+
+func TestPublicWishlistHandler_List(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "success", expectedStatus: http.StatusOK},
+		{name: "service error", mockError: errors.New("boom"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mockPublicWishlistService{
+				listFunc: func(ctx context.Context) ([]models.PublicWishlist, error) {
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return []models.PublicWishlist{{ID: "primes", Name: "Primes"}}, nil
+				},
+			}
+
+			rec := httptest.NewRecorder()
+			newPublicWishlistRouter(service).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			var body []models.PublicWishlist
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if len(body) != 1 || body[0].ID != "primes" {
+				t.Errorf("unexpected response: %+v", body)
+			}
+		})
+	}
+}
+
+func TestPublicWishlistHandler_Get(t *testing.T) {
+	service := &mockPublicWishlistService{
+		getFunc: func(ctx context.Context, id string) (*models.PublicWishlist, error) {
+			switch id {
+			case "primes":
+				return &models.PublicWishlist{ID: id, Items: []models.WishlistItem{{UniqueName: "/Lotus/A", Quantity: 1}}}, nil
+			case "broken":
+				return nil, errors.New("boom")
+			}
+			return nil, services.ErrPublicWishlistNotFound
+		},
+	}
+	router := newPublicWishlistRouter(service)
+
+	tests := map[string]int{
+		"/primes":  http.StatusOK,
+		"/missing": http.StatusNotFound,
+		"/broken":  http.StatusInternalServerError,
+	}
+	for path, expected := range tests {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != expected {
+			t.Errorf("%s: expected status %d, got %d", path, expected, rec.Code)
+		}
+	}
+}
+
+func TestPublicWishlistHandler_GetMaterials(t *testing.T) {
+	service := &mockPublicWishlistService{
+		getMaterialsFunc: func(ctx context.Context, id string) (*models.MaterialsResponse, error) {
+			switch id {
+			case "primes":
+				return &models.MaterialsResponse{
+					Materials:    []models.MaterialRequirement{{UniqueName: "/Lotus/Ferrite", Name: "Ferrite", TotalCount: 100}},
+					TotalCredits: 15000,
+				}, nil
+			case "broken":
+				return nil, errors.New("boom")
+			}
+			return nil, services.ErrPublicWishlistNotFound
+		},
+	}
+	router := newPublicWishlistRouter(service)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/primes/materials", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	var body struct {
+		Materials    []models.MaterialRequirement `json:"materials"`
+		TotalCredits int                          `json:"totalCredits"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(body.Materials) != 1 || body.TotalCredits != 15000 {
+		t.Errorf("unexpected response: %s", rec.Body.String())
+	}
+
+	for path, expected := range map[string]int{"/missing/materials": http.StatusNotFound, "/broken/materials": http.StatusInternalServerError} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != expected {
+			t.Errorf("%s: expected status %d, got %d", path, expected, rec.Code)
+		}
+	}
+}
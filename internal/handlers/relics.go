@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+	"github.com/graytonio/warframe-wishlist/internal/middleware"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"github.com/graytonio/warframe-wishlist/pkg/response"
+)
+
+type RelicHandler struct {
+	relicResolver services.RelicResolverInterface
+}
+
+func NewRelicHandler(relicResolver services.RelicResolverInterface) *RelicHandler {
+	return &RelicHandler{relicResolver: relicResolver}
+}
+
+// GetRelicRequirements lists the relics dropping the Prime parts the
+// caller's wishlist still needs.
+func (h *RelicHandler) GetRelicRequirements(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: GetRelicRequirements called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: GetRelicRequirements - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	requirements, err := h.relicResolver.GetRelicRequirements(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "handler: GetRelicRequirements - failed to resolve relics", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to resolve relics")
+		return
+	}
+
+	logger.Info(ctx, "handler: GetRelicRequirements - success", "relicCount", len(requirements.Relics), "unavailableCount", len(requirements.Unavailable))
+	response.JSON(w, http.StatusOK, dto.NewRelicRequirements(requirements))
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+func TestRelicHandler_GetRelicRequirements(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "success", userID: "user-123", expectedStatus: http.StatusOK},
+		{name: "unauthorized - no user ID", userID: "", expectedStatus: http.StatusUnauthorized},
+		{name: "service error", userID: "user-123", mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewRelicHandler(&mocks.MockRelicResolver{
+				GetRelicRequirementsFunc: func(ctx context.Context, userID string) (*models.RelicRequirements, error) {
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					part := models.PrimePart{UniqueName: "/Lotus/AshPrimeBlueprint", Name: "Ash Prime Blueprint", ItemUniqueName: "/Lotus/AshPrime", ItemName: "Ash Prime", Needed: 1}
+					return &models.RelicRequirements{
+						Relics: []models.RelicRequirement{{
+							Name:  "Axi I3",
+							Tier:  "Axi",
+							Parts: []models.RelicPart{{PrimePart: part, Rarity: "Uncommon", Chances: models.RelicChances{Intact: 0.11, Radiant: 0.2}}},
+						}},
+						Unavailable: []models.PrimePart{},
+					}, nil
+				},
+			})
+
+			req := createAuthenticatedRequest(http.MethodGet, "/api/v1/wishlist/relics", nil, tt.userID)
+			rec := httptest.NewRecorder()
+			handler.GetRelicRequirements(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var body map[string]interface{}
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			relics := body["relics"].([]interface{})
+			if len(relics) != 1 {
+				t.Fatalf("expected 1 relic, got %v", relics)
+			}
+			part := relics[0].(map[string]interface{})["parts"].([]interface{})[0].(map[string]interface{})
+			if part["name"] != "Ash Prime Blueprint" || part["itemName"] != "Ash Prime" || part["needed"] != 1.0 {
+				t.Errorf("expected the part fields inline, got %v", part)
+			}
+			chances := part["chances"].(map[string]interface{})
+			if chances["intact"] != 0.11 || chances["radiant"] != 0.2 || chances["flawless"] != 0.0 {
+				t.Errorf("unexpected chances %v", chances)
+			}
+			if !strings.Contains(rec.Header().Get("Content-Type"), "application/json") {
+				t.Errorf("unexpected content type %q", rec.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
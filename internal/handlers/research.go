@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"github.com/graytonio/warframe-wishlist/pkg/response"
+)
+
+type ResearchHandler struct {
+	researchCalculator services.ResearchCalculatorInterface
+}
+
+func NewResearchHandler(researchCalculator services.ResearchCalculatorInterface) *ResearchHandler {
+	return &ResearchHandler{researchCalculator: researchCalculator}
+}
+
+// ListClanTiers lists the clan tiers and their research cost multipliers.
+func (h *ResearchHandler) ListClanTiers(w http.ResponseWriter, r *http.Request) {
+	logger.Debug(r.Context(), "handler: ListClanTiers called")
+	response.JSON(w, http.StatusOK, dto.NewClanTiers(models.ClanTiers))
+}
+
+// CalculateResearchCost totals the research costs of the requested items
+// for a clan tier. It reads only item data, so it needs no user.
+func (h *ResearchHandler) CalculateResearchCost(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: CalculateResearchCost called")
+
+	var req dto.ResearchCostRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn(ctx, "handler: CalculateResearchCost - invalid request body", "error", err)
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	cost, err := h.researchCalculator.Calculate(ctx, req.ToModel())
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidResearchRequest) {
+			logger.Warn(ctx, "handler: CalculateResearchCost - invalid request", "error", err)
+			rejected(w, http.StatusBadRequest, err)
+			return
+		}
+		logger.Error(ctx, "handler: CalculateResearchCost - failed to calculate research cost", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to calculate research cost")
+		return
+	}
+
+	logger.Info(ctx, "handler: CalculateResearchCost - success", "tier", cost.Tier, "labCount", len(cost.Labs))
+	response.JSON(w, http.StatusOK, dto.NewResearchCost(cost))
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+)
+
+func TestResearchHandler_ListClanTiers(t *testing.T) {
+	handler := NewResearchHandler(&mocks.MockResearchCalculator{})
+
+	rec := httptest.NewRecorder()
+	handler.ListClanTiers(rec, httptest.NewRequest(http.MethodGet, "/api/v1/dojo/clan-tiers", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	var tiers []map[string]interface{}
+	if err := json.NewDecoder(rec.Body).Decode(&tiers); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(tiers) != len(models.ClanTiers) || tiers[0]["name"] != "ghost" || tiers[4]["name"] != "moon" || tiers[4]["multiplier"] != 100.0 {
+		t.Errorf("unexpected tiers %v", tiers)
+	}
+}
+
+func TestResearchHandler_CalculateResearchCost(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "success", body: `{"tier":"moon","items":[{"uniqueName":"/Lotus/Ogris","lab":"Chem Lab"}]}`, expectedStatus: http.StatusOK},
+		{name: "invalid body", body: `{"items":`, expectedStatus: http.StatusBadRequest},
+		{name: "invalid request", body: `{"tier":"galaxy"}`, mockError: fmt.Errorf("%w: unknown clan tier", services.ErrInvalidResearchRequest), expectedStatus: http.StatusBadRequest},
+		{name: "service error", body: `{"items":[{"uniqueName":"/Lotus/Ogris"}]}`, mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewResearchHandler(&mocks.MockResearchCalculator{
+				CalculateFunc: func(ctx context.Context, req models.ResearchCostRequest) (*models.ResearchCost, error) {
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					if req.Tier != "moon" || len(req.Items) != 1 || req.Items[0].Lab != "Chem Lab" {
+						t.Errorf("unexpected request %+v", req)
+					}
+					item := models.ResearchItemCost{UniqueName: "/Lotus/Ogris", Name: "Ogris", Lab: "Chem Lab", Credits: 2500000,
+						Materials: []models.ResearchMaterial{{UniqueName: "/Lotus/Nitain", Name: "Nitain Extract", Count: 100}}}
+					return &models.ResearchCost{
+						Tier: "moon", Multiplier: 100, Credits: 2500000,
+						Labs:      []models.ResearchLabCost{{Lab: "Chem Lab", Items: []models.ResearchItemCost{item}, Credits: 2500000, Materials: item.Materials}},
+						Materials: item.Materials,
+						NotFound:  []string{},
+					}, nil
+				},
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/dojo/research-cost", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			handler.CalculateResearchCost(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var body map[string]interface{}
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if body["tier"] != "moon" || body["multiplier"] != 100.0 || body["credits"] != 2500000.0 {
+				t.Errorf("unexpected totals %v", body)
+			}
+			lab := body["labs"].([]interface{})[0].(map[string]interface{})
+			item := lab["items"].([]interface{})[0].(map[string]interface{})
+			if lab["lab"] != "Chem Lab" || item["name"] != "Ogris" || item["materials"].([]interface{})[0].(map[string]interface{})["count"] != 100.0 {
+				t.Errorf("unexpected lab %v", lab)
+			}
+		})
+	}
+}
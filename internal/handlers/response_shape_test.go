@@ -0,0 +1,520 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/graytonio/warframe-wishlist/internal/buildinfo"
+	"github.com/graytonio/warframe-wishlist/internal/middleware"
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+)
+
+// newShapeRouter routes every JSON endpoint to services that return sparse
+// data: zero values, nil slices, and unset optional fields, which is what
+// omitempty used to drop from responses.
+func newShapeRouter() http.Handler {
+	itemHandler := NewItemHandler(&mockItemService{
+		searchFunc: func(ctx context.Context, params models.SearchParams) (*models.ItemSearchPage, error) {
+			if params.Query == "" {
+				return &models.ItemSearchPage{}, nil
+			}
+			return &models.ItemSearchPage{Items: []models.ItemSearchResult{{UniqueName: "/Lotus/Ferrite", Name: "Ferrite"}}, Total: 1}, nil
+		},
+		getByUniqueNameFunc: func(ctx context.Context, uniqueName string) (*models.Item, error) {
+			return &models.Item{
+				UniqueName: uniqueName,
+				Name:       "Ash",
+				Components: []models.Component{{UniqueName: "/Lotus/AshChassis", Name: "Chassis"}},
+			}, nil
+		},
+	})
+	itemChangesHandler := NewItemChangesHandler(&mocks.MockItemChangeService{
+		ListChangesFunc: func(ctx context.Context, since time.Time, limit int) (*models.ItemChangesResponse, error) {
+			return &models.ItemChangesResponse{
+				Changes: []models.ItemChange{{UniqueName: "/Lotus/Ash", Name: "Ash"}},
+				Count:   1,
+			}, nil
+		},
+	})
+	wishlistHandler := NewWishlistHandler(&mockWishlistService{
+		getWishlistFunc: func(ctx context.Context, userID string) (*models.Wishlist, error) {
+			return &models.Wishlist{UserID: userID, Items: []models.WishlistItem{{UniqueName: "/Lotus/Ash", Quantity: 1}}}, nil
+		},
+		getExpandedWishlistFunc: func(ctx context.Context, userID string) (*models.ExpandedWishlist, error) {
+			return &models.ExpandedWishlist{UserID: userID, Items: []models.ExpandedWishlistItem{{WishlistItem: models.WishlistItem{UniqueName: "/Lotus/Gone", Quantity: 1}}}}, nil
+		},
+		addItemFunc: func(ctx context.Context, userID string, req models.AddItemRequest) error {
+			if req.Quantity < 50 {
+				return nil
+			}
+			return &services.ApprovalRequiredError{Change: &models.PendingChange{MemberID: userID, UniqueName: req.UniqueName, Quantity: 50, Status: models.ChangeStatusPending}}
+		},
+	}, &mockMaterialResolver{
+		getMaterialsFunc: func(ctx context.Context, userID string) (*models.MaterialsResponse, error) {
+			return &models.MaterialsResponse{Materials: []models.MaterialRequirement{{UniqueName: "/Lotus/Ferrite", Name: "Ferrite", TotalCount: 100}}}, nil
+		},
+	})
+	wishlistHandler.SetQuantitySuggester(&mocks.MockQuantitySuggester{})
+	ownedBPHandler := NewOwnedBlueprintsHandler(&mockOwnedBlueprintsService{
+		getOwnedBlueprintsFunc: func(ctx context.Context, userID string) (*models.OwnedBlueprints, error) {
+			return &models.OwnedBlueprints{UserID: userID}, nil
+		},
+	})
+	ownedMatHandler := NewOwnedMaterialsHandler(&mockOwnedMaterialsService{})
+	importHandler := NewWishlistImportHandler(&mockWishlistImportService{
+		previewTextFunc: func(ctx context.Context, text string) (*models.ImportPreview, error) {
+			return &models.ImportPreview{
+				Ambiguous: []models.ImportAmbiguous{{Line: 1, Input: "Forma", Quantity: 1, Candidates: []models.ImportCandidate{{UniqueName: "/Lotus/Forma", Name: "Forma"}}}},
+			}, nil
+		},
+		confirmFunc: func(ctx context.Context, userID string, req models.ImportConfirmRequest) (*models.ImportConfirmResult, error) {
+			return &models.ImportConfirmResult{Results: []models.ImportItemResult{{UniqueName: "/Lotus/Forma", Quantity: 1, Status: models.ImportStatusAdded}}}, nil
+		},
+	})
+	settingsHandler := NewSettingsHandler(&mockSettingsService{
+		getSettingsFunc: func(ctx context.Context, userID string) (*models.UserSettings, error) {
+			return &models.UserSettings{UserID: userID, TimeZone: models.DefaultTimeZone}, nil
+		},
+	})
+	publicWishlistHandler := NewPublicWishlistHandler(&mockPublicWishlistService{
+		listFunc: func(ctx context.Context) ([]models.PublicWishlist, error) {
+			return []models.PublicWishlist{{ID: "primes", Name: "Primes"}}, nil
+		},
+		getFunc: func(ctx context.Context, id string) (*models.PublicWishlist, error) {
+			return &models.PublicWishlist{ID: id, Name: "Primes"}, nil
+		},
+		getMaterialsFunc: func(ctx context.Context, id string) (*models.MaterialsResponse, error) {
+			return &models.MaterialsResponse{}, nil
+		},
+	})
+	householdHandler := NewHouseholdHandler(&mockHouseholdService{
+		getHouseholdFunc: func(ctx context.Context, userID string) (*models.Household, error) {
+			return &models.Household{}, nil
+		},
+		listApprovalsFunc: func(ctx context.Context, userID string) (*models.HouseholdApprovals, error) {
+			return &models.HouseholdApprovals{}, nil
+		},
+		approveFunc: func(ctx context.Context, managerID, changeID string) (*models.PendingChange, error) {
+			return &models.PendingChange{ManagerID: managerID, Status: models.ChangeStatusApproved}, nil
+		},
+	})
+	userTraceHandler := NewUserTraceHandler(&mockUserTraceService{})
+	shareLinkHandler := NewShareLinkHandler(&mocks.MockShareLinkService{
+		GetViewFunc: func(ctx context.Context, token string) (*models.ShareLinkView, error) {
+			return &models.ShareLinkView{
+				Permissions: models.ShareLinkPermissions{HideQuantities: true, HideLinks: true, HideMaterials: true},
+				Items:       []models.ShareLinkViewItem{{UniqueName: "/Lotus/Forma"}},
+			}, nil
+		},
+	})
+	giftClaimHandler := NewGiftClaimHandler(&mocks.MockGiftClaimService{
+		GetSharedWishlistFunc: func(ctx context.Context, viewerID, ownerID string) (*models.SharedWishlist, error) {
+			return &models.SharedWishlist{OwnerID: ownerID, Items: []models.SharedWishlistItem{{UniqueName: "/Lotus/Forma", Claimed: true}}}, nil
+		},
+	})
+	transferHandler := NewWishlistTransferHandler(&mocks.MockWishlistTransferService{
+		ExportFunc: func(ctx context.Context, userID string) (*models.WishlistExport, error) {
+			return &models.WishlistExport{
+				Format:  models.WishlistExportFormat,
+				Version: models.WishlistExportVersion,
+				Items:   []models.WishlistExportItem{{UniqueName: "/Lotus/Forma", Quantity: 1}},
+			}, nil
+		},
+		ImportFunc: func(ctx context.Context, userID string, req models.WishlistDocumentImportRequest) (*models.WishlistDocumentImportResult, error) {
+			return &models.WishlistDocumentImportResult{
+				Mode:   models.ImportModeMerge,
+				DryRun: true,
+				Items:  []models.ImportItemResult{{UniqueName: "/Lotus/Forma", Quantity: 1, Status: models.ImportStatusAdded}},
+			}, nil
+		},
+	})
+	autocompleteHandler := NewItemAutocompleteHandler(&mocks.MockItemAutocompleteService{
+		SuggestFunc: func(ctx context.Context, query string, limit int) ([]models.ItemSuggestion, error) {
+			return []models.ItemSuggestion{{UniqueName: "/Lotus/Ash", Name: "Ash"}}, nil
+		},
+	})
+	customItemHandler := NewCustomItemHandler(&mocks.MockCustomItemService{
+		ListFunc: func(ctx context.Context, userID string) ([]models.CustomItem, error) {
+			return []models.CustomItem{{UniqueName: "/Custom/abc", Name: "Dojo Garden", Components: []models.Component{{UniqueName: "/Lotus/Forma", Name: "Forma", ItemCount: 1}}}}, nil
+		},
+	})
+	workspaceHandler := NewWorkspaceHandler(&mocks.MockWorkspaceService{
+		GetFunc: func(ctx context.Context, userID, id string) (*models.Workspace, error) {
+			return &models.Workspace{
+				Name:    "Dojo research",
+				Members: []models.WorkspaceMember{{UserID: userID, Role: models.WorkspaceRoleAdmin}},
+				Items:   []models.WorkspaceItem{{UniqueName: "/Lotus/Reactor", Quantity: 2}},
+			}, nil
+		},
+	})
+
+	foundryHandler := NewFoundryHandler(&mocks.MockFoundryService{})
+	metaHandler := NewMetaHandler(buildinfo.Info{Version: "dev"}, nil)
+	farmingPlanHandler := NewFarmingPlanHandler(&mocks.MockFarmingPlanner{})
+	relicHandler := NewRelicHandler(&mocks.MockRelicResolver{})
+	opportunityHandler := NewOpportunityHandler(&mocks.MockOpportunityFinder{})
+	researchHandler := NewResearchHandler(&mocks.MockResearchCalculator{})
+	notificationHandler := NewNotificationHandler(&mocks.MockNotificationService{})
+	usageHandler := NewUsageHandler(&mocks.MockUsageService{})
+	profileImportHandler := NewProfileImportHandler(&mocks.MockProfileImportService{
+		ImportFunc: func(ctx context.Context, userID string, req models.ProfileImportRequest) (*models.ProfileImportResult, error) {
+			return &models.ProfileImportResult{Format: req.Format, Mode: models.ImportModeMerge, DryRun: req.DryRun}, nil
+		},
+	})
+	masteryHandler := NewMasteryHandler(&mocks.MockMasteryService{
+		GetProfileFunc: func(ctx context.Context, userID string) (*models.MasteryProfile, error) {
+			return &models.MasteryProfile{UserID: userID, Entries: []models.MasteryEntry{}}, nil
+		},
+		GetSummaryFunc: func(ctx context.Context, userID string) (*models.MasterySummary, error) {
+			return &models.MasterySummary{UserID: userID, Categories: []models.MasteryCategorySummary{}}, nil
+		},
+	})
+
+	r := chi.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), middleware.UserIDKey, "user-123")))
+		})
+	})
+	r.Get("/items/search", itemHandler.Search)
+	r.Get("/items/blueprints/reusable", itemHandler.SearchReusableBlueprints)
+	r.Get("/items/changes", itemChangesHandler.List)
+	r.Get("/items/autocomplete", autocompleteHandler.Autocomplete)
+	r.Get("/items/*", itemHandler.GetByUniqueName)
+	r.Get("/public-wishlists", publicWishlistHandler.List)
+	r.Get("/public-wishlists/{id}", publicWishlistHandler.Get)
+	r.Get("/public-wishlists/{id}/materials", publicWishlistHandler.GetMaterials)
+	r.Get("/wishlist", wishlistHandler.GetWishlist)
+	r.Post("/wishlist", wishlistHandler.AddItem)
+	r.Get("/wishlist/materials", wishlistHandler.GetMaterials)
+	r.Put("/wishlist/links/*", wishlistHandler.SetItemLinks)
+	r.Put("/wishlist/progress/*", wishlistHandler.SetItemProgress)
+	r.Post("/wishlist/import/text", importHandler.PreviewText)
+	r.Post("/wishlist/import/text/confirm", importHandler.ConfirmImport)
+	r.Get("/wishlist/export", transferHandler.Export)
+	r.Post("/wishlist/import", transferHandler.Import)
+	r.Get("/blueprints", ownedBPHandler.GetOwnedBlueprints)
+	r.Get("/components", ownedBPHandler.GetOwnedComponents)
+	r.Get("/materials", ownedMatHandler.GetOwnedMaterials)
+	r.Get("/profile/usage", usageHandler.GetUsage)
+	r.Get("/profile/mastery", masteryHandler.GetProfile)
+	r.Get("/profile/summary", masteryHandler.GetSummary)
+	r.Post("/profile/import", profileImportHandler.Import)
+	r.Get("/settings", settingsHandler.GetSettings)
+	r.Get("/household", householdHandler.GetHousehold)
+	r.Get("/household/approvals", householdHandler.ListApprovals)
+	r.Post("/household/approvals/{changeID}/approve", householdHandler.Approve)
+	r.Put("/internal/users/{userID}/trace", userTraceHandler.EnableTrace)
+	r.Post("/share-links", shareLinkHandler.CreateLink)
+	r.Get("/shared/{token}", shareLinkHandler.GetView)
+	r.Get("/users/{userID}/wishlist", giftClaimHandler.GetSharedWishlist)
+	r.Get("/custom-items", customItemHandler.List)
+	r.Get("/custom-items/search", customItemHandler.Search)
+	r.Post("/custom-items", customItemHandler.Create)
+	r.Get("/workspaces/{workspaceID}", workspaceHandler.Get)
+	r.Get("/workspaces/{workspaceID}/materials", workspaceHandler.GetMaterials)
+	r.Get("/workspaces/{workspaceID}/activity", workspaceHandler.GetActivity)
+	r.Post("/workspaces/{workspaceID}/material-contributions", workspaceHandler.LogMaterialContribution)
+	r.Post("/users/{userID}/wishlist/claims", giftClaimHandler.Claim)
+	r.Get("/profile/foundry", foundryHandler.GetFoundry)
+	r.Post("/profile/foundry", foundryHandler.StartBuild)
+	r.Get("/meta/version", metaHandler.Version)
+	r.Get("/wishlist/farming-plan", farmingPlanHandler.GetFarmingPlan)
+	r.Get("/wishlist/relics", relicHandler.GetRelicRequirements)
+	r.Get("/wishlist/opportunities", opportunityHandler.GetOpportunities)
+	r.Post("/dojo/research-cost", researchHandler.CalculateResearchCost)
+	r.Get("/notifications/push-key", notificationHandler.GetPushKey)
+	r.Post("/notifications/channels", notificationHandler.CreateChannel)
+	return r
+}
+
+// emptyList marks a field that must be present as [].
+var emptyList = []interface{}{}
+
+func TestResponseShapes(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		target         string
+		body           string
+		expectedStatus int
+		// fields maps a dotted path (list indexes as numbers) to the value the
+		// field must have; the field must be present even when it is zero.
+		fields map[string]interface{}
+	}{
+		{
+			name: "item detail", method: http.MethodGet, target: "/items/Lotus/Ash", expectedStatus: http.StatusOK,
+			fields: map[string]interface{}{
+				"consumeOnBuild": false, "tradable": false, "isPrime": false, "archived": false,
+				"masteryReq": 0.0, "buildPrice": 0.0, "buildTime": 0.0, "skipBuildTimePrice": 0.0, "buildQuantity": 0.0,
+				"description": "", "imageName": "", "wikiaUrl": "",
+				"drops": emptyList, "degraded": emptyList,
+				"archivedAt": nil, "buildDuration": nil, "buildCost": nil, "rushCost": nil,
+				"components.0.itemCount": 0.0, "components.0.hasOwnPage": false, "components.0.tradable": false,
+				"components.0.drops": emptyList, "components.0.components": emptyList,
+			},
+		},
+		{
+			name: "empty search", method: http.MethodGet, target: "/items/search", expectedStatus: http.StatusOK,
+			fields: map[string]interface{}{"items": emptyList, "count": 0.0},
+		},
+		{
+			name: "search results", method: http.MethodGet, target: "/items/search?q=ferrite", expectedStatus: http.StatusOK,
+			fields: map[string]interface{}{"items.0.description": "", "items.0.imageName": "", "items.0.archived": false},
+		},
+		{
+			name: "empty reusable blueprints", method: http.MethodGet, target: "/items/blueprints/reusable", expectedStatus: http.StatusOK,
+			fields: map[string]interface{}{"items": emptyList, "count": 0.0},
+		},
+		{
+			name: "item changes", method: http.MethodGet, target: "/items/changes", expectedStatus: http.StatusOK,
+			fields: map[string]interface{}{"changes.0.kinds": emptyList, "changes.0.dataVersion": ""},
+		},
+		{
+			name: "autocomplete", method: http.MethodGet, target: "/items/autocomplete?q=a", expectedStatus: http.StatusOK,
+			fields: map[string]interface{}{"count": 1.0, "suggestions.0.category": "", "suggestions.0.imageName": ""},
+		},
+		{
+			name: "wishlist", method: http.MethodGet, target: "/wishlist", expectedStatus: http.StatusOK,
+			fields: map[string]interface{}{"items.0.links": emptyList, "items.0.quantity": 1.0, "items.0.progress": emptyList, "items.0.completion": 0.0},
+		},
+		{
+			name: "expanded wishlist", method: http.MethodGet, target: "/wishlist?expand=items", expectedStatus: http.StatusOK,
+			fields: map[string]interface{}{"items.0.item": nil, "items.0.links": emptyList},
+		},
+		{
+			name: "cleared item links", method: http.MethodPut, target: "/wishlist/links/Lotus/Ash", body: `{"links":[]}`, expectedStatus: http.StatusOK,
+			fields: map[string]interface{}{"uniqueName": "/Lotus/Ash", "links": emptyList},
+		},
+		{
+			name: "cleared item progress", method: http.MethodPut, target: "/wishlist/progress/Lotus/Ash", body: `{"components":[]}`, expectedStatus: http.StatusOK,
+			fields: map[string]interface{}{"uniqueName": "/Lotus/Ash", "progress": emptyList, "completion": 0.0},
+		},
+		{
+			name: "import preview", method: http.MethodPost, target: "/wishlist/import/text", body: `{"text":"Forma"}`, expectedStatus: http.StatusOK,
+			fields: map[string]interface{}{"matched": emptyList, "unmatched": emptyList, "ambiguous.0.candidates.0.imageName": ""},
+		},
+		{
+			name: "import confirm", method: http.MethodPost, target: "/wishlist/import/text/confirm", body: `{"items":[{"uniqueName":"/Lotus/Forma"}]}`, expectedStatus: http.StatusOK,
+			fields: map[string]interface{}{"results.0.status": "added", "results.0.pendingChange": nil},
+		},
+		{
+			name: "wishlist export", method: http.MethodGet, target: "/wishlist/export", expectedStatus: http.StatusOK,
+			fields: map[string]interface{}{"format": "warframe-wishlist", "ownedBlueprints": emptyList, "items.0.links": emptyList, "items.0.recipeId": ""},
+		},
+		{
+			name: "wishlist import", method: http.MethodPost, target: "/wishlist/import?dryRun=true", body: `{}`, expectedStatus: http.StatusOK,
+			fields: map[string]interface{}{"dryRun": true, "blueprints": emptyList, "items.0.pendingChange": nil},
+		},
+		{
+			name: "change held for approval", method: http.MethodPost, target: "/wishlist", body: `{"uniqueName":"/Lotus/Ash","quantity":50}`, expectedStatus: http.StatusAccepted,
+			fields: map[string]interface{}{"pendingChange.decidedAt": nil, "pendingChange.quantity": 50.0},
+		},
+		{
+			name: "added item without suggestion", method: http.MethodPost, target: "/wishlist", body: `{"uniqueName":"/Lotus/Ash","quantity":1}`, expectedStatus: http.StatusCreated,
+			fields: map[string]interface{}{"message": "item added to wishlist", "suggestion": nil},
+		},
+		{
+			name: "wishlist materials", method: http.MethodGet, target: "/wishlist/materials", expectedStatus: http.StatusOK,
+			fields: map[string]interface{}{"degraded": emptyList, "truncated": false, "truncatedReason": "", "totalCredits": 0.0, "rushPlatinum": 0.0, "rushCost.unit": "platinum", "materials.0.imageName": "", "materials.0.description": "", "materials.0.owned": 0.0, "materials.0.remaining": 0.0},
+		},
+		{
+			name: "owned blueprints", method: http.MethodGet, target: "/blueprints", expectedStatus: http.StatusOK,
+			fields: map[string]interface{}{"blueprints": emptyList, "components": emptyList},
+		},
+		{
+			name: "owned components", method: http.MethodGet, target: "/components", expectedStatus: http.StatusOK,
+			fields: map[string]interface{}{"components": emptyList, "userId": "user-123"},
+		},
+		{
+			name: "usage", method: http.MethodGet, target: "/profile/usage", expectedStatus: http.StatusOK,
+			fields: map[string]interface{}{"resources": emptyList, "userId": "user-123"},
+		},
+		{
+			name: "mastery profile", method: http.MethodGet, target: "/profile/mastery", expectedStatus: http.StatusOK,
+			fields: map[string]interface{}{"entries": emptyList, "userId": "user-123"},
+		},
+		{
+			name: "profile summary", method: http.MethodGet, target: "/profile/summary", expectedStatus: http.StatusOK,
+			fields: map[string]interface{}{"buildable": 0.0, "built": 0.0, "mastered": 0.0, "masteredPercent": 0.0, "categories": emptyList},
+		},
+		{
+			name: "profile import", method: http.MethodPost, target: "/profile/import?dryRun=true", body: `{"MiscItems":[]}`, expectedStatus: http.StatusOK,
+			fields: map[string]interface{}{"format": "game", "dryRun": true, "components.added": 0.0, "materials.removed": 0.0, "skipped.unused": 0.0},
+		},
+		{
+			name: "owned materials", method: http.MethodGet, target: "/materials", expectedStatus: http.StatusOK,
+			fields: map[string]interface{}{"materials": emptyList, "userId": "user-123"},
+		},
+		{
+			name: "settings", method: http.MethodGet, target: "/settings", expectedStatus: http.StatusOK,
+			fields: map[string]interface{}{"timeZone": models.DefaultTimeZone, "userId": "user-123"},
+		},
+		{
+			name: "public wishlists", method: http.MethodGet, target: "/public-wishlists", expectedStatus: http.StatusOK,
+			fields: map[string]interface{}{"0.description": "", "0.items": emptyList},
+		},
+		{
+			name: "public wishlist", method: http.MethodGet, target: "/public-wishlists/primes", expectedStatus: http.StatusOK,
+			fields: map[string]interface{}{"description": "", "items": emptyList},
+		},
+		{
+			name: "public wishlist materials", method: http.MethodGet, target: "/public-wishlists/primes/materials", expectedStatus: http.StatusOK,
+			fields: map[string]interface{}{"materials": emptyList, "degraded": emptyList},
+		},
+		{
+			name: "household", method: http.MethodGet, target: "/household", expectedStatus: http.StatusOK,
+			fields: map[string]interface{}{"manager": nil, "members": emptyList},
+		},
+		{
+			name: "household approvals", method: http.MethodGet, target: "/household/approvals", expectedStatus: http.StatusOK,
+			fields: map[string]interface{}{"toReview": emptyList, "requested": emptyList},
+		},
+		{
+			name: "approved change", method: http.MethodPost, target: "/household/approvals/abc/approve", expectedStatus: http.StatusOK,
+			fields: map[string]interface{}{"decidedAt": nil, "status": models.ChangeStatusApproved},
+		},
+		{
+			name: "user trace", method: http.MethodPut, target: "/internal/users/user-123/trace", expectedStatus: http.StatusOK,
+			fields: map[string]interface{}{"userId": "user-123", "reason": ""},
+		},
+		{
+			name: "share link", method: http.MethodPost, target: "/share-links", body: `{}`, expectedStatus: http.StatusCreated,
+			fields: map[string]interface{}{"label": "", "permissions.hideQuantities": false, "permissions.hideLinks": false, "permissions.hideMaterials": false},
+		},
+		{
+			name: "share link view", method: http.MethodGet, target: "/shared/token", expectedStatus: http.StatusOK,
+			fields: map[string]interface{}{"permissions.hideQuantities": true, "items.0.quantity": nil, "items.0.links": nil, "updatedAt": nil},
+		},
+		{
+			name: "shared wishlist", method: http.MethodGet, target: "/users/owner/wishlist", expectedStatus: http.StatusOK,
+			fields: map[string]interface{}{"ownerId": "owner", "items.0.claimed": true, "items.0.claim": nil},
+		},
+		{
+			name: "gift claim", method: http.MethodPost, target: "/users/owner/wishlist/claims", body: `{"uniqueName":"/Lotus/Forma"}`, expectedStatus: http.StatusCreated,
+			fields: map[string]interface{}{"claimerId": "user-123", "anonymous": false},
+		},
+		{
+			name: "custom items", method: http.MethodGet, target: "/custom-items", expectedStatus: http.StatusOK,
+			fields: map[string]interface{}{"items.0.category": models.CustomItemCategory, "items.0.description": "", "items.0.buildPrice": 0.0, "items.0.components.0.imageName": ""},
+		},
+		{
+			name: "empty custom item search", method: http.MethodGet, target: "/custom-items/search", expectedStatus: http.StatusOK,
+			fields: map[string]interface{}{"items": emptyList, "count": 0.0},
+		},
+		{
+			name: "created custom item", method: http.MethodPost, target: "/custom-items", body: `{"name":"Dojo Garden"}`, expectedStatus: http.StatusCreated,
+			fields: map[string]interface{}{"components": emptyList, "description": "", "category": models.CustomItemCategory},
+		},
+		{
+			name: "workspace", method: http.MethodGet, target: "/workspaces/abc", expectedStatus: http.StatusOK,
+			fields: map[string]interface{}{"description": "", "items.0.contributions": emptyList, "items.0.contributed": 0.0, "items.0.remaining": 2.0, "items.0.addedBy": ""},
+		},
+		{
+			name: "empty workspace materials", method: http.MethodGet, target: "/workspaces/abc/materials", expectedStatus: http.StatusOK,
+			fields: map[string]interface{}{"materials": emptyList, "members": emptyList, "truncatedReason": "", "degraded": emptyList},
+		},
+		{
+			name: "empty foundry", method: http.MethodGet, target: "/profile/foundry", expectedStatus: http.StatusOK,
+			fields: map[string]interface{}{"builds": emptyList, "finished": 0.0},
+		},
+		{
+			name: "started foundry build", method: http.MethodPost, target: "/profile/foundry", body: `{"uniqueName":"/Lotus/Forma"}`, expectedStatus: http.StatusCreated,
+			fields: map[string]interface{}{"name": "", "buildTime.seconds": 0.0, "remaining.iso8601": "PT0S", "finished": false},
+		},
+		{
+			name: "push key not configured", method: http.MethodGet, target: "/notifications/push-key", expectedStatus: http.StatusOK,
+			fields: map[string]interface{}{"publicKey": "", "enabled": false},
+		},
+		{
+			name: "created notification channel", method: http.MethodPost, target: "/notifications/channels", body: `{"type":"webhook","url":"https://example.com/hook"}`, expectedStatus: http.StatusCreated,
+			fields: map[string]interface{}{"type": "webhook", "events": emptyList, "secret": ""},
+		},
+		{
+			name: "empty farming plan", method: http.MethodGet, target: "/wishlist/farming-plan", expectedStatus: http.StatusOK,
+			fields: map[string]interface{}{"locations": emptyList, "unlocated": emptyList, "truncated": false, "truncatedReason": "", "degraded": emptyList},
+		},
+		{
+			name: "no relics needed", method: http.MethodGet, target: "/wishlist/relics", expectedStatus: http.StatusOK,
+			fields: map[string]interface{}{"relics": emptyList, "unavailable": emptyList},
+		},
+		{
+			name: "world state not fetched", method: http.MethodGet, target: "/wishlist/opportunities", expectedStatus: http.StatusOK,
+			fields: map[string]interface{}{"opportunities": emptyList, "fetchedAt": nil, "truncated": false, "truncatedReason": "", "degraded": emptyList},
+		},
+		{
+			name: "empty research cost", method: http.MethodPost, target: "/dojo/research-cost", body: `{"items":[{"uniqueName":"/Lotus/Gone"}]}`, expectedStatus: http.StatusOK,
+			fields: map[string]interface{}{"tier": models.DefaultClanTier, "multiplier": 1.0, "labs": emptyList, "materials": emptyList, "notFound": emptyList, "credits": 0.0},
+		},
+		{
+			name: "unstamped build version", method: http.MethodGet, target: "/meta/version", expectedStatus: http.StatusOK,
+			fields: map[string]interface{}{"version": "dev", "commit": "", "buildDate": nil, "modified": false, "features": map[string]interface{}{}},
+		},
+		{
+			name: "logged workspace material contribution", method: http.MethodPost, target: "/workspaces/abc/material-contributions", body: `{"uniqueName":"/Lotus/Alloy","quantity":5}`, expectedStatus: http.StatusCreated,
+			fields: map[string]interface{}{"kind": models.ContributionKindMaterial, "quantity": 5.0, "uniqueName": "/Lotus/Alloy"},
+		},
+	}
+
+	router := newShapeRouter()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			rr := httptest.NewRecorder()
+
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rr.Code, rr.Body.String())
+			}
+			var body interface{}
+			if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			for path, expected := range tt.fields {
+				got, ok := lookupField(body, path)
+				if !ok {
+					t.Errorf("expected field %q to be present in %s", path, rr.Body.String())
+					continue
+				}
+				if !reflect.DeepEqual(got, expected) {
+					t.Errorf("field %q: expected %#v, got %#v", path, expected, got)
+				}
+			}
+		})
+	}
+}
+
+// lookupField follows a dotted path through decoded JSON, reporting whether
+// the final field is present (a null field is present).
+func lookupField(value interface{}, path string) (interface{}, bool) {
+	for _, part := range strings.Split(path, ".") {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			next, ok := v[part]
+			if !ok {
+				return nil, false
+			}
+			value = next
+		case []interface{}:
+			i, err := strconv.Atoi(part)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			value = v[i]
+		default:
+			return nil, false
+		}
+	}
+	return value, true
+}
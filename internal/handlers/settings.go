@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+	"github.com/graytonio/warframe-wishlist/internal/middleware"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"github.com/graytonio/warframe-wishlist/pkg/response"
+)
+
+type SettingsHandler struct {
+	settingsService services.SettingsServiceInterface
+}
+
+func NewSettingsHandler(settingsService services.SettingsServiceInterface) *SettingsHandler {
+	return &SettingsHandler{settingsService: settingsService}
+}
+
+func (h *SettingsHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: GetSettings called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: GetSettings - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	settings, err := h.settingsService.GetSettings(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "handler: GetSettings - failed to get settings", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to get settings")
+		return
+	}
+
+	logger.Info(ctx, "handler: GetSettings - success")
+	response.JSON(w, http.StatusOK, dto.NewUserSettings(settings))
+}
+
+func (h *SettingsHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: UpdateSettings called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: UpdateSettings - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	var req dto.UpdateSettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn(ctx, "handler: UpdateSettings - invalid request body", "error", err)
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	settings, err := h.settingsService.UpdateSettings(ctx, userID, req.ToModel())
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidTimeZone) {
+			logger.Warn(ctx, "handler: UpdateSettings - invalid time zone")
+			response.Error(w, http.StatusBadRequest, "invalid time zone")
+			return
+		}
+		if errors.Is(err, services.ErrInvalidDefaultQuantities) || errors.Is(err, services.ErrInvalidMilestones) || errors.Is(err, services.ErrInvalidMaterialsRounding) || errors.Is(err, services.ErrInvalidDigestTime) {
+			logger.Warn(ctx, "handler: UpdateSettings - invalid settings", "error", err)
+			rejected(w, http.StatusBadRequest, err)
+			return
+		}
+		logger.Error(ctx, "handler: UpdateSettings - failed to update settings", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to update settings")
+		return
+	}
+
+	logger.Info(ctx, "handler: UpdateSettings - success")
+	response.JSON(w, http.StatusOK, dto.NewUserSettings(settings))
+}
@@ -0,0 +1,244 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+)
+
+type mockSettingsService struct {
+	getSettingsFunc    func(ctx context.Context, userID string) (*models.UserSettings, error)
+	updateSettingsFunc func(ctx context.Context, userID string, req models.UpdateSettingsRequest) (*models.UserSettings, error)
+}
+
+func (m *mockSettingsService) GetSettings(ctx context.Context, userID string) (*models.UserSettings, error) {
+	if m.getSettingsFunc != nil {
+		return m.getSettingsFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *mockSettingsService) UpdateSettings(ctx context.Context, userID string, req models.UpdateSettingsRequest) (*models.UserSettings, error) {
+	if m.updateSettingsFunc != nil {
+		return m.updateSettingsFunc(ctx, userID, req)
+	}
+	return nil, nil
+}
+
+func TestSettingsHandler_GetSettings(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		mockReturn     *models.UserSettings
+		mockError      error
+		expectedStatus int
+	}{
+		{
+			name:           "successful get settings",
+			userID:         "user-123",
+			mockReturn:     &models.UserSettings{UserID: "user-123", TimeZone: "Europe/Berlin"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "unauthorized - no user ID",
+			userID:         "",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "service error",
+			userID:         "user-123",
+			mockError:      errors.New("database error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mockSettingsService{
+				getSettingsFunc: func(ctx context.Context, userID string) (*models.UserSettings, error) {
+					return tt.mockReturn, tt.mockError
+				},
+			}
+
+			handler := NewSettingsHandler(mockService)
+
+			req := createAuthenticatedRequest(http.MethodGet, "/api/v1/profile/settings", nil, tt.userID)
+			rec := httptest.NewRecorder()
+
+			handler.GetSettings(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+
+			if tt.expectedStatus == http.StatusOK {
+				var settings models.UserSettings
+				if err := json.NewDecoder(rec.Body).Decode(&settings); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if settings.TimeZone != tt.mockReturn.TimeZone {
+					t.Errorf("expected time zone '%s', got '%s'", tt.mockReturn.TimeZone, settings.TimeZone)
+				}
+			}
+		})
+	}
+}
+
+func TestSettingsHandler_UpdateSettings(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		body           string
+		mockError      error
+		expectedStatus int
+	}{
+		{
+			name:           "successful update",
+			userID:         "user-123",
+			body:           `{"timeZone":"Asia/Tokyo"}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "unauthorized - no user ID",
+			userID:         "",
+			body:           `{"timeZone":"Asia/Tokyo"}`,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "invalid JSON",
+			userID:         "user-123",
+			body:           "invalid json",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid time zone",
+			userID:         "user-123",
+			body:           `{"timeZone":"Nowhere/Special"}`,
+			mockError:      services.ErrInvalidTimeZone,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid default quantities",
+			userID:         "user-123",
+			body:           `{"timeZone":"UTC","defaultQuantities":[{"category":"Gear","quantity":0}]}`,
+			mockError:      services.ErrInvalidDefaultQuantities,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "service error",
+			userID:         "user-123",
+			body:           `{"timeZone":"UTC"}`,
+			mockError:      errors.New("database error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mockSettingsService{
+				updateSettingsFunc: func(ctx context.Context, userID string, req models.UpdateSettingsRequest) (*models.UserSettings, error) {
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return &models.UserSettings{UserID: userID, TimeZone: *req.TimeZone}, nil
+				},
+			}
+
+			handler := NewSettingsHandler(mockService)
+
+			req := createAuthenticatedRequest(http.MethodPatch, "/api/v1/profile/settings", []byte(tt.body), tt.userID)
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+
+			handler.UpdateSettings(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+		})
+	}
+}
+
+func TestSettingsHandler_UpdateSettings_DefaultQuantities(t *testing.T) {
+	var captured models.UpdateSettingsRequest
+	mockService := &mockSettingsService{
+		updateSettingsFunc: func(ctx context.Context, userID string, req models.UpdateSettingsRequest) (*models.UserSettings, error) {
+			captured = req
+			return &models.UserSettings{UserID: userID, DefaultQuantities: *req.DefaultQuantities}, nil
+		},
+	}
+	handler := NewSettingsHandler(mockService)
+
+	body := `{"defaultQuantities":[{"category":"Gear","type":"Specter","quantity":3}]}`
+	req := createAuthenticatedRequest(http.MethodPatch, "/api/v1/profile/settings", []byte(body), "user-123")
+	rec := httptest.NewRecorder()
+
+	handler.UpdateSettings(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	if captured.TimeZone != nil || captured.DefaultQuantities == nil || len(*captured.DefaultQuantities) != 1 {
+		t.Fatalf("expected only default quantities to be updated, got %+v", captured)
+	}
+
+	var response struct {
+		DefaultQuantities []struct {
+			Category string `json:"category"`
+			Type     string `json:"type"`
+			Quantity int    `json:"quantity"`
+		} `json:"defaultQuantities"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(response.DefaultQuantities) != 1 || response.DefaultQuantities[0].Type != "Specter" || response.DefaultQuantities[0].Quantity != 3 {
+		t.Errorf("unexpected default quantities %+v", response.DefaultQuantities)
+	}
+}
+
+func TestSettingsHandler_UpdateSettings_MaterialsRounding(t *testing.T) {
+	mockService := &mockSettingsService{
+		updateSettingsFunc: func(ctx context.Context, userID string, req models.UpdateSettingsRequest) (*models.UserSettings, error) {
+			if req.MaterialsRounding == nil || *req.MaterialsRounding != models.MaterialsRoundingPooled {
+				return nil, fmt.Errorf("%w: must be one of strict, pooled", services.ErrInvalidMaterialsRounding)
+			}
+			return &models.UserSettings{UserID: userID, MaterialsRounding: *req.MaterialsRounding}, nil
+		},
+	}
+	handler := NewSettingsHandler(mockService)
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{name: "pooled", body: `{"materialsRounding":"pooled"}`, expectedStatus: http.StatusOK, expectedBody: `"materialsRounding":"pooled"`},
+		{name: "unknown mode", body: `{"materialsRounding":"nearest"}`, expectedStatus: http.StatusBadRequest, expectedBody: `"code":"invalid_materials_rounding"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := createAuthenticatedRequest(http.MethodPatch, "/api/v1/profile/settings", []byte(tt.body), "user-123")
+			rec := httptest.NewRecorder()
+
+			handler.UpdateSettings(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if !strings.Contains(rec.Body.String(), tt.expectedBody) {
+				t.Errorf("expected body to contain %s, got %s", tt.expectedBody, rec.Body.String())
+			}
+		})
+	}
+}
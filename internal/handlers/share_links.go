@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+	"github.com/graytonio/warframe-wishlist/internal/middleware"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"github.com/graytonio/warframe-wishlist/pkg/response"
+)
+
+type ShareLinkHandler struct {
+	shareLinkService services.ShareLinkServiceInterface
+}
+
+func NewShareLinkHandler(shareLinkService services.ShareLinkServiceInterface) *ShareLinkHandler {
+	return &ShareLinkHandler{shareLinkService: shareLinkService}
+}
+
+func (h *ShareLinkHandler) CreateLink(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: CreateShareLink called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: CreateShareLink - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	var req dto.CreateShareLinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn(ctx, "handler: CreateShareLink - invalid request body", "error", err)
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	link, err := h.shareLinkService.CreateLink(ctx, userID, req.ToModel())
+	if err != nil {
+		writeShareLinkError(w, r, "CreateShareLink", err, "failed to create share link")
+		return
+	}
+
+	logger.Info(ctx, "handler: CreateShareLink - success", "id", link.ID.Hex())
+	response.JSON(w, http.StatusCreated, dto.NewShareLink(link))
+}
+
+func (h *ShareLinkHandler) ListLinks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: ListShareLinks called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: ListShareLinks - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	links, err := h.shareLinkService.ListLinks(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "handler: ListShareLinks - failed to list share links", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to list share links")
+		return
+	}
+
+	logger.Info(ctx, "handler: ListShareLinks - success", "count", len(links))
+	response.JSON(w, http.StatusOK, dto.NewShareLinks(links))
+}
+
+func (h *ShareLinkHandler) DeleteLink(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: DeleteShareLink called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: DeleteShareLink - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	linkID := chi.URLParam(r, "linkID")
+	if err := h.shareLinkService.DeleteLink(ctx, userID, linkID); err != nil {
+		writeShareLinkError(w, r, "DeleteShareLink", err, "failed to delete share link")
+		return
+	}
+
+	logger.Info(ctx, "handler: DeleteShareLink - success", "linkID", linkID)
+	response.JSON(w, http.StatusOK, map[string]string{
+		"message": "share link deleted",
+	})
+}
+
+// GetView serves the wishlist behind a share link to anyone holding it.
+func (h *ShareLinkHandler) GetView(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: GetSharedView called")
+
+	view, err := h.shareLinkService.GetView(ctx, chi.URLParam(r, "token"))
+	if err != nil {
+		writeShareLinkError(w, r, "GetSharedView", err, "failed to get shared wishlist")
+		return
+	}
+
+	logger.Info(ctx, "handler: GetSharedView - success", "itemCount", len(view.Items))
+	response.JSON(w, http.StatusOK, dto.NewShareLinkView(view))
+}
+
+func (h *ShareLinkHandler) GetMaterials(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: GetSharedMaterials called")
+
+	materials, err := h.shareLinkService.GetMaterials(ctx, chi.URLParam(r, "token"))
+	if err != nil {
+		writeShareLinkError(w, r, "GetSharedMaterials", err, "failed to get materials")
+		return
+	}
+
+	logger.Info(ctx, "handler: GetSharedMaterials - success", "materialCount", len(materials.Materials))
+	response.JSON(w, http.StatusOK, dto.NewMaterialsSummary(materials))
+}
+
+func writeShareLinkError(w http.ResponseWriter, r *http.Request, name string, err error, fallback string) {
+	ctx := r.Context()
+
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrInvalidShareLinkLabel):
+		status = http.StatusBadRequest
+	case errors.Is(err, services.ErrSharedMaterialsHidden):
+		status = http.StatusForbidden
+	case errors.Is(err, services.ErrShareLinkNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrTooManyShareLinks):
+		status = http.StatusConflict
+	}
+
+	if status == http.StatusInternalServerError {
+		logger.Error(ctx, "handler: "+name+" - "+fallback, "error", err)
+		response.Error(w, status, fallback)
+		return
+	}
+	logger.Warn(ctx, "handler: "+name+" - request rejected", "error", err)
+	rejected(w, status, err)
+}
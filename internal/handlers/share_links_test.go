@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/graytonio/warframe-wishlist/internal/middleware"
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+)
+
+// newShareLinkRouter mounts the share link routes as main does, injecting
+// userID in place of the auth middleware on the owner routes only.
+func newShareLinkRouter(service services.ShareLinkServiceInterface, userID string) http.Handler {
+	handler := NewShareLinkHandler(service)
+
+	r := chi.NewRouter()
+	r.Route("/share-links", func(r chi.Router) {
+		r.Use(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ctx := context.WithValue(r.Context(), middleware.UserIDKey, userID)
+				next.ServeHTTP(w, r.WithContext(ctx))
+			})
+		})
+		r.Get("/", handler.ListLinks)
+		r.Post("/", handler.CreateLink)
+		r.Delete("/{linkID}", handler.DeleteLink)
+	})
+	r.Get("/shared/{token}", handler.GetView)
+	r.Get("/shared/{token}/materials", handler.GetMaterials)
+	return r
+}
+
+func TestShareLinkHandler_Unauthorized(t *testing.T) {
+	router := newShareLinkRouter(&mocks.MockShareLinkService{}, "")
+
+	routes := []struct{ method, path string }{
+		{http.MethodGet, "/share-links"},
+		{http.MethodPost, "/share-links"},
+		{http.MethodDelete, "/share-links/abc"},
+	}
+
+	for _, route := range routes {
+		t.Run(route.method+" "+route.path, func(t *testing.T) {
+			req := httptest.NewRequest(route.method, route.path, bytes.NewReader([]byte("{}")))
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusUnauthorized {
+				t.Errorf("expected status %d, got %d", http.StatusUnauthorized, rec.Code)
+			}
+		})
+	}
+}
+
+func TestShareLinkHandler_CreateLink(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "success", body: `{"label":"clan","permissions":{"hideQuantities":true,"hideLinks":true}}`, expectedStatus: http.StatusCreated},
+		{name: "invalid body", body: `{`, expectedStatus: http.StatusBadRequest},
+		{name: "invalid label", body: `{"label":"clan"}`, mockError: services.ErrInvalidShareLinkLabel, expectedStatus: http.StatusBadRequest},
+		{name: "too many links", body: `{}`, mockError: services.ErrTooManyShareLinks, expectedStatus: http.StatusConflict},
+		{name: "service error", body: `{}`, mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotReq models.CreateShareLinkRequest
+			service := &mocks.MockShareLinkService{
+				CreateLinkFunc: func(ctx context.Context, userID string, req models.CreateShareLinkRequest) (*models.ShareLink, error) {
+					gotReq = req
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return &models.ShareLink{UserID: userID, Token: "token", Label: req.Label, Permissions: req.Permissions}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/share-links", bytes.NewReader([]byte(tt.body)))
+			rec := httptest.NewRecorder()
+			newShareLinkRouter(service, "user-123").ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			want := models.CreateShareLinkRequest{Label: "clan", Permissions: models.ShareLinkPermissions{HideQuantities: true, HideLinks: true}}
+			if tt.expectedStatus == http.StatusCreated && gotReq != want {
+				t.Errorf("unexpected request passed to service: %+v", gotReq)
+			}
+		})
+	}
+}
+
+func TestShareLinkHandler_DeleteLink(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "success", expectedStatus: http.StatusOK},
+		{name: "not found", mockError: services.ErrShareLinkNotFound, expectedStatus: http.StatusNotFound},
+		{name: "service error", mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotUser, gotID string
+			service := &mocks.MockShareLinkService{
+				DeleteLinkFunc: func(ctx context.Context, userID, linkID string) error {
+					gotUser, gotID = userID, linkID
+					return tt.mockError
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodDelete, "/share-links/abc", nil)
+			rec := httptest.NewRecorder()
+			newShareLinkRouter(service, "user-123").ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if gotUser != "user-123" || gotID != "abc" {
+				t.Errorf("unexpected arguments %q, %q", gotUser, gotID)
+			}
+		})
+	}
+}
+
+func TestShareLinkHandler_GetView(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "success", expectedStatus: http.StatusOK},
+		{name: "unknown token", mockError: services.ErrShareLinkNotFound, expectedStatus: http.StatusNotFound},
+		{name: "service error", mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotToken string
+			service := &mocks.MockShareLinkService{
+				GetViewFunc: func(ctx context.Context, token string) (*models.ShareLinkView, error) {
+					gotToken = token
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return &models.ShareLinkView{
+						Permissions: models.ShareLinkPermissions{HideQuantities: true, HideLinks: true, HideMaterials: true},
+						Items:       []models.ShareLinkViewItem{{UniqueName: "/Lotus/Forma"}},
+					}, nil
+				},
+			}
+
+			// Shared views are public: no user is authenticated.
+			req := httptest.NewRequest(http.MethodGet, "/shared/token-1", nil)
+			rec := httptest.NewRecorder()
+			newShareLinkRouter(service, "").ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if gotToken != "token-1" {
+				t.Errorf("expected token token-1, got %q", gotToken)
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var body struct {
+				Items []map[string]interface{} `json:"items"`
+			}
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			item := body.Items[0]
+			if quantity, ok := item["quantity"]; !ok || quantity != nil {
+				t.Errorf("expected quantity to be null, got %v", item["quantity"])
+			}
+			if links, ok := item["links"]; !ok || links != nil {
+				t.Errorf("expected links to be null, got %v", item["links"])
+			}
+		})
+	}
+}
+
+func TestShareLinkHandler_GetMaterials(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "success", expectedStatus: http.StatusOK},
+		{name: "materials hidden", mockError: services.ErrSharedMaterialsHidden, expectedStatus: http.StatusForbidden},
+		{name: "unknown token", mockError: services.ErrShareLinkNotFound, expectedStatus: http.StatusNotFound},
+		{name: "service error", mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.MockShareLinkService{
+				GetMaterialsFunc: func(ctx context.Context, token string) (*models.MaterialsResponse, error) {
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return &models.MaterialsResponse{Materials: []models.MaterialRequirement{}}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/shared/token-1/materials", nil)
+			rec := httptest.NewRecorder()
+			newShareLinkRouter(service, "").ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
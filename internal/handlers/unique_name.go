@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+// uniqueNameParam returns the canonical uniqueName captured by a wildcard route
+// (e.g. /items/*). Chi routes on the raw path when the URL contains escapes
+// such as %2F, in which case the captured value is still escaped and must be
+// decoded here; otherwise it has already been decoded by net/http.
+func uniqueNameParam(r *http.Request) (string, error) {
+	return uniqueNameParamWithSuffix(r, "")
+}
+
+// uniqueNameParamWithSuffix is uniqueNameParam for wildcard routes that end in
+// a fixed suffix after the uniqueName, such as /items/*/recipe-tree.
+func uniqueNameParamWithSuffix(r *http.Request, suffix string) (string, error) {
+	raw := strings.TrimSuffix(chi.URLParam(r, "*"), suffix)
+
+	if r.URL.RawPath != "" {
+		unescaped, err := url.PathUnescape(raw)
+		if err != nil {
+			return "", models.ErrInvalidUniqueName
+		}
+		raw = unescaped
+	}
+
+	return models.CanonicalUniqueName(raw)
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+// resolveUniqueNameParam routes target through a chi wildcard route, the same
+// way the items, wishlist and blueprint routes are mounted, and returns what
+// uniqueNameParam extracted.
+func resolveUniqueNameParam(t *testing.T, target string) (string, error) {
+	t.Helper()
+
+	var (
+		got    string
+		gotErr error
+		called bool
+	)
+	r := chi.NewRouter()
+	r.Route("/api/v1/items", func(r chi.Router) {
+		r.Get("/*", func(w http.ResponseWriter, r *http.Request) {
+			called = true
+			got, gotErr = uniqueNameParam(r)
+		})
+	})
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	if !called {
+		t.Fatalf("route not matched for %s", target)
+	}
+	return got, gotErr
+}
+
+func TestUniqueNameParam(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   string
+		expected string
+		err      error
+	}{
+		{name: "plain path", target: "/api/v1/items/Lotus/Powersuits/Excalibur", expected: "/Lotus/Powersuits/Excalibur"},
+		{name: "encoded slashes", target: "/api/v1/items/%2FLotus%2FPowersuits%2FExcalibur", expected: "/Lotus/Powersuits/Excalibur"},
+		{name: "double leading slash", target: "/api/v1/items//Lotus/A", expected: "/Lotus/A"},
+		{name: "encoded percent", target: "/api/v1/items/Lotus/100%25Done", expected: "/Lotus/100%Done"},
+		{name: "encoded unicode", target: "/api/v1/items/Lotus/%C3%9Cber", expected: "/Lotus/Über"},
+		{name: "dots in segment", target: "/api/v1/items/Lotus/Bow.v2", expected: "/Lotus/Bow.v2"},
+		{name: "empty", target: "/api/v1/items/", err: models.ErrUniqueNameRequired},
+		{name: "encoded parent segment", target: "/api/v1/items/Lotus/%2E%2E/A", err: models.ErrInvalidUniqueName},
+		{name: "encoded control character", target: "/api/v1/items/Lotus/A%00", err: models.ErrInvalidUniqueName},
+		{name: "encoded empty segment", target: "/api/v1/items/Lotus%2F%2FA", err: models.ErrInvalidUniqueName},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveUniqueNameParam(t, tt.target)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("expected error %v, got %v (result '%s')", tt.err, err, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, got)
+			}
+		})
+	}
+}
+
+// FuzzUniqueNameParam checks that however a client escapes a uniqueName in the
+// URL (fully escaped or escaped per segment), the handler sees the same value
+// CanonicalUniqueName produces for the unescaped name.
+func FuzzUniqueNameParam(f *testing.F) {
+	seeds := []string{
+		"/Lotus/Powersuits/Excalibur/Excalibur",
+		"Lotus/Types/Items/MiscItems/Ferrite",
+		"/Lotus/100%Done",
+		"/Lotus/Über/名前",
+		"/Lotus/../A",
+		"/Lotus/A?b=c#d",
+		"/Lotus/A B",
+		"",
+	}
+	for _, s := range seeds {
+		f.Add(s)
+	}
+
+	f.Fuzz(func(t *testing.T, name string) {
+		expected, expectedErr := models.CanonicalUniqueName(name)
+
+		segments := strings.Split(name, "/")
+		for i := range segments {
+			segments[i] = url.PathEscape(segments[i])
+		}
+
+		targets := []string{
+			"/api/v1/items/" + url.PathEscape(name),
+			"/api/v1/items/" + strings.Join(segments, "/"),
+		}
+		for _, target := range targets {
+			got, err := resolveUniqueNameParam(t, target)
+			if expectedErr != nil {
+				if !errors.Is(err, expectedErr) {
+					t.Fatalf("%s: expected error %v, got %v (result %q)", target, expectedErr, err, got)
+				}
+				continue
+			}
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", target, err)
+			}
+			if got != expected {
+				t.Fatalf("%s: expected %q, got %q", target, expected, got)
+			}
+		}
+	})
+}
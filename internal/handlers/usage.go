@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+	"github.com/graytonio/warframe-wishlist/internal/middleware"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"github.com/graytonio/warframe-wishlist/pkg/response"
+)
+
+// UsageWarningHeader is set on mutation responses once the user nears a cap,
+// listing each such resource as "resource=used/limit".
+const UsageWarningHeader = "X-Usage-Warning"
+
+type UsageHandler struct {
+	usageService services.UsageServiceInterface
+}
+
+func NewUsageHandler(usageService services.UsageServiceInterface) *UsageHandler {
+	return &UsageHandler{usageService: usageService}
+}
+
+func (h *UsageHandler) GetUsage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: GetUsage called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: GetUsage - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	usage, err := h.usageService.GetUsage(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "handler: GetUsage - failed to get usage", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to get usage")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, dto.NewUserUsage(usage))
+}
+
+// setUsageWarnings sets UsageWarningHeader for the resources the user nears
+// the cap of. Warnings are advisory, so failing to work them out is only
+// logged. A nil usage service sets nothing.
+func setUsageWarnings(w http.ResponseWriter, r *http.Request, usageService services.UsageServiceInterface, userID string, resources ...string) {
+	if usageService == nil {
+		return
+	}
+	ctx := r.Context()
+	warnings, err := usageService.Warnings(ctx, userID, resources...)
+	if err != nil {
+		logger.Warn(ctx, "handler: failed to check usage", "error", err)
+		return
+	}
+	if len(warnings) == 0 {
+		return
+	}
+	values := make([]string, 0, len(warnings))
+	for _, warning := range warnings {
+		values = append(values, fmt.Sprintf("%s=%d/%d", warning.Resource, warning.Used, warning.Limit))
+	}
+	w.Header().Set(UsageWarningHeader, strings.Join(values, ", "))
+}
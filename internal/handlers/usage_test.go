@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/graytonio/warframe-wishlist/internal/middleware"
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+)
+
+// withTestUser injects userID in place of the auth middleware.
+func withTestUser(userID string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), middleware.UserIDKey, userID)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func TestUsageHandler_GetUsage(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "success", userID: "user-123", expectedStatus: http.StatusOK},
+		{name: "unauthorized - no user ID", userID: "", expectedStatus: http.StatusUnauthorized},
+		{name: "service error", userID: "user-123", mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewUsageHandler(&mocks.MockUsageService{
+				GetUsageFunc: func(ctx context.Context, userID string) (*models.UserUsage, error) {
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return &models.UserUsage{UserID: userID, Resources: []models.ResourceUsage{
+						{Resource: models.UsageWishlistItems, Used: 1900, Limit: 2000, Warning: true},
+					}}, nil
+				},
+			})
+			r := chi.NewRouter()
+			r.With(withTestUser(tt.userID)).Get("/api/v1/profile/usage", handler.GetUsage)
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/profile/usage", nil))
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			var body struct {
+				Resources []struct {
+					Resource string `json:"resource"`
+					Used     int    `json:"used"`
+					Warning  bool   `json:"warning"`
+				} `json:"resources"`
+			}
+			json.NewDecoder(rec.Body).Decode(&body)
+			if len(body.Resources) != 1 || body.Resources[0].Used != 1900 || !body.Resources[0].Warning {
+				t.Errorf("unexpected body %+v", body)
+			}
+		})
+	}
+}
+
+func TestUsageWarnings_OnMutations(t *testing.T) {
+	tests := []struct {
+		name     string
+		warnings []models.ResourceUsage
+		err      error
+		expected string
+	}{
+		{name: "below the caps", expected: ""},
+		{
+			name: "near the caps",
+			warnings: []models.ResourceUsage{
+				{Resource: models.UsageWishlistItems, Used: 1900, Limit: 2000, Warning: true},
+				{Resource: models.UsageWishlistBytes, Used: 15000000, Limit: models.MaxDocumentBytes, Warning: true},
+			},
+			expected: "wishlistItems=1900/2000, wishlistBytes=15000000/16777216",
+		},
+		{name: "usage unavailable", err: errors.New("database error"), expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var asked []string
+			handler := NewWishlistHandler(&mocks.MockWishlistService{}, &mocks.MockMaterialResolver{})
+			handler.SetUsageService(&mocks.MockUsageService{
+				WarningsFunc: func(ctx context.Context, userID string, resources ...string) ([]models.ResourceUsage, error) {
+					asked = resources
+					return tt.warnings, tt.err
+				},
+			})
+			r := chi.NewRouter()
+			r.With(withTestUser("user-123")).Post("/api/v1/wishlist", handler.AddItem)
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/wishlist", strings.NewReader(`{"uniqueName": "/Lotus/Forma"}`)))
+
+			if rec.Code != http.StatusCreated {
+				t.Fatalf("expected the add to succeed regardless of usage, got %d: %s", rec.Code, rec.Body.String())
+			}
+			if got := rec.Header().Get(UsageWarningHeader); got != tt.expected {
+				t.Errorf("expected warning %q, got %q", tt.expected, got)
+			}
+			if len(asked) == 0 || asked[0] != models.UsageWishlistItems {
+				t.Errorf("expected the wishlist resources checked, got %v", asked)
+			}
+		})
+	}
+}
+
+func TestCapErrors_AreConflicts(t *testing.T) {
+	wishlistHandler := NewWishlistHandler(&mocks.MockWishlistService{
+		AddItemFunc: func(ctx context.Context, userID string, req models.AddItemRequest) error {
+			return services.ErrWishlistFull
+		},
+	}, &mocks.MockMaterialResolver{})
+	blueprintsHandler := NewOwnedBlueprintsHandler(&mockOwnedBlueprintsService{
+		bulkAddBlueprintsFunc: func(ctx context.Context, userID string, req models.BulkAddBlueprintsRequest) error {
+			return services.ErrTooManyBlueprints
+		},
+	})
+	r := chi.NewRouter()
+	r.Use(withTestUser("user-123"))
+	r.Post("/wishlist", wishlistHandler.AddItem)
+	r.Post("/profile/blueprints/bulk", blueprintsHandler.BulkAddBlueprints)
+
+	for target, body := range map[string]string{
+		"/wishlist":                `{"uniqueName": "/Lotus/Forma"}`,
+		"/profile/blueprints/bulk": `{"uniqueNames": ["/Lotus/Forma"]}`,
+	} {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, target, strings.NewReader(body)))
+		if rec.Code != http.StatusConflict {
+			t.Errorf("%s: expected 409, got %d: %s", target, rec.Code, rec.Body.String())
+		}
+	}
+}
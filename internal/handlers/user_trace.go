@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/graytonio/warframe-wishlist/internal/audit"
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"github.com/graytonio/warframe-wishlist/pkg/response"
+)
+
+// UserTraceHandler serves the support routes that turn per-user tracing on
+// and off. They are internal routes that main restricts to admins.
+type UserTraceHandler struct {
+	traceService services.UserTraceServiceInterface
+}
+
+// NewUserTraceHandler returns the handler for the user trace routes.
+func NewUserTraceHandler(traceService services.UserTraceServiceInterface) *UserTraceHandler {
+	return &UserTraceHandler{
+		traceService: traceService,
+	}
+}
+
+func (h *UserTraceHandler) ListTraces(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: ListTraces called")
+
+	traces, err := h.traceService.ListTraces(ctx)
+	if err != nil {
+		logger.Error(ctx, "handler: ListTraces - failed to list traces", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to list traces")
+		return
+	}
+
+	logger.Info(ctx, "handler: ListTraces - success", "count", len(traces))
+	response.JSON(w, http.StatusOK, dto.NewUserTraces(traces))
+}
+
+func (h *UserTraceHandler) GetTrace(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: GetTrace called")
+
+	userID := chi.URLParam(r, "userID")
+	trace, err := h.traceService.GetTrace(ctx, userID)
+	if err != nil {
+		if errors.Is(err, services.ErrUserTraceNotFound) {
+			rejected(w, http.StatusNotFound, err)
+			return
+		}
+		logger.Error(ctx, "handler: GetTrace - failed to get trace", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to get trace")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, dto.NewUserTrace(trace))
+}
+
+// EnableTrace traces the user's requests for the requested window, replacing
+// any trace already set. The body is optional.
+func (h *UserTraceHandler) EnableTrace(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: EnableTrace called")
+
+	var req dto.EnableUserTraceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		logger.Warn(ctx, "handler: EnableTrace - invalid request body", "error", err)
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	userID := chi.URLParam(r, "userID")
+	trace, err := h.traceService.EnableTrace(ctx, userID, req.ToModel())
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidTraceDuration) || errors.Is(err, services.ErrTraceReasonTooLong) {
+			logger.Warn(ctx, "handler: EnableTrace - invalid trace", "error", err)
+			rejected(w, http.StatusBadRequest, err)
+			return
+		}
+		logger.Error(ctx, "handler: EnableTrace - failed to enable trace", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to enable trace")
+		return
+	}
+
+	logger.Info(ctx, "handler: EnableTrace - success", "userID", userID, "until", trace.Until)
+	audit.RecordRequest(r, audit.TypeUserTrace, audit.OutcomeSuccess, "trace enabled until "+trace.Until.UTC().Format(time.RFC3339), userID)
+	response.JSON(w, http.StatusOK, dto.NewUserTrace(trace))
+}
+
+func (h *UserTraceHandler) DisableTrace(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: DisableTrace called")
+
+	userID := chi.URLParam(r, "userID")
+	if err := h.traceService.DisableTrace(ctx, userID); err != nil {
+		logger.Error(ctx, "handler: DisableTrace - failed to disable trace", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to disable trace")
+		return
+	}
+
+	logger.Info(ctx, "handler: DisableTrace - success", "userID", userID)
+	audit.RecordRequest(r, audit.TypeUserTrace, audit.OutcomeSuccess, "trace disabled", userID)
+	response.JSON(w, http.StatusOK, map[string]string{
+		"message": "trace disabled",
+	})
+}
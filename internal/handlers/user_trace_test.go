@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+)
+
+type mockUserTraceService struct {
+	listTracesFunc   func(ctx context.Context) ([]models.UserTrace, error)
+	getTraceFunc     func(ctx context.Context, userID string) (*models.UserTrace, error)
+	enableTraceFunc  func(ctx context.Context, userID string, req models.EnableUserTraceRequest) (*models.UserTrace, error)
+	disableTraceFunc func(ctx context.Context, userID string) error
+}
+
+func (m *mockUserTraceService) ListTraces(ctx context.Context) ([]models.UserTrace, error) {
+	if m.listTracesFunc != nil {
+		return m.listTracesFunc(ctx)
+	}
+	return []models.UserTrace{}, nil
+}
+
+func (m *mockUserTraceService) GetTrace(ctx context.Context, userID string) (*models.UserTrace, error) {
+	if m.getTraceFunc != nil {
+		return m.getTraceFunc(ctx, userID)
+	}
+	return nil, services.ErrUserTraceNotFound
+}
+
+func (m *mockUserTraceService) EnableTrace(ctx context.Context, userID string, req models.EnableUserTraceRequest) (*models.UserTrace, error) {
+	if m.enableTraceFunc != nil {
+		return m.enableTraceFunc(ctx, userID, req)
+	}
+	return &models.UserTrace{UserID: userID}, nil
+}
+
+func (m *mockUserTraceService) DisableTrace(ctx context.Context, userID string) error {
+	if m.disableTraceFunc != nil {
+		return m.disableTraceFunc(ctx, userID)
+	}
+	return nil
+}
+
+// newUserTraceRouter mounts the handler on the cmd/server routes.
+func newUserTraceRouter(service services.UserTraceServiceInterface) http.Handler {
+	handler := NewUserTraceHandler(service)
+	r := chi.NewRouter()
+	r.Get("/internal/users/traces", handler.ListTraces)
+	r.Get("/internal/users/{userID}/trace", handler.GetTrace)
+	r.Put("/internal/users/{userID}/trace", handler.EnableTrace)
+	r.Delete("/internal/users/{userID}/trace", handler.DisableTrace)
+	return r
+}
+
+func TestUserTraceHandler_EnableTrace(t *testing.T) {
+	until := time.Date(2026, 10, 17, 13, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name           string
+		body           string
+		mockError      error
+		expectedStatus int
+		expectedReq    models.EnableUserTraceRequest
+	}{
+		{name: "with duration and reason", body: `{"durationMinutes":30,"reason":"ticket 42"}`, expectedStatus: http.StatusOK, expectedReq: models.EnableUserTraceRequest{DurationMinutes: 30, Reason: "ticket 42"}},
+		{name: "empty body uses defaults", body: ``, expectedStatus: http.StatusOK},
+		{name: "invalid body", body: `{`, expectedStatus: http.StatusBadRequest},
+		{name: "invalid duration", body: `{"durationMinutes":5000}`, mockError: services.ErrInvalidTraceDuration, expectedStatus: http.StatusBadRequest},
+		{name: "reason too long", body: `{"reason":"x"}`, mockError: services.ErrTraceReasonTooLong, expectedStatus: http.StatusBadRequest},
+		{name: "service error", body: `{}`, mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotUserID string
+			var gotReq models.EnableUserTraceRequest
+			service := &mockUserTraceService{
+				enableTraceFunc: func(ctx context.Context, userID string, req models.EnableUserTraceRequest) (*models.UserTrace, error) {
+					gotUserID, gotReq = userID, req
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return &models.UserTrace{UserID: userID, Until: until, Reason: req.Reason}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPut, "/internal/users/user-123/trace", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			newUserTraceRouter(service).ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			if gotUserID != "user-123" || gotReq != tt.expectedReq {
+				t.Errorf("unexpected service call: %q %+v", gotUserID, gotReq)
+			}
+			var trace dto.UserTrace
+			if err := json.NewDecoder(rec.Body).Decode(&trace); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if trace.UserID != "user-123" || !trace.Until.Equal(until) {
+				t.Errorf("unexpected trace: %+v", trace)
+			}
+		})
+	}
+}
+
+func TestUserTraceHandler_GetTrace(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "active trace", expectedStatus: http.StatusOK},
+		{name: "no trace", mockError: services.ErrUserTraceNotFound, expectedStatus: http.StatusNotFound},
+		{name: "service error", mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mockUserTraceService{
+				getTraceFunc: func(ctx context.Context, userID string) (*models.UserTrace, error) {
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return &models.UserTrace{UserID: userID}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/internal/users/user-123/trace", nil)
+			rec := httptest.NewRecorder()
+			newUserTraceRouter(service).ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+		})
+	}
+}
+
+func TestUserTraceHandler_ListTraces(t *testing.T) {
+	service := &mockUserTraceService{
+		listTracesFunc: func(ctx context.Context) ([]models.UserTrace, error) {
+			return []models.UserTrace{{UserID: "user-a"}, {UserID: "user-b"}}, nil
+		},
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/internal/users/traces", nil)
+	rec := httptest.NewRecorder()
+	newUserTraceRouter(service).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	var traces []dto.UserTrace
+	if err := json.NewDecoder(rec.Body).Decode(&traces); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(traces) != 2 || traces[0].UserID != "user-a" {
+		t.Errorf("unexpected traces: %+v", traces)
+	}
+}
+
+func TestUserTraceHandler_DisableTrace(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "disabled", expectedStatus: http.StatusOK},
+		{name: "service error", mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotUserID string
+			service := &mockUserTraceService{
+				disableTraceFunc: func(ctx context.Context, userID string) error {
+					gotUserID = userID
+					return tt.mockError
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodDelete, "/internal/users/user-123/trace", nil)
+			rec := httptest.NewRecorder()
+			newUserTraceRouter(service).ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if gotUserID != "user-123" {
+				t.Errorf("expected user-123 to be passed to the service, got %q", gotUserID)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"github.com/graytonio/warframe-wishlist/pkg/response"
+	"github.com/graytonio/warframe-wishlist/pkg/validate"
+)
+
+// ruleCodes codes the fields that break a validate rule, unless the field's
+// code tag says otherwise.
+var ruleCodes = map[string]response.Code{
+	validate.RuleRequired: response.CodeRequired,
+	validate.RuleMin:      response.CodeTooSmall,
+	validate.RuleMax:      response.CodeTooLarge,
+}
+
+// decodeAndValidate decodes the request body into req, a pointer to a dto
+// request, and checks it against its validate tags. If either fails it
+// writes a 400, listing every broken rule as a coded field, and returns
+// false.
+func decodeAndValidate(w http.ResponseWriter, r *http.Request, name string, req any) bool {
+	ctx := r.Context()
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		logger.Warn(ctx, "handler: "+name+" - invalid request body", "error", err)
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return false
+	}
+
+	var errs validate.Errors
+	if !errors.As(validate.Struct(req), &errs) {
+		return true
+	}
+	logger.Warn(ctx, "handler: "+name+" - request body failed validation", "error", errs)
+	fields := make([]response.FieldError, len(errs))
+	for i, fieldErr := range errs {
+		code := response.Code(fieldErr.Code)
+		if code == "" {
+			code = ruleCodes[fieldErr.Rule]
+		}
+		fields[i] = response.FieldError{Field: fieldErr.Field, Code: code, Message: fieldErr.Message}
+	}
+	response.WriteError(w, http.StatusBadRequest, response.ErrorResponse{
+		Message: services.ErrValidation.Error(),
+		Fields:  fields,
+	})
+	return false
+}
+
+// validationFailed writes a 422 listing every problem when err is a
+// services.ValidationError, both as coded fields and, for clients written
+// before fields, as the detail.
+func validationFailed(w http.ResponseWriter, r *http.Request, name string, err error) bool {
+	var validationErr *services.ValidationError
+	if !errors.As(err, &validationErr) {
+		return false
+	}
+
+	logger.Warn(r.Context(), "handler: "+name+" - request failed validation", "problems", len(validationErr.Problems), "error", err)
+	errs := validationErr.Unwrap()
+	fields := make([]response.FieldError, len(validationErr.Problems))
+	for i, problem := range validationErr.Problems {
+		fields[i] = response.FieldError{Field: problem.Field, Code: errorCode(errs[i]), Message: problem.Reason}
+	}
+	response.WriteError(w, http.StatusUnprocessableEntity, response.ErrorResponse{
+		Message: services.ErrValidation.Error(),
+		Fields:  fields,
+		Detail:  dto.NewValidationProblems(validationErr.Problems),
+	})
+	return true
+}
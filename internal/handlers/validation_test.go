@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+)
+
+func newTestValidationError() error {
+	var problems services.ValidationError
+	problems.Add("materials[0].count", "/Lotus/Ferrite", services.ErrInvalidMaterialCount)
+	problems.Add("materials[1].uniqueName", "", models.ErrUniqueNameRequired)
+	return problems.Err()
+}
+
+func TestValidationFailed(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/profile/materials/", nil)
+	if !validationFailed(rec, req, "SetMaterialCounts", fmt.Errorf("setting counts: %w", newTestValidationError())) {
+		t.Fatal("expected a wrapped ValidationError to be handled")
+	}
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected status 422, got %d", rec.Code)
+	}
+
+	var body struct {
+		Code   string `json:"code"`
+		Fields []struct {
+			Field   string `json:"field"`
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"fields"`
+		Detail struct {
+			Problems []struct {
+				Field      string `json:"field"`
+				UniqueName string `json:"uniqueName"`
+				Reason     string `json:"reason"`
+			} `json:"problems"`
+		} `json:"detail"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode body: %v", err)
+	}
+	if body.Code != "validation_failed" {
+		t.Errorf("expected code validation_failed, got %q", body.Code)
+	}
+	if len(body.Fields) != 2 || body.Fields[0].Field != "materials[0].count" || body.Fields[0].Code != "invalid_material_count" ||
+		body.Fields[1].Code != "unique_name_required" || body.Fields[1].Message != models.ErrUniqueNameRequired.Error() {
+		t.Errorf("unexpected fields %+v", body.Fields)
+	}
+	problems := body.Detail.Problems
+	if len(problems) != 2 {
+		t.Fatalf("expected 2 problems, got %+v", problems)
+	}
+	if problems[0].Field != "materials[0].count" || problems[0].UniqueName != "/Lotus/Ferrite" || problems[0].Reason != services.ErrInvalidMaterialCount.Error() {
+		t.Errorf("unexpected first problem %+v", problems[0])
+	}
+	if problems[1].Field != "materials[1].uniqueName" || problems[1].UniqueName != "" {
+		t.Errorf("unexpected second problem %+v", problems[1])
+	}
+
+	if validationFailed(httptest.NewRecorder(), req, "SetMaterialCounts", errors.New("database error")) {
+		t.Error("expected other errors to be left to the caller")
+	}
+}
+
+func TestDecodeAndValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantOK     bool
+		wantCode   string
+		wantFields []string
+	}{
+		{name: "valid", body: `{"uniqueNames":["/Lotus/Blueprint1"]}`, wantOK: true},
+		{name: "empty list", body: `{"uniqueNames":[]}`, wantOK: true},
+		{name: "invalid json", body: `{"uniqueNames":`, wantCode: "invalid_request_body"},
+		{name: "blank name", body: `{"uniqueNames":["/Lotus/Blueprint1"," "]}`, wantCode: "validation_failed", wantFields: []string{"uniqueNames[1]"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/profile/blueprints/bulk", strings.NewReader(tt.body))
+
+			var decoded dto.BulkAddBlueprintsRequest
+			if ok := decodeAndValidate(rec, req, "BulkAddBlueprints", &decoded); ok != tt.wantOK {
+				t.Fatalf("expected %v, got %v", tt.wantOK, ok)
+			}
+			if tt.wantOK {
+				if rec.Body.Len() != 0 {
+					t.Errorf("expected nothing written, got %q", rec.Body.String())
+				}
+				return
+			}
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("expected status 400, got %d", rec.Code)
+			}
+
+			var body struct {
+				Code   string `json:"code"`
+				Fields []struct {
+					Field string `json:"field"`
+					Code  string `json:"code"`
+				} `json:"fields"`
+			}
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatalf("failed to decode body: %v", err)
+			}
+			if body.Code != tt.wantCode {
+				t.Errorf("expected code %q, got %q", tt.wantCode, body.Code)
+			}
+			if len(body.Fields) != len(tt.wantFields) {
+				t.Fatalf("expected fields %v, got %+v", tt.wantFields, body.Fields)
+			}
+			for i, field := range tt.wantFields {
+				if body.Fields[i].Field != field || body.Fields[i].Code != "unique_name_required" {
+					t.Errorf("unexpected field %+v", body.Fields[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeAndValidate_RuleCodes(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPatch, "/api/v1/wishlist/Lotus-Item1", strings.NewReader(`{"quantity":0}`))
+
+	var decoded dto.UpdateQuantityRequest
+	if decodeAndValidate(rec, req, "UpdateQuantity", &decoded) {
+		t.Fatal("expected a zero quantity to be rejected")
+	}
+	var body struct {
+		Fields []struct {
+			Field   string `json:"field"`
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"fields"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode body: %v", err)
+	}
+	if len(body.Fields) != 1 || body.Fields[0].Field != "quantity" || body.Fields[0].Code != "invalid_quantity" ||
+		body.Fields[0].Message != "quantity must be at least 1" {
+		t.Errorf("unexpected fields %+v", body.Fields)
+	}
+	if ruleCodes["min"] != "too_small" {
+		t.Errorf("expected untagged min failures to be coded too_small, got %q", ruleCodes["min"])
+	}
+}
@@ -1,11 +1,14 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
+	"strconv"
 
-	"github.com/go-chi/chi/v5"
+	"github.com/graytonio/warframe-wishlist/internal/deprecation"
+	"github.com/graytonio/warframe-wishlist/internal/dto"
 	"github.com/graytonio/warframe-wishlist/internal/middleware"
 	"github.com/graytonio/warframe-wishlist/internal/models"
 	"github.com/graytonio/warframe-wishlist/internal/services"
@@ -16,8 +19,26 @@ import (
 type WishlistHandler struct {
 	wishlistService  services.WishlistServiceInterface
 	materialResolver services.MaterialResolverInterface
+	// quantitySuggester is optional; without it AddItem suggests nothing.
+	quantitySuggester services.QuantitySuggesterInterface
+	// usageService is optional; with it AddItem warns when the user nears the
+	// wishlist cap.
+	usageService services.UsageServiceInterface
+	// deprecations is optional; with it GetWishlist warns clients still
+	// reading bare items.
+	deprecations *deprecation.Tracker
+	// versions is optional; with it GetWishlist and GetMaterials send ETags
+	// and answer a matching If-None-Match with 304 Not Modified.
+	versions services.WishlistVersionServiceInterface
+	// onboarding is optional; with it GetWishlist sets OnboardingHeader for
+	// new users.
+	onboarding services.OnboardingServiceInterface
 }
 
+// OnboardingHeader is set to "true" on the wishlist of a user who has none
+// yet, for clients to show a first-run experience.
+const OnboardingHeader = "X-Onboarding"
+
 func NewWishlistHandler(wishlistService services.WishlistServiceInterface, materialResolver services.MaterialResolverInterface) *WishlistHandler {
 	return &WishlistHandler{
 		wishlistService:  wishlistService,
@@ -25,6 +46,52 @@ func NewWishlistHandler(wishlistService services.WishlistServiceInterface, mater
 	}
 }
 
+// SetQuantitySuggester makes AddItem suggest a quantity for items the rest of
+// the wishlist uses in its recipes.
+func (h *WishlistHandler) SetQuantitySuggester(quantitySuggester services.QuantitySuggesterInterface) {
+	h.quantitySuggester = quantitySuggester
+}
+
+// SetUsageService makes AddItem set UsageWarningHeader as the user nears the
+// wishlist cap.
+func (h *WishlistHandler) SetUsageService(usageService services.UsageServiceInterface) {
+	h.usageService = usageService
+}
+
+// SetDeprecations makes GetWishlist announce deprecation.WishlistBareItems to
+// clients that do not expand items.
+func (h *WishlistHandler) SetDeprecations(deprecations *deprecation.Tracker) {
+	h.deprecations = deprecations
+}
+
+// SetVersions makes GetWishlist and GetMaterials conditional on the versions
+// of what their responses are built from.
+func (h *WishlistHandler) SetVersions(versions services.WishlistVersionServiceInterface) {
+	h.versions = versions
+}
+
+// SetOnboarding makes GetWishlist flag new users with OnboardingHeader,
+// seeding their wishlist first if the service does.
+func (h *WishlistHandler) SetOnboarding(onboarding services.OnboardingServiceInterface) {
+	h.onboarding = onboarding
+}
+
+// versionETag returns the weak ETag of the response version returns for
+// userID, one per variant of it. It returns "" when the user has no version
+// or it cannot be read, and the response then goes out untagged.
+func versionETag(w http.ResponseWriter, r *http.Request, name string, version func(ctx context.Context, userID string) (string, error), userID string, variant ...string) string {
+	ctx := r.Context()
+	v, err := version(ctx, userID)
+	if err != nil {
+		logger.Warn(ctx, "handler: "+name+" - failed to read response version, serving it untagged", "error", err)
+		return ""
+	}
+	if v == "" {
+		return ""
+	}
+	return response.WeakETag(w, v, variant...)
+}
+
 func (h *WishlistHandler) GetWishlist(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	logger.Debug(ctx, "handler: GetWishlist called")
@@ -36,6 +103,54 @@ func (h *WishlistHandler) GetWishlist(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Onboarding may seed the wishlist, so it runs before the version is
+	// read. It is advisory: failing to check is only logged.
+	if h.onboarding != nil {
+		isNew, err := h.onboarding.Onboard(ctx, userID)
+		if err != nil {
+			logger.Warn(ctx, "handler: GetWishlist - failed to check onboarding", "error", err)
+		} else if isNew {
+			w.Header().Set(OnboardingHeader, "true")
+		}
+	}
+
+	// The query selects expansion and HAL, and Accept may too.
+	expand := r.URL.Query().Get("expand") == "items"
+	var etag string
+	if h.versions != nil {
+		etag = versionETag(w, r, "GetWishlist", h.versions.WishlistVersion, userID, r.URL.RawQuery, strconv.FormatBool(wantsHAL(r)))
+	}
+
+	if expand {
+		if response.IfNoneMatch(r, etag) {
+			logger.Debug(ctx, "handler: GetWishlist - not modified", "expanded", true)
+			response.NotModified(w, etag)
+			return
+		}
+		logger.Debug(ctx, "handler: GetWishlist - fetching expanded wishlist", "userID", userID)
+		expanded, err := h.wishlistService.GetExpandedWishlist(ctx, userID)
+		if err != nil {
+			logger.Error(ctx, "handler: GetWishlist - failed to get expanded wishlist", "error", err)
+			response.Error(w, http.StatusInternalServerError, "failed to get wishlist")
+			return
+		}
+		logger.Info(ctx, "handler: GetWishlist - success", "itemCount", len(expanded.Items), "expanded", true)
+		response.SetETag(w, etag)
+		if wantsHAL(r) {
+			response.HAL(w, http.StatusOK, halExpandedWishlist(r, dto.NewExpandedWishlist(expanded)))
+			return
+		}
+		response.JSON(w, http.StatusOK, dto.NewExpandedWishlist(expanded))
+		return
+	}
+
+	if response.IfNoneMatch(r, etag) {
+		logger.Debug(ctx, "handler: GetWishlist - not modified", "expanded", false)
+		// Revalidating bare items is still reading them.
+		h.deprecations.Use(w, r, deprecation.WishlistBareItems)
+		response.NotModified(w, etag)
+		return
+	}
 	logger.Debug(ctx, "handler: GetWishlist - fetching wishlist", "userID", userID)
 	wishlist, err := h.wishlistService.GetWishlist(ctx, userID)
 	if err != nil {
@@ -49,7 +164,13 @@ func (h *WishlistHandler) GetWishlist(w http.ResponseWriter, r *http.Request) {
 		itemCount = len(wishlist.Items)
 	}
 	logger.Info(ctx, "handler: GetWishlist - success", "itemCount", itemCount)
-	response.JSON(w, http.StatusOK, wishlist)
+	h.deprecations.Use(w, r, deprecation.WishlistBareItems)
+	response.SetETag(w, etag)
+	if wantsHAL(r) {
+		response.HAL(w, http.StatusOK, halWishlist(r, dto.NewWishlist(wishlist)))
+		return
+	}
+	response.JSON(w, http.StatusOK, dto.NewWishlist(wishlist))
 }
 
 func (h *WishlistHandler) AddItem(w http.ResponseWriter, r *http.Request) {
@@ -63,22 +184,17 @@ func (h *WishlistHandler) AddItem(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var req models.AddItemRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		logger.Warn(ctx, "handler: AddItem - invalid request body", "error", err)
-		response.Error(w, http.StatusBadRequest, "invalid request body")
-		return
-	}
-
-	if req.UniqueName == "" {
-		logger.Warn(ctx, "handler: AddItem - uniqueName is required")
-		response.Error(w, http.StatusBadRequest, "uniqueName is required")
+	var req dto.AddItemRequest
+	if !decodeAndValidate(w, r, "AddItem", &req) {
 		return
 	}
 
 	logger.Debug(ctx, "handler: AddItem - adding item to wishlist", "uniqueName", req.UniqueName, "quantity", req.Quantity)
-	err := h.wishlistService.AddItem(ctx, userID, req)
+	err := h.wishlistService.AddItem(ctx, userID, req.ToModel())
 	if err != nil {
+		if approvalRequired(w, r, err) || alreadyOwned(w, r, err) {
+			return
+		}
 		if errors.Is(err, services.ErrItemNotFound) {
 			logger.Warn(ctx, "handler: AddItem - item not found", "uniqueName", req.UniqueName)
 			response.Error(w, http.StatusNotFound, "item not found")
@@ -89,15 +205,29 @@ func (h *WishlistHandler) AddItem(w http.ResponseWriter, r *http.Request) {
 			response.Error(w, http.StatusConflict, "item already in wishlist")
 			return
 		}
+		if errors.Is(err, services.ErrWishlistFull) {
+			logger.Warn(ctx, "handler: AddItem - wishlist is full", "error", err)
+			rejected(w, http.StatusConflict, err)
+			return
+		}
 		logger.Error(ctx, "handler: AddItem - failed to add item to wishlist", "error", err)
 		response.Error(w, http.StatusInternalServerError, "failed to add item to wishlist")
 		return
 	}
 
 	logger.Info(ctx, "handler: AddItem - success", "uniqueName", req.UniqueName)
-	response.JSON(w, http.StatusCreated, map[string]string{
-		"message": "item added to wishlist",
-	})
+	added := dto.AddedItem{Message: "item added to wishlist"}
+	if h.quantitySuggester != nil {
+		// The suggestion is advisory, so failing to work it out doesn't fail
+		// the add.
+		suggestion, err := h.quantitySuggester.SuggestQuantity(ctx, userID, req.UniqueName)
+		if err != nil {
+			logger.Warn(ctx, "handler: AddItem - failed to suggest quantity", "error", err)
+		}
+		added.Suggestion = dto.NewQuantitySuggestion(suggestion)
+	}
+	setUsageWarnings(w, r, h.usageService, userID, models.UsageWishlistItems, models.UsageWishlistBytes)
+	response.JSON(w, http.StatusCreated, added)
 }
 
 func (h *WishlistHandler) RemoveItem(w http.ResponseWriter, r *http.Request) {
@@ -112,18 +242,15 @@ func (h *WishlistHandler) RemoveItem(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Use wildcard param to capture full path including slashes (e.g., /Lotus/Types/Items/...)
-	uniqueName := chi.URLParam(r, "*")
-	if uniqueName == "" {
-		logger.Warn(ctx, "handler: RemoveItem - uniqueName is required")
-		response.Error(w, http.StatusBadRequest, "uniqueName is required")
+	uniqueName, err := uniqueNameParam(r)
+	if err != nil {
+		logger.Warn(ctx, "handler: RemoveItem - invalid uniqueName", "error", err)
+		rejected(w, http.StatusBadRequest, err)
 		return
 	}
 
-	// Add leading slash to the uniqueName
-	uniqueName = "/" + uniqueName
-
 	logger.Debug(ctx, "handler: RemoveItem - removing item from wishlist", "uniqueName", uniqueName)
-	err := h.wishlistService.RemoveItem(ctx, userID, uniqueName)
+	err = h.wishlistService.RemoveItem(ctx, userID, uniqueName)
 	if err != nil {
 		if errors.Is(err, services.ErrItemNotInWishlist) {
 			logger.Warn(ctx, "handler: RemoveItem - item not in wishlist", "uniqueName", uniqueName)
@@ -153,23 +280,24 @@ func (h *WishlistHandler) UpdateQuantity(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Use wildcard param to capture full path including slashes (e.g., /Lotus/Types/Items/...)
-	uniqueName := chi.URLParam(r, "*")
-	if uniqueName == "" {
-		logger.Warn(ctx, "handler: UpdateQuantity - uniqueName is required")
-		response.Error(w, http.StatusBadRequest, "uniqueName is required")
+	uniqueName, err := uniqueNameParam(r)
+	if err != nil {
+		logger.Warn(ctx, "handler: UpdateQuantity - invalid uniqueName", "error", err)
+		rejected(w, http.StatusBadRequest, err)
 		return
 	}
 
-	var req models.UpdateQuantityRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		logger.Warn(ctx, "handler: UpdateQuantity - invalid request body", "error", err)
-		response.Error(w, http.StatusBadRequest, "invalid request body")
+	var req dto.UpdateQuantityRequest
+	if !decodeAndValidate(w, r, "UpdateQuantity", &req) {
 		return
 	}
 
 	logger.Debug(ctx, "handler: UpdateQuantity - updating quantity", "uniqueName", uniqueName, "quantity", req.Quantity)
-	err := h.wishlistService.UpdateQuantity(ctx, userID, uniqueName, req.Quantity)
+	err = h.wishlistService.UpdateQuantity(ctx, userID, uniqueName, req.Quantity)
 	if err != nil {
+		if approvalRequired(w, r, err) {
+			return
+		}
 		if errors.Is(err, services.ErrItemNotInWishlist) {
 			logger.Warn(ctx, "handler: UpdateQuantity - item not in wishlist", "uniqueName", uniqueName)
 			response.Error(w, http.StatusNotFound, "item not in wishlist")
@@ -191,6 +319,154 @@ func (h *WishlistHandler) UpdateQuantity(w http.ResponseWriter, r *http.Request)
 	})
 }
 
+// SetItemLinks replaces the source links on a wishlist item. Clients must
+// render the stored URLs with rel="noopener noreferrer nofollow ugc".
+func (h *WishlistHandler) SetItemLinks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: SetItemLinks called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: SetItemLinks - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	uniqueName, err := uniqueNameParam(r)
+	if err != nil {
+		logger.Warn(ctx, "handler: SetItemLinks - invalid uniqueName", "error", err)
+		rejected(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var req dto.UpdateItemLinksRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn(ctx, "handler: SetItemLinks - invalid request body", "error", err)
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	links, err := h.wishlistService.SetItemLinks(ctx, userID, uniqueName, req.ToModel())
+	if err != nil {
+		if errors.Is(err, services.ErrItemNotInWishlist) {
+			logger.Warn(ctx, "handler: SetItemLinks - item not in wishlist", "uniqueName", uniqueName)
+			response.Error(w, http.StatusNotFound, "item not in wishlist")
+			return
+		}
+		if errors.Is(err, services.ErrInvalidLink) || errors.Is(err, services.ErrTooManyLinks) {
+			logger.Warn(ctx, "handler: SetItemLinks - invalid links", "error", err)
+			rejected(w, http.StatusBadRequest, err)
+			return
+		}
+		logger.Error(ctx, "handler: SetItemLinks - failed to update links", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to update links")
+		return
+	}
+
+	logger.Info(ctx, "handler: SetItemLinks - success", "uniqueName", uniqueName, "linkCount", len(links))
+	response.JSON(w, http.StatusOK, dto.NewItemLinks(uniqueName, links))
+}
+
+// SetItemRecipe selects the alternate recipe used to resolve a wishlist
+// item's materials.
+func (h *WishlistHandler) SetItemRecipe(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: SetItemRecipe called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: SetItemRecipe - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	uniqueName, err := uniqueNameParam(r)
+	if err != nil {
+		logger.Warn(ctx, "handler: SetItemRecipe - invalid uniqueName", "error", err)
+		rejected(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var req dto.SetItemRecipeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn(ctx, "handler: SetItemRecipe - invalid request body", "error", err)
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := h.wishlistService.SetItemRecipe(ctx, userID, uniqueName, req.RecipeID); err != nil {
+		if errors.Is(err, services.ErrItemNotInWishlist) {
+			logger.Warn(ctx, "handler: SetItemRecipe - item not in wishlist", "uniqueName", uniqueName)
+			response.Error(w, http.StatusNotFound, "item not in wishlist")
+			return
+		}
+		if errors.Is(err, services.ErrItemNotFound) || errors.Is(err, services.ErrRecipeNotFound) {
+			logger.Warn(ctx, "handler: SetItemRecipe - recipe not found", "uniqueName", uniqueName, "recipeID", req.RecipeID)
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeRecipeNotFound, "recipe not found")
+			return
+		}
+		logger.Error(ctx, "handler: SetItemRecipe - failed to update recipe", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to update recipe")
+		return
+	}
+
+	logger.Info(ctx, "handler: SetItemRecipe - success", "uniqueName", uniqueName, "recipeID", req.RecipeID)
+	response.JSON(w, http.StatusOK, dto.NewItemRecipe(uniqueName, req.RecipeID))
+}
+
+// SetItemProgress replaces which components of a wishlist item are built or
+// acquired.
+func (h *WishlistHandler) SetItemProgress(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: SetItemProgress called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: SetItemProgress - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	uniqueName, err := uniqueNameParam(r)
+	if err != nil {
+		logger.Warn(ctx, "handler: SetItemProgress - invalid uniqueName", "error", err)
+		rejected(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var req dto.UpdateItemProgressRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn(ctx, "handler: SetItemProgress - invalid request body", "error", err)
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	item, err := h.wishlistService.SetItemProgress(ctx, userID, uniqueName, req.ToModel())
+	if err != nil {
+		if errors.Is(err, services.ErrItemNotInWishlist) {
+			logger.Warn(ctx, "handler: SetItemProgress - item not in wishlist", "uniqueName", uniqueName)
+			response.Error(w, http.StatusNotFound, "item not in wishlist")
+			return
+		}
+		if errors.Is(err, services.ErrItemNotFound) {
+			logger.Warn(ctx, "handler: SetItemProgress - item not found", "uniqueName", uniqueName)
+			response.Error(w, http.StatusNotFound, "item not found")
+			return
+		}
+		if errors.Is(err, services.ErrInvalidProgress) {
+			logger.Warn(ctx, "handler: SetItemProgress - invalid progress", "error", err)
+			rejected(w, http.StatusBadRequest, err)
+			return
+		}
+		logger.Error(ctx, "handler: SetItemProgress - failed to update progress", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to update progress")
+		return
+	}
+
+	logger.Info(ctx, "handler: SetItemProgress - success", "uniqueName", uniqueName, "completion", item.Completion)
+	response.JSON(w, http.StatusOK, dto.NewItemProgress(item))
+}
+
 func (h *WishlistHandler) GetMaterials(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	logger.Debug(ctx, "handler: GetMaterials called")
@@ -202,18 +478,79 @@ func (h *WishlistHandler) GetMaterials(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	logger.Debug(ctx, "handler: GetMaterials - resolving materials")
+	// ?format=csv, or an Accept header preferring text/csv, returns the
+	// shopping list as CSV instead of JSON.
+	query := r.URL.Query()
+	asCSV := false
+	switch format := query.Get("format"); format {
+	case "csv":
+		asCSV = true
+	case "json":
+	case "":
+		asCSV = prefersCSV(r.Header.Get("Accept"))
+	default:
+		logger.Warn(ctx, "handler: GetMaterials - unsupported format", "format", format)
+		response.ErrorCode(w, http.StatusBadRequest, response.CodeUnsupportedFormat, "unsupported format: "+format)
+		return
+	}
+	var columns []string
+	if asCSV {
+		var unknown string
+		columns, unknown = parseMaterialColumns(query.Get("columns"), shoppingListColumns)
+		if unknown != "" {
+			logger.Warn(ctx, "handler: GetMaterials - unknown column", "column", unknown)
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeUnknownColumn, "unknown column: "+unknown)
+			return
+		}
+	}
+
+	// The query selects the format, columns and BOM, and Accept may too.
+	var etag string
+	if h.versions != nil {
+		etag = versionETag(w, r, "GetMaterials", h.versions.MaterialsVersion, userID, r.URL.RawQuery, strconv.FormatBool(asCSV))
+	}
+	if response.IfNoneMatch(r, etag) {
+		logger.Debug(ctx, "handler: GetMaterials - not modified", "csv", asCSV)
+		response.NotModified(w, etag)
+		return
+	}
+
+	logger.Debug(ctx, "handler: GetMaterials - resolving materials", "csv", asCSV)
 	materials, err := h.materialResolver.GetMaterials(ctx, userID)
 	if err != nil {
 		logger.Error(ctx, "handler: GetMaterials - failed to get materials", "error", err)
 		response.Error(w, http.StatusInternalServerError, "failed to get materials")
 		return
 	}
+	// A partial result is not what the version promises.
+	if materials != nil && !materials.IsDegraded() && !materials.Truncated {
+		response.SetETag(w, etag)
+	}
+	if asCSV {
+		writeMaterialsCSV(w, r, "GetMaterials", materials, columns)
+		return
+	}
 
 	materialCount := 0
 	if materials != nil {
 		materialCount = len(materials.Materials)
 	}
 	logger.Info(ctx, "handler: GetMaterials - success", "materialCount", materialCount, "totalCredits", materials.TotalCredits)
-	response.JSON(w, http.StatusOK, materials)
+	response.JSON(w, http.StatusOK, dto.NewMaterialsSummary(materials))
+}
+
+// approvalRequired writes a 202 with the pending change when err reports that
+// the change was held for the member's household manager.
+func approvalRequired(w http.ResponseWriter, r *http.Request, err error) bool {
+	var approvalErr *services.ApprovalRequiredError
+	if !errors.As(err, &approvalErr) {
+		return false
+	}
+
+	logger.Info(r.Context(), "handler: change held for manager approval", "changeID", approvalErr.Change.ID.Hex(), "uniqueName", approvalErr.Change.UniqueName)
+	response.JSON(w, http.StatusAccepted, map[string]any{
+		"message":       "change requires manager approval",
+		"pendingChange": dto.NewPendingChange(approvalErr.Change),
+	})
+	return true
 }
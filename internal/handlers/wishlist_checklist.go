@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/pdf"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"github.com/graytonio/warframe-wishlist/pkg/response"
+)
+
+// Checklist layout, in points.
+const (
+	checklistMargin     = 50.0
+	checklistBottom     = 60.0
+	checklistRowHeight  = 18.0
+	checklistBoxSize    = 10.0
+	checklistCountX     = 470.0
+	checklistMaxNameLen = 70
+)
+
+// writeChecklistPDF writes checklist as a printable PDF download: a checkbox
+// per item with its quantity, then one per material still needed.
+func writeChecklistPDF(w http.ResponseWriter, r *http.Request, checklist *models.WishlistChecklist) {
+	ctx := r.Context()
+
+	var buf bytes.Buffer
+	if _, err := renderChecklist(checklist).WriteTo(&buf); err != nil {
+		logger.Error(ctx, "handler: ExportWishlist - failed to render pdf", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to export wishlist")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", `attachment; filename="wishlist-checklist.pdf"`)
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+
+	logger.Info(ctx, "handler: ExportWishlist - success", "format", "pdf", "itemCount", len(checklist.Items), "materialCount", len(checklist.Materials))
+}
+
+// checklistWriter lays rows out top to bottom, starting a new page when one
+// fills up.
+type checklistWriter struct {
+	doc   *pdf.Document
+	pages []*pdf.Page
+	page  *pdf.Page
+	y     float64
+}
+
+func renderChecklist(checklist *models.WishlistChecklist) *pdf.Document {
+	cw := &checklistWriter{doc: pdf.New("Warframe Wishlist Checklist")}
+	cw.newPage()
+	cw.page.Text(checklistMargin, cw.y, pdf.HelveticaBold, 18, "Warframe Wishlist Checklist")
+	cw.y -= 16
+	cw.page.Text(checklistMargin, cw.y, pdf.Helvetica, 9, "Generated "+checklist.GeneratedAt.Format("2006-01-02 15:04 MST"))
+	cw.y -= 14
+
+	cw.heading(fmt.Sprintf("Items (%d)", len(checklist.Items)), "Quantity")
+	if len(checklist.Items) == 0 {
+		cw.note("Your wishlist is empty.")
+	}
+	for _, item := range checklist.Items {
+		cw.row(item.Name, "x"+strconv.Itoa(item.Quantity))
+	}
+
+	if len(checklist.Items) > 0 {
+		cw.heading(fmt.Sprintf("Materials (%d)", len(checklist.Materials)), "Needed")
+		if checklist.MaterialsTruncated {
+			cw.note("This list is partial: the wishlist needs more materials than could be worked out.")
+		}
+		if len(checklist.Materials) == 0 && !checklist.MaterialsTruncated {
+			cw.note("You have every material the wishlist needs.")
+		}
+		for _, material := range checklist.Materials {
+			cw.row(material.Name, strconv.Itoa(material.Remaining))
+		}
+	}
+
+	for i, page := range cw.pages {
+		page.Text(pdf.PageWidth-checklistMargin-60, 30, pdf.Helvetica, 8, fmt.Sprintf("Page %d of %d", i+1, len(cw.pages)))
+	}
+	return cw.doc
+}
+
+func (cw *checklistWriter) newPage() {
+	cw.page = cw.doc.AddPage()
+	cw.pages = append(cw.pages, cw.page)
+	cw.page.LineWidth(0.75)
+	cw.y = pdf.PageHeight - checklistMargin - 10
+}
+
+// ensure starts a new page unless height fits above the bottom margin.
+func (cw *checklistWriter) ensure(height float64) {
+	if cw.y-height < checklistBottom {
+		cw.newPage()
+	}
+}
+
+// heading starts a section, keeping it on the same page as its first row.
+func (cw *checklistWriter) heading(title, countLabel string) {
+	cw.ensure(24 + 2*checklistRowHeight)
+	cw.y -= 24
+	cw.page.Text(checklistMargin, cw.y, pdf.HelveticaBold, 13, title)
+	cw.page.Text(checklistCountX, cw.y, pdf.HelveticaBold, 9, countLabel)
+	cw.page.Line(checklistMargin, cw.y-5, pdf.PageWidth-checklistMargin, cw.y-5)
+	cw.y -= 6
+}
+
+func (cw *checklistWriter) row(name, count string) {
+	cw.ensure(checklistRowHeight)
+	cw.y -= checklistRowHeight
+	cw.page.Rect(checklistMargin, cw.y-1, checklistBoxSize, checklistBoxSize)
+	cw.page.Text(checklistMargin+18, cw.y, pdf.Helvetica, 11, truncateName(name))
+	cw.page.Text(checklistCountX, cw.y, pdf.Helvetica, 11, count)
+}
+
+func (cw *checklistWriter) note(text string) {
+	cw.ensure(checklistRowHeight)
+	cw.y -= checklistRowHeight
+	cw.page.Text(checklistMargin, cw.y, pdf.Helvetica, 10, text)
+}
+
+// truncateName shortens names that would run into the count column.
+func truncateName(name string) string {
+	runes := []rune(name)
+	if len(runes) <= checklistMaxNameLen {
+		return name
+	}
+	return string(runes[:checklistMaxNameLen-3]) + "..."
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+	"github.com/graytonio/warframe-wishlist/internal/middleware"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"github.com/graytonio/warframe-wishlist/pkg/response"
+)
+
+type WishlistImportHandler struct {
+	importService services.WishlistImportServiceInterface
+}
+
+func NewWishlistImportHandler(importService services.WishlistImportServiceInterface) *WishlistImportHandler {
+	return &WishlistImportHandler{
+		importService: importService,
+	}
+}
+
+// PreviewText resolves pasted item names without changing the wishlist.
+func (h *WishlistImportHandler) PreviewText(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: PreviewText called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: PreviewText - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	var req dto.ImportTextRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn(ctx, "handler: PreviewText - invalid request body", "error", err)
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	preview, err := h.importService.PreviewText(ctx, req.Text)
+	if err != nil {
+		if errors.Is(err, services.ErrImportEmpty) || errors.Is(err, services.ErrImportTooLarge) {
+			logger.Warn(ctx, "handler: PreviewText - invalid import", "error", err)
+			rejected(w, http.StatusBadRequest, err)
+			return
+		}
+		logger.Error(ctx, "handler: PreviewText - failed to preview import", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to preview import")
+		return
+	}
+
+	logger.Info(ctx, "handler: PreviewText - success", "matched", len(preview.Matched), "ambiguous", len(preview.Ambiguous), "unmatched", len(preview.Unmatched))
+	response.JSON(w, http.StatusOK, dto.NewImportPreview(preview))
+}
+
+// ConfirmImport adds the items picked from a preview.
+func (h *WishlistImportHandler) ConfirmImport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: ConfirmImport called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: ConfirmImport - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	var req dto.ImportConfirmRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn(ctx, "handler: ConfirmImport - invalid request body", "error", err)
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	result, err := h.importService.Confirm(ctx, userID, req.ToModel())
+	if err != nil {
+		if errors.Is(err, services.ErrImportEmpty) || errors.Is(err, services.ErrImportTooLarge) {
+			logger.Warn(ctx, "handler: ConfirmImport - invalid import", "error", err)
+			rejected(w, http.StatusBadRequest, err)
+			return
+		}
+		if errors.Is(err, services.ErrWishlistFull) {
+			logger.Warn(ctx, "handler: ConfirmImport - wishlist is full", "error", err)
+			rejected(w, http.StatusConflict, err)
+			return
+		}
+		logger.Error(ctx, "handler: ConfirmImport - failed to import items", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to import items")
+		return
+	}
+
+	logger.Info(ctx, "handler: ConfirmImport - success", "count", len(result.Results))
+	response.JSON(w, http.StatusOK, dto.NewImportConfirmResult(result))
+}
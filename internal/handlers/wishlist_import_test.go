@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+	"github.com/graytonio/warframe-wishlist/internal/middleware"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+)
+
+type mockWishlistImportService struct {
+	previewTextFunc func(ctx context.Context, text string) (*models.ImportPreview, error)
+	confirmFunc     func(ctx context.Context, userID string, req models.ImportConfirmRequest) (*models.ImportConfirmResult, error)
+}
+
+func (m *mockWishlistImportService) PreviewText(ctx context.Context, text string) (*models.ImportPreview, error) {
+	if m.previewTextFunc != nil {
+		return m.previewTextFunc(ctx, text)
+	}
+	return &models.ImportPreview{}, nil
+}
+
+func (m *mockWishlistImportService) Confirm(ctx context.Context, userID string, req models.ImportConfirmRequest) (*models.ImportConfirmResult, error) {
+	if m.confirmFunc != nil {
+		return m.confirmFunc(ctx, userID, req)
+	}
+	return &models.ImportConfirmResult{}, nil
+}
+
+// newWishlistImportRouter mounts the handler on the cmd/server routes with
+// userID injected in place of the auth middleware.
+func newWishlistImportRouter(service services.WishlistImportServiceInterface, userID string) http.Handler {
+	handler := NewWishlistImportHandler(service)
+	r := chi.NewRouter()
+	r.Route("/api/v1/wishlist", func(r chi.Router) {
+		r.Use(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ctx := context.WithValue(r.Context(), middleware.UserIDKey, userID)
+				next.ServeHTTP(w, r.WithContext(ctx))
+			})
+		})
+		r.Post("/import/text", handler.PreviewText)
+		r.Post("/import/text/confirm", handler.ConfirmImport)
+	})
+	return r
+}
+
+func TestWishlistImportHandler_PreviewText(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		body           string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "successful preview", userID: "user-123", body: `{"text":"2x Soma Prime"}`, expectedStatus: http.StatusOK},
+		{name: "unauthorized - no user ID", userID: "", body: `{"text":"Soma"}`, expectedStatus: http.StatusUnauthorized},
+		{name: "invalid body", userID: "user-123", body: `{`, expectedStatus: http.StatusBadRequest},
+		{name: "empty text", userID: "user-123", body: `{"text":""}`, mockError: services.ErrImportEmpty, expectedStatus: http.StatusBadRequest},
+		{name: "too large", userID: "user-123", body: `{"text":"Soma"}`, mockError: services.ErrImportTooLarge, expectedStatus: http.StatusBadRequest},
+		{name: "service error", userID: "user-123", body: `{"text":"Soma"}`, mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotText string
+			mockService := &mockWishlistImportService{
+				previewTextFunc: func(ctx context.Context, text string) (*models.ImportPreview, error) {
+					gotText = text
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return &models.ImportPreview{
+						Matched: []models.ImportMatch{{
+							Line: 1, Input: "Soma Prime", Quantity: 2, MatchType: models.ImportMatchExact,
+							Item: models.ImportCandidate{UniqueName: "/Lotus/Weapons/SomaPrime", Name: "Soma Prime"},
+						}},
+					}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/wishlist/import/text", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			newWishlistImportRouter(mockService, tt.userID).ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			if gotText != "2x Soma Prime" {
+				t.Errorf("expected text to be passed through, got %q", gotText)
+			}
+			var preview dto.ImportPreview
+			if err := json.NewDecoder(rec.Body).Decode(&preview); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if len(preview.Matched) != 1 || preview.Matched[0].Item.UniqueName != "/Lotus/Weapons/SomaPrime" {
+				t.Errorf("unexpected preview: %+v", preview)
+			}
+			if preview.Ambiguous == nil || preview.Unmatched == nil {
+				t.Errorf("expected empty lists to encode as [], got %+v", preview)
+			}
+		})
+	}
+}
+
+func TestWishlistImportHandler_ConfirmImport(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		body           string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "successful confirm", userID: "user-123", body: `{"items":[{"uniqueName":"/Lotus/Weapons/SomaPrime","quantity":2}]}`, expectedStatus: http.StatusOK},
+		{name: "unauthorized - no user ID", userID: "", body: `{"items":[]}`, expectedStatus: http.StatusUnauthorized},
+		{name: "invalid body", userID: "user-123", body: `{"items":{}}`, expectedStatus: http.StatusBadRequest},
+		{name: "no items", userID: "user-123", body: `{"items":[]}`, mockError: services.ErrImportEmpty, expectedStatus: http.StatusBadRequest},
+		{name: "too many items", userID: "user-123", body: `{"items":[]}`, mockError: services.ErrImportTooLarge, expectedStatus: http.StatusBadRequest},
+		{name: "service error", userID: "user-123", body: `{"items":[]}`, mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotReq models.ImportConfirmRequest
+			mockService := &mockWishlistImportService{
+				confirmFunc: func(ctx context.Context, userID string, req models.ImportConfirmRequest) (*models.ImportConfirmResult, error) {
+					gotReq = req
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return &models.ImportConfirmResult{
+						Results: []models.ImportItemResult{{UniqueName: "/Lotus/Weapons/SomaPrime", Quantity: 2, Status: models.ImportStatusAdded}},
+					}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/wishlist/import/text/confirm", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			newWishlistImportRouter(mockService, tt.userID).ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			if len(gotReq.Items) != 1 || gotReq.Items[0].Quantity != 2 {
+				t.Errorf("unexpected request passed to service: %+v", gotReq)
+			}
+			var result dto.ImportConfirmResult
+			if err := json.NewDecoder(rec.Body).Decode(&result); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if len(result.Results) != 1 || result.Results[0].Status != models.ImportStatusAdded || result.Results[0].PendingChange != nil {
+				t.Errorf("unexpected result: %+v", result)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+	"github.com/graytonio/warframe-wishlist/internal/middleware"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"github.com/graytonio/warframe-wishlist/pkg/response"
+)
+
+type WishlistTemplateHandler struct {
+	templateService services.WishlistTemplateServiceInterface
+}
+
+func NewWishlistTemplateHandler(templateService services.WishlistTemplateServiceInterface) *WishlistTemplateHandler {
+	return &WishlistTemplateHandler{templateService: templateService}
+}
+
+// ListTemplates lists the caller's wishlist templates.
+func (h *WishlistTemplateHandler) ListTemplates(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: ListWishlistTemplates called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: ListWishlistTemplates - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	templates, err := h.templateService.ListTemplates(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "handler: ListWishlistTemplates - failed to list templates", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to list templates")
+		return
+	}
+
+	logger.Info(ctx, "handler: ListWishlistTemplates - success", "count", len(templates))
+	response.JSON(w, http.StatusOK, dto.NewWishlistTemplates(templates))
+}
+
+func (h *WishlistTemplateHandler) CreateTemplate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: CreateWishlistTemplate called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: CreateWishlistTemplate - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	var req dto.WishlistTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn(ctx, "handler: CreateWishlistTemplate - invalid request body", "error", err)
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	template, err := h.templateService.CreateTemplate(ctx, userID, req.ToModel())
+	if err != nil {
+		writeWishlistTemplateError(w, r, "CreateWishlistTemplate", err, "failed to create template")
+		return
+	}
+
+	logger.Info(ctx, "handler: CreateWishlistTemplate - success", "id", template.ID.Hex())
+	response.JSON(w, http.StatusCreated, dto.NewWishlistTemplate(template))
+}
+
+func (h *WishlistTemplateHandler) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: DeleteWishlistTemplate called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: DeleteWishlistTemplate - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	templateID := chi.URLParam(r, "templateID")
+	if err := h.templateService.DeleteTemplate(ctx, userID, templateID); err != nil {
+		writeWishlistTemplateError(w, r, "DeleteWishlistTemplate", err, "failed to delete template")
+		return
+	}
+
+	logger.Info(ctx, "handler: DeleteWishlistTemplate - success", "id", templateID)
+	response.JSON(w, http.StatusOK, map[string]string{
+		"message": "template deleted",
+	})
+}
+
+// ApplyTemplate adds a template's items to the caller's wishlist, with
+// quantities worked out from the variables given, and reports each item as
+// a confirmed text import does.
+func (h *WishlistTemplateHandler) ApplyTemplate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: ApplyWishlistTemplate called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: ApplyWishlistTemplate - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	// An empty body applies the template with every default.
+	var req dto.ApplyTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		logger.Warn(ctx, "handler: ApplyWishlistTemplate - invalid request body", "error", err)
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	templateID := chi.URLParam(r, "templateID")
+	result, err := h.templateService.ApplyTemplate(ctx, userID, templateID, req.Variables)
+	if err != nil {
+		writeWishlistTemplateError(w, r, "ApplyWishlistTemplate", err, "failed to apply template")
+		return
+	}
+
+	logger.Info(ctx, "handler: ApplyWishlistTemplate - success", "id", templateID, "count", len(result.Results))
+	response.JSON(w, http.StatusOK, dto.NewImportConfirmResult(result))
+}
+
+func writeWishlistTemplateError(w http.ResponseWriter, r *http.Request, name string, err error, fallback string) {
+	ctx := r.Context()
+	if validationFailed(w, r, name, err) {
+		return
+	}
+
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrImportEmpty):
+		status = http.StatusBadRequest
+	case errors.Is(err, services.ErrWishlistTemplateNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrTooManyWishlistTemplates), errors.Is(err, services.ErrWishlistFull):
+		status = http.StatusConflict
+	}
+
+	if status == http.StatusInternalServerError {
+		logger.Error(ctx, "handler: "+name+" - "+fallback, "error", err)
+		response.Error(w, status, fallback)
+		return
+	}
+	logger.Warn(ctx, "handler: "+name+" - request rejected", "error", err)
+	rejected(w, status, err)
+}
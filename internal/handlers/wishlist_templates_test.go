@@ -0,0 +1,260 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/graytonio/warframe-wishlist/internal/middleware"
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+	"github.com/graytonio/warframe-wishlist/pkg/response"
+)
+
+// newWishlistTemplateRouter mounts the template routes as main does, with
+// userID injected in place of the auth middleware.
+func newWishlistTemplateRouter(service services.WishlistTemplateServiceInterface, userID string) http.Handler {
+	handler := NewWishlistTemplateHandler(service)
+	r := chi.NewRouter()
+	r.Route("/api/v1/wishlist/templates", func(r chi.Router) {
+		r.Use(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ctx := context.WithValue(r.Context(), middleware.UserIDKey, userID)
+				next.ServeHTTP(w, r.WithContext(ctx))
+			})
+		})
+		r.Get("/", handler.ListTemplates)
+		r.Post("/", handler.CreateTemplate)
+		r.Delete("/{templateID}", handler.DeleteTemplate)
+		r.Post("/{templateID}/apply", handler.ApplyTemplate)
+	})
+	return r
+}
+
+// templateVariablesInvalid is the error ApplyTemplate returns for a missing
+// variable.
+func templateVariablesInvalid() error {
+	var problems services.ValidationError
+	problems.Add("variables.frames", "", fmt.Errorf("%w: frames is required", services.ErrInvalidTemplateVariables))
+	return problems.Err()
+}
+
+func TestWishlistTemplateHandler_ListTemplates(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "success", userID: "user-123", expectedStatus: http.StatusOK},
+		{name: "unauthorized - no user ID", userID: "", expectedStatus: http.StatusUnauthorized},
+		{name: "service error", userID: "user-123", mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.MockWishlistTemplateService{
+				ListTemplatesFunc: func(ctx context.Context, userID string) ([]models.WishlistTemplate, error) {
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return []models.WishlistTemplate{{UserID: userID, Name: "New frame", Items: []models.TemplateItem{{UniqueName: "/Lotus/Forma", Quantity: "forma"}}}}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/wishlist/templates", nil)
+			rec := httptest.NewRecorder()
+			newWishlistTemplateRouter(service, tt.userID).ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			var body struct {
+				Templates []struct {
+					Name  string `json:"name"`
+					Items []struct {
+						Quantity string `json:"quantity"`
+					} `json:"items"`
+				} `json:"templates"`
+			}
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if len(body.Templates) != 1 || body.Templates[0].Name != "New frame" || body.Templates[0].Items[0].Quantity != "forma" {
+				t.Errorf("expected the template, got %+v", body)
+			}
+		})
+	}
+}
+
+func TestWishlistTemplateHandler_CreateTemplate(t *testing.T) {
+	invalidTemplate := func() error {
+		var problems services.ValidationError
+		problems.Add("items[0].quantity", "/Lotus/Forma", fmt.Errorf("%w: quantity uses undeclared variable riven", services.ErrInvalidWishlistTemplate))
+		return problems.Err()
+	}
+
+	tests := []struct {
+		name           string
+		body           string
+		mockError      error
+		expectedStatus int
+		expectedCode   response.Code
+	}{
+		{name: "created", body: `{"name":"New frame","variables":[{"name":"forma","default":3}],"items":[{"uniqueName":"/Lotus/Forma","quantity":"2*forma"}]}`, expectedStatus: http.StatusCreated},
+		{name: "invalid body", body: `{`, expectedStatus: http.StatusBadRequest},
+		{name: "invalid template", body: `{"name":"New frame"}`, mockError: invalidTemplate(), expectedStatus: http.StatusUnprocessableEntity, expectedCode: response.CodeInvalidWishlistTemplate},
+		{name: "too many templates", body: `{"name":"New frame"}`, mockError: services.ErrTooManyWishlistTemplates, expectedStatus: http.StatusConflict, expectedCode: response.CodeTooManyWishlistTemplates},
+		{name: "service error", body: `{"name":"New frame"}`, mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got models.WishlistTemplateRequest
+			service := &mocks.MockWishlistTemplateService{
+				CreateTemplateFunc: func(ctx context.Context, userID string, req models.WishlistTemplateRequest) (*models.WishlistTemplate, error) {
+					got = req
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return &models.WishlistTemplate{UserID: userID, Name: req.Name, Variables: req.Variables, Items: req.Items}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/wishlist/templates", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			newWishlistTemplateRouter(service, "user-123").ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if tt.expectedCode != "" {
+				var body response.ErrorResponse
+				if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				code := body.Code
+				if len(body.Fields) > 0 {
+					code = body.Fields[0].Code
+				}
+				if code != tt.expectedCode {
+					t.Errorf("expected code %q, got %+v", tt.expectedCode, body)
+				}
+			}
+			if tt.name == "created" {
+				expected := models.WishlistTemplateRequest{
+					Name:      "New frame",
+					Variables: []models.TemplateVariable{{Name: "forma", Default: got.Variables[0].Default}},
+					Items:     []models.TemplateItem{{UniqueName: "/Lotus/Forma", Quantity: "2*forma"}},
+				}
+				if !reflect.DeepEqual(got, expected) || got.Variables[0].Default == nil || *got.Variables[0].Default != 3 {
+					t.Errorf("expected the template to be passed through, got %+v", got)
+				}
+			}
+		})
+	}
+}
+
+func TestWishlistTemplateHandler_DeleteTemplate(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "deleted", expectedStatus: http.StatusOK},
+		{name: "not found", mockError: services.ErrWishlistTemplateNotFound, expectedStatus: http.StatusNotFound},
+		{name: "service error", mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotID string
+			service := &mocks.MockWishlistTemplateService{
+				DeleteTemplateFunc: func(ctx context.Context, userID, id string) error {
+					gotID = id
+					return tt.mockError
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/wishlist/templates/template-1", nil)
+			rec := httptest.NewRecorder()
+			newWishlistTemplateRouter(service, "user-123").ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if gotID != "template-1" {
+				t.Errorf("expected template-1, got %q", gotID)
+			}
+		})
+	}
+}
+
+func TestWishlistTemplateHandler_ApplyTemplate(t *testing.T) {
+	tests := []struct {
+		name              string
+		body              string
+		mockError         error
+		expectedStatus    int
+		expectedVariables map[string]int
+	}{
+		{name: "applied", body: `{"variables":{"frames":2}}`, expectedStatus: http.StatusOK, expectedVariables: map[string]int{"frames": 2}},
+		{name: "empty body uses defaults", body: "", expectedStatus: http.StatusOK},
+		{name: "invalid body", body: `{"variables":{"frames":"two"}}`, expectedStatus: http.StatusBadRequest},
+		{name: "invalid variables", body: `{}`, mockError: templateVariablesInvalid(), expectedStatus: http.StatusUnprocessableEntity},
+		{name: "every quantity zero", body: `{}`, mockError: services.ErrImportEmpty, expectedStatus: http.StatusBadRequest},
+		{name: "not found", body: `{}`, mockError: services.ErrWishlistTemplateNotFound, expectedStatus: http.StatusNotFound},
+		{name: "wishlist full", body: `{}`, mockError: fmt.Errorf("%w: at most 2 items", services.ErrWishlistFull), expectedStatus: http.StatusConflict},
+		{name: "service error", body: `{}`, mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotID string
+			var gotVariables map[string]int
+			service := &mocks.MockWishlistTemplateService{
+				ApplyTemplateFunc: func(ctx context.Context, userID, id string, variables map[string]int) (*models.ImportConfirmResult, error) {
+					gotID, gotVariables = id, variables
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return &models.ImportConfirmResult{Results: []models.ImportItemResult{{UniqueName: "/Lotus/Forma", Quantity: 6, Status: models.ImportStatusAdded}}}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/wishlist/templates/template-1/apply", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			newWishlistTemplateRouter(service, "user-123").ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if tt.expectedStatus == http.StatusBadRequest && tt.mockError == nil {
+				return
+			}
+			if gotID != "template-1" || !reflect.DeepEqual(gotVariables, tt.expectedVariables) {
+				t.Errorf("expected template-1 with %v, got %q with %v", tt.expectedVariables, gotID, gotVariables)
+			}
+			if tt.name == "invalid variables" {
+				var body response.ErrorResponse
+				if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if len(body.Fields) != 1 || body.Fields[0].Field != "variables.frames" || body.Fields[0].Code != response.CodeInvalidTemplateVariables {
+					t.Errorf("expected the variable to be reported, got %+v", body)
+				}
+			}
+		})
+	}
+}
@@ -5,22 +5,31 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/graytonio/warframe-wishlist/internal/deprecation"
+	"github.com/graytonio/warframe-wishlist/internal/dto"
 	"github.com/graytonio/warframe-wishlist/internal/middleware"
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
 	"github.com/graytonio/warframe-wishlist/internal/models"
 	"github.com/graytonio/warframe-wishlist/internal/services"
 )
 
 type mockWishlistService struct {
-	getWishlistFunc    func(ctx context.Context, userID string) (*models.Wishlist, error)
-	addItemFunc        func(ctx context.Context, userID string, req models.AddItemRequest) error
-	removeItemFunc     func(ctx context.Context, userID, uniqueName string) error
-	updateQuantityFunc func(ctx context.Context, userID, uniqueName string, quantity int) error
+	getWishlistFunc         func(ctx context.Context, userID string) (*models.Wishlist, error)
+	addItemFunc             func(ctx context.Context, userID string, req models.AddItemRequest) error
+	removeItemFunc          func(ctx context.Context, userID, uniqueName string) error
+	updateQuantityFunc      func(ctx context.Context, userID, uniqueName string, quantity int) error
+	setItemLinksFunc        func(ctx context.Context, userID, uniqueName string, links []models.SourceLinkRequest) ([]models.SourceLink, error)
+	setItemRecipeFunc       func(ctx context.Context, userID, uniqueName, recipeID string) error
+	setItemProgressFunc     func(ctx context.Context, userID, uniqueName string, progress []models.ComponentProgressRequest) (*models.WishlistItem, error)
+	getExpandedWishlistFunc func(ctx context.Context, userID string) (*models.ExpandedWishlist, error)
 }
 
 func (m *mockWishlistService) GetWishlist(ctx context.Context, userID string) (*models.Wishlist, error) {
@@ -51,6 +60,34 @@ func (m *mockWishlistService) UpdateQuantity(ctx context.Context, userID, unique
 	return nil
 }
 
+func (m *mockWishlistService) SetItemLinks(ctx context.Context, userID, uniqueName string, links []models.SourceLinkRequest) ([]models.SourceLink, error) {
+	if m.setItemLinksFunc != nil {
+		return m.setItemLinksFunc(ctx, userID, uniqueName, links)
+	}
+	return nil, nil
+}
+
+func (m *mockWishlistService) SetItemRecipe(ctx context.Context, userID, uniqueName, recipeID string) error {
+	if m.setItemRecipeFunc != nil {
+		return m.setItemRecipeFunc(ctx, userID, uniqueName, recipeID)
+	}
+	return nil
+}
+
+func (m *mockWishlistService) SetItemProgress(ctx context.Context, userID, uniqueName string, progress []models.ComponentProgressRequest) (*models.WishlistItem, error) {
+	if m.setItemProgressFunc != nil {
+		return m.setItemProgressFunc(ctx, userID, uniqueName, progress)
+	}
+	return &models.WishlistItem{UniqueName: uniqueName}, nil
+}
+
+func (m *mockWishlistService) GetExpandedWishlist(ctx context.Context, userID string) (*models.ExpandedWishlist, error) {
+	if m.getExpandedWishlistFunc != nil {
+		return m.getExpandedWishlistFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
 type mockMaterialResolver struct {
 	getMaterialsFunc func(ctx context.Context, userID string) (*models.MaterialsResponse, error)
 }
@@ -136,14 +173,14 @@ func TestWishlistHandler_AddItem(t *testing.T) {
 	tests := []struct {
 		name           string
 		userID         string
-		requestBody    models.AddItemRequest
+		requestBody    dto.AddItemRequest
 		mockError      error
 		expectedStatus int
 	}{
 		{
 			name:   "successful add item",
 			userID: "user-123",
-			requestBody: models.AddItemRequest{
+			requestBody: dto.AddItemRequest{
 				UniqueName: "/Lotus/Item1",
 				Quantity:   1,
 			},
@@ -153,31 +190,45 @@ func TestWishlistHandler_AddItem(t *testing.T) {
 		{
 			name:           "unauthorized - no user ID",
 			userID:         "",
-			requestBody:    models.AddItemRequest{UniqueName: "/Lotus/Item1"},
+			requestBody:    dto.AddItemRequest{UniqueName: "/Lotus/Item1"},
 			mockError:      nil,
 			expectedStatus: http.StatusUnauthorized,
 		},
 		{
 			name:           "item not found",
 			userID:         "user-123",
-			requestBody:    models.AddItemRequest{UniqueName: "/Lotus/Nonexistent"},
+			requestBody:    dto.AddItemRequest{UniqueName: "/Lotus/Nonexistent"},
 			mockError:      services.ErrItemNotFound,
 			expectedStatus: http.StatusNotFound,
 		},
 		{
 			name:           "item already in wishlist",
 			userID:         "user-123",
-			requestBody:    models.AddItemRequest{UniqueName: "/Lotus/Item1"},
+			requestBody:    dto.AddItemRequest{UniqueName: "/Lotus/Item1"},
 			mockError:      services.ErrItemAlreadyInWishlist,
 			expectedStatus: http.StatusConflict,
 		},
+		{
+			name:           "held for manager approval",
+			userID:         "user-123",
+			requestBody:    dto.AddItemRequest{UniqueName: "/Lotus/Item1", Quantity: 20},
+			mockError:      &services.ApprovalRequiredError{Change: &models.PendingChange{UniqueName: "/Lotus/Item1", Quantity: 20}},
+			expectedStatus: http.StatusAccepted,
+		},
 		{
 			name:           "missing uniqueName",
 			userID:         "user-123",
-			requestBody:    models.AddItemRequest{UniqueName: ""},
+			requestBody:    dto.AddItemRequest{UniqueName: ""},
 			mockError:      nil,
 			expectedStatus: http.StatusBadRequest,
 		},
+		{
+			name:           "negative quantity",
+			userID:         "user-123",
+			requestBody:    dto.AddItemRequest{UniqueName: "/Lotus/Item1", Quantity: -1},
+			mockError:      errors.New("service should not be called"),
+			expectedStatus: http.StatusBadRequest,
+		},
 	}
 
 	for _, tt := range tests {
@@ -205,6 +256,65 @@ func TestWishlistHandler_AddItem(t *testing.T) {
 	}
 }
 
+func TestWishlistHandler_AddItem_QuantitySuggestion(t *testing.T) {
+	tests := []struct {
+		name               string
+		suggestion         *models.QuantitySuggestion
+		suggestErr         error
+		expectedSuggestion bool
+	}{
+		{
+			name:               "used by other items",
+			suggestion:         &models.QuantitySuggestion{UniqueName: "/Lotus/Catalyst", Suggested: 3, UsedBy: []models.QuantityUsage{{UniqueName: "/Lotus/Ash", Name: "Ash", Count: 3}}},
+			expectedSuggestion: true,
+		},
+		{
+			name: "not used by other items",
+		},
+		{
+			name:       "suggestion fails",
+			suggestErr: errors.New("connection refused"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewWishlistHandler(&mockWishlistService{}, &mockMaterialResolver{})
+			handler.SetQuantitySuggester(&mocks.MockQuantitySuggester{
+				SuggestQuantityFunc: func(ctx context.Context, userID, uniqueName string) (*models.QuantitySuggestion, error) {
+					if userID != "user-123" || uniqueName != "/Lotus/Catalyst" {
+						t.Errorf("unexpected suggestion request for %s, %s", userID, uniqueName)
+					}
+					return tt.suggestion, tt.suggestErr
+				},
+			})
+
+			body, _ := json.Marshal(dto.AddItemRequest{UniqueName: "/Lotus/Catalyst", Quantity: 1})
+			req := createAuthenticatedRequest(http.MethodPost, "/api/v1/wishlist", body, "user-123")
+			rec := httptest.NewRecorder()
+
+			handler.AddItem(rec, req)
+
+			if rec.Code != http.StatusCreated {
+				t.Fatalf("expected status %d, got %d", http.StatusCreated, rec.Code)
+			}
+			var added dto.AddedItem
+			if err := json.Unmarshal(rec.Body.Bytes(), &added); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if added.Message != "item added to wishlist" {
+				t.Errorf("unexpected message %q", added.Message)
+			}
+			if tt.expectedSuggestion != (added.Suggestion != nil) {
+				t.Fatalf("expected suggestion %v, got %+v", tt.expectedSuggestion, added.Suggestion)
+			}
+			if added.Suggestion != nil && (added.Suggestion.Suggested != 3 || len(added.Suggestion.UsedBy) != 1 || added.Suggestion.UsedBy[0].Name != "Ash") {
+				t.Errorf("unexpected suggestion %+v", added.Suggestion)
+			}
+		})
+	}
+}
+
 func TestWishlistHandler_RemoveItem(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -270,7 +380,7 @@ func TestWishlistHandler_UpdateQuantity(t *testing.T) {
 		name           string
 		userID         string
 		uniqueName     string
-		requestBody    models.UpdateQuantityRequest
+		requestBody    dto.UpdateQuantityRequest
 		mockError      error
 		expectedStatus int
 	}{
@@ -278,7 +388,7 @@ func TestWishlistHandler_UpdateQuantity(t *testing.T) {
 			name:           "successful update quantity",
 			userID:         "user-123",
 			uniqueName:     "Lotus-Item1",
-			requestBody:    models.UpdateQuantityRequest{Quantity: 5},
+			requestBody:    dto.UpdateQuantityRequest{Quantity: 5},
 			mockError:      nil,
 			expectedStatus: http.StatusOK,
 		},
@@ -286,7 +396,7 @@ func TestWishlistHandler_UpdateQuantity(t *testing.T) {
 			name:           "unauthorized - no user ID",
 			userID:         "",
 			uniqueName:     "Lotus-Item1",
-			requestBody:    models.UpdateQuantityRequest{Quantity: 5},
+			requestBody:    dto.UpdateQuantityRequest{Quantity: 5},
 			mockError:      nil,
 			expectedStatus: http.StatusUnauthorized,
 		},
@@ -294,7 +404,7 @@ func TestWishlistHandler_UpdateQuantity(t *testing.T) {
 			name:           "item not in wishlist",
 			userID:         "user-123",
 			uniqueName:     "Lotus-Item1",
-			requestBody:    models.UpdateQuantityRequest{Quantity: 5},
+			requestBody:    dto.UpdateQuantityRequest{Quantity: 5},
 			mockError:      services.ErrItemNotInWishlist,
 			expectedStatus: http.StatusNotFound,
 		},
@@ -302,10 +412,18 @@ func TestWishlistHandler_UpdateQuantity(t *testing.T) {
 			name:           "invalid quantity",
 			userID:         "user-123",
 			uniqueName:     "Lotus-Item1",
-			requestBody:    models.UpdateQuantityRequest{Quantity: 0},
+			requestBody:    dto.UpdateQuantityRequest{Quantity: 0},
 			mockError:      services.ErrInvalidQuantity,
 			expectedStatus: http.StatusBadRequest,
 		},
+		{
+			name:           "held for manager approval",
+			userID:         "user-123",
+			uniqueName:     "Lotus-Item1",
+			requestBody:    dto.UpdateQuantityRequest{Quantity: 20},
+			mockError:      &services.ApprovalRequiredError{Change: &models.PendingChange{UniqueName: "Lotus-Item1", Quantity: 20}},
+			expectedStatus: http.StatusAccepted,
+		},
 	}
 
 	for _, tt := range tests {
@@ -455,3 +573,563 @@ func TestWishlistHandler_GetWishlist_ReturnsCorrectData(t *testing.T) {
 		t.Errorf("expected %d items, got %d", len(expectedWishlist.Items), len(response.Items))
 	}
 }
+
+func TestWishlistHandler_GetWishlist_ExpandItems(t *testing.T) {
+	mockService := &mockWishlistService{
+		getWishlistFunc: func(ctx context.Context, userID string) (*models.Wishlist, error) {
+			t.Error("expected expanded view not to call GetWishlist")
+			return nil, nil
+		},
+		getExpandedWishlistFunc: func(ctx context.Context, userID string) (*models.ExpandedWishlist, error) {
+			return &models.ExpandedWishlist{
+				UserID: userID,
+				Items: []models.ExpandedWishlistItem{{
+					WishlistItem: models.WishlistItem{
+						UniqueName: "/Lotus/Item1",
+						Quantity:   1,
+						Links:      []models.SourceLink{{URL: "https://example.com/guide", Host: "example.com"}},
+					},
+					Item: &models.ItemSearchResult{UniqueName: "/Lotus/Item1", Name: "Item One"},
+				}},
+			}, nil
+		},
+	}
+
+	handler := NewWishlistHandler(mockService, &mockMaterialResolver{})
+
+	req := createAuthenticatedRequest(http.MethodGet, "/api/v1/wishlist?expand=items", nil, "user-123")
+	rec := httptest.NewRecorder()
+
+	handler.GetWishlist(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	var response models.ExpandedWishlist
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(response.Items) != 1 || response.Items[0].Item == nil || response.Items[0].Item.Name != "Item One" {
+		t.Errorf("unexpected items: %+v", response.Items)
+	}
+	if len(response.Items[0].Links) != 1 || response.Items[0].Links[0].Host != "example.com" {
+		t.Errorf("unexpected links: %+v", response.Items[0].Links)
+	}
+}
+
+func TestWishlistHandler_SetItemLinks(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		body           string
+		mockError      error
+		expectedStatus int
+	}{
+		{
+			name:           "successful update",
+			userID:         "user-123",
+			body:           `{"links":[{"url":"https://example.com/guide","title":"Guide"}]}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "unauthorized - no user ID",
+			userID:         "",
+			body:           `{"links":[]}`,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "invalid body",
+			userID:         "user-123",
+			body:           `{`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid link",
+			userID:         "user-123",
+			body:           `{"links":[{"url":"javascript:alert(1)"}]}`,
+			mockError:      fmt.Errorf("links[0]: %w", services.ErrInvalidLink),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "too many links",
+			userID:         "user-123",
+			body:           `{"links":[]}`,
+			mockError:      services.ErrTooManyLinks,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "item not in wishlist",
+			userID:         "user-123",
+			body:           `{"links":[]}`,
+			mockError:      services.ErrItemNotInWishlist,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "service error",
+			userID:         "user-123",
+			body:           `{"links":[]}`,
+			mockError:      errors.New("database error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotUniqueName string
+			mockService := &mockWishlistService{
+				setItemLinksFunc: func(ctx context.Context, userID, uniqueName string, links []models.SourceLinkRequest) ([]models.SourceLink, error) {
+					gotUniqueName = uniqueName
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return []models.SourceLink{{URL: "https://example.com/guide", Title: "Guide", Host: "example.com"}}, nil
+				},
+			}
+
+			handler := NewWishlistHandler(mockService, &mockMaterialResolver{})
+
+			r := chi.NewRouter()
+			r.Put("/api/v1/wishlist/links/*", func(w http.ResponseWriter, r *http.Request) {
+				ctx := context.WithValue(r.Context(), middleware.UserIDKey, tt.userID)
+				handler.SetItemLinks(w, r.WithContext(ctx))
+			})
+
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/wishlist/links/Lotus/Item1", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if tt.expectedStatus == http.StatusOK && gotUniqueName != "/Lotus/Item1" {
+				t.Errorf("expected uniqueName /Lotus/Item1, got %q", gotUniqueName)
+			}
+		})
+	}
+}
+
+func TestWishlistHandler_SetItemRecipe(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		body           string
+		mockError      error
+		expectedStatus int
+	}{
+		{
+			name:           "successful update",
+			userID:         "user-123",
+			body:           `{"recipeId":"alloy"}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "unauthorized - no user ID",
+			userID:         "",
+			body:           `{"recipeId":"alloy"}`,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "invalid body",
+			userID:         "user-123",
+			body:           `{`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown recipe",
+			userID:         "user-123",
+			body:           `{"recipeId":"missing"}`,
+			mockError:      services.ErrRecipeNotFound,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "item not in wishlist",
+			userID:         "user-123",
+			body:           `{"recipeId":""}`,
+			mockError:      services.ErrItemNotInWishlist,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "service error",
+			userID:         "user-123",
+			body:           `{"recipeId":"alloy"}`,
+			mockError:      errors.New("database error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotUniqueName, gotRecipeID string
+			mockService := &mockWishlistService{
+				setItemRecipeFunc: func(ctx context.Context, userID, uniqueName, recipeID string) error {
+					gotUniqueName, gotRecipeID = uniqueName, recipeID
+					return tt.mockError
+				},
+			}
+
+			handler := NewWishlistHandler(mockService, &mockMaterialResolver{})
+
+			r := chi.NewRouter()
+			r.Put("/api/v1/wishlist/recipe/*", func(w http.ResponseWriter, r *http.Request) {
+				ctx := context.WithValue(r.Context(), middleware.UserIDKey, tt.userID)
+				handler.SetItemRecipe(w, r.WithContext(ctx))
+			})
+
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/wishlist/recipe/Lotus/Item1", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			if gotUniqueName != "/Lotus/Item1" || gotRecipeID != "alloy" {
+				t.Errorf("expected /Lotus/Item1 and alloy, got %q and %q", gotUniqueName, gotRecipeID)
+			}
+
+			var response dto.ItemRecipe
+			if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if response.UniqueName != "/Lotus/Item1" || response.RecipeID != "alloy" {
+				t.Errorf("unexpected response %+v", response)
+			}
+		})
+	}
+}
+
+func TestWishlistHandler_SetItemProgress(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		body           string
+		mockError      error
+		expectedStatus int
+	}{
+		{
+			name:           "successful update",
+			userID:         "user-123",
+			body:           `{"components":[{"uniqueName":"/Lotus/Chassis","status":"built"}]}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "unauthorized - no user ID",
+			userID:         "",
+			body:           `{"components":[]}`,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "invalid body",
+			userID:         "user-123",
+			body:           `{`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "not a component",
+			userID:         "user-123",
+			body:           `{"components":[{"uniqueName":"/Lotus/Other","status":"built"}]}`,
+			mockError:      services.ErrInvalidProgress,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "item not in wishlist",
+			userID:         "user-123",
+			body:           `{"components":[]}`,
+			mockError:      services.ErrItemNotInWishlist,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "service error",
+			userID:         "user-123",
+			body:           `{"components":[]}`,
+			mockError:      errors.New("database error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotUniqueName string
+			var gotProgress []models.ComponentProgressRequest
+			mockService := &mockWishlistService{
+				setItemProgressFunc: func(ctx context.Context, userID, uniqueName string, progress []models.ComponentProgressRequest) (*models.WishlistItem, error) {
+					gotUniqueName, gotProgress = uniqueName, progress
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return &models.WishlistItem{
+						UniqueName: uniqueName,
+						Progress:   []models.ComponentProgress{{UniqueName: "/Lotus/Chassis", Status: models.ComponentBuilt, Count: 1}},
+						Completion: 50,
+					}, nil
+				},
+			}
+
+			handler := NewWishlistHandler(mockService, &mockMaterialResolver{})
+
+			r := chi.NewRouter()
+			r.Put("/api/v1/wishlist/progress/*", func(w http.ResponseWriter, r *http.Request) {
+				ctx := context.WithValue(r.Context(), middleware.UserIDKey, tt.userID)
+				handler.SetItemProgress(w, r.WithContext(ctx))
+			})
+
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/wishlist/progress/Lotus/Item1", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			if gotUniqueName != "/Lotus/Item1" || len(gotProgress) != 1 || gotProgress[0].Status != models.ComponentBuilt {
+				t.Errorf("expected /Lotus/Item1 with one built component, got %q and %+v", gotUniqueName, gotProgress)
+			}
+
+			var response dto.ItemProgress
+			if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if response.UniqueName != "/Lotus/Item1" || response.Completion != 50 || len(response.Progress) != 1 {
+				t.Errorf("unexpected response %+v", response)
+			}
+		})
+	}
+}
+
+func TestWishlistHandler_GetWishlist_Deprecation(t *testing.T) {
+	mockService := &mockWishlistService{
+		getWishlistFunc: func(ctx context.Context, userID string) (*models.Wishlist, error) {
+			return &models.Wishlist{UserID: userID}, nil
+		},
+		getExpandedWishlistFunc: func(ctx context.Context, userID string) (*models.ExpandedWishlist, error) {
+			return &models.ExpandedWishlist{UserID: userID}, nil
+		},
+	}
+	tracker, err := deprecation.NewTracker(deprecation.WishlistBareItems.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	handler := NewWishlistHandler(mockService, &mockMaterialResolver{})
+	handler.SetDeprecations(tracker)
+
+	tests := []struct {
+		target     string
+		deprecated bool
+	}{
+		{target: "/api/v1/wishlist", deprecated: true},
+		{target: "/api/v1/wishlist?expand=items", deprecated: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			req := createAuthenticatedRequest(http.MethodGet, tt.target, nil, "user-123")
+			rec := httptest.NewRecorder()
+
+			handler.GetWishlist(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+			}
+			if got := rec.Header().Get(deprecation.DeprecationHeader) != ""; got != tt.deprecated {
+				t.Errorf("expected deprecated %v, got headers %v", tt.deprecated, rec.Header())
+			}
+		})
+	}
+	if usage := tracker.Usage(); usage[0].Total != 1 {
+		t.Errorf("expected one use recorded, got %+v", usage)
+	}
+}
+
+func TestWishlistHandler_GetWishlist_Onboarding(t *testing.T) {
+	tests := []struct {
+		name      string
+		isNew     bool
+		mockError error
+		expected  string
+	}{
+		{name: "new user", isNew: true, expected: "true"},
+		{name: "returning user"},
+		{name: "onboarding error", mockError: errors.New("database error")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mockWishlistService{
+				getWishlistFunc: func(ctx context.Context, userID string) (*models.Wishlist, error) {
+					return &models.Wishlist{UserID: userID}, nil
+				},
+			}
+			var gotUserID string
+			handler := NewWishlistHandler(mockService, &mockMaterialResolver{})
+			handler.SetOnboarding(&mocks.MockOnboardingService{
+				OnboardFunc: func(ctx context.Context, userID string) (bool, error) {
+					gotUserID = userID
+					return tt.isNew, tt.mockError
+				},
+			})
+
+			rec := httptest.NewRecorder()
+			handler.GetWishlist(rec, createAuthenticatedRequest(http.MethodGet, "/api/v1/wishlist", nil, "user-123"))
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+			}
+			if gotUserID != "user-123" {
+				t.Errorf("expected onboarding for user-123, got %q", gotUserID)
+			}
+			if got := rec.Header().Get(OnboardingHeader); got != tt.expected {
+				t.Errorf("expected %s %q, got %q", OnboardingHeader, tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestWishlistHandler_GetWishlist_ETag(t *testing.T) {
+	mockService := &mockWishlistService{
+		getWishlistFunc: func(ctx context.Context, userID string) (*models.Wishlist, error) {
+			return &models.Wishlist{UserID: userID}, nil
+		},
+		getExpandedWishlistFunc: func(ctx context.Context, userID string) (*models.ExpandedWishlist, error) {
+			return &models.ExpandedWishlist{UserID: userID}, nil
+		},
+	}
+	version := "wishlist@1"
+	tracker, err := deprecation.NewTracker(deprecation.WishlistBareItems.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	handler := NewWishlistHandler(mockService, &mockMaterialResolver{})
+	handler.SetDeprecations(tracker)
+	handler.SetVersions(&mocks.MockWishlistVersionService{
+		WishlistVersionFunc: func(ctx context.Context, userID string) (string, error) {
+			return version, nil
+		},
+	})
+
+	for _, target := range []string{"/api/v1/wishlist", "/api/v1/wishlist?expand=items"} {
+		t.Run(target, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.GetWishlist(rec, createAuthenticatedRequest(http.MethodGet, target, nil, "user-123"))
+			etag := rec.Header().Get("ETag")
+			if rec.Code != http.StatusOK || etag == "" {
+				t.Fatalf("expected 200 with an ETag, got %d %q", rec.Code, etag)
+			}
+
+			req := createAuthenticatedRequest(http.MethodGet, target, nil, "user-123")
+			req.Header.Set("If-None-Match", etag)
+			rec = httptest.NewRecorder()
+			handler.GetWishlist(rec, req)
+			if rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+				t.Fatalf("expected empty 304, got %d %q", rec.Code, rec.Body.String())
+			}
+			if rec.Header().Get("ETag") != etag {
+				t.Errorf("expected ETag %q on 304, got %q", etag, rec.Header().Get("ETag"))
+			}
+			deprecated := !strings.Contains(target, "expand")
+			if got := rec.Header().Get(deprecation.DeprecationHeader) != ""; got != deprecated {
+				t.Errorf("expected deprecated %v on 304, got headers %v", deprecated, rec.Header())
+			}
+		})
+	}
+
+	t.Run("changed version", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.GetWishlist(rec, createAuthenticatedRequest(http.MethodGet, "/api/v1/wishlist?expand=items", nil, "user-123"))
+		etag := rec.Header().Get("ETag")
+
+		version = "wishlist@2"
+		req := createAuthenticatedRequest(http.MethodGet, "/api/v1/wishlist?expand=items", nil, "user-123")
+		req.Header.Set("If-None-Match", etag)
+		rec = httptest.NewRecorder()
+		handler.GetWishlist(rec, req)
+		if rec.Code != http.StatusOK || rec.Header().Get("ETag") == etag {
+			t.Errorf("expected 200 with a new ETag, got %d %q", rec.Code, rec.Header().Get("ETag"))
+		}
+	})
+}
+
+func TestWishlistHandler_GetMaterials_ETag(t *testing.T) {
+	tests := []struct {
+		name       string
+		materials  *models.MaterialsResponse
+		versionErr error
+		wantETag   bool
+	}{
+		{
+			name:      "complete",
+			materials: &models.MaterialsResponse{TotalCredits: 25000},
+			wantETag:  true,
+		},
+		{
+			name:      "truncated",
+			materials: &models.MaterialsResponse{Truncated: true},
+		},
+		{
+			name: "degraded",
+			materials: &models.MaterialsResponse{Degradation: models.Degradation{
+				Degraded: []models.DegradedSection{{Section: models.SectionOwnedMaterials, Reason: "unavailable"}},
+			}},
+		},
+		{
+			name:       "version error",
+			materials:  &models.MaterialsResponse{},
+			versionErr: errors.New("database error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolved := 0
+			handler := NewWishlistHandler(&mockWishlistService{}, &mockMaterialResolver{
+				getMaterialsFunc: func(ctx context.Context, userID string) (*models.MaterialsResponse, error) {
+					resolved++
+					return tt.materials, nil
+				},
+			})
+			handler.SetVersions(&mocks.MockWishlistVersionService{
+				MaterialsVersionFunc: func(ctx context.Context, userID string) (string, error) {
+					return "wishlist@1;materials", tt.versionErr
+				},
+			})
+
+			rec := httptest.NewRecorder()
+			handler.GetMaterials(rec, createAuthenticatedRequest(http.MethodGet, "/api/v1/wishlist/materials", nil, "user-123"))
+			etag := rec.Header().Get("ETag")
+			if rec.Code != http.StatusOK {
+				t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+			}
+			if (etag != "") != tt.wantETag {
+				t.Fatalf("expected ETag %v, got %q", tt.wantETag, etag)
+			}
+			if !tt.wantETag {
+				return
+			}
+
+			req := createAuthenticatedRequest(http.MethodGet, "/api/v1/wishlist/materials", nil, "user-123")
+			req.Header.Set("If-None-Match", etag)
+			rec = httptest.NewRecorder()
+			handler.GetMaterials(rec, req)
+			if rec.Code != http.StatusNotModified {
+				t.Errorf("expected status %d, got %d", http.StatusNotModified, rec.Code)
+			}
+			if resolved != 1 {
+				t.Errorf("expected the 304 to skip resolving, resolved %d times", resolved)
+			}
+
+			req = createAuthenticatedRequest(http.MethodGet, "/api/v1/wishlist/materials?format=csv", nil, "user-123")
+			req.Header.Set("If-None-Match", etag)
+			rec = httptest.NewRecorder()
+			handler.GetMaterials(rec, req)
+			if rec.Code != http.StatusOK || rec.Header().Get("ETag") == etag {
+				t.Errorf("expected CSV to have its own ETag, got %d %q", rec.Code, rec.Header().Get("ETag"))
+			}
+		})
+	}
+}
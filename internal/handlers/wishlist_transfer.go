@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+	"github.com/graytonio/warframe-wishlist/internal/middleware"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"github.com/graytonio/warframe-wishlist/pkg/response"
+)
+
+type WishlistTransferHandler struct {
+	transferService services.WishlistTransferServiceInterface
+}
+
+func NewWishlistTransferHandler(transferService services.WishlistTransferServiceInterface) *WishlistTransferHandler {
+	return &WishlistTransferHandler{
+		transferService: transferService,
+	}
+}
+
+// Export returns the user's wishlist and owned blueprints as a JSON document
+// that Import accepts, served as a download.
+func (h *WishlistTransferHandler) Export(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: ExportWishlist called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: ExportWishlist - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	switch format := r.URL.Query().Get("format"); format {
+	case "", "json":
+	case "pdf":
+		h.exportChecklist(w, r, userID)
+		return
+	default:
+		logger.Warn(ctx, "handler: ExportWishlist - unsupported format", "format", format)
+		response.ErrorCode(w, http.StatusBadRequest, response.CodeUnsupportedFormat, "unsupported format: "+format)
+		return
+	}
+
+	doc, err := h.transferService.Export(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "handler: ExportWishlist - failed to export wishlist", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to export wishlist")
+		return
+	}
+
+	logger.Info(ctx, "handler: ExportWishlist - success", "itemCount", len(doc.Items), "blueprintCount", len(doc.OwnedBlueprints))
+	w.Header().Set("Content-Disposition", `attachment; filename="wishlist-export.json"`)
+	response.JSON(w, http.StatusOK, dto.NewWishlistExport(doc))
+}
+
+func (h *WishlistTransferHandler) exportChecklist(w http.ResponseWriter, r *http.Request, userID string) {
+	ctx := r.Context()
+
+	checklist, err := h.transferService.Checklist(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "handler: ExportWishlist - failed to build checklist", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to export wishlist")
+		return
+	}
+
+	writeChecklistPDF(w, r, checklist)
+}
+
+// Import applies an export document sent as the request body. The mode query
+// parameter is "merge" (the default) or "replace"; dryRun=true reports the
+// changes without making them.
+func (h *WishlistTransferHandler) Import(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: ImportWishlist called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: ImportWishlist - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	dryRun := false
+	if raw := r.URL.Query().Get("dryRun"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			logger.Warn(ctx, "handler: ImportWishlist - invalid dryRun", "dryRun", raw)
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidDryRun, "dryRun must be true or false")
+			return
+		}
+		dryRun = parsed
+	}
+
+	var doc dto.WishlistExport
+	if err := json.NewDecoder(r.Body).Decode(&doc); err != nil {
+		logger.Warn(ctx, "handler: ImportWishlist - invalid request body", "error", err)
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	result, err := h.transferService.Import(ctx, userID, models.WishlistDocumentImportRequest{
+		Mode:     r.URL.Query().Get("mode"),
+		DryRun:   dryRun,
+		Document: doc.ToModel(),
+	})
+	if err != nil {
+		writeWishlistTransferError(w, r, "ImportWishlist", err, "failed to import wishlist")
+		return
+	}
+
+	logger.Info(ctx, "handler: ImportWishlist - success", "mode", result.Mode, "dryRun", result.DryRun, "itemCount", len(result.Items))
+	response.JSON(w, http.StatusOK, dto.NewWishlistDocumentImportResult(result))
+}
+
+// Merge applies an edit the client made against an earlier revision of the
+// wishlist, merged with the changes since, and returns the merged wishlist.
+func (h *WishlistTransferHandler) Merge(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: MergeWishlist called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: MergeWishlist - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	var req dto.WishlistMergeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn(ctx, "handler: MergeWishlist - invalid request body", "error", err)
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	result, err := h.transferService.Merge(ctx, userID, req.ToModel())
+	if err != nil {
+		writeWishlistTransferError(w, r, "MergeWishlist", err, "failed to merge wishlist")
+		return
+	}
+
+	logger.Info(ctx, "handler: MergeWishlist - success", "itemCount", len(result.Items), "conflictCount", len(result.Conflicts))
+	response.JSON(w, http.StatusOK, dto.NewWishlistMergeResult(result))
+}
+
+func writeWishlistTransferError(w http.ResponseWriter, r *http.Request, name string, err error, fallback string) {
+	ctx := r.Context()
+
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrInvalidImportMode),
+		errors.Is(err, services.ErrInvalidExportDocument),
+		errors.Is(err, services.ErrUnsupportedExportVersion),
+		errors.Is(err, services.ErrExportTooLarge),
+		errors.Is(err, services.ErrMergeTooLarge):
+		status = http.StatusBadRequest
+	case errors.Is(err, services.ErrWishlistFull), errors.Is(err, services.ErrTooManyBlueprints):
+		status = http.StatusConflict
+	}
+
+	if status == http.StatusInternalServerError {
+		logger.Error(ctx, "handler: "+name+" - "+fallback, "error", err)
+		response.Error(w, status, fallback)
+		return
+	}
+	logger.Warn(ctx, "handler: "+name+" - request rejected", "error", err)
+	rejected(w, status, err)
+}
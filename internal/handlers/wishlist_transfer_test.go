@@ -0,0 +1,294 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/graytonio/warframe-wishlist/internal/middleware"
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+)
+
+// newWishlistTransferRouter mounts the export, import and merge routes as main does,
+// with userID injected in place of the auth middleware.
+func newWishlistTransferRouter(service services.WishlistTransferServiceInterface, userID string) http.Handler {
+	handler := NewWishlistTransferHandler(service)
+	r := chi.NewRouter()
+	r.Route("/api/v1/wishlist", func(r chi.Router) {
+		r.Use(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ctx := context.WithValue(r.Context(), middleware.UserIDKey, userID)
+				next.ServeHTTP(w, r.WithContext(ctx))
+			})
+		})
+		r.Get("/export", handler.Export)
+		r.Post("/import", handler.Import)
+		r.Post("/merge", handler.Merge)
+	})
+	return r
+}
+
+func TestWishlistTransferHandler_Export(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "success", userID: "user-123", expectedStatus: http.StatusOK},
+		{name: "unauthorized - no user ID", userID: "", expectedStatus: http.StatusUnauthorized},
+		{name: "service error", userID: "user-123", mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.MockWishlistTransferService{
+				ExportFunc: func(ctx context.Context, userID string) (*models.WishlistExport, error) {
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return &models.WishlistExport{
+						Format:  models.WishlistExportFormat,
+						Version: models.WishlistExportVersion,
+						Items:   []models.WishlistExportItem{{UniqueName: "/Lotus/Forma", Quantity: 2}},
+					}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/wishlist/export", nil)
+			rec := httptest.NewRecorder()
+			newWishlistTransferRouter(service, tt.userID).ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			if cd := rec.Header().Get("Content-Disposition"); !strings.HasPrefix(cd, "attachment;") {
+				t.Errorf("expected an attachment, got Content-Disposition %q", cd)
+			}
+			var body map[string]interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if body["format"] != models.WishlistExportFormat {
+				t.Errorf("expected the export format, got %v", body["format"])
+			}
+			if blueprints, ok := body["ownedBlueprints"].([]interface{}); !ok || len(blueprints) != 0 {
+				t.Errorf("expected ownedBlueprints to be [], got %v", body["ownedBlueprints"])
+			}
+		})
+	}
+}
+
+func TestWishlistTransferHandler_ExportPDF(t *testing.T) {
+	tests := []struct {
+		name           string
+		format         string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "success", format: "pdf", expectedStatus: http.StatusOK},
+		{name: "unsupported format", format: "csv", expectedStatus: http.StatusBadRequest},
+		{name: "service error", format: "pdf", mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.MockWishlistTransferService{
+				ChecklistFunc: func(ctx context.Context, userID string) (*models.WishlistChecklist, error) {
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return &models.WishlistChecklist{
+						Items:     []models.ChecklistItem{{UniqueName: "/Lotus/Powersuits/Rhino/Rhino", Name: "Rhino", Quantity: 2}},
+						Materials: []models.MaterialRequirement{{UniqueName: "/Lotus/Types/Items/MiscItems/OrokinCell", Name: "Orokin Cell", Remaining: 3}},
+					}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/wishlist/export?format="+tt.format, nil)
+			rec := httptest.NewRecorder()
+			newWishlistTransferRouter(service, "user-123").ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != "application/pdf" {
+				t.Errorf("expected application/pdf, got %q", ct)
+			}
+			body := rec.Body.String()
+			if !strings.HasPrefix(body, "%PDF-") {
+				t.Fatalf("expected a PDF, got %q", body[:min(len(body), 20)])
+			}
+			for _, want := range []string{"(Rhino) Tj", "(x2) Tj", "(Orokin Cell) Tj", "(3) Tj"} {
+				if !strings.Contains(body, want) {
+					t.Errorf("expected %q in the PDF", want)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderChecklist_Pages(t *testing.T) {
+	checklist := &models.WishlistChecklist{}
+	for i := 0; i < 100; i++ {
+		checklist.Items = append(checklist.Items, models.ChecklistItem{Name: strings.Repeat("Long name ", 10), Quantity: 1})
+	}
+
+	var buf bytes.Buffer
+	if _, err := renderChecklist(checklist).WriteTo(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "/Count 3") || !strings.Contains(out, "(Page 3 of 3) Tj") {
+		t.Error("expected the items to run over three numbered pages")
+	}
+	if strings.Contains(out, strings.Repeat("Long name ", 10)) {
+		t.Error("expected long names to be truncated")
+	}
+}
+
+func TestWishlistTransferHandler_Import(t *testing.T) {
+	doc := `{"format":"warframe-wishlist","version":1,"items":[{"uniqueName":"/Lotus/Forma","quantity":2,"links":[{"url":"https://example.com","title":"Guide"}]}],"ownedBlueprints":["/Lotus/FormaBlueprint"]}`
+
+	tests := []struct {
+		name           string
+		userID         string
+		query          string
+		body           string
+		mockError      error
+		expectedStatus int
+		expectedMode   string
+		expectedDryRun bool
+	}{
+		{name: "merge by default", userID: "user-123", body: doc, expectedStatus: http.StatusOK},
+		{name: "replace dry run", userID: "user-123", query: "?mode=replace&dryRun=true", body: doc, expectedStatus: http.StatusOK, expectedMode: "replace", expectedDryRun: true},
+		{name: "unauthorized - no user ID", userID: "", body: doc, expectedStatus: http.StatusUnauthorized},
+		{name: "invalid dryRun", userID: "user-123", query: "?dryRun=maybe", body: doc, expectedStatus: http.StatusBadRequest},
+		{name: "invalid body", userID: "user-123", body: `{`, expectedStatus: http.StatusBadRequest},
+		{name: "invalid mode", userID: "user-123", query: "?mode=overwrite", body: doc, mockError: services.ErrInvalidImportMode, expectedStatus: http.StatusBadRequest, expectedMode: "overwrite"},
+		{name: "not an export", userID: "user-123", body: `{}`, mockError: services.ErrInvalidExportDocument, expectedStatus: http.StatusBadRequest},
+		{name: "newer version", userID: "user-123", body: doc, mockError: services.ErrUnsupportedExportVersion, expectedStatus: http.StatusBadRequest},
+		{name: "too large", userID: "user-123", body: doc, mockError: services.ErrExportTooLarge, expectedStatus: http.StatusBadRequest},
+		{name: "service error", userID: "user-123", body: doc, mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotReq models.WishlistDocumentImportRequest
+			service := &mocks.MockWishlistTransferService{
+				ImportFunc: func(ctx context.Context, userID string, req models.WishlistDocumentImportRequest) (*models.WishlistDocumentImportResult, error) {
+					gotReq = req
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return &models.WishlistDocumentImportResult{
+						Mode:   req.Mode,
+						DryRun: req.DryRun,
+						Items:  []models.ImportItemResult{{UniqueName: "/Lotus/Forma", Quantity: 2, Status: models.ImportStatusAdded}},
+					}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/wishlist/import"+tt.query, bytes.NewReader([]byte(tt.body)))
+			rec := httptest.NewRecorder()
+			newWishlistTransferRouter(service, tt.userID).ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			if gotReq.Mode != tt.expectedMode || gotReq.DryRun != tt.expectedDryRun {
+				t.Errorf("expected mode %q dryRun %v, got %q %v", tt.expectedMode, tt.expectedDryRun, gotReq.Mode, gotReq.DryRun)
+			}
+			items := gotReq.Document.Items
+			if gotReq.Document.Format != models.WishlistExportFormat || len(items) != 1 || items[0].Links[0].Title != "Guide" {
+				t.Errorf("expected the document to be passed through, got %+v", gotReq.Document)
+			}
+			if len(gotReq.Document.OwnedBlueprints) != 1 {
+				t.Errorf("expected blueprints to be passed through, got %v", gotReq.Document.OwnedBlueprints)
+			}
+		})
+	}
+}
+
+func TestWishlistTransferHandler_Merge(t *testing.T) {
+	body := `{"baseRevision":"2026-10-01T12:00:00Z","base":[{"uniqueName":"/Lotus/Forma","quantity":1}],"items":[{"uniqueName":"/Lotus/Forma","quantity":3}]}`
+
+	tests := []struct {
+		name           string
+		userID         string
+		body           string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "success", userID: "user-123", body: body, expectedStatus: http.StatusOK},
+		{name: "unauthorized - no user ID", userID: "", body: body, expectedStatus: http.StatusUnauthorized},
+		{name: "invalid body", userID: "user-123", body: `{`, expectedStatus: http.StatusBadRequest},
+		{name: "too large", userID: "user-123", body: body, mockError: services.ErrMergeTooLarge, expectedStatus: http.StatusBadRequest},
+		{name: "service error", userID: "user-123", body: body, mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotReq models.WishlistMergeRequest
+			service := &mocks.MockWishlistTransferService{
+				MergeFunc: func(ctx context.Context, userID string, req models.WishlistMergeRequest) (*models.WishlistMergeResult, error) {
+					gotReq = req
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return &models.WishlistMergeResult{
+						Items:     []models.ImportItemResult{{UniqueName: "/Lotus/Forma", Quantity: 3, Status: models.ImportStatusUpdated}},
+						Conflicts: []string{"/Lotus/Forma"},
+						Wishlist:  &models.Wishlist{UserID: userID, Items: []models.WishlistItem{{UniqueName: "/Lotus/Forma", Quantity: 3}}},
+					}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/wishlist/merge", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			newWishlistTransferRouter(service, tt.userID).ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			if !gotReq.BaseRevision.Equal(time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)) || len(gotReq.Base) != 1 || gotReq.Items[0].Quantity != 3 {
+				t.Errorf("expected the request to be passed through, got %+v", gotReq)
+			}
+			var got struct {
+				Conflicts []string `json:"conflicts"`
+				Wishlist  struct {
+					Items []struct {
+						Quantity int `json:"quantity"`
+					} `json:"items"`
+				} `json:"wishlist"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatalf("decoding response: %v", err)
+			}
+			if len(got.Conflicts) != 1 || len(got.Wishlist.Items) != 1 || got.Wishlist.Items[0].Quantity != 3 {
+				t.Errorf("unexpected response %s", rec.Body.String())
+			}
+		})
+	}
+}
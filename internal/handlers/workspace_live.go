@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+	"github.com/graytonio/warframe-wishlist/internal/middleware"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/realtime"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"github.com/graytonio/warframe-wishlist/pkg/response"
+)
+
+// LiveProtocol is the WebSocket subprotocol of workspace live connections.
+const LiveProtocol = "wishlist.v1"
+
+const (
+	livePingInterval = 30 * time.Second
+	liveIdleTimeout  = 75 * time.Second
+)
+
+// WorkspaceLiveHandler streams a workspace's changes, and who is viewing
+// it, to its members over a WebSocket.
+type WorkspaceLiveHandler struct {
+	workspaceService services.WorkspaceServiceInterface
+	hub              *realtime.Hub
+	pingInterval     time.Duration
+	idleTimeout      time.Duration
+}
+
+func NewWorkspaceLiveHandler(workspaceService services.WorkspaceServiceInterface, hub *realtime.Hub) *WorkspaceLiveHandler {
+	return &WorkspaceLiveHandler{
+		workspaceService: workspaceService,
+		hub:              hub,
+		pingInterval:     livePingInterval,
+		idleTimeout:      liveIdleTimeout,
+	}
+}
+
+// Live upgrades to a WebSocket that receives the workspace's presence
+// followed by its changes. Messages from the client are read only to notice
+// it going away. The connection is closed when the member is removed or the
+// workspace deleted.
+func (h *WorkspaceLiveHandler) Live(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: WorkspaceLive called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: WorkspaceLive - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+	if !realtime.IsUpgrade(r) {
+		logger.Warn(ctx, "handler: WorkspaceLive - not a websocket upgrade")
+		w.Header().Set("Upgrade", "websocket")
+		response.ErrorCode(w, http.StatusUpgradeRequired, response.CodeWebSocketRequired, "websocket upgrade required")
+		return
+	}
+
+	workspace, err := h.workspaceService.Get(ctx, userID, chi.URLParam(r, "workspaceID"))
+	if err != nil {
+		writeWorkspaceError(w, r, "WorkspaceLive", err, "failed to get workspace")
+		return
+	}
+
+	conn, err := realtime.Upgrade(w, r, LiveProtocol)
+	if err != nil {
+		if errors.Is(err, realtime.ErrNotWebSocket) {
+			logger.Warn(ctx, "handler: WorkspaceLive - invalid handshake", "error", err)
+			rejected(w, http.StatusBadRequest, err)
+			return
+		}
+		logger.Error(ctx, "handler: WorkspaceLive - upgrade failed", "error", err)
+		return
+	}
+
+	room := workspace.ID.Hex()
+	subscription, err := h.hub.Join(room, userID)
+	if err != nil {
+		logger.Warn(ctx, "handler: WorkspaceLive - cannot subscribe", "error", err)
+		conn.Close(realtime.CloseTryAgainLater, err.Error())
+		return
+	}
+	defer subscription.Leave()
+
+	logger.Info(ctx, "handler: WorkspaceLive - connected", "id", room)
+	code, reason := h.stream(conn, subscription, userID)
+	conn.Close(code, reason)
+	logger.Info(ctx, "handler: WorkspaceLive - disconnected", "id", room, "code", code, "reason", reason)
+}
+
+// stream writes the subscription's events to conn until either side ends,
+// returning the close code and reason to send.
+func (h *WorkspaceLiveHandler) stream(conn *realtime.Conn, subscription *realtime.Subscription, userID string) (int, string) {
+	conn.SetIdleTimeout(h.idleTimeout)
+	gone := make(chan struct{})
+	go func() {
+		defer close(gone)
+		for {
+			if _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(h.pingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-gone:
+			return realtime.CloseNormal, ""
+		case <-ticker.C:
+			if err := conn.Ping(); err != nil {
+				return realtime.CloseGoingAway, ""
+			}
+		case event, ok := <-subscription.Events():
+			if !ok {
+				// The hub closed or dropped a subscription that fell behind;
+				// the client reconnects and refetches.
+				return realtime.CloseGoingAway, "reconnect"
+			}
+			if err := writeLiveEvent(conn, event); err != nil {
+				return realtime.CloseGoingAway, ""
+			}
+			if e, ok := event.(models.WorkspaceEvent); ok {
+				switch {
+				case e.Type == models.WorkspaceEventDeleted:
+					return realtime.CloseNormal, "workspace deleted"
+				case e.Type == models.WorkspaceEventMemberRemoved && e.MemberID == userID:
+					return realtime.ClosePolicyViolated, "removed from workspace"
+				}
+			}
+		}
+	}
+}
+
+func writeLiveEvent(conn *realtime.Conn, event any) error {
+	var message any
+	switch e := event.(type) {
+	case realtime.Presence:
+		message = dto.NewWorkspacePresence(e.Users)
+	case models.WorkspaceEvent:
+		message = dto.NewWorkspaceEvent(&e)
+	default:
+		return nil
+	}
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	return conn.WriteMessage(data)
+}
@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/graytonio/warframe-wishlist/internal/middleware"
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/realtime"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var liveWorkspaceID = primitive.NewObjectID()
+
+// newLiveServer serves the live route as main mounts it, taking the user
+// from the X-Test-User header in place of the auth middleware.
+func newLiveServer(t *testing.T, hub *realtime.Hub) *httptest.Server {
+	service := &mocks.MockWorkspaceService{
+		GetFunc: func(ctx context.Context, userID, id string) (*models.Workspace, error) {
+			if id != liveWorkspaceID.Hex() || userID == "stranger" {
+				return nil, services.ErrWorkspaceNotFound
+			}
+			return &models.Workspace{ID: liveWorkspaceID}, nil
+		},
+	}
+	handler := NewWorkspaceLiveHandler(service, hub)
+	r := chi.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), middleware.UserIDKey, r.Header.Get("X-Test-User"))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	})
+	r.Get("/api/v1/workspaces/{workspaceID}/live", handler.Live)
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+	return server
+}
+
+type liveClient struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// dialLive opens the live connection as userID, returning the handshake
+// response.
+func dialLive(t *testing.T, server *httptest.Server, userID, id string) (*liveClient, *http.Response) {
+	t.Helper()
+	conn, err := net.Dial("tcp", strings.TrimPrefix(server.URL, "http://"))
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/api/v1/workspaces/"+id+"/live", nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	req.Header.Set("Sec-WebSocket-Protocol", LiveProtocol)
+	req.Header.Set("X-Test-User", userID)
+	req.Write(conn)
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		t.Fatalf("handshake failed: %v", err)
+	}
+	return &liveClient{conn: conn, reader: reader}, resp
+}
+
+// next reads the next message, returning its opcode and payload.
+func (c *liveClient) next(t *testing.T) (byte, []byte) {
+	t.Helper()
+	var header [2]byte
+	if _, err := io.ReadFull(c.reader, header[:]); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	length := int(header[1] & 0x7F)
+	if length == 126 {
+		var extended [2]byte
+		io.ReadFull(c.reader, extended[:])
+		length = int(binary.BigEndian.Uint16(extended[:]))
+	}
+	payload := make([]byte, length)
+	io.ReadFull(c.reader, payload)
+	return header[0] & 0x0F, payload
+}
+
+func (c *liveClient) nextJSON(t *testing.T) map[string]any {
+	t.Helper()
+	opcode, payload := c.next(t)
+	var message map[string]any
+	if opcode != 0x1 || json.Unmarshal(payload, &message) != nil {
+		t.Fatalf("expected a JSON text message, got %x %q", opcode, payload)
+	}
+	return message
+}
+
+func TestWorkspaceLiveHandler_StreamsPresenceAndChanges(t *testing.T) {
+	hub := realtime.NewHub(10)
+	server := newLiveServer(t, hub)
+	room := liveWorkspaceID.Hex()
+
+	alice, resp := dialLive(t, server, "alice", room)
+	if resp.StatusCode != http.StatusSwitchingProtocols || resp.Header.Get("Sec-WebSocket-Protocol") != LiveProtocol {
+		t.Fatalf("expected the upgrade, got %d %v", resp.StatusCode, resp.Header)
+	}
+	if message := alice.nextJSON(t); message["type"] != "presence" || len(message["users"].([]any)) != 1 {
+		t.Fatalf("expected alice's presence, got %v", message)
+	}
+
+	bob, _ := dialLive(t, server, "bob", room)
+	bob.nextJSON(t)
+	if message := alice.nextJSON(t); len(message["users"].([]any)) != 2 {
+		t.Fatalf("expected both present, got %v", message)
+	}
+
+	hub.Publish(room, models.WorkspaceEvent{
+		Type:       models.WorkspaceEventItemAdded,
+		UserID:     "bob",
+		UniqueName: "/Lotus/Forma",
+		Item:       &models.WorkspaceItem{UniqueName: "/Lotus/Forma", Quantity: 3},
+		Version:    4,
+	})
+	for _, client := range []*liveClient{alice, bob} {
+		message := client.nextJSON(t)
+		item, _ := message["item"].(map[string]any)
+		if message["type"] != models.WorkspaceEventItemAdded || message["userId"] != "bob" || item["quantity"] != 3.0 || message["version"] != 4.0 {
+			t.Errorf("expected the added item, got %v", message)
+		}
+	}
+
+	// A member removed from the workspace is disconnected; the rest see them go.
+	hub.Publish(room, models.WorkspaceEvent{Type: models.WorkspaceEventMemberRemoved, UserID: "alice", MemberID: "bob"})
+	bob.nextJSON(t)
+	if opcode, payload := bob.next(t); opcode != 0x8 || binary.BigEndian.Uint16(payload) != realtime.ClosePolicyViolated {
+		t.Errorf("expected bob's connection closed, got %x %q", opcode, payload)
+	}
+	alice.nextJSON(t)
+	if message := alice.nextJSON(t); message["type"] != "presence" || len(message["users"].([]any)) != 1 {
+		t.Errorf("expected only alice present, got %v", message)
+	}
+}
+
+func TestWorkspaceLiveHandler_Errors(t *testing.T) {
+	server := newLiveServer(t, realtime.NewHub(10))
+
+	tests := []struct {
+		name           string
+		userID         string
+		id             string
+		upgrade        bool
+		expectedStatus int
+	}{
+		{name: "unauthorized - no user ID", userID: "", id: liveWorkspaceID.Hex(), upgrade: true, expectedStatus: http.StatusUnauthorized},
+		{name: "not a member", userID: "stranger", id: liveWorkspaceID.Hex(), upgrade: true, expectedStatus: http.StatusNotFound},
+		{name: "not a websocket", userID: "alice", id: liveWorkspaceID.Hex(), upgrade: false, expectedStatus: http.StatusUpgradeRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.upgrade {
+				_, resp := dialLive(t, server, tt.userID, tt.id)
+				if resp.StatusCode != tt.expectedStatus {
+					t.Errorf("expected status %d, got %d", tt.expectedStatus, resp.StatusCode)
+				}
+				return
+			}
+			req, _ := http.NewRequest(http.MethodGet, server.URL+"/api/v1/workspaces/"+tt.id+"/live", nil)
+			req.Header.Set("X-Test-User", tt.userID)
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestWorkspaceLiveHandler_HubFull(t *testing.T) {
+	hub := realtime.NewHub(1)
+	server := newLiveServer(t, hub)
+	room := liveWorkspaceID.Hex()
+
+	first, _ := dialLive(t, server, "alice", room)
+	first.nextJSON(t)
+	second, resp := dialLive(t, server, "bob", room)
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("expected the upgrade, got %d", resp.StatusCode)
+	}
+	if opcode, payload := second.next(t); opcode != 0x8 || binary.BigEndian.Uint16(payload) != realtime.CloseTryAgainLater {
+		t.Errorf("expected a try-again-later close, got %x %q", opcode, payload)
+	}
+}
@@ -0,0 +1,470 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+	"github.com/graytonio/warframe-wishlist/internal/middleware"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"github.com/graytonio/warframe-wishlist/pkg/response"
+)
+
+// WorkspaceHandler serves the wishlists clans share. Every change answers
+// with the whole workspace as it now stands.
+type WorkspaceHandler struct {
+	workspaceService services.WorkspaceServiceInterface
+}
+
+func NewWorkspaceHandler(workspaceService services.WorkspaceServiceInterface) *WorkspaceHandler {
+	return &WorkspaceHandler{workspaceService: workspaceService}
+}
+
+func (h *WorkspaceHandler) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: ListWorkspaces called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: ListWorkspaces - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	workspaces, err := h.workspaceService.List(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "handler: ListWorkspaces - failed to list workspaces", "error", err)
+		response.Error(w, http.StatusInternalServerError, "failed to list workspaces")
+		return
+	}
+
+	logger.Info(ctx, "handler: ListWorkspaces - success", "count", len(workspaces))
+	response.JSON(w, http.StatusOK, dto.NewWorkspaces(workspaces))
+}
+
+func (h *WorkspaceHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: GetWorkspace called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: GetWorkspace - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	workspace, err := h.workspaceService.Get(ctx, userID, chi.URLParam(r, "workspaceID"))
+	if err != nil {
+		writeWorkspaceError(w, r, "GetWorkspace", err, "failed to get workspace")
+		return
+	}
+
+	logger.Info(ctx, "handler: GetWorkspace - success", "itemCount", len(workspace.Items))
+	response.JSON(w, http.StatusOK, dto.NewWorkspace(workspace))
+}
+
+func (h *WorkspaceHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: CreateWorkspace called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: CreateWorkspace - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	var req dto.WorkspaceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn(ctx, "handler: CreateWorkspace - invalid request body", "error", err)
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	workspace, err := h.workspaceService.Create(ctx, userID, req.ToModel())
+	if err != nil {
+		writeWorkspaceError(w, r, "CreateWorkspace", err, "failed to create workspace")
+		return
+	}
+
+	logger.Info(ctx, "handler: CreateWorkspace - success", "id", workspace.ID.Hex())
+	response.JSON(w, http.StatusCreated, dto.NewWorkspace(workspace))
+}
+
+func (h *WorkspaceHandler) Update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: UpdateWorkspace called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: UpdateWorkspace - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	var req dto.WorkspaceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn(ctx, "handler: UpdateWorkspace - invalid request body", "error", err)
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	workspace, err := h.workspaceService.Update(ctx, userID, chi.URLParam(r, "workspaceID"), req.ToModel())
+	if err != nil {
+		writeWorkspaceError(w, r, "UpdateWorkspace", err, "failed to update workspace")
+		return
+	}
+
+	logger.Info(ctx, "handler: UpdateWorkspace - success", "id", workspace.ID.Hex())
+	response.JSON(w, http.StatusOK, dto.NewWorkspace(workspace))
+}
+
+func (h *WorkspaceHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: DeleteWorkspace called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: DeleteWorkspace - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	workspaceID := chi.URLParam(r, "workspaceID")
+	if err := h.workspaceService.Delete(ctx, userID, workspaceID); err != nil {
+		writeWorkspaceError(w, r, "DeleteWorkspace", err, "failed to delete workspace")
+		return
+	}
+
+	logger.Info(ctx, "handler: DeleteWorkspace - success", "id", workspaceID)
+	response.JSON(w, http.StatusOK, map[string]string{
+		"message": "workspace deleted",
+	})
+}
+
+func (h *WorkspaceHandler) GetMaterials(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: GetWorkspaceMaterials called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: GetWorkspaceMaterials - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	materials, err := h.workspaceService.GetMaterials(ctx, userID, chi.URLParam(r, "workspaceID"))
+	if err != nil {
+		writeWorkspaceError(w, r, "GetWorkspaceMaterials", err, "failed to get materials")
+		return
+	}
+
+	logger.Info(ctx, "handler: GetWorkspaceMaterials - success", "materialCount", len(materials.Materials))
+	response.JSON(w, http.StatusOK, dto.NewWorkspaceMaterials(materials))
+}
+
+// LogMaterialContribution records materials the caller contributed toward
+// the workspace's material goals, answering with the activity entry.
+func (h *WorkspaceHandler) LogMaterialContribution(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: LogWorkspaceMaterialContribution called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: LogWorkspaceMaterialContribution - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	var req dto.WorkspaceMaterialContributionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn(ctx, "handler: LogWorkspaceMaterialContribution - invalid request body", "error", err)
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.UniqueName == "" {
+		logger.Warn(ctx, "handler: LogWorkspaceMaterialContribution - missing uniqueName")
+		response.Error(w, http.StatusBadRequest, "uniqueName is required")
+		return
+	}
+
+	event, err := h.workspaceService.LogMaterialContribution(ctx, userID, chi.URLParam(r, "workspaceID"), req.ToModel())
+	if err != nil {
+		writeWorkspaceError(w, r, "LogWorkspaceMaterialContribution", err, "failed to log contribution")
+		return
+	}
+
+	logger.Info(ctx, "handler: LogWorkspaceMaterialContribution - success", "uniqueName", req.UniqueName, "quantity", req.Quantity)
+	response.JSON(w, http.StatusCreated, dto.NewWorkspaceActivity(event))
+}
+
+// GetActivity lists the workspace's latest contributions, newest first.
+func (h *WorkspaceHandler) GetActivity(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: GetWorkspaceActivity called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: GetWorkspaceActivity - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	events, err := h.workspaceService.ListActivity(ctx, userID, chi.URLParam(r, "workspaceID"), limit)
+	if err != nil {
+		writeWorkspaceError(w, r, "GetWorkspaceActivity", err, "failed to get activity")
+		return
+	}
+
+	logger.Info(ctx, "handler: GetWorkspaceActivity - success", "count", len(events))
+	response.JSON(w, http.StatusOK, dto.NewWorkspaceActivities(events))
+}
+
+func (h *WorkspaceHandler) AddMember(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: AddWorkspaceMember called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: AddWorkspaceMember - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	var req dto.WorkspaceMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn(ctx, "handler: AddWorkspaceMember - invalid request body", "error", err)
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	workspace, err := h.workspaceService.AddMember(ctx, userID, chi.URLParam(r, "workspaceID"), req.ToModel())
+	if err != nil {
+		writeWorkspaceError(w, r, "AddWorkspaceMember", err, "failed to add member")
+		return
+	}
+
+	logger.Info(ctx, "handler: AddWorkspaceMember - success", "memberID", req.UserID, "role", req.Role)
+	response.JSON(w, http.StatusCreated, dto.NewWorkspace(workspace))
+}
+
+func (h *WorkspaceHandler) SetMemberRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: SetWorkspaceMemberRole called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: SetWorkspaceMemberRole - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	var req dto.WorkspaceMemberRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn(ctx, "handler: SetWorkspaceMemberRole - invalid request body", "error", err)
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	memberID := chi.URLParam(r, "memberID")
+	workspace, err := h.workspaceService.SetMemberRole(ctx, userID, chi.URLParam(r, "workspaceID"), memberID, req.Role)
+	if err != nil {
+		writeWorkspaceError(w, r, "SetWorkspaceMemberRole", err, "failed to update member")
+		return
+	}
+
+	logger.Info(ctx, "handler: SetWorkspaceMemberRole - success", "memberID", memberID, "role", req.Role)
+	response.JSON(w, http.StatusOK, dto.NewWorkspace(workspace))
+}
+
+// RemoveMember removes a member; members may remove themselves to leave.
+func (h *WorkspaceHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: RemoveWorkspaceMember called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: RemoveWorkspaceMember - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	memberID := chi.URLParam(r, "memberID")
+	workspace, err := h.workspaceService.RemoveMember(ctx, userID, chi.URLParam(r, "workspaceID"), memberID)
+	if err != nil {
+		writeWorkspaceError(w, r, "RemoveWorkspaceMember", err, "failed to remove member")
+		return
+	}
+
+	logger.Info(ctx, "handler: RemoveWorkspaceMember - success", "memberID", memberID)
+	response.JSON(w, http.StatusOK, dto.NewWorkspace(workspace))
+}
+
+func (h *WorkspaceHandler) AddItem(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: AddWorkspaceItem called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: AddWorkspaceItem - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	var req dto.WorkspaceItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn(ctx, "handler: AddWorkspaceItem - invalid request body", "error", err)
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	uniqueName, err := models.CanonicalUniqueName(req.UniqueName)
+	if err != nil {
+		logger.Warn(ctx, "handler: AddWorkspaceItem - invalid uniqueName", "error", err)
+		rejected(w, http.StatusBadRequest, err)
+		return
+	}
+	req.UniqueName = uniqueName
+
+	workspace, err := h.workspaceService.AddItem(ctx, userID, chi.URLParam(r, "workspaceID"), req.ToModel())
+	if err != nil {
+		writeWorkspaceError(w, r, "AddWorkspaceItem", err, "failed to add item")
+		return
+	}
+
+	logger.Info(ctx, "handler: AddWorkspaceItem - success", "uniqueName", uniqueName)
+	response.JSON(w, http.StatusCreated, dto.NewWorkspace(workspace))
+}
+
+func (h *WorkspaceHandler) UpdateItemQuantity(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: UpdateWorkspaceItemQuantity called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: UpdateWorkspaceItemQuantity - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	uniqueName, err := uniqueNameParam(r)
+	if err != nil {
+		logger.Warn(ctx, "handler: UpdateWorkspaceItemQuantity - invalid uniqueName", "error", err)
+		rejected(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var req dto.UpdateQuantityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn(ctx, "handler: UpdateWorkspaceItemQuantity - invalid request body", "error", err)
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	workspace, err := h.workspaceService.UpdateItemQuantity(ctx, userID, chi.URLParam(r, "workspaceID"), uniqueName, req.Quantity)
+	if err != nil {
+		writeWorkspaceError(w, r, "UpdateWorkspaceItemQuantity", err, "failed to update quantity")
+		return
+	}
+
+	logger.Info(ctx, "handler: UpdateWorkspaceItemQuantity - success", "uniqueName", uniqueName, "quantity", req.Quantity)
+	response.JSON(w, http.StatusOK, dto.NewWorkspace(workspace))
+}
+
+func (h *WorkspaceHandler) RemoveItem(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: RemoveWorkspaceItem called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: RemoveWorkspaceItem - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	uniqueName, err := uniqueNameParam(r)
+	if err != nil {
+		logger.Warn(ctx, "handler: RemoveWorkspaceItem - invalid uniqueName", "error", err)
+		rejected(w, http.StatusBadRequest, err)
+		return
+	}
+
+	workspace, err := h.workspaceService.RemoveItem(ctx, userID, chi.URLParam(r, "workspaceID"), uniqueName)
+	if err != nil {
+		writeWorkspaceError(w, r, "RemoveWorkspaceItem", err, "failed to remove item")
+		return
+	}
+
+	logger.Info(ctx, "handler: RemoveWorkspaceItem - success", "uniqueName", uniqueName)
+	response.JSON(w, http.StatusOK, dto.NewWorkspace(workspace))
+}
+
+// SetContribution records how many of an item the caller has delivered.
+func (h *WorkspaceHandler) SetContribution(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger.Debug(ctx, "handler: SetWorkspaceContribution called")
+
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		logger.Warn(ctx, "handler: SetWorkspaceContribution - user not authenticated")
+		response.Error(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	uniqueName, err := uniqueNameParam(r)
+	if err != nil {
+		logger.Warn(ctx, "handler: SetWorkspaceContribution - invalid uniqueName", "error", err)
+		rejected(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var req dto.UpdateQuantityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn(ctx, "handler: SetWorkspaceContribution - invalid request body", "error", err)
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	workspace, err := h.workspaceService.SetContribution(ctx, userID, chi.URLParam(r, "workspaceID"), uniqueName, req.Quantity)
+	if err != nil {
+		writeWorkspaceError(w, r, "SetWorkspaceContribution", err, "failed to record contribution")
+		return
+	}
+
+	logger.Info(ctx, "handler: SetWorkspaceContribution - success", "uniqueName", uniqueName, "quantity", req.Quantity)
+	response.JSON(w, http.StatusOK, dto.NewWorkspace(workspace))
+}
+
+func writeWorkspaceError(w http.ResponseWriter, r *http.Request, name string, err error, fallback string) {
+	ctx := r.Context()
+
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrInvalidWorkspace), errors.Is(err, services.ErrInvalidQuantity),
+		errors.Is(err, services.ErrWorkspaceMaterialNotNeeded):
+		status = http.StatusBadRequest
+	case errors.Is(err, services.ErrWorkspaceForbidden):
+		status = http.StatusForbidden
+	case errors.Is(err, services.ErrWorkspaceNotFound), errors.Is(err, services.ErrWorkspaceMemberNotFound),
+		errors.Is(err, services.ErrWorkspaceItemNotFound), errors.Is(err, services.ErrItemNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrTooManyWorkspaces), errors.Is(err, services.ErrTooManyWorkspaceMembers),
+		errors.Is(err, services.ErrTooManyWorkspaceItems), errors.Is(err, services.ErrWorkspaceMemberExists),
+		errors.Is(err, services.ErrWorkspaceItemExists), errors.Is(err, services.ErrLastWorkspaceAdmin),
+		errors.Is(err, services.ErrWorkspaceConflict):
+		status = http.StatusConflict
+	}
+
+	if status == http.StatusInternalServerError {
+		logger.Error(ctx, "handler: "+name+" - "+fallback, "error", err)
+		response.Error(w, status, fallback)
+		return
+	}
+	logger.Warn(ctx, "handler: "+name+" - request rejected", "error", err)
+	rejected(w, status, err)
+}
@@ -0,0 +1,448 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/graytonio/warframe-wishlist/internal/middleware"
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/services"
+)
+
+// newWorkspaceRouter mounts the workspace routes as main does, with userID
+// injected in place of the auth middleware.
+func newWorkspaceRouter(service services.WorkspaceServiceInterface, userID string) http.Handler {
+	handler := NewWorkspaceHandler(service)
+	r := chi.NewRouter()
+	r.Route("/api/v1/workspaces", func(r chi.Router) {
+		r.Use(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ctx := context.WithValue(r.Context(), middleware.UserIDKey, userID)
+				next.ServeHTTP(w, r.WithContext(ctx))
+			})
+		})
+		r.Get("/", handler.List)
+		r.Post("/", handler.Create)
+		r.Route("/{workspaceID}", func(r chi.Router) {
+			r.Get("/", handler.Get)
+			r.Put("/", handler.Update)
+			r.Delete("/", handler.Delete)
+			r.Get("/materials", handler.GetMaterials)
+			r.Post("/material-contributions", handler.LogMaterialContribution)
+			r.Get("/activity", handler.GetActivity)
+			r.Post("/members", handler.AddMember)
+			r.Put("/members/{memberID}", handler.SetMemberRole)
+			r.Delete("/members/{memberID}", handler.RemoveMember)
+			r.Post("/items", handler.AddItem)
+			r.Patch("/items/*", handler.UpdateItemQuantity)
+			r.Delete("/items/*", handler.RemoveItem)
+			r.Put("/contributions/*", handler.SetContribution)
+		})
+	})
+	return r
+}
+
+func TestWorkspaceHandler_List(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "success", userID: "user-123", expectedStatus: http.StatusOK},
+		{name: "unauthorized - no user ID", userID: "", expectedStatus: http.StatusUnauthorized},
+		{name: "service error", userID: "user-123", mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.MockWorkspaceService{
+				ListFunc: func(ctx context.Context, userID string) ([]models.Workspace, error) {
+					return []models.Workspace{{Name: "Dojo research"}}, tt.mockError
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/workspaces", nil)
+			rec := httptest.NewRecorder()
+			newWorkspaceRouter(service, tt.userID).ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestWorkspaceHandler_Get(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "success", expectedStatus: http.StatusOK},
+		{name: "not a member", mockError: services.ErrWorkspaceNotFound, expectedStatus: http.StatusNotFound},
+		{name: "service error", mockError: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotID string
+			service := &mocks.MockWorkspaceService{
+				GetFunc: func(ctx context.Context, userID, id string) (*models.Workspace, error) {
+					gotID = id
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return &models.Workspace{Name: "Dojo research"}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/workspaces/ws-1", nil)
+			rec := httptest.NewRecorder()
+			newWorkspaceRouter(service, "user-123").ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if gotID != "ws-1" {
+				t.Errorf("expected workspace ID ws-1, got %q", gotID)
+			}
+		})
+	}
+}
+
+func TestWorkspaceHandler_Create(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		body           string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "success", userID: "user-123", body: `{"name":"Dojo research"}`, expectedStatus: http.StatusCreated},
+		{name: "unauthorized - no user ID", userID: "", body: `{}`, expectedStatus: http.StatusUnauthorized},
+		{name: "invalid body", userID: "user-123", body: `{`, expectedStatus: http.StatusBadRequest},
+		{name: "invalid workspace", userID: "user-123", body: `{}`, mockError: services.ErrInvalidWorkspace, expectedStatus: http.StatusBadRequest},
+		{name: "too many workspaces", userID: "user-123", body: `{"name":"x"}`, mockError: services.ErrTooManyWorkspaces, expectedStatus: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotReq models.WorkspaceRequest
+			service := &mocks.MockWorkspaceService{
+				CreateFunc: func(ctx context.Context, userID string, req models.WorkspaceRequest) (*models.Workspace, error) {
+					gotReq = req
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return &models.Workspace{Name: req.Name}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/workspaces", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			newWorkspaceRouter(service, tt.userID).ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if tt.expectedStatus == http.StatusCreated && gotReq.Name != "Dojo research" {
+				t.Errorf("expected the name to be passed through, got %+v", gotReq)
+			}
+		})
+	}
+}
+
+func TestWorkspaceHandler_Delete(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "success", expectedStatus: http.StatusOK},
+		{name: "forbidden", mockError: services.ErrWorkspaceForbidden, expectedStatus: http.StatusForbidden},
+		{name: "not found", mockError: services.ErrWorkspaceNotFound, expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.MockWorkspaceService{
+				DeleteFunc: func(ctx context.Context, userID, id string) error {
+					return tt.mockError
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/workspaces/ws-1", nil)
+			rec := httptest.NewRecorder()
+			newWorkspaceRouter(service, "user-123").ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestWorkspaceHandler_Members(t *testing.T) {
+	var gotAdd models.WorkspaceMemberRequest
+	var gotMemberID, gotRole string
+	service := &mocks.MockWorkspaceService{
+		AddMemberFunc: func(ctx context.Context, userID, id string, req models.WorkspaceMemberRequest) (*models.Workspace, error) {
+			gotAdd = req
+			return &models.Workspace{}, nil
+		},
+		SetMemberRoleFunc: func(ctx context.Context, userID, id, memberID, role string) (*models.Workspace, error) {
+			gotMemberID, gotRole = memberID, role
+			return nil, services.ErrLastWorkspaceAdmin
+		},
+		RemoveMemberFunc: func(ctx context.Context, userID, id, memberID string) (*models.Workspace, error) {
+			return nil, services.ErrWorkspaceMemberNotFound
+		},
+	}
+	router := newWorkspaceRouter(service, "user-123")
+
+	tests := []struct {
+		name           string
+		method         string
+		target         string
+		body           string
+		expectedStatus int
+	}{
+		{name: "add", method: http.MethodPost, target: "/api/v1/workspaces/ws-1/members", body: `{"userId":"user-456","role":"editor"}`, expectedStatus: http.StatusCreated},
+		{name: "demote last admin", method: http.MethodPut, target: "/api/v1/workspaces/ws-1/members/user-123", body: `{"role":"viewer"}`, expectedStatus: http.StatusConflict},
+		{name: "remove unknown member", method: http.MethodDelete, target: "/api/v1/workspaces/ws-1/members/user-789", expectedStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+		})
+	}
+
+	if gotAdd.UserID != "user-456" || gotAdd.Role != models.WorkspaceRoleEditor {
+		t.Errorf("expected the new member to be passed through, got %+v", gotAdd)
+	}
+	if gotMemberID != "user-123" || gotRole != models.WorkspaceRoleViewer {
+		t.Errorf("expected member user-123 and role viewer, got %q, %q", gotMemberID, gotRole)
+	}
+}
+
+func TestWorkspaceHandler_Items(t *testing.T) {
+	var gotUniqueNames []string
+	var gotQuantities []int
+	record := func(uniqueName string, quantity int) {
+		gotUniqueNames = append(gotUniqueNames, uniqueName)
+		gotQuantities = append(gotQuantities, quantity)
+	}
+	service := &mocks.MockWorkspaceService{
+		AddItemFunc: func(ctx context.Context, userID, id string, req models.WorkspaceItemRequest) (*models.Workspace, error) {
+			record(req.UniqueName, req.Quantity)
+			return &models.Workspace{}, nil
+		},
+		UpdateItemQuantityFunc: func(ctx context.Context, userID, id, uniqueName string, quantity int) (*models.Workspace, error) {
+			record(uniqueName, quantity)
+			return nil, services.ErrWorkspaceForbidden
+		},
+		RemoveItemFunc: func(ctx context.Context, userID, id, uniqueName string) (*models.Workspace, error) {
+			record(uniqueName, 0)
+			return &models.Workspace{}, nil
+		},
+		SetContributionFunc: func(ctx context.Context, userID, id, uniqueName string, quantity int) (*models.Workspace, error) {
+			record(uniqueName, quantity)
+			return &models.Workspace{Items: []models.WorkspaceItem{{
+				UniqueName:    uniqueName,
+				Quantity:      5,
+				Contributions: []models.WorkspaceContribution{{UserID: userID, Quantity: quantity}},
+			}}}, nil
+		},
+	}
+	router := newWorkspaceRouter(service, "user-123")
+
+	tests := []struct {
+		name           string
+		method         string
+		target         string
+		body           string
+		expectedStatus int
+	}{
+		{name: "add", method: http.MethodPost, target: "/api/v1/workspaces/ws-1/items", body: `{"uniqueName":"/Lotus/Reactor","quantity":3}`, expectedStatus: http.StatusCreated},
+		{name: "add invalid uniqueName", method: http.MethodPost, target: "/api/v1/workspaces/ws-1/items", body: `{"uniqueName":""}`, expectedStatus: http.StatusBadRequest},
+		{name: "update as viewer", method: http.MethodPatch, target: "/api/v1/workspaces/ws-1/items/Lotus/Reactor", body: `{"quantity":4}`, expectedStatus: http.StatusForbidden},
+		{name: "remove", method: http.MethodDelete, target: "/api/v1/workspaces/ws-1/items/Lotus/Reactor", expectedStatus: http.StatusOK},
+		{name: "contribute", method: http.MethodPut, target: "/api/v1/workspaces/ws-1/contributions/Lotus/Reactor", body: `{"quantity":2}`, expectedStatus: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if tt.name != "contribute" {
+				return
+			}
+			var body map[string]interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			item := body["items"].([]interface{})[0].(map[string]interface{})
+			if item["contributed"] != 2.0 || item["remaining"] != 3.0 {
+				t.Errorf("expected 2 contributed and 3 remaining, got %v", item)
+			}
+		})
+	}
+
+	for i, want := range []string{"/Lotus/Reactor", "/Lotus/Reactor", "/Lotus/Reactor", "/Lotus/Reactor"} {
+		if i >= len(gotUniqueNames) || gotUniqueNames[i] != want {
+			t.Fatalf("expected every call for %s, got %v", want, gotUniqueNames)
+		}
+	}
+	if gotQuantities[0] != 3 || gotQuantities[1] != 4 || gotQuantities[3] != 2 {
+		t.Errorf("expected quantities 3, 4 and 2 to be passed through, got %v", gotQuantities)
+	}
+}
+
+func TestWorkspaceHandler_GetMaterials(t *testing.T) {
+	service := &mocks.MockWorkspaceService{
+		GetMaterialsFunc: func(ctx context.Context, userID, id string) (*models.WorkspaceMaterials, error) {
+			return &models.WorkspaceMaterials{
+				Materials: []models.WorkspaceMaterial{{
+					MaterialRequirement: models.MaterialRequirement{UniqueName: "/Lotus/Alloy", TotalCount: 100, Remaining: 60},
+					Contributed:         40,
+					Contributions:       []models.MemberContribution{{UserID: "user-2", Quantity: 40}},
+				}},
+				Members: []models.MemberContribution{{UserID: "user-2", Quantity: 40}},
+			}, nil
+		},
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/workspaces/ws-1/materials", nil)
+	rec := httptest.NewRecorder()
+	newWorkspaceRouter(service, "user-123").ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	materials, _ := body["materials"].([]interface{})
+	if len(materials) != 1 {
+		t.Fatalf("expected one material, got %v", body["materials"])
+	}
+	alloy := materials[0].(map[string]interface{})
+	if alloy["contributed"] != 40.0 || alloy["remaining"] != 60.0 {
+		t.Errorf("expected 40 contributed and 60 remaining, got %v", alloy)
+	}
+	if members, _ := body["members"].([]interface{}); len(members) != 1 {
+		t.Errorf("expected one member total, got %v", body["members"])
+	}
+}
+
+func TestWorkspaceHandler_LogMaterialContribution(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		serviceErr     error
+		expectedStatus int
+	}{
+		{name: "logged", body: `{"uniqueName":"/Lotus/Alloy","quantity":50}`, expectedStatus: http.StatusCreated},
+		{name: "invalid body", body: `{`, expectedStatus: http.StatusBadRequest},
+		{name: "missing uniqueName", body: `{"quantity":50}`, expectedStatus: http.StatusBadRequest},
+		{name: "invalid quantity", body: `{"uniqueName":"/Lotus/Alloy","quantity":0}`, serviceErr: services.ErrInvalidWorkspace, expectedStatus: http.StatusBadRequest},
+		{name: "material not needed", body: `{"uniqueName":"/Lotus/Ferrite","quantity":5}`, serviceErr: services.ErrWorkspaceMaterialNotNeeded, expectedStatus: http.StatusBadRequest},
+		{name: "not a member", body: `{"uniqueName":"/Lotus/Alloy","quantity":50}`, serviceErr: services.ErrWorkspaceNotFound, expectedStatus: http.StatusNotFound},
+		{name: "service error", body: `{"uniqueName":"/Lotus/Alloy","quantity":50}`, serviceErr: errors.New("db down"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got models.WorkspaceMaterialContributionRequest
+			service := &mocks.MockWorkspaceService{
+				LogMaterialContributionFunc: func(ctx context.Context, userID, id string, req models.WorkspaceMaterialContributionRequest) (*models.WorkspaceContributionEvent, error) {
+					got = req
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return &models.WorkspaceContributionEvent{UserID: userID, Kind: models.ContributionKindMaterial, UniqueName: req.UniqueName, Quantity: req.Quantity}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/workspaces/ws-1/material-contributions", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			newWorkspaceRouter(service, "user-123").ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if tt.expectedStatus != http.StatusCreated {
+				return
+			}
+			if got.UniqueName != "/Lotus/Alloy" || got.Quantity != 50 {
+				t.Errorf("expected the request to be passed through, got %+v", got)
+			}
+			var body map[string]interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if body["userId"] != "user-123" || body["kind"] != models.ContributionKindMaterial {
+				t.Errorf("expected the caller's material contribution, got %v", body)
+			}
+		})
+	}
+}
+
+func TestWorkspaceHandler_GetActivity(t *testing.T) {
+	var gotLimit int
+	service := &mocks.MockWorkspaceService{
+		ListActivityFunc: func(ctx context.Context, userID, id string, limit int) ([]models.WorkspaceContributionEvent, error) {
+			gotLimit = limit
+			return []models.WorkspaceContributionEvent{
+				{UserID: "user-2", Kind: models.ContributionKindItem, UniqueName: "/Lotus/Reactor", Quantity: -1},
+				{UserID: "user-123", Kind: models.ContributionKindMaterial, UniqueName: "/Lotus/Alloy", Quantity: 50},
+			}, nil
+		},
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/workspaces/ws-1/activity?limit=10", nil)
+	rec := httptest.NewRecorder()
+	newWorkspaceRouter(service, "user-123").ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if gotLimit != 10 {
+		t.Errorf("expected limit 10, got %d", gotLimit)
+	}
+	var body []map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(body) != 2 || body[0]["quantity"] != -1.0 || body[1]["kind"] != models.ContributionKindMaterial {
+		t.Errorf("expected both events in order, got %v", body)
+	}
+
+	service.ListActivityFunc = func(ctx context.Context, userID, id string, limit int) ([]models.WorkspaceContributionEvent, error) {
+		return nil, services.ErrWorkspaceNotFound
+	}
+	rec = httptest.NewRecorder()
+	newWorkspaceRouter(service, "user-123").ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/workspaces/ws-1/activity", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,94 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/dto"
+	"github.com/graytonio/warframe-wishlist/internal/ids"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/notify"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+)
+
+const (
+	bridgeBufferSize = 1000
+	bridgeTimeout    = 10 * time.Second
+)
+
+// WebhookBridge forwards events to an external extension as signed webhooks,
+// for extensions that do not run in the server. Events are queued and sent
+// one at a time from Run, so slow endpoints never hold up requests; when the
+// queue is full, or a send fails, the event is dropped. It only causes side
+// effects: the bridge never enriches responses.
+type WebhookBridge struct {
+	url     string
+	secret  string
+	client  *http.Client
+	ids     ids.Generator
+	events  chan Event
+	dropped atomic.Int64
+}
+
+// NewWebhookBridge returns a bridge to url, signing with secret as
+// notification webhooks are (see notify.Sign).
+func NewWebhookBridge(url, secret string) *WebhookBridge {
+	return &WebhookBridge{
+		url:    url,
+		secret: secret,
+		client: notify.NewHTTPClient(bridgeTimeout, true),
+		ids:    ids.Default,
+		events: make(chan Event, bridgeBufferSize),
+	}
+}
+
+// SetIDGenerator sets the generator of delivery IDs.
+func (b *WebhookBridge) SetIDGenerator(gen ids.Generator) {
+	b.ids = gen
+}
+
+// Hook queues each event it is given; register it for AllEvents.
+func (b *WebhookBridge) Hook(ctx context.Context, event Event) (any, error) {
+	select {
+	case b.events <- event:
+	default:
+		if dropped := b.dropped.Add(1); dropped%100 == 1 {
+			logger.Warn(ctx, "hooks: WebhookBridge - queue full, dropping events", "dropped", dropped)
+		}
+	}
+	return nil, nil
+}
+
+// Dropped returns the number of events lost to a full queue or a failed send.
+func (b *WebhookBridge) Dropped() int64 { return b.dropped.Load() }
+
+// Run sends queued events until ctx is done.
+func (b *WebhookBridge) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-b.events:
+			if err := b.send(ctx, event); err != nil {
+				b.dropped.Add(1)
+				logger.Warn(ctx, "hooks: WebhookBridge - send failed", "event", event.Name, "error", err)
+			}
+		}
+	}
+}
+
+func (b *WebhookBridge) send(ctx context.Context, event Event) error {
+	// Bridged events carry the API's representation of their data.
+	if materials, ok := event.Data.(*models.MaterialsResponse); ok {
+		event.Data = dto.NewMaterialsSummary(materials)
+	}
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = notify.SendWebhook(ctx, b.client, b.url, b.secret, event.Name, b.ids.NewID(), body)
+	return err
+}
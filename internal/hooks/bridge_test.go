@@ -0,0 +1,80 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/notify"
+)
+
+func TestWebhookBridge(t *testing.T) {
+	received := make(chan *http.Request, 2)
+	bodies := make(chan []byte, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- r
+		bodies <- body
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	bridge := NewWebhookBridge(server.URL, "s3cret")
+	go bridge.Run(ctx)
+
+	registry := NewRegistry()
+	registry.Register("bridge", AllEvents, bridge.Hook)
+	registry.Emit(ctx, Event{Name: EventItemAddedToWishlist, UserID: "user-123", Data: ItemAdded{UniqueName: "/Lotus/Volt", Quantity: 1}})
+	extensions := registry.Emit(ctx, Event{Name: EventMaterialsResolved, UserID: "user-123", Data: &models.MaterialsResponse{TotalCredits: 100}})
+	if extensions != nil {
+		t.Errorf("expected the bridge not to enrich responses, got %v", extensions)
+	}
+
+	for _, want := range []string{EventItemAddedToWishlist, EventMaterialsResolved} {
+		var req *http.Request
+		var body []byte
+		select {
+		case req = <-received:
+			body = <-bodies
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for %s", want)
+		}
+		if req.Header.Get(notify.EventHeader) != want || req.Header.Get(notify.SignatureHeader) != notify.Sign("s3cret", body) {
+			t.Errorf("unexpected headers %v for %s", req.Header, want)
+		}
+
+		var event struct {
+			Event  string         `json:"event"`
+			UserID string         `json:"userId"`
+			Data   map[string]any `json:"data"`
+		}
+		if err := json.Unmarshal(body, &event); err != nil {
+			t.Fatalf("invalid body %s: %v", body, err)
+		}
+		if event.Event != want || event.UserID != "user-123" {
+			t.Errorf("unexpected event %s", body)
+		}
+		if want == EventMaterialsResolved {
+			if _, ok := event.Data["credits"]; !ok {
+				t.Errorf("expected the materials in their API form, got %s", body)
+			}
+		}
+	}
+}
+
+func TestWebhookBridge_DropsWhenFull(t *testing.T) {
+	bridge := NewWebhookBridge("http://127.0.0.1:1", "")
+	for i := 0; i < bridgeBufferSize+3; i++ {
+		bridge.Hook(context.Background(), Event{Name: EventItemAddedToWishlist})
+	}
+	if bridge.Dropped() != 3 {
+		t.Errorf("expected 3 dropped events, got %d", bridge.Dropped())
+	}
+}
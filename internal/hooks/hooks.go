@@ -0,0 +1,148 @@
+// Package hooks lets extensions subscribe to domain events, such as an item
+// being added to a wishlist, without patching the services that raise them.
+// A hook can cause side effects, or add to the response the event was raised
+// for under its name in the response's extensions.
+//
+// Extensions register their hooks from an init function in their own file:
+//
+//	func init() {
+//		hooks.Register("relic-prices", hooks.EventMaterialsResolved, addRelicPrices)
+//	}
+package hooks
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+)
+
+// Events raised by the services.
+const (
+	// EventItemAddedToWishlist is raised after an item is added to a
+	// wishlist. Its Data is an ItemAdded.
+	EventItemAddedToWishlist = "wishlist.itemAdded"
+	// EventMaterialsResolved is raised before a user's materials are
+	// returned. Its Data is the *models.MaterialsResponse, and hooks may
+	// enrich it.
+	EventMaterialsResolved = "materials.resolved"
+)
+
+// AllEvents subscribes a hook to every event.
+const AllEvents = "*"
+
+// Event is a domain event. Data must be treated as read-only: it may be
+// shared with caches and other hooks.
+type Event struct {
+	Name       string    `json:"event"`
+	UserID     string    `json:"userId,omitempty"`
+	OccurredAt time.Time `json:"occurredAt"`
+	Data       any       `json:"data"`
+}
+
+// ItemAdded is the Data of EventItemAddedToWishlist.
+type ItemAdded struct {
+	UniqueName string `json:"uniqueName"`
+	Quantity   int    `json:"quantity"`
+}
+
+// Hook handles an event. A non-nil result enriches the response the event
+// was raised for, under the hook's name; events raised without a response
+// ignore it. Hooks run synchronously in registration order, so they must be
+// quick; an error or panic is logged and never fails the request.
+type Hook func(ctx context.Context, event Event) (any, error)
+
+type registration struct {
+	name string
+	hook Hook
+}
+
+// Registry holds the hooks subscribed to each event. A nil *Registry has no
+// hooks.
+type Registry struct {
+	mu    sync.RWMutex
+	hooks map[string][]registration
+}
+
+func NewRegistry() *Registry {
+	return &Registry{hooks: make(map[string][]registration)}
+}
+
+// Default is the registry Register adds to and the server raises events on.
+var Default = NewRegistry()
+
+// Register subscribes hook to event on Default.
+func Register(name, event string, hook Hook) {
+	Default.Register(name, event, hook)
+}
+
+// Register subscribes hook, named name, to event, or to every event for
+// AllEvents. Names must be unique per event, since they key the hook's
+// enrichment.
+func (r *Registry) Register(name, event string, hook Hook) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.hooks[event] {
+		if existing.name == name {
+			panic(fmt.Sprintf("hooks: %q is already registered for %s", name, event))
+		}
+	}
+	r.hooks[event] = append(r.hooks[event], registration{name: name, hook: hook})
+}
+
+// Has reports whether any hook is subscribed to event, so callers can skip
+// building events nobody handles.
+func (r *Registry) Has(event string) bool {
+	if r == nil {
+		return false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.hooks[event]) > 0 || len(r.hooks[AllEvents]) > 0
+}
+
+// Emit runs the hooks subscribed to event.Name, then those subscribed to
+// every event, and returns their enrichments by hook name, or nil if there
+// are none. A zero OccurredAt is set to now.
+func (r *Registry) Emit(ctx context.Context, event Event) map[string]any {
+	if r == nil {
+		return nil
+	}
+	r.mu.RLock()
+	hooks := append(append([]registration(nil), r.hooks[event.Name]...), r.hooks[AllEvents]...)
+	r.mu.RUnlock()
+	if len(hooks) == 0 {
+		return nil
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	var extensions map[string]any
+	for _, registered := range hooks {
+		value, err := run(ctx, registered.hook, event)
+		if err != nil {
+			logger.Error(ctx, "hooks: hook failed", "hook", registered.name, "event", event.Name, "error", err)
+			continue
+		}
+		if value != nil {
+			if extensions == nil {
+				extensions = make(map[string]any)
+			}
+			extensions[registered.name] = value
+		}
+	}
+	return extensions
+}
+
+// run calls hook, turning a panic into an error.
+func run(ctx context.Context, hook Hook, event Event) (value any, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			value, err = nil, fmt.Errorf("panic: %v", recovered)
+		}
+	}()
+	return hook(ctx, event)
+}
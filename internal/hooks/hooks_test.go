@@ -0,0 +1,71 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestRegistry_Emit(t *testing.T) {
+	registry := NewRegistry()
+	var order []string
+	record := func(name string, value any, err error) Hook {
+		return func(ctx context.Context, event Event) (any, error) {
+			order = append(order, name)
+			return value, err
+		}
+	}
+	registry.Register("first", EventMaterialsResolved, record("first", "a", nil))
+	registry.Register("failing", EventMaterialsResolved, record("failing", "ignored", errors.New("boom")))
+	registry.Register("panicking", EventMaterialsResolved, func(ctx context.Context, event Event) (any, error) {
+		order = append(order, "panicking")
+		panic("boom")
+	})
+	registry.Register("all", AllEvents, record("all", nil, nil))
+	registry.Register("other", EventItemAddedToWishlist, record("other", "b", nil))
+
+	extensions := registry.Emit(context.Background(), Event{Name: EventMaterialsResolved})
+
+	if want := []string{"first", "failing", "panicking", "all"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("expected hooks to run as %v, got %v", want, order)
+	}
+	if want := map[string]any{"first": "a"}; !reflect.DeepEqual(extensions, want) {
+		t.Errorf("expected extensions %v, got %v", want, extensions)
+	}
+}
+
+func TestRegistry_EmitWithoutHooks(t *testing.T) {
+	var nilRegistry *Registry
+	if nilRegistry.Has(EventMaterialsResolved) || nilRegistry.Emit(context.Background(), Event{Name: EventMaterialsResolved}) != nil {
+		t.Error("expected a nil registry to have no hooks")
+	}
+
+	registry := NewRegistry()
+	registry.Register("side-effect", EventItemAddedToWishlist, func(ctx context.Context, event Event) (any, error) {
+		if event.OccurredAt.IsZero() {
+			t.Error("expected OccurredAt to be set")
+		}
+		return nil, nil
+	})
+	if registry.Has(EventMaterialsResolved) || !registry.Has(EventItemAddedToWishlist) {
+		t.Error("unexpected Has result")
+	}
+	if extensions := registry.Emit(context.Background(), Event{Name: EventItemAddedToWishlist}); extensions != nil {
+		t.Errorf("expected no extensions, got %v", extensions)
+	}
+}
+
+func TestRegistry_RegisterTwicePanics(t *testing.T) {
+	registry := NewRegistry()
+	hook := func(ctx context.Context, event Event) (any, error) { return nil, nil }
+	registry.Register("dup", EventMaterialsResolved, hook)
+	registry.Register("dup", EventItemAddedToWishlist, hook)
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected registering a name twice for one event to panic")
+		}
+	}()
+	registry.Register("dup", EventMaterialsResolved, hook)
+}
@@ -0,0 +1,69 @@
+// Package ids generates the identifiers and tokens the server hands out:
+// request IDs, webhook delivery IDs, share tokens and webhook signing
+// secrets. Every subsystem takes a Generator, so they all follow the one
+// strategy ID_STRATEGY selects.
+package ids
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Generator makes new IDs and tokens.
+type Generator interface {
+	// NewID returns a new unique ID for correlating records, such as a
+	// request ID. IDs are not secret.
+	NewID() string
+	// NewToken returns a new unguessable token, such as a share token or a
+	// signing secret, carrying at least bits random bits.
+	NewToken(bits int) (string, error)
+}
+
+// Strategy names a Generator for ID_STRATEGY.
+type Strategy string
+
+const (
+	// StrategyULID makes ULIDs: sortable by creation time to the
+	// millisecond, and unique across instances. Tokens start with the same
+	// timestamp as IDs.
+	StrategyULID Strategy = "ulid"
+	// StrategyRandom makes IDs of 128 random bits in hex and tokens of
+	// random bits alone, URL-safe base64 encoded, as before ULIDs.
+	StrategyRandom Strategy = "random"
+)
+
+// Default is the Generator subsystems use until given another.
+var Default Generator = NewULIDGenerator()
+
+// Parse returns the Generator of strategy, as read from ID_STRATEGY; empty
+// means StrategyULID.
+func Parse(strategy string) (Generator, error) {
+	switch Strategy(strings.TrimSpace(strategy)) {
+	case "", StrategyULID:
+		return NewULIDGenerator(), nil
+	case StrategyRandom:
+		return RandomGenerator{}, nil
+	default:
+		return nil, fmt.Errorf("unknown ID strategy %q (want %q or %q)", strategy, StrategyULID, StrategyRandom)
+	}
+}
+
+// RandomGenerator is the StrategyRandom Generator.
+type RandomGenerator struct{}
+
+func (RandomGenerator) NewID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func (RandomGenerator) NewToken(bits int) (string, error) {
+	b := make([]byte, (bits+7)/8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,124 @@
+package ids
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEncode(t *testing.T) {
+	var id [16]byte
+	putTimestamp(id[:6], 1469918176385)
+	if got, want := encode(id[:]), "01ARYZ6S41"+strings.Repeat("0", 16); got != want {
+		t.Errorf("encode() = %q, want %q", got, want)
+	}
+	max := [16]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+	if got, want := encode(max[:]), "7"+strings.Repeat("Z", 25); got != want {
+		t.Errorf("encode() = %q, want %q", got, want)
+	}
+}
+
+func TestULIDGenerator_NewID(t *testing.T) {
+	clock := time.UnixMilli(1469918176385)
+	g := NewULIDGenerator()
+	g.now = func() time.Time { return clock }
+
+	var made []string
+	for i := 0; i < 100; i++ {
+		if i == 50 {
+			// The clock stepping back must not break the order.
+			clock = clock.Add(-time.Second)
+		}
+		id := g.NewID()
+		if len(id) != 26 || !strings.HasPrefix(id, "01ARYZ6S41") {
+			t.Fatalf("NewID() = %q, want a ULID of the first timestamp", id)
+		}
+		made = append(made, id)
+	}
+	if !sort.StringsAreSorted(made) {
+		t.Errorf("IDs made in one millisecond are not sorted: %v", made)
+	}
+
+	clock = time.UnixMilli(1469918176385 + 1000)
+	if id := g.NewID(); id <= made[len(made)-1] {
+		t.Errorf("NewID() = %q, want after %q", id, made[len(made)-1])
+	}
+}
+
+func TestULIDGenerator_Overflow(t *testing.T) {
+	g := NewULIDGenerator()
+	g.now = func() time.Time { return time.UnixMilli(1000) }
+	first := g.NewID()
+	for i := range g.last {
+		g.last[i] = 0xff
+	}
+	if id := g.NewID(); id <= first || g.lastMs != 1001 {
+		t.Errorf("NewID() = %q at %d, want the next millisecond", id, g.lastMs)
+	}
+}
+
+func TestULIDGenerator_NewToken(t *testing.T) {
+	g := NewULIDGenerator()
+	g.now = func() time.Time { return time.UnixMilli(1469918176385) }
+
+	token, err := g.NewToken(128)
+	if err != nil {
+		t.Fatalf("NewToken() error = %v", err)
+	}
+	// 48 timestamp bits and 128 random bits, 5 bits a character.
+	if len(token) != 36 || !strings.HasPrefix(token, "01ARYZ6S41") {
+		t.Errorf("NewToken(128) = %q", token)
+	}
+	other, _ := g.NewToken(128)
+	if other == token {
+		t.Errorf("NewToken() repeated %q", token)
+	}
+}
+
+func TestRandomGenerator(t *testing.T) {
+	g := RandomGenerator{}
+	if id, err := hex.DecodeString(g.NewID()); err != nil || len(id) != 16 {
+		t.Errorf("NewID() = %x, %v; want 16 hex bytes", id, err)
+	}
+	token, err := g.NewToken(256)
+	if err != nil {
+		t.Fatalf("NewToken() error = %v", err)
+	}
+	if b, err := base64.RawURLEncoding.DecodeString(token); err != nil || len(b) != 32 {
+		t.Errorf("NewToken(256) = %q, want 32 bytes", token)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		strategy string
+		want     Generator
+		wantErr  bool
+	}{
+		{strategy: "", want: &ULIDGenerator{}},
+		{strategy: "ulid", want: &ULIDGenerator{}},
+		{strategy: " random ", want: RandomGenerator{}},
+		{strategy: "uuid", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.strategy, func(t *testing.T) {
+			got, err := Parse(tt.strategy)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse(%q) error = %v, wantErr %v", tt.strategy, err, tt.wantErr)
+			}
+			switch tt.want.(type) {
+			case *ULIDGenerator:
+				if _, ok := got.(*ULIDGenerator); !ok {
+					t.Errorf("Parse(%q) = %T, want *ULIDGenerator", tt.strategy, got)
+				}
+			case RandomGenerator:
+				if _, ok := got.(RandomGenerator); !ok {
+					t.Errorf("Parse(%q) = %T, want RandomGenerator", tt.strategy, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package ids
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"sync"
+	"time"
+)
+
+// crockford is the Crockford base32 alphabet ULIDs are written in: no I, L,
+// O or U, and sorting as the values do.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ULIDGenerator is the StrategyULID Generator. Its IDs are ULIDs: a 48-bit
+// millisecond timestamp and 80 random bits, 26 characters of Crockford
+// base32. IDs made in the same millisecond increment the random bits of the
+// previous one, so an instance's IDs sort in the order they were made even
+// when the clock steps back. It is safe for concurrent use.
+type ULIDGenerator struct {
+	now func() time.Time
+
+	mu     sync.Mutex
+	lastMs uint64
+	last   [10]byte
+}
+
+func NewULIDGenerator() *ULIDGenerator {
+	return &ULIDGenerator{now: time.Now}
+}
+
+func (g *ULIDGenerator) NewID() string {
+	ms := uint64(g.now().UnixMilli())
+
+	g.mu.Lock()
+	if ms <= g.lastMs && increment(g.last[:]) {
+		ms = g.lastMs
+	} else {
+		// A new millisecond, or the random bits of this one ran out.
+		ms = max(ms, g.lastMs+1)
+		rand.Read(g.last[:])
+	}
+	g.lastMs = ms
+	var id [16]byte
+	putTimestamp(id[:6], ms)
+	copy(id[6:], g.last[:])
+	g.mu.Unlock()
+
+	return encode(id[:])
+}
+
+// NewToken returns a ULID timestamp followed by bits fresh random bits, so
+// tokens sort by creation like IDs without one hinting at the next.
+func (g *ULIDGenerator) NewToken(bits int) (string, error) {
+	var timestamp [6]byte
+	putTimestamp(timestamp[:], uint64(g.now().UnixMilli()))
+	random := make([]byte, (bits+7)/8)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	// Encoded apart, the timestamp reads as the first 10 characters of a
+	// ULID.
+	return encode(timestamp[:]) + encode(random), nil
+}
+
+func putTimestamp(b []byte, ms uint64) {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], ms)
+	copy(b, buf[2:])
+}
+
+// increment adds one to the big-endian b and reports false when it wraps.
+func increment(b []byte) bool {
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i]++
+		if b[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// encode writes b in Crockford base32, most significant bits first, padding
+// with leading zero bits to whole characters.
+func encode(b []byte) string {
+	n := (len(b)*8 + 4) / 5
+	out := make([]byte, n)
+	var acc uint
+	var accBits uint
+	i := n - 1
+	for j := len(b) - 1; j >= 0; j-- {
+		acc |= uint(b[j]) << accBits
+		accBits += 8
+		for accBits >= 5 {
+			out[i] = crockford[acc&31]
+			acc >>= 5
+			accBits -= 5
+			i--
+		}
+	}
+	if i >= 0 {
+		out[i] = crockford[acc&31]
+	}
+	return string(out)
+}
@@ -0,0 +1,89 @@
+// Package inventory reads inventory exports and extracts the blueprints and
+// countable items the app tracks. Each supported export format has an
+// adapter; the game's own inventory JSON is the default.
+package inventory
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+// FormatGame is the inventory JSON the game's API returns, with owned
+// blueprints under "Recipes" and resources and crafted components under
+// "MiscItems".
+const FormatGame = "game"
+
+var (
+	ErrUnsupportedFormat = fmt.Errorf("format must be one of: %s", strings.Join(Formats(), ", "))
+	ErrInvalidInventory  = errors.New("invalid inventory")
+)
+
+// adapters maps each format to its parser.
+var adapters = map[string]func(data []byte) (*models.ProfileInventory, error){
+	FormatGame: parseGame,
+}
+
+// Formats returns the supported formats in order.
+func Formats() []string {
+	formats := make([]string, 0, len(adapters))
+	for format := range adapters {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// Parse reads data as an export in format. An empty format means
+// FormatGame. Errors wrap ErrUnsupportedFormat or ErrInvalidInventory.
+func Parse(format string, data []byte) (*models.ProfileInventory, error) {
+	if format == "" {
+		format = FormatGame
+	}
+	parse, ok := adapters[format]
+	if !ok {
+		return nil, ErrUnsupportedFormat
+	}
+	return parse(data)
+}
+
+// gameItem is one stack in the game's inventory.
+type gameItem struct {
+	ItemType  string `json:"ItemType"`
+	ItemCount int    `json:"ItemCount"`
+}
+
+type gameInventory struct {
+	Recipes   *[]gameItem `json:"Recipes"`
+	MiscItems *[]gameItem `json:"MiscItems"`
+}
+
+func parseGame(data []byte) (*models.ProfileInventory, error) {
+	var raw gameInventory
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInventory, err)
+	}
+	if raw.Recipes == nil && raw.MiscItems == nil {
+		return nil, fmt.Errorf("%w: no Recipes or MiscItems, not a game inventory", ErrInvalidInventory)
+	}
+
+	inventory := &models.ProfileInventory{
+		Blueprints: []models.InventoryCount{},
+		Items:      []models.InventoryCount{},
+	}
+	if raw.Recipes != nil {
+		for _, item := range *raw.Recipes {
+			inventory.Blueprints = append(inventory.Blueprints, models.InventoryCount{UniqueName: item.ItemType, Count: item.ItemCount})
+		}
+	}
+	if raw.MiscItems != nil {
+		for _, item := range *raw.MiscItems {
+			inventory.Items = append(inventory.Items, models.InventoryCount{UniqueName: item.ItemType, Count: item.ItemCount})
+		}
+	}
+	return inventory, nil
+}
@@ -0,0 +1,61 @@
+package inventory
+
+import (
+	"errors"
+	"testing"
+)
+
+// sampleInventory trims a game inventory to what is parsed, plus a section
+// that is not.
+const sampleInventory = `{
+	"SubscribedToEmails": 0,
+	"Recipes": [
+		{"ItemCount": 1, "ItemType": "/Lotus/Types/Recipes/WarframeRecipes/RhinoBlueprint"}
+	],
+	"MiscItems": [
+		{"ItemCount": 12000, "ItemType": "/Lotus/Types/Items/MiscItems/Ferrite"},
+		{"ItemCount": 1, "ItemType": "/Lotus/Types/Recipes/WarframeRecipes/RhinoChassisComponent"}
+	],
+	"LongGuns": [{"ItemType": "/Lotus/Weapons/Tenno/Rifle/Rifle", "XP": 450000}]
+}`
+
+func TestParse_Game(t *testing.T) {
+	for _, format := range []string{"", FormatGame} {
+		inv, err := Parse(format, []byte(sampleInventory))
+		if err != nil {
+			t.Fatalf("format %q: unexpected error: %v", format, err)
+		}
+		if len(inv.Blueprints) != 1 || inv.Blueprints[0].UniqueName != "/Lotus/Types/Recipes/WarframeRecipes/RhinoBlueprint" || inv.Blueprints[0].Count != 1 {
+			t.Errorf("unexpected blueprints %+v", inv.Blueprints)
+		}
+		if len(inv.Items) != 2 || inv.Items[0].UniqueName != "/Lotus/Types/Items/MiscItems/Ferrite" || inv.Items[0].Count != 12000 {
+			t.Errorf("unexpected items %+v", inv.Items)
+		}
+	}
+
+	inv, err := Parse(FormatGame, []byte(`{"MiscItems": []}`))
+	if err != nil || inv.Blueprints == nil || len(inv.Items) != 0 {
+		t.Errorf("expected an empty inventory, got %+v (err %v)", inv, err)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		data   string
+		want   error
+	}{
+		{name: "unknown format", format: "csv", data: sampleInventory, want: ErrUnsupportedFormat},
+		{name: "not json", format: FormatGame, data: "Ferrite,12000", want: ErrInvalidInventory},
+		{name: "not an inventory", format: FormatGame, data: `{"format": "warframe-wishlist"}`, want: ErrInvalidInventory},
+		{name: "wrong shape", format: FormatGame, data: `{"MiscItems": {"Ferrite": 1}}`, want: ErrInvalidInventory},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(tt.format, []byte(tt.data)); !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+// Package itemsource reads the WFCD warframe-items JSON export, from its
+// published URL or a local directory, and validates it for syncing into the
+// item collections.
+package itemsource
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/graytonio/warframe-wishlist/internal/repository/memory"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// DefaultSource is the JSON export of the WFCD warframe-items package.
+const DefaultSource = "https://raw.githubusercontent.com/WFCD/warframe-items/master/data/json"
+
+// maxDataFileSize bounds a downloaded data file; the largest is about 12 MB.
+const maxDataFileSize = 128 << 20
+
+// DataFiles are the per-category files of the export, one per collection in
+// repository.ItemCollections. All.json duplicates them and i18n.json holds
+// translations, so neither is synced.
+var DataFiles = []string{
+	"Arcanes.json", "Arch-Gun.json", "Arch-Melee.json", "Archwing.json",
+	"Enemy.json", "Fish.json", "Gear.json", "Glyphs.json", "Melee.json",
+	"Misc.json", "Mods.json", "Node.json", "Pets.json", "Primary.json",
+	"Quests.json", "Railjack.json", "Relics.json", "Resources.json",
+	"Secondary.json", "SentinelWeapons.json", "Sentinels.json", "Sigils.json",
+	"Skins.json", "Warframes.json",
+}
+
+// Dataset is a validated data file ready to be synced.
+type Dataset struct {
+	File       string
+	Collection string
+	Items      []bson.M
+}
+
+// Load reads and validates every data file from source, failing before
+// anything is written if one is missing or invalid.
+func Load(ctx context.Context, client *http.Client, source string) ([]Dataset, error) {
+	datasets := make([]Dataset, 0, len(DataFiles))
+	for _, file := range DataFiles {
+		data, err := ReadDataFile(ctx, client, source, file)
+		if err != nil {
+			return nil, err
+		}
+		items, err := ParseItems(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", file, err)
+		}
+		datasets = append(datasets, Dataset{
+			File:       file,
+			Collection: memory.CollectionNameForFile(file),
+			Items:      items,
+		})
+	}
+	return datasets, nil
+}
+
+func isRemoteSource(source string) bool {
+	return strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+}
+
+// ReadDataFile reads file from source, a base URL or a local directory.
+func ReadDataFile(ctx context.Context, client *http.Client, source, file string) ([]byte, error) {
+	if !isRemoteSource(source) {
+		return os.ReadFile(filepath.Join(source, file))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(source, "/")+"/"+file, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download %s: status %d", file, resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxDataFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("download %s: %w", file, err)
+	}
+	if len(data) > maxDataFileSize {
+		return nil, fmt.Errorf("download %s: larger than %d bytes", file, maxDataFileSize)
+	}
+	return data, nil
+}
+
+// ParseItems validates a data file and returns its items. The file must be a
+// non-empty array of objects, each with a unique, non-empty uniqueName: an
+// empty or truncated export would otherwise archive the whole collection.
+func ParseItems(data []byte) ([]bson.M, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var raw []any
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("expected a JSON array of items: %w", err)
+	}
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("no items")
+	}
+
+	items := make([]bson.M, 0, len(raw))
+	seen := make(map[string]bool, len(raw))
+	for i, value := range raw {
+		item, ok := value.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("item %d is not an object", i)
+		}
+		uniqueName, _ := item["uniqueName"].(string)
+		if uniqueName == "" {
+			return nil, fmt.Errorf("item %d has no uniqueName", i)
+		}
+		if seen[uniqueName] {
+			return nil, fmt.Errorf("duplicate uniqueName %q", uniqueName)
+		}
+		seen[uniqueName] = true
+		items = append(items, bson.M(normalizeNumbers(item).(map[string]any)))
+	}
+	return items, nil
+}
+
+// normalizeNumbers converts the json.Numbers in value to int64 or float64 so
+// they are stored as BSON numbers, integers as integers like the Python sync
+// did, rather than as strings.
+func normalizeNumbers(value any) any {
+	switch v := value.(type) {
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]any:
+		for key, field := range v {
+			v[key] = normalizeNumbers(field)
+		}
+		return v
+	case []any:
+		for i, element := range v {
+			v[i] = normalizeNumbers(element)
+		}
+		return v
+	default:
+		return value
+	}
+}
@@ -0,0 +1,153 @@
+package itemsource
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+	"github.com/graytonio/warframe-wishlist/internal/repository/memory"
+)
+
+func TestDataFiles_CoverItemCollections(t *testing.T) {
+	var collections []string
+	for _, file := range DataFiles {
+		collections = append(collections, memory.CollectionNameForFile(file))
+	}
+	expected := slices.Clone(repository.ItemCollections)
+	slices.Sort(collections)
+	slices.Sort(expected)
+
+	if !slices.Equal(collections, expected) {
+		t.Errorf("expected data files for %v, got %v", expected, collections)
+	}
+}
+
+func TestParseItems(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		count   int
+		wantErr string
+	}{
+		{name: "valid", data: `[{"uniqueName":"/Lotus/A","name":"A"},{"uniqueName":"/Lotus/B"}]`, count: 2},
+		{name: "not an array", data: `{"uniqueName":"/Lotus/A"}`, wantErr: "expected a JSON array"},
+		{name: "truncated", data: `[{"uniqueName":"/Lotus/A"}`, wantErr: "expected a JSON array"},
+		{name: "empty", data: `[]`, wantErr: "no items"},
+		{name: "not an object", data: `[{"uniqueName":"/Lotus/A"},"B"]`, wantErr: "item 1 is not an object"},
+		{name: "missing uniqueName", data: `[{"name":"A"}]`, wantErr: "item 0 has no uniqueName"},
+		{name: "non-string uniqueName", data: `[{"uniqueName":1}]`, wantErr: "item 0 has no uniqueName"},
+		{name: "duplicate uniqueName", data: `[{"uniqueName":"/Lotus/A"},{"uniqueName":"/Lotus/A"}]`, wantErr: `duplicate uniqueName "/Lotus/A"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := ParseItems([]byte(tt.data))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(items) != tt.count {
+				t.Errorf("expected %d items, got %d", tt.count, len(items))
+			}
+		})
+	}
+}
+
+func TestParseItems_NormalizesNumbers(t *testing.T) {
+	items, err := ParseItems([]byte(`[{"uniqueName":"/Lotus/A","buildPrice":15000,"chance":0.25,"components":[{"itemCount":2}]}]`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	item := items[0]
+	if v, ok := item["buildPrice"].(int64); !ok || v != 15000 {
+		t.Errorf("expected buildPrice int64 15000, got %T %v", item["buildPrice"], item["buildPrice"])
+	}
+	if v, ok := item["chance"].(float64); !ok || v != 0.25 {
+		t.Errorf("expected chance float64 0.25, got %T %v", item["chance"], item["chance"])
+	}
+	component := item["components"].([]any)[0].(map[string]any)
+	if v, ok := component["itemCount"].(int64); !ok || v != 2 {
+		t.Errorf("expected nested itemCount int64 2, got %T %v", component["itemCount"], component["itemCount"])
+	}
+}
+
+func TestReadDataFile_Remote(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/data/json/Arcanes.json" {
+			http.NotFound(w, r)
+			return
+		}
+		io.WriteString(w, `[{"uniqueName":"/Lotus/A"}]`)
+	}))
+	defer server.Close()
+
+	data, err := ReadDataFile(context.Background(), server.Client(), server.URL+"/data/json/", "Arcanes.json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(data) != `[{"uniqueName":"/Lotus/A"}]` {
+		t.Errorf("unexpected body %q", data)
+	}
+
+	_, err = ReadDataFile(context.Background(), server.Client(), server.URL+"/data/json", "Mods.json")
+	if err == nil || !strings.Contains(err.Error(), "status 404") {
+		t.Errorf("expected status 404 error, got %v", err)
+	}
+}
+
+func TestLoad_FromDirectory(t *testing.T) {
+	dir := t.TempDir()
+	for _, file := range DataFiles {
+		data := `[{"uniqueName":"/Lotus/` + file + `"}]`
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	datasets, err := Load(context.Background(), http.DefaultClient, dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(datasets) != len(DataFiles) {
+		t.Fatalf("expected %d datasets, got %d", len(DataFiles), len(datasets))
+	}
+	for _, ds := range datasets {
+		if ds.File == "Arch-Gun.json" && ds.Collection != "arch_gun" {
+			t.Errorf("expected Arch-Gun.json to sync into arch_gun, got %s", ds.Collection)
+		}
+		if len(ds.Items) != 1 {
+			t.Errorf("%s: expected 1 item, got %d", ds.File, len(ds.Items))
+		}
+	}
+}
+
+func TestLoad_FailsOnInvalidFile(t *testing.T) {
+	dir := t.TempDir()
+	for _, file := range DataFiles {
+		data := `[{"uniqueName":"/Lotus/` + file + `"}]`
+		if file == "Mods.json" {
+			data = `[]`
+		}
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, err := Load(context.Background(), http.DefaultClient, dir)
+	if err == nil || !strings.Contains(err.Error(), "invalid Mods.json: no items") {
+		t.Errorf("expected invalid Mods.json error, got %v", err)
+	}
+}
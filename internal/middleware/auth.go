@@ -2,11 +2,14 @@ package middleware
 
 import (
 	"context"
-	"crypto/ecdsa"
 	"net/http"
 	"strings"
+	"time"
 
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/graytonio/warframe-wishlist/internal/audit"
+	"github.com/graytonio/warframe-wishlist/internal/realtime"
 	"github.com/graytonio/warframe-wishlist/pkg/logger"
 	"github.com/graytonio/warframe-wishlist/pkg/response"
 )
@@ -15,12 +18,54 @@ type contextKey string
 
 const UserIDKey contextKey = "userID"
 
+// RoleKey holds the staff Role of the authenticated user.
+const RoleKey contextKey = "role"
+
+// verificationKey caches a request's token verification, so middleware that
+// verifies it before Authenticate, such as the load shedder, does not have
+// the signature checked twice.
+const verificationKey contextKey = "verification"
+
+// verification is the outcome of verifying authHeader.
+type verification struct {
+	authHeader string
+	sub        string
+	claims     jwt.MapClaims
+	reason     string
+	err        error
+}
+
+// WebSocketTokenPrefix marks the subprotocol that carries the bearer token
+// of a WebSocket handshake, since browsers cannot set its headers.
+const WebSocketTokenPrefix = "bearer."
+
+// UserTracer reports whether support staff enabled tracing for a user.
+type UserTracer interface {
+	IsTraced(ctx context.Context, userID string) bool
+}
+
 type AuthMiddleware struct {
-	jwtPublicKey *ecdsa.PublicKey
+	keys       KeySet
+	demoUserID string
+	tracer     UserTracer
 }
 
-func NewAuthMiddleware(jwtPublicKey *ecdsa.PublicKey) *AuthMiddleware {
-	return &AuthMiddleware{jwtPublicKey: jwtPublicKey}
+// NewAuthMiddleware verifies tokens against keys, choosing the key by the
+// token's kid header so tokens signed before and after a key rotation are
+// both accepted.
+func NewAuthMiddleware(keys KeySet) *AuthMiddleware {
+	return &AuthMiddleware{keys: keys}
+}
+
+// NewDemoAuthMiddleware returns a middleware that skips token verification and
+// authenticates every request as userID. Only used when the server runs in demo mode.
+func NewDemoAuthMiddleware(userID string) *AuthMiddleware {
+	return &AuthMiddleware{demoUserID: userID}
+}
+
+// SetTracer makes requests of users traced by tracer log at every level.
+func (m *AuthMiddleware) SetTracer(tracer UserTracer) {
+	m.tracer = tracer
 }
 
 func (m *AuthMiddleware) Authenticate(next http.Handler) http.Handler {
@@ -28,60 +73,191 @@ func (m *AuthMiddleware) Authenticate(next http.Handler) http.Handler {
 		ctx := r.Context()
 		logger.Debug(ctx, "authenticating request")
 
+		if m.demoUserID != "" {
+			logger.Debug(ctx, "demo mode: authenticating as demo user", "userID", m.demoUserID)
+			ctx = context.WithValue(ctx, UserIDKey, m.demoUserID)
+			ctx = logger.ContextWithUserID(ctx, m.demoUserID)
+			m.serve(next, w, r.WithContext(ctx), m.demoUserID)
+			return
+		}
+
 		authHeader := r.Header.Get("Authorization")
+		if token := webSocketToken(r); authHeader == "" && token != "" {
+			authHeader = "Bearer " + token
+		}
 		if authHeader == "" {
 			logger.Warn(ctx, "authentication failed: missing authorization header")
+			audit.RecordRequest(r, audit.TypeAuthFailure, audit.OutcomeFailure, "missing authorization header", "")
 			response.Error(w, http.StatusUnauthorized, "missing authorization header")
 			return
 		}
 
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
-			logger.Warn(ctx, "authentication failed: invalid authorization header format")
-			response.Error(w, http.StatusUnauthorized, "invalid authorization header format")
+		sub, claims, reason, err := m.verifyRequest(r, authHeader)
+		if reason != "" {
+			if err != nil {
+				logger.Warn(ctx, "authentication failed: "+reason, "error", err)
+			} else {
+				logger.Warn(ctx, "authentication failed: "+reason)
+			}
+			audit.RecordRequest(r, audit.TypeAuthFailure, audit.OutcomeFailure, reason, "")
+			response.Error(w, http.StatusUnauthorized, reason)
 			return
 		}
 
-		tokenString := parts[1]
-		logger.Debug(ctx, "parsing JWT token")
+		role := tokenRole(claims)
+		logger.Debug(ctx, "authentication successful", "userID", sub, "role", role.String())
 
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodECDSA); !ok {
-				return nil, jwt.ErrSignatureInvalid
-			}
-			return m.jwtPublicKey, nil
-		})
+		// Add userID to both the standard context key and the logger context
+		ctx = context.WithValue(ctx, UserIDKey, sub)
+		ctx = context.WithValue(ctx, RoleKey, role)
+		ctx = logger.ContextWithUserID(ctx, sub)
+		m.serve(next, w, r.WithContext(ctx), sub)
+	})
+}
 
-		if err != nil || !token.Valid {
-			logger.Warn(ctx, "authentication failed: invalid token", "error", err)
-			response.Error(w, http.StatusUnauthorized, "invalid token")
-			return
-		}
+// verify checks a bearer authorization header, returning the token's user
+// and claims, or why it was refused and, for a bad token, the error.
+func (m *AuthMiddleware) verify(ctx context.Context, authHeader string) (string, jwt.MapClaims, string, error) {
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
+		return "", nil, "invalid authorization header format", nil
+	}
 
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
-			logger.Warn(ctx, "authentication failed: invalid token claims")
-			response.Error(w, http.StatusUnauthorized, "invalid token claims")
-			return
-		}
+	tokenString := parts[1]
+	logger.Debug(ctx, "parsing JWT token")
 
-		sub, ok := claims["sub"].(string)
-		if !ok || sub == "" {
-			logger.Warn(ctx, "authentication failed: missing user ID in token")
-			response.Error(w, http.StatusUnauthorized, "missing user ID in token")
-			return
+	// Only asymmetric methods are accepted; the key type returned for the
+	// kid must also match the method, which jwt checks when verifying.
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		switch token.Method.(type) {
+		case *jwt.SigningMethodECDSA, *jwt.SigningMethodRSA:
+		default:
+			return nil, jwt.ErrSignatureInvalid
 		}
+		kid, _ := token.Header["kid"].(string)
+		return m.keys.Key(ctx, kid)
+	})
+	if err != nil || !token.Valid {
+		return "", nil, "invalid token", err
+	}
 
-		logger.Debug(ctx, "authentication successful", "userID", sub)
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return "", nil, "invalid token claims", nil
+	}
 
-		// Add userID to both the standard context key and the logger context
-		ctx = context.WithValue(ctx, UserIDKey, sub)
-		ctx = logger.ContextWithUserID(ctx, sub)
-		next.ServeHTTP(w, r.WithContext(ctx))
-	})
+	sub, ok := claims["sub"].(string)
+	if !ok || sub == "" {
+		return "", nil, "missing user ID in token", nil
+	}
+	return sub, claims, "", nil
+}
+
+// verifyRequest is verify, reusing the result Verify cached in r's context
+// for the same header.
+func (m *AuthMiddleware) verifyRequest(r *http.Request, authHeader string) (string, jwt.MapClaims, string, error) {
+	if v, ok := r.Context().Value(verificationKey).(*verification); ok && v.authHeader == authHeader {
+		return v.sub, v.claims, v.reason, v.err
+	}
+	return m.verify(r.Context(), authHeader)
+}
+
+// Verify reports whether r carries credentials Authenticate would accept,
+// without serving it, and returns r with the outcome cached so Authenticate
+// and RequireRole do not verify the token again. Every request is verified
+// in demo mode, and none without keys.
+func (m *AuthMiddleware) Verify(r *http.Request) (*http.Request, bool) {
+	if m.demoUserID != "" {
+		return r, true
+	}
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" || m.keys == nil {
+		return r, false
+	}
+	v := &verification{authHeader: authHeader}
+	v.sub, v.claims, v.reason, v.err = m.verifyRequest(r, authHeader)
+	return r.WithContext(context.WithValue(r.Context(), verificationKey, v)), v.reason == ""
+}
+
+// identify verifies r's bearer token as a user's, returning the user and the
+// role its app_metadata.role claim grants. ok is false unless the token is
+// one Authenticate would accept. In demo mode every request is the demo
+// user, who has no role.
+func (m *AuthMiddleware) identify(r *http.Request) (userID string, role Role, ok bool) {
+	if m.demoUserID != "" {
+		return m.demoUserID, RoleNone, true
+	}
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" || m.keys == nil {
+		return "", RoleNone, false
+	}
+	sub, claims, reason, _ := m.verifyRequest(r, authHeader)
+	if reason != "" {
+		return "", RoleNone, false
+	}
+	return sub, tokenRole(claims), true
+}
+
+// serve calls next, first marking the request as traced when userID is. A
+// traced request also logs its start and completion, which LoggingMiddleware
+// logs before the user is known and so without the trace attribute.
+func (m *AuthMiddleware) serve(next http.Handler, w http.ResponseWriter, r *http.Request, userID string) {
+	ctx := r.Context()
+	if m.tracer == nil || !m.tracer.IsTraced(ctx, userID) {
+		next.ServeHTTP(w, r)
+		return
+	}
+
+	ctx = logger.ContextWithTrace(ctx)
+	start := time.Now()
+	ww, ok := w.(chimiddleware.WrapResponseWriter)
+	if !ok {
+		ww = chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+	}
+	logger.Debug(ctx, "trace: request started", "method", r.Method, "path", logPath(r), "query", r.URL.RawQuery)
+
+	next.ServeHTTP(ww, r.WithContext(ctx))
+
+	logger.Debug(ctx, "trace: request completed",
+		"method", r.Method,
+		"path", logPath(r),
+		"status", ww.Status(),
+		"bytes", ww.BytesWritten(),
+		"durationMs", time.Since(start).Milliseconds(),
+	)
+}
+
+// tokenRole returns the staff role named by the token's app_metadata.role
+// claim. Supabase only lets the service role write app_metadata; a role in
+// user_metadata, which users can edit themselves, is never trusted.
+func tokenRole(claims jwt.MapClaims) Role {
+	metadata, _ := claims["app_metadata"].(map[string]interface{})
+	name, _ := metadata["role"].(string)
+	return ParseRole(name)
+}
+
+// webSocketToken returns the token offered as a WebSocketTokenPrefix
+// subprotocol of a WebSocket handshake, or "".
+func webSocketToken(r *http.Request) string {
+	if !realtime.IsUpgrade(r) {
+		return ""
+	}
+	for _, protocol := range realtime.Protocols(r) {
+		if token, ok := strings.CutPrefix(protocol, WebSocketTokenPrefix); ok {
+			return token
+		}
+	}
+	return ""
 }
 
 func GetUserID(ctx context.Context) string {
 	userID, _ := ctx.Value(UserIDKey).(string)
 	return userID
 }
+
+// GetRole returns the staff role of the authenticated user, RoleNone for
+// regular users and in demo mode.
+func GetRole(ctx context.Context) Role {
+	role, _ := ctx.Value(RoleKey).(Role)
+	return role
+}
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"context"
+	"crypto"
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
@@ -11,8 +12,22 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/graytonio/warframe-wishlist/internal/audit"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
 )
 
+// staticKeySet is a KeySet with fixed keys. Test tokens carry no kid, so
+// most tests store their key under "".
+type staticKeySet map[string]crypto.PublicKey
+
+func (s staticKeySet) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	key, ok := s[kid]
+	if !ok {
+		return nil, ErrUnknownSigningKey
+	}
+	return key, nil
+}
+
 // generateTestKeyPair creates an ECDSA key pair for testing
 func generateTestKeyPair(t *testing.T) (*ecdsa.PrivateKey, *ecdsa.PublicKey) {
 	t.Helper()
@@ -39,7 +54,7 @@ func TestAuthMiddleware_Authenticate_ValidToken(t *testing.T) {
 	}
 	token := createTestToken(privateKey, claims)
 
-	middleware := NewAuthMiddleware(publicKey)
+	middleware := NewAuthMiddleware(staticKeySet{"": publicKey})
 
 	var capturedUserID string
 	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -62,9 +77,46 @@ func TestAuthMiddleware_Authenticate_ValidToken(t *testing.T) {
 	}
 }
 
+func TestAuthMiddleware_Authenticate_RoleClaim(t *testing.T) {
+	privateKey, publicKey := generateTestKeyPair(t)
+	middleware := NewAuthMiddleware(staticKeySet{"": publicKey})
+
+	tests := []struct {
+		name     string
+		claims   jwt.MapClaims
+		expected Role
+	}{
+		{name: "admin", claims: jwt.MapClaims{"app_metadata": map[string]interface{}{"role": "admin"}}, expected: RoleAdmin},
+		{name: "moderator", claims: jwt.MapClaims{"app_metadata": map[string]interface{}{"role": "moderator"}}, expected: RoleModerator},
+		{name: "unknown role", claims: jwt.MapClaims{"app_metadata": map[string]interface{}{"role": "owner"}}, expected: RoleNone},
+		{name: "no claim", claims: jwt.MapClaims{}, expected: RoleNone},
+		// Users can write their own user_metadata.
+		{name: "user metadata", claims: jwt.MapClaims{"user_metadata": map[string]interface{}{"role": "admin"}}, expected: RoleNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.claims["sub"] = "user-123"
+			tt.claims["exp"] = time.Now().Add(time.Hour).Unix()
+
+			captured := Role(-1)
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				captured = GetRole(r.Context())
+			})
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			req.Header.Set("Authorization", "Bearer "+createTestToken(privateKey, tt.claims))
+			middleware.Authenticate(next).ServeHTTP(httptest.NewRecorder(), req)
+
+			if captured != tt.expected {
+				t.Errorf("expected role %s, got %s", tt.expected, captured)
+			}
+		})
+	}
+}
+
 func TestAuthMiddleware_Authenticate_MissingHeader(t *testing.T) {
 	_, publicKey := generateTestKeyPair(t)
-	middleware := NewAuthMiddleware(publicKey)
+	middleware := NewAuthMiddleware(staticKeySet{"": publicKey})
 
 	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		t.Error("next handler should not be called")
@@ -92,7 +144,7 @@ func TestAuthMiddleware_Authenticate_InvalidHeaderFormat(t *testing.T) {
 	}
 
 	_, publicKey := generateTestKeyPair(t)
-	middleware := NewAuthMiddleware(publicKey)
+	middleware := NewAuthMiddleware(staticKeySet{"": publicKey})
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -115,7 +167,7 @@ func TestAuthMiddleware_Authenticate_InvalidHeaderFormat(t *testing.T) {
 
 func TestAuthMiddleware_Authenticate_InvalidToken(t *testing.T) {
 	_, publicKey := generateTestKeyPair(t)
-	middleware := NewAuthMiddleware(publicKey)
+	middleware := NewAuthMiddleware(staticKeySet{"": publicKey})
 
 	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		t.Error("next handler should not be called")
@@ -143,7 +195,7 @@ func TestAuthMiddleware_Authenticate_WrongKey(t *testing.T) {
 
 	// Validate with a different key pair
 	_, validationPublicKey := generateTestKeyPair(t)
-	middleware := NewAuthMiddleware(validationPublicKey)
+	middleware := NewAuthMiddleware(staticKeySet{"": validationPublicKey})
 
 	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		t.Error("next handler should not be called")
@@ -168,7 +220,7 @@ func TestAuthMiddleware_Authenticate_ExpiredToken(t *testing.T) {
 	}
 	token := createTestToken(privateKey, claims)
 
-	middleware := NewAuthMiddleware(publicKey)
+	middleware := NewAuthMiddleware(staticKeySet{"": publicKey})
 
 	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		t.Error("next handler should not be called")
@@ -192,7 +244,7 @@ func TestAuthMiddleware_Authenticate_MissingSubClaim(t *testing.T) {
 	}
 	token := createTestToken(privateKey, claims)
 
-	middleware := NewAuthMiddleware(publicKey)
+	middleware := NewAuthMiddleware(staticKeySet{"": publicKey})
 
 	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		t.Error("next handler should not be called")
@@ -217,7 +269,7 @@ func TestAuthMiddleware_Authenticate_EmptySubClaim(t *testing.T) {
 	}
 	token := createTestToken(privateKey, claims)
 
-	middleware := NewAuthMiddleware(publicKey)
+	middleware := NewAuthMiddleware(staticKeySet{"": publicKey})
 
 	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		t.Error("next handler should not be called")
@@ -242,7 +294,7 @@ func TestAuthMiddleware_Authenticate_CaseInsensitiveBearer(t *testing.T) {
 	}
 	token := createTestToken(privateKey, claims)
 
-	middleware := NewAuthMiddleware(publicKey)
+	middleware := NewAuthMiddleware(staticKeySet{"": publicKey})
 
 	bearerVariants := []string{"bearer", "Bearer", "BEARER", "BeArEr"}
 
@@ -265,6 +317,115 @@ func TestAuthMiddleware_Authenticate_CaseInsensitiveBearer(t *testing.T) {
 	}
 }
 
+func TestAuthMiddleware_Authenticate_DemoUser(t *testing.T) {
+	middleware := NewDemoAuthMiddleware("demo-user")
+
+	var capturedUserID string
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		capturedUserID = GetUserID(r.Context())
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	rec := httptest.NewRecorder()
+
+	middleware.Authenticate(nextHandler).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	if capturedUserID != "demo-user" {
+		t.Errorf("expected userID 'demo-user', got '%s'", capturedUserID)
+	}
+}
+
+func TestAuthMiddleware_Verify(t *testing.T) {
+	privateKey, publicKey := generateTestKeyPair(t)
+	otherKey, _ := generateTestKeyPair(t)
+	valid := createTestToken(privateKey, jwt.MapClaims{"sub": "user-123", "exp": time.Now().Add(time.Hour).Unix()})
+	forged := createTestToken(otherKey, jwt.MapClaims{"sub": "user-123", "exp": time.Now().Add(time.Hour).Unix()})
+	middleware := NewAuthMiddleware(staticKeySet{"": publicKey})
+
+	tests := []struct {
+		name     string
+		auth     string
+		expected bool
+	}{
+		{name: "valid token", auth: "Bearer " + valid, expected: true},
+		{name: "wrong key", auth: "Bearer " + forged},
+		{name: "not a token", auth: "x"},
+		{name: "missing header"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			if _, got := middleware.Verify(req); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Authorization", "Bearer "+valid)
+	if _, ok := NewAuthMiddleware(nil).Verify(req); ok {
+		t.Error("expected nothing verified without keys")
+	}
+	if _, ok := NewDemoAuthMiddleware("demo-user").Verify(httptest.NewRequest(http.MethodGet, "/test", nil)); !ok {
+		t.Error("expected every request verified in demo mode")
+	}
+}
+
+// countingKeySet counts the key lookups of token verifications.
+type countingKeySet struct {
+	staticKeySet
+	lookups int
+}
+
+func (s *countingKeySet) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	s.lookups++
+	return s.staticKeySet.Key(ctx, kid)
+}
+
+func TestAuthMiddleware_AuthenticateReusesVerify(t *testing.T) {
+	privateKey, publicKey := generateTestKeyPair(t)
+	valid := createTestToken(privateKey, jwt.MapClaims{"sub": "user-123", "exp": time.Now().Add(time.Hour).Unix()})
+	keys := &countingKeySet{staticKeySet: staticKeySet{"": publicKey}}
+	middleware := NewAuthMiddleware(keys)
+
+	var capturedUserID string
+	handler := middleware.Authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		capturedUserID = GetUserID(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/test", nil)
+	req.Header.Set("Authorization", "Bearer "+valid)
+	req, ok := middleware.Verify(req)
+	if !ok {
+		t.Fatal("expected the token verified")
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || capturedUserID != "user-123" {
+		t.Errorf("expected user-123 authenticated, got %d %q", rec.Code, capturedUserID)
+	}
+	if keys.lookups != 1 {
+		t.Errorf("expected the token verified once, got %d lookups", keys.lookups)
+	}
+
+	// A header changed after Verify is verified afresh.
+	req.Header.Set("Authorization", "Bearer not.a.token")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected the changed header refused, got %d", rec.Code)
+	}
+}
+
 func TestGetUserID_WithValue(t *testing.T) {
 	ctx := context.WithValue(context.Background(), UserIDKey, "user-123")
 	userID := GetUserID(ctx)
@@ -291,3 +452,131 @@ func TestGetUserID_WrongType(t *testing.T) {
 		t.Errorf("expected empty userID for wrong type, got '%s'", userID)
 	}
 }
+
+type auditCapture struct {
+	events []audit.Event
+}
+
+func (c *auditCapture) Record(event audit.Event) {
+	c.events = append(c.events, event)
+}
+
+func TestAuthMiddleware_Authenticate_AuditsFailures(t *testing.T) {
+	capture := &auditCapture{}
+	audit.SetDefault(capture)
+	defer audit.SetDefault(nil)
+
+	privateKey, publicKey := generateTestKeyPair(t)
+	middleware := NewAuthMiddleware(staticKeySet{"": publicKey})
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	validToken := createTestToken(privateKey, jwt.MapClaims{"sub": "user-123", "exp": time.Now().Add(time.Hour).Unix()})
+	for _, header := range []string{"", "Token abc", "Bearer invalid.token.here", "Bearer " + validToken} {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/wishlist", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		middleware.Authenticate(next).ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	expectedReasons := []string{"missing authorization header", "invalid authorization header format", "invalid token"}
+	if len(capture.events) != len(expectedReasons) {
+		t.Fatalf("expected %d audit events, got %+v", len(expectedReasons), capture.events)
+	}
+	for i, event := range capture.events {
+		if event.Type != audit.TypeAuthFailure || event.Outcome != audit.OutcomeFailure || event.Reason != expectedReasons[i] {
+			t.Errorf("event %d: unexpected %+v", i, event)
+		}
+		if event.Path != "/api/v1/wishlist" {
+			t.Errorf("event %d: expected request path, got %q", i, event.Path)
+		}
+	}
+}
+
+// tracedUsers is a UserTracer tracing the users it holds.
+type tracedUsers map[string]bool
+
+func (u tracedUsers) IsTraced(ctx context.Context, userID string) bool {
+	return u[userID]
+}
+
+func TestAuthMiddleware_Authenticate_TracesUser(t *testing.T) {
+	privateKey, publicKey := generateTestKeyPair(t)
+	middleware := NewAuthMiddleware(staticKeySet{"": publicKey})
+	middleware.SetTracer(tracedUsers{"traced-user": true})
+
+	for userID, expected := range map[string]bool{"traced-user": true, "other-user": false} {
+		token := createTestToken(privateKey, jwt.MapClaims{"sub": userID, "exp": time.Now().Add(time.Hour).Unix()})
+
+		var traced bool
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		middleware.Authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			traced = logger.IsTraced(r.Context())
+			w.WriteHeader(http.StatusTeapot)
+		})).ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusTeapot {
+			t.Errorf("%s: expected the handler's status, got %d", userID, rec.Code)
+		}
+		if traced != expected {
+			t.Errorf("%s: expected traced=%v, got %v", userID, expected, traced)
+		}
+	}
+}
+
+func TestAuthMiddleware_Authenticate_TracesDemoUser(t *testing.T) {
+	middleware := NewDemoAuthMiddleware("demo-user")
+	middleware.SetTracer(tracedUsers{"demo-user": true})
+
+	var traced bool
+	rec := httptest.NewRecorder()
+	middleware.Authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traced = logger.IsTraced(r.Context())
+	})).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/test", nil))
+
+	if !traced {
+		t.Error("expected the demo user's request to be traced")
+	}
+}
+
+func TestAuthMiddleware_Authenticate_WebSocketProtocolToken(t *testing.T) {
+	privateKey, publicKey := generateTestKeyPair(t)
+	token := createTestToken(privateKey, jwt.MapClaims{"sub": "user-123", "exp": time.Now().Add(time.Hour).Unix()})
+	middleware := NewAuthMiddleware(staticKeySet{"": publicKey})
+
+	var capturedUserID string
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		capturedUserID = GetUserID(r.Context())
+	})
+
+	tests := []struct {
+		name     string
+		upgrade  bool
+		expected int
+	}{
+		{name: "websocket handshake", upgrade: true, expected: http.StatusOK},
+		{name: "plain request", upgrade: false, expected: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			capturedUserID = ""
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			req.Header.Set("Sec-WebSocket-Protocol", "wishlist.v1, "+WebSocketTokenPrefix+token)
+			if tt.upgrade {
+				req.Header.Set("Connection", "Upgrade")
+				req.Header.Set("Upgrade", "websocket")
+			}
+			rec := httptest.NewRecorder()
+			middleware.Authenticate(nextHandler).ServeHTTP(rec, req)
+
+			if rec.Code != tt.expected {
+				t.Fatalf("expected status %d, got %d", tt.expected, rec.Code)
+			}
+			if tt.upgrade && capturedUserID != "user-123" {
+				t.Errorf("expected the token's user, got %q", capturedUserID)
+			}
+		})
+	}
+}
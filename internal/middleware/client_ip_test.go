@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseTrustedProxies(t *testing.T) {
+	prefixes, err := ParseTrustedProxies(" 10.0.0.0/8, 192.168.1.5 ,,2001:db8::/32,10.1.2.3/16")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"10.0.0.0/8", "192.168.1.5/32", "2001:db8::/32", "10.1.0.0/16"}
+	if len(prefixes) != len(want) {
+		t.Fatalf("expected %v, got %v", want, prefixes)
+	}
+	for i, prefix := range prefixes {
+		if prefix.String() != want[i] {
+			t.Errorf("expected %s, got %s", want[i], prefix)
+		}
+	}
+
+	if prefixes, err := ParseTrustedProxies(""); err != nil || len(prefixes) != 0 {
+		t.Errorf("expected no proxies, got %v (err %v)", prefixes, err)
+	}
+	for _, spec := range []string{"10.0.0.0/33", "loadbalancer", "10.0.0.0/8, 300.1.1.1"} {
+		if _, err := ParseTrustedProxies(spec); err == nil {
+			t.Errorf("expected an error for %q", spec)
+		}
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	trusted, err := ParseTrustedProxies("10.0.0.0/8, 2001:db8::1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor []string
+		realIP       string
+		expectedAddr string
+	}{
+		{name: "direct client keeps its address", remoteAddr: "203.0.113.7:5000", forwardedFor: []string{"198.51.100.1"}, expectedAddr: "203.0.113.7:5000"},
+		{name: "trusted proxy", remoteAddr: "10.0.0.5:41000", forwardedFor: []string{"203.0.113.7"}, expectedAddr: "203.0.113.7"},
+		{name: "spoofed entries left of the client are ignored", remoteAddr: "10.0.0.5:41000", forwardedFor: []string{"198.51.100.1, 203.0.113.7"}, expectedAddr: "203.0.113.7"},
+		{name: "chain of trusted proxies", remoteAddr: "10.0.0.5:41000", forwardedFor: []string{"203.0.113.7, 10.2.0.9", "10.3.0.1"}, expectedAddr: "203.0.113.7"},
+		{name: "every hop trusted", remoteAddr: "10.0.0.5:41000", forwardedFor: []string{"10.9.9.9, 10.2.0.9"}, expectedAddr: "10.9.9.9"},
+		{name: "invalid hop stops the walk", remoteAddr: "10.0.0.5:41000", forwardedFor: []string{"203.0.113.7, unknown"}, expectedAddr: "10.0.0.5:41000"},
+		{name: "hop with a port", remoteAddr: "10.0.0.5:41000", forwardedFor: []string{"203.0.113.7:6000"}, expectedAddr: "203.0.113.7"},
+		{name: "ipv6 proxy and client", remoteAddr: "[2001:db8::1]:41000", forwardedFor: []string{"2001:db8:ffff::7"}, expectedAddr: "2001:db8:ffff::7"},
+		{name: "x-real-ip without x-forwarded-for", remoteAddr: "10.0.0.5:41000", realIP: "203.0.113.7", expectedAddr: "203.0.113.7"},
+		{name: "x-forwarded-for wins over x-real-ip", remoteAddr: "10.0.0.5:41000", forwardedFor: []string{"203.0.113.7"}, realIP: "198.51.100.1", expectedAddr: "203.0.113.7"},
+		{name: "x-real-ip from an untrusted peer", remoteAddr: "203.0.113.7:5000", realIP: "198.51.100.1", expectedAddr: "203.0.113.7:5000"},
+		{name: "trusted proxy without headers", remoteAddr: "10.0.0.5:41000", expectedAddr: "10.0.0.5:41000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			handler := ClientIP(trusted)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.RemoteAddr
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/items", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for _, value := range tt.forwardedFor {
+				req.Header.Add("X-Forwarded-For", value)
+			}
+			if tt.realIP != "" {
+				req.Header.Set("X-Real-IP", tt.realIP)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			if got != tt.expectedAddr {
+				t.Errorf("expected remote address %q, got %q", tt.expectedAddr, got)
+			}
+		})
+	}
+}
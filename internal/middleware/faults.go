@@ -0,0 +1,215 @@
+package middleware
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"github.com/graytonio/warframe-wishlist/pkg/response"
+)
+
+// FaultHeader lists the faults injected into a response (for example
+// "latency=850ms, error=503"), so clients can tell staged misbehaviour from
+// real failures.
+const FaultHeader = "X-Fault-Injected"
+
+const (
+	defaultFaultMinDelay = 200 * time.Millisecond
+	defaultFaultMaxDelay = 2 * time.Second
+)
+
+// FaultRule injects faults into a share of the requests matching Method and
+// Pattern: LatencyPercent of them are delayed by a random duration between
+// MinDelay and MaxDelay, and ErrorPercent of them fail with Status instead of
+// reaching the handler.
+type FaultRule struct {
+	// Method is the HTTP method matched; empty matches any.
+	Method string
+	// Pattern is an exact path, or a path prefix when it ends in "*".
+	Pattern string
+
+	LatencyPercent float64
+	MinDelay       time.Duration
+	MaxDelay       time.Duration
+
+	ErrorPercent float64
+	Status       int
+}
+
+func (f FaultRule) matches(r *http.Request) bool {
+	if f.Method != "" && f.Method != r.Method {
+		return false
+	}
+	if prefix, ok := strings.CutSuffix(f.Pattern, "*"); ok {
+		return strings.HasPrefix(r.URL.Path, prefix)
+	}
+	return r.URL.Path == f.Pattern
+}
+
+// ParseFaultRules reads the FAULT_INJECTION_RULES format: rules separated by
+// ";", each an optional method, a path pattern and space-separated options:
+//
+//	GET /api/v1/wishlist/materials latency=50 delay=500ms-3s; /api/v1/* error=10 status=502
+//
+// latency and error are percentages of matching requests. delay is a fixed
+// duration or a min-max range (default 200ms-2s); status defaults to 503.
+// The first rule matching a request applies.
+func ParseFaultRules(spec string) ([]FaultRule, error) {
+	var rules []FaultRule
+	for _, text := range strings.Split(spec, ";") {
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+		rule, err := parseFaultRule(fields)
+		if err != nil {
+			return nil, fmt.Errorf("fault rule %q: %w", strings.TrimSpace(text), err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func parseFaultRule(fields []string) (FaultRule, error) {
+	rule := FaultRule{
+		MinDelay: defaultFaultMinDelay,
+		MaxDelay: defaultFaultMaxDelay,
+		Status:   http.StatusServiceUnavailable,
+	}
+	if !strings.HasPrefix(fields[0], "/") && fields[0] != "*" {
+		rule.Method = strings.ToUpper(fields[0])
+		fields = fields[1:]
+	}
+	if len(fields) == 0 {
+		return rule, fmt.Errorf("missing path pattern")
+	}
+	rule.Pattern, fields = fields[0], fields[1:]
+	if !strings.HasPrefix(rule.Pattern, "/") && rule.Pattern != "*" {
+		return rule, fmt.Errorf("path pattern must start with /")
+	}
+
+	for _, field := range fields {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return rule, fmt.Errorf("option %q is not key=value", field)
+		}
+		var err error
+		switch key {
+		case "latency":
+			rule.LatencyPercent, err = parsePercent(value)
+		case "error":
+			rule.ErrorPercent, err = parsePercent(value)
+		case "delay":
+			rule.MinDelay, rule.MaxDelay, err = parseDelayRange(value)
+		case "status":
+			rule.Status, err = strconv.Atoi(value)
+			if err == nil && (rule.Status < 400 || rule.Status > 599) {
+				err = fmt.Errorf("status must be a 4xx or 5xx code")
+			}
+		default:
+			err = fmt.Errorf("unknown option %q", key)
+		}
+		if err != nil {
+			return rule, err
+		}
+	}
+	if rule.LatencyPercent == 0 && rule.ErrorPercent == 0 {
+		return rule, fmt.Errorf("needs a latency or error percentage")
+	}
+	return rule, nil
+}
+
+func parsePercent(value string) (float64, error) {
+	percent, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+	if err != nil || percent < 0 || percent > 100 {
+		return 0, fmt.Errorf("percentage %q must be between 0 and 100", value)
+	}
+	return percent, nil
+}
+
+func parseDelayRange(value string) (time.Duration, time.Duration, error) {
+	minText, maxText, isRange := strings.Cut(value, "-")
+	if !isRange {
+		maxText = minText
+	}
+	minDelay, err := time.ParseDuration(minText)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid delay %q", value)
+	}
+	maxDelay, err := time.ParseDuration(maxText)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid delay %q", value)
+	}
+	if minDelay < 0 || maxDelay < minDelay {
+		return 0, 0, fmt.Errorf("invalid delay range %q", value)
+	}
+	return minDelay, maxDelay, nil
+}
+
+// FaultInjector delays and fails requests according to its rules, so
+// frontend retry and loading states can be exercised against a misbehaving
+// backend. It must never run in production; main refuses to mount it there.
+type FaultInjector struct {
+	rules []FaultRule
+	// roll returns a uniformly random number in [0, 1).
+	roll func() float64
+}
+
+func NewFaultInjector(rules []FaultRule) *FaultInjector {
+	return &FaultInjector{rules: rules, roll: rand.Float64}
+}
+
+func (f *FaultInjector) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rule, ok := f.match(r)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := r.Context()
+		var injected []string
+		if f.hit(rule.LatencyPercent) {
+			delay := rule.MinDelay + time.Duration(f.roll()*float64(rule.MaxDelay-rule.MinDelay))
+			injected = append(injected, "latency="+delay.Round(time.Millisecond).String())
+			timer := time.NewTimer(delay)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			}
+		}
+
+		if f.hit(rule.ErrorPercent) {
+			injected = append(injected, "error="+strconv.Itoa(rule.Status))
+			logger.Debug(ctx, "fault injection: failing request", "method", r.Method, "path", r.URL.Path, "faults", injected)
+			w.Header().Set(FaultHeader, strings.Join(injected, ", "))
+			response.Error(w, rule.Status, "injected fault")
+			return
+		}
+
+		if len(injected) > 0 {
+			logger.Debug(ctx, "fault injection: delaying request", "method", r.Method, "path", r.URL.Path, "faults", injected)
+			w.Header().Set(FaultHeader, strings.Join(injected, ", "))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (f *FaultInjector) match(r *http.Request) (FaultRule, bool) {
+	for _, rule := range f.rules {
+		if rule.matches(r) {
+			return rule, true
+		}
+	}
+	return FaultRule{}, false
+}
+
+func (f *FaultInjector) hit(percent float64) bool {
+	return percent > 0 && f.roll()*100 < percent
+}
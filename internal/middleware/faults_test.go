@@ -0,0 +1,202 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseFaultRules(t *testing.T) {
+	rules, err := ParseFaultRules("GET /api/v1/wishlist/materials latency=50 delay=500ms-3s; /api/v1/* error=10% status=502;")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rules) != 2 {
+		t.Fatalf("expected 2 rules, got %d", len(rules))
+	}
+
+	expected := []FaultRule{
+		{Method: http.MethodGet, Pattern: "/api/v1/wishlist/materials", LatencyPercent: 50, MinDelay: 500 * time.Millisecond, MaxDelay: 3 * time.Second, Status: http.StatusServiceUnavailable},
+		{Pattern: "/api/v1/*", ErrorPercent: 10, MinDelay: defaultFaultMinDelay, MaxDelay: defaultFaultMaxDelay, Status: http.StatusBadGateway},
+	}
+	for i, want := range expected {
+		if rules[i] != want {
+			t.Errorf("rule %d: expected %+v, got %+v", i, want, rules[i])
+		}
+	}
+
+	if rules, err := ParseFaultRules(""); err != nil || len(rules) != 0 {
+		t.Errorf("expected no rules for an empty spec, got %v, %v", rules, err)
+	}
+}
+
+func TestParseFaultRules_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+	}{
+		{name: "missing pattern", spec: "GET"},
+		{name: "relative pattern", spec: "GET api/v1 error=10"},
+		{name: "no faults", spec: "/api/v1/*"},
+		{name: "percentage too high", spec: "/api/v1/* error=150"},
+		{name: "bad delay", spec: "/api/v1/* latency=10 delay=soon"},
+		{name: "inverted delay range", spec: "/api/v1/* latency=10 delay=2s-1s"},
+		{name: "non-error status", spec: "/api/v1/* error=10 status=200"},
+		{name: "unknown option", spec: "/api/v1/* drop=10"},
+		{name: "bare option", spec: "/api/v1/* error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseFaultRules(tt.spec); err == nil {
+				t.Errorf("expected an error for %q", tt.spec)
+			}
+		})
+	}
+}
+
+func newTestFaultInjector(t *testing.T, spec string, rolls ...float64) *FaultInjector {
+	t.Helper()
+	rules, err := ParseFaultRules(spec)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	injector := NewFaultInjector(rules)
+	injector.roll = func() float64 {
+		if len(rolls) == 0 {
+			t.Fatal("unexpected roll")
+		}
+		roll := rolls[0]
+		rolls = rolls[1:]
+		return roll
+	}
+	return injector
+}
+
+func TestFaultInjector_Middleware(t *testing.T) {
+	tests := []struct {
+		name           string
+		spec           string
+		method         string
+		path           string
+		rolls          []float64
+		expectedStatus int
+		expectedHeader string
+		expectReached  bool
+	}{
+		{
+			name:           "error injected",
+			spec:           "/api/v1/* error=20 status=502",
+			method:         http.MethodGet,
+			path:           "/api/v1/wishlist",
+			rolls:          []float64{0.1},
+			expectedStatus: http.StatusBadGateway,
+			expectedHeader: "error=502",
+		},
+		{
+			name:           "error not rolled",
+			spec:           "/api/v1/* error=20",
+			method:         http.MethodGet,
+			path:           "/api/v1/wishlist",
+			rolls:          []float64{0.5},
+			expectedStatus: http.StatusOK,
+			expectReached:  true,
+		},
+		{
+			name:           "latency injected",
+			spec:           "/api/v1/wishlist latency=100 delay=10ms-20ms",
+			method:         http.MethodGet,
+			path:           "/api/v1/wishlist",
+			rolls:          []float64{0, 0.5},
+			expectedStatus: http.StatusOK,
+			expectedHeader: "latency=15ms",
+			expectReached:  true,
+		},
+		{
+			name:           "latency then error",
+			spec:           "/api/v1/* latency=100 delay=1ms error=100",
+			method:         http.MethodPost,
+			path:           "/api/v1/wishlist",
+			rolls:          []float64{0, 0, 0.99},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedHeader: "latency=1ms, error=503",
+		},
+		{
+			name:           "method not matched",
+			spec:           "POST /api/v1/* error=100",
+			method:         http.MethodGet,
+			path:           "/api/v1/wishlist",
+			expectedStatus: http.StatusOK,
+			expectReached:  true,
+		},
+		{
+			name:           "exact path not matched",
+			spec:           "/api/v1/wishlist error=100",
+			method:         http.MethodGet,
+			path:           "/api/v1/wishlist/materials",
+			expectedStatus: http.StatusOK,
+			expectReached:  true,
+		},
+		{
+			name:           "first matching rule applies",
+			spec:           "/api/v1/items/* error=0.5; /api/v1/* error=100",
+			method:         http.MethodGet,
+			path:           "/api/v1/items/search",
+			rolls:          []float64{0.5},
+			expectedStatus: http.StatusOK,
+			expectReached:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reached := false
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				reached = true
+				w.WriteHeader(http.StatusOK)
+			})
+			handler := newTestFaultInjector(t, tt.spec, tt.rolls...).Middleware(next)
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if got := rec.Header().Get(FaultHeader); got != tt.expectedHeader {
+				t.Errorf("expected %s %q, got %q", FaultHeader, tt.expectedHeader, got)
+			}
+			if reached != tt.expectReached {
+				t.Errorf("expected handler reached = %v, got %v", tt.expectReached, reached)
+			}
+			if !tt.expectReached && !strings.Contains(rec.Body.String(), "injected fault") {
+				t.Errorf("expected an injected fault error body, got %s", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestFaultInjector_DelayStopsWhenClientGone(t *testing.T) {
+	injector := newTestFaultInjector(t, "/api/v1/* latency=100 delay=1h", 0, 0)
+	handler := injector.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("handler should not be reached")
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/wishlist", nil).WithContext(ctx)
+
+	done := make(chan struct{})
+	go func() {
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("delay did not stop when the request was canceled")
+	}
+}
@@ -0,0 +1,211 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"github.com/graytonio/warframe-wishlist/pkg/response"
+)
+
+const (
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader marks a response replayed from an earlier
+	// request with the same key.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+	// maxIdempotentBodyBytes bounds the request body read to fingerprint a
+	// request, and maxIdempotentResponseBytes the response kept to replay.
+	// A larger response is not kept, so a retry is served again.
+	maxIdempotentBodyBytes     = 8 << 20
+	maxIdempotentResponseBytes = 1 << 20
+)
+
+// IdempotencyStore keeps the outcomes of requests sent with an
+// Idempotency-Key, as repository.IdempotencyRepositoryInterface does.
+type IdempotencyStore interface {
+	Reserve(ctx context.Context, record *models.IdempotencyRecord) (*models.IdempotencyRecord, error)
+	Complete(ctx context.Context, record *models.IdempotencyRecord) error
+	Release(ctx context.Context, userID, key string) error
+}
+
+// Idempotency makes POST, PUT, PATCH and DELETE requests sent with an
+// Idempotency-Key safe to retry: the first request with a key is served and
+// its response kept for ttl, and a retry with the same key gets that
+// response again, marked Idempotent-Replayed, instead of repeating the
+// change. So a client retrying an add over a flaky network neither adds the
+// item twice nor sees a 409 for its own first attempt.
+//
+// Keys are per user, so it must run after authentication. A key reused for a
+// different request (method, path, query or body) is a 422, and a retry
+// while the first request is still being served a 409. Responses of 5xx are
+// not kept, so the request can be retried with the same key; neither is a
+// handler panic or a response the store failed to keep. When the store
+// fails the request is served without the guarantee.
+type Idempotency struct {
+	store IdempotencyStore
+	ttl   time.Duration
+	now   func() time.Time
+}
+
+func NewIdempotency(store IdempotencyStore, ttl time.Duration) *Idempotency {
+	return &Idempotency{store: store, ttl: ttl, now: time.Now}
+}
+
+func (m *Idempotency) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		key := r.Header.Get(IdempotencyKeyHeader)
+		userID := GetUserID(ctx)
+		if key == "" || userID == "" || !isMutation(r.Method) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			logger.Warn(ctx, "idempotency: key too long", "length", len(key))
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidIdempotencyKey, "Idempotency-Key must be at most 255 characters")
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxIdempotentBodyBytes+1))
+		if err != nil {
+			logger.Warn(ctx, "idempotency: error reading request body", "error", err)
+			response.Error(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+		if len(body) > maxIdempotentBodyBytes {
+			logger.Warn(ctx, "idempotency: request body too large")
+			response.ErrorCode(w, http.StatusRequestEntityTooLarge, response.CodeTooLarge, "request body too large for an Idempotency-Key")
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		now := m.now()
+		record := &models.IdempotencyRecord{
+			UserID:      userID,
+			Key:         key,
+			Fingerprint: fingerprint(r, body),
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(m.ttl),
+		}
+		existing, err := m.store.Reserve(ctx, record)
+		if err != nil {
+			logger.Error(ctx, "idempotency: error reserving key; serving without it", "error", err)
+			next.ServeHTTP(w, r)
+			return
+		}
+		if existing != nil {
+			m.replay(w, r, existing, record.Fingerprint)
+			return
+		}
+
+		// The key must not stay reserved when the request fails, or every
+		// retry would get a 409 until it expires. The store is reached
+		// without ctx's cancellation, as the client may have gone already.
+		storeCtx := context.WithoutCancel(ctx)
+		completed := false
+		defer func() {
+			if completed {
+				return
+			}
+			p := recover()
+			if p != nil {
+				logger.Error(ctx, "idempotency: handler panicked; releasing key")
+			}
+			if err := m.store.Release(storeCtx, userID, key); err != nil {
+				logger.Error(ctx, "idempotency: error releasing key", "error", err)
+			}
+			if p != nil {
+				panic(p)
+			}
+		}()
+
+		// Only headers the handler sets are kept, not those set around it
+		// such as the request ID.
+		before := w.Header().Clone()
+		ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		var buf bytes.Buffer
+		ww.Tee(&buf)
+		next.ServeHTTP(ww, r)
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		if status >= http.StatusInternalServerError || buf.Len() > maxIdempotentResponseBytes {
+			logger.Debug(ctx, "idempotency: not keeping response", "status", status, "bytes", buf.Len())
+			return
+		}
+		record.Status = status
+		record.Header = addedHeaders(before, w.Header())
+		record.Body = buf.Bytes()
+		if err := m.store.Complete(storeCtx, record); err != nil {
+			logger.Error(ctx, "idempotency: error storing response; releasing key", "error", err)
+			return
+		}
+		completed = true
+	})
+}
+
+// replay answers a request whose key was already used: with the kept
+// response when it was for the same request, or with an error.
+func (m *Idempotency) replay(w http.ResponseWriter, r *http.Request, existing *models.IdempotencyRecord, fingerprint string) {
+	ctx := r.Context()
+	switch {
+	case existing.Fingerprint != fingerprint:
+		logger.Warn(ctx, "idempotency: key reused for a different request")
+		response.ErrorCode(w, http.StatusUnprocessableEntity, response.CodeIdempotencyKeyReused, "Idempotency-Key was already used for a different request")
+	case !existing.Completed:
+		logger.Info(ctx, "idempotency: request with key still in progress")
+		w.Header().Set("Retry-After", "1")
+		response.ErrorCode(w, http.StatusConflict, response.CodeIdempotencyKeyInProgress, "a request with this Idempotency-Key is still in progress")
+	default:
+		logger.Info(ctx, "idempotency: replaying response", "status", existing.Status)
+		for name, values := range existing.Header {
+			w.Header()[name] = values
+		}
+		w.Header().Set(IdempotentReplayedHeader, "true")
+		w.WriteHeader(existing.Status)
+		w.Write(existing.Body)
+	}
+}
+
+func isMutation(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// fingerprint identifies a request by its method, path, query and body.
+func fingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	for _, part := range []string{r.Method, r.URL.Path, r.URL.RawQuery} {
+		io.WriteString(h, strconv.Itoa(len(part))+":"+part)
+	}
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// addedHeaders returns the headers in after that are not in before with the
+// same values.
+func addedHeaders(before, after http.Header) map[string][]string {
+	added := map[string][]string{}
+	for name, values := range after {
+		if !slices.Equal(before[name], values) {
+			added[name] = slices.Clone(values)
+		}
+	}
+	return added
+}
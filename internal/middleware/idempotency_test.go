@@ -0,0 +1,269 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository/memory"
+)
+
+type failingIdempotencyStore struct{}
+
+func (failingIdempotencyStore) Reserve(ctx context.Context, record *models.IdempotencyRecord) (*models.IdempotencyRecord, error) {
+	return nil, errors.New("database down")
+}
+func (failingIdempotencyStore) Complete(ctx context.Context, record *models.IdempotencyRecord) error {
+	return nil
+}
+func (failingIdempotencyStore) Release(ctx context.Context, userID, key string) error { return nil }
+
+// failingCompleteStore reserves keys but cannot keep responses, and like
+// the Mongo store fails once ctx is cancelled.
+type failingCompleteStore struct {
+	*memory.IdempotencyRepository
+}
+
+func (failingCompleteStore) Complete(ctx context.Context, record *models.IdempotencyRecord) error {
+	return errors.New("database down")
+}
+
+func (s failingCompleteStore) Release(ctx context.Context, userID, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return s.IdempotencyRepository.Release(ctx, userID, key)
+}
+
+// newIdempotentHandler counts calls to a handler that answers with status,
+// wrapped as main does after authentication.
+func newIdempotentHandler(store IdempotencyStore, status int, calls *atomic.Int32) http.Handler {
+	m := NewIdempotency(store, time.Hour)
+	return m.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Call", strings.Repeat("I", int(n)))
+		w.WriteHeader(status)
+		w.Write([]byte(`{"call":` + strings.Repeat("1", int(n)) + `}`))
+	}))
+}
+
+func idempotentRequest(method, path, userID, key, body string) *http.Request {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	if userID != "" {
+		req = req.WithContext(context.WithValue(req.Context(), UserIDKey, userID))
+	}
+	return req
+}
+
+func TestIdempotency_Replay(t *testing.T) {
+	var calls atomic.Int32
+	handler := newIdempotentHandler(memory.NewIdempotencyRepository(), http.StatusCreated, &calls)
+
+	first := httptest.NewRecorder()
+	handler.ServeHTTP(first, idempotentRequest(http.MethodPost, "/api/v1/wishlist", "user1", "key-1", `{"quantity":1}`))
+	retry := httptest.NewRecorder()
+	handler.ServeHTTP(retry, idempotentRequest(http.MethodPost, "/api/v1/wishlist", "user1", "key-1", `{"quantity":1}`))
+
+	if calls.Load() != 1 {
+		t.Fatalf("expected the handler to run once, ran %d times", calls.Load())
+	}
+	if retry.Code != http.StatusCreated || retry.Body.String() != first.Body.String() {
+		t.Errorf("expected the first response replayed, got %d %s", retry.Code, retry.Body.String())
+	}
+	if retry.Header().Get("X-Call") != "I" || retry.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("expected the first response's headers, got %v", retry.Header())
+	}
+	if retry.Header().Get(IdempotentReplayedHeader) != "true" || first.Header().Get(IdempotentReplayedHeader) != "" {
+		t.Errorf("expected only the replay marked, got %q and %q", first.Header().Get(IdempotentReplayedHeader), retry.Header().Get(IdempotentReplayedHeader))
+	}
+
+	// The same key is another user's own.
+	other := httptest.NewRecorder()
+	handler.ServeHTTP(other, idempotentRequest(http.MethodPost, "/api/v1/wishlist", "user2", "key-1", `{"quantity":1}`))
+	if calls.Load() != 2 || other.Header().Get(IdempotentReplayedHeader) != "" {
+		t.Errorf("expected another user's request to be served, got %d calls", calls.Load())
+	}
+}
+
+func TestIdempotency_PassThrough(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		userID string
+		key    string
+	}{
+		{name: "no key", method: http.MethodPost, userID: "user1"},
+		{name: "read", method: http.MethodGet, userID: "user1", key: "key-1"},
+		{name: "anonymous", method: http.MethodPost, key: "key-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			handler := newIdempotentHandler(memory.NewIdempotencyRepository(), http.StatusOK, &calls)
+			for range 2 {
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, idempotentRequest(tt.method, "/api/v1/wishlist", tt.userID, tt.key, ""))
+				if rec.Header().Get(IdempotentReplayedHeader) != "" {
+					t.Errorf("expected no replay")
+				}
+			}
+			if calls.Load() != 2 {
+				t.Errorf("expected the handler to run twice, ran %d times", calls.Load())
+			}
+		})
+	}
+}
+
+func TestIdempotency_Rejections(t *testing.T) {
+	t.Run("key reused for a different request", func(t *testing.T) {
+		for _, second := range []*http.Request{
+			idempotentRequest(http.MethodPost, "/api/v1/wishlist", "user1", "key-1", `{"quantity":2}`),
+			idempotentRequest(http.MethodDelete, "/api/v1/wishlist", "user1", "key-1", `{"quantity":1}`),
+			idempotentRequest(http.MethodPost, "/api/v1/wishlist?x=1", "user1", "key-1", `{"quantity":1}`),
+		} {
+			var calls atomic.Int32
+			handler := newIdempotentHandler(memory.NewIdempotencyRepository(), http.StatusCreated, &calls)
+			handler.ServeHTTP(httptest.NewRecorder(), idempotentRequest(http.MethodPost, "/api/v1/wishlist", "user1", "key-1", `{"quantity":1}`))
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, second)
+			if rec.Code != http.StatusUnprocessableEntity || !strings.Contains(rec.Body.String(), "idempotency_key_reused") {
+				t.Errorf("%s %s: expected 422, got %d %s", second.Method, second.URL, rec.Code, rec.Body.String())
+			}
+			if calls.Load() != 1 {
+				t.Errorf("expected the handler to run once, ran %d times", calls.Load())
+			}
+		}
+	})
+
+	t.Run("first request still in progress", func(t *testing.T) {
+		store := memory.NewIdempotencyRepository()
+		started, release := make(chan struct{}), make(chan struct{})
+		handler := NewIdempotency(store, time.Hour).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-release
+			w.WriteHeader(http.StatusCreated)
+		}))
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			handler.ServeHTTP(httptest.NewRecorder(), idempotentRequest(http.MethodPost, "/api/v1/wishlist", "user1", "key-1", ""))
+		}()
+		<-started
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, idempotentRequest(http.MethodPost, "/api/v1/wishlist", "user1", "key-1", ""))
+		close(release)
+		<-done
+		if rec.Code != http.StatusConflict || rec.Header().Get("Retry-After") == "" || !strings.Contains(rec.Body.String(), "idempotency_key_in_progress") {
+			t.Errorf("expected 409 with Retry-After, got %d %v %s", rec.Code, rec.Header(), rec.Body.String())
+		}
+	})
+
+	t.Run("key too long", func(t *testing.T) {
+		var calls atomic.Int32
+		handler := newIdempotentHandler(memory.NewIdempotencyRepository(), http.StatusCreated, &calls)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, idempotentRequest(http.MethodPost, "/api/v1/wishlist", "user1", strings.Repeat("k", 256), ""))
+		if rec.Code != http.StatusBadRequest || calls.Load() != 0 {
+			t.Errorf("expected 400 without calling the handler, got %d after %d calls", rec.Code, calls.Load())
+		}
+	})
+}
+
+func TestIdempotency_ServerErrorsAreRetried(t *testing.T) {
+	var calls atomic.Int32
+	handler := newIdempotentHandler(memory.NewIdempotencyRepository(), http.StatusInternalServerError, &calls)
+	for range 2 {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, idempotentRequest(http.MethodPost, "/api/v1/wishlist", "user1", "key-1", ""))
+		if rec.Code != http.StatusInternalServerError || rec.Header().Get(IdempotentReplayedHeader) != "" {
+			t.Errorf("expected a fresh 500, got %d %v", rec.Code, rec.Header())
+		}
+	}
+	if calls.Load() != 2 {
+		t.Errorf("expected the handler to run again after a 500, ran %d times", calls.Load())
+	}
+}
+
+func TestIdempotency_PanicReleasesKey(t *testing.T) {
+	store := memory.NewIdempotencyRepository()
+	var calls atomic.Int32
+	handler := NewIdempotency(store, time.Hour).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			panic("handler failed")
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	func() {
+		defer func() {
+			if p := recover(); p != "handler failed" {
+				t.Errorf("expected the panic to propagate, got %v", p)
+			}
+		}()
+		handler.ServeHTTP(httptest.NewRecorder(), idempotentRequest(http.MethodPost, "/api/v1/wishlist", "user1", "key-1", ""))
+	}()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, idempotentRequest(http.MethodPost, "/api/v1/wishlist", "user1", "key-1", ""))
+	if rec.Code != http.StatusCreated || calls.Load() != 2 {
+		t.Errorf("expected the retry to be served, got %d after %d calls", rec.Code, calls.Load())
+	}
+}
+
+func TestIdempotency_CompleteErrorReleasesKey(t *testing.T) {
+	var calls atomic.Int32
+	handler := newIdempotentHandler(failingCompleteStore{memory.NewIdempotencyRepository()}, http.StatusCreated, &calls)
+
+	// The client has gone by the time the response is stored.
+	ctx, cancel := context.WithCancel(context.Background())
+	req := idempotentRequest(http.MethodPost, "/api/v1/wishlist", "user1", "key-1", "")
+	req = req.WithContext(context.WithValue(ctx, UserIDKey, "user1"))
+	cancel()
+	for range 2 {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusCreated {
+			t.Errorf("expected the request to be served, got %d", rec.Code)
+		}
+	}
+	if calls.Load() != 2 {
+		t.Errorf("expected the retry to run the handler again, ran %d times", calls.Load())
+	}
+}
+
+func TestIdempotency_StoreError(t *testing.T) {
+	var calls atomic.Int32
+	handler := newIdempotentHandler(failingIdempotencyStore{}, http.StatusCreated, &calls)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, idempotentRequest(http.MethodPost, "/api/v1/wishlist", "user1", "key-1", ""))
+	if rec.Code != http.StatusCreated || calls.Load() != 1 {
+		t.Errorf("expected the request served without the store, got %d after %d calls", rec.Code, calls.Load())
+	}
+}
+
+func TestIdempotency_BodyPassedOn(t *testing.T) {
+	var got string
+	handler := NewIdempotency(memory.NewIdempotencyRepository(), time.Hour).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got = string(body)
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), idempotentRequest(http.MethodPost, "/api/v1/wishlist", "user1", "key-1", `{"quantity":1}`))
+	if got != `{"quantity":1}` {
+		t.Errorf("expected the handler to read the body, got %q", got)
+	}
+}
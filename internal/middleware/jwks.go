@@ -0,0 +1,192 @@
+package middleware
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"github.com/lestrrat-go/jwx/jwk"
+)
+
+const (
+	// DefaultJWKSRefreshInterval is how long fetched keys are trusted before
+	// the key set is fetched again.
+	DefaultJWKSRefreshInterval = 10 * time.Minute
+	// jwksRetryInterval spaces out fetches after a failure or an unknown kid,
+	// so tokens signed with a bogus kid cannot make every request refetch.
+	jwksRetryInterval = 30 * time.Second
+	// maxJWKSBytes bounds the key set document.
+	maxJWKSBytes = 1 << 20
+)
+
+var ErrUnknownSigningKey = errors.New("unknown signing key")
+
+// KeySet resolves the public key a token was signed with.
+type KeySet interface {
+	// Key returns the key with the given kid. An empty kid selects the key of
+	// a set that holds exactly one.
+	Key(ctx context.Context, kid string) (crypto.PublicKey, error)
+}
+
+// JWKS is a KeySet backed by a JSON Web Key Set URL, such as Supabase's
+// /auth/v1/.well-known/jwks.json. Keys are cached and fetched again once they
+// are older than the refresh interval, or early when a token names a kid the
+// cache does not hold, which is how a rotated key is picked up. If a fetch
+// fails the previously fetched keys keep being used.
+type JWKS struct {
+	url             string
+	refreshInterval time.Duration
+	client          *http.Client
+	now             func() time.Time
+
+	// fetchMu serializes fetches so a rotation triggers one request, not one
+	// per in-flight token.
+	fetchMu sync.Mutex
+
+	mu          sync.Mutex
+	keys        map[string]crypto.PublicKey
+	fetchedAt   time.Time
+	attemptedAt time.Time
+}
+
+func NewJWKS(url string, refreshInterval time.Duration) *JWKS {
+	if refreshInterval <= 0 {
+		refreshInterval = DefaultJWKSRefreshInterval
+	}
+	return &JWKS{
+		url:             url,
+		refreshInterval: refreshInterval,
+		client:          &http.Client{Timeout: 10 * time.Second},
+		now:             time.Now,
+	}
+}
+
+func (s *JWKS) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	if key, ok, fresh := s.cached(kid); ok && fresh {
+		return key, nil
+	}
+
+	s.fetchMu.Lock()
+	defer s.fetchMu.Unlock()
+
+	// Another request may have fetched while this one waited.
+	key, ok, fresh := s.cached(kid)
+	if ok && fresh {
+		return key, nil
+	}
+	if s.mayFetch() {
+		if err := s.refresh(ctx); err != nil {
+			logger.Warn(ctx, "auth: JWKS refresh failed, using cached keys", "url", s.url, "error", err)
+		}
+		key, ok, _ = s.cached(kid)
+	}
+
+	if !ok {
+		return nil, ErrUnknownSigningKey
+	}
+	return key, nil
+}
+
+// Refresh fetches the key set now. It is called at startup so the first
+// requests do not wait on the fetch.
+func (s *JWKS) Refresh(ctx context.Context) error {
+	s.fetchMu.Lock()
+	defer s.fetchMu.Unlock()
+	return s.refresh(ctx)
+}
+
+func (s *JWKS) refresh(ctx context.Context) error {
+	s.mu.Lock()
+	s.attemptedAt = s.now()
+	s.mu.Unlock()
+
+	keys, err := s.fetch(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.keys = keys
+	s.fetchedAt = s.now()
+	s.mu.Unlock()
+
+	logger.Info(ctx, "auth: JWKS refreshed", "keyCount", len(keys))
+	return nil
+}
+
+func (s *JWKS) cached(kid string) (key crypto.PublicKey, ok, fresh bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if kid == "" && len(s.keys) == 1 {
+		for _, only := range s.keys {
+			key, ok = only, true
+		}
+	} else {
+		key, ok = s.keys[kid]
+	}
+	return key, ok, s.now().Sub(s.fetchedAt) < s.refreshInterval
+}
+
+func (s *JWKS) mayFetch() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.attemptedAt.IsZero() || s.now().Sub(s.attemptedAt) >= jwksRetryInterval
+}
+
+// fetch downloads the key set and returns its signature verification keys by
+// kid. ECDSA and RSA keys are supported; encryption keys are skipped.
+func (s *JWKS) fetch(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching JWKS: unexpected status %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxJWKSBytes))
+	if err != nil {
+		return nil, err
+	}
+
+	set, err := jwk.Parse(body)
+	if err != nil {
+		return nil, fmt.Errorf("parsing JWKS: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, set.Len())
+	for i := 0; i < set.Len(); i++ {
+		key, _ := set.Get(i)
+		if key.KeyUsage() == string(jwk.ForEncryption) {
+			continue
+		}
+		var raw interface{}
+		if err := key.Raw(&raw); err != nil {
+			return nil, fmt.Errorf("reading JWKS key %q: %w", key.KeyID(), err)
+		}
+		switch raw.(type) {
+		case *ecdsa.PublicKey, *rsa.PublicKey:
+			keys[key.KeyID()] = raw
+		}
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("JWKS contains no usable signing keys")
+	}
+	return keys, nil
+}
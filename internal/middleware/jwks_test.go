@@ -0,0 +1,290 @@
+package middleware
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/lestrrat-go/jwx/jwk"
+)
+
+// jwksServer serves a mutable JSON Web Key Set and counts fetches.
+type jwksServer struct {
+	t *testing.T
+
+	mu      sync.Mutex
+	keys    map[string]crypto.PublicKey
+	fail    bool
+	fetches int
+}
+
+func newJWKSServer(t *testing.T, keys map[string]crypto.PublicKey) (*jwksServer, string) {
+	t.Helper()
+	s := &jwksServer{t: t, keys: keys}
+	server := httptest.NewServer(s)
+	t.Cleanup(server.Close)
+	return s, server.URL
+}
+
+func (s *jwksServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.fetches++
+	if s.fail {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	set := jwk.NewSet()
+	for kid, raw := range s.keys {
+		key, err := jwk.New(raw)
+		if err != nil {
+			s.t.Errorf("failed to build JWK: %v", err)
+			return
+		}
+		if err := key.Set(jwk.KeyIDKey, kid); err != nil {
+			s.t.Errorf("failed to set kid: %v", err)
+			return
+		}
+		set.Add(key)
+	}
+	if err := json.NewEncoder(w).Encode(set); err != nil {
+		s.t.Errorf("failed to encode JWKS: %v", err)
+	}
+}
+
+func (s *jwksServer) set(keys map[string]crypto.PublicKey, fail bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = keys
+	s.fail = fail
+}
+
+func (s *jwksServer) fetchCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.fetches
+}
+
+// fakeClock is a settable time source for JWKS.now.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func newTestJWKS(url string) (*JWKS, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	jwks := NewJWKS(url, time.Hour)
+	jwks.now = clock.Now
+	return jwks, clock
+}
+
+func TestJWKS_SelectsKeyByKid(t *testing.T) {
+	_, first := generateTestKeyPair(t)
+	_, second := generateTestKeyPair(t)
+	server, url := newJWKSServer(t, map[string]crypto.PublicKey{"first": first, "second": second})
+	jwks, _ := newTestJWKS(url)
+
+	for kid, expected := range map[string]crypto.PublicKey{"first": first, "second": second} {
+		key, err := jwks.Key(context.Background(), kid)
+		if err != nil {
+			t.Fatalf("kid %s: unexpected error: %v", kid, err)
+		}
+		if !expected.(interface{ Equal(crypto.PublicKey) bool }).Equal(key) {
+			t.Errorf("kid %s: got the wrong key", kid)
+		}
+	}
+	if _, err := jwks.Key(context.Background(), ""); !errors.Is(err, ErrUnknownSigningKey) {
+		t.Errorf("expected an empty kid to be rejected when the set has several keys, got %v", err)
+	}
+	if server.fetchCount() != 1 {
+		t.Errorf("expected one fetch, got %d", server.fetchCount())
+	}
+}
+
+func TestJWKS_EmptyKidSelectsOnlyKey(t *testing.T) {
+	_, only := generateTestKeyPair(t)
+	_, url := newJWKSServer(t, map[string]crypto.PublicKey{"only": only})
+	jwks, _ := newTestJWKS(url)
+
+	key, err := jwks.Key(context.Background(), "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !only.Equal(key) {
+		t.Error("expected the only key in the set")
+	}
+}
+
+func TestJWKS_Refresh(t *testing.T) {
+	t.Run("refetches after the refresh interval", func(t *testing.T) {
+		_, old := generateTestKeyPair(t)
+		_, rotated := generateTestKeyPair(t)
+		server, url := newJWKSServer(t, map[string]crypto.PublicKey{"key": old})
+		jwks, clock := newTestJWKS(url)
+
+		if _, err := jwks.Key(context.Background(), "key"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		server.set(map[string]crypto.PublicKey{"key": rotated}, false)
+
+		clock.now = clock.now.Add(59 * time.Minute)
+		key, _ := jwks.Key(context.Background(), "key")
+		if !old.Equal(key) || server.fetchCount() != 1 {
+			t.Fatalf("expected the cached key within the interval, fetches=%d", server.fetchCount())
+		}
+
+		clock.now = clock.now.Add(2 * time.Minute)
+		key, _ = jwks.Key(context.Background(), "key")
+		if !rotated.Equal(key) || server.fetchCount() != 2 {
+			t.Errorf("expected the refetched key after the interval, fetches=%d", server.fetchCount())
+		}
+	})
+
+	t.Run("unknown kid triggers an early refetch", func(t *testing.T) {
+		_, old := generateTestKeyPair(t)
+		_, rotated := generateTestKeyPair(t)
+		server, url := newJWKSServer(t, map[string]crypto.PublicKey{"old": old})
+		jwks, clock := newTestJWKS(url)
+
+		if _, err := jwks.Key(context.Background(), "old"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		server.set(map[string]crypto.PublicKey{"old": old, "new": rotated}, false)
+
+		clock.now = clock.now.Add(jwksRetryInterval)
+		key, err := jwks.Key(context.Background(), "new")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !rotated.Equal(key) {
+			t.Error("expected the rotated key")
+		}
+	})
+
+	t.Run("unknown kids are refetched at most once per retry interval", func(t *testing.T) {
+		_, only := generateTestKeyPair(t)
+		server, url := newJWKSServer(t, map[string]crypto.PublicKey{"only": only})
+		jwks, clock := newTestJWKS(url)
+
+		for i := 0; i < 5; i++ {
+			if _, err := jwks.Key(context.Background(), "bogus"); !errors.Is(err, ErrUnknownSigningKey) {
+				t.Fatalf("expected ErrUnknownSigningKey, got %v", err)
+			}
+		}
+		if server.fetchCount() != 1 {
+			t.Fatalf("expected one fetch, got %d", server.fetchCount())
+		}
+
+		clock.now = clock.now.Add(jwksRetryInterval)
+		jwks.Key(context.Background(), "bogus")
+		if server.fetchCount() != 2 {
+			t.Errorf("expected a second fetch after the retry interval, got %d", server.fetchCount())
+		}
+	})
+
+	t.Run("keeps cached keys when a refetch fails", func(t *testing.T) {
+		_, key := generateTestKeyPair(t)
+		server, url := newJWKSServer(t, map[string]crypto.PublicKey{"key": key})
+		jwks, clock := newTestJWKS(url)
+
+		if _, err := jwks.Key(context.Background(), "key"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		server.set(nil, true)
+
+		clock.now = clock.now.Add(2 * time.Hour)
+		got, err := jwks.Key(context.Background(), "key")
+		if err != nil {
+			t.Fatalf("expected the stale key to be served, got %v", err)
+		}
+		if !key.Equal(got) || server.fetchCount() != 2 {
+			t.Errorf("expected the stale key after one failed fetch, fetches=%d", server.fetchCount())
+		}
+	})
+
+	t.Run("no keys when the first fetch fails", func(t *testing.T) {
+		server, url := newJWKSServer(t, nil)
+		server.set(nil, true)
+		jwks, _ := newTestJWKS(url)
+
+		if err := jwks.Refresh(context.Background()); err == nil {
+			t.Error("expected refresh to fail")
+		}
+		if _, err := jwks.Key(context.Background(), "key"); !errors.Is(err, ErrUnknownSigningKey) {
+			t.Errorf("expected ErrUnknownSigningKey, got %v", err)
+		}
+	})
+
+	t.Run("set without usable keys", func(t *testing.T) {
+		_, url := newJWKSServer(t, map[string]crypto.PublicKey{})
+		jwks, _ := newTestJWKS(url)
+
+		if err := jwks.Refresh(context.Background()); err == nil {
+			t.Error("expected an empty key set to be rejected")
+		}
+	})
+}
+
+func TestAuthMiddleware_Authenticate_KeyRotation(t *testing.T) {
+	oldPrivate, oldPublic := generateTestKeyPair(t)
+	newPrivate, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate RSA key: %v", err)
+	}
+	server, url := newJWKSServer(t, map[string]crypto.PublicKey{"old": oldPublic})
+	jwks, clock := newTestJWKS(url)
+	middleware := NewAuthMiddleware(jwks)
+
+	sign := func(method jwt.SigningMethod, kid string, key crypto.PrivateKey) string {
+		token := jwt.NewWithClaims(method, jwt.MapClaims{"sub": "user-123", "exp": time.Now().Add(time.Hour).Unix()})
+		token.Header["kid"] = kid
+		signed, err := token.SignedString(key)
+		if err != nil {
+			t.Fatalf("failed to sign token: %v", err)
+		}
+		return signed
+	}
+	authenticate := func(token string) int {
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		middleware.Authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})).ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	oldToken := sign(jwt.SigningMethodES256, "old", oldPrivate)
+	newToken := sign(jwt.SigningMethodRS256, "new", newPrivate)
+
+	if code := authenticate(oldToken); code != http.StatusOK {
+		t.Fatalf("expected old token to be accepted, got %d", code)
+	}
+
+	server.set(map[string]crypto.PublicKey{"old": oldPublic, "new": &newPrivate.PublicKey}, false)
+	clock.now = clock.now.Add(jwksRetryInterval)
+
+	if code := authenticate(newToken); code != http.StatusOK {
+		t.Errorf("expected token signed with the rotated key to be accepted, got %d", code)
+	}
+	if code := authenticate(oldToken); code != http.StatusOK {
+		t.Errorf("expected old token to still be accepted, got %d", code)
+	}
+	if code := authenticate(sign(jwt.SigningMethodRS256, "old", newPrivate)); code != http.StatusUnauthorized {
+		t.Errorf("expected a token whose kid names a key of another type to be rejected, got %d", code)
+	}
+}
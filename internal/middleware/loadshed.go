@@ -0,0 +1,202 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/realtime"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"github.com/graytonio/warframe-wishlist/pkg/response"
+)
+
+// latencyDecay is the weight given to each new latency sample in the moving average.
+const latencyDecay = 0.2
+
+// latencyHalfLife is how fast the moving average falls back towards zero
+// while no request completes, so shedding every anonymous request cannot
+// keep the average above the target forever.
+const latencyHalfLife = 5 * time.Second
+
+// LoadShedder rejects low-priority traffic with 503 once the server is under
+// pressure, so authenticated user flows keep getting Mongo connections during
+// traffic spikes. Pressure is measured by in-flight request count and an
+// exponentially weighted moving average of request latency.
+//
+// Anonymous requests, whatever their method, are low priority and are shed
+// first: once in-flight requests reach half of maxInFlight, or average
+// latency exceeds the target. Authenticated requests are only rejected at
+// the hard maxInFlight cap.
+type LoadShedder struct {
+	maxInFlight   int64
+	lowWatermark  int64
+	latencyTarget time.Duration
+	retryAfter    time.Duration
+	// authenticated reports whether a request's credentials are valid,
+	// returning the request to serve in its place; it is only asked under
+	// pressure. Without it a request counts as authenticated when it carries
+	// a bearer JWT.
+	authenticated func(r *http.Request) (*http.Request, bool)
+	// exempt paths are neither counted nor shed.
+	exempt map[string]bool
+
+	inFlight atomic.Int64
+
+	mu         sync.Mutex
+	avgMillis  float64
+	observedAt time.Time
+	now        func() time.Time
+}
+
+func NewLoadShedder(maxInFlight int, latencyTarget time.Duration) *LoadShedder {
+	low := int64(maxInFlight / 2)
+	if low < 1 {
+		low = 1
+	}
+	return &LoadShedder{
+		maxInFlight:   int64(maxInFlight),
+		lowWatermark:  low,
+		latencyTarget: latencyTarget,
+		retryAfter:    5 * time.Second,
+		exempt:        make(map[string]bool),
+		now:           time.Now,
+	}
+}
+
+// SetAuthenticator makes authenticated decide which requests keep priority
+// under pressure, such as AuthMiddleware.Verify. The request it returns is
+// served, so it can carry what was verified on to later middleware.
+func (s *LoadShedder) SetAuthenticator(authenticated func(r *http.Request) (*http.Request, bool)) {
+	s.authenticated = authenticated
+}
+
+// Exempt lets requests for paths through untouched, for probes that must
+// answer whatever the load.
+func (s *LoadShedder) Exempt(paths ...string) {
+	for _, path := range paths {
+		s.exempt[path] = true
+	}
+}
+
+func (s *LoadShedder) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		// Live connections last as long as the client stays and are capped
+		// by their hub, so they are neither counted in flight nor timed.
+		if realtime.IsUpgrade(r) || s.exempt[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		current := s.inFlight.Add(1)
+		defer s.inFlight.Add(-1)
+
+		reason, r := s.shedReason(r, current)
+		if reason != "" {
+			logger.Warn(ctx, "load shedding: rejecting request",
+				"reason", reason,
+				"inFlight", current,
+				"avgLatencyMs", s.averageLatency().Milliseconds(),
+			)
+			w.Header().Set("Retry-After", strconv.Itoa(int(s.retryAfter.Seconds())))
+			response.Error(w, http.StatusServiceUnavailable, "server is busy, please retry later")
+			return
+		}
+
+		// Streamed responses, such as the item export, last as long as the
+		// client reads them, so they would skew the average.
+		sw := &streamWriter{ResponseWriter: w}
+		start := time.Now()
+		next.ServeHTTP(sw, r)
+		if !sw.streamed {
+			s.observe(time.Since(start))
+		}
+	})
+}
+
+// shedReason reports why r is shed, or "" to serve the request returned.
+func (s *LoadShedder) shedReason(r *http.Request, inFlight int64) (string, *http.Request) {
+	if inFlight > s.maxInFlight {
+		return "max in-flight exceeded", r
+	}
+	reason := ""
+	if inFlight > s.lowWatermark {
+		reason = "low-priority in-flight exceeded"
+	} else if s.latencyTarget > 0 && s.averageLatency() > s.latencyTarget {
+		reason = "latency target exceeded"
+	}
+	if reason == "" {
+		return "", r
+	}
+	r, ok := s.isAuthenticated(r)
+	if ok {
+		return "", r
+	}
+	return reason, r
+}
+
+func (s *LoadShedder) isAuthenticated(r *http.Request) (*http.Request, bool) {
+	if s.authenticated != nil {
+		return s.authenticated(r)
+	}
+	return r, hasBearerJWT(r)
+}
+
+func (s *LoadShedder) observe(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	ms := float64(d) / float64(time.Millisecond)
+	if s.avgMillis == 0 {
+		s.avgMillis = ms
+	} else {
+		s.avgMillis = latencyDecay*ms + (1-latencyDecay)*s.decayedMillis(now)
+	}
+	s.observedAt = now
+}
+
+func (s *LoadShedder) averageLatency() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return time.Duration(s.decayedMillis(s.now()) * float64(time.Millisecond))
+}
+
+// decayedMillis is the average halved for every latencyHalfLife since the
+// last sample. The caller holds mu.
+func (s *LoadShedder) decayedMillis(now time.Time) float64 {
+	idle := now.Sub(s.observedAt)
+	if idle <= 0 {
+		return s.avgMillis
+	}
+	return s.avgMillis * math.Pow(0.5, float64(idle)/float64(latencyHalfLife))
+}
+
+// hasBearerJWT reports whether r carries a bearer token shaped like a JWT,
+// three non-empty dot-separated parts, without checking its signature.
+func hasBearerJWT(r *http.Request) bool {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "bearer") {
+		return false
+	}
+	parts := strings.Split(token, ".")
+	return len(parts) == 3 && parts[0] != "" && parts[1] != "" && parts[2] != ""
+}
+
+// streamWriter records whether the handler flushed its response early.
+type streamWriter struct {
+	http.ResponseWriter
+	streamed bool
+}
+
+func (w *streamWriter) Flush() {
+	w.streamed = true
+	http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+func (w *streamWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
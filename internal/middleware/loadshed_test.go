@@ -0,0 +1,382 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+type signalKey struct{}
+
+// testBearer is shaped like a JWT, which is all the shedder checks without
+// an authenticator.
+const testBearer = "Bearer header.payload.signature"
+
+// blockingHandler signals on the request's signal channel (if any) and then
+// blocks until release is closed.
+func blockingHandler(release chan struct{}) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ch, ok := r.Context().Value(signalKey{}).(chan struct{}); ok {
+			ch <- struct{}{}
+			<-release
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+// fillInFlight starts n blocking requests and waits until they are all in flight.
+func fillInFlight(t *testing.T, handler http.Handler, n int) *sync.WaitGroup {
+	t.Helper()
+	done := &sync.WaitGroup{}
+	started := make(chan struct{}, n)
+
+	for i := 0; i < n; i++ {
+		done.Add(1)
+		go func() {
+			defer done.Done()
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/wishlist", nil)
+			req.Header.Set("Authorization", testBearer)
+			ctx := context.WithValue(req.Context(), signalKey{}, started)
+			handler.ServeHTTP(httptest.NewRecorder(), req.WithContext(ctx))
+		}()
+	}
+	for i := 0; i < n; i++ {
+		select {
+		case <-started:
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for in-flight requests")
+		}
+	}
+	return done
+}
+
+func TestLoadShedder_AllowsUnderCapacity(t *testing.T) {
+	shedder := NewLoadShedder(10, time.Second)
+	handler := shedder.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/items/search", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+}
+
+func TestLoadShedder_ShedsAnonymousBeforeAuthenticated(t *testing.T) {
+	shedder := NewLoadShedder(4, 0)
+	release := make(chan struct{})
+	handler := shedder.Middleware(blockingHandler(release))
+
+	done := fillInFlight(t, handler, 2)
+	defer func() {
+		close(release)
+		done.Wait()
+	}()
+
+	anon := httptest.NewRequest(http.MethodGet, "/api/v1/items/search", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, anon)
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected anonymous request to be shed with %d, got %d", http.StatusServiceUnavailable, rec.Code)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Error("expected Retry-After header on shed response")
+	}
+
+	authed := httptest.NewRequest(http.MethodGet, "/api/v1/wishlist", nil)
+	authed.Header.Set("Authorization", testBearer)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, authed)
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected authenticated request to pass with %d, got %d", http.StatusOK, rec.Code)
+	}
+}
+
+func TestLoadShedder_ShedsEverythingAtHardCap(t *testing.T) {
+	shedder := NewLoadShedder(2, 0)
+	release := make(chan struct{})
+	handler := shedder.Middleware(blockingHandler(release))
+
+	done := fillInFlight(t, handler, 2)
+	defer func() {
+		close(release)
+		done.Wait()
+	}()
+
+	authed := httptest.NewRequest(http.MethodGet, "/api/v1/wishlist", nil)
+	authed.Header.Set("Authorization", testBearer)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, authed)
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status %d at hard cap, got %d", http.StatusServiceUnavailable, rec.Code)
+	}
+}
+
+func TestLoadShedder_ShedsAnonymousOnHighLatency(t *testing.T) {
+	shedder := NewLoadShedder(100, 100*time.Millisecond)
+	shedder.observe(time.Second)
+
+	handler := shedder.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	anon := httptest.NewRequest(http.MethodGet, "/api/v1/items/search", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, anon)
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status %d, got %d", http.StatusServiceUnavailable, rec.Code)
+	}
+
+	authed := httptest.NewRequest(http.MethodGet, "/api/v1/wishlist", nil)
+	authed.Header.Set("Authorization", testBearer)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, authed)
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+}
+
+func TestLoadShedder_LatencyAverageRecovers(t *testing.T) {
+	shedder := NewLoadShedder(100, 100*time.Millisecond)
+	shedder.observe(time.Second)
+	for i := 0; i < 30; i++ {
+		shedder.observe(time.Millisecond)
+	}
+
+	if avg := shedder.averageLatency(); avg > 100*time.Millisecond {
+		t.Errorf("expected average latency to recover below target, got %v", avg)
+	}
+}
+
+func TestLoadShedder_ShedsAnonymousWritesToo(t *testing.T) {
+	shedder := NewLoadShedder(100, 100*time.Millisecond)
+	shedder.observe(time.Second)
+	handler := shedder.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		method   string
+		auth     string
+		expected int
+	}{
+		{method: http.MethodGet, expected: http.StatusServiceUnavailable},
+		{method: http.MethodPost, expected: http.StatusServiceUnavailable},
+		{method: http.MethodDelete, expected: http.StatusServiceUnavailable},
+		{method: http.MethodPost, auth: testBearer, expected: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.auth, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/v1/shared/abc/report", nil)
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.expected {
+				t.Errorf("expected %d, got %d", tt.expected, rec.Code)
+			}
+		})
+	}
+}
+
+func TestHasBearerJWT(t *testing.T) {
+	tests := []struct {
+		auth     string
+		expected bool
+	}{
+		{"", false},
+		{"x", false},
+		{"Bearer token", false},
+		{"Basic a.b.c", false},
+		{"Bearer a..c", false},
+		{testBearer, true},
+		{"bearer a.b.c", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.auth, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Authorization", tt.auth)
+			if got := hasBearerJWT(req); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestLoadShedder_ShedsInvalidCredentials(t *testing.T) {
+	shedder := NewLoadShedder(100, 100*time.Millisecond)
+	shedder.observe(time.Second)
+	handler := shedder.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	fake := httptest.NewRequest(http.MethodGet, "/api/v1/items/search", nil)
+	fake.Header.Set("Authorization", "x")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, fake)
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected a made-up Authorization header to be shed, got %d", rec.Code)
+	}
+
+	calls := 0
+	shedder.SetAuthenticator(func(r *http.Request) (*http.Request, bool) {
+		calls++
+		return r, r.Header.Get("Authorization") == "Bearer valid.jwt.token"
+	})
+	for auth, expected := range map[string]int{testBearer: http.StatusServiceUnavailable, "Bearer valid.jwt.token": http.StatusOK} {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/wishlist", nil)
+		req.Header.Set("Authorization", auth)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != expected {
+			t.Errorf("%s: expected %d, got %d", auth, expected, rec.Code)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("expected the authenticator asked for each request, got %d calls", calls)
+	}
+}
+
+func TestLoadShedder_AuthenticatesOnlyUnderPressure(t *testing.T) {
+	shedder := NewLoadShedder(100, time.Second)
+	shedder.SetAuthenticator(func(r *http.Request) (*http.Request, bool) {
+		t.Error("expected no verification without pressure")
+		return r, true
+	})
+	handler := shedder.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/wishlist", nil)
+	req.Header.Set("Authorization", testBearer)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+}
+
+func TestLoadShedder_ServesAuthenticatorRequest(t *testing.T) {
+	shedder := NewLoadShedder(100, 100*time.Millisecond)
+	shedder.observe(time.Second)
+	shedder.SetAuthenticator(func(r *http.Request) (*http.Request, bool) {
+		return r.WithContext(context.WithValue(r.Context(), signalKey{}, "verified")), true
+	})
+	var got any
+	handler := shedder.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Context().Value(signalKey{})
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/v1/wishlist", nil))
+	if got != "verified" {
+		t.Errorf("expected the authenticator's request served, got %v", got)
+	}
+}
+
+func TestLoadShedder_LatencyAverageDecaysWhileShedding(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	shedder := NewLoadShedder(100, 100*time.Millisecond)
+	shedder.now = func() time.Time { return now }
+	shedder.observe(time.Second)
+	handler := shedder.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	serve := func() int {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/items/search", nil))
+		return rec.Code
+	}
+	if code := serve(); code != http.StatusServiceUnavailable {
+		t.Fatalf("expected anonymous traffic shed after the spike, got %d", code)
+	}
+
+	// With only anonymous traffic nothing completes, yet after a quiet
+	// spell the average has decayed and it is let through again.
+	now = now.Add(4 * latencyHalfLife)
+	if code := serve(); code != http.StatusOK {
+		t.Errorf("expected anonymous traffic admitted once the average decayed, got %d (average %v)", code, shedder.averageLatency())
+	}
+}
+
+func TestLoadShedder_ExemptsProbes(t *testing.T) {
+	shedder := NewLoadShedder(1, time.Millisecond)
+	shedder.Exempt("/health", "/ready")
+	shedder.observe(time.Second)
+	release := make(chan struct{})
+	handler := shedder.Middleware(blockingHandler(release))
+
+	done := fillInFlight(t, handler, 1)
+	defer func() {
+		close(release)
+		done.Wait()
+	}()
+
+	for _, path := range []string{"/health", "/ready"} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusOK {
+			t.Errorf("%s: expected the probe to pass at the cap, got %d", path, rec.Code)
+		}
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/items/search", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected other anonymous reads shed, got %d", rec.Code)
+	}
+}
+
+func TestLoadShedder_SkipsStreamedResponses(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	shedder := NewLoadShedder(100, 100*time.Millisecond)
+	shedder.now = func() time.Time { return now }
+	handler := shedder.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		if err := http.NewResponseController(w).Flush(); err != nil {
+			t.Errorf("expected the flush to reach the recorder, got %v", err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/items/export", nil))
+	if !rec.Flushed {
+		t.Error("expected the response to be flushed")
+	}
+	if avg := shedder.averageLatency(); avg != 0 {
+		t.Errorf("expected the streamed response not to be timed, got %v", avg)
+	}
+}
+
+func TestLoadShedder_LetsWebSocketsThrough(t *testing.T) {
+	shedder := NewLoadShedder(1, 0)
+	release := make(chan struct{})
+	handler := shedder.Middleware(blockingHandler(release))
+
+	done := fillInFlight(t, handler, 1)
+	defer func() {
+		close(release)
+		done.Wait()
+	}()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/workspaces/abc/live", nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected the upgrade to pass at the cap, got %d", rec.Code)
+	}
+	if shedder.inFlight.Load() != 1 {
+		t.Errorf("expected the upgrade not to be counted, got %d in flight", shedder.inFlight.Load())
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/go-chi/chi/v5"
 	chimiddleware "github.com/go-chi/chi/v5/middleware"
 	"github.com/graytonio/warframe-wishlist/pkg/logger"
 )
@@ -22,7 +23,7 @@ func LoggingMiddleware(next http.Handler) http.Handler {
 
 		logger.Info(ctx, "request started",
 			"method", r.Method,
-			"path", r.URL.Path,
+			"path", logPath(r),
 			"remoteAddr", r.RemoteAddr,
 			"userAgent", r.UserAgent(),
 		)
@@ -32,7 +33,7 @@ func LoggingMiddleware(next http.Handler) http.Handler {
 
 		logger.Info(ctx, "request completed",
 			"method", r.Method,
-			"path", r.URL.Path,
+			"path", logPath(r),
 			"status", ww.Status(),
 			"bytes", ww.BytesWritten(),
 			"duration", time.Since(start).String(),
@@ -40,3 +41,16 @@ func LoggingMiddleware(next http.Handler) http.Handler {
 		)
 	})
 }
+
+// logPath is the path to log for r. When logs are anonymized it is the route
+// pattern, since paths such as /internal/users/{userID}/trace carry user IDs;
+// before routing, when there is no pattern yet, it is empty.
+func logPath(r *http.Request) string {
+	if !logger.Anonymizing() {
+		return r.URL.Path
+	}
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		return rctx.RoutePattern()
+	}
+	return ""
+}
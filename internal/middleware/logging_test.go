@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+)
+
+func TestLogPath(t *testing.T) {
+	var got string
+	r := chi.NewRouter()
+	r.Get("/internal/users/{userID}/trace", func(w http.ResponseWriter, r *http.Request) {
+		got = logPath(r)
+	})
+	serve := func() string {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/internal/users/user-123/trace", nil))
+		return got
+	}
+
+	if path := serve(); path != "/internal/users/user-123/trace" {
+		t.Errorf("expected the request path, got %q", path)
+	}
+
+	logger.EnableAnonymization("secret")
+	defer logger.DisableAnonymization()
+	if path := serve(); path != "/internal/users/{userID}/trace" {
+		t.Errorf("expected the route pattern when anonymizing, got %q", path)
+	}
+}
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"github.com/graytonio/warframe-wishlist/pkg/response"
+)
+
+// ReadOnly rejects every request that is not GET, HEAD or OPTIONS. Kiosk mode
+// applies it to the API in addition to not mounting the mutation routes, so a
+// route added later without a kiosk check still cannot write.
+func ReadOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		logger.Warn(r.Context(), "read-only mode: rejecting request", "method", r.Method, "path", r.URL.Path)
+		w.Header().Set("Allow", "GET, HEAD, OPTIONS")
+		response.Error(w, http.StatusMethodNotAllowed, "this instance is read-only")
+	})
+}
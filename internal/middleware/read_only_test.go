@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestReadOnly(t *testing.T) {
+	handler := ReadOnly(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	tests := map[string]int{
+		http.MethodGet:     http.StatusNoContent,
+		http.MethodHead:    http.StatusNoContent,
+		http.MethodOptions: http.StatusNoContent,
+		http.MethodPost:    http.StatusMethodNotAllowed,
+		http.MethodPut:     http.StatusMethodNotAllowed,
+		http.MethodPatch:   http.StatusMethodNotAllowed,
+		http.MethodDelete:  http.StatusMethodNotAllowed,
+	}
+
+	for method, expected := range tests {
+		t.Run(method, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(method, "/api/v1/wishlist", nil))
+
+			if rec.Code != expected {
+				t.Errorf("expected status %d, got %d", expected, rec.Code)
+			}
+			if expected == http.StatusMethodNotAllowed && rec.Header().Get("Allow") != "GET, HEAD, OPTIONS" {
+				t.Errorf("expected Allow header, got %q", rec.Header().Get("Allow"))
+			}
+		})
+	}
+}
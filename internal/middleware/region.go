@@ -0,0 +1,170 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"github.com/graytonio/warframe-wishlist/pkg/response"
+)
+
+// ForwardedRegionHeader names the region a request was forwarded from. The
+// primary serves such requests itself; a read region receiving one rejects it
+// instead of forwarding it again, which would only happen if two read regions
+// were configured as each other's primary.
+const ForwardedRegionHeader = "X-Forwarded-Region"
+
+// RegionHeader names the region that served a response.
+const RegionHeader = "X-Served-Region"
+
+// RegionName sets RegionHeader on every response. RegionForwarder replaces it
+// with the primary's on the requests it forwards.
+func RegionName(region string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set(RegionHeader, region)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// maxRecentWriters bounds the read-your-writes table; expired entries are
+// swept once it grows past this.
+const maxRecentWriters = 10000
+
+// RegionForwarder runs on read regions: instances whose MongoDB is a nearby
+// replica that cannot take writes. Reads are served locally; writes are
+// proxied to the primary region's API unchanged, including the Authorization
+// header, so the primary authenticates them as usual.
+//
+// Replication to the local replica lags behind the primary, so a client that
+// just wrote would otherwise read its own change back stale. For stickyFor
+// after a forwarded write, reads with the same Authorization header are
+// forwarded too.
+type RegionForwarder struct {
+	primary   *url.URL
+	stickyFor time.Duration
+	proxy     *httputil.ReverseProxy
+	now       func() time.Time
+
+	mu     sync.Mutex
+	recent map[[sha256.Size]byte]time.Time
+}
+
+func NewRegionForwarder(region string, primary *url.URL, stickyFor time.Duration) *RegionForwarder {
+	f := &RegionForwarder{
+		primary:   primary,
+		stickyFor: stickyFor,
+		now:       time.Now,
+		recent:    make(map[[sha256.Size]byte]time.Time),
+	}
+	f.proxy = &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.SetURL(primary)
+			pr.SetXForwarded()
+			if _, _, err := net.SplitHostPort(pr.In.RemoteAddr); err != nil {
+				// ClientIP left only the client's address, which
+				// SetXForwarded does not accept.
+				pr.Out.Header.Set("X-Forwarded-For", pr.In.RemoteAddr)
+			}
+			pr.Out.Header.Set(ForwardedRegionHeader, region)
+		},
+		ModifyResponse: func(resp *http.Response) error {
+			// The local CORS middleware already set these; keeping the
+			// primary's copies would send every header twice.
+			for name := range resp.Header {
+				if strings.HasPrefix(name, "Access-Control-") || name == "Vary" {
+					resp.Header.Del(name)
+				}
+			}
+			return nil
+		},
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			logger.Error(r.Context(), "region: forwarding to primary region failed", "primary", primary.Host, "method", r.Method, "path", r.URL.Path, "error", err)
+			response.ErrorCode(w, http.StatusBadGateway, response.CodePrimaryRegionUnavailable, "primary region unavailable")
+		},
+	}
+	return f
+}
+
+func (f *RegionForwarder) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		if from := r.Header.Get(ForwardedRegionHeader); from != "" {
+			logger.Error(ctx, "region: request already forwarded, check PRIMARY_REGION_URL", "from", from, "method", r.Method, "path", r.URL.Path)
+			response.ErrorCode(w, http.StatusLoopDetected, response.CodeRegionLoop, "request forwarded between read regions")
+			return
+		}
+
+		write := !isReadMethod(r.Method)
+		key, hasKey := clientKey(r)
+		if !write && !(hasKey && f.recentlyWrote(key)) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if write && hasKey {
+			f.markWrite(key)
+		}
+		w.Header().Del(RegionHeader)
+		logger.Debug(ctx, "region: forwarding request to primary region", "primary", f.primary.Host, "method", r.Method, "path", r.URL.Path, "write", write)
+		f.proxy.ServeHTTP(w, r)
+	})
+}
+
+func isReadMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// clientKey identifies a client by a hash of its Authorization header, so the
+// table never holds tokens. Anonymous requests have no key.
+func clientKey(r *http.Request) ([sha256.Size]byte, bool) {
+	authorization := r.Header.Get("Authorization")
+	if authorization == "" {
+		return [sha256.Size]byte{}, false
+	}
+	return sha256.Sum256([]byte(authorization)), true
+}
+
+func (f *RegionForwarder) markWrite(key [sha256.Size]byte) {
+	if f.stickyFor <= 0 {
+		return
+	}
+	now := f.now()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.recent) >= maxRecentWriters {
+		for k, until := range f.recent {
+			if !now.Before(until) {
+				delete(f.recent, k)
+			}
+		}
+	}
+	f.recent[key] = now.Add(f.stickyFor)
+}
+
+func (f *RegionForwarder) recentlyWrote(key [sha256.Size]byte) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	until, ok := f.recent[key]
+	if !ok {
+		return false
+	}
+	if !f.now().Before(until) {
+		delete(f.recent, key)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,171 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// newRegionTest starts a fake primary and returns a read-region handler in
+// front of a local handler; both record the requests they served.
+func newRegionTest(t *testing.T, stickyFor time.Duration) (http.Handler, *RegionForwarder, *[]*http.Request, *int) {
+	t.Helper()
+	var forwarded []*http.Request
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		forwarded = append(forwarded, r)
+		w.Header().Set("Access-Control-Allow-Origin", "https://primary.example")
+		w.Header().Set(RegionHeader, "us")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	t.Cleanup(primary.Close)
+
+	primaryURL, err := url.Parse(primary.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	local := 0
+	f := NewRegionForwarder("oce", primaryURL, stickyFor)
+	handler := RegionName("oce")(f.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		local++
+		w.WriteHeader(http.StatusOK)
+	})))
+	return handler, f, &forwarded, &local
+}
+
+func TestRegionForwarder_ServesReadsLocally(t *testing.T) {
+	handler, _, forwarded, local := newRegionTest(t, time.Minute)
+
+	for _, method := range []string{http.MethodGet, http.MethodHead, http.MethodOptions} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, "/api/v1/wishlist", nil))
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("%s: expected status 200, got %d", method, rec.Code)
+		}
+		if rec.Header().Get(RegionHeader) != "oce" {
+			t.Errorf("%s: expected served region oce, got %q", method, rec.Header().Get(RegionHeader))
+		}
+	}
+	if *local != 3 || len(*forwarded) != 0 {
+		t.Errorf("expected 3 local and 0 forwarded requests, got %d and %d", *local, len(*forwarded))
+	}
+}
+
+func TestRegionForwarder_ForwardsWrites(t *testing.T) {
+	handler, _, forwarded, local := newRegionTest(t, time.Minute)
+
+	for _, method := range []string{http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete} {
+		req := httptest.NewRequest(method, "/api/v1/wishlist?x=1", nil)
+		req.Header.Set("Authorization", "Bearer token")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusCreated {
+			t.Errorf("%s: expected the primary's status 201, got %d", method, rec.Code)
+		}
+		if got := rec.Header().Values(RegionHeader); len(got) != 1 || got[0] != "us" {
+			t.Errorf("%s: expected served region us, got %q", method, got)
+		}
+		if rec.Header().Get("Access-Control-Allow-Origin") != "" {
+			t.Errorf("%s: expected the primary's CORS headers to be dropped", method)
+		}
+	}
+
+	if *local != 0 || len(*forwarded) != 4 {
+		t.Fatalf("expected 0 local and 4 forwarded requests, got %d and %d", *local, len(*forwarded))
+	}
+	out := (*forwarded)[0]
+	if out.URL.Path != "/api/v1/wishlist" || out.URL.RawQuery != "x=1" {
+		t.Errorf("unexpected forwarded URL %s", out.URL)
+	}
+	if out.Header.Get("Authorization") != "Bearer token" {
+		t.Errorf("expected the Authorization header to be forwarded, got %q", out.Header.Get("Authorization"))
+	}
+	if out.Header.Get(ForwardedRegionHeader) != "oce" {
+		t.Errorf("expected forwarded region oce, got %q", out.Header.Get(ForwardedRegionHeader))
+	}
+}
+
+func TestRegionForwarder_ForwardsClientAddress(t *testing.T) {
+	handler, _, forwarded, _ := newRegionTest(t, time.Minute)
+
+	for _, remoteAddr := range []string{"203.0.113.7:5000", "203.0.113.7"} {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/wishlist", nil)
+		req.RemoteAddr = remoteAddr
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	if len(*forwarded) != 2 {
+		t.Fatalf("expected 2 forwarded requests, got %d", len(*forwarded))
+	}
+	for i, out := range *forwarded {
+		if got := out.Header.Get("X-Forwarded-For"); got != "203.0.113.7" {
+			t.Errorf("request %d: expected X-Forwarded-For 203.0.113.7, got %q", i, got)
+		}
+	}
+}
+
+func TestRegionForwarder_ReadYourWrites(t *testing.T) {
+	handler, f, forwarded, local := newRegionTest(t, 10*time.Second)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	f.now = func() time.Time { return now }
+
+	request := func(method, authorization string) {
+		req := httptest.NewRequest(method, "/api/v1/wishlist", nil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	request(http.MethodPost, "Bearer writer")
+	request(http.MethodGet, "Bearer writer")
+	if len(*forwarded) != 2 || *local != 0 {
+		t.Fatalf("expected the writer's read to be forwarded, got %d forwarded and %d local", len(*forwarded), *local)
+	}
+
+	request(http.MethodGet, "Bearer other")
+	request(http.MethodGet, "")
+	if *local != 2 {
+		t.Fatalf("expected other clients to read locally, got %d local", *local)
+	}
+
+	now = now.Add(10 * time.Second)
+	request(http.MethodGet, "Bearer writer")
+	if len(*forwarded) != 2 || *local != 3 {
+		t.Errorf("expected the writer to read locally after the window, got %d forwarded and %d local", len(*forwarded), *local)
+	}
+}
+
+func TestRegionForwarder_RejectsForwardingLoops(t *testing.T) {
+	handler, _, forwarded, local := newRegionTest(t, time.Minute)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/wishlist", nil)
+	req.Header.Set(ForwardedRegionHeader, "asia")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusLoopDetected {
+		t.Errorf("expected status 508, got %d", rec.Code)
+	}
+	if *local != 0 || len(*forwarded) != 0 {
+		t.Errorf("expected the request to be served nowhere, got %d local and %d forwarded", *local, len(*forwarded))
+	}
+}
+
+func TestRegionForwarder_PrimaryUnavailable(t *testing.T) {
+	primary := httptest.NewServer(http.NotFoundHandler())
+	primaryURL, _ := url.Parse(primary.URL)
+	primary.Close()
+
+	handler := NewRegionForwarder("oce", primaryURL, time.Minute).Middleware(http.NotFoundHandler())
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/wishlist", nil))
+
+	if rec.Code != http.StatusBadGateway {
+		t.Errorf("expected status 502, got %d", rec.Code)
+	}
+}
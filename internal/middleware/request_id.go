@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/graytonio/warframe-wishlist/internal/ids"
+)
+
+// RequestID gives each request an ID from gen, or keeps the one a proxy sent
+// in X-Request-Id, as chi's RequestID does. The ID goes where chi's would,
+// so chimiddleware.GetReqID finds it.
+func RequestID(gen ids.Generator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestID := r.Header.Get(chimiddleware.RequestIDHeader)
+			if requestID == "" {
+				requestID = gen.NewID()
+			}
+			ctx := context.WithValue(r.Context(), chimiddleware.RequestIDKey, requestID)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
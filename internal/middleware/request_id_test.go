@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+)
+
+type fixedIDs struct{ id string }
+
+func (g fixedIDs) NewID() string                     { return g.id }
+func (g fixedIDs) NewToken(bits int) (string, error) { return g.id, nil }
+
+func TestRequestID(t *testing.T) {
+	var got string
+	handler := RequestID(fixedIDs{id: "01ARYZ6S41TSV4RRFFQ69G5FAV"})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = chimiddleware.GetReqID(r.Context())
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if got != "01ARYZ6S41TSV4RRFFQ69G5FAV" {
+		t.Errorf("expected the generated ID, got %q", got)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Request-Id", "proxy-123")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if got != "proxy-123" {
+		t.Errorf("expected the proxy's ID, got %q", got)
+	}
+}
@@ -0,0 +1,160 @@
+package middleware
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+
+	"github.com/graytonio/warframe-wishlist/internal/audit"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"github.com/graytonio/warframe-wishlist/pkg/response"
+)
+
+// Role is the access a staff bearer token, or the role claim of a user's
+// token, grants. Each role can do
+// everything the roles below it can: moderators work the moderation tools,
+// such as revoking abusive share links, and admins also the sync and
+// user-data tools.
+type Role int
+
+const (
+	RoleNone Role = iota
+	RoleModerator
+	RoleAdmin
+)
+
+func (r Role) String() string {
+	switch r {
+	case RoleModerator:
+		return "moderator"
+	case RoleAdmin:
+		return "admin"
+	default:
+		return "none"
+	}
+}
+
+// ParseRole returns the role named name, RoleNone for any other name.
+func ParseRole(name string) Role {
+	switch name {
+	case "moderator":
+		return RoleModerator
+	case "admin":
+		return RoleAdmin
+	default:
+		return RoleNone
+	}
+}
+
+// StaffTokens are the bearer tokens of the staff roles. An empty token grants
+// nothing.
+type StaffTokens struct {
+	Admin     string
+	Moderator string
+}
+
+// Role returns the role r's bearer token grants, RoleNone for any other
+// token.
+func (t StaffTokens) Role(r *http.Request) Role {
+	presented, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	switch {
+	case !ok:
+		return RoleNone
+	case matchesToken(presented, t.Admin):
+		return RoleAdmin
+	case matchesToken(presented, t.Moderator):
+		return RoleModerator
+	default:
+		return RoleNone
+	}
+}
+
+func matchesToken(presented, token string) bool {
+	return token != "" && subtle.ConstantTimeCompare([]byte(presented), []byte(token)) == 1
+}
+
+// RequireRole admits requests whose credentials grant min or a higher role:
+// either a staff bearer token, or a user's JWT whose app_metadata.role claim
+// names the role, checked against users. Both grant the same roles, so every
+// staff route is reached with either. A request authenticated by its JWT
+// carries the user and role in its context, as after Authenticate.
+//
+// A bearer token that is neither is a 401, audited as an authentication
+// failure; a staff token or user of a lower role is a 403. users may be nil
+// to accept staff tokens only.
+func RequireRole(tokens StaffTokens, users *AuthMiddleware, min Role) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			role := tokens.Role(r)
+			userID := ""
+			if role == RoleNone && users != nil {
+				if sub, claimed, ok := users.identify(r); ok {
+					userID, role = sub, claimed
+					ctx := context.WithValue(r.Context(), UserIDKey, sub)
+					ctx = context.WithValue(ctx, RoleKey, claimed)
+					r = r.WithContext(logger.ContextWithUserID(ctx, sub))
+				}
+			}
+
+			switch {
+			case role == RoleNone && userID == "":
+				logger.Warn(r.Context(), "staff: invalid staff token", "path", logPath(r))
+				audit.RecordRequest(r, audit.TypeAuthFailure, audit.OutcomeFailure, "invalid staff token", "")
+				response.ErrorCode(w, http.StatusUnauthorized, response.CodeInvalidStaffToken, "invalid staff token")
+			case role < min:
+				logger.Warn(r.Context(), "staff: role not allowed", "role", role.String(), "required", min.String(), "path", logPath(r))
+				audit.RecordRequest(r, audit.TypeAuthFailure, audit.OutcomeFailure, role.String()+" role not allowed", userID)
+				response.ErrorCode(w, http.StatusForbidden, response.CodeInsufficientRole, "requires the "+min.String()+" role")
+			case userID != "":
+				users.serve(next, w, r, userID)
+			default:
+				next.ServeHTTP(w, r)
+			}
+		})
+	}
+}
+
+// AuditAdminActions records every request it serves as an admin.api audit
+// event: the authenticated user as the actor, none for a staff token, the {userID} route parameter,
+// if any, as the user acted on, and the route pattern and status as the
+// reason. Requests answered with an error status are recorded as failures.
+func AuditAdminActions(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ww, ok := w.(chimiddleware.WrapResponseWriter)
+		if !ok {
+			ww = chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		}
+
+		next.ServeHTTP(ww, r)
+
+		// Routing fills in the route context as next runs.
+		var pattern, target string
+		if rctx := chi.RouteContext(r.Context()); rctx != nil {
+			pattern = rctx.RoutePattern()
+			target = rctx.URLParam("userID")
+		}
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		outcome := audit.OutcomeSuccess
+		if status >= http.StatusBadRequest {
+			outcome = audit.OutcomeFailure
+		}
+		audit.Record(r.Context(), audit.Event{
+			Type:       audit.TypeAdminAPI,
+			Outcome:    outcome,
+			Reason:     fmt.Sprintf("%s %s %d", r.Method, pattern, status),
+			UserID:     target,
+			ActorID:    GetUserID(r.Context()),
+			RemoteAddr: r.RemoteAddr,
+			Method:     r.Method,
+			Path:       logPath(r),
+		})
+	})
+}
@@ -0,0 +1,175 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/graytonio/warframe-wishlist/internal/audit"
+)
+
+func TestStaffTokens_Role(t *testing.T) {
+	tokens := StaffTokens{Admin: "admin-secret", Moderator: "mod-secret"}
+	tests := map[string]Role{
+		"Bearer admin-secret": RoleAdmin,
+		"Bearer mod-secret":   RoleModerator,
+		"Bearer wrong":        RoleNone,
+		"mod-secret":          RoleNone,
+		"":                    RoleNone,
+	}
+	for header, expected := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		if got := tokens.Role(req); got != expected {
+			t.Errorf("%q: expected %s, got %s", header, expected, got)
+		}
+	}
+
+	// An unset token grants nothing, even to an empty bearer token.
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer ")
+	if got := (StaffTokens{}).Role(req); got != RoleNone {
+		t.Errorf("expected no role without tokens, got %s", got)
+	}
+}
+
+func TestRequireRole(t *testing.T) {
+	tokens := StaffTokens{Admin: "admin-secret", Moderator: "mod-secret"}
+	privateKey, publicKey := generateTestKeyPair(t)
+	users := NewAuthMiddleware(staticKeySet{"": publicKey})
+	userToken := func(role string) string {
+		claims := jwt.MapClaims{"sub": "user-123", "exp": time.Now().Add(time.Hour).Unix()}
+		if role != "" {
+			claims["app_metadata"] = map[string]interface{}{"role": role}
+		}
+		return createTestToken(privateKey, claims)
+	}
+
+	tests := []struct {
+		name           string
+		required       Role
+		token          string
+		expectedStatus int
+		expectedCode   string
+		expectedUserID string
+	}{
+		{name: "admin on admin route", required: RoleAdmin, token: "admin-secret", expectedStatus: http.StatusNoContent},
+		{name: "moderator on admin route", required: RoleAdmin, token: "mod-secret", expectedStatus: http.StatusForbidden, expectedCode: "insufficient_role"},
+		{name: "no token on admin route", required: RoleAdmin, expectedStatus: http.StatusUnauthorized, expectedCode: "invalid_staff_token"},
+		{name: "admin on moderation route", required: RoleModerator, token: "admin-secret", expectedStatus: http.StatusNoContent},
+		{name: "moderator on moderation route", required: RoleModerator, token: "mod-secret", expectedStatus: http.StatusNoContent},
+		{name: "wrong token on moderation route", required: RoleModerator, token: "user-jwt", expectedStatus: http.StatusUnauthorized, expectedCode: "invalid_staff_token"},
+		{name: "admin claim on admin route", required: RoleAdmin, token: userToken("admin"), expectedStatus: http.StatusNoContent, expectedUserID: "user-123"},
+		{name: "moderator claim on admin route", required: RoleAdmin, token: userToken("moderator"), expectedStatus: http.StatusForbidden, expectedCode: "insufficient_role"},
+		{name: "moderator claim on moderation route", required: RoleModerator, token: userToken("moderator"), expectedStatus: http.StatusNoContent, expectedUserID: "user-123"},
+		{name: "user without a role on moderation route", required: RoleModerator, token: userToken(""), expectedStatus: http.StatusForbidden, expectedCode: "insufficient_role"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotUserID string
+			handler := RequireRole(tokens, users, tt.required)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotUserID = GetUserID(r.Context())
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/internal/users/traces", nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if tt.expectedCode != "" && !strings.Contains(rec.Body.String(), `"code":"`+tt.expectedCode+`"`) {
+				t.Errorf("expected code %s, got %s", tt.expectedCode, rec.Body.String())
+			}
+			if gotUserID != tt.expectedUserID {
+				t.Errorf("expected user %q in the context, got %q", tt.expectedUserID, gotUserID)
+			}
+		})
+	}
+}
+
+func TestRequireRole_AuditsDenials(t *testing.T) {
+	capture := &auditCapture{}
+	audit.SetDefault(capture)
+	defer audit.SetDefault(nil)
+
+	privateKey, publicKey := generateTestKeyPair(t)
+	users := NewAuthMiddleware(staticKeySet{"": publicKey})
+	handler := RequireRole(StaffTokens{Moderator: "mod-secret"}, users, RoleAdmin)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	for _, token := range []string{
+		"mod-secret",
+		"wrong",
+		createTestToken(privateKey, jwt.MapClaims{"sub": "user-123", "app_metadata": map[string]interface{}{"role": "moderator"}}),
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/sync", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	if len(capture.events) != 3 {
+		t.Fatalf("expected the 3 denials to be audited, got %+v", capture.events)
+	}
+	for i, expectedUserID := range []string{"", "", "user-123"} {
+		if event := capture.events[i]; event.Type != audit.TypeAuthFailure || event.UserID != expectedUserID {
+			t.Errorf("unexpected event %+v", event)
+		}
+	}
+}
+
+func TestParseRole(t *testing.T) {
+	for name, expected := range map[string]Role{"admin": RoleAdmin, "moderator": RoleModerator, "Admin": RoleNone, "": RoleNone} {
+		if got := ParseRole(name); got != expected {
+			t.Errorf("%q: expected %s, got %s", name, expected, got)
+		}
+	}
+}
+
+func TestAuditAdminActions(t *testing.T) {
+	capture := &auditCapture{}
+	audit.SetDefault(capture)
+	defer audit.SetDefault(nil)
+
+	r := chi.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), UserIDKey, "admin-1")))
+		})
+	})
+	r.Use(AuditAdminActions)
+	r.Get("/admin/users/{userID}", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("{}"))
+	})
+	r.Post("/admin/sync", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+	})
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/admin/users/user-123", nil))
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/admin/sync", nil))
+
+	if len(capture.events) != 2 {
+		t.Fatalf("expected 2 audit events, got %+v", capture.events)
+	}
+	lookup, sync := capture.events[0], capture.events[1]
+	if lookup.Type != audit.TypeAdminAPI || lookup.Outcome != audit.OutcomeSuccess || lookup.ActorID != "admin-1" || lookup.UserID != "user-123" {
+		t.Errorf("unexpected lookup event %+v", lookup)
+	}
+	if lookup.Reason != "GET /admin/users/{userID} 200" || lookup.Path != "/admin/users/user-123" {
+		t.Errorf("expected the route and status, got %+v", lookup)
+	}
+	if sync.Outcome != audit.OutcomeFailure || sync.UserID != "" || sync.Reason != "POST /admin/sync 409" {
+		t.Errorf("unexpected sync event %+v", sync)
+	}
+}
@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/graytonio/warframe-wishlist/internal/cdn"
+)
+
+// SurrogateKeys tags every response with the all-items key and the current
+// data version key, so caching proxies can purge item responses in bulk.
+// Handlers add per-item keys on top with cdn.AddKeys.
+func SurrogateKeys(version func() string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			keys := []string{cdn.AllItemsKey}
+			if v := version(); v != "" {
+				keys = append(keys, cdn.DataVersionKey(v))
+			}
+			cdn.AddKeys(w.Header(), keys...)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
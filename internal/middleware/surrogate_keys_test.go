@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/cdn"
+)
+
+func TestSurrogateKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		version  string
+		expected string
+	}{
+		{name: "with data version", version: "20261017T000000Z", expected: "items data:20261017T000000Z item:/Lotus/A"},
+		{name: "without data version", version: "", expected: "items item:/Lotus/A"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := SurrogateKeys(func() string { return tt.version })(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				cdn.AddKeys(w.Header(), cdn.ItemKey("/Lotus/A"))
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/items/Lotus/A", nil))
+
+			if got := rr.Header().Get(cdn.SurrogateKeyHeader); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
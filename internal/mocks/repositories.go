@@ -2,18 +2,21 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"github.com/graytonio/warframe-wishlist/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type MockItemRepository struct {
-	SearchFunc                   func(ctx context.Context, params models.SearchParams) ([]models.ItemSearchResult, error)
+	SearchFunc                   func(ctx context.Context, params models.SearchParams) (*models.ItemSearchPage, error)
 	FindByUniqueNameFunc         func(ctx context.Context, uniqueName string) (*models.Item, error)
 	FindByUniqueNamesFunc        func(ctx context.Context, uniqueNames []string) (map[string]*models.Item, error)
 	SearchReusableBlueprintsFunc func(ctx context.Context, query string, limit int) ([]models.ItemSearchResult, error)
 }
 
-func (m *MockItemRepository) Search(ctx context.Context, params models.SearchParams) ([]models.ItemSearchResult, error) {
+func (m *MockItemRepository) Search(ctx context.Context, params models.SearchParams) (*models.ItemSearchPage, error) {
 	if m.SearchFunc != nil {
 		return m.SearchFunc(ctx, params)
 	}
@@ -41,12 +44,38 @@ func (m *MockItemRepository) SearchReusableBlueprints(ctx context.Context, query
 	return nil, nil
 }
 
+type MockRelicCatalog struct {
+	FindRelicsByNamesFunc func(ctx context.Context, names []string) (map[string]*models.Item, error)
+}
+
+func (m *MockRelicCatalog) FindRelicsByNames(ctx context.Context, names []string) (map[string]*models.Item, error) {
+	if m.FindRelicsByNamesFunc != nil {
+		return m.FindRelicsByNamesFunc(ctx, names)
+	}
+	return make(map[string]*models.Item), nil
+}
+
+type MockItemGraph struct {
+	FindComponentTreeFunc func(ctx context.Context, uniqueNames []string) (map[string]*models.Item, error)
+}
+
+func (m *MockItemGraph) FindComponentTree(ctx context.Context, uniqueNames []string) (map[string]*models.Item, error) {
+	if m.FindComponentTreeFunc != nil {
+		return m.FindComponentTreeFunc(ctx, uniqueNames)
+	}
+	return make(map[string]*models.Item), nil
+}
+
 type MockWishlistRepository struct {
 	GetByUserIDFunc         func(ctx context.Context, userID string) (*models.Wishlist, error)
 	CreateFunc              func(ctx context.Context, wishlist *models.Wishlist) error
+	CreateIfAbsentFunc      func(ctx context.Context, wishlist *models.Wishlist) (bool, error)
 	AddItemFunc             func(ctx context.Context, userID string, item models.WishlistItem) error
 	RemoveItemFunc          func(ctx context.Context, userID, uniqueName string) error
 	UpdateItemQuantityFunc  func(ctx context.Context, userID, uniqueName string, quantity int) error
+	SetItemLinksFunc        func(ctx context.Context, userID, uniqueName string, links []models.SourceLink) error
+	SetItemRecipeFunc       func(ctx context.Context, userID, uniqueName, recipeID string) error
+	SetItemProgressFunc     func(ctx context.Context, userID, uniqueName string, progress []models.ComponentProgress) error
 	UpsertFunc              func(ctx context.Context, wishlist *models.Wishlist) error
 }
 
@@ -85,6 +114,34 @@ func (m *MockWishlistRepository) UpdateItemQuantity(ctx context.Context, userID,
 	return nil
 }
 
+func (m *MockWishlistRepository) SetItemLinks(ctx context.Context, userID, uniqueName string, links []models.SourceLink) error {
+	if m.SetItemLinksFunc != nil {
+		return m.SetItemLinksFunc(ctx, userID, uniqueName, links)
+	}
+	return nil
+}
+
+func (m *MockWishlistRepository) SetItemRecipe(ctx context.Context, userID, uniqueName, recipeID string) error {
+	if m.SetItemRecipeFunc != nil {
+		return m.SetItemRecipeFunc(ctx, userID, uniqueName, recipeID)
+	}
+	return nil
+}
+
+func (m *MockWishlistRepository) SetItemProgress(ctx context.Context, userID, uniqueName string, progress []models.ComponentProgress) error {
+	if m.SetItemProgressFunc != nil {
+		return m.SetItemProgressFunc(ctx, userID, uniqueName, progress)
+	}
+	return nil
+}
+
+func (m *MockWishlistRepository) CreateIfAbsent(ctx context.Context, wishlist *models.Wishlist) (bool, error) {
+	if m.CreateIfAbsentFunc != nil {
+		return m.CreateIfAbsentFunc(ctx, wishlist)
+	}
+	return true, nil
+}
+
 func (m *MockWishlistRepository) Upsert(ctx context.Context, wishlist *models.Wishlist) error {
 	if m.UpsertFunc != nil {
 		return m.UpsertFunc(ctx, wishlist)
@@ -99,6 +156,10 @@ type MockOwnedBlueprintsRepository struct {
 	RemoveBlueprintFunc   func(ctx context.Context, userID, uniqueName string) error
 	BulkAddBlueprintsFunc func(ctx context.Context, userID string, blueprints []models.OwnedBlueprint) error
 	ClearAllFunc          func(ctx context.Context, userID string) error
+	SetBlueprintFunc      func(ctx context.Context, userID string, blueprint models.OwnedBlueprint) error
+	SetComponentFunc      func(ctx context.Context, userID string, component models.OwnedComponent) error
+	RemoveComponentFunc   func(ctx context.Context, userID, uniqueName string) error
+	ClearComponentsFunc   func(ctx context.Context, userID string) error
 }
 
 func (m *MockOwnedBlueprintsRepository) GetByUserID(ctx context.Context, userID string) (*models.OwnedBlueprints, error) {
@@ -142,3 +203,322 @@ func (m *MockOwnedBlueprintsRepository) ClearAll(ctx context.Context, userID str
 	}
 	return nil
 }
+
+func (m *MockOwnedBlueprintsRepository) SetBlueprint(ctx context.Context, userID string, blueprint models.OwnedBlueprint) error {
+	if m.SetBlueprintFunc != nil {
+		return m.SetBlueprintFunc(ctx, userID, blueprint)
+	}
+	return nil
+}
+
+func (m *MockOwnedBlueprintsRepository) SetComponent(ctx context.Context, userID string, component models.OwnedComponent) error {
+	if m.SetComponentFunc != nil {
+		return m.SetComponentFunc(ctx, userID, component)
+	}
+	return nil
+}
+
+func (m *MockOwnedBlueprintsRepository) RemoveComponent(ctx context.Context, userID, uniqueName string) error {
+	if m.RemoveComponentFunc != nil {
+		return m.RemoveComponentFunc(ctx, userID, uniqueName)
+	}
+	return nil
+}
+
+func (m *MockOwnedBlueprintsRepository) ClearComponents(ctx context.Context, userID string) error {
+	if m.ClearComponentsFunc != nil {
+		return m.ClearComponentsFunc(ctx, userID)
+	}
+	return nil
+}
+
+type MockOwnedMaterialsRepository struct {
+	GetByUserIDFunc func(ctx context.Context, userID string) ([]models.OwnedMaterial, error)
+	SetCountsFunc   func(ctx context.Context, userID string, counts map[string]int) error
+	ClearAllFunc    func(ctx context.Context, userID string) error
+}
+
+func (m *MockOwnedMaterialsRepository) GetByUserID(ctx context.Context, userID string) ([]models.OwnedMaterial, error) {
+	if m.GetByUserIDFunc != nil {
+		return m.GetByUserIDFunc(ctx, userID)
+	}
+	return []models.OwnedMaterial{}, nil
+}
+
+func (m *MockOwnedMaterialsRepository) SetCounts(ctx context.Context, userID string, counts map[string]int) error {
+	if m.SetCountsFunc != nil {
+		return m.SetCountsFunc(ctx, userID, counts)
+	}
+	return nil
+}
+
+func (m *MockOwnedMaterialsRepository) ClearAll(ctx context.Context, userID string) error {
+	if m.ClearAllFunc != nil {
+		return m.ClearAllFunc(ctx, userID)
+	}
+	return nil
+}
+
+type MockMasteryRepository struct {
+	ListByUserFunc  func(ctx context.Context, userID string) ([]models.MasteryEntry, error)
+	GetFunc         func(ctx context.Context, userID, uniqueName string) (*models.MasteryEntry, error)
+	SetStatusesFunc func(ctx context.Context, userID string, statuses map[string]string) error
+	ClearAllFunc    func(ctx context.Context, userID string) error
+}
+
+func (m *MockMasteryRepository) ListByUser(ctx context.Context, userID string) ([]models.MasteryEntry, error) {
+	if m.ListByUserFunc != nil {
+		return m.ListByUserFunc(ctx, userID)
+	}
+	return []models.MasteryEntry{}, nil
+}
+
+func (m *MockMasteryRepository) Get(ctx context.Context, userID, uniqueName string) (*models.MasteryEntry, error) {
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx, userID, uniqueName)
+	}
+	return nil, nil
+}
+
+func (m *MockMasteryRepository) SetStatuses(ctx context.Context, userID string, statuses map[string]string) error {
+	if m.SetStatusesFunc != nil {
+		return m.SetStatusesFunc(ctx, userID, statuses)
+	}
+	return nil
+}
+
+func (m *MockMasteryRepository) ClearAll(ctx context.Context, userID string) error {
+	if m.ClearAllFunc != nil {
+		return m.ClearAllFunc(ctx, userID)
+	}
+	return nil
+}
+
+type MockSettingsRepository struct {
+	GetByUserIDFunc func(ctx context.Context, userID string) (*models.UserSettings, error)
+	UpsertFunc      func(ctx context.Context, settings *models.UserSettings) error
+}
+
+func (m *MockSettingsRepository) GetByUserID(ctx context.Context, userID string) (*models.UserSettings, error) {
+	if m.GetByUserIDFunc != nil {
+		return m.GetByUserIDFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *MockSettingsRepository) Upsert(ctx context.Context, settings *models.UserSettings) error {
+	if m.UpsertFunc != nil {
+		return m.UpsertFunc(ctx, settings)
+	}
+	return nil
+}
+
+type MockUserTraceRepository struct {
+	GetByUserIDFunc func(ctx context.Context, userID string) (*models.UserTrace, error)
+	UpsertFunc      func(ctx context.Context, trace *models.UserTrace) error
+	DeleteFunc      func(ctx context.Context, userID string) error
+	ListActiveFunc  func(ctx context.Context, now time.Time) ([]models.UserTrace, error)
+}
+
+func (m *MockUserTraceRepository) GetByUserID(ctx context.Context, userID string) (*models.UserTrace, error) {
+	if m.GetByUserIDFunc != nil {
+		return m.GetByUserIDFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *MockUserTraceRepository) Upsert(ctx context.Context, trace *models.UserTrace) error {
+	if m.UpsertFunc != nil {
+		return m.UpsertFunc(ctx, trace)
+	}
+	return nil
+}
+
+func (m *MockUserTraceRepository) Delete(ctx context.Context, userID string) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, userID)
+	}
+	return nil
+}
+
+func (m *MockUserTraceRepository) ListActive(ctx context.Context, now time.Time) ([]models.UserTrace, error) {
+	if m.ListActiveFunc != nil {
+		return m.ListActiveFunc(ctx, now)
+	}
+	return []models.UserTrace{}, nil
+}
+
+type MockPopularityRepository struct {
+	TopWishlistedItemsFunc func(ctx context.Context, limit int) ([]models.ItemPopularity, error)
+	WishlistSizeStatsFunc  func(ctx context.Context) (*models.WishlistSizeStats, error)
+}
+
+func (m *MockPopularityRepository) TopWishlistedItems(ctx context.Context, limit int) ([]models.ItemPopularity, error) {
+	if m.TopWishlistedItemsFunc != nil {
+		return m.TopWishlistedItemsFunc(ctx, limit)
+	}
+	return nil, nil
+}
+
+func (m *MockPopularityRepository) WishlistSizeStats(ctx context.Context) (*models.WishlistSizeStats, error) {
+	if m.WishlistSizeStatsFunc != nil {
+		return m.WishlistSizeStatsFunc(ctx)
+	}
+	return &models.WishlistSizeStats{}, nil
+}
+
+type MockHouseholdRepository struct {
+	GetLinkByMemberFunc      func(ctx context.Context, memberID string) (*models.HouseholdLink, error)
+	ListLinksByManagerFunc   func(ctx context.Context, managerID string) ([]models.HouseholdLink, error)
+	CreateLinkFunc           func(ctx context.Context, link *models.HouseholdLink) (bool, error)
+	UpdateLinkFunc           func(ctx context.Context, memberID, status string, quantityThreshold int) error
+	DeleteLinkFunc           func(ctx context.Context, memberID string) error
+	CreatePendingChangeFunc  func(ctx context.Context, change *models.PendingChange) error
+	GetPendingChangeFunc     func(ctx context.Context, id primitive.ObjectID) (*models.PendingChange, error)
+	ListChangesByManagerFunc func(ctx context.Context, managerID, status string) ([]models.PendingChange, error)
+	ListChangesByMemberFunc  func(ctx context.Context, memberID, status string) ([]models.PendingChange, error)
+	DecideChangeFunc         func(ctx context.Context, id primitive.ObjectID, status string) (bool, error)
+}
+
+func (m *MockHouseholdRepository) GetLinkByMember(ctx context.Context, memberID string) (*models.HouseholdLink, error) {
+	if m.GetLinkByMemberFunc != nil {
+		return m.GetLinkByMemberFunc(ctx, memberID)
+	}
+	return nil, nil
+}
+
+func (m *MockHouseholdRepository) ListLinksByManager(ctx context.Context, managerID string) ([]models.HouseholdLink, error) {
+	if m.ListLinksByManagerFunc != nil {
+		return m.ListLinksByManagerFunc(ctx, managerID)
+	}
+	return []models.HouseholdLink{}, nil
+}
+
+func (m *MockHouseholdRepository) CreateLink(ctx context.Context, link *models.HouseholdLink) (bool, error) {
+	if m.CreateLinkFunc != nil {
+		return m.CreateLinkFunc(ctx, link)
+	}
+	return true, nil
+}
+
+func (m *MockHouseholdRepository) UpdateLink(ctx context.Context, memberID, status string, quantityThreshold int) error {
+	if m.UpdateLinkFunc != nil {
+		return m.UpdateLinkFunc(ctx, memberID, status, quantityThreshold)
+	}
+	return nil
+}
+
+func (m *MockHouseholdRepository) DeleteLink(ctx context.Context, memberID string) error {
+	if m.DeleteLinkFunc != nil {
+		return m.DeleteLinkFunc(ctx, memberID)
+	}
+	return nil
+}
+
+func (m *MockHouseholdRepository) CreatePendingChange(ctx context.Context, change *models.PendingChange) error {
+	if m.CreatePendingChangeFunc != nil {
+		return m.CreatePendingChangeFunc(ctx, change)
+	}
+	return nil
+}
+
+func (m *MockHouseholdRepository) GetPendingChange(ctx context.Context, id primitive.ObjectID) (*models.PendingChange, error) {
+	if m.GetPendingChangeFunc != nil {
+		return m.GetPendingChangeFunc(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *MockHouseholdRepository) ListChangesByManager(ctx context.Context, managerID, status string) ([]models.PendingChange, error) {
+	if m.ListChangesByManagerFunc != nil {
+		return m.ListChangesByManagerFunc(ctx, managerID, status)
+	}
+	return []models.PendingChange{}, nil
+}
+
+func (m *MockHouseholdRepository) ListChangesByMember(ctx context.Context, memberID, status string) ([]models.PendingChange, error) {
+	if m.ListChangesByMemberFunc != nil {
+		return m.ListChangesByMemberFunc(ctx, memberID, status)
+	}
+	return []models.PendingChange{}, nil
+}
+
+func (m *MockHouseholdRepository) DecideChange(ctx context.Context, id primitive.ObjectID, status string) (bool, error) {
+	if m.DecideChangeFunc != nil {
+		return m.DecideChangeFunc(ctx, id, status)
+	}
+	return true, nil
+}
+
+type MockItemChangeRepository struct {
+	GetFingerprintsFunc     func(ctx context.Context) (map[string]models.ItemFingerprint, error)
+	ReplaceFingerprintsFunc func(ctx context.Context, fingerprints []models.ItemFingerprint) error
+	RecordChangesFunc       func(ctx context.Context, changes []models.ItemChange) error
+	ListChangesSinceFunc    func(ctx context.Context, since time.Time, limit int) ([]models.ItemChange, error)
+}
+
+func (m *MockItemChangeRepository) GetFingerprints(ctx context.Context) (map[string]models.ItemFingerprint, error) {
+	if m.GetFingerprintsFunc != nil {
+		return m.GetFingerprintsFunc(ctx)
+	}
+	return map[string]models.ItemFingerprint{}, nil
+}
+
+func (m *MockItemChangeRepository) ReplaceFingerprints(ctx context.Context, fingerprints []models.ItemFingerprint) error {
+	if m.ReplaceFingerprintsFunc != nil {
+		return m.ReplaceFingerprintsFunc(ctx, fingerprints)
+	}
+	return nil
+}
+
+func (m *MockItemChangeRepository) RecordChanges(ctx context.Context, changes []models.ItemChange) error {
+	if m.RecordChangesFunc != nil {
+		return m.RecordChangesFunc(ctx, changes)
+	}
+	return nil
+}
+
+func (m *MockItemChangeRepository) ListChangesSince(ctx context.Context, since time.Time, limit int) ([]models.ItemChange, error) {
+	if m.ListChangesSinceFunc != nil {
+		return m.ListChangesSinceFunc(ctx, since, limit)
+	}
+	return []models.ItemChange{}, nil
+}
+
+type MockItemSyncer struct {
+	EnsureIndexesFunc  func(ctx context.Context, collection string) error
+	SyncCollectionFunc func(ctx context.Context, collection string, items []bson.M, dryRun bool) (models.ItemSyncStats, error)
+}
+
+func (m *MockItemSyncer) EnsureIndexes(ctx context.Context, collection string) error {
+	if m.EnsureIndexesFunc != nil {
+		return m.EnsureIndexesFunc(ctx, collection)
+	}
+	return nil
+}
+
+func (m *MockItemSyncer) SyncCollection(ctx context.Context, collection string, items []bson.M, dryRun bool) (models.ItemSyncStats, error) {
+	if m.SyncCollectionFunc != nil {
+		return m.SyncCollectionFunc(ctx, collection, items, dryRun)
+	}
+	return models.ItemSyncStats{Unchanged: len(items)}, nil
+}
+
+type MockActivityRepository struct {
+	CreateFunc     func(ctx context.Context, entry *models.ActivityEntry) error
+	ListByUserFunc func(ctx context.Context, userID string, offset, limit int) ([]models.ActivityEntry, int, error)
+}
+
+func (m *MockActivityRepository) Create(ctx context.Context, entry *models.ActivityEntry) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, entry)
+	}
+	return nil
+}
+
+func (m *MockActivityRepository) ListByUser(ctx context.Context, userID string, offset, limit int) ([]models.ActivityEntry, int, error) {
+	if m.ListByUserFunc != nil {
+		return m.ListByUserFunc(ctx, userID, offset, limit)
+	}
+	return []models.ActivityEntry{}, 0, nil
+}
@@ -2,17 +2,19 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"github.com/graytonio/warframe-wishlist/internal/models"
 )
 
 type MockItemService struct {
-	SearchFunc                   func(ctx context.Context, params models.SearchParams) ([]models.ItemSearchResult, error)
+	SearchFunc                   func(ctx context.Context, params models.SearchParams) (*models.ItemSearchPage, error)
 	GetByUniqueNameFunc          func(ctx context.Context, uniqueName string) (*models.Item, error)
 	SearchReusableBlueprintsFunc func(ctx context.Context, query string, limit int) ([]models.ItemSearchResult, error)
+	GetRecipeTreeFunc            func(ctx context.Context, uniqueName string) (*models.RecipeTree, error)
 }
 
-func (m *MockItemService) Search(ctx context.Context, params models.SearchParams) ([]models.ItemSearchResult, error) {
+func (m *MockItemService) Search(ctx context.Context, params models.SearchParams) (*models.ItemSearchPage, error) {
 	if m.SearchFunc != nil {
 		return m.SearchFunc(ctx, params)
 	}
@@ -33,11 +35,63 @@ func (m *MockItemService) SearchReusableBlueprints(ctx context.Context, query st
 	return nil, nil
 }
 
+func (m *MockItemService) GetRecipeTree(ctx context.Context, uniqueName string) (*models.RecipeTree, error) {
+	if m.GetRecipeTreeFunc != nil {
+		return m.GetRecipeTreeFunc(ctx, uniqueName)
+	}
+	return nil, nil
+}
+
+type MockItemChangeService struct {
+	ListChangesFunc func(ctx context.Context, since time.Time, limit int) (*models.ItemChangesResponse, error)
+}
+
+func (m *MockItemChangeService) ListChanges(ctx context.Context, since time.Time, limit int) (*models.ItemChangesResponse, error) {
+	if m.ListChangesFunc != nil {
+		return m.ListChangesFunc(ctx, since, limit)
+	}
+	return &models.ItemChangesResponse{Since: since, Changes: []models.ItemChange{}}, nil
+}
+
+type MockItemExportService struct {
+	CountCategoryFunc  func(ctx context.Context, category string) (int, error)
+	ExportCategoryFunc func(ctx context.Context, category string, skip, limit int, fn func(item *models.Item) error) error
+}
+
+func (m *MockItemExportService) CountCategory(ctx context.Context, category string) (int, error) {
+	if m.CountCategoryFunc != nil {
+		return m.CountCategoryFunc(ctx, category)
+	}
+	return 0, nil
+}
+
+func (m *MockItemExportService) ExportCategory(ctx context.Context, category string, skip, limit int, fn func(item *models.Item) error) error {
+	if m.ExportCategoryFunc != nil {
+		return m.ExportCategoryFunc(ctx, category, skip, limit, fn)
+	}
+	return nil
+}
+
+type MockItemAutocompleteService struct {
+	SuggestFunc func(ctx context.Context, query string, limit int) ([]models.ItemSuggestion, error)
+}
+
+func (m *MockItemAutocompleteService) Suggest(ctx context.Context, query string, limit int) ([]models.ItemSuggestion, error) {
+	if m.SuggestFunc != nil {
+		return m.SuggestFunc(ctx, query, limit)
+	}
+	return []models.ItemSuggestion{}, nil
+}
+
 type MockWishlistService struct {
-	GetWishlistFunc    func(ctx context.Context, userID string) (*models.Wishlist, error)
-	AddItemFunc        func(ctx context.Context, userID string, req models.AddItemRequest) error
-	RemoveItemFunc     func(ctx context.Context, userID, uniqueName string) error
-	UpdateQuantityFunc func(ctx context.Context, userID, uniqueName string, quantity int) error
+	GetWishlistFunc         func(ctx context.Context, userID string) (*models.Wishlist, error)
+	AddItemFunc             func(ctx context.Context, userID string, req models.AddItemRequest) error
+	RemoveItemFunc          func(ctx context.Context, userID, uniqueName string) error
+	UpdateQuantityFunc      func(ctx context.Context, userID, uniqueName string, quantity int) error
+	SetItemLinksFunc        func(ctx context.Context, userID, uniqueName string, links []models.SourceLinkRequest) ([]models.SourceLink, error)
+	SetItemRecipeFunc       func(ctx context.Context, userID, uniqueName, recipeID string) error
+	SetItemProgressFunc     func(ctx context.Context, userID, uniqueName string, progress []models.ComponentProgressRequest) (*models.WishlistItem, error)
+	GetExpandedWishlistFunc func(ctx context.Context, userID string) (*models.ExpandedWishlist, error)
 }
 
 func (m *MockWishlistService) GetWishlist(ctx context.Context, userID string) (*models.Wishlist, error) {
@@ -68,6 +122,88 @@ func (m *MockWishlistService) UpdateQuantity(ctx context.Context, userID, unique
 	return nil
 }
 
+func (m *MockWishlistService) SetItemLinks(ctx context.Context, userID, uniqueName string, links []models.SourceLinkRequest) ([]models.SourceLink, error) {
+	if m.SetItemLinksFunc != nil {
+		return m.SetItemLinksFunc(ctx, userID, uniqueName, links)
+	}
+	return nil, nil
+}
+
+func (m *MockWishlistService) SetItemRecipe(ctx context.Context, userID, uniqueName, recipeID string) error {
+	if m.SetItemRecipeFunc != nil {
+		return m.SetItemRecipeFunc(ctx, userID, uniqueName, recipeID)
+	}
+	return nil
+}
+
+func (m *MockWishlistService) SetItemProgress(ctx context.Context, userID, uniqueName string, progress []models.ComponentProgressRequest) (*models.WishlistItem, error) {
+	if m.SetItemProgressFunc != nil {
+		return m.SetItemProgressFunc(ctx, userID, uniqueName, progress)
+	}
+	return &models.WishlistItem{UniqueName: uniqueName}, nil
+}
+
+func (m *MockWishlistService) GetExpandedWishlist(ctx context.Context, userID string) (*models.ExpandedWishlist, error) {
+	if m.GetExpandedWishlistFunc != nil {
+		return m.GetExpandedWishlistFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+type MockWishlistImportService struct {
+	PreviewTextFunc func(ctx context.Context, text string) (*models.ImportPreview, error)
+	ConfirmFunc     func(ctx context.Context, userID string, req models.ImportConfirmRequest) (*models.ImportConfirmResult, error)
+}
+
+func (m *MockWishlistImportService) PreviewText(ctx context.Context, text string) (*models.ImportPreview, error) {
+	if m.PreviewTextFunc != nil {
+		return m.PreviewTextFunc(ctx, text)
+	}
+	return &models.ImportPreview{}, nil
+}
+
+func (m *MockWishlistImportService) Confirm(ctx context.Context, userID string, req models.ImportConfirmRequest) (*models.ImportConfirmResult, error) {
+	if m.ConfirmFunc != nil {
+		return m.ConfirmFunc(ctx, userID, req)
+	}
+	return &models.ImportConfirmResult{}, nil
+}
+
+type MockWishlistTransferService struct {
+	ExportFunc    func(ctx context.Context, userID string) (*models.WishlistExport, error)
+	ChecklistFunc func(ctx context.Context, userID string) (*models.WishlistChecklist, error)
+	ImportFunc    func(ctx context.Context, userID string, req models.WishlistDocumentImportRequest) (*models.WishlistDocumentImportResult, error)
+	MergeFunc     func(ctx context.Context, userID string, req models.WishlistMergeRequest) (*models.WishlistMergeResult, error)
+}
+
+func (m *MockWishlistTransferService) Export(ctx context.Context, userID string) (*models.WishlistExport, error) {
+	if m.ExportFunc != nil {
+		return m.ExportFunc(ctx, userID)
+	}
+	return &models.WishlistExport{}, nil
+}
+
+func (m *MockWishlistTransferService) Checklist(ctx context.Context, userID string) (*models.WishlistChecklist, error) {
+	if m.ChecklistFunc != nil {
+		return m.ChecklistFunc(ctx, userID)
+	}
+	return &models.WishlistChecklist{}, nil
+}
+
+func (m *MockWishlistTransferService) Import(ctx context.Context, userID string, req models.WishlistDocumentImportRequest) (*models.WishlistDocumentImportResult, error) {
+	if m.ImportFunc != nil {
+		return m.ImportFunc(ctx, userID, req)
+	}
+	return &models.WishlistDocumentImportResult{}, nil
+}
+
+func (m *MockWishlistTransferService) Merge(ctx context.Context, userID string, req models.WishlistMergeRequest) (*models.WishlistMergeResult, error) {
+	if m.MergeFunc != nil {
+		return m.MergeFunc(ctx, userID, req)
+	}
+	return &models.WishlistMergeResult{}, nil
+}
+
 type MockMaterialResolver struct {
 	GetMaterialsFunc func(ctx context.Context, userID string) (*models.MaterialsResponse, error)
 }
@@ -80,11 +216,15 @@ func (m *MockMaterialResolver) GetMaterials(ctx context.Context, userID string)
 }
 
 type MockOwnedBlueprintsService struct {
-	GetOwnedBlueprintsFunc func(ctx context.Context, userID string) (*models.OwnedBlueprints, error)
-	AddBlueprintFunc       func(ctx context.Context, userID string, req models.AddBlueprintRequest) error
-	RemoveBlueprintFunc    func(ctx context.Context, userID, uniqueName string) error
-	BulkAddBlueprintsFunc  func(ctx context.Context, userID string, req models.BulkAddBlueprintsRequest) error
-	ClearAllBlueprintsFunc func(ctx context.Context, userID string) error
+	GetOwnedBlueprintsFunc   func(ctx context.Context, userID string) (*models.OwnedBlueprints, error)
+	AddBlueprintFunc         func(ctx context.Context, userID string, req models.AddBlueprintRequest) error
+	RemoveBlueprintFunc      func(ctx context.Context, userID, uniqueName string) error
+	BulkAddBlueprintsFunc    func(ctx context.Context, userID string, req models.BulkAddBlueprintsRequest) error
+	ClearAllBlueprintsFunc   func(ctx context.Context, userID string) error
+	SetBlueprintQuantityFunc func(ctx context.Context, userID, uniqueName string, quantity int) error
+	SetComponentCountFunc    func(ctx context.Context, userID, uniqueName string, count int) error
+	RemoveComponentFunc      func(ctx context.Context, userID, uniqueName string) error
+	ClearAllComponentsFunc   func(ctx context.Context, userID string) error
 }
 
 func (m *MockOwnedBlueprintsService) GetOwnedBlueprints(ctx context.Context, userID string) (*models.OwnedBlueprints, error) {
@@ -121,3 +261,944 @@ func (m *MockOwnedBlueprintsService) ClearAllBlueprints(ctx context.Context, use
 	}
 	return nil
 }
+
+func (m *MockOwnedBlueprintsService) SetBlueprintQuantity(ctx context.Context, userID, uniqueName string, quantity int) error {
+	if m.SetBlueprintQuantityFunc != nil {
+		return m.SetBlueprintQuantityFunc(ctx, userID, uniqueName, quantity)
+	}
+	return nil
+}
+
+func (m *MockOwnedBlueprintsService) SetComponentCount(ctx context.Context, userID, uniqueName string, count int) error {
+	if m.SetComponentCountFunc != nil {
+		return m.SetComponentCountFunc(ctx, userID, uniqueName, count)
+	}
+	return nil
+}
+
+func (m *MockOwnedBlueprintsService) RemoveComponent(ctx context.Context, userID, uniqueName string) error {
+	if m.RemoveComponentFunc != nil {
+		return m.RemoveComponentFunc(ctx, userID, uniqueName)
+	}
+	return nil
+}
+
+func (m *MockOwnedBlueprintsService) ClearAllComponents(ctx context.Context, userID string) error {
+	if m.ClearAllComponentsFunc != nil {
+		return m.ClearAllComponentsFunc(ctx, userID)
+	}
+	return nil
+}
+
+type MockOwnedMaterialsService struct {
+	GetOwnedMaterialsFunc func(ctx context.Context, userID string) (*models.OwnedMaterials, error)
+	SetMaterialCountFunc  func(ctx context.Context, userID, uniqueName string, count int) error
+	SetMaterialCountsFunc func(ctx context.Context, userID string, req models.SetOwnedMaterialsRequest) error
+	RemoveMaterialFunc    func(ctx context.Context, userID, uniqueName string) error
+	ClearAllMaterialsFunc func(ctx context.Context, userID string) error
+}
+
+func (m *MockOwnedMaterialsService) GetOwnedMaterials(ctx context.Context, userID string) (*models.OwnedMaterials, error) {
+	if m.GetOwnedMaterialsFunc != nil {
+		return m.GetOwnedMaterialsFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *MockOwnedMaterialsService) SetMaterialCount(ctx context.Context, userID, uniqueName string, count int) error {
+	if m.SetMaterialCountFunc != nil {
+		return m.SetMaterialCountFunc(ctx, userID, uniqueName, count)
+	}
+	return nil
+}
+
+func (m *MockOwnedMaterialsService) SetMaterialCounts(ctx context.Context, userID string, req models.SetOwnedMaterialsRequest) error {
+	if m.SetMaterialCountsFunc != nil {
+		return m.SetMaterialCountsFunc(ctx, userID, req)
+	}
+	return nil
+}
+
+func (m *MockOwnedMaterialsService) RemoveMaterial(ctx context.Context, userID, uniqueName string) error {
+	if m.RemoveMaterialFunc != nil {
+		return m.RemoveMaterialFunc(ctx, userID, uniqueName)
+	}
+	return nil
+}
+
+func (m *MockOwnedMaterialsService) ClearAllMaterials(ctx context.Context, userID string) error {
+	if m.ClearAllMaterialsFunc != nil {
+		return m.ClearAllMaterialsFunc(ctx, userID)
+	}
+	return nil
+}
+
+type MockMasteryService struct {
+	GetProfileFunc  func(ctx context.Context, userID string) (*models.MasteryProfile, error)
+	SetStatusFunc   func(ctx context.Context, userID, uniqueName, status string) error
+	SetStatusesFunc func(ctx context.Context, userID string, req models.SetMasteryRequest) error
+	RemoveItemFunc  func(ctx context.Context, userID, uniqueName string) error
+	ClearAllFunc    func(ctx context.Context, userID string) error
+	GetSummaryFunc  func(ctx context.Context, userID string) (*models.MasterySummary, error)
+}
+
+func (m *MockMasteryService) GetProfile(ctx context.Context, userID string) (*models.MasteryProfile, error) {
+	if m.GetProfileFunc != nil {
+		return m.GetProfileFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *MockMasteryService) SetStatus(ctx context.Context, userID, uniqueName, status string) error {
+	if m.SetStatusFunc != nil {
+		return m.SetStatusFunc(ctx, userID, uniqueName, status)
+	}
+	return nil
+}
+
+func (m *MockMasteryService) SetStatuses(ctx context.Context, userID string, req models.SetMasteryRequest) error {
+	if m.SetStatusesFunc != nil {
+		return m.SetStatusesFunc(ctx, userID, req)
+	}
+	return nil
+}
+
+func (m *MockMasteryService) RemoveItem(ctx context.Context, userID, uniqueName string) error {
+	if m.RemoveItemFunc != nil {
+		return m.RemoveItemFunc(ctx, userID, uniqueName)
+	}
+	return nil
+}
+
+func (m *MockMasteryService) ClearAll(ctx context.Context, userID string) error {
+	if m.ClearAllFunc != nil {
+		return m.ClearAllFunc(ctx, userID)
+	}
+	return nil
+}
+
+func (m *MockMasteryService) GetSummary(ctx context.Context, userID string) (*models.MasterySummary, error) {
+	if m.GetSummaryFunc != nil {
+		return m.GetSummaryFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+type MockProfileImportService struct {
+	ImportFunc func(ctx context.Context, userID string, req models.ProfileImportRequest) (*models.ProfileImportResult, error)
+}
+
+func (m *MockProfileImportService) Import(ctx context.Context, userID string, req models.ProfileImportRequest) (*models.ProfileImportResult, error) {
+	if m.ImportFunc != nil {
+		return m.ImportFunc(ctx, userID, req)
+	}
+	return nil, nil
+}
+
+type MockSettingsService struct {
+	GetSettingsFunc    func(ctx context.Context, userID string) (*models.UserSettings, error)
+	UpdateSettingsFunc func(ctx context.Context, userID string, req models.UpdateSettingsRequest) (*models.UserSettings, error)
+}
+
+func (m *MockSettingsService) GetSettings(ctx context.Context, userID string) (*models.UserSettings, error) {
+	if m.GetSettingsFunc != nil {
+		return m.GetSettingsFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *MockSettingsService) UpdateSettings(ctx context.Context, userID string, req models.UpdateSettingsRequest) (*models.UserSettings, error) {
+	if m.UpdateSettingsFunc != nil {
+		return m.UpdateSettingsFunc(ctx, userID, req)
+	}
+	return nil, nil
+}
+
+type MockUserTraceService struct {
+	ListTracesFunc   func(ctx context.Context) ([]models.UserTrace, error)
+	GetTraceFunc     func(ctx context.Context, userID string) (*models.UserTrace, error)
+	EnableTraceFunc  func(ctx context.Context, userID string, req models.EnableUserTraceRequest) (*models.UserTrace, error)
+	DisableTraceFunc func(ctx context.Context, userID string) error
+}
+
+func (m *MockUserTraceService) ListTraces(ctx context.Context) ([]models.UserTrace, error) {
+	if m.ListTracesFunc != nil {
+		return m.ListTracesFunc(ctx)
+	}
+	return []models.UserTrace{}, nil
+}
+
+func (m *MockUserTraceService) GetTrace(ctx context.Context, userID string) (*models.UserTrace, error) {
+	if m.GetTraceFunc != nil {
+		return m.GetTraceFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *MockUserTraceService) EnableTrace(ctx context.Context, userID string, req models.EnableUserTraceRequest) (*models.UserTrace, error) {
+	if m.EnableTraceFunc != nil {
+		return m.EnableTraceFunc(ctx, userID, req)
+	}
+	return nil, nil
+}
+
+func (m *MockUserTraceService) DisableTrace(ctx context.Context, userID string) error {
+	if m.DisableTraceFunc != nil {
+		return m.DisableTraceFunc(ctx, userID)
+	}
+	return nil
+}
+
+type MockDataSyncService struct {
+	VersionFunc      func() string
+	NotifySyncedFunc func(ctx context.Context, version string) error
+	FlushFunc        func(ctx context.Context) error
+}
+
+func (m *MockDataSyncService) Version() string {
+	if m.VersionFunc != nil {
+		return m.VersionFunc()
+	}
+	return ""
+}
+
+func (m *MockDataSyncService) NotifySynced(ctx context.Context, version string) error {
+	if m.NotifySyncedFunc != nil {
+		return m.NotifySyncedFunc(ctx, version)
+	}
+	return nil
+}
+
+func (m *MockDataSyncService) Flush(ctx context.Context) error {
+	if m.FlushFunc != nil {
+		return m.FlushFunc(ctx)
+	}
+	return nil
+}
+
+type MockItemRefreshService struct {
+	StatusFunc func(ctx context.Context) (*models.ItemRefreshStatus, error)
+	StartFunc  func(ctx context.Context) error
+}
+
+func (m *MockItemRefreshService) Status(ctx context.Context) (*models.ItemRefreshStatus, error) {
+	if m.StatusFunc != nil {
+		return m.StatusFunc(ctx)
+	}
+	return &models.ItemRefreshStatus{}, nil
+}
+
+func (m *MockItemRefreshService) Start(ctx context.Context) error {
+	if m.StartFunc != nil {
+		return m.StartFunc(ctx)
+	}
+	return nil
+}
+
+type MockHouseholdService struct {
+	GetHouseholdFunc   func(ctx context.Context, userID string) (*models.Household, error)
+	RequestManagerFunc func(ctx context.Context, memberID string, req models.RequestManagerRequest) (*models.HouseholdLink, error)
+	LeaveManagerFunc   func(ctx context.Context, memberID string) error
+	AcceptMemberFunc   func(ctx context.Context, managerID, memberID string) (*models.HouseholdLink, error)
+	UpdateMemberFunc   func(ctx context.Context, managerID, memberID string, req models.UpdateHouseholdMemberRequest) (*models.HouseholdLink, error)
+	RemoveMemberFunc   func(ctx context.Context, managerID, memberID string) error
+	ListApprovalsFunc  func(ctx context.Context, userID string) (*models.HouseholdApprovals, error)
+	ApproveFunc        func(ctx context.Context, managerID, changeID string) (*models.PendingChange, error)
+	RejectFunc         func(ctx context.Context, managerID, changeID string) (*models.PendingChange, error)
+}
+
+func (m *MockHouseholdService) GetHousehold(ctx context.Context, userID string) (*models.Household, error) {
+	if m.GetHouseholdFunc != nil {
+		return m.GetHouseholdFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *MockHouseholdService) RequestManager(ctx context.Context, memberID string, req models.RequestManagerRequest) (*models.HouseholdLink, error) {
+	if m.RequestManagerFunc != nil {
+		return m.RequestManagerFunc(ctx, memberID, req)
+	}
+	return nil, nil
+}
+
+func (m *MockHouseholdService) LeaveManager(ctx context.Context, memberID string) error {
+	if m.LeaveManagerFunc != nil {
+		return m.LeaveManagerFunc(ctx, memberID)
+	}
+	return nil
+}
+
+func (m *MockHouseholdService) AcceptMember(ctx context.Context, managerID, memberID string) (*models.HouseholdLink, error) {
+	if m.AcceptMemberFunc != nil {
+		return m.AcceptMemberFunc(ctx, managerID, memberID)
+	}
+	return nil, nil
+}
+
+func (m *MockHouseholdService) UpdateMember(ctx context.Context, managerID, memberID string, req models.UpdateHouseholdMemberRequest) (*models.HouseholdLink, error) {
+	if m.UpdateMemberFunc != nil {
+		return m.UpdateMemberFunc(ctx, managerID, memberID, req)
+	}
+	return nil, nil
+}
+
+func (m *MockHouseholdService) RemoveMember(ctx context.Context, managerID, memberID string) error {
+	if m.RemoveMemberFunc != nil {
+		return m.RemoveMemberFunc(ctx, managerID, memberID)
+	}
+	return nil
+}
+
+func (m *MockHouseholdService) ListApprovals(ctx context.Context, userID string) (*models.HouseholdApprovals, error) {
+	if m.ListApprovalsFunc != nil {
+		return m.ListApprovalsFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *MockHouseholdService) Approve(ctx context.Context, managerID, changeID string) (*models.PendingChange, error) {
+	if m.ApproveFunc != nil {
+		return m.ApproveFunc(ctx, managerID, changeID)
+	}
+	return nil, nil
+}
+
+func (m *MockHouseholdService) Reject(ctx context.Context, managerID, changeID string) (*models.PendingChange, error) {
+	if m.RejectFunc != nil {
+		return m.RejectFunc(ctx, managerID, changeID)
+	}
+	return nil, nil
+}
+
+type MockPublicWishlistService struct {
+	ListFunc         func(ctx context.Context) ([]models.PublicWishlist, error)
+	GetFunc          func(ctx context.Context, id string) (*models.PublicWishlist, error)
+	GetMaterialsFunc func(ctx context.Context, id string) (*models.MaterialsResponse, error)
+}
+
+func (m *MockPublicWishlistService) List(ctx context.Context) ([]models.PublicWishlist, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx)
+	}
+	return []models.PublicWishlist{}, nil
+}
+
+func (m *MockPublicWishlistService) Get(ctx context.Context, id string) (*models.PublicWishlist, error) {
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *MockPublicWishlistService) GetMaterials(ctx context.Context, id string) (*models.MaterialsResponse, error) {
+	if m.GetMaterialsFunc != nil {
+		return m.GetMaterialsFunc(ctx, id)
+	}
+	return nil, nil
+}
+
+type MockGiftClaimService struct {
+	GetSharedWishlistFunc func(ctx context.Context, viewerID, ownerID string) (*models.SharedWishlist, error)
+	ClaimFunc             func(ctx context.Context, claimerID, ownerID string, req models.ClaimGiftRequest) (*models.GiftClaim, error)
+	RevokeClaimFunc       func(ctx context.Context, callerID, ownerID, uniqueName string) error
+	ListClaimsFunc        func(ctx context.Context, claimerID string) ([]models.GiftClaim, error)
+}
+
+func (m *MockGiftClaimService) GetSharedWishlist(ctx context.Context, viewerID, ownerID string) (*models.SharedWishlist, error) {
+	if m.GetSharedWishlistFunc != nil {
+		return m.GetSharedWishlistFunc(ctx, viewerID, ownerID)
+	}
+	return &models.SharedWishlist{OwnerID: ownerID, Items: []models.SharedWishlistItem{}}, nil
+}
+
+func (m *MockGiftClaimService) Claim(ctx context.Context, claimerID, ownerID string, req models.ClaimGiftRequest) (*models.GiftClaim, error) {
+	if m.ClaimFunc != nil {
+		return m.ClaimFunc(ctx, claimerID, ownerID, req)
+	}
+	return &models.GiftClaim{OwnerID: ownerID, UniqueName: req.UniqueName, ClaimerID: claimerID, Anonymous: req.Anonymous}, nil
+}
+
+func (m *MockGiftClaimService) RevokeClaim(ctx context.Context, callerID, ownerID, uniqueName string) error {
+	if m.RevokeClaimFunc != nil {
+		return m.RevokeClaimFunc(ctx, callerID, ownerID, uniqueName)
+	}
+	return nil
+}
+
+func (m *MockGiftClaimService) ListClaims(ctx context.Context, claimerID string) ([]models.GiftClaim, error) {
+	if m.ListClaimsFunc != nil {
+		return m.ListClaimsFunc(ctx, claimerID)
+	}
+	return []models.GiftClaim{}, nil
+}
+
+type MockShareLinkService struct {
+	CreateLinkFunc   func(ctx context.Context, userID string, req models.CreateShareLinkRequest) (*models.ShareLink, error)
+	ListLinksFunc    func(ctx context.Context, userID string) ([]models.ShareLink, error)
+	DeleteLinkFunc   func(ctx context.Context, userID, linkID string) error
+	RevokeLinkFunc   func(ctx context.Context, token string) (*models.ShareLink, error)
+	GetViewFunc      func(ctx context.Context, token string) (*models.ShareLinkView, error)
+	GetMaterialsFunc func(ctx context.Context, token string) (*models.MaterialsResponse, error)
+}
+
+func (m *MockShareLinkService) CreateLink(ctx context.Context, userID string, req models.CreateShareLinkRequest) (*models.ShareLink, error) {
+	if m.CreateLinkFunc != nil {
+		return m.CreateLinkFunc(ctx, userID, req)
+	}
+	return &models.ShareLink{UserID: userID, Label: req.Label, Permissions: req.Permissions}, nil
+}
+
+func (m *MockShareLinkService) ListLinks(ctx context.Context, userID string) ([]models.ShareLink, error) {
+	if m.ListLinksFunc != nil {
+		return m.ListLinksFunc(ctx, userID)
+	}
+	return []models.ShareLink{}, nil
+}
+
+func (m *MockShareLinkService) DeleteLink(ctx context.Context, userID, linkID string) error {
+	if m.DeleteLinkFunc != nil {
+		return m.DeleteLinkFunc(ctx, userID, linkID)
+	}
+	return nil
+}
+
+func (m *MockShareLinkService) RevokeLink(ctx context.Context, token string) (*models.ShareLink, error) {
+	if m.RevokeLinkFunc != nil {
+		return m.RevokeLinkFunc(ctx, token)
+	}
+	return &models.ShareLink{Token: token}, nil
+}
+
+func (m *MockShareLinkService) GetView(ctx context.Context, token string) (*models.ShareLinkView, error) {
+	if m.GetViewFunc != nil {
+		return m.GetViewFunc(ctx, token)
+	}
+	return &models.ShareLinkView{Items: []models.ShareLinkViewItem{}}, nil
+}
+
+func (m *MockShareLinkService) GetMaterials(ctx context.Context, token string) (*models.MaterialsResponse, error) {
+	if m.GetMaterialsFunc != nil {
+		return m.GetMaterialsFunc(ctx, token)
+	}
+	return &models.MaterialsResponse{Materials: []models.MaterialRequirement{}}, nil
+}
+
+type MockModerationService struct {
+	ReportShareLinkFunc func(ctx context.Context, token string, req models.ReportShareLinkRequest) (*models.ShareLinkReport, error)
+	ListReportsFunc     func(ctx context.Context, status string) ([]models.ShareLinkReport, error)
+	DismissReportFunc   func(ctx context.Context, token string) error
+	RevokeShareLinkFunc func(ctx context.Context, token string) (*models.ShareLink, error)
+}
+
+func (m *MockModerationService) ReportShareLink(ctx context.Context, token string, req models.ReportShareLinkRequest) (*models.ShareLinkReport, error) {
+	if m.ReportShareLinkFunc != nil {
+		return m.ReportShareLinkFunc(ctx, token, req)
+	}
+	return &models.ShareLinkReport{Token: token, Reasons: []string{req.Reason}, Count: 1, Status: models.ReportStatusOpen}, nil
+}
+
+func (m *MockModerationService) ListReports(ctx context.Context, status string) ([]models.ShareLinkReport, error) {
+	if m.ListReportsFunc != nil {
+		return m.ListReportsFunc(ctx, status)
+	}
+	return []models.ShareLinkReport{}, nil
+}
+
+func (m *MockModerationService) DismissReport(ctx context.Context, token string) error {
+	if m.DismissReportFunc != nil {
+		return m.DismissReportFunc(ctx, token)
+	}
+	return nil
+}
+
+func (m *MockModerationService) RevokeShareLink(ctx context.Context, token string) (*models.ShareLink, error) {
+	if m.RevokeShareLinkFunc != nil {
+		return m.RevokeShareLinkFunc(ctx, token)
+	}
+	return &models.ShareLink{Token: token}, nil
+}
+
+type MockImportAliasService struct {
+	ListFunc   func(ctx context.Context) ([]models.ImportAlias, error)
+	SetFunc    func(ctx context.Context, word, expansion string) (*models.ImportAlias, error)
+	DeleteFunc func(ctx context.Context, word string) error
+}
+
+func (m *MockImportAliasService) List(ctx context.Context) ([]models.ImportAlias, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx)
+	}
+	return []models.ImportAlias{}, nil
+}
+
+func (m *MockImportAliasService) Set(ctx context.Context, word, expansion string) (*models.ImportAlias, error) {
+	if m.SetFunc != nil {
+		return m.SetFunc(ctx, word, expansion)
+	}
+	return &models.ImportAlias{Word: word, Expansion: expansion}, nil
+}
+
+func (m *MockImportAliasService) Delete(ctx context.Context, word string) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, word)
+	}
+	return nil
+}
+
+type MockCustomItemService struct {
+	ListFunc   func(ctx context.Context, userID string) ([]models.CustomItem, error)
+	SearchFunc func(ctx context.Context, userID string, params models.SearchParams) (*models.ItemSearchPage, error)
+	CreateFunc func(ctx context.Context, userID string, req models.CustomItemRequest) (*models.CustomItem, error)
+	UpdateFunc func(ctx context.Context, userID, uniqueName string, req models.CustomItemRequest) (*models.CustomItem, error)
+	DeleteFunc func(ctx context.Context, userID, uniqueName string) error
+}
+
+func (m *MockCustomItemService) List(ctx context.Context, userID string) ([]models.CustomItem, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx, userID)
+	}
+	return []models.CustomItem{}, nil
+}
+
+func (m *MockCustomItemService) Search(ctx context.Context, userID string, params models.SearchParams) (*models.ItemSearchPage, error) {
+	if m.SearchFunc != nil {
+		return m.SearchFunc(ctx, userID, params)
+	}
+	return &models.ItemSearchPage{Items: []models.ItemSearchResult{}}, nil
+}
+
+func (m *MockCustomItemService) Create(ctx context.Context, userID string, req models.CustomItemRequest) (*models.CustomItem, error) {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, userID, req)
+	}
+	return &models.CustomItem{UserID: userID, UniqueName: models.CustomItemPrefix + "new", Name: req.Name, Components: []models.Component{}}, nil
+}
+
+func (m *MockCustomItemService) Update(ctx context.Context, userID, uniqueName string, req models.CustomItemRequest) (*models.CustomItem, error) {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, userID, uniqueName, req)
+	}
+	return &models.CustomItem{UserID: userID, UniqueName: uniqueName, Name: req.Name, Components: []models.Component{}}, nil
+}
+
+func (m *MockCustomItemService) Delete(ctx context.Context, userID, uniqueName string) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, userID, uniqueName)
+	}
+	return nil
+}
+
+type MockFoundryService struct {
+	GetFoundryFunc  func(ctx context.Context, userID string) (*models.Foundry, error)
+	StartBuildFunc  func(ctx context.Context, userID string, req models.FoundryBuildRequest) (*models.FoundryBuildStatus, error)
+	RemoveBuildFunc func(ctx context.Context, userID, id string) error
+}
+
+func (m *MockFoundryService) GetFoundry(ctx context.Context, userID string) (*models.Foundry, error) {
+	if m.GetFoundryFunc != nil {
+		return m.GetFoundryFunc(ctx, userID)
+	}
+	return &models.Foundry{Builds: []models.FoundryBuildStatus{}}, nil
+}
+
+func (m *MockFoundryService) StartBuild(ctx context.Context, userID string, req models.FoundryBuildRequest) (*models.FoundryBuildStatus, error) {
+	if m.StartBuildFunc != nil {
+		return m.StartBuildFunc(ctx, userID, req)
+	}
+	return &models.FoundryBuildStatus{FoundryBuild: models.FoundryBuild{UserID: userID, UniqueName: req.UniqueName}}, nil
+}
+
+func (m *MockFoundryService) RemoveBuild(ctx context.Context, userID, id string) error {
+	if m.RemoveBuildFunc != nil {
+		return m.RemoveBuildFunc(ctx, userID, id)
+	}
+	return nil
+}
+
+type MockWishlistTemplateService struct {
+	ListTemplatesFunc  func(ctx context.Context, userID string) ([]models.WishlistTemplate, error)
+	CreateTemplateFunc func(ctx context.Context, userID string, req models.WishlistTemplateRequest) (*models.WishlistTemplate, error)
+	DeleteTemplateFunc func(ctx context.Context, userID, id string) error
+	ApplyTemplateFunc  func(ctx context.Context, userID, id string, variables map[string]int) (*models.ImportConfirmResult, error)
+}
+
+func (m *MockWishlistTemplateService) ListTemplates(ctx context.Context, userID string) ([]models.WishlistTemplate, error) {
+	if m.ListTemplatesFunc != nil {
+		return m.ListTemplatesFunc(ctx, userID)
+	}
+	return []models.WishlistTemplate{}, nil
+}
+
+func (m *MockWishlistTemplateService) CreateTemplate(ctx context.Context, userID string, req models.WishlistTemplateRequest) (*models.WishlistTemplate, error) {
+	if m.CreateTemplateFunc != nil {
+		return m.CreateTemplateFunc(ctx, userID, req)
+	}
+	return &models.WishlistTemplate{UserID: userID, Name: req.Name, Variables: req.Variables, Items: req.Items}, nil
+}
+
+func (m *MockWishlistTemplateService) DeleteTemplate(ctx context.Context, userID, id string) error {
+	if m.DeleteTemplateFunc != nil {
+		return m.DeleteTemplateFunc(ctx, userID, id)
+	}
+	return nil
+}
+
+func (m *MockWishlistTemplateService) ApplyTemplate(ctx context.Context, userID, id string, variables map[string]int) (*models.ImportConfirmResult, error) {
+	if m.ApplyTemplateFunc != nil {
+		return m.ApplyTemplateFunc(ctx, userID, id, variables)
+	}
+	return &models.ImportConfirmResult{Results: []models.ImportItemResult{}}, nil
+}
+
+type MockNotificationService struct {
+	PushKeyFunc        func() string
+	ListChannelsFunc   func(ctx context.Context, userID string) ([]models.NotificationChannel, error)
+	CreateChannelFunc  func(ctx context.Context, userID string, req models.NotificationChannelRequest) (*models.NotificationChannel, error)
+	DeleteChannelFunc  func(ctx context.Context, userID, id string) error
+	ListDeliveriesFunc func(ctx context.Context, userID string, limit int) ([]models.NotificationDelivery, error)
+}
+
+func (m *MockNotificationService) PushKey() string {
+	if m.PushKeyFunc != nil {
+		return m.PushKeyFunc()
+	}
+	return ""
+}
+
+func (m *MockNotificationService) ListChannels(ctx context.Context, userID string) ([]models.NotificationChannel, error) {
+	if m.ListChannelsFunc != nil {
+		return m.ListChannelsFunc(ctx, userID)
+	}
+	return []models.NotificationChannel{}, nil
+}
+
+func (m *MockNotificationService) CreateChannel(ctx context.Context, userID string, req models.NotificationChannelRequest) (*models.NotificationChannel, error) {
+	if m.CreateChannelFunc != nil {
+		return m.CreateChannelFunc(ctx, userID, req)
+	}
+	return &models.NotificationChannel{UserID: userID, Type: req.Type, URL: req.URL, Events: req.Events}, nil
+}
+
+func (m *MockNotificationService) DeleteChannel(ctx context.Context, userID, id string) error {
+	if m.DeleteChannelFunc != nil {
+		return m.DeleteChannelFunc(ctx, userID, id)
+	}
+	return nil
+}
+
+func (m *MockNotificationService) ListDeliveries(ctx context.Context, userID string, limit int) ([]models.NotificationDelivery, error) {
+	if m.ListDeliveriesFunc != nil {
+		return m.ListDeliveriesFunc(ctx, userID, limit)
+	}
+	return []models.NotificationDelivery{}, nil
+}
+
+type MockIntegrationService struct {
+	ListIntegrationsFunc  func(ctx context.Context) ([]models.Integration, error)
+	CreateIntegrationFunc func(ctx context.Context, req models.IntegrationRequest) (*models.Integration, error)
+	DeleteIntegrationFunc func(ctx context.Context, id string) error
+	ListDeliveriesFunc    func(ctx context.Context, id string, limit int) ([]models.IntegrationDelivery, error)
+}
+
+func (m *MockIntegrationService) ListIntegrations(ctx context.Context) ([]models.Integration, error) {
+	if m.ListIntegrationsFunc != nil {
+		return m.ListIntegrationsFunc(ctx)
+	}
+	return []models.Integration{}, nil
+}
+
+func (m *MockIntegrationService) CreateIntegration(ctx context.Context, req models.IntegrationRequest) (*models.Integration, error) {
+	if m.CreateIntegrationFunc != nil {
+		return m.CreateIntegrationFunc(ctx, req)
+	}
+	return &models.Integration{Name: req.Name, URL: req.URL, Events: req.Events}, nil
+}
+
+func (m *MockIntegrationService) DeleteIntegration(ctx context.Context, id string) error {
+	if m.DeleteIntegrationFunc != nil {
+		return m.DeleteIntegrationFunc(ctx, id)
+	}
+	return nil
+}
+
+func (m *MockIntegrationService) ListDeliveries(ctx context.Context, id string, limit int) ([]models.IntegrationDelivery, error) {
+	if m.ListDeliveriesFunc != nil {
+		return m.ListDeliveriesFunc(ctx, id, limit)
+	}
+	return []models.IntegrationDelivery{}, nil
+}
+
+type MockFarmingPlanner struct {
+	GetFarmingPlanFunc func(ctx context.Context, userID string, limit int) (*models.FarmingPlan, error)
+}
+
+func (m *MockFarmingPlanner) GetFarmingPlan(ctx context.Context, userID string, limit int) (*models.FarmingPlan, error) {
+	if m.GetFarmingPlanFunc != nil {
+		return m.GetFarmingPlanFunc(ctx, userID, limit)
+	}
+	return &models.FarmingPlan{Locations: []models.FarmingLocation{}, Unlocated: []models.MaterialRequirement{}}, nil
+}
+
+type MockRelicResolver struct {
+	GetRelicRequirementsFunc func(ctx context.Context, userID string) (*models.RelicRequirements, error)
+}
+
+func (m *MockRelicResolver) GetRelicRequirements(ctx context.Context, userID string) (*models.RelicRequirements, error) {
+	if m.GetRelicRequirementsFunc != nil {
+		return m.GetRelicRequirementsFunc(ctx, userID)
+	}
+	return &models.RelicRequirements{Relics: []models.RelicRequirement{}, Unavailable: []models.PrimePart{}}, nil
+}
+
+type MockResearchCalculator struct {
+	CalculateFunc func(ctx context.Context, req models.ResearchCostRequest) (*models.ResearchCost, error)
+}
+
+func (m *MockResearchCalculator) Calculate(ctx context.Context, req models.ResearchCostRequest) (*models.ResearchCost, error) {
+	if m.CalculateFunc != nil {
+		return m.CalculateFunc(ctx, req)
+	}
+	return &models.ResearchCost{Tier: models.DefaultClanTier, Multiplier: 1}, nil
+}
+
+type MockQuantitySuggester struct {
+	SuggestQuantityFunc func(ctx context.Context, userID, uniqueName string) (*models.QuantitySuggestion, error)
+}
+
+func (m *MockQuantitySuggester) SuggestQuantity(ctx context.Context, userID, uniqueName string) (*models.QuantitySuggestion, error) {
+	if m.SuggestQuantityFunc != nil {
+		return m.SuggestQuantityFunc(ctx, userID, uniqueName)
+	}
+	return nil, nil
+}
+
+type MockWorldStateService struct {
+	CurrentFunc func() *models.WorldState
+}
+
+func (m *MockWorldStateService) Current() *models.WorldState {
+	if m.CurrentFunc != nil {
+		return m.CurrentFunc()
+	}
+	return nil
+}
+
+type MockDashboardService struct {
+	GetDashboardFunc func(ctx context.Context, userID string) (*models.Dashboard, error)
+}
+
+func (m *MockDashboardService) GetDashboard(ctx context.Context, userID string) (*models.Dashboard, error) {
+	if m.GetDashboardFunc != nil {
+		return m.GetDashboardFunc(ctx, userID)
+	}
+	return &models.Dashboard{}, nil
+}
+
+type MockOpportunityFinder struct {
+	GetOpportunitiesFunc func(ctx context.Context, userID string) (*models.Opportunities, error)
+}
+
+func (m *MockOpportunityFinder) GetOpportunities(ctx context.Context, userID string) (*models.Opportunities, error) {
+	if m.GetOpportunitiesFunc != nil {
+		return m.GetOpportunitiesFunc(ctx, userID)
+	}
+	return &models.Opportunities{Opportunities: []models.Opportunity{}}, nil
+}
+
+type MockWorkspaceService struct {
+	ListFunc                    func(ctx context.Context, userID string) ([]models.Workspace, error)
+	GetFunc                     func(ctx context.Context, userID, id string) (*models.Workspace, error)
+	CreateFunc                  func(ctx context.Context, userID string, req models.WorkspaceRequest) (*models.Workspace, error)
+	UpdateFunc                  func(ctx context.Context, userID, id string, req models.WorkspaceRequest) (*models.Workspace, error)
+	DeleteFunc                  func(ctx context.Context, userID, id string) error
+	AddMemberFunc               func(ctx context.Context, userID, id string, req models.WorkspaceMemberRequest) (*models.Workspace, error)
+	SetMemberRoleFunc           func(ctx context.Context, userID, id, memberID, role string) (*models.Workspace, error)
+	RemoveMemberFunc            func(ctx context.Context, userID, id, memberID string) (*models.Workspace, error)
+	AddItemFunc                 func(ctx context.Context, userID, id string, req models.WorkspaceItemRequest) (*models.Workspace, error)
+	UpdateItemQuantityFunc      func(ctx context.Context, userID, id, uniqueName string, quantity int) (*models.Workspace, error)
+	RemoveItemFunc              func(ctx context.Context, userID, id, uniqueName string) (*models.Workspace, error)
+	SetContributionFunc         func(ctx context.Context, userID, id, uniqueName string, quantity int) (*models.Workspace, error)
+	GetMaterialsFunc            func(ctx context.Context, userID, id string) (*models.WorkspaceMaterials, error)
+	LogMaterialContributionFunc func(ctx context.Context, userID, id string, req models.WorkspaceMaterialContributionRequest) (*models.WorkspaceContributionEvent, error)
+	ListActivityFunc            func(ctx context.Context, userID, id string, limit int) ([]models.WorkspaceContributionEvent, error)
+}
+
+// mockWorkspace is the workspace the mock returns by default: the caller as
+// its only admin, with no items.
+func mockWorkspace(userID string) *models.Workspace {
+	return &models.Workspace{
+		Name:    "Workspace",
+		Members: []models.WorkspaceMember{{UserID: userID, Role: models.WorkspaceRoleAdmin}},
+		Items:   []models.WorkspaceItem{},
+	}
+}
+
+func (m *MockWorkspaceService) List(ctx context.Context, userID string) ([]models.Workspace, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx, userID)
+	}
+	return []models.Workspace{}, nil
+}
+
+func (m *MockWorkspaceService) Get(ctx context.Context, userID, id string) (*models.Workspace, error) {
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx, userID, id)
+	}
+	return mockWorkspace(userID), nil
+}
+
+func (m *MockWorkspaceService) Create(ctx context.Context, userID string, req models.WorkspaceRequest) (*models.Workspace, error) {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, userID, req)
+	}
+	return mockWorkspace(userID), nil
+}
+
+func (m *MockWorkspaceService) Update(ctx context.Context, userID, id string, req models.WorkspaceRequest) (*models.Workspace, error) {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, userID, id, req)
+	}
+	return mockWorkspace(userID), nil
+}
+
+func (m *MockWorkspaceService) Delete(ctx context.Context, userID, id string) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, userID, id)
+	}
+	return nil
+}
+
+func (m *MockWorkspaceService) AddMember(ctx context.Context, userID, id string, req models.WorkspaceMemberRequest) (*models.Workspace, error) {
+	if m.AddMemberFunc != nil {
+		return m.AddMemberFunc(ctx, userID, id, req)
+	}
+	return mockWorkspace(userID), nil
+}
+
+func (m *MockWorkspaceService) SetMemberRole(ctx context.Context, userID, id, memberID, role string) (*models.Workspace, error) {
+	if m.SetMemberRoleFunc != nil {
+		return m.SetMemberRoleFunc(ctx, userID, id, memberID, role)
+	}
+	return mockWorkspace(userID), nil
+}
+
+func (m *MockWorkspaceService) RemoveMember(ctx context.Context, userID, id, memberID string) (*models.Workspace, error) {
+	if m.RemoveMemberFunc != nil {
+		return m.RemoveMemberFunc(ctx, userID, id, memberID)
+	}
+	return mockWorkspace(userID), nil
+}
+
+func (m *MockWorkspaceService) AddItem(ctx context.Context, userID, id string, req models.WorkspaceItemRequest) (*models.Workspace, error) {
+	if m.AddItemFunc != nil {
+		return m.AddItemFunc(ctx, userID, id, req)
+	}
+	return mockWorkspace(userID), nil
+}
+
+func (m *MockWorkspaceService) UpdateItemQuantity(ctx context.Context, userID, id, uniqueName string, quantity int) (*models.Workspace, error) {
+	if m.UpdateItemQuantityFunc != nil {
+		return m.UpdateItemQuantityFunc(ctx, userID, id, uniqueName, quantity)
+	}
+	return mockWorkspace(userID), nil
+}
+
+func (m *MockWorkspaceService) RemoveItem(ctx context.Context, userID, id, uniqueName string) (*models.Workspace, error) {
+	if m.RemoveItemFunc != nil {
+		return m.RemoveItemFunc(ctx, userID, id, uniqueName)
+	}
+	return mockWorkspace(userID), nil
+}
+
+func (m *MockWorkspaceService) SetContribution(ctx context.Context, userID, id, uniqueName string, quantity int) (*models.Workspace, error) {
+	if m.SetContributionFunc != nil {
+		return m.SetContributionFunc(ctx, userID, id, uniqueName, quantity)
+	}
+	return mockWorkspace(userID), nil
+}
+
+func (m *MockWorkspaceService) GetMaterials(ctx context.Context, userID, id string) (*models.WorkspaceMaterials, error) {
+	if m.GetMaterialsFunc != nil {
+		return m.GetMaterialsFunc(ctx, userID, id)
+	}
+	return &models.WorkspaceMaterials{Materials: []models.WorkspaceMaterial{}, Members: []models.MemberContribution{}}, nil
+}
+
+func (m *MockWorkspaceService) LogMaterialContribution(ctx context.Context, userID, id string, req models.WorkspaceMaterialContributionRequest) (*models.WorkspaceContributionEvent, error) {
+	if m.LogMaterialContributionFunc != nil {
+		return m.LogMaterialContributionFunc(ctx, userID, id, req)
+	}
+	return &models.WorkspaceContributionEvent{
+		UserID:     userID,
+		Kind:       models.ContributionKindMaterial,
+		UniqueName: req.UniqueName,
+		Quantity:   req.Quantity,
+	}, nil
+}
+
+func (m *MockWorkspaceService) ListActivity(ctx context.Context, userID, id string, limit int) ([]models.WorkspaceContributionEvent, error) {
+	if m.ListActivityFunc != nil {
+		return m.ListActivityFunc(ctx, userID, id, limit)
+	}
+	return []models.WorkspaceContributionEvent{}, nil
+}
+
+type MockUsageService struct {
+	GetUsageFunc func(ctx context.Context, userID string) (*models.UserUsage, error)
+	WarningsFunc func(ctx context.Context, userID string, resources ...string) ([]models.ResourceUsage, error)
+}
+
+func (m *MockUsageService) GetUsage(ctx context.Context, userID string) (*models.UserUsage, error) {
+	if m.GetUsageFunc != nil {
+		return m.GetUsageFunc(ctx, userID)
+	}
+	return &models.UserUsage{UserID: userID, Resources: []models.ResourceUsage{}}, nil
+}
+
+func (m *MockUsageService) Warnings(ctx context.Context, userID string, resources ...string) ([]models.ResourceUsage, error) {
+	if m.WarningsFunc != nil {
+		return m.WarningsFunc(ctx, userID, resources...)
+	}
+	return nil, nil
+}
+
+type MockWishlistVersionService struct {
+	WishlistVersionFunc  func(ctx context.Context, userID string) (string, error)
+	MaterialsVersionFunc func(ctx context.Context, userID string) (string, error)
+}
+
+func (m *MockWishlistVersionService) WishlistVersion(ctx context.Context, userID string) (string, error) {
+	if m.WishlistVersionFunc != nil {
+		return m.WishlistVersionFunc(ctx, userID)
+	}
+	return "", nil
+}
+
+func (m *MockWishlistVersionService) MaterialsVersion(ctx context.Context, userID string) (string, error) {
+	if m.MaterialsVersionFunc != nil {
+		return m.MaterialsVersionFunc(ctx, userID)
+	}
+	return "", nil
+}
+
+type MockActivityService struct {
+	ListActivityFunc func(ctx context.Context, userID string, offset, limit int) (*models.ActivityPage, error)
+}
+
+func (m *MockActivityService) ListActivity(ctx context.Context, userID string, offset, limit int) (*models.ActivityPage, error) {
+	if m.ListActivityFunc != nil {
+		return m.ListActivityFunc(ctx, userID, offset, limit)
+	}
+	return &models.ActivityPage{Entries: []models.ActivityEntry{}, Limit: limit, Offset: offset}, nil
+}
+
+type MockOnboardingService struct {
+	OnboardFunc func(ctx context.Context, userID string) (bool, error)
+}
+
+func (m *MockOnboardingService) Onboard(ctx context.Context, userID string) (bool, error) {
+	if m.OnboardFunc != nil {
+		return m.OnboardFunc(ctx, userID)
+	}
+	return false, nil
+}
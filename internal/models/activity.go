@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Activity targets, what an activity entry changed.
+const (
+	ActivityTargetWishlist   = "wishlist"
+	ActivityTargetBlueprints = "blueprints"
+	ActivityTargetComponents = "components"
+)
+
+// Activity actions.
+const (
+	ActivityAdd    = "add"
+	ActivityRemove = "remove"
+	ActivityUpdate = "update"
+	ActivityClear  = "clear"
+)
+
+// ActivityRetentionDays is how long activity entries are kept.
+const ActivityRetentionDays = 90
+
+// ActivityEntry records one change a user made to their wishlist or owned
+// blueprints. Payload is the JSON describing the change, such as the item
+// and its new quantity; RequestID is the request that made it, when known.
+type ActivityEntry struct {
+	ID        primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	UserID    string             `json:"userId" bson:"userId"`
+	Target    string             `json:"target" bson:"target"`
+	Action    string             `json:"action" bson:"action"`
+	Payload   string             `json:"payload" bson:"payload"`
+	RequestID string             `json:"requestId,omitempty" bson:"requestId,omitempty"`
+	CreatedAt time.Time          `json:"createdAt" bson:"createdAt"`
+}
+
+// ActivityPage is one page of a user's activity, newest first, and how many
+// entries there are in all.
+type ActivityPage struct {
+	Entries []ActivityEntry `json:"entries"`
+	Total   int             `json:"total"`
+	Limit   int             `json:"limit"`
+	Offset  int             `json:"offset"`
+}
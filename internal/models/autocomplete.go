@@ -0,0 +1,12 @@
+package models
+
+// MaxAutocompleteSuggestions is the most suggestions autocomplete returns.
+const MaxAutocompleteSuggestions = 10
+
+// ItemSuggestion is an item name offered while the user is typing.
+type ItemSuggestion struct {
+	UniqueName string
+	Name       string
+	Category   string
+	ImageName  string
+}
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// CustomItemCategory is the category custom items report in searches
+	// and wishlist summaries.
+	CustomItemCategory = "Custom"
+	// CustomItemPrefix starts the uniqueName of every custom item, so they
+	// never collide with game items.
+	CustomItemPrefix = "/Custom/"
+	// MaxCustomItemsPerUser bounds how many custom items a user can define.
+	MaxCustomItemsPerUser = 100
+	// MaxCustomItemComponents bounds the components of one custom item.
+	MaxCustomItemComponents = 50
+)
+
+// CustomItem is a private item a user defines for something the game data
+// does not have, such as a dojo decoration project or an out-of-game goal.
+// Its components may be game items, other custom items of the same user or
+// free-form materials; the material resolver treats it like a game item.
+type CustomItem struct {
+	ID          primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	UserID      string             `json:"userId" bson:"userId"`
+	UniqueName  string             `json:"uniqueName" bson:"uniqueName"`
+	Name        string             `json:"name" bson:"name"`
+	Description string             `json:"description" bson:"description"`
+	BuildPrice  int                `json:"buildPrice" bson:"buildPrice"`
+	Components  []Component        `json:"components" bson:"components"`
+	CreatedAt   time.Time          `json:"createdAt" bson:"createdAt"`
+	UpdatedAt   time.Time          `json:"updatedAt" bson:"updatedAt"`
+}
+
+// CustomItemUniqueName returns the uniqueName of the custom item with id.
+func CustomItemUniqueName(id primitive.ObjectID) string {
+	return CustomItemPrefix + id.Hex()
+}
+
+// IsCustomItemName reports whether uniqueName belongs to a custom item.
+func IsCustomItemName(uniqueName string) bool {
+	return strings.HasPrefix(uniqueName, CustomItemPrefix)
+}
+
+// ToItem returns the custom item as an Item in the custom category, sharing
+// no slices with c.
+func (c *CustomItem) ToItem() *Item {
+	return &Item{
+		UniqueName:  c.UniqueName,
+		Name:        c.Name,
+		Description: c.Description,
+		Category:    CustomItemCategory,
+		BuildPrice:  c.BuildPrice,
+		Components:  append([]Component(nil), c.Components...),
+	}
+}
+
+// CustomItemRequest creates or replaces a custom item.
+type CustomItemRequest struct {
+	Name        string
+	Description string
+	BuildPrice  int
+	Components  []CustomItemComponentRequest
+}
+
+// CustomItemComponentRequest is one component of a custom item. Name may be
+// left empty for components that are game or custom items; free-form
+// materials need one.
+type CustomItemComponentRequest struct {
+	UniqueName string
+	Name       string
+	ItemCount  int
+}
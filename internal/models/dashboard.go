@@ -0,0 +1,49 @@
+package models
+
+// Sizes of the dashboard's lists.
+const (
+	DashboardTopItems           = 5
+	DashboardMaterialHighlights = 5
+)
+
+// Sections of the dashboard reported as degraded when their source fails.
+const (
+	SectionDashboardMaterials = "materials"
+	SectionDashboardFoundry   = "foundry"
+	SectionDashboardAlerts    = "alerts"
+)
+
+// WishlistSummary counts a wishlist's items: Quantity totals their
+// quantities, Completed counts those with every component done and
+// Completion is the average completion percentage.
+type WishlistSummary struct {
+	Items      int
+	Quantity   int
+	Completed  int
+	Completion int
+}
+
+// MaterialHighlights are the outstanding materials needing the most, out of
+// Outstanding in all, with the wishlist's totals. Truncated and
+// TruncatedReason carry over from the materials resolution.
+type MaterialHighlights struct {
+	Highlights      []MaterialRequirement
+	Outstanding     int
+	TotalCredits    int
+	RushPlatinum    int
+	Truncated       bool
+	TruncatedReason string
+}
+
+// Dashboard is a user's home screen: a wishlist summary, the unfinished items
+// closest to completion, material highlights, the foundry and the active
+// alerts rewarding what the wishlist needs. Sources other than the wishlist
+// that fail are listed in Degradation and left empty.
+type Dashboard struct {
+	Wishlist  WishlistSummary
+	TopItems  []ExpandedWishlistItem
+	Materials MaterialHighlights
+	Foundry   Foundry
+	Alerts    []Opportunity
+	Degradation
+}
@@ -0,0 +1,40 @@
+package models
+
+// Sections that can be reported as degraded when an enrichment source fails.
+const (
+	SectionComponentPages  = "components.hasOwnPage"
+	SectionCustomItems     = "customItems"
+	SectionOwnedBlueprints = "ownedBlueprints"
+	SectionOwnedMaterials  = "ownedMaterials"
+)
+
+// DegradedSection names a part of a response that could not be fully populated.
+type DegradedSection struct {
+	Section string `json:"section"`
+	Reason  string `json:"reason"`
+}
+
+// Degradation is embedded in responses that carry core data plus optional
+// enrichments. When an enrichment source fails, the core data is still
+// returned and the failed section is listed here instead of failing the whole
+// request with a 500. It is never persisted.
+type Degradation struct {
+	Degraded []DegradedSection `json:"degraded,omitempty" bson:"-"`
+}
+
+// MarkDegraded records that section could not be populated. The underlying
+// error is intentionally not exposed to clients; reason should be a short,
+// user-safe description.
+func (d *Degradation) MarkDegraded(section, reason string) {
+	for _, existing := range d.Degraded {
+		if existing.Section == section {
+			return
+		}
+	}
+	d.Degraded = append(d.Degraded, DegradedSection{Section: section, Reason: reason})
+}
+
+// IsDegraded reports whether any section was marked degraded.
+func (d *Degradation) IsDegraded() bool {
+	return len(d.Degraded) > 0
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// DeprecationUsage reports who still uses a deprecated field or parameter
+// since this instance started.
+type DeprecationUsage struct {
+	ID      string
+	Message string
+	Since   time.Time
+	// Sunset is when the deprecated use stops working; zero if not yet
+	// scheduled.
+	Sunset  time.Time
+	Total   int64
+	Clients []DeprecationClient
+}
+
+// DeprecationClient counts one client's uses of a deprecation.
+type DeprecationClient struct {
+	ClientID string
+	Count    int64
+	LastSeen time.Time
+}
@@ -0,0 +1,7 @@
+// Package models holds the documents the repositories store and the inputs
+// the services accept. It does not define the API: responses and request
+// bodies live in internal/dto and are mapped to and from these types field by
+// field, so adding a stored field never changes what clients see. The json
+// tags that remain here are used for loading data files (WFCD items, kiosk
+// wishlists), not as the API contract.
+package models
@@ -0,0 +1,33 @@
+package models
+
+// FarmingMaterial is an outstanding material that drops at a location.
+// Best is set when no location drops it with a higher chance.
+type FarmingMaterial struct {
+	UniqueName string
+	Name       string
+	Remaining  int
+	Chance     float64
+	Rarity     string
+	Best       bool
+}
+
+// FarmingLocation is a node or mission and the outstanding materials it
+// drops, best chance first. BestFor counts the materials it is the best
+// source of.
+type FarmingLocation struct {
+	Location  string
+	Materials []FarmingMaterial
+	BestFor   int
+}
+
+// FarmingPlan ranks the locations dropping a wishlist's outstanding
+// materials, those covering the most materials first. Unlocated lists the
+// materials no item data drop covers, such as mission pickups. Truncated,
+// TruncatedReason and Degradation carry over from the materials resolution.
+type FarmingPlan struct {
+	Locations       []FarmingLocation
+	Unlocated       []MaterialRequirement
+	Truncated       bool
+	TruncatedReason string
+	Degradation
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MaxFoundryBuilds bounds how many builds a user can track at once.
+const MaxFoundryBuilds = 50
+
+// FoundryBuild is an item a user has started building in their foundry.
+// BuildTime is the item's build time in seconds when the build was started,
+// so later data updates never move a running build's finish.
+type FoundryBuild struct {
+	ID         primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	UserID     string             `json:"userId" bson:"userId"`
+	UniqueName string             `json:"uniqueName" bson:"uniqueName"`
+	Name       string             `json:"name" bson:"name"`
+	BuildTime  int                `json:"buildTime" bson:"buildTime"`
+	StartedAt  time.Time          `json:"startedAt" bson:"startedAt"`
+	CreatedAt  time.Time          `json:"createdAt" bson:"createdAt"`
+}
+
+// ReadyAt is when the build finishes.
+func (b *FoundryBuild) ReadyAt() time.Time {
+	return b.StartedAt.Add(time.Duration(b.BuildTime) * time.Second)
+}
+
+// FoundryBuildStatus is a build as of a moment: how many seconds it still
+// needs, and whether it has finished and is waiting to be claimed.
+type FoundryBuildStatus struct {
+	FoundryBuild
+	ReadyAt          time.Time `json:"readyAt"`
+	RemainingSeconds int       `json:"remainingSeconds"`
+	Finished         bool      `json:"finished"`
+}
+
+// NewFoundryBuildStatus returns the status of build at now.
+func NewFoundryBuildStatus(build FoundryBuild, now time.Time) FoundryBuildStatus {
+	readyAt := build.ReadyAt()
+	remaining := 0
+	if readyAt.After(now) {
+		// Round up, so a build is never reported with 0 seconds left
+		// before it has finished.
+		remaining = int((readyAt.Sub(now) + time.Second - 1) / time.Second)
+	}
+	return FoundryBuildStatus{
+		FoundryBuild:     build,
+		ReadyAt:          readyAt,
+		RemainingSeconds: remaining,
+		Finished:         !readyAt.After(now),
+	}
+}
+
+// Foundry is what a user has building, the soonest to finish first.
+type Foundry struct {
+	Builds   []FoundryBuildStatus `json:"builds"`
+	Finished int                  `json:"finished"`
+}
+
+// FoundryBuildRequest starts tracking a build of an item. A nil StartedAt
+// means the build started now.
+type FoundryBuildRequest struct {
+	UniqueName string
+	StartedAt  *time.Time
+}
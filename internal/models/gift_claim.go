@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// DefaultGiftClaimDays is how long a claim lasts when the claimer does not
+	// say; MaxGiftClaimDays bounds what they may ask for.
+	DefaultGiftClaimDays = 14
+	MaxGiftClaimDays     = 90
+)
+
+// GiftClaim marks an item on another user's public wishlist as being bought
+// by ClaimerID, so friends do not gift the same item twice. An item has at
+// most one active claim; it lapses at ExpiresAt. Anonymous hides ClaimerID
+// from everyone but the claimer.
+type GiftClaim struct {
+	ID         primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	OwnerID    string             `json:"ownerId" bson:"ownerId"`
+	UniqueName string             `json:"uniqueName" bson:"uniqueName"`
+	ClaimerID  string             `json:"claimerId" bson:"claimerId"`
+	Anonymous  bool               `json:"anonymous" bson:"anonymous"`
+	CreatedAt  time.Time          `json:"createdAt" bson:"createdAt"`
+	ExpiresAt  time.Time          `json:"expiresAt" bson:"expiresAt"`
+}
+
+// Active reports whether the claim has not expired at now.
+func (c *GiftClaim) Active(now time.Time) bool {
+	return c != nil && now.Before(c.ExpiresAt)
+}
+
+type ClaimGiftRequest struct {
+	UniqueName string
+	Anonymous  bool
+	// Days is how long the claim lasts; 0 means DefaultGiftClaimDays.
+	Days int
+}
+
+// SharedWishlist is another user's public wishlist as a friend sees it, with
+// the active claim on each item. Claimed is true for every claimed item;
+// Claim is set only when the caller may see who claimed it.
+type SharedWishlist struct {
+	OwnerID string
+	Items   []SharedWishlistItem
+}
+
+type SharedWishlistItem struct {
+	UniqueName string
+	Quantity   int
+	AddedAt    time.Time
+	Claimed    bool
+	Claim      *GiftClaim
+}
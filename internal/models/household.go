@@ -0,0 +1,80 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Household link statuses. A member requests a manager (pending) and the
+// manager accepts (active). Only active links gate wishlist changes.
+const (
+	HouseholdLinkPending = "pending"
+	HouseholdLinkActive  = "active"
+)
+
+// Pending change types and statuses.
+const (
+	ChangeTypeAddItem        = "add_item"
+	ChangeTypeUpdateQuantity = "update_quantity"
+
+	ChangeStatusPending   = "pending"
+	ChangeStatusApproved  = "approved"
+	ChangeStatusRejected  = "rejected"
+	ChangeStatusCancelled = "cancelled"
+)
+
+// HouseholdLink ties a member account to the manager account that approves
+// its larger wishlist changes. A member has at most one link.
+type HouseholdLink struct {
+	ID                primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	ManagerID         string             `json:"managerId" bson:"managerId"`
+	MemberID          string             `json:"memberId" bson:"memberId"`
+	QuantityThreshold int                `json:"quantityThreshold" bson:"quantityThreshold"`
+	Status            string             `json:"status" bson:"status"`
+	CreatedAt         time.Time          `json:"createdAt" bson:"createdAt"`
+	UpdatedAt         time.Time          `json:"updatedAt" bson:"updatedAt"`
+}
+
+// RequiresApproval reports whether setting an item to quantity needs the
+// manager's approval.
+func (l *HouseholdLink) RequiresApproval(quantity int) bool {
+	return l != nil && l.Status == HouseholdLinkActive && quantity > l.QuantityThreshold
+}
+
+// PendingChange is a wishlist change held back until the member's manager
+// approves or rejects it.
+type PendingChange struct {
+	ID         primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	MemberID   string             `json:"memberId" bson:"memberId"`
+	ManagerID  string             `json:"managerId" bson:"managerId"`
+	Type       string             `json:"type" bson:"type"`
+	UniqueName string             `json:"uniqueName" bson:"uniqueName"`
+	Quantity   int                `json:"quantity" bson:"quantity"`
+	Status     string             `json:"status" bson:"status"`
+	CreatedAt  time.Time          `json:"createdAt" bson:"createdAt"`
+	DecidedAt  *time.Time         `json:"decidedAt,omitempty" bson:"decidedAt,omitempty"`
+}
+
+// Household is the caller's view of their links: the manager they are linked
+// to (if any) and the members they manage.
+type Household struct {
+	Manager *HouseholdLink  `json:"manager"`
+	Members []HouseholdLink `json:"members"`
+}
+
+// HouseholdApprovals lists changes awaiting the caller's decision and the
+// caller's own changes awaiting their manager.
+type HouseholdApprovals struct {
+	ToReview  []PendingChange `json:"toReview"`
+	Requested []PendingChange `json:"requested"`
+}
+
+type RequestManagerRequest struct {
+	ManagerUserID     string
+	QuantityThreshold int
+}
+
+type UpdateHouseholdMemberRequest struct {
+	QuantityThreshold *int
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// IdempotencyRecord is the outcome of a request a user sent with an
+// Idempotency-Key, kept so a retry with the same key gets the same response
+// instead of repeating the change. Fingerprint identifies the request the key
+// was first used for. A record is pending, with no response, while that
+// request is served, and lapses at ExpiresAt.
+type IdempotencyRecord struct {
+	ID          primitive.ObjectID  `bson:"_id,omitempty"`
+	UserID      string              `bson:"userId"`
+	Key         string              `bson:"key"`
+	Fingerprint string              `bson:"fingerprint"`
+	Completed   bool                `bson:"completed"`
+	Status      int                 `bson:"status,omitempty"`
+	Header      map[string][]string `bson:"header,omitempty"`
+	Body        []byte              `bson:"body,omitempty"`
+	CreatedAt   time.Time           `bson:"createdAt"`
+	ExpiresAt   time.Time           `bson:"expiresAt"`
+}
+
+// Active reports whether the record has not expired at now.
+func (r *IdempotencyRecord) Active(now time.Time) bool {
+	return r != nil && now.Before(r.ExpiresAt)
+}
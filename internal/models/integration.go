@@ -0,0 +1,95 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Integration events, sent after a data sync for the item changes it found.
+const (
+	IntegrationItemAdded           = "item.added"
+	IntegrationItemRemoved         = "item.removed"
+	IntegrationRecipeChanged       = "item.recipeChanged"
+	IntegrationStatsChanged        = "item.statsChanged"
+	IntegrationAvailabilityChanged = "item.availabilityChanged"
+)
+
+// IntegrationEvents lists every event an integration can subscribe to.
+var IntegrationEvents = []string{
+	IntegrationItemAdded, IntegrationItemRemoved, IntegrationRecipeChanged, IntegrationStatsChanged, IntegrationAvailabilityChanged,
+}
+
+// IntegrationEventForKind maps each ItemChange kind to its event.
+var IntegrationEventForKind = map[string]string{
+	ItemChangeAdded:        IntegrationItemAdded,
+	ItemChangeRemoved:      IntegrationItemRemoved,
+	ItemChangeRecipe:       IntegrationRecipeChanged,
+	ItemChangeStats:        IntegrationStatsChanged,
+	ItemChangeAvailability: IntegrationAvailabilityChanged,
+}
+
+// MaxIntegrations bounds the integrations that can be registered.
+const MaxIntegrations = 50
+
+// Integration is a downstream tool, such as a market bot or wiki updater,
+// sent item data changes as webhooks signed with Secret. Events limits the
+// events sent; empty means all of them.
+type Integration struct {
+	ID        primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	Name      string             `json:"name" bson:"name"`
+	URL       string             `json:"url" bson:"url"`
+	Secret    string             `json:"-" bson:"secret,omitempty"`
+	Events    []string           `json:"events" bson:"events"`
+	CreatedAt time.Time          `json:"createdAt" bson:"createdAt"`
+}
+
+// Subscribed reports whether the integration receives event.
+func (i *Integration) Subscribed(event string) bool {
+	if len(i.Events) == 0 {
+		return true
+	}
+	for _, e := range i.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// IntegrationRequest registers an integration.
+type IntegrationRequest struct {
+	Name   string
+	URL    string
+	Events []string
+}
+
+// IntegrationDelivery is a webhook queued for, or sent to, one integration,
+// with the same statuses and retries as NotificationDelivery. Payload is the
+// JSON body sent.
+type IntegrationDelivery struct {
+	ID primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	// DeliveryID is sent in the webhook's delivery header, the same on
+	// every attempt, for receivers to drop repeats.
+	DeliveryID     string             `json:"deliveryId,omitempty" bson:"deliveryId,omitempty"`
+	IntegrationID  primitive.ObjectID `json:"integrationId" bson:"integrationId"`
+	Event          string             `json:"event" bson:"event"`
+	Key            string             `json:"key" bson:"key"`
+	Payload        string             `json:"payload" bson:"payload"`
+	Status         string             `json:"status" bson:"status"`
+	Attempts       int                `json:"attempts" bson:"attempts"`
+	ResponseStatus int                `json:"responseStatus" bson:"responseStatus"`
+	LastError      string             `json:"lastError" bson:"lastError"`
+	NextAttemptAt  time.Time          `json:"nextAttemptAt" bson:"nextAttemptAt"`
+	CreatedAt      time.Time          `json:"createdAt" bson:"createdAt"`
+	DeliveredAt    *time.Time         `json:"deliveredAt,omitempty" bson:"deliveredAt,omitempty"`
+}
+
+// WebhookID returns DeliveryID, or the document ID of a delivery queued
+// before deliveries had one.
+func (d *IntegrationDelivery) WebhookID() string {
+	if d.DeliveryID != "" {
+		return d.DeliveryID
+	}
+	return d.ID.Hex()
+}
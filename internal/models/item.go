@@ -1,6 +1,11 @@
 package models
 
-import "go.mongodb.org/mongo-driver/bson/primitive"
+import (
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
 
 type Component struct {
 	UniqueName   string      `json:"uniqueName" bson:"uniqueName"`
@@ -39,10 +44,32 @@ type Item struct {
 	BuildQuantity    int                `json:"buildQuantity,omitempty" bson:"buildQuantity,omitempty"`
 	ConsumeOnBuild   bool               `json:"consumeOnBuild,omitempty" bson:"consumeOnBuild,omitempty"`
 	Components       []Component        `json:"components,omitempty" bson:"components,omitempty"`
+	// AlternateRecipes are other ways to craft the item; the fields above
+	// are its default recipe.
+	AlternateRecipes []Recipe           `json:"alternateRecipes,omitempty" bson:"alternateRecipes,omitempty"`
 	Drops            []Drop             `json:"drops,omitempty" bson:"drops,omitempty"`
 	WikiaThumbnail   string             `json:"wikiaThumbnail,omitempty" bson:"wikiaThumbnail,omitempty"`
 	WikiaURL         string             `json:"wikiaUrl,omitempty" bson:"wikiaUrl,omitempty"`
+	// The stats are stored flat in the item document, as in the item data.
+	FrameStats       `bson:",inline"`
+	WeaponStats      `bson:",inline"`
+	// Archived is set by the data sync when an item is removed upstream; the
+	// document is kept so wishlists and owned blueprints still resolve.
+	Archived         bool               `json:"archived,omitempty" bson:"archived,omitempty"`
+	ArchivedAt       *time.Time         `json:"archivedAt,omitempty" bson:"archivedAt,omitempty"`
 	Collection       string             `json:"_collection,omitempty" bson:"_collection,omitempty"`
+	Degradation      `bson:"-"`
+}
+
+// Recipe is an alternate way to craft an item, such as a variant blueprint.
+// ID is unique among the item's alternate recipes.
+type Recipe struct {
+	ID            string      `json:"id" bson:"id"`
+	Name          string      `json:"name" bson:"name"`
+	BuildPrice    int         `json:"buildPrice,omitempty" bson:"buildPrice,omitempty"`
+	BuildTime     int         `json:"buildTime,omitempty" bson:"buildTime,omitempty"`
+	BuildQuantity int         `json:"buildQuantity,omitempty" bson:"buildQuantity,omitempty"`
+	Components    []Component `json:"components,omitempty" bson:"components,omitempty"`
 }
 
 type ItemSearchResult struct {
@@ -51,12 +78,114 @@ type ItemSearchResult struct {
 	Description string `json:"description,omitempty" bson:"description,omitempty"`
 	Category    string `json:"category,omitempty" bson:"category,omitempty"`
 	ImageName   string `json:"imageName,omitempty" bson:"imageName,omitempty"`
+	Archived    bool   `json:"archived,omitempty" bson:"archived,omitempty"`
 	Collection  string `json:"_collection,omitempty" bson:"_collection,omitempty"`
+	// Score is the text search relevance; zero when listing without a query.
+	Score float64 `json:"-" bson:"score,omitempty"`
 }
 
+// ItemSearchPage is one page of search results. Total counts every match,
+// not only those on the page.
+type ItemSearchPage struct {
+	Items []ItemSearchResult
+	Total int
+}
+
+const (
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
+	// MaxSearchOffset bounds how deep search results can be paged, since every
+	// collection returns its best offset+limit matches to be merged.
+	MaxSearchOffset = 1000
+)
+
+// SearchParams selects items by a text query over name and description. The
+// query matches whole words, case-insensitively; "quoted phrases" must appear
+// as written and -words exclude items. Without a query every item matches,
+// in collection order.
 type SearchParams struct {
 	Query    string
 	Category string
 	Limit    int
 	Offset   int
+	// IncludeArchived also returns items removed from the game.
+	IncludeArchived bool
+}
+
+// Normalized returns p with the query trimmed, Limit defaulted and capped and
+// Offset clamped to [0, MaxSearchOffset].
+func (p SearchParams) Normalized() SearchParams {
+	p.Query = strings.TrimSpace(p.Query)
+	if p.Limit <= 0 {
+		p.Limit = DefaultSearchLimit
+	}
+	p.Limit = min(p.Limit, MaxSearchLimit)
+	p.Offset = min(max(p.Offset, 0), MaxSearchOffset)
+	return p
+}
+
+// Clone returns a deep copy of the item that shares no slices with the
+// original, so cached or stored items can be handed out and mutated safely.
+// Degradation is per-response and is not copied.
+func (i *Item) Clone() *Item {
+	if i == nil {
+		return nil
+	}
+	clone := *i
+	clone.Components = cloneComponents(i.Components)
+	if i.Drops != nil {
+		clone.Drops = append([]Drop(nil), i.Drops...)
+	}
+	if i.AlternateRecipes != nil {
+		clone.AlternateRecipes = make([]Recipe, len(i.AlternateRecipes))
+		for j, recipe := range i.AlternateRecipes {
+			recipe.Components = cloneComponents(recipe.Components)
+			clone.AlternateRecipes[j] = recipe
+		}
+	}
+	if i.ArchivedAt != nil {
+		archivedAt := *i.ArchivedAt
+		clone.ArchivedAt = &archivedAt
+	}
+	clone.FrameStats = i.FrameStats.clone()
+	clone.WeaponStats = i.WeaponStats.clone()
+	clone.Degradation = Degradation{}
+	return &clone
+}
+
+// WithRecipe returns the item as crafted with the alternate recipe recipeID:
+// a clone whose build fields and components are the recipe's. An empty
+// recipeID selects the default recipe and returns the item itself. It reports
+// false when the item has no such recipe.
+func (i *Item) WithRecipe(recipeID string) (*Item, bool) {
+	if recipeID == "" {
+		return i, true
+	}
+	for _, recipe := range i.AlternateRecipes {
+		if recipe.ID != recipeID {
+			continue
+		}
+		clone := i.Clone()
+		clone.BuildPrice = recipe.BuildPrice
+		clone.BuildTime = recipe.BuildTime
+		clone.BuildQuantity = recipe.BuildQuantity
+		clone.Components = cloneComponents(recipe.Components)
+		return clone, true
+	}
+	return nil, false
+}
+
+func cloneComponents(components []Component) []Component {
+	if components == nil {
+		return nil
+	}
+	cloned := make([]Component, len(components))
+	for i, c := range components {
+		c.Components = cloneComponents(c.Components)
+		if c.Drops != nil {
+			c.Drops = append([]Drop(nil), c.Drops...)
+		}
+		cloned[i] = c
+	}
+	return cloned
 }
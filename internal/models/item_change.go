@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Item change kinds reported by the changes feed.
+const (
+	ItemChangeAdded        = "added"
+	ItemChangeRemoved      = "removed"
+	ItemChangeRecipe       = "recipe"
+	ItemChangeStats        = "stats"
+	ItemChangeAvailability = "availability"
+)
+
+// ItemFingerprint holds hashes of the parts of an item the changes feed
+// tracks, as of the most recent data sync.
+type ItemFingerprint struct {
+	UniqueName   string `json:"uniqueName" bson:"uniqueName"`
+	Name         string `json:"name" bson:"name"`
+	Collection   string `json:"collection" bson:"collection"`
+	Recipe       string `json:"recipe" bson:"recipe"`
+	Stats        string `json:"stats" bson:"stats"`
+	Availability string `json:"availability" bson:"availability"`
+	Archived     bool   `json:"archived,omitempty" bson:"archived,omitempty"`
+}
+
+// ItemChange records that a data sync added, removed, or changed an item.
+// Kinds lists what changed, using the ItemChange* constants.
+type ItemChange struct {
+	ID          primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	UniqueName  string             `json:"uniqueName" bson:"uniqueName"`
+	Name        string             `json:"name" bson:"name"`
+	Collection  string             `json:"collection" bson:"collection"`
+	Kinds       []string           `json:"kinds" bson:"kinds"`
+	DataVersion string             `json:"dataVersion,omitempty" bson:"dataVersion,omitempty"`
+	ChangedAt   time.Time          `json:"changedAt" bson:"changedAt"`
+}
+
+// ItemChangesResponse is the changes feed returned to clients.
+type ItemChangesResponse struct {
+	Since   time.Time    `json:"since"`
+	Changes []ItemChange `json:"changes"`
+	Count   int          `json:"count"`
+}
@@ -0,0 +1,70 @@
+package models
+
+import "maps"
+
+// FrameStats are the base stats of warframes, archwings and companions, as
+// given by the item data.
+type FrameStats struct {
+	Health             float64   `json:"health,omitempty" bson:"health,omitempty"`
+	Shield             float64   `json:"shield,omitempty" bson:"shield,omitempty"`
+	Armor              float64   `json:"armor,omitempty" bson:"armor,omitempty"`
+	Power              float64   `json:"power,omitempty" bson:"power,omitempty"`
+	SprintSpeed        float64   `json:"sprintSpeed,omitempty" bson:"sprintSpeed,omitempty"`
+	PassiveDescription string    `json:"passiveDescription,omitempty" bson:"passiveDescription,omitempty"`
+	Abilities          []Ability `json:"abilities,omitempty" bson:"abilities,omitempty"`
+}
+
+type Ability struct {
+	UniqueName  string `json:"uniqueName" bson:"uniqueName"`
+	Name        string `json:"name" bson:"name"`
+	Description string `json:"description,omitempty" bson:"description,omitempty"`
+	ImageName   string `json:"imageName,omitempty" bson:"imageName,omitempty"`
+}
+
+// IsZero reports whether the item has no frame stats.
+func (s FrameStats) IsZero() bool {
+	return s.Health == 0 && s.Shield == 0 && s.Armor == 0 && s.Power == 0 && s.SprintSpeed == 0 &&
+		s.PassiveDescription == "" && len(s.Abilities) == 0
+}
+
+// WeaponStats are the unmodded stats of weapons, as given by the item data.
+// Damage maps each damage type to its amount per shot and includes "total".
+// Disposition is the riven disposition from 1 to 5 and OmegaAttenuation the
+// multiplier it is derived from.
+type WeaponStats struct {
+	TotalDamage        float64            `json:"totalDamage,omitempty" bson:"totalDamage,omitempty"`
+	Damage             map[string]float64 `json:"damage,omitempty" bson:"damage,omitempty"`
+	CriticalChance     float64            `json:"criticalChance,omitempty" bson:"criticalChance,omitempty"`
+	CriticalMultiplier float64            `json:"criticalMultiplier,omitempty" bson:"criticalMultiplier,omitempty"`
+	ProcChance         float64            `json:"procChance,omitempty" bson:"procChance,omitempty"`
+	FireRate           float64            `json:"fireRate,omitempty" bson:"fireRate,omitempty"`
+	Multishot          float64            `json:"multishot,omitempty" bson:"multishot,omitempty"`
+	Accuracy           float64            `json:"accuracy,omitempty" bson:"accuracy,omitempty"`
+	MagazineSize       int                `json:"magazineSize,omitempty" bson:"magazineSize,omitempty"`
+	ReloadTime         float64            `json:"reloadTime,omitempty" bson:"reloadTime,omitempty"`
+	Range              float64            `json:"range,omitempty" bson:"range,omitempty"`
+	Trigger            string             `json:"trigger,omitempty" bson:"trigger,omitempty"`
+	Noise              string             `json:"noise,omitempty" bson:"noise,omitempty"`
+	Disposition        int                `json:"disposition,omitempty" bson:"disposition,omitempty"`
+	OmegaAttenuation   float64            `json:"omegaAttenuation,omitempty" bson:"omegaAttenuation,omitempty"`
+}
+
+// IsZero reports whether the item has no weapon stats.
+func (s WeaponStats) IsZero() bool {
+	return s.TotalDamage == 0 && len(s.Damage) == 0 && s.CriticalChance == 0 && s.CriticalMultiplier == 0 &&
+		s.ProcChance == 0 && s.FireRate == 0 && s.Multishot == 0 && s.Accuracy == 0 && s.MagazineSize == 0 &&
+		s.ReloadTime == 0 && s.Range == 0 && s.Trigger == "" && s.Noise == "" && s.Disposition == 0 &&
+		s.OmegaAttenuation == 0
+}
+
+func (s FrameStats) clone() FrameStats {
+	if s.Abilities != nil {
+		s.Abilities = append([]Ability(nil), s.Abilities...)
+	}
+	return s
+}
+
+func (s WeaponStats) clone() WeaponStats {
+	s.Damage = maps.Clone(s.Damage)
+	return s
+}
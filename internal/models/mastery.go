@@ -0,0 +1,63 @@
+package models
+
+import "time"
+
+// Statuses of a mastery profile entry: the user has built the item, or has
+// also ranked it up for its mastery.
+const (
+	MasteryStatusBuilt    = "built"
+	MasteryStatusMastered = "mastered"
+)
+
+// MasteryCollections are the item collections whose items give mastery, the
+// ones the mastery summary counts.
+var MasteryCollections = []string{
+	"warframes", "primary", "secondary", "melee", "arch_gun", "arch_melee",
+	"archwing", "sentinels", "sentinelweapons", "pets",
+}
+
+// MasteryEntry is an item a user already has. Each is stored as its own
+// record.
+type MasteryEntry struct {
+	UserID     string    `json:"-" bson:"userId"`
+	UniqueName string    `json:"uniqueName" bson:"uniqueName"`
+	Status     string    `json:"status" bson:"status"`
+	UpdatedAt  time.Time `json:"updatedAt" bson:"updatedAt"`
+}
+
+// MasteryProfile is a user's built and mastered items, ordered by uniqueName.
+type MasteryProfile struct {
+	UserID  string         `json:"userId"`
+	Entries []MasteryEntry `json:"entries"`
+}
+
+type MasteryStatus struct {
+	UniqueName string
+	Status     string
+}
+
+// SetMasteryRequest sets the status of every listed item, leaving unlisted
+// ones unchanged. An empty status removes the item.
+type SetMasteryRequest struct {
+	Items []MasteryStatus
+}
+
+// MasterySummary is a user's progress against every buildable item that
+// gives mastery. Built counts mastered items too, and only items still in
+// the game data are counted.
+type MasterySummary struct {
+	UserID          string                   `json:"userId"`
+	Buildable       int                      `json:"buildable"`
+	Built           int                      `json:"built"`
+	Mastered        int                      `json:"mastered"`
+	MasteredPercent float64                  `json:"masteredPercent"`
+	Categories      []MasteryCategorySummary `json:"categories"`
+}
+
+// MasteryCategorySummary is the progress within one item collection.
+type MasteryCategorySummary struct {
+	Collection string `json:"collection"`
+	Buildable  int    `json:"buildable"`
+	Built      int    `json:"built"`
+	Mastered   int    `json:"mastered"`
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Reasons a share link can be reported for.
+const (
+	ReportReasonSpam  = "spam"
+	ReportReasonAbuse = "abuse"
+	ReportReasonOther = "other"
+)
+
+// ReportReasons lists every reason a share link can be reported for.
+var ReportReasons = []string{ReportReasonSpam, ReportReasonAbuse, ReportReasonOther}
+
+// Statuses of a share link report. An open report is in the moderation
+// queue; a moderator either revokes the link, which actions it, or dismisses
+// it.
+const (
+	ReportStatusOpen      = "open"
+	ReportStatusActioned  = "actioned"
+	ReportStatusDismissed = "dismissed"
+)
+
+// ShareLinkReport is the moderation queue entry of a reported share link.
+// Reports made while an entry is open count towards it rather than queueing
+// the link again; a report after it is closed opens a new one.
+type ShareLinkReport struct {
+	ID    primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	Token string             `json:"token" bson:"token"`
+	// OwnerID is the user the link belongs to.
+	OwnerID        string     `json:"ownerId" bson:"ownerId"`
+	Reasons        []string   `json:"reasons" bson:"reasons"`
+	Count          int        `json:"count" bson:"count"`
+	Status         string     `json:"status" bson:"status"`
+	CreatedAt      time.Time  `json:"createdAt" bson:"createdAt"`
+	LastReportedAt time.Time  `json:"lastReportedAt" bson:"lastReportedAt"`
+	ResolvedAt     *time.Time `json:"resolvedAt,omitempty" bson:"resolvedAt,omitempty"`
+}
+
+type ReportShareLinkRequest struct {
+	Reason string
+}
+
+// ImportAlias expands a word of shorthand in pasted wishlist imports, such as
+// "bp" to "blueprint". BuiltIn marks the aliases shipped with the server
+// that no stored alias overrides.
+type ImportAlias struct {
+	Word      string    `json:"word" bson:"_id"`
+	Expansion string    `json:"expansion" bson:"expansion"`
+	BuiltIn   bool      `json:"builtIn" bson:"-"`
+	UpdatedAt time.Time `json:"updatedAt" bson:"updatedAt"`
+}
@@ -0,0 +1,153 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Notification channel types.
+const (
+	NotificationWebhook = "webhook"
+	NotificationPush    = "push"
+)
+
+// Notification events.
+const (
+	NotificationOpportunity     = "opportunity.appeared"
+	NotificationFoundryFinished = "foundry.finished"
+	NotificationMilestone       = "wishlist.milestone"
+	// NotificationDigest is sent daily at the user's DigestTime.
+	NotificationDigest = "wishlist.digest"
+)
+
+// NotificationEvents lists every event a channel can subscribe to.
+var NotificationEvents = []string{NotificationOpportunity, NotificationFoundryFinished, NotificationMilestone, NotificationDigest}
+
+// Wishlist milestones, sent as NotificationMilestone when the wishlist
+// reaches them.
+const (
+	// MilestoneMaterialsHalf is half of the wishlist's materials gathered.
+	MilestoneMaterialsHalf = "materialsHalf"
+	// MilestoneBlueprintsOwned is every reusable blueprint the wishlist
+	// builds owned.
+	MilestoneBlueprintsOwned = "blueprintsOwned"
+	// MilestoneLastFoundryRun is one foundry build left to finish the
+	// wishlist.
+	MilestoneLastFoundryRun = "lastFoundryRun"
+)
+
+// Milestones lists every wishlist milestone.
+var Milestones = []string{MilestoneMaterialsHalf, MilestoneBlueprintsOwned, MilestoneLastFoundryRun}
+
+// Notification delivery statuses.
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed"
+)
+
+const (
+	// MaxNotificationChannels bounds the channels a user can register.
+	MaxNotificationChannels = 10
+	// MaxDeliveryAttempts is how many times a delivery is tried before it
+	// is marked failed.
+	MaxDeliveryAttempts = 6
+	// DeliveryRetentionDays is how long delivery logs are kept.
+	DeliveryRetentionDays = 30
+)
+
+// PushKeys are a browser push subscription's keys, base64url encoded as the
+// Push API returns them.
+type PushKeys struct {
+	P256dh string `json:"p256dh" bson:"p256dh"`
+	Auth   string `json:"auth" bson:"auth"`
+}
+
+// NotificationChannel is where a user's notifications are sent: a webhook
+// URL, signed with Secret, or a Web Push subscription endpoint. Events
+// limits the events sent; empty means all of them.
+type NotificationChannel struct {
+	ID        primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	UserID    string             `json:"userId" bson:"userId"`
+	Type      string             `json:"type" bson:"type"`
+	URL       string             `json:"url" bson:"url"`
+	Secret    string             `json:"-" bson:"secret,omitempty"`
+	Keys      PushKeys           `json:"keys" bson:"keys"`
+	Events    []string           `json:"events" bson:"events"`
+	CreatedAt time.Time          `json:"createdAt" bson:"createdAt"`
+}
+
+// Subscribed reports whether the channel receives event.
+func (c *NotificationChannel) Subscribed(event string) bool {
+	if len(c.Events) == 0 {
+		return true
+	}
+	for _, e := range c.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// NotificationChannelRequest registers a channel. Keys are used only for
+// push channels.
+type NotificationChannelRequest struct {
+	Type   string
+	URL    string
+	Keys   PushKeys
+	Events []string
+}
+
+// Notification is an event to send to a user's channels. Key identifies the
+// occurrence, so each is delivered to a channel at most once; OccurredAt
+// keeps events from before a channel was registered off it.
+type Notification struct {
+	Event      string
+	Key        string
+	Title      string
+	Body       string
+	Data       map[string]string
+	OccurredAt time.Time
+}
+
+// MilestoneState is the milestones a user's wishlist had reached when last
+// checked, each with when it was reached. Milestones are notified as they
+// enter Reached.
+type MilestoneState struct {
+	UserID    string               `json:"userId" bson:"userId"`
+	Reached   map[string]time.Time `json:"reached" bson:"reached"`
+	CheckedAt time.Time            `json:"checkedAt" bson:"checkedAt"`
+}
+
+// NotificationDelivery is a notification queued for, or sent to, one
+// channel. Payload is the JSON body sent. A pending delivery is next tried
+// at NextAttemptAt.
+type NotificationDelivery struct {
+	ID primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	// DeliveryID is sent in the webhook's delivery header, the same on
+	// every attempt, for receivers to drop repeats.
+	DeliveryID     string             `json:"deliveryId,omitempty" bson:"deliveryId,omitempty"`
+	UserID         string             `json:"userId" bson:"userId"`
+	ChannelID      primitive.ObjectID `json:"channelId" bson:"channelId"`
+	Event          string             `json:"event" bson:"event"`
+	Key            string             `json:"key" bson:"key"`
+	Payload        string             `json:"payload" bson:"payload"`
+	Status         string             `json:"status" bson:"status"`
+	Attempts       int                `json:"attempts" bson:"attempts"`
+	ResponseStatus int                `json:"responseStatus" bson:"responseStatus"`
+	LastError      string             `json:"lastError" bson:"lastError"`
+	NextAttemptAt  time.Time          `json:"nextAttemptAt" bson:"nextAttemptAt"`
+	CreatedAt      time.Time          `json:"createdAt" bson:"createdAt"`
+	DeliveredAt    *time.Time         `json:"deliveredAt,omitempty" bson:"deliveredAt,omitempty"`
+}
+
+// WebhookID returns DeliveryID, or the document ID of a delivery queued
+// before deliveries had one.
+func (d *NotificationDelivery) WebhookID() string {
+	if d.DeliveryID != "" {
+		return d.DeliveryID
+	}
+	return d.ID.Hex()
+}
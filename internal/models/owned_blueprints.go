@@ -6,23 +6,39 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// OwnedBlueprint is a blueprint the user owns. Reusable blueprints are owned
+// once, so their Quantity is 1; consumable blueprints, such as Forma, are used
+// up by each build and Quantity counts the copies in stock.
 type OwnedBlueprint struct {
 	UniqueName string    `json:"uniqueName" bson:"uniqueName"`
+	Quantity   int       `json:"quantity" bson:"quantity"`
 	AddedAt    time.Time `json:"addedAt" bson:"addedAt"`
 }
 
+// OwnedComponent is a crafted component the user has already built, such as
+// a Chassis, and how many of it.
+type OwnedComponent struct {
+	UniqueName string    `json:"uniqueName" bson:"uniqueName"`
+	Count      int       `json:"count" bson:"count"`
+	AddedAt    time.Time `json:"addedAt" bson:"addedAt"`
+}
+
+// OwnedBlueprints holds the parts a user already owns: reusable blueprints,
+// and crafted components whose materials no longer need gathering.
 type OwnedBlueprints struct {
-	ID         primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
-	UserID     string             `json:"userId" bson:"userId"`
-	Blueprints []OwnedBlueprint   `json:"blueprints" bson:"blueprints"`
-	CreatedAt  time.Time          `json:"createdAt" bson:"createdAt"`
-	UpdatedAt  time.Time          `json:"updatedAt" bson:"updatedAt"`
+	ID            primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	UserID        string             `json:"userId" bson:"userId"`
+	Blueprints    []OwnedBlueprint   `json:"blueprints" bson:"blueprints"`
+	Components    []OwnedComponent   `json:"components" bson:"components"`
+	SchemaVersion int                `json:"-" bson:"schemaVersion"`
+	CreatedAt     time.Time          `json:"createdAt" bson:"createdAt"`
+	UpdatedAt     time.Time          `json:"updatedAt" bson:"updatedAt"`
 }
 
 type AddBlueprintRequest struct {
-	UniqueName string `json:"uniqueName"`
+	UniqueName string
 }
 
 type BulkAddBlueprintsRequest struct {
-	UniqueNames []string `json:"uniqueNames"`
+	UniqueNames []string
 }
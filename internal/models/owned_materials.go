@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// OwnedMaterial is how much of a material (Ferrite, Plastids, a crafted
+// component) a user already has. Each material is stored as its own record.
+type OwnedMaterial struct {
+	UserID     string    `json:"-" bson:"userId"`
+	UniqueName string    `json:"uniqueName" bson:"uniqueName"`
+	Count      int       `json:"count" bson:"count"`
+	UpdatedAt  time.Time `json:"updatedAt" bson:"updatedAt"`
+}
+
+// OwnedMaterials is a user's material inventory, ordered by uniqueName.
+type OwnedMaterials struct {
+	UserID    string          `json:"userId"`
+	Materials []OwnedMaterial `json:"materials"`
+}
+
+type OwnedMaterialCount struct {
+	UniqueName string
+	Count      int
+}
+
+// SetOwnedMaterialsRequest sets the count of every listed material, leaving
+// unlisted ones unchanged. A count of 0 removes the material.
+type SetOwnedMaterialsRequest struct {
+	Materials []OwnedMaterialCount
+}
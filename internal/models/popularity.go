@@ -0,0 +1,13 @@
+package models
+
+// ItemPopularity counts how many users have an item on their wishlist.
+type ItemPopularity struct {
+	UniqueName    string `json:"uniqueName" bson:"_id"`
+	WishlistCount int    `json:"wishlistCount" bson:"wishlistCount"`
+}
+
+// WishlistSizeStats summarises wishlist sizes across all users.
+type WishlistSizeStats struct {
+	Wishlists  int `json:"wishlists" bson:"wishlists"`
+	TotalItems int `json:"totalItems" bson:"totalItems"`
+}
@@ -0,0 +1,55 @@
+package models
+
+// ProfileInventory is the part of a game inventory export the app tracks:
+// the blueprints the user owns, and how many they have of each countable
+// item, resources and crafted components alike. Package inventory turns
+// each supported export format into one.
+type ProfileInventory struct {
+	Blueprints []InventoryCount
+	Items      []InventoryCount
+}
+
+type InventoryCount struct {
+	UniqueName string
+	Count      int
+}
+
+// ProfileImportRequest applies Inventory, read from an export in Format, in
+// Mode (ImportModeMerge or ImportModeReplace). With DryRun set nothing is
+// written and the result reports what would change.
+type ProfileImportRequest struct {
+	Format    string
+	Mode      string
+	DryRun    bool
+	Inventory ProfileInventory
+}
+
+// ProfileImportTally counts what an import did to one kind of owned entry.
+type ProfileImportTally struct {
+	Added     int
+	Updated   int
+	Unchanged int
+	Removed   int
+}
+
+// ProfileImportSkipped counts the inventory entries an import left out:
+// blueprints the item data does not know, blueprints used up by building,
+// items no recipe uses (relics, fish, decorations) and entries with an
+// invalid uniqueName or count.
+type ProfileImportSkipped struct {
+	NotFound    int
+	NotReusable int
+	Unused      int
+	Invalid     int
+}
+
+// ProfileImportResult summarises an inventory import.
+type ProfileImportResult struct {
+	Format     string
+	Mode       string
+	DryRun     bool
+	Blueprints ProfileImportTally
+	Components ProfileImportTally
+	Materials  ProfileImportTally
+	Skipped    ProfileImportSkipped
+}
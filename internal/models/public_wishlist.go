@@ -0,0 +1,10 @@
+package models
+
+// PublicWishlist is an operator-curated, read-only wishlist served in kiosk
+// mode, such as "Top Prime farming targets" on a convention stand.
+type PublicWishlist struct {
+	ID          string         `json:"id"`
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Items       []WishlistItem `json:"items"`
+}
@@ -0,0 +1,17 @@
+package models
+
+// QuantityUsage is a wishlist item whose recipe uses Count of another item.
+type QuantityUsage struct {
+	UniqueName string
+	Name       string
+	Count      int
+}
+
+// QuantitySuggestion is how many of an item the rest of the wishlist's
+// recipes use, after component progress, summed over UsedBy. It is advice
+// only; the wishlist quantity is never changed to match.
+type QuantitySuggestion struct {
+	UniqueName string
+	Suggested  int
+	UsedBy     []QuantityUsage
+}
@@ -0,0 +1,63 @@
+package models
+
+// SectionRecipeTree is reported as degraded when some components of a recipe
+// tree could not be looked up.
+const SectionRecipeTree = "recipeTree"
+
+// Reasons a recipe tree node's components are left out.
+const (
+	// RecipeTruncatedCycle marks a node that already appears among its own
+	// ancestors; expanding it again would never end.
+	RecipeTruncatedCycle = "cycle"
+	// RecipeTruncatedDepth marks a node at the maximum tree depth.
+	RecipeTruncatedDepth = "depth"
+	// RecipeTruncatedSize marks a node reached after the tree hit its
+	// maximum number of nodes.
+	RecipeTruncatedSize = "size"
+	// RecipeTruncatedUnavailable marks a node whose item lookup failed.
+	RecipeTruncatedUnavailable = "unavailable"
+)
+
+// RecipeTree is the crafting tree of an item as a list of nodes and the edges
+// between them. Every occurrence of a component is its own node, so the same
+// uniqueName can appear under several parents.
+type RecipeTree struct {
+	Root  string
+	Nodes []RecipeNode
+	Edges []RecipeEdge
+	// TotalCredits is the credit cost of every craft in the tree.
+	TotalCredits int
+	// Truncated is set when any node was truncated.
+	Truncated bool
+	Degradation
+}
+
+// RecipeNode is one item or material in a recipe tree. Quantity is how many
+// are needed to craft the root once; Crafts is how many times the node itself
+// is crafted to get them, zero for base materials.
+type RecipeNode struct {
+	ID            string
+	UniqueName    string
+	Name          string
+	ImageName     string
+	Depth         int
+	Quantity      int
+	Crafts        int
+	BuildQuantity int
+	BuildPrice    int
+	BuildTime     int
+	// HasOwnPage reports whether the node is an item of its own rather than
+	// only a component entry.
+	HasOwnPage bool
+	// Truncated is one of the RecipeTruncated reasons when the node's
+	// components are left out.
+	Truncated string
+}
+
+// RecipeEdge links a node to one of its components. ItemCount is the number
+// of the component used by one craft of the parent.
+type RecipeEdge struct {
+	From      string
+	To        string
+	ItemCount int
+}
@@ -0,0 +1,55 @@
+package models
+
+// Relic refinement levels, as drop tables name them. An unrefined relic is
+// Intact.
+const (
+	RelicIntact      = "Intact"
+	RelicExceptional = "Exceptional"
+	RelicFlawless    = "Flawless"
+	RelicRadiant     = "Radiant"
+)
+
+// PrimePart is a tradable component of a Prime item on the wishlist. Needed
+// is how many the wishlist still needs, after component progress.
+type PrimePart struct {
+	UniqueName     string
+	Name           string
+	ItemUniqueName string
+	ItemName       string
+	Needed         int
+}
+
+// RelicChances are the chances of a relic dropping a part at each
+// refinement; zero where the drop tables do not list it.
+type RelicChances struct {
+	Intact      float64
+	Exceptional float64
+	Flawless    float64
+	Radiant     float64
+}
+
+// RelicPart is a needed Prime part one relic drops.
+type RelicPart struct {
+	PrimePart
+	Rarity  string
+	Chances RelicChances
+}
+
+// RelicRequirement is a relic ("Axi A1") and the needed parts it drops, best
+// chance first. UniqueName and ImageName are those of the intact relic in
+// the relics collection, empty when it has no such item.
+type RelicRequirement struct {
+	Name       string
+	Tier       string
+	UniqueName string
+	ImageName  string
+	Parts      []RelicPart
+}
+
+// RelicRequirements lists the relics dropping the Prime parts a wishlist
+// still needs, those covering the most parts first. Unavailable lists the
+// needed parts no relic drops.
+type RelicRequirements struct {
+	Relics      []RelicRequirement
+	Unavailable []PrimePart
+}
@@ -0,0 +1,82 @@
+package models
+
+// MaxResearchItems bounds the items one research cost calculation covers.
+const MaxResearchItems = 200
+
+// DefaultClanTier is the tier research costs are given for when none is
+// chosen; its multiplier is 1.
+const DefaultClanTier = "ghost"
+
+// ClanTier is a clan size tier and how many times the base cost its dojo
+// research takes.
+type ClanTier struct {
+	Name       string
+	Multiplier int
+}
+
+// ClanTiers lists the clan tiers from smallest to largest.
+var ClanTiers = []ClanTier{
+	{Name: "ghost", Multiplier: 1},
+	{Name: "shadow", Multiplier: 3},
+	{Name: "storm", Multiplier: 10},
+	{Name: "mountain", Multiplier: 30},
+	{Name: "moon", Multiplier: 100},
+}
+
+// ClanTierMultiplier returns the research cost multiplier of the named tier.
+func ClanTierMultiplier(name string) (int, bool) {
+	for _, tier := range ClanTiers {
+		if tier.Name == name {
+			return tier.Multiplier, true
+		}
+	}
+	return 0, false
+}
+
+// ResearchItemRequest is an item to research, labelled with the dojo lab it
+// is researched in. Lab only groups the costs; it is not checked.
+type ResearchItemRequest struct {
+	UniqueName string
+	Lab        string
+}
+
+type ResearchCostRequest struct {
+	Tier  string
+	Items []ResearchItemRequest
+}
+
+type ResearchMaterial struct {
+	UniqueName string
+	Name       string
+	ImageName  string
+	Count      int
+}
+
+// ResearchItemCost is what researching one item takes at the requested
+// tier.
+type ResearchItemCost struct {
+	UniqueName string
+	Name       string
+	Lab        string
+	Credits    int
+	Materials  []ResearchMaterial
+}
+
+// ResearchLabCost totals the research costs of the items in one lab.
+type ResearchLabCost struct {
+	Lab       string
+	Items     []ResearchItemCost
+	Credits   int
+	Materials []ResearchMaterial
+}
+
+// ResearchCost totals research costs by lab and overall. NotFound lists
+// the requested uniqueNames missing from the item data.
+type ResearchCost struct {
+	Tier       string
+	Multiplier int
+	Labs       []ResearchLabCost
+	Credits    int
+	Materials  []ResearchMaterial
+	NotFound   []string
+}
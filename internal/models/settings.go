@@ -0,0 +1,137 @@
+package models
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const DefaultTimeZone = "UTC"
+
+// DigestTimeLayout is the time.Parse layout of UserSettings.DigestTime.
+const DigestTimeLayout = "15:04"
+
+// MaxDefaultQuantityRules bounds how many default quantity rules a user can
+// set.
+const MaxDefaultQuantityRules = 50
+
+// Materials rounding modes: how intermediates crafted in batches, such as
+// Forma or Detonite Injectors, are counted across a wishlist.
+const (
+	// MaterialsRoundingStrict rounds each build's crafts up on its own, so
+	// every wishlist item gets whole batches of its own. It is the default.
+	MaterialsRoundingStrict = "strict"
+	// MaterialsRoundingPooled shares the surplus of a batch with later
+	// builds, rounding up only the wishlist's total need.
+	MaterialsRoundingPooled = "pooled"
+)
+
+// MaterialsRoundingModes lists every materials rounding mode.
+var MaterialsRoundingModes = []string{MaterialsRoundingStrict, MaterialsRoundingPooled}
+
+type UserSettings struct {
+	ID                primitive.ObjectID    `json:"id,omitempty" bson:"_id,omitempty"`
+	UserID            string                `json:"userId" bson:"userId"`
+	TimeZone          string                `json:"timeZone" bson:"timeZone"`
+	DefaultQuantities []DefaultQuantityRule `json:"defaultQuantities,omitempty" bson:"defaultQuantities,omitempty"`
+	// PublicWishlist lets other signed-in users view the wishlist and claim
+	// its items as gifts.
+	PublicWishlist bool `json:"publicWishlist" bson:"publicWishlist,omitempty"`
+	// MutedMilestones lists the wishlist milestones not to notify.
+	MutedMilestones []string `json:"mutedMilestones,omitempty" bson:"mutedMilestones,omitempty"`
+	// MaterialsRounding is one of MaterialsRoundingModes; empty means strict.
+	MaterialsRounding string `json:"materialsRounding,omitempty" bson:"materialsRounding,omitempty"`
+	// SkipStarterWishlist leaves a new user's wishlist empty instead of
+	// seeding it with the server's starter items.
+	SkipStarterWishlist bool `json:"skipStarterWishlist" bson:"skipStarterWishlist,omitempty"`
+	// DigestTime is the local time of day, as DigestTimeLayout in TimeZone,
+	// at which the daily digest notification is sent; empty sends none.
+	DigestTime string    `json:"digestTime,omitempty" bson:"digestTime,omitempty"`
+	CreatedAt  time.Time `json:"createdAt" bson:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt" bson:"updatedAt"`
+}
+
+// DefaultQuantityRule is the quantity an item is added with when the request
+// omits one, for items of Category and, when set, of Type within it.
+type DefaultQuantityRule struct {
+	Category string `json:"category" bson:"category"`
+	Type     string `json:"type,omitempty" bson:"type,omitempty"`
+	Quantity int    `json:"quantity" bson:"quantity"`
+}
+
+// Matches reports whether the rule applies to item, ignoring case.
+func (r DefaultQuantityRule) Matches(item *Item) bool {
+	return strings.EqualFold(r.Category, item.Category) && (r.Type == "" || strings.EqualFold(r.Type, item.Type))
+}
+
+// DefaultQuantity returns the quantity item is added with when none is
+// requested: a rule for its category and type takes precedence over a rule
+// for its whole category, and without a matching rule it is 1.
+func (s *UserSettings) DefaultQuantity(item *Item) int {
+	if s == nil || item == nil {
+		return 1
+	}
+	quantity := 1
+	for _, rule := range s.DefaultQuantities {
+		if !rule.Matches(item) {
+			continue
+		}
+		if rule.Type != "" {
+			return rule.Quantity
+		}
+		quantity = rule.Quantity
+	}
+	return quantity
+}
+
+// MilestoneMuted reports whether the user turned off notifications of
+// milestone.
+func (s *UserSettings) MilestoneMuted(milestone string) bool {
+	return s != nil && slices.Contains(s.MutedMilestones, milestone)
+}
+
+// PoolsCraftSurplus reports whether the user shares the surplus of batch
+// crafts across their wishlist.
+func (s *UserSettings) PoolsCraftSurplus() bool {
+	return s != nil && s.MaterialsRounding == MaterialsRoundingPooled
+}
+
+// Location returns the user's configured time zone, falling back to UTC when the
+// stored value is empty or no longer valid.
+func (s *UserSettings) Location() *time.Location {
+	if s == nil || s.TimeZone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(s.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// DigestClock returns the hour and minute of the user's daily digest, and
+// false when they get none.
+func (s *UserSettings) DigestClock() (hour, minute int, ok bool) {
+	if s == nil || s.DigestTime == "" {
+		return 0, 0, false
+	}
+	t, err := time.Parse(DigestTimeLayout, s.DigestTime)
+	if err != nil {
+		return 0, 0, false
+	}
+	return t.Hour(), t.Minute(), true
+}
+
+// UpdateSettingsRequest is a partial update; nil fields are left unchanged.
+// DefaultQuantities replaces every rule.
+type UpdateSettingsRequest struct {
+	TimeZone            *string
+	DefaultQuantities   *[]DefaultQuantityRule
+	PublicWishlist      *bool
+	MutedMilestones     *[]string
+	MaterialsRounding   *string
+	SkipStarterWishlist *bool
+	DigestTime          *string
+}
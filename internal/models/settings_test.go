@@ -0,0 +1,31 @@
+package models
+
+import "testing"
+
+func TestUserSettings_DefaultQuantity(t *testing.T) {
+	settings := &UserSettings{DefaultQuantities: []DefaultQuantityRule{
+		{Category: "Gear", Quantity: 2},
+		{Category: "gear", Type: "specter", Quantity: 3},
+		{Category: "Warframes", Quantity: 1},
+	}}
+
+	tests := []struct {
+		name     string
+		settings *UserSettings
+		item     *Item
+		expected int
+	}{
+		{name: "type rule wins over category rule", settings: settings, item: &Item{Category: "Gear", Type: "Specter"}, expected: 3},
+		{name: "category rule", settings: settings, item: &Item{Category: "Gear", Type: "Gear"}, expected: 2},
+		{name: "no matching rule", settings: settings, item: &Item{Category: "Resources"}, expected: 1},
+		{name: "no settings", item: &Item{Category: "Gear", Type: "Specter"}, expected: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.settings.DefaultQuantity(tt.item); got != tt.expected {
+				t.Errorf("expected %d, got %d", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MaxShareLinksPerUser bounds how many share links a user can have at once.
+const MaxShareLinksPerUser = 20
+
+// ShareLink lets anyone holding Token view UserID's wishlist without signing
+// in. The Hide flags are enforced when the shared view is served, so links
+// with different flags can coexist for different audiences.
+type ShareLink struct {
+	ID          primitive.ObjectID   `json:"id,omitempty" bson:"_id,omitempty"`
+	UserID      string               `json:"userId" bson:"userId"`
+	Token       string               `json:"token" bson:"token"`
+	Label       string               `json:"label" bson:"label"`
+	Permissions ShareLinkPermissions `json:"permissions" bson:"permissions"`
+	CreatedAt   time.Time            `json:"createdAt" bson:"createdAt"`
+}
+
+// ShareLinkPermissions selects what a share link hides. Material totals are
+// derived from quantities, so HideQuantities implies HideMaterials.
+type ShareLinkPermissions struct {
+	HideQuantities bool `json:"hideQuantities" bson:"hideQuantities"`
+	// HideLinks hides each item's source links, the notes users keep on why
+	// an item is on the wishlist.
+	HideLinks     bool `json:"hideLinks" bson:"hideLinks"`
+	HideMaterials bool `json:"hideMaterials" bson:"hideMaterials"`
+}
+
+type CreateShareLinkRequest struct {
+	Label       string
+	Permissions ShareLinkPermissions
+}
+
+// ShareLinkView is a wishlist as served through a share link. Hidden
+// quantities are nil and hidden links are nil rather than empty.
+type ShareLinkView struct {
+	Permissions ShareLinkPermissions
+	Items       []ShareLinkViewItem
+	UpdatedAt   time.Time
+}
+
+type ShareLinkViewItem struct {
+	UniqueName string
+	Quantity   *int
+	AddedAt    time.Time
+	Links      []SourceLink
+}
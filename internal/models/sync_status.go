@@ -0,0 +1,65 @@
+package models
+
+import "time"
+
+// SyncStatusItems is the name of the item data sync's status document.
+const SyncStatusItems = "items"
+
+// ItemSyncStats reports what an item sync did to a collection, or would do in
+// a dry run. A dry run cannot tell updated items from unchanged ones and
+// counts every existing item as updated.
+type ItemSyncStats struct {
+	Inserted  int `json:"inserted" bson:"inserted"`
+	Updated   int `json:"updated" bson:"updated"`
+	Unchanged int `json:"unchanged" bson:"unchanged"`
+	Archived  int `json:"archived" bson:"archived"`
+}
+
+// Add accumulates other into s.
+func (s *ItemSyncStats) Add(other ItemSyncStats) {
+	s.Inserted += other.Inserted
+	s.Updated += other.Updated
+	s.Unchanged += other.Unchanged
+	s.Archived += other.Archived
+}
+
+// Changed reports whether the sync inserted, updated or archived any item.
+func (s ItemSyncStats) Changed() bool {
+	return s.Inserted > 0 || s.Updated > 0 || s.Archived > 0
+}
+
+// SyncStatus records the most recent run of a background sync, keyed by
+// Name. LastSuccessAt and Version carry over from earlier runs when a run
+// fails.
+type SyncStatus struct {
+	Name           string    `json:"name" bson:"_id"`
+	Source         string    `json:"source" bson:"source"`
+	LastStartedAt  time.Time `json:"lastStartedAt" bson:"lastStartedAt"`
+	LastFinishedAt time.Time `json:"lastFinishedAt" bson:"lastFinishedAt"`
+	// LastSuccessAt is when a run last completed without errors; zero if
+	// none has.
+	LastSuccessAt time.Time `json:"lastSuccessAt" bson:"lastSuccessAt"`
+	// LastError is the error of the most recent run, empty if it succeeded.
+	LastError string `json:"lastError" bson:"lastError"`
+	// FailedCollections lists the collections the most recent run could not
+	// sync.
+	FailedCollections []string `json:"failedCollections" bson:"failedCollections"`
+	// Version is the data version reported after the last run that changed
+	// the item data.
+	Version    string        `json:"version" bson:"version"`
+	Stats      ItemSyncStats `json:"stats" bson:"stats"`
+	DurationMs int64         `json:"durationMs" bson:"durationMs"`
+}
+
+// ItemRefreshStatus is the state of the scheduled item refresh worker along
+// with its last recorded run.
+type ItemRefreshStatus struct {
+	// Enabled reports whether this instance runs the worker.
+	Enabled  bool
+	Running  bool
+	Interval time.Duration
+	// NextRunAt is zero when the worker is disabled or running.
+	NextRunAt time.Time
+	// Last is nil until a run has been recorded.
+	Last *SyncStatus
+}
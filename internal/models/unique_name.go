@@ -0,0 +1,50 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxUniqueNameLength bounds accepted uniqueNames; the longest in the WFCD
+// dataset is well under 200 bytes.
+const MaxUniqueNameLength = 512
+
+var (
+	ErrUniqueNameRequired = errors.New("uniqueName is required")
+	ErrInvalidUniqueName  = errors.New("invalid uniqueName")
+)
+
+// CanonicalUniqueName normalizes a uniqueName taken from a URL path to the
+// form stored in the database: exactly one leading slash, no trailing slash.
+// Names with empty, "." or ".." segments, control characters or invalid UTF-8
+// are rejected. The input must already be unescaped.
+func CanonicalUniqueName(raw string) (string, error) {
+	trimmed := strings.Trim(raw, "/")
+	if trimmed == "" {
+		return "", ErrUniqueNameRequired
+	}
+
+	if !utf8.ValidString(trimmed) {
+		return "", ErrInvalidUniqueName
+	}
+
+	for _, segment := range strings.Split(trimmed, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return "", ErrInvalidUniqueName
+		}
+	}
+
+	for _, r := range trimmed {
+		if unicode.IsControl(r) {
+			return "", ErrInvalidUniqueName
+		}
+	}
+
+	canonical := "/" + trimmed
+	if len(canonical) > MaxUniqueNameLength {
+		return "", ErrInvalidUniqueName
+	}
+	return canonical, nil
+}
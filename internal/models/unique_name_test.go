@@ -0,0 +1,93 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestCanonicalUniqueName(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		expected string
+		err      error
+	}{
+		{name: "without leading slash", raw: "Lotus/Powersuits/Excalibur", expected: "/Lotus/Powersuits/Excalibur"},
+		{name: "with leading slash", raw: "/Lotus/Powersuits/Excalibur", expected: "/Lotus/Powersuits/Excalibur"},
+		{name: "repeated leading slashes", raw: "///Lotus/A", expected: "/Lotus/A"},
+		{name: "trailing slash", raw: "Lotus/A/", expected: "/Lotus/A"},
+		{name: "dots inside segment", raw: "Lotus/Weapons/Tenno/Bows/Bow.v2", expected: "/Lotus/Weapons/Tenno/Bows/Bow.v2"},
+		{name: "unicode", raw: "Lotus/Ünïcödé/名前", expected: "/Lotus/Ünïcödé/名前"},
+		{name: "empty", raw: "", err: ErrUniqueNameRequired},
+		{name: "only slashes", raw: "//", err: ErrUniqueNameRequired},
+		{name: "empty segment", raw: "Lotus//A", err: ErrInvalidUniqueName},
+		{name: "dot segment", raw: "Lotus/./A", err: ErrInvalidUniqueName},
+		{name: "parent segment", raw: "Lotus/../A", err: ErrInvalidUniqueName},
+		{name: "control character", raw: "Lotus/A\x00B", err: ErrInvalidUniqueName},
+		{name: "newline", raw: "Lotus/A\nB", err: ErrInvalidUniqueName},
+		{name: "invalid utf8", raw: "Lotus/\xff", err: ErrInvalidUniqueName},
+		{name: "too long", raw: strings.Repeat("a", MaxUniqueNameLength), err: ErrInvalidUniqueName},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CanonicalUniqueName(tt.raw)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("expected error %v, got %v (result '%s')", tt.err, err, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, got)
+			}
+		})
+	}
+}
+
+func FuzzCanonicalUniqueName(f *testing.F) {
+	seeds := []string{
+		"Lotus/Powersuits/Excalibur/Excalibur",
+		"/Lotus/Types/Recipes/WarframeRecipes/ExcaliburBlueprint",
+		"//Lotus//A",
+		"Lotus/../A",
+		"Lotus/./A/",
+		"Lotus/Ünïcödé",
+		"%2FLotus%2FA",
+		"\xff\xfe",
+		"",
+	}
+	for _, s := range seeds {
+		f.Add(s)
+	}
+
+	f.Fuzz(func(t *testing.T, raw string) {
+		got, err := CanonicalUniqueName(raw)
+		if err != nil {
+			if !errors.Is(err, ErrUniqueNameRequired) && !errors.Is(err, ErrInvalidUniqueName) {
+				t.Fatalf("unexpected error type: %v", err)
+			}
+			return
+		}
+
+		if !strings.HasPrefix(got, "/") || strings.HasSuffix(got, "/") {
+			t.Fatalf("expected exactly one leading and no trailing slash, got '%s'", got)
+		}
+		if strings.Contains(got, "//") || strings.Contains(got+"/", "/./") || strings.Contains(got+"/", "/../") {
+			t.Fatalf("expected no empty or dot segments, got '%s'", got)
+		}
+		if !utf8.ValidString(got) || len(got) > MaxUniqueNameLength {
+			t.Fatalf("expected valid bounded UTF-8, got %q", got)
+		}
+
+		again, err := CanonicalUniqueName(got)
+		if err != nil || again != got {
+			t.Fatalf("expected canonicalization to be idempotent: '%s' -> '%s' (%v)", got, again, err)
+		}
+	})
+}
@@ -0,0 +1,34 @@
+package models
+
+// Resources reported by the usage endpoint. The Bytes resources are the
+// approximate sizes of documents stored whole, against MaxDocumentBytes.
+const (
+	UsageWishlistItems        = "wishlistItems"
+	UsageWishlistBytes        = "wishlistBytes"
+	UsageOwnedBlueprints      = "ownedBlueprints"
+	UsageOwnedPartsBytes      = "ownedPartsBytes"
+	UsageCustomItems          = "customItems"
+	UsageFoundryBuilds        = "foundryBuilds"
+	UsageShareLinks           = "shareLinks"
+	UsageNotificationChannels = "notificationChannels"
+	UsageWorkspaces           = "workspaces"
+	UsageActivityEntries      = "activityEntries"
+)
+
+// MaxDocumentBytes is MongoDB's size limit for one document.
+const MaxDocumentBytes = 16 * 1024 * 1024
+
+// ResourceUsage is how much of one resource a user has used. Limit is 0 when
+// the resource is uncapped; Warning is set once Used nears Limit.
+type ResourceUsage struct {
+	Resource string `json:"resource"`
+	Used     int    `json:"used"`
+	Limit    int    `json:"limit"`
+	Warning  bool   `json:"warning"`
+}
+
+// UserUsage is a user's usage of every reported resource.
+type UserUsage struct {
+	UserID    string          `json:"userId"`
+	Resources []ResourceUsage `json:"resources"`
+}
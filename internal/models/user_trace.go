@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// UserTrace enables verbose logging for one user's requests until Until. It
+// is set by support staff to debug user-specific data issues.
+type UserTrace struct {
+	UserID    string    `json:"userId" bson:"userId"`
+	Until     time.Time `json:"until" bson:"until"`
+	Reason    string    `json:"reason" bson:"reason"`
+	CreatedAt time.Time `json:"createdAt" bson:"createdAt"`
+}
+
+// Active reports whether the trace still applies at now.
+func (t *UserTrace) Active(now time.Time) bool {
+	return t != nil && now.Before(t.Until)
+}
+
+type EnableUserTraceRequest struct {
+	// DurationMinutes is how long the trace lasts; 0 selects the default.
+	DurationMinutes int
+	Reason          string
+}
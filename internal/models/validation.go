@@ -0,0 +1,10 @@
+package models
+
+// ValidationProblem is one thing wrong with a request: the field at fault,
+// the uniqueName of the entry when the field is part of a list, and why it
+// was rejected.
+type ValidationProblem struct {
+	Field      string
+	UniqueName string
+	Reason     string
+}
@@ -7,37 +7,122 @@ import (
 )
 
 type WishlistItem struct {
-	UniqueName string    `json:"uniqueName" bson:"uniqueName"`
-	Quantity   int       `json:"quantity" bson:"quantity"`
-	AddedAt    time.Time `json:"addedAt" bson:"addedAt"`
+	UniqueName string       `json:"uniqueName" bson:"uniqueName"`
+	Quantity   int          `json:"quantity" bson:"quantity"`
+	AddedAt    time.Time    `json:"addedAt" bson:"addedAt"`
+	Links      []SourceLink `json:"links,omitempty" bson:"links,omitempty"`
+	// RecipeID selects one of the item's alternate recipes for material
+	// resolution; empty means the default recipe.
+	RecipeID string `json:"recipeId,omitempty" bson:"recipeId,omitempty"`
+	// Progress records the item's components the user already has;
+	// MaterialResolver leaves them out of the totals.
+	Progress []ComponentProgress `json:"progress,omitempty" bson:"progress,omitempty"`
+	// Completion is the percentage of the item's components done. It is
+	// derived from Progress when the wishlist is read and never stored.
+	Completion int `json:"completion" bson:"-"`
+}
+
+// Component progress statuses: built means the user crafted the component,
+// acquired that they got it some other way, such as a trade or a drop.
+const (
+	ComponentBuilt    = "built"
+	ComponentAcquired = "acquired"
+)
+
+// ComponentProgress marks Count of one of a wishlist item's components, as
+// listed by its recipe, as done across all copies of the item.
+type ComponentProgress struct {
+	UniqueName string `json:"uniqueName" bson:"uniqueName"`
+	Status     string `json:"status" bson:"status"`
+	Count      int    `json:"count" bson:"count"`
+}
+
+// SourceLink is a reference (build guide, video) explaining why an item is on
+// the wishlist. URL is normalized and validated on save; Host is derived from
+// it so clients can show where a link goes without parsing it themselves.
+type SourceLink struct {
+	URL   string `json:"url" bson:"url"`
+	Title string `json:"title,omitempty" bson:"title,omitempty"`
+	Host  string `json:"host" bson:"host"`
 }
 
 type Wishlist struct {
-	ID        primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
-	UserID    string             `json:"userId" bson:"userId"`
-	Items     []WishlistItem     `json:"items" bson:"items"`
-	CreatedAt time.Time          `json:"createdAt" bson:"createdAt"`
-	UpdatedAt time.Time          `json:"updatedAt" bson:"updatedAt"`
+	ID            primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	UserID        string             `json:"userId" bson:"userId"`
+	Items         []WishlistItem     `json:"items" bson:"items"`
+	SchemaVersion int                `json:"-" bson:"schemaVersion"`
+	CreatedAt     time.Time          `json:"createdAt" bson:"createdAt"`
+	UpdatedAt     time.Time          `json:"updatedAt" bson:"updatedAt"`
 }
 
 type AddItemRequest struct {
-	UniqueName string `json:"uniqueName"`
-	Quantity   int    `json:"quantity,omitempty"`
+	UniqueName string
+	Quantity   int
+	// AllowOwned adds the item even when the user's mastery profile says
+	// they already have it.
+	AllowOwned bool
+}
+
+type SourceLinkRequest struct {
+	URL   string
+	Title string
+}
+
+// ComponentProgressRequest marks a component done; a zero Count means every
+// copy the wishlist item needs.
+type ComponentProgressRequest struct {
+	UniqueName string
+	Status     string
+	Count      int
 }
 
-type UpdateQuantityRequest struct {
-	Quantity int `json:"quantity"`
+// ExpandedWishlistItem is a wishlist item with its item summary attached.
+// Item is nil when the item no longer exists in the game data.
+type ExpandedWishlistItem struct {
+	WishlistItem
+	Item *ItemSearchResult `json:"item"`
 }
 
+// ExpandedWishlist is the wishlist view with item summaries and source links.
+type ExpandedWishlist struct {
+	UserID    string                 `json:"userId"`
+	Items     []ExpandedWishlistItem `json:"items"`
+	CreatedAt time.Time              `json:"createdAt"`
+	UpdatedAt time.Time              `json:"updatedAt"`
+}
+
+// MaterialRequirement is one aggregated material. Owned comes from the user's
+// material inventory and Remaining is TotalCount minus Owned, never negative.
 type MaterialRequirement struct {
 	UniqueName  string `json:"uniqueName"`
 	Name        string `json:"name"`
 	TotalCount  int    `json:"totalCount"`
+	Owned       int    `json:"owned"`
+	Remaining   int    `json:"remaining"`
 	ImageName   string `json:"imageName,omitempty"`
 	Description string `json:"description,omitempty"`
 }
 
+// Limits that can stop a materials resolution early, reported as
+// MaterialsResponse.TruncatedReason.
+const (
+	TruncatedDepth     = "depth"
+	TruncatedMaterials = "materials"
+	TruncatedTime      = "time"
+)
+
+// MaterialsResponse aggregates the materials for a wishlist. TotalCredits and
+// RushPlatinum are the credits to start and platinum to rush every build the
+// wishlist still needs, intermediate components included. Truncated is set
+// when a resolver safety limit stopped the resolution early, in which case the
+// materials and totals are a partial result and TruncatedReason names the
+// first limit hit. Extensions holds what extension hooks added, by hook name.
 type MaterialsResponse struct {
-	Materials    []MaterialRequirement `json:"materials"`
-	TotalCredits int                   `json:"totalCredits"`
+	Materials       []MaterialRequirement `json:"materials"`
+	TotalCredits    int                   `json:"totalCredits"`
+	RushPlatinum    int                   `json:"rushPlatinum"`
+	Truncated       bool                  `json:"truncated,omitempty"`
+	TruncatedReason string                `json:"truncatedReason,omitempty"`
+	Extensions      map[string]any        `json:"extensions,omitempty" bson:"-"`
+	Degradation     `bson:"-"`
 }
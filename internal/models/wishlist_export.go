@@ -0,0 +1,87 @@
+package models
+
+import "time"
+
+// WishlistExportFormat and WishlistExportVersion identify an export document.
+// The version is bumped whenever a field changes meaning, so an older server
+// can refuse a document it would misread.
+const (
+	WishlistExportFormat  = "warframe-wishlist"
+	WishlistExportVersion = 1
+)
+
+// How an imported document is applied. Merge adds and updates the document's
+// items and leaves everything else alone; replace also removes the items and
+// owned blueprints the document does not list.
+const (
+	ImportModeMerge   = "merge"
+	ImportModeReplace = "replace"
+)
+
+// Outcomes of a document import besides the ImportStatus values of a text
+// import.
+const (
+	ImportStatusUpdated   = "updated"
+	ImportStatusUnchanged = "unchanged"
+	ImportStatusRemoved   = "removed"
+)
+
+// WishlistExport is a portable copy of a user's wishlist and owned
+// blueprints. It holds only what the user chose, keyed by uniqueName, so it
+// can be imported into another account or server.
+type WishlistExport struct {
+	Format          string
+	Version         int
+	ExportedAt      time.Time
+	Items           []WishlistExportItem
+	OwnedBlueprints []string
+}
+
+type WishlistExportItem struct {
+	UniqueName string
+	Quantity   int
+	RecipeID   string
+	Links      []SourceLinkRequest
+}
+
+// WishlistDocumentImportRequest applies Document in Mode. With DryRun set
+// nothing is written and the result reports what would change.
+type WishlistDocumentImportRequest struct {
+	Mode     string
+	DryRun   bool
+	Document WishlistExport
+}
+
+// BlueprintImportResult reports what happened to one owned blueprint.
+type BlueprintImportResult struct {
+	UniqueName string
+	Status     string
+}
+
+// WishlistDocumentImportResult lists every item and blueprint the document
+// named, plus those removed in replace mode, in document order followed by
+// removals.
+type WishlistDocumentImportResult struct {
+	Mode       string
+	DryRun     bool
+	Items      []ImportItemResult
+	Blueprints []BlueprintImportResult
+}
+
+// WishlistChecklist is the printable view of a wishlist: each item with its
+// name and quantity, and the materials still to collect, ordered by name.
+// MaterialsTruncated is set when the materials are a partial result.
+type WishlistChecklist struct {
+	GeneratedAt        time.Time
+	Items              []ChecklistItem
+	Materials          []MaterialRequirement
+	MaterialsTruncated bool
+}
+
+// ChecklistItem is a wishlist item on a checklist. Name falls back to the
+// uniqueName for items no longer in the item data.
+type ChecklistItem struct {
+	UniqueName string
+	Name       string
+	Quantity   int
+}
@@ -0,0 +1,74 @@
+package models
+
+// How an import line was resolved to an item.
+const (
+	ImportMatchExact = "exact"
+	ImportMatchAlias = "alias"
+	ImportMatchFuzzy = "fuzzy"
+)
+
+// Outcome of confirming one imported item.
+const (
+	ImportStatusAdded             = "added"
+	ImportStatusAlreadyInWishlist = "alreadyInWishlist"
+	ImportStatusPendingApproval   = "pendingApproval"
+	ImportStatusNotFound          = "notFound"
+	ImportStatusInvalid           = "invalid"
+)
+
+// ImportCandidate is an item a pasted line may refer to.
+type ImportCandidate struct {
+	UniqueName string
+	Name       string
+	ImageName  string
+}
+
+// ImportMatch is a line that resolved to a single item. Line is 1-based and
+// Input is the item name as written, without bullets or quantity.
+type ImportMatch struct {
+	Line      int
+	Input     string
+	Quantity  int
+	MatchType string
+	Item      ImportCandidate
+}
+
+// ImportAmbiguous is a line that resolved to several equally likely items.
+type ImportAmbiguous struct {
+	Line       int
+	Input      string
+	Quantity   int
+	Candidates []ImportCandidate
+}
+
+type ImportUnmatched struct {
+	Line  int
+	Input string
+}
+
+// ImportPreview is the result of resolving pasted text. Nothing is written
+// until the chosen items are confirmed.
+type ImportPreview struct {
+	Matched   []ImportMatch
+	Ambiguous []ImportAmbiguous
+	Unmatched []ImportUnmatched
+}
+
+// ImportConfirmRequest lists the items to add, typically the matched items of
+// a preview plus the candidates picked for ambiguous lines.
+type ImportConfirmRequest struct {
+	Items []AddItemRequest
+}
+
+// ImportItemResult reports what happened to one confirmed item.
+// PendingChange is set when the status is ImportStatusPendingApproval.
+type ImportItemResult struct {
+	UniqueName    string
+	Quantity      int
+	Status        string
+	PendingChange *PendingChange
+}
+
+type ImportConfirmResult struct {
+	Results []ImportItemResult
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// WishlistMergeRequest is a client's edit of a wishlist made while it may
+// have changed elsewhere. Base is the items as the client last saw them, at
+// BaseRevision, the wishlist's UpdatedAt then, and Items what the client
+// wants them to be. When BaseRevision is still the wishlist's UpdatedAt,
+// Items are applied as they are and Base is not needed.
+type WishlistMergeRequest struct {
+	BaseRevision time.Time
+	Base         []WishlistMergeItem
+	Items        []WishlistMergeItem
+}
+
+type WishlistMergeItem struct {
+	UniqueName string
+	Quantity   int
+}
+
+// WishlistMergeResult reports a three-way merge of a WishlistMergeRequest
+// with the wishlist's changes since its base. Items has an entry, with an
+// ImportStatus, for each item the request named; Conflicts lists those the
+// client and the wishlist both changed, in the order of Items. Wishlist is
+// the merged wishlist, whose UpdatedAt is the revision to merge against
+// next.
+type WishlistMergeResult struct {
+	Items     []ImportItemResult
+	Conflicts []string
+	Wishlist  *Wishlist
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// MaxWishlistTemplates bounds how many templates a user can keep.
+	MaxWishlistTemplates = 50
+	// MaxTemplateItems bounds the items of one template.
+	MaxTemplateItems = 100
+	// MaxTemplateVariables bounds the variables of one template.
+	MaxTemplateVariables = 10
+	// MaxTemplateQuantity bounds both a variable's value and the quantity an
+	// item expands to.
+	MaxTemplateQuantity = 9999
+)
+
+// WishlistTemplate is a reusable set of wishlist items, such as "forma for a
+// new frame". Item quantities may refer to the template's variables, whose
+// values are asked for each time the template is applied.
+type WishlistTemplate struct {
+	ID        primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	UserID    string             `json:"userId" bson:"userId"`
+	Name      string             `json:"name" bson:"name"`
+	Variables []TemplateVariable `json:"variables" bson:"variables"`
+	Items     []TemplateItem     `json:"items" bson:"items"`
+	CreatedAt time.Time          `json:"createdAt" bson:"createdAt"`
+}
+
+// TemplateVariable is a value a template asks for when applied. Its value
+// must lie between Min and Max; a nil Default makes it required.
+type TemplateVariable struct {
+	Name    string `json:"name" bson:"name"`
+	Label   string `json:"label,omitempty" bson:"label,omitempty"`
+	Default *int   `json:"default,omitempty" bson:"default,omitempty"`
+	Min     int    `json:"min" bson:"min"`
+	Max     int    `json:"max" bson:"max"`
+}
+
+// TemplateItem is an item a template adds. Quantity is an expression: a
+// product of whole numbers and variable names, e.g. "2*forma".
+type TemplateItem struct {
+	UniqueName string `json:"uniqueName" bson:"uniqueName"`
+	Quantity   string `json:"quantity" bson:"quantity"`
+}
+
+// WishlistTemplateRequest creates a template. A variable's Max of 0 means
+// MaxTemplateQuantity, and an item's empty Quantity means "1".
+type WishlistTemplateRequest struct {
+	Name      string
+	Variables []TemplateVariable
+	Items     []TemplateItem
+}
@@ -0,0 +1,206 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Workspace member roles. Admins manage the workspace and its members,
+// editors change its items, and viewers only follow along. Every member can
+// record their own contributions.
+const (
+	WorkspaceRoleAdmin  = "admin"
+	WorkspaceRoleEditor = "editor"
+	WorkspaceRoleViewer = "viewer"
+)
+
+const (
+	// MaxWorkspacesPerUser bounds how many workspaces a user can belong to.
+	MaxWorkspacesPerUser = 20
+	// MaxWorkspaceMembers bounds the members of one workspace.
+	MaxWorkspaceMembers = 50
+	// MaxWorkspaceItems bounds the items of one workspace.
+	MaxWorkspaceItems = 500
+)
+
+// Workspace is a wishlist shared by a clan or team, such as their dojo
+// research goals. Version is bumped on every write so that concurrent edits
+// by different members never overwrite each other.
+type Workspace struct {
+	ID          primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	Name        string             `json:"name" bson:"name"`
+	Description string             `json:"description" bson:"description"`
+	Members     []WorkspaceMember  `json:"members" bson:"members"`
+	Items       []WorkspaceItem    `json:"items" bson:"items"`
+	Version     int64              `json:"version" bson:"version"`
+	CreatedAt   time.Time          `json:"createdAt" bson:"createdAt"`
+	UpdatedAt   time.Time          `json:"updatedAt" bson:"updatedAt"`
+}
+
+type WorkspaceMember struct {
+	UserID  string    `json:"userId" bson:"userId"`
+	Role    string    `json:"role" bson:"role"`
+	AddedAt time.Time `json:"addedAt" bson:"addedAt"`
+}
+
+// WorkspaceItem is an item the workspace needs Quantity of. Contributions
+// record how many each member has already delivered.
+type WorkspaceItem struct {
+	UniqueName    string                  `json:"uniqueName" bson:"uniqueName"`
+	Quantity      int                     `json:"quantity" bson:"quantity"`
+	AddedBy       string                  `json:"addedBy" bson:"addedBy"`
+	AddedAt       time.Time               `json:"addedAt" bson:"addedAt"`
+	Contributions []WorkspaceContribution `json:"contributions" bson:"contributions"`
+}
+
+type WorkspaceContribution struct {
+	UserID    string    `json:"userId" bson:"userId"`
+	Quantity  int       `json:"quantity" bson:"quantity"`
+	UpdatedAt time.Time `json:"updatedAt" bson:"updatedAt"`
+}
+
+// Kinds of workspace contribution: a material logged toward the workspace's
+// material goals, or a change to a member's contribution to one of its items.
+const (
+	ContributionKindMaterial = "material"
+	ContributionKindItem     = "item"
+)
+
+// WorkspaceContributionEvent is one entry of a workspace's activity feed.
+// Quantity is the change the member made, negative when they corrected an
+// earlier contribution. Material events also make up the material
+// contribution totals.
+type WorkspaceContributionEvent struct {
+	ID          primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	WorkspaceID primitive.ObjectID `json:"workspaceId" bson:"workspaceId"`
+	UserID      string             `json:"userId" bson:"userId"`
+	Kind        string             `json:"kind" bson:"kind"`
+	UniqueName  string             `json:"uniqueName" bson:"uniqueName"`
+	Quantity    int                `json:"quantity" bson:"quantity"`
+	CreatedAt   time.Time          `json:"createdAt" bson:"createdAt"`
+}
+
+// WorkspaceContributionTotal is how much of a material one member has
+// contributed in all.
+type WorkspaceContributionTotal struct {
+	UniqueName string `json:"uniqueName" bson:"uniqueName"`
+	UserID     string `json:"userId" bson:"userId"`
+	Quantity   int    `json:"quantity" bson:"quantity"`
+}
+
+// MemberContribution is a member's share of a contribution total.
+type MemberContribution struct {
+	UserID   string `json:"userId"`
+	Quantity int    `json:"quantity"`
+}
+
+// WorkspaceMaterial is a material the workspace needs with what its members
+// have contributed toward it. Remaining has Contributed taken off too, never
+// going below zero.
+type WorkspaceMaterial struct {
+	MaterialRequirement
+	Contributed   int                  `json:"contributed"`
+	Contributions []MemberContribution `json:"contributions"`
+}
+
+// WorkspaceMaterials is what a workspace's items still need, with the
+// material contributions taken off. Members holds each member's material
+// contributions in all, including those to materials no longer needed.
+type WorkspaceMaterials struct {
+	Materials       []WorkspaceMaterial  `json:"materials"`
+	Members         []MemberContribution `json:"members"`
+	TotalCredits    int                  `json:"totalCredits"`
+	RushPlatinum    int                  `json:"rushPlatinum"`
+	Truncated       bool                 `json:"truncated,omitempty"`
+	TruncatedReason string               `json:"truncatedReason,omitempty"`
+	Degradation     `bson:"-"`
+}
+
+// Kinds of live workspace event. Item events carry the item as it now
+// stands (only its uniqueName when removed); a workspace_updated event means
+// the name, description or members changed and the workspace should be
+// refetched.
+const (
+	WorkspaceEventItemAdded     = "item_added"
+	WorkspaceEventItemUpdated   = "item_updated"
+	WorkspaceEventItemRemoved   = "item_removed"
+	WorkspaceEventUpdated       = "workspace_updated"
+	WorkspaceEventMemberRemoved = "member_removed"
+	WorkspaceEventDeleted       = "workspace_deleted"
+)
+
+// WorkspaceEvent is a change to a workspace, sent live to the members
+// viewing it. UserID made the change; Version is the workspace's after it.
+type WorkspaceEvent struct {
+	Type       string
+	UserID     string
+	UniqueName string
+	Item       *WorkspaceItem
+	MemberID   string
+	Version    int64
+	OccurredAt time.Time
+}
+
+// Member returns userID's membership, or nil if userID is not a member.
+func (w *Workspace) Member(userID string) *WorkspaceMember {
+	for i := range w.Members {
+		if w.Members[i].UserID == userID {
+			return &w.Members[i]
+		}
+	}
+	return nil
+}
+
+// Item returns the item with uniqueName, or nil.
+func (w *Workspace) Item(uniqueName string) *WorkspaceItem {
+	for i := range w.Items {
+		if w.Items[i].UniqueName == uniqueName {
+			return &w.Items[i]
+		}
+	}
+	return nil
+}
+
+// Contributed is the total quantity the members have delivered.
+func (i *WorkspaceItem) Contributed() int {
+	total := 0
+	for _, c := range i.Contributions {
+		total += c.Quantity
+	}
+	return total
+}
+
+// Remaining is the quantity still needed, never negative.
+func (i *WorkspaceItem) Remaining() int {
+	return max(i.Quantity-i.Contributed(), 0)
+}
+
+// IsWorkspaceRole reports whether role is one of the member roles.
+func IsWorkspaceRole(role string) bool {
+	switch role {
+	case WorkspaceRoleAdmin, WorkspaceRoleEditor, WorkspaceRoleViewer:
+		return true
+	}
+	return false
+}
+
+type WorkspaceRequest struct {
+	Name        string
+	Description string
+}
+
+type WorkspaceMemberRequest struct {
+	UserID string
+	Role   string
+}
+
+type WorkspaceMaterialContributionRequest struct {
+	UniqueName string
+	Quantity   int
+}
+
+type WorkspaceItemRequest struct {
+	UniqueName string
+	Quantity   int
+}
@@ -0,0 +1,69 @@
+package models
+
+import "time"
+
+// Kinds of world state event.
+const (
+	WorldStateAlert    = "alert"
+	WorldStateInvasion = "invasion"
+	WorldStateFissure  = "fissure"
+)
+
+// WorldStateReward is an item an event rewards, by the uniqueName the item
+// data uses.
+type WorldStateReward struct {
+	UniqueName string
+	Count      int
+}
+
+// WorldStateEvent is an active alert, invasion or void fissure. Node is the
+// star chart node's uniqueName. RelicTier is set for fissures, which reward
+// whatever relic is opened rather than fixed items. Invasions have no
+// Expiry.
+type WorldStateEvent struct {
+	ID          string
+	Kind        string
+	Node        string
+	MissionType string
+	RelicTier   string
+	SteelPath   bool
+	Rewards     []WorldStateReward
+	Activation  time.Time
+	Expiry      time.Time
+}
+
+// WorldState is the game's world state as of FetchedAt.
+type WorldState struct {
+	Events    []WorldStateEvent
+	FetchedAt time.Time
+}
+
+// OpportunityMaterial is an event reward the wishlist still needs.
+type OpportunityMaterial struct {
+	UniqueName string
+	Name       string
+	Count      int
+	Remaining  int
+}
+
+// Opportunity is an active event worth running for the wishlist: it rewards
+// outstanding materials, or needed relics, or is a fissure of a tier the
+// wishlist needs relics from. Relics names those relics.
+type Opportunity struct {
+	WorldStateEvent
+	NodeName  string
+	Materials []OpportunityMaterial
+	Relics    []string
+}
+
+// Opportunities lists the active events rewarding what a wishlist needs,
+// from the world state fetched at FetchedAt (zero before the first fetch).
+// Truncated, TruncatedReason and Degradation carry over from the materials
+// resolution.
+type Opportunities struct {
+	Opportunities   []Opportunity
+	FetchedAt       time.Time
+	Truncated       bool
+	TruncatedReason string
+	Degradation
+}
@@ -0,0 +1,225 @@
+package notify
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+func TestSendWebhook(t *testing.T) {
+	var gotBody []byte
+	var gotHeader http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = io.ReadAll(r.Body)
+		gotHeader = r.Header
+		if r.URL.Path == "/gone" {
+			http.Error(w, "unsubscribed", http.StatusGone)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(5*time.Second, true)
+	body := []byte(`{"event":"foundry.finished"}`)
+	status, err := SendWebhook(context.Background(), client, server.URL+"/hook", "secret", "foundry.finished", "delivery-1", body)
+	if err != nil || status != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d (err %v)", status, err)
+	}
+	if string(gotBody) != string(body) || gotHeader.Get(EventHeader) != "foundry.finished" || gotHeader.Get(DeliveryHeader) != "delivery-1" {
+		t.Errorf("unexpected request %s %v", gotBody, gotHeader)
+	}
+	// The expected signature is HMAC-SHA256("secret", body), as a receiver
+	// would compute it.
+	if gotHeader.Get(SignatureHeader) != Sign("secret", body) || !strings.HasPrefix(Sign("secret", body), "sha256=") || Sign("other", body) == Sign("secret", body) {
+		t.Errorf("unexpected signature %q", gotHeader.Get(SignatureHeader))
+	}
+
+	status, err = SendWebhook(context.Background(), client, server.URL+"/gone", "secret", "foundry.finished", "delivery-1", body)
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || status != http.StatusGone || !statusErr.Permanent() || !strings.Contains(err.Error(), "unsubscribed") {
+		t.Errorf("expected a permanent status error, got %d (err %v)", status, err)
+	}
+}
+
+func TestStatusError_Permanent(t *testing.T) {
+	for code, want := range map[int]bool{400: true, 404: true, 410: true, 408: false, 429: false, 500: false, 503: false} {
+		if got := (&StatusError{StatusCode: code}).Permanent(); got != want {
+			t.Errorf("Permanent() for %d = %v, want %v", code, got, want)
+		}
+	}
+}
+
+func TestNewHTTPClient_RefusesPrivateAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	_, err := SendWebhook(context.Background(), NewHTTPClient(5*time.Second, false), server.URL, "secret", "e", "d", []byte("{}"))
+	if !errors.Is(err, ErrPrivateAddress) {
+		t.Errorf("expected loopback to be refused, got %v", err)
+	}
+}
+
+// newSubscription returns a browser's push subscription keys and its
+// private key, to decrypt what was sent to it.
+func newSubscription(t *testing.T) (models.PushKeys, *ecdh.PrivateKey, []byte) {
+	t.Helper()
+	private, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth := make([]byte, 16)
+	rand.Read(auth)
+	keys := models.PushKeys{
+		P256dh: base64.RawURLEncoding.EncodeToString(private.PublicKey().Bytes()),
+		Auth:   base64.URLEncoding.EncodeToString(auth),
+	}
+	return keys, private, auth
+}
+
+// decrypt reverses encrypt as a browser would.
+func decrypt(t *testing.T, body []byte, uaPrivate *ecdh.PrivateKey, authSecret []byte) []byte {
+	t.Helper()
+	salt, rs, idLen := body[:16], binary.BigEndian.Uint32(body[16:20]), int(body[20])
+	if rs != recordSize || idLen != 65 {
+		t.Fatalf("unexpected header rs=%d idlen=%d", rs, idLen)
+	}
+	asPublicBytes := body[21 : 21+idLen]
+	asPublic, err := ecdh.P256().NewPublicKey(asPublicBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret, err := uaPrivate.ECDH(asPublic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cek, nonce, err := deriveContentKeys(secret, authSecret, uaPrivate.PublicKey().Bytes(), asPublicBytes, salt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, _ := aes.NewCipher(cek)
+	gcm, _ := cipher.NewGCM(block)
+	plaintext, err := gcm.Open(nil, nonce, body[21+idLen:], nil)
+	if err != nil {
+		t.Fatalf("failed to decrypt: %v", err)
+	}
+	if plaintext[len(plaintext)-1] != 0x02 {
+		t.Fatalf("expected the last record delimiter, got %x", plaintext[len(plaintext)-1])
+	}
+	return plaintext[:len(plaintext)-1]
+}
+
+func TestSendPush(t *testing.T) {
+	publicKey, privateKey, err := GenerateVAPIDKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	vapid, err := ParseVAPID(publicKey, privateKey, "mailto:ops@example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	keys, uaPrivate, auth := newSubscription(t)
+
+	var gotBody []byte
+	var gotHeader http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = io.ReadAll(r.Body)
+		gotHeader = r.Header
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	payload := []byte(`{"title":"Build finished"}`)
+	status, err := SendPush(context.Background(), NewHTTPClient(5*time.Second, true), vapid, server.URL+"/push/abc", keys, payload)
+	if err != nil || status != http.StatusCreated {
+		t.Fatalf("expected 201, got %d (err %v)", status, err)
+	}
+	if gotHeader.Get("Content-Encoding") != "aes128gcm" || gotHeader.Get("TTL") == "" {
+		t.Errorf("unexpected headers %v", gotHeader)
+	}
+	if got := decrypt(t, gotBody, uaPrivate, auth); string(got) != string(payload) {
+		t.Errorf("expected the payload back, got %q", got)
+	}
+
+	// The VAPID token must verify against the advertised public key and name
+	// the push service's origin.
+	authorization := gotHeader.Get("Authorization")
+	token, key, ok := strings.Cut(strings.TrimPrefix(authorization, "vapid t="), ", k=")
+	if !ok || key != publicKey {
+		t.Fatalf("unexpected Authorization %q", authorization)
+	}
+	parts := strings.Split(token, ".")
+	claimsJSON, _ := base64.RawURLEncoding.DecodeString(parts[1])
+	var claims struct {
+		Aud string `json:"aud"`
+		Sub string `json:"sub"`
+		Exp int64  `json:"exp"`
+	}
+	if err := json.Unmarshal(claimsJSON, &claims); err != nil || claims.Aud != server.URL || claims.Sub != "mailto:ops@example.com" || claims.Exp <= time.Now().Unix() {
+		t.Errorf("unexpected claims %s", claimsJSON)
+	}
+	signature, _ := base64.RawURLEncoding.DecodeString(parts[2])
+	publicBytes, _ := base64.RawURLEncoding.DecodeString(publicKey)
+	verifier, err := ecdsa.ParseUncompressedPublicKey(elliptic.P256(), publicBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	r, s := new(big.Int).SetBytes(signature[:32]), new(big.Int).SetBytes(signature[32:])
+	if !ecdsa.Verify(verifier, digest[:], r, s) {
+		t.Error("expected the VAPID signature to verify")
+	}
+
+	if _, err := SendPush(context.Background(), http.DefaultClient, vapid, server.URL, keys, make([]byte, MaxPushPayload+1)); !errors.Is(err, ErrPushPayloadTooLarge) {
+		t.Errorf("expected ErrPushPayloadTooLarge, got %v", err)
+	}
+}
+
+func TestParseVAPID_Invalid(t *testing.T) {
+	publicKey, privateKey, _ := GenerateVAPIDKeys()
+	otherPublic, _, _ := GenerateVAPIDKeys()
+
+	tests := map[string][3]string{
+		"bad subject":     {publicKey, privateKey, "ops@example.com"},
+		"bad private key": {publicKey, "not-a-key", "mailto:ops@example.com"},
+		"mismatched pair": {otherPublic, privateKey, "mailto:ops@example.com"},
+	}
+	for name, args := range tests {
+		if _, err := ParseVAPID(args[0], args[1], args[2]); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestValidatePushKeys(t *testing.T) {
+	keys, _, _ := newSubscription(t)
+	if err := ValidatePushKeys(keys); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := ValidatePushKeys(models.PushKeys{P256dh: keys.P256dh, Auth: "c2hvcnQ"}); err == nil {
+		t.Error("expected a short auth secret to be rejected")
+	}
+	if err := ValidatePushKeys(models.PushKeys{P256dh: "AAAA", Auth: keys.Auth}); err == nil {
+		t.Error("expected an invalid p256dh key to be rejected")
+	}
+}
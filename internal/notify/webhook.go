@@ -0,0 +1,39 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+)
+
+// Webhook request headers. The signature is "sha256=" and the hex HMAC-SHA256
+// of the body keyed with the channel's secret.
+const (
+	SignatureHeader = "X-Wishlist-Signature"
+	EventHeader     = "X-Wishlist-Event"
+	DeliveryHeader  = "X-Wishlist-Delivery"
+)
+
+// Sign returns the signature header value for body.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// SendWebhook POSTs body to url, signed with secret, and returns the
+// response status.
+func SendWebhook(ctx context.Context, client *http.Client, url, secret, event, deliveryID string, body []byte) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, Sign(secret, body))
+	req.Header.Set(EventHeader, event)
+	req.Header.Set(DeliveryHeader, deliveryID)
+	return do(ctx, client, req)
+}
@@ -0,0 +1,243 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+const (
+	// MaxPushPayload bounds a push message before encryption; push services
+	// accept 4096 byte bodies.
+	MaxPushPayload = 3000
+	// pushTTL is how long a push service holds a message for an offline
+	// browser.
+	pushTTL = 24 * time.Hour
+	// recordSize is the aes128gcm record size; a payload fits in one record.
+	recordSize = 4096
+	// vapidExpiry is how long a VAPID token is valid; at most 24 hours.
+	vapidExpiry = 12 * time.Hour
+)
+
+var ErrPushPayloadTooLarge = fmt.Errorf("push payload larger than %d bytes", MaxPushPayload)
+
+// VAPID identifies this server to push services (RFC 8292). PublicKey is the
+// applicationServerKey browsers subscribe with.
+type VAPID struct {
+	PublicKey string
+	subject   string
+	key       *ecdsa.PrivateKey
+}
+
+// ParseVAPID parses a VAPID key pair, each base64url encoded as web-push
+// tools print them: the uncompressed P-256 public key and the raw private
+// key. subject is a mailto: or https: contact for the push service.
+func ParseVAPID(publicKey, privateKey, subject string) (*VAPID, error) {
+	if !strings.HasPrefix(subject, "mailto:") && !strings.HasPrefix(subject, "https:") {
+		return nil, errors.New("VAPID subject must be a mailto: or https: URL")
+	}
+	d, err := decodeBase64URL(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid VAPID private key: %w", err)
+	}
+	key, err := ecdsa.ParseRawPrivateKey(elliptic.P256(), d)
+	if err != nil {
+		return nil, fmt.Errorf("invalid VAPID private key: %w", err)
+	}
+	public, err := key.PublicKey.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	if given, err := decodeBase64URL(publicKey); err != nil || !bytes.Equal(given, public) {
+		return nil, errors.New("VAPID public key does not match the private key")
+	}
+	return &VAPID{PublicKey: base64.RawURLEncoding.EncodeToString(public), subject: subject, key: key}, nil
+}
+
+// GenerateVAPIDKeys returns a new VAPID key pair encoded for ParseVAPID.
+func GenerateVAPIDKeys() (publicKey, privateKey string, err error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+	public, err := key.PublicKey.Bytes()
+	if err != nil {
+		return "", "", err
+	}
+	private, err := key.Bytes()
+	if err != nil {
+		return "", "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(public), base64.RawURLEncoding.EncodeToString(private), nil
+}
+
+// ValidatePushKeys checks a subscription's keys decode to a P-256 public key
+// and a 16 byte auth secret.
+func ValidatePushKeys(keys models.PushKeys) error {
+	_, _, err := parsePushKeys(keys)
+	return err
+}
+
+func parsePushKeys(keys models.PushKeys) (*ecdh.PublicKey, []byte, error) {
+	p256dh, err := decodeBase64URL(keys.P256dh)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid p256dh key: %w", err)
+	}
+	public, err := ecdh.P256().NewPublicKey(p256dh)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid p256dh key: %w", err)
+	}
+	auth, err := decodeBase64URL(keys.Auth)
+	if err != nil || len(auth) != 16 {
+		return nil, nil, errors.New("invalid auth secret: must be 16 bytes")
+	}
+	return public, auth, nil
+}
+
+// SendPush encrypts payload for the subscription (RFC 8291) and POSTs it to
+// its endpoint, returning the response status.
+func SendPush(ctx context.Context, client *http.Client, vapid *VAPID, endpoint string, keys models.PushKeys, payload []byte) (int, error) {
+	if len(payload) > MaxPushPayload {
+		return 0, ErrPushPayloadTooLarge
+	}
+	body, err := encrypt(keys, payload)
+	if err != nil {
+		return 0, err
+	}
+	authorization, err := vapid.authorization(endpoint, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("TTL", strconv.Itoa(int(pushTTL.Seconds())))
+	req.Header.Set("Urgency", "normal")
+	req.Header.Set("Authorization", authorization)
+	return do(ctx, client, req)
+}
+
+// encrypt returns payload as an aes128gcm message for the subscription:
+// the salt, record size and this message's ephemeral public key, then one
+// encrypted record.
+func encrypt(keys models.PushKeys, payload []byte) ([]byte, error) {
+	uaPublic, authSecret, err := parsePushKeys(keys)
+	if err != nil {
+		return nil, err
+	}
+	asPrivate, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	secret, err := asPrivate.ECDH(uaPublic)
+	if err != nil {
+		return nil, err
+	}
+	asPublic := asPrivate.PublicKey().Bytes()
+	cek, nonce, err := deriveContentKeys(secret, authSecret, uaPublic.Bytes(), asPublic, salt)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	// A single, and so last, record ends with the 0x02 padding delimiter.
+	plaintext := append(append([]byte{}, payload...), 0x02)
+
+	header := make([]byte, 0, 16+4+1+len(asPublic))
+	header = append(header, salt...)
+	header = binary.BigEndian.AppendUint32(header, recordSize)
+	header = append(header, byte(len(asPublic)))
+	header = append(header, asPublic...)
+	return gcm.Seal(header, nonce, plaintext, nil), nil
+}
+
+// deriveContentKeys derives the content encryption key and nonce from the
+// ECDH secret between the user agent and application server keys (RFC 8291
+// section 3.4).
+func deriveContentKeys(secret, authSecret, uaPublic, asPublic, salt []byte) ([]byte, []byte, error) {
+	keyInfo := "WebPush: info\x00" + string(uaPublic) + string(asPublic)
+	ikm, err := hkdf.Key(sha256.New, secret, authSecret, keyInfo, 32)
+	if err != nil {
+		return nil, nil, err
+	}
+	cek, err := hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: aes128gcm\x00", 16)
+	if err != nil {
+		return nil, nil, err
+	}
+	nonce, err := hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: nonce\x00", 12)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cek, nonce, nil
+}
+
+// authorization returns the VAPID Authorization header for endpoint: a
+// JWT for the endpoint's origin signed with ES256, and the public key.
+func (v *VAPID) authorization(endpoint string, now time.Time) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"typ":"JWT","alg":"ES256"}`))
+	claims, err := json.Marshal(map[string]any{
+		"aud": u.Scheme + "://" + u.Host,
+		"exp": now.Add(vapidExpiry).Unix(),
+		"sub": v.subject,
+	})
+	if err != nil {
+		return "", err
+	}
+	unsigned := header + "." + base64.RawURLEncoding.EncodeToString(claims)
+
+	digest := sha256.Sum256([]byte(unsigned))
+	r, s, err := ecdsa.Sign(rand.Reader, v.key, digest[:])
+	if err != nil {
+		return "", err
+	}
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+
+	token := unsigned + "." + base64.RawURLEncoding.EncodeToString(signature)
+	return "vapid t=" + token + ", k=" + v.PublicKey, nil
+}
+
+// decodeBase64URL accepts base64url with or without padding, as browsers
+// and key tools differ.
+func decodeBase64URL(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
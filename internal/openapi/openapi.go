@@ -0,0 +1,361 @@
+// Package openapi builds an OpenAPI 3 description of the API from the
+// mounted router. Every route the router serves is listed, with its path
+// parameters and whether it needs a bearer token; a Spec describing a route
+// adds its summary, query parameters, and the Go types of its request and
+// response bodies, whose schemas are derived from their json tags.
+package openapi
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"reflect"
+	"regexp"
+	"runtime"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Version is the OpenAPI version of the documents built.
+const Version = "3.0.3"
+
+// Document is an OpenAPI document.
+type Document struct {
+	OpenAPI    string                           `json:"openapi"`
+	Info       Info                             `json:"info"`
+	Paths      map[string]map[string]*Operation `json:"paths"`
+	Components Components                       `json:"components"`
+}
+
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes"`
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+// Operation is one method on one path, as written to the document.
+type Operation struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []*Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                  `json:"required"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Spec describes a route for the document. Request and Response are values
+// of the body types (e.g. dto.AddItemRequest{}); nil means no JSON body.
+type Spec struct {
+	Summary string
+	// Wildcard names the parameter a trailing /* matches; "uniqueName"
+	// when empty.
+	Wildcard string
+	Query    []Query
+	Request  any
+	Response any
+	// Status is the success status; 200 when zero.
+	Status int
+	// ContentTypes lists other media types the success response can be
+	// sent as, such as text/csv.
+	ContentTypes []string
+}
+
+// Query is a query parameter: its name, type ("string", "integer" or
+// "boolean") and description.
+type Query struct {
+	Name        string
+	Type        string
+	Description string
+}
+
+// Message is the body of responses that only confirm what happened.
+type Message struct {
+	Message string `json:"message"`
+}
+
+// bearerScheme names the bearer token security scheme.
+const bearerScheme = "bearerAuth"
+
+var routeParam = regexp.MustCompile(`\{([^}:]+)(:[^}]*)?\}`)
+
+// Builder collects the routes to document.
+type Builder struct {
+	info      Info
+	specs     map[string]Spec
+	auth      uintptr
+	errorType reflect.Type
+}
+
+// NewBuilder documents routes with specs, keyed by method and route pattern
+// as chi reports them ("GET /api/v1/items/*"). Routes behind the auth
+// middleware require a bearer token; errorBody is a value of the error
+// response type.
+func NewBuilder(title, version string, specs map[string]Spec, auth func(http.Handler) http.Handler, errorBody any) *Builder {
+	b := &Builder{
+		info:      Info{Title: title, Version: version},
+		specs:     specs,
+		errorType: reflect.TypeOf(errorBody),
+	}
+	if auth != nil {
+		b.auth = reflect.ValueOf(auth).Pointer()
+	}
+	return b
+}
+
+// Build documents every route under prefix that routes serves. It also
+// returns the routes without a Spec and the Specs matching no route, so
+// callers can flag the document drifting from the handlers.
+func (b *Builder) Build(routes chi.Routes, prefix string) (doc *Document, undocumented, unused []string, err error) {
+	s := newSchemas()
+	doc = &Document{
+		OpenAPI: Version,
+		Info:    b.info,
+		Paths:   make(map[string]map[string]*Operation),
+		Components: Components{
+			Schemas: s.components,
+			SecuritySchemes: map[string]*SecurityScheme{
+				bearerScheme: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+	}
+	errorRef := s.of(b.errorType)
+
+	seen := make(map[string]bool)
+	operationIDs := make(map[string]bool)
+	err = walk(routes, "", nil, func(method, route string, handler http.Handler, middlewares []func(http.Handler) http.Handler) error {
+		if !strings.HasPrefix(route, prefix) {
+			return nil
+		}
+		key := method + " " + route
+		spec, ok := b.specs[key]
+		if ok {
+			seen[key] = true
+		} else {
+			undocumented = append(undocumented, key)
+		}
+
+		path, params := b.path(route, spec.Wildcard)
+		op := &Operation{
+			OperationID: operationID(handler, method, route),
+			Summary:     spec.Summary,
+			Tags:        []string{tag(route, prefix)},
+			Parameters:  params,
+			Responses:   make(map[string]*Response),
+		}
+		for id, n := op.OperationID, 2; operationIDs[op.OperationID]; n++ {
+			op.OperationID = id + strconv.Itoa(n)
+		}
+		operationIDs[op.OperationID] = true
+
+		for _, q := range spec.Query {
+			op.Parameters = append(op.Parameters, &Parameter{Name: q.Name, In: "query", Description: q.Description, Schema: &Schema{Type: queryType(q.Type)}})
+		}
+		if spec.Request != nil {
+			op.RequestBody = &RequestBody{
+				Required: true,
+				Content:  map[string]*MediaType{"application/json": {Schema: s.of(reflect.TypeOf(spec.Request))}},
+			}
+		}
+
+		status := spec.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		success := &Response{Description: http.StatusText(status)}
+		if spec.Response != nil {
+			success.Content = map[string]*MediaType{"application/json": {Schema: s.of(reflect.TypeOf(spec.Response))}}
+		}
+		for _, contentType := range spec.ContentTypes {
+			if success.Content == nil {
+				success.Content = make(map[string]*MediaType)
+			}
+			success.Content[contentType] = &MediaType{Schema: &Schema{Type: "string", Format: "binary"}}
+		}
+		op.Responses[strconv.Itoa(status)] = success
+		op.Responses["default"] = &Response{
+			Description: "Error",
+			Content:     map[string]*MediaType{"application/json": {Schema: errorRef}},
+		}
+		if b.requiresAuth(middlewares) {
+			op.Security = []map[string][]string{{bearerScheme: {}}}
+		}
+
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = make(map[string]*Operation)
+		}
+		doc.Paths[path][strings.ToLower(method)] = op
+		return nil
+	})
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("openapi: walking routes: %w", err)
+	}
+
+	for key := range b.specs {
+		if !seen[key] {
+			unused = append(unused, key)
+		}
+	}
+	sort.Strings(undocumented)
+	sort.Strings(unused)
+	return doc, undocumented, unused, nil
+}
+
+// walk calls fn for every route like chi.Walk, but also passes on the
+// middlewares of a group a subrouter is mounted in, which chi.Walk drops.
+func walk(routes chi.Routes, parent string, parentMiddlewares []func(http.Handler) http.Handler, fn func(method, route string, handler http.Handler, middlewares []func(http.Handler) http.Handler) error) error {
+	for _, route := range routes.Routes() {
+		middlewares := append(slices.Clip(parentMiddlewares), routes.Middlewares()...)
+		if route.SubRoutes != nil {
+			if chain, ok := route.Handlers["*"].(*chi.ChainHandler); ok {
+				middlewares = append(middlewares, chain.Middlewares...)
+			}
+			if err := walk(route.SubRoutes, parent+route.Pattern, middlewares, fn); err != nil {
+				return err
+			}
+			continue
+		}
+		// Sorted, so operation IDs numbered for shared handlers are stable.
+		for _, method := range slices.Sorted(maps.Keys(route.Handlers)) {
+			if method == "*" {
+				continue
+			}
+			handler := route.Handlers[method]
+			pattern := strings.ReplaceAll(parent+route.Pattern, "/*/", "/")
+			handlerMiddlewares := middlewares
+			if chain, ok := handler.(*chi.ChainHandler); ok {
+				handler = chain.Endpoint
+				handlerMiddlewares = append(slices.Clip(middlewares), chain.Middlewares...)
+			}
+			if err := fn(method, pattern, handler, handlerMiddlewares); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// path converts a chi route pattern to an OpenAPI path and its parameters.
+// A trailing /* becomes a parameter named wildcard, whose values may
+// contain slashes.
+func (b *Builder) path(route, wildcard string) (string, []*Parameter) {
+	var params []*Parameter
+	path := routeParam.ReplaceAllStringFunc(route, func(match string) string {
+		name := routeParam.FindStringSubmatch(match)[1]
+		params = append(params, &Parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: "string"}})
+		return "{" + name + "}"
+	})
+	if rest, ok := strings.CutSuffix(path, "/*"); ok {
+		if wildcard == "" {
+			wildcard = "uniqueName"
+		}
+		params = append(params, &Parameter{
+			Name:        wildcard,
+			In:          "path",
+			Description: "May contain slashes, e.g. /Lotus/Powersuits/Rhino/Rhino.",
+			Required:    true,
+			Schema:      &Schema{Type: "string"},
+		})
+		path = rest + "/{" + wildcard + "}"
+	}
+	return path, params
+}
+
+func (b *Builder) requiresAuth(middlewares []func(http.Handler) http.Handler) bool {
+	if b.auth == 0 {
+		return false
+	}
+	for _, mw := range middlewares {
+		if reflect.ValueOf(mw).Pointer() == b.auth {
+			return true
+		}
+	}
+	return false
+}
+
+// tag groups a route by its first segment after prefix, e.g. "wishlist".
+func tag(route, prefix string) string {
+	segment, _, _ := strings.Cut(strings.TrimPrefix(strings.TrimPrefix(route, prefix), "/"), "/")
+	if segment == "" {
+		return "api"
+	}
+	return segment
+}
+
+// operationID names an operation after its handler method, e.g.
+// "wishlistAddItem" for (*WishlistHandler).AddItem, falling back to the
+// method and route for plain functions and closures.
+func operationID(handler http.Handler, method, route string) string {
+	if v := reflect.ValueOf(handler); v.Kind() == reflect.Func {
+		if fn := runtime.FuncForPC(v.Pointer()); fn != nil {
+			name := strings.TrimSuffix(fn.Name(), "-fm")
+			name = name[strings.LastIndex(name, "/")+1:]
+			// package.(*Type).Method, or package.Type.Method for value
+			// receivers.
+			if _, typeAndMethod, ok := strings.Cut(name, "."); ok {
+				typeAndMethod = strings.NewReplacer("(*", "", ")", "").Replace(typeAndMethod)
+				if typeName, methodName, ok := strings.Cut(typeAndMethod, "."); ok && !strings.Contains(methodName, ".") {
+					typeName = strings.TrimSuffix(typeName, "Handler")
+					return lowerFirst(typeName) + methodName
+				}
+			}
+		}
+	}
+	id := strings.ToLower(method)
+	for _, part := range strings.FieldsFunc(route, func(r rune) bool {
+		return !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9')
+	}) {
+		id += strings.ToUpper(part[:1]) + part[1:]
+	}
+	return id
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+func queryType(t string) string {
+	if t == "" {
+		return "string"
+	}
+	return t
+}
@@ -0,0 +1,215 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type testAuth struct{}
+
+func (testAuth) Authenticate(next http.Handler) http.Handler { return next }
+
+type testHandler struct{}
+
+func (testHandler) List(w http.ResponseWriter, r *http.Request)   {}
+func (testHandler) Add(w http.ResponseWriter, r *http.Request)    {}
+func (testHandler) Remove(w http.ResponseWriter, r *http.Request) {}
+func (testHandler) Member(w http.ResponseWriter, r *http.Request) {}
+
+type testBase struct {
+	ID string `json:"id"`
+}
+
+type testItem struct {
+	testBase
+	Name     string     `json:"name"`
+	Count    int        `json:"count,omitempty"`
+	AddedAt  time.Time  `json:"addedAt"`
+	Parent   *testItem  `json:"parent"`
+	Tags     []string   `json:"tags,omitzero"`
+	Deadline *time.Time `json:"deadline,omitempty"`
+	Secret   string     `json:"-"`
+	internal string
+}
+
+type testError struct {
+	Error string `json:"error"`
+}
+
+func buildTestDocument(t *testing.T) (*Document, []string, []string) {
+	t.Helper()
+	auth := testAuth{}
+	h := testHandler{}
+	r := chi.NewRouter()
+	r.Route("/api/v1", func(r chi.Router) {
+		r.Get("/public", h.List)
+		r.Group(func(r chi.Router) {
+			r.Use(auth.Authenticate)
+			r.Route("/things", func(r chi.Router) {
+				r.Get("/", h.List)
+				r.Post("/", h.Add)
+				r.Delete("/*", h.Remove)
+			})
+			r.Get("/groups/{groupID}/members/{memberID}", h.Member)
+		})
+	})
+	r.Get("/healthz", h.List)
+
+	specs := map[string]Spec{
+		"GET /api/v1/public":  {Summary: "Public things", Response: []testItem{}},
+		"GET /api/v1/things/": {Summary: "List things", Query: []Query{{Name: "limit", Type: "integer"}}, Response: []testItem{}},
+		"POST /api/v1/things/": {
+			Summary:  "Add a thing",
+			Request:  testItem{},
+			Response: testItem{},
+			Status:   http.StatusCreated,
+		},
+		"DELETE /api/v1/things/*": {Summary: "Remove a thing", Wildcard: "thingPath", Response: Message{}},
+		"GET /api/v1/gone":        {Summary: "Not mounted"},
+	}
+	doc, undocumented, unused, err := NewBuilder("Test", "v1", specs, auth.Authenticate, testError{}).Build(r, "/api/v1")
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	return doc, undocumented, unused
+}
+
+func TestBuild_Paths(t *testing.T) {
+	doc, undocumented, unused := buildTestDocument(t)
+
+	if doc.OpenAPI != Version || doc.Info.Title != "Test" || doc.Info.Version != "v1" {
+		t.Errorf("unexpected header %q %+v", doc.OpenAPI, doc.Info)
+	}
+	if _, ok := doc.Paths["/healthz"]; ok {
+		t.Error("expected routes outside the prefix to be left out")
+	}
+	if !reflect.DeepEqual(undocumented, []string{"GET /api/v1/groups/{groupID}/members/{memberID}"}) {
+		t.Errorf("unexpected undocumented routes %v", undocumented)
+	}
+	if !reflect.DeepEqual(unused, []string{"GET /api/v1/gone"}) {
+		t.Errorf("unexpected unused specs %v", unused)
+	}
+
+	remove := doc.Paths["/api/v1/things/{thingPath}"]["delete"]
+	if remove == nil {
+		t.Fatalf("expected the wildcard route under its parameter name, got paths %v", keys(doc.Paths))
+	}
+	if len(remove.Parameters) != 1 || remove.Parameters[0].Name != "thingPath" || remove.Parameters[0].In != "path" || !remove.Parameters[0].Required {
+		t.Errorf("unexpected wildcard parameters %+v", remove.Parameters)
+	}
+	if remove.OperationID != "testRemove" || remove.Tags[0] != "things" {
+		t.Errorf("unexpected operation id %q and tags %v", remove.OperationID, remove.Tags)
+	}
+
+	member := doc.Paths["/api/v1/groups/{groupID}/members/{memberID}"]["get"]
+	if member == nil {
+		t.Fatalf("expected the undocumented route to be listed, got paths %v", keys(doc.Paths))
+	}
+	if len(member.Parameters) != 2 || member.Parameters[0].Name != "groupID" || member.Parameters[1].Name != "memberID" {
+		t.Errorf("unexpected path parameters %+v", member.Parameters)
+	}
+}
+
+func TestBuild_OperationIDsAreUnique(t *testing.T) {
+	doc, _, _ := buildTestDocument(t)
+
+	ids := make(map[string]bool)
+	for _, operations := range doc.Paths {
+		for _, op := range operations {
+			if ids[op.OperationID] {
+				t.Errorf("duplicate operation id %q", op.OperationID)
+			}
+			ids[op.OperationID] = true
+		}
+	}
+	// Two documented routes share (testHandler).List.
+	if !ids["testList"] || !ids["testList2"] {
+		t.Errorf("expected numbered operation ids for a shared handler, got %v", ids)
+	}
+}
+
+func TestBuild_Security(t *testing.T) {
+	doc, _, _ := buildTestDocument(t)
+
+	if doc.Paths["/api/v1/public"]["get"].Security != nil {
+		t.Error("expected routes without the auth middleware to need no token")
+	}
+	security := doc.Paths["/api/v1/things/"]["post"].Security
+	if len(security) != 1 || security[0][bearerScheme] == nil {
+		t.Errorf("expected routes behind the auth middleware to need a bearer token, got %v", security)
+	}
+	if doc.Components.SecuritySchemes[bearerScheme] == nil {
+		t.Error("expected the bearer security scheme")
+	}
+}
+
+func TestBuild_Bodies(t *testing.T) {
+	doc, _, _ := buildTestDocument(t)
+
+	add := doc.Paths["/api/v1/things/"]["post"]
+	if add.RequestBody == nil || add.RequestBody.Content["application/json"].Schema.Ref != "#/components/schemas/testItem" {
+		t.Errorf("unexpected request body %+v", add.RequestBody)
+	}
+	if add.Responses["201"] == nil || add.Responses["201"].Content["application/json"].Schema.Ref != "#/components/schemas/testItem" {
+		t.Errorf("expected a 201 response of testItem, got %v", add.Responses)
+	}
+	if add.Responses["default"].Content["application/json"].Schema.Ref != "#/components/schemas/testError" {
+		t.Errorf("expected the error body as the default response, got %+v", add.Responses["default"])
+	}
+
+	list := doc.Paths["/api/v1/things/"]["get"]
+	if len(list.Parameters) != 1 || list.Parameters[0].In != "query" || list.Parameters[0].Schema.Type != "integer" {
+		t.Errorf("unexpected query parameters %+v", list.Parameters)
+	}
+	if items := list.Responses["200"].Content["application/json"].Schema; items.Type != "array" || items.Items.Ref != "#/components/schemas/testItem" {
+		t.Errorf("unexpected list response %+v", items)
+	}
+}
+
+func TestBuild_Schemas(t *testing.T) {
+	doc, _, _ := buildTestDocument(t)
+
+	item := doc.Components.Schemas["testItem"]
+	if item == nil {
+		t.Fatalf("expected a testItem component, got %v", keys(doc.Components.Schemas))
+	}
+	if _, ok := doc.Components.Schemas["testBase"]; ok {
+		t.Error("expected embedded structs to be flattened, not registered")
+	}
+	want := []string{"addedAt", "count", "deadline", "id", "name", "parent", "tags"}
+	if got := keys(item.Properties); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected properties %v, got %v", want, got)
+	}
+	if !reflect.DeepEqual(item.Required, []string{"id", "name", "addedAt", "parent"}) {
+		t.Errorf("unexpected required fields %v", item.Required)
+	}
+	if p := item.Properties["addedAt"]; p.Type != "string" || p.Format != "date-time" {
+		t.Errorf("expected times as date-time strings, got %+v", p)
+	}
+	if p := item.Properties["deadline"]; !p.Nullable || p.Format != "date-time" {
+		t.Errorf("expected pointers to be nullable, got %+v", p)
+	}
+	if p := item.Properties["parent"]; !p.Nullable || len(p.AllOf) != 1 || p.AllOf[0].Ref != "#/components/schemas/testItem" {
+		t.Errorf("expected a nullable reference for the recursive field, got %+v", p)
+	}
+
+	// The document must encode, recursive types included.
+	if _, err := json.Marshal(doc); err != nil {
+		t.Errorf("failed to encode document: %v", err)
+	}
+}
+
+func keys[V any](m map[string]V) []string {
+	var out []string
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
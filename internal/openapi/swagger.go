@@ -0,0 +1,9 @@
+package openapi
+
+import _ "embed"
+
+// SwaggerUI is a page rendering the document at openapi.json, relative to
+// the page, with Swagger UI. The Swagger UI assets load from unpkg.
+//
+//go:embed swagger.html
+var SwaggerUI []byte
@@ -0,0 +1,94 @@
+package pdf
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestDocument_WriteTo(t *testing.T) {
+	doc := New("Wishlist")
+	first := doc.AddPage()
+	first.Text(50, 800, HelveticaBold, 18, "Checklist (draft)")
+	first.Rect(50, 760, 10, 10)
+	first.Line(50, 750, 545, 750)
+	doc.AddPage().Text(50, 800, Helvetica, 10, "Page two")
+
+	var buf bytes.Buffer
+	n, err := doc.WriteTo(&buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+	if int(n) != len(out) {
+		t.Errorf("expected %d bytes reported, got %d", len(out), n)
+	}
+	if !strings.HasPrefix(out, "%PDF-1.4\n") || !strings.HasSuffix(out, "%%EOF\n") {
+		t.Fatalf("expected a PDF header and trailer, got %q", out)
+	}
+	if !strings.Contains(out, "/Count 2") {
+		t.Error("expected two pages")
+	}
+	if !strings.Contains(out, `(Checklist \(draft\)) Tj`) {
+		t.Error("expected parentheses escaped in text")
+	}
+	if !strings.Contains(out, "/Title (Wishlist)") {
+		t.Error("expected the title in the info dictionary")
+	}
+
+	// Every cross-reference entry must point at its object.
+	xrefAt := strings.LastIndex(out, "startxref\n")
+	start, err := strconv.Atoi(strings.TrimSpace(strings.TrimSuffix(out[xrefAt+len("startxref\n"):], "%%EOF\n")))
+	if err != nil || !strings.HasPrefix(out[start:], "xref\n") {
+		t.Fatalf("expected startxref to point at the xref table, got %d (%v)", start, err)
+	}
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllStringSubmatch(out[start:], -1)
+	if len(entries) != 9 {
+		t.Fatalf("expected 9 objects, got %d", len(entries))
+	}
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(entry[1])
+		if want := strconv.Itoa(i+1) + " 0 obj"; !strings.HasPrefix(out[offset:], want) {
+			t.Errorf("expected object %d at offset %d, got %q", i+1, offset, out[offset:offset+10])
+		}
+	}
+
+	// Stream lengths must match their content.
+	for _, match := range regexp.MustCompile(`(?s)/Length (\d+) >>\nstream\n(.*?)endstream`).FindAllStringSubmatch(out, -1) {
+		if length, _ := strconv.Atoi(match[1]); length != len(match[2]) {
+			t.Errorf("expected stream length %d, got %d", len(match[2]), length)
+		}
+	}
+}
+
+func TestDocument_WriteTo_NoPages(t *testing.T) {
+	var buf bytes.Buffer
+	if _, err := New("Empty").WriteTo(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "/Count 1") {
+		t.Error("expected a blank page")
+	}
+}
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "Forma", want: "Forma"},
+		{in: "Équinoxe", want: "\xc9quinoxe"},
+		{in: "Nidus — Prime", want: "Nidus \x97 Prime"},
+		{in: "Ω\tgone", want: "?gone"},
+	}
+	for _, tt := range tests {
+		if got := string(encode(tt.in)); got != tt.want {
+			t.Errorf("encode(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+	if got := escape([]byte(`a\b(c)`)); got != `a\\b\(c\)` {
+		t.Errorf("unexpected escape %q", got)
+	}
+}
@@ -0,0 +1,171 @@
+package realtime
+
+import (
+	"errors"
+	"slices"
+	"sync"
+)
+
+// subscriptionBuffer is how many events a subscription holds before it is
+// considered too slow and dropped. A dropped client reconnects and refetches.
+const subscriptionBuffer = 32
+
+var (
+	ErrTooManyConnections = errors.New("too many live connections")
+	ErrHubClosed          = errors.New("live updates are shutting down")
+)
+
+// Presence lists the distinct users subscribed to a room. It is published
+// to the room whenever someone joins or leaves.
+type Presence struct {
+	Users []string
+}
+
+// Hub fans events out to the subscriptions of each room. It lives in memory,
+// so only subscribers on the instance that published an event receive it.
+type Hub struct {
+	maxSubscriptions int
+
+	mu     sync.Mutex
+	rooms  map[string]map[*Subscription]struct{}
+	count  int
+	closed bool
+}
+
+// NewHub returns a hub holding at most maxSubscriptions subscriptions
+// across all rooms.
+func NewHub(maxSubscriptions int) *Hub {
+	return &Hub{
+		maxSubscriptions: maxSubscriptions,
+		rooms:            make(map[string]map[*Subscription]struct{}),
+	}
+}
+
+// Subscription receives a room's events until it leaves or is dropped.
+type Subscription struct {
+	hub    *Hub
+	room   string
+	userID string
+	events chan any
+}
+
+// Events returns the subscription's events. The channel is closed once the
+// subscription has left, was too slow to keep up, or the hub closed.
+func (s *Subscription) Events() <-chan any {
+	return s.events
+}
+
+// Leave ends the subscription.
+func (s *Subscription) Leave() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.remove(s)
+}
+
+// Join subscribes userID to room and publishes the room's new presence,
+// which the new subscription receives first.
+func (h *Hub) Join(room, userID string) (*Subscription, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return nil, ErrHubClosed
+	}
+	if h.count >= h.maxSubscriptions {
+		return nil, ErrTooManyConnections
+	}
+
+	subscription := &Subscription{hub: h, room: room, userID: userID, events: make(chan any, subscriptionBuffer)}
+	subscriptions := h.rooms[room]
+	if subscriptions == nil {
+		subscriptions = make(map[*Subscription]struct{})
+		h.rooms[room] = subscriptions
+	}
+	subscriptions[subscription] = struct{}{}
+	h.count++
+	h.publishPresence(room)
+	return subscription, nil
+}
+
+// Publish sends event to every subscription of room. Subscriptions whose
+// buffer is full are dropped rather than slowing the publisher.
+func (h *Hub) Publish(room string, event any) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.publish(room, event)
+}
+
+// Present returns the distinct users subscribed to room, sorted.
+func (h *Hub) Present(room string) []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.present(room)
+}
+
+// Len returns the number of subscriptions across all rooms.
+func (h *Hub) Len() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.count
+}
+
+// Close ends every subscription and refuses new ones.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for room, subscriptions := range h.rooms {
+		for subscription := range subscriptions {
+			close(subscription.events)
+		}
+		delete(h.rooms, room)
+	}
+	h.count = 0
+}
+
+func (h *Hub) publish(room string, event any) {
+	var dropped []*Subscription
+	for subscription := range h.rooms[room] {
+		select {
+		case subscription.events <- event:
+		default:
+			dropped = append(dropped, subscription)
+		}
+	}
+	for _, subscription := range dropped {
+		h.remove(subscription)
+	}
+}
+
+// remove drops subscription and publishes the room's new presence. It is a
+// no-op for a subscription already removed.
+func (h *Hub) remove(subscription *Subscription) {
+	subscriptions := h.rooms[subscription.room]
+	if _, ok := subscriptions[subscription]; !ok {
+		return
+	}
+	delete(subscriptions, subscription)
+	close(subscription.events)
+	h.count--
+	if len(subscriptions) == 0 {
+		delete(h.rooms, subscription.room)
+		return
+	}
+	h.publishPresence(subscription.room)
+}
+
+func (h *Hub) publishPresence(room string) {
+	h.publish(room, Presence{Users: h.present(room)})
+}
+
+func (h *Hub) present(room string) []string {
+	users := []string{}
+	for subscription := range h.rooms[room] {
+		if !slices.Contains(users, subscription.userID) {
+			users = append(users, subscription.userID)
+		}
+	}
+	slices.Sort(users)
+	return users
+}
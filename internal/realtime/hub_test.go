@@ -0,0 +1,135 @@
+package realtime
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// receive returns the next event, failing if none is buffered.
+func receive(t *testing.T, subscription *Subscription) any {
+	t.Helper()
+	select {
+	case event, ok := <-subscription.Events():
+		if !ok {
+			t.Fatal("expected an event, the subscription ended")
+		}
+		return event
+	default:
+		t.Fatal("expected an event, none was published")
+		return nil
+	}
+}
+
+func expectPresence(t *testing.T, subscription *Subscription, users ...string) {
+	t.Helper()
+	presence, ok := receive(t, subscription).(Presence)
+	if !ok || !slices.Equal(presence.Users, users) {
+		t.Errorf("expected presence %v, got %+v", users, presence)
+	}
+}
+
+func TestHub_JoinPublishLeave(t *testing.T) {
+	hub := NewHub(10)
+
+	alice, err := hub.Join("room", "alice")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectPresence(t, alice, "alice")
+
+	bob, _ := hub.Join("room", "bob")
+	expectPresence(t, alice, "alice", "bob")
+	expectPresence(t, bob, "alice", "bob")
+
+	other, _ := hub.Join("other", "carol")
+	expectPresence(t, other, "carol")
+
+	hub.Publish("room", "item added")
+	if receive(t, alice) != "item added" || receive(t, bob) != "item added" {
+		t.Error("expected both subscribers of the room to get the event")
+	}
+	select {
+	case event := <-other.Events():
+		t.Errorf("expected nothing for another room, got %v", event)
+	default:
+	}
+
+	bob.Leave()
+	bob.Leave()
+	expectPresence(t, alice, "alice")
+	if _, ok := <-bob.Events(); ok {
+		t.Error("expected the left subscription to be closed")
+	}
+	if hub.Len() != 2 {
+		t.Errorf("expected 2 subscriptions, got %d", hub.Len())
+	}
+}
+
+func TestHub_PresenceListsUsersOnce(t *testing.T) {
+	hub := NewHub(10)
+	first, _ := hub.Join("room", "alice")
+	hub.Join("room", "alice")
+
+	if present := hub.Present("room"); !slices.Equal(present, []string{"alice"}) {
+		t.Errorf("expected alice once, got %v", present)
+	}
+	first.Leave()
+	if present := hub.Present("room"); !slices.Equal(present, []string{"alice"}) {
+		t.Errorf("expected alice still present in her other tab, got %v", present)
+	}
+	if present := hub.Present("empty"); present == nil || len(present) != 0 {
+		t.Errorf("expected an empty list, got %#v", present)
+	}
+}
+
+func TestHub_DropsSlowSubscribers(t *testing.T) {
+	hub := NewHub(10)
+	slow, _ := hub.Join("room", "slow")
+	fast, _ := hub.Join("room", "fast")
+
+	for range subscriptionBuffer * 2 {
+		hub.Publish("room", "event")
+		for {
+			select {
+			case <-fast.Events():
+				continue
+			default:
+			}
+			break
+		}
+	}
+
+	drained := 0
+	for range slow.Events() {
+		drained++
+	}
+	if drained != subscriptionBuffer {
+		t.Errorf("expected the slow subscription closed after %d buffered events, got %d", subscriptionBuffer, drained)
+	}
+	if present := hub.Present("room"); !slices.Equal(present, []string{"fast"}) {
+		t.Errorf("expected only the fast subscriber present, got %v", present)
+	}
+}
+
+func TestHub_Limits(t *testing.T) {
+	hub := NewHub(1)
+	if _, err := hub.Join("room", "alice"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := hub.Join("room", "bob"); !errors.Is(err, ErrTooManyConnections) {
+		t.Errorf("expected ErrTooManyConnections, got %v", err)
+	}
+
+	hub = NewHub(10)
+	subscription, _ := hub.Join("room", "alice")
+	hub.Close()
+	receive(t, subscription)
+	if _, ok := <-subscription.Events(); ok {
+		t.Error("expected the subscription closed with the hub")
+	}
+	subscription.Leave()
+	if _, err := hub.Join("room", "bob"); !errors.Is(err, ErrHubClosed) {
+		t.Errorf("expected ErrHubClosed, got %v", err)
+	}
+}
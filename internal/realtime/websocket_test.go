@@ -0,0 +1,234 @@
+package realtime
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testClient speaks just enough of the client side of RFC 6455 to drive a
+// server connection.
+type testClient struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// dialTest opens a WebSocket to server, returning the handshake response.
+func dialTest(t *testing.T, server *httptest.Server, header http.Header) (*testClient, *http.Response) {
+	t.Helper()
+	conn, err := net.Dial("tcp", strings.TrimPrefix(server.URL, "http://"))
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/live", nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	if err := req.Write(conn); err != nil {
+		t.Fatalf("handshake write failed: %v", err)
+	}
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		t.Fatalf("handshake read failed: %v", err)
+	}
+	return &testClient{conn: conn, reader: reader}, resp
+}
+
+func (c *testClient) writeFrame(t *testing.T, header byte, payload []byte, masked bool) {
+	t.Helper()
+	frame := []byte{header}
+	maskBit := byte(0)
+	if masked {
+		maskBit = 0x80
+	}
+	switch {
+	case len(payload) < 126:
+		frame = append(frame, maskBit|byte(len(payload)))
+	default:
+		frame = append(frame, maskBit|126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(len(payload)))
+	}
+	if masked {
+		mask := [4]byte{1, 2, 3, 4}
+		frame = append(frame, mask[:]...)
+		for i, b := range payload {
+			frame = append(frame, b^mask[i%4])
+		}
+	} else {
+		frame = append(frame, payload...)
+	}
+	if _, err := c.conn.Write(frame); err != nil {
+		t.Fatalf("frame write failed: %v", err)
+	}
+}
+
+func (c *testClient) readFrame(t *testing.T) (byte, []byte) {
+	t.Helper()
+	var header [2]byte
+	if _, err := io.ReadFull(c.reader, header[:]); err != nil {
+		t.Fatalf("frame read failed: %v", err)
+	}
+	if header[1]&0x80 != 0 {
+		t.Fatal("server frames must not be masked")
+	}
+	length := int(header[1] & 0x7F)
+	if length == 126 {
+		var extended [2]byte
+		io.ReadFull(c.reader, extended[:])
+		length = int(binary.BigEndian.Uint16(extended[:]))
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.reader, payload); err != nil {
+		t.Fatalf("payload read failed: %v", err)
+	}
+	return header[0] & 0x0F, payload
+}
+
+// newEchoServer upgrades every request and echoes each message back until
+// the connection ends, reporting how it ended on done.
+func newEchoServer(t *testing.T) (*httptest.Server, <-chan error) {
+	done := make(chan error, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := Upgrade(w, r, "wishlist.v1")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		for {
+			message, err := conn.ReadMessage()
+			if err != nil {
+				done <- err
+				return
+			}
+			conn.WriteMessage(message)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, done
+}
+
+func TestUpgrade_Handshake(t *testing.T) {
+	server, _ := newEchoServer(t)
+	_, resp := dialTest(t, server, http.Header{"Sec-Websocket-Protocol": {"other, wishlist.v1"}})
+
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("expected 101, got %d", resp.StatusCode)
+	}
+	// The accept key from RFC 6455's example handshake.
+	if got := resp.Header.Get("Sec-WebSocket-Accept"); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Errorf("unexpected accept key %q", got)
+	}
+	if got := resp.Header.Get("Sec-WebSocket-Protocol"); got != "wishlist.v1" {
+		t.Errorf("expected the offered protocol to be selected, got %q", got)
+	}
+}
+
+func TestUpgrade_RejectsInvalidHandshakes(t *testing.T) {
+	server, _ := newEchoServer(t)
+	for name, header := range map[string]http.Header{
+		"bad key":     {"Sec-Websocket-Key": {"short"}},
+		"bad version": {"Sec-Websocket-Version": {"8"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, resp := dialTest(t, server, header)
+			if resp.StatusCode != http.StatusBadRequest {
+				t.Errorf("expected 400, got %d", resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestConn_Messages(t *testing.T) {
+	server, done := newEchoServer(t)
+	client, _ := dialTest(t, server, nil)
+
+	client.writeFrame(t, 0x81, []byte("hello"), true)
+	if opcode, payload := client.readFrame(t); opcode != opText || string(payload) != "hello" {
+		t.Fatalf("expected the message echoed, got %x %q", opcode, payload)
+	}
+
+	// A fragmented message with a ping between its fragments.
+	client.writeFrame(t, 0x01, []byte("frag"), true)
+	client.writeFrame(t, 0x89, []byte("ping"), true)
+	client.writeFrame(t, 0x80, []byte("mented"), true)
+	if opcode, payload := client.readFrame(t); opcode != opPong || string(payload) != "ping" {
+		t.Fatalf("expected a pong, got %x %q", opcode, payload)
+	}
+	if _, payload := client.readFrame(t); string(payload) != "fragmented" {
+		t.Fatalf("expected the fragments joined, got %q", payload)
+	}
+
+	// A longer message uses the 16-bit length.
+	long := strings.Repeat("x", 300)
+	client.writeFrame(t, 0x81, []byte(long), true)
+	if _, payload := client.readFrame(t); string(payload) != long {
+		t.Fatalf("expected the long message echoed, got %d bytes", len(payload))
+	}
+
+	client.writeFrame(t, 0x88, binary.BigEndian.AppendUint16(nil, CloseNormal), true)
+	if opcode, payload := client.readFrame(t); opcode != opClose || binary.BigEndian.Uint16(payload) != CloseNormal {
+		t.Fatalf("expected the close echoed, got %x %v", opcode, payload)
+	}
+	if err := <-done; !errors.Is(err, ErrClosed) {
+		t.Errorf("expected ErrClosed, got %v", err)
+	}
+}
+
+func TestConn_ProtocolErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  byte
+		payload []byte
+		masked  bool
+		code    int
+	}{
+		{name: "unmasked", header: 0x81, payload: []byte("hi"), masked: false, code: CloseProtocolError},
+		{name: "too big", header: 0x81, payload: make([]byte, MaxMessageSize+1), masked: true, code: CloseTooBig},
+		{name: "unexpected continuation", header: 0x80, payload: []byte("hi"), masked: true, code: CloseProtocolError},
+		{name: "reserved bits", header: 0xC1, payload: []byte("hi"), masked: true, code: CloseProtocolError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, done := newEchoServer(t)
+			client, _ := dialTest(t, server, nil)
+
+			client.writeFrame(t, tt.header, tt.payload, tt.masked)
+			opcode, payload := client.readFrame(t)
+			if opcode != opClose || int(binary.BigEndian.Uint16(payload)) != tt.code {
+				t.Fatalf("expected close %d, got %x %v", tt.code, opcode, payload)
+			}
+			var closeErr *CloseError
+			if err := <-done; !errors.As(err, &closeErr) || closeErr.Code != tt.code {
+				t.Errorf("expected a CloseError with %d, got %v", tt.code, err)
+			}
+		})
+	}
+}
+
+func TestIsUpgrade(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if IsUpgrade(req) {
+		t.Error("expected a plain request not to be an upgrade")
+	}
+	req.Header.Set("Connection", "keep-alive, Upgrade")
+	req.Header.Set("Upgrade", "WebSocket")
+	if !IsUpgrade(req) {
+		t.Error("expected an upgrade")
+	}
+}
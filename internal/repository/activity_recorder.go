@@ -0,0 +1,254 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+)
+
+// activityRecorder writes the entries of the activity decorators. An entry
+// that cannot be written is logged and dropped: the change it describes has
+// already been made.
+type activityRecorder struct {
+	activity ActivityRepositoryInterface
+	now      func() time.Time
+}
+
+func (a activityRecorder) record(ctx context.Context, userID, target, action string, payload any) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		logger.Error(ctx, "repo: activityRecorder.record - error encoding payload", "error", err)
+		return
+	}
+
+	entry := &models.ActivityEntry{
+		UserID:    userID,
+		Target:    target,
+		Action:    action,
+		Payload:   string(data),
+		RequestID: logger.GetRequestID(ctx),
+		CreatedAt: a.now(),
+	}
+	if err := a.activity.Create(ctx, entry); err != nil {
+		logger.Error(ctx, "repo: activityRecorder.record - error recording activity", "userID", userID, "target", target, "action", action, "error", err)
+	}
+}
+
+// ActivityWishlistRepository records every successful change to a wishlist
+// in the owner's activity log, in front of another wishlist repository, so
+// changes are logged whichever service makes them. Reads are passed through.
+type ActivityWishlistRepository struct {
+	next WishlistRepositoryInterface
+	activityRecorder
+}
+
+func NewActivityWishlistRepository(next WishlistRepositoryInterface, activity ActivityRepositoryInterface) *ActivityWishlistRepository {
+	return &ActivityWishlistRepository{
+		next:             next,
+		activityRecorder: activityRecorder{activity: activity, now: time.Now},
+	}
+}
+
+type activityItem struct {
+	UniqueName string `json:"uniqueName"`
+	Quantity   int    `json:"quantity"`
+}
+
+func (r *ActivityWishlistRepository) GetByUserID(ctx context.Context, userID string) (*models.Wishlist, error) {
+	return r.next.GetByUserID(ctx, userID)
+}
+
+// Create records each of the wishlist's items as added, as AddItem would,
+// since a user's first item creates their wishlist.
+func (r *ActivityWishlistRepository) Create(ctx context.Context, wishlist *models.Wishlist) error {
+	if err := r.next.Create(ctx, wishlist); err != nil {
+		return err
+	}
+	for _, item := range wishlist.Items {
+		r.record(ctx, wishlist.UserID, models.ActivityTargetWishlist, models.ActivityAdd, activityItem{UniqueName: item.UniqueName, Quantity: item.Quantity})
+	}
+	return nil
+}
+
+// CreateIfAbsent records the items as Create does, only when the wishlist
+// was created.
+func (r *ActivityWishlistRepository) CreateIfAbsent(ctx context.Context, wishlist *models.Wishlist) (bool, error) {
+	created, err := r.next.CreateIfAbsent(ctx, wishlist)
+	if err != nil || !created {
+		return created, err
+	}
+	for _, item := range wishlist.Items {
+		r.record(ctx, wishlist.UserID, models.ActivityTargetWishlist, models.ActivityAdd, activityItem{UniqueName: item.UniqueName, Quantity: item.Quantity})
+	}
+	return true, nil
+}
+
+func (r *ActivityWishlistRepository) AddItem(ctx context.Context, userID string, item models.WishlistItem) error {
+	if err := r.next.AddItem(ctx, userID, item); err != nil {
+		return err
+	}
+	r.record(ctx, userID, models.ActivityTargetWishlist, models.ActivityAdd, activityItem{UniqueName: item.UniqueName, Quantity: item.Quantity})
+	return nil
+}
+
+func (r *ActivityWishlistRepository) RemoveItem(ctx context.Context, userID, uniqueName string) error {
+	if err := r.next.RemoveItem(ctx, userID, uniqueName); err != nil {
+		return err
+	}
+	r.record(ctx, userID, models.ActivityTargetWishlist, models.ActivityRemove, map[string]any{"uniqueName": uniqueName})
+	return nil
+}
+
+func (r *ActivityWishlistRepository) UpdateItemQuantity(ctx context.Context, userID, uniqueName string, quantity int) error {
+	if err := r.next.UpdateItemQuantity(ctx, userID, uniqueName, quantity); err != nil {
+		return err
+	}
+	r.record(ctx, userID, models.ActivityTargetWishlist, models.ActivityUpdate, map[string]any{"uniqueName": uniqueName, "field": "quantity", "quantity": quantity})
+	return nil
+}
+
+func (r *ActivityWishlistRepository) SetItemLinks(ctx context.Context, userID, uniqueName string, links []models.SourceLink) error {
+	if err := r.next.SetItemLinks(ctx, userID, uniqueName, links); err != nil {
+		return err
+	}
+	r.record(ctx, userID, models.ActivityTargetWishlist, models.ActivityUpdate, map[string]any{"uniqueName": uniqueName, "field": "links", "links": links})
+	return nil
+}
+
+func (r *ActivityWishlistRepository) SetItemRecipe(ctx context.Context, userID, uniqueName, recipeID string) error {
+	if err := r.next.SetItemRecipe(ctx, userID, uniqueName, recipeID); err != nil {
+		return err
+	}
+	r.record(ctx, userID, models.ActivityTargetWishlist, models.ActivityUpdate, map[string]any{"uniqueName": uniqueName, "field": "recipe", "recipeId": recipeID})
+	return nil
+}
+
+func (r *ActivityWishlistRepository) SetItemProgress(ctx context.Context, userID, uniqueName string, progress []models.ComponentProgress) error {
+	if err := r.next.SetItemProgress(ctx, userID, uniqueName, progress); err != nil {
+		return err
+	}
+	r.record(ctx, userID, models.ActivityTargetWishlist, models.ActivityUpdate, map[string]any{"uniqueName": uniqueName, "field": "progress", "progress": progress})
+	return nil
+}
+
+// Upsert records the whole wishlist as updated, with its item count rather
+// than its items.
+func (r *ActivityWishlistRepository) Upsert(ctx context.Context, wishlist *models.Wishlist) error {
+	if err := r.next.Upsert(ctx, wishlist); err != nil {
+		return err
+	}
+	r.record(ctx, wishlist.UserID, models.ActivityTargetWishlist, models.ActivityUpdate, map[string]any{"field": "items", "itemCount": len(wishlist.Items)})
+	return nil
+}
+
+// ActivityOwnedBlueprintsRepository records every successful change to a
+// user's owned blueprints and components in their activity log, in front of
+// another owned blueprints repository. Reads are passed through.
+type ActivityOwnedBlueprintsRepository struct {
+	next OwnedBlueprintsRepositoryInterface
+	activityRecorder
+}
+
+func NewActivityOwnedBlueprintsRepository(next OwnedBlueprintsRepositoryInterface, activity ActivityRepositoryInterface) *ActivityOwnedBlueprintsRepository {
+	return &ActivityOwnedBlueprintsRepository{
+		next:             next,
+		activityRecorder: activityRecorder{activity: activity, now: time.Now},
+	}
+}
+
+func (r *ActivityOwnedBlueprintsRepository) GetByUserID(ctx context.Context, userID string) (*models.OwnedBlueprints, error) {
+	return r.next.GetByUserID(ctx, userID)
+}
+
+// Create records the blueprints and components it stores as added.
+func (r *ActivityOwnedBlueprintsRepository) Create(ctx context.Context, ownedBlueprints *models.OwnedBlueprints) error {
+	if err := r.next.Create(ctx, ownedBlueprints); err != nil {
+		return err
+	}
+	if len(ownedBlueprints.Blueprints) > 0 {
+		uniqueNames := make([]string, len(ownedBlueprints.Blueprints))
+		for i, blueprint := range ownedBlueprints.Blueprints {
+			uniqueNames[i] = blueprint.UniqueName
+		}
+		r.record(ctx, ownedBlueprints.UserID, models.ActivityTargetBlueprints, models.ActivityAdd, map[string]any{"uniqueNames": uniqueNames})
+	}
+	if len(ownedBlueprints.Components) > 0 {
+		uniqueNames := make([]string, len(ownedBlueprints.Components))
+		for i, component := range ownedBlueprints.Components {
+			uniqueNames[i] = component.UniqueName
+		}
+		r.record(ctx, ownedBlueprints.UserID, models.ActivityTargetComponents, models.ActivityAdd, map[string]any{"uniqueNames": uniqueNames})
+	}
+	return nil
+}
+
+func (r *ActivityOwnedBlueprintsRepository) AddBlueprint(ctx context.Context, userID string, blueprint models.OwnedBlueprint) error {
+	if err := r.next.AddBlueprint(ctx, userID, blueprint); err != nil {
+		return err
+	}
+	r.record(ctx, userID, models.ActivityTargetBlueprints, models.ActivityAdd, activityItem{UniqueName: blueprint.UniqueName, Quantity: blueprint.Quantity})
+	return nil
+}
+
+func (r *ActivityOwnedBlueprintsRepository) RemoveBlueprint(ctx context.Context, userID, uniqueName string) error {
+	if err := r.next.RemoveBlueprint(ctx, userID, uniqueName); err != nil {
+		return err
+	}
+	r.record(ctx, userID, models.ActivityTargetBlueprints, models.ActivityRemove, map[string]any{"uniqueName": uniqueName})
+	return nil
+}
+
+func (r *ActivityOwnedBlueprintsRepository) BulkAddBlueprints(ctx context.Context, userID string, blueprints []models.OwnedBlueprint) error {
+	if err := r.next.BulkAddBlueprints(ctx, userID, blueprints); err != nil {
+		return err
+	}
+	uniqueNames := make([]string, len(blueprints))
+	for i, blueprint := range blueprints {
+		uniqueNames[i] = blueprint.UniqueName
+	}
+	r.record(ctx, userID, models.ActivityTargetBlueprints, models.ActivityAdd, map[string]any{"uniqueNames": uniqueNames})
+	return nil
+}
+
+func (r *ActivityOwnedBlueprintsRepository) ClearAll(ctx context.Context, userID string) error {
+	if err := r.next.ClearAll(ctx, userID); err != nil {
+		return err
+	}
+	r.record(ctx, userID, models.ActivityTargetBlueprints, models.ActivityClear, map[string]any{})
+	return nil
+}
+
+func (r *ActivityOwnedBlueprintsRepository) SetBlueprint(ctx context.Context, userID string, blueprint models.OwnedBlueprint) error {
+	if err := r.next.SetBlueprint(ctx, userID, blueprint); err != nil {
+		return err
+	}
+	r.record(ctx, userID, models.ActivityTargetBlueprints, models.ActivityUpdate, map[string]any{"uniqueName": blueprint.UniqueName, "field": "quantity", "quantity": blueprint.Quantity})
+	return nil
+}
+
+func (r *ActivityOwnedBlueprintsRepository) SetComponent(ctx context.Context, userID string, component models.OwnedComponent) error {
+	if err := r.next.SetComponent(ctx, userID, component); err != nil {
+		return err
+	}
+	r.record(ctx, userID, models.ActivityTargetComponents, models.ActivityUpdate, map[string]any{"uniqueName": component.UniqueName, "field": "count", "count": component.Count})
+	return nil
+}
+
+func (r *ActivityOwnedBlueprintsRepository) RemoveComponent(ctx context.Context, userID, uniqueName string) error {
+	if err := r.next.RemoveComponent(ctx, userID, uniqueName); err != nil {
+		return err
+	}
+	r.record(ctx, userID, models.ActivityTargetComponents, models.ActivityRemove, map[string]any{"uniqueName": uniqueName})
+	return nil
+}
+
+func (r *ActivityOwnedBlueprintsRepository) ClearComponents(ctx context.Context, userID string) error {
+	if err := r.next.ClearComponents(ctx, userID); err != nil {
+		return err
+	}
+	r.record(ctx, userID, models.ActivityTargetComponents, models.ActivityClear, map[string]any{})
+	return nil
+}
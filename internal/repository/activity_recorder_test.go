@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+)
+
+// newActivityLog returns an activity repository that keeps the entries it is
+// given.
+func newActivityLog() (*mocks.MockActivityRepository, *[]models.ActivityEntry) {
+	entries := &[]models.ActivityEntry{}
+	repo := &mocks.MockActivityRepository{
+		CreateFunc: func(ctx context.Context, entry *models.ActivityEntry) error {
+			*entries = append(*entries, *entry)
+			return nil
+		},
+	}
+	return repo, entries
+}
+
+func payloadOf(t *testing.T, entry models.ActivityEntry) map[string]any {
+	t.Helper()
+	var payload map[string]any
+	if err := json.Unmarshal([]byte(entry.Payload), &payload); err != nil {
+		t.Fatalf("expected a JSON object payload, got %q: %v", entry.Payload, err)
+	}
+	return payload
+}
+
+func TestActivityWishlistRepository_RecordsChanges(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	activity, entries := newActivityLog()
+	repo := NewActivityWishlistRepository(&mocks.MockWishlistRepository{}, activity)
+	repo.now = func() time.Time { return now }
+	ctx := logger.ContextWithRequestID(context.Background(), "req-1")
+
+	if err := repo.Create(ctx, &models.Wishlist{UserID: "user-1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(*entries) != 0 {
+		t.Fatalf("expected an empty wishlist not to be recorded, got %+v", *entries)
+	}
+
+	repo.Create(ctx, &models.Wishlist{UserID: "user-1", Items: []models.WishlistItem{{UniqueName: "/Lotus/Soma", Quantity: 2}}})
+	repo.AddItem(ctx, "user-1", models.WishlistItem{UniqueName: "/Lotus/Braton", Quantity: 1})
+	repo.UpdateItemQuantity(ctx, "user-1", "/Lotus/Soma", 3)
+	repo.SetItemRecipe(ctx, "user-1", "/Lotus/Soma", "alt")
+	repo.RemoveItem(ctx, "user-1", "/Lotus/Soma")
+
+	want := []string{models.ActivityAdd, models.ActivityAdd, models.ActivityUpdate, models.ActivityUpdate, models.ActivityRemove}
+	if len(*entries) != len(want) {
+		t.Fatalf("expected %d entries, got %+v", len(want), *entries)
+	}
+	for i, entry := range *entries {
+		if entry.Action != want[i] || entry.UserID != "user-1" || entry.Target != models.ActivityTargetWishlist {
+			t.Errorf("entry %d: expected a wishlist %s by user-1, got %+v", i, want[i], entry)
+		}
+		if entry.RequestID != "req-1" || !entry.CreatedAt.Equal(now) {
+			t.Errorf("entry %d: expected the request ID and time, got %+v", i, entry)
+		}
+	}
+
+	if payload := payloadOf(t, (*entries)[0]); payload["uniqueName"] != "/Lotus/Soma" || payload["quantity"] != 2.0 {
+		t.Errorf("expected the first item in the payload, got %v", payload)
+	}
+	if payload := payloadOf(t, (*entries)[1]); payload["uniqueName"] != "/Lotus/Braton" || payload["quantity"] != 1.0 {
+		t.Errorf("expected the added item in the payload, got %v", payload)
+	}
+	if payload := payloadOf(t, (*entries)[2]); payload["field"] != "quantity" || payload["quantity"] != 3.0 {
+		t.Errorf("expected the new quantity in the payload, got %v", payload)
+	}
+	if payload := payloadOf(t, (*entries)[3]); payload["field"] != "recipe" || payload["recipeId"] != "alt" {
+		t.Errorf("expected the new recipe in the payload, got %v", payload)
+	}
+}
+
+func TestActivityWishlistRepository_SkipsFailedWrites(t *testing.T) {
+	activity, entries := newActivityLog()
+	writeErr := errors.New("write failed")
+	repo := NewActivityWishlistRepository(&mocks.MockWishlistRepository{
+		AddItemFunc: func(ctx context.Context, userID string, item models.WishlistItem) error {
+			return writeErr
+		},
+	}, activity)
+
+	if err := repo.AddItem(context.Background(), "user-1", models.WishlistItem{UniqueName: "/Lotus/Soma", Quantity: 1}); !errors.Is(err, writeErr) {
+		t.Fatalf("expected the write error, got %v", err)
+	}
+	if len(*entries) != 0 {
+		t.Errorf("expected a failed write not to be recorded, got %+v", *entries)
+	}
+}
+
+func TestActivityWishlistRepository_IgnoresRecordingErrors(t *testing.T) {
+	repo := NewActivityWishlistRepository(&mocks.MockWishlistRepository{}, &mocks.MockActivityRepository{
+		CreateFunc: func(ctx context.Context, entry *models.ActivityEntry) error {
+			return errors.New("activity log unavailable")
+		},
+	})
+
+	if err := repo.RemoveItem(context.Background(), "user-1", "/Lotus/Soma"); err != nil {
+		t.Errorf("expected the change to succeed without its activity entry, got %v", err)
+	}
+}
+
+func TestActivityOwnedBlueprintsRepository_RecordsChanges(t *testing.T) {
+	activity, entries := newActivityLog()
+	repo := NewActivityOwnedBlueprintsRepository(&mocks.MockOwnedBlueprintsRepository{}, activity)
+	ctx := context.Background()
+
+	repo.BulkAddBlueprints(ctx, "user-1", []models.OwnedBlueprint{{UniqueName: "/Lotus/A", Quantity: 1}, {UniqueName: "/Lotus/B", Quantity: 1}})
+	repo.SetComponent(ctx, "user-1", models.OwnedComponent{UniqueName: "/Lotus/Chassis", Count: 2})
+	repo.ClearComponents(ctx, "user-1")
+	repo.ClearAll(ctx, "user-1")
+
+	want := []struct{ target, action string }{
+		{models.ActivityTargetBlueprints, models.ActivityAdd},
+		{models.ActivityTargetComponents, models.ActivityUpdate},
+		{models.ActivityTargetComponents, models.ActivityClear},
+		{models.ActivityTargetBlueprints, models.ActivityClear},
+	}
+	if len(*entries) != len(want) {
+		t.Fatalf("expected %d entries, got %+v", len(want), *entries)
+	}
+	for i, entry := range *entries {
+		if entry.Target != want[i].target || entry.Action != want[i].action {
+			t.Errorf("entry %d: expected %s %s, got %+v", i, want[i].target, want[i].action, entry)
+		}
+	}
+
+	if payload := payloadOf(t, (*entries)[0]); len(payload["uniqueNames"].([]any)) != 2 {
+		t.Errorf("expected both blueprints in the payload, got %v", payload)
+	}
+	if payload := payloadOf(t, (*entries)[1]); payload["uniqueName"] != "/Lotus/Chassis" || payload["count"] != 2.0 {
+		t.Errorf("expected the new count in the payload, got %v", payload)
+	}
+}
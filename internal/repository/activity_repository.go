@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/database"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const activityCollection = "activity"
+
+type ActivityRepository struct {
+	db         *database.MongoDB
+	collection *mongo.Collection
+}
+
+func NewActivityRepository(db *database.MongoDB) *ActivityRepository {
+	return &ActivityRepository{
+		db:         db,
+		collection: db.Collection(activityCollection),
+	}
+}
+
+// EnsureIndexes creates the index a user's activity is read by and the TTL
+// index that removes entries after models.ActivityRetentionDays.
+func (r *ActivityRepository) EnsureIndexes(ctx context.Context) error {
+	logger.Debug(ctx, "repo: ActivityRepository.EnsureIndexes called")
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "userId", Value: 1}, {Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}},
+			Options: options.Index().SetName("user_created"),
+		},
+		{
+			Keys:    bson.D{{Key: "createdAt", Value: 1}},
+			Options: options.Index().SetName("retention").SetExpireAfterSeconds(int32(models.ActivityRetentionDays * 24 * 60 * 60)),
+		},
+	})
+	if err != nil {
+		logger.Error(ctx, "repo: ActivityRepository.EnsureIndexes - error creating indexes", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (r *ActivityRepository) Create(ctx context.Context, entry *models.ActivityEntry) error {
+	logger.Debug(ctx, "repo: ActivityRepository.Create called", "userID", entry.UserID, "target", entry.Target, "action", entry.Action)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	entry.ID = primitive.NewObjectID()
+	if _, err := r.collection.InsertOne(ctx, entry); err != nil {
+		logger.Error(ctx, "repo: ActivityRepository.Create - error inserting entry", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *ActivityRepository) ListByUser(ctx context.Context, userID string, offset, limit int) ([]models.ActivityEntry, int, error) {
+	logger.Debug(ctx, "repo: ActivityRepository.ListByUser called", "userID", userID, "offset", offset, "limit", limit)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{"userId": userID}
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		logger.Error(ctx, "repo: ActivityRepository.ListByUser - error counting entries", "error", err)
+		return nil, 0, err
+	}
+
+	entries := []models.ActivityEntry{}
+	if int64(offset) >= total {
+		return entries, int(total), nil
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+	if err := findAll(ctx, "ActivityRepository.ListByUser", r.collection, filter, &entries, opts); err != nil {
+		logger.Error(ctx, "repo: ActivityRepository.ListByUser - error querying database", "error", err)
+		return nil, 0, err
+	}
+
+	logger.Debug(ctx, "repo: ActivityRepository.ListByUser - completed", "count", len(entries), "total", total)
+	return entries, int(total), nil
+}
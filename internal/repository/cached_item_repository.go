@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+)
+
+// CachedItemRepository caches uniqueName lookups in front of another item
+// repository. Item data only changes when the WFCD sync runs, so entries live
+// for ttl and are dropped wholesale by Purge after each sync. Misses are cached
+// too, since a miss against MongoDB scans every collection. Search methods are
+// passed through uncached.
+type CachedItemRepository struct {
+	next ItemRepositoryInterface
+	ttl  time.Duration
+	now  func() time.Time
+
+	mu      sync.RWMutex
+	entries map[string]cachedItem
+}
+
+type cachedItem struct {
+	item      *models.Item
+	expiresAt time.Time
+}
+
+func NewCachedItemRepository(next ItemRepositoryInterface, ttl time.Duration) *CachedItemRepository {
+	return &CachedItemRepository{
+		next:    next,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]cachedItem),
+	}
+}
+
+func (r *CachedItemRepository) Search(ctx context.Context, params models.SearchParams) (*models.ItemSearchPage, error) {
+	return r.next.Search(ctx, params)
+}
+
+func (r *CachedItemRepository) SearchReusableBlueprints(ctx context.Context, query string, limit int) ([]models.ItemSearchResult, error) {
+	return r.next.SearchReusableBlueprints(ctx, query, limit)
+}
+
+func (r *CachedItemRepository) FindByUniqueName(ctx context.Context, uniqueName string) (*models.Item, error) {
+	if item, ok := r.get(uniqueName); ok {
+		logger.Debug(ctx, "repo: CachedItemRepository.FindByUniqueName - cache hit", "uniqueName", uniqueName)
+		return item, nil
+	}
+
+	item, err := r.next.FindByUniqueName(ctx, uniqueName)
+	if err != nil {
+		return nil, err
+	}
+
+	r.set(map[string]*models.Item{uniqueName: item})
+	return item.Clone(), nil
+}
+
+func (r *CachedItemRepository) FindByUniqueNames(ctx context.Context, uniqueNames []string) (map[string]*models.Item, error) {
+	result := make(map[string]*models.Item, len(uniqueNames))
+
+	var missing []string
+	for _, uniqueName := range uniqueNames {
+		item, ok := r.get(uniqueName)
+		if !ok {
+			missing = append(missing, uniqueName)
+			continue
+		}
+		if item != nil {
+			result[uniqueName] = item
+		}
+	}
+
+	logger.Debug(ctx, "repo: CachedItemRepository.FindByUniqueNames - cache lookup", "hits", len(uniqueNames)-len(missing), "misses", len(missing))
+	if len(missing) == 0 {
+		return result, nil
+	}
+
+	found, err := r.next.FindByUniqueNames(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+
+	fetched := make(map[string]*models.Item, len(missing))
+	for _, uniqueName := range missing {
+		fetched[uniqueName] = found[uniqueName]
+	}
+	r.set(fetched)
+
+	for uniqueName, item := range found {
+		result[uniqueName] = item.Clone()
+	}
+	return result, nil
+}
+
+// Purge drops every cached entry.
+func (r *CachedItemRepository) Purge() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = make(map[string]cachedItem)
+}
+
+// Len returns the number of cached entries, including expired ones that have
+// not been replaced yet.
+func (r *CachedItemRepository) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.entries)
+}
+
+// get returns a copy of the cached item and whether the entry was present.
+// A present entry with a nil item is a cached miss.
+func (r *CachedItemRepository) get(uniqueName string) (*models.Item, bool) {
+	r.mu.RLock()
+	entry, ok := r.entries[uniqueName]
+	r.mu.RUnlock()
+
+	if !ok || !r.now().Before(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.item.Clone(), true
+}
+
+func (r *CachedItemRepository) set(items map[string]*models.Item) {
+	expiresAt := r.now().Add(r.ttl)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for uniqueName, item := range items {
+		r.entries[uniqueName] = cachedItem{item: item.Clone(), expiresAt: expiresAt}
+	}
+}
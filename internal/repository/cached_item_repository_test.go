@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+func newCountingItemRepo(items ...models.Item) (*mocks.MockItemRepository, *int, *[][]string) {
+	byName := make(map[string]models.Item, len(items))
+	for _, item := range items {
+		byName[item.UniqueName] = item
+	}
+
+	single := 0
+	var batches [][]string
+	repo := &mocks.MockItemRepository{
+		FindByUniqueNameFunc: func(ctx context.Context, uniqueName string) (*models.Item, error) {
+			single++
+			if item, ok := byName[uniqueName]; ok {
+				return &item, nil
+			}
+			return nil, nil
+		},
+		FindByUniqueNamesFunc: func(ctx context.Context, uniqueNames []string) (map[string]*models.Item, error) {
+			batches = append(batches, append([]string(nil), uniqueNames...))
+			result := make(map[string]*models.Item)
+			for _, uniqueName := range uniqueNames {
+				if item, ok := byName[uniqueName]; ok {
+					result[uniqueName] = &item
+				}
+			}
+			return result, nil
+		},
+	}
+	return repo, &single, &batches
+}
+
+func TestCachedItemRepository_FindByUniqueName_CachesHitsAndMisses(t *testing.T) {
+	ctx := context.Background()
+	next, calls, _ := newCountingItemRepo(models.Item{UniqueName: "/Lotus/Forma", Name: "Forma"})
+	repo := NewCachedItemRepository(next, time.Minute)
+
+	for i := 0; i < 3; i++ {
+		item, err := repo.FindByUniqueName(ctx, "/Lotus/Forma")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if item == nil || item.Name != "Forma" {
+			t.Fatalf("expected Forma, got %+v", item)
+		}
+
+		missing, err := repo.FindByUniqueName(ctx, "/Lotus/Missing")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if missing != nil {
+			t.Fatalf("expected nil for missing item, got %+v", missing)
+		}
+	}
+
+	if *calls != 2 {
+		t.Errorf("expected 2 underlying lookups, got %d", *calls)
+	}
+	if repo.Len() != 2 {
+		t.Errorf("expected 2 cached entries, got %d", repo.Len())
+	}
+}
+
+func TestCachedItemRepository_FindByUniqueName_ReturnsCopies(t *testing.T) {
+	ctx := context.Background()
+	next, _, _ := newCountingItemRepo(models.Item{
+		UniqueName: "/Lotus/Ash",
+		Components: []models.Component{{UniqueName: "/Lotus/AshChassis", ItemCount: 1}},
+	})
+	repo := NewCachedItemRepository(next, time.Minute)
+
+	first, _ := repo.FindByUniqueName(ctx, "/Lotus/Ash")
+	first.Components[0].ItemCount = 99
+
+	second, _ := repo.FindByUniqueName(ctx, "/Lotus/Ash")
+	if second.Components[0].ItemCount != 1 {
+		t.Errorf("expected cached item to be unaffected by caller mutation, got itemCount %d", second.Components[0].ItemCount)
+	}
+}
+
+func TestCachedItemRepository_FindByUniqueName_Expires(t *testing.T) {
+	ctx := context.Background()
+	next, calls, _ := newCountingItemRepo(models.Item{UniqueName: "/Lotus/Forma"})
+	repo := NewCachedItemRepository(next, time.Minute)
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo.now = func() time.Time { return now }
+
+	repo.FindByUniqueName(ctx, "/Lotus/Forma")
+	now = now.Add(59 * time.Second)
+	repo.FindByUniqueName(ctx, "/Lotus/Forma")
+	if *calls != 1 {
+		t.Fatalf("expected 1 underlying lookup before expiry, got %d", *calls)
+	}
+
+	now = now.Add(time.Second)
+	repo.FindByUniqueName(ctx, "/Lotus/Forma")
+	if *calls != 2 {
+		t.Errorf("expected 2 underlying lookups after expiry, got %d", *calls)
+	}
+}
+
+func TestCachedItemRepository_FindByUniqueName_DoesNotCacheErrors(t *testing.T) {
+	ctx := context.Background()
+	calls := 0
+	next := &mocks.MockItemRepository{
+		FindByUniqueNameFunc: func(ctx context.Context, uniqueName string) (*models.Item, error) {
+			calls++
+			return nil, errors.New("database error")
+		},
+	}
+	repo := NewCachedItemRepository(next, time.Minute)
+
+	for i := 0; i < 2; i++ {
+		if _, err := repo.FindByUniqueName(ctx, "/Lotus/Forma"); err == nil {
+			t.Fatal("expected error, got nil")
+		}
+	}
+	if calls != 2 {
+		t.Errorf("expected errors to bypass the cache, got %d calls", calls)
+	}
+	if repo.Len() != 0 {
+		t.Errorf("expected empty cache, got %d entries", repo.Len())
+	}
+}
+
+func TestCachedItemRepository_FindByUniqueNames_FetchesOnlyMisses(t *testing.T) {
+	ctx := context.Background()
+	next, _, batches := newCountingItemRepo(
+		models.Item{UniqueName: "/Lotus/A"},
+		models.Item{UniqueName: "/Lotus/B"},
+		models.Item{UniqueName: "/Lotus/C"},
+	)
+	repo := NewCachedItemRepository(next, time.Minute)
+
+	if _, err := repo.FindByUniqueNames(ctx, []string{"/Lotus/A", "/Lotus/Missing"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := repo.FindByUniqueNames(ctx, []string{"/Lotus/A", "/Lotus/B", "/Lotus/C", "/Lotus/Missing"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var names []string
+	for name := range result {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if !reflect.DeepEqual(names, []string{"/Lotus/A", "/Lotus/B", "/Lotus/C"}) {
+		t.Errorf("unexpected result keys: %v", names)
+	}
+
+	expected := [][]string{{"/Lotus/A", "/Lotus/Missing"}, {"/Lotus/B", "/Lotus/C"}}
+	if !reflect.DeepEqual(*batches, expected) {
+		t.Errorf("expected underlying batches %v, got %v", expected, *batches)
+	}
+
+	if _, err := repo.FindByUniqueNames(ctx, []string{"/Lotus/C", "/Lotus/Missing"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(*batches) != 2 {
+		t.Errorf("expected fully cached lookup to skip the underlying repository, got %d batches", len(*batches))
+	}
+}
+
+func TestCachedItemRepository_Purge(t *testing.T) {
+	ctx := context.Background()
+	next, calls, _ := newCountingItemRepo(models.Item{UniqueName: "/Lotus/Forma"})
+	repo := NewCachedItemRepository(next, time.Minute)
+
+	repo.FindByUniqueName(ctx, "/Lotus/Forma")
+	repo.Purge()
+	if repo.Len() != 0 {
+		t.Fatalf("expected empty cache after purge, got %d entries", repo.Len())
+	}
+
+	repo.FindByUniqueName(ctx, "/Lotus/Forma")
+	if *calls != 2 {
+		t.Errorf("expected lookup after purge to hit the underlying repository, got %d calls", *calls)
+	}
+}
@@ -0,0 +1,411 @@
+package repository_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/database"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+	"github.com/graytonio/warframe-wishlist/internal/repository/repotest"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// skipWithoutMongo skips the contract suite unless MONGO_TEST_URI is set.
+func skipWithoutMongo(t *testing.T) {
+	t.Helper()
+	if os.Getenv("MONGO_TEST_URI") == "" {
+		t.Skip("MONGO_TEST_URI not set, skipping MongoDB contract tests")
+	}
+}
+
+// newContractDB connects to MONGO_TEST_URI and returns a throwaway database
+// that is dropped when the test finishes.
+func newContractDB(t *testing.T) *database.MongoDB {
+	t.Helper()
+
+	db, err := database.NewMongoDB(os.Getenv("MONGO_TEST_URI"), "wishlist_contract_"+primitive.NewObjectID().Hex())
+	if err != nil {
+		t.Fatalf("failed to connect to MongoDB: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Database.Drop(context.Background())
+		db.Close()
+	})
+	return db
+}
+
+func TestItemRepository_Contract(t *testing.T) {
+	skipWithoutMongo(t)
+	repotest.RunItemRepositoryContract(t, func(t *testing.T, seed repotest.ItemSeed) repository.ItemRepositoryInterface {
+		return repository.NewItemRepository(newSeededDB(t, seed))
+	})
+}
+
+func TestItemRepository_CatalogContract(t *testing.T) {
+	skipWithoutMongo(t)
+	repotest.RunItemCatalogContract(t, func(t *testing.T, seed repotest.ItemSeed) repository.ItemCatalogInterface {
+		return repository.NewItemRepository(newSeededDB(t, seed))
+	})
+}
+
+func TestItemRepository_RelicCatalogContract(t *testing.T) {
+	skipWithoutMongo(t)
+	repotest.RunRelicCatalogContract(t, func(t *testing.T, seed repotest.ItemSeed) repository.RelicCatalogInterface {
+		return repository.NewItemRepository(newSeededDB(t, seed))
+	})
+}
+
+func TestItemRepository_ItemExportContract(t *testing.T) {
+	skipWithoutMongo(t)
+	repotest.RunItemExportContract(t, func(t *testing.T, seed repotest.ItemSeed) repository.ItemExportInterface {
+		return repository.NewItemRepository(newSeededDB(t, seed))
+	})
+}
+
+func TestItemRepository_ItemGraphContract(t *testing.T) {
+	skipWithoutMongo(t)
+	repotest.RunItemGraphContract(t, func(t *testing.T, seed repotest.ItemSeed) repository.ItemGraphInterface {
+		repo := repository.NewItemRepository(newSeededDB(t, seed))
+		if err := repo.RebuildItemGraph(context.Background()); err != nil {
+			t.Fatalf("failed to build item graph: %v", err)
+		}
+		return repo
+	})
+}
+
+func TestItemRepository_FindComponentTreeBeforeRebuild(t *testing.T) {
+	skipWithoutMongo(t)
+	repo := repository.NewItemRepository(newSeededDB(t, repotest.ItemSeed{
+		"warframes": `[{"uniqueName": "/Lotus/Powersuits/Alpha", "name": "Alpha Frame"}]`,
+	}))
+
+	if _, err := repo.FindComponentTree(context.Background(), []string{"/Lotus/Powersuits/Alpha"}); !errors.Is(err, repository.ErrItemGraphNotBuilt) {
+		t.Errorf("expected ErrItemGraphNotBuilt, got %v", err)
+	}
+}
+
+// newUnifiedItemRepository seeds the item collections, consolidates them
+// with ConsolidateItems and reads the result under ItemLayoutUnified.
+func newUnifiedItemRepository(t *testing.T, seed repotest.ItemSeed) *repository.ItemRepository {
+	t.Helper()
+
+	db := newSeededDB(t, seed)
+	if _, err := repository.ConsolidateItems(context.Background(), db, false); err != nil {
+		t.Fatalf("failed to consolidate items: %v", err)
+	}
+	repo := repository.NewItemRepository(db)
+	repo.SetLayout(repository.ItemLayoutUnified)
+	return repo
+}
+
+func TestItemRepository_UnifiedContract(t *testing.T) {
+	skipWithoutMongo(t)
+	repotest.RunItemRepositoryContract(t, func(t *testing.T, seed repotest.ItemSeed) repository.ItemRepositoryInterface {
+		return newUnifiedItemRepository(t, seed)
+	})
+}
+
+func TestItemRepository_UnifiedCatalogContract(t *testing.T) {
+	skipWithoutMongo(t)
+	repotest.RunItemCatalogContract(t, func(t *testing.T, seed repotest.ItemSeed) repository.ItemCatalogInterface {
+		return newUnifiedItemRepository(t, seed)
+	})
+}
+
+func TestItemRepository_UnifiedRelicCatalogContract(t *testing.T) {
+	skipWithoutMongo(t)
+	repotest.RunRelicCatalogContract(t, func(t *testing.T, seed repotest.ItemSeed) repository.RelicCatalogInterface {
+		return newUnifiedItemRepository(t, seed)
+	})
+}
+
+func TestItemRepository_UnifiedItemExportContract(t *testing.T) {
+	skipWithoutMongo(t)
+	repotest.RunItemExportContract(t, func(t *testing.T, seed repotest.ItemSeed) repository.ItemExportInterface {
+		return newUnifiedItemRepository(t, seed)
+	})
+}
+
+func TestItemRepository_UnifiedItemGraphContract(t *testing.T) {
+	skipWithoutMongo(t)
+	repotest.RunItemGraphContract(t, func(t *testing.T, seed repotest.ItemSeed) repository.ItemGraphInterface {
+		repo := newUnifiedItemRepository(t, seed)
+		if err := repo.RebuildItemGraph(context.Background()); err != nil {
+			t.Fatalf("failed to build item graph: %v", err)
+		}
+		return repo
+	})
+}
+
+// newSeededDB returns a throwaway database with the seed items inserted.
+func newSeededDB(t *testing.T, seed repotest.ItemSeed) *database.MongoDB {
+	t.Helper()
+
+	db := newContractDB(t)
+	for collection, data := range seed {
+		var docs []interface{}
+		if err := json.Unmarshal([]byte(data), &docs); err != nil {
+			t.Fatalf("failed to decode seed for %s: %v", collection, err)
+		}
+		if _, err := db.Collection(collection).InsertMany(context.Background(), docs); err != nil {
+			t.Fatalf("failed to seed %s: %v", collection, err)
+		}
+	}
+	return db
+}
+
+func TestWishlistRepository_Contract(t *testing.T) {
+	skipWithoutMongo(t)
+	repotest.RunWishlistRepositoryContract(t, func(t *testing.T) repository.WishlistRepositoryInterface {
+		return repository.NewWishlistRepository(newContractDB(t))
+	})
+}
+
+func TestOwnedBlueprintsRepository_Contract(t *testing.T) {
+	skipWithoutMongo(t)
+	repotest.RunOwnedBlueprintsRepositoryContract(t, func(t *testing.T) repository.OwnedBlueprintsRepositoryInterface {
+		return repository.NewOwnedBlueprintsRepository(newContractDB(t))
+	})
+}
+
+// migrationStore seeds outdated documents straight into the collections the
+// Mongo repositories read.
+type migrationStore struct {
+	db *database.MongoDB
+}
+
+func (s migrationStore) Wishlists() repository.WishlistRepositoryInterface {
+	return repository.NewWishlistRepository(s.db)
+}
+
+func (s migrationStore) OwnedBlueprints() repository.OwnedBlueprintsRepositoryInterface {
+	return repository.NewOwnedBlueprintsRepository(s.db)
+}
+
+func (s migrationStore) InsertLegacyWishlist(ctx context.Context, wishlist *models.Wishlist) error {
+	wishlist.SchemaVersion = 0
+	_, err := s.db.Collection(repository.WishlistCollection).InsertOne(ctx, wishlist)
+	return err
+}
+
+func (s migrationStore) InsertLegacyOwnedBlueprints(ctx context.Context, ownedBlueprints *models.OwnedBlueprints) error {
+	ownedBlueprints.SchemaVersion = 0
+	_, err := s.db.Collection(repository.OwnedBlueprintsCollection).InsertOne(ctx, ownedBlueprints)
+	return err
+}
+
+func (s migrationStore) Migrate(ctx context.Context, afterRead func()) (repository.MigrationStats, error) {
+	migrator := repository.NewSchemaMigrator(s.db)
+	migrator.SetAfterRead(afterRead)
+	return migrator.Run(ctx)
+}
+
+func TestSchemaMigrator_Contract(t *testing.T) {
+	skipWithoutMongo(t)
+	repotest.RunSchemaMigrationContract(t, func(t *testing.T) repotest.SchemaMigrationStore {
+		return migrationStore{db: newContractDB(t)}
+	})
+}
+
+func TestOwnedMaterialsRepository_Contract(t *testing.T) {
+	skipWithoutMongo(t)
+	repotest.RunOwnedMaterialsRepositoryContract(t, func(t *testing.T) repository.OwnedMaterialsRepositoryInterface {
+		return repository.NewOwnedMaterialsRepository(newContractDB(t))
+	})
+}
+
+func TestMasteryRepository_Contract(t *testing.T) {
+	skipWithoutMongo(t)
+	repotest.RunMasteryRepositoryContract(t, func(t *testing.T) repository.MasteryRepositoryInterface {
+		repo := repository.NewMasteryRepository(newContractDB(t))
+		if err := repo.EnsureIndexes(context.Background()); err != nil {
+			t.Fatalf("failed to create mastery indexes: %v", err)
+		}
+		return repo
+	})
+}
+
+func TestSettingsRepository_Contract(t *testing.T) {
+	skipWithoutMongo(t)
+	repotest.RunSettingsRepositoryContract(t, func(t *testing.T) repository.SettingsRepositoryInterface {
+		return repository.NewSettingsRepository(newContractDB(t))
+	})
+}
+
+func TestUserTraceRepository_Contract(t *testing.T) {
+	skipWithoutMongo(t)
+	repotest.RunUserTraceRepositoryContract(t, func(t *testing.T) repository.UserTraceRepositoryInterface {
+		return repository.NewUserTraceRepository(newContractDB(t))
+	})
+}
+
+func TestGiftClaimRepository_Contract(t *testing.T) {
+	skipWithoutMongo(t)
+	repotest.RunGiftClaimRepositoryContract(t, func(t *testing.T) repository.GiftClaimRepositoryInterface {
+		repo := repository.NewGiftClaimRepository(newContractDB(t))
+		if err := repo.EnsureIndexes(context.Background()); err != nil {
+			t.Fatalf("failed to create gift claim indexes: %v", err)
+		}
+		return repo
+	})
+}
+
+func TestIdempotencyRepository_Contract(t *testing.T) {
+	skipWithoutMongo(t)
+	repotest.RunIdempotencyRepositoryContract(t, func(t *testing.T) repository.IdempotencyRepositoryInterface {
+		repo := repository.NewIdempotencyRepository(newContractDB(t))
+		if err := repo.EnsureIndexes(context.Background()); err != nil {
+			t.Fatalf("failed to create idempotency indexes: %v", err)
+		}
+		return repo
+	})
+}
+
+func TestShareLinkRepository_Contract(t *testing.T) {
+	skipWithoutMongo(t)
+	repotest.RunShareLinkRepositoryContract(t, func(t *testing.T) repository.ShareLinkRepositoryInterface {
+		repo := repository.NewShareLinkRepository(newContractDB(t))
+		if err := repo.EnsureIndexes(context.Background()); err != nil {
+			t.Fatalf("failed to create share link indexes: %v", err)
+		}
+		return repo
+	})
+}
+
+func TestShareLinkReportRepository_Contract(t *testing.T) {
+	skipWithoutMongo(t)
+	repotest.RunShareLinkReportRepositoryContract(t, func(t *testing.T) repository.ShareLinkReportRepositoryInterface {
+		repo := repository.NewShareLinkReportRepository(newContractDB(t))
+		if err := repo.EnsureIndexes(context.Background()); err != nil {
+			t.Fatalf("failed to create share link report indexes: %v", err)
+		}
+		return repo
+	})
+}
+
+func TestImportAliasRepository_Contract(t *testing.T) {
+	skipWithoutMongo(t)
+	repotest.RunImportAliasRepositoryContract(t, func(t *testing.T) repository.ImportAliasRepositoryInterface {
+		return repository.NewImportAliasRepository(newContractDB(t))
+	})
+}
+
+func TestCustomItemRepository_Contract(t *testing.T) {
+	skipWithoutMongo(t)
+	repotest.RunCustomItemRepositoryContract(t, func(t *testing.T) repository.CustomItemRepositoryInterface {
+		repo := repository.NewCustomItemRepository(newContractDB(t))
+		if err := repo.EnsureIndexes(context.Background()); err != nil {
+			t.Fatalf("failed to create custom item indexes: %v", err)
+		}
+		return repo
+	})
+}
+
+func TestFoundryRepository_Contract(t *testing.T) {
+	skipWithoutMongo(t)
+	repotest.RunFoundryRepositoryContract(t, func(t *testing.T) repository.FoundryRepositoryInterface {
+		repo := repository.NewFoundryRepository(newContractDB(t))
+		if err := repo.EnsureIndexes(context.Background()); err != nil {
+			t.Fatalf("failed to create foundry indexes: %v", err)
+		}
+		return repo
+	})
+}
+
+func TestWishlistTemplateRepository_Contract(t *testing.T) {
+	skipWithoutMongo(t)
+	repotest.RunWishlistTemplateRepositoryContract(t, func(t *testing.T) repository.WishlistTemplateRepositoryInterface {
+		repo := repository.NewWishlistTemplateRepository(newContractDB(t))
+		if err := repo.EnsureIndexes(context.Background()); err != nil {
+			t.Fatalf("failed to create wishlist template indexes: %v", err)
+		}
+		return repo
+	})
+}
+
+func TestNotificationRepository_Contract(t *testing.T) {
+	skipWithoutMongo(t)
+	repotest.RunNotificationRepositoryContract(t, func(t *testing.T) repository.NotificationRepositoryInterface {
+		repo := repository.NewNotificationRepository(newContractDB(t))
+		if err := repo.EnsureIndexes(context.Background()); err != nil {
+			t.Fatalf("failed to create notification indexes: %v", err)
+		}
+		return repo
+	})
+}
+
+func TestIntegrationRepository_Contract(t *testing.T) {
+	skipWithoutMongo(t)
+	repotest.RunIntegrationRepositoryContract(t, func(t *testing.T) repository.IntegrationRepositoryInterface {
+		repo := repository.NewIntegrationRepository(newContractDB(t))
+		if err := repo.EnsureIndexes(context.Background()); err != nil {
+			t.Fatalf("failed to create integration indexes: %v", err)
+		}
+		return repo
+	})
+}
+
+func TestWorkspaceRepository_Contract(t *testing.T) {
+	skipWithoutMongo(t)
+	repotest.RunWorkspaceRepositoryContract(t, func(t *testing.T) repository.WorkspaceRepositoryInterface {
+		repo := repository.NewWorkspaceRepository(newContractDB(t))
+		if err := repo.EnsureIndexes(context.Background()); err != nil {
+			t.Fatalf("failed to create workspace indexes: %v", err)
+		}
+		return repo
+	})
+}
+
+func TestWorkspaceContributionRepository_Contract(t *testing.T) {
+	skipWithoutMongo(t)
+	repotest.RunWorkspaceContributionRepositoryContract(t, func(t *testing.T) repository.WorkspaceContributionRepositoryInterface {
+		repo := repository.NewWorkspaceContributionRepository(newContractDB(t))
+		if err := repo.EnsureIndexes(context.Background()); err != nil {
+			t.Fatalf("failed to create workspace contribution indexes: %v", err)
+		}
+		return repo
+	})
+}
+
+func TestActivityRepository_Contract(t *testing.T) {
+	skipWithoutMongo(t)
+	repotest.RunActivityRepositoryContract(t, func(t *testing.T) repository.ActivityRepositoryInterface {
+		repo := repository.NewActivityRepository(newContractDB(t))
+		if err := repo.EnsureIndexes(context.Background()); err != nil {
+			t.Fatalf("failed to create activity indexes: %v", err)
+		}
+		return repo
+	})
+}
+
+func TestSyncStatusRepository_Contract(t *testing.T) {
+	skipWithoutMongo(t)
+	repotest.RunSyncStatusRepositoryContract(t, func(t *testing.T) repository.SyncStatusRepositoryInterface {
+		return repository.NewSyncStatusRepository(newContractDB(t))
+	})
+}
+
+func TestWishlistRepository_PopularityContract(t *testing.T) {
+	skipWithoutMongo(t)
+	repotest.RunPopularityContract(t, func(t *testing.T) repotest.PopularityRepository {
+		return repository.NewWishlistRepository(newContractDB(t))
+	})
+}
+
+func TestHouseholdRepository_Contract(t *testing.T) {
+	skipWithoutMongo(t)
+	repotest.RunHouseholdRepositoryContract(t, func(t *testing.T) repository.HouseholdRepositoryInterface {
+		return repository.NewHouseholdRepository(newContractDB(t))
+	})
+}
+
+func TestItemChangeRepository_Contract(t *testing.T) {
+	skipWithoutMongo(t)
+	repotest.RunItemChangeRepositoryContract(t, func(t *testing.T) repository.ItemChangeRepositoryInterface {
+		return repository.NewItemChangeRepository(newContractDB(t))
+	})
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/database"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const customItemsCollection = "user_items"
+
+type CustomItemRepository struct {
+	db         *database.MongoDB
+	collection *mongo.Collection
+}
+
+func NewCustomItemRepository(db *database.MongoDB) *CustomItemRepository {
+	return &CustomItemRepository{
+		db:         db,
+		collection: db.Collection(customItemsCollection),
+	}
+}
+
+// EnsureIndexes creates the index a user's items are listed and looked up by.
+func (r *CustomItemRepository) EnsureIndexes(ctx context.Context) error {
+	logger.Debug(ctx, "repo: CustomItemRepository.EnsureIndexes called")
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "userId", Value: 1}, {Key: "uniqueName", Value: 1}},
+		Options: options.Index().SetName("user_item").SetUnique(true),
+	})
+	if err != nil {
+		logger.Error(ctx, "repo: CustomItemRepository.EnsureIndexes - error creating indexes", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (r *CustomItemRepository) ListByUser(ctx context.Context, userID string) ([]models.CustomItem, error) {
+	logger.Debug(ctx, "repo: CustomItemRepository.ListByUser called", "userID", userID)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	items := []models.CustomItem{}
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}, {Key: "uniqueName", Value: 1}})
+	if err := findAll(ctx, "CustomItemRepository.ListByUser", r.collection, bson.M{"userId": userID}, &items, opts); err != nil {
+		logger.Error(ctx, "repo: CustomItemRepository.ListByUser - error querying database", "error", err)
+		return nil, err
+	}
+	for i := range items {
+		if items[i].Components == nil {
+			items[i].Components = []models.Component{}
+		}
+	}
+
+	logger.Debug(ctx, "repo: CustomItemRepository.ListByUser - completed", "count", len(items))
+	return items, nil
+}
+
+func (r *CustomItemRepository) Create(ctx context.Context, item *models.CustomItem) error {
+	logger.Debug(ctx, "repo: CustomItemRepository.Create called", "userID", item.UserID)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	item.ID = primitive.NewObjectID()
+	item.UniqueName = models.CustomItemUniqueName(item.ID)
+	if _, err := r.collection.InsertOne(ctx, item); err != nil {
+		logger.Error(ctx, "repo: CustomItemRepository.Create - error inserting item", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *CustomItemRepository) Update(ctx context.Context, item *models.CustomItem) (bool, error) {
+	logger.Debug(ctx, "repo: CustomItemRepository.Update called", "userID", item.UserID, "uniqueName", item.UniqueName)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{"userId": item.UserID, "uniqueName": item.UniqueName}
+	update := bson.M{"$set": bson.M{
+		"name":        item.Name,
+		"description": item.Description,
+		"buildPrice":  item.BuildPrice,
+		"components":  item.Components,
+		"updatedAt":   item.UpdatedAt,
+	}}
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		logger.Error(ctx, "repo: CustomItemRepository.Update - error updating item", "error", err)
+		return false, err
+	}
+
+	return result.MatchedCount > 0, nil
+}
+
+func (r *CustomItemRepository) Delete(ctx context.Context, userID, uniqueName string) (bool, error) {
+	logger.Debug(ctx, "repo: CustomItemRepository.Delete called", "userID", userID, "uniqueName", uniqueName)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"userId": userID, "uniqueName": uniqueName})
+	if err != nil {
+		logger.Error(ctx, "repo: CustomItemRepository.Delete - error deleting item", "error", err)
+		return false, err
+	}
+
+	return result.DeletedCount > 0, nil
+}
@@ -0,0 +1,13 @@
+package repository
+
+// Collection names for contract tests that seed documents directly.
+const (
+	WishlistCollection        = wishlistCollection
+	OwnedBlueprintsCollection = ownedBlueprintsCollection
+)
+
+// SetAfterRead makes Run call fn between reading each outdated document and
+// saving its migrated copy.
+func (m *SchemaMigrator) SetAfterRead(fn func()) {
+	m.afterRead = fn
+}
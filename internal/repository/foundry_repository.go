@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/database"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const foundryBuildsCollection = "foundry_builds"
+
+type FoundryRepository struct {
+	db         *database.MongoDB
+	collection *mongo.Collection
+}
+
+func NewFoundryRepository(db *database.MongoDB) *FoundryRepository {
+	return &FoundryRepository{
+		db:         db,
+		collection: db.Collection(foundryBuildsCollection),
+	}
+}
+
+// EnsureIndexes creates the index a user's builds are listed by.
+func (r *FoundryRepository) EnsureIndexes(ctx context.Context) error {
+	logger.Debug(ctx, "repo: FoundryRepository.EnsureIndexes called")
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "userId", Value: 1}, {Key: "startedAt", Value: 1}},
+		Options: options.Index().SetName("user_started"),
+	})
+	if err != nil {
+		logger.Error(ctx, "repo: FoundryRepository.EnsureIndexes - error creating indexes", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (r *FoundryRepository) ListByUser(ctx context.Context, userID string) ([]models.FoundryBuild, error) {
+	logger.Debug(ctx, "repo: FoundryRepository.ListByUser called", "userID", userID)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	builds := []models.FoundryBuild{}
+	opts := options.Find().SetSort(bson.D{{Key: "startedAt", Value: 1}, {Key: "_id", Value: 1}})
+	if err := findAll(ctx, "FoundryRepository.ListByUser", r.collection, bson.M{"userId": userID}, &builds, opts); err != nil {
+		logger.Error(ctx, "repo: FoundryRepository.ListByUser - error querying database", "error", err)
+		return nil, err
+	}
+
+	logger.Debug(ctx, "repo: FoundryRepository.ListByUser - completed", "count", len(builds))
+	return builds, nil
+}
+
+func (r *FoundryRepository) Create(ctx context.Context, build *models.FoundryBuild) error {
+	logger.Debug(ctx, "repo: FoundryRepository.Create called", "userID", build.UserID, "uniqueName", build.UniqueName)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	build.ID = primitive.NewObjectID()
+	if _, err := r.collection.InsertOne(ctx, build); err != nil {
+		logger.Error(ctx, "repo: FoundryRepository.Create - error inserting build", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *FoundryRepository) Delete(ctx context.Context, userID string, id primitive.ObjectID) (bool, error) {
+	logger.Debug(ctx, "repo: FoundryRepository.Delete called", "userID", userID, "id", id.Hex())
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id, "userId": userID})
+	if err != nil {
+		logger.Error(ctx, "repo: FoundryRepository.Delete - error deleting build", "error", err)
+		return false, err
+	}
+
+	return result.DeletedCount > 0, nil
+}
@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/database"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const giftClaimsCollection = "gift_claims"
+
+type GiftClaimRepository struct {
+	db         *database.MongoDB
+	collection *mongo.Collection
+}
+
+func NewGiftClaimRepository(db *database.MongoDB) *GiftClaimRepository {
+	return &GiftClaimRepository{
+		db:         db,
+		collection: db.Collection(giftClaimsCollection),
+	}
+}
+
+// EnsureIndexes creates the unique owner and item index Claim relies on to
+// keep one claim per item, the claimer index, and a TTL index that removes
+// expired claims.
+func (r *GiftClaimRepository) EnsureIndexes(ctx context.Context) error {
+	logger.Debug(ctx, "repo: GiftClaimRepository.EnsureIndexes called")
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "ownerId", Value: 1}, {Key: "uniqueName", Value: 1}},
+			Options: options.Index().SetName("owner_item").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "claimerId", Value: 1}, {Key: "expiresAt", Value: 1}},
+			Options: options.Index().SetName("claimer"),
+		},
+		{
+			Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+			Options: options.Index().SetName("expiry").SetExpireAfterSeconds(0),
+		},
+	})
+	if err != nil {
+		logger.Error(ctx, "repo: GiftClaimRepository.EnsureIndexes - error creating indexes", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (r *GiftClaimRepository) Get(ctx context.Context, ownerID, uniqueName string) (*models.GiftClaim, error) {
+	logger.Debug(ctx, "repo: GiftClaimRepository.Get called", "ownerID", ownerID, "uniqueName", uniqueName)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{"ownerId": ownerID, "uniqueName": uniqueName, "expiresAt": bson.M{"$gt": time.Now()}}
+	var claim models.GiftClaim
+	err := findOne(ctx, "GiftClaimRepository.Get", r.collection, filter, &claim)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		logger.Error(ctx, "repo: GiftClaimRepository.Get - error querying database", "error", err)
+		return nil, err
+	}
+
+	return &claim, nil
+}
+
+func (r *GiftClaimRepository) Claim(ctx context.Context, claim *models.GiftClaim) (*models.GiftClaim, error) {
+	logger.Debug(ctx, "repo: GiftClaimRepository.Claim called", "ownerID", claim.OwnerID, "uniqueName", claim.UniqueName, "claimerID", claim.ClaimerID)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+
+	// Renew the claimer's own active claim in place.
+	own := bson.M{"ownerId": claim.OwnerID, "uniqueName": claim.UniqueName, "claimerId": claim.ClaimerID, "expiresAt": bson.M{"$gt": now}}
+	update := bson.M{"$set": bson.M{"anonymous": claim.Anonymous, "expiresAt": claim.ExpiresAt}}
+	result, err := updateOne(ctx, "GiftClaimRepository.Claim", r.collection, own, update)
+	if err != nil {
+		logger.Error(ctx, "repo: GiftClaimRepository.Claim - error renewing claim", "error", err)
+		return nil, err
+	}
+	if result.MatchedCount > 0 {
+		return r.Get(ctx, claim.OwnerID, claim.UniqueName)
+	}
+
+	// The TTL index removes expired claims lazily, so drop one still present
+	// before inserting; the unique index turns a concurrent or active claim
+	// by someone else into a duplicate key error.
+	expired := bson.M{"ownerId": claim.OwnerID, "uniqueName": claim.UniqueName, "expiresAt": bson.M{"$lte": now}}
+	if _, err := r.collection.DeleteOne(ctx, expired); err != nil {
+		logger.Error(ctx, "repo: GiftClaimRepository.Claim - error removing expired claim", "error", err)
+		return nil, err
+	}
+
+	stored := *claim
+	stored.ID = primitive.NewObjectID()
+	if _, err := r.collection.InsertOne(ctx, &stored); err != nil {
+		if !mongo.IsDuplicateKeyError(err) {
+			logger.Error(ctx, "repo: GiftClaimRepository.Claim - error inserting claim", "error", err)
+			return nil, err
+		}
+		logger.Debug(ctx, "repo: GiftClaimRepository.Claim - item already claimed")
+		return r.Get(ctx, claim.OwnerID, claim.UniqueName)
+	}
+
+	return &stored, nil
+}
+
+func (r *GiftClaimRepository) ListByOwner(ctx context.Context, ownerID string) ([]models.GiftClaim, error) {
+	logger.Debug(ctx, "repo: GiftClaimRepository.ListByOwner called", "ownerID", ownerID)
+	return r.list(ctx, "GiftClaimRepository.ListByOwner", bson.M{"ownerId": ownerID}, bson.D{{Key: "uniqueName", Value: 1}})
+}
+
+func (r *GiftClaimRepository) ListByClaimer(ctx context.Context, claimerID string) ([]models.GiftClaim, error) {
+	logger.Debug(ctx, "repo: GiftClaimRepository.ListByClaimer called", "claimerID", claimerID)
+	return r.list(ctx, "GiftClaimRepository.ListByClaimer", bson.M{"claimerId": claimerID}, bson.D{{Key: "expiresAt", Value: 1}, {Key: "_id", Value: 1}})
+}
+
+func (r *GiftClaimRepository) list(ctx context.Context, name string, filter bson.M, sort bson.D) ([]models.GiftClaim, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	filter["expiresAt"] = bson.M{"$gt": time.Now()}
+	claims := []models.GiftClaim{}
+	if err := findAll(ctx, name, r.collection, filter, &claims, options.Find().SetSort(sort)); err != nil {
+		logger.Error(ctx, "repo: "+name+" - error querying database", "error", err)
+		return nil, err
+	}
+
+	logger.Debug(ctx, "repo: "+name+" - completed", "count", len(claims))
+	return claims, nil
+}
+
+func (r *GiftClaimRepository) Delete(ctx context.Context, ownerID, uniqueName, claimerID string) (bool, error) {
+	logger.Debug(ctx, "repo: GiftClaimRepository.Delete called", "ownerID", ownerID, "uniqueName", uniqueName, "claimerID", claimerID)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{"ownerId": ownerID, "uniqueName": uniqueName, "expiresAt": bson.M{"$gt": time.Now()}}
+	if claimerID != "" {
+		filter["claimerId"] = claimerID
+	}
+	result, err := r.collection.DeleteOne(ctx, filter)
+	if err != nil {
+		logger.Error(ctx, "repo: GiftClaimRepository.Delete - error deleting claim", "error", err)
+		return false, err
+	}
+
+	return result.DeletedCount > 0, nil
+}
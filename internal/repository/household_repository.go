@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/database"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	householdLinksCollection = "household_links"
+	pendingChangesCollection = "pending_changes"
+)
+
+type HouseholdRepository struct {
+	db      *database.MongoDB
+	links   *mongo.Collection
+	changes *mongo.Collection
+}
+
+func NewHouseholdRepository(db *database.MongoDB) *HouseholdRepository {
+	return &HouseholdRepository{
+		db:      db,
+		links:   db.Collection(householdLinksCollection),
+		changes: db.Collection(pendingChangesCollection),
+	}
+}
+
+func (r *HouseholdRepository) GetLinkByMember(ctx context.Context, memberID string) (*models.HouseholdLink, error) {
+	logger.Debug(ctx, "repo: HouseholdRepository.GetLinkByMember called", "memberID", memberID)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var link models.HouseholdLink
+	err := findOne(ctx, "HouseholdRepository.GetLinkByMember", r.links, bson.M{"memberId": memberID}, &link)
+	if err == mongo.ErrNoDocuments {
+		logger.Debug(ctx, "repo: HouseholdRepository.GetLinkByMember - no link found")
+		return nil, nil
+	}
+	if err != nil {
+		logger.Error(ctx, "repo: HouseholdRepository.GetLinkByMember - error querying database", "error", err)
+		return nil, err
+	}
+
+	return &link, nil
+}
+
+func (r *HouseholdRepository) ListLinksByManager(ctx context.Context, managerID string) ([]models.HouseholdLink, error) {
+	logger.Debug(ctx, "repo: HouseholdRepository.ListLinksByManager called", "managerID", managerID)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}})
+	links := []models.HouseholdLink{}
+	if err := findAll(ctx, "HouseholdRepository.ListLinksByManager", r.links, bson.M{"managerId": managerID}, &links, opts); err != nil {
+		logger.Error(ctx, "repo: HouseholdRepository.ListLinksByManager - error querying database", "error", err)
+		return nil, err
+	}
+
+	logger.Debug(ctx, "repo: HouseholdRepository.ListLinksByManager - completed", "linkCount", len(links))
+	return links, nil
+}
+
+func (r *HouseholdRepository) CreateLink(ctx context.Context, link *models.HouseholdLink) (bool, error) {
+	logger.Debug(ctx, "repo: HouseholdRepository.CreateLink called", "memberID", link.MemberID, "managerID", link.ManagerID)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	link.ID = primitive.NewObjectID()
+	link.CreatedAt = time.Now()
+	link.UpdatedAt = link.CreatedAt
+
+	// Upsert with $setOnInsert so concurrent requests cannot give a member
+	// two links.
+	result, err := r.links.UpdateOne(ctx,
+		bson.M{"memberId": link.MemberID},
+		bson.M{"$setOnInsert": link},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		logger.Error(ctx, "repo: HouseholdRepository.CreateLink - error creating link", "error", err)
+		return false, err
+	}
+
+	created := result.UpsertedCount > 0
+	logger.Debug(ctx, "repo: HouseholdRepository.CreateLink - completed", "created", created)
+	return created, nil
+}
+
+func (r *HouseholdRepository) UpdateLink(ctx context.Context, memberID, status string, quantityThreshold int) error {
+	logger.Debug(ctx, "repo: HouseholdRepository.UpdateLink called", "memberID", memberID, "status", status, "quantityThreshold", quantityThreshold)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	update := bson.M{
+		"$set": bson.M{
+			"status":            status,
+			"quantityThreshold": quantityThreshold,
+			"updatedAt":         time.Now(),
+		},
+	}
+
+	result, err := updateOne(ctx, "HouseholdRepository.UpdateLink", r.links, bson.M{"memberId": memberID}, update)
+	if err != nil {
+		logger.Error(ctx, "repo: HouseholdRepository.UpdateLink - error updating link", "error", err)
+		return err
+	}
+
+	logger.Debug(ctx, "repo: HouseholdRepository.UpdateLink - completed", "matchedCount", result.MatchedCount)
+	return nil
+}
+
+func (r *HouseholdRepository) DeleteLink(ctx context.Context, memberID string) error {
+	logger.Debug(ctx, "repo: HouseholdRepository.DeleteLink called", "memberID", memberID)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result, err := r.links.DeleteOne(ctx, bson.M{"memberId": memberID})
+	if err != nil {
+		logger.Error(ctx, "repo: HouseholdRepository.DeleteLink - error deleting link", "error", err)
+		return err
+	}
+
+	logger.Debug(ctx, "repo: HouseholdRepository.DeleteLink - completed", "deletedCount", result.DeletedCount)
+	return nil
+}
+
+func (r *HouseholdRepository) CreatePendingChange(ctx context.Context, change *models.PendingChange) error {
+	logger.Debug(ctx, "repo: HouseholdRepository.CreatePendingChange called", "memberID", change.MemberID, "type", change.Type, "uniqueName", change.UniqueName)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	change.ID = primitive.NewObjectID()
+	change.CreatedAt = time.Now()
+
+	if _, err := r.changes.InsertOne(ctx, change); err != nil {
+		logger.Error(ctx, "repo: HouseholdRepository.CreatePendingChange - error inserting change", "error", err)
+		return err
+	}
+
+	logger.Debug(ctx, "repo: HouseholdRepository.CreatePendingChange - completed", "changeID", change.ID.Hex())
+	return nil
+}
+
+func (r *HouseholdRepository) GetPendingChange(ctx context.Context, id primitive.ObjectID) (*models.PendingChange, error) {
+	logger.Debug(ctx, "repo: HouseholdRepository.GetPendingChange called", "changeID", id.Hex())
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var change models.PendingChange
+	err := findOne(ctx, "HouseholdRepository.GetPendingChange", r.changes, bson.M{"_id": id}, &change)
+	if err == mongo.ErrNoDocuments {
+		logger.Debug(ctx, "repo: HouseholdRepository.GetPendingChange - change not found")
+		return nil, nil
+	}
+	if err != nil {
+		logger.Error(ctx, "repo: HouseholdRepository.GetPendingChange - error querying database", "error", err)
+		return nil, err
+	}
+
+	return &change, nil
+}
+
+func (r *HouseholdRepository) ListChangesByManager(ctx context.Context, managerID, status string) ([]models.PendingChange, error) {
+	logger.Debug(ctx, "repo: HouseholdRepository.ListChangesByManager called", "managerID", managerID, "status", status)
+	return r.listChanges(ctx, "managerId", managerID, status)
+}
+
+func (r *HouseholdRepository) ListChangesByMember(ctx context.Context, memberID, status string) ([]models.PendingChange, error) {
+	logger.Debug(ctx, "repo: HouseholdRepository.ListChangesByMember called", "memberID", memberID, "status", status)
+	return r.listChanges(ctx, "memberId", memberID, status)
+}
+
+func (r *HouseholdRepository) listChanges(ctx context.Context, field, userID, status string) ([]models.PendingChange, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{field: userID}
+	if status != "" {
+		filter["status"] = status
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}})
+	changes := []models.PendingChange{}
+	if err := findAll(ctx, "HouseholdRepository.listChanges", r.changes, filter, &changes, opts); err != nil {
+		logger.Error(ctx, "repo: HouseholdRepository.listChanges - error querying database", "error", err)
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+func (r *HouseholdRepository) DecideChange(ctx context.Context, id primitive.ObjectID, status string) (bool, error) {
+	logger.Debug(ctx, "repo: HouseholdRepository.DecideChange called", "changeID", id.Hex(), "status", status)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": id, "status": models.ChangeStatusPending}
+	update := bson.M{"$set": bson.M{"status": status, "decidedAt": time.Now()}}
+
+	result, err := r.changes.UpdateOne(ctx, filter, update)
+	if err != nil {
+		logger.Error(ctx, "repo: HouseholdRepository.DecideChange - error updating change", "error", err)
+		return false, err
+	}
+
+	decided := result.ModifiedCount > 0
+	logger.Debug(ctx, "repo: HouseholdRepository.DecideChange - completed", "decided", decided)
+	return decided, nil
+}
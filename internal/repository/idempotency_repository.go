@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/database"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const idempotencyKeysCollection = "idempotency_keys"
+
+type IdempotencyRepository struct {
+	db         *database.MongoDB
+	collection *mongo.Collection
+}
+
+func NewIdempotencyRepository(db *database.MongoDB) *IdempotencyRepository {
+	return &IdempotencyRepository{
+		db:         db,
+		collection: db.Collection(idempotencyKeysCollection),
+	}
+}
+
+// EnsureIndexes creates the unique user and key index Reserve relies on to
+// keep one record per key, and a TTL index that removes expired records.
+func (r *IdempotencyRepository) EnsureIndexes(ctx context.Context) error {
+	logger.Debug(ctx, "repo: IdempotencyRepository.EnsureIndexes called")
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "userId", Value: 1}, {Key: "key", Value: 1}},
+			Options: options.Index().SetName("user_key").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+			Options: options.Index().SetName("expiry").SetExpireAfterSeconds(0),
+		},
+	})
+	if err != nil {
+		logger.Error(ctx, "repo: IdempotencyRepository.EnsureIndexes - error creating indexes", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (r *IdempotencyRepository) Reserve(ctx context.Context, record *models.IdempotencyRecord) (*models.IdempotencyRecord, error) {
+	logger.Debug(ctx, "repo: IdempotencyRepository.Reserve called", "userID", record.UserID)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+
+	// The TTL index removes expired records lazily, so drop one still present
+	// before inserting; the unique index turns an active record into a
+	// duplicate key error.
+	expired := bson.M{"userId": record.UserID, "key": record.Key, "expiresAt": bson.M{"$lte": now}}
+	if _, err := r.collection.DeleteOne(ctx, expired); err != nil {
+		logger.Error(ctx, "repo: IdempotencyRepository.Reserve - error removing expired record", "error", err)
+		return nil, err
+	}
+
+	stored := *record
+	stored.ID = primitive.NewObjectID()
+	stored.Completed = false
+	if _, err := r.collection.InsertOne(ctx, &stored); err != nil {
+		if !mongo.IsDuplicateKeyError(err) {
+			logger.Error(ctx, "repo: IdempotencyRepository.Reserve - error inserting record", "error", err)
+			return nil, err
+		}
+		var existing models.IdempotencyRecord
+		filter := bson.M{"userId": record.UserID, "key": record.Key, "expiresAt": bson.M{"$gt": now}}
+		if err := findOne(ctx, "IdempotencyRepository.Reserve", r.collection, filter, &existing); err != nil {
+			logger.Error(ctx, "repo: IdempotencyRepository.Reserve - error querying database", "error", err)
+			return nil, err
+		}
+		logger.Debug(ctx, "repo: IdempotencyRepository.Reserve - key already used", "completed", existing.Completed)
+		return &existing, nil
+	}
+
+	record.ID = stored.ID
+	return nil, nil
+}
+
+func (r *IdempotencyRepository) Complete(ctx context.Context, record *models.IdempotencyRecord) error {
+	logger.Debug(ctx, "repo: IdempotencyRepository.Complete called", "userID", record.UserID, "status", record.Status)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{"userId": record.UserID, "key": record.Key, "completed": false}
+	update := bson.M{"$set": bson.M{"completed": true, "status": record.Status, "header": record.Header, "body": record.Body}}
+	if _, err := updateOne(ctx, "IdempotencyRepository.Complete", r.collection, filter, update); err != nil {
+		logger.Error(ctx, "repo: IdempotencyRepository.Complete - error updating record", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (r *IdempotencyRepository) Release(ctx context.Context, userID, key string) error {
+	logger.Debug(ctx, "repo: IdempotencyRepository.Release called", "userID", userID)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if _, err := r.collection.DeleteOne(ctx, bson.M{"userId": userID, "key": key, "completed": false}); err != nil {
+		logger.Error(ctx, "repo: IdempotencyRepository.Release - error deleting record", "error", err)
+		return err
+	}
+	return nil
+}
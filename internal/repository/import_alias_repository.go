@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/database"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const importAliasesCollection = "import_aliases"
+
+// ImportAliasRepository stores one document per alias, keyed by its word.
+type ImportAliasRepository struct {
+	db         *database.MongoDB
+	collection *mongo.Collection
+}
+
+func NewImportAliasRepository(db *database.MongoDB) *ImportAliasRepository {
+	return &ImportAliasRepository{
+		db:         db,
+		collection: db.Collection(importAliasesCollection),
+	}
+}
+
+func (r *ImportAliasRepository) List(ctx context.Context) ([]models.ImportAlias, error) {
+	logger.Debug(ctx, "repo: ImportAliasRepository.List called")
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	aliases := []models.ImportAlias{}
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+	if err := findAll(ctx, "ImportAliasRepository.List", r.collection, bson.M{}, &aliases, opts); err != nil {
+		logger.Error(ctx, "repo: ImportAliasRepository.List - error querying database", "error", err)
+		return nil, err
+	}
+
+	return aliases, nil
+}
+
+func (r *ImportAliasRepository) Set(ctx context.Context, alias models.ImportAlias) error {
+	logger.Debug(ctx, "repo: ImportAliasRepository.Set called", "word", alias.Word)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	update := bson.M{"$set": bson.M{"expansion": alias.Expansion, "updatedAt": alias.UpdatedAt}}
+	opts := options.Update().SetUpsert(true)
+	if _, err := updateOne(ctx, "ImportAliasRepository.Set", r.collection, bson.M{"_id": alias.Word}, update, opts); err != nil {
+		logger.Error(ctx, "repo: ImportAliasRepository.Set - error upserting alias", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *ImportAliasRepository) Delete(ctx context.Context, word string) (bool, error) {
+	logger.Debug(ctx, "repo: ImportAliasRepository.Delete called", "word", word)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": word})
+	if err != nil {
+		logger.Error(ctx, "repo: ImportAliasRepository.Delete - error deleting alias", "error", err)
+		return false, err
+	}
+
+	return result.DeletedCount > 0, nil
+}
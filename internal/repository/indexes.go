@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/database"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// namespaceNotFound is the server error for listing the indexes of a
+// collection that does not exist.
+const namespaceNotFound = 26
+
+// RequiredIndexes names the indexes queries rely on, by collection, with the
+// item data stored in layout. The item collections (or
+// UnifiedItemsCollection) get theirs from cmd/sync, cmd/migrate-items or
+// EnsureSearchIndexes; the others are created by their repository's
+// EnsureIndexes at startup.
+func RequiredIndexes(layout ItemLayout) map[string][]string {
+	required := map[string][]string{
+		giftClaimsCollection:             {"owner_item", "claimer", "expiry"},
+		idempotencyKeysCollection:        {"user_key", "expiry"},
+		shareLinksCollection:             {"token", "user"},
+		customItemsCollection:            {"user_item"},
+		foundryBuildsCollection:          {"user_started"},
+		wishlistTemplatesCollection:      {"user_created"},
+		notificationChannelsCollection:   {"user_created"},
+		notificationDeliveriesCollection: {"channel_key", "status_due", "user_created", "retention"},
+		workspacesCollection:             {"member"},
+		workspaceContributionsCollection: {"workspace_created", "user"},
+		masteryCollection:                {"user_item"},
+		activityCollection:               {"user_created", "retention"},
+	}
+	if layout == ItemLayoutUnified {
+		required[UnifiedItemsCollection] = unifiedItemIndexNames
+		return required
+	}
+	for _, collName := range ItemCollections {
+		required[collName] = []string{"uniqueName_1", "item_search"}
+	}
+	return required
+}
+
+// MissingIndexes returns each required index that does not exist as
+// "collection.index", sorted. A missing collection has no indexes.
+func MissingIndexes(ctx context.Context, db *database.MongoDB, layout ItemLayout) ([]string, error) {
+	var missing []string
+	for collName, names := range RequiredIndexes(layout) {
+		existing, err := indexNames(ctx, db.Collection(collName))
+		if err != nil {
+			return nil, fmt.Errorf("listing indexes of %s: %w", collName, err)
+		}
+		for _, name := range names {
+			if !existing[name] {
+				missing = append(missing, collName+"."+name)
+			}
+		}
+	}
+	sort.Strings(missing)
+	return missing, nil
+}
+
+func indexNames(ctx context.Context, coll *mongo.Collection) (map[string]bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	specs, err := coll.Indexes().ListSpecifications(ctx)
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) && cmdErr.Code == namespaceNotFound {
+		return map[string]bool{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool, len(specs))
+	for _, spec := range specs {
+		names[spec.Name] = true
+	}
+	return names, nil
+}
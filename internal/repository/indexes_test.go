@@ -0,0 +1,68 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+)
+
+func TestMissingIndexes(t *testing.T) {
+	skipWithoutMongo(t)
+	ctx := context.Background()
+	db := newContractDB(t)
+
+	missing, err := repository.MissingIndexes(ctx, db, repository.ItemLayoutCollections)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := 0
+	for _, names := range repository.RequiredIndexes(repository.ItemLayoutCollections) {
+		want += len(names)
+	}
+	if len(missing) != want {
+		t.Fatalf("expected every index to be missing on an empty database, got %v", missing)
+	}
+
+	syncer := repository.NewItemSyncer(db)
+	for _, collName := range repository.ItemCollections {
+		if err := syncer.EnsureIndexes(ctx, collName); err != nil {
+			t.Fatalf("failed to create item indexes: %v", err)
+		}
+	}
+	if err := repository.NewGiftClaimRepository(db).EnsureIndexes(ctx); err != nil {
+		t.Fatalf("failed to create gift claim indexes: %v", err)
+	}
+	if err := repository.NewShareLinkRepository(db).EnsureIndexes(ctx); err != nil {
+		t.Fatalf("failed to create share link indexes: %v", err)
+	}
+	if err := repository.NewCustomItemRepository(db).EnsureIndexes(ctx); err != nil {
+		t.Fatalf("failed to create custom item indexes: %v", err)
+	}
+	if err := repository.NewFoundryRepository(db).EnsureIndexes(ctx); err != nil {
+		t.Fatalf("failed to create foundry indexes: %v", err)
+	}
+	if err := repository.NewWishlistTemplateRepository(db).EnsureIndexes(ctx); err != nil {
+		t.Fatalf("failed to create wishlist template indexes: %v", err)
+	}
+	if err := repository.NewWorkspaceRepository(db).EnsureIndexes(ctx); err != nil {
+		t.Fatalf("failed to create workspace indexes: %v", err)
+	}
+	if err := repository.NewWorkspaceContributionRepository(db).EnsureIndexes(ctx); err != nil {
+		t.Fatalf("failed to create workspace contribution indexes: %v", err)
+	}
+	if err := repository.NewMasteryRepository(db).EnsureIndexes(ctx); err != nil {
+		t.Fatalf("failed to create mastery indexes: %v", err)
+	}
+	if err := repository.NewActivityRepository(db).EnsureIndexes(ctx); err != nil {
+		t.Fatalf("failed to create activity indexes: %v", err)
+	}
+
+	missing, err = repository.MissingIndexes(ctx, db, repository.ItemLayoutCollections)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(missing) != 0 {
+		t.Errorf("expected no missing indexes, got %v", missing)
+	}
+}
@@ -0,0 +1,215 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/database"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	integrationsCollection          = "integrations"
+	integrationDeliveriesCollection = "integration_deliveries"
+)
+
+type IntegrationRepository struct {
+	db           *database.MongoDB
+	integrations *mongo.Collection
+	deliveries   *mongo.Collection
+}
+
+func NewIntegrationRepository(db *database.MongoDB) *IntegrationRepository {
+	return &IntegrationRepository{
+		db:           db,
+		integrations: db.Collection(integrationsCollection),
+		deliveries:   db.Collection(integrationDeliveriesCollection),
+	}
+}
+
+// EnsureIndexes creates the unique integration and key index EnqueueDelivery
+// relies on to send each batch once, the due and integration indexes
+// deliveries are read by, and a TTL index that removes deliveries after
+// models.DeliveryRetentionDays.
+func (r *IntegrationRepository) EnsureIndexes(ctx context.Context) error {
+	logger.Debug(ctx, "repo: IntegrationRepository.EnsureIndexes called")
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	_, err := r.deliveries.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "integrationId", Value: 1}, {Key: "key", Value: 1}},
+			Options: options.Index().SetName("integration_key").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "nextAttemptAt", Value: 1}},
+			Options: options.Index().SetName("status_due"),
+		},
+		{
+			Keys:    bson.D{{Key: "integrationId", Value: 1}, {Key: "createdAt", Value: -1}},
+			Options: options.Index().SetName("integration_created"),
+		},
+		{
+			Keys:    bson.D{{Key: "createdAt", Value: 1}},
+			Options: options.Index().SetName("retention").SetExpireAfterSeconds(int32(models.DeliveryRetentionDays * 24 * 60 * 60)),
+		},
+	})
+	if err != nil {
+		logger.Error(ctx, "repo: IntegrationRepository.EnsureIndexes - error creating delivery indexes", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (r *IntegrationRepository) CreateIntegration(ctx context.Context, integration *models.Integration) error {
+	logger.Debug(ctx, "repo: IntegrationRepository.CreateIntegration called", "name", integration.Name)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	integration.ID = primitive.NewObjectID()
+	if _, err := r.integrations.InsertOne(ctx, integration); err != nil {
+		logger.Error(ctx, "repo: IntegrationRepository.CreateIntegration - error inserting integration", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (r *IntegrationRepository) GetIntegration(ctx context.Context, id primitive.ObjectID) (*models.Integration, error) {
+	logger.Debug(ctx, "repo: IntegrationRepository.GetIntegration called", "id", id.Hex())
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var integration models.Integration
+	err := findOne(ctx, "IntegrationRepository.GetIntegration", r.integrations, bson.M{"_id": id}, &integration)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		logger.Error(ctx, "repo: IntegrationRepository.GetIntegration - error querying database", "error", err)
+		return nil, err
+	}
+	return &integration, nil
+}
+
+func (r *IntegrationRepository) ListIntegrations(ctx context.Context) ([]models.Integration, error) {
+	logger.Debug(ctx, "repo: IntegrationRepository.ListIntegrations called")
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	integrations := []models.Integration{}
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}})
+	if err := findAll(ctx, "IntegrationRepository.ListIntegrations", r.integrations, bson.M{}, &integrations, opts); err != nil {
+		logger.Error(ctx, "repo: IntegrationRepository.ListIntegrations - error querying database", "error", err)
+		return nil, err
+	}
+	return integrations, nil
+}
+
+func (r *IntegrationRepository) DeleteIntegration(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	logger.Debug(ctx, "repo: IntegrationRepository.DeleteIntegration called", "id", id.Hex())
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result, err := r.integrations.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		logger.Error(ctx, "repo: IntegrationRepository.DeleteIntegration - error deleting integration", "error", err)
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
+
+func (r *IntegrationRepository) EnqueueDelivery(ctx context.Context, delivery *models.IntegrationDelivery) (bool, error) {
+	logger.Debug(ctx, "repo: IntegrationRepository.EnqueueDelivery called", "integrationID", delivery.IntegrationID.Hex(), "key", delivery.Key)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	delivery.ID = primitive.NewObjectID()
+	if _, err := r.deliveries.InsertOne(ctx, delivery); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			logger.Debug(ctx, "repo: IntegrationRepository.EnqueueDelivery - already enqueued")
+			return false, nil
+		}
+		logger.Error(ctx, "repo: IntegrationRepository.EnqueueDelivery - error inserting delivery", "error", err)
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *IntegrationRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.IntegrationDelivery, error) {
+	logger.Debug(ctx, "repo: IntegrationRepository.ClaimDueDeliveries called", "limit", limit)
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	// Claimed one at a time, as NotificationRepository.ClaimDueDeliveries.
+	filter := bson.M{"status": models.DeliveryPending, "nextAttemptAt": bson.M{"$lte": now}}
+	update := bson.M{"$set": bson.M{"nextAttemptAt": now.Add(lease)}}
+	opts := options.FindOneAndUpdate().SetSort(bson.D{{Key: "nextAttemptAt", Value: 1}, {Key: "_id", Value: 1}})
+
+	deliveries := []models.IntegrationDelivery{}
+	for len(deliveries) < limit {
+		var delivery models.IntegrationDelivery
+		err := r.deliveries.FindOneAndUpdate(ctx, filter, update, opts).Decode(&delivery)
+		if err == mongo.ErrNoDocuments {
+			break
+		}
+		if err != nil {
+			logger.Error(ctx, "repo: IntegrationRepository.ClaimDueDeliveries - error claiming delivery", "error", err)
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, nil
+}
+
+func (r *IntegrationRepository) UpdateDelivery(ctx context.Context, delivery *models.IntegrationDelivery) error {
+	logger.Debug(ctx, "repo: IntegrationRepository.UpdateDelivery called", "id", delivery.ID.Hex(), "status", delivery.Status)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	set := bson.M{
+		"status":         delivery.Status,
+		"attempts":       delivery.Attempts,
+		"responseStatus": delivery.ResponseStatus,
+		"lastError":      delivery.LastError,
+		"nextAttemptAt":  delivery.NextAttemptAt,
+	}
+	update := bson.M{"$set": set}
+	if delivery.DeliveredAt != nil {
+		set["deliveredAt"] = *delivery.DeliveredAt
+	} else {
+		update["$unset"] = bson.M{"deliveredAt": ""}
+	}
+	if _, err := updateOne(ctx, "IntegrationRepository.UpdateDelivery", r.deliveries, bson.M{"_id": delivery.ID}, update); err != nil {
+		logger.Error(ctx, "repo: IntegrationRepository.UpdateDelivery - error updating delivery", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (r *IntegrationRepository) ListDeliveries(ctx context.Context, integrationID primitive.ObjectID, limit int) ([]models.IntegrationDelivery, error) {
+	logger.Debug(ctx, "repo: IntegrationRepository.ListDeliveries called", "integrationID", integrationID.Hex(), "limit", limit)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	deliveries := []models.IntegrationDelivery{}
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}).SetLimit(int64(limit))
+	if err := findAll(ctx, "IntegrationRepository.ListDeliveries", r.deliveries, bson.M{"integrationId": integrationID}, &deliveries, opts); err != nil {
+		logger.Error(ctx, "repo: IntegrationRepository.ListDeliveries - error querying database", "error", err)
+		return nil, err
+	}
+	return deliveries, nil
+}
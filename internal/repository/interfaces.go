@@ -2,26 +2,363 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/graytonio/warframe-wishlist/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type ItemRepositoryInterface interface {
-	Search(ctx context.Context, params models.SearchParams) ([]models.ItemSearchResult, error)
+	// Search returns a page of the items matching params, ordered by
+	// relevance when there is a query.
+	Search(ctx context.Context, params models.SearchParams) (*models.ItemSearchPage, error)
 	FindByUniqueName(ctx context.Context, uniqueName string) (*models.Item, error)
 	FindByUniqueNames(ctx context.Context, uniqueNames []string) (map[string]*models.Item, error)
 	SearchReusableBlueprints(ctx context.Context, query string, limit int) ([]models.ItemSearchResult, error)
 }
 
+// ItemCatalogInterface walks the whole item dataset, for jobs that need every
+// item rather than lookups.
+type ItemCatalogInterface interface {
+	// ForEachItem calls fn for every item, collection by collection in
+	// ItemCollections order. An item stored in several collections is only
+	// visited in the first, matching FindByUniqueName with the default search
+	// scope. It stops at the first
+	// error, including one returned by fn.
+	ForEachItem(ctx context.Context, fn func(item models.Item) error) error
+}
+
+// ItemExportInterface reads one item category, the item collection of that
+// name (or its items in UnifiedItemsCollection), in a stable order, so
+// exports can be resumed part way through.
+type ItemExportInterface interface {
+	// CountCategory returns the number of items in category.
+	CountCategory(ctx context.Context, category string) (int, error)
+	// ForEachInCategory calls fn for the items of category in uniqueName
+	// order, skipping the first skip and stopping after limit of them, or at
+	// the end when limit is 0. It stops at the first error, including one
+	// returned by fn.
+	ForEachInCategory(ctx context.Context, category string, skip, limit int, fn func(item models.Item) error) error
+}
+
+// ItemGraphInterface loads whole recipe trees in one query, for materials
+// resolution.
+type ItemGraphInterface interface {
+	// FindComponentTree returns the items named in uniqueNames and every item
+	// reachable from them through their components, keyed by uniqueName.
+	// An item stored in several collections comes from the one
+	// FindByUniqueNames takes it from. Names missing from the item data are
+	// left out.
+	FindComponentTree(ctx context.Context, uniqueNames []string) (map[string]*models.Item, error)
+}
+
+// RelicCatalogInterface looks relics up by display name, as the drop tables
+// of Prime parts refer to them.
+type RelicCatalogInterface interface {
+	// FindRelicsByNames returns the relics collection items named in names
+	// (such as "Axi A1 Intact"), keyed by name. Missing names are left out.
+	FindRelicsByNames(ctx context.Context, names []string) (map[string]*models.Item, error)
+}
+
 type WishlistRepositoryInterface interface {
 	GetByUserID(ctx context.Context, userID string) (*models.Wishlist, error)
 	Create(ctx context.Context, wishlist *models.Wishlist) error
+	// CreateIfAbsent stores wishlist unless the user already has one,
+	// reporting whether it was created.
+	CreateIfAbsent(ctx context.Context, wishlist *models.Wishlist) (bool, error)
 	AddItem(ctx context.Context, userID string, item models.WishlistItem) error
 	RemoveItem(ctx context.Context, userID, uniqueName string) error
 	UpdateItemQuantity(ctx context.Context, userID, uniqueName string, quantity int) error
+	// SetItemLinks replaces the source links on the first item matching
+	// uniqueName; an empty slice clears them.
+	SetItemLinks(ctx context.Context, userID, uniqueName string, links []models.SourceLink) error
+	// SetItemRecipe sets the preferred recipe on the first item matching
+	// uniqueName; an empty recipeID restores the default recipe.
+	SetItemRecipe(ctx context.Context, userID, uniqueName, recipeID string) error
+	// SetItemProgress replaces the component progress on the first item
+	// matching uniqueName; an empty slice clears it.
+	SetItemProgress(ctx context.Context, userID, uniqueName string, progress []models.ComponentProgress) error
 	Upsert(ctx context.Context, wishlist *models.Wishlist) error
 }
 
+// PopularityRepositoryInterface reports aggregate wishlist statistics across users.
+type PopularityRepositoryInterface interface {
+	TopWishlistedItems(ctx context.Context, limit int) ([]models.ItemPopularity, error)
+	WishlistSizeStats(ctx context.Context) (*models.WishlistSizeStats, error)
+}
+
+// HouseholdRepositoryInterface stores manager/member links and the wishlist
+// changes awaiting a manager's approval.
+type HouseholdRepositoryInterface interface {
+	GetLinkByMember(ctx context.Context, memberID string) (*models.HouseholdLink, error)
+	ListLinksByManager(ctx context.Context, managerID string) ([]models.HouseholdLink, error)
+	// CreateLink stores link unless the member already has one, reporting
+	// whether it was created.
+	CreateLink(ctx context.Context, link *models.HouseholdLink) (bool, error)
+	UpdateLink(ctx context.Context, memberID, status string, quantityThreshold int) error
+	DeleteLink(ctx context.Context, memberID string) error
+
+	CreatePendingChange(ctx context.Context, change *models.PendingChange) error
+	GetPendingChange(ctx context.Context, id primitive.ObjectID) (*models.PendingChange, error)
+	// ListChangesByManager and ListChangesByMember return changes newest first;
+	// an empty status matches every status.
+	ListChangesByManager(ctx context.Context, managerID, status string) ([]models.PendingChange, error)
+	ListChangesByMember(ctx context.Context, memberID, status string) ([]models.PendingChange, error)
+	// DecideChange moves a pending change to status, reporting false if the
+	// change is missing or was already decided.
+	DecideChange(ctx context.Context, id primitive.ObjectID, status string) (bool, error)
+}
+
+// ShareLinkRepositoryInterface stores wishlist share links. Tokens are
+// unique across users.
+type ShareLinkRepositoryInterface interface {
+	// Create stores link and sets its ID.
+	Create(ctx context.Context, link *models.ShareLink) error
+	// GetByToken returns the link with token, or nil.
+	GetByToken(ctx context.Context, token string) (*models.ShareLink, error)
+	// ListByUser returns the user's links oldest first, or an empty slice.
+	ListByUser(ctx context.Context, userID string) ([]models.ShareLink, error)
+	// Delete removes the user's link with id, reporting whether it existed.
+	Delete(ctx context.Context, userID string, id primitive.ObjectID) (bool, error)
+}
+
+// ShareLinkReportRepositoryInterface stores the moderation queue of reported
+// share links. At most one report per token is open at a time.
+type ShareLinkReportRepositoryInterface interface {
+	// Report counts a report of the link with token, opening a queue entry
+	// owned by ownerID if none is open.
+	Report(ctx context.Context, token, ownerID, reason string, at time.Time) (*models.ShareLinkReport, error)
+	// List returns up to limit reports with status, oldest first, or an empty
+	// slice. An empty status matches every status.
+	List(ctx context.Context, status string, limit int) ([]models.ShareLinkReport, error)
+	// Resolve closes the open report of token with status, reporting false if
+	// none is open.
+	Resolve(ctx context.Context, token, status string, at time.Time) (bool, error)
+}
+
+// ImportAliasRepositoryInterface stores the moderator-edited aliases of the
+// wishlist import. Words are unique.
+type ImportAliasRepositoryInterface interface {
+	// List returns every stored alias sorted by word, or an empty slice.
+	List(ctx context.Context) ([]models.ImportAlias, error)
+	// Set creates or replaces the alias for alias.Word.
+	Set(ctx context.Context, alias models.ImportAlias) error
+	// Delete removes the alias for word, reporting whether it existed.
+	Delete(ctx context.Context, word string) (bool, error)
+}
+
+// CustomItemRepositoryInterface stores the private items users define.
+// uniqueNames are unique, and every lookup is scoped to the owning user.
+type CustomItemRepositoryInterface interface {
+	// ListByUser returns the user's items ordered by name, or an empty slice.
+	ListByUser(ctx context.Context, userID string) ([]models.CustomItem, error)
+	// Create stores item, setting its ID and the uniqueName derived from it.
+	Create(ctx context.Context, item *models.CustomItem) error
+	// Update replaces the name, description, build price, components and
+	// updatedAt of the user's item with item.UniqueName, reporting whether it
+	// existed.
+	Update(ctx context.Context, item *models.CustomItem) (bool, error)
+	// Delete removes the user's item, reporting whether it existed.
+	Delete(ctx context.Context, userID, uniqueName string) (bool, error)
+}
+
+// FoundryRepositoryInterface stores the builds users track in their foundry.
+// Every lookup is scoped to the owning user.
+type FoundryRepositoryInterface interface {
+	// ListByUser returns the user's builds ordered by start, or an empty
+	// slice.
+	ListByUser(ctx context.Context, userID string) ([]models.FoundryBuild, error)
+	// Create stores build and sets its ID.
+	Create(ctx context.Context, build *models.FoundryBuild) error
+	// Delete removes the user's build with id, reporting whether it existed.
+	Delete(ctx context.Context, userID string, id primitive.ObjectID) (bool, error)
+}
+
+// WishlistTemplateRepositoryInterface stores users' wishlist templates.
+// Every lookup is scoped to the owning user.
+type WishlistTemplateRepositoryInterface interface {
+	// ListByUser returns the user's templates, oldest first, or an empty
+	// slice.
+	ListByUser(ctx context.Context, userID string) ([]models.WishlistTemplate, error)
+	// Get returns the user's template with id, or nil if there is none.
+	Get(ctx context.Context, userID string, id primitive.ObjectID) (*models.WishlistTemplate, error)
+	// Create stores template and sets its ID.
+	Create(ctx context.Context, template *models.WishlistTemplate) error
+	// Delete removes the user's template with id, reporting whether it
+	// existed.
+	Delete(ctx context.Context, userID string, id primitive.ObjectID) (bool, error)
+}
+
+// NotificationRepositoryInterface stores users' notification channels, the
+// log of deliveries to them and the wishlist milestones they were last seen
+// to have reached.
+type NotificationRepositoryInterface interface {
+	// CreateChannel stores channel and sets its ID.
+	CreateChannel(ctx context.Context, channel *models.NotificationChannel) error
+	// GetChannel returns the channel with id, or nil.
+	GetChannel(ctx context.Context, id primitive.ObjectID) (*models.NotificationChannel, error)
+	// ListChannels returns the user's channels oldest first, or an empty
+	// slice.
+	ListChannels(ctx context.Context, userID string) ([]models.NotificationChannel, error)
+	// DeleteChannel removes the user's channel with id, reporting whether it
+	// existed. Its deliveries are kept.
+	DeleteChannel(ctx context.Context, userID string, id primitive.ObjectID) (bool, error)
+	// ListSubscribedUsers returns the IDs of the users with a channel.
+	ListSubscribedUsers(ctx context.Context) ([]string, error)
+	// EnqueueDelivery stores delivery and sets its ID, reporting false
+	// without storing it when the channel already has a delivery with the
+	// same key.
+	EnqueueDelivery(ctx context.Context, delivery *models.NotificationDelivery) (bool, error)
+	// ClaimDueDeliveries returns up to limit pending deliveries due at now,
+	// oldest due first, and moves each one's next attempt to now+lease so
+	// no other caller claims it meanwhile.
+	ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.NotificationDelivery, error)
+	// UpdateDelivery saves the status, attempts, response status, last
+	// error, next attempt and delivery time of delivery.
+	UpdateDelivery(ctx context.Context, delivery *models.NotificationDelivery) error
+	// ListDeliveries returns the user's latest deliveries, newest first, or
+	// an empty slice.
+	ListDeliveries(ctx context.Context, userID string, limit int) ([]models.NotificationDelivery, error)
+	// GetMilestones returns the user's milestone state, or nil when none is
+	// stored.
+	GetMilestones(ctx context.Context, userID string) (*models.MilestoneState, error)
+	// SaveMilestones replaces the user's milestone state.
+	SaveMilestones(ctx context.Context, state *models.MilestoneState) error
+	// DeleteMilestones removes the user's milestone state, if any.
+	DeleteMilestones(ctx context.Context, userID string) error
+}
+
+// IntegrationRepositoryInterface stores the integrations sent item data
+// changes and the log of deliveries to them.
+type IntegrationRepositoryInterface interface {
+	// CreateIntegration stores integration and sets its ID.
+	CreateIntegration(ctx context.Context, integration *models.Integration) error
+	// GetIntegration returns the integration with id, or nil.
+	GetIntegration(ctx context.Context, id primitive.ObjectID) (*models.Integration, error)
+	// ListIntegrations returns every integration oldest first, or an empty
+	// slice.
+	ListIntegrations(ctx context.Context) ([]models.Integration, error)
+	// DeleteIntegration removes the integration with id, reporting whether
+	// it existed. Its deliveries are kept.
+	DeleteIntegration(ctx context.Context, id primitive.ObjectID) (bool, error)
+	// EnqueueDelivery stores delivery and sets its ID, reporting false
+	// without storing it when the integration already has a delivery with
+	// the same key.
+	EnqueueDelivery(ctx context.Context, delivery *models.IntegrationDelivery) (bool, error)
+	// ClaimDueDeliveries returns up to limit pending deliveries due at now,
+	// oldest due first, and moves each one's next attempt to now+lease so
+	// no other caller claims it meanwhile.
+	ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.IntegrationDelivery, error)
+	// UpdateDelivery saves the status, attempts, response status, last
+	// error, next attempt and delivery time of delivery.
+	UpdateDelivery(ctx context.Context, delivery *models.IntegrationDelivery) error
+	// ListDeliveries returns the integration's latest deliveries, newest
+	// first, or an empty slice.
+	ListDeliveries(ctx context.Context, integrationID primitive.ObjectID, limit int) ([]models.IntegrationDelivery, error)
+}
+
+// WorkspaceRepositoryInterface stores the wishlists clans share. Writes are
+// versioned so that concurrent edits by different members never overwrite
+// each other.
+type WorkspaceRepositoryInterface interface {
+	// Create stores workspace and sets its ID.
+	Create(ctx context.Context, workspace *models.Workspace) error
+	// GetByID returns the workspace with id, or nil.
+	GetByID(ctx context.Context, id primitive.ObjectID) (*models.Workspace, error)
+	// ListByMember returns the workspaces userID is a member of ordered by
+	// name, or an empty slice.
+	ListByMember(ctx context.Context, userID string) ([]models.Workspace, error)
+	// Update replaces the name, description, members, items and updatedAt of
+	// the workspace if it is still at workspace.Version, then increments
+	// Version. It reports false when the workspace is gone or has changed
+	// since it was read.
+	Update(ctx context.Context, workspace *models.Workspace) (bool, error)
+	// Delete removes the workspace, reporting whether it existed.
+	Delete(ctx context.Context, id primitive.ObjectID) (bool, error)
+}
+
+// WorkspaceContributionRepositoryInterface stores the activity feed of
+// workspaces: every contribution a member logs, as an append-only list of
+// changes.
+type WorkspaceContributionRepositoryInterface interface {
+	// Create stores event and sets its ID.
+	Create(ctx context.Context, event *models.WorkspaceContributionEvent) error
+	// ListByWorkspace returns at most limit events of the workspace, newest
+	// first, or an empty slice.
+	ListByWorkspace(ctx context.Context, workspaceID primitive.ObjectID, limit int) ([]models.WorkspaceContributionEvent, error)
+	// MaterialTotals sums the material events of the workspace by material
+	// and member, ordered by material then member. Sums of zero are left
+	// out.
+	MaterialTotals(ctx context.Context, workspaceID primitive.ObjectID) ([]models.WorkspaceContributionTotal, error)
+	// CountByUser returns how many events userID logged across all
+	// workspaces.
+	CountByUser(ctx context.Context, userID string) (int, error)
+	// DeleteByWorkspace removes every event of the workspace.
+	DeleteByWorkspace(ctx context.Context, workspaceID primitive.ObjectID) error
+}
+
+// ActivityRepositoryInterface stores users' activity logs: the changes made
+// to their wishlists and owned blueprints. Entries older than
+// models.ActivityRetentionDays may be removed at any time.
+type ActivityRepositoryInterface interface {
+	// Create stores entry and sets its ID.
+	Create(ctx context.Context, entry *models.ActivityEntry) error
+	// ListByUser returns at most limit of the user's entries, newest first,
+	// after skipping offset of them, and how many entries the user has in
+	// all. It returns an empty slice past the end.
+	ListByUser(ctx context.Context, userID string, offset, limit int) ([]models.ActivityEntry, int, error)
+}
+
+// GiftClaimRepositoryInterface stores gift claims on wishlist items, at most
+// one per owner and item. Expired claims are never returned and may be
+// removed at any time.
+type GiftClaimRepositoryInterface interface {
+	// Get returns the active claim on the owner's item, or nil.
+	Get(ctx context.Context, ownerID, uniqueName string) (*models.GiftClaim, error)
+	// Claim stores claim unless the item has an active claim by another
+	// claimer; the claimer's own active claim is replaced, keeping its ID and
+	// CreatedAt. It returns the active claim on the item afterwards, which is
+	// the other claimer's when claim was not stored.
+	Claim(ctx context.Context, claim *models.GiftClaim) (*models.GiftClaim, error)
+	// ListByOwner returns the active claims on the owner's items ordered by
+	// uniqueName, and ListByClaimer the claimer's active claims ordered by
+	// expiresAt; both return an empty slice when there are none.
+	ListByOwner(ctx context.Context, ownerID string) ([]models.GiftClaim, error)
+	ListByClaimer(ctx context.Context, claimerID string) ([]models.GiftClaim, error)
+	// Delete removes the claim on the owner's item, only if it is claimerID's
+	// when claimerID is not empty, reporting whether an active claim was
+	// removed.
+	Delete(ctx context.Context, ownerID, uniqueName, claimerID string) (bool, error)
+}
+
+// IdempotencyRepositoryInterface stores the outcomes of requests sent with
+// an Idempotency-Key, at most one per user and key. Expired records are never
+// returned and may be removed at any time.
+type IdempotencyRepositoryInterface interface {
+	// Reserve stores record, pending, unless an active record has its user
+	// and key. It returns that record, or nil when record was stored.
+	Reserve(ctx context.Context, record *models.IdempotencyRecord) (*models.IdempotencyRecord, error)
+	// Complete stores the response of the pending record with record's user
+	// and key: its Status, Header and Body.
+	Complete(ctx context.Context, record *models.IdempotencyRecord) error
+	// Release removes the pending record for userID and key, so the request
+	// can be retried with the key. A completed record is kept.
+	Release(ctx context.Context, userID, key string) error
+}
+
+// ItemChangeRepositoryInterface stores item fingerprints as of the last data
+// sync and the item changes detected between syncs.
+type ItemChangeRepositoryInterface interface {
+	GetFingerprints(ctx context.Context) (map[string]models.ItemFingerprint, error)
+	// ReplaceFingerprints stores exactly fingerprints, removing any others.
+	ReplaceFingerprints(ctx context.Context, fingerprints []models.ItemFingerprint) error
+	RecordChanges(ctx context.Context, changes []models.ItemChange) error
+	// ListChangesSince returns up to limit changes recorded at or after since,
+	// newest first.
+	ListChangesSince(ctx context.Context, since time.Time, limit int) ([]models.ItemChange, error)
+}
+
 type OwnedBlueprintsRepositoryInterface interface {
 	GetByUserID(ctx context.Context, userID string) (*models.OwnedBlueprints, error)
 	Create(ctx context.Context, ownedBlueprints *models.OwnedBlueprints) error
@@ -29,8 +366,116 @@ type OwnedBlueprintsRepositoryInterface interface {
 	RemoveBlueprint(ctx context.Context, userID, uniqueName string) error
 	BulkAddBlueprints(ctx context.Context, userID string, blueprints []models.OwnedBlueprint) error
 	ClearAll(ctx context.Context, userID string) error
+	// SetBlueprint records blueprint, replacing the quantity of one already
+	// owned and keeping its addedAt. It is a no-op for a user without a
+	// document.
+	SetBlueprint(ctx context.Context, userID string, blueprint models.OwnedBlueprint) error
+	// SetComponent records component, replacing the count of one already
+	// owned and keeping its addedAt. Like the blueprint updates it is a no-op
+	// for a user without a document.
+	SetComponent(ctx context.Context, userID string, component models.OwnedComponent) error
+	RemoveComponent(ctx context.Context, userID, uniqueName string) error
+	ClearComponents(ctx context.Context, userID string) error
+}
+
+// OwnedMaterialsRepositoryInterface stores how much of each material users
+// already own.
+type OwnedMaterialsRepositoryInterface interface {
+	// GetByUserID returns the user's materials ordered by uniqueName, or an
+	// empty slice.
+	GetByUserID(ctx context.Context, userID string) ([]models.OwnedMaterial, error)
+	// SetCounts sets the count of each material in counts; a count of zero or
+	// less removes the material.
+	SetCounts(ctx context.Context, userID string, counts map[string]int) error
+	ClearAll(ctx context.Context, userID string) error
+}
+
+// MasteryRepositoryInterface stores the items users have already built or
+// mastered.
+type MasteryRepositoryInterface interface {
+	// ListByUser returns the user's entries ordered by uniqueName, or an empty
+	// slice.
+	ListByUser(ctx context.Context, userID string) ([]models.MasteryEntry, error)
+	// Get returns nil when the item is not in the user's profile.
+	Get(ctx context.Context, userID, uniqueName string) (*models.MasteryEntry, error)
+	// SetStatuses sets the status of each item in statuses; an empty status
+	// removes the item.
+	SetStatuses(ctx context.Context, userID string, statuses map[string]string) error
+	ClearAll(ctx context.Context, userID string) error
+}
+
+type SettingsRepositoryInterface interface {
+	GetByUserID(ctx context.Context, userID string) (*models.UserSettings, error)
+	Upsert(ctx context.Context, settings *models.UserSettings) error
+}
+
+// UserTraceRepositoryInterface stores the per-user tracing windows set by
+// support staff, at most one per user.
+type UserTraceRepositoryInterface interface {
+	GetByUserID(ctx context.Context, userID string) (*models.UserTrace, error)
+	// Upsert replaces the user's trace.
+	Upsert(ctx context.Context, trace *models.UserTrace) error
+	Delete(ctx context.Context, userID string) error
+	// ListActive returns the traces that end after now, ordered by userId, or
+	// an empty slice.
+	ListActive(ctx context.Context, now time.Time) ([]models.UserTrace, error)
+}
+
+// ItemSyncerInterface writes a validated item data export into the item
+// collections.
+type ItemSyncerInterface interface {
+	EnsureIndexes(ctx context.Context, collection string) error
+	SyncCollection(ctx context.Context, collection string, items []bson.M, dryRun bool) (models.ItemSyncStats, error)
+}
+
+// SyncStatusRepositoryInterface stores the status of background syncs, one
+// document per sync name.
+type SyncStatusRepositoryInterface interface {
+	// Get returns nil when no status has been recorded for name.
+	Get(ctx context.Context, name string) (*models.SyncStatus, error)
+	// Upsert replaces the status named status.Name.
+	Upsert(ctx context.Context, status *models.SyncStatus) error
+}
+
+// SharedCacheInterface is a key-value store shared by every instance, such
+// as Redis, that RedisItemRepository caches items in.
+type SharedCacheInterface interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// MGet returns the values of keys in order, nil for a missing key.
+	MGet(ctx context.Context, keys ...string) ([][]byte, error)
+	SetMany(ctx context.Context, values map[string][]byte, ttl time.Duration) error
+	// Incr increments the integer at key, starting from 0.
+	Incr(ctx context.Context, key string) (int64, error)
 }
 
 var _ ItemRepositoryInterface = (*ItemRepository)(nil)
+var _ ItemRepositoryInterface = (*CachedItemRepository)(nil)
+var _ ItemRepositoryInterface = (*RedisItemRepository)(nil)
+var _ ItemCatalogInterface = (*ItemRepository)(nil)
+var _ RelicCatalogInterface = (*ItemRepository)(nil)
+var _ ItemGraphInterface = (*ItemRepository)(nil)
 var _ WishlistRepositoryInterface = (*WishlistRepository)(nil)
+var _ WishlistRepositoryInterface = (*ActivityWishlistRepository)(nil)
+var _ PopularityRepositoryInterface = (*WishlistRepository)(nil)
+var _ HouseholdRepositoryInterface = (*HouseholdRepository)(nil)
+var _ ItemChangeRepositoryInterface = (*ItemChangeRepository)(nil)
+var _ GiftClaimRepositoryInterface = (*GiftClaimRepository)(nil)
+var _ IdempotencyRepositoryInterface = (*IdempotencyRepository)(nil)
+var _ ShareLinkRepositoryInterface = (*ShareLinkRepository)(nil)
+var _ ShareLinkReportRepositoryInterface = (*ShareLinkReportRepository)(nil)
+var _ ImportAliasRepositoryInterface = (*ImportAliasRepository)(nil)
+var _ CustomItemRepositoryInterface = (*CustomItemRepository)(nil)
+var _ FoundryRepositoryInterface = (*FoundryRepository)(nil)
+var _ WishlistTemplateRepositoryInterface = (*WishlistTemplateRepository)(nil)
+var _ NotificationRepositoryInterface = (*NotificationRepository)(nil)
+var _ WorkspaceRepositoryInterface = (*WorkspaceRepository)(nil)
+var _ WorkspaceContributionRepositoryInterface = (*WorkspaceContributionRepository)(nil)
+var _ ActivityRepositoryInterface = (*ActivityRepository)(nil)
 var _ OwnedBlueprintsRepositoryInterface = (*OwnedBlueprintsRepository)(nil)
+var _ OwnedBlueprintsRepositoryInterface = (*ActivityOwnedBlueprintsRepository)(nil)
+var _ OwnedMaterialsRepositoryInterface = (*OwnedMaterialsRepository)(nil)
+var _ MasteryRepositoryInterface = (*MasteryRepository)(nil)
+var _ SettingsRepositoryInterface = (*SettingsRepository)(nil)
+var _ UserTraceRepositoryInterface = (*UserTraceRepository)(nil)
+var _ ItemSyncerInterface = (*ItemSyncer)(nil)
+var _ SyncStatusRepositoryInterface = (*SyncStatusRepository)(nil)
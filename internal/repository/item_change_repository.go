@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/database"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	itemFingerprintsCollection = "item_fingerprints"
+	itemChangesCollection      = "item_changes"
+)
+
+type ItemChangeRepository struct {
+	db           *database.MongoDB
+	fingerprints *mongo.Collection
+	changes      *mongo.Collection
+}
+
+func NewItemChangeRepository(db *database.MongoDB) *ItemChangeRepository {
+	return &ItemChangeRepository{
+		db:           db,
+		fingerprints: db.Collection(itemFingerprintsCollection),
+		changes:      db.Collection(itemChangesCollection),
+	}
+}
+
+func (r *ItemChangeRepository) GetFingerprints(ctx context.Context) (map[string]models.ItemFingerprint, error) {
+	logger.Debug(ctx, "repo: ItemChangeRepository.GetFingerprints called")
+
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	cursor, err := r.fingerprints.Find(ctx, bson.M{})
+	if err != nil {
+		logger.Error(ctx, "repo: ItemChangeRepository.GetFingerprints - error querying database", "error", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	fingerprints := make(map[string]models.ItemFingerprint)
+	for cursor.Next(ctx) {
+		var fp models.ItemFingerprint
+		if err := cursor.Decode(&fp); err != nil {
+			logger.Error(ctx, "repo: ItemChangeRepository.GetFingerprints - error decoding fingerprint", "error", err)
+			return nil, err
+		}
+		fingerprints[fp.UniqueName] = fp
+	}
+	if err := cursor.Err(); err != nil {
+		logger.Error(ctx, "repo: ItemChangeRepository.GetFingerprints - cursor error", "error", err)
+		return nil, err
+	}
+
+	logger.Debug(ctx, "repo: ItemChangeRepository.GetFingerprints - completed", "count", len(fingerprints))
+	return fingerprints, nil
+}
+
+func (r *ItemChangeRepository) ReplaceFingerprints(ctx context.Context, fingerprints []models.ItemFingerprint) error {
+	logger.Debug(ctx, "repo: ItemChangeRepository.ReplaceFingerprints called", "count", len(fingerprints))
+
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	// Upsert then delete the rest, rather than drop and insert, so a failure
+	// part way leaves the previous fingerprints mostly intact instead of empty.
+	uniqueNames := make([]string, len(fingerprints))
+	writes := make([]mongo.WriteModel, len(fingerprints))
+	for i, fp := range fingerprints {
+		uniqueNames[i] = fp.UniqueName
+		writes[i] = mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"uniqueName": fp.UniqueName}).
+			SetReplacement(fp).
+			SetUpsert(true)
+	}
+
+	if len(writes) > 0 {
+		if _, err := r.fingerprints.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil {
+			logger.Error(ctx, "repo: ItemChangeRepository.ReplaceFingerprints - error writing fingerprints", "error", err)
+			return err
+		}
+	}
+
+	result, err := r.fingerprints.DeleteMany(ctx, bson.M{"uniqueName": bson.M{"$nin": uniqueNames}})
+	if err != nil {
+		logger.Error(ctx, "repo: ItemChangeRepository.ReplaceFingerprints - error deleting stale fingerprints", "error", err)
+		return err
+	}
+
+	logger.Debug(ctx, "repo: ItemChangeRepository.ReplaceFingerprints - completed", "deletedCount", result.DeletedCount)
+	return nil
+}
+
+func (r *ItemChangeRepository) RecordChanges(ctx context.Context, changes []models.ItemChange) error {
+	logger.Debug(ctx, "repo: ItemChangeRepository.RecordChanges called", "count", len(changes))
+
+	if len(changes) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	docs := make([]interface{}, len(changes))
+	for i := range changes {
+		changes[i].ID = primitive.NewObjectID()
+		docs[i] = changes[i]
+	}
+
+	if _, err := r.changes.InsertMany(ctx, docs); err != nil {
+		logger.Error(ctx, "repo: ItemChangeRepository.RecordChanges - error inserting changes", "error", err)
+		return err
+	}
+
+	logger.Debug(ctx, "repo: ItemChangeRepository.RecordChanges - completed")
+	return nil
+}
+
+func (r *ItemChangeRepository) ListChangesSince(ctx context.Context, since time.Time, limit int) ([]models.ItemChange, error) {
+	logger.Debug(ctx, "repo: ItemChangeRepository.ListChangesSince called", "since", since, "limit", limit)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "changedAt", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(int64(limit))
+	changes := []models.ItemChange{}
+	if err := findAll(ctx, "ItemChangeRepository.ListChangesSince", r.changes, bson.M{"changedAt": bson.M{"$gte": since}}, &changes, opts); err != nil {
+		logger.Error(ctx, "repo: ItemChangeRepository.ListChangesSince - error querying database", "error", err)
+		return nil, err
+	}
+
+	logger.Debug(ctx, "repo: ItemChangeRepository.ListChangesSince - completed", "count", len(changes))
+	return changes, nil
+}
@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ItemGraphCollection is the unified copy of the item collections that
+// FindComponentTree walks with $graphLookup. It holds each item once, from
+// the collection FindByUniqueNames would take it from, with
+// componentNames listing the uniqueNames of its components and of their
+// embedded components.
+const ItemGraphCollection = "item_graph"
+
+// itemGraphMaxDepth bounds the $graphLookup recursion. Recipe trees in the
+// item data are a few levels deep; the bound only stops runaway data.
+const itemGraphMaxDepth = 32
+
+// ErrItemGraphNotBuilt is returned by FindComponentTree until
+// RebuildItemGraph has filled ItemGraphCollection.
+var ErrItemGraphNotBuilt = errors.New("item graph not built")
+
+// componentNamesExpr collects the uniqueNames of an item's components and of
+// the components embedded in them, the two levels the item data nests.
+var componentNamesExpr = bson.M{"$setUnion": bson.A{
+	bson.M{"$ifNull": bson.A{"$components.uniqueName", bson.A{}}},
+	bson.M{"$reduce": bson.M{
+		"input":        bson.M{"$ifNull": bson.A{"$components.components.uniqueName", bson.A{}}},
+		"initialValue": bson.A{},
+		"in":           bson.M{"$concatArrays": bson.A{"$$value", "$$this"}},
+	}},
+}}
+
+// itemGraphPipeline unions every collection in order, keeps the copy of each
+// uniqueName from the earliest collection holding it and replaces
+// ItemGraphCollection with the result.
+func itemGraphPipeline(order []string) mongo.Pipeline {
+	tag := func(collName string, rank int) bson.D {
+		return bson.D{{Key: "$addFields", Value: bson.M{ItemCategoryField: collName, "_order": rank}}}
+	}
+
+	pipeline := mongo.Pipeline{tag(order[0], 0)}
+	for i, collName := range order[1:] {
+		pipeline = append(pipeline, bson.D{{Key: "$unionWith", Value: bson.M{
+			"coll":     collName,
+			"pipeline": bson.A{tag(collName, i+1)},
+		}}})
+	}
+	return append(pipeline, itemGraphStages()...)
+}
+
+// unifiedItemGraphPipeline is itemGraphPipeline over UnifiedItemsCollection,
+// ranking each copy by the position of its category in order.
+func unifiedItemGraphPipeline(order []string) mongo.Pipeline {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{ItemCategoryField: bson.M{"$in": order}}}},
+		{{Key: "$addFields", Value: bson.M{"_order": categoryRank(order)}}},
+	}
+	return append(pipeline, itemGraphStages()...)
+}
+
+// itemGraphStages dedups the ranked items by uniqueName, keeping the lowest
+// _order, and writes them to ItemGraphCollection.
+func itemGraphStages() mongo.Pipeline {
+	return mongo.Pipeline{
+		bson.D{{Key: "$match", Value: bson.M{"uniqueName": bson.M{"$type": "string", "$ne": ""}}}},
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "_order", Value: 1}}}},
+		bson.D{{Key: "$group", Value: bson.M{"_id": "$uniqueName", "doc": bson.M{"$first": "$$ROOT"}}}},
+		bson.D{{Key: "$replaceRoot", Value: bson.M{"newRoot": "$doc"}}},
+		bson.D{{Key: "$set", Value: bson.M{"componentNames": componentNamesExpr}}},
+		bson.D{{Key: "$unset", Value: bson.A{"_id", "_order"}}},
+		bson.D{{Key: "$out", Value: ItemGraphCollection}},
+	}
+}
+
+// RebuildItemGraph replaces ItemGraphCollection with the current item data.
+// $out swaps the collection in atomically, so FindComponentTree keeps reading
+// the previous graph while it runs. Call it after every item data sync.
+func (r *ItemRepository) RebuildItemGraph(ctx context.Context) error {
+	logger.Debug(ctx, "repo: ItemRepository.RebuildItemGraph called")
+	start := time.Now()
+
+	// Reads every item collection, so allow as long as a full scan.
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	err := withRetry(ctx, "ItemRepository.RebuildItemGraph", func() error {
+		order := r.scope.BatchLookupOrder()
+		source, pipeline := r.db.Collection(order[0]), itemGraphPipeline(order)
+		if r.layout == ItemLayoutUnified {
+			source, pipeline = r.db.Collection(UnifiedItemsCollection), unifiedItemGraphPipeline(order)
+		}
+		cursor, err := source.Aggregate(ctx, pipeline, options.Aggregate().SetAllowDiskUse(true))
+		if err != nil {
+			return err
+		}
+		return cursor.Close(ctx)
+	})
+	if err != nil {
+		logger.Error(ctx, "repo: ItemRepository.RebuildItemGraph - error building graph", "error", err)
+		return err
+	}
+
+	graph := r.db.Collection(ItemGraphCollection)
+	_, err = graph.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "uniqueName", Value: 1}},
+		Options: options.Index().SetName("uniqueName"),
+	})
+	if err != nil {
+		logger.Error(ctx, "repo: ItemRepository.RebuildItemGraph - error creating index", "error", err)
+		return err
+	}
+
+	count, err := graph.EstimatedDocumentCount(ctx)
+	if err != nil {
+		logger.Error(ctx, "repo: ItemRepository.RebuildItemGraph - error counting graph", "error", err)
+		return err
+	}
+	r.graphBuilt.Store(count > 0)
+
+	logger.Info(ctx, "repo: ItemRepository.RebuildItemGraph - completed", "itemCount", count, "elapsed", time.Since(start))
+	return nil
+}
+
+// FindComponentTree loads the items named in uniqueNames and everything
+// reachable through their components with a single $graphLookup over
+// ItemGraphCollection.
+func (r *ItemRepository) FindComponentTree(ctx context.Context, uniqueNames []string) (map[string]*models.Item, error) {
+	logger.Debug(ctx, "repo: ItemRepository.FindComponentTree called", "count", len(uniqueNames))
+
+	result := make(map[string]*models.Item)
+	if len(uniqueNames) == 0 {
+		return result, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	graph := r.db.Collection(ItemGraphCollection)
+	if !r.graphBuilt.Load() {
+		// Another instance may have built it, or a read region replicated it.
+		count, err := graph.EstimatedDocumentCount(ctx)
+		if err != nil {
+			logger.Error(ctx, "repo: ItemRepository.FindComponentTree - error counting graph", "error", err)
+			return nil, err
+		}
+		if count == 0 {
+			return nil, ErrItemGraphNotBuilt
+		}
+		r.graphBuilt.Store(true)
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"uniqueName": bson.M{"$in": uniqueNames}}}},
+		// Starting from the item's own uniqueName puts the item itself in the tree.
+		{{Key: "$graphLookup", Value: bson.M{
+			"from":             ItemGraphCollection,
+			"startWith":        "$uniqueName",
+			"connectFromField": "componentNames",
+			"connectToField":   "uniqueName",
+			"as":               "tree",
+			"maxDepth":         itemGraphMaxDepth,
+		}}},
+		{{Key: "$unwind", Value: "$tree"}},
+		{{Key: "$replaceRoot", Value: bson.M{"newRoot": "$tree"}}},
+		{{Key: "$group", Value: bson.M{"_id": "$uniqueName", "doc": bson.M{"$first": "$$ROOT"}}}},
+		{{Key: "$replaceRoot", Value: bson.M{"newRoot": "$doc"}}},
+	}
+
+	var items []models.Item
+	err := withRetry(ctx, "ItemRepository.FindComponentTree", func() error {
+		cursor, err := graph.Aggregate(ctx, pipeline)
+		if err != nil {
+			return err
+		}
+		defer cursor.Close(ctx)
+		return cursor.All(ctx, &items)
+	})
+	if err != nil {
+		logger.Error(ctx, "repo: ItemRepository.FindComponentTree - error walking graph", "error", err)
+		return nil, err
+	}
+
+	for i := range items {
+		result[items[i].UniqueName] = &items[i]
+	}
+	logger.Debug(ctx, "repo: ItemRepository.FindComponentTree - completed", "foundCount", len(result))
+	return result, nil
+}
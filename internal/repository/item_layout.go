@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/database"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ItemLayout is how the item data is stored in MongoDB.
+type ItemLayout string
+
+const (
+	// ItemLayoutCollections stores each category in the item collection of
+	// its name, one of ItemCollections. Lookups visit the collections one by
+	// one.
+	ItemLayoutCollections ItemLayout = "collections"
+	// ItemLayoutUnified stores every category in UnifiedItemsCollection,
+	// each item tagged with its category in ItemCategoryField, so a lookup
+	// is a single indexed query.
+	ItemLayoutUnified ItemLayout = "unified"
+)
+
+// UnifiedItemsCollection holds every item under ItemLayoutUnified. An item
+// exported in several categories has one document per category.
+const UnifiedItemsCollection = "items"
+
+// ItemCategoryField names an item's category, the ItemCollections name it
+// was exported under, in UnifiedItemsCollection and ItemGraphCollection. It
+// is the field models.Item.Collection reads.
+const ItemCategoryField = "_collection"
+
+// ParseItemLayout reads ITEM_STORAGE_LAYOUT; empty means
+// ItemLayoutCollections.
+func ParseItemLayout(layout string) (ItemLayout, error) {
+	switch ItemLayout(strings.TrimSpace(layout)) {
+	case "", ItemLayoutCollections:
+		return ItemLayoutCollections, nil
+	case ItemLayoutUnified:
+		return ItemLayoutUnified, nil
+	default:
+		return "", fmt.Errorf("unknown item storage layout %q (want %q or %q)", layout, ItemLayoutCollections, ItemLayoutUnified)
+	}
+}
+
+// unifiedItemIndexes are the indexes queries on UnifiedItemsCollection rely
+// on: uniqueName lookups, category exports in uniqueName order (and the
+// uniqueness of an item within a category), and the search text index.
+func unifiedItemIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "uniqueName", Value: 1}, {Key: ItemCategoryField, Value: 1}},
+			Options: options.Index().SetName("uniqueName_category"),
+		},
+		{
+			Keys:    bson.D{{Key: ItemCategoryField, Value: 1}, {Key: "uniqueName", Value: 1}},
+			Options: options.Index().SetName("category_uniqueName").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: ItemCategoryField, Value: 1}, {Key: "name", Value: 1}},
+			Options: options.Index().SetName("category_name"),
+		},
+		itemSearchIndex(),
+	}
+}
+
+// unifiedItemIndexNames are the names of unifiedItemIndexes, for
+// RequiredIndexes.
+var unifiedItemIndexNames = []string{"uniqueName_category", "category_uniqueName", "category_name", "item_search"}
+
+// ensureUnifiedItemIndexes creates unifiedItemIndexes unless they already
+// exist.
+func ensureUnifiedItemIndexes(ctx context.Context, db *database.MongoDB) error {
+	_, err := db.Collection(UnifiedItemsCollection).Indexes().CreateMany(ctx, unifiedItemIndexes())
+	return err
+}
+
+// categoryRank evaluates to the position of an item's category in order, so
+// a $sort on it visits categories in that order. Categories not in order
+// rank -1.
+func categoryRank(order []string) bson.M {
+	return bson.M{"$indexOfArray": bson.A{order, "$" + ItemCategoryField}}
+}
+
+// ConsolidationStats reports one category copied by ConsolidateItems.
+type ConsolidationStats struct {
+	Category string
+	// Source is the number of items with a uniqueName in the category's
+	// collection, and Unified the number in UnifiedItemsCollection after
+	// the copy (before it, on a dry run).
+	Source  int
+	Unified int
+}
+
+// ConsolidateItems copies every item collection into UnifiedItemsCollection,
+// tagging each item with its category, replacing the copies a previous run
+// made and creating unifiedItemIndexes first. The item collections are left
+// as they are, so servers still on ItemLayoutCollections keep working until
+// they are switched over. Items without a uniqueName are not copied. With
+// dryRun it only counts.
+func ConsolidateItems(ctx context.Context, db *database.MongoDB, dryRun bool) ([]ConsolidationStats, error) {
+	logger.Debug(ctx, "repo: ConsolidateItems called", "dryRun", dryRun)
+
+	unified := db.Collection(UnifiedItemsCollection)
+	if !dryRun {
+		if err := ensureUnifiedItemIndexes(ctx, db); err != nil {
+			logger.Error(ctx, "repo: ConsolidateItems - error creating indexes", "error", err)
+			return nil, err
+		}
+	}
+
+	stats := make([]ConsolidationStats, 0, len(ItemCollections))
+	for _, category := range ItemCollections {
+		// A whole collection is copied in one aggregation, so allow as long
+		// as a full scan.
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+		stat, err := consolidateCategory(ctx, db, unified, category, dryRun)
+		cancel()
+		if err != nil {
+			logger.Error(ctx, "repo: ConsolidateItems - error copying category", "category", category, "error", err)
+			return stats, fmt.Errorf("%s: %w", category, err)
+		}
+		stats = append(stats, stat)
+	}
+
+	logger.Info(ctx, "repo: ConsolidateItems - completed", "categories", len(stats), "dryRun", dryRun)
+	return stats, nil
+}
+
+func consolidateCategory(ctx context.Context, db *database.MongoDB, unified *mongo.Collection, category string, dryRun bool) (ConsolidationStats, error) {
+	stat := ConsolidationStats{Category: category}
+	source, err := db.Collection(category).CountDocuments(ctx, exportFilter)
+	if err != nil {
+		return stat, err
+	}
+	stat.Source = int(source)
+
+	if !dryRun {
+		pipeline := mongo.Pipeline{
+			{{Key: "$match", Value: bson.M{"uniqueName": bson.M{"$type": "string", "$ne": ""}}}},
+			{{Key: "$set", Value: bson.M{ItemCategoryField: category}}},
+			{{Key: "$unset", Value: "_id"}},
+			{{Key: "$merge", Value: bson.M{
+				"into":           UnifiedItemsCollection,
+				"on":             bson.A{ItemCategoryField, "uniqueName"},
+				"whenMatched":    "replace",
+				"whenNotMatched": "insert",
+			}}},
+		}
+		err := withRetry(ctx, "ConsolidateItems", func() error {
+			cursor, err := db.Collection(category).Aggregate(ctx, pipeline, options.Aggregate().SetAllowDiskUse(true))
+			if err != nil {
+				return err
+			}
+			return cursor.Close(ctx)
+		})
+		if err != nil {
+			return stat, err
+		}
+	}
+
+	count, err := unified.CountDocuments(ctx, bson.M{ItemCategoryField: category})
+	if err != nil {
+		return stat, err
+	}
+	stat.Unified = int(count)
+	return stat, nil
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseItemLayout(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    ItemLayout
+		wantErr bool
+	}{
+		{input: "", want: ItemLayoutCollections},
+		{input: "collections", want: ItemLayoutCollections},
+		{input: " unified ", want: ItemLayoutUnified},
+		{input: "sharded", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseItemLayout(tt.input)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseItemLayout(%q) = %q, %v; want %q (error %v)", tt.input, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestRequiredIndexes_Layout(t *testing.T) {
+	collections := RequiredIndexes(ItemLayoutCollections)
+	if _, ok := collections[UnifiedItemsCollection]; ok {
+		t.Errorf("expected no %s indexes in the collections layout", UnifiedItemsCollection)
+	}
+	if !slices.Equal(collections["warframes"], []string{"uniqueName_1", "item_search"}) {
+		t.Errorf("expected item collection indexes, got %v", collections["warframes"])
+	}
+
+	unified := RequiredIndexes(ItemLayoutUnified)
+	if _, ok := unified["warframes"]; ok {
+		t.Error("expected no item collection indexes in the unified layout")
+	}
+	var names []string
+	for _, index := range unifiedItemIndexes() {
+		names = append(names, *index.Options.Name)
+	}
+	if !slices.Equal(unified[UnifiedItemsCollection], names) {
+		t.Errorf("expected %v, got %v", names, unified[UnifiedItemsCollection])
+	}
+}
@@ -1,7 +1,12 @@
 package repository
 
 import (
+	"cmp"
 	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/graytonio/warframe-wishlist/internal/database"
@@ -9,6 +14,7 @@ import (
 	"github.com/graytonio/warframe-wishlist/pkg/logger"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
@@ -19,101 +25,300 @@ var ItemCollections = []string{
 	"relics", "quests", "node", "enemy",
 }
 
+// DefaultSearchTimeout bounds a whole search across the item collections.
+const DefaultSearchTimeout = 5 * time.Second
+
 type ItemRepository struct {
-	db *database.MongoDB
+	db            *database.MongoDB
+	layout        ItemLayout
+	scope         SearchScope
+	searchTimeout time.Duration
+	// searchStats is optional; with it Search skips and reorders
+	// collections by their past hits.
+	searchStats *SearchStats
+	// graphBuilt is set once ItemGraphCollection is known to have items.
+	graphBuilt atomic.Bool
 }
 
 func NewItemRepository(db *database.MongoDB) *ItemRepository {
-	return &ItemRepository{db: db}
+	return &ItemRepository{db: db, layout: ItemLayoutCollections, scope: DefaultSearchScope(), searchTimeout: DefaultSearchTimeout}
 }
 
-func (r *ItemRepository) Search(ctx context.Context, params models.SearchParams) ([]models.ItemSearchResult, error) {
-	logger.Debug(ctx, "repo: ItemRepository.Search called", "query", params.Query, "category", params.Category, "limit", params.Limit, "offset", params.Offset)
+// SetLayout replaces ItemLayoutCollections as where items are read from.
+// Call it before serving requests.
+func (r *ItemRepository) SetLayout(layout ItemLayout) {
+	r.layout = layout
+}
 
-	var results []models.ItemSearchResult
+// SetSearchScope replaces DefaultSearchScope. Call it before serving requests.
+func (r *ItemRepository) SetSearchScope(scope SearchScope) {
+	r.scope = scope
+}
 
-	limit := params.Limit
-	if limit <= 0 {
-		limit = 20
+// SetSearchTimeout replaces DefaultSearchTimeout as the budget of a whole
+// search; a timeout that is not positive is ignored. Call it before serving
+// requests.
+func (r *ItemRepository) SetSearchTimeout(timeout time.Duration) {
+	if timeout > 0 {
+		r.searchTimeout = timeout
 	}
-	if limit > 100 {
-		limit = 100
+}
+
+// SetSearchStats makes Search learn from stats which collections each query
+// term matches in. Call it before serving requests.
+func (r *ItemRepository) SetSearchStats(stats *SearchStats) {
+	r.searchStats = stats
+}
+
+// searchCollections calls search for each collection in order, all under one
+// deadline timeout from now, so a slow collection leaves less time for the
+// rest rather than each collection getting a timeout of its own. A
+// collection that fails is logged and skipped. It stops early once search
+// reports it has enough, or once the deadline passes, leaving the remaining
+// collections unsearched; it only returns an error when ctx itself is done,
+// since then nobody is waiting for the partial results.
+func searchCollections(ctx context.Context, name string, collections []string, timeout time.Duration, search func(ctx context.Context, collName string) (enough bool, err error)) error {
+	searchCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for i, collName := range collections {
+		if searchCtx.Err() != nil {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			logger.Warn(ctx, "repo: "+name+" - search timed out, skipping remaining collections", "timeout", timeout, "skipped", collections[i:])
+			return nil
+		}
+		enough, err := search(searchCtx, collName)
+		if err != nil {
+			logger.Warn(ctx, "repo: "+name+" - error querying collection", "collection", collName, "error", err)
+			continue
+		}
+		if enough {
+			return nil
+		}
+	}
+	return ctx.Err()
+}
+
+// itemSearchIndex is the text index Search queries in every item collection.
+// Stemming and stop words are disabled so queries match whole words exactly,
+// and the language override points at a field the item data does not use.
+func itemSearchIndex() mongo.IndexModel {
+	return mongo.IndexModel{
+		Keys: bson.D{{Key: "name", Value: "text"}, {Key: "description", Value: "text"}},
+		Options: options.Index().
+			SetName("item_search").
+			SetWeights(bson.D{{Key: "name", Value: 10}, {Key: "description", Value: 1}}).
+			SetDefaultLanguage("none").
+			SetLanguageOverride("searchLanguage"),
 	}
+}
 
-	offset := params.Offset
-	if offset < 0 {
-		offset = 0
+// EnsureSearchIndexes creates the text index Search relies on in every item
+// collection, for databases synced before it existed. Collections that fail
+// are logged and skipped; the joined errors are returned. Under
+// ItemLayoutUnified it creates every index of UnifiedItemsCollection instead.
+func (r *ItemRepository) EnsureSearchIndexes(ctx context.Context) error {
+	logger.Debug(ctx, "repo: ItemRepository.EnsureSearchIndexes called")
+
+	if r.layout == ItemLayoutUnified {
+		ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+		defer cancel()
+		if err := ensureUnifiedItemIndexes(ctx, r.db); err != nil {
+			logger.Error(ctx, "repo: ItemRepository.EnsureSearchIndexes - error creating index", "collection", UnifiedItemsCollection, "error", err)
+			return err
+		}
+		return nil
+	}
+
+	var errs []error
+	for _, collName := range ItemCollections {
+		ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+		_, err := r.db.Collection(collName).Indexes().CreateOne(ctx, itemSearchIndex())
+		cancel()
+		if err != nil {
+			logger.Error(ctx, "repo: ItemRepository.EnsureSearchIndexes - error creating index", "collection", collName, "error", err)
+			errs = append(errs, err)
+		}
 	}
+	return errors.Join(errs...)
+}
+
+// searchFacet is one collection's matches: the best offset+limit of them and
+// the number of all of them.
+type searchFacet struct {
+	Items []models.ItemSearchResult `bson:"items"`
+	Total []struct {
+		Count int `bson:"count"`
+	} `bson:"total"`
+}
+
+// Search queries each collection's text index for its best offset+limit
+// matches and their count, then merges them by relevance (name, then
+// uniqueName, break ties) and applies the page. Without a query the page is
+// taken in collection order. Without a category only the search scope is
+// searched. A collection that cannot be searched, such as one missing its
+// text index, is logged and skipped, and collections not reached within the
+// search timeout are left out of the page and its total. With search stats,
+// collections the query's words have all but never matched in are skipped.
+// Under ItemLayoutUnified the categories are searched in one query instead,
+// which fails as a whole.
+func (r *ItemRepository) Search(ctx context.Context, params models.SearchParams) (*models.ItemSearchPage, error) {
+	params = params.Normalized()
+	logger.Debug(ctx, "repo: ItemRepository.Search called", "query", params.Query, "category", params.Category, "limit", params.Limit, "offset", params.Offset, "includeArchived", params.IncludeArchived)
 
 	filter := bson.M{}
 	if params.Query != "" {
-		filter["name"] = bson.M{"$regex": primitive.Regex{Pattern: params.Query, Options: "i"}}
+		filter["$text"] = bson.M{"$search": params.Query}
 	}
-
-	collections := ItemCollections
-	if params.Category != "" {
-		collections = []string{params.Category}
+	if !params.IncludeArchived {
+		filter["archived"] = bson.M{"$ne": true}
 	}
 
-	findOptions := options.Find().
-		SetProjection(bson.M{
+	window := params.Offset + params.Limit
+	itemsPipeline := bson.A{}
+	if params.Query != "" {
+		itemsPipeline = append(itemsPipeline, bson.M{"$sort": bson.D{{Key: "score", Value: -1}, {Key: "name", Value: 1}, {Key: "uniqueName", Value: 1}}})
+	}
+	itemsPipeline = append(itemsPipeline,
+		bson.M{"$limit": window},
+		bson.M{"$project": bson.M{
 			"uniqueName":  1,
 			"name":        1,
 			"description": 1,
 			"category":    1,
 			"imageName":   1,
-		}).
-		SetLimit(int64(limit)).
-		SetSkip(int64(offset))
+			"archived":    1,
+			"score":       1,
+			// Only UnifiedItemsCollection stores the category.
+			ItemCategoryField: 1,
+		}},
+	)
+
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: filter}}}
+	if params.Query != "" {
+		pipeline = append(pipeline, bson.D{{Key: "$addFields", Value: bson.M{"score": bson.M{"$meta": "textScore"}}}})
+	}
+	pipeline = append(pipeline, bson.D{{Key: "$facet", Value: bson.M{
+		"items": itemsPipeline,
+		"total": bson.A{bson.M{"$count": "count"}},
+	}}})
 
-	logger.Debug(ctx, "repo: ItemRepository.Search - searching collections", "collectionCount", len(collections))
-	for _, collName := range collections {
-		collection := r.db.Collection(collName)
+	collections := r.scope.Collections
+	if params.Category != "" {
+		collections = []string{params.Category}
+	}
+	if r.layout == ItemLayoutUnified {
+		return r.searchUnified(ctx, params, filter, itemsPipeline, collections)
+	}
 
-		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
-		cursor, err := collection.Find(ctx, filter, findOptions)
-		cancel()
-		if err != nil {
-			logger.Debug(ctx, "repo: ItemRepository.Search - error querying collection", "collection", collName, "error", err)
-			continue
+	// Archived items can match where the learned searches, which leave them
+	// out, did not.
+	stats := r.searchStats
+	if params.Query == "" || params.IncludeArchived {
+		stats = nil
+	}
+	searched := collections
+	if stats != nil {
+		var skipped []string
+		searched, skipped = stats.Plan(params.Query, collections)
+		if len(skipped) > 0 {
+			logger.Debug(ctx, "repo: ItemRepository.Search - skipping collections without past hits", "skipped", skipped)
 		}
+	}
 
-		var items []models.ItemSearchResult
-		if err := cursor.All(ctx, &items); err != nil {
-			logger.Debug(ctx, "repo: ItemRepository.Search - error decoding results", "collection", collName, "error", err)
-			cursor.Close(ctx)
-			continue
+	logger.Debug(ctx, "repo: ItemRepository.Search - searching collections", "collectionCount", len(searched))
+	page := &models.ItemSearchPage{}
+	found := make(map[string][]models.ItemSearchResult, len(searched))
+	err := searchCollections(ctx, "ItemRepository.Search", searched, r.searchTimeout, func(ctx context.Context, collName string) (bool, error) {
+		start := time.Now()
+		var facets []searchFacet
+		err := withRetry(ctx, "ItemRepository.Search", func() error {
+			cursor, err := r.db.Collection(collName).Aggregate(ctx, pipeline)
+			if err != nil {
+				return err
+			}
+			defer cursor.Close(ctx)
+			return cursor.All(ctx, &facets)
+		})
+		if err != nil {
+			return false, err
 		}
-		cursor.Close(ctx)
 
-		for i := range items {
-			items[i].Collection = collName
+		var total int
+		if len(facets) > 0 && len(facets[0].Total) > 0 {
+			total = facets[0].Total[0].Count
+		}
+		if stats != nil {
+			stats.Record(params.Query, collName, total > 0, time.Since(start))
+		}
+		if len(facets) == 0 {
+			return false, nil
 		}
 
-		logger.Debug(ctx, "repo: ItemRepository.Search - found items in collection", "collection", collName, "count", len(items))
-		results = append(results, items...)
-
-		if len(results) >= limit {
-			results = results[:limit]
-			break
+		facet := facets[0]
+		for i := range facet.Items {
+			facet.Items[i].Collection = collName
 		}
+		page.Total += total
+		found[collName] = facet.Items
+		return false, nil
+	})
+	if err != nil {
+		logger.Debug(ctx, "repo: ItemRepository.Search - canceled", "error", err)
+		return nil, err
 	}
 
-	logger.Debug(ctx, "repo: ItemRepository.Search - completed", "totalResults", len(results))
-	return results, nil
+	// The page is merged in scope order whatever order the collections were
+	// searched in, so ties break the same way.
+	var matches []models.ItemSearchResult
+	for _, collName := range collections {
+		matches = append(matches, found[collName]...)
+	}
+	page.Items = PageSearchResults(matches, params)
+
+	logger.Debug(ctx, "repo: ItemRepository.Search - completed", "resultCount", len(page.Items), "total", page.Total)
+	return page, nil
+}
+
+// PageSearchResults merges the matches of every collection, given in
+// collection order, into the page selected by params (already normalized).
+// With a query they are ordered by descending relevance, then name, then
+// uniqueName.
+func PageSearchResults(matches []models.ItemSearchResult, params models.SearchParams) []models.ItemSearchResult {
+	if params.Query != "" {
+		slices.SortStableFunc(matches, func(a, b models.ItemSearchResult) int {
+			if c := cmp.Compare(b.Score, a.Score); c != 0 {
+				return c
+			}
+			if c := strings.Compare(a.Name, b.Name); c != 0 {
+				return c
+			}
+			return strings.Compare(a.UniqueName, b.UniqueName)
+		})
+	}
+	if params.Offset >= len(matches) {
+		return []models.ItemSearchResult{}
+	}
+	return matches[params.Offset:min(params.Offset+params.Limit, len(matches))]
 }
 
 func (r *ItemRepository) FindByUniqueName(ctx context.Context, uniqueName string) (*models.Item, error) {
 	logger.Debug(ctx, "repo: ItemRepository.FindByUniqueName called", "uniqueName", uniqueName)
 
+	if r.layout == ItemLayoutUnified {
+		return r.findByUniqueNameUnified(ctx, uniqueName)
+	}
+
 	filter := bson.M{"uniqueName": uniqueName}
 
-	for _, collName := range ItemCollections {
+	for _, collName := range r.scope.LookupOrder() {
 		collection := r.db.Collection(collName)
 
 		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 		var item models.Item
-		err := collection.FindOne(ctx, filter).Decode(&item)
+		err := findOne(ctx, "ItemRepository.FindByUniqueName", collection, filter, &item)
 		cancel()
 
 		if err == nil {
@@ -121,6 +326,12 @@ func (r *ItemRepository) FindByUniqueName(ctx context.Context, uniqueName string
 			logger.Debug(ctx, "repo: ItemRepository.FindByUniqueName - found item", "uniqueName", uniqueName, "collection", collName, "itemName", item.Name)
 			return &item, nil
 		}
+		// A failed lookup is not a miss: reporting one would be cached and
+		// turn the item into a base material until the cache expires.
+		if err != mongo.ErrNoDocuments {
+			logger.Error(ctx, "repo: ItemRepository.FindByUniqueName - error querying collection", "collection", collName, "error", err)
+			return nil, err
+		}
 	}
 
 	logger.Debug(ctx, "repo: ItemRepository.FindByUniqueName - item not found", "uniqueName", uniqueName)
@@ -136,27 +347,46 @@ func (r *ItemRepository) FindByUniqueNames(ctx context.Context, uniqueNames []st
 		logger.Debug(ctx, "repo: ItemRepository.FindByUniqueNames - empty input, returning empty result")
 		return result, nil
 	}
+	if r.layout == ItemLayoutUnified {
+		return r.findByUniqueNamesUnified(ctx, uniqueNames)
+	}
 
+	if err := r.findInCollections(ctx, r.scope.Collections, uniqueNames, result); err != nil {
+		return nil, err
+	}
+	// Only names the scope missed are looked up in the excluded collections.
+	var missing []string
+	for _, name := range uniqueNames {
+		if _, ok := result[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		if err := r.findInCollections(ctx, r.scope.Excluded, missing, result); err != nil {
+			return nil, err
+		}
+	}
+
+	logger.Debug(ctx, "repo: ItemRepository.FindByUniqueNames - completed", "foundCount", len(result))
+	return result, nil
+}
+
+// findInCollections adds the items named in uniqueNames found in collections
+// to result, later collections overwriting earlier ones.
+func (r *ItemRepository) findInCollections(ctx context.Context, collections []string, uniqueNames []string, result map[string]*models.Item) error {
 	filter := bson.M{"uniqueName": bson.M{"$in": uniqueNames}}
 
-	for _, collName := range ItemCollections {
+	for _, collName := range collections {
 		collection := r.db.Collection(collName)
 
 		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
-		cursor, err := collection.Find(ctx, filter)
+		var items []models.Item
+		err := findAll(ctx, "ItemRepository.FindByUniqueNames", collection, filter, &items)
 		cancel()
 		if err != nil {
-			logger.Debug(ctx, "repo: ItemRepository.FindByUniqueNames - error querying collection", "collection", collName, "error", err)
-			continue
-		}
-
-		var items []models.Item
-		if err := cursor.All(ctx, &items); err != nil {
-			logger.Debug(ctx, "repo: ItemRepository.FindByUniqueNames - error decoding results", "collection", collName, "error", err)
-			cursor.Close(ctx)
-			continue
+			logger.Error(ctx, "repo: ItemRepository.FindByUniqueNames - error querying collection", "collection", collName, "error", err)
+			return err
 		}
-		cursor.Close(ctx)
 
 		if len(items) > 0 {
 			logger.Debug(ctx, "repo: ItemRepository.FindByUniqueNames - found items in collection", "collection", collName, "count", len(items))
@@ -166,8 +396,147 @@ func (r *ItemRepository) FindByUniqueNames(ctx context.Context, uniqueNames []st
 			result[items[i].UniqueName] = &items[i]
 		}
 	}
+	return nil
+}
 
-	logger.Debug(ctx, "repo: ItemRepository.FindByUniqueNames - completed", "foundCount", len(result))
+func (r *ItemRepository) ForEachItem(ctx context.Context, fn func(item models.Item) error) error {
+	logger.Debug(ctx, "repo: ItemRepository.ForEachItem called")
+
+	seen := make(map[string]bool)
+	for _, collName := range ItemCollections {
+		coll, filter := r.inCategory(collName, bson.M{})
+		if err := r.forEachInCollection(ctx, coll, filter, collName, seen, fn); err != nil {
+			logger.Error(ctx, "repo: ItemRepository.ForEachItem - error walking collection", "collection", collName, "error", err)
+			return err
+		}
+	}
+
+	logger.Debug(ctx, "repo: ItemRepository.ForEachItem - completed", "itemCount", len(seen))
+	return nil
+}
+
+func (r *ItemRepository) forEachInCollection(ctx context.Context, coll *mongo.Collection, filter bson.M, collName string, seen map[string]bool, fn func(item models.Item) error) error {
+	// A full collection scan, so allow longer than the per-lookup timeout.
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	cursor, err := coll.Find(ctx, filter)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var item models.Item
+		if err := cursor.Decode(&item); err != nil {
+			return err
+		}
+		if item.UniqueName == "" || seen[item.UniqueName] {
+			continue
+		}
+		seen[item.UniqueName] = true
+		item.Collection = collName
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+// exportFilter matches the items ForEachItem visits: those with a uniqueName.
+var exportFilter = bson.M{"uniqueName": bson.M{"$gt": ""}}
+
+// inCategory returns the collection holding category's items and filter
+// narrowed to them: the item collection of that name, or under
+// ItemLayoutUnified the items of UnifiedItemsCollection tagged with it.
+// filter is not modified.
+func (r *ItemRepository) inCategory(category string, filter bson.M) (*mongo.Collection, bson.M) {
+	if r.layout != ItemLayoutUnified {
+		return r.db.Collection(category), filter
+	}
+	narrowed := make(bson.M, len(filter)+1)
+	for key, value := range filter {
+		narrowed[key] = value
+	}
+	narrowed[ItemCategoryField] = category
+	return r.db.Collection(UnifiedItemsCollection), narrowed
+}
+
+func (r *ItemRepository) CountCategory(ctx context.Context, category string) (int, error) {
+	logger.Debug(ctx, "repo: ItemRepository.CountCategory called", "category", category)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	coll, filter := r.inCategory(category, exportFilter)
+	var count int64
+	err := withRetry(ctx, "ItemRepository.CountCategory", func() error {
+		var err error
+		count, err = coll.CountDocuments(ctx, filter)
+		return err
+	})
+	if err != nil {
+		logger.Error(ctx, "repo: ItemRepository.CountCategory - error counting items", "category", category, "error", err)
+		return 0, err
+	}
+	return int(count), nil
+}
+
+func (r *ItemRepository) ForEachInCategory(ctx context.Context, category string, skip, limit int, fn func(item models.Item) error) error {
+	logger.Debug(ctx, "repo: ItemRepository.ForEachInCategory called", "category", category, "skip", skip, "limit", limit)
+
+	// A full collection scan, so allow longer than the per-lookup timeout.
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "uniqueName", Value: 1}}).SetSkip(int64(skip))
+	if limit > 0 {
+		findOptions.SetLimit(int64(limit))
+	}
+	coll, filter := r.inCategory(category, exportFilter)
+	cursor, err := coll.Find(ctx, filter, findOptions)
+	if err != nil {
+		logger.Error(ctx, "repo: ItemRepository.ForEachInCategory - error querying collection", "category", category, "error", err)
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var item models.Item
+		if err := cursor.Decode(&item); err != nil {
+			return err
+		}
+		item.Collection = category
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+func (r *ItemRepository) FindRelicsByNames(ctx context.Context, names []string) (map[string]*models.Item, error) {
+	logger.Debug(ctx, "repo: ItemRepository.FindRelicsByNames called", "count", len(names))
+
+	result := make(map[string]*models.Item)
+	if len(names) == 0 {
+		return result, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var items []models.Item
+	coll, filter := r.inCategory("relics", bson.M{"name": bson.M{"$in": names}})
+	if err := findAll(ctx, "ItemRepository.FindRelicsByNames", coll, filter, &items); err != nil {
+		logger.Error(ctx, "repo: ItemRepository.FindRelicsByNames - error querying relics", "error", err)
+		return nil, err
+	}
+	for i := range items {
+		items[i].Collection = "relics"
+		result[items[i].Name] = &items[i]
+	}
+
+	logger.Debug(ctx, "repo: ItemRepository.FindRelicsByNames - completed", "foundCount", len(result))
 	return result, nil
 }
 
@@ -199,27 +568,16 @@ func (r *ItemRepository) SearchReusableBlueprints(ctx context.Context, query str
 			"imageName":   1,
 		}).
 		SetLimit(int64(limit))
+	if r.layout == ItemLayoutUnified {
+		return r.searchReusableBlueprintsUnified(ctx, filter, limit)
+	}
 
-	logger.Debug(ctx, "repo: ItemRepository.SearchReusableBlueprints - searching collections", "collectionCount", len(ItemCollections))
-	for _, collName := range ItemCollections {
-		collection := r.db.Collection(collName)
-
-		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
-		cursor, err := collection.Find(ctx, filter, findOptions)
-		cancel()
-		if err != nil {
-			logger.Debug(ctx, "repo: ItemRepository.SearchReusableBlueprints - error querying collection", "collection", collName, "error", err)
-			continue
-		}
-
+	logger.Debug(ctx, "repo: ItemRepository.SearchReusableBlueprints - searching collections", "collectionCount", len(r.scope.Collections))
+	err := searchCollections(ctx, "ItemRepository.SearchReusableBlueprints", r.scope.Collections, r.searchTimeout, func(ctx context.Context, collName string) (bool, error) {
 		var items []models.ItemSearchResult
-		if err := cursor.All(ctx, &items); err != nil {
-			logger.Debug(ctx, "repo: ItemRepository.SearchReusableBlueprints - error decoding results", "collection", collName, "error", err)
-			cursor.Close(ctx)
-			continue
+		if err := findAll(ctx, "ItemRepository.SearchReusableBlueprints", r.db.Collection(collName), filter, &items, findOptions); err != nil {
+			return false, err
 		}
-		cursor.Close(ctx)
-
 		for i := range items {
 			items[i].Collection = collName
 		}
@@ -229,8 +587,13 @@ func (r *ItemRepository) SearchReusableBlueprints(ctx context.Context, query str
 
 		if len(results) >= limit {
 			results = results[:limit]
-			break
+			return true, nil
 		}
+		return false, nil
+	})
+	if err != nil {
+		logger.Debug(ctx, "repo: ItemRepository.SearchReusableBlueprints - canceled", "error", err)
+		return nil, err
 	}
 
 	logger.Debug(ctx, "repo: ItemRepository.SearchReusableBlueprints - completed", "totalResults", len(results))
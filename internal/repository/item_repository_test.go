@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// slowCollections fakes item collections that take delay to answer a query,
+// or give up with the query's context like the driver does.
+type slowCollections struct {
+	delay    map[string]time.Duration
+	err      map[string]error
+	searched []string
+}
+
+func (c *slowCollections) search(ctx context.Context, collName string) (bool, error) {
+	c.searched = append(c.searched, collName)
+	select {
+	case <-time.After(c.delay[collName]):
+		return false, c.err[collName]
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
+}
+
+func TestSearchCollections(t *testing.T) {
+	collections := []string{"warframes", "primary", "secondary", "melee"}
+
+	t.Run("searches every collection", func(t *testing.T) {
+		fake := &slowCollections{err: map[string]error{"primary": errors.New("no text index")}}
+
+		if err := searchCollections(context.Background(), "Test", collections, time.Second, fake.search); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Equal(fake.searched, collections) {
+			t.Errorf("expected a failed collection to be skipped, searched %v", fake.searched)
+		}
+	})
+
+	t.Run("one budget covers every collection", func(t *testing.T) {
+		// Each collection is well within the budget on its own, but not
+		// together.
+		fake := &slowCollections{delay: map[string]time.Duration{
+			"warframes": 60 * time.Millisecond,
+			"primary":   60 * time.Millisecond,
+			"secondary": 60 * time.Millisecond,
+			"melee":     60 * time.Millisecond,
+		}}
+
+		start := time.Now()
+		if err := searchCollections(context.Background(), "Test", collections, 100*time.Millisecond, fake.search); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > 200*time.Millisecond {
+			t.Errorf("expected the search to stop at its budget, took %v", elapsed)
+		}
+		if !slices.Equal(fake.searched, collections[:2]) {
+			t.Errorf("expected the search to end during the second collection, searched %v", fake.searched)
+		}
+	})
+
+	t.Run("a hung collection ends the search", func(t *testing.T) {
+		fake := &slowCollections{delay: map[string]time.Duration{"primary": time.Hour}}
+
+		start := time.Now()
+		if err := searchCollections(context.Background(), "Test", collections, 20*time.Millisecond, fake.search); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("expected the search to stop at its budget, took %v", elapsed)
+		}
+		if !slices.Equal(fake.searched, collections[:2]) {
+			t.Errorf("expected no collection after the hung one, searched %v", fake.searched)
+		}
+	})
+
+	t.Run("stops once search has enough", func(t *testing.T) {
+		var searched []string
+		err := searchCollections(context.Background(), "Test", collections, time.Second, func(ctx context.Context, collName string) (bool, error) {
+			searched = append(searched, collName)
+			return collName == "primary", nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Equal(searched, collections[:2]) {
+			t.Errorf("expected the search to stop after primary, searched %v", searched)
+		}
+	})
+
+	t.Run("returns the caller's cancellation", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		fake := &slowCollections{delay: map[string]time.Duration{"warframes": time.Hour}}
+		time.AfterFunc(10*time.Millisecond, cancel)
+
+		err := searchCollections(ctx, "Test", collections, time.Minute, fake.search)
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+		if len(fake.searched) != 1 {
+			t.Errorf("expected no collection after the cancellation, searched %v", fake.searched)
+		}
+	})
+
+	t.Run("collections share the search's deadline", func(t *testing.T) {
+		var deadlines []time.Time
+		err := searchCollections(context.Background(), "Test", collections, time.Minute, func(ctx context.Context, collName string) (bool, error) {
+			deadline, ok := ctx.Deadline()
+			if !ok {
+				t.Fatal("expected a deadline")
+			}
+			deadlines = append(deadlines, deadline)
+			return false, nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, deadline := range deadlines[1:] {
+			if !deadline.Equal(deadlines[0]) {
+				t.Errorf("expected one deadline for every collection, got %v", deadlines)
+				break
+			}
+		}
+	})
+}
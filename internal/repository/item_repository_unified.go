@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// The ItemLayoutUnified halves of the ItemRepository lookups. Each answers
+// with one query on UnifiedItemsCollection what the ItemLayoutCollections
+// half answers by visiting collections in turn, picking between the copies
+// of an item exported in several categories the same way.
+
+// searchUnified is Search over UnifiedItemsCollection: one $facet over the
+// matches in collections, ordered by relevance or, without a query, in
+// collection order.
+func (r *ItemRepository) searchUnified(ctx context.Context, params models.SearchParams, filter bson.M, itemsPipeline bson.A, collections []string) (*models.ItemSearchPage, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.searchTimeout)
+	defer cancel()
+
+	match := make(bson.M, len(filter)+1)
+	for key, value := range filter {
+		match[key] = value
+	}
+	match[ItemCategoryField] = bson.M{"$in": collections}
+
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: match}}}
+	if params.Query != "" {
+		pipeline = append(pipeline, bson.D{{Key: "$addFields", Value: bson.M{"score": bson.M{"$meta": "textScore"}}}})
+	} else {
+		pipeline = append(pipeline,
+			bson.D{{Key: "$addFields", Value: bson.M{"_rank": categoryRank(collections)}}},
+			bson.D{{Key: "$sort", Value: bson.D{{Key: "_rank", Value: 1}, {Key: "uniqueName", Value: 1}}}},
+		)
+	}
+	pipeline = append(pipeline, bson.D{{Key: "$facet", Value: bson.M{
+		"items": itemsPipeline,
+		"total": bson.A{bson.M{"$count": "count"}},
+	}}})
+
+	var facets []searchFacet
+	err := withRetry(ctx, "ItemRepository.Search", func() error {
+		cursor, err := r.db.Collection(UnifiedItemsCollection).Aggregate(ctx, pipeline)
+		if err != nil {
+			return err
+		}
+		defer cursor.Close(ctx)
+		return cursor.All(ctx, &facets)
+	})
+	if err != nil {
+		logger.Error(ctx, "repo: ItemRepository.Search - error querying items", "collection", UnifiedItemsCollection, "error", err)
+		return nil, err
+	}
+
+	page := &models.ItemSearchPage{}
+	var matches []models.ItemSearchResult
+	if len(facets) > 0 {
+		matches = facets[0].Items
+		if len(facets[0].Total) > 0 {
+			page.Total = facets[0].Total[0].Count
+		}
+	}
+	page.Items = PageSearchResults(matches, params)
+
+	logger.Debug(ctx, "repo: ItemRepository.Search - completed", "resultCount", len(page.Items), "total", page.Total)
+	return page, nil
+}
+
+// findByUniqueNameUnified is FindByUniqueName over UnifiedItemsCollection:
+// of the item's copies, the one earliest in the lookup order.
+func (r *ItemRepository) findByUniqueNameUnified(ctx context.Context, uniqueName string) (*models.Item, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	order := r.scope.LookupOrder()
+	filter := bson.M{"uniqueName": uniqueName, ItemCategoryField: bson.M{"$in": order}}
+	var items []models.Item
+	if err := findAll(ctx, "ItemRepository.FindByUniqueName", r.db.Collection(UnifiedItemsCollection), filter, &items); err != nil {
+		// A failed lookup is not a miss: reporting one would be cached and
+		// turn the item into a base material until the cache expires.
+		logger.Error(ctx, "repo: ItemRepository.FindByUniqueName - error querying items", "collection", UnifiedItemsCollection, "error", err)
+		return nil, err
+	}
+
+	var found *models.Item
+	for i := range items {
+		if found == nil || slices.Index(order, items[i].Collection) < slices.Index(order, found.Collection) {
+			found = &items[i]
+		}
+	}
+	if found == nil {
+		logger.Debug(ctx, "repo: ItemRepository.FindByUniqueName - item not found", "uniqueName", uniqueName)
+		return nil, nil
+	}
+	logger.Debug(ctx, "repo: ItemRepository.FindByUniqueName - found item", "uniqueName", uniqueName, "collection", found.Collection, "itemName", found.Name)
+	return found, nil
+}
+
+// findByUniqueNamesUnified is FindByUniqueNames over UnifiedItemsCollection:
+// of each item's copies, the one earliest in the batch lookup order.
+func (r *ItemRepository) findByUniqueNamesUnified(ctx context.Context, uniqueNames []string) (map[string]*models.Item, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	order := r.scope.BatchLookupOrder()
+	filter := bson.M{"uniqueName": bson.M{"$in": uniqueNames}, ItemCategoryField: bson.M{"$in": order}}
+	var items []models.Item
+	if err := findAll(ctx, "ItemRepository.FindByUniqueNames", r.db.Collection(UnifiedItemsCollection), filter, &items); err != nil {
+		logger.Error(ctx, "repo: ItemRepository.FindByUniqueNames - error querying items", "collection", UnifiedItemsCollection, "error", err)
+		return nil, err
+	}
+
+	result := make(map[string]*models.Item, len(items))
+	for i := range items {
+		item := &items[i]
+		if found, ok := result[item.UniqueName]; !ok || slices.Index(order, item.Collection) < slices.Index(order, found.Collection) {
+			result[item.UniqueName] = item
+		}
+	}
+
+	logger.Debug(ctx, "repo: ItemRepository.FindByUniqueNames - completed", "foundCount", len(result))
+	return result, nil
+}
+
+// searchReusableBlueprintsUnified is SearchReusableBlueprints over
+// UnifiedItemsCollection: the first limit matches in collection order.
+func (r *ItemRepository) searchReusableBlueprintsUnified(ctx context.Context, filter bson.M, limit int) ([]models.ItemSearchResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.searchTimeout)
+	defer cancel()
+
+	match := make(bson.M, len(filter)+1)
+	for key, value := range filter {
+		match[key] = value
+	}
+	match[ItemCategoryField] = bson.M{"$in": r.scope.Collections}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$addFields", Value: bson.M{"_rank": categoryRank(r.scope.Collections)}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_rank", Value: 1}, {Key: "uniqueName", Value: 1}}}},
+		{{Key: "$limit", Value: limit}},
+		{{Key: "$project", Value: bson.M{
+			"uniqueName":      1,
+			"name":            1,
+			"description":     1,
+			"category":        1,
+			"imageName":       1,
+			ItemCategoryField: 1,
+		}}},
+	}
+
+	var results []models.ItemSearchResult
+	err := withRetry(ctx, "ItemRepository.SearchReusableBlueprints", func() error {
+		cursor, err := r.db.Collection(UnifiedItemsCollection).Aggregate(ctx, pipeline, options.Aggregate().SetAllowDiskUse(true))
+		if err != nil {
+			return err
+		}
+		defer cursor.Close(ctx)
+		return cursor.All(ctx, &results)
+	})
+	if err != nil {
+		logger.Error(ctx, "repo: ItemRepository.SearchReusableBlueprints - error querying items", "collection", UnifiedItemsCollection, "error", err)
+		return nil, err
+	}
+
+	logger.Debug(ctx, "repo: ItemRepository.SearchReusableBlueprints - completed", "totalResults", len(results))
+	return results, nil
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/database"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// itemSyncBatchSize bounds the number of upserts sent in one bulk write.
+const itemSyncBatchSize = 1000
+
+// ItemSyncer writes a WFCD data export into the item collections read by
+// ItemRepository the same way sync_to_mongodb.py does: items are upserted by
+// uniqueName, and items missing from the export are archived, never deleted.
+type ItemSyncer struct {
+	db     *database.MongoDB
+	layout ItemLayout
+}
+
+func NewItemSyncer(db *database.MongoDB) *ItemSyncer {
+	return &ItemSyncer{db: db, layout: ItemLayoutCollections}
+}
+
+// SetLayout replaces ItemLayoutCollections as where items are written.
+func (s *ItemSyncer) SetLayout(layout ItemLayout) {
+	s.layout = layout
+}
+
+// EnsureIndexes creates the unique uniqueName index and the search text index
+// on collection unless they already exist. Under ItemLayoutUnified it
+// creates the indexes of UnifiedItemsCollection instead.
+func (s *ItemSyncer) EnsureIndexes(ctx context.Context, collection string) error {
+	var err error
+	if s.layout == ItemLayoutUnified {
+		err = ensureUnifiedItemIndexes(ctx, s.db)
+	} else {
+		_, err = s.db.Collection(collection).Indexes().CreateMany(ctx, []mongo.IndexModel{
+			{
+				Keys:    bson.D{{Key: "uniqueName", Value: 1}},
+				Options: options.Index().SetUnique(true).SetSparse(true),
+			},
+			itemSearchIndex(),
+		})
+	}
+	if err != nil {
+		logger.Error(ctx, "repo: ItemSyncer.EnsureIndexes - error creating index", "collection", collection, "error", err)
+	}
+	return err
+}
+
+// target returns where collection's items are stored and filter narrowed to
+// them, like ItemRepository.inCategory.
+func (s *ItemSyncer) target(collection string, filter bson.M) (*mongo.Collection, bson.M) {
+	if s.layout != ItemLayoutUnified {
+		return s.db.Collection(collection), filter
+	}
+	filter[ItemCategoryField] = collection
+	return s.db.Collection(UnifiedItemsCollection), filter
+}
+
+// SyncCollection upserts items into collection, clearing the archived flag of
+// items that reappeared, and archives the collection's items that are not in
+// items. Every item must have a uniqueName.
+func (s *ItemSyncer) SyncCollection(ctx context.Context, collection string, items []bson.M, dryRun bool) (models.ItemSyncStats, error) {
+	logger.Debug(ctx, "repo: ItemSyncer.SyncCollection called", "collection", collection, "items", len(items), "dryRun", dryRun)
+	var stats models.ItemSyncStats
+	coll, existingFilter := s.target(collection, bson.M{"uniqueName": bson.M{"$exists": true}})
+
+	var existing []struct {
+		UniqueName string `bson:"uniqueName"`
+		Archived   bool   `bson:"archived"`
+	}
+	projection := options.Find().SetProjection(bson.M{"uniqueName": 1, "archived": 1})
+	if err := findAll(ctx, "ItemSyncer.SyncCollection", coll, existingFilter, &existing, projection); err != nil {
+		logger.Error(ctx, "repo: ItemSyncer.SyncCollection - error listing existing items", "collection", collection, "error", err)
+		return stats, err
+	}
+
+	exported := make(map[string]bool, len(items))
+	for _, item := range items {
+		exported[item["uniqueName"].(string)] = true
+	}
+	var toArchive []string
+	present := 0
+	for _, doc := range existing {
+		if exported[doc.UniqueName] {
+			present++
+		} else if !doc.Archived {
+			toArchive = append(toArchive, doc.UniqueName)
+		}
+	}
+
+	if dryRun {
+		stats.Inserted = len(exported) - present
+		stats.Updated = present
+		stats.Archived = len(toArchive)
+		return stats, nil
+	}
+
+	for start := 0; start < len(items); start += itemSyncBatchSize {
+		end := min(start+itemSyncBatchSize, len(items))
+		writes := make([]mongo.WriteModel, 0, end-start)
+		for _, item := range items[start:end] {
+			set := make(bson.M, len(item))
+			for key, value := range item {
+				if key != "_id" && key != "archived" && key != "archivedAt" {
+					set[key] = value
+				}
+			}
+			_, filter := s.target(collection, bson.M{"uniqueName": item["uniqueName"]})
+			if s.layout == ItemLayoutUnified {
+				set[ItemCategoryField] = collection
+			}
+			writes = append(writes, mongo.NewUpdateOneModel().
+				SetFilter(filter).
+				SetUpdate(bson.M{"$set": set, "$unset": bson.M{"archived": "", "archivedAt": ""}}).
+				SetUpsert(true))
+		}
+
+		var result *mongo.BulkWriteResult
+		err := withRetry(ctx, "ItemSyncer.SyncCollection", func() error {
+			var err error
+			result, err = coll.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+			return err
+		})
+		if err != nil {
+			logger.Error(ctx, "repo: ItemSyncer.SyncCollection - error upserting items", "collection", collection, "error", err)
+			return stats, err
+		}
+		stats.Inserted += int(result.UpsertedCount)
+		stats.Updated += int(result.ModifiedCount)
+		stats.Unchanged += int(result.MatchedCount - result.ModifiedCount)
+	}
+
+	if len(toArchive) > 0 {
+		_, filter := s.target(collection, bson.M{"uniqueName": bson.M{"$in": toArchive}, "archived": bson.M{"$ne": true}})
+		update := bson.M{"$set": bson.M{"archived": true, "archivedAt": time.Now().UTC()}}
+		var result *mongo.UpdateResult
+		err := withRetry(ctx, "ItemSyncer.SyncCollection", func() error {
+			var err error
+			result, err = coll.UpdateMany(ctx, filter, update)
+			return err
+		})
+		if err != nil {
+			logger.Error(ctx, "repo: ItemSyncer.SyncCollection - error archiving items", "collection", collection, "error", err)
+			return stats, err
+		}
+		stats.Archived = int(result.ModifiedCount)
+	}
+
+	logger.Info(ctx, "repo: ItemSyncer.SyncCollection - synced", "collection", collection, "inserted", stats.Inserted, "updated", stats.Updated, "unchanged", stats.Unchanged, "archived", stats.Archived)
+	return stats, nil
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/database"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const masteryCollection = "mastery"
+
+// MasteryRepository stores one document per user and item, so setting a
+// status is a single atomic upsert.
+type MasteryRepository struct {
+	db         *database.MongoDB
+	collection *mongo.Collection
+}
+
+func NewMasteryRepository(db *database.MongoDB) *MasteryRepository {
+	return &MasteryRepository{
+		db:         db,
+		collection: db.Collection(masteryCollection),
+	}
+}
+
+// EnsureIndexes creates the index a user's entries are listed and looked up
+// by.
+func (r *MasteryRepository) EnsureIndexes(ctx context.Context) error {
+	logger.Debug(ctx, "repo: MasteryRepository.EnsureIndexes called")
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "userId", Value: 1}, {Key: "uniqueName", Value: 1}},
+		Options: options.Index().SetName("user_item").SetUnique(true),
+	})
+	if err != nil {
+		logger.Error(ctx, "repo: MasteryRepository.EnsureIndexes - error creating indexes", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (r *MasteryRepository) ListByUser(ctx context.Context, userID string) ([]models.MasteryEntry, error) {
+	logger.Debug(ctx, "repo: MasteryRepository.ListByUser called", "userID", userID)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "uniqueName", Value: 1}})
+	entries := []models.MasteryEntry{}
+	if err := findAll(ctx, "MasteryRepository.ListByUser", r.collection, bson.M{"userId": userID}, &entries, opts); err != nil {
+		logger.Error(ctx, "repo: MasteryRepository.ListByUser - error querying database", "error", err)
+		return nil, err
+	}
+
+	logger.Debug(ctx, "repo: MasteryRepository.ListByUser - completed", "entryCount", len(entries))
+	return entries, nil
+}
+
+func (r *MasteryRepository) Get(ctx context.Context, userID, uniqueName string) (*models.MasteryEntry, error) {
+	logger.Debug(ctx, "repo: MasteryRepository.Get called", "userID", userID, "uniqueName", uniqueName)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var entry models.MasteryEntry
+	err := findOne(ctx, "MasteryRepository.Get", r.collection, bson.M{"userId": userID, "uniqueName": uniqueName}, &entry)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		logger.Error(ctx, "repo: MasteryRepository.Get - error querying database", "error", err)
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *MasteryRepository) SetStatuses(ctx context.Context, userID string, statuses map[string]string) error {
+	logger.Debug(ctx, "repo: MasteryRepository.SetStatuses called", "userID", userID, "count", len(statuses))
+
+	if len(statuses) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	writes := make([]mongo.WriteModel, 0, len(statuses))
+	for uniqueName, status := range statuses {
+		filter := bson.M{"userId": userID, "uniqueName": uniqueName}
+		if status == "" {
+			writes = append(writes, mongo.NewDeleteOneModel().SetFilter(filter))
+			continue
+		}
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(filter).
+			SetUpdate(bson.M{"$set": bson.M{"status": status, "updatedAt": now}}).
+			SetUpsert(true))
+	}
+
+	result, err := r.collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		logger.Error(ctx, "repo: MasteryRepository.SetStatuses - error writing statuses", "error", err)
+		return err
+	}
+
+	logger.Debug(ctx, "repo: MasteryRepository.SetStatuses - completed", "upsertedCount", result.UpsertedCount, "modifiedCount", result.ModifiedCount, "deletedCount", result.DeletedCount)
+	return nil
+}
+
+func (r *MasteryRepository) ClearAll(ctx context.Context, userID string) error {
+	logger.Debug(ctx, "repo: MasteryRepository.ClearAll called", "userID", userID)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result, err := r.collection.DeleteMany(ctx, bson.M{"userId": userID})
+	if err != nil {
+		logger.Error(ctx, "repo: MasteryRepository.ClearAll - error clearing mastery", "error", err)
+		return err
+	}
+
+	logger.Debug(ctx, "repo: MasteryRepository.ClearAll - completed", "deletedCount", result.DeletedCount)
+	return nil
+}
@@ -0,0 +1,56 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ActivityRepository struct {
+	mu      sync.Mutex
+	entries []models.ActivityEntry
+}
+
+func NewActivityRepository() *ActivityRepository {
+	return &ActivityRepository{}
+}
+
+func (r *ActivityRepository) Create(ctx context.Context, entry *models.ActivityEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry.ID = primitive.NewObjectID()
+	r.entries = append(r.entries, *entry)
+	return nil
+}
+
+func (r *ActivityRepository) ListByUser(ctx context.Context, userID string, offset, limit int) ([]models.ActivityEntry, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entries := []models.ActivityEntry{}
+	for _, entry := range r.entries {
+		if entry.UserID == userID {
+			entries = append(entries, entry)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].CreatedAt.Equal(entries[j].CreatedAt) {
+			return entries[i].CreatedAt.After(entries[j].CreatedAt)
+		}
+		return entries[i].ID.Hex() > entries[j].ID.Hex()
+	})
+
+	total := len(entries)
+	if offset >= total {
+		return []models.ActivityEntry{}, total, nil
+	}
+	entries = entries[offset:]
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, total, nil
+}
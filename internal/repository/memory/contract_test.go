@@ -0,0 +1,206 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+	"github.com/graytonio/warframe-wishlist/internal/repository/repotest"
+)
+
+func TestItemRepository_Contract(t *testing.T) {
+	repotest.RunItemRepositoryContract(t, func(t *testing.T, seed repotest.ItemSeed) repository.ItemRepositoryInterface {
+		repo := NewItemRepository()
+		for collection, data := range seed {
+			if _, err := repo.AddJSON(collection, []byte(data)); err != nil {
+				t.Fatalf("failed to seed %s: %v", collection, err)
+			}
+		}
+		return repo
+	})
+}
+
+func TestWishlistRepository_Contract(t *testing.T) {
+	repotest.RunWishlistRepositoryContract(t, func(t *testing.T) repository.WishlistRepositoryInterface {
+		return NewWishlistRepository()
+	})
+}
+
+func TestOwnedBlueprintsRepository_Contract(t *testing.T) {
+	repotest.RunOwnedBlueprintsRepositoryContract(t, func(t *testing.T) repository.OwnedBlueprintsRepositoryInterface {
+		return NewOwnedBlueprintsRepository()
+	})
+}
+
+func TestOwnedMaterialsRepository_Contract(t *testing.T) {
+	repotest.RunOwnedMaterialsRepositoryContract(t, func(t *testing.T) repository.OwnedMaterialsRepositoryInterface {
+		return NewOwnedMaterialsRepository()
+	})
+}
+
+func TestMasteryRepository_Contract(t *testing.T) {
+	repotest.RunMasteryRepositoryContract(t, func(t *testing.T) repository.MasteryRepositoryInterface {
+		return NewMasteryRepository()
+	})
+}
+
+func TestSettingsRepository_Contract(t *testing.T) {
+	repotest.RunSettingsRepositoryContract(t, func(t *testing.T) repository.SettingsRepositoryInterface {
+		return NewSettingsRepository()
+	})
+}
+
+func TestUserTraceRepository_Contract(t *testing.T) {
+	repotest.RunUserTraceRepositoryContract(t, func(t *testing.T) repository.UserTraceRepositoryInterface {
+		return NewUserTraceRepository()
+	})
+}
+
+func TestGiftClaimRepository_Contract(t *testing.T) {
+	repotest.RunGiftClaimRepositoryContract(t, func(t *testing.T) repository.GiftClaimRepositoryInterface {
+		return NewGiftClaimRepository()
+	})
+}
+
+func TestIdempotencyRepository_Contract(t *testing.T) {
+	repotest.RunIdempotencyRepositoryContract(t, func(t *testing.T) repository.IdempotencyRepositoryInterface {
+		return NewIdempotencyRepository()
+	})
+}
+
+func TestShareLinkRepository_Contract(t *testing.T) {
+	repotest.RunShareLinkRepositoryContract(t, func(t *testing.T) repository.ShareLinkRepositoryInterface {
+		return NewShareLinkRepository()
+	})
+}
+
+func TestShareLinkReportRepository_Contract(t *testing.T) {
+	repotest.RunShareLinkReportRepositoryContract(t, func(t *testing.T) repository.ShareLinkReportRepositoryInterface {
+		return NewShareLinkReportRepository()
+	})
+}
+
+func TestImportAliasRepository_Contract(t *testing.T) {
+	repotest.RunImportAliasRepositoryContract(t, func(t *testing.T) repository.ImportAliasRepositoryInterface {
+		return NewImportAliasRepository()
+	})
+}
+
+func TestCustomItemRepository_Contract(t *testing.T) {
+	repotest.RunCustomItemRepositoryContract(t, func(t *testing.T) repository.CustomItemRepositoryInterface {
+		return NewCustomItemRepository()
+	})
+}
+
+func TestFoundryRepository_Contract(t *testing.T) {
+	repotest.RunFoundryRepositoryContract(t, func(t *testing.T) repository.FoundryRepositoryInterface {
+		return NewFoundryRepository()
+	})
+}
+
+func TestWishlistTemplateRepository_Contract(t *testing.T) {
+	repotest.RunWishlistTemplateRepositoryContract(t, func(t *testing.T) repository.WishlistTemplateRepositoryInterface {
+		return NewWishlistTemplateRepository()
+	})
+}
+
+func TestNotificationRepository_Contract(t *testing.T) {
+	repotest.RunNotificationRepositoryContract(t, func(t *testing.T) repository.NotificationRepositoryInterface {
+		return NewNotificationRepository()
+	})
+}
+
+func TestIntegrationRepository_Contract(t *testing.T) {
+	repotest.RunIntegrationRepositoryContract(t, func(t *testing.T) repository.IntegrationRepositoryInterface {
+		return NewIntegrationRepository()
+	})
+}
+
+func TestWorkspaceRepository_Contract(t *testing.T) {
+	repotest.RunWorkspaceRepositoryContract(t, func(t *testing.T) repository.WorkspaceRepositoryInterface {
+		return NewWorkspaceRepository()
+	})
+}
+
+func TestWorkspaceContributionRepository_Contract(t *testing.T) {
+	repotest.RunWorkspaceContributionRepositoryContract(t, func(t *testing.T) repository.WorkspaceContributionRepositoryInterface {
+		return NewWorkspaceContributionRepository()
+	})
+}
+
+func TestActivityRepository_Contract(t *testing.T) {
+	repotest.RunActivityRepositoryContract(t, func(t *testing.T) repository.ActivityRepositoryInterface {
+		return NewActivityRepository()
+	})
+}
+
+func TestSyncStatusRepository_Contract(t *testing.T) {
+	repotest.RunSyncStatusRepositoryContract(t, func(t *testing.T) repository.SyncStatusRepositoryInterface {
+		return NewSyncStatusRepository()
+	})
+}
+
+func TestWishlistRepository_PopularityContract(t *testing.T) {
+	repotest.RunPopularityContract(t, func(t *testing.T) repotest.PopularityRepository {
+		return NewWishlistRepository()
+	})
+}
+
+func TestHouseholdRepository_Contract(t *testing.T) {
+	repotest.RunHouseholdRepositoryContract(t, func(t *testing.T) repository.HouseholdRepositoryInterface {
+		return NewHouseholdRepository()
+	})
+}
+
+func TestItemRepository_CatalogContract(t *testing.T) {
+	repotest.RunItemCatalogContract(t, func(t *testing.T, seed repotest.ItemSeed) repository.ItemCatalogInterface {
+		repo := NewItemRepository()
+		for collection, data := range seed {
+			if _, err := repo.AddJSON(collection, []byte(data)); err != nil {
+				t.Fatalf("failed to seed %s: %v", collection, err)
+			}
+		}
+		return repo
+	})
+}
+
+func TestItemRepository_RelicCatalogContract(t *testing.T) {
+	repotest.RunRelicCatalogContract(t, func(t *testing.T, seed repotest.ItemSeed) repository.RelicCatalogInterface {
+		repo := NewItemRepository()
+		for collection, data := range seed {
+			if _, err := repo.AddJSON(collection, []byte(data)); err != nil {
+				t.Fatalf("failed to seed %s: %v", collection, err)
+			}
+		}
+		return repo
+	})
+}
+
+func TestItemRepository_ItemExportContract(t *testing.T) {
+	repotest.RunItemExportContract(t, func(t *testing.T, seed repotest.ItemSeed) repository.ItemExportInterface {
+		repo := NewItemRepository()
+		for collection, data := range seed {
+			if _, err := repo.AddJSON(collection, []byte(data)); err != nil {
+				t.Fatalf("failed to seed %s: %v", collection, err)
+			}
+		}
+		return repo
+	})
+}
+
+func TestItemRepository_ItemGraphContract(t *testing.T) {
+	repotest.RunItemGraphContract(t, func(t *testing.T, seed repotest.ItemSeed) repository.ItemGraphInterface {
+		repo := NewItemRepository()
+		for collection, data := range seed {
+			if _, err := repo.AddJSON(collection, []byte(data)); err != nil {
+				t.Fatalf("failed to seed %s: %v", collection, err)
+			}
+		}
+		return repo
+	})
+}
+
+func TestItemChangeRepository_Contract(t *testing.T) {
+	repotest.RunItemChangeRepositoryContract(t, func(t *testing.T) repository.ItemChangeRepositoryInterface {
+		return NewItemChangeRepository()
+	})
+}
@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CustomItemRepository struct {
+	mu    sync.Mutex
+	items map[string]models.CustomItem // by uniqueName
+}
+
+func NewCustomItemRepository() *CustomItemRepository {
+	return &CustomItemRepository{items: make(map[string]models.CustomItem)}
+}
+
+func (r *CustomItemRepository) ListByUser(ctx context.Context, userID string) ([]models.CustomItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	items := []models.CustomItem{}
+	for _, item := range r.items {
+		if item.UserID == userID {
+			items = append(items, cloneCustomItem(item))
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Name != items[j].Name {
+			return items[i].Name < items[j].Name
+		}
+		return items[i].UniqueName < items[j].UniqueName
+	})
+	return items, nil
+}
+
+func (r *CustomItemRepository) Create(ctx context.Context, item *models.CustomItem) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	item.ID = primitive.NewObjectID()
+	item.UniqueName = models.CustomItemUniqueName(item.ID)
+	r.items[item.UniqueName] = cloneCustomItem(*item)
+	return nil
+}
+
+func (r *CustomItemRepository) Update(ctx context.Context, item *models.CustomItem) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.items[item.UniqueName]
+	if !ok || existing.UserID != item.UserID {
+		return false, nil
+	}
+	existing.Name = item.Name
+	existing.Description = item.Description
+	existing.BuildPrice = item.BuildPrice
+	existing.Components = item.Components
+	existing.UpdatedAt = item.UpdatedAt
+	r.items[item.UniqueName] = cloneCustomItem(existing)
+	return true, nil
+}
+
+func (r *CustomItemRepository) Delete(ctx context.Context, userID, uniqueName string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.items[uniqueName]
+	if !ok || existing.UserID != userID {
+		return false, nil
+	}
+	delete(r.items, uniqueName)
+	return true, nil
+}
+
+// cloneCustomItem copies item so callers never share its components with the
+// store. Stored items always have a non-nil components slice, as in MongoDB.
+func cloneCustomItem(item models.CustomItem) models.CustomItem {
+	item.Components = append([]models.Component{}, item.Components...)
+	return item
+}
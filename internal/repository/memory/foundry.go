@@ -0,0 +1,59 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type FoundryRepository struct {
+	mu     sync.Mutex
+	builds map[primitive.ObjectID]models.FoundryBuild
+}
+
+func NewFoundryRepository() *FoundryRepository {
+	return &FoundryRepository{builds: make(map[primitive.ObjectID]models.FoundryBuild)}
+}
+
+func (r *FoundryRepository) ListByUser(ctx context.Context, userID string) ([]models.FoundryBuild, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	builds := []models.FoundryBuild{}
+	for _, build := range r.builds {
+		if build.UserID == userID {
+			builds = append(builds, build)
+		}
+	}
+	sort.Slice(builds, func(i, j int) bool {
+		if !builds[i].StartedAt.Equal(builds[j].StartedAt) {
+			return builds[i].StartedAt.Before(builds[j].StartedAt)
+		}
+		return builds[i].ID.Hex() < builds[j].ID.Hex()
+	})
+	return builds, nil
+}
+
+func (r *FoundryRepository) Create(ctx context.Context, build *models.FoundryBuild) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	build.ID = primitive.NewObjectID()
+	r.builds[build.ID] = *build
+	return nil
+}
+
+func (r *FoundryRepository) Delete(ctx context.Context, userID string, id primitive.ObjectID) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	build, ok := r.builds[id]
+	if !ok || build.UserID != userID {
+		return false, nil
+	}
+	delete(r.builds, id)
+	return true, nil
+}
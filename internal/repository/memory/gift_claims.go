@@ -0,0 +1,112 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type giftClaimKey struct {
+	ownerID    string
+	uniqueName string
+}
+
+type GiftClaimRepository struct {
+	mu     sync.Mutex
+	claims map[giftClaimKey]models.GiftClaim
+}
+
+func NewGiftClaimRepository() *GiftClaimRepository {
+	return &GiftClaimRepository{claims: make(map[giftClaimKey]models.GiftClaim)}
+}
+
+func (r *GiftClaimRepository) Get(ctx context.Context, ownerID, uniqueName string) (*models.GiftClaim, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.active(giftClaimKey{ownerID, uniqueName}, time.Now()), nil
+}
+
+func (r *GiftClaimRepository) Claim(ctx context.Context, claim *models.GiftClaim) (*models.GiftClaim, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := giftClaimKey{claim.OwnerID, claim.UniqueName}
+	existing := r.active(key, time.Now())
+	switch {
+	case existing == nil:
+		stored := *claim
+		stored.ID = primitive.NewObjectID()
+		r.claims[key] = stored
+		return &stored, nil
+	case existing.ClaimerID == claim.ClaimerID:
+		existing.Anonymous = claim.Anonymous
+		existing.ExpiresAt = claim.ExpiresAt
+		r.claims[key] = *existing
+		return existing, nil
+	default:
+		return existing, nil
+	}
+}
+
+func (r *GiftClaimRepository) ListByOwner(ctx context.Context, ownerID string) ([]models.GiftClaim, error) {
+	claims := r.list(func(c models.GiftClaim) bool { return c.OwnerID == ownerID })
+	sort.Slice(claims, func(i, j int) bool { return claims[i].UniqueName < claims[j].UniqueName })
+	return claims, nil
+}
+
+func (r *GiftClaimRepository) ListByClaimer(ctx context.Context, claimerID string) ([]models.GiftClaim, error) {
+	claims := r.list(func(c models.GiftClaim) bool { return c.ClaimerID == claimerID })
+	sort.Slice(claims, func(i, j int) bool {
+		if !claims[i].ExpiresAt.Equal(claims[j].ExpiresAt) {
+			return claims[i].ExpiresAt.Before(claims[j].ExpiresAt)
+		}
+		return claims[i].ID.Hex() < claims[j].ID.Hex()
+	})
+	return claims, nil
+}
+
+func (r *GiftClaimRepository) Delete(ctx context.Context, ownerID, uniqueName, claimerID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := giftClaimKey{ownerID, uniqueName}
+	existing := r.active(key, time.Now())
+	if existing == nil || (claimerID != "" && existing.ClaimerID != claimerID) {
+		return false, nil
+	}
+	delete(r.claims, key)
+	return true, nil
+}
+
+// active returns a copy of the claim under key if it has not expired,
+// dropping it if it has. r.mu must be held.
+func (r *GiftClaimRepository) active(key giftClaimKey, now time.Time) *models.GiftClaim {
+	claim, ok := r.claims[key]
+	if !ok {
+		return nil
+	}
+	if !claim.Active(now) {
+		delete(r.claims, key)
+		return nil
+	}
+	return &claim
+}
+
+func (r *GiftClaimRepository) list(match func(models.GiftClaim) bool) []models.GiftClaim {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	claims := []models.GiftClaim{}
+	for _, claim := range r.claims {
+		if claim.Active(now) && match(claim) {
+			claims = append(claims, claim)
+		}
+	}
+	return claims
+}
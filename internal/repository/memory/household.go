@@ -0,0 +1,157 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type HouseholdRepository struct {
+	mu      sync.Mutex
+	links   map[string]*models.HouseholdLink
+	changes map[primitive.ObjectID]*models.PendingChange
+}
+
+func NewHouseholdRepository() *HouseholdRepository {
+	return &HouseholdRepository{
+		links:   make(map[string]*models.HouseholdLink),
+		changes: make(map[primitive.ObjectID]*models.PendingChange),
+	}
+}
+
+func (r *HouseholdRepository) GetLinkByMember(ctx context.Context, memberID string) (*models.HouseholdLink, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.links[memberID]
+	if !ok {
+		return nil, nil
+	}
+	link := *stored
+	return &link, nil
+}
+
+func (r *HouseholdRepository) ListLinksByManager(ctx context.Context, managerID string) ([]models.HouseholdLink, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	links := []models.HouseholdLink{}
+	for _, link := range r.links {
+		if link.ManagerID == managerID {
+			links = append(links, *link)
+		}
+	}
+	sort.Slice(links, func(i, j int) bool {
+		return links[i].CreatedAt.Before(links[j].CreatedAt)
+	})
+	return links, nil
+}
+
+func (r *HouseholdRepository) CreateLink(ctx context.Context, link *models.HouseholdLink) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.links[link.MemberID]; ok {
+		return false, nil
+	}
+
+	link.ID = primitive.NewObjectID()
+	link.CreatedAt = time.Now()
+	link.UpdatedAt = link.CreatedAt
+
+	stored := *link
+	r.links[link.MemberID] = &stored
+	return true, nil
+}
+
+func (r *HouseholdRepository) UpdateLink(ctx context.Context, memberID, status string, quantityThreshold int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	link, ok := r.links[memberID]
+	if !ok {
+		return nil
+	}
+	link.Status = status
+	link.QuantityThreshold = quantityThreshold
+	link.UpdatedAt = time.Now()
+	return nil
+}
+
+func (r *HouseholdRepository) DeleteLink(ctx context.Context, memberID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.links, memberID)
+	return nil
+}
+
+func (r *HouseholdRepository) CreatePendingChange(ctx context.Context, change *models.PendingChange) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	change.ID = primitive.NewObjectID()
+	change.CreatedAt = time.Now()
+
+	stored := *change
+	r.changes[change.ID] = &stored
+	return nil
+}
+
+func (r *HouseholdRepository) GetPendingChange(ctx context.Context, id primitive.ObjectID) (*models.PendingChange, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.changes[id]
+	if !ok {
+		return nil, nil
+	}
+	change := *stored
+	return &change, nil
+}
+
+func (r *HouseholdRepository) ListChangesByManager(ctx context.Context, managerID, status string) ([]models.PendingChange, error) {
+	return r.listChanges(func(c *models.PendingChange) bool { return c.ManagerID == managerID }, status), nil
+}
+
+func (r *HouseholdRepository) ListChangesByMember(ctx context.Context, memberID, status string) ([]models.PendingChange, error) {
+	return r.listChanges(func(c *models.PendingChange) bool { return c.MemberID == memberID }, status), nil
+}
+
+func (r *HouseholdRepository) listChanges(match func(*models.PendingChange) bool, status string) []models.PendingChange {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	changes := []models.PendingChange{}
+	for _, change := range r.changes {
+		if match(change) && (status == "" || change.Status == status) {
+			changes = append(changes, *change)
+		}
+	}
+	// Newest first, matching the MongoDB sort on createdAt then _id.
+	sort.Slice(changes, func(i, j int) bool {
+		if !changes[i].CreatedAt.Equal(changes[j].CreatedAt) {
+			return changes[i].CreatedAt.After(changes[j].CreatedAt)
+		}
+		return changes[i].ID.Hex() > changes[j].ID.Hex()
+	})
+	return changes
+}
+
+func (r *HouseholdRepository) DecideChange(ctx context.Context, id primitive.ObjectID, status string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	change, ok := r.changes[id]
+	if !ok || change.Status != models.ChangeStatusPending {
+		return false, nil
+	}
+	now := time.Now()
+	change.Status = status
+	change.DecidedAt = &now
+	return true, nil
+}
@@ -0,0 +1,70 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type idempotencyKey struct {
+	userID string
+	key    string
+}
+
+type IdempotencyRepository struct {
+	mu      sync.Mutex
+	records map[idempotencyKey]models.IdempotencyRecord
+}
+
+func NewIdempotencyRepository() *IdempotencyRepository {
+	return &IdempotencyRepository{records: make(map[idempotencyKey]models.IdempotencyRecord)}
+}
+
+func (r *IdempotencyRepository) Reserve(ctx context.Context, record *models.IdempotencyRecord) (*models.IdempotencyRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := idempotencyKey{record.UserID, record.Key}
+	if existing, ok := r.records[key]; ok {
+		if existing.Active(time.Now()) {
+			return &existing, nil
+		}
+		delete(r.records, key)
+	}
+
+	record.ID = primitive.NewObjectID()
+	stored := *record
+	stored.Completed = false
+	r.records[key] = stored
+	return nil, nil
+}
+
+func (r *IdempotencyRepository) Complete(ctx context.Context, record *models.IdempotencyRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := idempotencyKey{record.UserID, record.Key}
+	stored, ok := r.records[key]
+	if !ok || stored.Completed {
+		return nil
+	}
+	stored.Completed = true
+	stored.Status = record.Status
+	stored.Header = record.Header
+	stored.Body = append([]byte(nil), record.Body...)
+	r.records[key] = stored
+	return nil
+}
+
+func (r *IdempotencyRepository) Release(ctx context.Context, userID, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if stored, ok := r.records[idempotencyKey{userID, key}]; ok && !stored.Completed {
+		delete(r.records, idempotencyKey{userID, key})
+	}
+	return nil
+}
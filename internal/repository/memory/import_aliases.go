@@ -0,0 +1,48 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+type ImportAliasRepository struct {
+	mu      sync.Mutex
+	aliases map[string]models.ImportAlias // by word
+}
+
+func NewImportAliasRepository() *ImportAliasRepository {
+	return &ImportAliasRepository{aliases: make(map[string]models.ImportAlias)}
+}
+
+func (r *ImportAliasRepository) List(ctx context.Context) ([]models.ImportAlias, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	aliases := []models.ImportAlias{}
+	for _, alias := range r.aliases {
+		aliases = append(aliases, alias)
+	}
+	sort.Slice(aliases, func(i, j int) bool { return aliases[i].Word < aliases[j].Word })
+	return aliases, nil
+}
+
+func (r *ImportAliasRepository) Set(ctx context.Context, alias models.ImportAlias) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	alias.BuiltIn = false
+	r.aliases[alias.Word] = alias
+	return nil
+}
+
+func (r *ImportAliasRepository) Delete(ctx context.Context, word string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.aliases[word]
+	delete(r.aliases, word)
+	return ok, nil
+}
@@ -0,0 +1,158 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type IntegrationRepository struct {
+	mu           sync.Mutex
+	integrations map[primitive.ObjectID]models.Integration
+	deliveries   map[primitive.ObjectID]models.IntegrationDelivery
+}
+
+func NewIntegrationRepository() *IntegrationRepository {
+	return &IntegrationRepository{
+		integrations: make(map[primitive.ObjectID]models.Integration),
+		deliveries:   make(map[primitive.ObjectID]models.IntegrationDelivery),
+	}
+}
+
+func (r *IntegrationRepository) CreateIntegration(ctx context.Context, integration *models.Integration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	integration.ID = primitive.NewObjectID()
+	stored := *integration
+	stored.Events = append([]string(nil), integration.Events...)
+	r.integrations[integration.ID] = stored
+	return nil
+}
+
+func (r *IntegrationRepository) GetIntegration(ctx context.Context, id primitive.ObjectID) (*models.Integration, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	integration, ok := r.integrations[id]
+	if !ok {
+		return nil, nil
+	}
+	return &integration, nil
+}
+
+func (r *IntegrationRepository) ListIntegrations(ctx context.Context) ([]models.Integration, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	integrations := []models.Integration{}
+	for _, integration := range r.integrations {
+		integrations = append(integrations, integration)
+	}
+	sort.Slice(integrations, func(i, j int) bool {
+		if !integrations[i].CreatedAt.Equal(integrations[j].CreatedAt) {
+			return integrations[i].CreatedAt.Before(integrations[j].CreatedAt)
+		}
+		return integrations[i].ID.Hex() < integrations[j].ID.Hex()
+	})
+	return integrations, nil
+}
+
+func (r *IntegrationRepository) DeleteIntegration(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.integrations[id]; !ok {
+		return false, nil
+	}
+	delete(r.integrations, id)
+	return true, nil
+}
+
+func (r *IntegrationRepository) EnqueueDelivery(ctx context.Context, delivery *models.IntegrationDelivery) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.deliveries {
+		if existing.IntegrationID == delivery.IntegrationID && existing.Key == delivery.Key {
+			return false, nil
+		}
+	}
+	delivery.ID = primitive.NewObjectID()
+	r.deliveries[delivery.ID] = *delivery
+	return true, nil
+}
+
+func (r *IntegrationRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.IntegrationDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	due := []models.IntegrationDelivery{}
+	for _, delivery := range r.deliveries {
+		if delivery.Status == models.DeliveryPending && !delivery.NextAttemptAt.After(now) {
+			due = append(due, delivery)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		if !due[i].NextAttemptAt.Equal(due[j].NextAttemptAt) {
+			return due[i].NextAttemptAt.Before(due[j].NextAttemptAt)
+		}
+		return due[i].ID.Hex() < due[j].ID.Hex()
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+	for i := range due {
+		due[i].NextAttemptAt = now.Add(lease)
+		r.deliveries[due[i].ID] = due[i]
+	}
+	return due, nil
+}
+
+func (r *IntegrationRepository) UpdateDelivery(ctx context.Context, delivery *models.IntegrationDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.deliveries[delivery.ID]
+	if !ok {
+		return nil
+	}
+	stored.Status = delivery.Status
+	stored.Attempts = delivery.Attempts
+	stored.ResponseStatus = delivery.ResponseStatus
+	stored.LastError = delivery.LastError
+	stored.NextAttemptAt = delivery.NextAttemptAt
+	stored.DeliveredAt = nil
+	if delivery.DeliveredAt != nil {
+		deliveredAt := *delivery.DeliveredAt
+		stored.DeliveredAt = &deliveredAt
+	}
+	r.deliveries[delivery.ID] = stored
+	return nil
+}
+
+func (r *IntegrationRepository) ListDeliveries(ctx context.Context, integrationID primitive.ObjectID, limit int) ([]models.IntegrationDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	deliveries := []models.IntegrationDelivery{}
+	for _, delivery := range r.deliveries {
+		if delivery.IntegrationID == integrationID {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	sort.Slice(deliveries, func(i, j int) bool {
+		if !deliveries[i].CreatedAt.Equal(deliveries[j].CreatedAt) {
+			return deliveries[i].CreatedAt.After(deliveries[j].CreatedAt)
+		}
+		return deliveries[i].ID.Hex() > deliveries[j].ID.Hex()
+	})
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, nil
+}
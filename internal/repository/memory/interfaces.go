@@ -0,0 +1,31 @@
+package memory
+
+import "github.com/graytonio/warframe-wishlist/internal/repository"
+
+var _ repository.ItemRepositoryInterface = (*ItemRepository)(nil)
+var _ repository.ItemCatalogInterface = (*ItemRepository)(nil)
+var _ repository.RelicCatalogInterface = (*ItemRepository)(nil)
+var _ repository.ItemGraphInterface = (*ItemRepository)(nil)
+var _ repository.WishlistRepositoryInterface = (*WishlistRepository)(nil)
+var _ repository.PopularityRepositoryInterface = (*WishlistRepository)(nil)
+var _ repository.HouseholdRepositoryInterface = (*HouseholdRepository)(nil)
+var _ repository.ItemChangeRepositoryInterface = (*ItemChangeRepository)(nil)
+var _ repository.GiftClaimRepositoryInterface = (*GiftClaimRepository)(nil)
+var _ repository.IdempotencyRepositoryInterface = (*IdempotencyRepository)(nil)
+var _ repository.ShareLinkRepositoryInterface = (*ShareLinkRepository)(nil)
+var _ repository.ShareLinkReportRepositoryInterface = (*ShareLinkReportRepository)(nil)
+var _ repository.ImportAliasRepositoryInterface = (*ImportAliasRepository)(nil)
+var _ repository.CustomItemRepositoryInterface = (*CustomItemRepository)(nil)
+var _ repository.FoundryRepositoryInterface = (*FoundryRepository)(nil)
+var _ repository.WishlistTemplateRepositoryInterface = (*WishlistTemplateRepository)(nil)
+var _ repository.NotificationRepositoryInterface = (*NotificationRepository)(nil)
+var _ repository.IntegrationRepositoryInterface = (*IntegrationRepository)(nil)
+var _ repository.WorkspaceRepositoryInterface = (*WorkspaceRepository)(nil)
+var _ repository.WorkspaceContributionRepositoryInterface = (*WorkspaceContributionRepository)(nil)
+var _ repository.ActivityRepositoryInterface = (*ActivityRepository)(nil)
+var _ repository.OwnedBlueprintsRepositoryInterface = (*OwnedBlueprintsRepository)(nil)
+var _ repository.OwnedMaterialsRepositoryInterface = (*OwnedMaterialsRepository)(nil)
+var _ repository.MasteryRepositoryInterface = (*MasteryRepository)(nil)
+var _ repository.SettingsRepositoryInterface = (*SettingsRepository)(nil)
+var _ repository.UserTraceRepositoryInterface = (*UserTraceRepository)(nil)
+var _ repository.SyncStatusRepositoryInterface = (*SyncStatusRepository)(nil)
@@ -0,0 +1,83 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ItemChangeRepository struct {
+	mu           sync.Mutex
+	fingerprints map[string]models.ItemFingerprint
+	changes      []models.ItemChange
+}
+
+func NewItemChangeRepository() *ItemChangeRepository {
+	return &ItemChangeRepository{
+		fingerprints: make(map[string]models.ItemFingerprint),
+	}
+}
+
+func (r *ItemChangeRepository) GetFingerprints(ctx context.Context) (map[string]models.ItemFingerprint, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	fingerprints := make(map[string]models.ItemFingerprint, len(r.fingerprints))
+	for name, fp := range r.fingerprints {
+		fingerprints[name] = fp
+	}
+	return fingerprints, nil
+}
+
+func (r *ItemChangeRepository) ReplaceFingerprints(ctx context.Context, fingerprints []models.ItemFingerprint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.fingerprints = make(map[string]models.ItemFingerprint, len(fingerprints))
+	for _, fp := range fingerprints {
+		r.fingerprints[fp.UniqueName] = fp
+	}
+	return nil
+}
+
+func (r *ItemChangeRepository) RecordChanges(ctx context.Context, changes []models.ItemChange) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range changes {
+		changes[i].ID = primitive.NewObjectID()
+		stored := changes[i]
+		stored.Kinds = append([]string(nil), stored.Kinds...)
+		r.changes = append(r.changes, stored)
+	}
+	return nil
+}
+
+func (r *ItemChangeRepository) ListChangesSince(ctx context.Context, since time.Time, limit int) ([]models.ItemChange, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	changes := []models.ItemChange{}
+	// Walk newest-recorded first so equal timestamps match Mongo's _id
+	// descending tie-break.
+	for i := len(r.changes) - 1; i >= 0; i-- {
+		if r.changes[i].ChangedAt.Before(since) {
+			continue
+		}
+		change := r.changes[i]
+		change.Kinds = append([]string(nil), change.Kinds...)
+		changes = append(changes, change)
+	}
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].ChangedAt.After(changes[j].ChangedAt)
+	})
+
+	if limit > 0 && len(changes) > limit {
+		changes = changes[:limit]
+	}
+	return changes, nil
+}
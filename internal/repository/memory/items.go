@@ -0,0 +1,395 @@
+// Package memory provides in-memory implementations of the repository
+// interfaces. They mirror the semantics of the MongoDB repositories (nil for
+// missing documents, no-op updates on missing documents, per-collection
+// ordering) and are used for handler-level integration tests and the
+// zero-dependency demo mode.
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+)
+
+type ItemRepository struct {
+	mu          sync.RWMutex
+	collections map[string][]storedItem
+	// index maps collection -> uniqueName -> position in collections, so
+	// lookups stay O(1) with the full WFCD dataset loaded.
+	index map[string]map[string]int
+	scope repository.SearchScope
+}
+
+// storedItem pairs an item with whether it matches Mongo's
+// {consumeOnBuild: false} filter, which requires the field to be present.
+type storedItem struct {
+	item     models.Item
+	reusable bool
+}
+
+// itemDocument decodes a raw item while recording whether consumeOnBuild was
+// present in the source document.
+type itemDocument struct {
+	models.Item
+	ConsumeOnBuild *bool `json:"consumeOnBuild"`
+}
+
+func NewItemRepository() *ItemRepository {
+	return &ItemRepository{
+		collections: make(map[string][]storedItem),
+		index:       make(map[string]map[string]int),
+		scope:       repository.DefaultSearchScope(),
+	}
+}
+
+// SetSearchScope replaces repository.DefaultSearchScope.
+func (r *ItemRepository) SetSearchScope(scope repository.SearchScope) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.scope = scope
+}
+
+// Add stores items in the named collection, replacing any existing item in
+// that collection with the same uniqueName. A false ConsumeOnBuild is treated
+// as explicitly set; use AddJSON to load documents where the field may be absent.
+func (r *ItemRepository) Add(collection string, items ...models.Item) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, item := range items {
+		r.store(collection, storedItem{item: copyItem(item), reusable: !item.ConsumeOnBuild})
+	}
+}
+
+// AddJSON stores a JSON array of raw item documents in the named collection,
+// in the same shape as the WFCD data files, and returns the number added.
+func (r *ItemRepository) AddJSON(collection string, data []byte) (int, error) {
+	var docs []itemDocument
+	if err := json.Unmarshal(data, &docs); err != nil {
+		return 0, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, doc := range docs {
+		item := doc.Item
+		item.ConsumeOnBuild = doc.ConsumeOnBuild != nil && *doc.ConsumeOnBuild
+		reusable := doc.ConsumeOnBuild != nil && !*doc.ConsumeOnBuild
+		r.store(collection, storedItem{item: copyItem(item), reusable: reusable})
+	}
+	return len(docs), nil
+}
+
+func (r *ItemRepository) store(collection string, stored storedItem) {
+	positions, ok := r.index[collection]
+	if !ok {
+		positions = make(map[string]int)
+		r.index[collection] = positions
+	}
+
+	if i, exists := positions[stored.item.UniqueName]; exists {
+		r.collections[collection][i] = stored
+		return
+	}
+	positions[stored.item.UniqueName] = len(r.collections[collection])
+	r.collections[collection] = append(r.collections[collection], stored)
+}
+
+// lookup returns the item stored in collection under uniqueName.
+func (r *ItemRepository) lookup(collection, uniqueName string) (storedItem, bool) {
+	i, ok := r.index[collection][uniqueName]
+	if !ok {
+		return storedItem{}, false
+	}
+	return r.collections[collection][i], true
+}
+
+// Count returns the total number of stored items across all collections.
+func (r *ItemRepository) Count() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	total := 0
+	for _, items := range r.collections {
+		total += len(items)
+	}
+	return total
+}
+
+// Search mirrors the Mongo text search: see textQuery for how the query is
+// matched and scored.
+func (r *ItemRepository) Search(ctx context.Context, params models.SearchParams) (*models.ItemSearchPage, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	params = params.Normalized()
+	query := parseTextQuery(params.Query)
+
+	collections := r.scope.Collections
+	if params.Category != "" {
+		collections = []string{params.Category}
+	}
+
+	var matches []models.ItemSearchResult
+	for _, collName := range collections {
+		for _, stored := range r.collections[collName] {
+			item := stored.item
+			if item.Archived && !params.IncludeArchived {
+				continue
+			}
+			result := toSearchResult(item, collName)
+			if params.Query != "" {
+				score, ok := query.score(item)
+				if !ok {
+					continue
+				}
+				result.Score = score
+			}
+			matches = append(matches, result)
+		}
+	}
+
+	return &models.ItemSearchPage{
+		Items: repository.PageSearchResults(matches, params),
+		Total: len(matches),
+	}, nil
+}
+
+func (r *ItemRepository) FindByUniqueName(ctx context.Context, uniqueName string) (*models.Item, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, collName := range r.scope.LookupOrder() {
+		if stored, ok := r.lookup(collName, uniqueName); ok {
+			found := copyItem(stored.item)
+			found.Collection = collName
+			return &found, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *ItemRepository) FindByUniqueNames(ctx context.Context, uniqueNames []string) (map[string]*models.Item, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make(map[string]*models.Item)
+	if len(uniqueNames) == 0 {
+		return result, nil
+	}
+
+	r.findInCollections(r.scope.Collections, uniqueNames, result)
+	var missing []string
+	for _, name := range uniqueNames {
+		if _, ok := result[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	r.findInCollections(r.scope.Excluded, missing, result)
+	return result, nil
+}
+
+// findInCollections adds the items named in uniqueNames found in collections
+// to result. Later collections overwrite earlier ones, matching the Mongo loop.
+func (r *ItemRepository) findInCollections(collections []string, uniqueNames []string, result map[string]*models.Item) {
+	for _, collName := range collections {
+		for _, name := range uniqueNames {
+			if stored, ok := r.lookup(collName, name); ok {
+				found := copyItem(stored.item)
+				found.Collection = collName
+				result[name] = &found
+			}
+		}
+	}
+}
+
+func (r *ItemRepository) ForEachItem(ctx context.Context, fn func(item models.Item) error) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	for _, collName := range repository.ItemCollections {
+		for _, stored := range r.collections[collName] {
+			if stored.item.UniqueName == "" || seen[stored.item.UniqueName] {
+				continue
+			}
+			seen[stored.item.UniqueName] = true
+			item := copyItem(stored.item)
+			item.Collection = collName
+			if err := fn(item); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (r *ItemRepository) CountCategory(ctx context.Context, category string) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	count := 0
+	for _, stored := range r.collections[category] {
+		if stored.item.UniqueName != "" {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *ItemRepository) ForEachInCategory(ctx context.Context, category string, skip, limit int, fn func(item models.Item) error) error {
+	r.mu.RLock()
+	items := make([]models.Item, 0, len(r.collections[category]))
+	for _, stored := range r.collections[category] {
+		if stored.item.UniqueName != "" {
+			item := copyItem(stored.item)
+			item.Collection = category
+			items = append(items, item)
+		}
+	}
+	r.mu.RUnlock()
+
+	slices.SortFunc(items, func(a, b models.Item) int { return strings.Compare(a.UniqueName, b.UniqueName) })
+	items = items[min(skip, len(items)):]
+	if limit > 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	for _, item := range items {
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FindComponentTree walks the components in memory. Like the Mongo item
+// graph it takes each item from the collection FindByUniqueNames would.
+func (r *ItemRepository) FindComponentTree(ctx context.Context, uniqueNames []string) (map[string]*models.Item, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make(map[string]*models.Item)
+	order := r.scope.BatchLookupOrder()
+	queue := slices.Clone(uniqueNames)
+	seen := make(map[string]bool, len(queue))
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		for _, collName := range order {
+			stored, ok := r.lookup(collName, name)
+			if !ok {
+				continue
+			}
+			found := copyItem(stored.item)
+			found.Collection = collName
+			result[name] = &found
+			for _, component := range found.Components {
+				queue = append(queue, component.UniqueName)
+				for _, embedded := range component.Components {
+					queue = append(queue, embedded.UniqueName)
+				}
+			}
+			break
+		}
+	}
+	return result, nil
+}
+
+func (r *ItemRepository) FindRelicsByNames(ctx context.Context, names []string) (map[string]*models.Item, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+	result := make(map[string]*models.Item)
+	for _, stored := range r.collections["relics"] {
+		if wanted[stored.item.Name] {
+			found := copyItem(stored.item)
+			found.Collection = "relics"
+			result[found.Name] = &found
+		}
+	}
+	return result, nil
+}
+
+func (r *ItemRepository) SearchReusableBlueprints(ctx context.Context, query string, limit int) ([]models.ItemSearchResult, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var results []models.ItemSearchResult
+
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	var pattern *regexp.Regexp
+	if query != "" {
+		var err error
+		pattern, err = regexp.Compile("(?i)" + query)
+		if err != nil {
+			return results, nil
+		}
+	}
+
+	for _, collName := range r.scope.Collections {
+		var items []models.ItemSearchResult
+		for _, stored := range r.collections[collName] {
+			item := stored.item
+			if !stored.reusable {
+				continue
+			}
+			if pattern != nil && !pattern.MatchString(item.Name) {
+				continue
+			}
+			if len(items) >= limit {
+				break
+			}
+			items = append(items, toSearchResult(item, collName))
+		}
+
+		results = append(results, items...)
+
+		if len(results) >= limit {
+			results = results[:limit]
+			break
+		}
+	}
+
+	return results, nil
+}
+
+func toSearchResult(item models.Item, collection string) models.ItemSearchResult {
+	return models.ItemSearchResult{
+		UniqueName:  item.UniqueName,
+		Name:        item.Name,
+		Description: item.Description,
+		Category:    item.Category,
+		ImageName:   item.ImageName,
+		Archived:    item.Archived,
+		Collection:  collection,
+	}
+}
+
+// copyItem returns a copy of item that shares no slices with the original, so
+// callers can mutate results (e.g. HasOwnPage) without touching stored data.
+func copyItem(item models.Item) models.Item {
+	return *item.Clone()
+}
@@ -0,0 +1,140 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+)
+
+func newTestItemRepository() *ItemRepository {
+	repo := NewItemRepository()
+	repo.Add("warframes",
+		models.Item{UniqueName: "/Lotus/Powersuits/Excalibur", Name: "Excalibur", ConsumeOnBuild: true,
+			Components: []models.Component{{UniqueName: "/Lotus/Chassis", Name: "Chassis", ItemCount: 1}}},
+		models.Item{UniqueName: "/Lotus/Powersuits/Ember", Name: "Ember", ConsumeOnBuild: true},
+	)
+	repo.Add("primary",
+		models.Item{UniqueName: "/Lotus/Weapons/Braton", Name: "Braton"},
+		models.Item{UniqueName: "/Lotus/Weapons/BratonPrime", Name: "Braton Prime"},
+	)
+	repo.Add("resources", models.Item{UniqueName: "/Lotus/Chassis", Name: "Chassis"})
+	return repo
+}
+
+func TestItemRepository_Search(t *testing.T) {
+	repo := newTestItemRepository()
+
+	tests := []struct {
+		name     string
+		params   models.SearchParams
+		expected []string
+	}{
+		{name: "case insensitive query", params: models.SearchParams{Query: "braton"}, expected: []string{"Braton", "Braton Prime"}},
+		{name: "collection order", params: models.SearchParams{}, expected: []string{"Excalibur", "Ember", "Braton", "Braton Prime", "Chassis"}},
+		{name: "category filter", params: models.SearchParams{Category: "warframes"}, expected: []string{"Excalibur", "Ember"}},
+		{name: "limit", params: models.SearchParams{Limit: 3}, expected: []string{"Excalibur", "Ember", "Braton"}},
+		{name: "offset applies across collections", params: models.SearchParams{Offset: 1}, expected: []string{"Ember", "Braton", "Braton Prime", "Chassis"}},
+		{name: "query without words", params: models.SearchParams{Query: "("}, expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := repo.Search(context.Background(), tt.params)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			results := page.Items
+			if len(results) != len(tt.expected) {
+				t.Fatalf("expected %d results, got %d", len(tt.expected), len(results))
+			}
+			for i, name := range tt.expected {
+				if results[i].Name != name {
+					t.Errorf("result %d: expected '%s', got '%s'", i, name, results[i].Name)
+				}
+			}
+		})
+	}
+}
+
+func TestItemRepository_FindByUniqueName(t *testing.T) {
+	repo := newTestItemRepository()
+
+	item, err := repo.FindByUniqueName(context.Background(), "/Lotus/Powersuits/Excalibur")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if item == nil || item.Collection != "warframes" {
+		t.Fatalf("expected item from warframes collection, got %+v", item)
+	}
+
+	// Mutating a result must not leak into later reads.
+	item.Components[0].HasOwnPage = true
+	again, _ := repo.FindByUniqueName(context.Background(), "/Lotus/Powersuits/Excalibur")
+	if again.Components[0].HasOwnPage {
+		t.Error("expected stored item to be unaffected by caller mutation")
+	}
+
+	missing, err := repo.FindByUniqueName(context.Background(), "/Lotus/Missing")
+	if err != nil || missing != nil {
+		t.Errorf("expected nil, nil for missing item, got %v, %v", missing, err)
+	}
+}
+
+func TestItemRepository_FindByUniqueNames(t *testing.T) {
+	repo := newTestItemRepository()
+
+	result, err := repo.FindByUniqueNames(context.Background(), []string{"/Lotus/Chassis", "/Lotus/Weapons/Braton", "/Lotus/Missing"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(result))
+	}
+	if result["/Lotus/Chassis"].Collection != "resources" {
+		t.Errorf("expected collection 'resources', got '%s'", result["/Lotus/Chassis"].Collection)
+	}
+}
+
+func TestItemRepository_SearchReusableBlueprints(t *testing.T) {
+	repo := newTestItemRepository()
+
+	results, err := repo.SearchReusableBlueprints(context.Background(), "", 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, r := range results {
+		if r.Collection == "warframes" {
+			t.Errorf("expected consumed blueprints to be excluded, got '%s'", r.Name)
+		}
+	}
+	if len(results) != 3 {
+		t.Errorf("expected 3 results, got %d", len(results))
+	}
+}
+
+func TestItemRepository_SetSearchScope(t *testing.T) {
+	repo := newTestItemRepository()
+	scope, err := repository.NewSearchScope([]string{"primary"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	repo.SetSearchScope(scope)
+
+	page, err := repo.Search(context.Background(), models.SearchParams{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if page.Total != 3 {
+		t.Errorf("expected the primary weapons to be left out, got %+v", page.Items)
+	}
+
+	item, err := repo.FindByUniqueName(context.Background(), "/Lotus/Weapons/Braton")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if item == nil || item.Collection != "primary" {
+		t.Errorf("expected an excluded item to stay resolvable, got %+v", item)
+	}
+}
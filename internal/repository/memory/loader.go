@@ -0,0 +1,104 @@
+package memory
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+// skipDataFiles mirrors SKIP_FILES in sync_to_mongodb.py.
+var skipDataFiles = map[string]bool{
+	"All.json":  true,
+	"i18n.json": true,
+}
+
+// CollectionNameForFile converts a WFCD data file name to its collection name
+// the same way sync_to_mongodb.py does (lowercase, dashes to underscores).
+func CollectionNameForFile(fileName string) string {
+	name := strings.TrimSuffix(fileName, filepath.Ext(fileName))
+	return strings.ReplaceAll(strings.ToLower(name), "-", "_")
+}
+
+// LoadItemsFromDir reads every WFCD JSON file in dir into repo and returns the
+// number of items loaded.
+func LoadItemsFromDir(repo *ItemRepository, dir string) (int, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return 0, err
+	}
+	if len(files) == 0 {
+		return 0, fmt.Errorf("no JSON data files found in %s", dir)
+	}
+
+	total := 0
+	for _, file := range files {
+		base := filepath.Base(file)
+		if skipDataFiles[base] {
+			continue
+		}
+
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return total, fmt.Errorf("read %s: %w", base, err)
+		}
+
+		count, err := repo.AddJSON(CollectionNameForFile(base), data)
+		if err != nil {
+			return total, fmt.Errorf("decode %s: %w", base, err)
+		}
+		total += count
+	}
+
+	return total, nil
+}
+
+// LoadPublicWishlists reads a JSON array of public wishlists from path. IDs
+// must be present and unique; items without a quantity default to 1 and items
+// without addedAt take the file's modification time.
+func LoadPublicWishlists(path string) ([]models.PublicWishlist, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var wishlists []models.PublicWishlist
+	if err := json.Unmarshal(data, &wishlists); err != nil {
+		return nil, fmt.Errorf("decode %s: %w", filepath.Base(path), err)
+	}
+
+	seen := make(map[string]bool, len(wishlists))
+	for i := range wishlists {
+		wl := &wishlists[i]
+		if wl.ID == "" {
+			return nil, fmt.Errorf("public wishlist %d: id is required", i)
+		}
+		if seen[wl.ID] {
+			return nil, fmt.Errorf("public wishlist %q: duplicate id", wl.ID)
+		}
+		seen[wl.ID] = true
+
+		if wl.Items == nil {
+			wl.Items = []models.WishlistItem{}
+		}
+		for j := range wl.Items {
+			if wl.Items[j].UniqueName == "" {
+				return nil, fmt.Errorf("public wishlist %q: item %d: uniqueName is required", wl.ID, j)
+			}
+			if wl.Items[j].Quantity <= 0 {
+				wl.Items[j].Quantity = 1
+			}
+			if wl.Items[j].AddedAt.IsZero() {
+				wl.Items[j].AddedAt = info.ModTime()
+			}
+		}
+	}
+	return wishlists, nil
+}
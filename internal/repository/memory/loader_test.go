@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCollectionNameForFile(t *testing.T) {
+	tests := map[string]string{
+		"Warframes.json":       "warframes",
+		"Arch-Gun.json":        "arch_gun",
+		"SentinelWeapons.json": "sentinelweapons",
+	}
+	for file, expected := range tests {
+		if got := CollectionNameForFile(file); got != expected {
+			t.Errorf("%s: expected '%s', got '%s'", file, expected, got)
+		}
+	}
+}
+
+func TestLoadItemsFromDir(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"Arch-Gun.json": `[{"uniqueName":"/Lotus/Weapons/Imperator","name":"Imperator"}]`,
+		"All.json":      `[{"uniqueName":"/Lotus/Skipped","name":"Skipped"}]`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write fixture: %v", err)
+		}
+	}
+
+	repo := NewItemRepository()
+	count, err := LoadItemsFromDir(repo, dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("expected 1 item loaded, got %d", count)
+	}
+
+	item, _ := repo.FindByUniqueName(context.Background(), "/Lotus/Weapons/Imperator")
+	if item == nil || item.Collection != "arch_gun" {
+		t.Errorf("expected item in arch_gun collection, got %+v", item)
+	}
+	if skipped, _ := repo.FindByUniqueName(context.Background(), "/Lotus/Skipped"); skipped != nil {
+		t.Error("expected All.json to be skipped")
+	}
+}
+
+func TestLoadItemsFromDir_Empty(t *testing.T) {
+	if _, err := LoadItemsFromDir(NewItemRepository(), t.TempDir()); err == nil {
+		t.Error("expected error for directory without data files")
+	}
+}
+
+func TestLoadPublicWishlists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wishlists.json")
+	content := `[
+		{"id": "primes", "name": "Prime targets", "items": [{"uniqueName": "/Lotus/A", "quantity": 2}, {"uniqueName": "/Lotus/B"}]},
+		{"id": "empty", "name": "Nothing yet"}
+	]`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+
+	wishlists, err := LoadPublicWishlists(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(wishlists) != 2 {
+		t.Fatalf("expected 2 wishlists, got %d", len(wishlists))
+	}
+	if items := wishlists[0].Items; len(items) != 2 || items[0].Quantity != 2 || items[1].Quantity != 1 {
+		t.Errorf("expected quantities 2 and default 1, got %+v", items)
+	}
+	if wishlists[0].Items[0].AddedAt.IsZero() {
+		t.Error("expected missing addedAt to default to the file modification time")
+	}
+	if wishlists[1].Items == nil {
+		t.Error("expected missing items to decode as an empty list")
+	}
+}
+
+func TestLoadPublicWishlists_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"malformed":    `[{"id": `,
+		"missing id":   `[{"name": "No ID"}]`,
+		"duplicate id": `[{"id": "a"}, {"id": "a"}]`,
+		"missing item": `[{"id": "a", "items": [{"quantity": 1}]}]`,
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "wishlists.json")
+			if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+				t.Fatalf("failed to write fixture: %v", err)
+			}
+			if _, err := LoadPublicWishlists(path); err == nil {
+				t.Error("expected error but got none")
+			}
+		})
+	}
+
+	if _, err := LoadPublicWishlists(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
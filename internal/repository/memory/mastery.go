@@ -0,0 +1,73 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+type MasteryRepository struct {
+	mu      sync.Mutex
+	entries map[string]map[string]models.MasteryEntry
+}
+
+func NewMasteryRepository() *MasteryRepository {
+	return &MasteryRepository{entries: make(map[string]map[string]models.MasteryEntry)}
+}
+
+func (r *MasteryRepository) ListByUser(ctx context.Context, userID string) ([]models.MasteryEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entries := make([]models.MasteryEntry, 0, len(r.entries[userID]))
+	for _, entry := range r.entries[userID] {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].UniqueName < entries[j].UniqueName
+	})
+	return entries, nil
+}
+
+func (r *MasteryRepository) Get(ctx context.Context, userID, uniqueName string) (*models.MasteryEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.entries[userID][uniqueName]
+	if !ok {
+		return nil, nil
+	}
+	return &entry, nil
+}
+
+func (r *MasteryRepository) SetStatuses(ctx context.Context, userID string, statuses map[string]string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entries, ok := r.entries[userID]
+	if !ok {
+		entries = make(map[string]models.MasteryEntry)
+		r.entries[userID] = entries
+	}
+
+	now := time.Now()
+	for uniqueName, status := range statuses {
+		if status == "" {
+			delete(entries, uniqueName)
+			continue
+		}
+		entries[uniqueName] = models.MasteryEntry{UserID: userID, UniqueName: uniqueName, Status: status, UpdatedAt: now}
+	}
+	return nil
+}
+
+func (r *MasteryRepository) ClearAll(ctx context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.entries, userID)
+	return nil
+}
@@ -0,0 +1,213 @@
+package memory
+
+import (
+	"context"
+	"maps"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type NotificationRepository struct {
+	mu         sync.Mutex
+	channels   map[primitive.ObjectID]models.NotificationChannel
+	deliveries map[primitive.ObjectID]models.NotificationDelivery
+	milestones map[string]models.MilestoneState
+}
+
+func NewNotificationRepository() *NotificationRepository {
+	return &NotificationRepository{
+		channels:   make(map[primitive.ObjectID]models.NotificationChannel),
+		deliveries: make(map[primitive.ObjectID]models.NotificationDelivery),
+		milestones: make(map[string]models.MilestoneState),
+	}
+}
+
+func (r *NotificationRepository) CreateChannel(ctx context.Context, channel *models.NotificationChannel) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	channel.ID = primitive.NewObjectID()
+	stored := *channel
+	stored.Events = append([]string(nil), channel.Events...)
+	r.channels[channel.ID] = stored
+	return nil
+}
+
+func (r *NotificationRepository) GetChannel(ctx context.Context, id primitive.ObjectID) (*models.NotificationChannel, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	channel, ok := r.channels[id]
+	if !ok {
+		return nil, nil
+	}
+	return &channel, nil
+}
+
+func (r *NotificationRepository) ListChannels(ctx context.Context, userID string) ([]models.NotificationChannel, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	channels := []models.NotificationChannel{}
+	for _, channel := range r.channels {
+		if channel.UserID == userID {
+			channels = append(channels, channel)
+		}
+	}
+	sort.Slice(channels, func(i, j int) bool {
+		if !channels[i].CreatedAt.Equal(channels[j].CreatedAt) {
+			return channels[i].CreatedAt.Before(channels[j].CreatedAt)
+		}
+		return channels[i].ID.Hex() < channels[j].ID.Hex()
+	})
+	return channels, nil
+}
+
+func (r *NotificationRepository) DeleteChannel(ctx context.Context, userID string, id primitive.ObjectID) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	channel, ok := r.channels[id]
+	if !ok || channel.UserID != userID {
+		return false, nil
+	}
+	delete(r.channels, id)
+	return true, nil
+}
+
+func (r *NotificationRepository) ListSubscribedUsers(ctx context.Context) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	seen := make(map[string]bool)
+	userIDs := []string{}
+	for _, channel := range r.channels {
+		if !seen[channel.UserID] {
+			seen[channel.UserID] = true
+			userIDs = append(userIDs, channel.UserID)
+		}
+	}
+	sort.Strings(userIDs)
+	return userIDs, nil
+}
+
+func (r *NotificationRepository) EnqueueDelivery(ctx context.Context, delivery *models.NotificationDelivery) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.deliveries {
+		if existing.ChannelID == delivery.ChannelID && existing.Key == delivery.Key {
+			return false, nil
+		}
+	}
+	delivery.ID = primitive.NewObjectID()
+	r.deliveries[delivery.ID] = *delivery
+	return true, nil
+}
+
+func (r *NotificationRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.NotificationDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	due := []models.NotificationDelivery{}
+	for _, delivery := range r.deliveries {
+		if delivery.Status == models.DeliveryPending && !delivery.NextAttemptAt.After(now) {
+			due = append(due, delivery)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		if !due[i].NextAttemptAt.Equal(due[j].NextAttemptAt) {
+			return due[i].NextAttemptAt.Before(due[j].NextAttemptAt)
+		}
+		return due[i].ID.Hex() < due[j].ID.Hex()
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+	for i := range due {
+		due[i].NextAttemptAt = now.Add(lease)
+		r.deliveries[due[i].ID] = due[i]
+	}
+	return due, nil
+}
+
+func (r *NotificationRepository) UpdateDelivery(ctx context.Context, delivery *models.NotificationDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.deliveries[delivery.ID]
+	if !ok {
+		return nil
+	}
+	stored.Status = delivery.Status
+	stored.Attempts = delivery.Attempts
+	stored.ResponseStatus = delivery.ResponseStatus
+	stored.LastError = delivery.LastError
+	stored.NextAttemptAt = delivery.NextAttemptAt
+	stored.DeliveredAt = nil
+	if delivery.DeliveredAt != nil {
+		deliveredAt := *delivery.DeliveredAt
+		stored.DeliveredAt = &deliveredAt
+	}
+	r.deliveries[delivery.ID] = stored
+	return nil
+}
+
+func (r *NotificationRepository) ListDeliveries(ctx context.Context, userID string, limit int) ([]models.NotificationDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	deliveries := []models.NotificationDelivery{}
+	for _, delivery := range r.deliveries {
+		if delivery.UserID == userID {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	sort.Slice(deliveries, func(i, j int) bool {
+		if !deliveries[i].CreatedAt.Equal(deliveries[j].CreatedAt) {
+			return deliveries[i].CreatedAt.After(deliveries[j].CreatedAt)
+		}
+		return deliveries[i].ID.Hex() > deliveries[j].ID.Hex()
+	})
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, nil
+}
+
+func (r *NotificationRepository) GetMilestones(ctx context.Context, userID string) (*models.MilestoneState, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	state, ok := r.milestones[userID]
+	if !ok {
+		return nil, nil
+	}
+	state.Reached = maps.Clone(state.Reached)
+	return &state, nil
+}
+
+func (r *NotificationRepository) SaveMilestones(ctx context.Context, state *models.MilestoneState) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *state
+	stored.Reached = maps.Clone(state.Reached)
+	if stored.Reached == nil {
+		stored.Reached = map[string]time.Time{}
+	}
+	r.milestones[state.UserID] = stored
+	return nil
+}
+
+func (r *NotificationRepository) DeleteMilestones(ctx context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.milestones, userID)
+	return nil
+}
@@ -0,0 +1,223 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type OwnedBlueprintsRepository struct {
+	mu    sync.Mutex
+	owned map[string]*models.OwnedBlueprints
+}
+
+func NewOwnedBlueprintsRepository() *OwnedBlueprintsRepository {
+	return &OwnedBlueprintsRepository{owned: make(map[string]*models.OwnedBlueprints)}
+}
+
+// Seed stores ownedBlueprints exactly as given, including its schema version.
+func (r *OwnedBlueprintsRepository) Seed(ownedBlueprints models.OwnedBlueprints) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if ownedBlueprints.ID.IsZero() {
+		ownedBlueprints.ID = primitive.NewObjectID()
+	}
+	stored := copyOwnedBlueprints(ownedBlueprints)
+	r.owned[ownedBlueprints.UserID] = &stored
+}
+
+func (r *OwnedBlueprintsRepository) GetByUserID(ctx context.Context, userID string) (*models.OwnedBlueprints, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.owned[userID]
+	if !ok {
+		return nil, nil
+	}
+
+	repository.MigrateOwnedBlueprints(stored)
+
+	ownedBlueprints := copyOwnedBlueprints(*stored)
+	return &ownedBlueprints, nil
+}
+
+func (r *OwnedBlueprintsRepository) Create(ctx context.Context, ownedBlueprints *models.OwnedBlueprints) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ownedBlueprints.CreatedAt = time.Now()
+	ownedBlueprints.UpdatedAt = time.Now()
+	ownedBlueprints.SchemaVersion = repository.CurrentOwnedBlueprintsSchemaVersion
+	if ownedBlueprints.Blueprints == nil {
+		ownedBlueprints.Blueprints = []models.OwnedBlueprint{}
+	}
+	if ownedBlueprints.Components == nil {
+		ownedBlueprints.Components = []models.OwnedComponent{}
+	}
+	ownedBlueprints.ID = primitive.NewObjectID()
+
+	if _, exists := r.owned[ownedBlueprints.UserID]; !exists {
+		stored := copyOwnedBlueprints(*ownedBlueprints)
+		r.owned[ownedBlueprints.UserID] = &stored
+	}
+	return nil
+}
+
+func (r *OwnedBlueprintsRepository) AddBlueprint(ctx context.Context, userID string, blueprint models.OwnedBlueprint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.owned[userID]
+	if !ok {
+		return nil
+	}
+
+	stored.Blueprints = append(stored.Blueprints, blueprint)
+	stored.UpdatedAt = time.Now()
+	return nil
+}
+
+func (r *OwnedBlueprintsRepository) RemoveBlueprint(ctx context.Context, userID, uniqueName string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.owned[userID]
+	if !ok {
+		return nil
+	}
+
+	blueprints := stored.Blueprints[:0]
+	for _, bp := range stored.Blueprints {
+		if bp.UniqueName != uniqueName {
+			blueprints = append(blueprints, bp)
+		}
+	}
+	stored.Blueprints = blueprints
+	stored.UpdatedAt = time.Now()
+	return nil
+}
+
+func (r *OwnedBlueprintsRepository) BulkAddBlueprints(ctx context.Context, userID string, blueprints []models.OwnedBlueprint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.owned[userID]
+	if !ok {
+		stored = &models.OwnedBlueprints{
+			ID:            primitive.NewObjectID(),
+			UserID:        userID,
+			Blueprints:    []models.OwnedBlueprint{},
+			Components:    []models.OwnedComponent{},
+			SchemaVersion: repository.CurrentOwnedBlueprintsSchemaVersion,
+			CreatedAt:     time.Now(),
+		}
+		r.owned[userID] = stored
+	}
+
+	stored.Blueprints = append(stored.Blueprints, blueprints...)
+	stored.UpdatedAt = time.Now()
+	return nil
+}
+
+func (r *OwnedBlueprintsRepository) ClearAll(ctx context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.owned[userID]
+	if !ok {
+		return nil
+	}
+
+	stored.Blueprints = []models.OwnedBlueprint{}
+	stored.UpdatedAt = time.Now()
+	return nil
+}
+
+func (r *OwnedBlueprintsRepository) SetBlueprint(ctx context.Context, userID string, blueprint models.OwnedBlueprint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.owned[userID]
+	if !ok {
+		return nil
+	}
+
+	stored.UpdatedAt = time.Now()
+	for i := range stored.Blueprints {
+		if stored.Blueprints[i].UniqueName == blueprint.UniqueName {
+			stored.Blueprints[i].Quantity = blueprint.Quantity
+			return nil
+		}
+	}
+	stored.Blueprints = append(stored.Blueprints, blueprint)
+	return nil
+}
+
+func (r *OwnedBlueprintsRepository) SetComponent(ctx context.Context, userID string, component models.OwnedComponent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.owned[userID]
+	if !ok {
+		return nil
+	}
+
+	stored.UpdatedAt = time.Now()
+	for i := range stored.Components {
+		if stored.Components[i].UniqueName == component.UniqueName {
+			stored.Components[i].Count = component.Count
+			return nil
+		}
+	}
+	stored.Components = append(stored.Components, component)
+	return nil
+}
+
+func (r *OwnedBlueprintsRepository) RemoveComponent(ctx context.Context, userID, uniqueName string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.owned[userID]
+	if !ok {
+		return nil
+	}
+
+	components := stored.Components[:0]
+	for _, component := range stored.Components {
+		if component.UniqueName != uniqueName {
+			components = append(components, component)
+		}
+	}
+	stored.Components = components
+	stored.UpdatedAt = time.Now()
+	return nil
+}
+
+func (r *OwnedBlueprintsRepository) ClearComponents(ctx context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.owned[userID]
+	if !ok {
+		return nil
+	}
+
+	stored.Components = []models.OwnedComponent{}
+	stored.UpdatedAt = time.Now()
+	return nil
+}
+
+func copyOwnedBlueprints(ownedBlueprints models.OwnedBlueprints) models.OwnedBlueprints {
+	if ownedBlueprints.Blueprints != nil {
+		ownedBlueprints.Blueprints = append([]models.OwnedBlueprint{}, ownedBlueprints.Blueprints...)
+	}
+	if ownedBlueprints.Components != nil {
+		ownedBlueprints.Components = append([]models.OwnedComponent{}, ownedBlueprints.Components...)
+	}
+	return ownedBlueprints
+}
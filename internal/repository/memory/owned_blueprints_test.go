@@ -0,0 +1,50 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+func TestOwnedBlueprintsRepository_BulkAddUpserts(t *testing.T) {
+	ctx := context.Background()
+	repo := NewOwnedBlueprintsRepository()
+
+	err := repo.BulkAddBlueprints(ctx, "user-123", []models.OwnedBlueprint{{UniqueName: "/Lotus/A"}, {UniqueName: "/Lotus/B"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := repo.GetByUserID(ctx, "user-123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got == nil || len(got.Blueprints) != 2 {
+		t.Fatalf("expected 2 blueprints, got %+v", got)
+	}
+
+	repo.RemoveBlueprint(ctx, "user-123", "/Lotus/A")
+	got, _ = repo.GetByUserID(ctx, "user-123")
+	if len(got.Blueprints) != 1 || got.Blueprints[0].UniqueName != "/Lotus/B" {
+		t.Errorf("unexpected blueprints after remove: %+v", got.Blueprints)
+	}
+
+	repo.ClearAll(ctx, "user-123")
+	got, _ = repo.GetByUserID(ctx, "user-123")
+	if got == nil || len(got.Blueprints) != 0 {
+		t.Errorf("expected empty blueprints after clear, got %+v", got)
+	}
+}
+
+func TestOwnedBlueprintsRepository_AddBlueprintMissingDocument(t *testing.T) {
+	ctx := context.Background()
+	repo := NewOwnedBlueprintsRepository()
+
+	if err := repo.AddBlueprint(ctx, "user-123", models.OwnedBlueprint{UniqueName: "/Lotus/A"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, _ := repo.GetByUserID(ctx, "user-123"); got != nil {
+		t.Errorf("expected no document to be created, got %+v", got)
+	}
+}
@@ -0,0 +1,62 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+type OwnedMaterialsRepository struct {
+	mu    sync.Mutex
+	owned map[string]map[string]models.OwnedMaterial
+}
+
+func NewOwnedMaterialsRepository() *OwnedMaterialsRepository {
+	return &OwnedMaterialsRepository{owned: make(map[string]map[string]models.OwnedMaterial)}
+}
+
+func (r *OwnedMaterialsRepository) GetByUserID(ctx context.Context, userID string) ([]models.OwnedMaterial, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	materials := make([]models.OwnedMaterial, 0, len(r.owned[userID]))
+	for _, material := range r.owned[userID] {
+		materials = append(materials, material)
+	}
+	sort.Slice(materials, func(i, j int) bool {
+		return materials[i].UniqueName < materials[j].UniqueName
+	})
+	return materials, nil
+}
+
+func (r *OwnedMaterialsRepository) SetCounts(ctx context.Context, userID string, counts map[string]int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	owned, ok := r.owned[userID]
+	if !ok {
+		owned = make(map[string]models.OwnedMaterial)
+		r.owned[userID] = owned
+	}
+
+	now := time.Now()
+	for uniqueName, count := range counts {
+		if count <= 0 {
+			delete(owned, uniqueName)
+			continue
+		}
+		owned[uniqueName] = models.OwnedMaterial{UserID: userID, UniqueName: uniqueName, Count: count, UpdatedAt: now}
+	}
+	return nil
+}
+
+func (r *OwnedMaterialsRepository) ClearAll(ctx context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.owned, userID)
+	return nil
+}
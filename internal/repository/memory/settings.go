@@ -0,0 +1,61 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type SettingsRepository struct {
+	mu       sync.Mutex
+	settings map[string]*models.UserSettings
+}
+
+func NewSettingsRepository() *SettingsRepository {
+	return &SettingsRepository{settings: make(map[string]*models.UserSettings)}
+}
+
+func (r *SettingsRepository) GetByUserID(ctx context.Context, userID string) (*models.UserSettings, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.settings[userID]
+	if !ok {
+		return nil, nil
+	}
+
+	settings := *stored
+	settings.DefaultQuantities = slices.Clone(stored.DefaultQuantities)
+	return &settings, nil
+}
+
+func (r *SettingsRepository) Upsert(ctx context.Context, settings *models.UserSettings) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	settings.UpdatedAt = time.Now()
+
+	stored, ok := r.settings[settings.UserID]
+	if !ok {
+		stored = &models.UserSettings{
+			ID:        primitive.NewObjectID(),
+			UserID:    settings.UserID,
+			CreatedAt: time.Now(),
+		}
+		r.settings[settings.UserID] = stored
+	}
+
+	stored.TimeZone = settings.TimeZone
+	stored.DefaultQuantities = slices.Clone(settings.DefaultQuantities)
+	stored.PublicWishlist = settings.PublicWishlist
+	stored.MutedMilestones = slices.Clone(settings.MutedMilestones)
+	stored.MaterialsRounding = settings.MaterialsRounding
+	stored.SkipStarterWishlist = settings.SkipStarterWishlist
+	stored.DigestTime = settings.DigestTime
+	stored.UpdatedAt = settings.UpdatedAt
+	return nil
+}
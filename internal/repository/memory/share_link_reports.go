@@ -0,0 +1,101 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ShareLinkReportRepository struct {
+	mu      sync.Mutex
+	reports []models.ShareLinkReport
+}
+
+func NewShareLinkReportRepository() *ShareLinkReportRepository {
+	return &ShareLinkReportRepository{}
+}
+
+// open returns the index of token's open report, or -1. Callers hold mu.
+func (r *ShareLinkReportRepository) open(token string) int {
+	for i, report := range r.reports {
+		if report.Token == token && report.Status == models.ReportStatusOpen {
+			return i
+		}
+	}
+	return -1
+}
+
+func (r *ShareLinkReportRepository) Report(ctx context.Context, token, ownerID, reason string, at time.Time) (*models.ShareLinkReport, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.open(token)
+	if i < 0 {
+		r.reports = append(r.reports, models.ShareLinkReport{
+			ID:        primitive.NewObjectID(),
+			Token:     token,
+			OwnerID:   ownerID,
+			Reasons:   []string{},
+			Status:    models.ReportStatusOpen,
+			CreatedAt: at,
+		})
+		i = len(r.reports) - 1
+	}
+
+	report := &r.reports[i]
+	report.Count++
+	report.LastReportedAt = at
+	found := false
+	for _, existing := range report.Reasons {
+		found = found || existing == reason
+	}
+	if !found {
+		report.Reasons = append(report.Reasons, reason)
+	}
+	return copyShareLinkReport(*report), nil
+}
+
+func (r *ShareLinkReportRepository) List(ctx context.Context, status string, limit int) ([]models.ShareLinkReport, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reports := []models.ShareLinkReport{}
+	for _, report := range r.reports {
+		if status == "" || report.Status == status {
+			reports = append(reports, *copyShareLinkReport(report))
+		}
+	}
+	sort.SliceStable(reports, func(i, j int) bool {
+		return reports[i].CreatedAt.Before(reports[j].CreatedAt)
+	})
+	if len(reports) > limit {
+		reports = reports[:limit]
+	}
+	return reports, nil
+}
+
+func (r *ShareLinkReportRepository) Resolve(ctx context.Context, token, status string, at time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.open(token)
+	if i < 0 {
+		return false, nil
+	}
+	r.reports[i].Status = status
+	r.reports[i].ResolvedAt = &at
+	return true, nil
+}
+
+func copyShareLinkReport(report models.ShareLinkReport) *models.ShareLinkReport {
+	report.Reasons = append([]string{}, report.Reasons...)
+	if report.ResolvedAt != nil {
+		resolvedAt := *report.ResolvedAt
+		report.ResolvedAt = &resolvedAt
+	}
+	return &report
+}
@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ShareLinkRepository struct {
+	mu    sync.Mutex
+	links map[string]models.ShareLink // by token
+}
+
+func NewShareLinkRepository() *ShareLinkRepository {
+	return &ShareLinkRepository{links: make(map[string]models.ShareLink)}
+}
+
+func (r *ShareLinkRepository) Create(ctx context.Context, link *models.ShareLink) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	link.ID = primitive.NewObjectID()
+	r.links[link.Token] = *link
+	return nil
+}
+
+func (r *ShareLinkRepository) GetByToken(ctx context.Context, token string) (*models.ShareLink, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	link, ok := r.links[token]
+	if !ok {
+		return nil, nil
+	}
+	return &link, nil
+}
+
+func (r *ShareLinkRepository) ListByUser(ctx context.Context, userID string) ([]models.ShareLink, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	links := []models.ShareLink{}
+	for _, link := range r.links {
+		if link.UserID == userID {
+			links = append(links, link)
+		}
+	}
+	sort.Slice(links, func(i, j int) bool {
+		if !links[i].CreatedAt.Equal(links[j].CreatedAt) {
+			return links[i].CreatedAt.Before(links[j].CreatedAt)
+		}
+		return links[i].ID.Hex() < links[j].ID.Hex()
+	})
+	return links, nil
+}
+
+func (r *ShareLinkRepository) Delete(ctx context.Context, userID string, id primitive.ObjectID) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for token, link := range r.links {
+		if link.ID == id && link.UserID == userID {
+			delete(r.links, token)
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,43 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+type SyncStatusRepository struct {
+	mu       sync.Mutex
+	statuses map[string]models.SyncStatus
+}
+
+func NewSyncStatusRepository() *SyncStatusRepository {
+	return &SyncStatusRepository{statuses: make(map[string]models.SyncStatus)}
+}
+
+func (r *SyncStatusRepository) Get(ctx context.Context, name string) (*models.SyncStatus, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	status, ok := r.statuses[name]
+	if !ok {
+		return nil, nil
+	}
+	status.FailedCollections = slices.Clone(status.FailedCollections)
+	if status.FailedCollections == nil {
+		status.FailedCollections = []string{}
+	}
+	return &status, nil
+}
+
+func (r *SyncStatusRepository) Upsert(ctx context.Context, status *models.SyncStatus) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *status
+	stored.FailedCollections = slices.Clone(status.FailedCollections)
+	r.statuses[status.Name] = stored
+	return nil
+}
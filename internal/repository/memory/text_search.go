@@ -0,0 +1,112 @@
+package memory
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+// Field weights of the Mongo "item_search" text index.
+const (
+	nameWeight        = 10
+	descriptionWeight = 1
+)
+
+// textQuery is a parsed $text search string: whitespace separated words,
+// -words that exclude items and "quoted phrases" that must appear as
+// written. The words of a phrase also count as words.
+type textQuery struct {
+	terms   []string
+	negated []string
+	phrases []string
+}
+
+func parseTextQuery(query string) textQuery {
+	var q textQuery
+	for i, part := range strings.Split(query, `"`) {
+		if i%2 == 1 {
+			if phrase := strings.ToLower(strings.TrimSpace(part)); phrase != "" {
+				q.phrases = append(q.phrases, phrase)
+			}
+			q.terms = appendUnique(q.terms, tokenize(part)...)
+			continue
+		}
+		for _, field := range strings.Fields(part) {
+			if negated, ok := strings.CutPrefix(field, "-"); ok {
+				q.negated = appendUnique(q.negated, tokenize(negated)...)
+				continue
+			}
+			q.terms = appendUnique(q.terms, tokenize(field)...)
+		}
+	}
+	return q
+}
+
+// score reports whether item matches the query and its relevance, following
+// Mongo's text score: per field and matched word, weight * frequency *
+// (0.5 * occurrences / words in field + 0.5), where repeated occurrences add
+// less and less to the frequency.
+func (q textQuery) score(item models.Item) (float64, bool) {
+	if len(q.terms) == 0 {
+		return 0, false
+	}
+	name, description := tokenize(item.Name), tokenize(item.Description)
+	for _, word := range q.negated {
+		if containsWord(name, word) || containsWord(description, word) {
+			return 0, false
+		}
+	}
+	for _, phrase := range q.phrases {
+		if !strings.Contains(strings.ToLower(item.Name), phrase) && !strings.Contains(strings.ToLower(item.Description), phrase) {
+			return 0, false
+		}
+	}
+
+	score := fieldScore(name, q.terms, nameWeight) + fieldScore(description, q.terms, descriptionWeight)
+	return score, score > 0
+}
+
+func fieldScore(words, terms []string, weight float64) float64 {
+	score := 0.0
+	for _, term := range terms {
+		count, freq, exp := 0, 0.0, 1.0
+		for _, word := range words {
+			if word == term {
+				count++
+				freq += 1 / exp
+				exp *= 2
+			}
+		}
+		if count > 0 {
+			score += weight * freq * (0.5*float64(count)/float64(len(words)) + 0.5)
+		}
+	}
+	return score
+}
+
+// tokenize splits text into lowercased words on anything but letters and
+// digits, like the text index's tokenizer.
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func containsWord(words []string, word string) bool {
+	for _, w := range words {
+		if w == word {
+			return true
+		}
+	}
+	return false
+}
+
+func appendUnique(list []string, values ...string) []string {
+	for _, v := range values {
+		if !containsWord(list, v) {
+			list = append(list, v)
+		}
+	}
+	return list
+}
@@ -0,0 +1,60 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+type UserTraceRepository struct {
+	mu     sync.Mutex
+	traces map[string]models.UserTrace
+}
+
+func NewUserTraceRepository() *UserTraceRepository {
+	return &UserTraceRepository{traces: make(map[string]models.UserTrace)}
+}
+
+func (r *UserTraceRepository) GetByUserID(ctx context.Context, userID string) (*models.UserTrace, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	trace, ok := r.traces[userID]
+	if !ok {
+		return nil, nil
+	}
+	return &trace, nil
+}
+
+func (r *UserTraceRepository) Upsert(ctx context.Context, trace *models.UserTrace) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.traces[trace.UserID] = *trace
+	return nil
+}
+
+func (r *UserTraceRepository) Delete(ctx context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.traces, userID)
+	return nil
+}
+
+func (r *UserTraceRepository) ListActive(ctx context.Context, now time.Time) ([]models.UserTrace, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	traces := []models.UserTrace{}
+	for _, trace := range r.traces {
+		if trace.Until.After(now) {
+			traces = append(traces, trace)
+		}
+	}
+	sort.Slice(traces, func(i, j int) bool { return traces[i].UserID < traces[j].UserID })
+	return traces, nil
+}
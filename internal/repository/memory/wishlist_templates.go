@@ -0,0 +1,80 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type WishlistTemplateRepository struct {
+	mu        sync.Mutex
+	templates map[primitive.ObjectID]models.WishlistTemplate
+}
+
+func NewWishlistTemplateRepository() *WishlistTemplateRepository {
+	return &WishlistTemplateRepository{templates: make(map[primitive.ObjectID]models.WishlistTemplate)}
+}
+
+func (r *WishlistTemplateRepository) ListByUser(ctx context.Context, userID string) ([]models.WishlistTemplate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	templates := []models.WishlistTemplate{}
+	for _, template := range r.templates {
+		if template.UserID == userID {
+			templates = append(templates, copyWishlistTemplate(template))
+		}
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		if !templates[i].CreatedAt.Equal(templates[j].CreatedAt) {
+			return templates[i].CreatedAt.Before(templates[j].CreatedAt)
+		}
+		return templates[i].ID.Hex() < templates[j].ID.Hex()
+	})
+	return templates, nil
+}
+
+func (r *WishlistTemplateRepository) Get(ctx context.Context, userID string, id primitive.ObjectID) (*models.WishlistTemplate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	template, ok := r.templates[id]
+	if !ok || template.UserID != userID {
+		return nil, nil
+	}
+	template = copyWishlistTemplate(template)
+	return &template, nil
+}
+
+func (r *WishlistTemplateRepository) Create(ctx context.Context, template *models.WishlistTemplate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	template.ID = primitive.NewObjectID()
+	r.templates[template.ID] = copyWishlistTemplate(*template)
+	return nil
+}
+
+func (r *WishlistTemplateRepository) Delete(ctx context.Context, userID string, id primitive.ObjectID) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	template, ok := r.templates[id]
+	if !ok || template.UserID != userID {
+		return false, nil
+	}
+	delete(r.templates, id)
+	return true, nil
+}
+
+// copyWishlistTemplate copies template's slices, so callers can't change
+// what is stored.
+func copyWishlistTemplate(template models.WishlistTemplate) models.WishlistTemplate {
+	template.Variables = slices.Clone(template.Variables)
+	template.Items = slices.Clone(template.Items)
+	return template
+}
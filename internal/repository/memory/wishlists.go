@@ -0,0 +1,291 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type WishlistRepository struct {
+	mu        sync.Mutex
+	wishlists map[string]*models.Wishlist
+}
+
+func NewWishlistRepository() *WishlistRepository {
+	return &WishlistRepository{wishlists: make(map[string]*models.Wishlist)}
+}
+
+// Seed stores wishlist exactly as given, including its schema version, so
+// tests and demos can exercise migration of older documents.
+func (r *WishlistRepository) Seed(wishlist models.Wishlist) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if wishlist.ID.IsZero() {
+		wishlist.ID = primitive.NewObjectID()
+	}
+	stored := copyWishlist(wishlist)
+	r.wishlists[wishlist.UserID] = &stored
+}
+
+func (r *WishlistRepository) GetByUserID(ctx context.Context, userID string) (*models.Wishlist, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.wishlists[userID]
+	if !ok {
+		return nil, nil
+	}
+
+	repository.MigrateWishlist(stored)
+
+	wishlist := copyWishlist(*stored)
+	return &wishlist, nil
+}
+
+func (r *WishlistRepository) Create(ctx context.Context, wishlist *models.Wishlist) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	wishlist.CreatedAt = time.Now()
+	wishlist.UpdatedAt = time.Now()
+	wishlist.SchemaVersion = repository.CurrentWishlistSchemaVersion
+	if wishlist.Items == nil {
+		wishlist.Items = []models.WishlistItem{}
+	}
+	wishlist.ID = primitive.NewObjectID()
+
+	// Mongo allows a second insert for the same user but reads always return
+	// the first document, so keeping the first one here is equivalent.
+	if _, exists := r.wishlists[wishlist.UserID]; !exists {
+		stored := copyWishlist(*wishlist)
+		r.wishlists[wishlist.UserID] = &stored
+	}
+	return nil
+}
+
+func (r *WishlistRepository) CreateIfAbsent(ctx context.Context, wishlist *models.Wishlist) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.wishlists[wishlist.UserID]; exists {
+		return false, nil
+	}
+
+	wishlist.CreatedAt = time.Now()
+	wishlist.UpdatedAt = wishlist.CreatedAt
+	wishlist.SchemaVersion = repository.CurrentWishlistSchemaVersion
+	if wishlist.Items == nil {
+		wishlist.Items = []models.WishlistItem{}
+	}
+	wishlist.ID = primitive.NewObjectID()
+
+	stored := copyWishlist(*wishlist)
+	r.wishlists[wishlist.UserID] = &stored
+	return true, nil
+}
+
+func (r *WishlistRepository) AddItem(ctx context.Context, userID string, item models.WishlistItem) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.wishlists[userID]
+	if !ok {
+		return nil
+	}
+
+	stored.Items = append(stored.Items, item)
+	stored.UpdatedAt = time.Now()
+	return nil
+}
+
+func (r *WishlistRepository) RemoveItem(ctx context.Context, userID, uniqueName string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.wishlists[userID]
+	if !ok {
+		return nil
+	}
+
+	items := stored.Items[:0]
+	for _, item := range stored.Items {
+		if item.UniqueName != uniqueName {
+			items = append(items, item)
+		}
+	}
+	stored.Items = items
+	stored.UpdatedAt = time.Now()
+	return nil
+}
+
+func (r *WishlistRepository) UpdateItemQuantity(ctx context.Context, userID, uniqueName string, quantity int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.wishlists[userID]
+	if !ok {
+		return nil
+	}
+
+	// Like the positional $ operator, only the first matching item is updated.
+	for i := range stored.Items {
+		if stored.Items[i].UniqueName == uniqueName {
+			stored.Items[i].Quantity = quantity
+			stored.UpdatedAt = time.Now()
+			return nil
+		}
+	}
+	return nil
+}
+
+func (r *WishlistRepository) SetItemLinks(ctx context.Context, userID, uniqueName string, links []models.SourceLink) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.wishlists[userID]
+	if !ok {
+		return nil
+	}
+
+	for i := range stored.Items {
+		if stored.Items[i].UniqueName == uniqueName {
+			if len(links) == 0 {
+				stored.Items[i].Links = nil
+			} else {
+				stored.Items[i].Links = append([]models.SourceLink(nil), links...)
+			}
+			stored.UpdatedAt = time.Now()
+			return nil
+		}
+	}
+	return nil
+}
+
+func (r *WishlistRepository) SetItemRecipe(ctx context.Context, userID, uniqueName, recipeID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.wishlists[userID]
+	if !ok {
+		return nil
+	}
+
+	for i := range stored.Items {
+		if stored.Items[i].UniqueName == uniqueName {
+			stored.Items[i].RecipeID = recipeID
+			stored.UpdatedAt = time.Now()
+			return nil
+		}
+	}
+	return nil
+}
+
+func (r *WishlistRepository) SetItemProgress(ctx context.Context, userID, uniqueName string, progress []models.ComponentProgress) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.wishlists[userID]
+	if !ok {
+		return nil
+	}
+
+	for i := range stored.Items {
+		if stored.Items[i].UniqueName == uniqueName {
+			if len(progress) == 0 {
+				stored.Items[i].Progress = nil
+			} else {
+				stored.Items[i].Progress = append([]models.ComponentProgress(nil), progress...)
+			}
+			stored.UpdatedAt = time.Now()
+			return nil
+		}
+	}
+	return nil
+}
+
+func (r *WishlistRepository) Upsert(ctx context.Context, wishlist *models.Wishlist) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	wishlist.UpdatedAt = time.Now()
+	wishlist.SchemaVersion = repository.CurrentWishlistSchemaVersion
+
+	stored, ok := r.wishlists[wishlist.UserID]
+	if !ok {
+		stored = &models.Wishlist{
+			ID:        primitive.NewObjectID(),
+			UserID:    wishlist.UserID,
+			CreatedAt: time.Now(),
+		}
+		r.wishlists[wishlist.UserID] = stored
+	}
+
+	stored.Items = append([]models.WishlistItem(nil), wishlist.Items...)
+	stored.SchemaVersion = wishlist.SchemaVersion
+	stored.UpdatedAt = wishlist.UpdatedAt
+	return nil
+}
+
+func (r *WishlistRepository) TopWishlistedItems(ctx context.Context, limit int) ([]models.ItemPopularity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	counts := make(map[string]int)
+	for _, wishlist := range r.wishlists {
+		seen := make(map[string]bool)
+		for _, item := range wishlist.Items {
+			if !seen[item.UniqueName] {
+				seen[item.UniqueName] = true
+				counts[item.UniqueName]++
+			}
+		}
+	}
+
+	popularity := make([]models.ItemPopularity, 0, len(counts))
+	for uniqueName, count := range counts {
+		popularity = append(popularity, models.ItemPopularity{UniqueName: uniqueName, WishlistCount: count})
+	}
+	sort.Slice(popularity, func(i, j int) bool {
+		if popularity[i].WishlistCount != popularity[j].WishlistCount {
+			return popularity[i].WishlistCount > popularity[j].WishlistCount
+		}
+		return popularity[i].UniqueName < popularity[j].UniqueName
+	})
+
+	if limit > 0 && len(popularity) > limit {
+		popularity = popularity[:limit]
+	}
+	return popularity, nil
+}
+
+func (r *WishlistRepository) WishlistSizeStats(ctx context.Context) (*models.WishlistSizeStats, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats := &models.WishlistSizeStats{Wishlists: len(r.wishlists)}
+	for _, wishlist := range r.wishlists {
+		stats.TotalItems += len(wishlist.Items)
+	}
+	return stats, nil
+}
+
+func copyWishlist(wishlist models.Wishlist) models.Wishlist {
+	if wishlist.Items != nil {
+		wishlist.Items = append([]models.WishlistItem{}, wishlist.Items...)
+		for i := range wishlist.Items {
+			if wishlist.Items[i].Links != nil {
+				wishlist.Items[i].Links = append([]models.SourceLink(nil), wishlist.Items[i].Links...)
+			}
+			if wishlist.Items[i].Progress != nil {
+				wishlist.Items[i].Progress = append([]models.ComponentProgress(nil), wishlist.Items[i].Progress...)
+			}
+		}
+	}
+	return wishlist
+}
@@ -0,0 +1,101 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+)
+
+func TestWishlistRepository_GetByUserID_NotFound(t *testing.T) {
+	repo := NewWishlistRepository()
+
+	wishlist, err := repo.GetByUserID(context.Background(), "user-123")
+	if err != nil || wishlist != nil {
+		t.Errorf("expected nil, nil, got %v, %v", wishlist, err)
+	}
+}
+
+func TestWishlistRepository_ItemLifecycle(t *testing.T) {
+	ctx := context.Background()
+	repo := NewWishlistRepository()
+
+	// Updates against a missing document are no-ops, as with Mongo UpdateOne.
+	if err := repo.AddItem(ctx, "user-123", models.WishlistItem{UniqueName: "/Lotus/A", Quantity: 1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if w, _ := repo.GetByUserID(ctx, "user-123"); w != nil {
+		t.Fatal("expected AddItem on missing wishlist not to create one")
+	}
+
+	wishlist := &models.Wishlist{UserID: "user-123"}
+	if err := repo.Create(ctx, wishlist); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if wishlist.ID.IsZero() || wishlist.SchemaVersion != repository.CurrentWishlistSchemaVersion {
+		t.Errorf("expected Create to set ID and schema version, got %+v", wishlist)
+	}
+
+	repo.AddItem(ctx, "user-123", models.WishlistItem{UniqueName: "/Lotus/A", Quantity: 1})
+	repo.AddItem(ctx, "user-123", models.WishlistItem{UniqueName: "/Lotus/B", Quantity: 2})
+	repo.UpdateItemQuantity(ctx, "user-123", "/Lotus/B", 5)
+	repo.RemoveItem(ctx, "user-123", "/Lotus/A")
+
+	got, err := repo.GetByUserID(ctx, "user-123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got.Items) != 1 || got.Items[0].UniqueName != "/Lotus/B" || got.Items[0].Quantity != 5 {
+		t.Errorf("unexpected items: %+v", got.Items)
+	}
+
+	// Returned documents are copies.
+	got.Items[0].Quantity = 99
+	again, _ := repo.GetByUserID(ctx, "user-123")
+	if again.Items[0].Quantity != 5 {
+		t.Error("expected stored wishlist to be unaffected by caller mutation")
+	}
+}
+
+func TestWishlistRepository_Upsert(t *testing.T) {
+	ctx := context.Background()
+	repo := NewWishlistRepository()
+
+	wishlist := &models.Wishlist{UserID: "user-123", Items: []models.WishlistItem{{UniqueName: "/Lotus/A", Quantity: 1}}}
+	if err := repo.Upsert(ctx, wishlist); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, _ := repo.GetByUserID(ctx, "user-123")
+	if got == nil || len(got.Items) != 1 || got.CreatedAt.IsZero() {
+		t.Fatalf("expected upsert to create wishlist, got %+v", got)
+	}
+}
+
+func TestWishlistRepository_MigratesSeededDocuments(t *testing.T) {
+	ctx := context.Background()
+	repo := NewWishlistRepository()
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	repo.Seed(models.Wishlist{
+		UserID:    "user-123",
+		CreatedAt: created,
+		Items: []models.WishlistItem{
+			{UniqueName: "/Lotus/A", Quantity: 0},
+			{UniqueName: "", Quantity: 3},
+		},
+	})
+
+	got, err := repo.GetByUserID(ctx, "user-123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.SchemaVersion != repository.CurrentWishlistSchemaVersion {
+		t.Errorf("expected schema version %d, got %d", repository.CurrentWishlistSchemaVersion, got.SchemaVersion)
+	}
+	if len(got.Items) != 1 || got.Items[0].Quantity != 1 || !got.Items[0].AddedAt.Equal(created) {
+		t.Errorf("unexpected migrated items: %+v", got.Items)
+	}
+}
@@ -0,0 +1,104 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type WorkspaceContributionRepository struct {
+	mu     sync.Mutex
+	events []models.WorkspaceContributionEvent
+}
+
+func NewWorkspaceContributionRepository() *WorkspaceContributionRepository {
+	return &WorkspaceContributionRepository{}
+}
+
+func (r *WorkspaceContributionRepository) Create(ctx context.Context, event *models.WorkspaceContributionEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	event.ID = primitive.NewObjectID()
+	r.events = append(r.events, *event)
+	return nil
+}
+
+func (r *WorkspaceContributionRepository) ListByWorkspace(ctx context.Context, workspaceID primitive.ObjectID, limit int) ([]models.WorkspaceContributionEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	events := []models.WorkspaceContributionEvent{}
+	for _, event := range r.events {
+		if event.WorkspaceID == workspaceID {
+			events = append(events, event)
+		}
+	}
+	sort.Slice(events, func(i, j int) bool {
+		if !events[i].CreatedAt.Equal(events[j].CreatedAt) {
+			return events[i].CreatedAt.After(events[j].CreatedAt)
+		}
+		return events[i].ID.Hex() > events[j].ID.Hex()
+	})
+	if limit > 0 && len(events) > limit {
+		events = events[:limit]
+	}
+	return events, nil
+}
+
+func (r *WorkspaceContributionRepository) MaterialTotals(ctx context.Context, workspaceID primitive.ObjectID) ([]models.WorkspaceContributionTotal, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	type key struct{ uniqueName, userID string }
+	sums := make(map[key]int)
+	for _, event := range r.events {
+		if event.WorkspaceID == workspaceID && event.Kind == models.ContributionKindMaterial {
+			sums[key{event.UniqueName, event.UserID}] += event.Quantity
+		}
+	}
+
+	totals := []models.WorkspaceContributionTotal{}
+	for k, quantity := range sums {
+		if quantity != 0 {
+			totals = append(totals, models.WorkspaceContributionTotal{UniqueName: k.uniqueName, UserID: k.userID, Quantity: quantity})
+		}
+	}
+	sort.Slice(totals, func(i, j int) bool {
+		if totals[i].UniqueName != totals[j].UniqueName {
+			return totals[i].UniqueName < totals[j].UniqueName
+		}
+		return totals[i].UserID < totals[j].UserID
+	})
+	return totals, nil
+}
+
+func (r *WorkspaceContributionRepository) CountByUser(ctx context.Context, userID string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for _, event := range r.events {
+		if event.UserID == userID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *WorkspaceContributionRepository) DeleteByWorkspace(ctx context.Context, workspaceID primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kept := r.events[:0]
+	for _, event := range r.events {
+		if event.WorkspaceID != workspaceID {
+			kept = append(kept, event)
+		}
+	}
+	r.events = kept
+	return nil
+}
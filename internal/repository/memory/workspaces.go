@@ -0,0 +1,102 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type WorkspaceRepository struct {
+	mu         sync.Mutex
+	workspaces map[primitive.ObjectID]models.Workspace
+}
+
+func NewWorkspaceRepository() *WorkspaceRepository {
+	return &WorkspaceRepository{workspaces: make(map[primitive.ObjectID]models.Workspace)}
+}
+
+func (r *WorkspaceRepository) Create(ctx context.Context, workspace *models.Workspace) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	workspace.ID = primitive.NewObjectID()
+	r.workspaces[workspace.ID] = cloneWorkspace(*workspace)
+	return nil
+}
+
+func (r *WorkspaceRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*models.Workspace, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	workspace, ok := r.workspaces[id]
+	if !ok {
+		return nil, nil
+	}
+	workspace = cloneWorkspace(workspace)
+	return &workspace, nil
+}
+
+func (r *WorkspaceRepository) ListByMember(ctx context.Context, userID string) ([]models.Workspace, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	workspaces := []models.Workspace{}
+	for _, workspace := range r.workspaces {
+		if workspace.Member(userID) != nil {
+			workspaces = append(workspaces, cloneWorkspace(workspace))
+		}
+	}
+	sort.Slice(workspaces, func(i, j int) bool {
+		if workspaces[i].Name != workspaces[j].Name {
+			return workspaces[i].Name < workspaces[j].Name
+		}
+		return workspaces[i].ID.Hex() < workspaces[j].ID.Hex()
+	})
+	return workspaces, nil
+}
+
+func (r *WorkspaceRepository) Update(ctx context.Context, workspace *models.Workspace) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.workspaces[workspace.ID]
+	if !ok || existing.Version != workspace.Version {
+		return false, nil
+	}
+	existing.Name = workspace.Name
+	existing.Description = workspace.Description
+	existing.Members = workspace.Members
+	existing.Items = workspace.Items
+	existing.UpdatedAt = workspace.UpdatedAt
+	existing.Version++
+	r.workspaces[workspace.ID] = cloneWorkspace(existing)
+	workspace.Version = existing.Version
+	return true, nil
+}
+
+func (r *WorkspaceRepository) Delete(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.workspaces[id]; !ok {
+		return false, nil
+	}
+	delete(r.workspaces, id)
+	return true, nil
+}
+
+// cloneWorkspace copies workspace so callers never share its slices with the
+// store. Stored workspaces always have non-nil slices, as in MongoDB.
+func cloneWorkspace(workspace models.Workspace) models.Workspace {
+	workspace.Members = append([]models.WorkspaceMember{}, workspace.Members...)
+	items := make([]models.WorkspaceItem, len(workspace.Items))
+	for i, item := range workspace.Items {
+		item.Contributions = append([]models.WorkspaceContribution{}, item.Contributions...)
+		items[i] = item
+	}
+	workspace.Items = items
+	return workspace
+}
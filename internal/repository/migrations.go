@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+// Schema versions for user documents. Bump the constant and append a step to
+// the matching migration list whenever the stored shape changes. Documents
+// written before versioning existed have no schemaVersion field and decode as
+// version 0.
+const (
+	CurrentWishlistSchemaVersion        = 1
+	CurrentOwnedBlueprintsSchemaVersion = 3
+)
+
+// wishlistMigrations[i] upgrades a wishlist from version i to i+1.
+var wishlistMigrations = []func(w *models.Wishlist){
+	migrateWishlistV0ToV1,
+}
+
+// ownedBlueprintsMigrations[i] upgrades owned blueprints from version i to i+1.
+var ownedBlueprintsMigrations = []func(o *models.OwnedBlueprints){
+	migrateOwnedBlueprintsV0ToV1,
+	migrateOwnedBlueprintsV1ToV2,
+	migrateOwnedBlueprintsV2ToV3,
+}
+
+// MigrateWishlist upgrades w in place to CurrentWishlistSchemaVersion and
+// reports whether anything was changed.
+func MigrateWishlist(w *models.Wishlist) bool {
+	if w == nil || w.SchemaVersion >= CurrentWishlistSchemaVersion {
+		return false
+	}
+	for v := w.SchemaVersion; v < CurrentWishlistSchemaVersion; v++ {
+		wishlistMigrations[v](w)
+	}
+	w.SchemaVersion = CurrentWishlistSchemaVersion
+	return true
+}
+
+// MigrateOwnedBlueprints upgrades o in place to
+// CurrentOwnedBlueprintsSchemaVersion and reports whether anything was changed.
+func MigrateOwnedBlueprints(o *models.OwnedBlueprints) bool {
+	if o == nil || o.SchemaVersion >= CurrentOwnedBlueprintsSchemaVersion {
+		return false
+	}
+	for v := o.SchemaVersion; v < CurrentOwnedBlueprintsSchemaVersion; v++ {
+		ownedBlueprintsMigrations[v](o)
+	}
+	o.SchemaVersion = CurrentOwnedBlueprintsSchemaVersion
+	return true
+}
+
+// migrateWishlistV0ToV1 normalizes legacy wishlists: a non-nil item list,
+// quantities of at least 1, no duplicate uniqueNames (the highest quantity
+// wins) and an AddedAt timestamp on every item.
+func migrateWishlistV0ToV1(w *models.Wishlist) {
+	fallback := w.CreatedAt
+	if fallback.IsZero() {
+		fallback = time.Now()
+	}
+
+	items := make([]models.WishlistItem, 0, len(w.Items))
+	index := make(map[string]int)
+	for _, item := range w.Items {
+		if item.UniqueName == "" {
+			continue
+		}
+		if item.Quantity <= 0 {
+			item.Quantity = 1
+		}
+		if item.AddedAt.IsZero() {
+			item.AddedAt = fallback
+		}
+		if i, exists := index[item.UniqueName]; exists {
+			if item.Quantity > items[i].Quantity {
+				items[i].Quantity = item.Quantity
+			}
+			continue
+		}
+		index[item.UniqueName] = len(items)
+		items = append(items, item)
+	}
+	w.Items = items
+}
+
+// migrateOwnedBlueprintsV0ToV1 normalizes legacy owned blueprints: a non-nil
+// list without duplicates and an AddedAt timestamp on every entry.
+func migrateOwnedBlueprintsV0ToV1(o *models.OwnedBlueprints) {
+	fallback := o.CreatedAt
+	if fallback.IsZero() {
+		fallback = time.Now()
+	}
+
+	blueprints := make([]models.OwnedBlueprint, 0, len(o.Blueprints))
+	seen := make(map[string]bool)
+	for _, bp := range o.Blueprints {
+		if bp.UniqueName == "" || seen[bp.UniqueName] {
+			continue
+		}
+		if bp.AddedAt.IsZero() {
+			bp.AddedAt = fallback
+		}
+		seen[bp.UniqueName] = true
+		blueprints = append(blueprints, bp)
+	}
+	o.Blueprints = blueprints
+}
+
+// migrateOwnedBlueprintsV1ToV2 adds the owned components list, which documents
+// from before components could be owned lack.
+func migrateOwnedBlueprintsV1ToV2(o *models.OwnedBlueprints) {
+	if o.Components == nil {
+		o.Components = []models.OwnedComponent{}
+	}
+}
+
+// migrateOwnedBlueprintsV2ToV3 gives every blueprint a quantity: documents from
+// before consumable blueprints could be stocked only held reusable ones, each
+// owned once.
+func migrateOwnedBlueprintsV2ToV3(o *models.OwnedBlueprints) {
+	for i := range o.Blueprints {
+		if o.Blueprints[i].Quantity <= 0 {
+			o.Blueprints[i].Quantity = 1
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+func TestMigrateWishlist_LegacyDocument(t *testing.T) {
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	added := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	wishlist := &models.Wishlist{
+		UserID:    "user-123",
+		CreatedAt: created,
+		Items: []models.WishlistItem{
+			{UniqueName: "/Lotus/Ash", Quantity: 0},
+			{UniqueName: "/Lotus/Forma", Quantity: 2, AddedAt: added},
+			{UniqueName: "/Lotus/Forma", Quantity: 5},
+			{UniqueName: "", Quantity: 1},
+		},
+	}
+
+	if !MigrateWishlist(wishlist) {
+		t.Fatal("expected legacy wishlist to be migrated")
+	}
+
+	if wishlist.SchemaVersion != CurrentWishlistSchemaVersion {
+		t.Errorf("expected schema version %d, got %d", CurrentWishlistSchemaVersion, wishlist.SchemaVersion)
+	}
+	if len(wishlist.Items) != 2 {
+		t.Fatalf("expected 2 items after dedupe, got %d", len(wishlist.Items))
+	}
+	if wishlist.Items[0].Quantity != 1 {
+		t.Errorf("expected invalid quantity to be normalized to 1, got %d", wishlist.Items[0].Quantity)
+	}
+	if !wishlist.Items[0].AddedAt.Equal(created) {
+		t.Errorf("expected missing addedAt to fall back to createdAt, got %v", wishlist.Items[0].AddedAt)
+	}
+	if wishlist.Items[1].Quantity != 5 {
+		t.Errorf("expected duplicate items to keep the highest quantity, got %d", wishlist.Items[1].Quantity)
+	}
+	if !wishlist.Items[1].AddedAt.Equal(added) {
+		t.Errorf("expected first occurrence addedAt to be kept, got %v", wishlist.Items[1].AddedAt)
+	}
+}
+
+func TestMigrateWishlist_NilItems(t *testing.T) {
+	wishlist := &models.Wishlist{UserID: "user-123"}
+
+	MigrateWishlist(wishlist)
+
+	if wishlist.Items == nil {
+		t.Error("expected nil items to be replaced with an empty list")
+	}
+}
+
+func TestMigrateWishlist_CurrentVersionUntouched(t *testing.T) {
+	wishlist := &models.Wishlist{
+		SchemaVersion: CurrentWishlistSchemaVersion,
+		Items:         []models.WishlistItem{{UniqueName: "/Lotus/Ash", Quantity: 0}},
+	}
+
+	if MigrateWishlist(wishlist) {
+		t.Error("expected current wishlist not to be migrated")
+	}
+	if wishlist.Items[0].Quantity != 0 {
+		t.Error("expected current wishlist items to be left untouched")
+	}
+	if MigrateWishlist(nil) {
+		t.Error("expected nil wishlist not to be migrated")
+	}
+}
+
+func TestMigrateOwnedBlueprints_LegacyDocument(t *testing.T) {
+	owned := &models.OwnedBlueprints{
+		UserID: "user-123",
+		Blueprints: []models.OwnedBlueprint{
+			{UniqueName: "/Lotus/BP1"},
+			{UniqueName: "/Lotus/BP1"},
+			{UniqueName: ""},
+			{UniqueName: "/Lotus/BP2", AddedAt: time.Now()},
+		},
+	}
+
+	if !MigrateOwnedBlueprints(owned) {
+		t.Fatal("expected legacy owned blueprints to be migrated")
+	}
+
+	if owned.SchemaVersion != CurrentOwnedBlueprintsSchemaVersion {
+		t.Errorf("expected schema version %d, got %d", CurrentOwnedBlueprintsSchemaVersion, owned.SchemaVersion)
+	}
+	if len(owned.Blueprints) != 2 {
+		t.Fatalf("expected 2 blueprints after dedupe, got %d", len(owned.Blueprints))
+	}
+	for _, bp := range owned.Blueprints {
+		if bp.AddedAt.IsZero() {
+			t.Errorf("expected addedAt to be set for %s", bp.UniqueName)
+		}
+		if bp.Quantity != 1 {
+			t.Errorf("expected %s to be owned once, got quantity %d", bp.UniqueName, bp.Quantity)
+		}
+	}
+	if owned.Components == nil {
+		t.Error("expected a non-nil components list")
+	}
+
+	if MigrateOwnedBlueprints(owned) {
+		t.Error("expected migrated document not to be migrated again")
+	}
+}
+
+func TestMigrateOwnedBlueprints_V2KeepsQuantities(t *testing.T) {
+	owned := &models.OwnedBlueprints{
+		UserID:        "user-123",
+		SchemaVersion: 2,
+		Blueprints: []models.OwnedBlueprint{
+			{UniqueName: "/Lotus/Reusable", AddedAt: time.Now()},
+			{UniqueName: "/Lotus/Consumable", Quantity: 4, AddedAt: time.Now()},
+		},
+		Components: []models.OwnedComponent{},
+	}
+
+	if !MigrateOwnedBlueprints(owned) {
+		t.Fatal("expected version 2 owned blueprints to be migrated")
+	}
+	if owned.Blueprints[0].Quantity != 1 || owned.Blueprints[1].Quantity != 4 {
+		t.Errorf("expected quantities 1 and 4, got %+v", owned.Blueprints)
+	}
+}
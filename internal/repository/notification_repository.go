@@ -0,0 +1,316 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/database"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	notificationChannelsCollection   = "notification_channels"
+	notificationDeliveriesCollection = "notification_deliveries"
+	notificationMilestonesCollection = "notification_milestones"
+)
+
+type NotificationRepository struct {
+	db         *database.MongoDB
+	channels   *mongo.Collection
+	deliveries *mongo.Collection
+	milestones *mongo.Collection
+}
+
+func NewNotificationRepository(db *database.MongoDB) *NotificationRepository {
+	return &NotificationRepository{
+		db:         db,
+		channels:   db.Collection(notificationChannelsCollection),
+		deliveries: db.Collection(notificationDeliveriesCollection),
+		milestones: db.Collection(notificationMilestonesCollection),
+	}
+}
+
+// EnsureIndexes creates the channel owner index, the unique channel and key
+// index EnqueueDelivery relies on to send each notification once, the due
+// and owner indexes deliveries are read by, and a TTL index that removes
+// deliveries after models.DeliveryRetentionDays, and the unique owner index
+// of milestone states.
+func (r *NotificationRepository) EnsureIndexes(ctx context.Context) error {
+	logger.Debug(ctx, "repo: NotificationRepository.EnsureIndexes called")
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	_, err := r.channels.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "userId", Value: 1}, {Key: "createdAt", Value: 1}},
+		Options: options.Index().SetName("user_created"),
+	})
+	if err != nil {
+		logger.Error(ctx, "repo: NotificationRepository.EnsureIndexes - error creating channel indexes", "error", err)
+		return err
+	}
+
+	_, err = r.deliveries.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "channelId", Value: 1}, {Key: "key", Value: 1}},
+			Options: options.Index().SetName("channel_key").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "nextAttemptAt", Value: 1}},
+			Options: options.Index().SetName("status_due"),
+		},
+		{
+			Keys:    bson.D{{Key: "userId", Value: 1}, {Key: "createdAt", Value: -1}},
+			Options: options.Index().SetName("user_created"),
+		},
+		{
+			Keys:    bson.D{{Key: "createdAt", Value: 1}},
+			Options: options.Index().SetName("retention").SetExpireAfterSeconds(int32(models.DeliveryRetentionDays * 24 * 60 * 60)),
+		},
+	})
+	if err != nil {
+		logger.Error(ctx, "repo: NotificationRepository.EnsureIndexes - error creating delivery indexes", "error", err)
+		return err
+	}
+
+	_, err = r.milestones.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "userId", Value: 1}},
+		Options: options.Index().SetName("user").SetUnique(true),
+	})
+	if err != nil {
+		logger.Error(ctx, "repo: NotificationRepository.EnsureIndexes - error creating milestone indexes", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (r *NotificationRepository) CreateChannel(ctx context.Context, channel *models.NotificationChannel) error {
+	logger.Debug(ctx, "repo: NotificationRepository.CreateChannel called", "userID", channel.UserID, "type", channel.Type)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	channel.ID = primitive.NewObjectID()
+	if _, err := r.channels.InsertOne(ctx, channel); err != nil {
+		logger.Error(ctx, "repo: NotificationRepository.CreateChannel - error inserting channel", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (r *NotificationRepository) GetChannel(ctx context.Context, id primitive.ObjectID) (*models.NotificationChannel, error) {
+	logger.Debug(ctx, "repo: NotificationRepository.GetChannel called", "id", id.Hex())
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var channel models.NotificationChannel
+	err := findOne(ctx, "NotificationRepository.GetChannel", r.channels, bson.M{"_id": id}, &channel)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		logger.Error(ctx, "repo: NotificationRepository.GetChannel - error querying database", "error", err)
+		return nil, err
+	}
+	return &channel, nil
+}
+
+func (r *NotificationRepository) ListChannels(ctx context.Context, userID string) ([]models.NotificationChannel, error) {
+	logger.Debug(ctx, "repo: NotificationRepository.ListChannels called", "userID", userID)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	channels := []models.NotificationChannel{}
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}})
+	if err := findAll(ctx, "NotificationRepository.ListChannels", r.channels, bson.M{"userId": userID}, &channels, opts); err != nil {
+		logger.Error(ctx, "repo: NotificationRepository.ListChannels - error querying database", "error", err)
+		return nil, err
+	}
+	return channels, nil
+}
+
+func (r *NotificationRepository) DeleteChannel(ctx context.Context, userID string, id primitive.ObjectID) (bool, error) {
+	logger.Debug(ctx, "repo: NotificationRepository.DeleteChannel called", "userID", userID, "id", id.Hex())
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result, err := r.channels.DeleteOne(ctx, bson.M{"_id": id, "userId": userID})
+	if err != nil {
+		logger.Error(ctx, "repo: NotificationRepository.DeleteChannel - error deleting channel", "error", err)
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
+
+func (r *NotificationRepository) ListSubscribedUsers(ctx context.Context) ([]string, error) {
+	logger.Debug(ctx, "repo: NotificationRepository.ListSubscribedUsers called")
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var values []interface{}
+	err := withRetry(ctx, "NotificationRepository.ListSubscribedUsers", func() error {
+		var err error
+		values, err = r.channels.Distinct(ctx, "userId", bson.M{})
+		return err
+	})
+	if err != nil {
+		logger.Error(ctx, "repo: NotificationRepository.ListSubscribedUsers - error querying database", "error", err)
+		return nil, err
+	}
+
+	userIDs := make([]string, 0, len(values))
+	for _, value := range values {
+		if userID, ok := value.(string); ok {
+			userIDs = append(userIDs, userID)
+		}
+	}
+	return userIDs, nil
+}
+
+func (r *NotificationRepository) EnqueueDelivery(ctx context.Context, delivery *models.NotificationDelivery) (bool, error) {
+	logger.Debug(ctx, "repo: NotificationRepository.EnqueueDelivery called", "channelID", delivery.ChannelID.Hex(), "key", delivery.Key)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	delivery.ID = primitive.NewObjectID()
+	if _, err := r.deliveries.InsertOne(ctx, delivery); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			logger.Debug(ctx, "repo: NotificationRepository.EnqueueDelivery - already enqueued")
+			return false, nil
+		}
+		logger.Error(ctx, "repo: NotificationRepository.EnqueueDelivery - error inserting delivery", "error", err)
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *NotificationRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.NotificationDelivery, error) {
+	logger.Debug(ctx, "repo: NotificationRepository.ClaimDueDeliveries called", "limit", limit)
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	// Each delivery is claimed on its own so concurrent instances never get
+	// the same one: the update moves it out of the due range atomically.
+	filter := bson.M{"status": models.DeliveryPending, "nextAttemptAt": bson.M{"$lte": now}}
+	update := bson.M{"$set": bson.M{"nextAttemptAt": now.Add(lease)}}
+	opts := options.FindOneAndUpdate().SetSort(bson.D{{Key: "nextAttemptAt", Value: 1}, {Key: "_id", Value: 1}})
+
+	deliveries := []models.NotificationDelivery{}
+	for len(deliveries) < limit {
+		var delivery models.NotificationDelivery
+		err := r.deliveries.FindOneAndUpdate(ctx, filter, update, opts).Decode(&delivery)
+		if err == mongo.ErrNoDocuments {
+			break
+		}
+		if err != nil {
+			logger.Error(ctx, "repo: NotificationRepository.ClaimDueDeliveries - error claiming delivery", "error", err)
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, nil
+}
+
+func (r *NotificationRepository) UpdateDelivery(ctx context.Context, delivery *models.NotificationDelivery) error {
+	logger.Debug(ctx, "repo: NotificationRepository.UpdateDelivery called", "id", delivery.ID.Hex(), "status", delivery.Status)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	set := bson.M{
+		"status":         delivery.Status,
+		"attempts":       delivery.Attempts,
+		"responseStatus": delivery.ResponseStatus,
+		"lastError":      delivery.LastError,
+		"nextAttemptAt":  delivery.NextAttemptAt,
+	}
+	update := bson.M{"$set": set}
+	if delivery.DeliveredAt != nil {
+		set["deliveredAt"] = *delivery.DeliveredAt
+	} else {
+		update["$unset"] = bson.M{"deliveredAt": ""}
+	}
+	if _, err := updateOne(ctx, "NotificationRepository.UpdateDelivery", r.deliveries, bson.M{"_id": delivery.ID}, update); err != nil {
+		logger.Error(ctx, "repo: NotificationRepository.UpdateDelivery - error updating delivery", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (r *NotificationRepository) ListDeliveries(ctx context.Context, userID string, limit int) ([]models.NotificationDelivery, error) {
+	logger.Debug(ctx, "repo: NotificationRepository.ListDeliveries called", "userID", userID, "limit", limit)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	deliveries := []models.NotificationDelivery{}
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}).SetLimit(int64(limit))
+	if err := findAll(ctx, "NotificationRepository.ListDeliveries", r.deliveries, bson.M{"userId": userID}, &deliveries, opts); err != nil {
+		logger.Error(ctx, "repo: NotificationRepository.ListDeliveries - error querying database", "error", err)
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (r *NotificationRepository) GetMilestones(ctx context.Context, userID string) (*models.MilestoneState, error) {
+	logger.Debug(ctx, "repo: NotificationRepository.GetMilestones called", "userID", userID)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var state models.MilestoneState
+	err := findOne(ctx, "NotificationRepository.GetMilestones", r.milestones, bson.M{"userId": userID}, &state)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		logger.Error(ctx, "repo: NotificationRepository.GetMilestones - error querying database", "error", err)
+		return nil, err
+	}
+	if state.Reached == nil {
+		state.Reached = map[string]time.Time{}
+	}
+	return &state, nil
+}
+
+func (r *NotificationRepository) SaveMilestones(ctx context.Context, state *models.MilestoneState) error {
+	logger.Debug(ctx, "repo: NotificationRepository.SaveMilestones called", "userID", state.UserID)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	reached := state.Reached
+	if reached == nil {
+		reached = map[string]time.Time{}
+	}
+	update := bson.M{"$set": bson.M{"reached": reached, "checkedAt": state.CheckedAt}}
+	if _, err := updateOne(ctx, "NotificationRepository.SaveMilestones", r.milestones, bson.M{"userId": state.UserID}, update, options.Update().SetUpsert(true)); err != nil {
+		logger.Error(ctx, "repo: NotificationRepository.SaveMilestones - error saving milestones", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (r *NotificationRepository) DeleteMilestones(ctx context.Context, userID string) error {
+	logger.Debug(ctx, "repo: NotificationRepository.DeleteMilestones called", "userID", userID)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if _, err := r.milestones.DeleteOne(ctx, bson.M{"userId": userID}); err != nil {
+		logger.Error(ctx, "repo: NotificationRepository.DeleteMilestones - error deleting milestones", "error", err)
+		return err
+	}
+	return nil
+}
@@ -34,9 +34,9 @@ func (r *OwnedBlueprintsRepository) GetByUserID(ctx context.Context, userID stri
 	defer cancel()
 
 	filter := bson.M{"userId": userID}
-	var ownedBlueprints models.OwnedBlueprints
+	var raw bson.Raw
 
-	err := r.collection.FindOne(ctx, filter).Decode(&ownedBlueprints)
+	err := findOne(ctx, "OwnedBlueprintsRepository.GetByUserID", r.collection, filter, &raw)
 	if err == mongo.ErrNoDocuments {
 		logger.Debug(ctx, "repo: OwnedBlueprintsRepository.GetByUserID - no owned blueprints found for user")
 		return nil, nil
@@ -46,6 +46,19 @@ func (r *OwnedBlueprintsRepository) GetByUserID(ctx context.Context, userID stri
 		return nil, err
 	}
 
+	var ownedBlueprints models.OwnedBlueprints
+	if err := bson.Unmarshal(raw, &ownedBlueprints); err != nil {
+		logger.Error(ctx, "repo: OwnedBlueprintsRepository.GetByUserID - error decoding owned blueprints", "error", err)
+		return nil, err
+	}
+	saved, err := migrateOwnedBlueprintsDocument(ctx, r.collection, raw, &ownedBlueprints)
+	if err != nil {
+		// The migrated copy is still valid to serve; the next read retries.
+		logger.Warn(ctx, "repo: OwnedBlueprintsRepository.GetByUserID - failed to persist migrated owned blueprints", "error", err)
+	} else if saved {
+		logger.Debug(ctx, "repo: OwnedBlueprintsRepository.GetByUserID - migrated owned blueprints on read", "schemaVersion", ownedBlueprints.SchemaVersion)
+	}
+
 	logger.Debug(ctx, "repo: OwnedBlueprintsRepository.GetByUserID - found owned blueprints", "blueprintCount", len(ownedBlueprints.Blueprints))
 	return &ownedBlueprints, nil
 }
@@ -58,9 +71,13 @@ func (r *OwnedBlueprintsRepository) Create(ctx context.Context, ownedBlueprints
 
 	ownedBlueprints.CreatedAt = time.Now()
 	ownedBlueprints.UpdatedAt = time.Now()
+	ownedBlueprints.SchemaVersion = CurrentOwnedBlueprintsSchemaVersion
 	if ownedBlueprints.Blueprints == nil {
 		ownedBlueprints.Blueprints = []models.OwnedBlueprint{}
 	}
+	if ownedBlueprints.Components == nil {
+		ownedBlueprints.Components = []models.OwnedComponent{}
+	}
 
 	result, err := r.collection.InsertOne(ctx, ownedBlueprints)
 	if err != nil {
@@ -107,7 +124,7 @@ func (r *OwnedBlueprintsRepository) RemoveBlueprint(ctx context.Context, userID,
 		"$set":  bson.M{"updatedAt": time.Now()},
 	}
 
-	result, err := r.collection.UpdateOne(ctx, filter, update)
+	result, err := updateOne(ctx, "OwnedBlueprintsRepository.RemoveBlueprint", r.collection, filter, update)
 	if err != nil {
 		logger.Error(ctx, "repo: OwnedBlueprintsRepository.RemoveBlueprint - error updating owned blueprints", "error", err)
 		return err
@@ -125,8 +142,9 @@ func (r *OwnedBlueprintsRepository) BulkAddBlueprints(ctx context.Context, userI
 
 	filter := bson.M{"userId": userID}
 	update := bson.M{
-		"$push": bson.M{"blueprints": bson.M{"$each": blueprints}},
-		"$set":  bson.M{"updatedAt": time.Now()},
+		"$push":        bson.M{"blueprints": bson.M{"$each": blueprints}},
+		"$set":         bson.M{"updatedAt": time.Now()},
+		"$setOnInsert": bson.M{"schemaVersion": CurrentOwnedBlueprintsSchemaVersion, "components": []models.OwnedComponent{}, "createdAt": time.Now()},
 	}
 
 	opts := options.Update().SetUpsert(true)
@@ -154,7 +172,7 @@ func (r *OwnedBlueprintsRepository) ClearAll(ctx context.Context, userID string)
 		},
 	}
 
-	result, err := r.collection.UpdateOne(ctx, filter, update)
+	result, err := updateOne(ctx, "OwnedBlueprintsRepository.ClearAll", r.collection, filter, update)
 	if err != nil {
 		logger.Error(ctx, "repo: OwnedBlueprintsRepository.ClearAll - error clearing owned blueprints", "error", err)
 		return err
@@ -163,3 +181,122 @@ func (r *OwnedBlueprintsRepository) ClearAll(ctx context.Context, userID string)
 	logger.Debug(ctx, "repo: OwnedBlueprintsRepository.ClearAll - completed", "matchedCount", result.MatchedCount, "modifiedCount", result.ModifiedCount)
 	return nil
 }
+
+func (r *OwnedBlueprintsRepository) SetBlueprint(ctx context.Context, userID string, blueprint models.OwnedBlueprint) error {
+	logger.Debug(ctx, "repo: OwnedBlueprintsRepository.SetBlueprint called", "userID", userID, "uniqueName", blueprint.UniqueName, "quantity", blueprint.Quantity)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	// Same two retry-safe steps as SetComponent.
+	filter := bson.M{"userId": userID, "blueprints.uniqueName": blueprint.UniqueName}
+	update := bson.M{
+		"$set": bson.M{"blueprints.$.quantity": blueprint.Quantity, "updatedAt": time.Now()},
+	}
+	result, err := updateOne(ctx, "OwnedBlueprintsRepository.SetBlueprint", r.collection, filter, update)
+	if err != nil {
+		logger.Error(ctx, "repo: OwnedBlueprintsRepository.SetBlueprint - error updating blueprint", "error", err)
+		return err
+	}
+	if result.MatchedCount > 0 {
+		logger.Debug(ctx, "repo: OwnedBlueprintsRepository.SetBlueprint - updated existing blueprint")
+		return nil
+	}
+
+	filter = bson.M{"userId": userID, "blueprints.uniqueName": bson.M{"$ne": blueprint.UniqueName}}
+	update = bson.M{
+		"$push": bson.M{"blueprints": blueprint},
+		"$set":  bson.M{"updatedAt": time.Now()},
+	}
+	result, err = updateOne(ctx, "OwnedBlueprintsRepository.SetBlueprint", r.collection, filter, update)
+	if err != nil {
+		logger.Error(ctx, "repo: OwnedBlueprintsRepository.SetBlueprint - error adding blueprint", "error", err)
+		return err
+	}
+
+	logger.Debug(ctx, "repo: OwnedBlueprintsRepository.SetBlueprint - completed", "matchedCount", result.MatchedCount, "modifiedCount", result.ModifiedCount)
+	return nil
+}
+
+func (r *OwnedBlueprintsRepository) SetComponent(ctx context.Context, userID string, component models.OwnedComponent) error {
+	logger.Debug(ctx, "repo: OwnedBlueprintsRepository.SetComponent called", "userID", userID, "uniqueName", component.UniqueName, "count", component.Count)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	// Update the count of an owned component in place, and only push a new
+	// entry when none matched. Both steps are safe to retry.
+	filter := bson.M{"userId": userID, "components.uniqueName": component.UniqueName}
+	update := bson.M{
+		"$set": bson.M{"components.$.count": component.Count, "updatedAt": time.Now()},
+	}
+	result, err := updateOne(ctx, "OwnedBlueprintsRepository.SetComponent", r.collection, filter, update)
+	if err != nil {
+		logger.Error(ctx, "repo: OwnedBlueprintsRepository.SetComponent - error updating component", "error", err)
+		return err
+	}
+	if result.MatchedCount > 0 {
+		logger.Debug(ctx, "repo: OwnedBlueprintsRepository.SetComponent - updated existing component")
+		return nil
+	}
+
+	filter = bson.M{"userId": userID, "components.uniqueName": bson.M{"$ne": component.UniqueName}}
+	update = bson.M{
+		"$push": bson.M{"components": component},
+		"$set":  bson.M{"updatedAt": time.Now()},
+	}
+	result, err = updateOne(ctx, "OwnedBlueprintsRepository.SetComponent", r.collection, filter, update)
+	if err != nil {
+		logger.Error(ctx, "repo: OwnedBlueprintsRepository.SetComponent - error adding component", "error", err)
+		return err
+	}
+
+	logger.Debug(ctx, "repo: OwnedBlueprintsRepository.SetComponent - completed", "matchedCount", result.MatchedCount, "modifiedCount", result.ModifiedCount)
+	return nil
+}
+
+func (r *OwnedBlueprintsRepository) RemoveComponent(ctx context.Context, userID, uniqueName string) error {
+	logger.Debug(ctx, "repo: OwnedBlueprintsRepository.RemoveComponent called", "userID", userID, "uniqueName", uniqueName)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{"userId": userID}
+	update := bson.M{
+		"$pull": bson.M{"components": bson.M{"uniqueName": uniqueName}},
+		"$set":  bson.M{"updatedAt": time.Now()},
+	}
+
+	result, err := updateOne(ctx, "OwnedBlueprintsRepository.RemoveComponent", r.collection, filter, update)
+	if err != nil {
+		logger.Error(ctx, "repo: OwnedBlueprintsRepository.RemoveComponent - error updating owned components", "error", err)
+		return err
+	}
+
+	logger.Debug(ctx, "repo: OwnedBlueprintsRepository.RemoveComponent - completed", "matchedCount", result.MatchedCount, "modifiedCount", result.ModifiedCount)
+	return nil
+}
+
+func (r *OwnedBlueprintsRepository) ClearComponents(ctx context.Context, userID string) error {
+	logger.Debug(ctx, "repo: OwnedBlueprintsRepository.ClearComponents called", "userID", userID)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{"userId": userID}
+	update := bson.M{
+		"$set": bson.M{
+			"components": []models.OwnedComponent{},
+			"updatedAt":  time.Now(),
+		},
+	}
+
+	result, err := updateOne(ctx, "OwnedBlueprintsRepository.ClearComponents", r.collection, filter, update)
+	if err != nil {
+		logger.Error(ctx, "repo: OwnedBlueprintsRepository.ClearComponents - error clearing owned components", "error", err)
+		return err
+	}
+
+	logger.Debug(ctx, "repo: OwnedBlueprintsRepository.ClearComponents - completed", "matchedCount", result.MatchedCount, "modifiedCount", result.ModifiedCount)
+	return nil
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/database"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const ownedMaterialsCollection = "owned_materials"
+
+// OwnedMaterialsRepository stores one document per user and material, so
+// setting a count is a single atomic upsert.
+type OwnedMaterialsRepository struct {
+	db         *database.MongoDB
+	collection *mongo.Collection
+}
+
+func NewOwnedMaterialsRepository(db *database.MongoDB) *OwnedMaterialsRepository {
+	return &OwnedMaterialsRepository{
+		db:         db,
+		collection: db.Collection(ownedMaterialsCollection),
+	}
+}
+
+func (r *OwnedMaterialsRepository) GetByUserID(ctx context.Context, userID string) ([]models.OwnedMaterial, error) {
+	logger.Debug(ctx, "repo: OwnedMaterialsRepository.GetByUserID called", "userID", userID)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "uniqueName", Value: 1}})
+	materials := []models.OwnedMaterial{}
+	if err := findAll(ctx, "OwnedMaterialsRepository.GetByUserID", r.collection, bson.M{"userId": userID}, &materials, opts); err != nil {
+		logger.Error(ctx, "repo: OwnedMaterialsRepository.GetByUserID - error querying database", "error", err)
+		return nil, err
+	}
+
+	logger.Debug(ctx, "repo: OwnedMaterialsRepository.GetByUserID - completed", "materialCount", len(materials))
+	return materials, nil
+}
+
+func (r *OwnedMaterialsRepository) SetCounts(ctx context.Context, userID string, counts map[string]int) error {
+	logger.Debug(ctx, "repo: OwnedMaterialsRepository.SetCounts called", "userID", userID, "count", len(counts))
+
+	if len(counts) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	writes := make([]mongo.WriteModel, 0, len(counts))
+	for uniqueName, count := range counts {
+		filter := bson.M{"userId": userID, "uniqueName": uniqueName}
+		if count <= 0 {
+			writes = append(writes, mongo.NewDeleteOneModel().SetFilter(filter))
+			continue
+		}
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(filter).
+			SetUpdate(bson.M{"$set": bson.M{"count": count, "updatedAt": now}}).
+			SetUpsert(true))
+	}
+
+	result, err := r.collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		logger.Error(ctx, "repo: OwnedMaterialsRepository.SetCounts - error writing counts", "error", err)
+		return err
+	}
+
+	logger.Debug(ctx, "repo: OwnedMaterialsRepository.SetCounts - completed", "upsertedCount", result.UpsertedCount, "modifiedCount", result.ModifiedCount, "deletedCount", result.DeletedCount)
+	return nil
+}
+
+func (r *OwnedMaterialsRepository) ClearAll(ctx context.Context, userID string) error {
+	logger.Debug(ctx, "repo: OwnedMaterialsRepository.ClearAll called", "userID", userID)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result, err := r.collection.DeleteMany(ctx, bson.M{"userId": userID})
+	if err != nil {
+		logger.Error(ctx, "repo: OwnedMaterialsRepository.ClearAll - error clearing owned materials", "error", err)
+		return err
+	}
+
+	logger.Debug(ctx, "repo: OwnedMaterialsRepository.ClearAll - completed", "deletedCount", result.DeletedCount)
+	return nil
+}
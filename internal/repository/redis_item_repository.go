@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	// redisItemKeyPrefix prefixes the cached items' keys, followed by the
+	// cache generation and the uniqueName.
+	redisItemKeyPrefix = "wfw:items:"
+	// redisItemGenerationKey holds the cache generation, which Invalidate
+	// bumps so every instance stops reading the previous generation's keys.
+	redisItemGenerationKey = "wfw:items:generation"
+	// redisGenerationRefresh is how long an instance reuses the generation it
+	// last read, and so how long other instances may serve items from before
+	// an Invalidate.
+	redisGenerationRefresh = 10 * time.Second
+)
+
+// RedisItemRepository caches uniqueName lookups in a cache shared by every
+// instance, in front of another item repository. Like CachedItemRepository,
+// it caches misses and passes searches through. Items are stored as BSON, as
+// in MongoDB, and entries live for ttl or until Invalidate after a sync.
+// Cache failures are logged and the lookup falls through to next, so an
+// unavailable cache only costs latency.
+type RedisItemRepository struct {
+	next  ItemRepositoryInterface
+	cache SharedCacheInterface
+	ttl   time.Duration
+	now   func() time.Time
+
+	mu           sync.Mutex
+	generation   int64
+	generationAt time.Time
+}
+
+func NewRedisItemRepository(next ItemRepositoryInterface, cache SharedCacheInterface, ttl time.Duration) *RedisItemRepository {
+	return &RedisItemRepository{
+		next:  next,
+		cache: cache,
+		ttl:   ttl,
+		now:   time.Now,
+	}
+}
+
+func (r *RedisItemRepository) Search(ctx context.Context, params models.SearchParams) (*models.ItemSearchPage, error) {
+	return r.next.Search(ctx, params)
+}
+
+func (r *RedisItemRepository) SearchReusableBlueprints(ctx context.Context, query string, limit int) ([]models.ItemSearchResult, error) {
+	return r.next.SearchReusableBlueprints(ctx, query, limit)
+}
+
+func (r *RedisItemRepository) FindByUniqueName(ctx context.Context, uniqueName string) (*models.Item, error) {
+	generation, err := r.currentGeneration(ctx)
+	if err != nil {
+		logger.Warn(ctx, "repo: RedisItemRepository.FindByUniqueName - cache unavailable", "error", err)
+		return r.next.FindByUniqueName(ctx, uniqueName)
+	}
+
+	key := redisItemKey(generation, uniqueName)
+	value, ok, err := r.cache.Get(ctx, key)
+	if err != nil {
+		logger.Warn(ctx, "repo: RedisItemRepository.FindByUniqueName - cache get failed", "error", err)
+	}
+	if ok {
+		if item, ok := decodeCachedItem(ctx, value); ok {
+			logger.Debug(ctx, "repo: RedisItemRepository.FindByUniqueName - cache hit", "uniqueName", uniqueName)
+			return item, nil
+		}
+	}
+
+	item, err := r.next.FindByUniqueName(ctx, uniqueName)
+	if err != nil {
+		return nil, err
+	}
+	r.store(ctx, generation, map[string]*models.Item{uniqueName: item})
+	return item, nil
+}
+
+func (r *RedisItemRepository) FindByUniqueNames(ctx context.Context, uniqueNames []string) (map[string]*models.Item, error) {
+	if len(uniqueNames) == 0 {
+		return r.next.FindByUniqueNames(ctx, uniqueNames)
+	}
+	generation, err := r.currentGeneration(ctx)
+	if err != nil {
+		logger.Warn(ctx, "repo: RedisItemRepository.FindByUniqueNames - cache unavailable", "error", err)
+		return r.next.FindByUniqueNames(ctx, uniqueNames)
+	}
+
+	keys := make([]string, len(uniqueNames))
+	for i, uniqueName := range uniqueNames {
+		keys[i] = redisItemKey(generation, uniqueName)
+	}
+	values, err := r.cache.MGet(ctx, keys...)
+	if err != nil {
+		logger.Warn(ctx, "repo: RedisItemRepository.FindByUniqueNames - cache get failed", "error", err)
+		values = make([][]byte, len(uniqueNames))
+	}
+
+	result := make(map[string]*models.Item, len(uniqueNames))
+	var missing []string
+	for i, uniqueName := range uniqueNames {
+		item, ok := decodeCachedItem(ctx, values[i])
+		if !ok {
+			missing = append(missing, uniqueName)
+			continue
+		}
+		if item != nil {
+			result[uniqueName] = item
+		}
+	}
+
+	logger.Debug(ctx, "repo: RedisItemRepository.FindByUniqueNames - cache lookup", "hits", len(uniqueNames)-len(missing), "misses", len(missing))
+	if len(missing) == 0 {
+		return result, nil
+	}
+
+	found, err := r.next.FindByUniqueNames(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+
+	fetched := make(map[string]*models.Item, len(missing))
+	for _, uniqueName := range missing {
+		fetched[uniqueName] = found[uniqueName]
+		if item := found[uniqueName]; item != nil {
+			result[uniqueName] = item
+		}
+	}
+	r.store(ctx, generation, fetched)
+	return result, nil
+}
+
+// Invalidate starts a new cache generation, so no instance reads items
+// cached before it. Other instances notice within redisGenerationRefresh;
+// the old entries expire with their ttl.
+func (r *RedisItemRepository) Invalidate(ctx context.Context) error {
+	generation, err := r.cache.Incr(ctx, redisItemGenerationKey)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.generation = generation
+	r.generationAt = r.now()
+	return nil
+}
+
+// currentGeneration returns the cache generation, reading it from the cache
+// at most once per redisGenerationRefresh.
+func (r *RedisItemRepository) currentGeneration(ctx context.Context) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.generationAt.IsZero() && r.now().Sub(r.generationAt) < redisGenerationRefresh {
+		return r.generation, nil
+	}
+
+	value, ok, err := r.cache.Get(ctx, redisItemGenerationKey)
+	if err != nil {
+		return 0, err
+	}
+	generation := int64(0)
+	if ok {
+		if generation, err = strconv.ParseInt(string(value), 10, 64); err != nil {
+			return 0, err
+		}
+	}
+	r.generation = generation
+	r.generationAt = r.now()
+	return generation, nil
+}
+
+// store caches items, a nil item as a miss. Failures are only logged.
+func (r *RedisItemRepository) store(ctx context.Context, generation int64, items map[string]*models.Item) {
+	values := make(map[string][]byte, len(items))
+	for uniqueName, item := range items {
+		value := []byte{}
+		if item != nil {
+			encoded, err := bson.Marshal(item)
+			if err != nil {
+				logger.Warn(ctx, "repo: RedisItemRepository.store - failed to encode item", "uniqueName", uniqueName, "error", err)
+				continue
+			}
+			value = encoded
+		}
+		values[redisItemKey(generation, uniqueName)] = value
+	}
+	if err := r.cache.SetMany(ctx, values, r.ttl); err != nil {
+		logger.Warn(ctx, "repo: RedisItemRepository.store - cache set failed", "error", err)
+	}
+}
+
+func redisItemKey(generation int64, uniqueName string) string {
+	return redisItemKeyPrefix + strconv.FormatInt(generation, 10) + ":" + uniqueName
+}
+
+// decodeCachedItem decodes a cached value and reports whether it was one. An
+// empty value is a cached miss, returned as a nil item.
+func decodeCachedItem(ctx context.Context, value []byte) (*models.Item, bool) {
+	if value == nil {
+		return nil, false
+	}
+	if len(value) == 0 {
+		return nil, true
+	}
+	var item models.Item
+	if err := bson.Unmarshal(value, &item); err != nil {
+		logger.Warn(ctx, "repo: RedisItemRepository - failed to decode cached item", "error", err)
+		return nil, false
+	}
+	return &item, true
+}
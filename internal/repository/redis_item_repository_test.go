@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+// fakeSharedCache is an in-memory SharedCacheInterface that can be made to
+// fail.
+type fakeSharedCache struct {
+	mu     sync.Mutex
+	values map[string][]byte
+	ttls   map[string]time.Duration
+	err    error
+}
+
+func newFakeSharedCache() *fakeSharedCache {
+	return &fakeSharedCache{values: make(map[string][]byte), ttls: make(map[string]time.Duration)}
+}
+
+func (c *fakeSharedCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return nil, false, c.err
+	}
+	value, ok := c.values[key]
+	return value, ok, nil
+}
+
+func (c *fakeSharedCache) MGet(ctx context.Context, keys ...string) ([][]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return nil, c.err
+	}
+	values := make([][]byte, len(keys))
+	for i, key := range keys {
+		values[i] = c.values[key]
+	}
+	return values, nil
+}
+
+func (c *fakeSharedCache) SetMany(ctx context.Context, values map[string][]byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return c.err
+	}
+	for key, value := range values {
+		c.values[key] = value
+		c.ttls[key] = ttl
+	}
+	return nil
+}
+
+func (c *fakeSharedCache) Incr(ctx context.Context, key string) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return 0, c.err
+	}
+	n, _ := strconv.ParseInt(string(c.values[key]), 10, 64)
+	n++
+	c.values[key] = []byte(strconv.FormatInt(n, 10))
+	return n, nil
+}
+
+func (c *fakeSharedCache) setErr(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.err = err
+}
+
+func TestRedisItemRepository_FindByUniqueName_CachesHitsAndMisses(t *testing.T) {
+	ctx := context.Background()
+	next, calls, _ := newCountingItemRepo(models.Item{UniqueName: "/Lotus/Forma", Name: "Forma", Collection: "misc"})
+	cache := newFakeSharedCache()
+	repo := NewRedisItemRepository(next, cache, time.Hour)
+
+	for i := 0; i < 3; i++ {
+		item, err := repo.FindByUniqueName(ctx, "/Lotus/Forma")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if item == nil || item.Name != "Forma" || item.Collection != "misc" {
+			t.Fatalf("expected Forma, got %+v", item)
+		}
+		if missing, err := repo.FindByUniqueName(ctx, "/Lotus/Missing"); err != nil || missing != nil {
+			t.Fatalf("expected a miss, got %+v %v", missing, err)
+		}
+	}
+
+	if *calls != 2 {
+		t.Errorf("expected one lookup per uniqueName, got %d", *calls)
+	}
+	if ttl := cache.ttls["wfw:items:0:/Lotus/Forma"]; ttl != time.Hour {
+		t.Errorf("expected entries to live for the ttl, got %v", ttl)
+	}
+}
+
+func TestRedisItemRepository_FindByUniqueNames_FetchesOnlyMisses(t *testing.T) {
+	ctx := context.Background()
+	next, _, batches := newCountingItemRepo(
+		models.Item{UniqueName: "/Lotus/A", Name: "A"},
+		models.Item{UniqueName: "/Lotus/B", Name: "B"},
+	)
+	repo := NewRedisItemRepository(next, newFakeSharedCache(), time.Hour)
+
+	if _, err := repo.FindByUniqueName(ctx, "/Lotus/A"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		items, err := repo.FindByUniqueNames(ctx, []string{"/Lotus/A", "/Lotus/B", "/Lotus/Missing"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(items) != 2 || items["/Lotus/A"].Name != "A" || items["/Lotus/B"].Name != "B" {
+			t.Fatalf("expected A and B, got %+v", items)
+		}
+	}
+
+	if !reflect.DeepEqual(*batches, [][]string{{"/Lotus/B", "/Lotus/Missing"}}) {
+		t.Errorf("expected only the misses fetched once, got %v", *batches)
+	}
+}
+
+func TestRedisItemRepository_Invalidate(t *testing.T) {
+	ctx := context.Background()
+	next, calls, _ := newCountingItemRepo(models.Item{UniqueName: "/Lotus/Forma", Name: "Forma"})
+	cache := newFakeSharedCache()
+	repo := NewRedisItemRepository(next, cache, time.Hour)
+	other := NewRedisItemRepository(next, cache, time.Hour)
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	other.now = func() time.Time { return now }
+
+	for _, r := range []*RedisItemRepository{repo, other} {
+		if _, err := r.FindByUniqueName(ctx, "/Lotus/Forma"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if *calls != 1 {
+		t.Fatalf("expected the instances to share the cache, got %d lookups", *calls)
+	}
+
+	if err := repo.Invalidate(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := repo.FindByUniqueName(ctx, "/Lotus/Forma"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *calls != 2 {
+		t.Errorf("expected a lookup after invalidating, got %d", *calls)
+	}
+
+	if _, err := other.FindByUniqueName(ctx, "/Lotus/Forma"); err != nil || *calls != 2 {
+		t.Errorf("expected another instance to keep its generation for a while, got %d lookups %v", *calls, err)
+	}
+	now = now.Add(redisGenerationRefresh)
+	if _, err := other.FindByUniqueName(ctx, "/Lotus/Forma"); err != nil || *calls != 2 {
+		t.Errorf("expected another instance to read the new generation's entry, got %d lookups %v", *calls, err)
+	}
+	if _, ok := cache.values["wfw:items:1:/Lotus/Forma"]; !ok {
+		t.Errorf("expected entries under the new generation, got %v", cache.values)
+	}
+}
+
+func TestRedisItemRepository_CacheFailureFallsThrough(t *testing.T) {
+	ctx := context.Background()
+	next, calls, batches := newCountingItemRepo(models.Item{UniqueName: "/Lotus/Forma", Name: "Forma"})
+	cache := newFakeSharedCache()
+	cache.setErr(errors.New("connection refused"))
+	repo := NewRedisItemRepository(next, cache, time.Hour)
+
+	item, err := repo.FindByUniqueName(ctx, "/Lotus/Forma")
+	if err != nil || item == nil || item.Name != "Forma" {
+		t.Fatalf("expected Forma from the next repository, got %+v %v", item, err)
+	}
+	items, err := repo.FindByUniqueNames(ctx, []string{"/Lotus/Forma"})
+	if err != nil || items["/Lotus/Forma"] == nil {
+		t.Fatalf("expected Forma from the next repository, got %+v %v", items, err)
+	}
+	if *calls != 1 || len(*batches) != 1 {
+		t.Errorf("expected each lookup to reach the next repository, got %d and %v", *calls, *batches)
+	}
+	if err := repo.Invalidate(ctx); err == nil || !strings.Contains(err.Error(), "connection refused") {
+		t.Errorf("expected Invalidate to report the failure, got %v", err)
+	}
+}
+
+func TestRedisItemRepository_DoesNotCacheErrors(t *testing.T) {
+	ctx := context.Background()
+	failing := true
+	next, _, _ := newCountingItemRepo()
+	next.FindByUniqueNameFunc = func(ctx context.Context, uniqueName string) (*models.Item, error) {
+		if failing {
+			return nil, errors.New("database error")
+		}
+		return &models.Item{UniqueName: uniqueName, Name: "Forma"}, nil
+	}
+	repo := NewRedisItemRepository(next, newFakeSharedCache(), time.Hour)
+
+	if _, err := repo.FindByUniqueName(ctx, "/Lotus/Forma"); err == nil {
+		t.Fatal("expected the error to be returned")
+	}
+	failing = false
+	if item, err := repo.FindByUniqueName(ctx, "/Lotus/Forma"); err != nil || item == nil {
+		t.Errorf("expected the item once the error clears, got %+v %v", item, err)
+	}
+}
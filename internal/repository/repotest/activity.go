@@ -0,0 +1,100 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+// RunActivityRepositoryContract runs the activity repository contract against
+// the implementation returned by newRepo.
+func RunActivityRepositoryContract(t *testing.T, newRepo ActivityRepositoryFactory) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	newEntry := func(userID, action, payload string, age time.Duration) *models.ActivityEntry {
+		return &models.ActivityEntry{
+			UserID:    userID,
+			Target:    models.ActivityTargetWishlist,
+			Action:    action,
+			Payload:   payload,
+			RequestID: "req-" + action,
+			CreatedAt: now.Add(-age),
+		}
+	}
+
+	t.Run("ListByUser returns the newest entries first", func(t *testing.T) {
+		repo := newRepo(t)
+
+		empty, total, err := repo.ListByUser(ctx, "user-1", 0, 10)
+		if err != nil || empty == nil || len(empty) != 0 || total != 0 {
+			t.Fatalf("expected an empty slice, got %v of %d (err %v)", empty, total, err)
+		}
+
+		for _, entry := range []*models.ActivityEntry{
+			newEntry("user-1", models.ActivityAdd, `{"uniqueName":"/Lotus/A","quantity":1}`, 2*time.Minute),
+			newEntry("user-1", models.ActivityUpdate, `{"uniqueName":"/Lotus/A","quantity":3}`, time.Minute),
+			newEntry("user-2", models.ActivityAdd, `{"uniqueName":"/Lotus/B","quantity":1}`, 0),
+			newEntry("user-1", models.ActivityRemove, `{"uniqueName":"/Lotus/A"}`, 0),
+		} {
+			if err := repo.Create(ctx, entry); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if entry.ID.IsZero() {
+				t.Fatal("expected Create to set the ID")
+			}
+		}
+
+		entries, total, err := repo.ListByUser(ctx, "user-1", 0, 10)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(entries) != 3 || total != 3 {
+			t.Fatalf("expected the user's 3 entries, got %+v of %d", entries, total)
+		}
+		if entries[0].Action != models.ActivityRemove || entries[1].Action != models.ActivityUpdate || entries[2].Action != models.ActivityAdd {
+			t.Errorf("expected newest first, got %+v", entries)
+		}
+		oldest := entries[2]
+		if oldest.Payload != `{"uniqueName":"/Lotus/A","quantity":1}` || oldest.RequestID != "req-add" || oldest.Target != models.ActivityTargetWishlist {
+			t.Errorf("expected the entry to round-trip, got %+v", oldest)
+		}
+		if !oldest.CreatedAt.Equal(now.Add(-2 * time.Minute)) {
+			t.Errorf("expected createdAt to round-trip, got %v", oldest.CreatedAt)
+		}
+	})
+
+	t.Run("ListByUser pages with offset and limit", func(t *testing.T) {
+		repo := newRepo(t)
+		for i := range 5 {
+			if err := repo.Create(ctx, newEntry("user-1", models.ActivityAdd, "{}", time.Duration(i)*time.Minute)); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		all, _, err := repo.ListByUser(ctx, "user-1", 0, 5)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		page, total, err := repo.ListByUser(ctx, "user-1", 2, 2)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if total != 5 || len(page) != 2 || page[0].ID != all[2].ID || page[1].ID != all[3].ID {
+			t.Errorf("expected the third and fourth entries of 5, got %+v of %d", page, total)
+		}
+
+		last, _, err := repo.ListByUser(ctx, "user-1", 4, 2)
+		if err != nil || len(last) != 1 || last[0].ID != all[4].ID {
+			t.Errorf("expected only the oldest entry, got %+v (err %v)", last, err)
+		}
+
+		past, total, err := repo.ListByUser(ctx, "user-1", 5, 2)
+		if err != nil || past == nil || len(past) != 0 || total != 5 {
+			t.Errorf("expected an empty slice past the end, got %v of %d (err %v)", past, total, err)
+		}
+	})
+}
@@ -0,0 +1,130 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+// RunCustomItemRepositoryContract runs the custom item repository contract
+// against the implementation returned by newRepo.
+func RunCustomItemRepositoryContract(t *testing.T, newRepo CustomItemRepositoryFactory) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	t.Run("Create and ListByUser", func(t *testing.T) {
+		repo := newRepo(t)
+
+		empty, err := repo.ListByUser(ctx, "user-1")
+		if err != nil || empty == nil || len(empty) != 0 {
+			t.Fatalf("expected an empty slice, got %v (err %v)", empty, err)
+		}
+
+		item := &models.CustomItem{
+			UserID:      "user-1",
+			Name:        "Dojo Garden",
+			Description: "Decoration project",
+			BuildPrice:  5000,
+			Components:  []models.Component{{UniqueName: "/Lotus/Ferrite", Name: "Ferrite", ItemCount: 1000}},
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		if err := repo.Create(ctx, item); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if item.ID.IsZero() || item.UniqueName != models.CustomItemUniqueName(item.ID) {
+			t.Fatalf("expected Create to set the ID and uniqueName, got %q %q", item.ID.Hex(), item.UniqueName)
+		}
+		if err := repo.Create(ctx, &models.CustomItem{UserID: "user-1", Name: "Armory", Components: []models.Component{}, CreatedAt: now, UpdatedAt: now}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := repo.Create(ctx, &models.CustomItem{UserID: "user-2", Name: "Other", Components: []models.Component{}, CreatedAt: now, UpdatedAt: now}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		items, err := repo.ListByUser(ctx, "user-1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(items) != 2 || items[0].Name != "Armory" || items[1].Name != "Dojo Garden" {
+			t.Fatalf("expected the user's items ordered by name, got %+v", items)
+		}
+		got := items[1]
+		if got.UniqueName != item.UniqueName || got.Description != "Decoration project" || got.BuildPrice != 5000 || !got.CreatedAt.Equal(now) {
+			t.Errorf("unexpected item %+v", got)
+		}
+		if len(got.Components) != 1 || got.Components[0].ItemCount != 1000 {
+			t.Errorf("expected the components to round-trip, got %+v", got.Components)
+		}
+		if items[0].Components == nil {
+			t.Error("expected an empty rather than nil components slice")
+		}
+	})
+
+	t.Run("Update only changes the user's own item", func(t *testing.T) {
+		repo := newRepo(t)
+
+		item := &models.CustomItem{UserID: "user-1", Name: "Dojo Garden", Components: []models.Component{}, CreatedAt: now, UpdatedAt: now}
+		if err := repo.Create(ctx, item); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		later := now.Add(time.Hour)
+		update := &models.CustomItem{
+			UserID:     "user-2",
+			UniqueName: item.UniqueName,
+			Name:       "Stolen",
+			Components: []models.Component{},
+			UpdatedAt:  later,
+		}
+		if updated, err := repo.Update(ctx, update); err != nil || updated {
+			t.Errorf("expected another user's update to do nothing, got %v (err %v)", updated, err)
+		}
+
+		update.UserID = "user-1"
+		update.Name = "Dojo Garden II"
+		update.BuildPrice = 100
+		update.Components = []models.Component{{UniqueName: "/Lotus/Rubedo", Name: "Rubedo", ItemCount: 5}}
+		if updated, err := repo.Update(ctx, update); err != nil || !updated {
+			t.Fatalf("expected the item to be updated, got %v (err %v)", updated, err)
+		}
+
+		items, _ := repo.ListByUser(ctx, "user-1")
+		if len(items) != 1 {
+			t.Fatalf("expected one item, got %+v", items)
+		}
+		got := items[0]
+		if got.Name != "Dojo Garden II" || got.BuildPrice != 100 || len(got.Components) != 1 || !got.UpdatedAt.Equal(later) || !got.CreatedAt.Equal(now) {
+			t.Errorf("unexpected updated item %+v", got)
+		}
+
+		missing := &models.CustomItem{UserID: "user-1", UniqueName: models.CustomItemPrefix + "missing", Components: []models.Component{}}
+		if updated, err := repo.Update(ctx, missing); err != nil || updated {
+			t.Errorf("expected an unknown item to do nothing, got %v (err %v)", updated, err)
+		}
+	})
+
+	t.Run("Delete only removes the user's own item", func(t *testing.T) {
+		repo := newRepo(t)
+
+		item := &models.CustomItem{UserID: "user-1", Name: "Dojo Garden", Components: []models.Component{}, CreatedAt: now, UpdatedAt: now}
+		if err := repo.Create(ctx, item); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if deleted, err := repo.Delete(ctx, "user-2", item.UniqueName); err != nil || deleted {
+			t.Errorf("expected another user's delete to do nothing, got %v (err %v)", deleted, err)
+		}
+		if deleted, err := repo.Delete(ctx, "user-1", item.UniqueName); err != nil || !deleted {
+			t.Fatalf("expected the item to be deleted, got %v (err %v)", deleted, err)
+		}
+		if deleted, err := repo.Delete(ctx, "user-1", item.UniqueName); err != nil || deleted {
+			t.Errorf("expected a second delete to do nothing, got %v (err %v)", deleted, err)
+		}
+		if items, _ := repo.ListByUser(ctx, "user-1"); len(items) != 0 {
+			t.Errorf("expected the deleted item to be gone, got %+v", items)
+		}
+	})
+}
@@ -0,0 +1,73 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RunFoundryRepositoryContract runs the foundry repository contract against
+// the implementation returned by newRepo.
+func RunFoundryRepositoryContract(t *testing.T, newRepo FoundryRepositoryFactory) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	newBuild := func(userID, uniqueName string, startedAt time.Time) *models.FoundryBuild {
+		return &models.FoundryBuild{UserID: userID, UniqueName: uniqueName, Name: "Build", BuildTime: 3600, StartedAt: startedAt, CreatedAt: now}
+	}
+
+	t.Run("ListByUser returns the user's builds ordered by start", func(t *testing.T) {
+		repo := newRepo(t)
+
+		empty, err := repo.ListByUser(ctx, "user-1")
+		if err != nil || empty == nil || len(empty) != 0 {
+			t.Fatalf("expected an empty slice, got %v (err %v)", empty, err)
+		}
+
+		later := newBuild("user-1", "/Lotus/Ash", now)
+		earlier := newBuild("user-1", "/Lotus/Forma", now.Add(-time.Hour))
+		for _, build := range []*models.FoundryBuild{later, earlier, newBuild("user-2", "/Lotus/Ash", now)} {
+			if err := repo.Create(ctx, build); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if build.ID.IsZero() {
+				t.Fatal("expected Create to set the ID")
+			}
+		}
+
+		builds, err := repo.ListByUser(ctx, "user-1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(builds) != 2 || builds[0].ID != earlier.ID || builds[1].ID != later.ID {
+			t.Fatalf("expected the user's two builds earliest first, got %+v", builds)
+		}
+		if builds[0].BuildTime != 3600 || !builds[0].StartedAt.Equal(earlier.StartedAt) || builds[0].Name != "Build" {
+			t.Errorf("expected the build to round-trip, got %+v", builds[0])
+		}
+	})
+
+	t.Run("Delete removes only the user's own build", func(t *testing.T) {
+		repo := newRepo(t)
+		build := newBuild("user-1", "/Lotus/Ash", now)
+		if err := repo.Create(ctx, build); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if deleted, err := repo.Delete(ctx, "user-2", build.ID); err != nil || deleted {
+			t.Errorf("expected another user's delete to miss, got %v (err %v)", deleted, err)
+		}
+		if deleted, err := repo.Delete(ctx, "user-1", primitive.NewObjectID()); err != nil || deleted {
+			t.Errorf("expected a missing build to report false, got %v (err %v)", deleted, err)
+		}
+		if deleted, err := repo.Delete(ctx, "user-1", build.ID); err != nil || !deleted {
+			t.Fatalf("expected the build to be deleted, got %v (err %v)", deleted, err)
+		}
+		if builds, _ := repo.ListByUser(ctx, "user-1"); len(builds) != 0 {
+			t.Errorf("expected no builds left, got %+v", builds)
+		}
+	})
+}
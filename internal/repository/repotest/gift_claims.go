@@ -0,0 +1,223 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+)
+
+// RunGiftClaimRepositoryContract runs the gift claim repository contract
+// against the implementation returned by newRepo.
+func RunGiftClaimRepositoryContract(t *testing.T, newRepo GiftClaimRepositoryFactory) {
+	ctx := context.Background()
+	// Expiry is checked against the clock, so claims are relative to now.
+	now := time.Now().Truncate(time.Millisecond)
+
+	newClaim := func(ownerID, uniqueName, claimerID string, expiresAt time.Time) *models.GiftClaim {
+		return &models.GiftClaim{OwnerID: ownerID, UniqueName: uniqueName, ClaimerID: claimerID, CreatedAt: now, ExpiresAt: expiresAt}
+	}
+	mustClaim := func(t *testing.T, repo repository.GiftClaimRepositoryInterface, claim *models.GiftClaim) *models.GiftClaim {
+		t.Helper()
+		stored, err := repo.Claim(ctx, claim)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return stored
+	}
+
+	t.Run("Get returns nil for an unclaimed item", func(t *testing.T) {
+		repo := newRepo(t)
+
+		claim, err := repo.Get(ctx, "owner", "/Lotus/Ash")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if claim != nil {
+			t.Errorf("expected nil, got %+v", claim)
+		}
+	})
+
+	t.Run("Claim stores a new claim", func(t *testing.T) {
+		repo := newRepo(t)
+
+		stored := mustClaim(t, repo, &models.GiftClaim{OwnerID: "owner", UniqueName: "/Lotus/Ash", ClaimerID: "friend", Anonymous: true, CreatedAt: now, ExpiresAt: now.Add(time.Hour)})
+		if stored == nil || stored.ID.IsZero() || stored.ClaimerID != "friend" {
+			t.Fatalf("expected the stored claim with an ID, got %+v", stored)
+		}
+
+		claim, err := repo.Get(ctx, "owner", "/Lotus/Ash")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if claim == nil || claim.ID != stored.ID || claim.ClaimerID != "friend" || !claim.Anonymous || !claim.CreatedAt.Equal(now) || !claim.ExpiresAt.Equal(now.Add(time.Hour)) {
+			t.Errorf("unexpected claim %+v", claim)
+		}
+	})
+
+	t.Run("Claim renews the claimer's own claim", func(t *testing.T) {
+		repo := newRepo(t)
+
+		first := mustClaim(t, repo, newClaim("owner", "/Lotus/Ash", "friend", now.Add(time.Hour)))
+		renewal := newClaim("owner", "/Lotus/Ash", "friend", now.Add(48*time.Hour))
+		renewal.Anonymous = true
+		renewal.CreatedAt = now.Add(time.Minute)
+		renewed := mustClaim(t, repo, renewal)
+
+		if renewed == nil || renewed.ID != first.ID || !renewed.CreatedAt.Equal(now) || !renewed.ExpiresAt.Equal(now.Add(48*time.Hour)) || !renewed.Anonymous {
+			t.Errorf("expected the claim to keep its ID and CreatedAt with the new expiry, got %+v", renewed)
+		}
+	})
+
+	t.Run("Claim keeps another claimer's active claim", func(t *testing.T) {
+		repo := newRepo(t)
+
+		first := mustClaim(t, repo, newClaim("owner", "/Lotus/Ash", "friend", now.Add(time.Hour)))
+		current := mustClaim(t, repo, newClaim("owner", "/Lotus/Ash", "rival", now.Add(2*time.Hour)))
+
+		if current == nil || current.ID != first.ID || current.ClaimerID != "friend" {
+			t.Errorf("expected the first claim to stand, got %+v", current)
+		}
+		// Claims are per owner and item.
+		other := mustClaim(t, repo, newClaim("owner", "/Lotus/Rhino", "rival", now.Add(time.Hour)))
+		if other == nil || other.ClaimerID != "rival" {
+			t.Errorf("expected a claim on another item to be stored, got %+v", other)
+		}
+	})
+
+	t.Run("Claim replaces an expired claim", func(t *testing.T) {
+		repo := newRepo(t)
+
+		expired := mustClaim(t, repo, newClaim("owner", "/Lotus/Ash", "friend", now.Add(-time.Minute)))
+		current := mustClaim(t, repo, newClaim("owner", "/Lotus/Ash", "rival", now.Add(time.Hour)))
+
+		if current == nil || current.ClaimerID != "rival" || current.ID == expired.ID {
+			t.Errorf("expected the new claim to replace the expired one, got %+v", current)
+		}
+
+		claim, err := repo.Get(ctx, "owner", "/Lotus/Ash")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if claim == nil || claim.ClaimerID != "rival" {
+			t.Errorf("expected rival's claim, got %+v", claim)
+		}
+	})
+
+	t.Run("Get ignores expired claims", func(t *testing.T) {
+		repo := newRepo(t)
+
+		mustClaim(t, repo, newClaim("owner", "/Lotus/Ash", "friend", now.Add(-time.Minute)))
+		claim, err := repo.Get(ctx, "owner", "/Lotus/Ash")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if claim != nil {
+			t.Errorf("expected nil, got %+v", claim)
+		}
+	})
+
+	t.Run("ListByOwner returns active claims ordered by uniqueName", func(t *testing.T) {
+		repo := newRepo(t)
+
+		empty, err := repo.ListByOwner(ctx, "owner")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if empty == nil || len(empty) != 0 {
+			t.Fatalf("expected an empty non-nil slice, got %#v", empty)
+		}
+
+		mustClaim(t, repo, newClaim("owner", "/Lotus/Rhino", "friend", now.Add(time.Hour)))
+		mustClaim(t, repo, newClaim("owner", "/Lotus/Ash", "rival", now.Add(time.Hour)))
+		mustClaim(t, repo, newClaim("owner", "/Lotus/Braton", "friend", now.Add(-time.Minute)))
+		mustClaim(t, repo, newClaim("someone-else", "/Lotus/Ash", "friend", now.Add(time.Hour)))
+
+		claims, err := repo.ListByOwner(ctx, "owner")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(claims) != 2 || claims[0].UniqueName != "/Lotus/Ash" || claims[1].UniqueName != "/Lotus/Rhino" {
+			t.Errorf("expected Ash and Rhino, got %+v", claims)
+		}
+	})
+
+	t.Run("ListByClaimer returns active claims ordered by expiry", func(t *testing.T) {
+		repo := newRepo(t)
+
+		empty, err := repo.ListByClaimer(ctx, "friend")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if empty == nil || len(empty) != 0 {
+			t.Fatalf("expected an empty non-nil slice, got %#v", empty)
+		}
+
+		mustClaim(t, repo, newClaim("owner-a", "/Lotus/Ash", "friend", now.Add(2*time.Hour)))
+		mustClaim(t, repo, newClaim("owner-b", "/Lotus/Ash", "friend", now.Add(time.Hour)))
+		mustClaim(t, repo, newClaim("owner-c", "/Lotus/Ash", "friend", now.Add(-time.Minute)))
+		mustClaim(t, repo, newClaim("owner-d", "/Lotus/Ash", "rival", now.Add(time.Hour)))
+
+		claims, err := repo.ListByClaimer(ctx, "friend")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(claims) != 2 || claims[0].OwnerID != "owner-b" || claims[1].OwnerID != "owner-a" {
+			t.Errorf("expected owner-b then owner-a, got %+v", claims)
+		}
+	})
+
+	t.Run("Delete checks the claimer when given", func(t *testing.T) {
+		repo := newRepo(t)
+
+		mustClaim(t, repo, newClaim("owner", "/Lotus/Ash", "friend", now.Add(time.Hour)))
+
+		deleted, err := repo.Delete(ctx, "owner", "/Lotus/Ash", "rival")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if deleted {
+			t.Error("expected another claimer's claim to be kept")
+		}
+
+		deleted, err = repo.Delete(ctx, "owner", "/Lotus/Ash", "friend")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !deleted {
+			t.Error("expected the claimer's claim to be deleted")
+		}
+
+		mustClaim(t, repo, newClaim("owner", "/Lotus/Ash", "friend", now.Add(time.Hour)))
+		deleted, err = repo.Delete(ctx, "owner", "/Lotus/Ash", "")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !deleted {
+			t.Error("expected any claim to be deleted without a claimer")
+		}
+
+		deleted, err = repo.Delete(ctx, "owner", "/Lotus/Ash", "")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if deleted {
+			t.Error("expected deleting a missing claim to report false")
+		}
+	})
+
+	t.Run("Delete ignores expired claims", func(t *testing.T) {
+		repo := newRepo(t)
+
+		mustClaim(t, repo, newClaim("owner", "/Lotus/Ash", "friend", now.Add(-time.Minute)))
+		deleted, err := repo.Delete(ctx, "owner", "/Lotus/Ash", "")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if deleted {
+			t.Error("expected an expired claim to count as missing")
+		}
+	})
+}
@@ -0,0 +1,205 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RunHouseholdRepositoryContract runs the household repository contract
+// against the implementation returned by newRepo.
+func RunHouseholdRepositoryContract(t *testing.T, newRepo HouseholdRepositoryFactory) {
+	ctx := context.Background()
+
+	t.Run("GetLinkByMember returns nil for missing link", func(t *testing.T) {
+		repo := newRepo(t)
+
+		link, err := repo.GetLinkByMember(ctx, "member")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if link != nil {
+			t.Errorf("expected nil link, got %+v", link)
+		}
+	})
+
+	t.Run("CreateLink stores one link per member", func(t *testing.T) {
+		repo := newRepo(t)
+
+		first := &models.HouseholdLink{ManagerID: "manager-1", MemberID: "member", QuantityThreshold: 2, Status: models.HouseholdLinkPending}
+		created, err := repo.CreateLink(ctx, first)
+		if err != nil || !created {
+			t.Fatalf("expected first link to be created, got created=%v err=%v", created, err)
+		}
+		if first.ID.IsZero() || first.CreatedAt.IsZero() {
+			t.Error("expected CreateLink to assign ID and CreatedAt")
+		}
+
+		created, err = repo.CreateLink(ctx, &models.HouseholdLink{ManagerID: "manager-2", MemberID: "member", Status: models.HouseholdLinkPending})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if created {
+			t.Error("expected second link for the same member to be rejected")
+		}
+
+		link, err := repo.GetLinkByMember(ctx, "member")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if link == nil || link.ManagerID != "manager-1" || link.QuantityThreshold != 2 || link.Status != models.HouseholdLinkPending {
+			t.Errorf("expected first link to be kept, got %+v", link)
+		}
+	})
+
+	t.Run("ListLinksByManager returns only that manager's links", func(t *testing.T) {
+		repo := newRepo(t)
+
+		if links, err := repo.ListLinksByManager(ctx, "manager"); err != nil || links == nil || len(links) != 0 {
+			t.Fatalf("expected empty non-nil list, got %v (err %v)", links, err)
+		}
+
+		repo.CreateLink(ctx, &models.HouseholdLink{ManagerID: "manager", MemberID: "member-1", Status: models.HouseholdLinkPending})
+		time.Sleep(2 * time.Millisecond)
+		repo.CreateLink(ctx, &models.HouseholdLink{ManagerID: "manager", MemberID: "member-2", Status: models.HouseholdLinkActive})
+		repo.CreateLink(ctx, &models.HouseholdLink{ManagerID: "other", MemberID: "member-3", Status: models.HouseholdLinkActive})
+
+		links, err := repo.ListLinksByManager(ctx, "manager")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(links) != 2 || links[0].MemberID != "member-1" || links[1].MemberID != "member-2" {
+			t.Errorf("expected member-1 then member-2, got %+v", links)
+		}
+	})
+
+	t.Run("UpdateLink and DeleteLink", func(t *testing.T) {
+		repo := newRepo(t)
+
+		if err := repo.UpdateLink(ctx, "missing", models.HouseholdLinkActive, 1); err != nil {
+			t.Fatalf("expected update of missing link to be a no-op, got %v", err)
+		}
+		if link, _ := repo.GetLinkByMember(ctx, "missing"); link != nil {
+			t.Errorf("expected update not to create a link, got %+v", link)
+		}
+
+		repo.CreateLink(ctx, &models.HouseholdLink{ManagerID: "manager", MemberID: "member", Status: models.HouseholdLinkPending})
+		if err := repo.UpdateLink(ctx, "member", models.HouseholdLinkActive, 5); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		link, _ := repo.GetLinkByMember(ctx, "member")
+		if link == nil || link.Status != models.HouseholdLinkActive || link.QuantityThreshold != 5 {
+			t.Errorf("expected active link with threshold 5, got %+v", link)
+		}
+
+		if err := repo.DeleteLink(ctx, "member"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if link, _ := repo.GetLinkByMember(ctx, "member"); link != nil {
+			t.Errorf("expected link to be deleted, got %+v", link)
+		}
+		if err := repo.DeleteLink(ctx, "member"); err != nil {
+			t.Errorf("expected deleting a missing link to be a no-op, got %v", err)
+		}
+	})
+
+	t.Run("GetPendingChange returns nil for missing change", func(t *testing.T) {
+		repo := newRepo(t)
+
+		change, err := repo.GetPendingChange(ctx, primitive.NewObjectID())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if change != nil {
+			t.Errorf("expected nil change, got %+v", change)
+		}
+	})
+
+	t.Run("pending changes list newest first and filter by status", func(t *testing.T) {
+		repo := newRepo(t)
+
+		newChange := func(memberID, uniqueName string) *models.PendingChange {
+			t.Helper()
+			change := &models.PendingChange{
+				MemberID:   memberID,
+				ManagerID:  "manager",
+				Type:       models.ChangeTypeAddItem,
+				UniqueName: uniqueName,
+				Quantity:   10,
+				Status:     models.ChangeStatusPending,
+			}
+			if err := repo.CreatePendingChange(ctx, change); err != nil {
+				t.Fatalf("CreatePendingChange: %v", err)
+			}
+			time.Sleep(2 * time.Millisecond)
+			return change
+		}
+
+		first := newChange("member-1", "/Lotus/A")
+		newChange("member-2", "/Lotus/B")
+		third := newChange("member-1", "/Lotus/C")
+
+		if first.ID.IsZero() || first.CreatedAt.IsZero() {
+			t.Error("expected CreatePendingChange to assign ID and CreatedAt")
+		}
+
+		stored, err := repo.GetPendingChange(ctx, first.ID)
+		if err != nil || stored == nil || stored.UniqueName != "/Lotus/A" || stored.Quantity != 10 {
+			t.Fatalf("expected stored change, got %+v (err %v)", stored, err)
+		}
+
+		decided, err := repo.DecideChange(ctx, first.ID, models.ChangeStatusApproved)
+		if err != nil || !decided {
+			t.Fatalf("expected change to be decided, got decided=%v err=%v", decided, err)
+		}
+
+		byManager, err := repo.ListChangesByManager(ctx, "manager", models.ChangeStatusPending)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(byManager) != 2 || byManager[0].UniqueName != "/Lotus/C" || byManager[1].UniqueName != "/Lotus/B" {
+			t.Errorf("expected pending C then B, got %+v", byManager)
+		}
+
+		byMember, err := repo.ListChangesByMember(ctx, "member-1", "")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(byMember) != 2 || byMember[0].ID != third.ID || byMember[1].ID != first.ID {
+			t.Errorf("expected member-1 changes C then A, got %+v", byMember)
+		}
+		if byMember[1].Status != models.ChangeStatusApproved || byMember[1].DecidedAt == nil {
+			t.Errorf("expected approved change with decision time, got %+v", byMember[1])
+		}
+
+		if empty, err := repo.ListChangesByMember(ctx, "nobody", ""); err != nil || empty == nil || len(empty) != 0 {
+			t.Errorf("expected empty non-nil list, got %v (err %v)", empty, err)
+		}
+	})
+
+	t.Run("DecideChange only moves pending changes", func(t *testing.T) {
+		repo := newRepo(t)
+
+		change := &models.PendingChange{MemberID: "member", ManagerID: "manager", Type: models.ChangeTypeAddItem, UniqueName: "/Lotus/A", Quantity: 3, Status: models.ChangeStatusPending}
+		repo.CreatePendingChange(ctx, change)
+
+		if decided, _ := repo.DecideChange(ctx, change.ID, models.ChangeStatusRejected); !decided {
+			t.Fatal("expected first decision to apply")
+		}
+		if decided, _ := repo.DecideChange(ctx, change.ID, models.ChangeStatusApproved); decided {
+			t.Error("expected second decision to be refused")
+		}
+		if decided, _ := repo.DecideChange(ctx, primitive.NewObjectID(), models.ChangeStatusApproved); decided {
+			t.Error("expected decision on missing change to be refused")
+		}
+
+		stored, _ := repo.GetPendingChange(ctx, change.ID)
+		if stored.Status != models.ChangeStatusRejected {
+			t.Errorf("expected status to stay rejected, got %s", stored.Status)
+		}
+	})
+}
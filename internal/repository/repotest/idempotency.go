@@ -0,0 +1,112 @@
+package repotest
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+)
+
+// RunIdempotencyRepositoryContract runs the idempotency repository contract
+// against the implementation returned by newRepo.
+func RunIdempotencyRepositoryContract(t *testing.T, newRepo IdempotencyRepositoryFactory) {
+	ctx := context.Background()
+	// Expiry is checked against the clock, so records are relative to now.
+	now := time.Now().Truncate(time.Millisecond)
+
+	newRecord := func(userID, key, fingerprint string, expiresAt time.Time) *models.IdempotencyRecord {
+		return &models.IdempotencyRecord{UserID: userID, Key: key, Fingerprint: fingerprint, CreatedAt: now, ExpiresAt: expiresAt}
+	}
+	mustReserve := func(t *testing.T, repo repository.IdempotencyRepositoryInterface, record *models.IdempotencyRecord) *models.IdempotencyRecord {
+		t.Helper()
+		existing, err := repo.Reserve(ctx, record)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return existing
+	}
+
+	t.Run("Reserve stores a new key as pending", func(t *testing.T) {
+		repo := newRepo(t)
+
+		if existing := mustReserve(t, repo, newRecord("user1", "key-1", "fp", now.Add(time.Hour))); existing != nil {
+			t.Fatalf("expected the key to be stored, got %+v", existing)
+		}
+		existing := mustReserve(t, repo, newRecord("user1", "key-1", "other", now.Add(time.Hour)))
+		if existing == nil || existing.Completed || existing.Fingerprint != "fp" || existing.ID.IsZero() {
+			t.Errorf("expected the pending record, got %+v", existing)
+		}
+	})
+
+	t.Run("Reserve scopes keys to users", func(t *testing.T) {
+		repo := newRepo(t)
+
+		mustReserve(t, repo, newRecord("user1", "key-1", "fp", now.Add(time.Hour)))
+		if existing := mustReserve(t, repo, newRecord("user2", "key-1", "fp", now.Add(time.Hour))); existing != nil {
+			t.Errorf("expected another user's key to be stored, got %+v", existing)
+		}
+	})
+
+	t.Run("Reserve replaces an expired record", func(t *testing.T) {
+		repo := newRepo(t)
+
+		mustReserve(t, repo, newRecord("user1", "key-1", "old", now.Add(-time.Minute)))
+		if existing := mustReserve(t, repo, newRecord("user1", "key-1", "new", now.Add(time.Hour))); existing != nil {
+			t.Fatalf("expected the expired record to be replaced, got %+v", existing)
+		}
+		existing := mustReserve(t, repo, newRecord("user1", "key-1", "again", now.Add(time.Hour)))
+		if existing == nil || existing.Fingerprint != "new" {
+			t.Errorf("expected the new record, got %+v", existing)
+		}
+	})
+
+	t.Run("Complete stores the response", func(t *testing.T) {
+		repo := newRepo(t)
+
+		record := newRecord("user1", "key-1", "fp", now.Add(time.Hour))
+		mustReserve(t, repo, record)
+		record.Status = http.StatusCreated
+		record.Header = map[string][]string{"Content-Type": {"application/json"}}
+		record.Body = []byte(`{"ok":true}`)
+		if err := repo.Complete(ctx, record); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		existing := mustReserve(t, repo, newRecord("user1", "key-1", "fp", now.Add(time.Hour)))
+		if existing == nil || !existing.Completed || existing.Status != http.StatusCreated || !bytes.Equal(existing.Body, record.Body) {
+			t.Fatalf("expected the completed record, got %+v", existing)
+		}
+		if got := existing.Header["Content-Type"]; len(got) != 1 || got[0] != "application/json" {
+			t.Errorf("expected the stored header, got %v", existing.Header)
+		}
+	})
+
+	t.Run("Release frees a pending key but not a completed one", func(t *testing.T) {
+		repo := newRepo(t)
+
+		mustReserve(t, repo, newRecord("user1", "pending", "fp", now.Add(time.Hour)))
+		if err := repo.Release(ctx, "user1", "pending"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if existing := mustReserve(t, repo, newRecord("user1", "pending", "fp", now.Add(time.Hour))); existing != nil {
+			t.Errorf("expected the released key to be free, got %+v", existing)
+		}
+
+		done := newRecord("user1", "done", "fp", now.Add(time.Hour))
+		mustReserve(t, repo, done)
+		done.Status = http.StatusOK
+		if err := repo.Complete(ctx, done); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := repo.Release(ctx, "user1", "done"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if existing := mustReserve(t, repo, newRecord("user1", "done", "fp", now.Add(time.Hour))); existing == nil || !existing.Completed {
+			t.Errorf("expected the completed record to be kept, got %+v", existing)
+		}
+	})
+}
@@ -0,0 +1,63 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+// RunImportAliasRepositoryContract runs the import alias repository contract
+// against the implementation returned by newRepo.
+func RunImportAliasRepositoryContract(t *testing.T, newRepo ImportAliasRepositoryFactory) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	t.Run("Set creates and replaces aliases listed by word", func(t *testing.T) {
+		repo := newRepo(t)
+
+		empty, err := repo.List(ctx)
+		if err != nil || empty == nil || len(empty) != 0 {
+			t.Fatalf("expected an empty slice, got %v (err %v)", empty, err)
+		}
+
+		for _, alias := range []models.ImportAlias{
+			{Word: "wf", Expansion: "warframe", UpdatedAt: now},
+			{Word: "chas", Expansion: "chassis", UpdatedAt: now},
+			{Word: "wf", Expansion: "war frame", UpdatedAt: now.Add(time.Minute)},
+		} {
+			if err := repo.Set(ctx, alias); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		aliases, err := repo.List(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(aliases) != 2 || aliases[0].Word != "chas" || aliases[1].Word != "wf" {
+			t.Fatalf("expected chas then wf, got %+v", aliases)
+		}
+		if aliases[1].Expansion != "war frame" || !aliases[1].UpdatedAt.Equal(now.Add(time.Minute)) || aliases[1].BuiltIn {
+			t.Errorf("unexpected alias %+v", aliases[1])
+		}
+	})
+
+	t.Run("Delete reports whether the alias existed", func(t *testing.T) {
+		repo := newRepo(t)
+
+		if err := repo.Set(ctx, models.ImportAlias{Word: "wf", Expansion: "warframe", UpdatedAt: now}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		deleted, err := repo.Delete(ctx, "wf")
+		if err != nil || !deleted {
+			t.Errorf("expected the alias to be deleted, got %v (err %v)", deleted, err)
+		}
+		again, err := repo.Delete(ctx, "wf")
+		if err != nil || again {
+			t.Errorf("expected a missing alias, got %v (err %v)", again, err)
+		}
+	})
+}
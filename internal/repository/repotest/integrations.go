@@ -0,0 +1,166 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RunIntegrationRepositoryContract runs the integration repository contract
+// against the implementation returned by newRepo.
+func RunIntegrationRepositoryContract(t *testing.T, newRepo IntegrationRepositoryFactory) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	newIntegration := func(name string, createdAt time.Time) *models.Integration {
+		return &models.Integration{
+			Name: name, URL: "https://example.com/hook", Secret: "secret",
+			Events: []string{models.IntegrationItemAdded}, CreatedAt: createdAt,
+		}
+	}
+	newDelivery := func(integrationID primitive.ObjectID, key string, due time.Time) *models.IntegrationDelivery {
+		return &models.IntegrationDelivery{
+			IntegrationID: integrationID, Event: models.IntegrationItemAdded, Key: key,
+			Payload: `{"event":"item.added"}`, Status: models.DeliveryPending, NextAttemptAt: due, CreatedAt: due,
+		}
+	}
+
+	t.Run("integrations are listed oldest first and deleted", func(t *testing.T) {
+		repo := newRepo(t)
+
+		empty, err := repo.ListIntegrations(ctx)
+		if err != nil || empty == nil || len(empty) != 0 {
+			t.Fatalf("expected an empty slice, got %v (err %v)", empty, err)
+		}
+
+		later := newIntegration("wiki", now)
+		earlier := newIntegration("market", now.Add(-time.Hour))
+		for _, integration := range []*models.Integration{later, earlier} {
+			if err := repo.CreateIntegration(ctx, integration); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if integration.ID.IsZero() {
+				t.Fatal("expected CreateIntegration to set the ID")
+			}
+		}
+
+		integrations, err := repo.ListIntegrations(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(integrations) != 2 || integrations[0].ID != earlier.ID || integrations[1].ID != later.ID {
+			t.Fatalf("expected both integrations oldest first, got %+v", integrations)
+		}
+		if integrations[0].Secret != "secret" || integrations[0].Name != "market" || len(integrations[0].Events) != 1 {
+			t.Errorf("expected the integration to round-trip, got %+v", integrations[0])
+		}
+
+		if got, err := repo.GetIntegration(ctx, later.ID); err != nil || got == nil || got.Name != "wiki" {
+			t.Errorf("expected GetIntegration to find the integration, got %+v (err %v)", got, err)
+		}
+		if missing, err := repo.GetIntegration(ctx, primitive.NewObjectID()); err != nil || missing != nil {
+			t.Errorf("expected nil for a missing integration, got %+v (err %v)", missing, err)
+		}
+
+		if deleted, err := repo.DeleteIntegration(ctx, later.ID); err != nil || !deleted {
+			t.Fatalf("expected the integration to be deleted, got %v (err %v)", deleted, err)
+		}
+		if deleted, err := repo.DeleteIntegration(ctx, later.ID); err != nil || deleted {
+			t.Errorf("expected a second delete to miss, got %v (err %v)", deleted, err)
+		}
+		if integrations, _ := repo.ListIntegrations(ctx); len(integrations) != 1 {
+			t.Errorf("expected one integration left, got %+v", integrations)
+		}
+	})
+
+	t.Run("EnqueueDelivery stores each key once per integration", func(t *testing.T) {
+		repo := newRepo(t)
+		integrationID, otherID := primitive.NewObjectID(), primitive.NewObjectID()
+
+		first := newDelivery(integrationID, "item.added:1", now)
+		if stored, err := repo.EnqueueDelivery(ctx, first); err != nil || !stored || first.ID.IsZero() {
+			t.Fatalf("expected the delivery to be stored with an ID, got %v (err %v)", stored, err)
+		}
+		if stored, err := repo.EnqueueDelivery(ctx, newDelivery(integrationID, "item.added:1", now)); err != nil || stored {
+			t.Errorf("expected a repeated key to be skipped, got %v (err %v)", stored, err)
+		}
+		if stored, err := repo.EnqueueDelivery(ctx, newDelivery(otherID, "item.added:1", now)); err != nil || !stored {
+			t.Errorf("expected the key to be stored for another integration, got %v (err %v)", stored, err)
+		}
+	})
+
+	t.Run("ClaimDueDeliveries leases due pending deliveries", func(t *testing.T) {
+		repo := newRepo(t)
+		integrationID := primitive.NewObjectID()
+
+		overdue := newDelivery(integrationID, "a", now.Add(-time.Minute))
+		due := newDelivery(integrationID, "b", now)
+		future := newDelivery(integrationID, "c", now.Add(time.Minute))
+		for _, delivery := range []*models.IntegrationDelivery{due, future, overdue} {
+			if _, err := repo.EnqueueDelivery(ctx, delivery); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		claimed, err := repo.ClaimDueDeliveries(ctx, now, time.Minute, 1)
+		if err != nil || len(claimed) != 1 || claimed[0].ID != overdue.ID {
+			t.Fatalf("expected the overdue delivery first, got %+v (err %v)", claimed, err)
+		}
+		claimed, err = repo.ClaimDueDeliveries(ctx, now, time.Minute, 10)
+		if err != nil || len(claimed) != 1 || claimed[0].ID != due.ID || claimed[0].Payload != due.Payload {
+			t.Fatalf("expected only the due delivery, the overdue one being leased, got %+v (err %v)", claimed, err)
+		}
+		if again, err := repo.ClaimDueDeliveries(ctx, now, time.Minute, 10); err != nil || len(again) != 0 {
+			t.Errorf("expected nothing due while leased, got %+v (err %v)", again, err)
+		}
+		if afterLease, err := repo.ClaimDueDeliveries(ctx, now.Add(time.Minute), time.Minute, 10); err != nil || len(afterLease) != 3 {
+			t.Errorf("expected all three due once the lease ends, got %+v (err %v)", afterLease, err)
+		}
+	})
+
+	t.Run("UpdateDelivery saves the outcome and ListDeliveries returns the latest first", func(t *testing.T) {
+		repo := newRepo(t)
+		integrationID := primitive.NewObjectID()
+		var deliveries []*models.IntegrationDelivery
+		for i, key := range []string{"a", "b", "c"} {
+			delivery := newDelivery(integrationID, key, now.Add(time.Duration(i)*time.Minute))
+			if _, err := repo.EnqueueDelivery(ctx, delivery); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			deliveries = append(deliveries, delivery)
+		}
+		if _, err := repo.EnqueueDelivery(ctx, newDelivery(primitive.NewObjectID(), "a", now)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		deliveredAt := now.Add(time.Hour)
+		latest := deliveries[2]
+		latest.Status = models.DeliveryDelivered
+		latest.Attempts = 2
+		latest.ResponseStatus = 204
+		latest.LastError = "earlier failure"
+		latest.DeliveredAt = &deliveredAt
+		if err := repo.UpdateDelivery(ctx, latest); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		listed, err := repo.ListDeliveries(ctx, integrationID, 2)
+		if err != nil || len(listed) != 2 || listed[0].Key != "c" || listed[1].Key != "b" {
+			t.Fatalf("expected the two newest deliveries, got %+v (err %v)", listed, err)
+		}
+		got := listed[0]
+		if got.Status != models.DeliveryDelivered || got.Attempts != 2 || got.ResponseStatus != 204 || got.LastError != "earlier failure" ||
+			got.DeliveredAt == nil || !got.DeliveredAt.Equal(deliveredAt) {
+			t.Errorf("expected the outcome to round-trip, got %+v", got)
+		}
+		if empty, err := repo.ListDeliveries(ctx, primitive.NewObjectID(), 10); err != nil || empty == nil || len(empty) != 0 {
+			t.Errorf("expected an empty slice, got %v (err %v)", empty, err)
+		}
+		if err := repo.UpdateDelivery(ctx, &models.IntegrationDelivery{ID: primitive.NewObjectID(), Status: models.DeliveryFailed}); err != nil {
+			t.Errorf("expected updating a missing delivery to be a no-op, got %v", err)
+		}
+	})
+}
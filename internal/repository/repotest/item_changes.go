@@ -0,0 +1,111 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+// RunItemChangeRepositoryContract runs the item change repository contract
+// against the implementation returned by newRepo.
+func RunItemChangeRepositoryContract(t *testing.T, newRepo ItemChangeRepositoryFactory) {
+	ctx := context.Background()
+
+	t.Run("GetFingerprints returns an empty map initially", func(t *testing.T) {
+		repo := newRepo(t)
+
+		fingerprints, err := repo.GetFingerprints(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if fingerprints == nil || len(fingerprints) != 0 {
+			t.Errorf("expected empty non-nil map, got %#v", fingerprints)
+		}
+	})
+
+	t.Run("ReplaceFingerprints stores exactly the given fingerprints", func(t *testing.T) {
+		repo := newRepo(t)
+
+		first := []models.ItemFingerprint{
+			{UniqueName: "/Lotus/A", Name: "A", Collection: "warframes", Recipe: "r1", Stats: "s1", Availability: "a1"},
+			{UniqueName: "/Lotus/B", Name: "B", Collection: "resources", Recipe: "r2", Stats: "s2", Availability: "a2"},
+		}
+		if err := repo.ReplaceFingerprints(ctx, first); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		second := []models.ItemFingerprint{
+			{UniqueName: "/Lotus/B", Name: "B", Collection: "resources", Recipe: "r3", Stats: "s2", Availability: "a2"},
+			{UniqueName: "/Lotus/C", Name: "C", Collection: "mods"},
+		}
+		if err := repo.ReplaceFingerprints(ctx, second); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		fingerprints, err := repo.GetFingerprints(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(fingerprints) != 2 {
+			t.Fatalf("expected 2 fingerprints, got %+v", fingerprints)
+		}
+		if fingerprints["/Lotus/B"] != second[0] || fingerprints["/Lotus/C"] != second[1] {
+			t.Errorf("unexpected fingerprints: %+v", fingerprints)
+		}
+		if _, ok := fingerprints["/Lotus/A"]; ok {
+			t.Error("expected /Lotus/A to be removed")
+		}
+	})
+
+	t.Run("ListChangesSince filters by time and returns newest first", func(t *testing.T) {
+		repo := newRepo(t)
+		base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+		changes := []models.ItemChange{
+			{UniqueName: "/Lotus/Old", Name: "Old", Kinds: []string{models.ItemChangeRecipe}, ChangedAt: base.Add(-48 * time.Hour)},
+			{UniqueName: "/Lotus/A", Name: "A", Kinds: []string{models.ItemChangeRecipe, models.ItemChangeStats}, DataVersion: "v2", ChangedAt: base},
+			{UniqueName: "/Lotus/B", Name: "B", Kinds: []string{models.ItemChangeAdded}, DataVersion: "v3", ChangedAt: base.Add(time.Hour)},
+		}
+		if err := repo.RecordChanges(ctx, changes); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, change := range changes {
+			if change.ID.IsZero() {
+				t.Errorf("expected RecordChanges to assign an ID to %s", change.UniqueName)
+			}
+		}
+
+		listed, err := repo.ListChangesSince(ctx, base, 10)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(listed) != 2 || listed[0].UniqueName != "/Lotus/B" || listed[1].UniqueName != "/Lotus/A" {
+			t.Fatalf("unexpected changes: %+v", listed)
+		}
+		if !listed[1].ChangedAt.Equal(base) || listed[1].DataVersion != "v2" || len(listed[1].Kinds) != 2 {
+			t.Errorf("unexpected change fields: %+v", listed[1])
+		}
+
+		limited, err := repo.ListChangesSince(ctx, time.Time{}, 1)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(limited) != 1 || limited[0].UniqueName != "/Lotus/B" {
+			t.Errorf("expected only the newest change, got %+v", limited)
+		}
+	})
+
+	t.Run("ListChangesSince returns an empty list when nothing changed", func(t *testing.T) {
+		repo := newRepo(t)
+
+		listed, err := repo.ListChangesSince(ctx, time.Time{}, 10)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if listed == nil || len(listed) != 0 {
+			t.Errorf("expected empty non-nil list, got %#v", listed)
+		}
+	})
+}
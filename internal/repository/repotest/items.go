@@ -0,0 +1,571 @@
+package repotest
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+)
+
+var contractItemSeed = ItemSeed{
+	"warframes": `[
+		{"uniqueName": "/Lotus/Powersuits/Alpha", "name": "Alpha Frame", "consumeOnBuild": true,
+		 "components": [{"uniqueName": "/Lotus/Resources/Plate", "name": "Plate", "itemCount": 2}]},
+		{"uniqueName": "/Lotus/Powersuits/Beta", "name": "Beta Frame", "health": 300, "sprintSpeed": 1.15,
+		 "abilities": [{"uniqueName": "/Lotus/Abilities/Dash", "name": "Dash"}]}
+	]`,
+	"primary": `[
+		{"uniqueName": "/Lotus/Weapons/AlphaRifle", "name": "Alpha Rifle", "consumeOnBuild": false},
+		{"uniqueName": "/Lotus/Weapons/GammaRifle", "name": "Gamma Rifle", "consumeOnBuild": false,
+		 "totalDamage": 24.5, "damage": {"total": 24.5, "impact": 24.5, "heat": 0}, "magazineSize": 30, "disposition": 4}
+	]`,
+	"resources": `[
+		{"uniqueName": "/Lotus/Resources/Plate", "name": "Plate"}
+	]`,
+}
+
+// RunItemRepositoryContract runs the item repository contract against the
+// implementation returned by newRepo.
+func RunItemRepositoryContract(t *testing.T, newRepo ItemRepositoryFactory) {
+	ctx := context.Background()
+
+	t.Run("FindByUniqueName returns nil for missing item", func(t *testing.T) {
+		repo := newRepo(t, contractItemSeed)
+
+		item, err := repo.FindByUniqueName(ctx, "/Lotus/Missing")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if item != nil {
+			t.Errorf("expected nil item, got %+v", item)
+		}
+	})
+
+	t.Run("FindByUniqueName sets collection and nested components", func(t *testing.T) {
+		repo := newRepo(t, contractItemSeed)
+
+		item, err := repo.FindByUniqueName(ctx, "/Lotus/Powersuits/Alpha")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if item == nil {
+			t.Fatal("expected item, got nil")
+		}
+		if item.Collection != "warframes" {
+			t.Errorf("expected collection 'warframes', got '%s'", item.Collection)
+		}
+		if !item.ConsumeOnBuild {
+			t.Error("expected consumeOnBuild to be true")
+		}
+		if len(item.Components) != 1 || item.Components[0].ItemCount != 2 {
+			t.Errorf("unexpected components: %+v", item.Components)
+		}
+	})
+
+	t.Run("FindByUniqueNames decodes frame and weapon stats", func(t *testing.T) {
+		repo := newRepo(t, contractItemSeed)
+
+		result, err := repo.FindByUniqueNames(ctx, []string{"/Lotus/Powersuits/Beta", "/Lotus/Weapons/GammaRifle"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		frame := result["/Lotus/Powersuits/Beta"]
+		if frame == nil || frame.Health != 300 || frame.SprintSpeed != 1.15 || len(frame.Abilities) != 1 || frame.Abilities[0].Name != "Dash" {
+			t.Errorf("unexpected frame stats %+v", frame)
+		}
+		weapon := result["/Lotus/Weapons/GammaRifle"]
+		if weapon == nil || weapon.TotalDamage != 24.5 || weapon.Damage["impact"] != 24.5 || weapon.MagazineSize != 30 || weapon.Disposition != 4 {
+			t.Errorf("unexpected weapon stats %+v", weapon)
+		}
+	})
+
+	t.Run("FindByUniqueNames returns empty map for no names", func(t *testing.T) {
+		repo := newRepo(t, contractItemSeed)
+
+		result, err := repo.FindByUniqueNames(ctx, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result == nil || len(result) != 0 {
+			t.Errorf("expected empty non-nil map, got %v", result)
+		}
+	})
+
+	t.Run("FindByUniqueNames omits missing items", func(t *testing.T) {
+		repo := newRepo(t, contractItemSeed)
+
+		result, err := repo.FindByUniqueNames(ctx, []string{"/Lotus/Resources/Plate", "/Lotus/Weapons/GammaRifle", "/Lotus/Missing"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(result) != 2 {
+			t.Fatalf("expected 2 items, got %d", len(result))
+		}
+		if _, ok := result["/Lotus/Missing"]; ok {
+			t.Error("expected missing item to be absent from the map")
+		}
+		if result["/Lotus/Resources/Plate"].Collection != "resources" {
+			t.Errorf("expected collection 'resources', got '%s'", result["/Lotus/Resources/Plate"].Collection)
+		}
+	})
+
+	t.Run("Search", func(t *testing.T) {
+		repo := newRepo(t, contractItemSeed)
+
+		tests := []struct {
+			name          string
+			params        models.SearchParams
+			expected      []string
+			expectedTotal int
+		}{
+			{name: "case insensitive whole words", params: models.SearchParams{Query: "ALPHA"}, expected: []string{"Alpha Frame", "Alpha Rifle"}, expectedTotal: 2},
+			{name: "partial words do not match", params: models.SearchParams{Query: "alph"}, expected: nil},
+			{name: "relevance order", params: models.SearchParams{Query: "rifle alpha"}, expected: []string{"Alpha Rifle", "Alpha Frame", "Gamma Rifle"}, expectedTotal: 3},
+			{name: "offset and limit page the relevance order", params: models.SearchParams{Query: "rifle alpha", Offset: 1, Limit: 1}, expected: []string{"Alpha Frame"}, expectedTotal: 3},
+			{name: "negated word", params: models.SearchParams{Query: "rifle -gamma"}, expected: []string{"Alpha Rifle"}, expectedTotal: 1},
+			{name: "only negated words", params: models.SearchParams{Query: "-alpha"}, expected: nil},
+			{name: "phrase", params: models.SearchParams{Query: `"alpha rifle"`}, expected: []string{"Alpha Rifle"}, expectedTotal: 1},
+			{name: "category", params: models.SearchParams{Category: "primary"}, expected: []string{"Alpha Rifle", "Gamma Rifle"}, expectedTotal: 2},
+			{name: "limit", params: models.SearchParams{Limit: 3}, expected: []string{"Alpha Frame", "Beta Frame", "Alpha Rifle"}, expectedTotal: 5},
+			{name: "offset applies across collections", params: models.SearchParams{Offset: 1}, expected: []string{"Beta Frame", "Alpha Rifle", "Gamma Rifle", "Plate"}, expectedTotal: 5},
+			{name: "offset past the end", params: models.SearchParams{Offset: 10}, expected: nil, expectedTotal: 5},
+			{name: "no matches", params: models.SearchParams{Query: "zeta"}, expected: nil},
+			{name: "unknown category", params: models.SearchParams{Category: "nonexistent"}, expected: nil},
+			{name: "query without words", params: models.SearchParams{Query: "("}, expected: nil},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				page, err := repo.Search(ctx, tt.params)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				assertResultNames(t, page.Items, tt.expected)
+				if page.Total != tt.expectedTotal {
+					t.Errorf("expected total %d, got %d", tt.expectedTotal, page.Total)
+				}
+			})
+		}
+	})
+
+	t.Run("Search ranks name matches above description matches", func(t *testing.T) {
+		repo := newRepo(t, ItemSeed{
+			"mods": `[
+				{"uniqueName": "/Lotus/Mods/Fireball", "name": "Fireball", "description": "Augment for Ash"},
+				{"uniqueName": "/Lotus/Mods/AshPrime", "name": "Ash Prime"},
+				{"uniqueName": "/Lotus/Mods/Ash", "name": "Ash"}
+			]`,
+		})
+
+		page, err := repo.Search(ctx, models.SearchParams{Query: "ash"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assertResultNames(t, page.Items, []string{"Ash", "Ash Prime", "Fireball"})
+	})
+
+	t.Run("Search excludes archived items unless requested", func(t *testing.T) {
+		repo := newRepo(t, ItemSeed{
+			"mods": `[
+				{"uniqueName": "/Lotus/Mods/Live", "name": "Live Mod"},
+				{"uniqueName": "/Lotus/Mods/Gone", "name": "Gone Mod", "archived": true}
+			]`,
+		})
+
+		page, err := repo.Search(ctx, models.SearchParams{Query: "mod"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assertResultNames(t, page.Items, []string{"Live Mod"})
+
+		page, err = repo.Search(ctx, models.SearchParams{Query: "mod", IncludeArchived: true})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assertResultNames(t, page.Items, []string{"Gone Mod", "Live Mod"})
+		if !page.Items[0].Archived || page.Items[1].Archived {
+			t.Errorf("expected only Gone Mod to be flagged archived, got %+v", page.Items)
+		}
+
+		item, err := repo.FindByUniqueName(ctx, "/Lotus/Mods/Gone")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if item == nil || !item.Archived {
+			t.Errorf("expected archived item to stay resolvable, got %+v", item)
+		}
+	})
+
+	t.Run("default search scope excludes nodes but resolves them", func(t *testing.T) {
+		repo := newRepo(t, ItemSeed{
+			"mods": `[{"uniqueName": "/Lotus/Mods/Venus", "name": "Venus Mod", "consumeOnBuild": false}]`,
+			"node": `[{"uniqueName": "SolNode1", "name": "Venus Node", "consumeOnBuild": false}]`,
+		})
+
+		page, err := repo.Search(ctx, models.SearchParams{Query: "venus"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assertResultNames(t, page.Items, []string{"Venus Mod"})
+		if page.Total != 1 {
+			t.Errorf("expected total 1, got %d", page.Total)
+		}
+
+		page, err = repo.Search(ctx, models.SearchParams{Query: "venus", Category: "node"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assertResultNames(t, page.Items, []string{"Venus Node"})
+
+		results, err := repo.SearchReusableBlueprints(ctx, "venus", 0)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assertResultNames(t, results, []string{"Venus Mod"})
+
+		item, err := repo.FindByUniqueName(ctx, "SolNode1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if item == nil || item.Collection != "node" {
+			t.Errorf("expected the node to resolve, got %+v", item)
+		}
+
+		found, err := repo.FindByUniqueNames(ctx, []string{"/Lotus/Mods/Venus", "SolNode1"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(found) != 2 || found["SolNode1"] == nil || found["SolNode1"].Collection != "node" {
+			t.Errorf("expected the mod and the node, got %v", found)
+		}
+	})
+
+	t.Run("SearchReusableBlueprints requires explicit consumeOnBuild false", func(t *testing.T) {
+		repo := newRepo(t, contractItemSeed)
+
+		results, err := repo.SearchReusableBlueprints(ctx, "", 0)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assertResultNames(t, results, []string{"Alpha Rifle", "Gamma Rifle"})
+
+		results, err = repo.SearchReusableBlueprints(ctx, "gamma", 0)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assertResultNames(t, results, []string{"Gamma Rifle"})
+	})
+}
+
+func assertResultNames(t *testing.T, results []models.ItemSearchResult, expected []string) {
+	t.Helper()
+
+	if len(results) != len(expected) {
+		t.Fatalf("expected %d results %v, got %d: %+v", len(expected), expected, len(results), results)
+	}
+	for i, name := range expected {
+		if results[i].Name != name {
+			t.Errorf("result %d: expected '%s', got '%s'", i, name, results[i].Name)
+		}
+		if results[i].Collection == "" {
+			t.Errorf("result %d: expected collection to be set", i)
+		}
+	}
+}
+
+// RunItemCatalogContract runs the item catalog contract against the
+// implementation returned by newRepo.
+func RunItemCatalogContract(t *testing.T, newRepo ItemCatalogFactory) {
+	ctx := context.Background()
+
+	t.Run("ForEachItem visits each item once in collection order", func(t *testing.T) {
+		repo := newRepo(t, ItemSeed{
+			"warframes": `[{"uniqueName": "/Lotus/Powersuits/Alpha", "name": "Alpha Frame"}]`,
+			"resources": `[
+				{"uniqueName": "/Lotus/Resources/Plate", "name": "Plate"},
+				{"uniqueName": "/Lotus/Powersuits/Alpha", "name": "Alpha Duplicate"}
+			]`,
+		})
+
+		var visited []models.Item
+		err := repo.ForEachItem(ctx, func(item models.Item) error {
+			visited = append(visited, item)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(visited) != 2 {
+			t.Fatalf("expected 2 items, got %+v", visited)
+		}
+		if visited[0].UniqueName != "/Lotus/Powersuits/Alpha" || visited[0].Name != "Alpha Frame" || visited[0].Collection != "warframes" {
+			t.Errorf("expected Alpha from warframes first, got %+v", visited[0])
+		}
+		if visited[1].UniqueName != "/Lotus/Resources/Plate" || visited[1].Collection != "resources" {
+			t.Errorf("expected Plate from resources second, got %+v", visited[1])
+		}
+	})
+
+	t.Run("ForEachItem stops at the first callback error", func(t *testing.T) {
+		repo := newRepo(t, contractItemSeed)
+		stop := errors.New("stop")
+
+		calls := 0
+		err := repo.ForEachItem(ctx, func(item models.Item) error {
+			calls++
+			return stop
+		})
+		if !errors.Is(err, stop) {
+			t.Errorf("expected callback error, got %v", err)
+		}
+		if calls != 1 {
+			t.Errorf("expected 1 call, got %d", calls)
+		}
+	})
+}
+
+// RunRelicCatalogContract runs the relic catalog contract against the
+// implementation returned by newRepo.
+func RunRelicCatalogContract(t *testing.T, newRepo RelicCatalogFactory) {
+	ctx := context.Background()
+
+	t.Run("FindRelicsByNames returns relics keyed by name", func(t *testing.T) {
+		repo := newRepo(t, ItemSeed{
+			"relics": `[
+				{"uniqueName": "/Lotus/Types/Game/Projections/T1Intact", "name": "Lith A1 Intact", "imageName": "lith-intact.png"},
+				{"uniqueName": "/Lotus/Types/Game/Projections/T1Radiant", "name": "Lith A1 Radiant"},
+				{"uniqueName": "/Lotus/Types/Game/Projections/T4Intact", "name": "Axi B2 Intact"}
+			]`,
+			"resources": `[{"uniqueName": "/Lotus/Resources/Lith", "name": "Axi B2 Intact"}]`,
+		})
+
+		found, err := repo.FindRelicsByNames(ctx, []string{"Lith A1 Intact", "Axi B2 Intact", "Meso Z9 Intact"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(found) != 2 {
+			t.Fatalf("expected 2 relics, got %+v", found)
+		}
+		lith := found["Lith A1 Intact"]
+		if lith == nil || lith.UniqueName != "/Lotus/Types/Game/Projections/T1Intact" || lith.ImageName != "lith-intact.png" || lith.Collection != "relics" {
+			t.Errorf("unexpected Lith A1 Intact %+v", lith)
+		}
+		if axi := found["Axi B2 Intact"]; axi == nil || axi.UniqueName != "/Lotus/Types/Game/Projections/T4Intact" {
+			t.Errorf("expected Axi B2 Intact from the relics collection, got %+v", axi)
+		}
+	})
+
+	t.Run("FindRelicsByNames with no names returns an empty map", func(t *testing.T) {
+		repo := newRepo(t, contractItemSeed)
+
+		found, err := repo.FindRelicsByNames(ctx, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if found == nil || len(found) != 0 {
+			t.Errorf("expected an empty map, got %v", found)
+		}
+	})
+}
+
+// RunItemExportContract runs the item export contract against the
+// implementation returned by newRepo.
+func RunItemExportContract(t *testing.T, newRepo ItemExportFactory) {
+	ctx := context.Background()
+	seed := ItemSeed{
+		"mods": `[
+			{"uniqueName": "/Lotus/Mods/Serration", "name": "Serration"},
+			{"uniqueName": "/Lotus/Mods/Continuity", "name": "Continuity"},
+			{"name": "No Unique Name"},
+			{"uniqueName": "/Lotus/Mods/Vitality", "name": "Vitality"},
+			{"uniqueName": "/Lotus/Mods/Flow", "name": "Flow"}
+		]`,
+		"resources": `[{"uniqueName": "/Lotus/Resources/Alloy", "name": "Alloy Plate"}]`,
+	}
+	collect := func(t *testing.T, repo repository.ItemExportInterface, skip, limit int) []string {
+		t.Helper()
+		var names []string
+		err := repo.ForEachInCategory(ctx, "mods", skip, limit, func(item models.Item) error {
+			if item.Collection != "mods" {
+				t.Errorf("expected collection mods, got %q", item.Collection)
+			}
+			names = append(names, item.Name)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return names
+	}
+
+	t.Run("CountCategory counts items with a uniqueName", func(t *testing.T) {
+		repo := newRepo(t, seed)
+
+		count, err := repo.CountCategory(ctx, "mods")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if count != 4 {
+			t.Errorf("expected 4 mods, got %d", count)
+		}
+		if count, err := repo.CountCategory(ctx, "arcanes"); err != nil || count != 0 {
+			t.Errorf("expected an empty category to count 0, got %d, %v", count, err)
+		}
+	})
+
+	t.Run("ForEachInCategory visits items in uniqueName order", func(t *testing.T) {
+		repo := newRepo(t, seed)
+
+		names := collect(t, repo, 0, 0)
+		if expected := []string{"Continuity", "Flow", "Serration", "Vitality"}; !slices.Equal(names, expected) {
+			t.Errorf("expected %v, got %v", expected, names)
+		}
+	})
+
+	t.Run("ForEachInCategory skips and limits", func(t *testing.T) {
+		repo := newRepo(t, seed)
+
+		if names := collect(t, repo, 1, 2); !slices.Equal(names, []string{"Flow", "Serration"}) {
+			t.Errorf("expected the second and third mods, got %v", names)
+		}
+		if names := collect(t, repo, 3, 10); !slices.Equal(names, []string{"Vitality"}) {
+			t.Errorf("expected the last mod, got %v", names)
+		}
+		if names := collect(t, repo, 4, 0); len(names) != 0 {
+			t.Errorf("expected nothing past the end, got %v", names)
+		}
+	})
+
+	t.Run("ForEachInCategory stops at the first callback error", func(t *testing.T) {
+		repo := newRepo(t, seed)
+
+		stop := errors.New("stop")
+		visited := 0
+		err := repo.ForEachInCategory(ctx, "mods", 0, 0, func(item models.Item) error {
+			visited++
+			return stop
+		})
+		if !errors.Is(err, stop) || visited != 1 {
+			t.Errorf("expected to stop after one item with the callback error, got %v after %d", err, visited)
+		}
+	})
+}
+
+// itemGraphSeed is a three-level recipe: the frame needs a chassis (its own
+// item, needing plates and a chassis blueprint) and an embedded systems
+// component whose cell is only listed inside it. The plate and the chassis
+// blueprint are stored twice, to check the copy FindByUniqueNames would pick
+// is the one in the tree.
+var itemGraphSeed = ItemSeed{
+	"warframes": `[
+		{"uniqueName": "/Lotus/Powersuits/Alpha", "name": "Alpha Frame", "components": [
+			{"uniqueName": "/Lotus/Parts/Chassis", "name": "Chassis", "itemCount": 1},
+			{"uniqueName": "/Lotus/Parts/Systems", "name": "Systems", "itemCount": 1, "components": [
+				{"uniqueName": "/Lotus/Resources/Cell", "name": "Cell", "itemCount": 2}
+			]},
+			{"uniqueName": "/Lotus/Resources/Missing", "name": "Missing", "itemCount": 1}
+		]},
+		{"uniqueName": "/Lotus/Powersuits/Beta", "name": "Beta Frame", "components": [
+			{"uniqueName": "/Lotus/Resources/Salvage", "name": "Salvage", "itemCount": 1}
+		]}
+	]`,
+	"misc": `[
+		{"uniqueName": "/Lotus/Parts/Chassis", "name": "Chassis", "buildQuantity": 1, "components": [
+			{"uniqueName": "/Lotus/Resources/Plate", "name": "Plate", "itemCount": 50},
+			{"uniqueName": "/Lotus/Parts/ChassisBlueprint", "name": "Blueprint", "itemCount": 1}
+		]},
+		{"uniqueName": "/Lotus/Parts/ChassisBlueprint", "name": "Blueprint"},
+		{"uniqueName": "/Lotus/Parts/Systems", "name": "Systems", "skipBuildTimePrice": 10}
+	]`,
+	"resources": `[
+		{"uniqueName": "/Lotus/Resources/Plate", "name": "Plate"},
+		{"uniqueName": "/Lotus/Resources/Cell", "name": "Cell"},
+		{"uniqueName": "/Lotus/Resources/Salvage", "name": "Salvage"},
+		{"uniqueName": "/Lotus/Parts/ChassisBlueprint", "name": "Resource Blueprint"}
+	]`,
+	"node": `[{"uniqueName": "/Lotus/Resources/Plate", "name": "Plate Node"}]`,
+}
+
+// RunItemGraphContract runs the item graph contract against the
+// implementation returned by newRepo.
+func RunItemGraphContract(t *testing.T, newRepo ItemGraphFactory) {
+	ctx := context.Background()
+
+	t.Run("FindComponentTree returns every reachable item", func(t *testing.T) {
+		repo := newRepo(t, itemGraphSeed)
+
+		tree, err := repo.FindComponentTree(ctx, []string{"/Lotus/Powersuits/Alpha"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expected := map[string]string{
+			"/Lotus/Powersuits/Alpha":       "warframes",
+			"/Lotus/Parts/Chassis":          "misc",
+			"/Lotus/Parts/ChassisBlueprint": "misc",
+			"/Lotus/Parts/Systems":          "misc",
+			"/Lotus/Resources/Plate":        "resources",
+			"/Lotus/Resources/Cell":         "resources",
+		}
+		if len(tree) != len(expected) {
+			t.Fatalf("expected %d items, got %d: %v", len(expected), len(tree), tree)
+		}
+		for uniqueName, collection := range expected {
+			item := tree[uniqueName]
+			if item == nil {
+				t.Errorf("expected %s in the tree", uniqueName)
+				continue
+			}
+			if item.Collection != collection {
+				t.Errorf("expected %s from %s, got %s", uniqueName, collection, item.Collection)
+			}
+		}
+		if blueprint := tree["/Lotus/Parts/ChassisBlueprint"]; blueprint != nil && blueprint.Name != "Blueprint" {
+			t.Errorf("expected the misc copy of the blueprint, got %+v", blueprint)
+		}
+		if plate := tree["/Lotus/Resources/Plate"]; plate != nil && plate.Name != "Plate" {
+			t.Errorf("expected the resources copy of the plate, got %+v", plate)
+		}
+		if chassis := tree["/Lotus/Parts/Chassis"]; chassis != nil && (chassis.BuildQuantity != 1 || len(chassis.Components) != 2) {
+			t.Errorf("expected the full chassis item, got %+v", chassis)
+		}
+		if systems := tree["/Lotus/Parts/Systems"]; systems != nil && systems.SkipBuildTimePrice != 10 {
+			t.Errorf("expected the systems item, got %+v", systems)
+		}
+	})
+
+	t.Run("FindComponentTree merges the trees of several items", func(t *testing.T) {
+		repo := newRepo(t, itemGraphSeed)
+
+		tree, err := repo.FindComponentTree(ctx, []string{"/Lotus/Parts/Chassis", "/Lotus/Powersuits/Beta", "/Lotus/Missing"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		for _, uniqueName := range []string{"/Lotus/Parts/Chassis", "/Lotus/Parts/ChassisBlueprint", "/Lotus/Resources/Plate", "/Lotus/Powersuits/Beta", "/Lotus/Resources/Salvage"} {
+			if tree[uniqueName] == nil {
+				t.Errorf("expected %s in the tree", uniqueName)
+			}
+		}
+		if len(tree) != 5 {
+			t.Errorf("expected 5 items, got %d: %v", len(tree), tree)
+		}
+	})
+
+	t.Run("FindComponentTree with no names returns an empty map", func(t *testing.T) {
+		repo := newRepo(t, itemGraphSeed)
+
+		tree, err := repo.FindComponentTree(ctx, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if tree == nil || len(tree) != 0 {
+			t.Errorf("expected an empty map, got %v", tree)
+		}
+	})
+}
@@ -0,0 +1,127 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+// RunMasteryRepositoryContract runs the mastery repository contract against
+// the implementation returned by newRepo.
+func RunMasteryRepositoryContract(t *testing.T, newRepo MasteryRepositoryFactory) {
+	ctx := context.Background()
+	const userID = "contract-user"
+
+	t.Run("ListByUser returns an empty slice for a new user", func(t *testing.T) {
+		repo := newRepo(t)
+
+		entries, err := repo.ListByUser(ctx, userID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if entries == nil || len(entries) != 0 {
+			t.Errorf("expected empty non-nil slice, got %#v", entries)
+		}
+	})
+
+	t.Run("SetStatuses sets, updates and removes entries", func(t *testing.T) {
+		repo := newRepo(t)
+
+		if err := repo.SetStatuses(ctx, userID, map[string]string{"/Excalibur": models.MasteryStatusBuilt, "/Braton": models.MasteryStatusBuilt}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := repo.SetStatuses(ctx, userID, map[string]string{"/Excalibur": models.MasteryStatusMastered, "/Braton": ""}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		entries, err := repo.ListByUser(ctx, userID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(entries) != 1 {
+			t.Fatalf("expected 1 entry, got %d", len(entries))
+		}
+		if entries[0].UniqueName != "/Excalibur" || entries[0].Status != models.MasteryStatusMastered {
+			t.Errorf("expected /Excalibur mastered, got %+v", entries[0])
+		}
+		if entries[0].UpdatedAt.IsZero() {
+			t.Error("expected updatedAt to be set")
+		}
+	})
+
+	t.Run("Get returns the entry or nil", func(t *testing.T) {
+		repo := newRepo(t)
+
+		if err := repo.SetStatuses(ctx, userID, map[string]string{"/Excalibur": models.MasteryStatusBuilt}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		entry, err := repo.Get(ctx, userID, "/Excalibur")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if entry == nil || entry.Status != models.MasteryStatusBuilt {
+			t.Errorf("expected /Excalibur built, got %+v", entry)
+		}
+		missing, err := repo.Get(ctx, userID, "/Braton")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if missing != nil {
+			t.Errorf("expected nil for an item not in the profile, got %+v", missing)
+		}
+		other, err := repo.Get(ctx, "other-user", "/Excalibur")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if other != nil {
+			t.Errorf("expected users not to share profiles, got %+v", other)
+		}
+	})
+
+	t.Run("ListByUser sorts by uniqueName", func(t *testing.T) {
+		repo := newRepo(t)
+
+		if err := repo.SetStatuses(ctx, userID, map[string]string{"/C": models.MasteryStatusBuilt, "/A": models.MasteryStatusBuilt, "/B": models.MasteryStatusBuilt}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		entries, err := repo.ListByUser(ctx, userID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(entries) != 3 || entries[0].UniqueName != "/A" || entries[1].UniqueName != "/B" || entries[2].UniqueName != "/C" {
+			t.Errorf("expected entries sorted by uniqueName, got %+v", entries)
+		}
+	})
+
+	t.Run("ClearAll removes every entry for the user", func(t *testing.T) {
+		repo := newRepo(t)
+
+		if err := repo.SetStatuses(ctx, userID, map[string]string{"/Excalibur": models.MasteryStatusBuilt}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := repo.SetStatuses(ctx, "other-user", map[string]string{"/Excalibur": models.MasteryStatusBuilt}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := repo.ClearAll(ctx, userID); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		entries, err := repo.ListByUser(ctx, userID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(entries) != 0 {
+			t.Errorf("expected no entries after clear, got %+v", entries)
+		}
+		other, err := repo.ListByUser(ctx, "other-user")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(other) != 1 {
+			t.Errorf("expected other user's profile to be kept, got %+v", other)
+		}
+	})
+}
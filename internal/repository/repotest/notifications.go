@@ -0,0 +1,244 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RunNotificationRepositoryContract runs the notification repository
+// contract against the implementation returned by newRepo.
+func RunNotificationRepositoryContract(t *testing.T, newRepo NotificationRepositoryFactory) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	newChannel := func(userID string, createdAt time.Time) *models.NotificationChannel {
+		return &models.NotificationChannel{
+			UserID: userID, Type: models.NotificationWebhook, URL: "https://example.com/hook", Secret: "secret",
+			Events: []string{models.NotificationFoundryFinished}, CreatedAt: createdAt,
+		}
+	}
+	newDelivery := func(userID string, channelID primitive.ObjectID, key string, due time.Time) *models.NotificationDelivery {
+		return &models.NotificationDelivery{
+			UserID: userID, ChannelID: channelID, Event: models.NotificationFoundryFinished, Key: key,
+			Payload: `{"event":"foundry.finished"}`, Status: models.DeliveryPending, NextAttemptAt: due, CreatedAt: due,
+		}
+	}
+
+	t.Run("channels are listed per user oldest first", func(t *testing.T) {
+		repo := newRepo(t)
+
+		empty, err := repo.ListChannels(ctx, "user-1")
+		if err != nil || empty == nil || len(empty) != 0 {
+			t.Fatalf("expected an empty slice, got %v (err %v)", empty, err)
+		}
+
+		later := newChannel("user-1", now)
+		earlier := newChannel("user-1", now.Add(-time.Hour))
+		for _, channel := range []*models.NotificationChannel{later, earlier, newChannel("user-2", now)} {
+			if err := repo.CreateChannel(ctx, channel); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if channel.ID.IsZero() {
+				t.Fatal("expected CreateChannel to set the ID")
+			}
+		}
+
+		channels, err := repo.ListChannels(ctx, "user-1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(channels) != 2 || channels[0].ID != earlier.ID || channels[1].ID != later.ID {
+			t.Fatalf("expected the user's two channels oldest first, got %+v", channels)
+		}
+		if channels[0].Secret != "secret" || len(channels[0].Events) != 1 || channels[0].URL != "https://example.com/hook" {
+			t.Errorf("expected the channel to round-trip, got %+v", channels[0])
+		}
+
+		got, err := repo.GetChannel(ctx, later.ID)
+		if err != nil || got == nil || got.UserID != "user-1" {
+			t.Errorf("expected GetChannel to find the channel, got %+v (err %v)", got, err)
+		}
+		if missing, err := repo.GetChannel(ctx, primitive.NewObjectID()); err != nil || missing != nil {
+			t.Errorf("expected nil for a missing channel, got %+v (err %v)", missing, err)
+		}
+
+		users, err := repo.ListSubscribedUsers(ctx)
+		if err != nil || len(users) != 2 {
+			t.Errorf("expected both users once, got %v (err %v)", users, err)
+		}
+	})
+
+	t.Run("DeleteChannel removes only the user's own channel", func(t *testing.T) {
+		repo := newRepo(t)
+		channel := newChannel("user-1", now)
+		if err := repo.CreateChannel(ctx, channel); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if deleted, err := repo.DeleteChannel(ctx, "user-2", channel.ID); err != nil || deleted {
+			t.Errorf("expected another user's delete to miss, got %v (err %v)", deleted, err)
+		}
+		if deleted, err := repo.DeleteChannel(ctx, "user-1", channel.ID); err != nil || !deleted {
+			t.Fatalf("expected the channel to be deleted, got %v (err %v)", deleted, err)
+		}
+		if users, _ := repo.ListSubscribedUsers(ctx); len(users) != 0 {
+			t.Errorf("expected no subscribed users left, got %v", users)
+		}
+	})
+
+	t.Run("EnqueueDelivery stores each key once per channel", func(t *testing.T) {
+		repo := newRepo(t)
+		channelID, otherChannelID := primitive.NewObjectID(), primitive.NewObjectID()
+
+		first := newDelivery("user-1", channelID, "foundry:1", now)
+		if stored, err := repo.EnqueueDelivery(ctx, first); err != nil || !stored || first.ID.IsZero() {
+			t.Fatalf("expected the delivery to be stored with an ID, got %v (err %v)", stored, err)
+		}
+		if stored, err := repo.EnqueueDelivery(ctx, newDelivery("user-1", channelID, "foundry:1", now)); err != nil || stored {
+			t.Errorf("expected a repeated key to be skipped, got %v (err %v)", stored, err)
+		}
+		if stored, err := repo.EnqueueDelivery(ctx, newDelivery("user-1", otherChannelID, "foundry:1", now)); err != nil || !stored {
+			t.Errorf("expected the key to be stored for another channel, got %v (err %v)", stored, err)
+		}
+	})
+
+	t.Run("ClaimDueDeliveries leases due pending deliveries", func(t *testing.T) {
+		repo := newRepo(t)
+		channelID := primitive.NewObjectID()
+
+		overdue := newDelivery("user-1", channelID, "a", now.Add(-time.Minute))
+		due := newDelivery("user-1", channelID, "b", now)
+		future := newDelivery("user-1", channelID, "c", now.Add(time.Minute))
+		for _, delivery := range []*models.NotificationDelivery{due, future, overdue} {
+			if _, err := repo.EnqueueDelivery(ctx, delivery); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		claimed, err := repo.ClaimDueDeliveries(ctx, now, time.Minute, 1)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(claimed) != 1 || claimed[0].ID != overdue.ID {
+			t.Fatalf("expected the overdue delivery first, got %+v", claimed)
+		}
+
+		claimed, err = repo.ClaimDueDeliveries(ctx, now, time.Minute, 10)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(claimed) != 1 || claimed[0].ID != due.ID || claimed[0].Payload != due.Payload {
+			t.Fatalf("expected only the due delivery, the overdue one being leased, got %+v", claimed)
+		}
+
+		if again, err := repo.ClaimDueDeliveries(ctx, now, time.Minute, 10); err != nil || len(again) != 0 {
+			t.Errorf("expected nothing due while leased, got %+v (err %v)", again, err)
+		}
+		if afterLease, err := repo.ClaimDueDeliveries(ctx, now.Add(time.Minute), time.Minute, 10); err != nil || len(afterLease) != 3 {
+			t.Errorf("expected all three due once the lease ends, got %+v (err %v)", afterLease, err)
+		}
+	})
+
+	t.Run("UpdateDelivery saves the outcome", func(t *testing.T) {
+		repo := newRepo(t)
+		delivery := newDelivery("user-1", primitive.NewObjectID(), "a", now)
+		if _, err := repo.EnqueueDelivery(ctx, delivery); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		deliveredAt := now.Add(time.Second)
+		delivery.Status = models.DeliveryDelivered
+		delivery.Attempts = 2
+		delivery.ResponseStatus = 204
+		delivery.LastError = "earlier failure"
+		delivery.DeliveredAt = &deliveredAt
+		if err := repo.UpdateDelivery(ctx, delivery); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		deliveries, err := repo.ListDeliveries(ctx, "user-1", 10)
+		if err != nil || len(deliveries) != 1 {
+			t.Fatalf("expected the delivery, got %+v (err %v)", deliveries, err)
+		}
+		got := deliveries[0]
+		if got.Status != models.DeliveryDelivered || got.Attempts != 2 || got.ResponseStatus != 204 || got.LastError != "earlier failure" ||
+			got.DeliveredAt == nil || !got.DeliveredAt.Equal(deliveredAt) {
+			t.Errorf("expected the outcome to round-trip, got %+v", got)
+		}
+		if claimed, _ := repo.ClaimDueDeliveries(ctx, now.Add(time.Hour), time.Minute, 10); len(claimed) != 0 {
+			t.Errorf("expected a delivered delivery never to be claimed, got %+v", claimed)
+		}
+
+		if err := repo.UpdateDelivery(ctx, &models.NotificationDelivery{ID: primitive.NewObjectID(), Status: models.DeliveryFailed}); err != nil {
+			t.Errorf("expected updating a missing delivery to be a no-op, got %v", err)
+		}
+	})
+
+	t.Run("ListDeliveries returns the user's latest first", func(t *testing.T) {
+		repo := newRepo(t)
+		channelID := primitive.NewObjectID()
+		for i, key := range []string{"a", "b", "c"} {
+			if _, err := repo.EnqueueDelivery(ctx, newDelivery("user-1", channelID, key, now.Add(time.Duration(i)*time.Minute))); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		if _, err := repo.EnqueueDelivery(ctx, newDelivery("user-2", primitive.NewObjectID(), "a", now)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		deliveries, err := repo.ListDeliveries(ctx, "user-1", 2)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(deliveries) != 2 || deliveries[0].Key != "c" || deliveries[1].Key != "b" {
+			t.Errorf("expected the two newest deliveries, got %+v", deliveries)
+		}
+		if empty, err := repo.ListDeliveries(ctx, "user-3", 10); err != nil || empty == nil || len(empty) != 0 {
+			t.Errorf("expected an empty slice, got %v (err %v)", empty, err)
+		}
+	})
+
+	t.Run("milestones are saved, replaced and deleted per user", func(t *testing.T) {
+		repo := newRepo(t)
+
+		if state, err := repo.GetMilestones(ctx, "user-1"); err != nil || state != nil {
+			t.Fatalf("expected nil before any save, got %+v (err %v)", state, err)
+		}
+
+		first := &models.MilestoneState{UserID: "user-1", Reached: map[string]time.Time{models.MilestoneMaterialsHalf: now}, CheckedAt: now}
+		if err := repo.SaveMilestones(ctx, first); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := repo.SaveMilestones(ctx, &models.MilestoneState{UserID: "user-2", CheckedAt: now}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		state, err := repo.GetMilestones(ctx, "user-1")
+		if err != nil || state == nil || !state.Reached[models.MilestoneMaterialsHalf].Equal(now) || !state.CheckedAt.Equal(now) {
+			t.Fatalf("expected the state to round-trip, got %+v (err %v)", state, err)
+		}
+
+		later := now.Add(time.Hour)
+		replaced := &models.MilestoneState{UserID: "user-1", Reached: map[string]time.Time{models.MilestoneLastFoundryRun: later}, CheckedAt: later}
+		if err := repo.SaveMilestones(ctx, replaced); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		state, err = repo.GetMilestones(ctx, "user-1")
+		if err != nil || state == nil || len(state.Reached) != 1 || !state.Reached[models.MilestoneLastFoundryRun].Equal(later) {
+			t.Fatalf("expected the state to be replaced, got %+v (err %v)", state, err)
+		}
+
+		if err := repo.DeleteMilestones(ctx, "user-1"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if state, err := repo.GetMilestones(ctx, "user-1"); err != nil || state != nil {
+			t.Errorf("expected the state to be deleted, got %+v (err %v)", state, err)
+		}
+		if state, err := repo.GetMilestones(ctx, "user-2"); err != nil || state == nil || state.Reached == nil {
+			t.Errorf("expected the other user's state with an empty map, got %+v (err %v)", state, err)
+		}
+	})
+}
@@ -0,0 +1,196 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+)
+
+// RunOwnedBlueprintsRepositoryContract runs the owned blueprints repository
+// contract against the implementation returned by newRepo.
+func RunOwnedBlueprintsRepositoryContract(t *testing.T, newRepo OwnedBlueprintsRepositoryFactory) {
+	ctx := context.Background()
+	const userID = "contract-user"
+
+	get := func(t *testing.T, repo repository.OwnedBlueprintsRepositoryInterface) *models.OwnedBlueprints {
+		t.Helper()
+		owned, err := repo.GetByUserID(ctx, userID)
+		if err != nil {
+			t.Fatalf("GetByUserID: unexpected error: %v", err)
+		}
+		return owned
+	}
+
+	t.Run("GetByUserID returns nil for missing document", func(t *testing.T) {
+		repo := newRepo(t)
+
+		if owned := get(t, repo); owned != nil {
+			t.Errorf("expected nil, got %+v", owned)
+		}
+	})
+
+	t.Run("Create stores an empty non-nil blueprint list", func(t *testing.T) {
+		repo := newRepo(t)
+
+		created := &models.OwnedBlueprints{UserID: userID}
+		if err := repo.Create(ctx, created); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if created.ID.IsZero() {
+			t.Error("expected Create to assign an ID")
+		}
+
+		owned := get(t, repo)
+		if owned == nil || owned.Blueprints == nil || len(owned.Blueprints) != 0 {
+			t.Errorf("expected empty non-nil blueprints, got %+v", owned)
+		}
+		if owned.Components == nil || len(owned.Components) != 0 {
+			t.Errorf("expected empty non-nil components, got %+v", owned.Components)
+		}
+	})
+
+	t.Run("updates on a missing document are no-ops", func(t *testing.T) {
+		repo := newRepo(t)
+
+		if err := repo.AddBlueprint(ctx, userID, models.OwnedBlueprint{UniqueName: "/Lotus/A"}); err != nil {
+			t.Fatalf("AddBlueprint: unexpected error: %v", err)
+		}
+		if err := repo.RemoveBlueprint(ctx, userID, "/Lotus/A"); err != nil {
+			t.Fatalf("RemoveBlueprint: unexpected error: %v", err)
+		}
+		if err := repo.ClearAll(ctx, userID); err != nil {
+			t.Fatalf("ClearAll: unexpected error: %v", err)
+		}
+		if err := repo.SetComponent(ctx, userID, models.OwnedComponent{UniqueName: "/Lotus/Chassis", Count: 1}); err != nil {
+			t.Fatalf("SetComponent: unexpected error: %v", err)
+		}
+		if err := repo.RemoveComponent(ctx, userID, "/Lotus/Chassis"); err != nil {
+			t.Fatalf("RemoveComponent: unexpected error: %v", err)
+		}
+		if err := repo.ClearComponents(ctx, userID); err != nil {
+			t.Fatalf("ClearComponents: unexpected error: %v", err)
+		}
+
+		if owned := get(t, repo); owned != nil {
+			t.Errorf("expected no document to be created, got %+v", owned)
+		}
+	})
+
+	t.Run("BulkAddBlueprints upserts and keeps duplicates", func(t *testing.T) {
+		repo := newRepo(t)
+
+		err := repo.BulkAddBlueprints(ctx, userID, []models.OwnedBlueprint{{UniqueName: "/Lotus/A"}, {UniqueName: "/Lotus/B"}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		err = repo.BulkAddBlueprints(ctx, userID, []models.OwnedBlueprint{{UniqueName: "/Lotus/A"}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		owned := get(t, repo)
+		if owned == nil {
+			t.Fatal("expected document to be created")
+		}
+		if len(owned.Blueprints) != 3 {
+			t.Errorf("expected 3 blueprints, got %d", len(owned.Blueprints))
+		}
+		if owned.SchemaVersion != repository.CurrentOwnedBlueprintsSchemaVersion {
+			t.Errorf("expected schema version %d, got %d", repository.CurrentOwnedBlueprintsSchemaVersion, owned.SchemaVersion)
+		}
+		if owned.CreatedAt.IsZero() || owned.ID.IsZero() {
+			t.Error("expected ID and createdAt to be set on upsert")
+		}
+	})
+
+	t.Run("RemoveBlueprint and ClearAll leave an empty list", func(t *testing.T) {
+		repo := newRepo(t)
+
+		repo.BulkAddBlueprints(ctx, userID, []models.OwnedBlueprint{{UniqueName: "/Lotus/A"}, {UniqueName: "/Lotus/A"}, {UniqueName: "/Lotus/B"}})
+		if err := repo.RemoveBlueprint(ctx, userID, "/Lotus/A"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		owned := get(t, repo)
+		if len(owned.Blueprints) != 1 || owned.Blueprints[0].UniqueName != "/Lotus/B" {
+			t.Errorf("expected only /Lotus/B to remain, got %+v", owned.Blueprints)
+		}
+
+		if err := repo.ClearAll(ctx, userID); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		owned = get(t, repo)
+		if owned == nil || owned.Blueprints == nil || len(owned.Blueprints) != 0 {
+			t.Errorf("expected empty non-nil blueprints, got %+v", owned)
+		}
+	})
+	t.Run("SetComponent adds or replaces a count and leaves blueprints alone", func(t *testing.T) {
+		repo := newRepo(t)
+
+		repo.BulkAddBlueprints(ctx, userID, []models.OwnedBlueprint{{UniqueName: "/Lotus/A"}})
+		if err := repo.SetComponent(ctx, userID, models.OwnedComponent{UniqueName: "/Lotus/Chassis", Count: 1}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		repo.SetComponent(ctx, userID, models.OwnedComponent{UniqueName: "/Lotus/Systems", Count: 1})
+		if err := repo.SetComponent(ctx, userID, models.OwnedComponent{UniqueName: "/Lotus/Chassis", Count: 3}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		owned := get(t, repo)
+		if len(owned.Components) != 2 || owned.Components[0].UniqueName != "/Lotus/Chassis" || owned.Components[0].Count != 3 {
+			t.Errorf("expected the chassis count replaced in place, got %+v", owned.Components)
+		}
+		if len(owned.Blueprints) != 1 {
+			t.Errorf("expected the blueprints untouched, got %+v", owned.Blueprints)
+		}
+	})
+
+	t.Run("SetBlueprint adds or replaces a quantity and leaves components alone", func(t *testing.T) {
+		repo := newRepo(t)
+
+		repo.BulkAddBlueprints(ctx, userID, []models.OwnedBlueprint{{UniqueName: "/Lotus/A", Quantity: 1}})
+		repo.SetComponent(ctx, userID, models.OwnedComponent{UniqueName: "/Lotus/Chassis", Count: 1})
+		if err := repo.SetBlueprint(ctx, userID, models.OwnedBlueprint{UniqueName: "/Lotus/FormaBlueprint", Quantity: 2}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := repo.SetBlueprint(ctx, userID, models.OwnedBlueprint{UniqueName: "/Lotus/FormaBlueprint", Quantity: 5}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		owned := get(t, repo)
+		if len(owned.Blueprints) != 2 || owned.Blueprints[1].UniqueName != "/Lotus/FormaBlueprint" || owned.Blueprints[1].Quantity != 5 {
+			t.Errorf("expected the stock replaced in place, got %+v", owned.Blueprints)
+		}
+		if len(owned.Components) != 1 {
+			t.Errorf("expected the components untouched, got %+v", owned.Components)
+		}
+	})
+
+	t.Run("RemoveComponent and ClearComponents leave an empty list", func(t *testing.T) {
+		repo := newRepo(t)
+
+		repo.BulkAddBlueprints(ctx, userID, []models.OwnedBlueprint{{UniqueName: "/Lotus/A"}})
+		repo.SetComponent(ctx, userID, models.OwnedComponent{UniqueName: "/Lotus/Chassis", Count: 1})
+		repo.SetComponent(ctx, userID, models.OwnedComponent{UniqueName: "/Lotus/Systems", Count: 1})
+		if err := repo.RemoveComponent(ctx, userID, "/Lotus/Chassis"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		owned := get(t, repo)
+		if len(owned.Components) != 1 || owned.Components[0].UniqueName != "/Lotus/Systems" {
+			t.Errorf("expected only /Lotus/Systems to remain, got %+v", owned.Components)
+		}
+
+		if err := repo.ClearComponents(ctx, userID); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		owned = get(t, repo)
+		if owned.Components == nil || len(owned.Components) != 0 || len(owned.Blueprints) != 1 {
+			t.Errorf("expected only the components cleared, got %+v", owned)
+		}
+	})
+}
@@ -0,0 +1,111 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+)
+
+// RunOwnedMaterialsRepositoryContract runs the owned materials repository
+// contract against the implementation returned by newRepo.
+func RunOwnedMaterialsRepositoryContract(t *testing.T, newRepo OwnedMaterialsRepositoryFactory) {
+	ctx := context.Background()
+	const userID = "contract-user"
+
+	t.Run("GetByUserID returns an empty slice for a new user", func(t *testing.T) {
+		repo := newRepo(t)
+
+		materials, err := repo.GetByUserID(ctx, userID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if materials == nil || len(materials) != 0 {
+			t.Errorf("expected empty non-nil slice, got %#v", materials)
+		}
+	})
+
+	t.Run("SetCounts sets, updates and removes counts", func(t *testing.T) {
+		repo := newRepo(t)
+
+		if err := repo.SetCounts(ctx, userID, map[string]int{"/Ferrite": 500, "/Plastids": 20}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := repo.SetCounts(ctx, userID, map[string]int{"/Ferrite": 750, "/Plastids": 0}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		materials, err := repo.GetByUserID(ctx, userID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(materials) != 1 {
+			t.Fatalf("expected 1 material, got %d", len(materials))
+		}
+		if materials[0].UniqueName != "/Ferrite" || materials[0].Count != 750 {
+			t.Errorf("expected /Ferrite x750, got %+v", materials[0])
+		}
+		if materials[0].UpdatedAt.IsZero() {
+			t.Error("expected updatedAt to be set")
+		}
+	})
+
+	t.Run("GetByUserID sorts by uniqueName", func(t *testing.T) {
+		repo := newRepo(t)
+
+		if err := repo.SetCounts(ctx, userID, map[string]int{"/C": 1, "/A": 1, "/B": 1}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		materials, err := repo.GetByUserID(ctx, userID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(materials) != 3 || materials[0].UniqueName != "/A" || materials[1].UniqueName != "/B" || materials[2].UniqueName != "/C" {
+			t.Errorf("expected materials sorted by uniqueName, got %+v", materials)
+		}
+	})
+
+	t.Run("users do not share inventories", func(t *testing.T) {
+		repo := newRepo(t)
+
+		if err := repo.SetCounts(ctx, userID, map[string]int{"/Ferrite": 10}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		materials, err := repo.GetByUserID(ctx, "other-user")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(materials) != 0 {
+			t.Errorf("expected other user to have no materials, got %+v", materials)
+		}
+	})
+
+	t.Run("ClearAll removes every material for the user", func(t *testing.T) {
+		repo := newRepo(t)
+
+		if err := repo.SetCounts(ctx, userID, map[string]int{"/Ferrite": 10, "/Plastids": 5}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := repo.SetCounts(ctx, "other-user", map[string]int{"/Ferrite": 3}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := repo.ClearAll(ctx, userID); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		materials, err := repo.GetByUserID(ctx, userID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(materials) != 0 {
+			t.Errorf("expected no materials after clear, got %+v", materials)
+		}
+		other, err := repo.GetByUserID(ctx, "other-user")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(other) != 1 {
+			t.Errorf("expected other user's inventory to be kept, got %+v", other)
+		}
+	})
+}
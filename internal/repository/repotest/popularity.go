@@ -0,0 +1,122 @@
+package repotest
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+// RunPopularityContract runs the popularity contract against the
+// implementation returned by newRepo.
+func RunPopularityContract(t *testing.T, newRepo PopularityRepositoryFactory) {
+	ctx := context.Background()
+
+	seed := func(t *testing.T, repo PopularityRepository, userID string, uniqueNames ...string) {
+		t.Helper()
+		if err := repo.Create(ctx, &models.Wishlist{UserID: userID}); err != nil {
+			t.Fatalf("Create: unexpected error: %v", err)
+		}
+		for _, uniqueName := range uniqueNames {
+			if err := repo.AddItem(ctx, userID, models.WishlistItem{UniqueName: uniqueName, Quantity: 1}); err != nil {
+				t.Fatalf("AddItem: unexpected error: %v", err)
+			}
+		}
+	}
+
+	t.Run("TopWishlistedItems returns empty for no wishlists", func(t *testing.T) {
+		repo := newRepo(t)
+
+		popularity, err := repo.TopWishlistedItems(ctx, 10)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(popularity) != 0 {
+			t.Errorf("expected no results, got %+v", popularity)
+		}
+	})
+
+	t.Run("TopWishlistedItems orders by count then uniqueName", func(t *testing.T) {
+		repo := newRepo(t)
+		seed(t, repo, "user-1", "/Lotus/A", "/Lotus/B", "/Lotus/C")
+		seed(t, repo, "user-2", "/Lotus/B", "/Lotus/C")
+		seed(t, repo, "user-3", "/Lotus/C")
+
+		popularity, err := repo.TopWishlistedItems(ctx, 10)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := []models.ItemPopularity{
+			{UniqueName: "/Lotus/C", WishlistCount: 3},
+			{UniqueName: "/Lotus/B", WishlistCount: 2},
+			{UniqueName: "/Lotus/A", WishlistCount: 1},
+		}
+		if !reflect.DeepEqual(popularity, expected) {
+			t.Errorf("expected %+v, got %+v", expected, popularity)
+		}
+	})
+
+	t.Run("TopWishlistedItems counts each user once per item", func(t *testing.T) {
+		repo := newRepo(t)
+		seed(t, repo, "user-1", "/Lotus/A", "/Lotus/A")
+		seed(t, repo, "user-2", "/Lotus/B")
+
+		popularity, err := repo.TopWishlistedItems(ctx, 10)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := []models.ItemPopularity{
+			{UniqueName: "/Lotus/A", WishlistCount: 1},
+			{UniqueName: "/Lotus/B", WishlistCount: 1},
+		}
+		if !reflect.DeepEqual(popularity, expected) {
+			t.Errorf("expected %+v, got %+v", expected, popularity)
+		}
+	})
+
+	t.Run("TopWishlistedItems applies limit", func(t *testing.T) {
+		repo := newRepo(t)
+		seed(t, repo, "user-1", "/Lotus/A", "/Lotus/B", "/Lotus/C")
+		seed(t, repo, "user-2", "/Lotus/C")
+
+		popularity, err := repo.TopWishlistedItems(ctx, 2)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(popularity) != 2 {
+			t.Fatalf("expected 2 results, got %d", len(popularity))
+		}
+		if popularity[0].UniqueName != "/Lotus/C" || popularity[1].UniqueName != "/Lotus/A" {
+			t.Errorf("unexpected order: %+v", popularity)
+		}
+	})
+
+	t.Run("WishlistSizeStats is zero for no wishlists", func(t *testing.T) {
+		repo := newRepo(t)
+
+		stats, err := repo.WishlistSizeStats(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if *stats != (models.WishlistSizeStats{}) {
+			t.Errorf("expected zero stats, got %+v", stats)
+		}
+	})
+
+	t.Run("WishlistSizeStats counts wishlists and items", func(t *testing.T) {
+		repo := newRepo(t)
+		seed(t, repo, "user-1", "/Lotus/A", "/Lotus/B", "/Lotus/C")
+		seed(t, repo, "user-2", "/Lotus/A")
+		seed(t, repo, "user-3")
+
+		stats, err := repo.WishlistSizeStats(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := models.WishlistSizeStats{Wishlists: 3, TotalItems: 4}
+		if *stats != expected {
+			t.Errorf("expected %+v, got %+v", expected, *stats)
+		}
+	})
+}
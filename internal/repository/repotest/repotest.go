@@ -0,0 +1,132 @@
+// Package repotest holds contract test suites for the repository interfaces.
+// Every implementation (MongoDB, in-memory) runs the same suites so their
+// observable semantics stay identical: nil for missing documents, empty rather
+// than nil slices on stored documents, no-op updates against missing documents,
+// and duplicate handling left to the service layer.
+package repotest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+)
+
+// ItemSeed maps a collection name to a JSON array of raw item documents, in
+// the same shape as the WFCD data files.
+type ItemSeed map[string]string
+
+// ItemRepositoryFactory returns a repository containing exactly the seed data.
+type ItemRepositoryFactory func(t *testing.T, seed ItemSeed) repository.ItemRepositoryInterface
+
+// WishlistRepositoryFactory returns an empty wishlist repository.
+type WishlistRepositoryFactory func(t *testing.T) repository.WishlistRepositoryInterface
+
+// OwnedBlueprintsRepositoryFactory returns an empty owned blueprints repository.
+type OwnedBlueprintsRepositoryFactory func(t *testing.T) repository.OwnedBlueprintsRepositoryInterface
+
+// OwnedMaterialsRepositoryFactory returns an empty owned materials repository.
+type OwnedMaterialsRepositoryFactory func(t *testing.T) repository.OwnedMaterialsRepositoryInterface
+
+// MasteryRepositoryFactory returns an empty mastery repository.
+type MasteryRepositoryFactory func(t *testing.T) repository.MasteryRepositoryInterface
+
+// SettingsRepositoryFactory returns an empty settings repository.
+type SettingsRepositoryFactory func(t *testing.T) repository.SettingsRepositoryInterface
+
+// UserTraceRepositoryFactory returns an empty user trace repository.
+type UserTraceRepositoryFactory func(t *testing.T) repository.UserTraceRepositoryInterface
+
+// PopularityRepository is a wishlist store that can also report popularity,
+// so the suite can seed wishlists through the regular write path.
+type PopularityRepository interface {
+	repository.WishlistRepositoryInterface
+	repository.PopularityRepositoryInterface
+}
+
+// PopularityRepositoryFactory returns an empty wishlist repository that
+// reports popularity.
+type PopularityRepositoryFactory func(t *testing.T) PopularityRepository
+
+// SchemaMigrationStore holds user documents in an outdated shape and runs the
+// background schema migration over them. Only stores that version their
+// documents run the suite.
+type SchemaMigrationStore interface {
+	Wishlists() repository.WishlistRepositoryInterface
+	OwnedBlueprints() repository.OwnedBlueprintsRepositoryInterface
+	// InsertLegacyWishlist stores wishlist as schema version 0 wrote it.
+	InsertLegacyWishlist(ctx context.Context, wishlist *models.Wishlist) error
+	// InsertLegacyOwnedBlueprints stores ownedBlueprints as schema version 0
+	// wrote them.
+	InsertLegacyOwnedBlueprints(ctx context.Context, ownedBlueprints *models.OwnedBlueprints) error
+	// Migrate runs the background migration, calling afterRead between
+	// reading each outdated document and saving its migrated copy.
+	Migrate(ctx context.Context, afterRead func()) (repository.MigrationStats, error)
+}
+
+// SchemaMigrationStoreFactory returns an empty store.
+type SchemaMigrationStoreFactory func(t *testing.T) SchemaMigrationStore
+
+// HouseholdRepositoryFactory returns an empty household repository.
+type HouseholdRepositoryFactory func(t *testing.T) repository.HouseholdRepositoryInterface
+
+// GiftClaimRepositoryFactory returns an empty gift claim repository.
+type GiftClaimRepositoryFactory func(t *testing.T) repository.GiftClaimRepositoryInterface
+
+// IdempotencyRepositoryFactory returns an empty idempotency repository.
+type IdempotencyRepositoryFactory func(t *testing.T) repository.IdempotencyRepositoryInterface
+
+// ShareLinkRepositoryFactory returns an empty share link repository.
+type ShareLinkRepositoryFactory func(t *testing.T) repository.ShareLinkRepositoryInterface
+
+// ShareLinkReportRepositoryFactory returns an empty share link report
+// repository.
+type ShareLinkReportRepositoryFactory func(t *testing.T) repository.ShareLinkReportRepositoryInterface
+
+// ImportAliasRepositoryFactory returns an empty import alias repository.
+type ImportAliasRepositoryFactory func(t *testing.T) repository.ImportAliasRepositoryInterface
+
+// CustomItemRepositoryFactory returns an empty custom item repository.
+type CustomItemRepositoryFactory func(t *testing.T) repository.CustomItemRepositoryInterface
+
+// FoundryRepositoryFactory returns an empty foundry repository.
+type FoundryRepositoryFactory func(t *testing.T) repository.FoundryRepositoryInterface
+
+// WishlistTemplateRepositoryFactory returns an empty wishlist template
+// repository.
+type WishlistTemplateRepositoryFactory func(t *testing.T) repository.WishlistTemplateRepositoryInterface
+
+// NotificationRepositoryFactory returns an empty notification repository.
+type NotificationRepositoryFactory func(t *testing.T) repository.NotificationRepositoryInterface
+
+// IntegrationRepositoryFactory returns an empty integration repository.
+type IntegrationRepositoryFactory func(t *testing.T) repository.IntegrationRepositoryInterface
+
+// WorkspaceRepositoryFactory returns an empty workspace repository.
+type WorkspaceRepositoryFactory func(t *testing.T) repository.WorkspaceRepositoryInterface
+
+// WorkspaceContributionRepositoryFactory returns an empty workspace
+// contribution repository.
+type WorkspaceContributionRepositoryFactory func(t *testing.T) repository.WorkspaceContributionRepositoryInterface
+
+// ActivityRepositoryFactory returns an empty activity repository.
+type ActivityRepositoryFactory func(t *testing.T) repository.ActivityRepositoryInterface
+
+// ItemCatalogFactory returns an item catalog containing exactly the seed data.
+type ItemCatalogFactory func(t *testing.T, seed ItemSeed) repository.ItemCatalogInterface
+
+// ItemGraphFactory returns an item graph built from exactly the seed data.
+type ItemGraphFactory func(t *testing.T, seed ItemSeed) repository.ItemGraphInterface
+
+// ItemExportFactory returns an item export containing exactly the seed data.
+type ItemExportFactory func(t *testing.T, seed ItemSeed) repository.ItemExportInterface
+
+// RelicCatalogFactory returns a relic catalog containing exactly the seed data.
+type RelicCatalogFactory func(t *testing.T, seed ItemSeed) repository.RelicCatalogInterface
+
+// ItemChangeRepositoryFactory returns an empty item change repository.
+type ItemChangeRepositoryFactory func(t *testing.T) repository.ItemChangeRepositoryInterface
+
+// SyncStatusRepositoryFactory returns an empty sync status repository.
+type SyncStatusRepositoryFactory func(t *testing.T) repository.SyncStatusRepositoryInterface
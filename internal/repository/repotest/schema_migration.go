@@ -0,0 +1,157 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+)
+
+// RunSchemaMigrationContract runs the schema migration contract against the
+// store returned by newStore. Writes that land while a document is being
+// migrated must survive the migration.
+func RunSchemaMigrationContract(t *testing.T, newStore SchemaMigrationStoreFactory) {
+	ctx := context.Background()
+	const userID = "contract-user"
+	added := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	insertWishlist := func(t *testing.T, store SchemaMigrationStore, items ...models.WishlistItem) {
+		t.Helper()
+		wishlist := &models.Wishlist{UserID: userID, Items: items, CreatedAt: added}
+		if err := store.InsertLegacyWishlist(ctx, wishlist); err != nil {
+			t.Fatalf("InsertLegacyWishlist: unexpected error: %v", err)
+		}
+	}
+
+	insertOwnedBlueprints := func(t *testing.T, store SchemaMigrationStore, blueprints ...models.OwnedBlueprint) {
+		t.Helper()
+		owned := &models.OwnedBlueprints{UserID: userID, Blueprints: blueprints, CreatedAt: added}
+		if err := store.InsertLegacyOwnedBlueprints(ctx, owned); err != nil {
+			t.Fatalf("InsertLegacyOwnedBlueprints: unexpected error: %v", err)
+		}
+	}
+
+	migrate := func(t *testing.T, store SchemaMigrationStore, afterRead func()) repository.MigrationStats {
+		t.Helper()
+		stats, err := store.Migrate(ctx, afterRead)
+		if err != nil {
+			t.Fatalf("Migrate: unexpected error: %v", err)
+		}
+		return stats
+	}
+
+	// once runs write the first time a document is read, as a user request
+	// arriving in the middle of the migration would.
+	once := func(write func()) func() {
+		done := false
+		return func() {
+			if !done {
+				done = true
+				write()
+			}
+		}
+	}
+
+	itemNames := func(t *testing.T, store SchemaMigrationStore) map[string]int {
+		t.Helper()
+		wishlist, err := store.Wishlists().GetByUserID(ctx, userID)
+		if err != nil || wishlist == nil {
+			t.Fatalf("GetByUserID: expected wishlist, got %+v, %v", wishlist, err)
+		}
+		quantities := make(map[string]int)
+		for _, item := range wishlist.Items {
+			quantities[item.UniqueName] = item.Quantity
+		}
+		return quantities
+	}
+
+	t.Run("Migrate upgrades legacy documents once", func(t *testing.T) {
+		store := newStore(t)
+		insertWishlist(t, store, models.WishlistItem{UniqueName: "/Lotus/Forma"})
+		insertOwnedBlueprints(t, store, models.OwnedBlueprint{UniqueName: "/Lotus/FormaBlueprint", AddedAt: added})
+
+		stats := migrate(t, store, nil)
+		if stats.Wishlists != 1 || stats.OwnedBlueprints != 1 || stats.Failed != 0 {
+			t.Errorf("expected one wishlist and one owned blueprints migrated, got %+v", stats)
+		}
+		if quantities := itemNames(t, store); quantities["/Lotus/Forma"] != 1 {
+			t.Errorf("expected the legacy item to get quantity 1, got %v", quantities)
+		}
+
+		if stats := migrate(t, store, nil); stats != (repository.MigrationStats{}) {
+			t.Errorf("expected nothing left to migrate, got %+v", stats)
+		}
+	})
+
+	t.Run("Migrate keeps an item added during the migration", func(t *testing.T) {
+		store := newStore(t)
+		insertWishlist(t, store, models.WishlistItem{UniqueName: "/Lotus/Forma", Quantity: 2})
+
+		stats := migrate(t, store, once(func() {
+			item := models.WishlistItem{UniqueName: "/Lotus/Excalibur", Quantity: 1, AddedAt: time.Now()}
+			if err := store.Wishlists().AddItem(ctx, userID, item); err != nil {
+				t.Fatalf("AddItem: unexpected error: %v", err)
+			}
+		}))
+		if stats.Wishlists != 1 || stats.Failed != 0 {
+			t.Errorf("expected the wishlist to be migrated, got %+v", stats)
+		}
+
+		quantities := itemNames(t, store)
+		if len(quantities) != 2 || quantities["/Lotus/Forma"] != 2 || quantities["/Lotus/Excalibur"] != 1 {
+			t.Errorf("expected both items to survive the migration, got %v", quantities)
+		}
+	})
+
+	t.Run("Migrate keeps an item removed during the migration", func(t *testing.T) {
+		store := newStore(t)
+		insertWishlist(t, store,
+			models.WishlistItem{UniqueName: "/Lotus/Forma", Quantity: 1},
+			models.WishlistItem{UniqueName: "/Lotus/Excalibur", Quantity: 1},
+		)
+
+		migrate(t, store, once(func() {
+			if err := store.Wishlists().RemoveItem(ctx, userID, "/Lotus/Forma"); err != nil {
+				t.Fatalf("RemoveItem: unexpected error: %v", err)
+			}
+		}))
+
+		quantities := itemNames(t, store)
+		if len(quantities) != 1 || quantities["/Lotus/Excalibur"] != 1 {
+			t.Errorf("expected only the remaining item, got %v", quantities)
+		}
+	})
+
+	t.Run("Migrate keeps a blueprint added during the migration", func(t *testing.T) {
+		store := newStore(t)
+		insertOwnedBlueprints(t, store, models.OwnedBlueprint{UniqueName: "/Lotus/FormaBlueprint", AddedAt: added})
+
+		stats := migrate(t, store, once(func() {
+			blueprint := models.OwnedBlueprint{UniqueName: "/Lotus/ExcaliburBlueprint", Quantity: 1, AddedAt: time.Now()}
+			if err := store.OwnedBlueprints().AddBlueprint(ctx, userID, blueprint); err != nil {
+				t.Fatalf("AddBlueprint: unexpected error: %v", err)
+			}
+		}))
+		if stats.OwnedBlueprints != 1 || stats.Failed != 0 {
+			t.Errorf("expected the owned blueprints to be migrated, got %+v", stats)
+		}
+
+		owned, err := store.OwnedBlueprints().GetByUserID(ctx, userID)
+		if err != nil || owned == nil {
+			t.Fatalf("GetByUserID: expected owned blueprints, got %+v, %v", owned, err)
+		}
+		if len(owned.Blueprints) != 2 {
+			t.Fatalf("expected both blueprints to survive the migration, got %+v", owned.Blueprints)
+		}
+		for _, bp := range owned.Blueprints {
+			if bp.Quantity != 1 {
+				t.Errorf("expected %s to be migrated to quantity 1, got %d", bp.UniqueName, bp.Quantity)
+			}
+		}
+		if owned.Components == nil {
+			t.Error("expected the migration to add a components list")
+		}
+	})
+}
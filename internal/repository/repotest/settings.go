@@ -0,0 +1,180 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+// RunSettingsRepositoryContract runs the settings repository contract against
+// the implementation returned by newRepo.
+func RunSettingsRepositoryContract(t *testing.T, newRepo SettingsRepositoryFactory) {
+	ctx := context.Background()
+	const userID = "contract-user"
+
+	t.Run("GetByUserID returns nil for missing settings", func(t *testing.T) {
+		repo := newRepo(t)
+
+		settings, err := repo.GetByUserID(ctx, userID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if settings != nil {
+			t.Errorf("expected nil, got %+v", settings)
+		}
+	})
+
+	t.Run("Upsert creates and then updates", func(t *testing.T) {
+		repo := newRepo(t)
+
+		if err := repo.Upsert(ctx, &models.UserSettings{UserID: userID, TimeZone: "Europe/Berlin"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		created, err := repo.GetByUserID(ctx, userID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if created == nil || created.TimeZone != "Europe/Berlin" || created.ID.IsZero() || created.CreatedAt.IsZero() {
+			t.Fatalf("expected settings to be created, got %+v", created)
+		}
+
+		if err := repo.Upsert(ctx, &models.UserSettings{UserID: userID, TimeZone: "America/New_York"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		updated, err := repo.GetByUserID(ctx, userID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if updated.TimeZone != "America/New_York" {
+			t.Errorf("expected time zone to be updated, got '%s'", updated.TimeZone)
+		}
+		if updated.ID != created.ID || !updated.CreatedAt.Equal(created.CreatedAt) {
+			t.Error("expected upsert to keep ID and createdAt")
+		}
+	})
+
+	t.Run("Upsert replaces default quantity rules", func(t *testing.T) {
+		repo := newRepo(t)
+
+		rules := []models.DefaultQuantityRule{
+			{Category: "Gear", Type: "Specter", Quantity: 3},
+			{Category: "Warframes", Quantity: 1},
+		}
+		if err := repo.Upsert(ctx, &models.UserSettings{UserID: userID, TimeZone: "UTC", DefaultQuantities: rules}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		settings, err := repo.GetByUserID(ctx, userID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(settings.DefaultQuantities) != 2 || settings.DefaultQuantities[0] != rules[0] || settings.DefaultQuantities[1] != rules[1] {
+			t.Fatalf("expected rules to be stored in order, got %+v", settings.DefaultQuantities)
+		}
+
+		settings.DefaultQuantities[0].Quantity = 99
+		if err := repo.Upsert(ctx, &models.UserSettings{UserID: userID, TimeZone: "UTC"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		cleared, err := repo.GetByUserID(ctx, userID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(cleared.DefaultQuantities) != 0 {
+			t.Errorf("expected rules to be cleared, got %+v", cleared.DefaultQuantities)
+		}
+	})
+
+	t.Run("Upsert stores public wishlist flag", func(t *testing.T) {
+		repo := newRepo(t)
+
+		if err := repo.Upsert(ctx, &models.UserSettings{UserID: userID, TimeZone: "UTC", PublicWishlist: true}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		settings, err := repo.GetByUserID(ctx, userID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !settings.PublicWishlist {
+			t.Fatal("expected public wishlist to be stored")
+		}
+
+		if err := repo.Upsert(ctx, &models.UserSettings{UserID: userID, TimeZone: "UTC"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		cleared, err := repo.GetByUserID(ctx, userID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cleared.PublicWishlist {
+			t.Error("expected public wishlist to be cleared")
+		}
+	})
+
+	t.Run("Upsert stores materials rounding", func(t *testing.T) {
+		repo := newRepo(t)
+
+		if err := repo.Upsert(ctx, &models.UserSettings{UserID: userID, TimeZone: "UTC", MaterialsRounding: models.MaterialsRoundingPooled}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		settings, err := repo.GetByUserID(ctx, userID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !settings.PoolsCraftSurplus() {
+			t.Errorf("expected pooled rounding to be stored, got %q", settings.MaterialsRounding)
+		}
+	})
+
+	t.Run("Upsert stores and clears the starter wishlist opt-out", func(t *testing.T) {
+		repo := newRepo(t)
+
+		if err := repo.Upsert(ctx, &models.UserSettings{UserID: userID, TimeZone: "UTC", SkipStarterWishlist: true}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		settings, err := repo.GetByUserID(ctx, userID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !settings.SkipStarterWishlist {
+			t.Error("expected the opt-out to be stored")
+		}
+
+		if err := repo.Upsert(ctx, &models.UserSettings{UserID: userID, TimeZone: "UTC"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		settings, err = repo.GetByUserID(ctx, userID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if settings.SkipStarterWishlist {
+			t.Error("expected the opt-out to be cleared")
+		}
+	})
+
+	t.Run("Upsert stores and clears the digest time", func(t *testing.T) {
+		repo := newRepo(t)
+
+		if err := repo.Upsert(ctx, &models.UserSettings{UserID: userID, TimeZone: "UTC", DigestTime: "09:30"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		settings, err := repo.GetByUserID(ctx, userID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if settings.DigestTime != "09:30" {
+			t.Errorf("expected the digest time to be stored, got %q", settings.DigestTime)
+		}
+
+		if err := repo.Upsert(ctx, &models.UserSettings{UserID: userID, TimeZone: "UTC"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		settings, err = repo.GetByUserID(ctx, userID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if settings.DigestTime != "" {
+			t.Errorf("expected the digest time to be cleared, got %q", settings.DigestTime)
+		}
+	})
+}
@@ -0,0 +1,118 @@
+package repotest
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+// RunShareLinkReportRepositoryContract runs the share link report repository
+// contract against the implementation returned by newRepo.
+func RunShareLinkReportRepositoryContract(t *testing.T, newRepo ShareLinkReportRepositoryFactory) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	t.Run("Report counts towards the open entry", func(t *testing.T) {
+		repo := newRepo(t)
+
+		first, err := repo.Report(ctx, "token-1", "owner-1", models.ReportReasonSpam, now)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if first.ID.IsZero() || first.Count != 1 || first.Status != models.ReportStatusOpen || first.OwnerID != "owner-1" || !first.CreatedAt.Equal(now) {
+			t.Errorf("unexpected first report %+v", first)
+		}
+
+		later := now.Add(time.Minute)
+		for _, reason := range []string{models.ReportReasonAbuse, models.ReportReasonSpam} {
+			if _, err := repo.Report(ctx, "token-1", "owner-1", reason, later); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		reports, err := repo.List(ctx, models.ReportStatusOpen, 10)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(reports) != 1 {
+			t.Fatalf("expected one open report, got %+v", reports)
+		}
+		got := reports[0]
+		want := []string{models.ReportReasonSpam, models.ReportReasonAbuse}
+		if got.ID != first.ID || got.Count != 3 || !reflect.DeepEqual(got.Reasons, want) || !got.CreatedAt.Equal(now) || !got.LastReportedAt.Equal(later) {
+			t.Errorf("unexpected report %+v", got)
+		}
+	})
+
+	t.Run("List filters by status oldest first up to limit", func(t *testing.T) {
+		repo := newRepo(t)
+
+		empty, err := repo.List(ctx, "", 10)
+		if err != nil || empty == nil || len(empty) != 0 {
+			t.Fatalf("expected an empty slice, got %v (err %v)", empty, err)
+		}
+
+		for i, token := range []string{"newer", "older", "oldest"} {
+			if _, err := repo.Report(ctx, token, "owner-1", models.ReportReasonOther, now.Add(time.Duration(-i)*time.Minute)); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		if _, err := repo.Resolve(ctx, "oldest", models.ReportStatusDismissed, now); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		open, err := repo.List(ctx, models.ReportStatusOpen, 10)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(open) != 2 || open[0].Token != "older" || open[1].Token != "newer" {
+			t.Errorf("expected older then newer, got %+v", open)
+		}
+
+		all, err := repo.List(ctx, "", 2)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(all) != 2 || all[0].Token != "oldest" || all[1].Token != "older" {
+			t.Errorf("expected oldest then older, got %+v", all)
+		}
+	})
+
+	t.Run("Resolve closes the open entry", func(t *testing.T) {
+		repo := newRepo(t)
+
+		if _, err := repo.Report(ctx, "token-1", "owner-1", models.ReportReasonSpam, now); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		resolvedAt := now.Add(time.Hour)
+		resolved, err := repo.Resolve(ctx, "token-1", models.ReportStatusActioned, resolvedAt)
+		if err != nil || !resolved {
+			t.Fatalf("expected the report to resolve, got %v (err %v)", resolved, err)
+		}
+		again, err := repo.Resolve(ctx, "token-1", models.ReportStatusDismissed, resolvedAt)
+		if err != nil || again {
+			t.Errorf("expected no open report to resolve, got %v (err %v)", again, err)
+		}
+
+		actioned, err := repo.List(ctx, models.ReportStatusActioned, 10)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(actioned) != 1 || actioned[0].ResolvedAt == nil || !actioned[0].ResolvedAt.Equal(resolvedAt) {
+			t.Errorf("unexpected actioned reports %+v", actioned)
+		}
+
+		// A report after the entry is closed queues the link again.
+		reopened, err := repo.Report(ctx, "token-1", "owner-1", models.ReportReasonAbuse, resolvedAt)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if reopened.ID == actioned[0].ID || reopened.Count != 1 || reopened.Status != models.ReportStatusOpen {
+			t.Errorf("expected a new open report, got %+v", reopened)
+		}
+	})
+}
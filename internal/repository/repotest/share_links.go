@@ -0,0 +1,98 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RunShareLinkRepositoryContract runs the share link repository contract
+// against the implementation returned by newRepo.
+func RunShareLinkRepositoryContract(t *testing.T, newRepo ShareLinkRepositoryFactory) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	t.Run("Create and GetByToken", func(t *testing.T) {
+		repo := newRepo(t)
+
+		link := &models.ShareLink{
+			UserID:      "user-1",
+			Token:       "token-1",
+			Label:       "clan",
+			Permissions: models.ShareLinkPermissions{HideQuantities: true, HideMaterials: true},
+			CreatedAt:   now,
+		}
+		if err := repo.Create(ctx, link); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if link.ID.IsZero() {
+			t.Fatal("expected Create to set the ID")
+		}
+
+		got, err := repo.GetByToken(ctx, "token-1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got == nil || got.ID != link.ID || got.UserID != "user-1" || got.Label != "clan" || got.Permissions != link.Permissions || !got.CreatedAt.Equal(now) {
+			t.Errorf("unexpected link %+v", got)
+		}
+
+		missing, err := repo.GetByToken(ctx, "token-2")
+		if err != nil || missing != nil {
+			t.Errorf("expected nil for an unknown token, got %+v (err %v)", missing, err)
+		}
+	})
+
+	t.Run("ListByUser returns the user's links oldest first", func(t *testing.T) {
+		repo := newRepo(t)
+
+		empty, err := repo.ListByUser(ctx, "user-1")
+		if err != nil || empty == nil || len(empty) != 0 {
+			t.Fatalf("expected an empty slice, got %v (err %v)", empty, err)
+		}
+
+		for i, token := range []string{"newer", "older", "other"} {
+			userID := "user-1"
+			if token == "other" {
+				userID = "user-2"
+			}
+			createdAt := now.Add(time.Duration(-i) * time.Minute)
+			if err := repo.Create(ctx, &models.ShareLink{UserID: userID, Token: token, CreatedAt: createdAt}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		links, err := repo.ListByUser(ctx, "user-1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(links) != 2 || links[0].Token != "older" || links[1].Token != "newer" {
+			t.Errorf("expected older then newer, got %+v", links)
+		}
+	})
+
+	t.Run("Delete only removes the user's own link", func(t *testing.T) {
+		repo := newRepo(t)
+
+		link := &models.ShareLink{UserID: "user-1", Token: "token-1", CreatedAt: now}
+		if err := repo.Create(ctx, link); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if deleted, err := repo.Delete(ctx, "user-2", link.ID); err != nil || deleted {
+			t.Errorf("expected another user's delete to do nothing, got %v (err %v)", deleted, err)
+		}
+		if deleted, err := repo.Delete(ctx, "user-1", primitive.NewObjectID()); err != nil || deleted {
+			t.Errorf("expected an unknown ID to do nothing, got %v (err %v)", deleted, err)
+		}
+		if deleted, err := repo.Delete(ctx, "user-1", link.ID); err != nil || !deleted {
+			t.Fatalf("expected the link to be deleted, got %v (err %v)", deleted, err)
+		}
+		if got, _ := repo.GetByToken(ctx, "token-1"); got != nil {
+			t.Errorf("expected the deleted link to be gone, got %+v", got)
+		}
+	})
+}
@@ -0,0 +1,77 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+// RunSyncStatusRepositoryContract runs the sync status repository contract
+// against the implementation returned by newRepo.
+func RunSyncStatusRepositoryContract(t *testing.T, newRepo SyncStatusRepositoryFactory) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+	t.Run("Get returns nil for a missing status", func(t *testing.T) {
+		repo := newRepo(t)
+
+		status, err := repo.Get(ctx, models.SyncStatusItems)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if status != nil {
+			t.Errorf("expected nil, got %+v", status)
+		}
+	})
+
+	t.Run("Upsert creates and then replaces by name", func(t *testing.T) {
+		repo := newRepo(t)
+
+		first := &models.SyncStatus{
+			Name:              models.SyncStatusItems,
+			LastStartedAt:     now,
+			LastError:         "download failed",
+			FailedCollections: []string{"mods"},
+		}
+		if err := repo.Upsert(ctx, first); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := repo.Upsert(ctx, &models.SyncStatus{Name: "other", Version: "other"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		second := &models.SyncStatus{
+			Name:           models.SyncStatusItems,
+			Source:         "json",
+			LastStartedAt:  now.Add(time.Hour),
+			LastFinishedAt: now.Add(time.Hour + time.Minute),
+			LastSuccessAt:  now.Add(time.Hour + time.Minute),
+			Version:        "v2",
+			Stats:          models.ItemSyncStats{Inserted: 1, Updated: 2, Unchanged: 3, Archived: 4},
+			DurationMs:     60000,
+		}
+		if err := repo.Upsert(ctx, second); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		status, err := repo.Get(ctx, models.SyncStatusItems)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if status == nil || status.Version != "v2" || status.LastError != "" || !status.LastSuccessAt.Equal(second.LastSuccessAt) || status.Stats != second.Stats || status.DurationMs != 60000 {
+			t.Errorf("expected the replaced status, got %+v", status)
+		}
+		if status != nil && (status.FailedCollections == nil || len(status.FailedCollections) != 0) {
+			t.Errorf("expected an empty non-nil FailedCollections, got %#v", status.FailedCollections)
+		}
+
+		other, err := repo.Get(ctx, "other")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if other == nil || other.Version != "other" {
+			t.Errorf("expected the other status to be kept, got %+v", other)
+		}
+	})
+}
@@ -0,0 +1,101 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+// RunUserTraceRepositoryContract runs the user trace repository contract
+// against the implementation returned by newRepo.
+func RunUserTraceRepositoryContract(t *testing.T, newRepo UserTraceRepositoryFactory) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+	t.Run("GetByUserID returns nil for a missing trace", func(t *testing.T) {
+		repo := newRepo(t)
+
+		trace, err := repo.GetByUserID(ctx, "user-1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if trace != nil {
+			t.Errorf("expected nil, got %+v", trace)
+		}
+	})
+
+	t.Run("Upsert creates and then replaces", func(t *testing.T) {
+		repo := newRepo(t)
+
+		if err := repo.Upsert(ctx, &models.UserTrace{UserID: "user-1", Until: now.Add(time.Hour), Reason: "first", CreatedAt: now}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := repo.Upsert(ctx, &models.UserTrace{UserID: "user-1", Until: now.Add(2 * time.Hour), Reason: "second", CreatedAt: now}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		trace, err := repo.GetByUserID(ctx, "user-1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if trace == nil || trace.Reason != "second" || !trace.Until.Equal(now.Add(2*time.Hour)) || !trace.CreatedAt.Equal(now) {
+			t.Errorf("expected the replaced trace, got %+v", trace)
+		}
+	})
+
+	t.Run("Delete removes the trace and ignores missing ones", func(t *testing.T) {
+		repo := newRepo(t)
+
+		if err := repo.Upsert(ctx, &models.UserTrace{UserID: "user-1", Until: now.Add(time.Hour)}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := repo.Delete(ctx, "user-1"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := repo.Delete(ctx, "user-1"); err != nil {
+			t.Fatalf("expected deleting a missing trace to succeed, got %v", err)
+		}
+
+		trace, err := repo.GetByUserID(ctx, "user-1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if trace != nil {
+			t.Errorf("expected trace to be deleted, got %+v", trace)
+		}
+	})
+
+	t.Run("ListActive returns unexpired traces ordered by user", func(t *testing.T) {
+		repo := newRepo(t)
+
+		empty, err := repo.ListActive(ctx, now)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if empty == nil || len(empty) != 0 {
+			t.Fatalf("expected an empty non-nil slice, got %#v", empty)
+		}
+
+		for _, trace := range []models.UserTrace{
+			{UserID: "user-b", Until: now.Add(time.Hour)},
+			{UserID: "user-expired", Until: now.Add(-time.Minute)},
+			{UserID: "user-ending-now", Until: now},
+			{UserID: "user-a", Until: now.Add(time.Minute)},
+		} {
+			trace := trace
+			if err := repo.Upsert(ctx, &trace); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		active, err := repo.ListActive(ctx, now)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(active) != 2 || active[0].UserID != "user-a" || active[1].UserID != "user-b" {
+			t.Errorf("expected user-a and user-b, got %+v", active)
+		}
+	})
+}
@@ -0,0 +1,100 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RunWishlistTemplateRepositoryContract runs the wishlist template repository
+// contract against the implementation returned by newRepo.
+func RunWishlistTemplateRepositoryContract(t *testing.T, newRepo WishlistTemplateRepositoryFactory) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	three := 3
+
+	newTemplate := func(userID, name string, createdAt time.Time) *models.WishlistTemplate {
+		return &models.WishlistTemplate{
+			UserID:    userID,
+			Name:      name,
+			Variables: []models.TemplateVariable{{Name: "forma", Label: "Forma per item", Default: &three, Min: 1, Max: 10}},
+			Items:     []models.TemplateItem{{UniqueName: "/Lotus/Forma", Quantity: "2*forma"}},
+			CreatedAt: createdAt,
+		}
+	}
+
+	t.Run("ListByUser returns the user's templates oldest first", func(t *testing.T) {
+		repo := newRepo(t)
+
+		empty, err := repo.ListByUser(ctx, "user-1")
+		if err != nil || empty == nil || len(empty) != 0 {
+			t.Fatalf("expected an empty slice, got %v (err %v)", empty, err)
+		}
+
+		later := newTemplate("user-1", "Later", now)
+		earlier := newTemplate("user-1", "Earlier", now.Add(-time.Hour))
+		for _, template := range []*models.WishlistTemplate{later, earlier, newTemplate("user-2", "Other", now)} {
+			if err := repo.Create(ctx, template); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if template.ID.IsZero() {
+				t.Fatal("expected Create to set the ID")
+			}
+		}
+
+		templates, err := repo.ListByUser(ctx, "user-1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(templates) != 2 || templates[0].ID != earlier.ID || templates[1].ID != later.ID {
+			t.Fatalf("expected the user's two templates oldest first, got %+v", templates)
+		}
+	})
+
+	t.Run("Get returns only the user's own template", func(t *testing.T) {
+		repo := newRepo(t)
+		template := newTemplate("user-1", "Forma", now)
+		if err := repo.Create(ctx, template); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		got, err := repo.Get(ctx, "user-1", template.ID)
+		if err != nil || got == nil {
+			t.Fatalf("expected the template, got %v (err %v)", got, err)
+		}
+		if got.Name != "Forma" || !got.CreatedAt.Equal(now) || len(got.Items) != 1 || got.Items[0].Quantity != "2*forma" {
+			t.Errorf("expected the template to round-trip, got %+v", got)
+		}
+		if len(got.Variables) != 1 || got.Variables[0].Default == nil || *got.Variables[0].Default != 3 || got.Variables[0].Max != 10 || got.Variables[0].Label != "Forma per item" {
+			t.Errorf("expected the variables to round-trip, got %+v", got.Variables)
+		}
+
+		if other, err := repo.Get(ctx, "user-2", template.ID); err != nil || other != nil {
+			t.Errorf("expected another user's template to be hidden, got %v (err %v)", other, err)
+		}
+		if missing, err := repo.Get(ctx, "user-1", primitive.NewObjectID()); err != nil || missing != nil {
+			t.Errorf("expected nil for a missing template, got %v (err %v)", missing, err)
+		}
+	})
+
+	t.Run("Delete removes only the user's own template", func(t *testing.T) {
+		repo := newRepo(t)
+		template := newTemplate("user-1", "Forma", now)
+		if err := repo.Create(ctx, template); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if deleted, err := repo.Delete(ctx, "user-2", template.ID); err != nil || deleted {
+			t.Errorf("expected another user's delete to miss, got %v (err %v)", deleted, err)
+		}
+		if deleted, err := repo.Delete(ctx, "user-1", template.ID); err != nil || !deleted {
+			t.Fatalf("expected the template to be deleted, got %v (err %v)", deleted, err)
+		}
+		if templates, _ := repo.ListByUser(ctx, "user-1"); len(templates) != 0 {
+			t.Errorf("expected no templates left, got %+v", templates)
+		}
+	})
+}
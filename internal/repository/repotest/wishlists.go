@@ -0,0 +1,296 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+)
+
+// RunWishlistRepositoryContract runs the wishlist repository contract against
+// the implementation returned by newRepo.
+func RunWishlistRepositoryContract(t *testing.T, newRepo WishlistRepositoryFactory) {
+	ctx := context.Background()
+	const userID = "contract-user"
+
+	create := func(t *testing.T, repo repository.WishlistRepositoryInterface) *models.Wishlist {
+		t.Helper()
+		wishlist := &models.Wishlist{UserID: userID}
+		if err := repo.Create(ctx, wishlist); err != nil {
+			t.Fatalf("Create: unexpected error: %v", err)
+		}
+		return wishlist
+	}
+
+	get := func(t *testing.T, repo repository.WishlistRepositoryInterface) *models.Wishlist {
+		t.Helper()
+		wishlist, err := repo.GetByUserID(ctx, userID)
+		if err != nil {
+			t.Fatalf("GetByUserID: unexpected error: %v", err)
+		}
+		return wishlist
+	}
+
+	t.Run("GetByUserID returns nil for missing wishlist", func(t *testing.T) {
+		repo := newRepo(t)
+
+		if wishlist := get(t, repo); wishlist != nil {
+			t.Errorf("expected nil wishlist, got %+v", wishlist)
+		}
+	})
+
+	t.Run("Create stores an empty non-nil item list", func(t *testing.T) {
+		repo := newRepo(t)
+		created := create(t, repo)
+
+		if created.ID.IsZero() {
+			t.Error("expected Create to assign an ID")
+		}
+		if created.SchemaVersion != repository.CurrentWishlistSchemaVersion {
+			t.Errorf("expected schema version %d, got %d", repository.CurrentWishlistSchemaVersion, created.SchemaVersion)
+		}
+
+		wishlist := get(t, repo)
+		if wishlist == nil {
+			t.Fatal("expected wishlist, got nil")
+		}
+		if wishlist.ID != created.ID {
+			t.Errorf("expected ID %s, got %s", created.ID.Hex(), wishlist.ID.Hex())
+		}
+		if wishlist.Items == nil || len(wishlist.Items) != 0 {
+			t.Errorf("expected empty non-nil items, got %#v", wishlist.Items)
+		}
+		if wishlist.CreatedAt.IsZero() || wishlist.UpdatedAt.IsZero() {
+			t.Error("expected timestamps to be set")
+		}
+	})
+
+	t.Run("updates on a missing wishlist are no-ops", func(t *testing.T) {
+		repo := newRepo(t)
+
+		if err := repo.AddItem(ctx, userID, models.WishlistItem{UniqueName: "/Lotus/A", Quantity: 1}); err != nil {
+			t.Fatalf("AddItem: unexpected error: %v", err)
+		}
+		if err := repo.RemoveItem(ctx, userID, "/Lotus/A"); err != nil {
+			t.Fatalf("RemoveItem: unexpected error: %v", err)
+		}
+		if err := repo.UpdateItemQuantity(ctx, userID, "/Lotus/A", 3); err != nil {
+			t.Fatalf("UpdateItemQuantity: unexpected error: %v", err)
+		}
+
+		if wishlist := get(t, repo); wishlist != nil {
+			t.Errorf("expected no wishlist to be created, got %+v", wishlist)
+		}
+	})
+
+	t.Run("AddItem keeps duplicates", func(t *testing.T) {
+		repo := newRepo(t)
+		create(t, repo)
+
+		repo.AddItem(ctx, userID, models.WishlistItem{UniqueName: "/Lotus/A", Quantity: 1})
+		repo.AddItem(ctx, userID, models.WishlistItem{UniqueName: "/Lotus/A", Quantity: 2})
+
+		wishlist := get(t, repo)
+		if len(wishlist.Items) != 2 {
+			t.Fatalf("expected 2 items, got %d", len(wishlist.Items))
+		}
+		if wishlist.Items[0].Quantity != 1 || wishlist.Items[1].Quantity != 2 {
+			t.Errorf("expected items in insertion order, got %+v", wishlist.Items)
+		}
+	})
+
+	t.Run("UpdateItemQuantity updates only the first match", func(t *testing.T) {
+		repo := newRepo(t)
+		create(t, repo)
+
+		repo.AddItem(ctx, userID, models.WishlistItem{UniqueName: "/Lotus/A", Quantity: 1})
+		repo.AddItem(ctx, userID, models.WishlistItem{UniqueName: "/Lotus/A", Quantity: 2})
+		if err := repo.UpdateItemQuantity(ctx, userID, "/Lotus/A", 7); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := repo.UpdateItemQuantity(ctx, userID, "/Lotus/Missing", 9); err != nil {
+			t.Fatalf("unexpected error for missing item: %v", err)
+		}
+
+		wishlist := get(t, repo)
+		if len(wishlist.Items) != 2 || wishlist.Items[0].Quantity != 7 || wishlist.Items[1].Quantity != 2 {
+			t.Errorf("unexpected items: %+v", wishlist.Items)
+		}
+	})
+
+	t.Run("SetItemLinks replaces and clears links on the first match", func(t *testing.T) {
+		repo := newRepo(t)
+		create(t, repo)
+
+		repo.AddItem(ctx, userID, models.WishlistItem{UniqueName: "/Lotus/A", Quantity: 1})
+		repo.AddItem(ctx, userID, models.WishlistItem{UniqueName: "/Lotus/B", Quantity: 1})
+		links := []models.SourceLink{
+			{URL: "https://example.com/guide", Title: "Guide", Host: "example.com"},
+			{URL: "https://www.youtube.com/watch?v=abc", Host: "www.youtube.com"},
+		}
+		if err := repo.SetItemLinks(ctx, userID, "/Lotus/A", links); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := repo.SetItemLinks(ctx, userID, "/Lotus/Missing", links); err != nil {
+			t.Fatalf("unexpected error for missing item: %v", err)
+		}
+
+		wishlist := get(t, repo)
+		if len(wishlist.Items[0].Links) != 2 || wishlist.Items[0].Links[0] != links[0] || wishlist.Items[0].Links[1] != links[1] {
+			t.Errorf("unexpected links: %+v", wishlist.Items[0].Links)
+		}
+		if len(wishlist.Items[1].Links) != 0 {
+			t.Errorf("expected other item to have no links, got %+v", wishlist.Items[1].Links)
+		}
+
+		if err := repo.SetItemLinks(ctx, userID, "/Lotus/A", nil); err != nil {
+			t.Fatalf("unexpected error clearing links: %v", err)
+		}
+		if wishlist := get(t, repo); len(wishlist.Items[0].Links) != 0 {
+			t.Errorf("expected links to be cleared, got %+v", wishlist.Items[0].Links)
+		}
+	})
+
+	t.Run("SetItemRecipe sets and clears the recipe on the first match", func(t *testing.T) {
+		repo := newRepo(t)
+		create(t, repo)
+
+		repo.AddItem(ctx, userID, models.WishlistItem{UniqueName: "/Lotus/A", Quantity: 1})
+		repo.AddItem(ctx, userID, models.WishlistItem{UniqueName: "/Lotus/B", Quantity: 1})
+		before := get(t, repo).UpdatedAt
+		time.Sleep(2 * time.Millisecond)
+		if err := repo.SetItemRecipe(ctx, userID, "/Lotus/A", "variant"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := repo.SetItemRecipe(ctx, userID, "/Lotus/Missing", "variant"); err != nil {
+			t.Fatalf("unexpected error for missing item: %v", err)
+		}
+
+		wishlist := get(t, repo)
+		if wishlist.Items[0].RecipeID != "variant" || wishlist.Items[1].RecipeID != "" {
+			t.Errorf("unexpected recipes: %+v", wishlist.Items)
+		}
+		if !wishlist.UpdatedAt.After(before) {
+			t.Errorf("expected updatedAt to advance past %v, got %v", before, wishlist.UpdatedAt)
+		}
+
+		if err := repo.SetItemRecipe(ctx, userID, "/Lotus/A", ""); err != nil {
+			t.Fatalf("unexpected error clearing recipe: %v", err)
+		}
+		if wishlist := get(t, repo); wishlist.Items[0].RecipeID != "" {
+			t.Errorf("expected recipe to be cleared, got %q", wishlist.Items[0].RecipeID)
+		}
+	})
+
+	t.Run("SetItemProgress replaces and clears progress on the first match", func(t *testing.T) {
+		repo := newRepo(t)
+		create(t, repo)
+
+		repo.AddItem(ctx, userID, models.WishlistItem{UniqueName: "/Lotus/A", Quantity: 2})
+		repo.AddItem(ctx, userID, models.WishlistItem{UniqueName: "/Lotus/B", Quantity: 1})
+		progress := []models.ComponentProgress{
+			{UniqueName: "/Lotus/A/Chassis", Status: models.ComponentBuilt, Count: 2},
+			{UniqueName: "/Lotus/A/Blueprint", Status: models.ComponentAcquired, Count: 1},
+		}
+		if err := repo.SetItemProgress(ctx, userID, "/Lotus/A", progress); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := repo.SetItemProgress(ctx, userID, "/Lotus/Missing", progress); err != nil {
+			t.Fatalf("unexpected error for missing item: %v", err)
+		}
+
+		wishlist := get(t, repo)
+		if got := wishlist.Items[0].Progress; len(got) != 2 || got[0] != progress[0] || got[1] != progress[1] || len(wishlist.Items[1].Progress) != 0 {
+			t.Errorf("unexpected progress: %+v", wishlist.Items)
+		}
+
+		if err := repo.SetItemProgress(ctx, userID, "/Lotus/A", nil); err != nil {
+			t.Fatalf("unexpected error clearing progress: %v", err)
+		}
+		if wishlist := get(t, repo); len(wishlist.Items[0].Progress) != 0 {
+			t.Errorf("expected progress to be cleared, got %+v", wishlist.Items[0].Progress)
+		}
+	})
+
+	t.Run("RemoveItem removes every match and leaves an empty list", func(t *testing.T) {
+		repo := newRepo(t)
+		create(t, repo)
+
+		repo.AddItem(ctx, userID, models.WishlistItem{UniqueName: "/Lotus/A", Quantity: 1})
+		repo.AddItem(ctx, userID, models.WishlistItem{UniqueName: "/Lotus/A", Quantity: 2})
+		if err := repo.RemoveItem(ctx, userID, "/Lotus/A"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		wishlist := get(t, repo)
+		if wishlist.Items == nil || len(wishlist.Items) != 0 {
+			t.Errorf("expected empty non-nil items, got %#v", wishlist.Items)
+		}
+	})
+
+	t.Run("Create twice keeps the first wishlist", func(t *testing.T) {
+		repo := newRepo(t)
+		first := create(t, repo)
+		create(t, repo)
+
+		if wishlist := get(t, repo); wishlist.ID != first.ID {
+			t.Errorf("expected first wishlist %s, got %s", first.ID.Hex(), wishlist.ID.Hex())
+		}
+	})
+
+	t.Run("CreateIfAbsent creates only a missing wishlist", func(t *testing.T) {
+		repo := newRepo(t)
+
+		first := &models.Wishlist{UserID: userID, Items: []models.WishlistItem{{UniqueName: "/Lotus/A", Quantity: 2}}}
+		created, err := repo.CreateIfAbsent(ctx, first)
+		if err != nil || !created {
+			t.Fatalf("expected the wishlist to be created, got %v (err %v)", created, err)
+		}
+		if first.ID.IsZero() || first.SchemaVersion != repository.CurrentWishlistSchemaVersion {
+			t.Errorf("expected an ID and the current schema version, got %+v", first)
+		}
+
+		created, err = repo.CreateIfAbsent(ctx, &models.Wishlist{UserID: userID, Items: []models.WishlistItem{{UniqueName: "/Lotus/B", Quantity: 1}}})
+		if err != nil || created {
+			t.Fatalf("expected the existing wishlist to be kept, got %v (err %v)", created, err)
+		}
+
+		wishlist := get(t, repo)
+		if wishlist == nil || wishlist.ID != first.ID || len(wishlist.Items) != 1 || wishlist.Items[0].UniqueName != "/Lotus/A" || wishlist.CreatedAt.IsZero() {
+			t.Errorf("expected the first wishlist, got %+v", wishlist)
+		}
+	})
+
+	t.Run("Upsert creates and then replaces items", func(t *testing.T) {
+		repo := newRepo(t)
+		addedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		err := repo.Upsert(ctx, &models.Wishlist{UserID: userID, Items: []models.WishlistItem{{UniqueName: "/Lotus/A", Quantity: 1, AddedAt: addedAt}}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		created := get(t, repo)
+		if created == nil || len(created.Items) != 1 || created.CreatedAt.IsZero() || created.ID.IsZero() {
+			t.Fatalf("expected upsert to create wishlist, got %+v", created)
+		}
+		if created.SchemaVersion != repository.CurrentWishlistSchemaVersion {
+			t.Errorf("expected schema version %d, got %d", repository.CurrentWishlistSchemaVersion, created.SchemaVersion)
+		}
+
+		err = repo.Upsert(ctx, &models.Wishlist{UserID: userID, Items: []models.WishlistItem{{UniqueName: "/Lotus/B", Quantity: 4, AddedAt: addedAt}}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		updated := get(t, repo)
+		if len(updated.Items) != 1 || updated.Items[0].UniqueName != "/Lotus/B" || updated.Items[0].Quantity != 4 {
+			t.Errorf("expected items to be replaced, got %+v", updated.Items)
+		}
+		if updated.ID != created.ID || !updated.CreatedAt.Equal(created.CreatedAt) {
+			t.Error("expected upsert to keep ID and createdAt")
+		}
+	})
+}
@@ -0,0 +1,162 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RunWorkspaceContributionRepositoryContract runs the workspace contribution
+// repository contract against the implementation returned by newRepo.
+func RunWorkspaceContributionRepositoryContract(t *testing.T, newRepo WorkspaceContributionRepositoryFactory) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	newEvent := func(workspaceID primitive.ObjectID, userID, kind, uniqueName string, quantity int, age time.Duration) *models.WorkspaceContributionEvent {
+		return &models.WorkspaceContributionEvent{
+			WorkspaceID: workspaceID,
+			UserID:      userID,
+			Kind:        kind,
+			UniqueName:  uniqueName,
+			Quantity:    quantity,
+			CreatedAt:   now.Add(-age),
+		}
+	}
+
+	t.Run("ListByWorkspace returns the newest events first", func(t *testing.T) {
+		repo := newRepo(t)
+		workspaceID := primitive.NewObjectID()
+
+		empty, err := repo.ListByWorkspace(ctx, workspaceID, 10)
+		if err != nil || empty == nil || len(empty) != 0 {
+			t.Fatalf("expected an empty slice, got %v (err %v)", empty, err)
+		}
+
+		for _, event := range []*models.WorkspaceContributionEvent{
+			newEvent(workspaceID, "user-1", models.ContributionKindMaterial, "/Lotus/Alloy", 50, 2*time.Minute),
+			newEvent(workspaceID, "user-2", models.ContributionKindItem, "/Lotus/Forma", 1, time.Minute),
+			newEvent(primitive.NewObjectID(), "user-1", models.ContributionKindMaterial, "/Lotus/Alloy", 10, 0),
+			newEvent(workspaceID, "user-1", models.ContributionKindMaterial, "/Lotus/Alloy", -20, 0),
+		} {
+			if err := repo.Create(ctx, event); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if event.ID.IsZero() {
+				t.Fatal("expected Create to set the ID")
+			}
+		}
+
+		events, err := repo.ListByWorkspace(ctx, workspaceID, 10)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(events) != 3 {
+			t.Fatalf("expected the workspace's 3 events, got %+v", events)
+		}
+		if events[0].Quantity != -20 || events[1].Kind != models.ContributionKindItem || events[2].Quantity != 50 {
+			t.Errorf("expected newest first, got %+v", events)
+		}
+		if !events[2].CreatedAt.Equal(now.Add(-2 * time.Minute)) {
+			t.Errorf("expected createdAt to round-trip, got %v", events[2].CreatedAt)
+		}
+
+		limited, err := repo.ListByWorkspace(ctx, workspaceID, 2)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(limited) != 2 || limited[0].ID != events[0].ID {
+			t.Errorf("expected the 2 newest events, got %+v", limited)
+		}
+	})
+
+	t.Run("MaterialTotals sums material events by material and member", func(t *testing.T) {
+		repo := newRepo(t)
+		workspaceID := primitive.NewObjectID()
+
+		empty, err := repo.MaterialTotals(ctx, workspaceID)
+		if err != nil || empty == nil || len(empty) != 0 {
+			t.Fatalf("expected an empty slice, got %v (err %v)", empty, err)
+		}
+
+		for _, event := range []*models.WorkspaceContributionEvent{
+			newEvent(workspaceID, "user-2", models.ContributionKindMaterial, "/Lotus/Alloy", 30, 0),
+			newEvent(workspaceID, "user-1", models.ContributionKindMaterial, "/Lotus/Alloy", 50, 0),
+			newEvent(workspaceID, "user-1", models.ContributionKindMaterial, "/Lotus/Alloy", -20, 0),
+			newEvent(workspaceID, "user-1", models.ContributionKindMaterial, "/Lotus/Ferrite", 5, 0),
+			newEvent(workspaceID, "user-1", models.ContributionKindMaterial, "/Lotus/Ferrite", -5, 0),
+			newEvent(workspaceID, "user-1", models.ContributionKindItem, "/Lotus/Alloy", 7, 0),
+			newEvent(primitive.NewObjectID(), "user-1", models.ContributionKindMaterial, "/Lotus/Alloy", 100, 0),
+		} {
+			if err := repo.Create(ctx, event); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		totals, err := repo.MaterialTotals(ctx, workspaceID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := []models.WorkspaceContributionTotal{
+			{UniqueName: "/Lotus/Alloy", UserID: "user-1", Quantity: 30},
+			{UniqueName: "/Lotus/Alloy", UserID: "user-2", Quantity: 30},
+		}
+		if len(totals) != len(expected) {
+			t.Fatalf("expected %+v, got %+v", expected, totals)
+		}
+		for i := range expected {
+			if totals[i] != expected[i] {
+				t.Errorf("expected %+v at %d, got %+v", expected[i], i, totals[i])
+			}
+		}
+	})
+
+	t.Run("CountByUser counts a user's events across workspaces", func(t *testing.T) {
+		repo := newRepo(t)
+		for _, event := range []*models.WorkspaceContributionEvent{
+			newEvent(primitive.NewObjectID(), "user-1", models.ContributionKindMaterial, "/Lotus/Alloy", 1, 0),
+			newEvent(primitive.NewObjectID(), "user-1", models.ContributionKindItem, "/Lotus/Forma", 1, 0),
+			newEvent(primitive.NewObjectID(), "user-2", models.ContributionKindMaterial, "/Lotus/Alloy", 1, 0),
+		} {
+			if err := repo.Create(ctx, event); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		if count, err := repo.CountByUser(ctx, "user-1"); err != nil || count != 2 {
+			t.Errorf("expected 2 events, got %d (err %v)", count, err)
+		}
+		if count, err := repo.CountByUser(ctx, "nobody"); err != nil || count != 0 {
+			t.Errorf("expected no events, got %d (err %v)", count, err)
+		}
+	})
+
+	t.Run("DeleteByWorkspace removes only that workspace's events", func(t *testing.T) {
+		repo := newRepo(t)
+		workspaceID, otherID := primitive.NewObjectID(), primitive.NewObjectID()
+		for _, event := range []*models.WorkspaceContributionEvent{
+			newEvent(workspaceID, "user-1", models.ContributionKindMaterial, "/Lotus/Alloy", 1, 0),
+			newEvent(otherID, "user-1", models.ContributionKindMaterial, "/Lotus/Alloy", 1, 0),
+		} {
+			if err := repo.Create(ctx, event); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		if err := repo.DeleteByWorkspace(ctx, workspaceID); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := repo.DeleteByWorkspace(ctx, primitive.NewObjectID()); err != nil {
+			t.Fatalf("expected deleting a workspace without events to succeed, got %v", err)
+		}
+
+		if events, _ := repo.ListByWorkspace(ctx, workspaceID, 10); len(events) != 0 {
+			t.Errorf("expected no events left, got %+v", events)
+		}
+		if events, _ := repo.ListByWorkspace(ctx, otherID, 10); len(events) != 1 {
+			t.Errorf("expected the other workspace's event to stay, got %+v", events)
+		}
+	})
+}
@@ -0,0 +1,121 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RunWorkspaceRepositoryContract runs the workspace repository contract
+// against the implementation returned by newRepo.
+func RunWorkspaceRepositoryContract(t *testing.T, newRepo WorkspaceRepositoryFactory) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	newWorkspace := func(name string, members ...string) *models.Workspace {
+		workspace := &models.Workspace{Name: name, Members: []models.WorkspaceMember{}, Items: []models.WorkspaceItem{}, CreatedAt: now, UpdatedAt: now}
+		for _, userID := range members {
+			workspace.Members = append(workspace.Members, models.WorkspaceMember{UserID: userID, Role: models.WorkspaceRoleEditor, AddedAt: now})
+		}
+		return workspace
+	}
+
+	t.Run("Create, GetByID and ListByMember", func(t *testing.T) {
+		repo := newRepo(t)
+
+		empty, err := repo.ListByMember(ctx, "user-1")
+		if err != nil || empty == nil || len(empty) != 0 {
+			t.Fatalf("expected an empty slice, got %v (err %v)", empty, err)
+		}
+		if got, err := repo.GetByID(ctx, primitive.NewObjectID()); err != nil || got != nil {
+			t.Fatalf("expected nil for a missing workspace, got %+v (err %v)", got, err)
+		}
+
+		research := newWorkspace("Research", "user-1", "user-2")
+		research.Items = []models.WorkspaceItem{{
+			UniqueName:    "/Lotus/Forma",
+			Quantity:      10,
+			AddedBy:       "user-1",
+			AddedAt:       now,
+			Contributions: []models.WorkspaceContribution{{UserID: "user-2", Quantity: 3, UpdatedAt: now}},
+		}}
+		for _, workspace := range []*models.Workspace{research, newWorkspace("Decorations", "user-1"), newWorkspace("Other", "user-3")} {
+			if err := repo.Create(ctx, workspace); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		if research.ID.IsZero() {
+			t.Fatal("expected Create to set the ID")
+		}
+
+		got, err := repo.GetByID(ctx, research.ID)
+		if err != nil || got == nil {
+			t.Fatalf("expected the workspace, got %+v (err %v)", got, err)
+		}
+		if len(got.Members) != 2 || len(got.Items) != 1 || got.Items[0].Contributions[0].Quantity != 3 || !got.CreatedAt.Equal(now) {
+			t.Errorf("expected the workspace to round-trip, got %+v", got)
+		}
+
+		listed, err := repo.ListByMember(ctx, "user-1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(listed) != 2 || listed[0].Name != "Decorations" || listed[1].Name != "Research" {
+			t.Fatalf("expected the member's workspaces ordered by name, got %+v", listed)
+		}
+		if listed[0].Items == nil {
+			t.Error("expected items to be an empty slice")
+		}
+	})
+
+	t.Run("Update is versioned", func(t *testing.T) {
+		repo := newRepo(t)
+
+		workspace := newWorkspace("Research", "user-1")
+		if err := repo.Create(ctx, workspace); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		stale, _ := repo.GetByID(ctx, workspace.ID)
+
+		workspace.Name = "Dojo research"
+		workspace.Items = []models.WorkspaceItem{{UniqueName: "/Lotus/Forma", Quantity: 5, AddedBy: "user-1", AddedAt: now, Contributions: []models.WorkspaceContribution{}}}
+		updated, err := repo.Update(ctx, workspace)
+		if err != nil || !updated {
+			t.Fatalf("expected the update to apply, got %v (err %v)", updated, err)
+		}
+		if workspace.Version != stale.Version+1 {
+			t.Errorf("expected Update to increment the version, got %d", workspace.Version)
+		}
+
+		stale.Name = "Lost update"
+		if updated, err := repo.Update(ctx, stale); err != nil || updated {
+			t.Fatalf("expected a stale update to be refused, got %v (err %v)", updated, err)
+		}
+
+		got, _ := repo.GetByID(ctx, workspace.ID)
+		if got.Name != "Dojo research" || len(got.Items) != 1 || got.Version != workspace.Version {
+			t.Errorf("expected the first update to be kept, got %+v", got)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		repo := newRepo(t)
+
+		workspace := newWorkspace("Research", "user-1")
+		if err := repo.Create(ctx, workspace); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if deleted, err := repo.Delete(ctx, workspace.ID); err != nil || !deleted {
+			t.Fatalf("expected the workspace to be deleted, got %v (err %v)", deleted, err)
+		}
+		if deleted, err := repo.Delete(ctx, workspace.ID); err != nil || deleted {
+			t.Fatalf("expected a second delete to report false, got %v (err %v)", deleted, err)
+		}
+		if updated, err := repo.Update(ctx, workspace); err != nil || updated {
+			t.Fatalf("expected updating a deleted workspace to report false, got %v (err %v)", updated, err)
+		}
+	})
+}
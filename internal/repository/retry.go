@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// transientRetryAttempts bounds how often an operation is tried when it
+	// fails with a transient error. The driver already retried it once.
+	transientRetryAttempts = 3
+	// transientRetryBackoff is the wait before the first retry; it doubles
+	// before each further retry, giving an election time to finish.
+	transientRetryBackoff = 200 * time.Millisecond
+)
+
+// transientErrorCodes are the server errors returned while a replica set is
+// changing primary or a node is shutting down.
+var transientErrorCodes = []int{
+	6,     // HostUnreachable
+	7,     // HostNotFound
+	89,    // NetworkTimeout
+	91,    // ShutdownInProgress
+	189,   // PrimarySteppedDown
+	9001,  // SocketException
+	10107, // NotWritablePrimary
+	11600, // InterruptedAtShutdown
+	11602, // InterruptedDueToReplStateChange
+	13435, // NotPrimaryNoSecondaryOk
+	13436, // NotPrimaryOrSecondary
+}
+
+// isTransient reports whether err is one a failover produces and that is
+// likely to succeed when retried. Timeouts are not transient: the operation's
+// deadline has already passed.
+func isTransient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || mongo.IsTimeout(err) {
+		return false
+	}
+	if mongo.IsNetworkError(err) {
+		return true
+	}
+
+	var serverErr mongo.ServerError
+	if !errors.As(err, &serverErr) {
+		return false
+	}
+	if serverErr.HasErrorLabel("RetryableWriteError") || serverErr.HasErrorLabel("TransientTransactionError") {
+		return true
+	}
+	for _, code := range transientErrorCodes {
+		if serverErr.HasErrorCode(code) {
+			return true
+		}
+	}
+	return false
+}
+
+// withRetry runs op, retrying it with backoff while it fails with a transient
+// error and ctx allows. Only idempotent operations may use it: a write that
+// failed on the network may still have been applied, so $push and $inc
+// updates rely on the driver's retryable writes instead.
+func withRetry(ctx context.Context, name string, op func() error) error {
+	backoff := transientRetryBackoff
+	for attempt := 1; ; attempt++ {
+		err := op()
+		if err == nil || attempt == transientRetryAttempts || !isTransient(err) {
+			return err
+		}
+
+		logger.Warn(ctx, "repo: "+name+" - transient database error, retrying", "attempt", attempt, "backoff", backoff, "error", err)
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
+
+// findOne decodes the document matching filter into v, retrying transient
+// errors. A missing document returns mongo.ErrNoDocuments without a retry.
+func findOne(ctx context.Context, name string, collection *mongo.Collection, filter any, v any) error {
+	return withRetry(ctx, name, func() error {
+		return collection.FindOne(ctx, filter).Decode(v)
+	})
+}
+
+// findAll decodes every document matching filter into results, a pointer to a
+// slice, retrying transient errors from the query or the cursor.
+func findAll(ctx context.Context, name string, collection *mongo.Collection, filter any, results any, opts ...*options.FindOptions) error {
+	return withRetry(ctx, name, func() error {
+		cursor, err := collection.Find(ctx, filter, opts...)
+		if err != nil {
+			return err
+		}
+		defer cursor.Close(ctx)
+		return cursor.All(ctx, results)
+	})
+}
+
+// updateOne applies an idempotent update, such as $set, $pull or an upsert,
+// retrying transient errors.
+func updateOne(ctx context.Context, name string, collection *mongo.Collection, filter, update any, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	var result *mongo.UpdateResult
+	err := withRetry(ctx, name, func() error {
+		var err error
+		result, err = collection.UpdateOne(ctx, filter, update, opts...)
+		return err
+	})
+	return result, err
+}
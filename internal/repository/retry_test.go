@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "nil", err: nil},
+		{name: "plain error", err: errors.New("boom")},
+		{name: "no documents", err: mongo.ErrNoDocuments},
+		{name: "duplicate key", err: mongo.CommandError{Code: 11000}},
+		{name: "canceled", err: context.Canceled},
+		{name: "deadline exceeded", err: fmt.Errorf("query: %w", context.DeadlineExceeded)},
+		{name: "not writable primary", err: mongo.CommandError{Code: 10107, Name: "NotWritablePrimary"}, expected: true},
+		{name: "primary stepped down", err: mongo.CommandError{Code: 189}, expected: true},
+		{name: "interrupted by replication state change", err: mongo.CommandError{Code: 11602}, expected: true},
+		{name: "network error label", err: mongo.CommandError{Labels: []string{"NetworkError"}}, expected: true},
+		{name: "retryable write label", err: mongo.CommandError{Code: 1, Labels: []string{"RetryableWriteError"}}, expected: true},
+		{name: "wrapped write exception", err: fmt.Errorf("update: %w", mongo.WriteException{WriteConcernError: &mongo.WriteConcernError{Code: 91}}), expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isTransient(tt.err); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func isNotWritablePrimary(err error) bool {
+	var commandErr mongo.CommandError
+	return errors.As(err, &commandErr) && commandErr.Code == 10107
+}
+
+func TestWithRetry(t *testing.T) {
+	transient := mongo.CommandError{Code: 10107}
+
+	t.Run("retries transient errors until success", func(t *testing.T) {
+		calls := 0
+		err := withRetry(context.Background(), "Test", func() error {
+			calls++
+			if calls < 2 {
+				return transient
+			}
+			return nil
+		})
+		if err != nil || calls != 2 {
+			t.Errorf("expected success on the second attempt, got %v after %d calls", err, calls)
+		}
+	})
+
+	t.Run("gives up after the last attempt", func(t *testing.T) {
+		calls := 0
+		err := withRetry(context.Background(), "Test", func() error {
+			calls++
+			return transient
+		})
+		if !isNotWritablePrimary(err) || calls != transientRetryAttempts {
+			t.Errorf("expected the transient error after %d calls, got %v after %d", transientRetryAttempts, err, calls)
+		}
+	})
+
+	t.Run("does not retry other errors", func(t *testing.T) {
+		calls := 0
+		err := withRetry(context.Background(), "Test", func() error {
+			calls++
+			return mongo.ErrNoDocuments
+		})
+		if err != mongo.ErrNoDocuments || calls != 1 {
+			t.Errorf("expected one call returning ErrNoDocuments, got %v after %d", err, calls)
+		}
+	})
+
+	t.Run("stops when the context ends", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		calls := 0
+		start := time.Now()
+		err := withRetry(ctx, "Test", func() error {
+			calls++
+			cancel()
+			return transient
+		})
+		if !isNotWritablePrimary(err) || calls != 1 {
+			t.Errorf("expected the transient error after one call, got %v after %d", err, calls)
+		}
+		if time.Since(start) >= transientRetryBackoff {
+			t.Error("expected no backoff wait after the context ended")
+		}
+	})
+}
@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/database"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// outdatedFilter matches documents older than version, including documents
+// written before the schemaVersion field existed.
+func outdatedFilter(version int) bson.M {
+	return bson.M{"$or": []bson.M{
+		{"schemaVersion": bson.M{"$exists": false}},
+		{"schemaVersion": bson.M{"$lt": version}},
+	}}
+}
+
+// maxMigrationAttempts bounds how often a document is read and migrated again
+// after a concurrent write changed it before the migrated copy was saved.
+const maxMigrationAttempts = 3
+
+// errMigrationConflict reports a document that kept changing underneath its
+// migration; a later read or run migrates it instead.
+var errMigrationConflict = errors.New("document changed during migration")
+
+// unchangedFilter matches the document raw was read from only while fields
+// still hold the values they had in raw. Saving a migration under it cannot
+// overwrite a write that landed after the read, such as a $push to items.
+func unchangedFilter(raw bson.Raw, fields []string) bson.M {
+	filter := bson.M{"_id": raw.Lookup("_id")}
+	for _, field := range fields {
+		value := raw.Lookup(field)
+		if value.Type == 0 {
+			filter[field] = bson.M{"$exists": false}
+			continue
+		}
+		filter[field] = value
+	}
+	return filter
+}
+
+// migrateDocument migrates doc, decoded from raw, and saves the fields set
+// returns while the stored copy still holds what raw was read with. When a
+// concurrent write got there first it rereads the document and migrates that
+// instead. It reports whether a migrated copy was saved; doc holds the latest
+// migrated copy either way, or the current one if another reader already
+// migrated it.
+func migrateDocument[T any](ctx context.Context, collection *mongo.Collection, raw bson.Raw, doc *T, migrate func(*T) bool, set func(*T) bson.M) (bool, error) {
+	for attempt := 1; ; attempt++ {
+		if !migrate(doc) {
+			return false, nil
+		}
+
+		fields := set(doc)
+		names := make([]string, 0, len(fields))
+		for name := range fields {
+			names = append(names, name)
+		}
+		result, err := collection.UpdateOne(ctx, unchangedFilter(raw, names), bson.M{"$set": fields})
+		if err != nil {
+			return false, err
+		}
+		if result.MatchedCount > 0 {
+			return true, nil
+		}
+		if attempt == maxMigrationAttempts {
+			return false, errMigrationConflict
+		}
+
+		raw, err = collection.FindOne(ctx, bson.M{"_id": raw.Lookup("_id")}).Raw()
+		if err == mongo.ErrNoDocuments {
+			// Deleted since it was read; there is nothing left to migrate.
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		var fresh T
+		if err := bson.Unmarshal(raw, &fresh); err != nil {
+			return false, err
+		}
+		*doc = fresh
+	}
+}
+
+// migrateWishlistDocument migrates and saves wishlist, decoded from raw, with
+// migrateDocument.
+func migrateWishlistDocument(ctx context.Context, collection *mongo.Collection, raw bson.Raw, wishlist *models.Wishlist) (bool, error) {
+	return migrateDocument(ctx, collection, raw, wishlist, MigrateWishlist, func(w *models.Wishlist) bson.M {
+		return bson.M{
+			"items":         w.Items,
+			"schemaVersion": w.SchemaVersion,
+		}
+	})
+}
+
+// migrateOwnedBlueprintsDocument migrates and saves ownedBlueprints, decoded
+// from raw, with migrateDocument.
+func migrateOwnedBlueprintsDocument(ctx context.Context, collection *mongo.Collection, raw bson.Raw, ownedBlueprints *models.OwnedBlueprints) (bool, error) {
+	return migrateDocument(ctx, collection, raw, ownedBlueprints, MigrateOwnedBlueprints, func(o *models.OwnedBlueprints) bson.M {
+		return bson.M{
+			"blueprints":    o.Blueprints,
+			"components":    o.Components,
+			"schemaVersion": o.SchemaVersion,
+		}
+	})
+}
+
+// MigrationStats reports how many documents a migration run upgraded.
+type MigrationStats struct {
+	Wishlists       int
+	OwnedBlueprints int
+	Failed          int
+}
+
+// SchemaMigrator upgrades every outdated user document in the background so
+// that lazy migration on read eventually stops being needed for old data.
+type SchemaMigrator struct {
+	db *database.MongoDB
+	// afterRead runs between reading each outdated document and saving its
+	// migrated copy; tests use it to interleave writes.
+	afterRead func()
+}
+
+func NewSchemaMigrator(db *database.MongoDB) *SchemaMigrator {
+	return &SchemaMigrator{db: db}
+}
+
+// Run migrates all outdated wishlists and owned blueprints. Individual document
+// failures are counted and logged but do not stop the run.
+func (m *SchemaMigrator) Run(ctx context.Context) (MigrationStats, error) {
+	logger.Info(ctx, "repo: SchemaMigrator.Run - starting background schema migration")
+	var stats MigrationStats
+
+	wishlists := m.db.Collection(wishlistCollection)
+	cursor, err := wishlists.Find(ctx, outdatedFilter(CurrentWishlistSchemaVersion))
+	if err != nil {
+		logger.Error(ctx, "repo: SchemaMigrator.Run - error querying wishlists", "error", err)
+		return stats, err
+	}
+	for cursor.Next(ctx) {
+		var wishlist models.Wishlist
+		if err := cursor.Decode(&wishlist); err != nil {
+			logger.Warn(ctx, "repo: SchemaMigrator.Run - error decoding wishlist", "error", err)
+			stats.Failed++
+			continue
+		}
+		if m.afterRead != nil {
+			m.afterRead()
+		}
+		writeCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		saved, err := migrateWishlistDocument(writeCtx, wishlists, cursor.Current, &wishlist)
+		cancel()
+		if err != nil {
+			logger.Warn(ctx, "repo: SchemaMigrator.Run - error migrating wishlist", "wishlistID", wishlist.ID.Hex(), "error", err)
+			stats.Failed++
+			continue
+		}
+		if saved {
+			stats.Wishlists++
+		}
+	}
+	cursor.Close(ctx)
+
+	ownedBlueprints := m.db.Collection(ownedBlueprintsCollection)
+	cursor, err = ownedBlueprints.Find(ctx, outdatedFilter(CurrentOwnedBlueprintsSchemaVersion))
+	if err != nil {
+		logger.Error(ctx, "repo: SchemaMigrator.Run - error querying owned blueprints", "error", err)
+		return stats, err
+	}
+	for cursor.Next(ctx) {
+		var owned models.OwnedBlueprints
+		if err := cursor.Decode(&owned); err != nil {
+			logger.Warn(ctx, "repo: SchemaMigrator.Run - error decoding owned blueprints", "error", err)
+			stats.Failed++
+			continue
+		}
+		if m.afterRead != nil {
+			m.afterRead()
+		}
+		writeCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		saved, err := migrateOwnedBlueprintsDocument(writeCtx, ownedBlueprints, cursor.Current, &owned)
+		cancel()
+		if err != nil {
+			logger.Warn(ctx, "repo: SchemaMigrator.Run - error migrating owned blueprints", "id", owned.ID.Hex(), "error", err)
+			stats.Failed++
+			continue
+		}
+		if saved {
+			stats.OwnedBlueprints++
+		}
+	}
+	cursor.Close(ctx)
+
+	logger.Info(ctx, "repo: SchemaMigrator.Run - completed", "wishlists", stats.Wishlists, "ownedBlueprints", stats.OwnedBlueprints, "failed", stats.Failed)
+	return stats, nil
+}
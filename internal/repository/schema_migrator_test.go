@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestUnchangedFilter(t *testing.T) {
+	id := primitive.NewObjectID()
+	raw, err := bson.Marshal(bson.D{
+		{Key: "_id", Value: id},
+		{Key: "items", Value: bson.A{bson.D{{Key: "uniqueName", Value: "/Lotus/Forma"}}}},
+		{Key: "schemaVersion", Value: 0},
+	})
+	if err != nil {
+		t.Fatalf("failed to marshal document: %v", err)
+	}
+
+	filter := unchangedFilter(raw, []string{"items", "components", "schemaVersion"})
+
+	if got, ok := filter["_id"].(bson.RawValue); !ok || got.ObjectID() != id {
+		t.Errorf("expected the document ID, got %v", filter["_id"])
+	}
+	for _, field := range []string{"items", "schemaVersion"} {
+		got, ok := filter[field].(bson.RawValue)
+		if !ok || !got.Equal(bson.Raw(raw).Lookup(field)) {
+			t.Errorf("expected %s to match the value read, got %v", field, filter[field])
+		}
+	}
+	missing, ok := filter["components"].(bson.M)
+	if !ok || missing["$exists"] != false {
+		t.Errorf("expected components to be required missing, got %v", filter["components"])
+	}
+
+	if _, err := bson.Marshal(filter); err != nil {
+		t.Errorf("expected the filter to marshal, got %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// DefaultSearchExcluded are the collections left out of the default search
+// scope: star chart nodes and enemies are not items anyone wishlists.
+var DefaultSearchExcluded = []string{"node", "enemy"}
+
+// SearchScope splits ItemCollections into the collections searched when no
+// category is given and the excluded rest. Excluded collections can still be
+// searched by naming them as the category, and uniqueName lookups fall back
+// to them after missing in the scope.
+type SearchScope struct {
+	Collections []string
+	Excluded    []string
+}
+
+// NewSearchScope returns the scope of every item collection but excluded,
+// both halves in ItemCollections order. Naming a collection that does not
+// exist is an error.
+func NewSearchScope(excluded []string) (SearchScope, error) {
+	for _, name := range excluded {
+		if !slices.Contains(ItemCollections, name) {
+			return SearchScope{}, fmt.Errorf("unknown item collection %q", name)
+		}
+	}
+
+	var scope SearchScope
+	for _, name := range ItemCollections {
+		if slices.Contains(excluded, name) {
+			scope.Excluded = append(scope.Excluded, name)
+		} else {
+			scope.Collections = append(scope.Collections, name)
+		}
+	}
+	return scope, nil
+}
+
+// DefaultSearchScope excludes DefaultSearchExcluded.
+func DefaultSearchScope() SearchScope {
+	scope, _ := NewSearchScope(DefaultSearchExcluded)
+	return scope
+}
+
+// ParseSearchScope reads a comma-separated list of excluded collections, as
+// set in ITEM_SEARCH_EXCLUDED_COLLECTIONS. "none" excludes nothing.
+func ParseSearchScope(excluded string) (SearchScope, error) {
+	var names []string
+	if strings.TrimSpace(excluded) != "none" {
+		for _, name := range strings.Split(excluded, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+	return NewSearchScope(names)
+}
+
+// LookupOrder is the order uniqueName lookups visit collections in: the
+// scope, then the excluded collections.
+func (s SearchScope) LookupOrder() []string {
+	return append(slices.Clip(s.Collections), s.Excluded...)
+}
+
+// BatchLookupOrder ranks collections the way FindByUniqueNames picks between
+// copies of an item, most preferred first: later scope collections overwrite
+// earlier ones, and the excluded collections only fill in what the scope
+// missed.
+func (s SearchScope) BatchLookupOrder() []string {
+	order := make([]string, 0, len(s.Collections)+len(s.Excluded))
+	for _, half := range [][]string{s.Collections, s.Excluded} {
+		for i := len(half) - 1; i >= 0; i-- {
+			order = append(order, half[i])
+		}
+	}
+	return order
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseSearchScope(t *testing.T) {
+	tests := []struct {
+		name             string
+		excluded         string
+		expectedExcluded []string
+		expectError      bool
+	}{
+		{name: "default", excluded: "node,enemy", expectedExcluded: []string{"node", "enemy"}},
+		{name: "kept in collection order", excluded: " enemy , relics ", expectedExcluded: []string{"relics", "enemy"}},
+		{name: "none", excluded: "none", expectedExcluded: nil},
+		{name: "empty", excluded: "", expectedExcluded: nil},
+		{name: "unknown collection", excluded: "node,planets", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scope, err := ParseSearchScope(tt.excluded)
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(scope.Excluded, tt.expectedExcluded) {
+				t.Errorf("expected excluded %v, got %v", tt.expectedExcluded, scope.Excluded)
+			}
+			if len(scope.Collections)+len(scope.Excluded) != len(ItemCollections) {
+				t.Errorf("expected every collection in one half, got %+v", scope)
+			}
+			if !slices.Equal(scope.LookupOrder()[:len(scope.Collections)], scope.Collections) {
+				t.Errorf("expected lookups to start with the scope, got %v", scope.LookupOrder())
+			}
+		})
+	}
+}
+
+func TestDefaultSearchScope(t *testing.T) {
+	scope := DefaultSearchScope()
+	if slices.Contains(scope.Collections, "node") || slices.Contains(scope.Collections, "enemy") {
+		t.Errorf("expected nodes and enemies outside the default scope, got %v", scope.Collections)
+	}
+	if !slices.Equal(scope.LookupOrder(), ItemCollections) {
+		t.Errorf("expected the default lookup order to match ItemCollections, got %v", scope.LookupOrder())
+	}
+}
+
+func TestSearchScope_BatchLookupOrder(t *testing.T) {
+	scope := SearchScope{Collections: []string{"warframes", "resources", "misc"}, Excluded: []string{"node", "enemy"}}
+	expected := []string{"misc", "resources", "warframes", "enemy", "node"}
+	if got := scope.BatchLookupOrder(); !slices.Equal(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
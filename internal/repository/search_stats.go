@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"container/list"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// searchStatsMinSamples is how many searches of a term must have reached a
+// collection before the term's hit rate there is trusted.
+const searchStatsMinSamples = 5
+
+// searchStatsMaxHitRate is the hit rate at or below which a term is taken to
+// never match in a collection.
+const searchStatsMaxHitRate = 0.01
+
+// searchLatencyWeight is the weight of each new search in a collection's
+// moving average latency.
+const searchLatencyWeight = 0.2
+
+// SearchStats learns which collections each query term matches in, so Search
+// can skip the collections none of a query's terms has ever matched and
+// search the rest likeliest and fastest first. A text query matches any of
+// its terms, so a search that missed a collection tells that none of its
+// terms matches there; a hit is credited to every term. Only queries of
+// plain words are learned from; phrases, negations and punctuation are
+// searched everywhere. Terms are kept for ttl, and the least recently used
+// ones are dropped beyond capacity; call Reset when the item data changes.
+type SearchStats struct {
+	capacity int
+	ttl      time.Duration
+	now      func() time.Time
+
+	mu      sync.Mutex
+	order   *list.List
+	terms   map[string]*list.Element
+	latency map[string]time.Duration
+}
+
+type termStats struct {
+	term      string
+	expiresAt time.Time
+	// collections holds the searches that reached each collection and how
+	// many of them hit.
+	collections map[string]*collectionHits
+}
+
+type collectionHits struct {
+	searches int
+	hits     int
+}
+
+func NewSearchStats(capacity int, ttl time.Duration) *SearchStats {
+	return &SearchStats{
+		capacity: capacity,
+		ttl:      ttl,
+		now:      time.Now,
+		order:    list.New(),
+		terms:    make(map[string]*list.Element),
+		latency:  make(map[string]time.Duration),
+	}
+}
+
+// Plan orders collections for query: the collections to search, likeliest
+// to match per millisecond first, and those skipped because every term has
+// all but never matched there. A query that is not learned from is searched
+// in every collection, in the given order.
+func (s *SearchStats) Plan(query string, collections []string) (search, skipped []string) {
+	terms := searchTerms(query)
+	if terms == nil {
+		return collections, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	scores := make(map[string]float64, len(collections))
+	for _, collName := range collections {
+		likelihood, skip := s.likelihood(terms, collName)
+		if skip {
+			skipped = append(skipped, collName)
+			continue
+		}
+		search = append(search, collName)
+		// Collections with no latency yet are tried early, which measures
+		// them.
+		scores[collName] = likelihood / max(s.latency[collName].Seconds()*1000, 1)
+	}
+	slices.SortStableFunc(search, func(a, b string) int {
+		switch {
+		case scores[a] > scores[b]:
+			return -1
+		case scores[a] < scores[b]:
+			return 1
+		default:
+			return 0
+		}
+	})
+	return search, skipped
+}
+
+// likelihood estimates the chance that a query of terms matches in collName,
+// the best of its terms' smoothed hit rates, and reports whether every term
+// has been seen enough to be trusted never to match there.
+func (s *SearchStats) likelihood(terms []string, collName string) (float64, bool) {
+	best := 0.0
+	skip := true
+	for _, term := range terms {
+		var searches, hits int
+		if stats := s.get(term); stats != nil {
+			if counts := stats.collections[collName]; counts != nil {
+				searches, hits = counts.searches, counts.hits
+			}
+		}
+		if searches < searchStatsMinSamples || float64(hits) > searchStatsMaxHitRate*float64(searches) {
+			skip = false
+		}
+		best = max(best, float64(hits+1)/float64(searches+2))
+	}
+	return best, skip
+}
+
+// Record notes that a search of query reached collName, whether it matched
+// anything there, and how long the collection took.
+func (s *SearchStats) Record(query, collName string, hit bool, took time.Duration) {
+	terms := searchTerms(query)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if previous, ok := s.latency[collName]; ok {
+		s.latency[collName] = previous + time.Duration(searchLatencyWeight*float64(took-previous))
+	} else {
+		s.latency[collName] = took
+	}
+
+	for _, term := range terms {
+		stats := s.get(term)
+		if stats == nil {
+			stats = s.add(term)
+		}
+		counts := stats.collections[collName]
+		if counts == nil {
+			counts = &collectionHits{}
+			stats.collections[collName] = counts
+		}
+		counts.searches++
+		if hit {
+			counts.hits++
+		}
+	}
+}
+
+// Reset forgets every term, for when the item data has changed. Latencies
+// are kept.
+func (s *SearchStats) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.order.Init()
+	clear(s.terms)
+}
+
+// get returns the unexpired stats of term, marking them recently used.
+// Callers hold mu.
+func (s *SearchStats) get(term string) *termStats {
+	element, ok := s.terms[term]
+	if !ok {
+		return nil
+	}
+	stats := element.Value.(*termStats)
+	if !s.now().Before(stats.expiresAt) {
+		s.order.Remove(element)
+		delete(s.terms, term)
+		return nil
+	}
+	s.order.MoveToFront(element)
+	return stats
+}
+
+// add starts the stats of term, evicting the least recently used terms
+// beyond capacity. Callers hold mu.
+func (s *SearchStats) add(term string) *termStats {
+	stats := &termStats{
+		term:        term,
+		expiresAt:   s.now().Add(s.ttl),
+		collections: make(map[string]*collectionHits),
+	}
+	s.terms[term] = s.order.PushFront(stats)
+	for s.order.Len() > s.capacity {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.terms, oldest.Value.(*termStats).term)
+	}
+	return stats
+}
+
+// searchTerms splits query into its distinct lowercase words, or returns nil
+// when it is empty or holds anything but letters, digits and spaces, whose
+// matches do not follow from its words alone.
+func searchTerms(query string) []string {
+	var terms []string
+	for _, field := range strings.Fields(strings.ToLower(query)) {
+		for _, r := range field {
+			if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+				return nil
+			}
+		}
+		if !slices.Contains(terms, field) {
+			terms = append(terms, field)
+		}
+	}
+	return terms
+}
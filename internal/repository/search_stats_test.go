@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+// recordSearches records n searches of query that reached collName.
+func recordSearches(stats *SearchStats, query, collName string, hit bool, n int) {
+	for range n {
+		stats.Record(query, collName, hit, time.Millisecond)
+	}
+}
+
+func TestSearchStats_SkipsCollectionsWithoutHits(t *testing.T) {
+	stats := NewSearchStats(100, time.Hour)
+	collections := []string{"warframes", "mods", "resources"}
+
+	// Until a term has been seen enough, every collection is searched.
+	recordSearches(stats, "forma", "warframes", false, searchStatsMinSamples-1)
+	if _, skipped := stats.Plan("forma", collections); skipped != nil {
+		t.Fatalf("expected no skips before %d samples, got %v", searchStatsMinSamples, skipped)
+	}
+
+	recordSearches(stats, "forma", "warframes", false, 1)
+	recordSearches(stats, "forma", "mods", false, searchStatsMinSamples)
+	recordSearches(stats, "forma", "resources", true, searchStatsMinSamples)
+
+	search, skipped := stats.Plan("forma", collections)
+	if !slices.Equal(search, []string{"resources"}) || !slices.Equal(skipped, []string{"warframes", "mods"}) {
+		t.Errorf("expected only resources searched, got %v (skipped %v)", search, skipped)
+	}
+
+	// Terms are matched case-insensitively.
+	if _, skipped := stats.Plan("FORMA", collections); len(skipped) != 2 {
+		t.Errorf("expected the learned term to apply to any case, skipped %v", skipped)
+	}
+}
+
+func TestSearchStats_EveryTermMustMiss(t *testing.T) {
+	stats := NewSearchStats(100, time.Hour)
+	recordSearches(stats, "forma", "mods", false, searchStatsMinSamples)
+
+	// The query also matches "serration", which has not been seen in mods.
+	if _, skipped := stats.Plan("forma serration", []string{"mods"}); skipped != nil {
+		t.Errorf("expected mods to be searched for an unseen term, skipped %v", skipped)
+	}
+
+	recordSearches(stats, "serration", "mods", true, searchStatsMinSamples)
+	if _, skipped := stats.Plan("forma serration", []string{"mods"}); skipped != nil {
+		t.Errorf("expected mods to be searched for a term that hits there, skipped %v", skipped)
+	}
+}
+
+func TestSearchStats_NearZeroHitRate(t *testing.T) {
+	stats := NewSearchStats(100, time.Hour)
+	recordSearches(stats, "prime", "misc", false, 199)
+	recordSearches(stats, "prime", "misc", true, 1)
+
+	if _, skipped := stats.Plan("prime", []string{"misc"}); !slices.Equal(skipped, []string{"misc"}) {
+		t.Errorf("expected a 0.5%% hit rate to be skipped, skipped %v", skipped)
+	}
+
+	recordSearches(stats, "prime", "misc", true, 2)
+	if _, skipped := stats.Plan("prime", []string{"misc"}); skipped != nil {
+		t.Errorf("expected a 1.5%% hit rate to be searched, skipped %v", skipped)
+	}
+}
+
+func TestSearchStats_OrdersByHitsPerMillisecond(t *testing.T) {
+	stats := NewSearchStats(100, time.Hour)
+	collections := []string{"warframes", "primary", "mods"}
+	for range 10 {
+		stats.Record("soma", "warframes", false, time.Millisecond)
+		stats.Record("soma", "primary", true, 5*time.Millisecond)
+		stats.Record("soma", "mods", true, 2*time.Millisecond)
+	}
+	// warframes still has a hit, so it is searched, last.
+	stats.Record("soma", "warframes", true, time.Millisecond)
+
+	search, skipped := stats.Plan("soma", collections)
+	if !slices.Equal(search, []string{"mods", "primary", "warframes"}) || skipped != nil {
+		t.Errorf("expected the likeliest and fastest first, got %v (skipped %v)", search, skipped)
+	}
+}
+
+func TestSearchStats_UnlearnedQueries(t *testing.T) {
+	stats := NewSearchStats(100, time.Hour)
+	collections := []string{"warframes", "mods"}
+	for _, query := range []string{`"forma blueprint"`, "forma -prime", "ash-prime", ""} {
+		recordSearches(stats, query, "mods", false, searchStatsMinSamples)
+
+		search, skipped := stats.Plan(query, collections)
+		if !slices.Equal(search, collections) || skipped != nil {
+			t.Errorf("%q: expected every collection in order, got %v (skipped %v)", query, search, skipped)
+		}
+	}
+	if len(stats.terms) != 0 {
+		t.Errorf("expected nothing learned, got %d terms", len(stats.terms))
+	}
+}
+
+func TestSearchStats_ExpiryEvictionAndReset(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	stats := NewSearchStats(2, time.Hour)
+	stats.now = func() time.Time { return now }
+	skippedFor := func(query string) []string {
+		_, skipped := stats.Plan(query, []string{"mods"})
+		return skipped
+	}
+
+	recordSearches(stats, "forma", "mods", false, searchStatsMinSamples)
+	now = now.Add(time.Hour)
+	if skipped := skippedFor("forma"); skipped != nil {
+		t.Errorf("expected expired stats to be relearned, skipped %v", skipped)
+	}
+
+	recordSearches(stats, "forma", "mods", false, searchStatsMinSamples)
+	recordSearches(stats, "ash", "mods", false, searchStatsMinSamples)
+	recordSearches(stats, "soma", "mods", false, searchStatsMinSamples)
+	if skipped := skippedFor("forma"); skipped != nil || len(stats.terms) != 2 {
+		t.Errorf("expected the least recently used term evicted, skipped %v with %d terms", skipped, len(stats.terms))
+	}
+
+	stats.Reset()
+	if skipped := skippedFor("soma"); skipped != nil || len(stats.terms) != 0 {
+		t.Errorf("expected reset to forget every term, skipped %v", skipped)
+	}
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/database"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const settingsCollection = "user_settings"
+
+type SettingsRepository struct {
+	db         *database.MongoDB
+	collection *mongo.Collection
+}
+
+func NewSettingsRepository(db *database.MongoDB) *SettingsRepository {
+	return &SettingsRepository{
+		db:         db,
+		collection: db.Collection(settingsCollection),
+	}
+}
+
+func (r *SettingsRepository) GetByUserID(ctx context.Context, userID string) (*models.UserSettings, error) {
+	logger.Debug(ctx, "repo: SettingsRepository.GetByUserID called", "userID", userID)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{"userId": userID}
+	var settings models.UserSettings
+
+	err := findOne(ctx, "SettingsRepository.GetByUserID", r.collection, filter, &settings)
+	if err == mongo.ErrNoDocuments {
+		logger.Debug(ctx, "repo: SettingsRepository.GetByUserID - no settings found for user")
+		return nil, nil
+	}
+	if err != nil {
+		logger.Error(ctx, "repo: SettingsRepository.GetByUserID - error querying database", "error", err)
+		return nil, err
+	}
+
+	logger.Debug(ctx, "repo: SettingsRepository.GetByUserID - found settings")
+	return &settings, nil
+}
+
+func (r *SettingsRepository) Upsert(ctx context.Context, settings *models.UserSettings) error {
+	logger.Debug(ctx, "repo: SettingsRepository.Upsert called", "userID", settings.UserID)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{"userId": settings.UserID}
+	settings.UpdatedAt = time.Now()
+
+	opts := options.Update().SetUpsert(true)
+	update := bson.M{
+		"$set": bson.M{
+			"timeZone":            settings.TimeZone,
+			"defaultQuantities":   settings.DefaultQuantities,
+			"publicWishlist":      settings.PublicWishlist,
+			"mutedMilestones":     settings.MutedMilestones,
+			"materialsRounding":   settings.MaterialsRounding,
+			"skipStarterWishlist": settings.SkipStarterWishlist,
+			"digestTime":          settings.DigestTime,
+			"updatedAt":           settings.UpdatedAt,
+		},
+		"$setOnInsert": bson.M{
+			"userId":    settings.UserID,
+			"createdAt": time.Now(),
+		},
+	}
+
+	result, err := updateOne(ctx, "SettingsRepository.Upsert", r.collection, filter, update, opts)
+	if err != nil {
+		logger.Error(ctx, "repo: SettingsRepository.Upsert - error upserting settings", "error", err)
+		return err
+	}
+
+	logger.Debug(ctx, "repo: SettingsRepository.Upsert - completed", "matchedCount", result.MatchedCount, "modifiedCount", result.ModifiedCount, "upsertedCount", result.UpsertedCount)
+	return nil
+}
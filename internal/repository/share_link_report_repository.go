@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/database"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const shareLinkReportsCollection = "share_link_reports"
+
+type ShareLinkReportRepository struct {
+	db         *database.MongoDB
+	collection *mongo.Collection
+}
+
+func NewShareLinkReportRepository(db *database.MongoDB) *ShareLinkReportRepository {
+	return &ShareLinkReportRepository{
+		db:         db,
+		collection: db.Collection(shareLinkReportsCollection),
+	}
+}
+
+// EnsureIndexes creates the partial unique index that keeps one open report
+// per token and the index the queue is listed by.
+func (r *ShareLinkReportRepository) EnsureIndexes(ctx context.Context) error {
+	logger.Debug(ctx, "repo: ShareLinkReportRepository.EnsureIndexes called")
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "token", Value: 1}},
+			Options: options.Index().SetName("open_token").SetUnique(true).
+				SetPartialFilterExpression(bson.M{"status": models.ReportStatusOpen}),
+		},
+		{
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "createdAt", Value: 1}},
+			Options: options.Index().SetName("queue"),
+		},
+	})
+	if err != nil {
+		logger.Error(ctx, "repo: ShareLinkReportRepository.EnsureIndexes - error creating indexes", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (r *ShareLinkReportRepository) Report(ctx context.Context, token, ownerID, reason string, at time.Time) (*models.ShareLinkReport, error) {
+	logger.Debug(ctx, "repo: ShareLinkReportRepository.Report called", "ownerID", ownerID, "reason", reason)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{"token": token, "status": models.ReportStatusOpen}
+	update := bson.M{
+		"$inc":         bson.M{"count": 1},
+		"$addToSet":    bson.M{"reasons": reason},
+		"$set":         bson.M{"lastReportedAt": at},
+		"$setOnInsert": bson.M{"ownerId": ownerID, "createdAt": at},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	// $inc is not idempotent, so this is not retried on transient errors. Two
+	// first reports racing to upsert the same token collide on the open_token
+	// index; the loser counts towards the winner's entry.
+	var report models.ShareLinkReport
+	err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&report)
+	if mongo.IsDuplicateKeyError(err) {
+		err = r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&report)
+	}
+	if err != nil {
+		logger.Error(ctx, "repo: ShareLinkReportRepository.Report - error upserting report", "error", err)
+		return nil, err
+	}
+
+	return &report, nil
+}
+
+func (r *ShareLinkReportRepository) List(ctx context.Context, status string, limit int) ([]models.ShareLinkReport, error) {
+	logger.Debug(ctx, "repo: ShareLinkReportRepository.List called", "status", status, "limit", limit)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{}
+	if status != "" {
+		filter["status"] = status
+	}
+
+	reports := []models.ShareLinkReport{}
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}}).SetLimit(int64(limit))
+	if err := findAll(ctx, "ShareLinkReportRepository.List", r.collection, filter, &reports, opts); err != nil {
+		logger.Error(ctx, "repo: ShareLinkReportRepository.List - error querying database", "error", err)
+		return nil, err
+	}
+
+	logger.Debug(ctx, "repo: ShareLinkReportRepository.List - completed", "count", len(reports))
+	return reports, nil
+}
+
+func (r *ShareLinkReportRepository) Resolve(ctx context.Context, token, status string, at time.Time) (bool, error) {
+	logger.Debug(ctx, "repo: ShareLinkReportRepository.Resolve called", "status", status)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{"token": token, "status": models.ReportStatusOpen}
+	update := bson.M{"$set": bson.M{"status": status, "resolvedAt": at}}
+	result, err := updateOne(ctx, "ShareLinkReportRepository.Resolve", r.collection, filter, update)
+	if err != nil {
+		logger.Error(ctx, "repo: ShareLinkReportRepository.Resolve - error updating report", "error", err)
+		return false, err
+	}
+
+	return result.MatchedCount > 0, nil
+}
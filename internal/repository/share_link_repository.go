@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/database"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const shareLinksCollection = "share_links"
+
+type ShareLinkRepository struct {
+	db         *database.MongoDB
+	collection *mongo.Collection
+}
+
+func NewShareLinkRepository(db *database.MongoDB) *ShareLinkRepository {
+	return &ShareLinkRepository{
+		db:         db,
+		collection: db.Collection(shareLinksCollection),
+	}
+}
+
+// EnsureIndexes creates the unique token index shared views are looked up
+// by and the user index links are listed by.
+func (r *ShareLinkRepository) EnsureIndexes(ctx context.Context) error {
+	logger.Debug(ctx, "repo: ShareLinkRepository.EnsureIndexes called")
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "token", Value: 1}},
+			Options: options.Index().SetName("token").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "userId", Value: 1}, {Key: "createdAt", Value: 1}},
+			Options: options.Index().SetName("user"),
+		},
+	})
+	if err != nil {
+		logger.Error(ctx, "repo: ShareLinkRepository.EnsureIndexes - error creating indexes", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (r *ShareLinkRepository) Create(ctx context.Context, link *models.ShareLink) error {
+	logger.Debug(ctx, "repo: ShareLinkRepository.Create called", "userID", link.UserID)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	link.ID = primitive.NewObjectID()
+	if _, err := r.collection.InsertOne(ctx, link); err != nil {
+		logger.Error(ctx, "repo: ShareLinkRepository.Create - error inserting link", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *ShareLinkRepository) GetByToken(ctx context.Context, token string) (*models.ShareLink, error) {
+	logger.Debug(ctx, "repo: ShareLinkRepository.GetByToken called")
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var link models.ShareLink
+	err := findOne(ctx, "ShareLinkRepository.GetByToken", r.collection, bson.M{"token": token}, &link)
+	if err == mongo.ErrNoDocuments {
+		logger.Debug(ctx, "repo: ShareLinkRepository.GetByToken - no link found")
+		return nil, nil
+	}
+	if err != nil {
+		logger.Error(ctx, "repo: ShareLinkRepository.GetByToken - error querying database", "error", err)
+		return nil, err
+	}
+
+	return &link, nil
+}
+
+func (r *ShareLinkRepository) ListByUser(ctx context.Context, userID string) ([]models.ShareLink, error) {
+	logger.Debug(ctx, "repo: ShareLinkRepository.ListByUser called", "userID", userID)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	links := []models.ShareLink{}
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}})
+	if err := findAll(ctx, "ShareLinkRepository.ListByUser", r.collection, bson.M{"userId": userID}, &links, opts); err != nil {
+		logger.Error(ctx, "repo: ShareLinkRepository.ListByUser - error querying database", "error", err)
+		return nil, err
+	}
+
+	logger.Debug(ctx, "repo: ShareLinkRepository.ListByUser - completed", "count", len(links))
+	return links, nil
+}
+
+func (r *ShareLinkRepository) Delete(ctx context.Context, userID string, id primitive.ObjectID) (bool, error) {
+	logger.Debug(ctx, "repo: ShareLinkRepository.Delete called", "userID", userID, "id", id.Hex())
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id, "userId": userID})
+	if err != nil {
+		logger.Error(ctx, "repo: ShareLinkRepository.Delete - error deleting link", "error", err)
+		return false, err
+	}
+
+	return result.DeletedCount > 0, nil
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/database"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const syncStatusCollection = "sync_status"
+
+type SyncStatusRepository struct {
+	db         *database.MongoDB
+	collection *mongo.Collection
+}
+
+func NewSyncStatusRepository(db *database.MongoDB) *SyncStatusRepository {
+	return &SyncStatusRepository{
+		db:         db,
+		collection: db.Collection(syncStatusCollection),
+	}
+}
+
+func (r *SyncStatusRepository) Get(ctx context.Context, name string) (*models.SyncStatus, error) {
+	logger.Debug(ctx, "repo: SyncStatusRepository.Get called", "name", name)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var status models.SyncStatus
+	err := findOne(ctx, "SyncStatusRepository.Get", r.collection, bson.M{"_id": name}, &status)
+	if err == mongo.ErrNoDocuments {
+		logger.Debug(ctx, "repo: SyncStatusRepository.Get - no status recorded", "name", name)
+		return nil, nil
+	}
+	if err != nil {
+		logger.Error(ctx, "repo: SyncStatusRepository.Get - error querying database", "error", err)
+		return nil, err
+	}
+	if status.FailedCollections == nil {
+		status.FailedCollections = []string{}
+	}
+
+	return &status, nil
+}
+
+func (r *SyncStatusRepository) Upsert(ctx context.Context, status *models.SyncStatus) error {
+	logger.Debug(ctx, "repo: SyncStatusRepository.Upsert called", "name", status.Name)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	err := withRetry(ctx, "SyncStatusRepository.Upsert", func() error {
+		_, err := r.collection.ReplaceOne(ctx, bson.M{"_id": status.Name}, status, options.Replace().SetUpsert(true))
+		return err
+	})
+	if err != nil {
+		logger.Error(ctx, "repo: SyncStatusRepository.Upsert - error upserting status", "error", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/database"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const userTracesCollection = "user_traces"
+
+type UserTraceRepository struct {
+	db         *database.MongoDB
+	collection *mongo.Collection
+}
+
+func NewUserTraceRepository(db *database.MongoDB) *UserTraceRepository {
+	return &UserTraceRepository{
+		db:         db,
+		collection: db.Collection(userTracesCollection),
+	}
+}
+
+func (r *UserTraceRepository) GetByUserID(ctx context.Context, userID string) (*models.UserTrace, error) {
+	logger.Debug(ctx, "repo: UserTraceRepository.GetByUserID called", "userID", userID)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var trace models.UserTrace
+	err := findOne(ctx, "UserTraceRepository.GetByUserID", r.collection, bson.M{"userId": userID}, &trace)
+	if err == mongo.ErrNoDocuments {
+		logger.Debug(ctx, "repo: UserTraceRepository.GetByUserID - no trace found for user")
+		return nil, nil
+	}
+	if err != nil {
+		logger.Error(ctx, "repo: UserTraceRepository.GetByUserID - error querying database", "error", err)
+		return nil, err
+	}
+
+	return &trace, nil
+}
+
+func (r *UserTraceRepository) Upsert(ctx context.Context, trace *models.UserTrace) error {
+	logger.Debug(ctx, "repo: UserTraceRepository.Upsert called", "userID", trace.UserID)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	_, err := r.collection.ReplaceOne(ctx, bson.M{"userId": trace.UserID}, trace, options.Replace().SetUpsert(true))
+	if err != nil {
+		logger.Error(ctx, "repo: UserTraceRepository.Upsert - error upserting trace", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *UserTraceRepository) Delete(ctx context.Context, userID string) error {
+	logger.Debug(ctx, "repo: UserTraceRepository.Delete called", "userID", userID)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if _, err := r.collection.DeleteOne(ctx, bson.M{"userId": userID}); err != nil {
+		logger.Error(ctx, "repo: UserTraceRepository.Delete - error deleting trace", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *UserTraceRepository) ListActive(ctx context.Context, now time.Time) ([]models.UserTrace, error) {
+	logger.Debug(ctx, "repo: UserTraceRepository.ListActive called")
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "userId", Value: 1}})
+	traces := []models.UserTrace{}
+	if err := findAll(ctx, "UserTraceRepository.ListActive", r.collection, bson.M{"until": bson.M{"$gt": now}}, &traces, opts); err != nil {
+		logger.Error(ctx, "repo: UserTraceRepository.ListActive - error querying database", "error", err)
+		return nil, err
+	}
+
+	logger.Debug(ctx, "repo: UserTraceRepository.ListActive - completed", "count", len(traces))
+	return traces, nil
+}
@@ -34,9 +34,9 @@ func (r *WishlistRepository) GetByUserID(ctx context.Context, userID string) (*m
 	defer cancel()
 
 	filter := bson.M{"userId": userID}
-	var wishlist models.Wishlist
+	var raw bson.Raw
 
-	err := r.collection.FindOne(ctx, filter).Decode(&wishlist)
+	err := findOne(ctx, "WishlistRepository.GetByUserID", r.collection, filter, &raw)
 	if err == mongo.ErrNoDocuments {
 		logger.Debug(ctx, "repo: WishlistRepository.GetByUserID - no wishlist found for user")
 		return nil, nil
@@ -46,6 +46,19 @@ func (r *WishlistRepository) GetByUserID(ctx context.Context, userID string) (*m
 		return nil, err
 	}
 
+	var wishlist models.Wishlist
+	if err := bson.Unmarshal(raw, &wishlist); err != nil {
+		logger.Error(ctx, "repo: WishlistRepository.GetByUserID - error decoding wishlist", "error", err)
+		return nil, err
+	}
+	saved, err := migrateWishlistDocument(ctx, r.collection, raw, &wishlist)
+	if err != nil {
+		// The migrated copy is still valid to serve; the next read retries.
+		logger.Warn(ctx, "repo: WishlistRepository.GetByUserID - failed to persist migrated wishlist", "error", err)
+	} else if saved {
+		logger.Debug(ctx, "repo: WishlistRepository.GetByUserID - migrated wishlist on read", "schemaVersion", wishlist.SchemaVersion)
+	}
+
 	logger.Debug(ctx, "repo: WishlistRepository.GetByUserID - found wishlist", "itemCount", len(wishlist.Items))
 	return &wishlist, nil
 }
@@ -58,6 +71,7 @@ func (r *WishlistRepository) Create(ctx context.Context, wishlist *models.Wishli
 
 	wishlist.CreatedAt = time.Now()
 	wishlist.UpdatedAt = time.Now()
+	wishlist.SchemaVersion = CurrentWishlistSchemaVersion
 	if wishlist.Items == nil {
 		wishlist.Items = []models.WishlistItem{}
 	}
@@ -73,6 +87,46 @@ func (r *WishlistRepository) Create(ctx context.Context, wishlist *models.Wishli
 	return nil
 }
 
+// CreateIfAbsent upserts on userId with every field in $setOnInsert, so a
+// wishlist created meanwhile, by this instance or another, is left as it is.
+func (r *WishlistRepository) CreateIfAbsent(ctx context.Context, wishlist *models.Wishlist) (bool, error) {
+	logger.Debug(ctx, "repo: WishlistRepository.CreateIfAbsent called", "userID", wishlist.UserID)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	wishlist.CreatedAt = time.Now()
+	wishlist.UpdatedAt = wishlist.CreatedAt
+	wishlist.SchemaVersion = CurrentWishlistSchemaVersion
+	if wishlist.Items == nil {
+		wishlist.Items = []models.WishlistItem{}
+	}
+
+	update := bson.M{
+		"$setOnInsert": bson.M{
+			"userId":        wishlist.UserID,
+			"items":         wishlist.Items,
+			"schemaVersion": wishlist.SchemaVersion,
+			"createdAt":     wishlist.CreatedAt,
+			"updatedAt":     wishlist.UpdatedAt,
+		},
+	}
+	opts := options.Update().SetUpsert(true)
+	result, err := updateOne(ctx, "WishlistRepository.CreateIfAbsent", r.collection, bson.M{"userId": wishlist.UserID}, update, opts)
+	if err != nil {
+		logger.Error(ctx, "repo: WishlistRepository.CreateIfAbsent - error upserting wishlist", "error", err)
+		return false, err
+	}
+	if result.UpsertedCount == 0 {
+		logger.Debug(ctx, "repo: WishlistRepository.CreateIfAbsent - wishlist already exists")
+		return false, nil
+	}
+
+	wishlist.ID, _ = result.UpsertedID.(primitive.ObjectID)
+	logger.Info(ctx, "repo: WishlistRepository.CreateIfAbsent - wishlist created", "wishlistID", wishlist.ID.Hex())
+	return true, nil
+}
+
 func (r *WishlistRepository) AddItem(ctx context.Context, userID string, item models.WishlistItem) error {
 	logger.Debug(ctx, "repo: WishlistRepository.AddItem called", "userID", userID, "uniqueName", item.UniqueName, "quantity", item.Quantity)
 
@@ -107,7 +161,7 @@ func (r *WishlistRepository) RemoveItem(ctx context.Context, userID, uniqueName
 		"$set":  bson.M{"updatedAt": time.Now()},
 	}
 
-	result, err := r.collection.UpdateOne(ctx, filter, update)
+	result, err := updateOne(ctx, "WishlistRepository.RemoveItem", r.collection, filter, update)
 	if err != nil {
 		logger.Error(ctx, "repo: WishlistRepository.RemoveItem - error updating wishlist", "error", err)
 		return err
@@ -134,7 +188,7 @@ func (r *WishlistRepository) UpdateItemQuantity(ctx context.Context, userID, uni
 		},
 	}
 
-	result, err := r.collection.UpdateOne(ctx, filter, update)
+	result, err := updateOne(ctx, "WishlistRepository.UpdateItemQuantity", r.collection, filter, update)
 	if err != nil {
 		logger.Error(ctx, "repo: WishlistRepository.UpdateItemQuantity - error updating wishlist", "error", err)
 		return err
@@ -144,6 +198,93 @@ func (r *WishlistRepository) UpdateItemQuantity(ctx context.Context, userID, uni
 	return nil
 }
 
+func (r *WishlistRepository) SetItemLinks(ctx context.Context, userID, uniqueName string, links []models.SourceLink) error {
+	logger.Debug(ctx, "repo: WishlistRepository.SetItemLinks called", "userID", userID, "uniqueName", uniqueName, "linkCount", len(links))
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"userId":           userID,
+		"items.uniqueName": uniqueName,
+	}
+	update := bson.M{
+		"$set": bson.M{"updatedAt": time.Now()},
+	}
+	if len(links) == 0 {
+		update["$unset"] = bson.M{"items.$.links": ""}
+	} else {
+		update["$set"].(bson.M)["items.$.links"] = links
+	}
+
+	result, err := updateOne(ctx, "WishlistRepository.SetItemLinks", r.collection, filter, update)
+	if err != nil {
+		logger.Error(ctx, "repo: WishlistRepository.SetItemLinks - error updating wishlist", "error", err)
+		return err
+	}
+
+	logger.Debug(ctx, "repo: WishlistRepository.SetItemLinks - completed", "matchedCount", result.MatchedCount, "modifiedCount", result.ModifiedCount)
+	return nil
+}
+
+func (r *WishlistRepository) SetItemRecipe(ctx context.Context, userID, uniqueName, recipeID string) error {
+	logger.Debug(ctx, "repo: WishlistRepository.SetItemRecipe called", "userID", userID, "uniqueName", uniqueName, "recipeID", recipeID)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"userId":           userID,
+		"items.uniqueName": uniqueName,
+	}
+	update := bson.M{
+		"$set": bson.M{"updatedAt": time.Now()},
+	}
+	if recipeID == "" {
+		update["$unset"] = bson.M{"items.$.recipeId": ""}
+	} else {
+		update["$set"].(bson.M)["items.$.recipeId"] = recipeID
+	}
+
+	result, err := updateOne(ctx, "WishlistRepository.SetItemRecipe", r.collection, filter, update)
+	if err != nil {
+		logger.Error(ctx, "repo: WishlistRepository.SetItemRecipe - error updating wishlist", "error", err)
+		return err
+	}
+
+	logger.Debug(ctx, "repo: WishlistRepository.SetItemRecipe - completed", "matchedCount", result.MatchedCount, "modifiedCount", result.ModifiedCount)
+	return nil
+}
+
+func (r *WishlistRepository) SetItemProgress(ctx context.Context, userID, uniqueName string, progress []models.ComponentProgress) error {
+	logger.Debug(ctx, "repo: WishlistRepository.SetItemProgress called", "userID", userID, "uniqueName", uniqueName, "componentCount", len(progress))
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"userId":           userID,
+		"items.uniqueName": uniqueName,
+	}
+	update := bson.M{
+		"$set": bson.M{"updatedAt": time.Now()},
+	}
+	if len(progress) == 0 {
+		update["$unset"] = bson.M{"items.$.progress": ""}
+	} else {
+		update["$set"].(bson.M)["items.$.progress"] = progress
+	}
+
+	result, err := updateOne(ctx, "WishlistRepository.SetItemProgress", r.collection, filter, update)
+	if err != nil {
+		logger.Error(ctx, "repo: WishlistRepository.SetItemProgress - error updating wishlist", "error", err)
+		return err
+	}
+
+	logger.Debug(ctx, "repo: WishlistRepository.SetItemProgress - completed", "matchedCount", result.MatchedCount, "modifiedCount", result.ModifiedCount)
+	return nil
+}
+
 func (r *WishlistRepository) Upsert(ctx context.Context, wishlist *models.Wishlist) error {
 	logger.Debug(ctx, "repo: WishlistRepository.Upsert called", "userID", wishlist.UserID, "itemCount", len(wishlist.Items))
 
@@ -152,12 +293,14 @@ func (r *WishlistRepository) Upsert(ctx context.Context, wishlist *models.Wishli
 
 	filter := bson.M{"userId": wishlist.UserID}
 	wishlist.UpdatedAt = time.Now()
+	wishlist.SchemaVersion = CurrentWishlistSchemaVersion
 
 	opts := options.Update().SetUpsert(true)
 	update := bson.M{
 		"$set": bson.M{
-			"items":     wishlist.Items,
-			"updatedAt": wishlist.UpdatedAt,
+			"items":         wishlist.Items,
+			"schemaVersion": wishlist.SchemaVersion,
+			"updatedAt":     wishlist.UpdatedAt,
 		},
 		"$setOnInsert": bson.M{
 			"userId":    wishlist.UserID,
@@ -165,7 +308,7 @@ func (r *WishlistRepository) Upsert(ctx context.Context, wishlist *models.Wishli
 		},
 	}
 
-	result, err := r.collection.UpdateOne(ctx, filter, update, opts)
+	result, err := updateOne(ctx, "WishlistRepository.Upsert", r.collection, filter, update, opts)
 	if err != nil {
 		logger.Error(ctx, "repo: WishlistRepository.Upsert - error upserting wishlist", "error", err)
 		return err
@@ -174,3 +317,73 @@ func (r *WishlistRepository) Upsert(ctx context.Context, wishlist *models.Wishli
 	logger.Debug(ctx, "repo: WishlistRepository.Upsert - completed", "matchedCount", result.MatchedCount, "modifiedCount", result.ModifiedCount, "upsertedCount", result.UpsertedCount)
 	return nil
 }
+
+// TopWishlistedItems returns the items on the most wishlists, counting each
+// user at most once per item, ordered by count and then uniqueName.
+func (r *WishlistRepository) TopWishlistedItems(ctx context.Context, limit int) ([]models.ItemPopularity, error) {
+	logger.Debug(ctx, "repo: WishlistRepository.TopWishlistedItems called", "limit", limit)
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$unwind", Value: "$items"}},
+		{{Key: "$group", Value: bson.M{"_id": bson.M{"userId": "$userId", "uniqueName": "$items.uniqueName"}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$_id.uniqueName", "wishlistCount": bson.M{"$sum": 1}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "wishlistCount", Value: -1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$limit", Value: limit}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		logger.Error(ctx, "repo: WishlistRepository.TopWishlistedItems - error aggregating", "error", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	popularity := []models.ItemPopularity{}
+	if err := cursor.All(ctx, &popularity); err != nil {
+		logger.Error(ctx, "repo: WishlistRepository.TopWishlistedItems - error decoding results", "error", err)
+		return nil, err
+	}
+
+	logger.Debug(ctx, "repo: WishlistRepository.TopWishlistedItems - completed", "itemCount", len(popularity))
+	return popularity, nil
+}
+
+// WishlistSizeStats counts wishlists and the items on them across all users.
+func (r *WishlistRepository) WishlistSizeStats(ctx context.Context) (*models.WishlistSizeStats, error) {
+	logger.Debug(ctx, "repo: WishlistRepository.WishlistSizeStats called")
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{
+			"_id":        nil,
+			"wishlists":  bson.M{"$sum": 1},
+			"totalItems": bson.M{"$sum": bson.M{"$size": bson.M{"$ifNull": bson.A{"$items", bson.A{}}}}},
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		logger.Error(ctx, "repo: WishlistRepository.WishlistSizeStats - error aggregating", "error", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	stats := &models.WishlistSizeStats{}
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(stats); err != nil {
+			logger.Error(ctx, "repo: WishlistRepository.WishlistSizeStats - error decoding result", "error", err)
+			return nil, err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	logger.Debug(ctx, "repo: WishlistRepository.WishlistSizeStats - completed", "wishlists", stats.Wishlists, "totalItems", stats.TotalItems)
+	return stats, nil
+}
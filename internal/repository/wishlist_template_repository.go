@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/database"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const wishlistTemplatesCollection = "wishlist_templates"
+
+type WishlistTemplateRepository struct {
+	db         *database.MongoDB
+	collection *mongo.Collection
+}
+
+func NewWishlistTemplateRepository(db *database.MongoDB) *WishlistTemplateRepository {
+	return &WishlistTemplateRepository{
+		db:         db,
+		collection: db.Collection(wishlistTemplatesCollection),
+	}
+}
+
+// EnsureIndexes creates the index a user's templates are listed by.
+func (r *WishlistTemplateRepository) EnsureIndexes(ctx context.Context) error {
+	logger.Debug(ctx, "repo: WishlistTemplateRepository.EnsureIndexes called")
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "userId", Value: 1}, {Key: "createdAt", Value: 1}},
+		Options: options.Index().SetName("user_created"),
+	})
+	if err != nil {
+		logger.Error(ctx, "repo: WishlistTemplateRepository.EnsureIndexes - error creating indexes", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (r *WishlistTemplateRepository) ListByUser(ctx context.Context, userID string) ([]models.WishlistTemplate, error) {
+	logger.Debug(ctx, "repo: WishlistTemplateRepository.ListByUser called", "userID", userID)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	templates := []models.WishlistTemplate{}
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}})
+	if err := findAll(ctx, "WishlistTemplateRepository.ListByUser", r.collection, bson.M{"userId": userID}, &templates, opts); err != nil {
+		logger.Error(ctx, "repo: WishlistTemplateRepository.ListByUser - error querying database", "error", err)
+		return nil, err
+	}
+
+	logger.Debug(ctx, "repo: WishlistTemplateRepository.ListByUser - completed", "count", len(templates))
+	return templates, nil
+}
+
+func (r *WishlistTemplateRepository) Get(ctx context.Context, userID string, id primitive.ObjectID) (*models.WishlistTemplate, error) {
+	logger.Debug(ctx, "repo: WishlistTemplateRepository.Get called", "userID", userID, "id", id.Hex())
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var template models.WishlistTemplate
+	err := findOne(ctx, "WishlistTemplateRepository.Get", r.collection, bson.M{"_id": id, "userId": userID}, &template)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		logger.Error(ctx, "repo: WishlistTemplateRepository.Get - error querying database", "error", err)
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (r *WishlistTemplateRepository) Create(ctx context.Context, template *models.WishlistTemplate) error {
+	logger.Debug(ctx, "repo: WishlistTemplateRepository.Create called", "userID", template.UserID, "name", template.Name)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	template.ID = primitive.NewObjectID()
+	if _, err := r.collection.InsertOne(ctx, template); err != nil {
+		logger.Error(ctx, "repo: WishlistTemplateRepository.Create - error inserting template", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *WishlistTemplateRepository) Delete(ctx context.Context, userID string, id primitive.ObjectID) (bool, error) {
+	logger.Debug(ctx, "repo: WishlistTemplateRepository.Delete called", "userID", userID, "id", id.Hex())
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id, "userId": userID})
+	if err != nil {
+		logger.Error(ctx, "repo: WishlistTemplateRepository.Delete - error deleting template", "error", err)
+		return false, err
+	}
+
+	return result.DeletedCount > 0, nil
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/database"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const workspaceContributionsCollection = "workspace_contributions"
+
+type WorkspaceContributionRepository struct {
+	db         *database.MongoDB
+	collection *mongo.Collection
+}
+
+func NewWorkspaceContributionRepository(db *database.MongoDB) *WorkspaceContributionRepository {
+	return &WorkspaceContributionRepository{
+		db:         db,
+		collection: db.Collection(workspaceContributionsCollection),
+	}
+}
+
+// EnsureIndexes creates the indexes a workspace's activity feed and
+// contribution totals, and a user's event count, are read by.
+func (r *WorkspaceContributionRepository) EnsureIndexes(ctx context.Context) error {
+	logger.Debug(ctx, "repo: WorkspaceContributionRepository.EnsureIndexes called")
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "workspaceId", Value: 1}, {Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}},
+			Options: options.Index().SetName("workspace_created"),
+		},
+		{
+			Keys:    bson.D{{Key: "userId", Value: 1}},
+			Options: options.Index().SetName("user"),
+		},
+	})
+	if err != nil {
+		logger.Error(ctx, "repo: WorkspaceContributionRepository.EnsureIndexes - error creating indexes", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (r *WorkspaceContributionRepository) Create(ctx context.Context, event *models.WorkspaceContributionEvent) error {
+	logger.Debug(ctx, "repo: WorkspaceContributionRepository.Create called", "workspaceID", event.WorkspaceID.Hex(), "kind", event.Kind)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	event.ID = primitive.NewObjectID()
+	if _, err := r.collection.InsertOne(ctx, event); err != nil {
+		logger.Error(ctx, "repo: WorkspaceContributionRepository.Create - error inserting event", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *WorkspaceContributionRepository) ListByWorkspace(ctx context.Context, workspaceID primitive.ObjectID, limit int) ([]models.WorkspaceContributionEvent, error) {
+	logger.Debug(ctx, "repo: WorkspaceContributionRepository.ListByWorkspace called", "workspaceID", workspaceID.Hex(), "limit", limit)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(int64(limit))
+	events := []models.WorkspaceContributionEvent{}
+	if err := findAll(ctx, "WorkspaceContributionRepository.ListByWorkspace", r.collection, bson.M{"workspaceId": workspaceID}, &events, opts); err != nil {
+		logger.Error(ctx, "repo: WorkspaceContributionRepository.ListByWorkspace - error querying database", "error", err)
+		return nil, err
+	}
+
+	logger.Debug(ctx, "repo: WorkspaceContributionRepository.ListByWorkspace - completed", "count", len(events))
+	return events, nil
+}
+
+func (r *WorkspaceContributionRepository) MaterialTotals(ctx context.Context, workspaceID primitive.ObjectID) ([]models.WorkspaceContributionTotal, error) {
+	logger.Debug(ctx, "repo: WorkspaceContributionRepository.MaterialTotals called", "workspaceID", workspaceID.Hex())
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"workspaceId": workspaceID, "kind": models.ContributionKindMaterial}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":      bson.M{"uniqueName": "$uniqueName", "userId": "$userId"},
+			"quantity": bson.M{"$sum": "$quantity"},
+		}}},
+		{{Key: "$match", Value: bson.M{"quantity": bson.M{"$ne": 0}}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":        0,
+			"uniqueName": "$_id.uniqueName",
+			"userId":     "$_id.userId",
+			"quantity":   1,
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "uniqueName", Value: 1}, {Key: "userId", Value: 1}}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		logger.Error(ctx, "repo: WorkspaceContributionRepository.MaterialTotals - error aggregating", "error", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	totals := []models.WorkspaceContributionTotal{}
+	if err := cursor.All(ctx, &totals); err != nil {
+		logger.Error(ctx, "repo: WorkspaceContributionRepository.MaterialTotals - error decoding results", "error", err)
+		return nil, err
+	}
+
+	logger.Debug(ctx, "repo: WorkspaceContributionRepository.MaterialTotals - completed", "count", len(totals))
+	return totals, nil
+}
+
+func (r *WorkspaceContributionRepository) CountByUser(ctx context.Context, userID string) (int, error) {
+	logger.Debug(ctx, "repo: WorkspaceContributionRepository.CountByUser called", "userID", userID)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	count, err := r.collection.CountDocuments(ctx, bson.M{"userId": userID})
+	if err != nil {
+		logger.Error(ctx, "repo: WorkspaceContributionRepository.CountByUser - error counting events", "error", err)
+		return 0, err
+	}
+	return int(count), nil
+}
+
+func (r *WorkspaceContributionRepository) DeleteByWorkspace(ctx context.Context, workspaceID primitive.ObjectID) error {
+	logger.Debug(ctx, "repo: WorkspaceContributionRepository.DeleteByWorkspace called", "workspaceID", workspaceID.Hex())
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if _, err := r.collection.DeleteMany(ctx, bson.M{"workspaceId": workspaceID}); err != nil {
+		logger.Error(ctx, "repo: WorkspaceContributionRepository.DeleteByWorkspace - error deleting events", "error", err)
+		return err
+	}
+
+	return nil
+}
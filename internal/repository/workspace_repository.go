@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/database"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const workspacesCollection = "workspaces"
+
+type WorkspaceRepository struct {
+	db         *database.MongoDB
+	collection *mongo.Collection
+}
+
+func NewWorkspaceRepository(db *database.MongoDB) *WorkspaceRepository {
+	return &WorkspaceRepository{
+		db:         db,
+		collection: db.Collection(workspacesCollection),
+	}
+}
+
+// EnsureIndexes creates the index a member's workspaces are listed by.
+func (r *WorkspaceRepository) EnsureIndexes(ctx context.Context) error {
+	logger.Debug(ctx, "repo: WorkspaceRepository.EnsureIndexes called")
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "members.userId", Value: 1}},
+		Options: options.Index().SetName("member"),
+	})
+	if err != nil {
+		logger.Error(ctx, "repo: WorkspaceRepository.EnsureIndexes - error creating indexes", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (r *WorkspaceRepository) Create(ctx context.Context, workspace *models.Workspace) error {
+	logger.Debug(ctx, "repo: WorkspaceRepository.Create called", "name", workspace.Name)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	workspace.ID = primitive.NewObjectID()
+	if _, err := r.collection.InsertOne(ctx, workspace); err != nil {
+		logger.Error(ctx, "repo: WorkspaceRepository.Create - error inserting workspace", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *WorkspaceRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*models.Workspace, error) {
+	logger.Debug(ctx, "repo: WorkspaceRepository.GetByID called", "id", id.Hex())
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var workspace models.Workspace
+	err := findOne(ctx, "WorkspaceRepository.GetByID", r.collection, bson.M{"_id": id}, &workspace)
+	if err == mongo.ErrNoDocuments {
+		logger.Debug(ctx, "repo: WorkspaceRepository.GetByID - no workspace found")
+		return nil, nil
+	}
+	if err != nil {
+		logger.Error(ctx, "repo: WorkspaceRepository.GetByID - error querying database", "error", err)
+		return nil, err
+	}
+
+	normalizeWorkspace(&workspace)
+	return &workspace, nil
+}
+
+func (r *WorkspaceRepository) ListByMember(ctx context.Context, userID string) ([]models.Workspace, error) {
+	logger.Debug(ctx, "repo: WorkspaceRepository.ListByMember called", "userID", userID)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	workspaces := []models.Workspace{}
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}, {Key: "_id", Value: 1}})
+	if err := findAll(ctx, "WorkspaceRepository.ListByMember", r.collection, bson.M{"members.userId": userID}, &workspaces, opts); err != nil {
+		logger.Error(ctx, "repo: WorkspaceRepository.ListByMember - error querying database", "error", err)
+		return nil, err
+	}
+	for i := range workspaces {
+		normalizeWorkspace(&workspaces[i])
+	}
+
+	logger.Debug(ctx, "repo: WorkspaceRepository.ListByMember - completed", "count", len(workspaces))
+	return workspaces, nil
+}
+
+func (r *WorkspaceRepository) Update(ctx context.Context, workspace *models.Workspace) (bool, error) {
+	logger.Debug(ctx, "repo: WorkspaceRepository.Update called", "id", workspace.ID.Hex(), "version", workspace.Version)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": workspace.ID, "version": workspace.Version}
+	update := bson.M{
+		"$set": bson.M{
+			"name":        workspace.Name,
+			"description": workspace.Description,
+			"members":     workspace.Members,
+			"items":       workspace.Items,
+			"updatedAt":   workspace.UpdatedAt,
+		},
+		"$inc": bson.M{"version": 1},
+	}
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		logger.Error(ctx, "repo: WorkspaceRepository.Update - error updating workspace", "error", err)
+		return false, err
+	}
+	if result.MatchedCount == 0 {
+		logger.Debug(ctx, "repo: WorkspaceRepository.Update - workspace missing or changed since read")
+		return false, nil
+	}
+
+	workspace.Version++
+	return true, nil
+}
+
+func (r *WorkspaceRepository) Delete(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	logger.Debug(ctx, "repo: WorkspaceRepository.Delete called", "id", id.Hex())
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		logger.Error(ctx, "repo: WorkspaceRepository.Delete - error deleting workspace", "error", err)
+		return false, err
+	}
+
+	return result.DeletedCount > 0, nil
+}
+
+// normalizeWorkspace replaces the nil slices a document without members,
+// items or contributions decodes to with empty ones.
+func normalizeWorkspace(workspace *models.Workspace) {
+	if workspace.Members == nil {
+		workspace.Members = []models.WorkspaceMember{}
+	}
+	if workspace.Items == nil {
+		workspace.Items = []models.WorkspaceItem{}
+	}
+	for i := range workspace.Items {
+		if workspace.Items[i].Contributions == nil {
+			workspace.Items[i].Contributions = []models.WorkspaceContribution{}
+		}
+	}
+}
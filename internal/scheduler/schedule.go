@@ -0,0 +1,59 @@
+// Package scheduler computes wall-clock schedules in a user's time zone.
+//
+// All calculations are done on local calendar dates and converted back to
+// absolute instants, so a "daily at 09:00" schedule stays at 09:00 local time
+// across DST transitions instead of drifting by an hour.
+package scheduler
+
+import "time"
+
+// NextDailyRun returns the next instant strictly after now at which the local
+// clock in loc reads hour:minute. If that local time does not exist on a given
+// day (spring-forward gap), the run is shifted forward by the size of the gap,
+// matching time.Date's normalization.
+func NextDailyRun(now time.Time, loc *time.Location, hour, minute int) time.Time {
+	if loc == nil {
+		loc = time.UTC
+	}
+	local := now.In(loc)
+	next := time.Date(local.Year(), local.Month(), local.Day(), hour, minute, 0, 0, loc)
+	if !next.After(now) {
+		next = time.Date(local.Year(), local.Month(), local.Day()+1, hour, minute, 0, 0, loc)
+	}
+	return next
+}
+
+// LastDailyRun returns the latest instant at or before now at which the local
+// clock in loc reads hour:minute, normalized like NextDailyRun.
+func LastDailyRun(now time.Time, loc *time.Location, hour, minute int) time.Time {
+	if loc == nil {
+		loc = time.UTC
+	}
+	local := now.In(loc)
+	last := time.Date(local.Year(), local.Month(), local.Day(), hour, minute, 0, 0, loc)
+	if last.After(now) {
+		last = time.Date(local.Year(), local.Month(), local.Day()-1, hour, minute, 0, 0, loc)
+	}
+	return last
+}
+
+// EndOfLocalDay returns the last instant of the calendar day containing t in
+// loc, so "later today" ends at local midnight, not UTC midnight.
+func EndOfLocalDay(t time.Time, loc *time.Location) time.Time {
+	if loc == nil {
+		loc = time.UTC
+	}
+	local := t.In(loc)
+	startOfNext := time.Date(local.Year(), local.Month(), local.Day()+1, 0, 0, 0, 0, loc)
+	return startOfNext.Add(-time.Nanosecond)
+}
+
+// CompletesAt returns the instant a job started at start finishes after
+// duration. Build timers run on real elapsed time, so this is plain addition;
+// the result is expressed in loc for display.
+func CompletesAt(start time.Time, duration time.Duration, loc *time.Location) time.Time {
+	if loc == nil {
+		loc = time.UTC
+	}
+	return start.Add(duration).In(loc)
+}
@@ -0,0 +1,137 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("time zone %s unavailable: %v", name, err)
+	}
+	return loc
+}
+
+func TestNextDailyRun_SameDay(t *testing.T) {
+	loc := mustLoad(t, "America/New_York")
+	now := time.Date(2026, 6, 1, 7, 0, 0, 0, loc)
+
+	next := NextDailyRun(now, loc, 9, 0)
+
+	expected := time.Date(2026, 6, 1, 9, 0, 0, 0, loc)
+	if !next.Equal(expected) {
+		t.Errorf("expected %v, got %v", expected, next)
+	}
+}
+
+func TestNextDailyRun_NextDay(t *testing.T) {
+	loc := mustLoad(t, "Australia/Sydney")
+	now := time.Date(2026, 6, 1, 9, 0, 0, 0, loc)
+
+	next := NextDailyRun(now, loc, 9, 0)
+
+	expected := time.Date(2026, 6, 2, 9, 0, 0, 0, loc)
+	if !next.Equal(expected) {
+		t.Errorf("expected %v, got %v", expected, next)
+	}
+}
+
+func TestNextDailyRun_AcrossSpringForward(t *testing.T) {
+	loc := mustLoad(t, "America/New_York")
+	// DST starts 2026-03-08 at 02:00 local. A 09:00 digest should stay at 09:00
+	// local, which is 23 hours after the previous run rather than 24.
+	prev := time.Date(2026, 3, 7, 9, 0, 0, 0, loc)
+
+	next := NextDailyRun(prev, loc, 9, 0)
+
+	if next.In(loc).Hour() != 9 {
+		t.Errorf("expected local hour 9, got %d", next.In(loc).Hour())
+	}
+	if gap := next.Sub(prev); gap != 23*time.Hour {
+		t.Errorf("expected 23h between runs across spring-forward, got %v", gap)
+	}
+}
+
+func TestNextDailyRun_AcrossFallBack(t *testing.T) {
+	loc := mustLoad(t, "Europe/Berlin")
+	// DST ends 2026-10-25 at 03:00 local.
+	prev := time.Date(2026, 10, 24, 9, 0, 0, 0, loc)
+
+	next := NextDailyRun(prev, loc, 9, 0)
+
+	if next.In(loc).Hour() != 9 {
+		t.Errorf("expected local hour 9, got %d", next.In(loc).Hour())
+	}
+	if gap := next.Sub(prev); gap != 25*time.Hour {
+		t.Errorf("expected 25h between runs across fall-back, got %v", gap)
+	}
+}
+
+func TestNextDailyRun_NonexistentLocalTime(t *testing.T) {
+	loc := mustLoad(t, "America/New_York")
+	now := time.Date(2026, 3, 8, 0, 0, 0, 0, loc)
+
+	next := NextDailyRun(now, loc, 2, 30)
+
+	if !next.After(now) {
+		t.Errorf("expected run after now, got %v", next)
+	}
+	if next.In(loc).Day() != 8 {
+		t.Errorf("expected run on the same local day, got %v", next.In(loc))
+	}
+}
+
+func TestLastDailyRun(t *testing.T) {
+	loc := mustLoad(t, "America/New_York")
+
+	now := time.Date(2026, 6, 1, 9, 0, 0, 0, loc)
+	if last := LastDailyRun(now, loc, 9, 0); !last.Equal(now) {
+		t.Errorf("expected a run at now to count, got %v", last)
+	}
+
+	now = time.Date(2026, 6, 1, 8, 59, 0, 0, loc)
+	if last := LastDailyRun(now, loc, 9, 0); !last.Equal(time.Date(2026, 5, 31, 9, 0, 0, 0, loc)) {
+		t.Errorf("expected the previous day's run, got %v", last)
+	}
+}
+
+func TestLastDailyRun_AcrossSpringForward(t *testing.T) {
+	loc := mustLoad(t, "America/New_York")
+	// Just before the first run after DST starts, the last run was at 09:00
+	// EST the day before, 23 hours before the next.
+	now := time.Date(2026, 3, 8, 8, 0, 0, 0, loc)
+
+	last := LastDailyRun(now, loc, 9, 0)
+	next := NextDailyRun(now, loc, 9, 0)
+
+	if last.In(loc).Hour() != 9 || last.In(loc).Day() != 7 {
+		t.Errorf("expected 09:00 on March 7, got %v", last.In(loc))
+	}
+	if gap := next.Sub(last); gap != 23*time.Hour {
+		t.Errorf("expected 23h between runs, got %v", gap)
+	}
+}
+
+func TestEndOfLocalDay(t *testing.T) {
+	loc := mustLoad(t, "America/Los_Angeles")
+
+	// 2026-06-11 03:00 UTC is still June 10 in Los Angeles.
+	end := EndOfLocalDay(time.Date(2026, 6, 11, 3, 0, 0, 0, time.UTC), loc)
+	if local := end.In(loc); local.Day() != 10 || local.Hour() != 23 || local.Minute() != 59 {
+		t.Errorf("expected the end of June 10 local, got %v", local)
+	}
+}
+
+func TestCompletesAt_AcrossDST(t *testing.T) {
+	loc := mustLoad(t, "America/New_York")
+	start := time.Date(2026, 3, 7, 22, 0, 0, 0, loc)
+
+	done := CompletesAt(start, 12*time.Hour, loc)
+
+	// Twelve elapsed hours across the spring-forward gap lands at 11:00 local.
+	if done.Hour() != 11 {
+		t.Errorf("expected completion at 11:00 local, got %v", done)
+	}
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+)
+
+const (
+	// defaultActivityLimit and maxActivityLimit bound ListActivity pages.
+	defaultActivityLimit = 50
+	maxActivityLimit     = 200
+)
+
+// ActivityService reads users' activity logs, which the activity repository
+// decorators write on every wishlist and owned blueprint change, so users can
+// review their recent changes.
+type ActivityService struct {
+	repo repository.ActivityRepositoryInterface
+}
+
+func NewActivityService(repo repository.ActivityRepositoryInterface) *ActivityService {
+	return &ActivityService{repo: repo}
+}
+
+// ListActivity returns the user's entries newest first, skipping offset of
+// them. A limit of 0 or less selects the default; a negative offset starts
+// at the newest.
+func (s *ActivityService) ListActivity(ctx context.Context, userID string, offset, limit int) (*models.ActivityPage, error) {
+	logger.Debug(ctx, "service: ActivityService.ListActivity called", "userID", userID, "offset", offset, "limit", limit)
+
+	if limit <= 0 {
+		limit = defaultActivityLimit
+	}
+	limit = min(limit, maxActivityLimit)
+	offset = max(offset, 0)
+
+	entries, total, err := s.repo.ListByUser(ctx, userID, offset, limit)
+	if err != nil {
+		logger.Error(ctx, "service: ActivityService.ListActivity - error fetching activity", "error", err)
+		return nil, err
+	}
+
+	return &models.ActivityPage{Entries: entries, Total: total, Limit: limit, Offset: offset}, nil
+}
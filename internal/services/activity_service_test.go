@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository/memory"
+)
+
+func TestActivityService_ListActivity(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewActivityRepository()
+	now := time.Now()
+	for i := range 3 {
+		repo.Create(ctx, &models.ActivityEntry{UserID: "user-1", Target: models.ActivityTargetWishlist, Action: models.ActivityAdd, Payload: "{}", CreatedAt: now.Add(time.Duration(i) * time.Minute)})
+	}
+	repo.Create(ctx, &models.ActivityEntry{UserID: "user-2", Action: models.ActivityAdd, CreatedAt: now})
+	service := NewActivityService(repo)
+
+	page, err := service.ListActivity(ctx, "user-1", 1, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if page.Total != 3 || page.Offset != 1 || page.Limit != 1 || len(page.Entries) != 1 {
+		t.Fatalf("expected the second of 3 entries, got %+v", page)
+	}
+	if !page.Entries[0].CreatedAt.Equal(now.Add(time.Minute)) {
+		t.Errorf("expected newest first, got %+v", page.Entries[0])
+	}
+}
+
+func TestActivityService_ListActivity_Bounds(t *testing.T) {
+	var gotOffset, gotLimit int
+	service := NewActivityService(&mocks.MockActivityRepository{
+		ListByUserFunc: func(ctx context.Context, userID string, offset, limit int) ([]models.ActivityEntry, int, error) {
+			gotOffset, gotLimit = offset, limit
+			return []models.ActivityEntry{}, 0, nil
+		},
+	})
+
+	tests := []struct {
+		offset, limit         int
+		wantOffset, wantLimit int
+	}{
+		{0, 0, 0, defaultActivityLimit},
+		{-5, -1, 0, defaultActivityLimit},
+		{10, 20, 10, 20},
+		{0, 1000, 0, maxActivityLimit},
+	}
+	for _, tt := range tests {
+		page, err := service.ListActivity(context.Background(), "user-1", tt.offset, tt.limit)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if gotOffset != tt.wantOffset || gotLimit != tt.wantLimit || page.Offset != tt.wantOffset || page.Limit != tt.wantLimit {
+			t.Errorf("offset %d limit %d: expected %d/%d, got %d/%d", tt.offset, tt.limit, tt.wantOffset, tt.wantLimit, gotOffset, gotLimit)
+		}
+	}
+}
+
+func TestActivityService_ListActivity_Error(t *testing.T) {
+	service := NewActivityService(&mocks.MockActivityRepository{
+		ListByUserFunc: func(ctx context.Context, userID string, offset, limit int) ([]models.ActivityEntry, int, error) {
+			return nil, 0, errors.New("database error")
+		},
+	})
+
+	if _, err := service.ListActivity(context.Background(), "user-1", 0, 0); err == nil {
+		t.Error("expected the repository error")
+	}
+}
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+	"github.com/graytonio/warframe-wishlist/internal/storage"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+)
+
+const (
+	popularItemsFile  = "popular_items.csv"
+	wishlistSizesFile = "wishlist_sizes.csv"
+)
+
+// AggregateExportResult lists the object keys written by one export.
+type AggregateExportResult struct {
+	Keys         []string
+	PopularItems int
+}
+
+// AggregateExporter publishes anonymized aggregate datasets for community
+// analysis. Only counts across users are exported, never user IDs, and any
+// figure based on fewer than minUsers users is withheld so individual
+// wishlists cannot be singled out.
+type AggregateExporter struct {
+	statsRepo repository.PopularityRepositoryInterface
+	store     storage.ObjectStore
+	prefix    string
+	topItems  int
+	minUsers  int
+	now       func() time.Time
+}
+
+func NewAggregateExporter(statsRepo repository.PopularityRepositoryInterface, store storage.ObjectStore, prefix string, topItems, minUsers int) *AggregateExporter {
+	return &AggregateExporter{
+		statsRepo: statsRepo,
+		store:     store,
+		prefix:    prefix,
+		topItems:  topItems,
+		minUsers:  minUsers,
+		now:       time.Now,
+	}
+}
+
+// Export writes each dataset under <prefix>/<UTC date>/ and <prefix>/latest/.
+func (e *AggregateExporter) Export(ctx context.Context) (*AggregateExportResult, error) {
+	logger.Debug(ctx, "service: AggregateExporter.Export called", "topItems", e.topItems, "minUsers", e.minUsers)
+
+	generatedAt := e.now().UTC()
+
+	popular, err := e.statsRepo.TopWishlistedItems(ctx, e.topItems)
+	if err != nil {
+		logger.Error(ctx, "service: AggregateExporter.Export - failed to get popular items", "error", err)
+		return nil, err
+	}
+	sizes, err := e.statsRepo.WishlistSizeStats(ctx)
+	if err != nil {
+		logger.Error(ctx, "service: AggregateExporter.Export - failed to get wishlist sizes", "error", err)
+		return nil, err
+	}
+
+	popularRows := [][]string{{"rank", "unique_name", "wishlist_count"}}
+	for _, p := range popular {
+		// Results are sorted by count, so everything after the first small
+		// count is below the threshold too.
+		if p.WishlistCount < e.minUsers {
+			break
+		}
+		popularRows = append(popularRows, []string{strconv.Itoa(len(popularRows)), p.UniqueName, strconv.Itoa(p.WishlistCount)})
+	}
+
+	sizeRows := [][]string{{"generated_at", "wishlists", "total_items", "average_items"}}
+	if sizes.Wishlists >= e.minUsers && sizes.Wishlists > 0 {
+		sizeRows = append(sizeRows, []string{
+			generatedAt.Format(time.RFC3339),
+			strconv.Itoa(sizes.Wishlists),
+			strconv.Itoa(sizes.TotalItems),
+			strconv.FormatFloat(averageWishlistSize(sizes), 'f', 2, 64),
+		})
+	}
+
+	result := &AggregateExportResult{PopularItems: len(popularRows) - 1}
+	datasets := []struct {
+		name string
+		rows [][]string
+	}{
+		{popularItemsFile, popularRows},
+		{wishlistSizesFile, sizeRows},
+	}
+
+	for _, dataset := range datasets {
+		body, err := encodeCSV(dataset.rows)
+		if err != nil {
+			return nil, err
+		}
+		for _, dir := range []string{generatedAt.Format("2006-01-02"), "latest"} {
+			key := path.Join(e.prefix, dir, dataset.name)
+			if err := e.store.Put(ctx, key, "text/csv", body); err != nil {
+				logger.Error(ctx, "service: AggregateExporter.Export - failed to store dataset", "key", key, "error", err)
+				return nil, err
+			}
+			result.Keys = append(result.Keys, key)
+		}
+	}
+
+	logger.Info(ctx, "service: AggregateExporter.Export - completed", "popularItems", result.PopularItems, "wishlists", sizes.Wishlists)
+	return result, nil
+}
+
+// Run exports once immediately and then every interval until ctx is done.
+// Failures are logged and retried on the next tick.
+func (e *AggregateExporter) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := e.Export(ctx); err != nil {
+			logger.Error(ctx, "service: AggregateExporter.Run - export failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func averageWishlistSize(stats *models.WishlistSizeStats) float64 {
+	if stats.Wishlists == 0 {
+		return 0
+	}
+	return float64(stats.TotalItems) / float64(stats.Wishlists)
+}
+
+func encodeCSV(rows [][]string) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.WriteAll(rows); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
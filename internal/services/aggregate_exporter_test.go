@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+type recordingObjectStore struct {
+	objects map[string]string
+	err     error
+}
+
+func (s *recordingObjectStore) Put(ctx context.Context, key, contentType string, body []byte) error {
+	if s.err != nil {
+		return s.err
+	}
+	if s.objects == nil {
+		s.objects = make(map[string]string)
+	}
+	s.objects[key] = string(body)
+	return nil
+}
+
+func newTestExporter(repo *mocks.MockPopularityRepository, store *recordingObjectStore, minUsers int) *AggregateExporter {
+	exporter := NewAggregateExporter(repo, store, "aggregates", 100, minUsers)
+	exporter.now = func() time.Time { return time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC) }
+	return exporter
+}
+
+func TestAggregateExporter_Export(t *testing.T) {
+	repo := &mocks.MockPopularityRepository{
+		TopWishlistedItemsFunc: func(ctx context.Context, limit int) ([]models.ItemPopularity, error) {
+			if limit != 100 {
+				t.Errorf("expected limit 100, got %d", limit)
+			}
+			return []models.ItemPopularity{
+				{UniqueName: "/Lotus/Ash", WishlistCount: 12},
+				{UniqueName: "/Lotus/Ember", WishlistCount: 5},
+				{UniqueName: "/Lotus/Rare", WishlistCount: 2},
+			}, nil
+		},
+		WishlistSizeStatsFunc: func(ctx context.Context) (*models.WishlistSizeStats, error) {
+			return &models.WishlistSizeStats{Wishlists: 8, TotalItems: 27}, nil
+		},
+	}
+	store := &recordingObjectStore{}
+
+	result, err := newTestExporter(repo, store, 5).Export(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.PopularItems != 2 {
+		t.Errorf("expected 2 exported popular items, got %d", result.PopularItems)
+	}
+	if len(result.Keys) != 4 {
+		t.Errorf("expected 4 keys, got %v", result.Keys)
+	}
+
+	expectedPopular := "rank,unique_name,wishlist_count\n1,/Lotus/Ash,12\n2,/Lotus/Ember,5\n"
+	expectedSizes := "generated_at,wishlists,total_items,average_items\n2026-10-17T12:00:00Z,8,27,3.38\n"
+	for _, dir := range []string{"2026-10-17", "latest"} {
+		if got := store.objects["aggregates/"+dir+"/popular_items.csv"]; got != expectedPopular {
+			t.Errorf("%s popular_items.csv:\n got: %q\nwant: %q", dir, got, expectedPopular)
+		}
+		if got := store.objects["aggregates/"+dir+"/wishlist_sizes.csv"]; got != expectedSizes {
+			t.Errorf("%s wishlist_sizes.csv:\n got: %q\nwant: %q", dir, got, expectedSizes)
+		}
+	}
+}
+
+func TestAggregateExporter_Export_WithholdsSmallPopulations(t *testing.T) {
+	repo := &mocks.MockPopularityRepository{
+		TopWishlistedItemsFunc: func(ctx context.Context, limit int) ([]models.ItemPopularity, error) {
+			return []models.ItemPopularity{{UniqueName: "/Lotus/Ash", WishlistCount: 3}}, nil
+		},
+		WishlistSizeStatsFunc: func(ctx context.Context) (*models.WishlistSizeStats, error) {
+			return &models.WishlistSizeStats{Wishlists: 3, TotalItems: 9}, nil
+		},
+	}
+	store := &recordingObjectStore{}
+
+	if _, err := newTestExporter(repo, store, 5).Export(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := store.objects["aggregates/latest/popular_items.csv"]; got != "rank,unique_name,wishlist_count\n" {
+		t.Errorf("expected header-only popular items, got %q", got)
+	}
+	if got := store.objects["aggregates/latest/wishlist_sizes.csv"]; got != "generated_at,wishlists,total_items,average_items\n" {
+		t.Errorf("expected header-only wishlist sizes, got %q", got)
+	}
+}
+
+func TestAggregateExporter_Export_Errors(t *testing.T) {
+	dbErr := errors.New("database error")
+	storeErr := errors.New("access denied")
+
+	tests := []struct {
+		name  string
+		repo  *mocks.MockPopularityRepository
+		store *recordingObjectStore
+		want  error
+	}{
+		{
+			name: "popularity error",
+			repo: &mocks.MockPopularityRepository{
+				TopWishlistedItemsFunc: func(ctx context.Context, limit int) ([]models.ItemPopularity, error) { return nil, dbErr },
+			},
+			store: &recordingObjectStore{},
+			want:  dbErr,
+		},
+		{
+			name: "size stats error",
+			repo: &mocks.MockPopularityRepository{
+				WishlistSizeStatsFunc: func(ctx context.Context) (*models.WishlistSizeStats, error) { return nil, dbErr },
+			},
+			store: &recordingObjectStore{},
+			want:  dbErr,
+		},
+		{
+			name:  "store error",
+			repo:  &mocks.MockPopularityRepository{},
+			store: &recordingObjectStore{err: storeErr},
+			want:  storeErr,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := newTestExporter(tt.repo, tt.store, 5).Export(context.Background()); !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestAggregateExporter_Run_StopsOnCancel(t *testing.T) {
+	store := &recordingObjectStore{}
+	exporter := newTestExporter(&mocks.MockPopularityRepository{}, store, 5)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		exporter.Run(ctx, time.Hour)
+		close(done)
+	}()
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected Run to return after cancel")
+	}
+}
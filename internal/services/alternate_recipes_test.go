@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository/memory"
+)
+
+// newAlternateRecipeItem returns a blueprint with a default recipe of 100
+// ferrite and an alternate recipe of 10 alloy plates.
+func newAlternateRecipeItem() *models.Item {
+	return &models.Item{
+		UniqueName: "/Lotus/Forma",
+		Name:       "Forma",
+		BuildPrice: 500,
+		Components: []models.Component{
+			{UniqueName: "/Lotus/Ferrite", Name: "Ferrite", ItemCount: 100},
+		},
+		AlternateRecipes: []models.Recipe{
+			{
+				ID:         "alloy",
+				Name:       "Alloy Forma",
+				BuildPrice: 2000,
+				Components: []models.Component{
+					{UniqueName: "/Lotus/Alloy", Name: "Alloy Plate", ItemCount: 10},
+				},
+			},
+		},
+	}
+}
+
+func newRecipeFixture(t *testing.T) (*WishlistService, *memory.WishlistRepository) {
+	t.Helper()
+
+	items := memory.NewItemRepository()
+	items.Add("resources", *newAlternateRecipeItem())
+	wishlists := memory.NewWishlistRepository()
+	wishlists.Create(context.Background(), &models.Wishlist{
+		UserID: "user-123",
+		Items: []models.WishlistItem{
+			{UniqueName: "/Lotus/Forma", Quantity: 1},
+			{UniqueName: "/Lotus/Removed", Quantity: 1},
+		},
+	})
+	return NewWishlistService(wishlists, items), wishlists
+}
+
+func TestWishlistService_SetItemRecipe(t *testing.T) {
+	ctx := context.Background()
+	service, wishlists := newRecipeFixture(t)
+
+	if err := service.SetItemRecipe(ctx, "user-123", "/Lotus/Forma", "alloy"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stored, _ := wishlists.GetByUserID(ctx, "user-123")
+	if stored.Items[0].RecipeID != "alloy" {
+		t.Errorf("expected recipe to be stored, got %q", stored.Items[0].RecipeID)
+	}
+
+	if err := service.SetItemRecipe(ctx, "user-123", "/Lotus/Forma", ""); err != nil {
+		t.Fatalf("unexpected error clearing recipe: %v", err)
+	}
+	stored, _ = wishlists.GetByUserID(ctx, "user-123")
+	if stored.Items[0].RecipeID != "" {
+		t.Errorf("expected recipe to be cleared, got %q", stored.Items[0].RecipeID)
+	}
+}
+
+func TestWishlistService_SetItemRecipe_Errors(t *testing.T) {
+	tests := []struct {
+		name          string
+		userID        string
+		uniqueName    string
+		recipeID      string
+		expectedError error
+	}{
+		{name: "unknown recipe", userID: "user-123", uniqueName: "/Lotus/Forma", recipeID: "missing", expectedError: ErrRecipeNotFound},
+		{name: "item no longer exists", userID: "user-123", uniqueName: "/Lotus/Removed", recipeID: "alloy", expectedError: ErrItemNotFound},
+		{name: "item not in wishlist", userID: "user-123", uniqueName: "/Lotus/Other", recipeID: "alloy", expectedError: ErrItemNotInWishlist},
+		{name: "no wishlist", userID: "new-user", uniqueName: "/Lotus/Forma", expectedError: ErrItemNotInWishlist},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, _ := newRecipeFixture(t)
+
+			err := service.SetItemRecipe(context.Background(), tt.userID, tt.uniqueName, tt.recipeID)
+			if !errors.Is(err, tt.expectedError) {
+				t.Errorf("expected %v, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestMaterialResolver_GetMaterials_HonorsRecipeChoice(t *testing.T) {
+	tests := []struct {
+		name             string
+		recipeID         string
+		expectedMaterial string
+		expectedCount    int
+		expectedCredits  int
+	}{
+		{name: "default recipe", expectedMaterial: "/Lotus/Ferrite", expectedCount: 100, expectedCredits: 500},
+		{name: "alternate recipe", recipeID: "alloy", expectedMaterial: "/Lotus/Alloy", expectedCount: 10, expectedCredits: 2000},
+		{name: "removed recipe falls back to default", recipeID: "missing", expectedMaterial: "/Lotus/Ferrite", expectedCount: 100, expectedCredits: 500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := newAlternateRecipeItem()
+			wishlistRepo := &mocks.MockWishlistRepository{
+				GetByUserIDFunc: func(ctx context.Context, userID string) (*models.Wishlist, error) {
+					return &models.Wishlist{
+						UserID: userID,
+						Items:  []models.WishlistItem{{UniqueName: "/Lotus/Forma", Quantity: 1, RecipeID: tt.recipeID}},
+					}, nil
+				},
+			}
+
+			resolver := NewMaterialResolver(newCatalogItemRepository(item), wishlistRepo, nil, nil)
+			result, err := resolver.GetMaterials(context.Background(), "user-123")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(result.Materials) != 1 || result.Materials[0].UniqueName != tt.expectedMaterial || result.Materials[0].TotalCount != tt.expectedCount {
+				t.Errorf("expected %d of %s, got %+v", tt.expectedCount, tt.expectedMaterial, result.Materials)
+			}
+			if result.TotalCredits != tt.expectedCredits {
+				t.Errorf("expected %d credits, got %d", tt.expectedCredits, result.TotalCredits)
+			}
+			if item.Components[0].UniqueName != "/Lotus/Ferrite" {
+				t.Error("expected the catalog item to be left unchanged")
+			}
+		})
+	}
+}
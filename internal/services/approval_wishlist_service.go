@@ -0,0 +1,196 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+)
+
+var ErrApprovalRequired = errors.New("change requires manager approval")
+
+// ApprovalRequiredError is returned when a change was held for the member's
+// manager instead of being applied. It matches ErrApprovalRequired.
+type ApprovalRequiredError struct {
+	Change *models.PendingChange
+}
+
+func (e *ApprovalRequiredError) Error() string {
+	return ErrApprovalRequired.Error()
+}
+
+func (e *ApprovalRequiredError) Is(target error) bool {
+	return target == ErrApprovalRequired
+}
+
+// ApprovalWishlistService wraps a wishlist service for household accounts.
+// Members with an active manager link get additions and quantity increases
+// above their threshold recorded as pending changes instead of applied;
+// everything else goes straight to next.
+type ApprovalWishlistService struct {
+	next          WishlistServiceInterface
+	householdRepo repository.HouseholdRepositoryInterface
+	itemRepo      repository.ItemRepositoryInterface
+	// settingsRepo and customItemRepo are optional, as for WishlistService.
+	settingsRepo   repository.SettingsRepositoryInterface
+	customItemRepo repository.CustomItemRepositoryInterface
+}
+
+func NewApprovalWishlistService(next WishlistServiceInterface, householdRepo repository.HouseholdRepositoryInterface, itemRepo repository.ItemRepositoryInterface) *ApprovalWishlistService {
+	return &ApprovalWishlistService{
+		next:          next,
+		householdRepo: householdRepo,
+		itemRepo:      itemRepo,
+	}
+}
+
+// SetSettingsRepository makes additions without a quantity use the user's
+// default quantity rules, both for the approval threshold and when held.
+func (s *ApprovalWishlistService) SetSettingsRepository(settingsRepo repository.SettingsRepositoryInterface) {
+	s.settingsRepo = settingsRepo
+}
+
+// SetCustomItemRepository lets members add their own custom items, as
+// WishlistService.SetCustomItemRepository does.
+func (s *ApprovalWishlistService) SetCustomItemRepository(customItemRepo repository.CustomItemRepositoryInterface) {
+	s.customItemRepo = customItemRepo
+}
+
+func (s *ApprovalWishlistService) GetWishlist(ctx context.Context, userID string) (*models.Wishlist, error) {
+	return s.next.GetWishlist(ctx, userID)
+}
+
+func (s *ApprovalWishlistService) RemoveItem(ctx context.Context, userID, uniqueName string) error {
+	return s.next.RemoveItem(ctx, userID, uniqueName)
+}
+
+func (s *ApprovalWishlistService) SetItemLinks(ctx context.Context, userID, uniqueName string, links []models.SourceLinkRequest) ([]models.SourceLink, error) {
+	return s.next.SetItemLinks(ctx, userID, uniqueName, links)
+}
+
+func (s *ApprovalWishlistService) SetItemRecipe(ctx context.Context, userID, uniqueName, recipeID string) error {
+	return s.next.SetItemRecipe(ctx, userID, uniqueName, recipeID)
+}
+
+func (s *ApprovalWishlistService) SetItemProgress(ctx context.Context, userID, uniqueName string, progress []models.ComponentProgressRequest) (*models.WishlistItem, error) {
+	return s.next.SetItemProgress(ctx, userID, uniqueName, progress)
+}
+
+func (s *ApprovalWishlistService) GetExpandedWishlist(ctx context.Context, userID string) (*models.ExpandedWishlist, error) {
+	return s.next.GetExpandedWishlist(ctx, userID)
+}
+
+func (s *ApprovalWishlistService) AddItem(ctx context.Context, userID string, req models.AddItemRequest) error {
+	logger.Debug(ctx, "service: ApprovalWishlistService.AddItem called", "userID", userID, "uniqueName", req.UniqueName, "quantity", req.Quantity)
+
+	link, err := s.householdRepo.GetLinkByMember(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "service: ApprovalWishlistService.AddItem - error fetching household link", "error", err)
+		return err
+	}
+	if link == nil || link.Status != models.HouseholdLinkActive {
+		return s.next.AddItem(ctx, userID, req)
+	}
+
+	// Validate up front so the manager is never asked to approve a change
+	// that would fail when applied. The item also selects the default
+	// quantity, which decides whether approval is needed.
+	itemRepo, err := itemsForUser(ctx, s.itemRepo, s.customItemRepo, userID)
+	if err != nil {
+		logger.Error(ctx, "service: ApprovalWishlistService.AddItem - error fetching custom items", "error", err)
+		return err
+	}
+	item, err := itemRepo.FindByUniqueName(ctx, req.UniqueName)
+	if err != nil {
+		logger.Error(ctx, "service: ApprovalWishlistService.AddItem - error finding item", "error", err)
+		return err
+	}
+	if item == nil {
+		logger.Warn(ctx, "service: ApprovalWishlistService.AddItem - item not found", "uniqueName", req.UniqueName)
+		return ErrItemNotFound
+	}
+
+	if req.Quantity <= 0 {
+		req.Quantity = defaultQuantity(ctx, s.settingsRepo, userID, item)
+	}
+	if !link.RequiresApproval(req.Quantity) {
+		return s.next.AddItem(ctx, userID, req)
+	}
+
+	current, err := s.currentItem(ctx, userID, req.UniqueName)
+	if err != nil {
+		return err
+	}
+	if current != nil {
+		logger.Warn(ctx, "service: ApprovalWishlistService.AddItem - item already in wishlist", "uniqueName", req.UniqueName)
+		return ErrItemAlreadyInWishlist
+	}
+
+	return s.hold(ctx, link, models.ChangeTypeAddItem, req.UniqueName, req.Quantity)
+}
+
+func (s *ApprovalWishlistService) UpdateQuantity(ctx context.Context, userID, uniqueName string, quantity int) error {
+	logger.Debug(ctx, "service: ApprovalWishlistService.UpdateQuantity called", "userID", userID, "uniqueName", uniqueName, "quantity", quantity)
+
+	link, err := s.householdRepo.GetLinkByMember(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "service: ApprovalWishlistService.UpdateQuantity - error fetching household link", "error", err)
+		return err
+	}
+	if !link.RequiresApproval(quantity) {
+		return s.next.UpdateQuantity(ctx, userID, uniqueName, quantity)
+	}
+
+	current, err := s.currentItem(ctx, userID, uniqueName)
+	if err != nil {
+		return err
+	}
+	if current == nil {
+		logger.Warn(ctx, "service: ApprovalWishlistService.UpdateQuantity - item not in wishlist", "uniqueName", uniqueName)
+		return ErrItemNotInWishlist
+	}
+	// Lowering a quantity never needs approval, even when both the old and
+	// new quantities are above the threshold.
+	if quantity <= current.Quantity {
+		return s.next.UpdateQuantity(ctx, userID, uniqueName, quantity)
+	}
+
+	return s.hold(ctx, link, models.ChangeTypeUpdateQuantity, uniqueName, quantity)
+}
+
+func (s *ApprovalWishlistService) currentItem(ctx context.Context, userID, uniqueName string) (*models.WishlistItem, error) {
+	wishlist, err := s.next.GetWishlist(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "service: ApprovalWishlistService - error fetching wishlist", "error", err)
+		return nil, err
+	}
+	if wishlist == nil {
+		return nil, nil
+	}
+	for i := range wishlist.Items {
+		if wishlist.Items[i].UniqueName == uniqueName {
+			return &wishlist.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *ApprovalWishlistService) hold(ctx context.Context, link *models.HouseholdLink, changeType, uniqueName string, quantity int) error {
+	change := &models.PendingChange{
+		MemberID:   link.MemberID,
+		ManagerID:  link.ManagerID,
+		Type:       changeType,
+		UniqueName: uniqueName,
+		Quantity:   quantity,
+		Status:     models.ChangeStatusPending,
+	}
+	if err := s.householdRepo.CreatePendingChange(ctx, change); err != nil {
+		logger.Error(ctx, "service: ApprovalWishlistService - error creating pending change", "error", err)
+		return err
+	}
+
+	logger.Info(ctx, "service: ApprovalWishlistService - change held for approval", "type", changeType, "uniqueName", uniqueName, "quantity", quantity, "threshold", link.QuantityThreshold)
+	return &ApprovalRequiredError{Change: change}
+}
@@ -0,0 +1,222 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository/memory"
+)
+
+type householdFixture struct {
+	household *memory.HouseholdRepository
+	wishlists *memory.WishlistRepository
+	base      *WishlistService
+	approval  *ApprovalWishlistService
+	service   *HouseholdService
+}
+
+// newHouseholdFixture links member to manager with the given threshold and
+// link status, backed by in-memory repositories holding /Lotus/Forma and
+// /Lotus/Orokin.
+func newHouseholdFixture(t *testing.T, threshold int, status string) *householdFixture {
+	t.Helper()
+
+	items := memory.NewItemRepository()
+	items.Add("resources", models.Item{UniqueName: "/Lotus/Forma", Name: "Forma"}, models.Item{UniqueName: "/Lotus/Orokin", Name: "Orokin Catalyst"})
+
+	f := &householdFixture{
+		household: memory.NewHouseholdRepository(),
+		wishlists: memory.NewWishlistRepository(),
+	}
+	f.base = NewWishlistService(f.wishlists, items)
+	f.approval = NewApprovalWishlistService(f.base, f.household, items)
+	f.service = NewHouseholdService(f.household, f.base)
+
+	if status != "" {
+		created, err := f.household.CreateLink(context.Background(), &models.HouseholdLink{
+			ManagerID:         "manager",
+			MemberID:          "member",
+			QuantityThreshold: threshold,
+			Status:            status,
+		})
+		if err != nil || !created {
+			t.Fatalf("failed to create link: created=%v err=%v", created, err)
+		}
+	}
+	return f
+}
+
+func (f *householdFixture) quantity(t *testing.T, uniqueName string) int {
+	t.Helper()
+	wishlist, err := f.base.GetWishlist(context.Background(), "member")
+	if err != nil {
+		t.Fatalf("GetWishlist: %v", err)
+	}
+	for _, item := range wishlist.Items {
+		if item.UniqueName == uniqueName {
+			return item.Quantity
+		}
+	}
+	return 0
+}
+
+func TestApprovalWishlistService_AddItem(t *testing.T) {
+	tests := []struct {
+		name           string
+		threshold      int
+		status         string
+		quantity       int
+		expectHeld     bool
+		expectQuantity int
+	}{
+		{name: "no household link", quantity: 50, expectQuantity: 50},
+		{name: "pending link does not gate", threshold: 5, status: models.HouseholdLinkPending, quantity: 50, expectQuantity: 50},
+		{name: "at threshold is applied", threshold: 5, status: models.HouseholdLinkActive, quantity: 5, expectQuantity: 5},
+		{name: "default quantity below threshold is applied", threshold: 1, status: models.HouseholdLinkActive, quantity: 0, expectQuantity: 1},
+		{name: "above threshold is held", threshold: 5, status: models.HouseholdLinkActive, quantity: 6, expectHeld: true},
+		{name: "zero threshold holds every addition", threshold: 0, status: models.HouseholdLinkActive, quantity: 0, expectHeld: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newHouseholdFixture(t, tt.threshold, tt.status)
+			ctx := context.Background()
+
+			err := f.approval.AddItem(ctx, "member", models.AddItemRequest{UniqueName: "/Lotus/Forma", Quantity: tt.quantity})
+
+			if !tt.expectHeld {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if got := f.quantity(t, "/Lotus/Forma"); got != tt.expectQuantity {
+					t.Errorf("expected quantity %d, got %d", tt.expectQuantity, got)
+				}
+				return
+			}
+
+			var approvalErr *ApprovalRequiredError
+			if !errors.As(err, &approvalErr) || !errors.Is(err, ErrApprovalRequired) {
+				t.Fatalf("expected ApprovalRequiredError, got %v", err)
+			}
+			change := approvalErr.Change
+			if change.ID.IsZero() || change.Type != models.ChangeTypeAddItem || change.ManagerID != "manager" || change.Status != models.ChangeStatusPending {
+				t.Errorf("unexpected pending change: %+v", change)
+			}
+			if got := f.quantity(t, "/Lotus/Forma"); got != 0 {
+				t.Errorf("expected held item not to be in wishlist, got quantity %d", got)
+			}
+
+			pending, _ := f.household.ListChangesByManager(ctx, "manager", models.ChangeStatusPending)
+			if len(pending) != 1 {
+				t.Errorf("expected 1 pending change, got %d", len(pending))
+			}
+		})
+	}
+}
+
+func TestApprovalWishlistService_AddItem_ValidatesBeforeHolding(t *testing.T) {
+	ctx := context.Background()
+	f := newHouseholdFixture(t, 1, models.HouseholdLinkActive)
+
+	if err := f.approval.AddItem(ctx, "member", models.AddItemRequest{UniqueName: "/Lotus/Missing", Quantity: 10}); !errors.Is(err, ErrItemNotFound) {
+		t.Errorf("expected ErrItemNotFound, got %v", err)
+	}
+
+	if err := f.base.AddItem(ctx, "member", models.AddItemRequest{UniqueName: "/Lotus/Forma", Quantity: 1}); err != nil {
+		t.Fatalf("AddItem: %v", err)
+	}
+	if err := f.approval.AddItem(ctx, "member", models.AddItemRequest{UniqueName: "/Lotus/Forma", Quantity: 10}); !errors.Is(err, ErrItemAlreadyInWishlist) {
+		t.Errorf("expected ErrItemAlreadyInWishlist, got %v", err)
+	}
+
+	pending, _ := f.household.ListChangesByManager(ctx, "manager", "")
+	if len(pending) != 0 {
+		t.Errorf("expected no pending changes for invalid additions, got %d", len(pending))
+	}
+}
+
+func TestApprovalWishlistService_UpdateQuantity(t *testing.T) {
+	tests := []struct {
+		name           string
+		quantity       int
+		expectHeld     bool
+		expectErr      error
+		expectQuantity int
+	}{
+		{name: "increase within threshold is applied", quantity: 5, expectQuantity: 5},
+		{name: "increase above threshold is held", quantity: 12, expectHeld: true, expectQuantity: 8},
+		{name: "decrease above threshold is applied", quantity: 6, expectQuantity: 6},
+		{name: "invalid quantity is rejected", quantity: 0, expectErr: ErrInvalidQuantity, expectQuantity: 8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			f := newHouseholdFixture(t, 5, models.HouseholdLinkActive)
+			f.wishlists.Create(ctx, &models.Wishlist{
+				UserID: "member",
+				Items:  []models.WishlistItem{{UniqueName: "/Lotus/Forma", Quantity: 8}},
+			})
+
+			err := f.approval.UpdateQuantity(ctx, "member", "/Lotus/Forma", tt.quantity)
+
+			switch {
+			case tt.expectHeld:
+				var approvalErr *ApprovalRequiredError
+				if !errors.As(err, &approvalErr) {
+					t.Fatalf("expected ApprovalRequiredError, got %v", err)
+				}
+				if approvalErr.Change.Type != models.ChangeTypeUpdateQuantity || approvalErr.Change.Quantity != tt.quantity {
+					t.Errorf("unexpected pending change: %+v", approvalErr.Change)
+				}
+			case tt.expectErr != nil:
+				if !errors.Is(err, tt.expectErr) {
+					t.Fatalf("expected %v, got %v", tt.expectErr, err)
+				}
+			default:
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			if got := f.quantity(t, "/Lotus/Forma"); got != tt.expectQuantity {
+				t.Errorf("expected quantity %d, got %d", tt.expectQuantity, got)
+			}
+		})
+	}
+}
+
+func TestApprovalWishlistService_UpdateQuantity_ItemNotInWishlist(t *testing.T) {
+	f := newHouseholdFixture(t, 1, models.HouseholdLinkActive)
+
+	err := f.approval.UpdateQuantity(context.Background(), "member", "/Lotus/Forma", 10)
+	if !errors.Is(err, ErrItemNotInWishlist) {
+		t.Errorf("expected ErrItemNotInWishlist, got %v", err)
+	}
+}
+
+func TestApprovalWishlistService_LinkLookupError(t *testing.T) {
+	householdRepo := &mocks.MockHouseholdRepository{
+		GetLinkByMemberFunc: func(ctx context.Context, memberID string) (*models.HouseholdLink, error) {
+			return nil, errors.New("database error")
+		},
+	}
+	applied := false
+	next := &mocks.MockWishlistService{
+		AddItemFunc: func(ctx context.Context, userID string, req models.AddItemRequest) error {
+			applied = true
+			return nil
+		},
+	}
+	service := NewApprovalWishlistService(next, householdRepo, &mocks.MockItemRepository{})
+
+	if err := service.AddItem(context.Background(), "member", models.AddItemRequest{UniqueName: "/Lotus/Forma"}); err == nil {
+		t.Error("expected error but got none")
+	}
+	if applied {
+		t.Error("expected change not to be applied when the link lookup fails")
+	}
+}
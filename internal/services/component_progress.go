@@ -0,0 +1,113 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+var ErrInvalidProgress = errors.New("invalid component progress")
+
+// componentRequirements maps each component of item to how many of it
+// quantity copies of the item need.
+func componentRequirements(item *models.Item, quantity int) map[string]int {
+	required := make(map[string]int, len(item.Components))
+	for _, component := range item.Components {
+		required[component.UniqueName] += component.ItemCount * quantity
+	}
+	return required
+}
+
+// buildComponentProgress validates progress requests against the components
+// of item, as crafted with the wishlist item's recipe. A zero count marks
+// every copy the wishlist item needs.
+func buildComponentProgress(item *models.Item, wishlistItem models.WishlistItem, reqs []models.ComponentProgressRequest) ([]models.ComponentProgress, error) {
+	required := componentRequirements(item, wishlistItem.Quantity)
+	progress := make([]models.ComponentProgress, 0, len(reqs))
+	seen := make(map[string]bool, len(reqs))
+	for _, req := range reqs {
+		needed, ok := required[req.UniqueName]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q is not a component of the item", ErrInvalidProgress, req.UniqueName)
+		}
+		if seen[req.UniqueName] {
+			return nil, fmt.Errorf("%w: %q is listed more than once", ErrInvalidProgress, req.UniqueName)
+		}
+		seen[req.UniqueName] = true
+		if req.Status != models.ComponentBuilt && req.Status != models.ComponentAcquired {
+			return nil, fmt.Errorf("%w: status must be %s or %s", ErrInvalidProgress, models.ComponentBuilt, models.ComponentAcquired)
+		}
+		count := req.Count
+		if count == 0 {
+			count = needed
+		}
+		if count < 0 || count > needed {
+			return nil, fmt.Errorf("%w: count for %q must be between 1 and %d", ErrInvalidProgress, req.UniqueName, needed)
+		}
+		progress = append(progress, models.ComponentProgress{UniqueName: req.UniqueName, Status: req.Status, Count: count})
+	}
+	return progress, nil
+}
+
+// completedComponents maps each component of item to how many of it the
+// wishlist item's progress marks done. Progress for components the recipe no
+// longer lists is ignored.
+func completedComponents(item *models.Item, wishlistItem models.WishlistItem) map[string]int {
+	if len(wishlistItem.Progress) == 0 {
+		return nil
+	}
+	required := componentRequirements(item, wishlistItem.Quantity)
+	done := make(map[string]int, len(wishlistItem.Progress))
+	for _, p := range wishlistItem.Progress {
+		if needed, ok := required[p.UniqueName]; ok {
+			done[p.UniqueName] = min(done[p.UniqueName]+p.Count, needed)
+		}
+	}
+	return done
+}
+
+// itemCompletion is the percentage of item's components the wishlist item's
+// progress marks done, each component weighing the same however many of it
+// the recipe takes. An item without components is 0% complete.
+func itemCompletion(item *models.Item, wishlistItem models.WishlistItem) int {
+	required := componentRequirements(item, wishlistItem.Quantity)
+	if len(required) == 0 || wishlistItem.Quantity <= 0 {
+		return 0
+	}
+	done := completedComponents(item, wishlistItem)
+	total := 0
+	for uniqueName, needed := range required {
+		if needed > 0 {
+			total += done[uniqueName] * 100 / needed
+		} else {
+			total += 100
+		}
+	}
+	return total / len(required)
+}
+
+// withoutCompleted returns one copy of item with done components taken off
+// its recipe, consuming them from done so the next copy only loses what is
+// left. item itself is returned when nothing is done.
+func withoutCompleted(item *models.Item, done map[string]int) *models.Item {
+	if len(done) == 0 {
+		return item
+	}
+	remaining := *item
+	remaining.Components = make([]models.Component, 0, len(item.Components))
+	for _, component := range item.Components {
+		taken := min(done[component.UniqueName], component.ItemCount)
+		if taken > 0 {
+			done[component.UniqueName] -= taken
+			if done[component.UniqueName] == 0 {
+				delete(done, component.UniqueName)
+			}
+			component.ItemCount -= taken
+		}
+		if component.ItemCount > 0 {
+			remaining.Components = append(remaining.Components, component)
+		}
+	}
+	return &remaining
+}
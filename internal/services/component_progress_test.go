@@ -0,0 +1,178 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository/memory"
+)
+
+// newProgressFixture returns a wishlist of two Ash, each built from a chassis
+// (100 ferrite) and two orokin cells, with the services reading it.
+func newProgressFixture(t *testing.T) (*WishlistService, *MaterialResolver) {
+	t.Helper()
+
+	items := memory.NewItemRepository()
+	items.Add("warframes", models.Item{
+		UniqueName:         "/Lotus/Ash",
+		Name:               "Ash",
+		BuildPrice:         25000,
+		SkipBuildTimePrice: 50,
+		Components: []models.Component{
+			{UniqueName: "/Lotus/AshChassis", Name: "Chassis", ItemCount: 1},
+			{UniqueName: "/Lotus/OrokinCell", Name: "Orokin Cell", ItemCount: 2},
+		},
+	})
+	items.Add("misc",
+		models.Item{
+			UniqueName: "/Lotus/AshChassis",
+			Name:       "Chassis",
+			BuildPrice: 15000,
+			Components: []models.Component{{UniqueName: "/Lotus/Ferrite", Name: "Ferrite", ItemCount: 100}},
+		},
+		models.Item{UniqueName: "/Lotus/OrokinCell", Name: "Orokin Cell"},
+		models.Item{UniqueName: "/Lotus/Ferrite", Name: "Ferrite"},
+	)
+	wishlists := memory.NewWishlistRepository()
+	wishlists.Create(context.Background(), &models.Wishlist{
+		UserID: "user-123",
+		Items: []models.WishlistItem{
+			{UniqueName: "/Lotus/Ash", Quantity: 2},
+			{UniqueName: "/Lotus/Removed", Quantity: 1},
+		},
+	})
+	return NewWishlistService(wishlists, items), NewMaterialResolver(items, wishlists, nil, nil)
+}
+
+func materialCounts(materials *models.MaterialsResponse) map[string]int {
+	counts := make(map[string]int, len(materials.Materials))
+	for _, m := range materials.Materials {
+		counts[m.UniqueName] = m.TotalCount
+	}
+	return counts
+}
+
+func TestWishlistService_SetItemProgress(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newProgressFixture(t)
+
+	item, err := service.SetItemProgress(ctx, "user-123", "/Lotus/Ash", []models.ComponentProgressRequest{
+		{UniqueName: "/Lotus/AshChassis", Status: models.ComponentBuilt},
+		{UniqueName: "/Lotus/OrokinCell", Status: models.ComponentAcquired, Count: 1},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if item.Progress[0].Count != 2 {
+		t.Errorf("expected a zero count to mark both chassis, got %+v", item.Progress[0])
+	}
+	// Chassis 2/2 is 100%, cells 1/4 are 25%.
+	if item.Completion != 62 {
+		t.Errorf("expected 62%% completion, got %d", item.Completion)
+	}
+
+	wishlist, err := service.GetWishlist(ctx, "user-123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if wishlist.Items[0].Completion != 62 || wishlist.Items[1].Completion != 0 {
+		t.Errorf("expected completion on read, got %+v", wishlist.Items)
+	}
+
+	if _, err := service.SetItemProgress(ctx, "user-123", "/Lotus/Ash", nil); err != nil {
+		t.Fatalf("unexpected error clearing progress: %v", err)
+	}
+	wishlist, _ = service.GetWishlist(ctx, "user-123")
+	if len(wishlist.Items[0].Progress) != 0 || wishlist.Items[0].Completion != 0 {
+		t.Errorf("expected progress to be cleared, got %+v", wishlist.Items[0])
+	}
+}
+
+func TestWishlistService_SetItemProgress_Errors(t *testing.T) {
+	tests := []struct {
+		name          string
+		uniqueName    string
+		progress      []models.ComponentProgressRequest
+		expectedError error
+	}{
+		{name: "not a component", uniqueName: "/Lotus/Ash", progress: []models.ComponentProgressRequest{{UniqueName: "/Lotus/Ferrite", Status: models.ComponentBuilt}}, expectedError: ErrInvalidProgress},
+		{name: "unknown status", uniqueName: "/Lotus/Ash", progress: []models.ComponentProgressRequest{{UniqueName: "/Lotus/AshChassis", Status: "done"}}, expectedError: ErrInvalidProgress},
+		{name: "more than needed", uniqueName: "/Lotus/Ash", progress: []models.ComponentProgressRequest{{UniqueName: "/Lotus/OrokinCell", Status: models.ComponentBuilt, Count: 5}}, expectedError: ErrInvalidProgress},
+		{name: "listed twice", uniqueName: "/Lotus/Ash", progress: []models.ComponentProgressRequest{
+			{UniqueName: "/Lotus/AshChassis", Status: models.ComponentBuilt},
+			{UniqueName: "/Lotus/AshChassis", Status: models.ComponentAcquired},
+		}, expectedError: ErrInvalidProgress},
+		{name: "item no longer exists", uniqueName: "/Lotus/Removed", expectedError: ErrItemNotFound},
+		{name: "item not in wishlist", uniqueName: "/Lotus/Other", expectedError: ErrItemNotInWishlist},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, _ := newProgressFixture(t)
+
+			_, err := service.SetItemProgress(context.Background(), "user-123", tt.uniqueName, tt.progress)
+			if !errors.Is(err, tt.expectedError) {
+				t.Errorf("expected %v, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestMaterialResolver_GetMaterials_SubtractsCompletedComponents(t *testing.T) {
+	tests := []struct {
+		name            string
+		progress        []models.ComponentProgressRequest
+		expected        map[string]int
+		expectedCredits int
+	}{
+		{
+			name:            "no progress",
+			expected:        map[string]int{"/Lotus/Ferrite": 200, "/Lotus/OrokinCell": 4},
+			expectedCredits: 2 * (25000 + 15000),
+		},
+		{
+			name:            "one chassis built",
+			progress:        []models.ComponentProgressRequest{{UniqueName: "/Lotus/AshChassis", Status: models.ComponentBuilt, Count: 1}},
+			expected:        map[string]int{"/Lotus/Ferrite": 100, "/Lotus/OrokinCell": 4},
+			expectedCredits: 2*25000 + 15000,
+		},
+		{
+			name: "everything done",
+			progress: []models.ComponentProgressRequest{
+				{UniqueName: "/Lotus/AshChassis", Status: models.ComponentBuilt},
+				{UniqueName: "/Lotus/OrokinCell", Status: models.ComponentAcquired},
+			},
+			expected:        map[string]int{},
+			expectedCredits: 2 * 25000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			service, resolver := newProgressFixture(t)
+			if _, err := service.SetItemProgress(ctx, "user-123", "/Lotus/Ash", tt.progress); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			materials, err := resolver.GetMaterials(ctx, "user-123")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			counts := materialCounts(materials)
+			if len(counts) != len(tt.expected) {
+				t.Errorf("expected materials %v, got %v", tt.expected, counts)
+			}
+			for uniqueName, count := range tt.expected {
+				if counts[uniqueName] != count {
+					t.Errorf("expected %d %s, got %d", count, uniqueName, counts[uniqueName])
+				}
+			}
+			if materials.TotalCredits != tt.expectedCredits {
+				t.Errorf("expected %d credits, got %d", tt.expectedCredits, materials.TotalCredits)
+			}
+		})
+	}
+}
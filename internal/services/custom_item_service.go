@@ -0,0 +1,355 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+)
+
+const (
+	maxCustomItemNameLength        = 100
+	maxCustomItemDescriptionLength = 500
+	// MaxCustomItemBuildPrice and MaxCustomItemComponentCount keep totals
+	// far from overflowing when a custom item is wishlisted many times.
+	MaxCustomItemBuildPrice     = 10_000_000
+	MaxCustomItemComponentCount = 1_000_000
+)
+
+var (
+	ErrCustomItemNotFound = errors.New("custom item not found")
+	ErrTooManyCustomItems = fmt.Errorf("at most %d custom items per user", models.MaxCustomItemsPerUser)
+	ErrInvalidCustomItem  = errors.New("invalid custom item")
+)
+
+// CustomItemService manages the private items users define. Their components
+// can be game items, the user's other custom items or free-form materials.
+type CustomItemService struct {
+	customItemRepo repository.CustomItemRepositoryInterface
+	itemRepo       repository.ItemRepositoryInterface
+	// materialsCache is optional; changes to an item drop the user's cached
+	// materials response from it.
+	materialsCache MaterialsCache
+	now            func() time.Time
+}
+
+func NewCustomItemService(customItemRepo repository.CustomItemRepositoryInterface, itemRepo repository.ItemRepositoryInterface) *CustomItemService {
+	return &CustomItemService{
+		customItemRepo: customItemRepo,
+		itemRepo:       itemRepo,
+		now:            time.Now,
+	}
+}
+
+// SetMaterialsCache makes item changes invalidate the user's entry in cache.
+func (s *CustomItemService) SetMaterialsCache(cache MaterialsCache) {
+	s.materialsCache = cache
+}
+
+func (s *CustomItemService) List(ctx context.Context, userID string) ([]models.CustomItem, error) {
+	logger.Debug(ctx, "service: CustomItemService.List called", "userID", userID)
+
+	items, err := s.customItemRepo.ListByUser(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "service: CustomItemService.List - error fetching items", "error", err)
+		return nil, err
+	}
+	return items, nil
+}
+
+// Search returns a page of the user's custom items whose name or description
+// contains the query, ignoring case, in the same shape as a game item search.
+// The category in params is ignored; every result is in the custom category.
+func (s *CustomItemService) Search(ctx context.Context, userID string, params models.SearchParams) (*models.ItemSearchPage, error) {
+	params = params.Normalized()
+	logger.Debug(ctx, "service: CustomItemService.Search called", "userID", userID, "query", params.Query)
+
+	items, err := s.customItemRepo.ListByUser(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "service: CustomItemService.Search - error fetching items", "error", err)
+		return nil, err
+	}
+
+	query := strings.ToLower(params.Query)
+	page := &models.ItemSearchPage{Items: []models.ItemSearchResult{}}
+	for _, item := range items {
+		if query != "" && !strings.Contains(strings.ToLower(item.Name), query) && !strings.Contains(strings.ToLower(item.Description), query) {
+			continue
+		}
+		if page.Total >= params.Offset && len(page.Items) < params.Limit {
+			page.Items = append(page.Items, models.ItemSearchResult{
+				UniqueName:  item.UniqueName,
+				Name:        item.Name,
+				Description: item.Description,
+				Category:    models.CustomItemCategory,
+			})
+		}
+		page.Total++
+	}
+
+	logger.Debug(ctx, "service: CustomItemService.Search - completed", "resultCount", len(page.Items), "total", page.Total)
+	return page, nil
+}
+
+func (s *CustomItemService) Create(ctx context.Context, userID string, req models.CustomItemRequest) (*models.CustomItem, error) {
+	logger.Debug(ctx, "service: CustomItemService.Create called", "userID", userID)
+
+	existing, err := s.customItemRepo.ListByUser(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "service: CustomItemService.Create - error fetching items", "error", err)
+		return nil, err
+	}
+	if len(existing) >= models.MaxCustomItemsPerUser {
+		logger.Warn(ctx, "service: CustomItemService.Create - too many items", "count", len(existing))
+		return nil, ErrTooManyCustomItems
+	}
+
+	item, err := s.buildItem(ctx, req, existing, "")
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	item.UserID = userID
+	item.CreatedAt = now
+	item.UpdatedAt = now
+	if err := s.customItemRepo.Create(ctx, item); err != nil {
+		logger.Error(ctx, "service: CustomItemService.Create - error storing item", "error", err)
+		return nil, err
+	}
+
+	logger.Info(ctx, "service: CustomItemService.Create - item created", "uniqueName", item.UniqueName, "componentCount", len(item.Components))
+	return item, nil
+}
+
+// Update replaces the user's custom item with uniqueName, keeping its
+// uniqueName so wishlist entries and other custom items still refer to it.
+func (s *CustomItemService) Update(ctx context.Context, userID, uniqueName string, req models.CustomItemRequest) (*models.CustomItem, error) {
+	logger.Debug(ctx, "service: CustomItemService.Update called", "userID", userID, "uniqueName", uniqueName)
+
+	existing, err := s.customItemRepo.ListByUser(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "service: CustomItemService.Update - error fetching items", "error", err)
+		return nil, err
+	}
+	var current *models.CustomItem
+	for i := range existing {
+		if existing[i].UniqueName == uniqueName {
+			current = &existing[i]
+		}
+	}
+	if current == nil {
+		logger.Warn(ctx, "service: CustomItemService.Update - item not found", "uniqueName", uniqueName)
+		return nil, ErrCustomItemNotFound
+	}
+
+	item, err := s.buildItem(ctx, req, existing, uniqueName)
+	if err != nil {
+		return nil, err
+	}
+	item.ID = current.ID
+	item.UserID = userID
+	item.UniqueName = uniqueName
+	item.CreatedAt = current.CreatedAt
+	item.UpdatedAt = s.now()
+	updated, err := s.customItemRepo.Update(ctx, item)
+	if err != nil {
+		logger.Error(ctx, "service: CustomItemService.Update - error storing item", "error", err)
+		return nil, err
+	}
+	if !updated {
+		logger.Warn(ctx, "service: CustomItemService.Update - item deleted concurrently", "uniqueName", uniqueName)
+		return nil, ErrCustomItemNotFound
+	}
+	invalidateMaterials(ctx, s.materialsCache, userID)
+
+	logger.Info(ctx, "service: CustomItemService.Update - item updated", "uniqueName", uniqueName, "componentCount", len(item.Components))
+	return item, nil
+}
+
+// Delete removes the user's custom item. Wishlist entries and other custom
+// items that refer to it are left alone, like references to items dropped
+// from the game data.
+func (s *CustomItemService) Delete(ctx context.Context, userID, uniqueName string) error {
+	logger.Debug(ctx, "service: CustomItemService.Delete called", "userID", userID, "uniqueName", uniqueName)
+
+	deleted, err := s.customItemRepo.Delete(ctx, userID, uniqueName)
+	if err != nil {
+		logger.Error(ctx, "service: CustomItemService.Delete - error deleting item", "error", err)
+		return err
+	}
+	if !deleted {
+		logger.Warn(ctx, "service: CustomItemService.Delete - item not found", "uniqueName", uniqueName)
+		return ErrCustomItemNotFound
+	}
+	invalidateMaterials(ctx, s.materialsCache, userID)
+
+	logger.Info(ctx, "service: CustomItemService.Delete - item deleted", "uniqueName", uniqueName)
+	return nil
+}
+
+// buildItem validates req and returns the item it describes, or an error
+// wrapping ErrInvalidCustomItem that names the problem. existing are the
+// user's custom items, which components may refer to; self is the uniqueName
+// of the item being updated, which its own components may not.
+func (s *CustomItemService) buildItem(ctx context.Context, req models.CustomItemRequest, existing []models.CustomItem, self string) (*models.CustomItem, error) {
+	invalid := func(format string, args ...any) error {
+		err := fmt.Errorf("%w: "+format, append([]any{ErrInvalidCustomItem}, args...)...)
+		logger.Warn(ctx, "service: CustomItemService - invalid item", "error", err)
+		return err
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, invalid("name is required")
+	}
+	if utf8.RuneCountInString(name) > maxCustomItemNameLength {
+		return nil, invalid("name must be at most %d characters", maxCustomItemNameLength)
+	}
+	description := strings.TrimSpace(req.Description)
+	if utf8.RuneCountInString(description) > maxCustomItemDescriptionLength {
+		return nil, invalid("description must be at most %d characters", maxCustomItemDescriptionLength)
+	}
+	if req.BuildPrice < 0 || req.BuildPrice > MaxCustomItemBuildPrice {
+		return nil, invalid("buildPrice must be between 0 and %d", MaxCustomItemBuildPrice)
+	}
+	if len(req.Components) > models.MaxCustomItemComponents {
+		return nil, invalid("at most %d components are allowed", models.MaxCustomItemComponents)
+	}
+
+	custom := make(map[string]*models.CustomItem, len(existing))
+	for i := range existing {
+		custom[existing[i].UniqueName] = &existing[i]
+	}
+
+	components := make([]models.Component, 0, len(req.Components))
+	var lookup []string
+	seen := make(map[string]bool, len(req.Components))
+	for _, c := range req.Components {
+		uniqueName, err := models.CanonicalUniqueName(c.UniqueName)
+		if err != nil {
+			return nil, invalid("component uniqueName %q: %v", c.UniqueName, err)
+		}
+		if uniqueName == self {
+			return nil, invalid("an item cannot be its own component")
+		}
+		if seen[uniqueName] {
+			return nil, invalid("duplicate component %s", uniqueName)
+		}
+		seen[uniqueName] = true
+		if c.ItemCount < 1 || c.ItemCount > MaxCustomItemComponentCount {
+			return nil, invalid("itemCount for %s must be between 1 and %d", uniqueName, MaxCustomItemComponentCount)
+		}
+		componentName := strings.TrimSpace(c.Name)
+		if utf8.RuneCountInString(componentName) > maxCustomItemNameLength {
+			return nil, invalid("component name for %s must be at most %d characters", uniqueName, maxCustomItemNameLength)
+		}
+		if models.IsCustomItemName(uniqueName) && custom[uniqueName] == nil {
+			return nil, invalid("component %s is not one of your custom items", uniqueName)
+		}
+		if !models.IsCustomItemName(uniqueName) {
+			lookup = append(lookup, uniqueName)
+		}
+		components = append(components, models.Component{UniqueName: uniqueName, Name: componentName, ItemCount: c.ItemCount})
+	}
+
+	var found map[string]*models.Item
+	if len(lookup) > 0 {
+		var err error
+		found, err = s.itemRepo.FindByUniqueNames(ctx, lookup)
+		if err != nil {
+			logger.Error(ctx, "service: CustomItemService - error looking up components", "error", err)
+			return nil, err
+		}
+	}
+
+	// Named components default to the name of the item they refer to;
+	// free-form materials have nothing to default to.
+	for i := range components {
+		component := &components[i]
+		if other := custom[component.UniqueName]; other != nil {
+			if component.Name == "" {
+				component.Name = other.Name
+			}
+			continue
+		}
+		if item := found[component.UniqueName]; item != nil {
+			if component.Name == "" {
+				component.Name = item.Name
+			}
+			component.ImageName = item.ImageName
+			continue
+		}
+		if component.Name == "" {
+			return nil, invalid("component %s is not a known item, so it needs a name", component.UniqueName)
+		}
+	}
+
+	return &models.CustomItem{
+		Name:        name,
+		Description: description,
+		BuildPrice:  req.BuildPrice,
+		Components:  components,
+	}, nil
+}
+
+// customItemOverlay serves one user's custom items ahead of the game items,
+// so lookups made on the user's behalf find both. Other users' custom items
+// are never found.
+type customItemOverlay struct {
+	repository.ItemRepositoryInterface
+	custom map[string]*models.Item
+}
+
+// itemsForUser returns items with userID's custom items laid over it, or
+// items itself when customItemRepo is nil.
+func itemsForUser(ctx context.Context, items repository.ItemRepositoryInterface, customItemRepo repository.CustomItemRepositoryInterface, userID string) (repository.ItemRepositoryInterface, error) {
+	if customItemRepo == nil {
+		return items, nil
+	}
+	customItems, err := customItemRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	overlay := &customItemOverlay{ItemRepositoryInterface: items, custom: make(map[string]*models.Item, len(customItems))}
+	for i := range customItems {
+		overlay.custom[customItems[i].UniqueName] = customItems[i].ToItem()
+	}
+	return overlay, nil
+}
+
+func (o *customItemOverlay) FindByUniqueName(ctx context.Context, uniqueName string) (*models.Item, error) {
+	if models.IsCustomItemName(uniqueName) {
+		return o.custom[uniqueName], nil
+	}
+	return o.ItemRepositoryInterface.FindByUniqueName(ctx, uniqueName)
+}
+
+func (o *customItemOverlay) FindByUniqueNames(ctx context.Context, uniqueNames []string) (map[string]*models.Item, error) {
+	var game []string
+	found := make(map[string]*models.Item)
+	for _, uniqueName := range uniqueNames {
+		if !models.IsCustomItemName(uniqueName) {
+			game = append(game, uniqueName)
+		} else if item := o.custom[uniqueName]; item != nil {
+			found[uniqueName] = item
+		}
+	}
+	if len(game) == 0 {
+		return found, nil
+	}
+
+	items, err := o.ItemRepositoryInterface.FindByUniqueNames(ctx, game)
+	if err != nil {
+		return nil, err
+	}
+	for uniqueName, item := range items {
+		found[uniqueName] = item
+	}
+	return found, nil
+}
@@ -0,0 +1,272 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository/memory"
+)
+
+// failingCustomItemRepository fails every ListByUser, as an unreachable
+// user-items collection would.
+type failingCustomItemRepository struct {
+	*memory.CustomItemRepository
+}
+
+func (r failingCustomItemRepository) ListByUser(ctx context.Context, userID string) ([]models.CustomItem, error) {
+	return nil, errors.New("database error")
+}
+
+func newCustomItemFixture() (*CustomItemService, *memory.CustomItemRepository) {
+	items := memory.NewItemRepository()
+	items.Add("misc",
+		models.Item{UniqueName: "/Lotus/Types/Forma", Name: "Forma", ImageName: "forma.png"},
+		models.Item{UniqueName: "/Lotus/Types/Alloy", Name: "Alloy Plate"},
+	)
+	customItems := memory.NewCustomItemRepository()
+	service := NewCustomItemService(customItems, items)
+	service.now = func() time.Time { return time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC) }
+	return service, customItems
+}
+
+func TestCustomItemService_Create(t *testing.T) {
+	service, _ := newCustomItemFixture()
+	ctx := context.Background()
+
+	item, err := service.Create(ctx, "user-123", models.CustomItemRequest{
+		Name:        "  Dojo Garden  ",
+		Description: "Tenno garden decoration",
+		BuildPrice:  5000,
+		Components: []models.CustomItemComponentRequest{
+			{UniqueName: "/Lotus/Types/Forma", ItemCount: 2},
+			{UniqueName: "/Lotus/Types/Alloy", Name: "Alloy", ItemCount: 300},
+			{UniqueName: "/Out/Of/Game/Stone", Name: "River Stone", ItemCount: 4},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !models.IsCustomItemName(item.UniqueName) || item.Name != "Dojo Garden" || item.UserID != "user-123" {
+		t.Errorf("expected a trimmed custom item owned by the user, got %+v", item)
+	}
+	forma := item.Components[0]
+	if forma.Name != "Forma" || forma.ImageName != "forma.png" {
+		t.Errorf("expected the game item's name and image as defaults, got %+v", forma)
+	}
+	if item.Components[1].Name != "Alloy" {
+		t.Errorf("expected a given name to be kept, got %q", item.Components[1].Name)
+	}
+
+	nested, err := service.Create(ctx, "user-123", models.CustomItemRequest{
+		Name:       "Dojo",
+		Components: []models.CustomItemComponentRequest{{UniqueName: item.UniqueName, ItemCount: 1}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error creating a nested item: %v", err)
+	}
+	if nested.Components[0].Name != "Dojo Garden" {
+		t.Errorf("expected the custom component's name as default, got %q", nested.Components[0].Name)
+	}
+
+	listed, err := service.List(ctx, "user-123")
+	if err != nil || len(listed) != 2 {
+		t.Fatalf("expected 2 items, got %d (%v)", len(listed), err)
+	}
+}
+
+func TestCustomItemService_Create_Invalid(t *testing.T) {
+	service, customItems := newCustomItemFixture()
+	ctx := context.Background()
+	other := &models.CustomItem{UserID: "user-456", Name: "Theirs"}
+	if err := customItems.Create(ctx, other); err != nil {
+		t.Fatalf("seeding: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		req  models.CustomItemRequest
+	}{
+		{name: "missing name", req: models.CustomItemRequest{Name: "  "}},
+		{name: "long name", req: models.CustomItemRequest{Name: strings.Repeat("a", maxCustomItemNameLength+1)}},
+		{name: "negative build price", req: models.CustomItemRequest{Name: "x", BuildPrice: -1}},
+		{name: "zero item count", req: models.CustomItemRequest{Name: "x", Components: []models.CustomItemComponentRequest{{UniqueName: "/Lotus/Types/Forma"}}}},
+		{name: "bad uniqueName", req: models.CustomItemRequest{Name: "x", Components: []models.CustomItemComponentRequest{{UniqueName: "Forma", ItemCount: 1}}}},
+		{name: "duplicate component", req: models.CustomItemRequest{Name: "x", Components: []models.CustomItemComponentRequest{
+			{UniqueName: "/Lotus/Types/Forma", ItemCount: 1},
+			{UniqueName: "/Lotus/Types/Forma", ItemCount: 2},
+		}}},
+		{name: "unnamed unknown component", req: models.CustomItemRequest{Name: "x", Components: []models.CustomItemComponentRequest{{UniqueName: "/Out/Of/Game", ItemCount: 1}}}},
+		{name: "another user's custom item", req: models.CustomItemRequest{Name: "x", Components: []models.CustomItemComponentRequest{{UniqueName: other.UniqueName, ItemCount: 1}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.Create(ctx, "user-123", tt.req); !errors.Is(err, ErrInvalidCustomItem) {
+				t.Errorf("expected ErrInvalidCustomItem, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCustomItemService_Create_TooMany(t *testing.T) {
+	service, customItems := newCustomItemFixture()
+	ctx := context.Background()
+	for i := 0; i < models.MaxCustomItemsPerUser; i++ {
+		if err := customItems.Create(ctx, &models.CustomItem{UserID: "user-123", Name: fmt.Sprintf("Item %d", i)}); err != nil {
+			t.Fatalf("seeding: %v", err)
+		}
+	}
+
+	if _, err := service.Create(ctx, "user-123", models.CustomItemRequest{Name: "One more"}); !errors.Is(err, ErrTooManyCustomItems) {
+		t.Errorf("expected ErrTooManyCustomItems, got %v", err)
+	}
+}
+
+func TestCustomItemService_Search(t *testing.T) {
+	service, _ := newCustomItemFixture()
+	ctx := context.Background()
+	for _, name := range []string{"Dojo Garden", "Dojo Hall", "Clan Banner"} {
+		if _, err := service.Create(ctx, "user-123", models.CustomItemRequest{Name: name}); err != nil {
+			t.Fatalf("seeding: %v", err)
+		}
+	}
+	if _, err := service.Create(ctx, "user-456", models.CustomItemRequest{Name: "Dojo Tower"}); err != nil {
+		t.Fatalf("seeding: %v", err)
+	}
+
+	page, err := service.Search(ctx, "user-123", models.SearchParams{Query: "dojo", Limit: 1, Offset: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if page.Total != 2 || len(page.Items) != 1 || page.Items[0].Name != "Dojo Hall" {
+		t.Errorf("expected the second of 2 matches, got %+v", page)
+	}
+	if page.Items[0].Category != models.CustomItemCategory {
+		t.Errorf("expected category %q, got %q", models.CustomItemCategory, page.Items[0].Category)
+	}
+}
+
+func TestCustomItemService_UpdateAndDelete(t *testing.T) {
+	service, _ := newCustomItemFixture()
+	ctx := context.Background()
+	cache, _ := newTestMaterialsCache(10)
+	service.SetMaterialsCache(cache)
+	key := MaterialsCacheKey{UserID: "user-123"}
+
+	item, err := service.Create(ctx, "user-123", models.CustomItemRequest{Name: "Dojo Garden"})
+	if err != nil {
+		t.Fatalf("seeding: %v", err)
+	}
+
+	if _, err := service.Update(ctx, "user-456", item.UniqueName, models.CustomItemRequest{Name: "Stolen"}); !errors.Is(err, ErrCustomItemNotFound) {
+		t.Errorf("expected another user's update to be not found, got %v", err)
+	}
+	if _, err := service.Update(ctx, "user-123", item.UniqueName, models.CustomItemRequest{
+		Name:       "Loop",
+		Components: []models.CustomItemComponentRequest{{UniqueName: item.UniqueName, ItemCount: 1}},
+	}); !errors.Is(err, ErrInvalidCustomItem) {
+		t.Errorf("expected a self-referencing update to be invalid, got %v", err)
+	}
+
+	cache.Set(ctx, key, testMaterialsResponse(1000))
+	updated, err := service.Update(ctx, "user-123", item.UniqueName, models.CustomItemRequest{Name: "Dojo Garden II", BuildPrice: 100})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updated.UniqueName != item.UniqueName || updated.Name != "Dojo Garden II" || !updated.CreatedAt.Equal(item.CreatedAt) {
+		t.Errorf("expected the item to be replaced in place, got %+v", updated)
+	}
+	if _, ok := cache.Get(ctx, key); ok {
+		t.Error("expected the update to invalidate cached materials")
+	}
+
+	if err := service.Delete(ctx, "user-456", item.UniqueName); !errors.Is(err, ErrCustomItemNotFound) {
+		t.Errorf("expected another user's delete to be not found, got %v", err)
+	}
+	cache.Set(ctx, key, testMaterialsResponse(1000))
+	if err := service.Delete(ctx, "user-123", item.UniqueName); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := cache.Get(ctx, key); ok {
+		t.Error("expected the delete to invalidate cached materials")
+	}
+	if listed, _ := service.List(ctx, "user-123"); len(listed) != 0 {
+		t.Errorf("expected no items left, got %d", len(listed))
+	}
+}
+
+func TestCustomItemService_RepositoryError(t *testing.T) {
+	service, customItems := newCustomItemFixture()
+	service.customItemRepo = failingCustomItemRepository{customItems}
+	ctx := context.Background()
+
+	if _, err := service.List(ctx, "user-123"); err == nil {
+		t.Error("expected List to fail")
+	}
+	if _, err := service.Search(ctx, "user-123", models.SearchParams{}); err == nil {
+		t.Error("expected Search to fail")
+	}
+	if _, err := service.Create(ctx, "user-123", models.CustomItemRequest{Name: "x"}); err == nil {
+		t.Error("expected Create to fail")
+	}
+}
+
+func TestItemsForUser_OnlyServesTheUsersCustomItems(t *testing.T) {
+	items := memory.NewItemRepository()
+	items.Add("misc", models.Item{UniqueName: "/Lotus/Types/Forma", Name: "Forma"})
+	customItems := memory.NewCustomItemRepository()
+	ctx := context.Background()
+	mine := &models.CustomItem{UserID: "user-123", Name: "Mine"}
+	theirs := &models.CustomItem{UserID: "user-456", Name: "Theirs"}
+	for _, item := range []*models.CustomItem{mine, theirs} {
+		if err := customItems.Create(ctx, item); err != nil {
+			t.Fatalf("seeding: %v", err)
+		}
+	}
+
+	repo, err := itemsForUser(ctx, items, customItems, "user-123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	found, err := repo.FindByUniqueNames(ctx, []string{"/Lotus/Types/Forma", mine.UniqueName, theirs.UniqueName})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(found) != 2 || found[mine.UniqueName].Category != models.CustomItemCategory || found["/Lotus/Types/Forma"] == nil {
+		t.Errorf("expected Forma and the user's own item, got %v", found)
+	}
+	if item, _ := repo.FindByUniqueName(ctx, theirs.UniqueName); item != nil {
+		t.Errorf("expected another user's item to be hidden, got %+v", item)
+	}
+}
+
+func TestWishlistService_CustomItems(t *testing.T) {
+	ctx := context.Background()
+	customItems := memory.NewCustomItemRepository()
+	mine := &models.CustomItem{UserID: "user-123", Name: "Dojo Garden"}
+	if err := customItems.Create(ctx, mine); err != nil {
+		t.Fatalf("seeding: %v", err)
+	}
+	service := NewWishlistService(memory.NewWishlistRepository(), memory.NewItemRepository())
+	service.SetCustomItemRepository(customItems)
+
+	if err := service.AddItem(ctx, "user-456", models.AddItemRequest{UniqueName: mine.UniqueName, Quantity: 1}); !errors.Is(err, ErrItemNotFound) {
+		t.Errorf("expected another user's custom item to be not found, got %v", err)
+	}
+	if err := service.AddItem(ctx, "user-123", models.AddItemRequest{UniqueName: mine.UniqueName, Quantity: 1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expanded, err := service.GetExpandedWishlist(ctx, "user-123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(expanded.Items) != 1 || expanded.Items[0].Item == nil || expanded.Items[0].Item.Category != models.CustomItemCategory {
+		t.Errorf("expected the custom item in the expanded wishlist, got %+v", expanded.Items)
+	}
+}
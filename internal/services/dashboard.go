@@ -0,0 +1,179 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+)
+
+// DashboardService gathers the home screen's resources in parallel, so the
+// app needs a single request.
+type DashboardService struct {
+	wishlistService   WishlistServiceInterface
+	materialResolver  MaterialResolverInterface
+	foundryService    FoundryServiceInterface
+	opportunityFinder OpportunityFinderInterface
+}
+
+func NewDashboardService(wishlistService WishlistServiceInterface, materialResolver MaterialResolverInterface, foundryService FoundryServiceInterface, opportunityFinder OpportunityFinderInterface) *DashboardService {
+	return &DashboardService{
+		wishlistService:   wishlistService,
+		materialResolver:  materialResolver,
+		foundryService:    foundryService,
+		opportunityFinder: opportunityFinder,
+	}
+}
+
+// GetDashboard fails only when the wishlist cannot be read; the materials,
+// foundry and alerts are degraded instead.
+func (s *DashboardService) GetDashboard(ctx context.Context, userID string) (*models.Dashboard, error) {
+	logger.Debug(ctx, "service: DashboardService.GetDashboard called", "userID", userID)
+
+	var (
+		wg            sync.WaitGroup
+		wishlist      *models.ExpandedWishlist
+		wishlistErr   error
+		materials     *models.MaterialsResponse
+		materialsErr  error
+		foundry       *models.Foundry
+		foundryErr    error
+		opportunities *models.Opportunities
+		alertsErr     error
+	)
+	wg.Add(4)
+	go func() {
+		defer wg.Done()
+		wishlist, wishlistErr = s.wishlistService.GetExpandedWishlist(ctx, userID)
+	}()
+	go func() {
+		defer wg.Done()
+		materials, materialsErr = s.materialResolver.GetMaterials(ctx, userID)
+	}()
+	go func() {
+		defer wg.Done()
+		foundry, foundryErr = s.foundryService.GetFoundry(ctx, userID)
+	}()
+	go func() {
+		defer wg.Done()
+		opportunities, alertsErr = s.opportunityFinder.GetOpportunities(ctx, userID)
+	}()
+	wg.Wait()
+
+	if wishlistErr != nil {
+		logger.Error(ctx, "service: DashboardService.GetDashboard - error fetching wishlist", "error", wishlistErr)
+		return nil, wishlistErr
+	}
+
+	dashboard := &models.Dashboard{
+		Wishlist:  summarizeWishlist(wishlist),
+		TopItems:  topWishlistItems(wishlist),
+		Materials: models.MaterialHighlights{Highlights: []models.MaterialRequirement{}},
+		Foundry:   models.Foundry{Builds: []models.FoundryBuildStatus{}},
+		Alerts:    []models.Opportunity{},
+	}
+
+	if materialsErr != nil {
+		logger.Error(ctx, "service: DashboardService.GetDashboard - error resolving materials, continuing without them", "error", materialsErr)
+		dashboard.MarkDegraded(models.SectionDashboardMaterials, "materials unavailable")
+	} else if materials != nil {
+		dashboard.Materials = highlightMaterials(materials)
+		for _, section := range materials.Degraded {
+			dashboard.MarkDegraded(section.Section, section.Reason)
+		}
+	}
+
+	if foundryErr != nil {
+		logger.Error(ctx, "service: DashboardService.GetDashboard - error fetching foundry, continuing without it", "error", foundryErr)
+		dashboard.MarkDegraded(models.SectionDashboardFoundry, "foundry unavailable")
+	} else if foundry != nil {
+		dashboard.Foundry = *foundry
+	}
+
+	if alertsErr != nil {
+		logger.Error(ctx, "service: DashboardService.GetDashboard - error finding alerts, continuing without them", "error", alertsErr)
+		dashboard.MarkDegraded(models.SectionDashboardAlerts, "alerts unavailable")
+	} else if opportunities != nil {
+		for _, opportunity := range opportunities.Opportunities {
+			if opportunity.Kind == models.WorldStateAlert {
+				dashboard.Alerts = append(dashboard.Alerts, opportunity)
+			}
+		}
+	}
+
+	logger.Info(ctx, "service: DashboardService.GetDashboard - completed", "itemCount", dashboard.Wishlist.Items, "alertCount", len(dashboard.Alerts), "degraded", dashboard.IsDegraded())
+	return dashboard, nil
+}
+
+func summarizeWishlist(wishlist *models.ExpandedWishlist) models.WishlistSummary {
+	var summary models.WishlistSummary
+	if wishlist == nil || len(wishlist.Items) == 0 {
+		return summary
+	}
+	completion := 0
+	for _, item := range wishlist.Items {
+		summary.Items++
+		summary.Quantity += item.Quantity
+		completion += item.Completion
+		if item.Completion >= 100 {
+			summary.Completed++
+		}
+	}
+	summary.Completion = completion / summary.Items
+	return summary
+}
+
+// topWishlistItems returns the unfinished items closest to completion, ties
+// going to the item added first.
+func topWishlistItems(wishlist *models.ExpandedWishlist) []models.ExpandedWishlistItem {
+	items := []models.ExpandedWishlistItem{}
+	if wishlist == nil {
+		return items
+	}
+	for _, item := range wishlist.Items {
+		if item.Completion < 100 {
+			items = append(items, item)
+		}
+	}
+	slices.SortStableFunc(items, func(a, b models.ExpandedWishlistItem) int {
+		if c := cmp.Compare(b.Completion, a.Completion); c != 0 {
+			return c
+		}
+		return a.AddedAt.Compare(b.AddedAt)
+	})
+	if len(items) > models.DashboardTopItems {
+		items = items[:models.DashboardTopItems]
+	}
+	return items
+}
+
+// highlightMaterials picks the outstanding materials with the most
+// remaining, ties by name.
+func highlightMaterials(materials *models.MaterialsResponse) models.MaterialHighlights {
+	highlights := models.MaterialHighlights{
+		Highlights:      []models.MaterialRequirement{},
+		TotalCredits:    materials.TotalCredits,
+		RushPlatinum:    materials.RushPlatinum,
+		Truncated:       materials.Truncated,
+		TruncatedReason: materials.TruncatedReason,
+	}
+	for _, m := range materials.Materials {
+		if m.Remaining > 0 {
+			highlights.Highlights = append(highlights.Highlights, m)
+		}
+	}
+	highlights.Outstanding = len(highlights.Highlights)
+	slices.SortStableFunc(highlights.Highlights, func(a, b models.MaterialRequirement) int {
+		if c := cmp.Compare(b.Remaining, a.Remaining); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Name, b.Name)
+	})
+	if len(highlights.Highlights) > models.DashboardMaterialHighlights {
+		highlights.Highlights = highlights.Highlights[:models.DashboardMaterialHighlights]
+	}
+	return highlights
+}
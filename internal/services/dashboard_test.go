@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+func newDashboardFixture() (*mocks.MockWishlistService, *mocks.MockMaterialResolver, *mocks.MockFoundryService, *mocks.MockOpportunityFinder) {
+	added := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	wishlist := &mocks.MockWishlistService{
+		GetExpandedWishlistFunc: func(ctx context.Context, userID string) (*models.ExpandedWishlist, error) {
+			item := func(uniqueName string, quantity, completion, day int) models.ExpandedWishlistItem {
+				return models.ExpandedWishlistItem{WishlistItem: models.WishlistItem{UniqueName: uniqueName, Quantity: quantity, Completion: completion, AddedAt: added.AddDate(0, 0, day)}}
+			}
+			return &models.ExpandedWishlist{UserID: userID, Items: []models.ExpandedWishlistItem{
+				item("/Lotus/Done", 1, 100, 0),
+				item("/Lotus/Half", 2, 50, 1),
+				item("/Lotus/Fresh", 1, 0, 2),
+				item("/Lotus/AlsoHalf", 1, 50, 0),
+				item("/Lotus/A", 1, 0, 3),
+				item("/Lotus/B", 1, 0, 4),
+				item("/Lotus/C", 1, 0, 5),
+			}}, nil
+		},
+	}
+	materials := &mocks.MockMaterialResolver{
+		GetMaterialsFunc: func(ctx context.Context, userID string) (*models.MaterialsResponse, error) {
+			response := &models.MaterialsResponse{TotalCredits: 50000, RushPlatinum: 30}
+			for i, name := range []string{"Alloy", "Ferrite", "Rubedo", "Salvage", "Plastids", "Circuits"} {
+				response.Materials = append(response.Materials, models.MaterialRequirement{Name: name, TotalCount: 100, Remaining: 100 * (i % 3)})
+			}
+			response.MarkDegraded(models.SectionOwnedMaterials, "owned materials unavailable")
+			return response, nil
+		},
+	}
+	foundry := &mocks.MockFoundryService{
+		GetFoundryFunc: func(ctx context.Context, userID string) (*models.Foundry, error) {
+			return &models.Foundry{Builds: []models.FoundryBuildStatus{{FoundryBuild: models.FoundryBuild{UniqueName: "/Lotus/Forma"}, RemainingSeconds: 60}}}, nil
+		},
+	}
+	opportunities := &mocks.MockOpportunityFinder{
+		GetOpportunitiesFunc: func(ctx context.Context, userID string) (*models.Opportunities, error) {
+			return &models.Opportunities{Opportunities: []models.Opportunity{
+				{WorldStateEvent: models.WorldStateEvent{ID: "fissure", Kind: models.WorldStateFissure}},
+				{WorldStateEvent: models.WorldStateEvent{ID: "alert", Kind: models.WorldStateAlert}},
+			}}, nil
+		},
+	}
+	return wishlist, materials, foundry, opportunities
+}
+
+func TestDashboardService_GetDashboard(t *testing.T) {
+	wishlist, materials, foundry, opportunities := newDashboardFixture()
+	service := NewDashboardService(wishlist, materials, foundry, opportunities)
+
+	dashboard, err := service.GetDashboard(context.Background(), "user-123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if expected := (models.WishlistSummary{Items: 7, Quantity: 8, Completed: 1, Completion: 28}); dashboard.Wishlist != expected {
+		t.Errorf("expected summary %+v, got %+v", expected, dashboard.Wishlist)
+	}
+	var top []string
+	for _, item := range dashboard.TopItems {
+		top = append(top, item.UniqueName)
+	}
+	if expected := []string{"/Lotus/AlsoHalf", "/Lotus/Half", "/Lotus/Fresh", "/Lotus/A", "/Lotus/B"}; !reflect.DeepEqual(top, expected) {
+		t.Errorf("expected top items %v, got %v", expected, top)
+	}
+
+	var highlights []string
+	for _, m := range dashboard.Materials.Highlights {
+		highlights = append(highlights, m.Name)
+	}
+	if expected := []string{"Circuits", "Rubedo", "Ferrite", "Plastids"}; !reflect.DeepEqual(highlights, expected) {
+		t.Errorf("expected highlights %v, got %v", expected, highlights)
+	}
+	if dashboard.Materials.Outstanding != 4 || dashboard.Materials.TotalCredits != 50000 || dashboard.Materials.RushPlatinum != 30 {
+		t.Errorf("unexpected material totals %+v", dashboard.Materials)
+	}
+
+	if len(dashboard.Foundry.Builds) != 1 {
+		t.Errorf("expected the foundry build, got %+v", dashboard.Foundry)
+	}
+	if len(dashboard.Alerts) != 1 || dashboard.Alerts[0].ID != "alert" {
+		t.Errorf("expected only the alert, got %+v", dashboard.Alerts)
+	}
+	if len(dashboard.Degraded) != 1 || dashboard.Degraded[0].Section != models.SectionOwnedMaterials {
+		t.Errorf("expected the materials' degradation carried over, got %+v", dashboard.Degraded)
+	}
+}
+
+func TestDashboardService_GetDashboard_Degraded(t *testing.T) {
+	wishlist, materials, foundry, opportunities := newDashboardFixture()
+	dbErr := errors.New("database error")
+	materials.GetMaterialsFunc = func(ctx context.Context, userID string) (*models.MaterialsResponse, error) { return nil, dbErr }
+	foundry.GetFoundryFunc = func(ctx context.Context, userID string) (*models.Foundry, error) { return nil, dbErr }
+	opportunities.GetOpportunitiesFunc = func(ctx context.Context, userID string) (*models.Opportunities, error) { return nil, dbErr }
+	service := NewDashboardService(wishlist, materials, foundry, opportunities)
+
+	dashboard, err := service.GetDashboard(context.Background(), "user-123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var sections []string
+	for _, section := range dashboard.Degraded {
+		sections = append(sections, section.Section)
+	}
+	if expected := []string{models.SectionDashboardMaterials, models.SectionDashboardFoundry, models.SectionDashboardAlerts}; !reflect.DeepEqual(sections, expected) {
+		t.Errorf("expected degraded sections %v, got %v", expected, sections)
+	}
+	if dashboard.Wishlist.Items != 7 || dashboard.Materials.Highlights == nil || dashboard.Foundry.Builds == nil || dashboard.Alerts == nil {
+		t.Errorf("expected the wishlist with empty sections, got %+v", dashboard)
+	}
+
+	wishlist.GetExpandedWishlistFunc = func(ctx context.Context, userID string) (*models.ExpandedWishlist, error) { return nil, dbErr }
+	if _, err := service.GetDashboard(context.Background(), "user-123"); !errors.Is(err, dbErr) {
+		t.Errorf("expected the wishlist error, got %v", err)
+	}
+}
+
+func TestDashboardService_GetDashboard_EmptyWishlist(t *testing.T) {
+	wishlist, materials, foundry, opportunities := newDashboardFixture()
+	wishlist.GetExpandedWishlistFunc = func(ctx context.Context, userID string) (*models.ExpandedWishlist, error) {
+		return &models.ExpandedWishlist{UserID: userID, Items: []models.ExpandedWishlistItem{}}, nil
+	}
+	service := NewDashboardService(wishlist, materials, foundry, opportunities)
+
+	dashboard, err := service.GetDashboard(context.Background(), "user-123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dashboard.Wishlist != (models.WishlistSummary{}) || len(dashboard.TopItems) != 0 || dashboard.TopItems == nil {
+		t.Errorf("expected an empty summary and top items, got %+v %+v", dashboard.Wishlist, dashboard.TopItems)
+	}
+}
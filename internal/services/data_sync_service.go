@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+)
+
+// DataVersionLayout formats the data version reported for a sync that did not
+// name one, the sync time in UTC.
+const DataVersionLayout = "20060102T150405Z"
+
+// DataSyncHook runs after the item data has been re-synced from WFCD.
+type DataSyncHook func(ctx context.Context) error
+
+// DataSyncService tracks the current item data version and fans a "data
+// synced" notification out to registered hooks, such as purging and re-warming
+// the item cache.
+type DataSyncService struct {
+	hooks []namedDataSyncHook
+
+	mu      sync.RWMutex
+	version string
+}
+
+type namedDataSyncHook struct {
+	name string
+	hook DataSyncHook
+}
+
+func NewDataSyncService(version string) *DataSyncService {
+	return &DataSyncService{version: version}
+}
+
+// Version returns the data version of the most recent sync, or the startup
+// version if no sync has been reported yet.
+func (s *DataSyncService) Version() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.version
+}
+
+// OnSync registers a hook. Hooks run in registration order. Register all hooks
+// before serving requests; OnSync is not safe to call concurrently with
+// NotifySynced.
+func (s *DataSyncService) OnSync(name string, hook DataSyncHook) {
+	s.hooks = append(s.hooks, namedDataSyncHook{name: name, hook: hook})
+}
+
+// NotifySynced records version as the current data version, then runs every
+// hook, continuing past failures, and returns the joined hook errors.
+func (s *DataSyncService) NotifySynced(ctx context.Context, version string) error {
+	logger.Info(ctx, "service: DataSyncService.NotifySynced called", "version", version, "hookCount", len(s.hooks))
+
+	s.mu.Lock()
+	s.version = version
+	s.mu.Unlock()
+
+	return s.runHooks(ctx)
+}
+
+// Flush runs every hook without changing the data version, so an admin can
+// purge and re-warm the caches when they are suspected to be stale.
+func (s *DataSyncService) Flush(ctx context.Context) error {
+	logger.Info(ctx, "service: DataSyncService.Flush called", "version", s.Version(), "hookCount", len(s.hooks))
+	return s.runHooks(ctx)
+}
+
+func (s *DataSyncService) runHooks(ctx context.Context) error {
+	var errs []error
+	for _, h := range s.hooks {
+		if err := h.hook(ctx); err != nil {
+			logger.Error(ctx, "service: DataSyncService.runHooks - hook failed", "hook", h.name, "error", err)
+			errs = append(errs, err)
+			continue
+		}
+		logger.Debug(ctx, "service: DataSyncService.runHooks - hook completed", "hook", h.name)
+	}
+	return errors.Join(errs...)
+}
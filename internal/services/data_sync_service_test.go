@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDataSyncService_NotifySynced(t *testing.T) {
+	var calls []string
+	hookErr := errors.New("purge failed")
+
+	service := NewDataSyncService("v1")
+	service.OnSync("first", func(ctx context.Context) error {
+		calls = append(calls, "first")
+		return hookErr
+	})
+	service.OnSync("second", func(ctx context.Context) error {
+		calls = append(calls, "second")
+		return nil
+	})
+
+	err := service.NotifySynced(context.Background(), "v2")
+	if !errors.Is(err, hookErr) {
+		t.Errorf("expected hook error, got %v", err)
+	}
+	if !reflect.DeepEqual(calls, []string{"first", "second"}) {
+		t.Errorf("expected hooks to run in order past failures, got %v", calls)
+	}
+	if service.Version() != "v2" {
+		t.Errorf("expected version v2, got %q", service.Version())
+	}
+}
+
+func TestDataSyncService_NotifySynced_NoHooks(t *testing.T) {
+	if err := NewDataSyncService("").NotifySynced(context.Background(), "v1"); err != nil {
+		t.Errorf("expected nil error, got %v", err)
+	}
+}
+
+func TestDataSyncService_Flush(t *testing.T) {
+	var calls []string
+	service := NewDataSyncService("v1")
+	service.OnSync("cache", func(ctx context.Context) error {
+		calls = append(calls, "cache")
+		return nil
+	})
+
+	if err := service.Flush(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(calls, []string{"cache"}) {
+		t.Errorf("expected the hook to run, got %v", calls)
+	}
+	if service.Version() != "v1" {
+		t.Errorf("expected the version to be kept, got %q", service.Version())
+	}
+}
+
+func TestDataSyncService_Version_BeforeSync(t *testing.T) {
+	if version := NewDataSyncService("startup").Version(); version != "startup" {
+		t.Errorf("expected startup version, got %q", version)
+	}
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository/memory"
+)
+
+// specterRules add 3 of each specter and 2 of any other gear.
+var specterRules = []models.DefaultQuantityRule{
+	{Category: "Gear", Quantity: 2},
+	{Category: "Gear", Type: "Specter", Quantity: 3},
+}
+
+func newDefaultQuantityItems() *memory.ItemRepository {
+	items := memory.NewItemRepository()
+	items.Add("gear",
+		models.Item{UniqueName: "/Lotus/WispSpecter", Name: "Wisp Specter", Category: "Gear", Type: "Specter"},
+		models.Item{UniqueName: "/Lotus/Pad", Name: "Team Ammo Restore", Category: "Gear", Type: "Gear"},
+	)
+	items.Add("resources", models.Item{UniqueName: "/Lotus/Forma", Name: "Forma", Category: "Resources"})
+	return items
+}
+
+func TestWishlistService_AddItem_DefaultQuantities(t *testing.T) {
+	tests := []struct {
+		name       string
+		uniqueName string
+		quantity   int
+		expected   int
+	}{
+		{name: "type rule", uniqueName: "/Lotus/WispSpecter", expected: 3},
+		{name: "category rule", uniqueName: "/Lotus/Pad", expected: 2},
+		{name: "no rule", uniqueName: "/Lotus/Forma", expected: 1},
+		{name: "requested quantity wins", uniqueName: "/Lotus/WispSpecter", quantity: 5, expected: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			settingsRepo := memory.NewSettingsRepository()
+			settingsRepo.Upsert(ctx, &models.UserSettings{UserID: "user-123", DefaultQuantities: specterRules})
+			wishlists := memory.NewWishlistRepository()
+			service := NewWishlistService(wishlists, newDefaultQuantityItems())
+			service.SetSettingsRepository(settingsRepo)
+
+			if err := service.AddItem(ctx, "user-123", models.AddItemRequest{UniqueName: tt.uniqueName, Quantity: tt.quantity}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			wishlist, _ := wishlists.GetByUserID(ctx, "user-123")
+			if len(wishlist.Items) != 1 || wishlist.Items[0].Quantity != tt.expected {
+				t.Errorf("expected quantity %d, got %+v", tt.expected, wishlist.Items)
+			}
+		})
+	}
+}
+
+func TestWishlistService_AddItem_SettingsErrorFallsBackToOne(t *testing.T) {
+	ctx := context.Background()
+	wishlists := memory.NewWishlistRepository()
+	service := NewWishlistService(wishlists, newDefaultQuantityItems())
+	service.SetSettingsRepository(&mocks.MockSettingsRepository{
+		GetByUserIDFunc: func(ctx context.Context, userID string) (*models.UserSettings, error) {
+			return nil, errors.New("database error")
+		},
+	})
+
+	if err := service.AddItem(ctx, "user-123", models.AddItemRequest{UniqueName: "/Lotus/WispSpecter"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wishlist, _ := wishlists.GetByUserID(ctx, "user-123")
+	if len(wishlist.Items) != 1 || wishlist.Items[0].Quantity != 1 {
+		t.Errorf("expected quantity 1, got %+v", wishlist.Items)
+	}
+}
+
+func TestApprovalWishlistService_AddItem_DefaultQuantityIsGated(t *testing.T) {
+	ctx := context.Background()
+	household := memory.NewHouseholdRepository()
+	household.CreateLink(ctx, &models.HouseholdLink{ManagerID: "manager", MemberID: "member", QuantityThreshold: 2, Status: models.HouseholdLinkActive})
+	settingsRepo := memory.NewSettingsRepository()
+	settingsRepo.Upsert(ctx, &models.UserSettings{UserID: "member", DefaultQuantities: specterRules})
+
+	items := newDefaultQuantityItems()
+	base := NewWishlistService(memory.NewWishlistRepository(), items)
+	base.SetSettingsRepository(settingsRepo)
+	approval := NewApprovalWishlistService(base, household, items)
+	approval.SetSettingsRepository(settingsRepo)
+
+	var approvalErr *ApprovalRequiredError
+	err := approval.AddItem(ctx, "member", models.AddItemRequest{UniqueName: "/Lotus/WispSpecter"})
+	if !errors.As(err, &approvalErr) {
+		t.Fatalf("expected the default of 3 to need approval, got %v", err)
+	}
+	if approvalErr.Change.Quantity != 3 {
+		t.Errorf("expected the held change to carry the default quantity, got %d", approvalErr.Change.Quantity)
+	}
+
+	if err := approval.AddItem(ctx, "member", models.AddItemRequest{UniqueName: "/Lotus/Pad"}); err != nil {
+		t.Errorf("expected the default of 2 to be applied, got %v", err)
+	}
+}
+
+func TestSettingsService_UpdateSettings_DefaultQuantities(t *testing.T) {
+	tests := []struct {
+		name      string
+		rules     []models.DefaultQuantityRule
+		expectErr bool
+	}{
+		{name: "valid rules", rules: []models.DefaultQuantityRule{{Category: " Gear ", Type: "Specter", Quantity: 3}, {Category: "Gear", Quantity: 2}}},
+		{name: "empty list clears", rules: []models.DefaultQuantityRule{}},
+		{name: "missing category", rules: []models.DefaultQuantityRule{{Quantity: 3}}, expectErr: true},
+		{name: "zero quantity", rules: []models.DefaultQuantityRule{{Category: "Gear"}}, expectErr: true},
+		{name: "duplicate rule", rules: []models.DefaultQuantityRule{{Category: "Gear", Quantity: 1}, {Category: "gear", Quantity: 2}}, expectErr: true},
+		{name: "too many rules", rules: make([]models.DefaultQuantityRule, models.MaxDefaultQuantityRules+1), expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := memory.NewSettingsRepository()
+			service := NewSettingsService(repo)
+
+			settings, err := service.UpdateSettings(context.Background(), "user-123", models.UpdateSettingsRequest{DefaultQuantities: &tt.rules})
+			if tt.expectErr {
+				if !errors.Is(err, ErrInvalidDefaultQuantities) {
+					t.Errorf("expected ErrInvalidDefaultQuantities, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(settings.DefaultQuantities) != len(tt.rules) {
+				t.Fatalf("expected %d rules, got %+v", len(tt.rules), settings.DefaultQuantities)
+			}
+			if len(tt.rules) > 0 && settings.DefaultQuantities[0].Category != "Gear" {
+				t.Errorf("expected the category to be trimmed, got %q", settings.DefaultQuantities[0].Category)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+)
+
+const (
+	DefaultFarmingPlanLimit = 20
+	MaxFarmingPlanLimit     = 100
+)
+
+// FarmingPlanner ranks where to farm the materials a wishlist still needs,
+// from the drop tables in the item data.
+type FarmingPlanner struct {
+	materialResolver MaterialResolverInterface
+	itemRepo         repository.ItemRepositoryInterface
+}
+
+func NewFarmingPlanner(materialResolver MaterialResolverInterface, itemRepo repository.ItemRepositoryInterface) *FarmingPlanner {
+	return &FarmingPlanner{materialResolver: materialResolver, itemRepo: itemRepo}
+}
+
+// GetFarmingPlan groups the user's outstanding materials by the locations
+// dropping them and returns the limit locations covering the most, ties
+// going to the location that is the best source of more of them, then to
+// the higher combined drop chance.
+func (p *FarmingPlanner) GetFarmingPlan(ctx context.Context, userID string, limit int) (*models.FarmingPlan, error) {
+	if limit <= 0 {
+		limit = DefaultFarmingPlanLimit
+	}
+	if limit > MaxFarmingPlanLimit {
+		limit = MaxFarmingPlanLimit
+	}
+	logger.Debug(ctx, "service: FarmingPlanner.GetFarmingPlan called", "userID", userID, "limit", limit)
+
+	materials, err := p.materialResolver.GetMaterials(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "service: FarmingPlanner.GetFarmingPlan - error resolving materials", "error", err)
+		return nil, err
+	}
+
+	plan := &models.FarmingPlan{
+		Locations:       []models.FarmingLocation{},
+		Unlocated:       []models.MaterialRequirement{},
+		Truncated:       materials.Truncated,
+		TruncatedReason: materials.TruncatedReason,
+		Degradation:     materials.Degradation,
+	}
+
+	var outstanding []models.MaterialRequirement
+	var uniqueNames []string
+	for _, m := range materials.Materials {
+		if m.Remaining > 0 {
+			outstanding = append(outstanding, m)
+			uniqueNames = append(uniqueNames, m.UniqueName)
+		}
+	}
+	if len(outstanding) == 0 {
+		return plan, nil
+	}
+
+	items, err := p.itemRepo.FindByUniqueNames(ctx, uniqueNames)
+	if err != nil {
+		logger.Error(ctx, "service: FarmingPlanner.GetFarmingPlan - error fetching items", "error", err)
+		return nil, err
+	}
+
+	byLocation := make(map[string]*models.FarmingLocation)
+	for _, m := range outstanding {
+		var drops map[string]models.Drop
+		if item := items[m.UniqueName]; item != nil {
+			drops = bestDropPerLocation(item.Drops)
+		}
+		if len(drops) == 0 {
+			plan.Unlocated = append(plan.Unlocated, m)
+			continue
+		}
+
+		best := 0.0
+		for _, drop := range drops {
+			best = max(best, drop.Chance)
+		}
+		for location, drop := range drops {
+			entry := byLocation[location]
+			if entry == nil {
+				entry = &models.FarmingLocation{Location: location}
+				byLocation[location] = entry
+			}
+			isBest := drop.Chance == best
+			if isBest {
+				entry.BestFor++
+			}
+			entry.Materials = append(entry.Materials, models.FarmingMaterial{
+				UniqueName: m.UniqueName,
+				Name:       m.Name,
+				Remaining:  m.Remaining,
+				Chance:     drop.Chance,
+				Rarity:     drop.Rarity,
+				Best:       isBest,
+			})
+		}
+	}
+
+	for _, entry := range byLocation {
+		slices.SortFunc(entry.Materials, func(a, b models.FarmingMaterial) int {
+			if c := cmp.Compare(b.Chance, a.Chance); c != 0 {
+				return c
+			}
+			return strings.Compare(a.Name, b.Name)
+		})
+		plan.Locations = append(plan.Locations, *entry)
+	}
+	slices.SortFunc(plan.Locations, compareFarmingLocations)
+	if len(plan.Locations) > limit {
+		plan.Locations = plan.Locations[:limit]
+	}
+
+	logger.Debug(ctx, "service: FarmingPlanner.GetFarmingPlan - completed", "locationCount", len(byLocation), "unlocatedCount", len(plan.Unlocated))
+	return plan, nil
+}
+
+// bestDropPerLocation keeps the highest chance drop at each location; a
+// drop table may list a material once per stack size or rotation.
+func bestDropPerLocation(drops []models.Drop) map[string]models.Drop {
+	best := make(map[string]models.Drop, len(drops))
+	for _, drop := range drops {
+		if drop.Location == "" {
+			continue
+		}
+		if existing, ok := best[drop.Location]; !ok || drop.Chance > existing.Chance {
+			best[drop.Location] = drop
+		}
+	}
+	return best
+}
+
+func compareFarmingLocations(a, b models.FarmingLocation) int {
+	if c := cmp.Compare(len(b.Materials), len(a.Materials)); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(b.BestFor, a.BestFor); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(totalChance(b), totalChance(a)); c != 0 {
+		return c
+	}
+	return strings.Compare(a.Location, b.Location)
+}
+
+func totalChance(location models.FarmingLocation) float64 {
+	total := 0.0
+	for _, m := range location.Materials {
+		total += m.Chance
+	}
+	return total
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository/memory"
+)
+
+// newFarmingFixture needs alloy (Venus and Earth), salvage (Venus, best on
+// Mars) and a cell no drop table lists; the polymer is already owned.
+func newFarmingFixture() (*mocks.MockMaterialResolver, *memory.ItemRepository) {
+	resolver := &mocks.MockMaterialResolver{
+		GetMaterialsFunc: func(ctx context.Context, userID string) (*models.MaterialsResponse, error) {
+			return &models.MaterialsResponse{Materials: []models.MaterialRequirement{
+				{UniqueName: "/Lotus/Alloy", Name: "Alloy Plate", TotalCount: 500, Remaining: 500},
+				{UniqueName: "/Lotus/Cell", Name: "Orokin Cell", TotalCount: 2, Remaining: 2},
+				{UniqueName: "/Lotus/Polymer", Name: "Polymer Bundle", TotalCount: 100, Owned: 100},
+				{UniqueName: "/Lotus/Salvage", Name: "Salvage", TotalCount: 300, Remaining: 200},
+			}}, nil
+		},
+	}
+
+	items := memory.NewItemRepository()
+	items.Add("resources",
+		models.Item{UniqueName: "/Lotus/Alloy", Name: "Alloy Plate", Drops: []models.Drop{
+			{Location: "Venus/Kiliken", Chance: 0.2, Rarity: "Common"},
+			{Location: "Venus/Kiliken", Chance: 0.3, Rarity: "Common"},
+			{Location: "Earth/Gaia", Chance: 0.1, Rarity: "Uncommon"},
+		}},
+		models.Item{UniqueName: "/Lotus/Cell", Name: "Orokin Cell"},
+		models.Item{UniqueName: "/Lotus/Polymer", Name: "Polymer Bundle", Drops: []models.Drop{{Location: "Earth/Gaia", Chance: 0.9}}},
+		models.Item{UniqueName: "/Lotus/Salvage", Name: "Salvage", Drops: []models.Drop{
+			{Location: "Venus/Kiliken", Chance: 0.1},
+			{Location: "Mars/Ares", Chance: 0.4},
+		}},
+	)
+	return resolver, items
+}
+
+func TestFarmingPlanner_GetFarmingPlan(t *testing.T) {
+	resolver, items := newFarmingFixture()
+	planner := NewFarmingPlanner(resolver, items)
+
+	plan, err := planner.GetFarmingPlan(context.Background(), "user-123", 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{"Venus/Kiliken", "Mars/Ares", "Earth/Gaia"}
+	if len(plan.Locations) != len(expected) {
+		t.Fatalf("expected locations %v, got %+v", expected, plan.Locations)
+	}
+	for i, location := range expected {
+		if plan.Locations[i].Location != location {
+			t.Errorf("location %d: expected %s, got %s", i, location, plan.Locations[i].Location)
+		}
+	}
+
+	venus := plan.Locations[0]
+	if venus.BestFor != 1 || len(venus.Materials) != 2 {
+		t.Errorf("expected Venus to cover alloy and salvage and be best for alloy, got %+v", venus)
+	}
+	alloy := venus.Materials[0]
+	if alloy.UniqueName != "/Lotus/Alloy" || alloy.Chance != 0.3 || !alloy.Best || alloy.Remaining != 500 {
+		t.Errorf("expected the best alloy drop first, got %+v", alloy)
+	}
+	if salvage := venus.Materials[1]; salvage.Best {
+		t.Errorf("expected Mars to be the best salvage source, got %+v", salvage)
+	}
+	// Owned materials are not farmed.
+	for _, m := range plan.Locations[2].Materials {
+		if m.UniqueName == "/Lotus/Polymer" {
+			t.Errorf("expected owned polymer to be left out, got %+v", plan.Locations[2])
+		}
+	}
+
+	if len(plan.Unlocated) != 1 || plan.Unlocated[0].UniqueName != "/Lotus/Cell" {
+		t.Errorf("expected the cell to be unlocated, got %+v", plan.Unlocated)
+	}
+}
+
+func TestFarmingPlanner_GetFarmingPlan_Limit(t *testing.T) {
+	resolver, items := newFarmingFixture()
+	planner := NewFarmingPlanner(resolver, items)
+
+	plan, err := planner.GetFarmingPlan(context.Background(), "user-123", 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(plan.Locations) != 1 || plan.Locations[0].Location != "Venus/Kiliken" {
+		t.Errorf("expected only the top location, got %+v", plan.Locations)
+	}
+}
+
+func TestFarmingPlanner_GetFarmingPlan_NothingOutstanding(t *testing.T) {
+	resolver := &mocks.MockMaterialResolver{
+		GetMaterialsFunc: func(ctx context.Context, userID string) (*models.MaterialsResponse, error) {
+			return &models.MaterialsResponse{Truncated: true, TruncatedReason: models.TruncatedDepth}, nil
+		},
+	}
+	planner := NewFarmingPlanner(resolver, &mocks.MockItemRepository{
+		FindByUniqueNamesFunc: func(ctx context.Context, uniqueNames []string) (map[string]*models.Item, error) {
+			t.Error("expected no item lookup")
+			return nil, nil
+		},
+	})
+
+	plan, err := planner.GetFarmingPlan(context.Background(), "user-123", 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if plan.Locations == nil || plan.Unlocated == nil || len(plan.Locations) != 0 {
+		t.Errorf("expected an empty plan, got %+v", plan)
+	}
+	if !plan.Truncated || plan.TruncatedReason != models.TruncatedDepth {
+		t.Errorf("expected truncation to carry over, got %+v", plan)
+	}
+}
+
+func TestFarmingPlanner_GetFarmingPlan_Errors(t *testing.T) {
+	dbErr := errors.New("database error")
+	resolver, _ := newFarmingFixture()
+
+	tests := []struct {
+		name     string
+		resolver MaterialResolverInterface
+		items    *mocks.MockItemRepository
+	}{
+		{
+			name: "materials fail",
+			resolver: &mocks.MockMaterialResolver{GetMaterialsFunc: func(ctx context.Context, userID string) (*models.MaterialsResponse, error) {
+				return nil, dbErr
+			}},
+			items: &mocks.MockItemRepository{},
+		},
+		{
+			name:     "item lookup fails",
+			resolver: resolver,
+			items: &mocks.MockItemRepository{FindByUniqueNamesFunc: func(ctx context.Context, uniqueNames []string) (map[string]*models.Item, error) {
+				return nil, dbErr
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewFarmingPlanner(tt.resolver, tt.items).GetFarmingPlan(context.Background(), "user-123", 0)
+			if !errors.Is(err, dbErr) {
+				t.Errorf("expected %v, got %v", dbErr, err)
+			}
+		})
+	}
+}
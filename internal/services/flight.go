@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// flightGroup runs one call per key at a time; callers asking for a key
+// already in flight wait for that call's result instead of starting another.
+type flightGroup[T any] struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall[T]
+}
+
+type flightCall[T any] struct {
+	done chan struct{}
+	val  T
+	err  error
+}
+
+// do returns fn's result for key, sharing it with every caller that asks for
+// key before fn returns. fn runs detached from ctx's cancellation, so a
+// caller that gives up does not fail the others; each caller stops waiting
+// when its own ctx is done.
+func (g *flightGroup[T]) do(ctx context.Context, key string, fn func(context.Context) (T, error)) (T, bool, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall[T])
+	}
+	call, shared := g.calls[key]
+	if !shared {
+		call = &flightCall[T]{done: make(chan struct{})}
+		g.calls[key] = call
+		go g.run(context.WithoutCancel(ctx), key, call, fn)
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.val, shared, call.err
+	case <-ctx.Done():
+		var zero T
+		return zero, shared, ctx.Err()
+	}
+}
+
+func (g *flightGroup[T]) run(ctx context.Context, key string, call *flightCall[T], fn func(context.Context) (T, error)) {
+	defer func() {
+		if p := recover(); p != nil {
+			call.err = fmt.Errorf("panic: %v", p)
+		}
+		g.forgetCall(key, call)
+		close(call.done)
+	}()
+	call.val, call.err = fn(ctx)
+}
+
+// forget makes the next caller for key start a new call rather than join the
+// one in flight, which still completes for those already waiting.
+func (g *flightGroup[T]) forget(key string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.calls, key)
+}
+
+// forgetAll forgets every key in flight.
+func (g *flightGroup[T]) forgetAll() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.calls = nil
+}
+
+func (g *flightGroup[T]) forgetCall(key string, call *flightCall[T]) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.calls[key] == call {
+		delete(g.calls, key)
+	}
+}
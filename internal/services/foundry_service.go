@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// maxFoundryClockSkew is how far in the future a build's start may be, to
+// allow for a client clock running ahead of the server's.
+const maxFoundryClockSkew = 5 * time.Minute
+
+var (
+	ErrFoundryBuildNotFound = errors.New("foundry build not found")
+	ErrTooManyFoundryBuilds = fmt.Errorf("at most %d foundry builds per user", models.MaxFoundryBuilds)
+	ErrInvalidFoundryBuild  = errors.New("invalid foundry build")
+)
+
+// FoundryService tracks what users have building in their foundry. Build
+// times come from the item data when a build is started; whether a build has
+// finished is worked out whenever the foundry is read.
+type FoundryService struct {
+	foundryRepo repository.FoundryRepositoryInterface
+	itemRepo    repository.ItemRepositoryInterface
+	now         func() time.Time
+}
+
+func NewFoundryService(foundryRepo repository.FoundryRepositoryInterface, itemRepo repository.ItemRepositoryInterface) *FoundryService {
+	return &FoundryService{
+		foundryRepo: foundryRepo,
+		itemRepo:    itemRepo,
+		now:         time.Now,
+	}
+}
+
+// GetFoundry returns the user's builds, the soonest to finish first, with
+// finished builds flagged.
+func (s *FoundryService) GetFoundry(ctx context.Context, userID string) (*models.Foundry, error) {
+	logger.Debug(ctx, "service: FoundryService.GetFoundry called", "userID", userID)
+
+	builds, err := s.foundryRepo.ListByUser(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "service: FoundryService.GetFoundry - error fetching builds", "error", err)
+		return nil, err
+	}
+
+	now := s.now()
+	foundry := &models.Foundry{Builds: make([]models.FoundryBuildStatus, 0, len(builds))}
+	for _, build := range builds {
+		status := models.NewFoundryBuildStatus(build, now)
+		if status.Finished {
+			foundry.Finished++
+		}
+		foundry.Builds = append(foundry.Builds, status)
+	}
+	sort.SliceStable(foundry.Builds, func(i, j int) bool {
+		return foundry.Builds[i].ReadyAt.Before(foundry.Builds[j].ReadyAt)
+	})
+
+	logger.Debug(ctx, "service: FoundryService.GetFoundry - completed", "count", len(foundry.Builds), "finished", foundry.Finished)
+	return foundry, nil
+}
+
+// StartBuild starts tracking a build of the item, started at req.StartedAt
+// or now.
+func (s *FoundryService) StartBuild(ctx context.Context, userID string, req models.FoundryBuildRequest) (*models.FoundryBuildStatus, error) {
+	logger.Debug(ctx, "service: FoundryService.StartBuild called", "userID", userID, "uniqueName", req.UniqueName)
+
+	now := s.now()
+	startedAt := now
+	if req.StartedAt != nil {
+		startedAt = *req.StartedAt
+		if startedAt.After(now.Add(maxFoundryClockSkew)) {
+			logger.Warn(ctx, "service: FoundryService.StartBuild - start in the future", "startedAt", startedAt)
+			return nil, fmt.Errorf("%w: startedAt cannot be in the future", ErrInvalidFoundryBuild)
+		}
+	}
+
+	item, err := s.itemRepo.FindByUniqueName(ctx, req.UniqueName)
+	if err != nil {
+		logger.Error(ctx, "service: FoundryService.StartBuild - error finding item", "error", err)
+		return nil, err
+	}
+	if item == nil {
+		logger.Warn(ctx, "service: FoundryService.StartBuild - item not found", "uniqueName", req.UniqueName)
+		return nil, ErrItemNotFound
+	}
+	if item.BuildTime <= 0 {
+		logger.Warn(ctx, "service: FoundryService.StartBuild - item has no build time", "uniqueName", req.UniqueName)
+		return nil, fmt.Errorf("%w: %s is not built in the foundry", ErrInvalidFoundryBuild, item.Name)
+	}
+
+	existing, err := s.foundryRepo.ListByUser(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "service: FoundryService.StartBuild - error fetching builds", "error", err)
+		return nil, err
+	}
+	if len(existing) >= models.MaxFoundryBuilds {
+		logger.Warn(ctx, "service: FoundryService.StartBuild - too many builds", "count", len(existing))
+		return nil, ErrTooManyFoundryBuilds
+	}
+
+	build := &models.FoundryBuild{
+		UserID:     userID,
+		UniqueName: item.UniqueName,
+		Name:       item.Name,
+		BuildTime:  item.BuildTime,
+		StartedAt:  startedAt,
+		CreatedAt:  now,
+	}
+	if err := s.foundryRepo.Create(ctx, build); err != nil {
+		logger.Error(ctx, "service: FoundryService.StartBuild - error storing build", "error", err)
+		return nil, err
+	}
+
+	logger.Info(ctx, "service: FoundryService.StartBuild - build started", "id", build.ID.Hex(), "uniqueName", build.UniqueName)
+	status := models.NewFoundryBuildStatus(*build, now)
+	return &status, nil
+}
+
+// RemoveBuild stops tracking a build, as when it is claimed or cancelled.
+func (s *FoundryService) RemoveBuild(ctx context.Context, userID, id string) error {
+	logger.Debug(ctx, "service: FoundryService.RemoveBuild called", "userID", userID, "id", id)
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		logger.Warn(ctx, "service: FoundryService.RemoveBuild - invalid build ID", "id", id)
+		return ErrFoundryBuildNotFound
+	}
+	deleted, err := s.foundryRepo.Delete(ctx, userID, objectID)
+	if err != nil {
+		logger.Error(ctx, "service: FoundryService.RemoveBuild - error deleting build", "error", err)
+		return err
+	}
+	if !deleted {
+		logger.Warn(ctx, "service: FoundryService.RemoveBuild - build not found", "id", id)
+		return ErrFoundryBuildNotFound
+	}
+
+	logger.Info(ctx, "service: FoundryService.RemoveBuild - build removed", "id", id)
+	return nil
+}
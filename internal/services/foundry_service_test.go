@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository/memory"
+)
+
+var foundryNow = time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+func newFoundryFixture() *FoundryService {
+	items := memory.NewItemRepository()
+	items.Add("warframes", models.Item{UniqueName: "/Lotus/Ash", Name: "Ash", BuildTime: 72 * 3600})
+	items.Add("misc",
+		models.Item{UniqueName: "/Lotus/Forma", Name: "Forma", BuildTime: 24 * 3600},
+		models.Item{UniqueName: "/Lotus/Ferrite", Name: "Ferrite"},
+	)
+	service := NewFoundryService(memory.NewFoundryRepository(), items)
+	service.now = func() time.Time { return foundryNow }
+	return service
+}
+
+func TestFoundryService_StartBuildAndGetFoundry(t *testing.T) {
+	service := newFoundryFixture()
+	ctx := context.Background()
+
+	ash, err := service.StartBuild(ctx, "user-123", models.FoundryBuildRequest{UniqueName: "/Lotus/Ash"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ash.Name != "Ash" || ash.BuildTime != 72*3600 || !ash.StartedAt.Equal(foundryNow) {
+		t.Errorf("expected Ash started now with its build time, got %+v", ash)
+	}
+	if ash.RemainingSeconds != 72*3600 || ash.Finished {
+		t.Errorf("expected 72 hours to go, got %+v", ash)
+	}
+
+	yesterday := foundryNow.Add(-25 * time.Hour)
+	if _, err := service.StartBuild(ctx, "user-123", models.FoundryBuildRequest{UniqueName: "/Lotus/Forma", StartedAt: &yesterday}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	recent := foundryNow.Add(-time.Hour)
+	if _, err := service.StartBuild(ctx, "user-123", models.FoundryBuildRequest{UniqueName: "/Lotus/Forma", StartedAt: &recent}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := service.StartBuild(ctx, "other-user", models.FoundryBuildRequest{UniqueName: "/Lotus/Forma"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	foundry, err := service.GetFoundry(ctx, "user-123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(foundry.Builds) != 3 || foundry.Finished != 1 {
+		t.Fatalf("expected three builds, one finished, got %+v", foundry)
+	}
+	first, second, third := foundry.Builds[0], foundry.Builds[1], foundry.Builds[2]
+	if !first.Finished || first.RemainingSeconds != 0 || !first.StartedAt.Equal(yesterday) {
+		t.Errorf("expected yesterday's forma first and finished, got %+v", first)
+	}
+	if second.Finished || second.RemainingSeconds != 23*3600 {
+		t.Errorf("expected the recent forma second with 23 hours to go, got %+v", second)
+	}
+	if third.UniqueName != "/Lotus/Ash" || !third.ReadyAt.Equal(foundryNow.Add(72*time.Hour)) {
+		t.Errorf("expected Ash last, got %+v", third)
+	}
+
+	// Later, everything has finished.
+	service.now = func() time.Time { return foundryNow.Add(100 * time.Hour) }
+	if foundry, _ = service.GetFoundry(ctx, "user-123"); foundry.Finished != 3 {
+		t.Errorf("expected every build finished, got %+v", foundry)
+	}
+}
+
+func TestFoundryService_StartBuild_Errors(t *testing.T) {
+	tomorrow := foundryNow.Add(24 * time.Hour)
+
+	tests := []struct {
+		name          string
+		req           models.FoundryBuildRequest
+		expectedError error
+	}{
+		{name: "unknown item", req: models.FoundryBuildRequest{UniqueName: "/Lotus/Missing"}, expectedError: ErrItemNotFound},
+		{name: "not built in the foundry", req: models.FoundryBuildRequest{UniqueName: "/Lotus/Ferrite"}, expectedError: ErrInvalidFoundryBuild},
+		{name: "started in the future", req: models.FoundryBuildRequest{UniqueName: "/Lotus/Forma", StartedAt: &tomorrow}, expectedError: ErrInvalidFoundryBuild},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newFoundryFixture()
+
+			_, err := service.StartBuild(context.Background(), "user-123", tt.req)
+			if !errors.Is(err, tt.expectedError) {
+				t.Errorf("expected %v, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestFoundryService_StartBuild_Limit(t *testing.T) {
+	service := newFoundryFixture()
+	ctx := context.Background()
+
+	for i := 0; i < models.MaxFoundryBuilds; i++ {
+		if _, err := service.StartBuild(ctx, "user-123", models.FoundryBuildRequest{UniqueName: "/Lotus/Forma"}); err != nil {
+			t.Fatalf("unexpected error on build %d: %v", i, err)
+		}
+	}
+	if _, err := service.StartBuild(ctx, "user-123", models.FoundryBuildRequest{UniqueName: "/Lotus/Forma"}); !errors.Is(err, ErrTooManyFoundryBuilds) {
+		t.Errorf("expected ErrTooManyFoundryBuilds, got %v", err)
+	}
+}
+
+func TestFoundryService_RemoveBuild(t *testing.T) {
+	service := newFoundryFixture()
+	ctx := context.Background()
+
+	build, err := service.StartBuild(ctx, "user-123", models.FoundryBuildRequest{UniqueName: "/Lotus/Forma"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	id := build.ID.Hex()
+
+	if err := service.RemoveBuild(ctx, "other-user", id); !errors.Is(err, ErrFoundryBuildNotFound) {
+		t.Errorf("expected another user's build to be not found, got %v", err)
+	}
+	if err := service.RemoveBuild(ctx, "user-123", "not-an-id"); !errors.Is(err, ErrFoundryBuildNotFound) {
+		t.Errorf("expected an invalid ID to be not found, got %v", err)
+	}
+	if err := service.RemoveBuild(ctx, "user-123", id); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if foundry, _ := service.GetFoundry(ctx, "user-123"); len(foundry.Builds) != 0 {
+		t.Errorf("expected the build to be gone, got %+v", foundry.Builds)
+	}
+}
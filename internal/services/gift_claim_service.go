@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+)
+
+var (
+	ErrWishlistNotShared  = errors.New("wishlist not found or not public")
+	ErrCannotClaimOwnItem = errors.New("cannot claim an item on your own wishlist")
+	ErrInvalidClaimDays   = fmt.Errorf("days must be between 1 and %d", models.MaxGiftClaimDays)
+	ErrItemAlreadyClaimed = errors.New("item already claimed")
+	ErrClaimNotFound      = errors.New("claim not found")
+)
+
+// GiftClaimService lets users view the wishlists others have made public and
+// claim items on them as gifts, so two friends do not buy the same one. A
+// claimer can revoke their claim and the owner can revoke any claim on their
+// wishlist, e.g. after getting the item themselves.
+type GiftClaimService struct {
+	claimRepo    repository.GiftClaimRepositoryInterface
+	wishlistRepo repository.WishlistRepositoryInterface
+	settingsRepo repository.SettingsRepositoryInterface
+	now          func() time.Time
+}
+
+func NewGiftClaimService(claimRepo repository.GiftClaimRepositoryInterface, wishlistRepo repository.WishlistRepositoryInterface, settingsRepo repository.SettingsRepositoryInterface) *GiftClaimService {
+	return &GiftClaimService{
+		claimRepo:    claimRepo,
+		wishlistRepo: wishlistRepo,
+		settingsRepo: settingsRepo,
+		now:          time.Now,
+	}
+}
+
+// GetSharedWishlist returns ownerID's public wishlist as viewerID sees it.
+func (s *GiftClaimService) GetSharedWishlist(ctx context.Context, viewerID, ownerID string) (*models.SharedWishlist, error) {
+	logger.Debug(ctx, "service: GiftClaimService.GetSharedWishlist called", "viewerID", viewerID, "ownerID", ownerID)
+
+	wishlist, err := s.sharedWishlist(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := s.claimRepo.ListByOwner(ctx, ownerID)
+	if err != nil {
+		logger.Error(ctx, "service: GiftClaimService.GetSharedWishlist - error fetching claims", "error", err)
+		return nil, err
+	}
+	byItem := make(map[string]models.GiftClaim, len(claims))
+	for _, claim := range claims {
+		byItem[claim.UniqueName] = claim
+	}
+
+	shared := &models.SharedWishlist{OwnerID: ownerID, Items: make([]models.SharedWishlistItem, 0, len(wishlist.Items))}
+	for _, item := range wishlist.Items {
+		sharedItem := models.SharedWishlistItem{
+			UniqueName: item.UniqueName,
+			Quantity:   item.Quantity,
+			AddedAt:    item.AddedAt,
+		}
+		if claim, ok := byItem[item.UniqueName]; ok {
+			sharedItem.Claimed = true
+			sharedItem.Claim = visibleClaim(claim, viewerID)
+		}
+		shared.Items = append(shared.Items, sharedItem)
+	}
+
+	logger.Debug(ctx, "service: GiftClaimService.GetSharedWishlist - completed", "itemCount", len(shared.Items), "claimCount", len(claims))
+	return shared, nil
+}
+
+// Claim claims an item on ownerID's public wishlist for claimerID, or renews
+// claimerID's existing claim on it.
+func (s *GiftClaimService) Claim(ctx context.Context, claimerID, ownerID string, req models.ClaimGiftRequest) (*models.GiftClaim, error) {
+	logger.Debug(ctx, "service: GiftClaimService.Claim called", "claimerID", claimerID, "ownerID", ownerID, "uniqueName", req.UniqueName)
+
+	if claimerID == ownerID {
+		logger.Warn(ctx, "service: GiftClaimService.Claim - cannot claim own item")
+		return nil, ErrCannotClaimOwnItem
+	}
+	days := req.Days
+	if days == 0 {
+		days = models.DefaultGiftClaimDays
+	}
+	if days < 1 || days > models.MaxGiftClaimDays {
+		logger.Warn(ctx, "service: GiftClaimService.Claim - invalid days", "days", req.Days)
+		return nil, ErrInvalidClaimDays
+	}
+	uniqueName, err := models.CanonicalUniqueName(req.UniqueName)
+	if err != nil {
+		logger.Warn(ctx, "service: GiftClaimService.Claim - invalid uniqueName", "uniqueName", req.UniqueName, "error", err)
+		return nil, err
+	}
+
+	wishlist, err := s.sharedWishlist(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if !hasWishlistItem(wishlist, uniqueName) {
+		logger.Warn(ctx, "service: GiftClaimService.Claim - item not in wishlist", "uniqueName", uniqueName)
+		return nil, ErrItemNotInWishlist
+	}
+
+	now := s.now()
+	claim, err := s.claimRepo.Claim(ctx, &models.GiftClaim{
+		OwnerID:    ownerID,
+		UniqueName: uniqueName,
+		ClaimerID:  claimerID,
+		Anonymous:  req.Anonymous,
+		CreatedAt:  now,
+		ExpiresAt:  now.AddDate(0, 0, days),
+	})
+	if err != nil {
+		logger.Error(ctx, "service: GiftClaimService.Claim - error storing claim", "error", err)
+		return nil, err
+	}
+	if claim == nil || claim.ClaimerID != claimerID {
+		logger.Warn(ctx, "service: GiftClaimService.Claim - item already claimed", "uniqueName", uniqueName)
+		return nil, ErrItemAlreadyClaimed
+	}
+
+	logger.Info(ctx, "service: GiftClaimService.Claim - item claimed", "ownerID", ownerID, "uniqueName", uniqueName, "expiresAt", claim.ExpiresAt)
+	return claim, nil
+}
+
+// RevokeClaim removes the claim on ownerID's item. The owner may revoke any
+// claim on their wishlist; anyone else only their own.
+func (s *GiftClaimService) RevokeClaim(ctx context.Context, callerID, ownerID, uniqueName string) error {
+	logger.Debug(ctx, "service: GiftClaimService.RevokeClaim called", "callerID", callerID, "ownerID", ownerID, "uniqueName", uniqueName)
+
+	claimerID := callerID
+	if callerID == ownerID {
+		claimerID = ""
+	}
+	deleted, err := s.claimRepo.Delete(ctx, ownerID, uniqueName, claimerID)
+	if err != nil {
+		logger.Error(ctx, "service: GiftClaimService.RevokeClaim - error deleting claim", "error", err)
+		return err
+	}
+	if !deleted {
+		logger.Warn(ctx, "service: GiftClaimService.RevokeClaim - claim not found", "uniqueName", uniqueName)
+		return ErrClaimNotFound
+	}
+
+	logger.Info(ctx, "service: GiftClaimService.RevokeClaim - claim revoked", "ownerID", ownerID, "uniqueName", uniqueName, "byOwner", claimerID == "")
+	return nil
+}
+
+// ListClaims returns claimerID's active claims, soonest to expire first.
+func (s *GiftClaimService) ListClaims(ctx context.Context, claimerID string) ([]models.GiftClaim, error) {
+	logger.Debug(ctx, "service: GiftClaimService.ListClaims called", "claimerID", claimerID)
+
+	claims, err := s.claimRepo.ListByClaimer(ctx, claimerID)
+	if err != nil {
+		logger.Error(ctx, "service: GiftClaimService.ListClaims - error fetching claims", "error", err)
+		return nil, err
+	}
+	return claims, nil
+}
+
+// sharedWishlist returns ownerID's wishlist if they made it public. A missing
+// wishlist and a private one are both ErrWishlistNotShared, so the response
+// does not reveal which users exist.
+func (s *GiftClaimService) sharedWishlist(ctx context.Context, ownerID string) (*models.Wishlist, error) {
+	settings, err := s.settingsRepo.GetByUserID(ctx, ownerID)
+	if err != nil {
+		logger.Error(ctx, "service: GiftClaimService - error fetching owner settings", "error", err)
+		return nil, err
+	}
+	if settings == nil || !settings.PublicWishlist {
+		logger.Warn(ctx, "service: GiftClaimService - wishlist not public", "ownerID", ownerID)
+		return nil, ErrWishlistNotShared
+	}
+
+	wishlist, err := s.wishlistRepo.GetByUserID(ctx, ownerID)
+	if err != nil {
+		logger.Error(ctx, "service: GiftClaimService - error fetching wishlist", "error", err)
+		return nil, err
+	}
+	if wishlist == nil {
+		wishlist = &models.Wishlist{UserID: ownerID, Items: []models.WishlistItem{}}
+	}
+	return wishlist, nil
+}
+
+func hasWishlistItem(wishlist *models.Wishlist, uniqueName string) bool {
+	for _, item := range wishlist.Items {
+		if item.UniqueName == uniqueName {
+			return true
+		}
+	}
+	return false
+}
+
+// visibleClaim returns claim as viewerID may see it: anonymous claims are
+// only shown to their claimer.
+func visibleClaim(claim models.GiftClaim, viewerID string) *models.GiftClaim {
+	if claim.Anonymous && claim.ClaimerID != viewerID {
+		return nil
+	}
+	return &claim
+}
@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository/memory"
+)
+
+// newGiftClaimFixture returns a service where owner's wishlist holds
+// /Lotus/Forma and /Lotus/Orokin and is public if shared is set.
+func newGiftClaimFixture(t *testing.T, shared bool) (*GiftClaimService, *memory.GiftClaimRepository) {
+	t.Helper()
+	ctx := context.Background()
+
+	wishlists := memory.NewWishlistRepository()
+	wishlists.Seed(models.Wishlist{UserID: "owner", Items: []models.WishlistItem{
+		{UniqueName: "/Lotus/Forma", Quantity: 3},
+		{UniqueName: "/Lotus/Orokin", Quantity: 1},
+	}})
+	settings := memory.NewSettingsRepository()
+	if err := settings.Upsert(ctx, &models.UserSettings{UserID: "owner", PublicWishlist: shared}); err != nil {
+		t.Fatalf("Upsert settings: %v", err)
+	}
+
+	claims := memory.NewGiftClaimRepository()
+	return NewGiftClaimService(claims, wishlists, settings), claims
+}
+
+func TestGiftClaimService_Claim(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newGiftClaimFixture(t, true)
+	now := time.Now().Truncate(time.Second)
+	service.now = func() time.Time { return now }
+
+	claim, err := service.Claim(ctx, "alice", "owner", models.ClaimGiftRequest{UniqueName: "/Lotus/Forma"})
+	if err != nil {
+		t.Fatalf("Claim: %v", err)
+	}
+	if claim.ClaimerID != "alice" || claim.UniqueName != "/Lotus/Forma" {
+		t.Errorf("unexpected claim %+v", claim)
+	}
+	if want := now.AddDate(0, 0, models.DefaultGiftClaimDays); !claim.ExpiresAt.Equal(want) {
+		t.Errorf("expected default expiry %v, got %v", want, claim.ExpiresAt)
+	}
+
+	renewed, err := service.Claim(ctx, "alice", "owner", models.ClaimGiftRequest{UniqueName: "/Lotus/Forma", Days: 30})
+	if err != nil {
+		t.Fatalf("renew Claim: %v", err)
+	}
+	if renewed.ID != claim.ID || !renewed.ExpiresAt.Equal(now.AddDate(0, 0, 30)) {
+		t.Errorf("expected the claim to be renewed in place, got %+v", renewed)
+	}
+
+	if _, err := service.Claim(ctx, "bob", "owner", models.ClaimGiftRequest{UniqueName: "/Lotus/Forma"}); !errors.Is(err, ErrItemAlreadyClaimed) {
+		t.Errorf("expected ErrItemAlreadyClaimed for a second claimer, got %v", err)
+	}
+	if _, err := service.Claim(ctx, "bob", "owner", models.ClaimGiftRequest{UniqueName: "/Lotus/Orokin"}); err != nil {
+		t.Errorf("expected another item to be claimable, got %v", err)
+	}
+}
+
+func TestGiftClaimService_ClaimErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		shared    bool
+		claimerID string
+		req       models.ClaimGiftRequest
+		wantErr   error
+	}{
+		{"private wishlist", false, "alice", models.ClaimGiftRequest{UniqueName: "/Lotus/Forma"}, ErrWishlistNotShared},
+		{"own item", true, "owner", models.ClaimGiftRequest{UniqueName: "/Lotus/Forma"}, ErrCannotClaimOwnItem},
+		{"item not on wishlist", true, "alice", models.ClaimGiftRequest{UniqueName: "/Lotus/Nitain"}, ErrItemNotInWishlist},
+		{"invalid uniqueName", true, "alice", models.ClaimGiftRequest{UniqueName: "/Lotus/../Forma"}, models.ErrInvalidUniqueName},
+		{"negative days", true, "alice", models.ClaimGiftRequest{UniqueName: "/Lotus/Forma", Days: -1}, ErrInvalidClaimDays},
+		{"too many days", true, "alice", models.ClaimGiftRequest{UniqueName: "/Lotus/Forma", Days: models.MaxGiftClaimDays + 1}, ErrInvalidClaimDays},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, _ := newGiftClaimFixture(t, tt.shared)
+			if _, err := service.Claim(context.Background(), tt.claimerID, "owner", tt.req); !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestGiftClaimService_GetSharedWishlist(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newGiftClaimFixture(t, true)
+
+	if _, err := service.Claim(ctx, "alice", "owner", models.ClaimGiftRequest{UniqueName: "/Lotus/Forma", Anonymous: true}); err != nil {
+		t.Fatalf("Claim: %v", err)
+	}
+	if _, err := service.Claim(ctx, "bob", "owner", models.ClaimGiftRequest{UniqueName: "/Lotus/Orokin"}); err != nil {
+		t.Fatalf("Claim: %v", err)
+	}
+
+	tests := []struct {
+		viewerID       string
+		wantFormaClaim bool
+	}{
+		{"alice", true},
+		{"bob", false},
+		{"owner", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.viewerID, func(t *testing.T) {
+			shared, err := service.GetSharedWishlist(ctx, tt.viewerID, "owner")
+			if err != nil {
+				t.Fatalf("GetSharedWishlist: %v", err)
+			}
+			if len(shared.Items) != 2 {
+				t.Fatalf("expected 2 items, got %d", len(shared.Items))
+			}
+			forma, orokin := shared.Items[0], shared.Items[1]
+			if !forma.Claimed || !orokin.Claimed {
+				t.Errorf("expected both items to be claimed, got %v and %v", forma.Claimed, orokin.Claimed)
+			}
+			if (forma.Claim != nil) != tt.wantFormaClaim {
+				t.Errorf("expected anonymous claim visible=%v, got %+v", tt.wantFormaClaim, forma.Claim)
+			}
+			if orokin.Claim == nil || orokin.Claim.ClaimerID != "bob" {
+				t.Errorf("expected bob's claim to be visible, got %+v", orokin.Claim)
+			}
+		})
+	}
+
+	private, _ := newGiftClaimFixture(t, false)
+	if _, err := private.GetSharedWishlist(ctx, "alice", "owner"); !errors.Is(err, ErrWishlistNotShared) {
+		t.Errorf("expected ErrWishlistNotShared, got %v", err)
+	}
+	if _, err := service.GetSharedWishlist(ctx, "alice", "nobody"); !errors.Is(err, ErrWishlistNotShared) {
+		t.Errorf("expected ErrWishlistNotShared for a user without settings, got %v", err)
+	}
+}
+
+func TestGiftClaimService_RevokeClaim(t *testing.T) {
+	ctx := context.Background()
+	service, claims := newGiftClaimFixture(t, true)
+
+	if _, err := service.Claim(ctx, "alice", "owner", models.ClaimGiftRequest{UniqueName: "/Lotus/Forma"}); err != nil {
+		t.Fatalf("Claim: %v", err)
+	}
+	if _, err := service.Claim(ctx, "alice", "owner", models.ClaimGiftRequest{UniqueName: "/Lotus/Orokin"}); err != nil {
+		t.Fatalf("Claim: %v", err)
+	}
+
+	if err := service.RevokeClaim(ctx, "bob", "owner", "/Lotus/Forma"); !errors.Is(err, ErrClaimNotFound) {
+		t.Errorf("expected ErrClaimNotFound revoking someone else's claim, got %v", err)
+	}
+	if err := service.RevokeClaim(ctx, "alice", "owner", "/Lotus/Forma"); err != nil {
+		t.Errorf("expected the claimer to revoke their claim, got %v", err)
+	}
+	if err := service.RevokeClaim(ctx, "owner", "owner", "/Lotus/Orokin"); err != nil {
+		t.Errorf("expected the owner to revoke any claim, got %v", err)
+	}
+	if err := service.RevokeClaim(ctx, "owner", "owner", "/Lotus/Orokin"); !errors.Is(err, ErrClaimNotFound) {
+		t.Errorf("expected ErrClaimNotFound for a revoked claim, got %v", err)
+	}
+
+	remaining, err := claims.ListByOwner(ctx, "owner")
+	if err != nil || len(remaining) != 0 {
+		t.Errorf("expected no claims left, got %v (err %v)", remaining, err)
+	}
+}
+
+func TestGiftClaimService_ListClaims(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newGiftClaimFixture(t, true)
+
+	if _, err := service.Claim(ctx, "alice", "owner", models.ClaimGiftRequest{UniqueName: "/Lotus/Forma", Days: 30}); err != nil {
+		t.Fatalf("Claim: %v", err)
+	}
+	if _, err := service.Claim(ctx, "alice", "owner", models.ClaimGiftRequest{UniqueName: "/Lotus/Orokin", Days: 2}); err != nil {
+		t.Fatalf("Claim: %v", err)
+	}
+
+	claims, err := service.ListClaims(ctx, "alice")
+	if err != nil {
+		t.Fatalf("ListClaims: %v", err)
+	}
+	if len(claims) != 2 || claims[0].UniqueName != "/Lotus/Orokin" {
+		t.Errorf("expected 2 claims soonest-expiring first, got %+v", claims)
+	}
+	if other, _ := service.ListClaims(ctx, "bob"); len(other) != 0 {
+		t.Errorf("expected no claims for bob, got %+v", other)
+	}
+}
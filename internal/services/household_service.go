@@ -0,0 +1,329 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrCannotManageSelf     = errors.New("cannot link an account to itself")
+	ErrInvalidThreshold     = errors.New("quantity threshold must not be negative")
+	ErrAlreadyLinked        = errors.New("account is already linked to a manager")
+	ErrLinkNotFound         = errors.New("household link not found")
+	ErrLinkActive           = errors.New("household link is active and can only be removed by the manager")
+	ErrChangeNotFound       = errors.New("pending change not found")
+	ErrChangeAlreadyDecided = errors.New("pending change already decided")
+)
+
+// HouseholdService manages manager/member links and the manager's decisions
+// on pending wishlist changes. Approved changes are applied through wishlist,
+// which must be the undecorated wishlist service so they are not held back
+// for approval a second time.
+type HouseholdService struct {
+	householdRepo repository.HouseholdRepositoryInterface
+	wishlist      WishlistServiceInterface
+}
+
+func NewHouseholdService(householdRepo repository.HouseholdRepositoryInterface, wishlist WishlistServiceInterface) *HouseholdService {
+	return &HouseholdService{
+		householdRepo: householdRepo,
+		wishlist:      wishlist,
+	}
+}
+
+func (s *HouseholdService) GetHousehold(ctx context.Context, userID string) (*models.Household, error) {
+	logger.Debug(ctx, "service: HouseholdService.GetHousehold called", "userID", userID)
+
+	manager, err := s.householdRepo.GetLinkByMember(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "service: HouseholdService.GetHousehold - error fetching manager link", "error", err)
+		return nil, err
+	}
+
+	members, err := s.householdRepo.ListLinksByManager(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "service: HouseholdService.GetHousehold - error fetching member links", "error", err)
+		return nil, err
+	}
+
+	logger.Debug(ctx, "service: HouseholdService.GetHousehold - completed", "hasManager", manager != nil, "memberCount", len(members))
+	return &models.Household{Manager: manager, Members: members}, nil
+}
+
+// RequestManager asks managerUserID to manage memberID's wishlist. The link
+// stays pending, and changes are not gated, until the manager accepts.
+func (s *HouseholdService) RequestManager(ctx context.Context, memberID string, req models.RequestManagerRequest) (*models.HouseholdLink, error) {
+	logger.Debug(ctx, "service: HouseholdService.RequestManager called", "memberID", memberID, "managerID", req.ManagerUserID)
+
+	if req.ManagerUserID == memberID {
+		logger.Warn(ctx, "service: HouseholdService.RequestManager - cannot manage self")
+		return nil, ErrCannotManageSelf
+	}
+	if req.QuantityThreshold < 0 {
+		logger.Warn(ctx, "service: HouseholdService.RequestManager - invalid threshold", "quantityThreshold", req.QuantityThreshold)
+		return nil, ErrInvalidThreshold
+	}
+
+	link := &models.HouseholdLink{
+		ManagerID:         req.ManagerUserID,
+		MemberID:          memberID,
+		QuantityThreshold: req.QuantityThreshold,
+		Status:            models.HouseholdLinkPending,
+	}
+	created, err := s.householdRepo.CreateLink(ctx, link)
+	if err != nil {
+		logger.Error(ctx, "service: HouseholdService.RequestManager - error creating link", "error", err)
+		return nil, err
+	}
+	if !created {
+		logger.Warn(ctx, "service: HouseholdService.RequestManager - member already linked")
+		return nil, ErrAlreadyLinked
+	}
+
+	logger.Info(ctx, "service: HouseholdService.RequestManager - link requested", "memberID", memberID, "managerID", req.ManagerUserID)
+	return link, nil
+}
+
+// LeaveManager withdraws memberID's link request. Once the manager has
+// accepted, only the manager can remove the link; otherwise the member could
+// leave to skip an approval.
+func (s *HouseholdService) LeaveManager(ctx context.Context, memberID string) error {
+	logger.Debug(ctx, "service: HouseholdService.LeaveManager called", "memberID", memberID)
+
+	link, err := s.householdRepo.GetLinkByMember(ctx, memberID)
+	if err != nil {
+		logger.Error(ctx, "service: HouseholdService.LeaveManager - error fetching link", "error", err)
+		return err
+	}
+	if link == nil {
+		logger.Warn(ctx, "service: HouseholdService.LeaveManager - link not found")
+		return ErrLinkNotFound
+	}
+	if link.Status == models.HouseholdLinkActive {
+		logger.Warn(ctx, "service: HouseholdService.LeaveManager - link is active")
+		return ErrLinkActive
+	}
+
+	if err := s.householdRepo.DeleteLink(ctx, memberID); err != nil {
+		logger.Error(ctx, "service: HouseholdService.LeaveManager - error deleting link", "error", err)
+		return err
+	}
+	logger.Info(ctx, "service: HouseholdService.LeaveManager - link withdrawn", "memberID", memberID)
+	return nil
+}
+
+func (s *HouseholdService) AcceptMember(ctx context.Context, managerID, memberID string) (*models.HouseholdLink, error) {
+	logger.Debug(ctx, "service: HouseholdService.AcceptMember called", "managerID", managerID, "memberID", memberID)
+
+	link, err := s.managedLink(ctx, managerID, memberID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.householdRepo.UpdateLink(ctx, memberID, models.HouseholdLinkActive, link.QuantityThreshold); err != nil {
+		logger.Error(ctx, "service: HouseholdService.AcceptMember - error updating link", "error", err)
+		return nil, err
+	}
+	link.Status = models.HouseholdLinkActive
+
+	logger.Info(ctx, "service: HouseholdService.AcceptMember - member accepted", "memberID", memberID)
+	return link, nil
+}
+
+func (s *HouseholdService) UpdateMember(ctx context.Context, managerID, memberID string, req models.UpdateHouseholdMemberRequest) (*models.HouseholdLink, error) {
+	logger.Debug(ctx, "service: HouseholdService.UpdateMember called", "managerID", managerID, "memberID", memberID)
+
+	link, err := s.managedLink(ctx, managerID, memberID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.QuantityThreshold != nil {
+		if *req.QuantityThreshold < 0 {
+			logger.Warn(ctx, "service: HouseholdService.UpdateMember - invalid threshold", "quantityThreshold", *req.QuantityThreshold)
+			return nil, ErrInvalidThreshold
+		}
+		link.QuantityThreshold = *req.QuantityThreshold
+	}
+
+	if err := s.householdRepo.UpdateLink(ctx, memberID, link.Status, link.QuantityThreshold); err != nil {
+		logger.Error(ctx, "service: HouseholdService.UpdateMember - error updating link", "error", err)
+		return nil, err
+	}
+
+	logger.Info(ctx, "service: HouseholdService.UpdateMember - member updated", "memberID", memberID, "quantityThreshold", link.QuantityThreshold)
+	return link, nil
+}
+
+// RemoveMember unlinks memberID and cancels any of their changes still
+// awaiting managerID's decision.
+func (s *HouseholdService) RemoveMember(ctx context.Context, managerID, memberID string) error {
+	logger.Debug(ctx, "service: HouseholdService.RemoveMember called", "managerID", managerID, "memberID", memberID)
+
+	if _, err := s.managedLink(ctx, managerID, memberID); err != nil {
+		return err
+	}
+
+	if err := s.householdRepo.DeleteLink(ctx, memberID); err != nil {
+		logger.Error(ctx, "service: HouseholdService.RemoveMember - error deleting link", "error", err)
+		return err
+	}
+
+	pending, err := s.householdRepo.ListChangesByMember(ctx, memberID, models.ChangeStatusPending)
+	if err != nil {
+		logger.Error(ctx, "service: HouseholdService.RemoveMember - error listing pending changes", "error", err)
+		return err
+	}
+	cancelled := 0
+	for _, change := range pending {
+		if change.ManagerID != managerID {
+			continue
+		}
+		if _, err := s.householdRepo.DecideChange(ctx, change.ID, models.ChangeStatusCancelled); err != nil {
+			logger.Error(ctx, "service: HouseholdService.RemoveMember - error cancelling pending change", "changeID", change.ID.Hex(), "error", err)
+			return err
+		}
+		cancelled++
+	}
+
+	logger.Info(ctx, "service: HouseholdService.RemoveMember - member removed", "memberID", memberID, "cancelledChanges", cancelled)
+	return nil
+}
+
+// ListApprovals returns the changes awaiting userID's decision and every
+// change userID has requested, so members can see what was decided.
+func (s *HouseholdService) ListApprovals(ctx context.Context, userID string) (*models.HouseholdApprovals, error) {
+	logger.Debug(ctx, "service: HouseholdService.ListApprovals called", "userID", userID)
+
+	toReview, err := s.householdRepo.ListChangesByManager(ctx, userID, models.ChangeStatusPending)
+	if err != nil {
+		logger.Error(ctx, "service: HouseholdService.ListApprovals - error listing changes to review", "error", err)
+		return nil, err
+	}
+
+	requested, err := s.householdRepo.ListChangesByMember(ctx, userID, "")
+	if err != nil {
+		logger.Error(ctx, "service: HouseholdService.ListApprovals - error listing requested changes", "error", err)
+		return nil, err
+	}
+
+	logger.Debug(ctx, "service: HouseholdService.ListApprovals - completed", "toReview", len(toReview), "requested", len(requested))
+	return &models.HouseholdApprovals{ToReview: toReview, Requested: requested}, nil
+}
+
+// Approve applies the change to the member's wishlist and marks it approved.
+// If applying fails the change stays pending so the manager can retry or
+// reject it.
+func (s *HouseholdService) Approve(ctx context.Context, managerID, changeID string) (*models.PendingChange, error) {
+	logger.Debug(ctx, "service: HouseholdService.Approve called", "managerID", managerID, "changeID", changeID)
+
+	change, err := s.pendingChange(ctx, managerID, changeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.apply(ctx, change); err != nil {
+		logger.Warn(ctx, "service: HouseholdService.Approve - error applying change", "changeID", changeID, "error", err)
+		return nil, err
+	}
+
+	return s.decide(ctx, change, models.ChangeStatusApproved)
+}
+
+func (s *HouseholdService) Reject(ctx context.Context, managerID, changeID string) (*models.PendingChange, error) {
+	logger.Debug(ctx, "service: HouseholdService.Reject called", "managerID", managerID, "changeID", changeID)
+
+	change, err := s.pendingChange(ctx, managerID, changeID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.decide(ctx, change, models.ChangeStatusRejected)
+}
+
+// managedLink returns memberID's link if it belongs to managerID.
+func (s *HouseholdService) managedLink(ctx context.Context, managerID, memberID string) (*models.HouseholdLink, error) {
+	link, err := s.householdRepo.GetLinkByMember(ctx, memberID)
+	if err != nil {
+		logger.Error(ctx, "service: HouseholdService - error fetching link", "memberID", memberID, "error", err)
+		return nil, err
+	}
+	if link == nil || link.ManagerID != managerID {
+		logger.Warn(ctx, "service: HouseholdService - link not found", "memberID", memberID)
+		return nil, ErrLinkNotFound
+	}
+	return link, nil
+}
+
+// pendingChange returns the change if it is addressed to managerID and still
+// pending. Changes addressed to other managers are reported as not found.
+func (s *HouseholdService) pendingChange(ctx context.Context, managerID, changeID string) (*models.PendingChange, error) {
+	id, err := primitive.ObjectIDFromHex(changeID)
+	if err != nil {
+		logger.Warn(ctx, "service: HouseholdService - invalid change ID", "changeID", changeID)
+		return nil, ErrChangeNotFound
+	}
+
+	change, err := s.householdRepo.GetPendingChange(ctx, id)
+	if err != nil {
+		logger.Error(ctx, "service: HouseholdService - error fetching pending change", "error", err)
+		return nil, err
+	}
+	if change == nil || change.ManagerID != managerID {
+		logger.Warn(ctx, "service: HouseholdService - pending change not found", "changeID", changeID)
+		return nil, ErrChangeNotFound
+	}
+	if change.Status != models.ChangeStatusPending {
+		logger.Warn(ctx, "service: HouseholdService - pending change already decided", "changeID", changeID, "status", change.Status)
+		return nil, ErrChangeAlreadyDecided
+	}
+	return change, nil
+}
+
+// apply makes the change on the member's wishlist. An addition for an item
+// the member has since added some other way becomes a quantity update, since
+// the manager approved the quantity either way. The manager's approval also
+// stands in for confirming items the member has marked as owned.
+func (s *HouseholdService) apply(ctx context.Context, change *models.PendingChange) error {
+	switch change.Type {
+	case models.ChangeTypeAddItem:
+		err := s.wishlist.AddItem(ctx, change.MemberID, models.AddItemRequest{UniqueName: change.UniqueName, Quantity: change.Quantity, AllowOwned: true})
+		if errors.Is(err, ErrItemAlreadyInWishlist) {
+			return s.wishlist.UpdateQuantity(ctx, change.MemberID, change.UniqueName, change.Quantity)
+		}
+		return err
+	case models.ChangeTypeUpdateQuantity:
+		return s.wishlist.UpdateQuantity(ctx, change.MemberID, change.UniqueName, change.Quantity)
+	default:
+		return errors.New("unknown pending change type: " + change.Type)
+	}
+}
+
+func (s *HouseholdService) decide(ctx context.Context, change *models.PendingChange, status string) (*models.PendingChange, error) {
+	decided, err := s.householdRepo.DecideChange(ctx, change.ID, status)
+	if err != nil {
+		logger.Error(ctx, "service: HouseholdService - error deciding change", "changeID", change.ID.Hex(), "error", err)
+		return nil, err
+	}
+	if !decided {
+		logger.Warn(ctx, "service: HouseholdService - change decided concurrently", "changeID", change.ID.Hex())
+		return nil, ErrChangeAlreadyDecided
+	}
+
+	updated, err := s.householdRepo.GetPendingChange(ctx, change.ID)
+	if err != nil {
+		logger.Error(ctx, "service: HouseholdService - error fetching decided change", "error", err)
+		return nil, err
+	}
+	if updated == nil {
+		return nil, ErrChangeNotFound
+	}
+
+	logger.Info(ctx, "service: HouseholdService - change decided", "changeID", change.ID.Hex(), "status", status)
+	return updated, nil
+}
@@ -0,0 +1,273 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestHouseholdService_RequestManager(t *testing.T) {
+	tests := []struct {
+		name          string
+		existing      string
+		req           models.RequestManagerRequest
+		expectedError error
+	}{
+		{name: "creates pending link", req: models.RequestManagerRequest{ManagerUserID: "manager", QuantityThreshold: 3}},
+		{name: "cannot manage self", req: models.RequestManagerRequest{ManagerUserID: "member"}, expectedError: ErrCannotManageSelf},
+		{name: "negative threshold", req: models.RequestManagerRequest{ManagerUserID: "manager", QuantityThreshold: -1}, expectedError: ErrInvalidThreshold},
+		{name: "already linked", existing: models.HouseholdLinkPending, req: models.RequestManagerRequest{ManagerUserID: "other"}, expectedError: ErrAlreadyLinked},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newHouseholdFixture(t, 0, tt.existing)
+
+			link, err := f.service.RequestManager(context.Background(), "member", tt.req)
+
+			if tt.expectedError != nil {
+				if !errors.Is(err, tt.expectedError) {
+					t.Errorf("expected error %v, got %v", tt.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if link.Status != models.HouseholdLinkPending || link.ManagerID != "manager" || link.QuantityThreshold != 3 {
+				t.Errorf("unexpected link: %+v", link)
+			}
+		})
+	}
+}
+
+func TestHouseholdService_LinkLifecycle(t *testing.T) {
+	ctx := context.Background()
+	f := newHouseholdFixture(t, 0, "")
+
+	if _, err := f.service.RequestManager(ctx, "member", models.RequestManagerRequest{ManagerUserID: "manager", QuantityThreshold: 2}); err != nil {
+		t.Fatalf("RequestManager: %v", err)
+	}
+
+	if _, err := f.service.AcceptMember(ctx, "someone-else", "member"); !errors.Is(err, ErrLinkNotFound) {
+		t.Errorf("expected other managers to get ErrLinkNotFound, got %v", err)
+	}
+
+	link, err := f.service.AcceptMember(ctx, "manager", "member")
+	if err != nil {
+		t.Fatalf("AcceptMember: %v", err)
+	}
+	if link.Status != models.HouseholdLinkActive {
+		t.Errorf("expected active link, got %s", link.Status)
+	}
+
+	if err := f.service.LeaveManager(ctx, "member"); !errors.Is(err, ErrLinkActive) {
+		t.Errorf("expected member to be unable to leave an active link, got %v", err)
+	}
+
+	if _, err := f.service.UpdateMember(ctx, "manager", "member", models.UpdateHouseholdMemberRequest{QuantityThreshold: intPtr(-1)}); !errors.Is(err, ErrInvalidThreshold) {
+		t.Errorf("expected ErrInvalidThreshold, got %v", err)
+	}
+	link, err = f.service.UpdateMember(ctx, "manager", "member", models.UpdateHouseholdMemberRequest{QuantityThreshold: intPtr(7)})
+	if err != nil {
+		t.Fatalf("UpdateMember: %v", err)
+	}
+	if link.QuantityThreshold != 7 || link.Status != models.HouseholdLinkActive {
+		t.Errorf("unexpected link after update: %+v", link)
+	}
+
+	household, err := f.service.GetHousehold(ctx, "manager")
+	if err != nil {
+		t.Fatalf("GetHousehold: %v", err)
+	}
+	if household.Manager != nil || len(household.Members) != 1 || household.Members[0].QuantityThreshold != 7 {
+		t.Errorf("unexpected manager household: %+v", household)
+	}
+
+	household, _ = f.service.GetHousehold(ctx, "member")
+	if household.Manager == nil || household.Manager.ManagerID != "manager" || len(household.Members) != 0 {
+		t.Errorf("unexpected member household: %+v", household)
+	}
+}
+
+func TestHouseholdService_LeaveManager(t *testing.T) {
+	ctx := context.Background()
+
+	f := newHouseholdFixture(t, 0, "")
+	if err := f.service.LeaveManager(ctx, "member"); !errors.Is(err, ErrLinkNotFound) {
+		t.Errorf("expected ErrLinkNotFound, got %v", err)
+	}
+
+	f = newHouseholdFixture(t, 0, models.HouseholdLinkPending)
+	if err := f.service.LeaveManager(ctx, "member"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if link, _ := f.household.GetLinkByMember(ctx, "member"); link != nil {
+		t.Errorf("expected pending link to be withdrawn, got %+v", link)
+	}
+}
+
+func TestHouseholdService_RemoveMember_CancelsPendingChanges(t *testing.T) {
+	ctx := context.Background()
+	f := newHouseholdFixture(t, 1, models.HouseholdLinkActive)
+
+	err := f.approval.AddItem(ctx, "member", models.AddItemRequest{UniqueName: "/Lotus/Forma", Quantity: 5})
+	var approvalErr *ApprovalRequiredError
+	if !errors.As(err, &approvalErr) {
+		t.Fatalf("expected change to be held, got %v", err)
+	}
+
+	if err := f.service.RemoveMember(ctx, "someone-else", "member"); !errors.Is(err, ErrLinkNotFound) {
+		t.Errorf("expected ErrLinkNotFound for another manager, got %v", err)
+	}
+	if err := f.service.RemoveMember(ctx, "manager", "member"); err != nil {
+		t.Fatalf("RemoveMember: %v", err)
+	}
+
+	if link, _ := f.household.GetLinkByMember(ctx, "member"); link != nil {
+		t.Errorf("expected link to be removed, got %+v", link)
+	}
+	change, _ := f.household.GetPendingChange(ctx, approvalErr.Change.ID)
+	if change.Status != models.ChangeStatusCancelled {
+		t.Errorf("expected pending change to be cancelled, got %s", change.Status)
+	}
+
+	if err := f.approval.AddItem(ctx, "member", models.AddItemRequest{UniqueName: "/Lotus/Forma", Quantity: 5}); err != nil {
+		t.Errorf("expected unlinked member's additions to apply directly, got %v", err)
+	}
+}
+
+func TestHouseholdService_Approve(t *testing.T) {
+	ctx := context.Background()
+	f := newHouseholdFixture(t, 1, models.HouseholdLinkActive)
+
+	err := f.approval.AddItem(ctx, "member", models.AddItemRequest{UniqueName: "/Lotus/Forma", Quantity: 5})
+	var approvalErr *ApprovalRequiredError
+	if !errors.As(err, &approvalErr) {
+		t.Fatalf("expected change to be held, got %v", err)
+	}
+	changeID := approvalErr.Change.ID.Hex()
+
+	approvals, err := f.service.ListApprovals(ctx, "manager")
+	if err != nil {
+		t.Fatalf("ListApprovals: %v", err)
+	}
+	if len(approvals.ToReview) != 1 || len(approvals.Requested) != 0 {
+		t.Errorf("unexpected manager approvals: %+v", approvals)
+	}
+
+	if _, err := f.service.Approve(ctx, "someone-else", changeID); !errors.Is(err, ErrChangeNotFound) {
+		t.Errorf("expected other managers to get ErrChangeNotFound, got %v", err)
+	}
+	if _, err := f.service.Approve(ctx, "manager", "not-an-id"); !errors.Is(err, ErrChangeNotFound) {
+		t.Errorf("expected invalid IDs to get ErrChangeNotFound, got %v", err)
+	}
+
+	change, err := f.service.Approve(ctx, "manager", changeID)
+	if err != nil {
+		t.Fatalf("Approve: %v", err)
+	}
+	if change.Status != models.ChangeStatusApproved || change.DecidedAt == nil {
+		t.Errorf("unexpected approved change: %+v", change)
+	}
+	if got := f.quantity(t, "/Lotus/Forma"); got != 5 {
+		t.Errorf("expected approved quantity 5 in wishlist, got %d", got)
+	}
+
+	if _, err := f.service.Approve(ctx, "manager", changeID); !errors.Is(err, ErrChangeAlreadyDecided) {
+		t.Errorf("expected ErrChangeAlreadyDecided, got %v", err)
+	}
+
+	approvals, _ = f.service.ListApprovals(ctx, "member")
+	if len(approvals.Requested) != 1 || approvals.Requested[0].Status != models.ChangeStatusApproved {
+		t.Errorf("expected member to see the approved change, got %+v", approvals.Requested)
+	}
+}
+
+func TestHouseholdService_Approve_AddedMeanwhile(t *testing.T) {
+	ctx := context.Background()
+	f := newHouseholdFixture(t, 1, models.HouseholdLinkActive)
+
+	err := f.approval.AddItem(ctx, "member", models.AddItemRequest{UniqueName: "/Lotus/Forma", Quantity: 5})
+	var approvalErr *ApprovalRequiredError
+	if !errors.As(err, &approvalErr) {
+		t.Fatalf("expected change to be held, got %v", err)
+	}
+	if err := f.approval.AddItem(ctx, "member", models.AddItemRequest{UniqueName: "/Lotus/Forma", Quantity: 1}); err != nil {
+		t.Fatalf("AddItem: %v", err)
+	}
+
+	if _, err := f.service.Approve(ctx, "manager", approvalErr.Change.ID.Hex()); err != nil {
+		t.Fatalf("Approve: %v", err)
+	}
+	if got := f.quantity(t, "/Lotus/Forma"); got != 5 {
+		t.Errorf("expected approved quantity to replace the existing one, got %d", got)
+	}
+}
+
+func TestHouseholdService_Approve_ApplyFailureKeepsChangePending(t *testing.T) {
+	ctx := context.Background()
+	f := newHouseholdFixture(t, 1, models.HouseholdLinkActive)
+	f.wishlists.Create(ctx, &models.Wishlist{
+		UserID: "member",
+		Items:  []models.WishlistItem{{UniqueName: "/Lotus/Forma", Quantity: 1}},
+	})
+
+	err := f.approval.UpdateQuantity(ctx, "member", "/Lotus/Forma", 9)
+	var approvalErr *ApprovalRequiredError
+	if !errors.As(err, &approvalErr) {
+		t.Fatalf("expected change to be held, got %v", err)
+	}
+	if err := f.approval.RemoveItem(ctx, "member", "/Lotus/Forma"); err != nil {
+		t.Fatalf("RemoveItem: %v", err)
+	}
+
+	if _, err := f.service.Approve(ctx, "manager", approvalErr.Change.ID.Hex()); !errors.Is(err, ErrItemNotInWishlist) {
+		t.Fatalf("expected ErrItemNotInWishlist, got %v", err)
+	}
+	change, _ := f.household.GetPendingChange(ctx, approvalErr.Change.ID)
+	if change.Status != models.ChangeStatusPending {
+		t.Errorf("expected change to stay pending, got %s", change.Status)
+	}
+
+	rejected, err := f.service.Reject(ctx, "manager", approvalErr.Change.ID.Hex())
+	if err != nil {
+		t.Fatalf("Reject: %v", err)
+	}
+	if rejected.Status != models.ChangeStatusRejected {
+		t.Errorf("expected rejected change, got %s", rejected.Status)
+	}
+}
+
+func TestHouseholdService_Reject(t *testing.T) {
+	ctx := context.Background()
+	f := newHouseholdFixture(t, 1, models.HouseholdLinkActive)
+
+	if _, err := f.service.Reject(ctx, "manager", primitive.NewObjectID().Hex()); !errors.Is(err, ErrChangeNotFound) {
+		t.Errorf("expected ErrChangeNotFound, got %v", err)
+	}
+
+	err := f.approval.AddItem(ctx, "member", models.AddItemRequest{UniqueName: "/Lotus/Forma", Quantity: 5})
+	var approvalErr *ApprovalRequiredError
+	if !errors.As(err, &approvalErr) {
+		t.Fatalf("expected change to be held, got %v", err)
+	}
+
+	change, err := f.service.Reject(ctx, "manager", approvalErr.Change.ID.Hex())
+	if err != nil {
+		t.Fatalf("Reject: %v", err)
+	}
+	if change.Status != models.ChangeStatusRejected {
+		t.Errorf("expected rejected change, got %s", change.Status)
+	}
+	if got := f.quantity(t, "/Lotus/Forma"); got != 0 {
+		t.Errorf("expected rejected item not to be in wishlist, got quantity %d", got)
+	}
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+)
+
+// MaxImportAliasExpansionLength bounds the text an alias expands to.
+const MaxImportAliasExpansionLength = 100
+
+var (
+	ErrInvalidImportAliasWord      = errors.New("word must be a single word of letters and digits")
+	ErrInvalidImportAliasExpansion = fmt.Errorf("expansion must be 1 to %d characters of letters and digits", MaxImportAliasExpansionLength)
+	ErrImportAliasNotFound         = errors.New("import alias not found")
+)
+
+// ImportAliasService manages the alias dictionary the wishlist import
+// expands shorthand with. Moderators add aliases and override the built-in
+// ones; deleting a stored alias restores the built-in one, if any.
+type ImportAliasService struct {
+	aliasRepo repository.ImportAliasRepositoryInterface
+	now       func() time.Time
+}
+
+func NewImportAliasService(aliasRepo repository.ImportAliasRepositoryInterface) *ImportAliasService {
+	return &ImportAliasService{aliasRepo: aliasRepo, now: time.Now}
+}
+
+// List returns every alias in effect sorted by word, marking the built-in
+// ones that no stored alias overrides.
+func (s *ImportAliasService) List(ctx context.Context) ([]models.ImportAlias, error) {
+	logger.Debug(ctx, "service: ImportAliasService.List called")
+
+	stored, err := s.aliasRepo.List(ctx)
+	if err != nil {
+		logger.Error(ctx, "service: ImportAliasService.List - error fetching aliases", "error", err)
+		return nil, err
+	}
+
+	aliases := stored
+	overridden := make(map[string]bool, len(stored))
+	for _, alias := range stored {
+		overridden[alias.Word] = true
+	}
+	for word, expansion := range importAliases {
+		if !overridden[word] {
+			aliases = append(aliases, models.ImportAlias{Word: word, Expansion: expansion, BuiltIn: true})
+		}
+	}
+	sort.Slice(aliases, func(i, j int) bool { return aliases[i].Word < aliases[j].Word })
+	return aliases, nil
+}
+
+// Set stores the alias of word. Both are normalized as import lines are, so
+// the alias matches however a player capitalizes or punctuates it.
+func (s *ImportAliasService) Set(ctx context.Context, word, expansion string) (*models.ImportAlias, error) {
+	logger.Debug(ctx, "service: ImportAliasService.Set called", "word", word)
+
+	word = normalizeImportName(word)
+	if word == "" || strings.Contains(word, " ") {
+		logger.Warn(ctx, "service: ImportAliasService.Set - invalid word", "word", word)
+		return nil, ErrInvalidImportAliasWord
+	}
+	expansion = normalizeImportName(expansion)
+	if expansion == "" || len(expansion) > MaxImportAliasExpansionLength {
+		logger.Warn(ctx, "service: ImportAliasService.Set - invalid expansion", "word", word)
+		return nil, ErrInvalidImportAliasExpansion
+	}
+
+	alias := models.ImportAlias{Word: word, Expansion: expansion, UpdatedAt: s.now()}
+	if err := s.aliasRepo.Set(ctx, alias); err != nil {
+		logger.Error(ctx, "service: ImportAliasService.Set - error storing alias", "error", err)
+		return nil, err
+	}
+
+	logger.Info(ctx, "service: ImportAliasService.Set - alias stored", "word", word)
+	return &alias, nil
+}
+
+// Delete removes the stored alias of word. Built-in aliases cannot be
+// deleted, only overridden.
+func (s *ImportAliasService) Delete(ctx context.Context, word string) error {
+	logger.Debug(ctx, "service: ImportAliasService.Delete called", "word", word)
+
+	deleted, err := s.aliasRepo.Delete(ctx, normalizeImportName(word))
+	if err != nil {
+		logger.Error(ctx, "service: ImportAliasService.Delete - error deleting alias", "error", err)
+		return err
+	}
+	if !deleted {
+		logger.Warn(ctx, "service: ImportAliasService.Delete - alias not found", "word", word)
+		return ErrImportAliasNotFound
+	}
+
+	logger.Info(ctx, "service: ImportAliasService.Delete - alias deleted", "word", word)
+	return nil
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/repository/memory"
+)
+
+func TestImportAliasService_List(t *testing.T) {
+	ctx := context.Background()
+	service := NewImportAliasService(memory.NewImportAliasRepository())
+
+	if _, err := service.Set(ctx, "BP", "Blueprints"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if _, err := service.Set(ctx, "chas", "chassis"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	aliases, err := service.List(ctx)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(aliases) != len(importAliases)+1 {
+		t.Fatalf("expected the built-in aliases and one more, got %+v", aliases)
+	}
+	for i, alias := range aliases {
+		if i > 0 && aliases[i-1].Word >= alias.Word {
+			t.Errorf("expected aliases sorted by word, got %q before %q", aliases[i-1].Word, alias.Word)
+		}
+		switch alias.Word {
+		case "bp":
+			if alias.Expansion != "blueprints" || alias.BuiltIn {
+				t.Errorf("expected the stored override, got %+v", alias)
+			}
+		case "chas":
+			if alias.Expansion != "chassis" || alias.BuiltIn {
+				t.Errorf("unexpected alias %+v", alias)
+			}
+		default:
+			if !alias.BuiltIn || alias.Expansion != importAliases[alias.Word] {
+				t.Errorf("expected a built-in alias, got %+v", alias)
+			}
+		}
+	}
+}
+
+func TestImportAliasService_SetErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		word      string
+		expansion string
+		expected  error
+	}{
+		{name: "empty word", word: "--", expansion: "chassis", expected: ErrInvalidImportAliasWord},
+		{name: "several words", word: "kuva b", expansion: "kuva bramma", expected: ErrInvalidImportAliasWord},
+		{name: "empty expansion", word: "chas", expansion: " ", expected: ErrInvalidImportAliasExpansion},
+		{name: "long expansion", word: "chas", expansion: strings.Repeat("a", MaxImportAliasExpansionLength+1), expected: ErrInvalidImportAliasExpansion},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewImportAliasService(memory.NewImportAliasRepository())
+
+			if _, err := service.Set(context.Background(), tt.word, tt.expansion); !errors.Is(err, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestImportAliasService_Delete(t *testing.T) {
+	ctx := context.Background()
+	service := NewImportAliasService(memory.NewImportAliasRepository())
+
+	if _, err := service.Set(ctx, "chas", "chassis"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := service.Delete(ctx, "Chas"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := service.Delete(ctx, "chas"); !errors.Is(err, ErrImportAliasNotFound) {
+		t.Errorf("expected ErrImportAliasNotFound, got %v", err)
+	}
+	// Built-in aliases are not stored, so they cannot be deleted.
+	if err := service.Delete(ctx, "bp"); !errors.Is(err, ErrImportAliasNotFound) {
+		t.Errorf("expected ErrImportAliasNotFound for a built-in alias, got %v", err)
+	}
+}
@@ -0,0 +1,366 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/ids"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/notify"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// integrationBatchSize bounds the items in one delivery; larger syncs
+	// are split into pages.
+	integrationBatchSize = 200
+	// maxIntegrationNameLength bounds integration names.
+	maxIntegrationNameLength = 100
+)
+
+var (
+	ErrIntegrationNotFound = errors.New("integration not found")
+	ErrTooManyIntegrations = fmt.Errorf("at most %d integrations", models.MaxIntegrations)
+	ErrInvalidIntegration  = errors.New("invalid integration")
+)
+
+// IntegrationService manages the integrations, such as market bots and wiki
+// updaters, that are sent the item changes each data sync finds. Changes are
+// queued per event in batches, one delivery per integration subscribed to
+// it, and sent with the same retries and backoff as notifications.
+type IntegrationService struct {
+	repo         repository.IntegrationRepositoryInterface
+	allowPrivate bool
+	send         func(ctx context.Context, integration *models.Integration, delivery *models.IntegrationDelivery) (int, error)
+	ids          ids.Generator
+	now          func() time.Time
+}
+
+// NewIntegrationService returns the integration service. allowPrivate
+// permits plain http and private network URLs, for local development only.
+func NewIntegrationService(repo repository.IntegrationRepositoryInterface, allowPrivate bool) *IntegrationService {
+	client := notify.NewHTTPClient(notificationSendTimeout, allowPrivate)
+	return &IntegrationService{
+		repo:         repo,
+		allowPrivate: allowPrivate,
+		send: func(ctx context.Context, integration *models.Integration, delivery *models.IntegrationDelivery) (int, error) {
+			return notify.SendWebhook(ctx, client, integration.URL, integration.Secret, delivery.Event, delivery.WebhookID(), []byte(delivery.Payload))
+		},
+		ids: ids.Default,
+		now: time.Now,
+	}
+}
+
+// SetIDGenerator sets the generator of integration secrets and delivery
+// IDs.
+func (s *IntegrationService) SetIDGenerator(gen ids.Generator) {
+	s.ids = gen
+}
+
+func (s *IntegrationService) ListIntegrations(ctx context.Context) ([]models.Integration, error) {
+	logger.Debug(ctx, "service: IntegrationService.ListIntegrations called")
+
+	integrations, err := s.repo.ListIntegrations(ctx)
+	if err != nil {
+		logger.Error(ctx, "service: IntegrationService.ListIntegrations - error fetching integrations", "error", err)
+		return nil, err
+	}
+	return integrations, nil
+}
+
+// CreateIntegration registers an integration with a new signing secret,
+// returned only here.
+func (s *IntegrationService) CreateIntegration(ctx context.Context, req models.IntegrationRequest) (*models.Integration, error) {
+	logger.Debug(ctx, "service: IntegrationService.CreateIntegration called", "name", req.Name)
+
+	req.Name = strings.TrimSpace(req.Name)
+	if err := s.validateIntegration(req); err != nil {
+		logger.Warn(ctx, "service: IntegrationService.CreateIntegration - invalid integration", "error", err)
+		return nil, err
+	}
+
+	existing, err := s.repo.ListIntegrations(ctx)
+	if err != nil {
+		logger.Error(ctx, "service: IntegrationService.CreateIntegration - error fetching integrations", "error", err)
+		return nil, err
+	}
+	if len(existing) >= models.MaxIntegrations {
+		logger.Warn(ctx, "service: IntegrationService.CreateIntegration - too many integrations", "count", len(existing))
+		return nil, ErrTooManyIntegrations
+	}
+
+	secret, err := s.ids.NewToken(webhookSecretBits)
+	if err != nil {
+		logger.Error(ctx, "service: IntegrationService.CreateIntegration - error generating secret", "error", err)
+		return nil, err
+	}
+	integration := &models.Integration{
+		Name:      req.Name,
+		URL:       req.URL,
+		Secret:    secret,
+		Events:    dedupeStrings(req.Events),
+		CreatedAt: s.now(),
+	}
+	if err := s.repo.CreateIntegration(ctx, integration); err != nil {
+		logger.Error(ctx, "service: IntegrationService.CreateIntegration - error storing integration", "error", err)
+		return nil, err
+	}
+
+	logger.Info(ctx, "service: IntegrationService.CreateIntegration - integration created", "id", integration.ID.Hex(), "name", integration.Name)
+	return integration, nil
+}
+
+func (s *IntegrationService) validateIntegration(req models.IntegrationRequest) error {
+	if req.Name == "" || len(req.Name) > maxIntegrationNameLength {
+		return fmt.Errorf("%w: name must be 1 to %d characters", ErrInvalidIntegration, maxIntegrationNameLength)
+	}
+	if err := validateWebhookURL(req.URL, s.allowPrivate); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidIntegration, err)
+	}
+	for _, event := range req.Events {
+		if !slices.Contains(models.IntegrationEvents, event) {
+			return fmt.Errorf("%w: unknown event %q", ErrInvalidIntegration, event)
+		}
+	}
+	return nil
+}
+
+func (s *IntegrationService) DeleteIntegration(ctx context.Context, id string) error {
+	logger.Debug(ctx, "service: IntegrationService.DeleteIntegration called", "id", id)
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		logger.Warn(ctx, "service: IntegrationService.DeleteIntegration - invalid integration ID", "id", id)
+		return ErrIntegrationNotFound
+	}
+	deleted, err := s.repo.DeleteIntegration(ctx, objectID)
+	if err != nil {
+		logger.Error(ctx, "service: IntegrationService.DeleteIntegration - error deleting integration", "error", err)
+		return err
+	}
+	if !deleted {
+		logger.Warn(ctx, "service: IntegrationService.DeleteIntegration - integration not found", "id", id)
+		return ErrIntegrationNotFound
+	}
+
+	logger.Info(ctx, "service: IntegrationService.DeleteIntegration - integration deleted", "id", id)
+	return nil
+}
+
+// ListDeliveries returns the integration's latest deliveries, newest first.
+// A limit of 0 or less selects the default.
+func (s *IntegrationService) ListDeliveries(ctx context.Context, id string, limit int) ([]models.IntegrationDelivery, error) {
+	logger.Debug(ctx, "service: IntegrationService.ListDeliveries called", "id", id, "limit", limit)
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		logger.Warn(ctx, "service: IntegrationService.ListDeliveries - invalid integration ID", "id", id)
+		return nil, ErrIntegrationNotFound
+	}
+	integration, err := s.repo.GetIntegration(ctx, objectID)
+	if err != nil {
+		logger.Error(ctx, "service: IntegrationService.ListDeliveries - error fetching integration", "error", err)
+		return nil, err
+	}
+	if integration == nil {
+		return nil, ErrIntegrationNotFound
+	}
+
+	if limit <= 0 {
+		limit = defaultDeliveryLimit
+	}
+	limit = min(limit, maxDeliveryLimit)
+	deliveries, err := s.repo.ListDeliveries(ctx, objectID, limit)
+	if err != nil {
+		logger.Error(ctx, "service: IntegrationService.ListDeliveries - error fetching deliveries", "error", err)
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// integrationPayload is the JSON body sent to integrations. Page counts
+// from 1 up to Pages when a sync changed more than integrationBatchSize
+// items for the event.
+type integrationPayload struct {
+	Event       string            `json:"event"`
+	DataVersion string            `json:"dataVersion"`
+	ChangedAt   time.Time         `json:"changedAt"`
+	Page        int               `json:"page"`
+	Pages       int               `json:"pages"`
+	Items       []integrationItem `json:"items"`
+}
+
+type integrationItem struct {
+	UniqueName string   `json:"uniqueName"`
+	Name       string   `json:"name"`
+	Collection string   `json:"collection"`
+	Kinds      []string `json:"kinds"`
+}
+
+// PublishChanges queues the changes one data sync found for every
+// integration subscribed to their events, returning how many deliveries
+// were queued. A change with several kinds is sent under each kind's event.
+// Changes must share ChangedAt, which keys the deliveries, so publishing the
+// same changes again queues nothing.
+func (s *IntegrationService) PublishChanges(ctx context.Context, changes []models.ItemChange) (int, error) {
+	logger.Debug(ctx, "service: IntegrationService.PublishChanges called", "count", len(changes))
+
+	if len(changes) == 0 {
+		return 0, nil
+	}
+	integrations, err := s.repo.ListIntegrations(ctx)
+	if err != nil {
+		logger.Error(ctx, "service: IntegrationService.PublishChanges - error fetching integrations", "error", err)
+		return 0, err
+	}
+	if len(integrations) == 0 {
+		return 0, nil
+	}
+
+	byEvent := make(map[string][]integrationItem)
+	for _, change := range changes {
+		item := integrationItem{UniqueName: change.UniqueName, Name: change.Name, Collection: change.Collection, Kinds: change.Kinds}
+		for _, kind := range change.Kinds {
+			if event, ok := models.IntegrationEventForKind[kind]; ok {
+				byEvent[event] = append(byEvent[event], item)
+			}
+		}
+	}
+
+	now := s.now()
+	first := changes[0]
+	queued := 0
+	for _, event := range models.IntegrationEvents {
+		items := byEvent[event]
+		pages := (len(items) + integrationBatchSize - 1) / integrationBatchSize
+		for page := 1; page <= pages; page++ {
+			payload, err := json.Marshal(integrationPayload{
+				Event:       event,
+				DataVersion: first.DataVersion,
+				ChangedAt:   first.ChangedAt,
+				Page:        page,
+				Pages:       pages,
+				Items:       items[(page-1)*integrationBatchSize : min(page*integrationBatchSize, len(items))],
+			})
+			if err != nil {
+				return queued, err
+			}
+			key := event + ":" + strconv.FormatInt(first.ChangedAt.UnixMilli(), 10) + ":" + strconv.Itoa(page)
+			for _, integration := range integrations {
+				if !integration.Subscribed(event) {
+					continue
+				}
+				stored, err := s.repo.EnqueueDelivery(ctx, &models.IntegrationDelivery{
+					DeliveryID:    s.ids.NewID(),
+					IntegrationID: integration.ID,
+					Event:         event,
+					Key:           key,
+					Payload:       string(payload),
+					Status:        models.DeliveryPending,
+					NextAttemptAt: now,
+					CreatedAt:     now,
+				})
+				if err != nil {
+					logger.Error(ctx, "service: IntegrationService.PublishChanges - error queueing delivery", "error", err)
+					return queued, err
+				}
+				if stored {
+					queued++
+				}
+			}
+		}
+	}
+
+	if queued > 0 {
+		logger.Info(ctx, "service: IntegrationService.PublishChanges - deliveries queued", "changes", len(changes), "count", queued)
+	}
+	return queued, nil
+}
+
+// Run sends due deliveries every interval until ctx is done.
+func (s *IntegrationService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := s.DispatchDue(ctx); err != nil {
+			logger.Error(ctx, "service: IntegrationService.Run - dispatch failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchDue sends the deliveries that are due, returning how many were
+// delivered, as NotificationService.DispatchDue.
+func (s *IntegrationService) DispatchDue(ctx context.Context) (int, error) {
+	deliveries, err := s.repo.ClaimDueDeliveries(ctx, s.now(), deliveryLease, deliveryBatch)
+	if err != nil {
+		logger.Error(ctx, "service: IntegrationService.DispatchDue - error claiming deliveries", "error", err)
+		return 0, err
+	}
+
+	delivered := 0
+	for i := range deliveries {
+		delivery := &deliveries[i]
+		if s.deliver(ctx, delivery) {
+			delivered++
+		}
+		if err := s.repo.UpdateDelivery(ctx, delivery); err != nil {
+			logger.Error(ctx, "service: IntegrationService.DispatchDue - error saving delivery", "id", delivery.ID.Hex(), "error", err)
+			return delivered, err
+		}
+	}
+
+	if len(deliveries) > 0 {
+		logger.Debug(ctx, "service: IntegrationService.DispatchDue - completed", "claimed", len(deliveries), "delivered", delivered)
+	}
+	return delivered, nil
+}
+
+// deliver sends delivery to its integration and records the outcome on it,
+// reporting whether it was delivered.
+func (s *IntegrationService) deliver(ctx context.Context, delivery *models.IntegrationDelivery) bool {
+	integration, err := s.repo.GetIntegration(ctx, delivery.IntegrationID)
+	if err != nil {
+		// Leave it pending; the lease makes it due again later.
+		delivery.LastError = err.Error()
+		return false
+	}
+	if integration == nil {
+		delivery.Status = models.DeliveryFailed
+		delivery.LastError = "integration deleted"
+		return false
+	}
+
+	status, err := s.send(ctx, integration, delivery)
+	now := s.now()
+	delivery.Attempts++
+	delivery.ResponseStatus = status
+	if err == nil {
+		delivery.Status = models.DeliveryDelivered
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+		return true
+	}
+
+	delivery.LastError = err.Error()
+	if permanentSendError(err) || delivery.Attempts >= models.MaxDeliveryAttempts {
+		delivery.Status = models.DeliveryFailed
+		logger.Warn(ctx, "service: IntegrationService.DispatchDue - delivery failed", "id", delivery.ID.Hex(), "attempts", delivery.Attempts, "error", err)
+		return false
+	}
+	delivery.NextAttemptAt = now.Add(retryBackoff(delivery.Attempts))
+	logger.Debug(ctx, "service: IntegrationService.DispatchDue - delivery will be retried", "id", delivery.ID.Hex(), "attempts", delivery.Attempts, "error", err)
+	return false
+}
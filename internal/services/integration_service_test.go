@@ -0,0 +1,232 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/notify"
+	"github.com/graytonio/warframe-wishlist/internal/repository/memory"
+)
+
+// newIntegrationFixture returns a service whose clock is *now and whose
+// sends are answered by send.
+func newIntegrationFixture(now *time.Time, send func(*models.Integration, *models.IntegrationDelivery) (int, error)) (*IntegrationService, *memory.IntegrationRepository) {
+	repo := memory.NewIntegrationRepository()
+	service := NewIntegrationService(repo, false)
+	service.now = func() time.Time { return *now }
+	service.send = func(_ context.Context, integration *models.Integration, delivery *models.IntegrationDelivery) (int, error) {
+		return send(integration, delivery)
+	}
+	return service, repo
+}
+
+func TestIntegrationService_CreateIntegration(t *testing.T) {
+	now := notificationNow
+	service, _ := newIntegrationFixture(&now, nil)
+	ctx := context.Background()
+
+	integration, err := service.CreateIntegration(ctx, models.IntegrationRequest{
+		Name:   "  Market bot ",
+		URL:    "https://example.com/hook",
+		Events: []string{models.IntegrationItemAdded, models.IntegrationItemAdded},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if integration.ID.IsZero() || integration.Name != "Market bot" || len(integration.Secret) != 62 || len(integration.Events) != 1 {
+		t.Errorf("expected a stored integration with a secret and deduped events, got %+v", integration)
+	}
+
+	tests := []struct {
+		name string
+		req  models.IntegrationRequest
+	}{
+		{name: "missing name", req: models.IntegrationRequest{URL: "https://example.com"}},
+		{name: "name too long", req: models.IntegrationRequest{Name: strings.Repeat("a", 101), URL: "https://example.com"}},
+		{name: "plain http", req: models.IntegrationRequest{Name: "bot", URL: "http://example.com/hook"}},
+		{name: "unknown event", req: models.IntegrationRequest{Name: "bot", URL: "https://example.com", Events: []string{models.NotificationMilestone}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.CreateIntegration(ctx, tt.req); !errors.Is(err, ErrInvalidIntegration) {
+				t.Errorf("expected ErrInvalidIntegration, got %v", err)
+			}
+		})
+	}
+}
+
+func TestIntegrationService_DeleteAndListDeliveries_NotFound(t *testing.T) {
+	now := notificationNow
+	service, _ := newIntegrationFixture(&now, nil)
+	ctx := context.Background()
+
+	for _, id := range []string{"not-an-id", "0123456789abcdef01234567"} {
+		if err := service.DeleteIntegration(ctx, id); !errors.Is(err, ErrIntegrationNotFound) {
+			t.Errorf("expected ErrIntegrationNotFound deleting %q, got %v", id, err)
+		}
+		if _, err := service.ListDeliveries(ctx, id, 10); !errors.Is(err, ErrIntegrationNotFound) {
+			t.Errorf("expected ErrIntegrationNotFound listing %q, got %v", id, err)
+		}
+	}
+}
+
+func TestIntegrationService_PublishChanges(t *testing.T) {
+	now := notificationNow
+	service, repo := newIntegrationFixture(&now, nil)
+	ctx := context.Background()
+
+	all, _ := service.CreateIntegration(ctx, models.IntegrationRequest{Name: "wiki", URL: "https://wiki.example.com/hook"})
+	recipes, _ := service.CreateIntegration(ctx, models.IntegrationRequest{Name: "market", URL: "https://market.example.com/hook", Events: []string{models.IntegrationRecipeChanged}})
+
+	changedAt := now.Add(-time.Minute)
+	changes := []models.ItemChange{
+		{UniqueName: "/Lotus/Volt", Name: "Volt", Collection: "warframes", Kinds: []string{models.ItemChangeRecipe, models.ItemChangeStats}, DataVersion: "v2", ChangedAt: changedAt},
+	}
+	for i := 0; i < integrationBatchSize+1; i++ {
+		changes = append(changes, models.ItemChange{UniqueName: "/Lotus/New", Kinds: []string{models.ItemChangeAdded}, DataVersion: "v2", ChangedAt: changedAt})
+	}
+
+	queued, err := service.PublishChanges(ctx, changes)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// wiki: two pages of added, one recipe, one stats; market: one recipe.
+	if queued != 5 {
+		t.Fatalf("expected 5 deliveries, got %d", queued)
+	}
+
+	deliveries, _ := repo.ListDeliveries(ctx, all.ID, 10)
+	pages := map[int]int{}
+	for _, delivery := range deliveries {
+		var payload integrationPayload
+		if err := json.Unmarshal([]byte(delivery.Payload), &payload); err != nil {
+			t.Fatalf("invalid payload: %v", err)
+		}
+		if payload.Event != delivery.Event || payload.DataVersion != "v2" || !payload.ChangedAt.Equal(changedAt) {
+			t.Errorf("unexpected payload %+v for %s", payload, delivery.Event)
+		}
+		if delivery.Event == models.IntegrationItemAdded {
+			if payload.Pages != 2 {
+				t.Errorf("expected the added items over 2 pages, got %d", payload.Pages)
+			}
+			pages[payload.Page] = len(payload.Items)
+		}
+	}
+	if pages[1] != integrationBatchSize || pages[2] != 1 {
+		t.Errorf("expected a full first page and one item on the second, got %v", pages)
+	}
+
+	marketDeliveries, _ := repo.ListDeliveries(ctx, recipes.ID, 10)
+	if len(marketDeliveries) != 1 || marketDeliveries[0].Event != models.IntegrationRecipeChanged ||
+		!strings.Contains(marketDeliveries[0].Payload, `"kinds":["recipe","stats"]`) {
+		t.Errorf("expected only the recipe change with all its kinds, got %+v", marketDeliveries)
+	}
+
+	if queued, err := service.PublishChanges(ctx, changes); err != nil || queued != 0 {
+		t.Errorf("expected publishing the same changes again to queue nothing, got %d (err %v)", queued, err)
+	}
+}
+
+func TestIntegrationService_DispatchDue(t *testing.T) {
+	now := notificationNow
+	var responses []error
+	var sent []string
+	service, repo := newIntegrationFixture(&now, func(integration *models.Integration, delivery *models.IntegrationDelivery) (int, error) {
+		sent = append(sent, integration.Name)
+		err := responses[0]
+		responses = responses[1:]
+		if err != nil {
+			return http.StatusServiceUnavailable, err
+		}
+		return http.StatusNoContent, nil
+	})
+	ctx := context.Background()
+	integration, _ := service.CreateIntegration(ctx, models.IntegrationRequest{Name: "wiki", URL: "https://wiki.example.com/hook"})
+	changes := []models.ItemChange{{UniqueName: "/Lotus/Volt", Kinds: []string{models.ItemChangeAdded}, ChangedAt: now}}
+	if _, err := service.PublishChanges(ctx, changes); err != nil {
+		t.Fatal(err)
+	}
+
+	responses = []error{&notify.StatusError{StatusCode: http.StatusServiceUnavailable}}
+	if delivered, err := service.DispatchDue(ctx); err != nil || delivered != 0 {
+		t.Fatalf("expected the failed send to be retried later, got %d (err %v)", delivered, err)
+	}
+	deliveries, _ := repo.ListDeliveries(ctx, integration.ID, 10)
+	if deliveries[0].Status != models.DeliveryPending || deliveries[0].Attempts != 1 || !deliveries[0].NextAttemptAt.Equal(now.Add(deliveryBackoff)) {
+		t.Fatalf("expected a retry after the backoff, got %+v", deliveries[0])
+	}
+
+	now = now.Add(deliveryBackoff)
+	responses = []error{nil}
+	if delivered, err := service.DispatchDue(ctx); err != nil || delivered != 1 {
+		t.Fatalf("expected the retry to be delivered, got %d (err %v)", delivered, err)
+	}
+	deliveries, _ = repo.ListDeliveries(ctx, integration.ID, 10)
+	if deliveries[0].Status != models.DeliveryDelivered || deliveries[0].ResponseStatus != http.StatusNoContent || deliveries[0].DeliveredAt == nil {
+		t.Errorf("expected the delivery recorded as delivered, got %+v", deliveries[0])
+	}
+
+	// Deliveries to a deleted integration fail without a send.
+	if _, err := service.PublishChanges(ctx, []models.ItemChange{{UniqueName: "/Lotus/Mag", Kinds: []string{models.ItemChangeAdded}, ChangedAt: now}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.DeleteIntegration(ctx, integration.ID.Hex()); err != nil {
+		t.Fatal(err)
+	}
+	sent = nil
+	if _, err := service.DispatchDue(ctx); err != nil {
+		t.Fatal(err)
+	}
+	deliveries, _ = repo.ListDeliveries(ctx, integration.ID, 10)
+	if len(sent) != 0 || deliveries[0].Status != models.DeliveryFailed || deliveries[0].LastError != "integration deleted" {
+		t.Errorf("expected the delivery to fail unsent, got %+v (sent %v)", deliveries[0], sent)
+	}
+}
+
+func TestIntegrationService_SendsSignedWebhook(t *testing.T) {
+	var body []byte
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		header = r.Header
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	repo := memory.NewIntegrationRepository()
+	service := NewIntegrationService(repo, true)
+	integration, err := service.CreateIntegration(ctx, models.IntegrationRequest{Name: "wiki", URL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes := []models.ItemChange{{UniqueName: "/Lotus/Volt", Name: "Volt", Kinds: []string{models.ItemChangeAdded}, ChangedAt: time.Now()}}
+	if _, err := service.PublishChanges(ctx, changes); err != nil {
+		t.Fatal(err)
+	}
+	if delivered, err := service.DispatchDue(ctx); err != nil || delivered != 1 {
+		t.Fatalf("expected one delivery, got %d (err %v)", delivered, err)
+	}
+
+	deliveries, _ := repo.ListDeliveries(ctx, integration.ID, 10)
+	if len(deliveries) != 1 || deliveries[0].DeliveryID == "" {
+		t.Fatalf("expected one delivery with a delivery ID, got %+v", deliveries)
+	}
+	if header.Get(notify.EventHeader) != models.IntegrationItemAdded || header.Get(notify.DeliveryHeader) != deliveries[0].DeliveryID {
+		t.Errorf("unexpected headers %v", header)
+	}
+	if got, want := header.Get(notify.SignatureHeader), notify.Sign(integration.Secret, body); got != want {
+		t.Errorf("expected signature %q, got %q", want, got)
+	}
+	if !strings.Contains(string(body), `"uniqueName":"/Lotus/Volt"`) {
+		t.Errorf("unexpected body %s", body)
+	}
+}
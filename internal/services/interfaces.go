@@ -2,14 +2,20 @@ package services
 
 import (
 	"context"
+	"time"
 
 	"github.com/graytonio/warframe-wishlist/internal/models"
 )
 
 type ItemServiceInterface interface {
-	Search(ctx context.Context, params models.SearchParams) ([]models.ItemSearchResult, error)
+	Search(ctx context.Context, params models.SearchParams) (*models.ItemSearchPage, error)
 	GetByUniqueName(ctx context.Context, uniqueName string) (*models.Item, error)
 	SearchReusableBlueprints(ctx context.Context, query string, limit int) ([]models.ItemSearchResult, error)
+	GetRecipeTree(ctx context.Context, uniqueName string) (*models.RecipeTree, error)
+}
+
+type ItemAutocompleteServiceInterface interface {
+	Suggest(ctx context.Context, query string, limit int) ([]models.ItemSuggestion, error)
 }
 
 type WishlistServiceInterface interface {
@@ -17,21 +23,342 @@ type WishlistServiceInterface interface {
 	AddItem(ctx context.Context, userID string, req models.AddItemRequest) error
 	RemoveItem(ctx context.Context, userID, uniqueName string) error
 	UpdateQuantity(ctx context.Context, userID, uniqueName string, quantity int) error
+	SetItemLinks(ctx context.Context, userID, uniqueName string, links []models.SourceLinkRequest) ([]models.SourceLink, error)
+	// SetItemRecipe selects one of the item's alternate recipes for material
+	// resolution; an empty recipeID restores the default recipe.
+	SetItemRecipe(ctx context.Context, userID, uniqueName, recipeID string) error
+	// SetItemProgress replaces which of the item's components are done and
+	// returns the item with its new completion.
+	SetItemProgress(ctx context.Context, userID, uniqueName string, progress []models.ComponentProgressRequest) (*models.WishlistItem, error)
+	GetExpandedWishlist(ctx context.Context, userID string) (*models.ExpandedWishlist, error)
+}
+
+type WishlistImportServiceInterface interface {
+	PreviewText(ctx context.Context, text string) (*models.ImportPreview, error)
+	Confirm(ctx context.Context, userID string, req models.ImportConfirmRequest) (*models.ImportConfirmResult, error)
+}
+
+type WishlistTransferServiceInterface interface {
+	Export(ctx context.Context, userID string) (*models.WishlistExport, error)
+	// Checklist returns the wishlist as a printable checklist of items and
+	// the materials still needed.
+	Checklist(ctx context.Context, userID string) (*models.WishlistChecklist, error)
+	Import(ctx context.Context, userID string, req models.WishlistDocumentImportRequest) (*models.WishlistDocumentImportResult, error)
+	// Merge applies an edit made against an earlier revision of the
+	// wishlist, merged three ways with the changes since.
+	Merge(ctx context.Context, userID string, req models.WishlistMergeRequest) (*models.WishlistMergeResult, error)
+}
+
+type ItemChangeServiceInterface interface {
+	ListChanges(ctx context.Context, since time.Time, limit int) (*models.ItemChangesResponse, error)
+}
+
+// ItemExportServiceInterface streams whole item categories.
+type ItemExportServiceInterface interface {
+	CountCategory(ctx context.Context, category string) (int, error)
+	ExportCategory(ctx context.Context, category string, skip, limit int, fn func(item *models.Item) error) error
 }
 
 type MaterialResolverInterface interface {
 	GetMaterials(ctx context.Context, userID string) (*models.MaterialsResponse, error)
 }
 
+type FarmingPlannerInterface interface {
+	GetFarmingPlan(ctx context.Context, userID string, limit int) (*models.FarmingPlan, error)
+}
+
+type RelicResolverInterface interface {
+	GetRelicRequirements(ctx context.Context, userID string) (*models.RelicRequirements, error)
+}
+
+type ResearchCalculatorInterface interface {
+	Calculate(ctx context.Context, req models.ResearchCostRequest) (*models.ResearchCost, error)
+}
+
+type QuantitySuggesterInterface interface {
+	SuggestQuantity(ctx context.Context, userID, uniqueName string) (*models.QuantitySuggestion, error)
+}
+
+type WorldStateServiceInterface interface {
+	Current() *models.WorldState
+}
+
+type OpportunityFinderInterface interface {
+	GetOpportunities(ctx context.Context, userID string) (*models.Opportunities, error)
+}
+
+// MaterialsCache stores resolved materials responses for
+// CachedMaterialResolver. Implementations must be safe for concurrent use and
+// return copies, so callers may modify what they get.
+type MaterialsCache interface {
+	Get(ctx context.Context, key MaterialsCacheKey) (*models.MaterialsResponse, bool)
+	// Set drops response if the user was invalidated, or the cache purged,
+	// since key.Generation was read.
+	Set(ctx context.Context, key MaterialsCacheKey, response *models.MaterialsResponse)
+	// Generation returns the user's invalidation generation, read before
+	// resolving a response to cache.
+	Generation(ctx context.Context, userID string) uint64
+	// Invalidate drops the user's response whatever its key.
+	Invalidate(ctx context.Context, userID string)
+	Purge(ctx context.Context)
+}
+
 type OwnedBlueprintsServiceInterface interface {
 	GetOwnedBlueprints(ctx context.Context, userID string) (*models.OwnedBlueprints, error)
 	AddBlueprint(ctx context.Context, userID string, req models.AddBlueprintRequest) error
 	RemoveBlueprint(ctx context.Context, userID, uniqueName string) error
 	BulkAddBlueprints(ctx context.Context, userID string, req models.BulkAddBlueprintsRequest) error
 	ClearAllBlueprints(ctx context.Context, userID string) error
+	SetBlueprintQuantity(ctx context.Context, userID, uniqueName string, quantity int) error
+	SetComponentCount(ctx context.Context, userID, uniqueName string, count int) error
+	RemoveComponent(ctx context.Context, userID, uniqueName string) error
+	ClearAllComponents(ctx context.Context, userID string) error
+}
+
+type OwnedMaterialsServiceInterface interface {
+	GetOwnedMaterials(ctx context.Context, userID string) (*models.OwnedMaterials, error)
+	SetMaterialCount(ctx context.Context, userID, uniqueName string, count int) error
+	SetMaterialCounts(ctx context.Context, userID string, req models.SetOwnedMaterialsRequest) error
+	RemoveMaterial(ctx context.Context, userID, uniqueName string) error
+	ClearAllMaterials(ctx context.Context, userID string) error
+}
+
+type MasteryServiceInterface interface {
+	GetProfile(ctx context.Context, userID string) (*models.MasteryProfile, error)
+	SetStatus(ctx context.Context, userID, uniqueName, status string) error
+	SetStatuses(ctx context.Context, userID string, req models.SetMasteryRequest) error
+	RemoveItem(ctx context.Context, userID, uniqueName string) error
+	ClearAll(ctx context.Context, userID string) error
+	GetSummary(ctx context.Context, userID string) (*models.MasterySummary, error)
+}
+
+type ProfileImportServiceInterface interface {
+	Import(ctx context.Context, userID string, req models.ProfileImportRequest) (*models.ProfileImportResult, error)
+}
+
+type SettingsServiceInterface interface {
+	GetSettings(ctx context.Context, userID string) (*models.UserSettings, error)
+	UpdateSettings(ctx context.Context, userID string, req models.UpdateSettingsRequest) (*models.UserSettings, error)
+}
+
+// UserTraceServiceInterface is the support API for per-user tracing; the
+// auth middleware only needs IsTraced.
+type UserTraceServiceInterface interface {
+	ListTraces(ctx context.Context) ([]models.UserTrace, error)
+	GetTrace(ctx context.Context, userID string) (*models.UserTrace, error)
+	EnableTrace(ctx context.Context, userID string, req models.EnableUserTraceRequest) (*models.UserTrace, error)
+	DisableTrace(ctx context.Context, userID string) error
+}
+
+type DataSyncServiceInterface interface {
+	Version() string
+	NotifySynced(ctx context.Context, version string) error
+	Flush(ctx context.Context) error
+}
+
+// ItemRefreshServiceInterface reports the scheduled item refresh worker and
+// starts unscheduled runs.
+type ItemRefreshServiceInterface interface {
+	Status(ctx context.Context) (*models.ItemRefreshStatus, error)
+	Start(ctx context.Context) error
+}
+
+type HouseholdServiceInterface interface {
+	GetHousehold(ctx context.Context, userID string) (*models.Household, error)
+	RequestManager(ctx context.Context, memberID string, req models.RequestManagerRequest) (*models.HouseholdLink, error)
+	LeaveManager(ctx context.Context, memberID string) error
+	AcceptMember(ctx context.Context, managerID, memberID string) (*models.HouseholdLink, error)
+	UpdateMember(ctx context.Context, managerID, memberID string, req models.UpdateHouseholdMemberRequest) (*models.HouseholdLink, error)
+	RemoveMember(ctx context.Context, managerID, memberID string) error
+	ListApprovals(ctx context.Context, userID string) (*models.HouseholdApprovals, error)
+	Approve(ctx context.Context, managerID, changeID string) (*models.PendingChange, error)
+	Reject(ctx context.Context, managerID, changeID string) (*models.PendingChange, error)
+}
+
+type PublicWishlistServiceInterface interface {
+	List(ctx context.Context) ([]models.PublicWishlist, error)
+	Get(ctx context.Context, id string) (*models.PublicWishlist, error)
+	GetMaterials(ctx context.Context, id string) (*models.MaterialsResponse, error)
+}
+
+type GiftClaimServiceInterface interface {
+	GetSharedWishlist(ctx context.Context, viewerID, ownerID string) (*models.SharedWishlist, error)
+	Claim(ctx context.Context, claimerID, ownerID string, req models.ClaimGiftRequest) (*models.GiftClaim, error)
+	RevokeClaim(ctx context.Context, callerID, ownerID, uniqueName string) error
+	ListClaims(ctx context.Context, claimerID string) ([]models.GiftClaim, error)
+}
+
+type ShareLinkServiceInterface interface {
+	CreateLink(ctx context.Context, userID string, req models.CreateShareLinkRequest) (*models.ShareLink, error)
+	ListLinks(ctx context.Context, userID string) ([]models.ShareLink, error)
+	DeleteLink(ctx context.Context, userID, linkID string) error
+	// RevokeLink deletes the link with token whoever owns it, for moderators
+	// acting on a report, and returns it.
+	RevokeLink(ctx context.Context, token string) (*models.ShareLink, error)
+	GetView(ctx context.Context, token string) (*models.ShareLinkView, error)
+	GetMaterials(ctx context.Context, token string) (*models.MaterialsResponse, error)
+}
+
+// ModerationServiceInterface keeps the moderation queue of reported share
+// links.
+type ModerationServiceInterface interface {
+	ReportShareLink(ctx context.Context, token string, req models.ReportShareLinkRequest) (*models.ShareLinkReport, error)
+	ListReports(ctx context.Context, status string) ([]models.ShareLinkReport, error)
+	DismissReport(ctx context.Context, token string) error
+	RevokeShareLink(ctx context.Context, token string) (*models.ShareLink, error)
+}
+
+// ImportAliasServiceInterface manages the alias dictionary of the wishlist
+// import.
+type ImportAliasServiceInterface interface {
+	List(ctx context.Context) ([]models.ImportAlias, error)
+	Set(ctx context.Context, word, expansion string) (*models.ImportAlias, error)
+	Delete(ctx context.Context, word string) error
+}
+
+// CustomItemServiceInterface manages the private items users define. Items
+// are addressed by their uniqueName.
+type CustomItemServiceInterface interface {
+	List(ctx context.Context, userID string) ([]models.CustomItem, error)
+	Search(ctx context.Context, userID string, params models.SearchParams) (*models.ItemSearchPage, error)
+	Create(ctx context.Context, userID string, req models.CustomItemRequest) (*models.CustomItem, error)
+	Update(ctx context.Context, userID, uniqueName string, req models.CustomItemRequest) (*models.CustomItem, error)
+	Delete(ctx context.Context, userID, uniqueName string) error
+}
+
+// FoundryServiceInterface tracks the builds in users' foundries.
+type FoundryServiceInterface interface {
+	GetFoundry(ctx context.Context, userID string) (*models.Foundry, error)
+	StartBuild(ctx context.Context, userID string, req models.FoundryBuildRequest) (*models.FoundryBuildStatus, error)
+	RemoveBuild(ctx context.Context, userID, id string) error
+}
+
+// DashboardServiceInterface gathers a user's home screen.
+type DashboardServiceInterface interface {
+	GetDashboard(ctx context.Context, userID string) (*models.Dashboard, error)
+}
+
+// WishlistTemplateServiceInterface keeps users' wishlist templates and
+// applies them.
+type WishlistTemplateServiceInterface interface {
+	ListTemplates(ctx context.Context, userID string) ([]models.WishlistTemplate, error)
+	CreateTemplate(ctx context.Context, userID string, req models.WishlistTemplateRequest) (*models.WishlistTemplate, error)
+	DeleteTemplate(ctx context.Context, userID, id string) error
+	ApplyTemplate(ctx context.Context, userID, id string, variables map[string]int) (*models.ImportConfirmResult, error)
+}
+
+// NotificationServiceInterface manages users' notification channels and
+// their delivery log.
+type NotificationServiceInterface interface {
+	PushKey() string
+	ListChannels(ctx context.Context, userID string) ([]models.NotificationChannel, error)
+	CreateChannel(ctx context.Context, userID string, req models.NotificationChannelRequest) (*models.NotificationChannel, error)
+	DeleteChannel(ctx context.Context, userID, id string) error
+	ListDeliveries(ctx context.Context, userID string, limit int) ([]models.NotificationDelivery, error)
+}
+
+// IntegrationServiceInterface manages the integrations sent item data
+// changes and their delivery log. Integrations are addressed by hex ID.
+type IntegrationServiceInterface interface {
+	ListIntegrations(ctx context.Context) ([]models.Integration, error)
+	CreateIntegration(ctx context.Context, req models.IntegrationRequest) (*models.Integration, error)
+	DeleteIntegration(ctx context.Context, id string) error
+	ListDeliveries(ctx context.Context, id string, limit int) ([]models.IntegrationDelivery, error)
+}
+
+// WorkspacePublisher sends live events to the subscribers of a room.
+type WorkspacePublisher interface {
+	Publish(room string, event any)
+}
+
+// WorkspaceServiceInterface manages the wishlists clans share. Workspaces are
+// addressed by their hex ID and visible only to their members.
+type WorkspaceServiceInterface interface {
+	List(ctx context.Context, userID string) ([]models.Workspace, error)
+	Get(ctx context.Context, userID, id string) (*models.Workspace, error)
+	Create(ctx context.Context, userID string, req models.WorkspaceRequest) (*models.Workspace, error)
+	Update(ctx context.Context, userID, id string, req models.WorkspaceRequest) (*models.Workspace, error)
+	Delete(ctx context.Context, userID, id string) error
+	AddMember(ctx context.Context, userID, id string, req models.WorkspaceMemberRequest) (*models.Workspace, error)
+	SetMemberRole(ctx context.Context, userID, id, memberID, role string) (*models.Workspace, error)
+	RemoveMember(ctx context.Context, userID, id, memberID string) (*models.Workspace, error)
+	AddItem(ctx context.Context, userID, id string, req models.WorkspaceItemRequest) (*models.Workspace, error)
+	UpdateItemQuantity(ctx context.Context, userID, id, uniqueName string, quantity int) (*models.Workspace, error)
+	RemoveItem(ctx context.Context, userID, id, uniqueName string) (*models.Workspace, error)
+	SetContribution(ctx context.Context, userID, id, uniqueName string, quantity int) (*models.Workspace, error)
+	GetMaterials(ctx context.Context, userID, id string) (*models.WorkspaceMaterials, error)
+	LogMaterialContribution(ctx context.Context, userID, id string, req models.WorkspaceMaterialContributionRequest) (*models.WorkspaceContributionEvent, error)
+	ListActivity(ctx context.Context, userID, id string, limit int) ([]models.WorkspaceContributionEvent, error)
+}
+
+type UsageServiceInterface interface {
+	GetUsage(ctx context.Context, userID string) (*models.UserUsage, error)
+	// Warnings returns the given resources the user is close to the cap of.
+	Warnings(ctx context.Context, userID string, resources ...string) ([]models.ResourceUsage, error)
+}
+
+// WishlistVersionServiceInterface versions per-user responses for
+// conditional requests. An empty version means the response has none.
+type WishlistVersionServiceInterface interface {
+	WishlistVersion(ctx context.Context, userID string) (string, error)
+	MaterialsVersion(ctx context.Context, userID string) (string, error)
+}
+
+// OnboardingServiceInterface recognizes, and seeds the wishlists of, users
+// new to the wishlist.
+type OnboardingServiceInterface interface {
+	// Onboard reports whether userID had no wishlist yet.
+	Onboard(ctx context.Context, userID string) (bool, error)
+}
+
+// ActivityServiceInterface reads users' activity logs.
+type ActivityServiceInterface interface {
+	// ListActivity returns a page of the user's activity, newest first.
+	ListActivity(ctx context.Context, userID string, offset, limit int) (*models.ActivityPage, error)
 }
 
 var _ ItemServiceInterface = (*ItemService)(nil)
+var _ ItemServiceInterface = (*DedupedItemService)(nil)
+var _ ItemAutocompleteServiceInterface = (*ItemAutocompleteService)(nil)
+var _ ItemChangeServiceInterface = (*ItemChangeService)(nil)
+var _ ItemExportServiceInterface = (*ItemExportService)(nil)
 var _ WishlistServiceInterface = (*WishlistService)(nil)
+var _ WishlistServiceInterface = (*ApprovalWishlistService)(nil)
+var _ WishlistImportServiceInterface = (*WishlistImportService)(nil)
+var _ WishlistTransferServiceInterface = (*WishlistTransferService)(nil)
 var _ MaterialResolverInterface = (*MaterialResolver)(nil)
+var _ MaterialResolverInterface = (*CachedMaterialResolver)(nil)
+var _ MaterialResolverInterface = (*DedupedMaterialResolver)(nil)
+var _ FarmingPlannerInterface = (*FarmingPlanner)(nil)
+var _ RelicResolverInterface = (*RelicResolver)(nil)
+var _ QuantitySuggesterInterface = (*QuantitySuggester)(nil)
+var _ ResearchCalculatorInterface = (*ResearchCalculator)(nil)
+var _ WorldStateServiceInterface = (*WorldStateService)(nil)
+var _ OpportunityFinderInterface = (*OpportunityFinder)(nil)
+var _ MaterialsCache = (*LRUMaterialsCache)(nil)
+var _ MaterialsCache = (*dedupedMaterialsCache)(nil)
 var _ OwnedBlueprintsServiceInterface = (*OwnedBlueprintsService)(nil)
+var _ OwnedMaterialsServiceInterface = (*OwnedMaterialsService)(nil)
+var _ MasteryServiceInterface = (*MasteryService)(nil)
+var _ ProfileImportServiceInterface = (*ProfileImportService)(nil)
+var _ SettingsServiceInterface = (*SettingsService)(nil)
+var _ UserTraceServiceInterface = (*UserTraceService)(nil)
+var _ DataSyncServiceInterface = (*DataSyncService)(nil)
+var _ ItemRefreshServiceInterface = (*ItemRefreshService)(nil)
+var _ HouseholdServiceInterface = (*HouseholdService)(nil)
+var _ PublicWishlistServiceInterface = (*PublicWishlistService)(nil)
+var _ GiftClaimServiceInterface = (*GiftClaimService)(nil)
+var _ ShareLinkServiceInterface = (*ShareLinkService)(nil)
+var _ ModerationServiceInterface = (*ModerationService)(nil)
+var _ ImportAliasServiceInterface = (*ImportAliasService)(nil)
+var _ CustomItemServiceInterface = (*CustomItemService)(nil)
+var _ FoundryServiceInterface = (*FoundryService)(nil)
+var _ DashboardServiceInterface = (*DashboardService)(nil)
+var _ WishlistTemplateServiceInterface = (*WishlistTemplateService)(nil)
+var _ NotificationServiceInterface = (*NotificationService)(nil)
+var _ IntegrationServiceInterface = (*IntegrationService)(nil)
+var _ WorkspaceServiceInterface = (*WorkspaceService)(nil)
+var _ UsageServiceInterface = (*UsageService)(nil)
+var _ WishlistVersionServiceInterface = (*WishlistVersionService)(nil)
+var _ ActivityServiceInterface = (*ActivityService)(nil)
+var _ OnboardingServiceInterface = (*OnboardingService)(nil)
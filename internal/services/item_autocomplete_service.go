@@ -0,0 +1,206 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+)
+
+// ItemAutocompleteService suggests item names for a typed prefix from an
+// in-memory index of the catalog, so keystrokes never reach the database.
+// Rebuild loads the index at startup and after each data sync; until the
+// first build finishes, Suggest builds it itself.
+type ItemAutocompleteService struct {
+	catalog repository.ItemCatalogInterface
+
+	index   atomic.Pointer[autocompleteIndex]
+	buildMu sync.Mutex
+}
+
+func NewItemAutocompleteService(catalog repository.ItemCatalogInterface) *ItemAutocompleteService {
+	return &ItemAutocompleteService{catalog: catalog}
+}
+
+// Rebuild replaces the index with one built from the current item data.
+// Suggestions keep coming from the previous index while it runs.
+func (s *ItemAutocompleteService) Rebuild(ctx context.Context) error {
+	s.buildMu.Lock()
+	defer s.buildMu.Unlock()
+	_, err := s.build(ctx)
+	return err
+}
+
+// Suggest returns up to limit items whose name, or a word in it, starts with
+// query. Whole-name matches come before word matches, then shorter names
+// first. limit is clamped to MaxAutocompleteSuggestions.
+func (s *ItemAutocompleteService) Suggest(ctx context.Context, query string, limit int) ([]models.ItemSuggestion, error) {
+	logger.Debug(ctx, "service: ItemAutocompleteService.Suggest called", "query", query, "limit", limit)
+
+	if limit <= 0 || limit > models.MaxAutocompleteSuggestions {
+		limit = models.MaxAutocompleteSuggestions
+	}
+	prefix := normalizeImportName(query)
+	if prefix == "" {
+		return []models.ItemSuggestion{}, nil
+	}
+
+	index, err := s.loadIndex(ctx)
+	if err != nil {
+		logger.Error(ctx, "service: ItemAutocompleteService.Suggest - error building prefix index", "error", err)
+		return nil, err
+	}
+
+	suggestions := index.suggest(prefix, limit)
+	logger.Debug(ctx, "service: ItemAutocompleteService.Suggest - completed", "count", len(suggestions))
+	return suggestions, nil
+}
+
+func (s *ItemAutocompleteService) loadIndex(ctx context.Context) (*autocompleteIndex, error) {
+	if index := s.index.Load(); index != nil {
+		return index, nil
+	}
+
+	s.buildMu.Lock()
+	defer s.buildMu.Unlock()
+	if index := s.index.Load(); index != nil {
+		return index, nil
+	}
+	return s.build(ctx)
+}
+
+// build must be called with buildMu held.
+func (s *ItemAutocompleteService) build(ctx context.Context) (*autocompleteIndex, error) {
+	index := &autocompleteIndex{}
+	err := s.catalog.ForEachItem(ctx, func(item models.Item) error {
+		// Star chart nodes and enemies are skipped, as in wishlist import.
+		if item.Archived || item.Name == "" || importSkippedCollections[item.Collection] {
+			return nil
+		}
+		index.add(item)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	index.finish()
+
+	logger.Info(ctx, "service: ItemAutocompleteService - prefix index built", "itemCount", len(index.items), "keyCount", len(index.keys))
+	s.index.Store(index)
+	return index, nil
+}
+
+// autocompleteIndex holds a sorted key for every word-start of every item
+// name ("ash prime", "prime"), so a prefix lookup is a binary search followed
+// by a scan of the matching run.
+type autocompleteIndex struct {
+	items []autocompleteItem
+	keys  []autocompleteKey
+}
+
+type autocompleteItem struct {
+	suggestion models.ItemSuggestion
+	// nameLength ranks shorter, closer matches first.
+	nameLength int
+}
+
+type autocompleteKey struct {
+	key  string
+	item int
+	// word is the position of the word the key starts at; 0 is the whole name.
+	word int
+}
+
+func (x *autocompleteIndex) add(item models.Item) {
+	name := normalizeImportName(item.Name)
+	if name == "" {
+		return
+	}
+	id := len(x.items)
+	x.items = append(x.items, autocompleteItem{
+		suggestion: models.ItemSuggestion{
+			UniqueName: item.UniqueName,
+			Name:       item.Name,
+			Category:   item.Category,
+			ImageName:  item.ImageName,
+		},
+		nameLength: len(name),
+	})
+
+	word := 0
+	for key := name; ; word++ {
+		x.keys = append(x.keys, autocompleteKey{key: key, item: id, word: word})
+		space := strings.IndexByte(key, ' ')
+		if space < 0 {
+			break
+		}
+		key = key[space+1:]
+	}
+}
+
+func (x *autocompleteIndex) finish() {
+	sort.Slice(x.keys, func(i, j int) bool {
+		return x.keys[i].key < x.keys[j].key
+	})
+}
+
+func (x *autocompleteIndex) suggest(prefix string, limit int) []models.ItemSuggestion {
+	start := sort.Search(len(x.keys), func(i int) bool {
+		return x.keys[i].key >= prefix
+	})
+
+	// top holds the best limit matches so far, in rank order, with at most
+	// one entry per item; a one-letter prefix matches thousands of keys, so
+	// they are never all sorted.
+	top := make([]autocompleteKey, 0, limit+1)
+	for i := start; i < len(x.keys) && strings.HasPrefix(x.keys[i].key, prefix); i++ {
+		k := x.keys[i]
+		if existing := slices.IndexFunc(top, func(t autocompleteKey) bool { return t.item == k.item }); existing >= 0 {
+			if !x.before(k, top[existing]) {
+				continue
+			}
+			top = slices.Delete(top, existing, existing+1)
+		}
+		if len(top) == limit && !x.before(k, top[len(top)-1]) {
+			continue
+		}
+		at, _ := slices.BinarySearchFunc(top, k, func(t, k autocompleteKey) int {
+			if x.before(t, k) {
+				return -1
+			}
+			return 1
+		})
+		top = slices.Insert(top, at, k)
+		if len(top) > limit {
+			top = top[:limit]
+		}
+	}
+
+	suggestions := make([]models.ItemSuggestion, len(top))
+	for i, k := range top {
+		suggestions[i] = x.items[k.item].suggestion
+	}
+	return suggestions
+}
+
+// before ranks whole-name matches ahead of word matches, then shorter names,
+// then by name and uniqueName so the order is stable.
+func (x *autocompleteIndex) before(a, b autocompleteKey) bool {
+	if (a.word == 0) != (b.word == 0) {
+		return a.word == 0
+	}
+	itemA, itemB := x.items[a.item], x.items[b.item]
+	if itemA.nameLength != itemB.nameLength {
+		return itemA.nameLength < itemB.nameLength
+	}
+	if itemA.suggestion.Name != itemB.suggestion.Name {
+		return itemA.suggestion.Name < itemB.suggestion.Name
+	}
+	return itemA.suggestion.UniqueName < itemB.suggestion.UniqueName
+}
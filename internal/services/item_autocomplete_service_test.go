@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+func suggestionNames(suggestions []models.ItemSuggestion) []string {
+	names := make([]string, len(suggestions))
+	for i, s := range suggestions {
+		names[i] = s.Name
+	}
+	return names
+}
+
+func TestItemAutocompleteService_Suggest(t *testing.T) {
+	service := NewItemAutocompleteService(newImportCatalog())
+
+	tests := []struct {
+		name     string
+		query    string
+		limit    int
+		expected []string
+	}{
+		{name: "whole name prefix", query: "rhi", expected: []string{"Rhino", "Rhino Prime"}},
+		{name: "case and punctuation ignored", query: "  RHINO-p", expected: []string{"Rhino Prime"}},
+		{name: "whole name matches before word matches", query: "b", expected: []string{"Braton", "MK1-Braton", "Kuva Bramma"}},
+		{name: "word prefix", query: "prime", expected: []string{"Soma Prime", "Rhino Prime"}},
+		{name: "shared names are both suggested", query: "form", expected: []string{"Forma", "Forma"}},
+		{name: "limit", query: "b", limit: 1, expected: []string{"Braton"}},
+		{name: "archived items and nodes are skipped", query: "o", expected: []string{}},
+		{name: "empty query", query: " ", expected: []string{}},
+		{name: "no match", query: "zzz", expected: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suggestions, err := service.Suggest(context.Background(), tt.query, tt.limit)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := suggestionNames(suggestions); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestItemAutocompleteService_SuggestFields(t *testing.T) {
+	catalog := newImportCatalog()
+	catalog.Add("warframes", models.Item{UniqueName: "/Lotus/Powersuits/Excalibur/Excalibur", Name: "Excalibur", Category: "Warframes", ImageName: "excalibur.png"})
+	service := NewItemAutocompleteService(catalog)
+
+	suggestions, err := service.Suggest(context.Background(), "exc", 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []models.ItemSuggestion{{UniqueName: "/Lotus/Powersuits/Excalibur/Excalibur", Name: "Excalibur", Category: "Warframes", ImageName: "excalibur.png"}}
+	if !reflect.DeepEqual(suggestions, expected) {
+		t.Errorf("expected %+v, got %+v", expected, suggestions)
+	}
+}
+
+func TestItemAutocompleteService_LimitIsCapped(t *testing.T) {
+	catalog := catalogFunc(func(ctx context.Context, fn func(item models.Item) error) error {
+		for i := 0; i < 25; i++ {
+			if err := fn(models.Item{UniqueName: fmt.Sprintf("/Lotus/Mod%02d", i), Name: fmt.Sprintf("Mod %02d", i)}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	service := NewItemAutocompleteService(catalog)
+
+	suggestions, err := service.Suggest(context.Background(), "mod", 100)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(suggestions) != models.MaxAutocompleteSuggestions {
+		t.Errorf("expected %d suggestions, got %d", models.MaxAutocompleteSuggestions, len(suggestions))
+	}
+}
+
+func TestItemAutocompleteService_Rebuild(t *testing.T) {
+	names := []string{"Rhino"}
+	builds := 0
+	catalog := catalogFunc(func(ctx context.Context, fn func(item models.Item) error) error {
+		builds++
+		for _, name := range names {
+			if err := fn(models.Item{UniqueName: "/Lotus/" + name, Name: name}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	service := NewItemAutocompleteService(catalog)
+
+	for i := 0; i < 2; i++ {
+		if _, err := service.Suggest(context.Background(), "r", 0); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if builds != 1 {
+		t.Fatalf("expected the index to be built once, got %d builds", builds)
+	}
+
+	names = []string{"Rhino", "Revenant"}
+	if err := service.Rebuild(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	suggestions, _ := service.Suggest(context.Background(), "r", 0)
+	if got := suggestionNames(suggestions); !reflect.DeepEqual(got, []string{"Rhino", "Revenant"}) {
+		t.Errorf("expected the rebuilt index, got %v", got)
+	}
+}
+
+func TestItemAutocompleteService_CatalogError(t *testing.T) {
+	catalogErr := errors.New("database error")
+	service := NewItemAutocompleteService(catalogFunc(func(ctx context.Context, fn func(item models.Item) error) error {
+		return catalogErr
+	}))
+
+	if _, err := service.Suggest(context.Background(), "rhino", 0); !errors.Is(err, catalogErr) {
+		t.Errorf("expected the catalog error, got %v", err)
+	}
+	if err := service.Rebuild(context.Background()); !errors.Is(err, catalogErr) {
+		t.Errorf("expected Rebuild to return the catalog error, got %v", err)
+	}
+}
+
+// BenchmarkItemAutocompleteService_Suggest measures a one-letter prefix, the
+// widest lookup, over a catalog the size of the full WFCD export.
+func BenchmarkItemAutocompleteService_Suggest(b *testing.B) {
+	words := []string{"Prime", "Kuva", "Tenet", "Blueprint", "Systems", "Chassis", "Neuroptics", "Barrel", "Receiver", "Stock"}
+	catalog := catalogFunc(func(ctx context.Context, fn func(item models.Item) error) error {
+		for i := 0; i < 20000; i++ {
+			name := fmt.Sprintf("%s Item%d %s", words[i%len(words)], i, words[(i/len(words))%len(words)])
+			if err := fn(models.Item{UniqueName: fmt.Sprintf("/Lotus/Item%d", i), Name: name}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	service := NewItemAutocompleteService(catalog)
+	if err := service.Rebuild(context.Background()); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := service.Suggest(context.Background(), "s", 0); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+)
+
+// maxWarmDepth bounds the recipe walk in case the data contains a cycle the
+// visited set does not catch (e.g. differently cased uniqueNames).
+const maxWarmDepth = 10
+
+// ItemCacheWarmStats summarises a warm run.
+type ItemCacheWarmStats struct {
+	PopularItems int
+	ItemsLoaded  int
+}
+
+// ItemCacheWarmer loads the most wishlisted items and their full recipe trees
+// through the item cache so material resolution does not pay for cold lookups.
+type ItemCacheWarmer struct {
+	popularityRepo repository.PopularityRepositoryInterface
+	itemRepo       repository.ItemRepositoryInterface
+	limit          int
+}
+
+func NewItemCacheWarmer(popularityRepo repository.PopularityRepositoryInterface, itemRepo repository.ItemRepositoryInterface, limit int) *ItemCacheWarmer {
+	return &ItemCacheWarmer{
+		popularityRepo: popularityRepo,
+		itemRepo:       itemRepo,
+		limit:          limit,
+	}
+}
+
+func (w *ItemCacheWarmer) Warm(ctx context.Context) (*ItemCacheWarmStats, error) {
+	logger.Debug(ctx, "service: ItemCacheWarmer.Warm called", "limit", w.limit)
+
+	popular, err := w.popularityRepo.TopWishlistedItems(ctx, w.limit)
+	if err != nil {
+		logger.Error(ctx, "service: ItemCacheWarmer.Warm - failed to get popular items", "error", err)
+		return nil, err
+	}
+
+	stats := &ItemCacheWarmStats{PopularItems: len(popular)}
+	visited := make(map[string]bool)
+
+	var level []string
+	for _, p := range popular {
+		if !visited[p.UniqueName] {
+			visited[p.UniqueName] = true
+			level = append(level, p.UniqueName)
+		}
+	}
+
+	for depth := 0; len(level) > 0 && depth < maxWarmDepth; depth++ {
+		items, err := w.itemRepo.FindByUniqueNames(ctx, level)
+		if err != nil {
+			logger.Error(ctx, "service: ItemCacheWarmer.Warm - failed to load items", "depth", depth, "error", err)
+			return nil, err
+		}
+		stats.ItemsLoaded += len(items)
+
+		var next []string
+		for _, item := range items {
+			next = appendUnvisitedComponents(next, item.Components, visited)
+		}
+
+		logger.Debug(ctx, "service: ItemCacheWarmer.Warm - loaded level", "depth", depth, "requested", len(level), "found", len(items))
+		level = next
+	}
+
+	logger.Info(ctx, "service: ItemCacheWarmer.Warm - completed", "popularItems", stats.PopularItems, "itemsLoaded", stats.ItemsLoaded)
+	return stats, nil
+}
+
+// appendUnvisitedComponents collects component uniqueNames, including those of
+// components embedded inline, since the material resolver looks each of them up.
+func appendUnvisitedComponents(names []string, components []models.Component, visited map[string]bool) []string {
+	for _, component := range components {
+		if component.UniqueName != "" && !visited[component.UniqueName] {
+			visited[component.UniqueName] = true
+			names = append(names, component.UniqueName)
+		}
+		names = appendUnvisitedComponents(names, component.Components, visited)
+	}
+	return names
+}
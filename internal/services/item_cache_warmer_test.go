@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+func TestItemCacheWarmer_Warm_WalksRecipeTrees(t *testing.T) {
+	items := map[string]*models.Item{
+		"/Lotus/Ash": {UniqueName: "/Lotus/Ash", Components: []models.Component{
+			{UniqueName: "/Lotus/AshChassis", Components: []models.Component{{UniqueName: "/Lotus/Alloy"}}},
+			{UniqueName: "/Lotus/Forma"},
+		}},
+		"/Lotus/AshChassis": {UniqueName: "/Lotus/AshChassis", Components: []models.Component{{UniqueName: "/Lotus/Morphics"}}},
+		"/Lotus/Forma":      {UniqueName: "/Lotus/Forma", Components: []models.Component{{UniqueName: "/Lotus/FormaBlueprint"}}},
+		"/Lotus/Alloy":      {UniqueName: "/Lotus/Alloy"},
+		"/Lotus/Morphics":   {UniqueName: "/Lotus/Morphics"},
+		// Cycle back to a root must not be walked twice.
+		"/Lotus/FormaBlueprint": {UniqueName: "/Lotus/FormaBlueprint", Components: []models.Component{{UniqueName: "/Lotus/Ash"}}},
+	}
+
+	var batches [][]string
+	itemRepo := &mocks.MockItemRepository{
+		FindByUniqueNamesFunc: func(ctx context.Context, uniqueNames []string) (map[string]*models.Item, error) {
+			batch := append([]string(nil), uniqueNames...)
+			sort.Strings(batch)
+			batches = append(batches, batch)
+			result := make(map[string]*models.Item)
+			for _, name := range uniqueNames {
+				if item, ok := items[name]; ok {
+					result[name] = item
+				}
+			}
+			return result, nil
+		},
+	}
+	popularityRepo := &mocks.MockPopularityRepository{
+		TopWishlistedItemsFunc: func(ctx context.Context, limit int) ([]models.ItemPopularity, error) {
+			if limit != 500 {
+				t.Errorf("expected limit 500, got %d", limit)
+			}
+			return []models.ItemPopularity{
+				{UniqueName: "/Lotus/Ash", WishlistCount: 3},
+				{UniqueName: "/Lotus/Removed", WishlistCount: 1},
+			}, nil
+		},
+	}
+
+	stats, err := NewItemCacheWarmer(popularityRepo, itemRepo, 500).Warm(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedBatches := [][]string{
+		{"/Lotus/Ash", "/Lotus/Removed"},
+		{"/Lotus/Alloy", "/Lotus/AshChassis", "/Lotus/Forma"},
+		{"/Lotus/FormaBlueprint", "/Lotus/Morphics"},
+	}
+	if !reflect.DeepEqual(batches, expectedBatches) {
+		t.Errorf("expected batches %v, got %v", expectedBatches, batches)
+	}
+	if stats.PopularItems != 2 {
+		t.Errorf("expected 2 popular items, got %d", stats.PopularItems)
+	}
+	if stats.ItemsLoaded != 6 {
+		t.Errorf("expected 6 items loaded, got %d", stats.ItemsLoaded)
+	}
+}
+
+func TestItemCacheWarmer_Warm_NoPopularItems(t *testing.T) {
+	itemRepo := &mocks.MockItemRepository{
+		FindByUniqueNamesFunc: func(ctx context.Context, uniqueNames []string) (map[string]*models.Item, error) {
+			t.Error("expected no item lookups")
+			return nil, nil
+		},
+	}
+
+	stats, err := NewItemCacheWarmer(&mocks.MockPopularityRepository{}, itemRepo, 500).Warm(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats.PopularItems != 0 || stats.ItemsLoaded != 0 {
+		t.Errorf("expected empty stats, got %+v", stats)
+	}
+}
+
+func TestItemCacheWarmer_Warm_Errors(t *testing.T) {
+	popular := &mocks.MockPopularityRepository{
+		TopWishlistedItemsFunc: func(ctx context.Context, limit int) ([]models.ItemPopularity, error) {
+			return []models.ItemPopularity{{UniqueName: "/Lotus/Ash", WishlistCount: 1}}, nil
+		},
+	}
+	failingPopularity := &mocks.MockPopularityRepository{
+		TopWishlistedItemsFunc: func(ctx context.Context, limit int) ([]models.ItemPopularity, error) {
+			return nil, errors.New("database error")
+		},
+	}
+	failingItems := &mocks.MockItemRepository{
+		FindByUniqueNamesFunc: func(ctx context.Context, uniqueNames []string) (map[string]*models.Item, error) {
+			return nil, errors.New("database error")
+		},
+	}
+
+	if _, err := NewItemCacheWarmer(failingPopularity, &mocks.MockItemRepository{}, 10).Warm(context.Background()); err == nil {
+		t.Error("expected popularity error, got nil")
+	}
+	if _, err := NewItemCacheWarmer(popular, failingItems, 10).Warm(context.Background()); err == nil {
+		t.Error("expected item lookup error, got nil")
+	}
+}
@@ -0,0 +1,319 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+)
+
+const (
+	DefaultItemChangesWindow = 7 * 24 * time.Hour
+	DefaultItemChangesLimit  = 100
+	MaxItemChangesLimit      = 500
+)
+
+// ItemChangePublisher is sent the changes each data sync finds.
+type ItemChangePublisher interface {
+	PublishChanges(ctx context.Context, changes []models.ItemChange) (int, error)
+}
+
+// ItemChangeService detects which items a data sync added, removed, or
+// changed, by comparing per-item fingerprints against those stored at the
+// previous sync, and serves the resulting changes feed.
+type ItemChangeService struct {
+	catalog    repository.ItemCatalogInterface
+	changeRepo repository.ItemChangeRepositoryInterface
+	publisher  ItemChangePublisher
+	version    func() string
+	now        func() time.Time
+}
+
+func NewItemChangeService(catalog repository.ItemCatalogInterface, changeRepo repository.ItemChangeRepositoryInterface, version func() string) *ItemChangeService {
+	return &ItemChangeService{
+		catalog:    catalog,
+		changeRepo: changeRepo,
+		version:    version,
+		now:        time.Now,
+	}
+}
+
+// SetPublisher has the changes Detect records sent to publisher, such as the
+// integrations.
+func (s *ItemChangeService) SetPublisher(publisher ItemChangePublisher) {
+	s.publisher = publisher
+}
+
+// Detect fingerprints the current item data, records a change for every item
+// that differs from the stored fingerprints, and stores the new fingerprints.
+// With no stored fingerprints it only records the baseline, since every item
+// would otherwise be reported as added. It returns the number of changes.
+func (s *ItemChangeService) Detect(ctx context.Context) (int, error) {
+	logger.Debug(ctx, "service: ItemChangeService.Detect called")
+
+	previous, err := s.changeRepo.GetFingerprints(ctx)
+	if err != nil {
+		logger.Error(ctx, "service: ItemChangeService.Detect - error fetching fingerprints", "error", err)
+		return 0, err
+	}
+
+	var current []models.ItemFingerprint
+	err = s.catalog.ForEachItem(ctx, func(item models.Item) error {
+		fp, err := fingerprintItem(item)
+		if err != nil {
+			return err
+		}
+		current = append(current, fp)
+		return nil
+	})
+	if err != nil {
+		logger.Error(ctx, "service: ItemChangeService.Detect - error fingerprinting items", "error", err)
+		return 0, err
+	}
+
+	var changes []models.ItemChange
+	if len(previous) > 0 {
+		changes = diffFingerprints(previous, current)
+		changedAt := s.now()
+		version := s.version()
+		for i := range changes {
+			changes[i].ChangedAt = changedAt
+			changes[i].DataVersion = version
+		}
+	}
+
+	// Record before replacing the fingerprints: if recording fails the next
+	// sync diffs against the same baseline and reports these changes again.
+	if err := s.changeRepo.RecordChanges(ctx, changes); err != nil {
+		logger.Error(ctx, "service: ItemChangeService.Detect - error recording changes", "error", err)
+		return 0, err
+	}
+	// Publishing is retried the same way, so integrations are sent every
+	// change at least once.
+	if s.publisher != nil && len(changes) > 0 {
+		if _, err := s.publisher.PublishChanges(ctx, changes); err != nil {
+			logger.Error(ctx, "service: ItemChangeService.Detect - error publishing changes", "error", err)
+			return 0, err
+		}
+	}
+	if err := s.changeRepo.ReplaceFingerprints(ctx, current); err != nil {
+		logger.Error(ctx, "service: ItemChangeService.Detect - error storing fingerprints", "error", err)
+		return 0, err
+	}
+
+	logger.Info(ctx, "service: ItemChangeService.Detect - completed", "itemCount", len(current), "changeCount", len(changes), "baseline", len(previous) == 0)
+	return len(changes), nil
+}
+
+// EnsureBaseline records the initial fingerprints if none are stored yet, so
+// the first data sync after deployment already reports changes. It never
+// records changes itself, so it is safe to run on every replica at startup.
+func (s *ItemChangeService) EnsureBaseline(ctx context.Context) error {
+	previous, err := s.changeRepo.GetFingerprints(ctx)
+	if err != nil {
+		logger.Error(ctx, "service: ItemChangeService.EnsureBaseline - error fetching fingerprints", "error", err)
+		return err
+	}
+	if len(previous) > 0 {
+		logger.Debug(ctx, "service: ItemChangeService.EnsureBaseline - baseline exists", "count", len(previous))
+		return nil
+	}
+	_, err = s.Detect(ctx)
+	return err
+}
+
+// ListChanges returns changes recorded at or after since, newest first. A
+// zero since defaults to DefaultItemChangesWindow ago; limit is clamped to
+// MaxItemChangesLimit and defaults to DefaultItemChangesLimit.
+func (s *ItemChangeService) ListChanges(ctx context.Context, since time.Time, limit int) (*models.ItemChangesResponse, error) {
+	if since.IsZero() {
+		since = s.now().Add(-DefaultItemChangesWindow)
+	}
+	if limit <= 0 {
+		limit = DefaultItemChangesLimit
+	}
+	if limit > MaxItemChangesLimit {
+		limit = MaxItemChangesLimit
+	}
+	logger.Debug(ctx, "service: ItemChangeService.ListChanges called", "since", since, "limit", limit)
+
+	changes, err := s.changeRepo.ListChangesSince(ctx, since, limit)
+	if err != nil {
+		logger.Error(ctx, "service: ItemChangeService.ListChanges - repository error", "error", err)
+		return nil, err
+	}
+
+	return &models.ItemChangesResponse{
+		Since:   since,
+		Changes: changes,
+		Count:   len(changes),
+	}, nil
+}
+
+// diffFingerprints returns a change for every added, removed, or modified
+// item, ordered by uniqueName.
+func diffFingerprints(previous map[string]models.ItemFingerprint, current []models.ItemFingerprint) []models.ItemChange {
+	var changes []models.ItemChange
+	seen := make(map[string]bool, len(current))
+
+	for _, fp := range current {
+		seen[fp.UniqueName] = true
+		old, ok := previous[fp.UniqueName]
+		// Archived items stay in the catalog, so archiving reads as a removal
+		// and un-archiving as an addition.
+		switch {
+		case !ok && fp.Archived:
+			continue
+		case !ok || (old.Archived && !fp.Archived):
+			changes = append(changes, newItemChange(fp, models.ItemChangeAdded))
+			continue
+		case fp.Archived && !old.Archived:
+			changes = append(changes, newItemChange(fp, models.ItemChangeRemoved))
+			continue
+		case fp.Archived:
+			continue
+		}
+
+		var kinds []string
+		if fp.Recipe != old.Recipe {
+			kinds = append(kinds, models.ItemChangeRecipe)
+		}
+		if fp.Stats != old.Stats {
+			kinds = append(kinds, models.ItemChangeStats)
+		}
+		if fp.Availability != old.Availability {
+			kinds = append(kinds, models.ItemChangeAvailability)
+		}
+		if len(kinds) > 0 {
+			changes = append(changes, newItemChange(fp, kinds...))
+		}
+	}
+
+	for name, fp := range previous {
+		if !seen[name] && !fp.Archived {
+			changes = append(changes, newItemChange(fp, models.ItemChangeRemoved))
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].UniqueName < changes[j].UniqueName
+	})
+	return changes
+}
+
+func newItemChange(fp models.ItemFingerprint, kinds ...string) models.ItemChange {
+	return models.ItemChange{
+		UniqueName: fp.UniqueName,
+		Name:       fp.Name,
+		Collection: fp.Collection,
+		Kinds:      kinds,
+	}
+}
+
+// recipeComponent is the part of a component that affects crafting.
+type recipeComponent struct {
+	UniqueName string            `json:"u"`
+	ItemCount  int               `json:"n"`
+	Components []recipeComponent `json:"c,omitempty"`
+}
+
+// fingerprintItem hashes the recipe (build cost, time, components), stats,
+// and availability (drop sources, including components') of an item.
+func fingerprintItem(item models.Item) (models.ItemFingerprint, error) {
+	recipe, err := hashJSON(struct {
+		BuildPrice         int               `json:"price"`
+		BuildTime          int               `json:"time"`
+		SkipBuildTimePrice int               `json:"skip"`
+		BuildQuantity      int               `json:"qty"`
+		ConsumeOnBuild     bool              `json:"consume"`
+		Components         []recipeComponent `json:"components"`
+	}{item.BuildPrice, item.BuildTime, item.SkipBuildTimePrice, item.BuildQuantity, item.ConsumeOnBuild, recipeComponents(item.Components)})
+	if err != nil {
+		return models.ItemFingerprint{}, err
+	}
+
+	stats, err := hashJSON(struct {
+		Description string `json:"description"`
+		Type        string `json:"type"`
+		Category    string `json:"category"`
+		MasteryReq  int    `json:"mastery"`
+		Tradable    bool   `json:"tradable"`
+		IsPrime     bool   `json:"prime"`
+	}{item.Description, item.Type, item.Category, item.MasteryReq, item.Tradable, item.IsPrime})
+	if err != nil {
+		return models.ItemFingerprint{}, err
+	}
+
+	availability, err := hashJSON(collectDrops(item))
+	if err != nil {
+		return models.ItemFingerprint{}, err
+	}
+
+	return models.ItemFingerprint{
+		UniqueName:   item.UniqueName,
+		Name:         item.Name,
+		Collection:   item.Collection,
+		Recipe:       recipe,
+		Stats:        stats,
+		Availability: availability,
+		Archived:     item.Archived,
+	}, nil
+}
+
+func recipeComponents(components []models.Component) []recipeComponent {
+	if len(components) == 0 {
+		return nil
+	}
+	result := make([]recipeComponent, len(components))
+	for i, c := range components {
+		result[i] = recipeComponent{UniqueName: c.UniqueName, ItemCount: c.ItemCount, Components: recipeComponents(c.Components)}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].UniqueName < result[j].UniqueName
+	})
+	return result
+}
+
+// collectDrops returns the item's and its components' drops in a stable
+// order, so reordering in the source data does not count as a change.
+func collectDrops(item models.Item) []models.Drop {
+	drops := append([]models.Drop(nil), item.Drops...)
+	var walk func(components []models.Component)
+	walk = func(components []models.Component) {
+		for _, c := range components {
+			drops = append(drops, c.Drops...)
+			walk(c.Components)
+		}
+	}
+	walk(item.Components)
+
+	sort.Slice(drops, func(i, j int) bool {
+		a, b := drops[i], drops[j]
+		if a.Location != b.Location {
+			return a.Location < b.Location
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if a.Rarity != b.Rarity {
+			return a.Rarity < b.Rarity
+		}
+		return a.Chance < b.Chance
+	})
+	return drops
+}
+
+func hashJSON(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:16]), nil
+}
@@ -0,0 +1,304 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository/memory"
+)
+
+func newItemChangeFixture(items ...models.Item) (*ItemChangeService, *memory.ItemRepository, *memory.ItemChangeRepository) {
+	catalog := memory.NewItemRepository()
+	catalog.Add("warframes", items...)
+	changeRepo := memory.NewItemChangeRepository()
+	service := NewItemChangeService(catalog, changeRepo, func() string { return "v2" })
+	service.now = func() time.Time { return time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC) }
+	return service, catalog, changeRepo
+}
+
+func TestItemChangeService_Detect_FirstRunRecordsBaselineOnly(t *testing.T) {
+	ctx := context.Background()
+	service, _, changeRepo := newItemChangeFixture(models.Item{UniqueName: "/Lotus/Alpha", Name: "Alpha"})
+
+	count, err := service.Detect(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("expected no changes on first run, got %d", count)
+	}
+
+	fingerprints, _ := changeRepo.GetFingerprints(ctx)
+	if len(fingerprints) != 1 {
+		t.Errorf("expected 1 fingerprint, got %d", len(fingerprints))
+	}
+}
+
+func TestItemChangeService_Detect(t *testing.T) {
+	ctx := context.Background()
+	alpha := models.Item{
+		UniqueName: "/Lotus/Alpha",
+		Name:       "Alpha",
+		BuildPrice: 25000,
+		MasteryReq: 5,
+		Components: []models.Component{
+			{UniqueName: "/Lotus/Plate", ItemCount: 2, Drops: []models.Drop{{Location: "Earth", Type: "Plate", Chance: 0.1}}},
+		},
+	}
+	beta := models.Item{UniqueName: "/Lotus/Beta", Name: "Beta", MasteryReq: 1}
+	gamma := models.Item{UniqueName: "/Lotus/Gamma", Name: "Gamma"}
+	service, catalog, changeRepo := newItemChangeFixture(alpha, beta, gamma)
+
+	if _, err := service.Detect(ctx); err != nil {
+		t.Fatalf("baseline: %v", err)
+	}
+
+	// Alpha's recipe and drops change, Beta's stats change, Gamma is
+	// untouched, Delta is new; Alpha's re-ordered drops must not matter.
+	alpha.Components = []models.Component{
+		{UniqueName: "/Lotus/Plate", ItemCount: 3, Drops: []models.Drop{{Location: "Mars", Type: "Plate", Chance: 0.2}, {Location: "Earth", Type: "Plate", Chance: 0.1}}},
+	}
+	beta.MasteryReq = 2
+	catalog.Add("warframes", alpha, beta, models.Item{UniqueName: "/Lotus/Delta", Name: "Delta"})
+
+	count, err := service.Detect(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count != 3 {
+		t.Fatalf("expected 3 changes, got %d", count)
+	}
+
+	changes, _ := changeRepo.ListChangesSince(ctx, time.Time{}, 10)
+	byName := make(map[string]models.ItemChange)
+	for _, change := range changes {
+		byName[change.UniqueName] = change
+		if change.DataVersion != "v2" || change.Collection != "warframes" || !change.ChangedAt.Equal(service.now()) {
+			t.Errorf("unexpected change metadata: %+v", change)
+		}
+	}
+
+	assertKinds(t, byName["/Lotus/Alpha"], models.ItemChangeRecipe, models.ItemChangeAvailability)
+	assertKinds(t, byName["/Lotus/Beta"], models.ItemChangeStats)
+	assertKinds(t, byName["/Lotus/Delta"], models.ItemChangeAdded)
+	if _, ok := byName["/Lotus/Gamma"]; ok {
+		t.Error("expected unchanged Gamma not to be reported")
+	}
+}
+
+func TestItemChangeService_Detect_ReorderedDropsAreNotAChange(t *testing.T) {
+	ctx := context.Background()
+	item := models.Item{UniqueName: "/Lotus/Alpha", Name: "Alpha", Drops: []models.Drop{{Location: "Earth"}, {Location: "Mars"}}}
+	service, catalog, _ := newItemChangeFixture(item)
+
+	if _, err := service.Detect(ctx); err != nil {
+		t.Fatalf("baseline: %v", err)
+	}
+	item.Drops = []models.Drop{{Location: "Mars"}, {Location: "Earth"}}
+	catalog.Add("warframes", item)
+
+	if count, err := service.Detect(ctx); err != nil || count != 0 {
+		t.Errorf("expected no changes, got count=%d err=%v", count, err)
+	}
+}
+
+func TestItemChangeService_Detect_RemovedItem(t *testing.T) {
+	ctx := context.Background()
+	changeRepo := memory.NewItemChangeRepository()
+	changeRepo.ReplaceFingerprints(ctx, []models.ItemFingerprint{{UniqueName: "/Lotus/Gone", Name: "Gone", Collection: "mods"}})
+	service := NewItemChangeService(memory.NewItemRepository(), changeRepo, func() string { return "" })
+
+	if count, err := service.Detect(ctx); err != nil || count != 1 {
+		t.Fatalf("expected 1 change, got count=%d err=%v", count, err)
+	}
+
+	changes, _ := changeRepo.ListChangesSince(ctx, time.Time{}, 10)
+	if len(changes) != 1 {
+		t.Fatalf("expected 1 change, got %+v", changes)
+	}
+	assertKinds(t, changes[0], models.ItemChangeRemoved)
+	if changes[0].Name != "Gone" || changes[0].Collection != "mods" {
+		t.Errorf("expected removed change to keep the last known name, got %+v", changes[0])
+	}
+}
+
+func TestItemChangeService_Detect_Archiving(t *testing.T) {
+	ctx := context.Background()
+	alpha := models.Item{UniqueName: "/Lotus/Alpha", Name: "Alpha"}
+	beta := models.Item{UniqueName: "/Lotus/Beta", Name: "Beta", Archived: true}
+	service, catalog, changeRepo := newItemChangeFixture(alpha, beta)
+
+	if _, err := service.Detect(ctx); err != nil {
+		t.Fatalf("baseline: %v", err)
+	}
+
+	// Alpha is archived upstream and Beta comes back.
+	alpha.Archived = true
+	beta.Archived = false
+	catalog.Add("warframes", alpha, beta)
+
+	if count, err := service.Detect(ctx); err != nil || count != 2 {
+		t.Fatalf("expected 2 changes, got count=%d err=%v", count, err)
+	}
+	changes, _ := changeRepo.ListChangesSince(ctx, time.Time{}, 10)
+	byName := make(map[string]models.ItemChange)
+	for _, change := range changes {
+		byName[change.UniqueName] = change
+	}
+	assertKinds(t, byName["/Lotus/Alpha"], models.ItemChangeRemoved)
+	assertKinds(t, byName["/Lotus/Beta"], models.ItemChangeAdded)
+
+	// Edits to an item that stays archived are not reported.
+	alpha.MasteryReq = 8
+	catalog.Add("warframes", alpha)
+	if count, err := service.Detect(ctx); err != nil || count != 0 {
+		t.Errorf("expected no changes for an archived item, got count=%d err=%v", count, err)
+	}
+}
+
+func TestItemChangeService_Detect_RecordFailureKeepsFingerprints(t *testing.T) {
+	replaced := false
+	changeRepo := &mocks.MockItemChangeRepository{
+		GetFingerprintsFunc: func(ctx context.Context) (map[string]models.ItemFingerprint, error) {
+			return map[string]models.ItemFingerprint{"/Lotus/Gone": {UniqueName: "/Lotus/Gone"}}, nil
+		},
+		RecordChangesFunc: func(ctx context.Context, changes []models.ItemChange) error {
+			return errors.New("database error")
+		},
+		ReplaceFingerprintsFunc: func(ctx context.Context, fingerprints []models.ItemFingerprint) error {
+			replaced = true
+			return nil
+		},
+	}
+	service := NewItemChangeService(memory.NewItemRepository(), changeRepo, func() string { return "" })
+
+	if _, err := service.Detect(context.Background()); err == nil {
+		t.Error("expected error but got none")
+	}
+	if replaced {
+		t.Error("expected fingerprints not to be replaced when recording changes fails")
+	}
+}
+
+type recordingChangePublisher struct {
+	published []models.ItemChange
+	err       error
+}
+
+func (p *recordingChangePublisher) PublishChanges(ctx context.Context, changes []models.ItemChange) (int, error) {
+	p.published = append(p.published, changes...)
+	return len(changes), p.err
+}
+
+func TestItemChangeService_Detect_Publishes(t *testing.T) {
+	ctx := context.Background()
+	service, catalog, changeRepo := newItemChangeFixture(models.Item{UniqueName: "/Lotus/Alpha", Name: "Alpha"})
+	publisher := &recordingChangePublisher{}
+	service.SetPublisher(publisher)
+
+	if _, err := service.Detect(ctx); err != nil {
+		t.Fatalf("baseline: %v", err)
+	}
+	if len(publisher.published) != 0 {
+		t.Fatalf("expected the baseline to publish nothing, got %+v", publisher.published)
+	}
+
+	catalog.Add("warframes", models.Item{UniqueName: "/Lotus/Beta", Name: "Beta"})
+	publisher.err = errors.New("database error")
+	if _, err := service.Detect(ctx); err == nil {
+		t.Fatal("expected the publish error")
+	}
+	if fingerprints, _ := changeRepo.GetFingerprints(ctx); len(fingerprints) != 1 {
+		t.Errorf("expected fingerprints kept when publishing fails, got %d", len(fingerprints))
+	}
+
+	publisher.published, publisher.err = nil, nil
+	if _, err := service.Detect(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(publisher.published) != 1 || publisher.published[0].UniqueName != "/Lotus/Beta" || publisher.published[0].DataVersion != "v2" {
+		t.Errorf("expected the retried sync to publish Beta, got %+v", publisher.published)
+	}
+}
+
+func TestItemChangeService_EnsureBaseline(t *testing.T) {
+	ctx := context.Background()
+	service, catalog, changeRepo := newItemChangeFixture(models.Item{UniqueName: "/Lotus/Alpha", Name: "Alpha"})
+
+	if err := service.EnsureBaseline(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	catalog.Add("warframes", models.Item{UniqueName: "/Lotus/Beta", Name: "Beta"})
+	if err := service.EnsureBaseline(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	fingerprints, _ := changeRepo.GetFingerprints(ctx)
+	if len(fingerprints) != 1 {
+		t.Errorf("expected existing baseline to be kept, got %d fingerprints", len(fingerprints))
+	}
+	if changes, _ := changeRepo.ListChangesSince(ctx, time.Time{}, 10); len(changes) != 0 {
+		t.Errorf("expected EnsureBaseline never to record changes, got %+v", changes)
+	}
+}
+
+func TestItemChangeService_ListChanges(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		since         time.Time
+		limit         int
+		expectedSince time.Time
+		expectedLimit int
+	}{
+		{name: "defaults", expectedSince: now.Add(-DefaultItemChangesWindow), expectedLimit: DefaultItemChangesLimit},
+		{name: "explicit values", since: now.Add(-time.Hour), limit: 5, expectedSince: now.Add(-time.Hour), expectedLimit: 5},
+		{name: "limit clamped", since: now.Add(-time.Hour), limit: 10000, expectedSince: now.Add(-time.Hour), expectedLimit: MaxItemChangesLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotSince time.Time
+			var gotLimit int
+			changeRepo := &mocks.MockItemChangeRepository{
+				ListChangesSinceFunc: func(ctx context.Context, since time.Time, limit int) ([]models.ItemChange, error) {
+					gotSince, gotLimit = since, limit
+					return []models.ItemChange{{UniqueName: "/Lotus/Alpha"}}, nil
+				},
+			}
+			service := NewItemChangeService(memory.NewItemRepository(), changeRepo, func() string { return "" })
+			service.now = func() time.Time { return now }
+
+			result, err := service.ListChanges(context.Background(), tt.since, tt.limit)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !gotSince.Equal(tt.expectedSince) || gotLimit != tt.expectedLimit {
+				t.Errorf("expected since=%v limit=%d, got since=%v limit=%d", tt.expectedSince, tt.expectedLimit, gotSince, gotLimit)
+			}
+			if !result.Since.Equal(tt.expectedSince) || result.Count != 1 {
+				t.Errorf("unexpected response: %+v", result)
+			}
+		})
+	}
+}
+
+func assertKinds(t *testing.T, change models.ItemChange, expected ...string) {
+	t.Helper()
+	if len(change.Kinds) != len(expected) {
+		t.Errorf("%s: expected kinds %v, got %v", change.UniqueName, expected, change.Kinds)
+		return
+	}
+	for i := range expected {
+		if change.Kinds[i] != expected[i] {
+			t.Errorf("%s: expected kinds %v, got %v", change.UniqueName, expected, change.Kinds)
+			return
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+)
+
+var ErrUnknownCategory = errors.New("unknown category")
+
+// ItemExportService streams whole item categories, for clients mirroring the
+// item data.
+type ItemExportService struct {
+	exportRepo repository.ItemExportInterface
+}
+
+func NewItemExportService(exportRepo repository.ItemExportInterface) *ItemExportService {
+	return &ItemExportService{exportRepo: exportRepo}
+}
+
+// CountCategory returns the number of items ExportCategory visits in
+// category, which must be one of repository.ItemCollections.
+func (s *ItemExportService) CountCategory(ctx context.Context, category string) (int, error) {
+	logger.Debug(ctx, "service: ItemExportService.CountCategory called", "category", category)
+
+	if !slices.Contains(repository.ItemCollections, category) {
+		logger.Warn(ctx, "service: ItemExportService.CountCategory - unknown category", "category", category)
+		return 0, ErrUnknownCategory
+	}
+	count, err := s.exportRepo.CountCategory(ctx, category)
+	if err != nil {
+		logger.Error(ctx, "service: ItemExportService.CountCategory - repository error", "error", err)
+		return 0, err
+	}
+	return count, nil
+}
+
+// ExportCategory calls fn for the items of category in uniqueName order,
+// skipping the first skip and stopping after limit of them, or at the end
+// when limit is 0. The order is stable between data syncs, so an export cut
+// short can be resumed by skipping what was already received.
+func (s *ItemExportService) ExportCategory(ctx context.Context, category string, skip, limit int, fn func(item *models.Item) error) error {
+	logger.Debug(ctx, "service: ItemExportService.ExportCategory called", "category", category, "skip", skip, "limit", limit)
+
+	if !slices.Contains(repository.ItemCollections, category) {
+		logger.Warn(ctx, "service: ItemExportService.ExportCategory - unknown category", "category", category)
+		return ErrUnknownCategory
+	}
+	exported := 0
+	err := s.exportRepo.ForEachInCategory(ctx, category, max(skip, 0), max(limit, 0), func(item models.Item) error {
+		exported++
+		return fn(&item)
+	})
+	if err != nil {
+		logger.Error(ctx, "service: ItemExportService.ExportCategory - export failed", "category", category, "exported", exported, "error", err)
+		return err
+	}
+
+	logger.Debug(ctx, "service: ItemExportService.ExportCategory - completed", "category", category, "exported", exported)
+	return nil
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository/memory"
+)
+
+func TestItemExportService(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewItemRepository()
+	if _, err := repo.AddJSON("mods", []byte(`[
+		{"uniqueName": "/Lotus/Mods/Serration", "name": "Serration"},
+		{"uniqueName": "/Lotus/Mods/Flow", "name": "Flow"},
+		{"uniqueName": "/Lotus/Mods/Vitality", "name": "Vitality"}
+	]`)); err != nil {
+		t.Fatalf("seeding: %v", err)
+	}
+	service := NewItemExportService(repo)
+
+	if count, err := service.CountCategory(ctx, "mods"); err != nil || count != 3 {
+		t.Errorf("expected 3 mods, got %d, %v", count, err)
+	}
+	if _, err := service.CountCategory(ctx, "planets"); !errors.Is(err, ErrUnknownCategory) {
+		t.Errorf("expected ErrUnknownCategory, got %v", err)
+	}
+	if _, err := service.CountCategory(ctx, ""); !errors.Is(err, ErrUnknownCategory) {
+		t.Errorf("expected a missing category to be unknown, got %v", err)
+	}
+
+	var names []string
+	err := service.ExportCategory(ctx, "mods", 1, 0, func(item *models.Item) error {
+		names = append(names, item.Name)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(names) != 2 || names[0] != "Serration" || names[1] != "Vitality" {
+		t.Errorf("expected the mods after Flow in uniqueName order, got %v", names)
+	}
+
+	err = service.ExportCategory(ctx, "planets", 0, 0, func(item *models.Item) error { return nil })
+	if !errors.Is(err, ErrUnknownCategory) {
+		t.Errorf("expected ErrUnknownCategory, got %v", err)
+	}
+}
@@ -0,0 +1,260 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/itemsource"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+)
+
+// itemRefreshDownloadTimeout bounds the download of each data file.
+const itemRefreshDownloadTimeout = 2 * time.Minute
+
+// ErrItemRefreshRunning is returned when a refresh is requested while one is
+// already in progress.
+var ErrItemRefreshRunning = errors.New("item refresh already running")
+
+// ErrItemRefreshUnavailable is returned when a refresh is requested on an
+// instance without MongoDB, which has no item collections to sync.
+var ErrItemRefreshUnavailable = errors.New("item refresh requires MongoDB")
+
+// ItemRefreshService periodically re-syncs the item collections from the WFCD
+// export, like cmd/sync, and records each run in the sync status collection.
+// When a run changes the item data it notifies the data sync service so
+// caches are refreshed. Only one instance should run the worker; the others
+// can still report the recorded status.
+type ItemRefreshService struct {
+	syncer     repository.ItemSyncerInterface
+	statusRepo repository.SyncStatusRepositoryInterface
+	dataSync   DataSyncServiceInterface
+	source     string
+	interval   time.Duration
+	load       func(ctx context.Context) ([]itemsource.Dataset, error)
+	now        func() time.Time
+
+	mu        sync.Mutex
+	enabled   bool
+	running   bool
+	nextRunAt time.Time
+}
+
+// NewItemRefreshService returns a service that syncs from source, a base URL
+// or local directory of the data files, every interval once Run is called. An
+// empty source selects itemsource.DefaultSource.
+func NewItemRefreshService(syncer repository.ItemSyncerInterface, statusRepo repository.SyncStatusRepositoryInterface, dataSync DataSyncServiceInterface, source string, interval time.Duration) *ItemRefreshService {
+	if source == "" {
+		source = itemsource.DefaultSource
+	}
+	client := &http.Client{Timeout: itemRefreshDownloadTimeout}
+	return &ItemRefreshService{
+		syncer:     syncer,
+		statusRepo: statusRepo,
+		dataSync:   dataSync,
+		source:     source,
+		interval:   interval,
+		load: func(ctx context.Context) ([]itemsource.Dataset, error) {
+			return itemsource.Load(ctx, client, source)
+		},
+		now: time.Now,
+	}
+}
+
+// Run refreshes the item data every interval until ctx is done. The first run
+// is scheduled one interval after the last recorded start, so restarts do not
+// trigger a refresh each time. Failures are recorded and retried on the next
+// run.
+func (s *ItemRefreshService) Run(ctx context.Context) {
+	s.mu.Lock()
+	s.enabled = true
+	s.mu.Unlock()
+
+	delay := s.initialDelay(ctx)
+	for {
+		s.mu.Lock()
+		s.nextRunAt = s.now().Add(delay)
+		s.mu.Unlock()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if _, err := s.Refresh(ctx); err != nil {
+			logger.Error(ctx, "service: ItemRefreshService.Run - refresh failed", "error", err)
+		}
+		delay = s.interval
+	}
+}
+
+func (s *ItemRefreshService) initialDelay(ctx context.Context) time.Duration {
+	status, err := s.statusRepo.Get(ctx, models.SyncStatusItems)
+	if err != nil {
+		logger.Warn(ctx, "service: ItemRefreshService.Run - failed to get sync status, refreshing now", "error", err)
+		return 0
+	}
+	if status == nil || status.LastStartedAt.IsZero() {
+		return 0
+	}
+	return max(status.LastStartedAt.Add(s.interval).Sub(s.now()), 0)
+}
+
+// Refresh syncs every item collection once and records the run. Collections
+// that fail are skipped and reported in the returned error; the others are
+// still synced.
+func (s *ItemRefreshService) Refresh(ctx context.Context) (*models.SyncStatus, error) {
+	logger.Info(ctx, "service: ItemRefreshService.Refresh called", "source", s.source)
+
+	if !s.begin() {
+		return nil, ErrItemRefreshRunning
+	}
+	defer s.end()
+	return s.refresh(ctx)
+}
+
+// Start begins a refresh in the background, for an admin who wants the item
+// data re-synced now rather than at the next scheduled run. The run outlives
+// ctx but keeps its values, such as the request ID it logs with.
+func (s *ItemRefreshService) Start(ctx context.Context) error {
+	logger.Info(ctx, "service: ItemRefreshService.Start called", "source", s.source)
+
+	if s.syncer == nil {
+		return ErrItemRefreshUnavailable
+	}
+	if !s.begin() {
+		return ErrItemRefreshRunning
+	}
+	go func(ctx context.Context) {
+		defer s.end()
+		if _, err := s.refresh(ctx); err != nil {
+			logger.Error(ctx, "service: ItemRefreshService.Start - refresh failed", "error", err)
+		}
+	}(context.WithoutCancel(ctx))
+	return nil
+}
+
+// begin marks a run as started, reporting false when one already is.
+func (s *ItemRefreshService) begin() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.running {
+		return false
+	}
+	s.running = true
+	s.nextRunAt = time.Time{}
+	return true
+}
+
+func (s *ItemRefreshService) end() {
+	s.mu.Lock()
+	s.running = false
+	s.mu.Unlock()
+}
+
+func (s *ItemRefreshService) refresh(ctx context.Context) (*models.SyncStatus, error) {
+	status, err := s.statusRepo.Get(ctx, models.SyncStatusItems)
+	if err != nil {
+		logger.Warn(ctx, "service: ItemRefreshService.Refresh - failed to get previous status", "error", err)
+	}
+	if status == nil {
+		status = &models.SyncStatus{Name: models.SyncStatusItems}
+	}
+
+	startedAt := s.now()
+	status.Source = s.source
+	status.LastStartedAt = startedAt
+	status.LastError = ""
+	status.FailedCollections = []string{}
+	status.Stats = models.ItemSyncStats{}
+
+	runErr := s.sync(ctx, status)
+
+	finishedAt := s.now()
+	status.LastFinishedAt = finishedAt
+	status.DurationMs = finishedAt.Sub(startedAt).Milliseconds()
+	if runErr != nil {
+		status.LastError = runErr.Error()
+	} else {
+		status.LastSuccessAt = finishedAt
+	}
+	changed := status.Stats.Changed()
+	if changed {
+		status.Version = finishedAt.UTC().Format(DataVersionLayout)
+	}
+
+	if err := s.statusRepo.Upsert(ctx, status); err != nil {
+		logger.Error(ctx, "service: ItemRefreshService.Refresh - failed to record status", "error", err)
+	}
+	// Collections that did sync changed the data even if others failed.
+	if changed {
+		if err := s.dataSync.NotifySynced(ctx, status.Version); err != nil {
+			logger.Error(ctx, "service: ItemRefreshService.Refresh - sync hooks failed", "error", err)
+		}
+	}
+
+	logger.Info(ctx, "service: ItemRefreshService.Refresh - completed",
+		"inserted", status.Stats.Inserted, "updated", status.Stats.Updated, "archived", status.Stats.Archived,
+		"unchanged", status.Stats.Unchanged, "failedCollections", len(status.FailedCollections), "durationMs", status.DurationMs)
+	return status, runErr
+}
+
+func (s *ItemRefreshService) sync(ctx context.Context, status *models.SyncStatus) error {
+	datasets, err := s.load(ctx)
+	if err != nil {
+		logger.Error(ctx, "service: ItemRefreshService.Refresh - failed to load item data", "error", err)
+		return err
+	}
+
+	for _, ds := range datasets {
+		stats, err := s.syncDataset(ctx, ds)
+		if err != nil {
+			logger.Error(ctx, "service: ItemRefreshService.Refresh - failed to sync collection", "collection", ds.Collection, "error", err)
+			status.FailedCollections = append(status.FailedCollections, ds.Collection)
+			continue
+		}
+		status.Stats.Add(stats)
+	}
+
+	if len(status.FailedCollections) > 0 {
+		return fmt.Errorf("%d of %d collections failed to sync", len(status.FailedCollections), len(datasets))
+	}
+	return nil
+}
+
+func (s *ItemRefreshService) syncDataset(ctx context.Context, ds itemsource.Dataset) (models.ItemSyncStats, error) {
+	if err := s.syncer.EnsureIndexes(ctx, ds.Collection); err != nil {
+		return models.ItemSyncStats{}, err
+	}
+	return s.syncer.SyncCollection(ctx, ds.Collection, ds.Items, false)
+}
+
+// Status returns the worker's state on this instance and the last recorded
+// run, which may have been made by another instance.
+func (s *ItemRefreshService) Status(ctx context.Context) (*models.ItemRefreshStatus, error) {
+	logger.Debug(ctx, "service: ItemRefreshService.Status called")
+
+	last, err := s.statusRepo.Get(ctx, models.SyncStatusItems)
+	if err != nil {
+		logger.Error(ctx, "service: ItemRefreshService.Status - failed to get sync status", "error", err)
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return &models.ItemRefreshStatus{
+		Enabled:   s.enabled,
+		Running:   s.running,
+		Interval:  s.interval,
+		NextRunAt: s.nextRunAt,
+		Last:      last,
+	}, nil
+}
@@ -0,0 +1,310 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/itemsource"
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository/memory"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// newItemDataDir writes a valid one-item data file for every collection.
+func newItemDataDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, file := range itemsource.DataFiles {
+		data := `[{"uniqueName":"/Lotus/` + file + `"}]`
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// newItemRefreshFixture returns a refresh service over a local data directory
+// whose clock reads now.
+func newItemRefreshFixture(t *testing.T, syncer *mocks.MockItemSyncer, dataSync *mocks.MockDataSyncService, now time.Time) (*ItemRefreshService, *memory.SyncStatusRepository) {
+	t.Helper()
+	statusRepo := memory.NewSyncStatusRepository()
+	service := NewItemRefreshService(syncer, statusRepo, dataSync, newItemDataDir(t), 6*time.Hour)
+	service.now = func() time.Time { return now }
+	return service, statusRepo
+}
+
+func TestItemRefreshService_Refresh(t *testing.T) {
+	now := time.Date(2026, 10, 17, 6, 0, 0, 0, time.UTC)
+	var indexed, synced []string
+	var notified string
+	syncer := &mocks.MockItemSyncer{
+		EnsureIndexesFunc: func(ctx context.Context, collection string) error {
+			indexed = append(indexed, collection)
+			return nil
+		},
+		SyncCollectionFunc: func(ctx context.Context, collection string, items []bson.M, dryRun bool) (models.ItemSyncStats, error) {
+			synced = append(synced, collection)
+			if dryRun || len(items) != 1 {
+				t.Errorf("expected a real sync of one item, got dryRun=%v items=%d", dryRun, len(items))
+			}
+			if collection == "mods" {
+				return models.ItemSyncStats{Updated: 1}, nil
+			}
+			return models.ItemSyncStats{Unchanged: 1}, nil
+		},
+	}
+	dataSync := &mocks.MockDataSyncService{
+		NotifySyncedFunc: func(ctx context.Context, version string) error {
+			notified = version
+			return nil
+		},
+	}
+	service, statusRepo := newItemRefreshFixture(t, syncer, dataSync, now)
+
+	status, err := service.Refresh(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(indexed) != len(itemsource.DataFiles) || len(synced) != len(itemsource.DataFiles) {
+		t.Errorf("expected every collection to be indexed and synced, got %d and %d", len(indexed), len(synced))
+	}
+	expectedStats := models.ItemSyncStats{Updated: 1, Unchanged: len(itemsource.DataFiles) - 1}
+	if status.Stats != expectedStats || status.LastError != "" || !status.LastSuccessAt.Equal(now) {
+		t.Errorf("unexpected status %+v", status)
+	}
+	if status.Version != "20261017T060000Z" || notified != status.Version {
+		t.Errorf("expected version 20261017T060000Z to be notified, got %q and %q", status.Version, notified)
+	}
+
+	stored, _ := statusRepo.Get(context.Background(), models.SyncStatusItems)
+	if stored == nil || stored.Stats != expectedStats || !stored.LastStartedAt.Equal(now) {
+		t.Errorf("expected the run to be recorded, got %+v", stored)
+	}
+}
+
+func TestItemRefreshService_Refresh_UnchangedDataSkipsNotify(t *testing.T) {
+	now := time.Date(2026, 10, 17, 6, 0, 0, 0, time.UTC)
+	dataSync := &mocks.MockDataSyncService{
+		NotifySyncedFunc: func(ctx context.Context, version string) error {
+			t.Error("expected no notification when nothing changed")
+			return nil
+		},
+	}
+	service, statusRepo := newItemRefreshFixture(t, &mocks.MockItemSyncer{}, dataSync, now)
+	statusRepo.Upsert(context.Background(), &models.SyncStatus{Name: models.SyncStatusItems, Version: "v1"})
+
+	status, err := service.Refresh(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status.Version != "v1" || !status.LastSuccessAt.Equal(now) {
+		t.Errorf("expected the previous version to be kept, got %+v", status)
+	}
+}
+
+func TestItemRefreshService_Refresh_CollectionFailure(t *testing.T) {
+	lastSuccess := time.Date(2026, 10, 16, 6, 0, 0, 0, time.UTC)
+	now := lastSuccess.Add(24 * time.Hour)
+	notified := false
+	syncer := &mocks.MockItemSyncer{
+		SyncCollectionFunc: func(ctx context.Context, collection string, items []bson.M, dryRun bool) (models.ItemSyncStats, error) {
+			if collection == "mods" {
+				return models.ItemSyncStats{}, errors.New("bulk write failed")
+			}
+			return models.ItemSyncStats{Inserted: 1}, nil
+		},
+	}
+	dataSync := &mocks.MockDataSyncService{
+		NotifySyncedFunc: func(ctx context.Context, version string) error {
+			notified = true
+			return nil
+		},
+	}
+	service, statusRepo := newItemRefreshFixture(t, syncer, dataSync, now)
+	statusRepo.Upsert(context.Background(), &models.SyncStatus{Name: models.SyncStatusItems, LastSuccessAt: lastSuccess})
+
+	status, err := service.Refresh(context.Background())
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if len(status.FailedCollections) != 1 || status.FailedCollections[0] != "mods" || status.LastError != err.Error() {
+		t.Errorf("expected mods to be reported as failed, got %+v", status)
+	}
+	if !status.LastSuccessAt.Equal(lastSuccess) {
+		t.Errorf("expected the last success to be kept, got %v", status.LastSuccessAt)
+	}
+	if !notified {
+		t.Error("expected the collections that synced to be notified")
+	}
+}
+
+func TestItemRefreshService_Refresh_LoadFailure(t *testing.T) {
+	syncer := &mocks.MockItemSyncer{
+		SyncCollectionFunc: func(ctx context.Context, collection string, items []bson.M, dryRun bool) (models.ItemSyncStats, error) {
+			t.Error("expected nothing to be synced")
+			return models.ItemSyncStats{}, nil
+		},
+	}
+	statusRepo := memory.NewSyncStatusRepository()
+	service := NewItemRefreshService(syncer, statusRepo, &mocks.MockDataSyncService{}, t.TempDir(), time.Hour)
+
+	if _, err := service.Refresh(context.Background()); err == nil {
+		t.Fatal("expected error")
+	}
+	stored, _ := statusRepo.Get(context.Background(), models.SyncStatusItems)
+	if stored == nil || stored.LastError == "" || !stored.LastSuccessAt.IsZero() {
+		t.Errorf("expected the failure to be recorded, got %+v", stored)
+	}
+}
+
+func TestItemRefreshService_Refresh_AlreadyRunning(t *testing.T) {
+	service, _ := newItemRefreshFixture(t, &mocks.MockItemSyncer{}, &mocks.MockDataSyncService{}, time.Now())
+	service.running = true
+
+	if _, err := service.Refresh(context.Background()); !errors.Is(err, ErrItemRefreshRunning) {
+		t.Errorf("expected ErrItemRefreshRunning, got %v", err)
+	}
+}
+
+func TestItemRefreshService_Start(t *testing.T) {
+	notified := make(chan string, 1)
+	dataSync := &mocks.MockDataSyncService{
+		NotifySyncedFunc: func(ctx context.Context, version string) error {
+			notified <- version
+			return nil
+		},
+	}
+	syncer := &mocks.MockItemSyncer{
+		SyncCollectionFunc: func(ctx context.Context, collection string, items []bson.M, dryRun bool) (models.ItemSyncStats, error) {
+			return models.ItemSyncStats{Inserted: 1}, nil
+		},
+	}
+	service, statusRepo := newItemRefreshFixture(t, syncer, dataSync, time.Date(2026, 10, 17, 6, 0, 0, 0, time.UTC))
+
+	// The run must outlive the request that started it.
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := service.Start(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cancel()
+
+	select {
+	case <-notified:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the started refresh to run")
+	}
+	stored, _ := statusRepo.Get(context.Background(), models.SyncStatusItems)
+	if stored == nil || stored.LastError != "" {
+		t.Errorf("expected a successful run to be recorded, got %+v", stored)
+	}
+}
+
+func TestItemRefreshService_Start_AlreadyRunning(t *testing.T) {
+	service, _ := newItemRefreshFixture(t, &mocks.MockItemSyncer{}, &mocks.MockDataSyncService{}, time.Now())
+	service.running = true
+
+	if err := service.Start(context.Background()); !errors.Is(err, ErrItemRefreshRunning) {
+		t.Errorf("expected ErrItemRefreshRunning, got %v", err)
+	}
+}
+
+func TestItemRefreshService_Start_WithoutSyncer(t *testing.T) {
+	service := NewItemRefreshService(nil, memory.NewSyncStatusRepository(), &mocks.MockDataSyncService{}, "", time.Hour)
+
+	if err := service.Start(context.Background()); !errors.Is(err, ErrItemRefreshUnavailable) {
+		t.Errorf("expected ErrItemRefreshUnavailable, got %v", err)
+	}
+	if service.running {
+		t.Error("expected no run to be marked as started")
+	}
+}
+
+func TestItemRefreshService_InitialDelay(t *testing.T) {
+	now := time.Date(2026, 10, 17, 6, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		lastStarted time.Time
+		expected    time.Duration
+	}{
+		{name: "never run", expected: 0},
+		{name: "recent run", lastStarted: now.Add(-2 * time.Hour), expected: 4 * time.Hour},
+		{name: "overdue run", lastStarted: now.Add(-7 * time.Hour), expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, statusRepo := newItemRefreshFixture(t, &mocks.MockItemSyncer{}, &mocks.MockDataSyncService{}, now)
+			if !tt.lastStarted.IsZero() {
+				statusRepo.Upsert(context.Background(), &models.SyncStatus{Name: models.SyncStatusItems, LastStartedAt: tt.lastStarted})
+			}
+
+			if got := service.initialDelay(context.Background()); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestItemRefreshService_Run(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	refreshed := make(chan string, 1)
+	dataSync := &mocks.MockDataSyncService{
+		NotifySyncedFunc: func(ctx context.Context, version string) error {
+			refreshed <- version
+			return nil
+		},
+	}
+	syncer := &mocks.MockItemSyncer{
+		SyncCollectionFunc: func(ctx context.Context, collection string, items []bson.M, dryRun bool) (models.ItemSyncStats, error) {
+			return models.ItemSyncStats{Inserted: 1}, nil
+		},
+	}
+	service, _ := newItemRefreshFixture(t, syncer, dataSync, time.Date(2026, 10, 17, 6, 0, 0, 0, time.UTC))
+
+	done := make(chan struct{})
+	go func() {
+		service.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-refreshed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the first refresh to run immediately")
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected Run to return once ctx is done")
+	}
+
+	status, err := service.Status(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !status.Enabled || status.Running || status.Interval != 6*time.Hour || status.Last == nil {
+		t.Errorf("unexpected status %+v", status)
+	}
+}
+
+func TestItemRefreshService_Status_NoRuns(t *testing.T) {
+	service, _ := newItemRefreshFixture(t, &mocks.MockItemSyncer{}, &mocks.MockDataSyncService{}, time.Now())
+
+	status, err := service.Status(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status.Enabled || status.Running || !status.NextRunAt.IsZero() || status.Last != nil {
+		t.Errorf("expected a disabled worker without runs, got %+v", status)
+	}
+}
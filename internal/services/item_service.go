@@ -16,15 +16,18 @@ func NewItemService(repo repository.ItemRepositoryInterface) *ItemService {
 	return &ItemService{repo: repo}
 }
 
-func (s *ItemService) Search(ctx context.Context, params models.SearchParams) ([]models.ItemSearchResult, error) {
+func (s *ItemService) Search(ctx context.Context, params models.SearchParams) (*models.ItemSearchPage, error) {
 	logger.Debug(ctx, "service: ItemService.Search called", "query", params.Query, "category", params.Category)
-	results, err := s.repo.Search(ctx, params)
+	page, err := s.repo.Search(ctx, params.Normalized())
 	if err != nil {
 		logger.Error(ctx, "service: ItemService.Search - repository error", "error", err)
 		return nil, err
 	}
-	logger.Debug(ctx, "service: ItemService.Search - completed", "resultCount", len(results))
-	return results, nil
+	if page == nil {
+		page = &models.ItemSearchPage{}
+	}
+	logger.Debug(ctx, "service: ItemService.Search - completed", "resultCount", len(page.Items), "total", page.Total)
+	return page, nil
 }
 
 func (s *ItemService) GetByUniqueName(ctx context.Context, uniqueName string) (*models.Item, error) {
@@ -52,6 +55,7 @@ func (s *ItemService) GetByUniqueName(ctx context.Context, uniqueName string) (*
 		if err != nil {
 			logger.Error(ctx, "service: ItemService.GetByUniqueName - error checking component pages", "error", err)
 			// Don't fail the request, just skip populating HasOwnPage
+			item.MarkDegraded(models.SectionComponentPages, "component page lookup unavailable")
 		} else {
 			for i := range item.Components {
 				if _, exists := existingItems[item.Components[i].UniqueName]; exists {
@@ -73,13 +73,16 @@ func TestItemService_Search(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := &mocks.MockItemRepository{
-				SearchFunc: func(ctx context.Context, params models.SearchParams) ([]models.ItemSearchResult, error) {
-					return tt.mockReturn, tt.mockError
+				SearchFunc: func(ctx context.Context, params models.SearchParams) (*models.ItemSearchPage, error) {
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					return &models.ItemSearchPage{Items: tt.mockReturn, Total: len(tt.mockReturn)}, nil
 				},
 			}
 
 			service := NewItemService(mockRepo)
-			results, err := service.Search(context.Background(), tt.params)
+			page, err := service.Search(context.Background(), tt.params)
 
 			if tt.expectError && err == nil {
 				t.Error("expected error but got none")
@@ -87,13 +90,35 @@ func TestItemService_Search(t *testing.T) {
 			if !tt.expectError && err != nil {
 				t.Errorf("unexpected error: %v", err)
 			}
-			if len(results) != tt.expectedCount {
-				t.Errorf("expected %d results, got %d", tt.expectedCount, len(results))
+			if page != nil && len(page.Items) != tt.expectedCount {
+				t.Errorf("expected %d results, got %d", tt.expectedCount, len(page.Items))
 			}
 		})
 	}
 }
 
+func TestItemService_Search_NormalizesParams(t *testing.T) {
+	var captured models.SearchParams
+	service := NewItemService(&mocks.MockItemRepository{
+		SearchFunc: func(ctx context.Context, params models.SearchParams) (*models.ItemSearchPage, error) {
+			captured = params
+			return nil, nil
+		},
+	})
+
+	page, err := service.Search(context.Background(), models.SearchParams{Query: "  ash ", Limit: 500, Offset: -3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := models.SearchParams{Query: "ash", Limit: models.MaxSearchLimit}
+	if captured != expected {
+		t.Errorf("expected params %+v, got %+v", expected, captured)
+	}
+	if page == nil || page.Total != 0 {
+		t.Errorf("expected an empty page, got %+v", page)
+	}
+}
+
 func TestItemService_GetByUniqueName(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -159,3 +184,36 @@ func TestItemService_GetByUniqueName(t *testing.T) {
 		})
 	}
 }
+
+func TestItemService_GetByUniqueName_DegradesWhenComponentLookupFails(t *testing.T) {
+	mockRepo := &mocks.MockItemRepository{
+		FindByUniqueNameFunc: func(ctx context.Context, uniqueName string) (*models.Item, error) {
+			return &models.Item{
+				UniqueName: uniqueName,
+				Name:       "Ash",
+				Components: []models.Component{
+					{UniqueName: "/Lotus/AshChassis", Name: "Chassis", ItemCount: 1},
+				},
+			}, nil
+		},
+		FindByUniqueNamesFunc: func(ctx context.Context, uniqueNames []string) (map[string]*models.Item, error) {
+			return nil, errors.New("database error")
+		},
+	}
+
+	service := NewItemService(mockRepo)
+	item, err := service.GetByUniqueName(context.Background(), "/Lotus/Ash")
+
+	if err != nil {
+		t.Fatalf("expected core data without error, got %v", err)
+	}
+	if item == nil {
+		t.Fatal("expected item but got nil")
+	}
+	if !item.IsDegraded() {
+		t.Fatal("expected item to be marked degraded")
+	}
+	if item.Degraded[0].Section != models.SectionComponentPages {
+		t.Errorf("expected degraded section '%s', got '%s'", models.SectionComponentPages, item.Degraded[0].Section)
+	}
+}
@@ -0,0 +1,286 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+)
+
+// MaxMasteryItemsPerRequest bounds a bulk update.
+const MaxMasteryItemsPerRequest = 500
+
+var (
+	ErrInvalidMasteryStatus = fmt.Errorf("status must be %q or %q", models.MasteryStatusBuilt, models.MasteryStatusMastered)
+	ErrTooManyMasteryItems  = fmt.Errorf("at most %d items can be set at once", MaxMasteryItemsPerRequest)
+	ErrItemNotInProfile     = errors.New("item not in mastery profile")
+)
+
+// MasteryService manages the items a user has already built or mastered, and
+// reports their progress against every buildable item that gives mastery.
+// The buildable items are indexed from the catalog on first use and the
+// index is dropped by Invalidate after a data sync.
+type MasteryService struct {
+	masteryRepo repository.MasteryRepositoryInterface
+	itemRepo    repository.ItemRepositoryInterface
+	catalog     repository.ItemCatalogInterface
+
+	mu    sync.Mutex
+	index *masteryIndex
+}
+
+// masteryIndex maps each buildable item that gives mastery to its
+// collection.
+type masteryIndex struct {
+	collections map[string]string
+	buildable   map[string]int
+}
+
+func NewMasteryService(masteryRepo repository.MasteryRepositoryInterface, itemRepo repository.ItemRepositoryInterface, catalog repository.ItemCatalogInterface) *MasteryService {
+	return &MasteryService{
+		masteryRepo: masteryRepo,
+		itemRepo:    itemRepo,
+		catalog:     catalog,
+	}
+}
+
+// Invalidate drops the buildable item index so the next summary rebuilds it
+// from the catalog.
+func (s *MasteryService) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.index = nil
+}
+
+func (s *MasteryService) GetProfile(ctx context.Context, userID string) (*models.MasteryProfile, error) {
+	logger.Debug(ctx, "service: MasteryService.GetProfile called", "userID", userID)
+
+	entries, err := s.masteryRepo.ListByUser(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "service: MasteryService.GetProfile - repository error", "error", err)
+		return nil, err
+	}
+
+	logger.Debug(ctx, "service: MasteryService.GetProfile - completed", "entryCount", len(entries))
+	return &models.MasteryProfile{
+		UserID:  userID,
+		Entries: entries,
+	}, nil
+}
+
+func (s *MasteryService) SetStatus(ctx context.Context, userID, uniqueName, status string) error {
+	logger.Debug(ctx, "service: MasteryService.SetStatus called", "userID", userID, "uniqueName", uniqueName, "status", status)
+
+	if !validMasteryStatus(status) {
+		logger.Warn(ctx, "service: MasteryService.SetStatus - invalid status", "status", status)
+		return ErrInvalidMasteryStatus
+	}
+
+	item, err := s.itemRepo.FindByUniqueName(ctx, uniqueName)
+	if err != nil {
+		logger.Error(ctx, "service: MasteryService.SetStatus - error finding item", "error", err)
+		return err
+	}
+	if item == nil {
+		logger.Warn(ctx, "service: MasteryService.SetStatus - item not found", "uniqueName", uniqueName)
+		return ErrItemNotFound
+	}
+
+	if err := s.masteryRepo.SetStatuses(ctx, userID, map[string]string{uniqueName: status}); err != nil {
+		logger.Error(ctx, "service: MasteryService.SetStatus - error setting status", "error", err)
+		return err
+	}
+
+	logger.Info(ctx, "service: MasteryService.SetStatus - status set successfully", "uniqueName", uniqueName, "status", status)
+	return nil
+}
+
+// SetStatuses applies every status in req in one write. The whole request is
+// validated first so a bad entry never leaves a partial update, and every bad
+// entry is reported in one ValidationError; if an item is listed more than
+// once, the last status wins.
+func (s *MasteryService) SetStatuses(ctx context.Context, userID string, req models.SetMasteryRequest) error {
+	logger.Debug(ctx, "service: MasteryService.SetStatuses called", "userID", userID, "count", len(req.Items))
+
+	if len(req.Items) > MaxMasteryItemsPerRequest {
+		logger.Warn(ctx, "service: MasteryService.SetStatuses - too many items", "count", len(req.Items))
+		return ErrTooManyMasteryItems
+	}
+
+	statuses := make(map[string]string, len(req.Items))
+	var added []string
+	addedAt := make(map[string]int)
+	var problems ValidationError
+	for i, item := range req.Items {
+		uniqueName, err := models.CanonicalUniqueName(item.UniqueName)
+		if err != nil {
+			logger.Warn(ctx, "service: MasteryService.SetStatuses - invalid uniqueName", "uniqueName", item.UniqueName, "error", err)
+			problems.Add(fmt.Sprintf("items[%d].uniqueName", i), item.UniqueName, err)
+			continue
+		}
+		if item.Status != "" && !validMasteryStatus(item.Status) {
+			logger.Warn(ctx, "service: MasteryService.SetStatuses - invalid status", "uniqueName", uniqueName, "status", item.Status)
+			problems.Add(fmt.Sprintf("items[%d].status", i), uniqueName, ErrInvalidMasteryStatus)
+			continue
+		}
+		if item.Status != "" {
+			if _, ok := addedAt[uniqueName]; !ok {
+				added = append(added, uniqueName)
+			}
+			addedAt[uniqueName] = i
+		}
+		statuses[uniqueName] = item.Status
+	}
+
+	if len(statuses) == 0 && len(problems.Problems) == 0 {
+		logger.Debug(ctx, "service: MasteryService.SetStatuses - empty request, nothing to do")
+		return nil
+	}
+
+	if len(added) > 0 {
+		found, err := s.itemRepo.FindByUniqueNames(ctx, added)
+		if err != nil {
+			logger.Error(ctx, "service: MasteryService.SetStatuses - error finding items", "error", err)
+			return err
+		}
+		for _, uniqueName := range added {
+			if found[uniqueName] == nil {
+				logger.Warn(ctx, "service: MasteryService.SetStatuses - item not found", "uniqueName", uniqueName)
+				problems.Add(fmt.Sprintf("items[%d].uniqueName", addedAt[uniqueName]), uniqueName, fmt.Errorf("%w: %s", ErrItemNotFound, uniqueName))
+			}
+		}
+	}
+	if err := problems.Err(); err != nil {
+		return err
+	}
+
+	if err := s.masteryRepo.SetStatuses(ctx, userID, statuses); err != nil {
+		logger.Error(ctx, "service: MasteryService.SetStatuses - error setting statuses", "error", err)
+		return err
+	}
+
+	logger.Info(ctx, "service: MasteryService.SetStatuses - statuses set successfully", "count", len(statuses))
+	return nil
+}
+
+func (s *MasteryService) RemoveItem(ctx context.Context, userID, uniqueName string) error {
+	logger.Debug(ctx, "service: MasteryService.RemoveItem called", "userID", userID, "uniqueName", uniqueName)
+
+	entry, err := s.masteryRepo.Get(ctx, userID, uniqueName)
+	if err != nil {
+		logger.Error(ctx, "service: MasteryService.RemoveItem - error fetching entry", "error", err)
+		return err
+	}
+	if entry == nil {
+		logger.Warn(ctx, "service: MasteryService.RemoveItem - item not in profile", "uniqueName", uniqueName)
+		return ErrItemNotInProfile
+	}
+
+	if err := s.masteryRepo.SetStatuses(ctx, userID, map[string]string{uniqueName: ""}); err != nil {
+		logger.Error(ctx, "service: MasteryService.RemoveItem - error removing item", "error", err)
+		return err
+	}
+
+	logger.Info(ctx, "service: MasteryService.RemoveItem - item removed successfully", "uniqueName", uniqueName)
+	return nil
+}
+
+func (s *MasteryService) ClearAll(ctx context.Context, userID string) error {
+	logger.Debug(ctx, "service: MasteryService.ClearAll called", "userID", userID)
+
+	if err := s.masteryRepo.ClearAll(ctx, userID); err != nil {
+		logger.Error(ctx, "service: MasteryService.ClearAll - error clearing profile", "error", err)
+		return err
+	}
+
+	logger.Info(ctx, "service: MasteryService.ClearAll - profile cleared successfully")
+	return nil
+}
+
+// GetSummary counts the user's built and mastered items against the
+// buildable items of each mastery collection. Entries for items that are no
+// longer buildable, or never gave mastery, are left out of the counts.
+func (s *MasteryService) GetSummary(ctx context.Context, userID string) (*models.MasterySummary, error) {
+	logger.Debug(ctx, "service: MasteryService.GetSummary called", "userID", userID)
+
+	index, err := s.loadIndex(ctx)
+	if err != nil {
+		logger.Error(ctx, "service: MasteryService.GetSummary - error indexing buildable items", "error", err)
+		return nil, err
+	}
+	entries, err := s.masteryRepo.ListByUser(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "service: MasteryService.GetSummary - repository error", "error", err)
+		return nil, err
+	}
+
+	categories := make(map[string]*models.MasteryCategorySummary, len(index.buildable))
+	for collection, buildable := range index.buildable {
+		categories[collection] = &models.MasteryCategorySummary{Collection: collection, Buildable: buildable}
+	}
+	summary := &models.MasterySummary{UserID: userID, Categories: []models.MasteryCategorySummary{}}
+	for _, entry := range entries {
+		collection, ok := index.collections[entry.UniqueName]
+		if !ok {
+			continue
+		}
+		category := categories[collection]
+		category.Built++
+		summary.Built++
+		if entry.Status == models.MasteryStatusMastered {
+			category.Mastered++
+			summary.Mastered++
+		}
+	}
+	for _, collection := range models.MasteryCollections {
+		if category, ok := categories[collection]; ok {
+			summary.Buildable += category.Buildable
+			summary.Categories = append(summary.Categories, *category)
+		}
+	}
+	if summary.Buildable > 0 {
+		summary.MasteredPercent = math.Round(float64(summary.Mastered)*1000/float64(summary.Buildable)) / 10
+	}
+
+	logger.Debug(ctx, "service: MasteryService.GetSummary - completed", "buildable", summary.Buildable, "built", summary.Built, "mastered", summary.Mastered)
+	return summary, nil
+}
+
+func (s *MasteryService) loadIndex(ctx context.Context) (*masteryIndex, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.index != nil {
+		return s.index, nil
+	}
+
+	masteryCollections := make(map[string]bool, len(models.MasteryCollections))
+	for _, collection := range models.MasteryCollections {
+		masteryCollections[collection] = true
+	}
+	index := &masteryIndex{collections: make(map[string]string), buildable: make(map[string]int)}
+	err := s.catalog.ForEachItem(ctx, func(item models.Item) error {
+		if item.Archived || len(item.Components) == 0 || !masteryCollections[item.Collection] {
+			return nil
+		}
+		index.collections[item.UniqueName] = item.Collection
+		index.buildable[item.Collection]++
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info(ctx, "service: MasteryService - buildable item index built", "itemCount", len(index.collections))
+	s.index = index
+	return index, nil
+}
+
+func validMasteryStatus(status string) bool {
+	return status == models.MasteryStatusBuilt || status == models.MasteryStatusMastered
+}
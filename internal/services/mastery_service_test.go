@@ -0,0 +1,245 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository/memory"
+)
+
+func newTestMasteryItems() *memory.ItemRepository {
+	recipe := []models.Component{{UniqueName: "/Lotus/Types/Items/MiscItems/OrokinCell", ItemCount: 1}}
+	items := memory.NewItemRepository()
+	items.Add("warframes",
+		models.Item{UniqueName: "/Lotus/Powersuits/Excalibur", Name: "Excalibur", Components: recipe},
+		models.Item{UniqueName: "/Lotus/Powersuits/Ash", Name: "Ash", Components: recipe},
+		models.Item{UniqueName: "/Lotus/Powersuits/Old", Name: "Old", Components: recipe, Archived: true},
+	)
+	items.Add("primary",
+		models.Item{UniqueName: "/Lotus/Weapons/Braton", Name: "Braton", Components: recipe},
+		models.Item{UniqueName: "/Lotus/Weapons/MarketOnly", Name: "Market Only"},
+	)
+	items.Add("misc", models.Item{UniqueName: "/Lotus/Types/Items/MiscItems/OrokinCell", Name: "Orokin Cell"})
+	return items
+}
+
+func TestMasteryService_SetStatus(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewMasteryRepository()
+	service := NewMasteryService(repo, newTestMasteryItems(), newTestMasteryItems())
+
+	if err := service.SetStatus(ctx, "user-123", "/Lotus/Powersuits/Excalibur", models.MasteryStatusMastered); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	entry, _ := repo.Get(ctx, "user-123", "/Lotus/Powersuits/Excalibur")
+	if entry == nil || entry.Status != models.MasteryStatusMastered {
+		t.Errorf("expected Excalibur mastered, got %+v", entry)
+	}
+
+	if err := service.SetStatus(ctx, "user-123", "/Lotus/Powersuits/Excalibur", "owned"); !errors.Is(err, ErrInvalidMasteryStatus) {
+		t.Errorf("expected ErrInvalidMasteryStatus, got %v", err)
+	}
+	if err := service.SetStatus(ctx, "user-123", "/Lotus/Nope", models.MasteryStatusBuilt); !errors.Is(err, ErrItemNotFound) {
+		t.Errorf("expected ErrItemNotFound, got %v", err)
+	}
+}
+
+func TestMasteryService_SetStatuses(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		items       []models.MasteryStatus
+		expectedErr error
+		expected    map[string]string
+	}{
+		{
+			name: "sets and removes",
+			items: []models.MasteryStatus{
+				{UniqueName: "/Lotus/Powersuits/Ash", Status: models.MasteryStatusBuilt},
+				{UniqueName: "/Lotus/Powersuits/Excalibur", Status: ""},
+			},
+			expected: map[string]string{"/Lotus/Powersuits/Ash": models.MasteryStatusBuilt},
+		},
+		{
+			name:        "invalid status rejects the whole request",
+			items:       []models.MasteryStatus{{UniqueName: "/Lotus/Powersuits/Ash", Status: models.MasteryStatusBuilt}, {UniqueName: "/Lotus/Weapons/Braton", Status: "owned"}},
+			expectedErr: ErrInvalidMasteryStatus,
+			expected:    map[string]string{"/Lotus/Powersuits/Excalibur": models.MasteryStatusBuilt},
+		},
+		{
+			name:        "unknown item rejects the whole request",
+			items:       []models.MasteryStatus{{UniqueName: "/Lotus/Powersuits/Ash", Status: models.MasteryStatusBuilt}, {UniqueName: "/Lotus/Nope", Status: models.MasteryStatusBuilt}},
+			expectedErr: ErrItemNotFound,
+			expected:    map[string]string{"/Lotus/Powersuits/Excalibur": models.MasteryStatusBuilt},
+		},
+		{
+			name:        "invalid uniqueName",
+			items:       []models.MasteryStatus{{UniqueName: "", Status: models.MasteryStatusBuilt}},
+			expectedErr: models.ErrUniqueNameRequired,
+			expected:    map[string]string{"/Lotus/Powersuits/Excalibur": models.MasteryStatusBuilt},
+		},
+		{
+			name:        "too many items",
+			items:       make([]models.MasteryStatus, MaxMasteryItemsPerRequest+1),
+			expectedErr: ErrTooManyMasteryItems,
+			expected:    map[string]string{"/Lotus/Powersuits/Excalibur": models.MasteryStatusBuilt},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := memory.NewMasteryRepository()
+			repo.SetStatuses(ctx, "user-123", map[string]string{"/Lotus/Powersuits/Excalibur": models.MasteryStatusBuilt})
+			service := NewMasteryService(repo, newTestMasteryItems(), newTestMasteryItems())
+
+			err := service.SetStatuses(ctx, "user-123", models.SetMasteryRequest{Items: tt.items})
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			entries, _ := repo.ListByUser(ctx, "user-123")
+			got := make(map[string]string, len(entries))
+			for _, entry := range entries {
+				got[entry.UniqueName] = entry.Status
+			}
+			if len(got) != len(tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, got)
+			}
+			for uniqueName, status := range tt.expected {
+				if got[uniqueName] != status {
+					t.Errorf("expected %s %q, got %q", uniqueName, status, got[uniqueName])
+				}
+			}
+		})
+	}
+}
+
+func TestMasteryService_SetStatuses_ReportsEveryProblem(t *testing.T) {
+	ctx := context.Background()
+	service := NewMasteryService(memory.NewMasteryRepository(), newTestMasteryItems(), newTestMasteryItems())
+
+	err := service.SetStatuses(ctx, "user-123", models.SetMasteryRequest{Items: []models.MasteryStatus{
+		{UniqueName: "/Lotus/Powersuits/Ash", Status: "owned"},
+		{UniqueName: "/Lotus/Powersuits/Excalibur", Status: models.MasteryStatusBuilt},
+		{UniqueName: "/Lotus/Nope", Status: models.MasteryStatusBuilt},
+		{UniqueName: "", Status: models.MasteryStatusBuilt},
+	}})
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected a ValidationError, got %v", err)
+	}
+	want := []models.ValidationProblem{
+		{Field: "items[0].status", UniqueName: "/Lotus/Powersuits/Ash", Reason: ErrInvalidMasteryStatus.Error()},
+		{Field: "items[3].uniqueName", UniqueName: "", Reason: models.ErrUniqueNameRequired.Error()},
+		{Field: "items[2].uniqueName", UniqueName: "/Lotus/Nope", Reason: "item not found: /Lotus/Nope"},
+	}
+	if len(validationErr.Problems) != len(want) {
+		t.Fatalf("expected %d problems, got %+v", len(want), validationErr.Problems)
+	}
+	for i, problem := range want {
+		if validationErr.Problems[i] != problem {
+			t.Errorf("problem %d: expected %+v, got %+v", i, problem, validationErr.Problems[i])
+		}
+	}
+}
+
+func TestMasteryService_RemoveItem(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewMasteryRepository()
+	repo.SetStatuses(ctx, "user-123", map[string]string{"/Lotus/Powersuits/Excalibur": models.MasteryStatusBuilt})
+	service := NewMasteryService(repo, newTestMasteryItems(), newTestMasteryItems())
+
+	if err := service.RemoveItem(ctx, "user-123", "/Lotus/Powersuits/Excalibur"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := service.RemoveItem(ctx, "user-123", "/Lotus/Powersuits/Excalibur"); !errors.Is(err, ErrItemNotInProfile) {
+		t.Errorf("expected ErrItemNotInProfile, got %v", err)
+	}
+}
+
+func TestMasteryService_GetSummary(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewMasteryRepository()
+	catalog := newTestMasteryItems()
+	service := NewMasteryService(repo, catalog, catalog)
+
+	repo.SetStatuses(ctx, "user-123", map[string]string{
+		"/Lotus/Powersuits/Excalibur": models.MasteryStatusMastered,
+		"/Lotus/Weapons/Braton":       models.MasteryStatusBuilt,
+		// Neither counts: one is archived, the other never needs building.
+		"/Lotus/Powersuits/Old":     models.MasteryStatusMastered,
+		"/Lotus/Weapons/MarketOnly": models.MasteryStatusMastered,
+	})
+
+	summary, err := service.GetSummary(ctx, "user-123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if summary.Buildable != 3 || summary.Built != 2 || summary.Mastered != 1 || summary.MasteredPercent != 33.3 {
+		t.Errorf("unexpected totals %+v", summary)
+	}
+	if len(summary.Categories) != 2 || summary.Categories[0].Collection != "warframes" || summary.Categories[1].Collection != "primary" {
+		t.Fatalf("expected warframes then primary, got %+v", summary.Categories)
+	}
+	if got := summary.Categories[0]; got.Buildable != 2 || got.Built != 1 || got.Mastered != 1 {
+		t.Errorf("unexpected warframes progress %+v", got)
+	}
+
+	// The index is cached until invalidated.
+	catalog.Add("warframes", models.Item{UniqueName: "/Lotus/Powersuits/Mag", Name: "Mag", Components: []models.Component{{UniqueName: "/Lotus/Types/Items/MiscItems/OrokinCell", ItemCount: 1}}})
+	summary, _ = service.GetSummary(ctx, "user-123")
+	if summary.Buildable != 3 {
+		t.Errorf("expected the cached index, got %d buildable", summary.Buildable)
+	}
+	service.Invalidate()
+	summary, _ = service.GetSummary(ctx, "user-123")
+	if summary.Buildable != 4 {
+		t.Errorf("expected the rebuilt index, got %d buildable", summary.Buildable)
+	}
+
+	empty, err := service.GetSummary(ctx, "other-user")
+	if err != nil || empty.Built != 0 || empty.MasteredPercent != 0 || len(empty.Categories) != 2 {
+		t.Errorf("expected no progress for a new user, got %+v (err %v)", empty, err)
+	}
+}
+
+func TestWishlistService_AddItem_AlreadyOwned(t *testing.T) {
+	ctx := context.Background()
+	items := newTestMasteryItems()
+	mastery := memory.NewMasteryRepository()
+	mastery.SetStatuses(ctx, "user-123", map[string]string{"/Lotus/Powersuits/Excalibur": models.MasteryStatusBuilt})
+	wishlists := memory.NewWishlistRepository()
+	service := NewWishlistService(wishlists, items)
+	service.SetMasteryRepository(mastery)
+
+	err := service.AddItem(ctx, "user-123", models.AddItemRequest{UniqueName: "/Lotus/Powersuits/Excalibur"})
+	var ownedErr *ItemAlreadyOwnedError
+	if !errors.Is(err, ErrItemAlreadyOwned) || !errors.As(err, &ownedErr) || ownedErr.Entry.Status != models.MasteryStatusBuilt {
+		t.Fatalf("expected ItemAlreadyOwnedError, got %v", err)
+	}
+	if wishlist, _ := wishlists.GetByUserID(ctx, "user-123"); wishlist != nil {
+		t.Errorf("expected nothing added, got %+v", wishlist)
+	}
+
+	if err := service.AddItem(ctx, "user-123", models.AddItemRequest{UniqueName: "/Lotus/Powersuits/Excalibur", AllowOwned: true}); err != nil {
+		t.Errorf("expected allowOwned to add the item, got %v", err)
+	}
+	if err := service.AddItem(ctx, "user-123", models.AddItemRequest{UniqueName: "/Lotus/Powersuits/Ash"}); err != nil {
+		t.Errorf("expected an item not in the profile to be added, got %v", err)
+	}
+
+	failing := NewWishlistService(wishlists, items)
+	failing.SetMasteryRepository(&mocks.MockMasteryRepository{
+		GetFunc: func(ctx context.Context, userID, uniqueName string) (*models.MasteryEntry, error) {
+			return nil, errors.New("database down")
+		},
+	})
+	if err := failing.AddItem(ctx, "user-123", models.AddItemRequest{UniqueName: "/Lotus/Weapons/Braton"}); err == nil {
+		t.Error("expected the repository error")
+	}
+}
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"context"
+
+	"github.com/graytonio/warframe-wishlist/internal/hooks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+// HookedMaterialResolver raises hooks.EventMaterialsResolved for each
+// materials response of another resolver, and returns it with the hooks'
+// enrichments as its Extensions. The response is copied before it is
+// enriched, since the resolver behind may share it with a cache.
+type HookedMaterialResolver struct {
+	next  MaterialResolverInterface
+	hooks *hooks.Registry
+}
+
+func NewHookedMaterialResolver(next MaterialResolverInterface, registry *hooks.Registry) *HookedMaterialResolver {
+	return &HookedMaterialResolver{next: next, hooks: registry}
+}
+
+func (r *HookedMaterialResolver) GetMaterials(ctx context.Context, userID string) (*models.MaterialsResponse, error) {
+	materials, err := r.next.GetMaterials(ctx, userID)
+	if err != nil || materials == nil || !r.hooks.Has(hooks.EventMaterialsResolved) {
+		return materials, err
+	}
+
+	extensions := r.hooks.Emit(ctx, hooks.Event{Name: hooks.EventMaterialsResolved, UserID: userID, Data: materials})
+	if extensions == nil {
+		return materials, nil
+	}
+	enriched := *materials
+	enriched.Extensions = extensions
+	return &enriched, nil
+}
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/hooks"
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+func TestHookedMaterialResolver(t *testing.T) {
+	shared := &models.MaterialsResponse{TotalCredits: 100}
+	next := &mocks.MockMaterialResolver{
+		GetMaterialsFunc: func(ctx context.Context, userID string) (*models.MaterialsResponse, error) {
+			if userID == "broken" {
+				return nil, errors.New("database error")
+			}
+			return shared, nil
+		},
+	}
+	ctx := context.Background()
+
+	// Without hooks the response passes through untouched.
+	if materials, err := NewHookedMaterialResolver(next, hooks.NewRegistry()).GetMaterials(ctx, "user-123"); err != nil || materials != shared {
+		t.Fatalf("expected the response unchanged, got %+v (err %v)", materials, err)
+	}
+
+	registry := hooks.NewRegistry()
+	registry.Register("credits", hooks.EventMaterialsResolved, func(ctx context.Context, event hooks.Event) (any, error) {
+		materials := event.Data.(*models.MaterialsResponse)
+		return map[string]int{"doubled": materials.TotalCredits * 2}, nil
+	})
+	registry.Register("quiet", hooks.EventMaterialsResolved, func(ctx context.Context, event hooks.Event) (any, error) {
+		return nil, nil
+	})
+	resolver := NewHookedMaterialResolver(next, registry)
+
+	materials, err := resolver.GetMaterials(ctx, "user-123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(materials.Extensions) != 1 || materials.Extensions["credits"].(map[string]int)["doubled"] != 200 || materials.TotalCredits != 100 {
+		t.Errorf("expected the credits enrichment only, got %+v", materials)
+	}
+	if shared.Extensions != nil {
+		t.Error("expected the shared response not to be modified")
+	}
+
+	if _, err := resolver.GetMaterials(ctx, "broken"); err == nil {
+		t.Error("expected the resolver's error to be returned")
+	}
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+)
+
+// MaterialLimits bound a single materials resolution so that a pathological
+// recipe tree returns a partial, truncated result instead of hanging the
+// request. A zero field disables that limit.
+type MaterialLimits struct {
+	// MaxDepth is how many recipe levels below a wishlist item are followed.
+	MaxDepth int
+	// MaxMaterials is how many distinct materials a response may hold.
+	MaxMaterials int
+	// MaxDuration is the wall time a resolution may take.
+	MaxDuration time.Duration
+}
+
+// MaterialLimitHits counts the resolutions each limit has truncated since the
+// resolver was created.
+type MaterialLimitHits struct {
+	Depth     int64
+	Materials int64
+	Time      int64
+}
+
+// resolveBudget enforces MaterialLimits over one GetMaterials call. A nil
+// budget enforces nothing. The resolution is single-threaded, so depth is
+// tracked by entering and leaving items.
+type resolveBudget struct {
+	limits   MaterialLimits
+	deadline time.Time
+	depth    int
+	// reason is the first limit hit, empty while the resolution is complete.
+	reason string
+}
+
+func newResolveBudget(limits MaterialLimits, now time.Time) *resolveBudget {
+	budget := &resolveBudget{limits: limits}
+	if limits.MaxDuration > 0 {
+		budget.deadline = now.Add(limits.MaxDuration)
+	}
+	return budget
+}
+
+// enter reports whether an item may be resolved one level deeper, checking the
+// depth and time limits. Every successful enter must be paired with leave.
+func (b *resolveBudget) enter() bool {
+	if b == nil {
+		return true
+	}
+	if b.reason != "" {
+		return false
+	}
+	if !b.deadline.IsZero() && time.Now().After(b.deadline) {
+		b.reason = models.TruncatedTime
+		return false
+	}
+	// The wishlist item itself is depth 0.
+	if b.limits.MaxDepth > 0 && b.depth > b.limits.MaxDepth {
+		b.reason = models.TruncatedDepth
+		return false
+	}
+	b.depth++
+	return true
+}
+
+func (b *resolveBudget) leave() {
+	if b != nil {
+		b.depth--
+	}
+}
+
+// allowMaterial reports whether uniqueName may be counted, refusing a new
+// material once the response holds MaxMaterials of them.
+func (b *resolveBudget) allowMaterial(materialCounts map[string]int, uniqueName string) bool {
+	if b == nil || b.limits.MaxMaterials <= 0 {
+		return true
+	}
+	if _, counted := materialCounts[uniqueName]; counted || len(materialCounts) < b.limits.MaxMaterials {
+		return true
+	}
+	if b.reason == "" {
+		b.reason = models.TruncatedMaterials
+	}
+	return false
+}
+
+// stopped reports whether a limit has been hit.
+func (b *resolveBudget) stopped() bool {
+	return b != nil && b.reason != ""
+}
+
+// recordTruncation counts and logs a resolution cut short by a limit.
+func (r *MaterialResolver) recordTruncation(ctx context.Context, userID, reason string) {
+	var hits int64
+	switch reason {
+	case models.TruncatedDepth:
+		hits = r.depthLimitHits.Add(1)
+	case models.TruncatedMaterials:
+		hits = r.materialLimitHits.Add(1)
+	case models.TruncatedTime:
+		hits = r.timeLimitHits.Add(1)
+	}
+	logger.Warn(ctx, "service: MaterialResolver.GetMaterials - resolution limit hit, returning partial result", "userID", userID, "reason", reason, "hits", hits)
+}
+
+// LimitHits returns how many resolutions each limit has truncated.
+func (r *MaterialResolver) LimitHits() MaterialLimitHits {
+	return MaterialLimitHits{
+		Depth:     r.depthLimitHits.Load(),
+		Materials: r.materialLimitHits.Load(),
+		Time:      r.timeLimitHits.Load(),
+	}
+}
@@ -3,6 +3,8 @@ package services
 import (
 	"context"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/graytonio/warframe-wishlist/internal/models"
 	"github.com/graytonio/warframe-wishlist/internal/repository"
@@ -13,16 +15,74 @@ type MaterialResolver struct {
 	itemRepo     repository.ItemRepositoryInterface
 	wishlistRepo repository.WishlistRepositoryInterface
 	ownedBPRepo  repository.OwnedBlueprintsRepositoryInterface
+	// ownedMaterialsRepo is optional; without it every material's remaining
+	// count equals its total.
+	ownedMaterialsRepo repository.OwnedMaterialsRepositoryInterface
+	// customItemRepo is optional; without it custom items on a wishlist are
+	// skipped like items missing from the game data.
+	customItemRepo repository.CustomItemRepositoryInterface
+	// itemGraph is optional; with it the component tree is fetched in one
+	// graph lookup instead of one lookup per recipe level.
+	itemGraph repository.ItemGraphInterface
+	// settingsRepo is optional; without it every user's materials are
+	// rounded strictly.
+	settingsRepo repository.SettingsRepositoryInterface
+
+	limits            MaterialLimits
+	depthLimitHits    atomic.Int64
+	materialLimitHits atomic.Int64
+	timeLimitHits     atomic.Int64
 }
 
-func NewMaterialResolver(itemRepo repository.ItemRepositoryInterface, wishlistRepo repository.WishlistRepositoryInterface, ownedBPRepo repository.OwnedBlueprintsRepositoryInterface) *MaterialResolver {
+func NewMaterialResolver(itemRepo repository.ItemRepositoryInterface, wishlistRepo repository.WishlistRepositoryInterface, ownedBPRepo repository.OwnedBlueprintsRepositoryInterface, ownedMaterialsRepo repository.OwnedMaterialsRepositoryInterface) *MaterialResolver {
 	return &MaterialResolver{
-		itemRepo:     itemRepo,
-		wishlistRepo: wishlistRepo,
-		ownedBPRepo:  ownedBPRepo,
+		itemRepo:           itemRepo,
+		wishlistRepo:       wishlistRepo,
+		ownedBPRepo:        ownedBPRepo,
+		ownedMaterialsRepo: ownedMaterialsRepo,
 	}
 }
 
+// SetLimits bounds every later resolution. Without limits a resolution runs to
+// completion however large the recipe tree is.
+func (r *MaterialResolver) SetLimits(limits MaterialLimits) {
+	r.limits = limits
+}
+
+// SetCustomItemRepository makes resolutions include the user's custom items,
+// both on the wishlist and as components.
+func (r *MaterialResolver) SetCustomItemRepository(customItemRepo repository.CustomItemRepositoryInterface) {
+	r.customItemRepo = customItemRepo
+}
+
+// SetItemGraph makes resolutions fetch the component tree from itemGraph.
+// Anything the graph misses, such as custom items and their components, is
+// still looked up level by level, so the result is the same either way.
+func (r *MaterialResolver) SetItemGraph(itemGraph repository.ItemGraphInterface) {
+	r.itemGraph = itemGraph
+}
+
+// SetSettingsRepository makes resolutions follow each user's materials
+// rounding setting.
+func (r *MaterialResolver) SetSettingsRepository(settingsRepo repository.SettingsRepositoryInterface) {
+	r.settingsRepo = settingsRepo
+}
+
+// poolsSurplus reports whether userID shares the surplus of batch crafts
+// across their wishlist. The setting only changes how intermediates are
+// rounded, so when it cannot be read the totals are rounded strictly.
+func (r *MaterialResolver) poolsSurplus(ctx context.Context, userID string) bool {
+	if r.settingsRepo == nil {
+		return false
+	}
+	settings, err := r.settingsRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		logger.Warn(ctx, "service: MaterialResolver.GetMaterials - error fetching settings, rounding strictly", "error", err)
+		return false
+	}
+	return settings.PoolsCraftSurplus()
+}
+
 func (r *MaterialResolver) GetMaterials(ctx context.Context, userID string) (*models.MaterialsResponse, error) {
 	logger.Debug(ctx, "service: MaterialResolver.GetMaterials called", "userID", userID)
 
@@ -37,25 +97,43 @@ func (r *MaterialResolver) GetMaterials(ctx context.Context, userID string) (*mo
 		return &models.MaterialsResponse{
 			Materials:    []models.MaterialRequirement{},
 			TotalCredits: 0,
+			RushPlatinum: 0,
 		}, nil
 	}
 
-	// Fetch owned blueprints to exclude from materials
+	// Fetch owned blueprints and components to exclude from materials. Owned
+	// parts only refine the totals, so a lookup failure degrades the response
+	// rather than failing it. Stocked consumable blueprints are used up by
+	// builds just like owned components, so they share the components' counts.
+	var degradation models.Degradation
 	ownedBlueprintsSet := make(map[string]bool)
+	ownedComponents := make(map[string]int)
 	if r.ownedBPRepo != nil {
 		ownedBP, err := r.ownedBPRepo.GetByUserID(ctx, userID)
 		if err != nil {
-			logger.Error(ctx, "service: MaterialResolver.GetMaterials - error fetching owned blueprints", "error", err)
-			return nil, err
-		}
-		if ownedBP != nil {
+			logger.Error(ctx, "service: MaterialResolver.GetMaterials - error fetching owned blueprints, continuing without them", "error", err)
+			degradation.MarkDegraded(models.SectionOwnedBlueprints, "owned blueprints unavailable; totals include all blueprints")
+		} else if ownedBP != nil {
 			for _, bp := range ownedBP.Blueprints {
 				ownedBlueprintsSet[bp.UniqueName] = true
+				ownedComponents[bp.UniqueName] += bp.Quantity
 			}
-			logger.Debug(ctx, "service: MaterialResolver.GetMaterials - fetched owned blueprints", "count", len(ownedBP.Blueprints))
+			for _, component := range ownedBP.Components {
+				ownedComponents[component.UniqueName] += component.Count
+			}
+			logger.Debug(ctx, "service: MaterialResolver.GetMaterials - fetched owned blueprints", "count", len(ownedBP.Blueprints), "componentCount", len(ownedBP.Components))
 		}
 	}
 
+	// Custom items are looked up alongside the game items. Without them the
+	// rest of the wishlist still resolves, so a failure degrades the response.
+	itemRepo, err := itemsForUser(ctx, r.itemRepo, r.customItemRepo, userID)
+	if err != nil {
+		logger.Error(ctx, "service: MaterialResolver.GetMaterials - error fetching custom items, continuing without them", "error", err)
+		degradation.MarkDegraded(models.SectionCustomItems, "custom items unavailable; they are left out of the totals")
+		itemRepo = r.itemRepo
+	}
+
 	logger.Debug(ctx, "service: MaterialResolver.GetMaterials - processing wishlist items", "itemCount", len(wishlist.Items))
 
 	uniqueNames := make([]string, len(wishlist.Items))
@@ -66,20 +144,57 @@ func (r *MaterialResolver) GetMaterials(ctx context.Context, userID string) (*mo
 	}
 
 	logger.Debug(ctx, "service: MaterialResolver.GetMaterials - fetching item details")
-	items, err := r.itemRepo.FindByUniqueNames(ctx, uniqueNames)
+	items, err := itemRepo.FindByUniqueNames(ctx, uniqueNames)
 	if err != nil {
 		logger.Error(ctx, "service: MaterialResolver.GetMaterials - error fetching items", "error", err)
 		return nil, err
 	}
 	logger.Debug(ctx, "service: MaterialResolver.GetMaterials - fetched item details", "foundCount", len(items))
 
+	// Resolve each item with its preferred recipe. A recipe dropped by a data
+	// sync falls back to the default one.
+	for _, wishlistItem := range wishlist.Items {
+		item, exists := items[wishlistItem.UniqueName]
+		if !exists || wishlistItem.RecipeID == "" {
+			continue
+		}
+		if withRecipe, ok := item.WithRecipe(wishlistItem.RecipeID); ok {
+			items[wishlistItem.UniqueName] = withRecipe
+		} else {
+			logger.Warn(ctx, "service: MaterialResolver.GetMaterials - preferred recipe not found, using default", "uniqueName", wishlistItem.UniqueName, "recipeID", wishlistItem.RecipeID)
+		}
+	}
+
+	budget := newResolveBudget(r.limits, time.Now())
+	components := prefetchComponentsWithGraph(ctx, itemRepo, r.itemGraph, items)
+	// Components missed by the prefetch are looked up in the game items
+	// alone, so every custom item the user has must already be known.
+	if overlay, ok := itemRepo.(*customItemOverlay); ok {
+		for uniqueName, item := range overlay.custom {
+			if _, known := components[uniqueName]; !known {
+				components[uniqueName] = item
+			}
+		}
+	}
+
+	// Pooled rounding keeps the extra copies of a batch craft, such as the
+	// rest of a stack of Forma, with the owned components so later builds use
+	// them up instead of crafting their own batch.
+	var surplus map[string]int
+	if r.poolsSurplus(ctx, userID) {
+		surplus = ownedComponents
+	}
+
 	materialCounts := make(map[string]int)
 	materialInfo := make(map[string]*models.Item)
 	visited := make(map[string]bool)
 	nonConsumableCounted := make(map[string]bool) // Track non-consumable items globally
-	totalCredits := 0
+	var total buildCost
 
 	for _, wishlistItem := range wishlist.Items {
+		if budget.stopped() {
+			break
+		}
 		item, exists := items[wishlistItem.UniqueName]
 		if !exists {
 			logger.Debug(ctx, "service: MaterialResolver.GetMaterials - item not found in database, skipping", "uniqueName", wishlistItem.UniqueName)
@@ -87,12 +202,38 @@ func (r *MaterialResolver) GetMaterials(ctx context.Context, userID string) (*mo
 		}
 
 		logger.Debug(ctx, "service: MaterialResolver.GetMaterials - resolving materials for item", "uniqueName", wishlistItem.UniqueName, "quantity", wishlistItem.Quantity)
-		for i := 0; i < wishlistItem.Quantity; i++ {
+		// Components the user already has are taken off the first copies.
+		done := completedComponents(item, wishlistItem)
+		for i := 0; i < wishlistItem.Quantity && !budget.stopped(); i++ {
 			for k := range visited {
 				delete(visited, k)
 			}
-			credits := r.resolveItemInternal(ctx, item, "", 1, materialCounts, materialInfo, visited, nonConsumableCounted, ownedBlueprintsSet)
-			totalCredits += credits
+			remaining := withoutCompleted(item, done)
+			if len(remaining.Components) == 0 && len(item.Components) > 0 {
+				// Every component is done; only the final build is left.
+				total.add(buildCost{credits: item.BuildPrice, rushPlatinum: item.SkipBuildTimePrice})
+				continue
+			}
+			total.add(r.resolveItemInternal(ctx, remaining, "", 1, materialCounts, materialInfo, visited, nonConsumableCounted, ownedBlueprintsSet, ownedComponents, surplus, components, budget))
+		}
+	}
+	if budget.stopped() {
+		r.recordTruncation(ctx, userID, budget.reason)
+	}
+
+	// The inventory only lowers the remaining counts, so like owned
+	// blueprints a lookup failure degrades the response.
+	ownedCounts := make(map[string]int)
+	if r.ownedMaterialsRepo != nil {
+		owned, err := r.ownedMaterialsRepo.GetByUserID(ctx, userID)
+		if err != nil {
+			logger.Error(ctx, "service: MaterialResolver.GetMaterials - error fetching owned materials, continuing without them", "error", err)
+			degradation.MarkDegraded(models.SectionOwnedMaterials, "owned materials unavailable; remaining counts equal totals")
+		} else {
+			for _, material := range owned {
+				ownedCounts[material.UniqueName] = material.Count
+			}
+			logger.Debug(ctx, "service: MaterialResolver.GetMaterials - fetched owned materials", "count", len(owned))
 		}
 	}
 
@@ -101,6 +242,8 @@ func (r *MaterialResolver) GetMaterials(ctx context.Context, userID string) (*mo
 		mat := models.MaterialRequirement{
 			UniqueName: uniqueName,
 			TotalCount: count,
+			Owned:      ownedCounts[uniqueName],
+			Remaining:  max(count-ownedCounts[uniqueName], 0),
 		}
 
 		if info, exists := materialInfo[uniqueName]; exists {
@@ -112,17 +255,142 @@ func (r *MaterialResolver) GetMaterials(ctx context.Context, userID string) (*mo
 		materials = append(materials, mat)
 	}
 
-	logger.Info(ctx, "service: MaterialResolver.GetMaterials - completed", "materialCount", len(materials), "totalCredits", totalCredits)
+	logger.Info(ctx, "service: MaterialResolver.GetMaterials - completed", "materialCount", len(materials), "totalCredits", total.credits, "rushPlatinum", total.rushPlatinum)
 	return &models.MaterialsResponse{
-		Materials:    materials,
-		TotalCredits: totalCredits,
+		Materials:       materials,
+		TotalCredits:    total.credits,
+		RushPlatinum:    total.rushPlatinum,
+		Truncated:       budget.stopped(),
+		TruncatedReason: budget.reason,
+		Degradation:     degradation,
 	}, nil
 }
 
-func (r *MaterialResolver) resolveItem(ctx context.Context, item *models.Item, multiplier int, materialCounts map[string]int, materialInfo map[string]*models.Item, visited map[string]bool) int {
+// buildCost is what the crafts under an item cost: credits to start them and
+// platinum to rush them.
+type buildCost struct {
+	credits      int
+	rushPlatinum int
+}
+
+func (c *buildCost) add(other buildCost) {
+	c.credits += other.credits
+	c.rushPlatinum += other.rushPlatinum
+}
+
+func (r *MaterialResolver) resolveItem(ctx context.Context, item *models.Item, multiplier int, materialCounts map[string]int, materialInfo map[string]*models.Item, visited map[string]bool) buildCost {
 	nonConsumableCounted := make(map[string]bool)
 	ownedBlueprintsSet := make(map[string]bool)
-	return r.resolveItemInternal(ctx, item, "", multiplier, materialCounts, materialInfo, visited, nonConsumableCounted, ownedBlueprintsSet)
+	return r.resolveItemInternal(ctx, item, "", multiplier, materialCounts, materialInfo, visited, nonConsumableCounted, ownedBlueprintsSet, nil, nil, nil, nil)
+}
+
+// prefetchComponents loads every item reachable through the components of
+// items, one FindByUniqueNames call per level of the component tree instead of
+// one FindByUniqueName call per component. The result maps each looked-up
+// uniqueName to its item, or to nil when it is not in the database. If a level
+// fails to load, the remaining names are left out and findComponent looks
+// them up one by one.
+func prefetchComponents(ctx context.Context, itemRepo repository.ItemRepositoryInterface, items map[string]*models.Item) map[string]*models.Item {
+	return prefetchComponentsWithGraph(ctx, itemRepo, nil, items)
+}
+
+// prefetchComponentsWithGraph is prefetchComponents seeded from a single
+// FindComponentTree call when graph is set. The level walk then only looks up
+// what the graph did not hold; if the graph fails, it walks the whole tree.
+func prefetchComponentsWithGraph(ctx context.Context, itemRepo repository.ItemRepositoryInterface, graph repository.ItemGraphInterface, items map[string]*models.Item) map[string]*models.Item {
+	components := make(map[string]*models.Item, len(items))
+	level := make([]*models.Item, 0, len(items))
+	for uniqueName, item := range items {
+		components[uniqueName] = item
+		level = append(level, item)
+	}
+
+	if graph != nil {
+		tree, err := graph.FindComponentTree(ctx, componentNames(items))
+		if err != nil {
+			logger.Warn(ctx, "service: prefetchComponents - error fetching component tree, falling back to level lookups", "error", err)
+		} else {
+			logger.Debug(ctx, "service: prefetchComponents - fetched component tree", "foundCount", len(tree))
+			for uniqueName, item := range tree {
+				// Wishlist items keep their preferred recipe.
+				if _, known := components[uniqueName]; !known {
+					components[uniqueName] = item
+					level = append(level, item)
+				}
+			}
+		}
+	}
+
+	for depth := 1; len(level) > 0; depth++ {
+		var uniqueNames []string
+		seen := make(map[string]bool)
+		var collect func(entries []models.Component)
+		collect = func(entries []models.Component) {
+			for _, component := range entries {
+				if _, known := components[component.UniqueName]; !known && !seen[component.UniqueName] {
+					seen[component.UniqueName] = true
+					uniqueNames = append(uniqueNames, component.UniqueName)
+				}
+				// Embedded components are resolved in place, so their own
+				// components belong to the same pass.
+				collect(component.Components)
+			}
+		}
+		for _, item := range level {
+			collect(item.Components)
+		}
+		if len(uniqueNames) == 0 {
+			break
+		}
+
+		found, err := itemRepo.FindByUniqueNames(ctx, uniqueNames)
+		if err != nil {
+			logger.Warn(ctx, "service: prefetchComponents - error fetching components, falling back to single lookups", "depth", depth, "error", err)
+			break
+		}
+		logger.Debug(ctx, "service: prefetchComponents - fetched components", "depth", depth, "requested", len(uniqueNames), "foundCount", len(found))
+
+		level = level[:0]
+		for _, uniqueName := range uniqueNames {
+			item := found[uniqueName]
+			components[uniqueName] = item
+			if item != nil {
+				level = append(level, item)
+			}
+		}
+	}
+
+	return components
+}
+
+// componentNames lists the uniqueNames of the components of items, embedded
+// components included.
+func componentNames(items map[string]*models.Item) []string {
+	var uniqueNames []string
+	seen := make(map[string]bool)
+	var collect func(entries []models.Component)
+	collect = func(entries []models.Component) {
+		for _, component := range entries {
+			if !seen[component.UniqueName] {
+				seen[component.UniqueName] = true
+				uniqueNames = append(uniqueNames, component.UniqueName)
+			}
+			collect(component.Components)
+		}
+	}
+	for _, item := range items {
+		collect(item.Components)
+	}
+	return uniqueNames
+}
+
+// findComponent returns the prefetched item for uniqueName, looking it up
+// directly when the prefetch did not cover it.
+func findComponent(ctx context.Context, itemRepo repository.ItemRepositoryInterface, components map[string]*models.Item, uniqueName string) (*models.Item, error) {
+	if item, ok := components[uniqueName]; ok {
+		return item, nil
+	}
+	return itemRepo.FindByUniqueName(ctx, uniqueName)
 }
 
 // ceilDiv performs ceiling division: ceil(a / b)
@@ -152,20 +420,28 @@ func containsIgnoreCase(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(s) > len(substr) && (s[:len(substr)] == substr || s[len(s)-len(substr):] == substr || strings.Contains(s, substr)))
 }
 
-func (r *MaterialResolver) resolveItemInternal(ctx context.Context, item *models.Item, parentName string, multiplier int, materialCounts map[string]int, materialInfo map[string]*models.Item, visited map[string]bool, nonConsumableCounted map[string]bool, ownedBlueprintsSet map[string]bool) int {
+func (r *MaterialResolver) resolveItemInternal(ctx context.Context, item *models.Item, parentName string, multiplier int, materialCounts map[string]int, materialInfo map[string]*models.Item, visited map[string]bool, nonConsumableCounted map[string]bool, ownedBlueprintsSet map[string]bool, ownedComponents map[string]int, surplus map[string]int, components map[string]*models.Item, budget *resolveBudget) buildCost {
 	if item == nil {
 		logger.Debug(ctx, "service: MaterialResolver.resolveItem - nil item, returning 0")
-		return 0
+		return buildCost{}
 	}
+	if !budget.enter() {
+		logger.Debug(ctx, "service: MaterialResolver.resolveItem - resolution limit hit, skipping", "uniqueName", item.UniqueName, "reason", budget.reason)
+		return buildCost{}
+	}
+	defer budget.leave()
 
 	if visited[item.UniqueName] {
 		logger.Debug(ctx, "service: MaterialResolver.resolveItem - already visited, skipping", "uniqueName", item.UniqueName)
-		return 0
+		return buildCost{}
 	}
 	visited[item.UniqueName] = true
 
-	totalCredits := item.BuildPrice * multiplier
-	logger.Debug(ctx, "service: MaterialResolver.resolveItem - processing", "uniqueName", item.UniqueName, "multiplier", multiplier, "buildPrice", item.BuildPrice)
+	total := buildCost{
+		credits:      item.BuildPrice * multiplier,
+		rushPlatinum: item.SkipBuildTimePrice * multiplier,
+	}
+	logger.Debug(ctx, "service: MaterialResolver.resolveItem - processing", "uniqueName", item.UniqueName, "multiplier", multiplier, "buildPrice", item.BuildPrice, "skipBuildTimePrice", item.SkipBuildTimePrice)
 
 	if len(item.Components) == 0 {
 		// Determine if this is actually a reusable blueprint
@@ -176,13 +452,17 @@ func (r *MaterialResolver) resolveItemInternal(ctx context.Context, item *models
 		// Check if this is a reusable blueprint that user already owns
 		if isReusableBlueprint && ownedBlueprintsSet[item.UniqueName] {
 			logger.Debug(ctx, "service: MaterialResolver.resolveItem - user already owns this reusable blueprint, skipping", "uniqueName", item.UniqueName)
-			return totalCredits
+			return total
 		}
 
 		// Check if this is a reusable blueprint already counted
 		if isReusableBlueprint && nonConsumableCounted[item.UniqueName] {
 			logger.Debug(ctx, "service: MaterialResolver.resolveItem - non-consumable already counted, skipping", "uniqueName", item.UniqueName)
-			return totalCredits
+			return total
+		}
+
+		if !budget.allowMaterial(materialCounts, item.UniqueName) {
+			return total
 		}
 
 		countToAdd := multiplier
@@ -192,7 +472,11 @@ func (r *MaterialResolver) resolveItemInternal(ctx context.Context, item *models
 			nonConsumableCounted[item.UniqueName] = true
 			logger.Debug(ctx, "service: MaterialResolver.resolveItem - non-consumable base material", "uniqueName", item.UniqueName)
 		} else {
-			logger.Debug(ctx, "service: MaterialResolver.resolveItem - base material (no components)", "uniqueName", item.UniqueName, "count", multiplier)
+			if countToAdd = takeOwnedComponents(ownedComponents, item.UniqueName, countToAdd); countToAdd == 0 {
+				logger.Debug(ctx, "service: MaterialResolver.resolveItem - user has enough of this consumable blueprint, skipping", "uniqueName", item.UniqueName)
+				return total
+			}
+			logger.Debug(ctx, "service: MaterialResolver.resolveItem - base material (no components)", "uniqueName", item.UniqueName, "count", countToAdd)
 		}
 
 		materialCounts[item.UniqueName] += countToAdd
@@ -207,7 +491,7 @@ func (r *MaterialResolver) resolveItemInternal(ctx context.Context, item *models
 			}
 		}
 		materialInfo[item.UniqueName] = itemToStore
-		return totalCredits
+		return total
 	}
 
 	logger.Debug(ctx, "service: MaterialResolver.resolveItem - processing components", "uniqueName", item.UniqueName, "componentCount", len(item.Components))
@@ -216,13 +500,18 @@ func (r *MaterialResolver) resolveItemInternal(ctx context.Context, item *models
 
 		// Check if component has nested components in the embedded data
 		if len(component.Components) > 0 {
+			if componentCount = takeOwnedComponents(ownedComponents, component.UniqueName, componentCount); componentCount == 0 {
+				logger.Debug(ctx, "service: MaterialResolver.resolveItem - user already owns this component, skipping", "uniqueName", component.UniqueName)
+				continue
+			}
 			// Try to fetch from database to get buildQuantity
-			componentItem, _ := r.itemRepo.FindByUniqueName(ctx, component.UniqueName)
+			componentItem, _ := findComponent(ctx, r.itemRepo, components, component.UniqueName)
 			buildQuantity := 1
 			if componentItem != nil && componentItem.BuildQuantity > 0 {
 				buildQuantity = componentItem.BuildQuantity
 			}
 			craftsNeeded := ceilDiv(componentCount, buildQuantity)
+			keepSurplus(surplus, component.UniqueName, craftsNeeded*buildQuantity-componentCount)
 			logger.Debug(ctx, "service: MaterialResolver.resolveItem - component has nested components, recursing", "uniqueName", component.UniqueName, "needed", componentCount, "buildQuantity", buildQuantity, "crafts", craftsNeeded)
 			// Create a temporary Item from the component to recurse
 			componentAsItem := &models.Item{
@@ -232,15 +521,28 @@ func (r *MaterialResolver) resolveItemInternal(ctx context.Context, item *models
 				Description: component.Description,
 				Components:  component.Components,
 			}
-			credits := r.resolveItemInternal(ctx, componentAsItem, item.Name, craftsNeeded, materialCounts, materialInfo, visited, nonConsumableCounted, ownedBlueprintsSet)
-			totalCredits += credits
+			// Embedded components carry no rush price, so take it from
+			// the component's own item.
+			if componentItem != nil {
+				componentAsItem.SkipBuildTimePrice = componentItem.SkipBuildTimePrice
+			}
+			total.add(r.resolveItemInternal(ctx, componentAsItem, item.Name, craftsNeeded, materialCounts, materialInfo, visited, nonConsumableCounted, ownedBlueprintsSet, ownedComponents, surplus, components, budget))
 			continue
 		}
 
 		// Try to fetch from database to check for additional components
-		componentItem, err := r.itemRepo.FindByUniqueName(ctx, component.UniqueName)
+		componentItem, err := findComponent(ctx, r.itemRepo, components, component.UniqueName)
 		if err != nil || componentItem == nil {
 			// Component not found in database and has no nested components - it's a base material
+			if !budget.allowMaterial(materialCounts, component.UniqueName) {
+				continue
+			}
+			// Most consumable blueprints only exist here, embedded in the
+			// item they build.
+			if componentCount = takeOwnedComponents(ownedComponents, component.UniqueName, componentCount); componentCount == 0 {
+				logger.Debug(ctx, "service: MaterialResolver.resolveItem - user has enough of this consumable blueprint, skipping", "uniqueName", component.UniqueName)
+				continue
+			}
 			logger.Debug(ctx, "service: MaterialResolver.resolveItem - component is base material (not in db)", "uniqueName", component.UniqueName, "count", componentCount)
 			materialCounts[component.UniqueName] += componentCount
 			// For components named "Blueprint", add parent context
@@ -274,6 +576,10 @@ func (r *MaterialResolver) resolveItemInternal(ctx context.Context, item *models
 				continue
 			}
 
+			if !budget.allowMaterial(materialCounts, component.UniqueName) {
+				continue
+			}
+
 			countToAdd := componentCount
 			if isReusableBlueprint {
 				// Non-consumable items only need 1 regardless of quantity
@@ -281,7 +587,11 @@ func (r *MaterialResolver) resolveItemInternal(ctx context.Context, item *models
 				nonConsumableCounted[component.UniqueName] = true
 				logger.Debug(ctx, "service: MaterialResolver.resolveItem - non-consumable component", "uniqueName", component.UniqueName)
 			} else {
-				logger.Debug(ctx, "service: MaterialResolver.resolveItem - component is base material", "uniqueName", component.UniqueName, "count", componentCount)
+				if countToAdd = takeOwnedComponents(ownedComponents, component.UniqueName, countToAdd); countToAdd == 0 {
+					logger.Debug(ctx, "service: MaterialResolver.resolveItem - user has enough of this consumable blueprint, skipping", "uniqueName", component.UniqueName)
+					continue
+				}
+				logger.Debug(ctx, "service: MaterialResolver.resolveItem - component is base material", "uniqueName", component.UniqueName, "count", countToAdd)
 			}
 
 			materialCounts[component.UniqueName] += countToAdd
@@ -296,17 +606,46 @@ func (r *MaterialResolver) resolveItemInternal(ctx context.Context, item *models
 			}
 			materialInfo[component.UniqueName] = componentItem
 		} else {
+			if componentCount = takeOwnedComponents(ownedComponents, component.UniqueName, componentCount); componentCount == 0 {
+				logger.Debug(ctx, "service: MaterialResolver.resolveItem - user already owns this component, skipping", "uniqueName", component.UniqueName)
+				continue
+			}
 			// Calculate crafts needed based on buildQuantity
 			buildQuantity := 1
 			if componentItem.BuildQuantity > 0 {
 				buildQuantity = componentItem.BuildQuantity
 			}
 			craftsNeeded := ceilDiv(componentCount, buildQuantity)
+			keepSurplus(surplus, component.UniqueName, craftsNeeded*buildQuantity-componentCount)
 			logger.Debug(ctx, "service: MaterialResolver.resolveItem - recursing into component", "uniqueName", component.UniqueName, "needed", componentCount, "buildQuantity", buildQuantity, "crafts", craftsNeeded)
-			credits := r.resolveItemInternal(ctx, componentItem, item.Name, craftsNeeded, materialCounts, materialInfo, visited, nonConsumableCounted, ownedBlueprintsSet)
-			totalCredits += credits
+			total.add(r.resolveItemInternal(ctx, componentItem, item.Name, craftsNeeded, materialCounts, materialInfo, visited, nonConsumableCounted, ownedBlueprintsSet, ownedComponents, surplus, components, budget))
 		}
 	}
 
-	return totalCredits
+	return total
+}
+
+// takeOwnedComponents uses up to needed of the user's owned copies of a
+// crafted component, or of a stocked consumable blueprint, and returns how
+// many are left to build or farm. Used copies are gone for later builds, so
+// two wishlisted frames share one owned Chassis rather than both skipping
+// theirs. owned may be nil.
+func takeOwnedComponents(owned map[string]int, uniqueName string, needed int) int {
+	have := owned[uniqueName]
+	if have <= 0 {
+		return needed
+	}
+	used := min(have, needed)
+	owned[uniqueName] = have - used
+	return needed - used
+}
+
+// keepSurplus sets aside the extra copies a batch craft made beyond what was
+// needed, for later builds to take like owned components. surplus is nil
+// when every build keeps its own extras.
+func keepSurplus(surplus map[string]int, uniqueName string, extra int) {
+	if surplus == nil || extra <= 0 {
+		return
+	}
+	surplus[uniqueName] += extra
 }
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+	"github.com/graytonio/warframe-wishlist/internal/repository/memory"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+)
+
+const benchUserID = "bench-user"
+
+// benchResourcePool is the number of distinct base resources shared by every
+// synthetic recipe, so aggregation merges counts the way real recipes do.
+const benchResourcePool = 32
+
+// countingItemRepository counts repository calls so benchmarks can report
+// round trips per resolution alongside time and allocations.
+type countingItemRepository struct {
+	repository.ItemRepositoryInterface
+	calls atomic.Int64
+}
+
+func (r *countingItemRepository) FindByUniqueName(ctx context.Context, uniqueName string) (*models.Item, error) {
+	r.calls.Add(1)
+	return r.ItemRepositoryInterface.FindByUniqueName(ctx, uniqueName)
+}
+
+func (r *countingItemRepository) FindByUniqueNames(ctx context.Context, uniqueNames []string) (map[string]*models.Item, error) {
+	r.calls.Add(1)
+	return r.ItemRepositoryInterface.FindByUniqueNames(ctx, uniqueNames)
+}
+
+// countingItemGraph counts FindComponentTree calls, each a single round trip
+// however deep the tree.
+type countingItemGraph struct {
+	repository.ItemGraphInterface
+	calls *atomic.Int64
+}
+
+func (g *countingItemGraph) FindComponentTree(ctx context.Context, uniqueNames []string) (map[string]*models.Item, error) {
+	g.calls.Add(1)
+	return g.ItemGraphInterface.FindComponentTree(ctx, uniqueNames)
+}
+
+// addSyntheticRecipe stores a recipe tree of the given depth and width under
+// prefix and returns the root uniqueName. Every intermediate component is its
+// own item (as crafted parts are in the WFCD data), leaves draw from the
+// shared resource pool, and one reusable blueprint is attached per level.
+func addSyntheticRecipe(repo *memory.ItemRepository, prefix string, depth, width int) string {
+	var build func(path string, level int) models.Item
+	build = func(path string, level int) models.Item {
+		item := models.Item{
+			UniqueName: path,
+			Name:       path,
+			BuildPrice: 1000 * (level + 1),
+		}
+		if level == 0 {
+			return item
+		}
+
+		for i := 0; i < width; i++ {
+			var child models.Item
+			if level == 1 {
+				resource := fmt.Sprintf("/Lotus/Bench/Resources/R%d", (len(path)+i)%benchResourcePool)
+				child = models.Item{UniqueName: resource, Name: resource}
+			} else {
+				child = build(fmt.Sprintf("%s/C%d", path, i), level-1)
+			}
+			repo.Add("misc", child)
+			item.Components = append(item.Components, models.Component{
+				UniqueName: child.UniqueName,
+				Name:       child.Name,
+				ItemCount:  i + 1,
+			})
+		}
+
+		blueprint := models.Item{UniqueName: path + "/ToolBlueprint", Name: "Tool Blueprint"}
+		repo.Add("misc", blueprint)
+		item.Components = append(item.Components, models.Component{UniqueName: blueprint.UniqueName, Name: blueprint.Name, ItemCount: 1})
+		return item
+	}
+
+	root := build(prefix, depth)
+	repo.Add("warframes", root)
+	return root.UniqueName
+}
+
+type resolverBenchCase struct {
+	name       string
+	items      int
+	depth      int
+	width      int
+	quantity   int
+	ownedRatio int // every Nth reusable blueprint is owned; 0 owns none
+	graph      bool
+}
+
+func newResolverBench(b *testing.B, bc resolverBenchCase) (*MaterialResolver, *countingItemRepository) {
+	b.Helper()
+
+	itemRepo := memory.NewItemRepository()
+	wishlistRepo := memory.NewWishlistRepository()
+	ownedRepo := memory.NewOwnedBlueprintsRepository()
+
+	wishlist := &models.Wishlist{UserID: benchUserID}
+	var owned []models.OwnedBlueprint
+	for i := 0; i < bc.items; i++ {
+		root := addSyntheticRecipe(itemRepo, fmt.Sprintf("/Lotus/Bench/Item%d", i), bc.depth, bc.width)
+		wishlist.Items = append(wishlist.Items, models.WishlistItem{UniqueName: root, Quantity: bc.quantity, AddedAt: time.Now()})
+		if bc.ownedRatio > 0 && i%bc.ownedRatio == 0 {
+			owned = append(owned, models.OwnedBlueprint{UniqueName: root + "/ToolBlueprint"})
+		}
+	}
+
+	ctx := context.Background()
+	if err := wishlistRepo.Upsert(ctx, wishlist); err != nil {
+		b.Fatalf("failed to seed wishlist: %v", err)
+	}
+	if len(owned) > 0 {
+		if err := ownedRepo.BulkAddBlueprints(ctx, benchUserID, owned); err != nil {
+			b.Fatalf("failed to seed owned blueprints: %v", err)
+		}
+	}
+
+	counting := &countingItemRepository{ItemRepositoryInterface: itemRepo}
+	resolver := NewMaterialResolver(counting, wishlistRepo, ownedRepo, nil)
+	if bc.graph {
+		resolver.SetItemGraph(&countingItemGraph{ItemGraphInterface: itemRepo, calls: &counting.calls})
+	}
+	return resolver, counting
+}
+
+func BenchmarkMaterialResolver_GetMaterials(b *testing.B) {
+	// Per-request info logs would dominate the larger cases.
+	logger.Init("error")
+
+	cases := []resolverBenchCase{
+		{name: "single/shallow", items: 1, depth: 1, width: 4, quantity: 1},
+		{name: "single/deep", items: 1, depth: 8, width: 2, quantity: 1},
+		{name: "single/wide", items: 1, depth: 2, width: 40, quantity: 1},
+		{name: "single/quantity10", items: 1, depth: 4, width: 3, quantity: 10},
+		{name: "wishlist/50", items: 50, depth: 3, width: 4, quantity: 1},
+		{name: "wishlist/250", items: 250, depth: 3, width: 4, quantity: 2},
+		{name: "wishlist/250/owned", items: 250, depth: 3, width: 4, quantity: 2, ownedRatio: 2},
+	}
+	// Every case again with the component tree fetched through the item
+	// graph, for comparing round trips against the level-by-level prefetch.
+	for _, bc := range cases[:len(cases):len(cases)] {
+		bc.name += "/graph"
+		bc.graph = true
+		cases = append(cases, bc)
+	}
+
+	for _, bc := range cases {
+		b.Run(bc.name, func(b *testing.B) {
+			resolver, counting := newResolverBench(b, bc)
+			ctx := context.Background()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			counting.calls.Store(0)
+
+			for i := 0; i < b.N; i++ {
+				if _, err := resolver.GetMaterials(ctx, benchUserID); err != nil {
+					b.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			b.ReportMetric(float64(counting.calls.Load())/float64(b.N), "repoCalls/op")
+		})
+	}
+}
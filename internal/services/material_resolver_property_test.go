@@ -0,0 +1,276 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository/memory"
+)
+
+// recipeScenario is a randomly generated item catalog: a handful of base
+// resources (some stored as items, some only referenced as components),
+// optional reusable blueprints, and root items built from up to three levels
+// of crafted intermediates with random counts, build quantities and prices.
+type recipeScenario struct {
+	items      []models.Item
+	roots      []string
+	blueprints []string
+}
+
+// consumableRecipes generates scenarios without reusable blueprints.
+type consumableRecipes struct{ recipeScenario }
+
+// blueprintRecipes generates scenarios that include reusable blueprints.
+type blueprintRecipes struct{ recipeScenario }
+
+func (consumableRecipes) Generate(rnd *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(consumableRecipes{generateRecipeScenario(rnd, false)})
+}
+
+func (blueprintRecipes) Generate(rnd *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(blueprintRecipes{generateRecipeScenario(rnd, true)})
+}
+
+func generateRecipeScenario(rnd *rand.Rand, withBlueprints bool) recipeScenario {
+	var sc recipeScenario
+
+	var leaves []string
+	for i := 0; i < 2+rnd.Intn(6); i++ {
+		name := fmt.Sprintf("/Lotus/Property/Resources/Res%d", i)
+		leaves = append(leaves, name)
+		// Half the resources exist as items, the rest are only referenced.
+		if i%2 == 0 {
+			sc.items = append(sc.items, models.Item{UniqueName: name, Name: fmt.Sprintf("Resource %d", i), ConsumeOnBuild: true})
+		}
+	}
+
+	if withBlueprints {
+		for i := 0; i < 1+rnd.Intn(3); i++ {
+			name := fmt.Sprintf("/Lotus/Property/Recipes/ToolBlueprint%d", i)
+			sc.blueprints = append(sc.blueprints, name)
+			sc.items = append(sc.items, models.Item{UniqueName: name, Name: fmt.Sprintf("Tool Blueprint %d", i)})
+		}
+	}
+
+	leaf := func() string {
+		if len(sc.blueprints) > 0 && rnd.Intn(5) == 0 {
+			return sc.blueprints[rnd.Intn(len(sc.blueprints))]
+		}
+		return leaves[rnd.Intn(len(leaves))]
+	}
+
+	counter := 0
+	var build func(level int) models.Item
+	build = func(level int) models.Item {
+		counter++
+		item := models.Item{
+			UniqueName:    fmt.Sprintf("/Lotus/Property/Parts/Part%d", counter),
+			Name:          fmt.Sprintf("Part %d", counter),
+			BuildPrice:    rnd.Intn(10) * 1000,
+			BuildQuantity: 1 + rnd.Intn(3),
+		}
+		for i := 0; i < 1+rnd.Intn(4); i++ {
+			component := models.Component{ItemCount: 1 + rnd.Intn(5)}
+			if level > 1 && rnd.Intn(2) == 0 {
+				child := build(level - 1)
+				sc.items = append(sc.items, child)
+				component.UniqueName, component.Name = child.UniqueName, child.Name
+			} else {
+				component.UniqueName = leaf()
+				component.Name = component.UniqueName[strings.LastIndex(component.UniqueName, "/")+1:]
+			}
+			item.Components = append(item.Components, component)
+		}
+		return item
+	}
+
+	for i := 0; i < 1+rnd.Intn(4); i++ {
+		root := build(1 + rnd.Intn(3))
+		sc.items = append(sc.items, root)
+		sc.roots = append(sc.roots, root.UniqueName)
+	}
+
+	return sc
+}
+
+// resolve runs the real resolver against in-memory repositories seeded with
+// the scenario and returns material counts keyed by uniqueName plus credits.
+func (sc recipeScenario) resolve(t *testing.T, wishlist []models.WishlistItem, owned []string) (map[string]int, int) {
+	t.Helper()
+	ctx := context.Background()
+
+	itemRepo := memory.NewItemRepository()
+	itemRepo.Add("misc", sc.items...)
+	wishlistRepo := memory.NewWishlistRepository()
+	wishlistRepo.Upsert(ctx, &models.Wishlist{UserID: "user-123", Items: wishlist})
+	ownedRepo := memory.NewOwnedBlueprintsRepository()
+	if len(owned) > 0 {
+		var blueprints []models.OwnedBlueprint
+		for _, name := range owned {
+			blueprints = append(blueprints, models.OwnedBlueprint{UniqueName: name})
+		}
+		ownedRepo.BulkAddBlueprints(ctx, "user-123", blueprints)
+	}
+
+	result, err := NewMaterialResolver(itemRepo, wishlistRepo, ownedRepo, nil).GetMaterials(ctx, "user-123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	counts := make(map[string]int)
+	for _, m := range result.Materials {
+		counts[m.UniqueName] = m.TotalCount
+	}
+	return counts, result.TotalCredits
+}
+
+// GoString keeps quick.Check failure reports readable: one line per crafted
+// item listing its build quantity and components.
+func (sc recipeScenario) GoString() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "roots=%v blueprints=%v", sc.roots, sc.blueprints)
+	for _, item := range sc.items {
+		if len(item.Components) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n  %s (x%d, %dcr):", item.UniqueName, item.BuildQuantity, item.BuildPrice)
+		for _, c := range item.Components {
+			fmt.Fprintf(&b, " %s*%d", c.UniqueName, c.ItemCount)
+		}
+	}
+	return b.String()
+}
+
+func (sc recipeScenario) isBlueprint(uniqueName string) bool {
+	for _, bp := range sc.blueprints {
+		if bp == uniqueName {
+			return true
+		}
+	}
+	return false
+}
+
+var propertyConfig = &quick.Config{MaxCount: 200}
+
+func TestMaterialResolver_Property_QuantityScalesConsumables(t *testing.T) {
+	property := func(sc consumableRecipes, rawQuantity uint8) bool {
+		quantity := 1 + int(rawQuantity%10)
+		root := sc.roots[0]
+
+		single, singleCredits := sc.resolve(t, []models.WishlistItem{{UniqueName: root, Quantity: 1}}, nil)
+		scaled, scaledCredits := sc.resolve(t, []models.WishlistItem{{UniqueName: root, Quantity: quantity}}, nil)
+
+		if scaledCredits != quantity*singleCredits || len(scaled) != len(single) {
+			t.Logf("quantity %d: credits %d vs %d x %d, materials %d vs %d", quantity, scaledCredits, quantity, singleCredits, len(scaled), len(single))
+			return false
+		}
+		for name, count := range single {
+			if scaled[name] != quantity*count {
+				t.Logf("quantity %d: %s expected %d, got %d", quantity, name, quantity*count, scaled[name])
+				return false
+			}
+		}
+		return true
+	}
+
+	if err := quick.Check(property, propertyConfig); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMaterialResolver_Property_OwnedBlueprintsNeverIncreaseTotals(t *testing.T) {
+	property := func(sc blueprintRecipes, ownedMask uint8, rawQuantity uint8) bool {
+		var wishlist []models.WishlistItem
+		for _, root := range sc.roots {
+			wishlist = append(wishlist, models.WishlistItem{UniqueName: root, Quantity: 1 + int(rawQuantity%4)})
+		}
+
+		var owned []string
+		for i, bp := range sc.blueprints {
+			if ownedMask&(1<<i) != 0 {
+				owned = append(owned, bp)
+			}
+		}
+
+		baseline, baselineCredits := sc.resolve(t, wishlist, nil)
+		withOwned, ownedCredits := sc.resolve(t, wishlist, owned)
+
+		if ownedCredits > baselineCredits {
+			t.Logf("credits increased from %d to %d with owned %v", baselineCredits, ownedCredits, owned)
+			return false
+		}
+		for name, count := range withOwned {
+			if count > baseline[name] {
+				t.Logf("%s increased from %d to %d with owned %v", name, baseline[name], count, owned)
+				return false
+			}
+		}
+		for _, name := range owned {
+			if withOwned[name] != 0 {
+				t.Logf("owned blueprint %s still required %d times", name, withOwned[name])
+				return false
+			}
+		}
+		return true
+	}
+
+	if err := quick.Check(property, propertyConfig); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMaterialResolver_Property_MergedWishlistsSumBaseResources(t *testing.T) {
+	property := func(sc blueprintRecipes, split uint8, rawQuantity uint8) bool {
+		var first, second []models.WishlistItem
+		cut := int(split) % (len(sc.roots) + 1)
+		for i, root := range sc.roots {
+			item := models.WishlistItem{UniqueName: root, Quantity: 1 + (int(rawQuantity)+i)%3}
+			if i < cut {
+				first = append(first, item)
+			} else {
+				second = append(second, item)
+			}
+		}
+
+		firstCounts, firstCredits := sc.resolve(t, first, nil)
+		secondCounts, secondCredits := sc.resolve(t, second, nil)
+		merged, mergedCredits := sc.resolve(t, append(append([]models.WishlistItem{}, first...), second...), nil)
+
+		if mergedCredits != firstCredits+secondCredits {
+			t.Logf("credits: merged %d, separate %d + %d", mergedCredits, firstCredits, secondCredits)
+			return false
+		}
+
+		// Reusable blueprints are needed once overall, so only base resources add up.
+		names := make(map[string]bool)
+		for name := range merged {
+			names[name] = true
+		}
+		for name := range firstCounts {
+			names[name] = true
+		}
+		for name := range secondCounts {
+			names[name] = true
+		}
+		for name := range names {
+			if sc.isBlueprint(name) {
+				continue
+			}
+			if merged[name] != firstCounts[name]+secondCounts[name] {
+				t.Logf("%s: merged %d, separate %d + %d", name, merged[name], firstCounts[name], secondCounts[name])
+				return false
+			}
+		}
+		return true
+	}
+
+	if err := quick.Check(property, propertyConfig); err != nil {
+		t.Error(err)
+	}
+}
@@ -3,13 +3,43 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/graytonio/warframe-wishlist/internal/mocks"
 	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+	"github.com/graytonio/warframe-wishlist/internal/repository/memory"
 )
 
+// newCatalogItemRepository returns an item repository that serves catalog
+// through both lookups the way the real repositories do, so a component is
+// found whether it is fetched alone or in a batch.
+func newCatalogItemRepository(catalog ...*models.Item) *mocks.MockItemRepository {
+	byName := make(map[string]*models.Item, len(catalog))
+	for _, item := range catalog {
+		byName[item.UniqueName] = item
+	}
+	return &mocks.MockItemRepository{
+		FindByUniqueNamesFunc: func(ctx context.Context, uniqueNames []string) (map[string]*models.Item, error) {
+			found := make(map[string]*models.Item)
+			for _, uniqueName := range uniqueNames {
+				if item, ok := byName[uniqueName]; ok {
+					found[uniqueName] = item
+				}
+			}
+			return found, nil
+		},
+		FindByUniqueNameFunc: func(ctx context.Context, uniqueName string) (*models.Item, error) {
+			return byName[uniqueName], nil
+		},
+	}
+}
+
 func TestMaterialResolver_GetMaterials_EmptyWishlist(t *testing.T) {
 	mockItemRepo := &mocks.MockItemRepository{}
 	mockWishlistRepo := &mocks.MockWishlistRepository{
@@ -18,7 +48,7 @@ func TestMaterialResolver_GetMaterials_EmptyWishlist(t *testing.T) {
 		},
 	}
 
-	resolver := NewMaterialResolver(mockItemRepo, mockWishlistRepo, nil)
+	resolver := NewMaterialResolver(mockItemRepo, mockWishlistRepo, nil, nil)
 	result, err := resolver.GetMaterials(context.Background(), "user-123")
 
 	if err != nil {
@@ -33,8 +63,8 @@ func TestMaterialResolver_GetMaterials_EmptyWishlist(t *testing.T) {
 		t.Errorf("expected empty materials, got %d", len(result.Materials))
 	}
 
-	if result.TotalCredits != 0 {
-		t.Errorf("expected 0 credits, got %d", result.TotalCredits)
+	if result.TotalCredits != 0 || result.RushPlatinum != 0 {
+		t.Errorf("expected 0 credits and rush platinum, got %d and %d", result.TotalCredits, result.RushPlatinum)
 	}
 }
 
@@ -49,7 +79,7 @@ func TestMaterialResolver_GetMaterials_WishlistWithEmptyItems(t *testing.T) {
 		},
 	}
 
-	resolver := NewMaterialResolver(mockItemRepo, mockWishlistRepo, nil)
+	resolver := NewMaterialResolver(mockItemRepo, mockWishlistRepo, nil, nil)
 	result, err := resolver.GetMaterials(context.Background(), "user-123")
 
 	if err != nil {
@@ -85,7 +115,7 @@ func TestMaterialResolver_GetMaterials_SimpleItem(t *testing.T) {
 		},
 	}
 
-	resolver := NewMaterialResolver(mockItemRepo, mockWishlistRepo, nil)
+	resolver := NewMaterialResolver(mockItemRepo, mockWishlistRepo, nil, nil)
 	result, err := resolver.GetMaterials(context.Background(), "user-123")
 
 	if err != nil {
@@ -131,7 +161,7 @@ func TestMaterialResolver_GetMaterials_ItemWithComponents(t *testing.T) {
 		},
 	}
 
-	resolver := NewMaterialResolver(mockItemRepo, mockWishlistRepo, nil)
+	resolver := NewMaterialResolver(mockItemRepo, mockWishlistRepo, nil, nil)
 	result, err := resolver.GetMaterials(context.Background(), "user-123")
 
 	if err != nil {
@@ -188,7 +218,7 @@ func TestMaterialResolver_GetMaterials_MultipleQuantity(t *testing.T) {
 		},
 	}
 
-	resolver := NewMaterialResolver(mockItemRepo, mockWishlistRepo, nil)
+	resolver := NewMaterialResolver(mockItemRepo, mockWishlistRepo, nil, nil)
 	result, err := resolver.GetMaterials(context.Background(), "user-123")
 
 	if err != nil {
@@ -207,33 +237,24 @@ func TestMaterialResolver_GetMaterials_MultipleQuantity(t *testing.T) {
 }
 
 func TestMaterialResolver_GetMaterials_NestedComponents(t *testing.T) {
-	mockItemRepo := &mocks.MockItemRepository{
-		FindByUniqueNamesFunc: func(ctx context.Context, uniqueNames []string) (map[string]*models.Item, error) {
-			return map[string]*models.Item{
-				"/Lotus/Warframe": {
-					UniqueName: "/Lotus/Warframe",
-					Name:       "Test Warframe",
-					BuildPrice: 25000,
-					Components: []models.Component{
-						{UniqueName: "/Lotus/Chassis", Name: "Chassis", ItemCount: 1},
-					},
-				},
-			}, nil
+	mockItemRepo := newCatalogItemRepository(
+		&models.Item{
+			UniqueName: "/Lotus/Warframe",
+			Name:       "Test Warframe",
+			BuildPrice: 25000,
+			Components: []models.Component{
+				{UniqueName: "/Lotus/Chassis", Name: "Chassis", ItemCount: 1},
+			},
 		},
-		FindByUniqueNameFunc: func(ctx context.Context, uniqueName string) (*models.Item, error) {
-			if uniqueName == "/Lotus/Chassis" {
-				return &models.Item{
-					UniqueName: "/Lotus/Chassis",
-					Name:       "Chassis",
-					BuildPrice: 15000,
-					Components: []models.Component{
-						{UniqueName: "/Lotus/Alloy", Name: "Alloy Plate", ItemCount: 500},
-					},
-				}, nil
-			}
-			return nil, nil
+		&models.Item{
+			UniqueName: "/Lotus/Chassis",
+			Name:       "Chassis",
+			BuildPrice: 15000,
+			Components: []models.Component{
+				{UniqueName: "/Lotus/Alloy", Name: "Alloy Plate", ItemCount: 500},
+			},
 		},
-	}
+	)
 	mockWishlistRepo := &mocks.MockWishlistRepository{
 		GetByUserIDFunc: func(ctx context.Context, userID string) (*models.Wishlist, error) {
 			return &models.Wishlist{
@@ -245,7 +266,7 @@ func TestMaterialResolver_GetMaterials_NestedComponents(t *testing.T) {
 		},
 	}
 
-	resolver := NewMaterialResolver(mockItemRepo, mockWishlistRepo, nil)
+	resolver := NewMaterialResolver(mockItemRepo, mockWishlistRepo, nil, nil)
 	result, err := resolver.GetMaterials(context.Background(), "user-123")
 
 	if err != nil {
@@ -263,6 +284,58 @@ func TestMaterialResolver_GetMaterials_NestedComponents(t *testing.T) {
 	}
 }
 
+func TestMaterialResolver_GetMaterials_RushPlatinum(t *testing.T) {
+	mockItemRepo := newCatalogItemRepository(
+		&models.Item{
+			UniqueName:         "/Lotus/Warframe",
+			Name:               "Test Warframe",
+			SkipBuildTimePrice: 50,
+			Components: []models.Component{
+				{UniqueName: "/Lotus/Chassis", Name: "Chassis", ItemCount: 1},
+				{
+					UniqueName: "/Lotus/Systems", Name: "Systems", ItemCount: 1,
+					Components: []models.Component{{UniqueName: "/Lotus/Circuits", Name: "Circuits", ItemCount: 100}},
+				},
+				{UniqueName: "/Lotus/Cell", Name: "Orokin Cell", ItemCount: 3},
+			},
+		},
+		&models.Item{
+			UniqueName:         "/Lotus/Chassis",
+			Name:               "Chassis",
+			SkipBuildTimePrice: 25,
+			Components:         []models.Component{{UniqueName: "/Lotus/Alloy", Name: "Alloy Plate", ItemCount: 500}},
+		},
+		// Embedded components take their rush price from their own item.
+		&models.Item{UniqueName: "/Lotus/Systems", Name: "Systems", SkipBuildTimePrice: 20},
+		// Two crafts of a double batch cover the 3 cells.
+		&models.Item{
+			UniqueName:         "/Lotus/Cell",
+			Name:               "Orokin Cell",
+			BuildQuantity:      2,
+			SkipBuildTimePrice: 10,
+			Components:         []models.Component{{UniqueName: "/Lotus/Gallium", Name: "Gallium", ItemCount: 1}},
+		},
+	)
+	mockWishlistRepo := &mocks.MockWishlistRepository{
+		GetByUserIDFunc: func(ctx context.Context, userID string) (*models.Wishlist, error) {
+			return &models.Wishlist{
+				UserID: userID,
+				Items:  []models.WishlistItem{{UniqueName: "/Lotus/Warframe", Quantity: 2, AddedAt: time.Now()}},
+			}, nil
+		},
+	}
+
+	resolver := NewMaterialResolver(mockItemRepo, mockWishlistRepo, nil, nil)
+	result, err := resolver.GetMaterials(context.Background(), "user-123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if expected := 2 * (50 + 25 + 20 + 2*10); result.RushPlatinum != expected {
+		t.Errorf("expected %d rush platinum, got %d", expected, result.RushPlatinum)
+	}
+}
+
 func TestMaterialResolver_GetMaterials_RepositoryError(t *testing.T) {
 	mockItemRepo := &mocks.MockItemRepository{}
 	mockWishlistRepo := &mocks.MockWishlistRepository{
@@ -271,7 +344,7 @@ func TestMaterialResolver_GetMaterials_RepositoryError(t *testing.T) {
 		},
 	}
 
-	resolver := NewMaterialResolver(mockItemRepo, mockWishlistRepo, nil)
+	resolver := NewMaterialResolver(mockItemRepo, mockWishlistRepo, nil, nil)
 	_, err := resolver.GetMaterials(context.Background(), "user-123")
 
 	if err == nil {
@@ -296,7 +369,7 @@ func TestMaterialResolver_GetMaterials_ItemNotInRepository(t *testing.T) {
 		},
 	}
 
-	resolver := NewMaterialResolver(mockItemRepo, mockWishlistRepo, nil)
+	resolver := NewMaterialResolver(mockItemRepo, mockWishlistRepo, nil, nil)
 	result, err := resolver.GetMaterials(context.Background(), "user-123")
 
 	if err != nil {
@@ -310,36 +383,38 @@ func TestMaterialResolver_GetMaterials_ItemNotInRepository(t *testing.T) {
 
 func TestMaterialResolver_GetMaterials_CycleDetection(t *testing.T) {
 	callCount := 0
-	mockItemRepo := &mocks.MockItemRepository{
-		FindByUniqueNamesFunc: func(ctx context.Context, uniqueNames []string) (map[string]*models.Item, error) {
-			return map[string]*models.Item{
-				"/Lotus/ItemA": {
-					UniqueName: "/Lotus/ItemA",
-					Name:       "Item A",
-					BuildPrice: 1000,
-					Components: []models.Component{
-						{UniqueName: "/Lotus/ItemB", Name: "Item B", ItemCount: 1},
-					},
-				},
-			}, nil
+	mockItemRepo := newCatalogItemRepository(
+		&models.Item{
+			UniqueName: "/Lotus/ItemA",
+			Name:       "Item A",
+			BuildPrice: 1000,
+			Components: []models.Component{
+				{UniqueName: "/Lotus/ItemB", Name: "Item B", ItemCount: 1},
+			},
 		},
-		FindByUniqueNameFunc: func(ctx context.Context, uniqueName string) (*models.Item, error) {
-			callCount++
-			if callCount > 100 {
-				t.Fatal("too many recursive calls, cycle detection may have failed")
-			}
-			if uniqueName == "/Lotus/ItemB" {
-				return &models.Item{
-					UniqueName: "/Lotus/ItemB",
-					Name:       "Item B",
-					BuildPrice: 500,
-					Components: []models.Component{
-						{UniqueName: "/Lotus/ItemA", Name: "Item A", ItemCount: 1},
-					},
-				}, nil
-			}
-			return nil, nil
+		&models.Item{
+			UniqueName: "/Lotus/ItemB",
+			Name:       "Item B",
+			BuildPrice: 500,
+			Components: []models.Component{
+				{UniqueName: "/Lotus/ItemA", Name: "Item A", ItemCount: 1},
+			},
 		},
+	)
+	findByUniqueNames, findByUniqueName := mockItemRepo.FindByUniqueNamesFunc, mockItemRepo.FindByUniqueNameFunc
+	mockItemRepo.FindByUniqueNamesFunc = func(ctx context.Context, uniqueNames []string) (map[string]*models.Item, error) {
+		callCount++
+		if callCount > 100 {
+			t.Fatal("too many recursive calls, cycle detection may have failed")
+		}
+		return findByUniqueNames(ctx, uniqueNames)
+	}
+	mockItemRepo.FindByUniqueNameFunc = func(ctx context.Context, uniqueName string) (*models.Item, error) {
+		callCount++
+		if callCount > 100 {
+			t.Fatal("too many recursive calls, cycle detection may have failed")
+		}
+		return findByUniqueName(ctx, uniqueName)
 	}
 	mockWishlistRepo := &mocks.MockWishlistRepository{
 		GetByUserIDFunc: func(ctx context.Context, userID string) (*models.Wishlist, error) {
@@ -352,7 +427,7 @@ func TestMaterialResolver_GetMaterials_CycleDetection(t *testing.T) {
 		},
 	}
 
-	resolver := NewMaterialResolver(mockItemRepo, mockWishlistRepo, nil)
+	resolver := NewMaterialResolver(mockItemRepo, mockWishlistRepo, nil, nil)
 	result, err := resolver.GetMaterials(context.Background(), "user-123")
 
 	if err != nil {
@@ -365,31 +440,22 @@ func TestMaterialResolver_GetMaterials_CycleDetection(t *testing.T) {
 }
 
 func TestMaterialResolver_GetMaterials_ExcludesOwnedBlueprints(t *testing.T) {
-	mockItemRepo := &mocks.MockItemRepository{
-		FindByUniqueNamesFunc: func(ctx context.Context, uniqueNames []string) (map[string]*models.Item, error) {
-			return map[string]*models.Item{
-				"/Lotus/Warframe": {
-					UniqueName: "/Lotus/Warframe",
-					Name:       "Test Warframe",
-					BuildPrice: 25000,
-					Components: []models.Component{
-						{UniqueName: "/Lotus/ReusableBlueprint", Name: "Reusable Blueprint", ItemCount: 1},
-						{UniqueName: "/Lotus/Resource1", Name: "Resource 1", ItemCount: 100},
-					},
-				},
-			}, nil
+	mockItemRepo := newCatalogItemRepository(
+		&models.Item{
+			UniqueName: "/Lotus/Warframe",
+			Name:       "Test Warframe",
+			BuildPrice: 25000,
+			Components: []models.Component{
+				{UniqueName: "/Lotus/ReusableBlueprint", Name: "Reusable Blueprint", ItemCount: 1},
+				{UniqueName: "/Lotus/Resource1", Name: "Resource 1", ItemCount: 100},
+			},
 		},
-		FindByUniqueNameFunc: func(ctx context.Context, uniqueName string) (*models.Item, error) {
-			if uniqueName == "/Lotus/ReusableBlueprint" {
-				return &models.Item{
-					UniqueName:     "/Lotus/ReusableBlueprint",
-					Name:           "Reusable Blueprint",
-					ConsumeOnBuild: false,
-				}, nil
-			}
-			return nil, nil
+		&models.Item{
+			UniqueName:     "/Lotus/ReusableBlueprint",
+			Name:           "Reusable Blueprint",
+			ConsumeOnBuild: false,
 		},
-	}
+	)
 	mockWishlistRepo := &mocks.MockWishlistRepository{
 		GetByUserIDFunc: func(ctx context.Context, userID string) (*models.Wishlist, error) {
 			return &models.Wishlist{
@@ -411,7 +477,7 @@ func TestMaterialResolver_GetMaterials_ExcludesOwnedBlueprints(t *testing.T) {
 		},
 	}
 
-	resolver := NewMaterialResolver(mockItemRepo, mockWishlistRepo, mockOwnedBPRepo)
+	resolver := NewMaterialResolver(mockItemRepo, mockWishlistRepo, mockOwnedBPRepo, nil)
 	result, err := resolver.GetMaterials(context.Background(), "user-123")
 
 	if err != nil {
@@ -430,31 +496,145 @@ func TestMaterialResolver_GetMaterials_ExcludesOwnedBlueprints(t *testing.T) {
 	}
 }
 
-func TestMaterialResolver_GetMaterials_IncludesNonOwnedReusableBlueprints(t *testing.T) {
-	mockItemRepo := &mocks.MockItemRepository{
-		FindByUniqueNamesFunc: func(ctx context.Context, uniqueNames []string) (map[string]*models.Item, error) {
-			return map[string]*models.Item{
-				"/Lotus/Warframe": {
-					UniqueName: "/Lotus/Warframe",
-					Name:       "Test Warframe",
-					BuildPrice: 25000,
-					Components: []models.Component{
-						{UniqueName: "/Lotus/ReusableBlueprint", Name: "Reusable Blueprint", ItemCount: 1},
-					},
+func TestMaterialResolver_GetMaterials_SkipsOwnedComponents(t *testing.T) {
+	mockItemRepo := newCatalogItemRepository(
+		&models.Item{
+			UniqueName: "/Lotus/Warframe",
+			Name:       "Test Warframe",
+			BuildPrice: 25000,
+			Components: []models.Component{
+				{UniqueName: "/Lotus/Chassis", Name: "Chassis", ItemCount: 1},
+				{UniqueName: "/Lotus/Systems", Name: "Systems", ItemCount: 1, Components: []models.Component{
+					{UniqueName: "/Lotus/Circuits", Name: "Circuits", ItemCount: 100},
+				}},
+			},
+		},
+		&models.Item{
+			UniqueName: "/Lotus/Chassis",
+			Name:       "Chassis",
+			BuildPrice: 15000,
+			Components: []models.Component{
+				{UniqueName: "/Lotus/Alloy", Name: "Alloy Plate", ItemCount: 500},
+			},
+		},
+	)
+	mockWishlistRepo := &mocks.MockWishlistRepository{
+		GetByUserIDFunc: func(ctx context.Context, userID string) (*models.Wishlist, error) {
+			return &models.Wishlist{
+				UserID: userID,
+				Items: []models.WishlistItem{
+					{UniqueName: "/Lotus/Warframe", Quantity: 2, AddedAt: time.Now()},
 				},
 			}, nil
 		},
-		FindByUniqueNameFunc: func(ctx context.Context, uniqueName string) (*models.Item, error) {
-			if uniqueName == "/Lotus/ReusableBlueprint" {
-				return &models.Item{
-					UniqueName:     "/Lotus/ReusableBlueprint",
-					Name:           "Reusable Blueprint",
-					ConsumeOnBuild: false,
-				}, nil
-			}
-			return nil, nil
+	}
+	mockOwnedBPRepo := &mocks.MockOwnedBlueprintsRepository{
+		GetByUserIDFunc: func(ctx context.Context, userID string) (*models.OwnedBlueprints, error) {
+			return &models.OwnedBlueprints{
+				UserID: userID,
+				Components: []models.OwnedComponent{
+					{UniqueName: "/Lotus/Chassis", Count: 1},
+					{UniqueName: "/Lotus/Systems", Count: 5},
+				},
+			}, nil
+		},
+	}
+
+	resolver := NewMaterialResolver(mockItemRepo, mockWishlistRepo, mockOwnedBPRepo, nil)
+	result, err := resolver.GetMaterials(context.Background(), "user-123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The owned chassis covers one of the two frames; the owned systems both.
+	counts := materialCounts(result)
+	if counts["/Lotus/Alloy"] != 500 {
+		t.Errorf("expected 500 Alloy Plate for the one chassis left to build, got %d", counts["/Lotus/Alloy"])
+	}
+	if _, ok := counts["/Lotus/Circuits"]; ok {
+		t.Errorf("expected no Circuits for the owned systems, got %d", counts["/Lotus/Circuits"])
+	}
+	if result.TotalCredits != 65000 {
+		t.Errorf("expected 65000 credits (2 x 25000 + 15000), got %d", result.TotalCredits)
+	}
+}
+
+func TestMaterialResolver_GetMaterials_UsesStockedConsumableBlueprints(t *testing.T) {
+	mockItemRepo := newCatalogItemRepository(
+		&models.Item{
+			UniqueName: "/Lotus/Rifle",
+			Name:       "Test Rifle",
+			Components: []models.Component{
+				{UniqueName: "/Lotus/RifleBlueprint", Name: "Blueprint", ItemCount: 1},
+				{UniqueName: "/Lotus/RifleStockBlueprint", Name: "Stock Blueprint", ItemCount: 1},
+				{UniqueName: "/Lotus/Ferrite", Name: "Ferrite", ItemCount: 100},
+			},
+		},
+		&models.Item{
+			UniqueName:     "/Lotus/RifleBlueprint",
+			Name:           "Blueprint",
+			ConsumeOnBuild: true,
+		},
+	)
+	mockWishlistRepo := &mocks.MockWishlistRepository{
+		GetByUserIDFunc: func(ctx context.Context, userID string) (*models.Wishlist, error) {
+			return &models.Wishlist{
+				UserID: userID,
+				Items: []models.WishlistItem{
+					{UniqueName: "/Lotus/Rifle", Quantity: 3, AddedAt: time.Now()},
+				},
+			}, nil
+		},
+	}
+	mockOwnedBPRepo := &mocks.MockOwnedBlueprintsRepository{
+		GetByUserIDFunc: func(ctx context.Context, userID string) (*models.OwnedBlueprints, error) {
+			return &models.OwnedBlueprints{
+				UserID: userID,
+				Blueprints: []models.OwnedBlueprint{
+					{UniqueName: "/Lotus/RifleBlueprint", Quantity: 2},
+					{UniqueName: "/Lotus/RifleStockBlueprint", Quantity: 5},
+				},
+			}, nil
 		},
 	}
+
+	resolver := NewMaterialResolver(mockItemRepo, mockWishlistRepo, mockOwnedBPRepo, nil)
+	result, err := resolver.GetMaterials(context.Background(), "user-123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Two of the three builds use stocked blueprints, and the stock
+	// blueprints, which are not in the catalog, cover all three; the
+	// materials are unaffected.
+	counts := materialCounts(result)
+	if counts["/Lotus/RifleBlueprint"] != 1 {
+		t.Errorf("expected 1 blueprint left to farm, got %d", counts["/Lotus/RifleBlueprint"])
+	}
+	if _, ok := counts["/Lotus/RifleStockBlueprint"]; ok {
+		t.Errorf("expected no stock blueprints left to farm, got %d", counts["/Lotus/RifleStockBlueprint"])
+	}
+	if counts["/Lotus/Ferrite"] != 300 {
+		t.Errorf("expected 300 Ferrite, got %d", counts["/Lotus/Ferrite"])
+	}
+}
+
+func TestMaterialResolver_GetMaterials_IncludesNonOwnedReusableBlueprints(t *testing.T) {
+	mockItemRepo := newCatalogItemRepository(
+		&models.Item{
+			UniqueName: "/Lotus/Warframe",
+			Name:       "Test Warframe",
+			BuildPrice: 25000,
+			Components: []models.Component{
+				{UniqueName: "/Lotus/ReusableBlueprint", Name: "Reusable Blueprint", ItemCount: 1},
+			},
+		},
+		&models.Item{
+			UniqueName:     "/Lotus/ReusableBlueprint",
+			Name:           "Reusable Blueprint",
+			ConsumeOnBuild: false,
+		},
+	)
 	mockWishlistRepo := &mocks.MockWishlistRepository{
 		GetByUserIDFunc: func(ctx context.Context, userID string) (*models.Wishlist, error) {
 			return &models.Wishlist{
@@ -474,7 +654,7 @@ func TestMaterialResolver_GetMaterials_IncludesNonOwnedReusableBlueprints(t *tes
 		},
 	}
 
-	resolver := NewMaterialResolver(mockItemRepo, mockWishlistRepo, mockOwnedBPRepo)
+	resolver := NewMaterialResolver(mockItemRepo, mockWishlistRepo, mockOwnedBPRepo, nil)
 	result, err := resolver.GetMaterials(context.Background(), "user-123")
 
 	if err != nil {
@@ -498,3 +678,687 @@ func TestMaterialResolver_GetMaterials_IncludesNonOwnedReusableBlueprints(t *tes
 		t.Error("non-owned reusable blueprint should be included in materials")
 	}
 }
+
+func TestMaterialResolver_GetMaterials_DegradesWhenOwnedBlueprintsFail(t *testing.T) {
+	mockItemRepo := &mocks.MockItemRepository{
+		FindByUniqueNamesFunc: func(ctx context.Context, uniqueNames []string) (map[string]*models.Item, error) {
+			return map[string]*models.Item{
+				"/Lotus/Warframe": {
+					UniqueName: "/Lotus/Warframe",
+					Name:       "Test Warframe",
+					BuildPrice: 25000,
+					Components: []models.Component{
+						{UniqueName: "/Lotus/Resource1", Name: "Resource 1", ItemCount: 100},
+					},
+				},
+			}, nil
+		},
+	}
+	mockWishlistRepo := &mocks.MockWishlistRepository{
+		GetByUserIDFunc: func(ctx context.Context, userID string) (*models.Wishlist, error) {
+			return &models.Wishlist{
+				UserID: userID,
+				Items: []models.WishlistItem{
+					{UniqueName: "/Lotus/Warframe", Quantity: 1, AddedAt: time.Now()},
+				},
+			}, nil
+		},
+	}
+	mockOwnedBPRepo := &mocks.MockOwnedBlueprintsRepository{
+		GetByUserIDFunc: func(ctx context.Context, userID string) (*models.OwnedBlueprints, error) {
+			return nil, errors.New("database error")
+		},
+	}
+
+	resolver := NewMaterialResolver(mockItemRepo, mockWishlistRepo, mockOwnedBPRepo, nil)
+	result, err := resolver.GetMaterials(context.Background(), "user-123")
+
+	if err != nil {
+		t.Fatalf("expected partial result without error, got %v", err)
+	}
+	if len(result.Materials) != 1 {
+		t.Errorf("expected 1 material, got %d", len(result.Materials))
+	}
+	if !result.IsDegraded() {
+		t.Fatal("expected result to be marked degraded")
+	}
+	if result.Degraded[0].Section != models.SectionOwnedBlueprints {
+		t.Errorf("expected degraded section '%s', got '%s'", models.SectionOwnedBlueprints, result.Degraded[0].Section)
+	}
+}
+
+func newOwnedMaterialsResolverRepos() (*mocks.MockItemRepository, *mocks.MockWishlistRepository) {
+	mockItemRepo := &mocks.MockItemRepository{
+		FindByUniqueNamesFunc: func(ctx context.Context, uniqueNames []string) (map[string]*models.Item, error) {
+			return map[string]*models.Item{
+				"/Lotus/Warframe": {
+					UniqueName: "/Lotus/Warframe",
+					Name:       "Test Warframe",
+					Components: []models.Component{
+						{UniqueName: "/Lotus/Resource1", Name: "Resource 1", ItemCount: 100},
+						{UniqueName: "/Lotus/Resource2", Name: "Resource 2", ItemCount: 50},
+						{UniqueName: "/Lotus/Resource3", Name: "Resource 3", ItemCount: 10},
+					},
+				},
+			}, nil
+		},
+	}
+	mockWishlistRepo := &mocks.MockWishlistRepository{
+		GetByUserIDFunc: func(ctx context.Context, userID string) (*models.Wishlist, error) {
+			return &models.Wishlist{
+				UserID: userID,
+				Items: []models.WishlistItem{
+					{UniqueName: "/Lotus/Warframe", Quantity: 2, AddedAt: time.Now()},
+				},
+			}, nil
+		},
+	}
+	return mockItemRepo, mockWishlistRepo
+}
+
+func TestMaterialResolver_GetMaterials_SubtractsOwnedMaterials(t *testing.T) {
+	mockItemRepo, mockWishlistRepo := newOwnedMaterialsResolverRepos()
+	mockOwnedMatRepo := &mocks.MockOwnedMaterialsRepository{
+		GetByUserIDFunc: func(ctx context.Context, userID string) ([]models.OwnedMaterial, error) {
+			return []models.OwnedMaterial{
+				{UniqueName: "/Lotus/Resource1", Count: 150},
+				{UniqueName: "/Lotus/Resource2", Count: 500},
+				{UniqueName: "/Lotus/Unrelated", Count: 10},
+			}, nil
+		},
+	}
+
+	resolver := NewMaterialResolver(mockItemRepo, mockWishlistRepo, nil, mockOwnedMatRepo)
+	result, err := resolver.GetMaterials(context.Background(), "user-123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string][3]int{
+		"/Lotus/Resource1": {200, 150, 50},
+		"/Lotus/Resource2": {100, 500, 0},
+		"/Lotus/Resource3": {20, 0, 20},
+	}
+	if len(result.Materials) != len(expected) {
+		t.Fatalf("expected %d materials, got %d", len(expected), len(result.Materials))
+	}
+	for _, mat := range result.Materials {
+		want := expected[mat.UniqueName]
+		if mat.TotalCount != want[0] || mat.Owned != want[1] || mat.Remaining != want[2] {
+			t.Errorf("%s: expected total/owned/remaining %v, got %d/%d/%d", mat.UniqueName, want, mat.TotalCount, mat.Owned, mat.Remaining)
+		}
+	}
+}
+
+func TestMaterialResolver_GetMaterials_RemainingEqualsTotalWithoutInventory(t *testing.T) {
+	mockItemRepo, mockWishlistRepo := newOwnedMaterialsResolverRepos()
+
+	resolver := NewMaterialResolver(mockItemRepo, mockWishlistRepo, nil, nil)
+	result, err := resolver.GetMaterials(context.Background(), "user-123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, mat := range result.Materials {
+		if mat.Owned != 0 || mat.Remaining != mat.TotalCount {
+			t.Errorf("%s: expected remaining to equal total %d, got owned %d remaining %d", mat.UniqueName, mat.TotalCount, mat.Owned, mat.Remaining)
+		}
+	}
+}
+
+func TestMaterialResolver_GetMaterials_DegradesWhenOwnedMaterialsFail(t *testing.T) {
+	mockItemRepo, mockWishlistRepo := newOwnedMaterialsResolverRepos()
+	mockOwnedMatRepo := &mocks.MockOwnedMaterialsRepository{
+		GetByUserIDFunc: func(ctx context.Context, userID string) ([]models.OwnedMaterial, error) {
+			return nil, errors.New("database error")
+		},
+	}
+
+	resolver := NewMaterialResolver(mockItemRepo, mockWishlistRepo, nil, mockOwnedMatRepo)
+	result, err := resolver.GetMaterials(context.Background(), "user-123")
+	if err != nil {
+		t.Fatalf("expected partial result without error, got %v", err)
+	}
+	if !result.IsDegraded() || result.Degraded[0].Section != models.SectionOwnedMaterials {
+		t.Fatalf("expected '%s' to be degraded, got %+v", models.SectionOwnedMaterials, result.Degraded)
+	}
+	for _, mat := range result.Materials {
+		if mat.Remaining != mat.TotalCount {
+			t.Errorf("%s: expected remaining to equal total %d, got %d", mat.UniqueName, mat.TotalCount, mat.Remaining)
+		}
+	}
+}
+
+func newBatchedLookupCatalog() []*models.Item {
+	return []*models.Item{
+		{
+			UniqueName: "/Lotus/Warframe",
+			Name:       "Test Warframe",
+			BuildPrice: 25000,
+			Components: []models.Component{
+				{UniqueName: "/Lotus/Chassis", Name: "Chassis", ItemCount: 1},
+				{UniqueName: "/Lotus/Systems", Name: "Systems", ItemCount: 1, Components: []models.Component{
+					{UniqueName: "/Lotus/Circuits", Name: "Circuits", ItemCount: 200},
+				}},
+			},
+		},
+		{
+			UniqueName: "/Lotus/Chassis",
+			Name:       "Chassis",
+			BuildPrice: 15000,
+			Components: []models.Component{
+				{UniqueName: "/Lotus/Plating", Name: "Plating", ItemCount: 2},
+			},
+		},
+		{
+			UniqueName:    "/Lotus/Plating",
+			Name:          "Plating",
+			BuildPrice:    500,
+			BuildQuantity: 2,
+			Components: []models.Component{
+				{UniqueName: "/Lotus/Alloy", Name: "Alloy Plate", ItemCount: 100},
+			},
+		},
+		{UniqueName: "/Lotus/Systems", Name: "Systems", BuildQuantity: 1},
+	}
+}
+
+func TestMaterialResolver_GetMaterials_BatchesComponentLookups(t *testing.T) {
+	mockItemRepo := newCatalogItemRepository(newBatchedLookupCatalog()...)
+	findByUniqueNames := mockItemRepo.FindByUniqueNamesFunc
+	var batches [][]string
+	mockItemRepo.FindByUniqueNamesFunc = func(ctx context.Context, uniqueNames []string) (map[string]*models.Item, error) {
+		batches = append(batches, uniqueNames)
+		return findByUniqueNames(ctx, uniqueNames)
+	}
+	mockItemRepo.FindByUniqueNameFunc = func(ctx context.Context, uniqueName string) (*models.Item, error) {
+		t.Errorf("expected no single lookups, got one for %s", uniqueName)
+		return nil, nil
+	}
+	mockWishlistRepo := &mocks.MockWishlistRepository{
+		GetByUserIDFunc: func(ctx context.Context, userID string) (*models.Wishlist, error) {
+			return &models.Wishlist{
+				UserID: userID,
+				Items: []models.WishlistItem{
+					{UniqueName: "/Lotus/Warframe", Quantity: 3, AddedAt: time.Now()},
+				},
+			}, nil
+		},
+	}
+
+	resolver := NewMaterialResolver(mockItemRepo, mockWishlistRepo, nil, nil)
+	result, err := resolver.GetMaterials(context.Background(), "user-123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The wishlist, then one batch per level of the component tree.
+	if len(batches) != 4 {
+		t.Fatalf("expected 4 batched lookups, got %d: %v", len(batches), batches)
+	}
+	if len(batches[1]) != 3 {
+		t.Errorf("expected the first level to include embedded components, got %v", batches[1])
+	}
+
+	if result.TotalCredits != 3*(25000+15000+500) {
+		t.Errorf("expected %d credits, got %d", 3*(25000+15000+500), result.TotalCredits)
+	}
+	expected := map[string]int{"/Lotus/Alloy": 300, "/Lotus/Circuits": 600}
+	for _, mat := range result.Materials {
+		if want, ok := expected[mat.UniqueName]; ok && mat.TotalCount != want {
+			t.Errorf("%s: expected %d, got %d", mat.UniqueName, want, mat.TotalCount)
+		}
+		delete(expected, mat.UniqueName)
+	}
+	if len(expected) != 0 {
+		t.Errorf("missing materials %v", expected)
+	}
+}
+
+func TestMaterialResolver_GetMaterials_FallsBackWhenBatchLookupFails(t *testing.T) {
+	mockItemRepo := newCatalogItemRepository(newBatchedLookupCatalog()...)
+	findByUniqueNames := mockItemRepo.FindByUniqueNamesFunc
+	batchCalls := 0
+	mockItemRepo.FindByUniqueNamesFunc = func(ctx context.Context, uniqueNames []string) (map[string]*models.Item, error) {
+		batchCalls++
+		if batchCalls > 1 {
+			return nil, errors.New("database error")
+		}
+		return findByUniqueNames(ctx, uniqueNames)
+	}
+	mockWishlistRepo := &mocks.MockWishlistRepository{
+		GetByUserIDFunc: func(ctx context.Context, userID string) (*models.Wishlist, error) {
+			return &models.Wishlist{
+				UserID: userID,
+				Items: []models.WishlistItem{
+					{UniqueName: "/Lotus/Warframe", Quantity: 1, AddedAt: time.Now()},
+				},
+			}, nil
+		},
+	}
+
+	resolver := NewMaterialResolver(mockItemRepo, mockWishlistRepo, nil, nil)
+	result, err := resolver.GetMaterials(context.Background(), "user-123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.TotalCredits != 25000+15000+500 {
+		t.Errorf("expected single lookups to resolve the full tree, got %d credits", result.TotalCredits)
+	}
+}
+
+// chainCatalog returns a recipe chain depth levels deep: /Lotus/Level0 needs
+// /Lotus/Level1 and so on, down to /Lotus/Base.
+func chainCatalog(depth int) []*models.Item {
+	catalog := make([]*models.Item, depth)
+	for i := range catalog {
+		next := fmt.Sprintf("/Lotus/Level%d", i+1)
+		if i == depth-1 {
+			next = "/Lotus/Base"
+		}
+		catalog[i] = &models.Item{
+			UniqueName: fmt.Sprintf("/Lotus/Level%d", i),
+			Name:       fmt.Sprintf("Level %d", i),
+			BuildPrice: 100,
+			Components: []models.Component{{UniqueName: next, Name: "Next", ItemCount: 1}},
+		}
+	}
+	return catalog
+}
+
+func singleItemWishlistRepo(uniqueName string, quantity int) *mocks.MockWishlistRepository {
+	return &mocks.MockWishlistRepository{
+		GetByUserIDFunc: func(ctx context.Context, userID string) (*models.Wishlist, error) {
+			return &models.Wishlist{UserID: userID, Items: []models.WishlistItem{{UniqueName: uniqueName, Quantity: quantity}}}, nil
+		},
+	}
+}
+
+func TestMaterialResolver_GetMaterials_WithinLimits(t *testing.T) {
+	resolver := NewMaterialResolver(newCatalogItemRepository(chainCatalog(5)...), singleItemWishlistRepo("/Lotus/Level0", 1), nil, nil)
+	resolver.SetLimits(MaterialLimits{MaxDepth: 5, MaxMaterials: 1, MaxDuration: time.Minute})
+
+	result, err := resolver.GetMaterials(context.Background(), "user-123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Truncated || result.TruncatedReason != "" {
+		t.Errorf("expected a complete result, got truncated %q", result.TruncatedReason)
+	}
+	if len(result.Materials) != 1 || result.Materials[0].UniqueName != "/Lotus/Base" || result.TotalCredits != 500 {
+		t.Errorf("expected /Lotus/Base and 500 credits, got %+v and %d credits", result.Materials, result.TotalCredits)
+	}
+	if hits := resolver.LimitHits(); hits != (MaterialLimitHits{}) {
+		t.Errorf("expected no limit hits, got %+v", hits)
+	}
+}
+
+func TestMaterialResolver_GetMaterials_DepthLimit(t *testing.T) {
+	resolver := NewMaterialResolver(newCatalogItemRepository(chainCatalog(10)...), singleItemWishlistRepo("/Lotus/Level0", 3), nil, nil)
+	resolver.SetLimits(MaterialLimits{MaxDepth: 3})
+
+	result, err := resolver.GetMaterials(context.Background(), "user-123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Truncated || result.TruncatedReason != models.TruncatedDepth {
+		t.Fatalf("expected a depth truncation, got truncated=%v reason %q", result.Truncated, result.TruncatedReason)
+	}
+	// The wishlist item and three levels below it are built once before
+	// the limit stops the resolution.
+	if len(result.Materials) != 0 || result.TotalCredits != 400 {
+		t.Errorf("expected no materials and 400 credits, got %+v and %d credits", result.Materials, result.TotalCredits)
+	}
+	if hits := resolver.LimitHits(); hits.Depth != 1 || hits.Materials != 0 || hits.Time != 0 {
+		t.Errorf("expected one depth hit, got %+v", hits)
+	}
+}
+
+func TestMaterialResolver_GetMaterials_MaterialsLimit(t *testing.T) {
+	components := make([]models.Component, 10)
+	for i := range components {
+		components[i] = models.Component{UniqueName: fmt.Sprintf("/Lotus/Resource%d", i), Name: fmt.Sprintf("Resource %d", i), ItemCount: 2}
+	}
+	itemRepo := newCatalogItemRepository(&models.Item{UniqueName: "/Lotus/Wide", Name: "Wide", Components: components})
+	resolver := NewMaterialResolver(itemRepo, singleItemWishlistRepo("/Lotus/Wide", 2), nil, nil)
+	resolver.SetLimits(MaterialLimits{MaxMaterials: 4})
+
+	result, err := resolver.GetMaterials(context.Background(), "user-123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Truncated || result.TruncatedReason != models.TruncatedMaterials {
+		t.Fatalf("expected a materials truncation, got truncated=%v reason %q", result.Truncated, result.TruncatedReason)
+	}
+	if len(result.Materials) != 4 {
+		t.Errorf("expected 4 materials, got %d", len(result.Materials))
+	}
+	if hits := resolver.LimitHits(); hits.Materials != 1 {
+		t.Errorf("expected one materials hit, got %+v", hits)
+	}
+}
+
+func TestMaterialResolver_GetMaterials_TimeLimit(t *testing.T) {
+	itemRepo := newCatalogItemRepository(chainCatalog(3)...)
+	batch := itemRepo.FindByUniqueNamesFunc
+	itemRepo.FindByUniqueNamesFunc = func(ctx context.Context, uniqueNames []string) (map[string]*models.Item, error) {
+		time.Sleep(5 * time.Millisecond)
+		return batch(ctx, uniqueNames)
+	}
+	resolver := NewMaterialResolver(itemRepo, singleItemWishlistRepo("/Lotus/Level0", 1000000), nil, nil)
+	resolver.SetLimits(MaterialLimits{MaxDuration: time.Millisecond})
+
+	result, err := resolver.GetMaterials(context.Background(), "user-123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Truncated || result.TruncatedReason != models.TruncatedTime {
+		t.Fatalf("expected a time truncation, got truncated=%v reason %q", result.Truncated, result.TruncatedReason)
+	}
+	if hits := resolver.LimitHits(); hits.Time != 1 {
+		t.Errorf("expected one time hit, got %+v", hits)
+	}
+}
+
+func TestMaterialResolver_GetMaterials_CustomItems(t *testing.T) {
+	ctx := context.Background()
+	customItems := memory.NewCustomItemRepository()
+	garden := &models.CustomItem{
+		UserID:     "user-123",
+		Name:       "Dojo Garden",
+		BuildPrice: 1000,
+		Components: []models.Component{{UniqueName: "/Out/Of/Game/Stone", Name: "River Stone", ItemCount: 4}},
+	}
+	if err := customItems.Create(ctx, garden); err != nil {
+		t.Fatalf("seeding: %v", err)
+	}
+	dojo := &models.CustomItem{
+		UserID: "user-123",
+		Name:   "Dojo",
+		Components: []models.Component{
+			{UniqueName: garden.UniqueName, Name: "Dojo Garden", ItemCount: 2},
+			{UniqueName: "/Lotus/Level1", Name: "Level 1", ItemCount: 1},
+		},
+	}
+	if err := customItems.Create(ctx, dojo); err != nil {
+		t.Fatalf("seeding: %v", err)
+	}
+
+	resolver := NewMaterialResolver(newCatalogItemRepository(chainCatalog(2)...), singleItemWishlistRepo(dojo.UniqueName, 1), nil, nil)
+	resolver.SetCustomItemRepository(customItems)
+	result, err := resolver.GetMaterials(ctx, "user-123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	counts := make(map[string]int)
+	for _, m := range result.Materials {
+		counts[m.UniqueName] = m.TotalCount
+	}
+	if counts["/Out/Of/Game/Stone"] != 8 || counts["/Lotus/Base"] != 1 || len(counts) != 2 {
+		t.Errorf("expected 8 stones and 1 base, got %v", counts)
+	}
+	// Two gardens and the one game recipe level below the dojo.
+	if result.TotalCredits != 2100 {
+		t.Errorf("expected 2100 credits, got %d", result.TotalCredits)
+	}
+
+	// Another user's wishlist cannot resolve this user's items.
+	resolver = NewMaterialResolver(newCatalogItemRepository(), singleItemWishlistRepo(dojo.UniqueName, 1), nil, nil)
+	resolver.SetCustomItemRepository(customItems)
+	result, err = resolver.GetMaterials(ctx, "user-456")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Materials) != 0 {
+		t.Errorf("expected no materials for another user, got %+v", result.Materials)
+	}
+}
+
+func TestMaterialResolver_GetMaterials_DegradesWhenCustomItemsFail(t *testing.T) {
+	resolver := NewMaterialResolver(newCatalogItemRepository(chainCatalog(1)...), singleItemWishlistRepo("/Lotus/Level0", 1), nil, nil)
+	resolver.SetCustomItemRepository(failingCustomItemRepository{memory.NewCustomItemRepository()})
+
+	result, err := resolver.GetMaterials(context.Background(), "user-123")
+	if err != nil {
+		t.Fatalf("expected partial result without error, got %v", err)
+	}
+	if len(result.Materials) != 1 {
+		t.Errorf("expected the game item to resolve, got %+v", result.Materials)
+	}
+	if !result.IsDegraded() || result.Degraded[0].Section != models.SectionCustomItems {
+		t.Errorf("expected the custom items section to be degraded, got %+v", result.Degraded)
+	}
+}
+
+// newItemGraphFixture stores synthetic recipes, the batched lookup catalog
+// and an item missing one component, wishlists them and owns one reusable
+// blueprint.
+func newItemGraphFixture(t *testing.T) (*memory.ItemRepository, *memory.WishlistRepository, *memory.OwnedBlueprintsRepository) {
+	t.Helper()
+	ctx := context.Background()
+
+	items := memory.NewItemRepository()
+	wishlist := &models.Wishlist{UserID: "user-123"}
+	for i, shape := range []struct{ depth, width int }{{1, 3}, {3, 2}, {4, 3}} {
+		root := addSyntheticRecipe(items, fmt.Sprintf("/Lotus/Graph/Item%d", i), shape.depth, shape.width)
+		wishlist.Items = append(wishlist.Items, models.WishlistItem{UniqueName: root, Quantity: i + 1})
+	}
+	for _, item := range newBatchedLookupCatalog() {
+		items.Add("misc", *item)
+	}
+	items.Add("warframes", models.Item{UniqueName: "/Lotus/Incomplete", Name: "Incomplete", BuildPrice: 100, Components: []models.Component{
+		{UniqueName: "/Lotus/Missing", Name: "Missing", ItemCount: 3},
+		{UniqueName: "/Lotus/Chassis", Name: "Chassis", ItemCount: 1},
+	}})
+	wishlist.Items = append(wishlist.Items,
+		models.WishlistItem{UniqueName: "/Lotus/Warframe", Quantity: 2},
+		models.WishlistItem{UniqueName: "/Lotus/Incomplete", Quantity: 1},
+	)
+
+	wishlists := memory.NewWishlistRepository()
+	if err := wishlists.Upsert(ctx, wishlist); err != nil {
+		t.Fatalf("failed to seed wishlist: %v", err)
+	}
+	owned := memory.NewOwnedBlueprintsRepository()
+	if err := owned.BulkAddBlueprints(ctx, "user-123", []models.OwnedBlueprint{{UniqueName: "/Lotus/Graph/Item1/ToolBlueprint"}}); err != nil {
+		t.Fatalf("failed to seed owned blueprints: %v", err)
+	}
+	return items, wishlists, owned
+}
+
+// sortedMaterials orders a response's materials by uniqueName, as the
+// resolver leaves them in map order.
+func sortedMaterials(response *models.MaterialsResponse) *models.MaterialsResponse {
+	slices.SortFunc(response.Materials, func(a, b models.MaterialRequirement) int {
+		return strings.Compare(a.UniqueName, b.UniqueName)
+	})
+	return response
+}
+
+func TestMaterialResolver_GetMaterials_ItemGraphMatchesPrefetch(t *testing.T) {
+	ctx := context.Background()
+	items, wishlists, owned := newItemGraphFixture(t)
+
+	expected, err := NewMaterialResolver(items, wishlists, owned, nil).GetMaterials(ctx, "user-123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	counting := &countingItemRepository{ItemRepositoryInterface: items}
+	graphCalls := 0
+	resolver := NewMaterialResolver(counting, wishlists, owned, nil)
+	resolver.SetItemGraph(&mocks.MockItemGraph{
+		FindComponentTreeFunc: func(ctx context.Context, uniqueNames []string) (map[string]*models.Item, error) {
+			graphCalls++
+			return items.FindComponentTree(ctx, uniqueNames)
+		},
+	})
+	result, err := resolver.GetMaterials(ctx, "user-123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(sortedMaterials(result), sortedMaterials(expected)) {
+		t.Errorf("graph resolution differs from prefetch:\ngraph:    %+v\nprefetch: %+v", result, expected)
+	}
+	// The wishlist items, one graph lookup, then one batch for the name the
+	// graph could not find.
+	if graphCalls != 1 || counting.calls.Load() != 2 {
+		t.Errorf("expected 1 graph lookup and 2 item lookups, got %d and %d", graphCalls, counting.calls.Load())
+	}
+}
+
+func TestMaterialResolver_GetMaterials_FallsBackWhenItemGraphFails(t *testing.T) {
+	ctx := context.Background()
+	items, wishlists, owned := newItemGraphFixture(t)
+
+	expected, err := NewMaterialResolver(items, wishlists, owned, nil).GetMaterials(ctx, "user-123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	resolver := NewMaterialResolver(items, wishlists, owned, nil)
+	resolver.SetItemGraph(&mocks.MockItemGraph{
+		FindComponentTreeFunc: func(ctx context.Context, uniqueNames []string) (map[string]*models.Item, error) {
+			return nil, repository.ErrItemGraphNotBuilt
+		},
+	})
+	result, err := resolver.GetMaterials(ctx, "user-123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(sortedMaterials(result), sortedMaterials(expected)) {
+		t.Errorf("expected the level prefetch to resolve the same materials:\ngot:  %+v\nwant: %+v", result, expected)
+	}
+}
+
+func TestMaterialResolver_GetMaterials_ItemGraphWithCustomItems(t *testing.T) {
+	ctx := context.Background()
+	customItems := memory.NewCustomItemRepository()
+	dojo := &models.CustomItem{
+		UserID:     "user-123",
+		Name:       "Dojo",
+		Components: []models.Component{{UniqueName: "/Lotus/Level1", Name: "Level 1", ItemCount: 2}},
+	}
+	if err := customItems.Create(ctx, dojo); err != nil {
+		t.Fatalf("seeding: %v", err)
+	}
+	items := memory.NewItemRepository()
+	for _, item := range chainCatalog(3) {
+		items.Add("misc", *item)
+	}
+
+	resolver := NewMaterialResolver(items, singleItemWishlistRepo(dojo.UniqueName, 1), nil, nil)
+	resolver.SetCustomItemRepository(customItems)
+	resolver.SetItemGraph(items)
+	result, err := resolver.GetMaterials(ctx, "user-123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.Materials) != 1 || result.Materials[0].UniqueName != "/Lotus/Base" || result.Materials[0].TotalCount != 2 {
+		t.Errorf("expected 2 base through the game recipe, got %+v", result.Materials)
+	}
+	if result.TotalCredits != 400 {
+		t.Errorf("expected 400 credits, got %d", result.TotalCredits)
+	}
+}
+
+func TestMaterialResolver_GetMaterials_MaterialsRounding(t *testing.T) {
+	// Each frame needs one Orokin Cell, and a craft makes two.
+	newItemRepo := func() *mocks.MockItemRepository {
+		return newCatalogItemRepository(
+			&models.Item{
+				UniqueName: "/Lotus/Frame",
+				Name:       "Frame",
+				Components: []models.Component{{UniqueName: "/Lotus/Cell", Name: "Orokin Cell", ItemCount: 1}},
+			},
+			&models.Item{
+				UniqueName: "/Lotus/OtherFrame",
+				Name:       "Other Frame",
+				Components: []models.Component{{UniqueName: "/Lotus/Cell", Name: "Orokin Cell", ItemCount: 1}},
+			},
+			&models.Item{
+				UniqueName:    "/Lotus/Cell",
+				Name:          "Orokin Cell",
+				BuildPrice:    1000,
+				BuildQuantity: 2,
+				Components:    []models.Component{{UniqueName: "/Lotus/Gallium", Name: "Gallium", ItemCount: 1}},
+			},
+		)
+	}
+	wishlistRepo := &mocks.MockWishlistRepository{
+		GetByUserIDFunc: func(ctx context.Context, userID string) (*models.Wishlist, error) {
+			return &models.Wishlist{
+				UserID: userID,
+				Items: []models.WishlistItem{
+					{UniqueName: "/Lotus/Frame", Quantity: 2, AddedAt: time.Now()},
+					{UniqueName: "/Lotus/OtherFrame", Quantity: 1, AddedAt: time.Now()},
+				},
+			}, nil
+		},
+	}
+
+	tests := []struct {
+		name            string
+		settingsRepo    *mocks.MockSettingsRepository
+		expectedGallium int
+	}{
+		{name: "no settings repository is strict", expectedGallium: 3},
+		{
+			name: "strict crafts a batch per frame",
+			settingsRepo: &mocks.MockSettingsRepository{
+				GetByUserIDFunc: func(ctx context.Context, userID string) (*models.UserSettings, error) {
+					return &models.UserSettings{UserID: userID, MaterialsRounding: models.MaterialsRoundingStrict}, nil
+				},
+			},
+			expectedGallium: 3,
+		},
+		{
+			name: "pooled shares batches across frames",
+			settingsRepo: &mocks.MockSettingsRepository{
+				GetByUserIDFunc: func(ctx context.Context, userID string) (*models.UserSettings, error) {
+					return &models.UserSettings{UserID: userID, MaterialsRounding: models.MaterialsRoundingPooled}, nil
+				},
+			},
+			expectedGallium: 2,
+		},
+		{
+			name: "settings error falls back to strict",
+			settingsRepo: &mocks.MockSettingsRepository{
+				GetByUserIDFunc: func(ctx context.Context, userID string) (*models.UserSettings, error) {
+					return nil, errors.New("database error")
+				},
+			},
+			expectedGallium: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver := NewMaterialResolver(newItemRepo(), wishlistRepo, nil, nil)
+			if tt.settingsRepo != nil {
+				resolver.SetSettingsRepository(tt.settingsRepo)
+			}
+			result, err := resolver.GetMaterials(context.Background(), "user-123")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			gallium := 0
+			for _, mat := range result.Materials {
+				if mat.UniqueName == "/Lotus/Gallium" {
+					gallium = mat.TotalCount
+				}
+			}
+			if gallium != tt.expectedGallium {
+				t.Errorf("expected %d Gallium, got %d", tt.expectedGallium, gallium)
+			}
+			if expected := tt.expectedGallium * 1000; result.TotalCredits != expected {
+				t.Errorf("expected %d credits, got %d", expected, result.TotalCredits)
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+package services
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+)
+
+// MaterialsCacheKey identifies a cached materials response. UpdatedAt is the
+// wishlist's, so a wishlist change made on any instance misses the cache.
+// Generation is the cache's generation for the user when resolution started;
+// lookups ignore it.
+type MaterialsCacheKey struct {
+	UserID     string
+	UpdatedAt  time.Time
+	Generation uint64
+}
+
+// LRUMaterialsCache keeps the most recently used materials responses in
+// memory, one per user. Entries expire after ttl, which bounds how long an
+// owned blueprint or material change made on another instance goes unseen.
+type LRUMaterialsCache struct {
+	capacity int
+	ttl      time.Duration
+	now      func() time.Time
+
+	mu      sync.Mutex
+	order   *list.List
+	entries map[string]*list.Element
+	// generation counts invalidations. invalidated holds the generation of
+	// each user's last one and purged that of the last purge, so Set can
+	// tell whether a response was resolved before them.
+	generation  uint64
+	invalidated map[string]uint64
+	purged      uint64
+}
+
+type cachedMaterials struct {
+	key       MaterialsCacheKey
+	response  *models.MaterialsResponse
+	expiresAt time.Time
+}
+
+func NewLRUMaterialsCache(capacity int, ttl time.Duration) *LRUMaterialsCache {
+	return &LRUMaterialsCache{
+		capacity:    capacity,
+		ttl:         ttl,
+		now:         time.Now,
+		order:       list.New(),
+		entries:     make(map[string]*list.Element),
+		invalidated: make(map[string]uint64),
+	}
+}
+
+// Get returns a copy of the response cached under key. An entry for the same
+// user under an older key is dropped.
+func (c *LRUMaterialsCache) Get(ctx context.Context, key MaterialsCacheKey) (*models.MaterialsResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key.UserID]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*cachedMaterials)
+	if !entry.key.UpdatedAt.Equal(key.UpdatedAt) || !c.now().Before(entry.expiresAt) {
+		c.remove(element)
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+	return cloneMaterialsResponse(entry.response), true
+}
+
+// Set caches a copy of response, evicting the least recently used entries
+// beyond capacity. A response resolved before the user's last invalidation
+// or the last purge is dropped, as it may predate the change behind them.
+func (c *LRUMaterialsCache) Set(ctx context.Context, key MaterialsCacheKey, response *models.MaterialsResponse) {
+	entry := &cachedMaterials{
+		key:       key,
+		response:  cloneMaterialsResponse(response),
+		expiresAt: c.now().Add(c.ttl),
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.purged > key.Generation || c.invalidated[key.UserID] > key.Generation {
+		logger.Debug(ctx, "service: LRUMaterialsCache.Set - invalidated while resolving, not caching")
+		return
+	}
+
+	if element, ok := c.entries[key.UserID]; ok {
+		element.Value = entry
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[key.UserID] = c.order.PushFront(entry)
+	for c.order.Len() > c.capacity {
+		c.remove(c.order.Back())
+	}
+}
+
+func (c *LRUMaterialsCache) Generation(ctx context.Context, userID string) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.generation
+}
+
+func (c *LRUMaterialsCache) Invalidate(ctx context.Context, userID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[userID]; ok {
+		c.remove(element)
+	}
+	c.generation++
+	// Forgetting the users invalidated so far as a purge keeps the map
+	// bounded; it only drops responses that were in flight.
+	if len(c.invalidated) >= c.capacity {
+		c.invalidated = make(map[string]uint64)
+		c.purged = c.generation
+	}
+	c.invalidated[userID] = c.generation
+}
+
+func (c *LRUMaterialsCache) Purge(ctx context.Context) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.order.Init()
+	c.entries = make(map[string]*list.Element)
+	c.generation++
+	c.invalidated = make(map[string]uint64)
+	c.purged = c.generation
+}
+
+// Len returns the number of cached responses, including expired ones that
+// have not been looked up since.
+func (c *LRUMaterialsCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+func (c *LRUMaterialsCache) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*cachedMaterials).key.UserID)
+}
+
+func cloneMaterialsResponse(response *models.MaterialsResponse) *models.MaterialsResponse {
+	clone := *response
+	clone.Materials = append([]models.MaterialRequirement(nil), response.Materials...)
+	clone.Degraded = append([]models.DegradedSection(nil), response.Degraded...)
+	return &clone
+}
+
+// CachedMaterialResolver serves materials responses from a MaterialsCache in
+// front of another resolver, so repeated requests skip the recursive
+// resolution. It reads the wishlist on every request to build the cache key;
+// services that change a user's owned blueprints or materials invalidate the
+// user's entry, and the data sync purges the cache.
+type CachedMaterialResolver struct {
+	next         MaterialResolverInterface
+	wishlistRepo repository.WishlistRepositoryInterface
+	cache        MaterialsCache
+}
+
+func NewCachedMaterialResolver(next MaterialResolverInterface, wishlistRepo repository.WishlistRepositoryInterface, cache MaterialsCache) *CachedMaterialResolver {
+	return &CachedMaterialResolver{
+		next:         next,
+		wishlistRepo: wishlistRepo,
+		cache:        cache,
+	}
+}
+
+// GetMaterials returns the cached response for the user's current wishlist,
+// resolving and caching it on a miss. Degraded responses are not cached so
+// the next request retries the lookups that failed.
+func (r *CachedMaterialResolver) GetMaterials(ctx context.Context, userID string) (*models.MaterialsResponse, error) {
+	wishlist, err := r.wishlistRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "service: CachedMaterialResolver.GetMaterials - error fetching wishlist", "error", err)
+		return nil, err
+	}
+	if wishlist == nil || len(wishlist.Items) == 0 {
+		return r.next.GetMaterials(ctx, userID)
+	}
+
+	key := MaterialsCacheKey{
+		UserID:     userID,
+		UpdatedAt:  wishlist.UpdatedAt,
+		Generation: r.cache.Generation(ctx, userID),
+	}
+	if response, ok := r.cache.Get(ctx, key); ok {
+		logger.Debug(ctx, "service: CachedMaterialResolver.GetMaterials - cache hit")
+		return response, nil
+	}
+
+	response, err := r.next.GetMaterials(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if response.IsDegraded() {
+		logger.Debug(ctx, "service: CachedMaterialResolver.GetMaterials - degraded response, not caching")
+		return response, nil
+	}
+	// A time limit depends on load and the other limits can be raised, so a
+	// truncated response is recomputed rather than served until it expires.
+	if response.Truncated {
+		logger.Debug(ctx, "service: CachedMaterialResolver.GetMaterials - truncated response, not caching")
+		return response, nil
+	}
+
+	r.cache.Set(ctx, key, response)
+	return response, nil
+}
+
+// invalidateMaterials drops the user's cached materials response after a
+// change that affects it. cache may be nil when caching is disabled.
+func invalidateMaterials(ctx context.Context, cache MaterialsCache, userID string) {
+	if cache == nil {
+		return
+	}
+	logger.Debug(ctx, "service: invalidating cached materials", "userID", userID)
+	cache.Invalidate(ctx, userID)
+}
@@ -0,0 +1,413 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository/memory"
+)
+
+var materialsCacheTestTime = time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+func newTestMaterialsCache(capacity int) (*LRUMaterialsCache, *time.Time) {
+	now := materialsCacheTestTime
+	cache := NewLRUMaterialsCache(capacity, time.Minute)
+	cache.now = func() time.Time { return now }
+	return cache, &now
+}
+
+func testMaterialsResponse(credits int) *models.MaterialsResponse {
+	return &models.MaterialsResponse{
+		Materials:    []models.MaterialRequirement{{UniqueName: "/Lotus/Alloy", TotalCount: 100, Remaining: 100}},
+		TotalCredits: credits,
+	}
+}
+
+func TestLRUMaterialsCache_GetAndSet(t *testing.T) {
+	ctx := context.Background()
+	cache, _ := newTestMaterialsCache(10)
+	key := MaterialsCacheKey{UserID: "user-123", UpdatedAt: materialsCacheTestTime}
+
+	if _, ok := cache.Get(ctx, key); ok {
+		t.Fatal("expected a miss on an empty cache")
+	}
+
+	cache.Set(ctx, key, testMaterialsResponse(1000))
+	response, ok := cache.Get(ctx, key)
+	if !ok || response.TotalCredits != 1000 {
+		t.Fatalf("expected the cached response, got %+v %v", response, ok)
+	}
+
+	// Callers get copies, so changing one leaves the cached entry intact.
+	response.Materials[0].TotalCount = 1
+	if again, _ := cache.Get(ctx, key); again.Materials[0].TotalCount != 100 {
+		t.Errorf("expected the cached response to be unchanged, got %+v", again.Materials)
+	}
+}
+
+func TestLRUMaterialsCache_NewerWishlistMisses(t *testing.T) {
+	ctx := context.Background()
+	cache, _ := newTestMaterialsCache(10)
+	cache.Set(ctx, MaterialsCacheKey{UserID: "user-123", UpdatedAt: materialsCacheTestTime}, testMaterialsResponse(1000))
+
+	if _, ok := cache.Get(ctx, MaterialsCacheKey{UserID: "user-123", UpdatedAt: materialsCacheTestTime.Add(time.Second)}); ok {
+		t.Error("expected a miss for a newer wishlist")
+	}
+	if cache.Len() != 0 {
+		t.Errorf("expected the outdated entry to be dropped, got %d entries", cache.Len())
+	}
+}
+
+func TestLRUMaterialsCache_Expires(t *testing.T) {
+	ctx := context.Background()
+	cache, now := newTestMaterialsCache(10)
+	key := MaterialsCacheKey{UserID: "user-123", UpdatedAt: materialsCacheTestTime}
+	cache.Set(ctx, key, testMaterialsResponse(1000))
+
+	*now = now.Add(time.Minute - time.Second)
+	if _, ok := cache.Get(ctx, key); !ok {
+		t.Fatal("expected a hit within the TTL")
+	}
+	*now = now.Add(time.Second)
+	if _, ok := cache.Get(ctx, key); ok {
+		t.Error("expected a miss after the TTL")
+	}
+}
+
+func TestLRUMaterialsCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	cache, _ := newTestMaterialsCache(2)
+	keys := map[string]MaterialsCacheKey{}
+	for _, userID := range []string{"user-a", "user-b", "user-c"} {
+		keys[userID] = MaterialsCacheKey{UserID: userID, UpdatedAt: materialsCacheTestTime}
+	}
+
+	cache.Set(ctx, keys["user-a"], testMaterialsResponse(1))
+	cache.Set(ctx, keys["user-b"], testMaterialsResponse(2))
+	cache.Get(ctx, keys["user-a"])
+	cache.Set(ctx, keys["user-c"], testMaterialsResponse(3))
+
+	if _, ok := cache.Get(ctx, keys["user-b"]); ok {
+		t.Error("expected the least recently used entry to be evicted")
+	}
+	for _, userID := range []string{"user-a", "user-c"} {
+		if _, ok := cache.Get(ctx, keys[userID]); !ok {
+			t.Errorf("expected %s to stay cached", userID)
+		}
+	}
+}
+
+func TestLRUMaterialsCache_InvalidateAndPurge(t *testing.T) {
+	ctx := context.Background()
+	cache, _ := newTestMaterialsCache(10)
+	keyA := MaterialsCacheKey{UserID: "user-a", UpdatedAt: materialsCacheTestTime}
+	keyB := MaterialsCacheKey{UserID: "user-b", UpdatedAt: materialsCacheTestTime}
+	cache.Set(ctx, keyA, testMaterialsResponse(1))
+	cache.Set(ctx, keyB, testMaterialsResponse(2))
+
+	cache.Invalidate(ctx, "user-a")
+	if _, ok := cache.Get(ctx, keyA); ok {
+		t.Error("expected user-a to be invalidated")
+	}
+	if _, ok := cache.Get(ctx, keyB); !ok {
+		t.Error("expected user-b to stay cached")
+	}
+
+	cache.Purge(ctx)
+	if cache.Len() != 0 {
+		t.Errorf("expected an empty cache after purge, got %d entries", cache.Len())
+	}
+}
+
+type countingMaterialResolver struct {
+	calls    int
+	response func() *models.MaterialsResponse
+	err      error
+}
+
+func (r *countingMaterialResolver) GetMaterials(ctx context.Context, userID string) (*models.MaterialsResponse, error) {
+	r.calls++
+	if r.err != nil {
+		return nil, r.err
+	}
+	return r.response(), nil
+}
+
+func TestCachedMaterialResolver_GetMaterials(t *testing.T) {
+	ctx := context.Background()
+	wishlist := &models.Wishlist{
+		UserID:    "user-123",
+		Items:     []models.WishlistItem{{UniqueName: "/Lotus/Warframe", Quantity: 1}},
+		UpdatedAt: materialsCacheTestTime,
+	}
+	wishlistRepo := &mocks.MockWishlistRepository{
+		GetByUserIDFunc: func(ctx context.Context, userID string) (*models.Wishlist, error) {
+			return wishlist, nil
+		},
+	}
+	next := &countingMaterialResolver{response: func() *models.MaterialsResponse { return testMaterialsResponse(1000) }}
+	cache, _ := newTestMaterialsCache(10)
+	resolver := NewCachedMaterialResolver(next, wishlistRepo, cache)
+
+	for i := 0; i < 3; i++ {
+		response, err := resolver.GetMaterials(ctx, "user-123")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if response.TotalCredits != 1000 {
+			t.Fatalf("unexpected response: %+v", response)
+		}
+	}
+	if next.calls != 1 {
+		t.Fatalf("expected one resolution, got %d", next.calls)
+	}
+
+	wishlist.UpdatedAt = wishlist.UpdatedAt.Add(time.Second)
+	if _, err := resolver.GetMaterials(ctx, "user-123"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if next.calls != 2 {
+		t.Errorf("expected a wishlist change to resolve again, got %d resolutions", next.calls)
+	}
+}
+
+func TestCachedMaterialResolver_DoesNotCacheDegradedResponses(t *testing.T) {
+	ctx := context.Background()
+	wishlistRepo := &mocks.MockWishlistRepository{
+		GetByUserIDFunc: func(ctx context.Context, userID string) (*models.Wishlist, error) {
+			return &models.Wishlist{UserID: userID, Items: []models.WishlistItem{{UniqueName: "/Lotus/Warframe", Quantity: 1}}}, nil
+		},
+	}
+	next := &countingMaterialResolver{response: func() *models.MaterialsResponse {
+		response := testMaterialsResponse(1000)
+		response.MarkDegraded(models.SectionOwnedBlueprints, "owned blueprints unavailable")
+		return response
+	}}
+	cache, _ := newTestMaterialsCache(10)
+	resolver := NewCachedMaterialResolver(next, wishlistRepo, cache)
+
+	resolver.GetMaterials(ctx, "user-123")
+	resolver.GetMaterials(ctx, "user-123")
+
+	if next.calls != 2 || cache.Len() != 0 {
+		t.Errorf("expected degraded responses to be resolved every time, got %d resolutions and %d entries", next.calls, cache.Len())
+	}
+}
+
+func TestCachedMaterialResolver_DoesNotCacheTruncatedResponses(t *testing.T) {
+	ctx := context.Background()
+	wishlistRepo := &mocks.MockWishlistRepository{
+		GetByUserIDFunc: func(ctx context.Context, userID string) (*models.Wishlist, error) {
+			return &models.Wishlist{UserID: userID, Items: []models.WishlistItem{{UniqueName: "/Lotus/Warframe", Quantity: 1}}}, nil
+		},
+	}
+	next := &countingMaterialResolver{response: func() *models.MaterialsResponse {
+		response := testMaterialsResponse(1000)
+		response.Truncated = true
+		response.TruncatedReason = models.TruncatedTime
+		return response
+	}}
+	cache, _ := newTestMaterialsCache(10)
+	resolver := NewCachedMaterialResolver(next, wishlistRepo, cache)
+
+	resolver.GetMaterials(ctx, "user-123")
+	resolver.GetMaterials(ctx, "user-123")
+
+	if next.calls != 2 || cache.Len() != 0 {
+		t.Errorf("expected truncated responses to be resolved every time, got %d resolutions and %d entries", next.calls, cache.Len())
+	}
+}
+
+func TestCachedMaterialResolver_EmptyWishlistIsNotCached(t *testing.T) {
+	next := &countingMaterialResolver{response: func() *models.MaterialsResponse { return &models.MaterialsResponse{} }}
+	cache, _ := newTestMaterialsCache(10)
+	resolver := NewCachedMaterialResolver(next, &mocks.MockWishlistRepository{}, cache)
+
+	if _, err := resolver.GetMaterials(context.Background(), "user-123"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if next.calls != 1 || cache.Len() != 0 {
+		t.Errorf("expected the empty wishlist to pass through uncached, got %d resolutions and %d entries", next.calls, cache.Len())
+	}
+}
+
+func TestCachedMaterialResolver_InvalidatedWhileResolving(t *testing.T) {
+	wishlistRepo := &mocks.MockWishlistRepository{
+		GetByUserIDFunc: func(ctx context.Context, userID string) (*models.Wishlist, error) {
+			return &models.Wishlist{UserID: userID, Items: []models.WishlistItem{{UniqueName: "/Lotus/Warframe", Quantity: 1}}, UpdatedAt: materialsCacheTestTime}, nil
+		},
+	}
+
+	tests := []struct {
+		name       string
+		invalidate func(ctx context.Context, cache MaterialsCache)
+		cached     bool
+	}{
+		{name: "user invalidated", invalidate: func(ctx context.Context, cache MaterialsCache) {
+			cache.Invalidate(ctx, "user-123")
+		}},
+		{name: "cache purged", invalidate: func(ctx context.Context, cache MaterialsCache) {
+			cache.Purge(ctx)
+		}},
+		{name: "other user invalidated", cached: true, invalidate: func(ctx context.Context, cache MaterialsCache) {
+			cache.Invalidate(ctx, "user-456")
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			next := newBlockingMaterialResolver()
+			cache, _ := newTestMaterialsCache(10)
+			resolver := NewCachedMaterialResolver(next, wishlistRepo, cache)
+
+			done := make(chan error)
+			go func() {
+				_, err := resolver.GetMaterials(ctx, "user-123")
+				done <- err
+			}()
+			<-next.started
+			tt.invalidate(ctx, cache)
+			close(next.release)
+			if err := <-done; err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			key := MaterialsCacheKey{UserID: "user-123", UpdatedAt: materialsCacheTestTime}
+			if _, ok := cache.Get(ctx, key); ok != tt.cached {
+				t.Errorf("expected cached %v, got %v", tt.cached, ok)
+			}
+		})
+	}
+}
+
+func TestCachedMaterialResolver_Errors(t *testing.T) {
+	t.Run("wishlist error", func(t *testing.T) {
+		wishlistRepo := &mocks.MockWishlistRepository{
+			GetByUserIDFunc: func(ctx context.Context, userID string) (*models.Wishlist, error) {
+				return nil, errors.New("database error")
+			},
+		}
+		next := &countingMaterialResolver{}
+		cache, _ := newTestMaterialsCache(10)
+
+		if _, err := NewCachedMaterialResolver(next, wishlistRepo, cache).GetMaterials(context.Background(), "user-123"); err == nil {
+			t.Error("expected error but got none")
+		}
+		if next.calls != 0 {
+			t.Errorf("expected no resolution, got %d", next.calls)
+		}
+	})
+
+	t.Run("resolution error", func(t *testing.T) {
+		wishlistRepo := &mocks.MockWishlistRepository{
+			GetByUserIDFunc: func(ctx context.Context, userID string) (*models.Wishlist, error) {
+				return &models.Wishlist{UserID: userID, Items: []models.WishlistItem{{UniqueName: "/Lotus/Warframe", Quantity: 1}}}, nil
+			},
+		}
+		next := &countingMaterialResolver{err: errors.New("database error")}
+		cache, _ := newTestMaterialsCache(10)
+
+		if _, err := NewCachedMaterialResolver(next, wishlistRepo, cache).GetMaterials(context.Background(), "user-123"); err == nil {
+			t.Error("expected error but got none")
+		}
+		if cache.Len() != 0 {
+			t.Errorf("expected nothing cached, got %d entries", cache.Len())
+		}
+	})
+}
+
+func TestMaterialsCache_InvalidatedByMutations(t *testing.T) {
+	wishlist := &models.Wishlist{
+		UserID: "user-123",
+		Items:  []models.WishlistItem{{UniqueName: "/Lotus/Warframe", Quantity: 1}},
+	}
+	wishlistRepo := &mocks.MockWishlistRepository{
+		GetByUserIDFunc: func(ctx context.Context, userID string) (*models.Wishlist, error) {
+			return wishlist, nil
+		},
+	}
+	ownedBPRepo := &mocks.MockOwnedBlueprintsRepository{
+		GetByUserIDFunc: func(ctx context.Context, userID string) (*models.OwnedBlueprints, error) {
+			return &models.OwnedBlueprints{UserID: userID, Blueprints: []models.OwnedBlueprint{{UniqueName: "/Lotus/ToolBlueprint"}}}, nil
+		},
+	}
+
+	mutations := []struct {
+		name   string
+		mutate func(ctx context.Context, cache MaterialsCache) error
+	}{
+		{name: "wishlist quantity", mutate: func(ctx context.Context, cache MaterialsCache) error {
+			service := NewWishlistService(wishlistRepo, &mocks.MockItemRepository{})
+			service.SetMaterialsCache(cache)
+			return service.UpdateQuantity(ctx, "user-123", "/Lotus/Warframe", 2)
+		}},
+		{name: "wishlist removal", mutate: func(ctx context.Context, cache MaterialsCache) error {
+			service := NewWishlistService(wishlistRepo, &mocks.MockItemRepository{})
+			service.SetMaterialsCache(cache)
+			return service.RemoveItem(ctx, "user-123", "/Lotus/Warframe")
+		}},
+		{name: "blueprint removal", mutate: func(ctx context.Context, cache MaterialsCache) error {
+			service := NewOwnedBlueprintsService(ownedBPRepo, &mocks.MockItemRepository{})
+			service.SetMaterialsCache(cache)
+			return service.RemoveBlueprint(ctx, "user-123", "/Lotus/ToolBlueprint")
+		}},
+		{name: "blueprints cleared", mutate: func(ctx context.Context, cache MaterialsCache) error {
+			service := NewOwnedBlueprintsService(ownedBPRepo, &mocks.MockItemRepository{})
+			service.SetMaterialsCache(cache)
+			return service.ClearAllBlueprints(ctx, "user-123")
+		}},
+		{name: "component count", mutate: func(ctx context.Context, cache MaterialsCache) error {
+			service := NewOwnedBlueprintsService(ownedBPRepo, &mocks.MockItemRepository{})
+			service.SetMaterialsCache(cache)
+			return service.SetComponentCount(ctx, "user-123", "/Lotus/Chassis", 1)
+		}},
+		{name: "material count", mutate: func(ctx context.Context, cache MaterialsCache) error {
+			service := NewOwnedMaterialsService(&mocks.MockOwnedMaterialsRepository{})
+			service.SetMaterialsCache(cache)
+			return service.SetMaterialCount(ctx, "user-123", "/Lotus/Alloy", 50)
+		}},
+		{name: "materials cleared", mutate: func(ctx context.Context, cache MaterialsCache) error {
+			service := NewOwnedMaterialsService(&mocks.MockOwnedMaterialsRepository{})
+			service.SetMaterialsCache(cache)
+			return service.ClearAllMaterials(ctx, "user-123")
+		}},
+		{name: "starter wishlist", mutate: func(ctx context.Context, cache MaterialsCache) error {
+			service := NewOnboardingService(memory.NewWishlistRepository(), memory.NewSettingsRepository(), newStarterItems(), starterItems)
+			service.SetMaterialsCache(cache)
+			_, err := service.Onboard(ctx, "user-123")
+			return err
+		}},
+		{name: "materials rounding", mutate: func(ctx context.Context, cache MaterialsCache) error {
+			service := NewSettingsService(&mocks.MockSettingsRepository{})
+			service.SetMaterialsCache(cache)
+			_, err := service.UpdateSettings(ctx, "user-123", models.UpdateSettingsRequest{MaterialsRounding: strPtr(models.MaterialsRoundingPooled)})
+			return err
+		}},
+	}
+
+	for _, tt := range mutations {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			cache, _ := newTestMaterialsCache(10)
+			key := MaterialsCacheKey{UserID: "user-123", UpdatedAt: wishlist.UpdatedAt}
+			cache.Set(ctx, key, testMaterialsResponse(1000))
+			cache.Set(ctx, MaterialsCacheKey{UserID: "user-456"}, testMaterialsResponse(2000))
+
+			if err := tt.mutate(ctx, cache); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if _, ok := cache.Get(ctx, key); ok {
+				t.Error("expected the user's cached materials to be invalidated")
+			}
+			if _, ok := cache.Get(ctx, MaterialsCacheKey{UserID: "user-456"}); !ok {
+				t.Error("expected other users' cached materials to be kept")
+			}
+		})
+	}
+}
@@ -0,0 +1,293 @@
+package services
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+)
+
+// MilestoneTracker notifies users as their wishlist reaches milestones. It
+// learns of wishlist and progress changes through the materials cache
+// invalidations every change already makes, and on the notification
+// watcher's next poll recomputes the changed users' milestones and diffs
+// them against those last stored: each newly reached milestone is notified
+// unless the user muted it. Changes are remembered in memory, so a change
+// made through another instance is picked up with that user's next change
+// here.
+type MilestoneTracker struct {
+	notifications    *NotificationService
+	materialResolver MaterialResolverInterface
+	wishlistRepo     repository.WishlistRepositoryInterface
+	ownedBPRepo      repository.OwnedBlueprintsRepositoryInterface
+	itemRepo         repository.ItemRepositoryInterface
+	settingsRepo     repository.SettingsRepositoryInterface
+	now              func() time.Time
+
+	mu      sync.Mutex
+	changed map[string]bool
+}
+
+func NewMilestoneTracker(notifications *NotificationService, materialResolver MaterialResolverInterface, wishlistRepo repository.WishlistRepositoryInterface, ownedBPRepo repository.OwnedBlueprintsRepositoryInterface, itemRepo repository.ItemRepositoryInterface, settingsRepo repository.SettingsRepositoryInterface) *MilestoneTracker {
+	return &MilestoneTracker{
+		notifications:    notifications,
+		materialResolver: materialResolver,
+		wishlistRepo:     wishlistRepo,
+		ownedBPRepo:      ownedBPRepo,
+		itemRepo:         itemRepo,
+		settingsRepo:     settingsRepo,
+		now:              time.Now,
+		changed:          make(map[string]bool),
+	}
+}
+
+// MarkChanged has the user's milestones recomputed on the next poll.
+func (t *MilestoneTracker) MarkChanged(userID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.changed[userID] = true
+}
+
+// takeChanged returns the users marked since the last call.
+func (t *MilestoneTracker) takeChanged() map[string]bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	changed := t.changed
+	t.changed = make(map[string]bool)
+	return changed
+}
+
+// InvalidatingCache wraps cache so that each invalidation also marks the
+// user changed. cache may be nil when caching is disabled.
+func (t *MilestoneTracker) InvalidatingCache(cache MaterialsCache) MaterialsCache {
+	return &milestoneMaterialsCache{cache: cache, tracker: t}
+}
+
+type milestoneMaterialsCache struct {
+	cache   MaterialsCache
+	tracker *MilestoneTracker
+}
+
+func (c *milestoneMaterialsCache) Get(ctx context.Context, key MaterialsCacheKey) (*models.MaterialsResponse, bool) {
+	if c.cache == nil {
+		return nil, false
+	}
+	return c.cache.Get(ctx, key)
+}
+
+func (c *milestoneMaterialsCache) Set(ctx context.Context, key MaterialsCacheKey, response *models.MaterialsResponse) {
+	if c.cache != nil {
+		c.cache.Set(ctx, key, response)
+	}
+}
+
+func (c *milestoneMaterialsCache) Generation(ctx context.Context, userID string) uint64 {
+	if c.cache == nil {
+		return 0
+	}
+	return c.cache.Generation(ctx, userID)
+}
+
+func (c *milestoneMaterialsCache) Invalidate(ctx context.Context, userID string) {
+	if c.cache != nil {
+		c.cache.Invalidate(ctx, userID)
+	}
+	c.tracker.MarkChanged(userID)
+}
+
+func (c *milestoneMaterialsCache) Purge(ctx context.Context) {
+	if c.cache != nil {
+		c.cache.Purge(ctx)
+	}
+}
+
+// check diffs the user's milestones against the stored ones and notifies
+// the newly reached. Unchanged users with a stored state are skipped. A user
+// without one, such as one who just subscribed, only has a state stored, so
+// milestones reached before subscribing are not sent.
+func (t *MilestoneTracker) check(ctx context.Context, userID string, changed bool) error {
+	previous, err := t.notifications.repo.GetMilestones(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "service: MilestoneTracker.check - error fetching milestones", "error", err)
+		return err
+	}
+	if previous != nil && !changed {
+		return nil
+	}
+
+	current, err := t.milestones(ctx, userID)
+	if err != nil {
+		return err
+	}
+	settings, err := t.settingsRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "service: MilestoneTracker.check - error fetching settings", "error", err)
+		return err
+	}
+
+	now := t.now()
+	state := &models.MilestoneState{UserID: userID, Reached: make(map[string]time.Time), CheckedAt: now}
+	for _, milestone := range models.Milestones {
+		var reachedAt time.Time
+		wasReached := false
+		if previous != nil {
+			reachedAt, wasReached = previous.Reached[milestone]
+		}
+		reached, known := current[milestone]
+		switch {
+		case !known:
+			// Left as it was until it can be computed again.
+			if wasReached {
+				state.Reached[milestone] = reachedAt
+			}
+		case reached && wasReached:
+			state.Reached[milestone] = reachedAt
+		case reached:
+			state.Reached[milestone] = now
+			if previous == nil || settings.MilestoneMuted(milestone) {
+				continue
+			}
+			if _, err := t.notifications.Notify(ctx, userID, milestoneNotification(milestone, now)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := t.notifications.repo.SaveMilestones(ctx, state); err != nil {
+		logger.Error(ctx, "service: MilestoneTracker.check - error saving milestones", "error", err)
+		return err
+	}
+	return nil
+}
+
+// milestones reports which milestones the user's wishlist has reached. A
+// milestone that cannot be computed, such as the materials one when the
+// materials response is degraded or truncated, is left out. An empty
+// wishlist has reached none.
+func (t *MilestoneTracker) milestones(ctx context.Context, userID string) (map[string]bool, error) {
+	reached := map[string]bool{
+		models.MilestoneMaterialsHalf:   false,
+		models.MilestoneBlueprintsOwned: false,
+		models.MilestoneLastFoundryRun:  false,
+	}
+
+	wishlist, err := t.wishlistRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "service: MilestoneTracker.milestones - error fetching wishlist", "error", err)
+		return nil, err
+	}
+	if wishlist == nil || len(wishlist.Items) == 0 {
+		return reached, nil
+	}
+
+	uniqueNames := make([]string, len(wishlist.Items))
+	for i, wishlistItem := range wishlist.Items {
+		uniqueNames[i] = wishlistItem.UniqueName
+	}
+	items, err := t.itemRepo.FindByUniqueNames(ctx, uniqueNames)
+	if err != nil {
+		logger.Error(ctx, "service: MilestoneTracker.milestones - error fetching items", "error", err)
+		return nil, err
+	}
+	owned, err := t.ownedBPRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "service: MilestoneTracker.milestones - error fetching owned blueprints", "error", err)
+		return nil, err
+	}
+	ownedBlueprints := make(map[string]bool)
+	ownedComponents := make(map[string]int)
+	if owned != nil {
+		for _, bp := range owned.Blueprints {
+			ownedBlueprints[bp.UniqueName] = true
+		}
+		for _, component := range owned.Components {
+			ownedComponents[component.UniqueName] += component.Count
+		}
+	}
+
+	blueprints, missingBlueprints, runs := 0, 0, 0
+	for _, wishlistItem := range wishlist.Items {
+		item := items[wishlistItem.UniqueName]
+		if item == nil || len(item.Components) == 0 {
+			continue
+		}
+		if !item.ConsumeOnBuild {
+			blueprints++
+			if !ownedBlueprints[item.UniqueName] {
+				missingBlueprints++
+			}
+		}
+		runs += foundryRuns(item, wishlistItem, ownedComponents)
+	}
+	reached[models.MilestoneBlueprintsOwned] = blueprints > 0 && missingBlueprints == 0
+	reached[models.MilestoneLastFoundryRun] = runs == 1
+
+	materials, err := t.materialResolver.GetMaterials(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "service: MilestoneTracker.milestones - error resolving materials", "error", err)
+		return nil, err
+	}
+	if materials.IsDegraded() || materials.Truncated {
+		delete(reached, models.MilestoneMaterialsHalf)
+		return reached, nil
+	}
+	total, gathered := 0, 0
+	for _, material := range materials.Materials {
+		total += material.TotalCount
+		gathered += min(material.Owned, material.TotalCount)
+	}
+	reached[models.MilestoneMaterialsHalf] = total > 0 && gathered*2 >= total
+	return reached, nil
+}
+
+// foundryRuns counts the builds left to craft the wishlist item: its own,
+// plus one per craftable component neither marked done in its progress nor
+// covered by the owned ones, which are used up as in the materials
+// resolution.
+func foundryRuns(item *models.Item, wishlistItem models.WishlistItem, ownedComponents map[string]int) int {
+	runs := ceilDiv(wishlistItem.Quantity, max(item.BuildQuantity, 1))
+	required := componentRequirements(item, wishlistItem.Quantity)
+	done := completedComponents(item, wishlistItem)
+	for _, component := range item.Components {
+		needed, ok := required[component.UniqueName]
+		if !ok || len(component.Components) == 0 {
+			continue
+		}
+		// Components listed twice are counted once.
+		delete(required, component.UniqueName)
+		runs += takeOwnedComponents(ownedComponents, component.UniqueName, needed-done[component.UniqueName])
+	}
+	return runs
+}
+
+var milestoneTitles = map[string]string{
+	models.MilestoneMaterialsHalf:   "Halfway there",
+	models.MilestoneBlueprintsOwned: "Every blueprint owned",
+	models.MilestoneLastFoundryRun:  "One build to go",
+}
+
+var milestoneBodies = map[string]string{
+	models.MilestoneMaterialsHalf:   "You have gathered half of the materials your wishlist needs.",
+	models.MilestoneBlueprintsOwned: "You own the blueprint of everything on your wishlist.",
+	models.MilestoneLastFoundryRun:  "One more foundry build finishes your wishlist.",
+}
+
+// milestoneNotification keys the notification by when the milestone was
+// reached, so reaching it again after falling back is sent again.
+func milestoneNotification(milestone string, reachedAt time.Time) models.Notification {
+	return models.Notification{
+		Event: models.NotificationMilestone,
+		Key:   "milestone:" + milestone + ":" + strconv.FormatInt(reachedAt.Unix(), 10),
+		Title: milestoneTitles[milestone],
+		Body:  milestoneBodies[milestone],
+		Data: map[string]string{
+			"milestone": milestone,
+			"reachedAt": reachedAt.UTC().Format(time.RFC3339),
+		},
+		OccurredAt: reachedAt,
+	}
+}
@@ -0,0 +1,211 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository/memory"
+)
+
+type milestoneFixture struct {
+	watcher   *NotificationWatcher
+	tracker   *MilestoneTracker
+	service   *NotificationService
+	repo      *memory.NotificationRepository
+	owned     *memory.OwnedBlueprintsRepository
+	settings  *memory.SettingsRepository
+	materials *models.MaterialsResponse
+	sent      []*models.NotificationDelivery
+}
+
+// newMilestoneFixture wishlists one Volt, whose Chassis is crafted, for
+// user-123, who has a channel subscribed to milestones.
+func newMilestoneFixture(t *testing.T, now *time.Time) *milestoneFixture {
+	t.Helper()
+	ctx := context.Background()
+	f := &milestoneFixture{materials: &models.MaterialsResponse{Materials: []models.MaterialRequirement{
+		{UniqueName: "/Lotus/Morphic", TotalCount: 2},
+		{UniqueName: "/Lotus/Ferrite", TotalCount: 1000},
+	}}}
+	f.service, f.repo = newNotificationFixture(now, func(_ *models.NotificationChannel, delivery *models.NotificationDelivery) (int, error) {
+		f.sent = append(f.sent, delivery)
+		return http.StatusOK, nil
+	})
+
+	items := memory.NewItemRepository()
+	items.Add("warframes", models.Item{
+		UniqueName: "/Lotus/Volt",
+		Name:       "Volt",
+		Components: []models.Component{
+			{UniqueName: "/Lotus/VoltChassis", Name: "Chassis", ItemCount: 1, Components: []models.Component{{UniqueName: "/Lotus/Ferrite", ItemCount: 1000}}},
+			{UniqueName: "/Lotus/Morphic", Name: "Morphics", ItemCount: 2},
+		},
+	})
+	wishlists := memory.NewWishlistRepository()
+	if err := wishlists.Create(ctx, &models.Wishlist{UserID: "user-123", Items: []models.WishlistItem{{UniqueName: "/Lotus/Volt", Quantity: 1}}}); err != nil {
+		t.Fatal(err)
+	}
+	f.owned = memory.NewOwnedBlueprintsRepository()
+	f.settings = memory.NewSettingsRepository()
+	resolver := &mocks.MockMaterialResolver{GetMaterialsFunc: func(ctx context.Context, userID string) (*models.MaterialsResponse, error) {
+		return f.materials, nil
+	}}
+
+	f.tracker = NewMilestoneTracker(f.service, resolver, wishlists, f.owned, items, f.settings)
+	f.tracker.now = func() time.Time { return *now }
+	f.watcher = NewNotificationWatcher(f.service, &mocks.MockFoundryService{}, &mocks.MockOpportunityFinder{}, time.Minute)
+	f.watcher.SetMilestoneTracker(f.tracker)
+
+	registered := *now
+	*now = registered.Add(-time.Hour)
+	if _, err := f.service.CreateChannel(ctx, "user-123", models.NotificationChannelRequest{
+		Type: models.NotificationWebhook, URL: "https://example.com/hook", Events: []string{models.NotificationMilestone},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	*now = registered
+	return f
+}
+
+// change updates the user's progress the way the services do, invalidating
+// their cached materials.
+func (f *milestoneFixture) change(t *testing.T, update func()) {
+	t.Helper()
+	update()
+	f.tracker.InvalidatingCache(nil).Invalidate(context.Background(), "user-123")
+}
+
+func (f *milestoneFixture) poll(t *testing.T) []string {
+	t.Helper()
+	f.sent = nil
+	if err := f.watcher.Poll(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var milestones []string
+	for _, d := range f.sent {
+		milestones = append(milestones, d.Key)
+	}
+	return milestones
+}
+
+func TestMilestoneTracker_Poll(t *testing.T) {
+	now := notificationNow
+	f := newMilestoneFixture(t, &now)
+	ctx := context.Background()
+
+	// Reached before the first check, so only stored.
+	f.materials.Materials[0].Owned = 2
+	f.materials.Materials[1].Owned = 1000
+	if sent := f.poll(t); len(sent) != 0 {
+		t.Fatalf("expected the first check to only store the state, got %v", sent)
+	}
+	state, _ := f.repo.GetMilestones(ctx, "user-123")
+	if state == nil || len(state.Reached) != 1 || !state.Reached[models.MilestoneMaterialsHalf].Equal(now) {
+		t.Fatalf("expected the materials milestone stored, got %+v", state)
+	}
+
+	now = now.Add(time.Minute)
+	f.change(t, func() {
+		f.owned.Create(ctx, &models.OwnedBlueprints{
+			UserID:     "user-123",
+			Blueprints: []models.OwnedBlueprint{{UniqueName: "/Lotus/Volt"}},
+			Components: []models.OwnedComponent{{UniqueName: "/Lotus/VoltChassis", Count: 1}},
+		})
+	})
+	sent := f.poll(t)
+	stamp := "1790856060"
+	if len(sent) != 2 || sent[0] != "milestone:blueprintsOwned:"+stamp || sent[1] != "milestone:lastFoundryRun:"+stamp {
+		t.Fatalf("expected the blueprint and last build milestones, got %v", sent)
+	}
+	if f.sent[0].Event != models.NotificationMilestone || !strings.Contains(f.sent[0].Payload, `"milestone":"blueprintsOwned"`) {
+		t.Errorf("unexpected delivery %+v", f.sent[0])
+	}
+
+	if sent := f.poll(t); len(sent) != 0 {
+		t.Errorf("expected no change to send nothing, got %v", sent)
+	}
+
+	// Falling back and reaching it again is sent again.
+	now = now.Add(time.Minute)
+	f.change(t, func() { f.materials.Materials[1].Owned = 0 })
+	if sent := f.poll(t); len(sent) != 0 {
+		t.Fatalf("expected falling back to send nothing, got %v", sent)
+	}
+	now = now.Add(time.Minute)
+	f.change(t, func() { f.materials.Materials[1].Owned = 600 })
+	if sent := f.poll(t); len(sent) != 1 || sent[0] != "milestone:materialsHalf:1790856180" {
+		t.Errorf("expected the materials milestone again, got %v", sent)
+	}
+}
+
+func TestMilestoneTracker_MutedAndDegraded(t *testing.T) {
+	now := notificationNow
+	f := newMilestoneFixture(t, &now)
+	ctx := context.Background()
+	f.poll(t)
+
+	if err := f.settings.Upsert(ctx, &models.UserSettings{UserID: "user-123", MutedMilestones: []string{models.MilestoneLastFoundryRun}}); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(time.Minute)
+	f.change(t, func() {
+		f.owned.Create(ctx, &models.OwnedBlueprints{UserID: "user-123", Components: []models.OwnedComponent{{UniqueName: "/Lotus/VoltChassis", Count: 1}}})
+		f.materials.Materials[1].Owned = 1000
+		f.materials.MarkDegraded(models.SectionOwnedMaterials, "owned materials unavailable")
+	})
+	if sent := f.poll(t); len(sent) != 0 {
+		t.Fatalf("expected a muted milestone and degraded materials to send nothing, got %v", sent)
+	}
+	state, _ := f.repo.GetMilestones(ctx, "user-123")
+	if _, ok := state.Reached[models.MilestoneLastFoundryRun]; !ok {
+		t.Error("expected a muted milestone to be stored as reached")
+	}
+	if _, ok := state.Reached[models.MilestoneMaterialsHalf]; ok {
+		t.Error("expected degraded materials to leave the milestone as it was")
+	}
+}
+
+func TestNotificationService_DeleteChannel_ForgetsMilestones(t *testing.T) {
+	now := notificationNow
+	f := newMilestoneFixture(t, &now)
+	ctx := context.Background()
+	f.poll(t)
+
+	channels, _ := f.service.ListChannels(ctx, "user-123")
+	if err := f.service.DeleteChannel(ctx, "user-123", channels[0].ID.Hex()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if state, _ := f.repo.GetMilestones(ctx, "user-123"); state != nil {
+		t.Errorf("expected the milestone state dropped with the last subscribed channel, got %+v", state)
+	}
+}
+
+func TestFoundryRuns(t *testing.T) {
+	item := &models.Item{
+		UniqueName: "/Lotus/Volt",
+		Components: []models.Component{
+			{UniqueName: "/Lotus/VoltChassis", ItemCount: 1, Components: []models.Component{{UniqueName: "/Lotus/Ferrite"}}},
+			{UniqueName: "/Lotus/VoltSystems", ItemCount: 1, Components: []models.Component{{UniqueName: "/Lotus/Salvage"}}},
+			{UniqueName: "/Lotus/Morphic", ItemCount: 2},
+		},
+	}
+	wishlistItem := models.WishlistItem{
+		UniqueName: "/Lotus/Volt",
+		Quantity:   2,
+		Progress:   []models.ComponentProgress{{UniqueName: "/Lotus/VoltSystems", Status: models.ComponentBuilt, Count: 1}},
+	}
+	owned := map[string]int{"/Lotus/VoltChassis": 1}
+
+	// Two Volts, one more Chassis and one more Systems.
+	if runs := foundryRuns(item, wishlistItem, owned); runs != 4 {
+		t.Errorf("expected 4 runs, got %d", runs)
+	}
+	if owned["/Lotus/VoltChassis"] != 0 {
+		t.Errorf("expected the owned Chassis to be used up, got %v", owned)
+	}
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+)
+
+// MaxModerationQueueSize bounds how many reports ListReports returns.
+const MaxModerationQueueSize = 200
+
+var (
+	ErrInvalidReportReason = errors.New("reason must be one of spam, abuse or other")
+	ErrInvalidReportStatus = errors.New("status must be one of open, actioned or dismissed")
+	ErrReportNotFound      = errors.New("no open report for share link")
+)
+
+// ModerationService keeps the queue of share links viewers reported and
+// lets moderators work it: revoking a link actions its report, and
+// dismissing a report leaves the link alone.
+type ModerationService struct {
+	reportRepo       repository.ShareLinkReportRepositoryInterface
+	shareLinkRepo    repository.ShareLinkRepositoryInterface
+	shareLinkService ShareLinkServiceInterface
+	now              func() time.Time
+}
+
+func NewModerationService(reportRepo repository.ShareLinkReportRepositoryInterface, shareLinkRepo repository.ShareLinkRepositoryInterface, shareLinkService ShareLinkServiceInterface) *ModerationService {
+	return &ModerationService{
+		reportRepo:       reportRepo,
+		shareLinkRepo:    shareLinkRepo,
+		shareLinkService: shareLinkService,
+		now:              time.Now,
+	}
+}
+
+// ReportShareLink queues the link with token for moderation. Reports are
+// anonymous, so the only validation is that the link exists.
+func (s *ModerationService) ReportShareLink(ctx context.Context, token string, req models.ReportShareLinkRequest) (*models.ShareLinkReport, error) {
+	logger.Debug(ctx, "service: ModerationService.ReportShareLink called", "reason", req.Reason)
+
+	if !slices.Contains(models.ReportReasons, req.Reason) {
+		logger.Warn(ctx, "service: ModerationService.ReportShareLink - invalid reason", "reason", req.Reason)
+		return nil, ErrInvalidReportReason
+	}
+
+	link, err := s.shareLinkRepo.GetByToken(ctx, token)
+	if err != nil {
+		logger.Error(ctx, "service: ModerationService.ReportShareLink - error fetching link", "error", err)
+		return nil, err
+	}
+	if link == nil {
+		logger.Warn(ctx, "service: ModerationService.ReportShareLink - unknown share token")
+		return nil, ErrShareLinkNotFound
+	}
+
+	report, err := s.reportRepo.Report(ctx, token, link.UserID, req.Reason, s.now())
+	if err != nil {
+		logger.Error(ctx, "service: ModerationService.ReportShareLink - error storing report", "error", err)
+		return nil, err
+	}
+
+	logger.Info(ctx, "service: ModerationService.ReportShareLink - report queued", "linkID", link.ID.Hex(), "count", report.Count)
+	return report, nil
+}
+
+// ListReports returns the oldest reports with status; an empty status lists
+// open reports.
+func (s *ModerationService) ListReports(ctx context.Context, status string) ([]models.ShareLinkReport, error) {
+	logger.Debug(ctx, "service: ModerationService.ListReports called", "status", status)
+
+	if status == "" {
+		status = models.ReportStatusOpen
+	}
+	if status != models.ReportStatusOpen && status != models.ReportStatusActioned && status != models.ReportStatusDismissed {
+		logger.Warn(ctx, "service: ModerationService.ListReports - invalid status", "status", status)
+		return nil, ErrInvalidReportStatus
+	}
+
+	reports, err := s.reportRepo.List(ctx, status, MaxModerationQueueSize)
+	if err != nil {
+		logger.Error(ctx, "service: ModerationService.ListReports - error fetching reports", "error", err)
+		return nil, err
+	}
+	return reports, nil
+}
+
+// DismissReport closes the open report of the link with token without
+// touching the link.
+func (s *ModerationService) DismissReport(ctx context.Context, token string) error {
+	logger.Debug(ctx, "service: ModerationService.DismissReport called")
+
+	resolved, err := s.reportRepo.Resolve(ctx, token, models.ReportStatusDismissed, s.now())
+	if err != nil {
+		logger.Error(ctx, "service: ModerationService.DismissReport - error resolving report", "error", err)
+		return err
+	}
+	if !resolved {
+		logger.Warn(ctx, "service: ModerationService.DismissReport - no open report")
+		return ErrReportNotFound
+	}
+
+	logger.Info(ctx, "service: ModerationService.DismissReport - report dismissed")
+	return nil
+}
+
+// RevokeShareLink deletes the link with token whoever owns it and actions
+// its open report, if any; links can also be revoked without a report.
+func (s *ModerationService) RevokeShareLink(ctx context.Context, token string) (*models.ShareLink, error) {
+	logger.Debug(ctx, "service: ModerationService.RevokeShareLink called")
+
+	link, err := s.shareLinkService.RevokeLink(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	// The link is already gone, so a report left open here only costs a
+	// moderator a dismissal later.
+	if _, err := s.reportRepo.Resolve(ctx, token, models.ReportStatusActioned, s.now()); err != nil {
+		logger.Warn(ctx, "service: ModerationService.RevokeShareLink - error resolving report", "error", err)
+	}
+
+	logger.Info(ctx, "service: ModerationService.RevokeShareLink - link revoked", "linkID", link.ID.Hex())
+	return link, nil
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository/memory"
+)
+
+// newModerationFixture returns a service whose only share link, "token-1",
+// belongs to owner.
+func newModerationFixture(t *testing.T) (*ModerationService, *memory.ShareLinkRepository) {
+	t.Helper()
+
+	links := memory.NewShareLinkRepository()
+	if err := links.Create(context.Background(), &models.ShareLink{UserID: "owner", Token: "token-1"}); err != nil {
+		t.Fatalf("failed to seed link: %v", err)
+	}
+	shareLinkService := NewShareLinkService(links, memory.NewWishlistRepository(), &mocks.MockMaterialResolver{})
+	return NewModerationService(memory.NewShareLinkReportRepository(), links, shareLinkService), links
+}
+
+func TestModerationService_ReportShareLink(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newModerationFixture(t)
+
+	for _, reason := range []string{models.ReportReasonSpam, models.ReportReasonAbuse} {
+		if _, err := service.ReportShareLink(ctx, "token-1", models.ReportShareLinkRequest{Reason: reason}); err != nil {
+			t.Fatalf("ReportShareLink: %v", err)
+		}
+	}
+
+	reports, err := service.ListReports(ctx, "")
+	if err != nil {
+		t.Fatalf("ListReports: %v", err)
+	}
+	if len(reports) != 1 || reports[0].OwnerID != "owner" || reports[0].Count != 2 || len(reports[0].Reasons) != 2 {
+		t.Errorf("expected one open report of both reasons, got %+v", reports)
+	}
+}
+
+func TestModerationService_ReportShareLinkErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		token    string
+		reason   string
+		expected error
+	}{
+		{name: "unknown reason", token: "token-1", reason: "rude", expected: ErrInvalidReportReason},
+		{name: "missing reason", token: "token-1", expected: ErrInvalidReportReason},
+		{name: "unknown token", token: "token-2", reason: models.ReportReasonSpam, expected: ErrShareLinkNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, _ := newModerationFixture(t)
+
+			if _, err := service.ReportShareLink(context.Background(), tt.token, models.ReportShareLinkRequest{Reason: tt.reason}); !errors.Is(err, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestModerationService_ListReportsInvalidStatus(t *testing.T) {
+	service, _ := newModerationFixture(t)
+
+	if _, err := service.ListReports(context.Background(), "pending"); !errors.Is(err, ErrInvalidReportStatus) {
+		t.Errorf("expected ErrInvalidReportStatus, got %v", err)
+	}
+}
+
+func TestModerationService_DismissReport(t *testing.T) {
+	ctx := context.Background()
+	service, links := newModerationFixture(t)
+
+	if err := service.DismissReport(ctx, "token-1"); !errors.Is(err, ErrReportNotFound) {
+		t.Fatalf("expected ErrReportNotFound without a report, got %v", err)
+	}
+
+	if _, err := service.ReportShareLink(ctx, "token-1", models.ReportShareLinkRequest{Reason: models.ReportReasonOther}); err != nil {
+		t.Fatalf("ReportShareLink: %v", err)
+	}
+	if err := service.DismissReport(ctx, "token-1"); err != nil {
+		t.Fatalf("DismissReport: %v", err)
+	}
+
+	open, _ := service.ListReports(ctx, models.ReportStatusOpen)
+	dismissed, _ := service.ListReports(ctx, models.ReportStatusDismissed)
+	if len(open) != 0 || len(dismissed) != 1 {
+		t.Errorf("expected the report to move to dismissed, got open %+v and dismissed %+v", open, dismissed)
+	}
+	if link, _ := links.GetByToken(ctx, "token-1"); link == nil {
+		t.Error("expected dismissing to leave the link")
+	}
+}
+
+func TestModerationService_RevokeShareLink(t *testing.T) {
+	ctx := context.Background()
+	service, links := newModerationFixture(t)
+
+	if _, err := service.ReportShareLink(ctx, "token-1", models.ReportShareLinkRequest{Reason: models.ReportReasonAbuse}); err != nil {
+		t.Fatalf("ReportShareLink: %v", err)
+	}
+
+	link, err := service.RevokeShareLink(ctx, "token-1")
+	if err != nil {
+		t.Fatalf("RevokeShareLink: %v", err)
+	}
+	if link.UserID != "owner" {
+		t.Errorf("expected the revoked link, got %+v", link)
+	}
+	if stored, _ := links.GetByToken(ctx, "token-1"); stored != nil {
+		t.Error("expected the link to be deleted")
+	}
+
+	actioned, _ := service.ListReports(ctx, models.ReportStatusActioned)
+	if len(actioned) != 1 || actioned[0].ResolvedAt == nil {
+		t.Errorf("expected the report to be actioned, got %+v", actioned)
+	}
+
+	if _, err := service.RevokeShareLink(ctx, "token-1"); !errors.Is(err, ErrShareLinkNotFound) {
+		t.Errorf("expected ErrShareLinkNotFound, got %v", err)
+	}
+}
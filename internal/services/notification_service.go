@@ -0,0 +1,414 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/ids"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/notify"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// notificationSendTimeout bounds each webhook or push request.
+	notificationSendTimeout = 10 * time.Second
+	// notificationMaxAge is how old an event may be and still be sent, so
+	// nothing is resent once its delivery log has expired.
+	notificationMaxAge = 24 * time.Hour
+	// deliveryLease is how long a claimed delivery is left to its sender
+	// before another dispatch may claim it; deliveryBatch sends fit well
+	// inside it.
+	deliveryLease = 5 * time.Minute
+	deliveryBatch = 20
+	// Failed deliveries are retried after deliveryBackoff, doubling with
+	// each attempt up to maxDeliveryBackoff.
+	deliveryBackoff    = 30 * time.Second
+	maxDeliveryBackoff = time.Hour
+	// defaultDeliveryLimit and maxDeliveryLimit bound ListDeliveries.
+	defaultDeliveryLimit = 50
+	maxDeliveryLimit     = 200
+	// maxNotificationURLLength bounds channel URLs.
+	maxNotificationURLLength = 2048
+	// webhookSecretBits is the randomness of the secrets webhooks to
+	// channels and integrations are signed with.
+	webhookSecretBits = 256
+)
+
+var (
+	ErrNotificationChannelNotFound = errors.New("notification channel not found")
+	ErrTooManyNotificationChannels = fmt.Errorf("at most %d notification channels per user", models.MaxNotificationChannels)
+	ErrInvalidNotificationChannel  = errors.New("invalid notification channel")
+	ErrPushNotConfigured           = errors.New("web push is not configured on this server")
+)
+
+// NotificationService manages the webhooks and Web Push subscriptions users
+// register, queues a delivery to each of them per notification and sends
+// the queued deliveries, retrying failures with backoff. Deliveries are
+// stored, so they survive restarts and users can read the log.
+type NotificationService struct {
+	repo         repository.NotificationRepositoryInterface
+	vapid        *notify.VAPID
+	allowPrivate bool
+	send         func(ctx context.Context, channel *models.NotificationChannel, delivery *models.NotificationDelivery) (int, error)
+	ids          ids.Generator
+	now          func() time.Time
+}
+
+// NewNotificationService returns a service sending push messages with vapid,
+// which may be nil to disable Web Push. allowPrivate permits plain http and
+// private network URLs, for local development only.
+func NewNotificationService(repo repository.NotificationRepositoryInterface, vapid *notify.VAPID, allowPrivate bool) *NotificationService {
+	client := notify.NewHTTPClient(notificationSendTimeout, allowPrivate)
+	s := &NotificationService{
+		repo:         repo,
+		vapid:        vapid,
+		allowPrivate: allowPrivate,
+		ids:          ids.Default,
+		now:          time.Now,
+	}
+	s.send = func(ctx context.Context, channel *models.NotificationChannel, delivery *models.NotificationDelivery) (int, error) {
+		if channel.Type == models.NotificationPush {
+			if s.vapid == nil {
+				return 0, ErrPushNotConfigured
+			}
+			return notify.SendPush(ctx, client, s.vapid, channel.URL, channel.Keys, []byte(delivery.Payload))
+		}
+		return notify.SendWebhook(ctx, client, channel.URL, channel.Secret, delivery.Event, delivery.WebhookID(), []byte(delivery.Payload))
+	}
+	return s
+}
+
+// SetIDGenerator sets the generator of webhook secrets and delivery IDs.
+func (s *NotificationService) SetIDGenerator(gen ids.Generator) {
+	s.ids = gen
+}
+
+// PushKey returns the VAPID public key browsers subscribe with, or "" when
+// Web Push is not configured.
+func (s *NotificationService) PushKey() string {
+	if s.vapid == nil {
+		return ""
+	}
+	return s.vapid.PublicKey
+}
+
+func (s *NotificationService) ListChannels(ctx context.Context, userID string) ([]models.NotificationChannel, error) {
+	logger.Debug(ctx, "service: NotificationService.ListChannels called", "userID", userID)
+
+	channels, err := s.repo.ListChannels(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "service: NotificationService.ListChannels - error fetching channels", "error", err)
+		return nil, err
+	}
+	return channels, nil
+}
+
+// CreateChannel registers a webhook or push subscription. A webhook is
+// given a signing secret, returned only here.
+func (s *NotificationService) CreateChannel(ctx context.Context, userID string, req models.NotificationChannelRequest) (*models.NotificationChannel, error) {
+	logger.Debug(ctx, "service: NotificationService.CreateChannel called", "userID", userID, "type", req.Type)
+
+	if err := s.validateChannel(req); err != nil {
+		logger.Warn(ctx, "service: NotificationService.CreateChannel - invalid channel", "error", err)
+		return nil, err
+	}
+
+	existing, err := s.repo.ListChannels(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "service: NotificationService.CreateChannel - error fetching channels", "error", err)
+		return nil, err
+	}
+	if len(existing) >= models.MaxNotificationChannels {
+		logger.Warn(ctx, "service: NotificationService.CreateChannel - too many channels", "count", len(existing))
+		return nil, ErrTooManyNotificationChannels
+	}
+
+	channel := &models.NotificationChannel{
+		UserID:    userID,
+		Type:      req.Type,
+		URL:       req.URL,
+		Events:    dedupeStrings(req.Events),
+		CreatedAt: s.now(),
+	}
+	if req.Type == models.NotificationPush {
+		channel.Keys = req.Keys
+	} else {
+		secret, err := s.ids.NewToken(webhookSecretBits)
+		if err != nil {
+			logger.Error(ctx, "service: NotificationService.CreateChannel - error generating secret", "error", err)
+			return nil, err
+		}
+		channel.Secret = secret
+	}
+	if err := s.repo.CreateChannel(ctx, channel); err != nil {
+		logger.Error(ctx, "service: NotificationService.CreateChannel - error storing channel", "error", err)
+		return nil, err
+	}
+
+	logger.Info(ctx, "service: NotificationService.CreateChannel - channel created", "id", channel.ID.Hex(), "type", channel.Type)
+	return channel, nil
+}
+
+func (s *NotificationService) validateChannel(req models.NotificationChannelRequest) error {
+	if req.Type != models.NotificationWebhook && req.Type != models.NotificationPush {
+		return fmt.Errorf("%w: type must be %q or %q", ErrInvalidNotificationChannel, models.NotificationWebhook, models.NotificationPush)
+	}
+	if err := validateWebhookURL(req.URL, s.allowPrivate); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidNotificationChannel, err)
+	}
+	for _, event := range req.Events {
+		if !slices.Contains(models.NotificationEvents, event) {
+			return fmt.Errorf("%w: unknown event %q", ErrInvalidNotificationChannel, event)
+		}
+	}
+	if req.Type == models.NotificationPush {
+		if s.vapid == nil {
+			return ErrPushNotConfigured
+		}
+		if err := notify.ValidatePushKeys(req.Keys); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidNotificationChannel, err)
+		}
+	}
+	return nil
+}
+
+// validateWebhookURL checks that rawURL is an https URL without credentials,
+// or http too when allowPrivate is set.
+func validateWebhookURL(rawURL string, allowPrivate bool) error {
+	if len(rawURL) > maxNotificationURLLength {
+		return fmt.Errorf("url must be at most %d characters", maxNotificationURLLength)
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" || u.User != nil || (u.Scheme != "https" && !(allowPrivate && u.Scheme == "http")) {
+		return errors.New("url must be an https URL")
+	}
+	return nil
+}
+
+func (s *NotificationService) DeleteChannel(ctx context.Context, userID, id string) error {
+	logger.Debug(ctx, "service: NotificationService.DeleteChannel called", "userID", userID, "id", id)
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		logger.Warn(ctx, "service: NotificationService.DeleteChannel - invalid channel ID", "id", id)
+		return ErrNotificationChannelNotFound
+	}
+	deleted, err := s.repo.DeleteChannel(ctx, userID, objectID)
+	if err != nil {
+		logger.Error(ctx, "service: NotificationService.DeleteChannel - error deleting channel", "error", err)
+		return err
+	}
+	if !deleted {
+		logger.Warn(ctx, "service: NotificationService.DeleteChannel - channel not found", "id", id)
+		return ErrNotificationChannelNotFound
+	}
+	s.forgetMilestones(ctx, userID)
+
+	logger.Info(ctx, "service: NotificationService.DeleteChannel - channel deleted", "id", id)
+	return nil
+}
+
+// forgetMilestones drops the user's milestone state once no channel is
+// subscribed to milestones, so subscribing again starts from the wishlist as
+// it is then rather than sending milestones reached in between. Failures are
+// only logged, as the channel is already gone.
+func (s *NotificationService) forgetMilestones(ctx context.Context, userID string) {
+	channels, err := s.repo.ListChannels(ctx, userID)
+	if err == nil {
+		for _, channel := range channels {
+			if channel.Subscribed(models.NotificationMilestone) {
+				return
+			}
+		}
+		err = s.repo.DeleteMilestones(ctx, userID)
+	}
+	if err != nil {
+		logger.Warn(ctx, "service: NotificationService.DeleteChannel - error dropping milestones", "error", err)
+	}
+}
+
+// ListDeliveries returns the user's latest deliveries, newest first. A limit
+// of 0 or less selects the default.
+func (s *NotificationService) ListDeliveries(ctx context.Context, userID string, limit int) ([]models.NotificationDelivery, error) {
+	logger.Debug(ctx, "service: NotificationService.ListDeliveries called", "userID", userID, "limit", limit)
+
+	if limit <= 0 {
+		limit = defaultDeliveryLimit
+	}
+	limit = min(limit, maxDeliveryLimit)
+	deliveries, err := s.repo.ListDeliveries(ctx, userID, limit)
+	if err != nil {
+		logger.Error(ctx, "service: NotificationService.ListDeliveries - error fetching deliveries", "error", err)
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// notificationPayload is the JSON body sent to webhooks and push
+// subscriptions.
+type notificationPayload struct {
+	Event      string            `json:"event"`
+	Title      string            `json:"title"`
+	Body       string            `json:"body"`
+	Data       map[string]string `json:"data"`
+	OccurredAt time.Time         `json:"occurredAt"`
+}
+
+// Notify queues n for each of the user's channels subscribed to its event,
+// returning how many deliveries were queued. Channels registered after the
+// event occurred, and channels already sent its key, are skipped, as are
+// events older than a day.
+func (s *NotificationService) Notify(ctx context.Context, userID string, n models.Notification) (int, error) {
+	logger.Debug(ctx, "service: NotificationService.Notify called", "userID", userID, "event", n.Event, "key", n.Key)
+
+	now := s.now()
+	if n.OccurredAt.Before(now.Add(-notificationMaxAge)) {
+		return 0, nil
+	}
+	channels, err := s.repo.ListChannels(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "service: NotificationService.Notify - error fetching channels", "error", err)
+		return 0, err
+	}
+
+	var payload []byte
+	queued := 0
+	for _, channel := range channels {
+		if !channel.Subscribed(n.Event) || channel.CreatedAt.After(n.OccurredAt) {
+			continue
+		}
+		if payload == nil {
+			data := n.Data
+			if data == nil {
+				data = map[string]string{}
+			}
+			payload, err = json.Marshal(notificationPayload{Event: n.Event, Title: n.Title, Body: n.Body, Data: data, OccurredAt: n.OccurredAt})
+			if err != nil {
+				return queued, err
+			}
+		}
+		stored, err := s.repo.EnqueueDelivery(ctx, &models.NotificationDelivery{
+			DeliveryID:    s.ids.NewID(),
+			UserID:        userID,
+			ChannelID:     channel.ID,
+			Event:         n.Event,
+			Key:           n.Key,
+			Payload:       string(payload),
+			Status:        models.DeliveryPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		})
+		if err != nil {
+			logger.Error(ctx, "service: NotificationService.Notify - error queueing delivery", "error", err)
+			return queued, err
+		}
+		if stored {
+			queued++
+		}
+	}
+
+	if queued > 0 {
+		logger.Info(ctx, "service: NotificationService.Notify - deliveries queued", "event", n.Event, "key", n.Key, "count", queued)
+	}
+	return queued, nil
+}
+
+// DispatchDue sends the deliveries that are due, returning how many were
+// delivered. A failure is retried with backoff until MaxDeliveryAttempts, or
+// fails the delivery at once when retrying cannot help.
+func (s *NotificationService) DispatchDue(ctx context.Context) (int, error) {
+	deliveries, err := s.repo.ClaimDueDeliveries(ctx, s.now(), deliveryLease, deliveryBatch)
+	if err != nil {
+		logger.Error(ctx, "service: NotificationService.DispatchDue - error claiming deliveries", "error", err)
+		return 0, err
+	}
+
+	delivered := 0
+	for i := range deliveries {
+		delivery := &deliveries[i]
+		if s.deliver(ctx, delivery) {
+			delivered++
+		}
+		if err := s.repo.UpdateDelivery(ctx, delivery); err != nil {
+			logger.Error(ctx, "service: NotificationService.DispatchDue - error saving delivery", "id", delivery.ID.Hex(), "error", err)
+			return delivered, err
+		}
+	}
+
+	if len(deliveries) > 0 {
+		logger.Debug(ctx, "service: NotificationService.DispatchDue - completed", "claimed", len(deliveries), "delivered", delivered)
+	}
+	return delivered, nil
+}
+
+// deliver sends delivery to its channel and records the outcome on it,
+// reporting whether it was delivered.
+func (s *NotificationService) deliver(ctx context.Context, delivery *models.NotificationDelivery) bool {
+	channel, err := s.repo.GetChannel(ctx, delivery.ChannelID)
+	if err != nil {
+		// Leave it pending; the lease makes it due again later.
+		delivery.LastError = err.Error()
+		return false
+	}
+	if channel == nil {
+		delivery.Status = models.DeliveryFailed
+		delivery.LastError = "channel deleted"
+		return false
+	}
+
+	status, err := s.send(ctx, channel, delivery)
+	now := s.now()
+	delivery.Attempts++
+	delivery.ResponseStatus = status
+	if err == nil {
+		delivery.Status = models.DeliveryDelivered
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+		return true
+	}
+
+	delivery.LastError = err.Error()
+	if permanentSendError(err) || delivery.Attempts >= models.MaxDeliveryAttempts {
+		delivery.Status = models.DeliveryFailed
+		logger.Warn(ctx, "service: NotificationService.DispatchDue - delivery failed", "id", delivery.ID.Hex(), "attempts", delivery.Attempts, "error", err)
+		return false
+	}
+	delivery.NextAttemptAt = now.Add(retryBackoff(delivery.Attempts))
+	logger.Debug(ctx, "service: NotificationService.DispatchDue - delivery will be retried", "id", delivery.ID.Hex(), "attempts", delivery.Attempts, "error", err)
+	return false
+}
+
+// permanentSendError reports whether retrying a failed send cannot help.
+func permanentSendError(err error) bool {
+	var statusErr *notify.StatusError
+	return errors.Is(err, ErrPushNotConfigured) || errors.Is(err, notify.ErrPushPayloadTooLarge) ||
+		(errors.As(err, &statusErr) && statusErr.Permanent())
+}
+
+// retryBackoff returns the wait after the given number of failed attempts.
+func retryBackoff(attempts int) time.Duration {
+	backoff := deliveryBackoff
+	for i := 1; i < attempts && backoff < maxDeliveryBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxDeliveryBackoff)
+}
+
+// dedupeStrings returns values without repeats, in first-appearance order.
+func dedupeStrings(values []string) []string {
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		if !slices.Contains(out, v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,357 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+	"github.com/graytonio/warframe-wishlist/internal/scheduler"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+)
+
+// maxOpportunityBodyItems bounds the rewards listed in an opportunity
+// notification's body.
+const maxOpportunityBodyItems = 5
+
+// NotificationWatcher turns finished foundry builds, newly active world
+// state opportunities and, with a MilestoneTracker, wishlist milestones into
+// notifications for the users who have channels, then sends whatever is
+// due. With a settings repository it also sends daily digests, and times are
+// given in each user's time zone. Each event is keyed so polling it again
+// does not notify twice.
+//
+// The watcher remembers each user's last digest, so the foundry and
+// opportunities a digest summarizes are read only once it is due again.
+// After a restart each user's current digest is built once more and dropped
+// by its key.
+type NotificationWatcher struct {
+	notifications *NotificationService
+	foundry       FoundryServiceInterface
+	opportunities OpportunityFinderInterface
+	milestones    *MilestoneTracker
+	settingsRepo  repository.SettingsRepositoryInterface
+	interval      time.Duration
+	now           func() time.Time
+
+	mu      sync.Mutex
+	digests map[string]time.Time // the last digest run handled, per user
+}
+
+func NewNotificationWatcher(notifications *NotificationService, foundry FoundryServiceInterface, opportunities OpportunityFinderInterface, interval time.Duration) *NotificationWatcher {
+	return &NotificationWatcher{
+		notifications: notifications,
+		foundry:       foundry,
+		opportunities: opportunities,
+		interval:      interval,
+		now:           time.Now,
+		digests:       map[string]time.Time{},
+	}
+}
+
+// SetMilestoneTracker has each poll check the wishlist milestones of the
+// users tracker saw change.
+func (w *NotificationWatcher) SetMilestoneTracker(tracker *MilestoneTracker) {
+	w.milestones = tracker
+}
+
+// SetSettingsRepository has each poll read the user's time zone and send
+// their daily digest.
+func (w *NotificationWatcher) SetSettingsRepository(settingsRepo repository.SettingsRepositoryInterface) {
+	w.settingsRepo = settingsRepo
+}
+
+// Run polls now and then every interval until ctx is done. Failures are
+// logged and retried on the next tick.
+func (w *NotificationWatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		if err := w.Poll(ctx); err != nil {
+			logger.Error(ctx, "service: NotificationWatcher.Run - poll failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll checks every user with a channel for events, queueing a notification
+// for each, then dispatches the due deliveries. A user whose events cannot
+// be read is logged and skipped.
+func (w *NotificationWatcher) Poll(ctx context.Context) error {
+	users, err := w.notifications.repo.ListSubscribedUsers(ctx)
+	if err != nil {
+		logger.Error(ctx, "service: NotificationWatcher.Poll - error fetching users", "error", err)
+		return err
+	}
+	// Changes by users without channels need no check, so they are dropped
+	// here too.
+	var changed map[string]bool
+	if w.milestones != nil {
+		changed = w.milestones.takeChanged()
+	}
+	for _, userID := range users {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err := w.pollUser(ctx, userID, changed[userID]); err != nil {
+			logger.Warn(ctx, "service: NotificationWatcher.Poll - error checking user", "userID", userID, "error", err)
+		}
+	}
+	w.forgetDigests(users)
+	_, err = w.notifications.DispatchDue(ctx)
+	return err
+}
+
+// pollUser reads only the events the user's channels subscribe to. changed
+// reports whether the user's wishlist or progress changed since the last
+// poll.
+func (w *NotificationWatcher) pollUser(ctx context.Context, userID string, changed bool) error {
+	channels, err := w.notifications.ListChannels(ctx, userID)
+	if err != nil {
+		return err
+	}
+	subscribed := func(event string) bool {
+		for _, channel := range channels {
+			if channel.Subscribed(event) {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Settings that cannot be read leave the user in UTC without a digest
+	// for this poll.
+	var settings *models.UserSettings
+	if w.settingsRepo != nil {
+		settings, err = w.settingsRepo.GetByUserID(ctx, userID)
+		if err != nil {
+			logger.Warn(ctx, "service: NotificationWatcher.pollUser - error fetching settings", "userID", userID, "error", err)
+			settings = nil
+		}
+	}
+	loc := settings.Location()
+	now := w.now()
+	digestHour, digestMinute, digest := settings.DigestClock()
+	var digestRun time.Time
+	if digest {
+		digestRun = scheduler.LastDailyRun(now, loc, digestHour, digestMinute)
+		digest = w.digestDue(userID, digestRun, channels)
+	}
+
+	var foundry *models.Foundry
+	if subscribed(models.NotificationFoundryFinished) || digest {
+		if foundry, err = w.foundry.GetFoundry(ctx, userID); err != nil {
+			return err
+		}
+	}
+	if subscribed(models.NotificationFoundryFinished) {
+		for _, build := range foundry.Builds {
+			if !build.Finished {
+				continue
+			}
+			if _, err := w.notifications.Notify(ctx, userID, foundryNotification(build, loc)); err != nil {
+				return err
+			}
+		}
+	}
+
+	var opportunities *models.Opportunities
+	if subscribed(models.NotificationOpportunity) || digest {
+		if opportunities, err = w.opportunities.GetOpportunities(ctx, userID); err != nil {
+			return err
+		}
+	}
+	if subscribed(models.NotificationOpportunity) {
+		for _, opportunity := range opportunities.Opportunities {
+			if _, err := w.notifications.Notify(ctx, userID, opportunityNotification(opportunity, now)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if digest {
+		if _, err := w.notifications.Notify(ctx, userID, digestNotification(now, loc, digestHour, digestMinute, foundry, opportunities)); err != nil {
+			return err
+		}
+		w.mu.Lock()
+		w.digests[userID] = digestRun
+		w.mu.Unlock()
+	}
+
+	if w.milestones != nil && subscribed(models.NotificationMilestone) {
+		if err := w.milestones.check(ctx, userID, changed); err != nil {
+			if changed {
+				// Retried on the next poll.
+				w.milestones.MarkChanged(userID)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// digestDue reports whether the digest run at run is yet to be handled for
+// userID and one of channels subscribed to digests was registered by then,
+// so Notify would queue it.
+func (w *NotificationWatcher) digestDue(userID string, run time.Time, channels []models.NotificationChannel) bool {
+	w.mu.Lock()
+	last, handled := w.digests[userID]
+	w.mu.Unlock()
+	if handled && !run.After(last) {
+		return false
+	}
+	for _, channel := range channels {
+		if channel.Subscribed(models.NotificationDigest) && !channel.CreatedAt.After(run) {
+			return true
+		}
+	}
+	return false
+}
+
+// forgetDigests drops the last digests of users who no longer have a
+// channel.
+func (w *NotificationWatcher) forgetDigests(users []string) {
+	subscribed := make(map[string]bool, len(users))
+	for _, userID := range users {
+		subscribed[userID] = true
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for userID := range w.digests {
+		if !subscribed[userID] {
+			delete(w.digests, userID)
+		}
+	}
+}
+
+// foundryNotification gives the time the build finished in loc.
+func foundryNotification(build models.FoundryBuildStatus, loc *time.Location) models.Notification {
+	readyAt := scheduler.CompletesAt(build.StartedAt, time.Duration(build.BuildTime)*time.Second, loc)
+	return models.Notification{
+		Event: models.NotificationFoundryFinished,
+		Key:   "foundry:" + build.ID.Hex(),
+		Title: build.Name + " is ready",
+		Body:  build.Name + " finished building in your foundry at " + readyAt.Format(models.DigestTimeLayout) + ".",
+		Data: map[string]string{
+			"buildId":      build.ID.Hex(),
+			"uniqueName":   build.UniqueName,
+			"readyAt":      readyAt.UTC().Format(time.RFC3339),
+			"readyAtLocal": readyAt.Format(time.RFC3339),
+		},
+		OccurredAt: readyAt,
+	}
+}
+
+// digestNotification summarizes the foundry and opportunities as of now for
+// the latest digest due in loc. It is keyed by the digest's local date, so
+// each day's is sent once whenever it is polled.
+func digestNotification(now time.Time, loc *time.Location, hour, minute int, foundry *models.Foundry, opportunities *models.Opportunities) models.Notification {
+	run := scheduler.LastDailyRun(now, loc, hour, minute)
+	endOfDay := scheduler.EndOfLocalDay(now, loc)
+
+	ready, building, finishingToday := 0, 0, 0
+	var next time.Time
+	for _, build := range foundry.Builds {
+		if build.Finished {
+			ready++
+			continue
+		}
+		building++
+		if !build.ReadyAt.After(endOfDay) {
+			finishingToday++
+		}
+		if next.IsZero() || build.ReadyAt.Before(next) {
+			next = build.ReadyAt
+		}
+	}
+
+	var body string
+	if ready == 0 && building == 0 {
+		body = "Nothing is building in your foundry."
+	} else {
+		body = fmt.Sprintf("%d ready to claim and %d building in your foundry", ready, building)
+		if finishingToday > 0 {
+			body += fmt.Sprintf(", %d finishing later today", finishingToday)
+		}
+		body += "."
+		if !next.IsZero() {
+			body += " The next finishes at " + next.In(loc).Format(models.DigestTimeLayout) + "."
+		}
+	}
+	if n := len(opportunities.Opportunities); n > 0 {
+		body += fmt.Sprintf(" %d active opportunities reward what your wishlist needs.", n)
+	}
+
+	date := run.In(loc).Format(time.DateOnly)
+	return models.Notification{
+		Event: models.NotificationDigest,
+		Key:   "digest:" + date,
+		Title: "Your wishlist digest",
+		Body:  body,
+		Data: map[string]string{
+			"date":           date,
+			"readyBuilds":    strconv.Itoa(ready),
+			"building":       strconv.Itoa(building),
+			"finishingToday": strconv.Itoa(finishingToday),
+			"opportunities":  strconv.Itoa(len(opportunities.Opportunities)),
+			"nextDigestAt":   scheduler.NextDailyRun(now, loc, hour, minute).UTC().Format(time.RFC3339),
+		},
+		OccurredAt: run,
+	}
+}
+
+// opportunityNotification describes an opportunity as appearing when it
+// activated, so a channel registered while it was already active is not
+// sent it.
+func opportunityNotification(opportunity models.Opportunity, now time.Time) models.Notification {
+	occurredAt := opportunity.Activation
+	if occurredAt.IsZero() || occurredAt.After(now) {
+		occurredAt = now
+	}
+
+	node := opportunity.NodeName
+	if node == "" {
+		node = opportunity.Node
+	}
+	kind := opportunity.Kind
+	if kind != "" {
+		kind = strings.ToUpper(kind[:1]) + kind[1:]
+	}
+
+	var rewards []string
+	for _, m := range opportunity.Materials {
+		rewards = append(rewards, strconv.Itoa(m.Count)+"x "+m.Name)
+	}
+	rewards = append(rewards, opportunity.Relics...)
+	if opportunity.RelicTier != "" && len(rewards) == 0 {
+		rewards = append(rewards, opportunity.RelicTier+" relics")
+	}
+	if len(rewards) > maxOpportunityBodyItems {
+		rewards = append(rewards[:maxOpportunityBodyItems], fmt.Sprintf("%d more", len(rewards)-maxOpportunityBodyItems))
+	}
+
+	data := map[string]string{
+		"eventId": opportunity.ID,
+		"kind":    opportunity.Kind,
+		"node":    opportunity.Node,
+	}
+	if !opportunity.Expiry.IsZero() {
+		data["expiry"] = opportunity.Expiry.UTC().Format(time.RFC3339)
+	}
+	return models.Notification{
+		Event:      models.NotificationOpportunity,
+		Key:        "opportunity:" + opportunity.ID,
+		Title:      kind + " at " + node,
+		Body:       "Rewards what your wishlist needs: " + strings.Join(rewards, ", ") + ".",
+		Data:       data,
+		OccurredAt: occurredAt,
+	}
+}
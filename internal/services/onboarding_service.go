@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+)
+
+// maxStarterItems bounds the starter wishlist.
+const maxStarterItems = 50
+
+// ParseStarterItems reads the ONBOARDING_STARTER_ITEMS format: uniqueNames
+// separated by ",", each optionally followed by "=" and the quantity to add
+// (default 1):
+//
+//	/Lotus/Types/Recipes/Components/FormaBlueprint=3, /Lotus/Powersuits/Excalibur/Excalibur
+//
+// An empty spec returns no items.
+func ParseStarterItems(spec string) ([]models.WishlistItem, error) {
+	var items []models.WishlistItem
+	seen := make(map[string]bool)
+	for _, text := range strings.Split(spec, ",") {
+		text = strings.TrimSpace(text)
+		if text == "" {
+			continue
+		}
+		uniqueName, rawQuantity, hasQuantity := strings.Cut(text, "=")
+		uniqueName = strings.TrimSpace(uniqueName)
+		if !strings.HasPrefix(uniqueName, "/") {
+			return nil, fmt.Errorf("starter item %q: uniqueName must start with /", text)
+		}
+		if seen[uniqueName] {
+			return nil, fmt.Errorf("starter item %q: listed twice", uniqueName)
+		}
+		seen[uniqueName] = true
+
+		quantity := 1
+		if hasQuantity {
+			var err error
+			quantity, err = strconv.Atoi(strings.TrimSpace(rawQuantity))
+			if err != nil || quantity <= 0 {
+				return nil, fmt.Errorf("starter item %q: quantity must be a positive integer", text)
+			}
+		}
+		items = append(items, models.WishlistItem{UniqueName: uniqueName, Quantity: quantity})
+	}
+	if len(items) > maxStarterItems {
+		return nil, fmt.Errorf("at most %d starter items are allowed, got %d", maxStarterItems, len(items))
+	}
+	return items, nil
+}
+
+// OnboardingService recognizes users reading their wishlist for the first
+// time, before they have one, so clients can show a first-run experience,
+// and seeds those users' wishlists with the server's starter items. Users
+// who set SkipStarterWishlist start with an empty wishlist instead, and stay
+// new until they add their first item.
+type OnboardingService struct {
+	wishlistRepo repository.WishlistRepositoryInterface
+	settingsRepo repository.SettingsRepositoryInterface
+	itemRepo     repository.ItemRepositoryInterface
+	starterItems []models.WishlistItem
+	// materialsCache is optional; seeding invalidates the user's entry.
+	materialsCache MaterialsCache
+	// onboarding shares one first access per user between concurrent
+	// requests, so the wishlist is seeded once.
+	onboarding flightGroup[bool]
+}
+
+// NewOnboardingService seeds new wishlists with starterItems; with none it
+// only recognizes new users.
+func NewOnboardingService(wishlistRepo repository.WishlistRepositoryInterface, settingsRepo repository.SettingsRepositoryInterface, itemRepo repository.ItemRepositoryInterface, starterItems []models.WishlistItem) *OnboardingService {
+	return &OnboardingService{
+		wishlistRepo: wishlistRepo,
+		settingsRepo: settingsRepo,
+		itemRepo:     itemRepo,
+		starterItems: starterItems,
+	}
+}
+
+// SetMaterialsCache makes seeding a wishlist invalidate the user's entry in
+// cache.
+func (s *OnboardingService) SetMaterialsCache(cache MaterialsCache) {
+	s.materialsCache = cache
+}
+
+// Onboard reports whether userID had no wishlist yet, seeding it with the
+// starter items unless the user opted out. A failure to seed is logged and
+// leaves the wishlist empty; the user is still reported as new. A wishlist
+// created while seeding, by another instance or the user's first item, is
+// kept and the user is not new.
+func (s *OnboardingService) Onboard(ctx context.Context, userID string) (bool, error) {
+	logger.Debug(ctx, "service: OnboardingService.Onboard called", "userID", userID)
+
+	isNew, _, err := s.onboarding.do(ctx, userID, func(ctx context.Context) (bool, error) {
+		wishlist, err := s.wishlistRepo.GetByUserID(ctx, userID)
+		if err != nil {
+			logger.Error(ctx, "service: OnboardingService.Onboard - error fetching wishlist", "error", err)
+			return false, err
+		}
+		if wishlist != nil {
+			return false, nil
+		}
+		return s.seed(ctx, userID), nil
+	})
+	return isNew, err
+}
+
+// seed creates userID's wishlist from the starter items that exist in the
+// item data, unless there are none or the user opted out. It reports false
+// only when the wishlist turned out to exist already.
+func (s *OnboardingService) seed(ctx context.Context, userID string) bool {
+	if len(s.starterItems) == 0 {
+		return true
+	}
+
+	settings, err := s.settingsRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		logger.Warn(ctx, "service: OnboardingService.seed - failed to get settings, not seeding", "error", err)
+		return true
+	}
+	if settings != nil && settings.SkipStarterWishlist {
+		logger.Debug(ctx, "service: OnboardingService.seed - user opted out of the starter wishlist")
+		return true
+	}
+
+	uniqueNames := make([]string, len(s.starterItems))
+	for i, item := range s.starterItems {
+		uniqueNames[i] = item.UniqueName
+	}
+	found, err := s.itemRepo.FindByUniqueNames(ctx, uniqueNames)
+	if err != nil {
+		logger.Warn(ctx, "service: OnboardingService.seed - failed to look up starter items, not seeding", "error", err)
+		return true
+	}
+
+	now := time.Now()
+	items := make([]models.WishlistItem, 0, len(s.starterItems))
+	for _, item := range s.starterItems {
+		if found[item.UniqueName] == nil {
+			logger.Warn(ctx, "service: OnboardingService.seed - starter item not found, skipping", "uniqueName", item.UniqueName)
+			continue
+		}
+		item.AddedAt = now
+		items = append(items, item)
+	}
+	if len(items) == 0 {
+		return true
+	}
+
+	created, err := s.wishlistRepo.CreateIfAbsent(ctx, &models.Wishlist{UserID: userID, Items: items})
+	if err != nil {
+		logger.Warn(ctx, "service: OnboardingService.seed - failed to create starter wishlist", "error", err)
+		return true
+	}
+	if !created {
+		logger.Info(ctx, "service: OnboardingService.seed - wishlist created meanwhile, not seeding")
+		return false
+	}
+	invalidateMaterials(ctx, s.materialsCache, userID)
+	logger.Info(ctx, "service: OnboardingService.seed - seeded starter wishlist", "itemCount", len(items))
+	return true
+}
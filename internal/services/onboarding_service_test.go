@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository/memory"
+)
+
+func TestParseStarterItems(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     string
+		expected []models.WishlistItem
+		wantErr  bool
+	}{
+		{name: "empty", spec: ""},
+		{name: "default quantity", spec: "/Lotus/Forma", expected: []models.WishlistItem{{UniqueName: "/Lotus/Forma", Quantity: 1}}},
+		{
+			name: "quantities and spaces",
+			spec: " /Lotus/Forma = 3 , /Lotus/Excalibur,",
+			expected: []models.WishlistItem{
+				{UniqueName: "/Lotus/Forma", Quantity: 3},
+				{UniqueName: "/Lotus/Excalibur", Quantity: 1},
+			},
+		},
+		{name: "not a uniqueName", spec: "Forma", wantErr: true},
+		{name: "listed twice", spec: "/Lotus/Forma,/Lotus/Forma=2", wantErr: true},
+		{name: "zero quantity", spec: "/Lotus/Forma=0", wantErr: true},
+		{name: "bad quantity", spec: "/Lotus/Forma=lots", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := ParseStarterItems(tt.spec)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected an error, got %+v", items)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(items) != len(tt.expected) {
+				t.Fatalf("expected %+v, got %+v", tt.expected, items)
+			}
+			for i := range items {
+				if items[i].UniqueName != tt.expected[i].UniqueName || items[i].Quantity != tt.expected[i].Quantity {
+					t.Errorf("item %d: expected %+v, got %+v", i, tt.expected[i], items[i])
+				}
+			}
+		})
+	}
+}
+
+func newStarterItems() *memory.ItemRepository {
+	items := memory.NewItemRepository()
+	items.Add("resources", models.Item{UniqueName: "/Lotus/Forma", Name: "Forma", Category: "Resources"})
+	items.Add("warframes", models.Item{UniqueName: "/Lotus/Excalibur", Name: "Excalibur", Category: "Warframes"})
+	return items
+}
+
+var starterItems = []models.WishlistItem{
+	{UniqueName: "/Lotus/Forma", Quantity: 3},
+	{UniqueName: "/Lotus/Removed", Quantity: 1},
+	{UniqueName: "/Lotus/Excalibur", Quantity: 1},
+}
+
+func TestOnboardingService_Onboard(t *testing.T) {
+	tests := []struct {
+		name          string
+		existing      bool
+		skipStarter   bool
+		starterItems  []models.WishlistItem
+		expectedNew   bool
+		expectedItems []string
+	}{
+		{name: "seeds a new user", starterItems: starterItems, expectedNew: true, expectedItems: []string{"/Lotus/Forma", "/Lotus/Excalibur"}},
+		{name: "existing wishlist", existing: true, starterItems: starterItems, expectedItems: []string{"/Lotus/Braton"}},
+		{name: "opted out", skipStarter: true, starterItems: starterItems, expectedNew: true},
+		{name: "no starter items", expectedNew: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			wishlists := memory.NewWishlistRepository()
+			if tt.existing {
+				wishlists.Create(ctx, &models.Wishlist{UserID: "user-123", Items: []models.WishlistItem{{UniqueName: "/Lotus/Braton", Quantity: 1}}})
+			}
+			settingsRepo := memory.NewSettingsRepository()
+			settingsRepo.Upsert(ctx, &models.UserSettings{UserID: "user-123", SkipStarterWishlist: tt.skipStarter})
+			service := NewOnboardingService(wishlists, settingsRepo, newStarterItems(), tt.starterItems)
+
+			isNew, err := service.Onboard(ctx, "user-123")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if isNew != tt.expectedNew {
+				t.Errorf("expected new %v, got %v", tt.expectedNew, isNew)
+			}
+
+			wishlist, _ := wishlists.GetByUserID(ctx, "user-123")
+			var got []string
+			if wishlist != nil {
+				for _, item := range wishlist.Items {
+					got = append(got, item.UniqueName)
+				}
+			}
+			if len(got) != len(tt.expectedItems) {
+				t.Fatalf("expected items %v, got %v", tt.expectedItems, got)
+			}
+			for i := range got {
+				if got[i] != tt.expectedItems[i] {
+					t.Errorf("item %d: expected %s, got %s", i, tt.expectedItems[i], got[i])
+				}
+			}
+			if tt.expectedNew && len(tt.expectedItems) > 0 {
+				if wishlist.Items[0].Quantity != 3 || wishlist.Items[0].AddedAt.IsZero() {
+					t.Errorf("expected the starter quantity and an added time, got %+v", wishlist.Items[0])
+				}
+			}
+
+			if isNew, _ := service.Onboard(ctx, "user-123"); isNew && len(tt.expectedItems) > 0 {
+				t.Error("expected a seeded user not to be new on their next access")
+			}
+		})
+	}
+}
+
+func TestOnboardingService_Onboard_Errors(t *testing.T) {
+	ctx := context.Background()
+	dbErr := errors.New("database error")
+
+	service := NewOnboardingService(&mocks.MockWishlistRepository{
+		GetByUserIDFunc: func(ctx context.Context, userID string) (*models.Wishlist, error) {
+			return nil, dbErr
+		},
+	}, memory.NewSettingsRepository(), newStarterItems(), starterItems)
+	if _, err := service.Onboard(ctx, "user-123"); !errors.Is(err, dbErr) {
+		t.Errorf("expected the wishlist error, got %v", err)
+	}
+
+	// A wishlist that cannot be seeded leaves the user new with an empty one.
+	service = NewOnboardingService(&mocks.MockWishlistRepository{
+		CreateIfAbsentFunc: func(ctx context.Context, wishlist *models.Wishlist) (bool, error) {
+			return false, dbErr
+		},
+	}, memory.NewSettingsRepository(), newStarterItems(), starterItems)
+	isNew, err := service.Onboard(ctx, "user-123")
+	if err != nil || !isNew {
+		t.Errorf("expected a new user despite the seeding failure, got %v %v", isNew, err)
+	}
+}
+
+// racingWishlistRepository misses the user's wishlist on the first read, as
+// when another instance or the user's first item creates it right after.
+type racingWishlistRepository struct {
+	*memory.WishlistRepository
+	missed bool
+}
+
+func (r *racingWishlistRepository) GetByUserID(ctx context.Context, userID string) (*models.Wishlist, error) {
+	if !r.missed {
+		r.missed = true
+		return nil, nil
+	}
+	return r.WishlistRepository.GetByUserID(ctx, userID)
+}
+
+func TestOnboardingService_Onboard_WishlistCreatedMeanwhile(t *testing.T) {
+	ctx := context.Background()
+	wishlists := memory.NewWishlistRepository()
+	wishlists.Create(ctx, &models.Wishlist{UserID: "user-123", Items: []models.WishlistItem{{UniqueName: "/Lotus/Braton", Quantity: 1}}})
+	service := NewOnboardingService(&racingWishlistRepository{WishlistRepository: wishlists}, memory.NewSettingsRepository(), newStarterItems(), starterItems)
+
+	isNew, err := service.Onboard(ctx, "user-123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if isNew {
+		t.Error("expected a user whose wishlist was created meanwhile not to be new")
+	}
+
+	wishlist, _ := wishlists.GetByUserID(ctx, "user-123")
+	if len(wishlist.Items) != 1 || wishlist.Items[0].UniqueName != "/Lotus/Braton" {
+		t.Errorf("expected the existing wishlist to be kept, got %+v", wishlist.Items)
+	}
+}
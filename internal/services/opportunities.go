@@ -0,0 +1,167 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+)
+
+// OpportunityFinder flags the active world state events that reward what a
+// wishlist still needs.
+type OpportunityFinder struct {
+	worldState       WorldStateServiceInterface
+	materialResolver MaterialResolverInterface
+	relicResolver    RelicResolverInterface
+	itemRepo         repository.ItemRepositoryInterface
+	now              func() time.Time
+}
+
+func NewOpportunityFinder(worldState WorldStateServiceInterface, materialResolver MaterialResolverInterface, relicResolver RelicResolverInterface, itemRepo repository.ItemRepositoryInterface) *OpportunityFinder {
+	return &OpportunityFinder{
+		worldState:       worldState,
+		materialResolver: materialResolver,
+		relicResolver:    relicResolver,
+		itemRepo:         itemRepo,
+		now:              time.Now,
+	}
+}
+
+// GetOpportunities matches the active alerts and invasions against the
+// user's outstanding materials and needed relics, and the active fissures
+// against the tiers of those relics. Events covering the most come first,
+// ties going to the one expiring soonest.
+func (f *OpportunityFinder) GetOpportunities(ctx context.Context, userID string) (*models.Opportunities, error) {
+	logger.Debug(ctx, "service: OpportunityFinder.GetOpportunities called", "userID", userID)
+
+	result := &models.Opportunities{Opportunities: []models.Opportunity{}}
+	state := f.worldState.Current()
+	if state == nil {
+		logger.Debug(ctx, "service: OpportunityFinder.GetOpportunities - no world state yet")
+		return result, nil
+	}
+	result.FetchedAt = state.FetchedAt
+
+	now := f.now()
+	var active []models.WorldStateEvent
+	for _, event := range state.Events {
+		if event.Expiry.IsZero() || event.Expiry.After(now) {
+			active = append(active, event)
+		}
+	}
+	if len(active) == 0 {
+		return result, nil
+	}
+
+	materials, err := f.materialResolver.GetMaterials(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "service: OpportunityFinder.GetOpportunities - error resolving materials", "error", err)
+		return nil, err
+	}
+	result.Truncated = materials.Truncated
+	result.TruncatedReason = materials.TruncatedReason
+	result.Degradation = materials.Degradation
+
+	relics, err := f.relicResolver.GetRelicRequirements(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "service: OpportunityFinder.GetOpportunities - error resolving relics", "error", err)
+		return nil, err
+	}
+
+	outstanding := make(map[string]models.MaterialRequirement)
+	for _, m := range materials.Materials {
+		if m.Remaining > 0 {
+			outstanding[m.UniqueName] = m
+		}
+	}
+	relicsByTier := make(map[string][]string)
+	relicsByUniqueName := make(map[string]string)
+	for _, relic := range relics.Relics {
+		relicsByTier[relic.Tier] = append(relicsByTier[relic.Tier], relic.Name)
+		if relic.UniqueName != "" {
+			relicsByUniqueName[relic.UniqueName] = relic.Name
+		}
+	}
+
+	var nodes []string
+	for _, event := range active {
+		opportunity := models.Opportunity{
+			WorldStateEvent: event,
+			Materials:       []models.OpportunityMaterial{},
+			Relics:          []string{},
+		}
+		for _, reward := range event.Rewards {
+			if m, ok := outstanding[reward.UniqueName]; ok {
+				opportunity.Materials = append(opportunity.Materials, models.OpportunityMaterial{
+					UniqueName: m.UniqueName,
+					Name:       m.Name,
+					Count:      reward.Count,
+					Remaining:  m.Remaining,
+				})
+			}
+			if name, ok := relicsByUniqueName[reward.UniqueName]; ok {
+				opportunity.Relics = append(opportunity.Relics, name)
+			}
+		}
+		if event.Kind == models.WorldStateFissure {
+			opportunity.Relics = append(opportunity.Relics, relicsByTier[event.RelicTier]...)
+		}
+		if len(opportunity.Materials) == 0 && len(opportunity.Relics) == 0 {
+			continue
+		}
+		result.Opportunities = append(result.Opportunities, opportunity)
+		nodes = append(nodes, event.Node)
+	}
+
+	f.nameNodes(ctx, result.Opportunities, nodes)
+
+	slices.SortStableFunc(result.Opportunities, func(a, b models.Opportunity) int {
+		if c := cmp.Compare(len(b.Materials)+len(b.Relics), len(a.Materials)+len(a.Relics)); c != 0 {
+			return c
+		}
+		if c := compareExpiry(a.Expiry, b.Expiry); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	logger.Info(ctx, "service: OpportunityFinder.GetOpportunities - completed", "activeEvents", len(active), "opportunityCount", len(result.Opportunities))
+	return result, nil
+}
+
+// nameNodes sets each opportunity's node name from the star chart nodes in
+// the item data, falling back to the node's uniqueName.
+func (f *OpportunityFinder) nameNodes(ctx context.Context, opportunities []models.Opportunity, nodes []string) {
+	var items map[string]*models.Item
+	if len(nodes) > 0 {
+		var err error
+		items, err = f.itemRepo.FindByUniqueNames(ctx, nodes)
+		if err != nil {
+			logger.Warn(ctx, "service: OpportunityFinder.GetOpportunities - error fetching node names", "error", err)
+		}
+	}
+	for i := range opportunities {
+		opportunities[i].NodeName = opportunities[i].Node
+		if item := items[opportunities[i].Node]; item != nil && item.Name != "" {
+			opportunities[i].NodeName = item.Name
+		}
+	}
+}
+
+// compareExpiry orders the soonest expiry first; events without one, such as
+// invasions, go last.
+func compareExpiry(a, b time.Time) int {
+	switch {
+	case a.IsZero() && b.IsZero():
+		return 0
+	case a.IsZero():
+		return 1
+	case b.IsZero():
+		return -1
+	}
+	return a.Compare(b)
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository/memory"
+)
+
+var opportunityNow = time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+// newOpportunityFinder serves a world state with an alert rewarding cells,
+// an invasion rewarding an Axi relic and detonite, an expired alert, an
+// unrelated invasion and Axi and Lith fissures, to a wishlist still needing
+// cells and Axi and Neo relics.
+func newOpportunityFinder(t *testing.T) *OpportunityFinder {
+	t.Helper()
+
+	state := &models.WorldState{FetchedAt: opportunityNow.Add(-time.Minute), Events: []models.WorldStateEvent{
+		{ID: "alert", Kind: models.WorldStateAlert, Node: "SolNode27", Expiry: opportunityNow.Add(time.Hour), Rewards: []models.WorldStateReward{
+			{UniqueName: "/Lotus/OrokinCell", Count: 3},
+			{UniqueName: "/Lotus/Catalyst", Count: 1},
+		}},
+		{ID: "expired", Kind: models.WorldStateAlert, Node: "SolNode27", Expiry: opportunityNow.Add(-time.Minute), Rewards: []models.WorldStateReward{
+			{UniqueName: "/Lotus/OrokinCell", Count: 10},
+		}},
+		{ID: "invasion", Kind: models.WorldStateInvasion, Node: "SolNode64", Rewards: []models.WorldStateReward{
+			{UniqueName: "/Lotus/Relics/AxiI3Intact", Count: 1},
+			{UniqueName: "/Lotus/Detonite", Count: 3},
+		}},
+		{ID: "unrelated", Kind: models.WorldStateInvasion, Node: "SolNode65", Rewards: []models.WorldStateReward{
+			{UniqueName: "/Lotus/Fieldron", Count: 3},
+		}},
+		{ID: "axi", Kind: models.WorldStateFissure, Node: "SolNode203", RelicTier: "Axi", Expiry: opportunityNow.Add(30 * time.Minute), Rewards: []models.WorldStateReward{}},
+		{ID: "lith", Kind: models.WorldStateFissure, Node: "SolNode4", RelicTier: "Lith", Expiry: opportunityNow.Add(30 * time.Minute), Rewards: []models.WorldStateReward{}},
+	}}
+
+	items := memory.NewItemRepository()
+	items.Add("node", models.Item{UniqueName: "SolNode203", Name: "Abaddon"}, models.Item{UniqueName: "SolNode64", Name: "Unda"})
+
+	finder := NewOpportunityFinder(
+		&mocks.MockWorldStateService{CurrentFunc: func() *models.WorldState { return state }},
+		&mocks.MockMaterialResolver{GetMaterialsFunc: func(ctx context.Context, userID string) (*models.MaterialsResponse, error) {
+			return &models.MaterialsResponse{Materials: []models.MaterialRequirement{
+				{UniqueName: "/Lotus/OrokinCell", Name: "Orokin Cell", TotalCount: 5, Remaining: 5},
+				{UniqueName: "/Lotus/Detonite", Name: "Detonite Injector", TotalCount: 4, Owned: 4, Remaining: 0},
+			}}, nil
+		}},
+		&mocks.MockRelicResolver{GetRelicRequirementsFunc: func(ctx context.Context, userID string) (*models.RelicRequirements, error) {
+			return &models.RelicRequirements{Relics: []models.RelicRequirement{
+				{Name: "Axi I3", Tier: "Axi", UniqueName: "/Lotus/Relics/AxiI3Intact"},
+				{Name: "Axi A1", Tier: "Axi"},
+				{Name: "Neo N2", Tier: "Neo"},
+			}}, nil
+		}},
+		items,
+	)
+	finder.now = func() time.Time { return opportunityNow }
+	return finder
+}
+
+func TestOpportunityFinder_GetOpportunities(t *testing.T) {
+	result, err := newOpportunityFinder(t).GetOpportunities(context.Background(), "user-123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !result.FetchedAt.Equal(opportunityNow.Add(-time.Minute)) {
+		t.Errorf("unexpected fetchedAt %v", result.FetchedAt)
+	}
+	var ids []string
+	for _, o := range result.Opportunities {
+		ids = append(ids, o.ID)
+	}
+	// The Axi fissure matches two relics and expires first; the invasion
+	// has no expiry. The Lith fissure, the unrelated invasion and the
+	// expired alert are left out.
+	if !slices.Equal(ids, []string{"axi", "alert", "invasion"}) {
+		t.Fatalf("expected axi, alert and invasion, got %v", ids)
+	}
+
+	axi := result.Opportunities[0]
+	if axi.NodeName != "Abaddon" || !slices.Equal(axi.Relics, []string{"Axi I3", "Axi A1"}) || len(axi.Materials) != 0 {
+		t.Errorf("unexpected fissure %+v", axi)
+	}
+	alert := result.Opportunities[1]
+	if alert.NodeName != "SolNode27" || len(alert.Materials) != 1 || alert.Materials[0] != (models.OpportunityMaterial{UniqueName: "/Lotus/OrokinCell", Name: "Orokin Cell", Count: 3, Remaining: 5}) {
+		t.Errorf("expected the cells on the alert under its node's uniqueName, got %+v", alert)
+	}
+	invasion := result.Opportunities[2]
+	if invasion.NodeName != "Unda" || !slices.Equal(invasion.Relics, []string{"Axi I3"}) || len(invasion.Materials) != 0 {
+		t.Errorf("expected only the relic on the invasion, with detonite already owned, got %+v", invasion)
+	}
+}
+
+func TestOpportunityFinder_GetOpportunities_NoWorldState(t *testing.T) {
+	finder := NewOpportunityFinder(&mocks.MockWorldStateService{}, &mocks.MockMaterialResolver{
+		GetMaterialsFunc: func(ctx context.Context, userID string) (*models.MaterialsResponse, error) {
+			t.Error("materials should not be resolved")
+			return nil, nil
+		},
+	}, &mocks.MockRelicResolver{}, memory.NewItemRepository())
+
+	result, err := finder.GetOpportunities(context.Background(), "user-123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Opportunities == nil || len(result.Opportunities) != 0 || !result.FetchedAt.IsZero() {
+		t.Errorf("expected no opportunities, got %+v", result)
+	}
+}
+
+func TestOpportunityFinder_GetOpportunities_Errors(t *testing.T) {
+	dbErr := errors.New("database error")
+
+	finder := newOpportunityFinder(t)
+	finder.materialResolver = &mocks.MockMaterialResolver{GetMaterialsFunc: func(ctx context.Context, userID string) (*models.MaterialsResponse, error) {
+		return nil, dbErr
+	}}
+	if _, err := finder.GetOpportunities(context.Background(), "user-123"); !errors.Is(err, dbErr) {
+		t.Errorf("expected the materials error, got %v", err)
+	}
+
+	finder = newOpportunityFinder(t)
+	finder.relicResolver = &mocks.MockRelicResolver{GetRelicRequirementsFunc: func(ctx context.Context, userID string) (*models.RelicRequirements, error) {
+		return nil, dbErr
+	}}
+	if _, err := finder.GetOpportunities(context.Background(), "user-123"); !errors.Is(err, dbErr) {
+		t.Errorf("expected the relics error, got %v", err)
+	}
+
+	// Node names are cosmetic, so a lookup failure falls back to the
+	// uniqueNames.
+	finder = newOpportunityFinder(t)
+	finder.itemRepo = &mocks.MockItemRepository{FindByUniqueNamesFunc: func(ctx context.Context, uniqueNames []string) (map[string]*models.Item, error) {
+		return nil, dbErr
+	}}
+	result, err := finder.GetOpportunities(context.Background(), "user-123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Opportunities[0].NodeName != "SolNode203" {
+		t.Errorf("expected the node uniqueName as its name, got %q", result.Opportunities[0].NodeName)
+	}
+}
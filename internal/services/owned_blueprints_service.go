@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/graytonio/warframe-wishlist/internal/models"
@@ -17,9 +18,32 @@ var (
 	ErrBlueprintNotOwned       = errors.New("blueprint not owned")
 )
 
+// MaxOwnedComponentCount only rejects typos; nobody keeps thousands of one
+// crafted component.
+const MaxOwnedComponentCount = 10_000
+
+// MaxOwnedBlueprintQuantity does the same for stocked consumable blueprints.
+const MaxOwnedBlueprintQuantity = 10_000
+
+var (
+	ErrInvalidBlueprintQuantity = fmt.Errorf("blueprint quantity must be between 0 and %d", MaxOwnedBlueprintQuantity)
+	ErrInvalidComponentCount    = fmt.Errorf("component count must be between 0 and %d", MaxOwnedComponentCount)
+	ErrComponentNotOwned        = errors.New("component not owned")
+	ErrTooManyBlueprints        = errors.New("owned blueprint limit reached")
+)
+
+// OwnedBlueprintsService manages the parts a user owns: reusable blueprints,
+// and crafted components the material resolver takes off what is left to
+// build. Components are not checked against the item catalog, since most only
+// exist embedded in their parent item.
 type OwnedBlueprintsService struct {
 	ownedBPRepo repository.OwnedBlueprintsRepositoryInterface
 	itemRepo    repository.ItemRepositoryInterface
+	// materialsCache is optional; blueprint changes drop the user's cached
+	// materials response from it.
+	materialsCache MaterialsCache
+	// maxBlueprints caps the blueprints one user owns; 0 leaves it uncapped.
+	maxBlueprints int
 }
 
 func NewOwnedBlueprintsService(ownedBPRepo repository.OwnedBlueprintsRepositoryInterface, itemRepo repository.ItemRepositoryInterface) *OwnedBlueprintsService {
@@ -29,6 +53,28 @@ func NewOwnedBlueprintsService(ownedBPRepo repository.OwnedBlueprintsRepositoryI
 	}
 }
 
+// SetMaterialsCache makes blueprint changes invalidate the user's entry in
+// cache.
+func (s *OwnedBlueprintsService) SetMaterialsCache(cache MaterialsCache) {
+	s.materialsCache = cache
+}
+
+// SetMaxBlueprints makes adding blueprints fail when the user would own more
+// than max; 0 leaves it uncapped.
+func (s *OwnedBlueprintsService) SetMaxBlueprints(max int) {
+	s.maxBlueprints = max
+}
+
+// checkBlueprintCount returns ErrTooManyBlueprints when adding more
+// blueprints to the owned ones would exceed the cap.
+func (s *OwnedBlueprintsService) checkBlueprintCount(ctx context.Context, owned, adding int) error {
+	if s.maxBlueprints > 0 && owned+adding > s.maxBlueprints {
+		logger.Warn(ctx, "service: OwnedBlueprintsService - too many blueprints", "owned", owned, "adding", adding, "max", s.maxBlueprints)
+		return fmt.Errorf("%w: at most %d blueprints", ErrTooManyBlueprints, s.maxBlueprints)
+	}
+	return nil
+}
+
 func (s *OwnedBlueprintsService) GetOwnedBlueprints(ctx context.Context, userID string) (*models.OwnedBlueprints, error) {
 	logger.Debug(ctx, "service: OwnedBlueprintsService.GetOwnedBlueprints called", "userID", userID)
 
@@ -43,6 +89,7 @@ func (s *OwnedBlueprintsService) GetOwnedBlueprints(ctx context.Context, userID
 		ownedBP = &models.OwnedBlueprints{
 			UserID:     userID,
 			Blueprints: []models.OwnedBlueprint{},
+			Components: []models.OwnedComponent{},
 			CreatedAt:  time.Now(),
 			UpdatedAt:  time.Now(),
 		}
@@ -85,6 +132,7 @@ func (s *OwnedBlueprintsService) AddBlueprint(ctx context.Context, userID string
 			Blueprints: []models.OwnedBlueprint{
 				{
 					UniqueName: req.UniqueName,
+					Quantity:   1,
 					AddedAt:    time.Now(),
 				},
 			},
@@ -94,6 +142,7 @@ func (s *OwnedBlueprintsService) AddBlueprint(ctx context.Context, userID string
 			logger.Error(ctx, "service: OwnedBlueprintsService.AddBlueprint - error creating owned blueprints", "error", err)
 			return err
 		}
+		invalidateMaterials(ctx, s.materialsCache, userID)
 		logger.Info(ctx, "service: OwnedBlueprintsService.AddBlueprint - created new owned blueprints with blueprint", "uniqueName", req.UniqueName)
 		return nil
 	}
@@ -105,10 +154,14 @@ func (s *OwnedBlueprintsService) AddBlueprint(ctx context.Context, userID string
 			return ErrBlueprintAlreadyOwned
 		}
 	}
+	if err := s.checkBlueprintCount(ctx, len(ownedBP.Blueprints), 1); err != nil {
+		return err
+	}
 
 	// Add blueprint
 	newBlueprint := models.OwnedBlueprint{
 		UniqueName: req.UniqueName,
+		Quantity:   1,
 		AddedAt:    time.Now(),
 	}
 
@@ -118,6 +171,7 @@ func (s *OwnedBlueprintsService) AddBlueprint(ctx context.Context, userID string
 		return err
 	}
 
+	invalidateMaterials(ctx, s.materialsCache, userID)
 	logger.Info(ctx, "service: OwnedBlueprintsService.AddBlueprint - blueprint added successfully", "uniqueName", req.UniqueName)
 	return nil
 }
@@ -156,6 +210,7 @@ func (s *OwnedBlueprintsService) RemoveBlueprint(ctx context.Context, userID, un
 		return err
 	}
 
+	invalidateMaterials(ctx, s.materialsCache, userID)
 	logger.Info(ctx, "service: OwnedBlueprintsService.RemoveBlueprint - blueprint removed successfully", "uniqueName", uniqueName)
 	return nil
 }
@@ -188,6 +243,7 @@ func (s *OwnedBlueprintsService) BulkAddBlueprints(ctx context.Context, userID s
 		}
 		validBlueprints = append(validBlueprints, models.OwnedBlueprint{
 			UniqueName: uniqueName,
+			Quantity:   1,
 			AddedAt:    time.Now(),
 		})
 	}
@@ -223,6 +279,9 @@ func (s *OwnedBlueprintsService) BulkAddBlueprints(ctx context.Context, userID s
 		logger.Debug(ctx, "service: OwnedBlueprintsService.BulkAddBlueprints - all blueprints already owned")
 		return nil
 	}
+	if err := s.checkBlueprintCount(ctx, len(existingSet), len(newBlueprints)); err != nil {
+		return err
+	}
 
 	// Create if doesn't exist, then bulk add
 	if ownedBP == nil {
@@ -243,6 +302,7 @@ func (s *OwnedBlueprintsService) BulkAddBlueprints(ctx context.Context, userID s
 		}
 	}
 
+	invalidateMaterials(ctx, s.materialsCache, userID)
 	logger.Info(ctx, "service: OwnedBlueprintsService.BulkAddBlueprints - blueprints added successfully", "count", len(newBlueprints))
 	return nil
 }
@@ -267,6 +327,178 @@ func (s *OwnedBlueprintsService) ClearAllBlueprints(ctx context.Context, userID
 		return err
 	}
 
+	invalidateMaterials(ctx, s.materialsCache, userID)
 	logger.Info(ctx, "service: OwnedBlueprintsService.ClearAllBlueprints - all blueprints cleared successfully")
 	return nil
 }
+
+// SetBlueprintQuantity records that the user has quantity copies of a
+// blueprint, which is how consumable blueprints, such as Forma, are stocked;
+// the material resolver takes them off what is left to farm. A reusable
+// blueprint is owned once, so its quantity can only be 1. A quantity of 0
+// removes the blueprint. Like components, blueprints missing from the item
+// catalog are accepted as consumable, since most only exist embedded in the
+// item they build.
+func (s *OwnedBlueprintsService) SetBlueprintQuantity(ctx context.Context, userID, uniqueName string, quantity int) error {
+	logger.Debug(ctx, "service: OwnedBlueprintsService.SetBlueprintQuantity called", "userID", userID, "uniqueName", uniqueName, "quantity", quantity)
+
+	if quantity < 0 || quantity > MaxOwnedBlueprintQuantity {
+		logger.Warn(ctx, "service: OwnedBlueprintsService.SetBlueprintQuantity - invalid quantity", "uniqueName", uniqueName, "quantity", quantity)
+		return ErrInvalidBlueprintQuantity
+	}
+
+	item, err := s.itemRepo.FindByUniqueName(ctx, uniqueName)
+	if err != nil {
+		logger.Error(ctx, "service: OwnedBlueprintsService.SetBlueprintQuantity - error finding item", "error", err)
+		return err
+	}
+	if item != nil && !item.ConsumeOnBuild && quantity > 1 {
+		logger.Warn(ctx, "service: OwnedBlueprintsService.SetBlueprintQuantity - reusable blueprint owned more than once", "uniqueName", uniqueName, "quantity", quantity)
+		return fmt.Errorf("%w: reusable blueprints are owned once", ErrInvalidBlueprintQuantity)
+	}
+
+	ownedBP, err := s.ownedBPRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "service: OwnedBlueprintsService.SetBlueprintQuantity - error fetching owned blueprints", "error", err)
+		return err
+	}
+
+	owned := false
+	if ownedBP != nil {
+		for _, bp := range ownedBP.Blueprints {
+			if bp.UniqueName == uniqueName {
+				owned = true
+				break
+			}
+		}
+	}
+
+	blueprint := models.OwnedBlueprint{UniqueName: uniqueName, Quantity: quantity, AddedAt: time.Now()}
+	switch {
+	case quantity == 0:
+		if !owned {
+			logger.Debug(ctx, "service: OwnedBlueprintsService.SetBlueprintQuantity - nothing to remove")
+			return nil
+		}
+		err = s.ownedBPRepo.RemoveBlueprint(ctx, userID, uniqueName)
+	case ownedBP == nil:
+		logger.Debug(ctx, "service: OwnedBlueprintsService.SetBlueprintQuantity - creating new owned blueprints for user")
+		if err := s.checkBlueprintCount(ctx, 0, 1); err != nil {
+			return err
+		}
+		err = s.ownedBPRepo.Create(ctx, &models.OwnedBlueprints{UserID: userID, Blueprints: []models.OwnedBlueprint{blueprint}})
+	default:
+		if !owned {
+			if err := s.checkBlueprintCount(ctx, len(ownedBP.Blueprints), 1); err != nil {
+				return err
+			}
+		}
+		err = s.ownedBPRepo.SetBlueprint(ctx, userID, blueprint)
+	}
+	if err != nil {
+		logger.Error(ctx, "service: OwnedBlueprintsService.SetBlueprintQuantity - error setting blueprint", "error", err)
+		return err
+	}
+
+	invalidateMaterials(ctx, s.materialsCache, userID)
+	logger.Info(ctx, "service: OwnedBlueprintsService.SetBlueprintQuantity - blueprint set successfully", "uniqueName", uniqueName, "quantity", quantity)
+	return nil
+}
+
+// SetComponentCount records that the user has count of a crafted component. A
+// count of 0 removes it.
+func (s *OwnedBlueprintsService) SetComponentCount(ctx context.Context, userID, uniqueName string, count int) error {
+	logger.Debug(ctx, "service: OwnedBlueprintsService.SetComponentCount called", "userID", userID, "uniqueName", uniqueName, "count", count)
+
+	if count < 0 || count > MaxOwnedComponentCount {
+		logger.Warn(ctx, "service: OwnedBlueprintsService.SetComponentCount - invalid count", "uniqueName", uniqueName, "count", count)
+		return ErrInvalidComponentCount
+	}
+
+	ownedBP, err := s.ownedBPRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "service: OwnedBlueprintsService.SetComponentCount - error fetching owned parts", "error", err)
+		return err
+	}
+
+	if count == 0 {
+		if ownedBP == nil {
+			logger.Debug(ctx, "service: OwnedBlueprintsService.SetComponentCount - nothing to remove")
+			return nil
+		}
+		err = s.ownedBPRepo.RemoveComponent(ctx, userID, uniqueName)
+	} else if ownedBP == nil {
+		logger.Debug(ctx, "service: OwnedBlueprintsService.SetComponentCount - creating new owned parts for user")
+		err = s.ownedBPRepo.Create(ctx, &models.OwnedBlueprints{
+			UserID:     userID,
+			Components: []models.OwnedComponent{{UniqueName: uniqueName, Count: count, AddedAt: time.Now()}},
+		})
+	} else {
+		err = s.ownedBPRepo.SetComponent(ctx, userID, models.OwnedComponent{UniqueName: uniqueName, Count: count, AddedAt: time.Now()})
+	}
+	if err != nil {
+		logger.Error(ctx, "service: OwnedBlueprintsService.SetComponentCount - error setting component", "error", err)
+		return err
+	}
+
+	invalidateMaterials(ctx, s.materialsCache, userID)
+	logger.Info(ctx, "service: OwnedBlueprintsService.SetComponentCount - component set successfully", "uniqueName", uniqueName, "count", count)
+	return nil
+}
+
+func (s *OwnedBlueprintsService) RemoveComponent(ctx context.Context, userID, uniqueName string) error {
+	logger.Debug(ctx, "service: OwnedBlueprintsService.RemoveComponent called", "userID", userID, "uniqueName", uniqueName)
+
+	ownedBP, err := s.ownedBPRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "service: OwnedBlueprintsService.RemoveComponent - error fetching owned parts", "error", err)
+		return err
+	}
+
+	found := false
+	if ownedBP != nil {
+		for _, component := range ownedBP.Components {
+			if component.UniqueName == uniqueName {
+				found = true
+				break
+			}
+		}
+	}
+	if !found {
+		logger.Warn(ctx, "service: OwnedBlueprintsService.RemoveComponent - component not owned", "uniqueName", uniqueName)
+		return ErrComponentNotOwned
+	}
+
+	if err := s.ownedBPRepo.RemoveComponent(ctx, userID, uniqueName); err != nil {
+		logger.Error(ctx, "service: OwnedBlueprintsService.RemoveComponent - error removing component", "error", err)
+		return err
+	}
+
+	invalidateMaterials(ctx, s.materialsCache, userID)
+	logger.Info(ctx, "service: OwnedBlueprintsService.RemoveComponent - component removed successfully", "uniqueName", uniqueName)
+	return nil
+}
+
+func (s *OwnedBlueprintsService) ClearAllComponents(ctx context.Context, userID string) error {
+	logger.Debug(ctx, "service: OwnedBlueprintsService.ClearAllComponents called", "userID", userID)
+
+	ownedBP, err := s.ownedBPRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "service: OwnedBlueprintsService.ClearAllComponents - error fetching owned parts", "error", err)
+		return err
+	}
+
+	if ownedBP == nil {
+		logger.Debug(ctx, "service: OwnedBlueprintsService.ClearAllComponents - no owned components to clear")
+		return nil
+	}
+
+	if err := s.ownedBPRepo.ClearComponents(ctx, userID); err != nil {
+		logger.Error(ctx, "service: OwnedBlueprintsService.ClearAllComponents - error clearing components", "error", err)
+		return err
+	}
+
+	invalidateMaterials(ctx, s.materialsCache, userID)
+	logger.Info(ctx, "service: OwnedBlueprintsService.ClearAllComponents - all components cleared successfully")
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/graytonio/warframe-wishlist/internal/mocks"
 	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository/memory"
 )
 
 func TestOwnedBlueprintsService_GetOwnedBlueprints(t *testing.T) {
@@ -451,3 +452,131 @@ func TestOwnedBlueprintsService_AddBlueprint_WithTimestamp(t *testing.T) {
 		t.Error("AddedAt timestamp should be set to current time")
 	}
 }
+
+func TestOwnedBlueprintsService_Components(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewOwnedBlueprintsRepository()
+	service := NewOwnedBlueprintsService(repo, &mocks.MockItemRepository{})
+
+	if err := service.SetComponentCount(ctx, "user-123", "/Lotus/Chassis", 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if owned, _ := repo.GetByUserID(ctx, "user-123"); owned != nil {
+		t.Errorf("expected a zero count not to create a document, got %+v", owned)
+	}
+
+	if err := service.SetComponentCount(ctx, "user-123", "/Lotus/Chassis", 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	service.SetComponentCount(ctx, "user-123", "/Lotus/Systems", 1)
+	service.SetComponentCount(ctx, "user-123", "/Lotus/Chassis", 2)
+	owned, _ := service.GetOwnedBlueprints(ctx, "user-123")
+	if len(owned.Components) != 2 || owned.Components[0].Count != 2 || owned.Components[0].AddedAt.IsZero() {
+		t.Errorf("expected the chassis count updated, got %+v", owned.Components)
+	}
+
+	for _, count := range []int{-1, MaxOwnedComponentCount + 1} {
+		if err := service.SetComponentCount(ctx, "user-123", "/Lotus/Chassis", count); !errors.Is(err, ErrInvalidComponentCount) {
+			t.Errorf("expected ErrInvalidComponentCount for %d, got %v", count, err)
+		}
+	}
+
+	service.SetComponentCount(ctx, "user-123", "/Lotus/Systems", 0)
+	if err := service.RemoveComponent(ctx, "user-123", "/Lotus/Systems"); !errors.Is(err, ErrComponentNotOwned) {
+		t.Errorf("expected ErrComponentNotOwned after a zero count, got %v", err)
+	}
+	if err := service.RemoveComponent(ctx, "user-123", "/Lotus/Chassis"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	service.SetComponentCount(ctx, "user-123", "/Lotus/Chassis", 1)
+	if err := service.ClearAllComponents(ctx, "user-123"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if owned, _ := service.GetOwnedBlueprints(ctx, "user-123"); len(owned.Components) != 0 {
+		t.Errorf("expected no components left, got %+v", owned.Components)
+	}
+}
+
+func TestOwnedBlueprintsService_MaxBlueprints(t *testing.T) {
+	ctx := context.Background()
+	items := memory.NewItemRepository()
+	items.Add("misc",
+		models.Item{UniqueName: "/Lotus/A", Name: "A"},
+		models.Item{UniqueName: "/Lotus/B", Name: "B"},
+		models.Item{UniqueName: "/Lotus/C", Name: "C"},
+	)
+	service := NewOwnedBlueprintsService(memory.NewOwnedBlueprintsRepository(), items)
+	service.SetMaxBlueprints(2)
+
+	if err := service.AddBlueprint(ctx, "user-123", models.AddBlueprintRequest{UniqueName: "/Lotus/A"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err := service.BulkAddBlueprints(ctx, "user-123", models.BulkAddBlueprintsRequest{UniqueNames: []string{"/Lotus/A", "/Lotus/B", "/Lotus/C"}})
+	if !errors.Is(err, ErrTooManyBlueprints) {
+		t.Fatalf("expected ErrTooManyBlueprints for a bulk add past the cap, got %v", err)
+	}
+	if err := service.BulkAddBlueprints(ctx, "user-123", models.BulkAddBlueprintsRequest{UniqueNames: []string{"/Lotus/A", "/Lotus/B"}}); err != nil {
+		t.Fatalf("expected already owned blueprints not to count twice, got %v", err)
+	}
+	if err := service.AddBlueprint(ctx, "user-123", models.AddBlueprintRequest{UniqueName: "/Lotus/C"}); !errors.Is(err, ErrTooManyBlueprints) {
+		t.Errorf("expected ErrTooManyBlueprints at the cap, got %v", err)
+	}
+}
+
+func TestOwnedBlueprintsService_SetBlueprintQuantity(t *testing.T) {
+	ctx := context.Background()
+	items := memory.NewItemRepository()
+	items.Add("misc",
+		models.Item{UniqueName: "/Lotus/Reusable", Name: "Reusable"},
+		models.Item{UniqueName: "/Lotus/FormaBlueprint", Name: "Forma Blueprint", ConsumeOnBuild: true},
+		models.Item{UniqueName: "/Lotus/OrokinCatalystBlueprint", Name: "Orokin Catalyst Blueprint", ConsumeOnBuild: true},
+	)
+	repo := memory.NewOwnedBlueprintsRepository()
+	service := NewOwnedBlueprintsService(repo, items)
+	service.SetMaxBlueprints(2)
+
+	if err := service.SetBlueprintQuantity(ctx, "user-123", "/Lotus/FormaBlueprint", 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if owned, _ := repo.GetByUserID(ctx, "user-123"); owned != nil {
+		t.Errorf("expected a zero quantity not to create a document, got %+v", owned)
+	}
+
+	if err := service.SetBlueprintQuantity(ctx, "user-123", "/Lotus/FormaBlueprint", 3); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := service.SetBlueprintQuantity(ctx, "user-123", "/Lotus/Reusable", 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := service.SetBlueprintQuantity(ctx, "user-123", "/Lotus/FormaBlueprint", 7); err != nil {
+		t.Fatalf("expected restocking an owned blueprint to ignore the cap, got %v", err)
+	}
+	owned, _ := service.GetOwnedBlueprints(ctx, "user-123")
+	if len(owned.Blueprints) != 2 || owned.Blueprints[0].Quantity != 7 || owned.Blueprints[1].Quantity != 1 {
+		t.Errorf("expected 7 Forma blueprints and the reusable one, got %+v", owned.Blueprints)
+	}
+
+	if err := service.SetBlueprintQuantity(ctx, "user-123", "/Lotus/OrokinCatalystBlueprint", 1); !errors.Is(err, ErrTooManyBlueprints) {
+		t.Errorf("expected ErrTooManyBlueprints past the cap, got %v", err)
+	}
+	if err := service.SetBlueprintQuantity(ctx, "user-123", "/Lotus/Reusable", 2); !errors.Is(err, ErrInvalidBlueprintQuantity) {
+		t.Errorf("expected ErrInvalidBlueprintQuantity for a reusable blueprint owned twice, got %v", err)
+	}
+	for _, quantity := range []int{-1, MaxOwnedBlueprintQuantity + 1} {
+		if err := service.SetBlueprintQuantity(ctx, "user-123", "/Lotus/FormaBlueprint", quantity); !errors.Is(err, ErrInvalidBlueprintQuantity) {
+			t.Errorf("expected ErrInvalidBlueprintQuantity for %d, got %v", quantity, err)
+		}
+	}
+
+	if err := service.SetBlueprintQuantity(ctx, "user-123", "/Lotus/FormaBlueprint", 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if owned, _ := service.GetOwnedBlueprints(ctx, "user-123"); len(owned.Blueprints) != 1 || owned.Blueprints[0].UniqueName != "/Lotus/Reusable" {
+		t.Errorf("expected only the reusable blueprint left, got %+v", owned.Blueprints)
+	}
+
+	// Blueprints only embedded in the item they build are stocked too.
+	if err := service.SetBlueprintQuantity(ctx, "user-123", "/Lotus/Types/Recipes/Weapons/SapientPrimaryBlueprint", 2); err != nil {
+		t.Errorf("expected an uncatalogued blueprint to be accepted, got %v", err)
+	}
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+)
+
+const (
+	// MaxOwnedMaterialCount is well above any in-game stack, so it only
+	// rejects typos and overflow attempts.
+	MaxOwnedMaterialCount = 1_000_000_000
+	// MaxOwnedMaterialsPerRequest bounds a bulk update.
+	MaxOwnedMaterialsPerRequest = 500
+)
+
+var (
+	ErrInvalidMaterialCount = fmt.Errorf("material count must be between 0 and %d", MaxOwnedMaterialCount)
+	ErrTooManyMaterials     = fmt.Errorf("at most %d materials can be set at once", MaxOwnedMaterialsPerRequest)
+	ErrMaterialNotOwned     = errors.New("material not owned")
+)
+
+// OwnedMaterialsService manages a user's material inventory. Materials are not
+// checked against the item catalog: the resolver also reports components that
+// only exist embedded in their parent item, and those must be recordable too.
+type OwnedMaterialsService struct {
+	ownedMaterialsRepo repository.OwnedMaterialsRepositoryInterface
+	// materialsCache is optional; inventory changes drop the user's cached
+	// materials response from it, since it carries owned and remaining counts.
+	materialsCache MaterialsCache
+}
+
+func NewOwnedMaterialsService(ownedMaterialsRepo repository.OwnedMaterialsRepositoryInterface) *OwnedMaterialsService {
+	return &OwnedMaterialsService{
+		ownedMaterialsRepo: ownedMaterialsRepo,
+	}
+}
+
+// SetMaterialsCache makes inventory changes invalidate the user's entry in
+// cache.
+func (s *OwnedMaterialsService) SetMaterialsCache(cache MaterialsCache) {
+	s.materialsCache = cache
+}
+
+func (s *OwnedMaterialsService) GetOwnedMaterials(ctx context.Context, userID string) (*models.OwnedMaterials, error) {
+	logger.Debug(ctx, "service: OwnedMaterialsService.GetOwnedMaterials called", "userID", userID)
+
+	materials, err := s.ownedMaterialsRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "service: OwnedMaterialsService.GetOwnedMaterials - repository error", "error", err)
+		return nil, err
+	}
+
+	logger.Debug(ctx, "service: OwnedMaterialsService.GetOwnedMaterials - completed", "materialCount", len(materials))
+	return &models.OwnedMaterials{
+		UserID:    userID,
+		Materials: materials,
+	}, nil
+}
+
+func (s *OwnedMaterialsService) SetMaterialCount(ctx context.Context, userID, uniqueName string, count int) error {
+	logger.Debug(ctx, "service: OwnedMaterialsService.SetMaterialCount called", "userID", userID, "uniqueName", uniqueName, "count", count)
+
+	if err := validateMaterialCount(count); err != nil {
+		logger.Warn(ctx, "service: OwnedMaterialsService.SetMaterialCount - invalid material", "uniqueName", uniqueName, "error", err)
+		return err
+	}
+
+	if err := s.ownedMaterialsRepo.SetCounts(ctx, userID, map[string]int{uniqueName: count}); err != nil {
+		logger.Error(ctx, "service: OwnedMaterialsService.SetMaterialCount - error setting count", "error", err)
+		return err
+	}
+
+	invalidateMaterials(ctx, s.materialsCache, userID)
+	logger.Info(ctx, "service: OwnedMaterialsService.SetMaterialCount - count set successfully", "uniqueName", uniqueName, "count", count)
+	return nil
+}
+
+// SetMaterialCounts applies every count in req in one write. The whole request
+// is validated first so a bad entry never leaves a partial update, and every
+// bad entry is reported in one ValidationError; if a material is listed more
+// than once, the last count wins.
+func (s *OwnedMaterialsService) SetMaterialCounts(ctx context.Context, userID string, req models.SetOwnedMaterialsRequest) error {
+	logger.Debug(ctx, "service: OwnedMaterialsService.SetMaterialCounts called", "userID", userID, "count", len(req.Materials))
+
+	if len(req.Materials) > MaxOwnedMaterialsPerRequest {
+		logger.Warn(ctx, "service: OwnedMaterialsService.SetMaterialCounts - too many materials", "count", len(req.Materials))
+		return ErrTooManyMaterials
+	}
+
+	counts := make(map[string]int, len(req.Materials))
+	var problems ValidationError
+	for i, material := range req.Materials {
+		uniqueName, err := models.CanonicalUniqueName(material.UniqueName)
+		if err != nil {
+			logger.Warn(ctx, "service: OwnedMaterialsService.SetMaterialCounts - invalid uniqueName", "uniqueName", material.UniqueName, "error", err)
+			problems.Add(fmt.Sprintf("materials[%d].uniqueName", i), material.UniqueName, err)
+			continue
+		}
+		if err := validateMaterialCount(material.Count); err != nil {
+			logger.Warn(ctx, "service: OwnedMaterialsService.SetMaterialCounts - invalid material", "uniqueName", uniqueName, "error", err)
+			problems.Add(fmt.Sprintf("materials[%d].count", i), uniqueName, err)
+			continue
+		}
+		counts[uniqueName] = material.Count
+	}
+	if err := problems.Err(); err != nil {
+		return err
+	}
+
+	if len(counts) == 0 {
+		logger.Debug(ctx, "service: OwnedMaterialsService.SetMaterialCounts - empty request, nothing to do")
+		return nil
+	}
+
+	if err := s.ownedMaterialsRepo.SetCounts(ctx, userID, counts); err != nil {
+		logger.Error(ctx, "service: OwnedMaterialsService.SetMaterialCounts - error setting counts", "error", err)
+		return err
+	}
+
+	invalidateMaterials(ctx, s.materialsCache, userID)
+	logger.Info(ctx, "service: OwnedMaterialsService.SetMaterialCounts - counts set successfully", "count", len(counts))
+	return nil
+}
+
+func (s *OwnedMaterialsService) RemoveMaterial(ctx context.Context, userID, uniqueName string) error {
+	logger.Debug(ctx, "service: OwnedMaterialsService.RemoveMaterial called", "userID", userID, "uniqueName", uniqueName)
+
+	materials, err := s.ownedMaterialsRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "service: OwnedMaterialsService.RemoveMaterial - error fetching owned materials", "error", err)
+		return err
+	}
+
+	found := false
+	for _, material := range materials {
+		if material.UniqueName == uniqueName {
+			found = true
+			break
+		}
+	}
+	if !found {
+		logger.Warn(ctx, "service: OwnedMaterialsService.RemoveMaterial - material not owned", "uniqueName", uniqueName)
+		return ErrMaterialNotOwned
+	}
+
+	if err := s.ownedMaterialsRepo.SetCounts(ctx, userID, map[string]int{uniqueName: 0}); err != nil {
+		logger.Error(ctx, "service: OwnedMaterialsService.RemoveMaterial - error removing material", "error", err)
+		return err
+	}
+
+	invalidateMaterials(ctx, s.materialsCache, userID)
+	logger.Info(ctx, "service: OwnedMaterialsService.RemoveMaterial - material removed successfully", "uniqueName", uniqueName)
+	return nil
+}
+
+func (s *OwnedMaterialsService) ClearAllMaterials(ctx context.Context, userID string) error {
+	logger.Debug(ctx, "service: OwnedMaterialsService.ClearAllMaterials called", "userID", userID)
+
+	if err := s.ownedMaterialsRepo.ClearAll(ctx, userID); err != nil {
+		logger.Error(ctx, "service: OwnedMaterialsService.ClearAllMaterials - error clearing materials", "error", err)
+		return err
+	}
+
+	invalidateMaterials(ctx, s.materialsCache, userID)
+	logger.Info(ctx, "service: OwnedMaterialsService.ClearAllMaterials - all materials cleared successfully")
+	return nil
+}
+
+func validateMaterialCount(count int) error {
+	if count < 0 || count > MaxOwnedMaterialCount {
+		return ErrInvalidMaterialCount
+	}
+	return nil
+}
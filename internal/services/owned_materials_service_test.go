@@ -0,0 +1,255 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+func TestOwnedMaterialsService_GetOwnedMaterials(t *testing.T) {
+	t.Run("returns inventory", func(t *testing.T) {
+		mockRepo := &mocks.MockOwnedMaterialsRepository{
+			GetByUserIDFunc: func(ctx context.Context, userID string) ([]models.OwnedMaterial, error) {
+				return []models.OwnedMaterial{{UserID: userID, UniqueName: "/Ferrite", Count: 500}}, nil
+			},
+		}
+
+		owned, err := NewOwnedMaterialsService(mockRepo).GetOwnedMaterials(context.Background(), "user-123")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if owned.UserID != "user-123" || len(owned.Materials) != 1 || owned.Materials[0].Count != 500 {
+			t.Errorf("unexpected inventory: %+v", owned)
+		}
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		mockRepo := &mocks.MockOwnedMaterialsRepository{
+			GetByUserIDFunc: func(ctx context.Context, userID string) ([]models.OwnedMaterial, error) {
+				return nil, errors.New("database error")
+			},
+		}
+
+		if _, err := NewOwnedMaterialsService(mockRepo).GetOwnedMaterials(context.Background(), "user-123"); err == nil {
+			t.Error("expected error but got none")
+		}
+	})
+}
+
+func TestOwnedMaterialsService_SetMaterialCount(t *testing.T) {
+	tests := []struct {
+		name          string
+		count         int
+		expectedError error
+		expectWrite   bool
+	}{
+		{name: "positive count", count: 250, expectWrite: true},
+		{name: "zero count removes", count: 0, expectWrite: true},
+		{name: "negative count", count: -1, expectedError: ErrInvalidMaterialCount},
+		{name: "count above maximum", count: MaxOwnedMaterialCount + 1, expectedError: ErrInvalidMaterialCount},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var written map[string]int
+			mockRepo := &mocks.MockOwnedMaterialsRepository{
+				SetCountsFunc: func(ctx context.Context, userID string, counts map[string]int) error {
+					written = counts
+					return nil
+				},
+			}
+
+			err := NewOwnedMaterialsService(mockRepo).SetMaterialCount(context.Background(), "user-123", "/Ferrite", tt.count)
+			if !errors.Is(err, tt.expectedError) {
+				t.Fatalf("expected error %v, got %v", tt.expectedError, err)
+			}
+			if tt.expectWrite && written["/Ferrite"] != tt.count {
+				t.Errorf("expected count %d to be written, got %v", tt.count, written)
+			}
+			if !tt.expectWrite && written != nil {
+				t.Errorf("expected no write, got %v", written)
+			}
+		})
+	}
+}
+
+func TestOwnedMaterialsService_SetMaterialCounts(t *testing.T) {
+	tooMany := make([]models.OwnedMaterialCount, MaxOwnedMaterialsPerRequest+1)
+	for i := range tooMany {
+		tooMany[i] = models.OwnedMaterialCount{UniqueName: "/Ferrite", Count: 1}
+	}
+
+	tests := []struct {
+		name           string
+		materials      []models.OwnedMaterialCount
+		expectedError  error
+		expectedCounts map[string]int
+	}{
+		{
+			name: "canonicalizes names and keeps the last duplicate",
+			materials: []models.OwnedMaterialCount{
+				{UniqueName: "Lotus/Ferrite/", Count: 10},
+				{UniqueName: "/Lotus/Plastids", Count: 0},
+				{UniqueName: "/Lotus/Ferrite", Count: 20},
+			},
+			expectedCounts: map[string]int{"/Lotus/Ferrite": 20, "/Lotus/Plastids": 0},
+		},
+		{
+			name:      "empty request writes nothing",
+			materials: nil,
+		},
+		{
+			name:          "invalid uniqueName rejects the whole request",
+			materials:     []models.OwnedMaterialCount{{UniqueName: "/Lotus/Ferrite", Count: 1}, {UniqueName: "/Lotus/../Plastids", Count: 1}},
+			expectedError: models.ErrInvalidUniqueName,
+		},
+		{
+			name:          "missing uniqueName",
+			materials:     []models.OwnedMaterialCount{{Count: 1}},
+			expectedError: models.ErrUniqueNameRequired,
+		},
+		{
+			name:          "negative count rejects the whole request",
+			materials:     []models.OwnedMaterialCount{{UniqueName: "/Lotus/Ferrite", Count: 1}, {UniqueName: "/Lotus/Plastids", Count: -5}},
+			expectedError: ErrInvalidMaterialCount,
+		},
+		{
+			name:          "too many materials",
+			materials:     tooMany,
+			expectedError: ErrTooManyMaterials,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var written map[string]int
+			mockRepo := &mocks.MockOwnedMaterialsRepository{
+				SetCountsFunc: func(ctx context.Context, userID string, counts map[string]int) error {
+					written = counts
+					return nil
+				},
+			}
+
+			err := NewOwnedMaterialsService(mockRepo).SetMaterialCounts(context.Background(), "user-123", models.SetOwnedMaterialsRequest{Materials: tt.materials})
+			if !errors.Is(err, tt.expectedError) {
+				t.Fatalf("expected error %v, got %v", tt.expectedError, err)
+			}
+			if tt.expectedCounts == nil {
+				if written != nil {
+					t.Errorf("expected no write, got %v", written)
+				}
+				return
+			}
+			if len(written) != len(tt.expectedCounts) {
+				t.Fatalf("expected counts %v, got %v", tt.expectedCounts, written)
+			}
+			for name, count := range tt.expectedCounts {
+				if got, ok := written[name]; !ok || got != count {
+					t.Errorf("expected %s=%d, got %v", name, count, written)
+				}
+			}
+		})
+	}
+}
+
+func TestOwnedMaterialsService_SetMaterialCounts_ReportsEveryProblem(t *testing.T) {
+	mockRepo := &mocks.MockOwnedMaterialsRepository{
+		SetCountsFunc: func(ctx context.Context, userID string, counts map[string]int) error {
+			t.Errorf("expected no write, got %v", counts)
+			return nil
+		},
+	}
+
+	err := NewOwnedMaterialsService(mockRepo).SetMaterialCounts(context.Background(), "user-123", models.SetOwnedMaterialsRequest{Materials: []models.OwnedMaterialCount{
+		{UniqueName: "/Lotus/Ferrite", Count: 1},
+		{UniqueName: "/Lotus/Plastids", Count: -5},
+		{UniqueName: "/Lotus/../Salvage", Count: 1},
+	}})
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected a ValidationError, got %v", err)
+	}
+	if len(validationErr.Problems) != 2 {
+		t.Fatalf("expected 2 problems, got %+v", validationErr.Problems)
+	}
+	if got := validationErr.Problems[0]; got.Field != "materials[1].count" || got.UniqueName != "/Lotus/Plastids" {
+		t.Errorf("unexpected first problem %+v", got)
+	}
+	if got := validationErr.Problems[1]; got.Field != "materials[2].uniqueName" || got.UniqueName != "/Lotus/../Salvage" {
+		t.Errorf("unexpected second problem %+v", got)
+	}
+}
+
+func TestOwnedMaterialsService_RemoveMaterial(t *testing.T) {
+	tests := []struct {
+		name          string
+		owned         []models.OwnedMaterial
+		getError      error
+		expectedError error
+		expectRemove  bool
+	}{
+		{
+			name:         "owned material is removed",
+			owned:        []models.OwnedMaterial{{UniqueName: "/Ferrite", Count: 5}},
+			expectRemove: true,
+		},
+		{
+			name:          "material not owned",
+			owned:         []models.OwnedMaterial{{UniqueName: "/Plastids", Count: 5}},
+			expectedError: ErrMaterialNotOwned,
+		},
+		{
+			name:          "repository error",
+			getError:      errors.New("database error"),
+			expectedError: errors.New("database error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			removed := false
+			mockRepo := &mocks.MockOwnedMaterialsRepository{
+				GetByUserIDFunc: func(ctx context.Context, userID string) ([]models.OwnedMaterial, error) {
+					return tt.owned, tt.getError
+				},
+				SetCountsFunc: func(ctx context.Context, userID string, counts map[string]int) error {
+					removed = counts["/Ferrite"] == 0
+					return nil
+				},
+			}
+
+			err := NewOwnedMaterialsService(mockRepo).RemoveMaterial(context.Background(), "user-123", "/Ferrite")
+			if tt.expectedError != nil {
+				if err == nil || err.Error() != tt.expectedError.Error() {
+					t.Fatalf("expected error %v, got %v", tt.expectedError, err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if removed != tt.expectRemove {
+				t.Errorf("expected removed=%v, got %v", tt.expectRemove, removed)
+			}
+		})
+	}
+}
+
+func TestOwnedMaterialsService_ClearAllMaterials(t *testing.T) {
+	cleared := ""
+	mockRepo := &mocks.MockOwnedMaterialsRepository{
+		ClearAllFunc: func(ctx context.Context, userID string) error {
+			cleared = userID
+			return nil
+		},
+	}
+
+	if err := NewOwnedMaterialsService(mockRepo).ClearAllMaterials(context.Background(), "user-123"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cleared != "user-123" {
+		t.Errorf("expected inventory of user-123 to be cleared, got '%s'", cleared)
+	}
+}
@@ -0,0 +1,407 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+)
+
+// MaxProfileImportEntries bounds both the blueprints and the items of an
+// imported inventory; a long-played account lists a few thousand.
+const MaxProfileImportEntries = 20000
+
+var ErrInventoryTooLarge = fmt.Errorf("an inventory can list at most %d blueprints and %d items", MaxProfileImportEntries, MaxProfileImportEntries)
+
+// ProfileImportService fills a user's owned blueprints, crafted components
+// and materials from a game inventory export in one request.
+//
+// Like a wishlist document import, the inventory is planned in full against
+// the current state before anything is written, and changes go through the
+// blueprint and material services so the blueprint cap and materials cache
+// invalidation still apply. The writes are not atomic: when one fails, those
+// already made are undone before the error is returned.
+//
+// Inventories list items without saying what they are, so they are sorted
+// by an index of the catalog's recipes: an ingredient that is only ever
+// crafted from its own components is a crafted component, any other
+// ingredient a material, and anything else is skipped. Craftable resources
+// such as Neurodes are materials, since the resolver counts them as such
+// wherever a recipe uses them directly. The index is built on first use and
+// dropped by Invalidate after a data sync.
+type ProfileImportService struct {
+	blueprintsService OwnedBlueprintsServiceInterface
+	materialsService  OwnedMaterialsServiceInterface
+	itemRepo          repository.ItemRepositoryInterface
+	catalog           repository.ItemCatalogInterface
+
+	mu    sync.Mutex
+	index *recipeIndex
+}
+
+// recipeIndex holds every uniqueName some recipe uses, and which of them are
+// always crafted.
+type recipeIndex struct {
+	ingredients map[string]bool
+	crafted     map[string]bool
+}
+
+func NewProfileImportService(blueprintsService OwnedBlueprintsServiceInterface, materialsService OwnedMaterialsServiceInterface, itemRepo repository.ItemRepositoryInterface, catalog repository.ItemCatalogInterface) *ProfileImportService {
+	return &ProfileImportService{
+		blueprintsService: blueprintsService,
+		materialsService:  materialsService,
+		itemRepo:          itemRepo,
+		catalog:           catalog,
+	}
+}
+
+// Invalidate drops the recipe index so the next import rebuilds it from the
+// catalog.
+func (s *ProfileImportService) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.index = nil
+}
+
+// Import applies req.Inventory to the user's owned blueprints, components and
+// materials. Listed blueprints are added and listed components and materials
+// set to the inventory's count; in replace mode everything else is removed.
+// Entries that cannot be imported are counted as skipped.
+func (s *ProfileImportService) Import(ctx context.Context, userID string, req models.ProfileImportRequest) (*models.ProfileImportResult, error) {
+	logger.Debug(ctx, "service: ProfileImportService.Import called", "userID", userID, "format", req.Format, "mode", req.Mode, "dryRun", req.DryRun)
+
+	mode := req.Mode
+	if mode == "" {
+		mode = models.ImportModeMerge
+	}
+	if mode != models.ImportModeMerge && mode != models.ImportModeReplace {
+		logger.Warn(ctx, "service: ProfileImportService.Import - invalid mode", "mode", req.Mode)
+		return nil, ErrInvalidImportMode
+	}
+	if len(req.Inventory.Blueprints) > MaxProfileImportEntries || len(req.Inventory.Items) > MaxProfileImportEntries {
+		logger.Warn(ctx, "service: ProfileImportService.Import - inventory too large", "blueprintCount", len(req.Inventory.Blueprints), "itemCount", len(req.Inventory.Items))
+		return nil, ErrInventoryTooLarge
+	}
+
+	plan, err := s.plan(ctx, userID, mode, req.Inventory)
+	if err != nil {
+		return nil, err
+	}
+	plan.result.Format = req.Format
+	plan.result.DryRun = req.DryRun
+	if !req.DryRun {
+		if err := s.apply(ctx, userID, plan); err != nil {
+			return nil, err
+		}
+	}
+
+	logger.Info(ctx, "service: ProfileImportService.Import - completed", "mode", mode, "dryRun", req.DryRun,
+		"blueprintsAdded", plan.result.Blueprints.Added, "componentsChanged", len(plan.components), "materialsChanged", len(plan.materials))
+	return plan.result, nil
+}
+
+// profileImportPlan is an import worked out against the current state.
+// components and materials map each entry to change to its new count, 0
+// removing it; the previous maps hold the counts to undo to.
+type profileImportPlan struct {
+	result             *models.ProfileImportResult
+	addBlueprints      []string
+	removeBlueprints   []string
+	components         map[string]int
+	materials          map[string]int
+	previousComponents map[string]int
+	previousMaterials  map[string]int
+}
+
+func (s *ProfileImportService) plan(ctx context.Context, userID, mode string, inventory models.ProfileInventory) (*profileImportPlan, error) {
+	owned, err := s.blueprintsService.GetOwnedBlueprints(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "service: ProfileImportService.Import - error fetching owned blueprints", "error", err)
+		return nil, err
+	}
+	ownedMaterials, err := s.materialsService.GetOwnedMaterials(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "service: ProfileImportService.Import - error fetching owned materials", "error", err)
+		return nil, err
+	}
+	index, err := s.loadIndex(ctx)
+	if err != nil {
+		logger.Error(ctx, "service: ProfileImportService.Import - error indexing recipes", "error", err)
+		return nil, err
+	}
+
+	plan := &profileImportPlan{
+		result:             &models.ProfileImportResult{Mode: mode},
+		components:         make(map[string]int),
+		materials:          make(map[string]int),
+		previousComponents: make(map[string]int, len(owned.Components)),
+		previousMaterials:  make(map[string]int, len(ownedMaterials.Materials)),
+	}
+	for _, component := range owned.Components {
+		plan.previousComponents[component.UniqueName] = component.Count
+	}
+	for _, material := range ownedMaterials.Materials {
+		plan.previousMaterials[material.UniqueName] = material.Count
+	}
+
+	if err := s.planBlueprints(ctx, plan, mode, owned.Blueprints, inventory.Blueprints); err != nil {
+		return nil, err
+	}
+
+	// Add up repeated stacks before sorting items into components and
+	// materials. Single counts above the material cap are rejected first so
+	// the sums cannot overflow; sums above a cap are capped.
+	counts := make(map[string]int, len(inventory.Items))
+	var order []string
+	for _, item := range inventory.Items {
+		name, err := models.CanonicalUniqueName(item.UniqueName)
+		if err != nil || item.Count <= 0 || item.Count > MaxOwnedMaterialCount {
+			plan.result.Skipped.Invalid++
+			continue
+		}
+		if _, ok := counts[name]; !ok {
+			order = append(order, name)
+		}
+		counts[name] += item.Count
+	}
+
+	listedComponents := make(map[string]bool)
+	listedMaterials := make(map[string]bool)
+	for _, name := range order {
+		count := counts[name]
+		switch {
+		case index.crafted[name]:
+			// More than the cap covers every build anyway.
+			listedComponents[name] = true
+			planCount(&plan.result.Components, plan.components, plan.previousComponents, name, min(count, MaxOwnedComponentCount))
+		case index.ingredients[name]:
+			listedMaterials[name] = true
+			planCount(&plan.result.Materials, plan.materials, plan.previousMaterials, name, min(count, MaxOwnedMaterialCount))
+		default:
+			plan.result.Skipped.Unused++
+		}
+	}
+
+	if mode == models.ImportModeReplace {
+		for name := range plan.previousComponents {
+			if !listedComponents[name] {
+				plan.result.Components.Removed++
+				plan.components[name] = 0
+			}
+		}
+		for name := range plan.previousMaterials {
+			if !listedMaterials[name] {
+				plan.result.Materials.Removed++
+				plan.materials[name] = 0
+			}
+		}
+	}
+
+	return plan, nil
+}
+
+// planBlueprints plans the owned blueprint changes: reusable blueprints the
+// item data knows are added, and in replace mode unlisted ones removed.
+func (s *ProfileImportService) planBlueprints(ctx context.Context, plan *profileImportPlan, mode string, owned []models.OwnedBlueprint, listed []models.InventoryCount) error {
+	names := make([]string, len(listed))
+	var lookup []string
+	for i, blueprint := range listed {
+		if name, err := models.CanonicalUniqueName(blueprint.UniqueName); err == nil && blueprint.Count > 0 {
+			names[i] = name
+			lookup = append(lookup, name)
+		}
+	}
+	catalog := map[string]*models.Item{}
+	if len(lookup) > 0 {
+		var err error
+		catalog, err = s.itemRepo.FindByUniqueNames(ctx, lookup)
+		if err != nil {
+			logger.Error(ctx, "service: ProfileImportService.Import - error finding blueprints", "error", err)
+			return err
+		}
+	}
+
+	ownedSet := make(map[string]bool, len(owned))
+	for _, blueprint := range owned {
+		ownedSet[blueprint.UniqueName] = true
+	}
+	seen := make(map[string]bool, len(listed))
+	for _, name := range names {
+		if name == "" {
+			plan.result.Skipped.Invalid++
+			continue
+		}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		item := catalog[name]
+		switch {
+		case ownedSet[name]:
+			plan.result.Blueprints.Unchanged++
+		case item == nil:
+			plan.result.Skipped.NotFound++
+		case item.ConsumeOnBuild:
+			plan.result.Skipped.NotReusable++
+		default:
+			plan.result.Blueprints.Added++
+			plan.addBlueprints = append(plan.addBlueprints, name)
+		}
+	}
+
+	if mode == models.ImportModeReplace {
+		for _, blueprint := range owned {
+			if !seen[blueprint.UniqueName] {
+				plan.result.Blueprints.Removed++
+				plan.removeBlueprints = append(plan.removeBlueprints, blueprint.UniqueName)
+			}
+		}
+	}
+	return nil
+}
+
+// planCount records setting name to count, unless it already has it.
+func planCount(tally *models.ProfileImportTally, changes, previous map[string]int, name string, count int) {
+	switch previous[name] {
+	case count:
+		tally.Unchanged++
+		return
+	case 0:
+		tally.Added++
+	default:
+		tally.Updated++
+	}
+	changes[name] = count
+}
+
+// apply writes plan, undoing every change already made if a write fails.
+func (s *ProfileImportService) apply(ctx context.Context, userID string, plan *profileImportPlan) error {
+	var undo []func(ctx context.Context) error
+	err := s.write(ctx, userID, plan, &undo)
+	if err == nil {
+		return nil
+	}
+
+	// The request may have been cancelled, which must not stop the undo.
+	undoCtx := context.WithoutCancel(ctx)
+	for i := len(undo) - 1; i >= 0; i-- {
+		if undoErr := undo[i](undoCtx); undoErr != nil {
+			logger.Error(ctx, "service: ProfileImportService.Import - error undoing a change", "error", undoErr)
+		}
+	}
+	logger.Warn(ctx, "service: ProfileImportService.Import - import undone", "undoneCount", len(undo))
+	return err
+}
+
+func (s *ProfileImportService) write(ctx context.Context, userID string, plan *profileImportPlan, undo *[]func(ctx context.Context) error) error {
+	// Removals go first so a replace frees room under the blueprint cap.
+	for _, name := range plan.removeBlueprints {
+		if err := s.blueprintsService.RemoveBlueprint(ctx, userID, name); err != nil && !errors.Is(err, ErrBlueprintNotOwned) {
+			logger.Error(ctx, "service: ProfileImportService.Import - error removing blueprint", "uniqueName", name, "error", err)
+			return err
+		}
+		*undo = append(*undo, func(ctx context.Context) error {
+			return s.blueprintsService.BulkAddBlueprints(ctx, userID, models.BulkAddBlueprintsRequest{UniqueNames: []string{name}})
+		})
+	}
+	if len(plan.addBlueprints) > 0 {
+		if err := s.blueprintsService.BulkAddBlueprints(ctx, userID, models.BulkAddBlueprintsRequest{UniqueNames: plan.addBlueprints}); err != nil {
+			logger.Error(ctx, "service: ProfileImportService.Import - error adding blueprints", "error", err)
+			return err
+		}
+		*undo = append(*undo, func(ctx context.Context) error {
+			for _, name := range plan.addBlueprints {
+				if err := s.blueprintsService.RemoveBlueprint(ctx, userID, name); err != nil && !errors.Is(err, ErrBlueprintNotOwned) {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+
+	for _, name := range sortedKeys(plan.components) {
+		if err := s.blueprintsService.SetComponentCount(ctx, userID, name, plan.components[name]); err != nil {
+			logger.Error(ctx, "service: ProfileImportService.Import - error setting component", "uniqueName", name, "error", err)
+			return err
+		}
+		previous := plan.previousComponents[name]
+		*undo = append(*undo, func(ctx context.Context) error {
+			return s.blueprintsService.SetComponentCount(ctx, userID, name, previous)
+		})
+	}
+
+	materials := sortedKeys(plan.materials)
+	for start := 0; start < len(materials); start += MaxOwnedMaterialsPerRequest {
+		batch := materials[start:min(start+MaxOwnedMaterialsPerRequest, len(materials))]
+		set := models.SetOwnedMaterialsRequest{Materials: make([]models.OwnedMaterialCount, len(batch))}
+		restore := models.SetOwnedMaterialsRequest{Materials: make([]models.OwnedMaterialCount, len(batch))}
+		for i, name := range batch {
+			set.Materials[i] = models.OwnedMaterialCount{UniqueName: name, Count: plan.materials[name]}
+			restore.Materials[i] = models.OwnedMaterialCount{UniqueName: name, Count: plan.previousMaterials[name]}
+		}
+		if err := s.materialsService.SetMaterialCounts(ctx, userID, set); err != nil {
+			logger.Error(ctx, "service: ProfileImportService.Import - error setting materials", "error", err)
+			return err
+		}
+		*undo = append(*undo, func(ctx context.Context) error {
+			return s.materialsService.SetMaterialCounts(ctx, userID, restore)
+		})
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (s *ProfileImportService) loadIndex(ctx context.Context) (*recipeIndex, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.index != nil {
+		return s.index, nil
+	}
+
+	index := &recipeIndex{ingredients: make(map[string]bool), crafted: make(map[string]bool)}
+	leaves := make(map[string]bool)
+	var walk func(components []models.Component)
+	walk = func(components []models.Component) {
+		for _, component := range components {
+			index.ingredients[component.UniqueName] = true
+			if len(component.Components) == 0 {
+				leaves[component.UniqueName] = true
+				continue
+			}
+			index.crafted[component.UniqueName] = true
+			walk(component.Components)
+		}
+	}
+	err := s.catalog.ForEachItem(ctx, func(item models.Item) error {
+		walk(item.Components)
+		for _, recipe := range item.AlternateRecipes {
+			walk(recipe.Components)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for name := range leaves {
+		delete(index.crafted, name)
+	}
+
+	logger.Info(ctx, "service: ProfileImportService - recipe index built", "ingredientCount", len(index.ingredients), "craftedCount", len(index.crafted))
+	s.index = index
+	return index, nil
+}
@@ -0,0 +1,265 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository/memory"
+)
+
+const (
+	testRhinoBlueprint   = "/Lotus/Types/Recipes/WarframeRecipes/RhinoBlueprint"
+	testChassisBlueprint = "/Lotus/Types/Recipes/WarframeRecipes/RhinoChassisBlueprint"
+	testRhinoChassis     = "/Lotus/Types/Recipes/WarframeRecipes/RhinoChassisComponent"
+	testFerrite          = "/Lotus/Types/Items/MiscItems/Ferrite"
+	testOrokinCell       = "/Lotus/Types/Items/MiscItems/OrokinCell"
+	testPlastids         = "/Lotus/Types/Items/MiscItems/Plastids"
+	testNeurode          = "/Lotus/Types/Items/MiscItems/Neurode"
+	testVoidRelic        = "/Lotus/Types/Game/Projections/T1VoidProjectionA"
+	testOldBlueprint     = "/Lotus/Types/Recipes/WarframeRecipes/AshBlueprint"
+	testUnknownBlueprint = "/Lotus/Types/Recipes/WarframeRecipes/NopeBlueprint"
+)
+
+type profileImportFixture struct {
+	service    *ProfileImportService
+	items      *memory.ItemRepository
+	blueprints *memory.OwnedBlueprintsRepository
+	materials  *memory.OwnedMaterialsRepository
+}
+
+func newProfileImportFixture(t *testing.T) *profileImportFixture {
+	t.Helper()
+	ctx := context.Background()
+
+	items := memory.NewItemRepository()
+	items.Add("warframes", models.Item{
+		UniqueName: "/Lotus/Powersuits/Rhino/Rhino",
+		Name:       "Rhino",
+		Components: []models.Component{
+			{UniqueName: testRhinoBlueprint, Name: "Blueprint", ItemCount: 1},
+			{UniqueName: testRhinoChassis, Name: "Chassis", ItemCount: 1, Components: []models.Component{
+				{UniqueName: testChassisBlueprint, Name: "Blueprint", ItemCount: 1},
+				{UniqueName: testFerrite, Name: "Ferrite", ItemCount: 1000},
+			}},
+			{UniqueName: testOrokinCell, Name: "Orokin Cell", ItemCount: 1},
+			{UniqueName: testPlastids, Name: "Plastids", ItemCount: 100},
+			{UniqueName: testNeurode, Name: "Neurodes", ItemCount: 1},
+		},
+	})
+	// Neurodes can be crafted, but Rhino uses them as they are.
+	items.Add("secondary", models.Item{
+		UniqueName: "/Lotus/Weapons/ClanTech/Bio/AcidDartPistol",
+		Name:       "Acrid",
+		Components: []models.Component{
+			{UniqueName: testNeurode, Name: "Neurodes", ItemCount: 2, Components: []models.Component{{UniqueName: testFerrite, ItemCount: 10}}},
+		},
+	})
+	items.Add("misc",
+		models.Item{UniqueName: testRhinoBlueprint, Name: "Rhino Blueprint"},
+		models.Item{UniqueName: testOldBlueprint, Name: "Ash Blueprint"},
+		models.Item{UniqueName: testChassisBlueprint, Name: "Rhino Chassis Blueprint", ConsumeOnBuild: true},
+		models.Item{UniqueName: testFerrite, Name: "Ferrite"},
+	)
+
+	blueprints := memory.NewOwnedBlueprintsRepository()
+	materials := memory.NewOwnedMaterialsRepository()
+	if err := blueprints.Create(ctx, &models.OwnedBlueprints{UserID: "user-123", Blueprints: []models.OwnedBlueprint{{UniqueName: testOldBlueprint}}}); err != nil {
+		t.Fatalf("seeding blueprints: %v", err)
+	}
+	if err := materials.SetCounts(ctx, "user-123", map[string]int{testOrokinCell: 3, testPlastids: 10}); err != nil {
+		t.Fatalf("seeding materials: %v", err)
+	}
+
+	service := NewProfileImportService(NewOwnedBlueprintsService(blueprints, items), NewOwnedMaterialsService(materials), items, items)
+	return &profileImportFixture{service: service, items: items, blueprints: blueprints, materials: materials}
+}
+
+func sampleProfileInventory() models.ProfileInventory {
+	return models.ProfileInventory{
+		Blueprints: []models.InventoryCount{
+			{UniqueName: testRhinoBlueprint, Count: 1},
+			{UniqueName: testRhinoBlueprint, Count: 1},
+			{UniqueName: testChassisBlueprint, Count: 2},
+			{UniqueName: testUnknownBlueprint, Count: 1},
+			{UniqueName: "", Count: 1},
+		},
+		Items: []models.InventoryCount{
+			{UniqueName: testFerrite, Count: 500},
+			{UniqueName: testRhinoChassis, Count: 2},
+			{UniqueName: testFerrite, Count: 100},
+			{UniqueName: testOrokinCell, Count: 3},
+			{UniqueName: testVoidRelic, Count: 5},
+			{UniqueName: testNeurode, Count: 4},
+			{UniqueName: testFerrite, Count: -1},
+		},
+	}
+}
+
+func (f *profileImportFixture) state(t *testing.T) (blueprints map[string]bool, components, materials map[string]int) {
+	t.Helper()
+	ctx := context.Background()
+	owned, err := f.blueprints.GetByUserID(ctx, "user-123")
+	if err != nil {
+		t.Fatalf("reading blueprints: %v", err)
+	}
+	blueprints, components, materials = map[string]bool{}, map[string]int{}, map[string]int{}
+	for _, bp := range owned.Blueprints {
+		blueprints[bp.UniqueName] = true
+	}
+	for _, component := range owned.Components {
+		components[component.UniqueName] = component.Count
+	}
+	owns, err := f.materials.GetByUserID(ctx, "user-123")
+	if err != nil {
+		t.Fatalf("reading materials: %v", err)
+	}
+	for _, material := range owns {
+		materials[material.UniqueName] = material.Count
+	}
+	return blueprints, components, materials
+}
+
+func TestProfileImportService_ImportMerge(t *testing.T) {
+	f := newProfileImportFixture(t)
+
+	result, err := f.service.Import(context.Background(), "user-123", models.ProfileImportRequest{Format: "game", Inventory: sampleProfileInventory()})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Format != "game" || result.Mode != models.ImportModeMerge || result.DryRun {
+		t.Errorf("unexpected header %+v", result)
+	}
+	if result.Blueprints != (models.ProfileImportTally{Added: 1}) {
+		t.Errorf("unexpected blueprints %+v", result.Blueprints)
+	}
+	if result.Components != (models.ProfileImportTally{Added: 1}) {
+		t.Errorf("unexpected components %+v", result.Components)
+	}
+	if result.Materials != (models.ProfileImportTally{Added: 2, Unchanged: 1}) {
+		t.Errorf("unexpected materials %+v", result.Materials)
+	}
+	if result.Skipped != (models.ProfileImportSkipped{NotFound: 1, NotReusable: 1, Unused: 1, Invalid: 2}) {
+		t.Errorf("unexpected skipped %+v", result.Skipped)
+	}
+
+	blueprints, components, materials := f.state(t)
+	if !blueprints[testRhinoBlueprint] || !blueprints[testOldBlueprint] || len(blueprints) != 2 {
+		t.Errorf("unexpected owned blueprints %v", blueprints)
+	}
+	if components[testRhinoChassis] != 2 || len(components) != 1 {
+		t.Errorf("unexpected owned components %v", components)
+	}
+	if materials[testFerrite] != 600 || materials[testNeurode] != 4 || materials[testOrokinCell] != 3 || materials[testPlastids] != 10 {
+		t.Errorf("expected the stacks summed and other materials kept, got %v", materials)
+	}
+}
+
+func TestProfileImportService_ImportReplace(t *testing.T) {
+	f := newProfileImportFixture(t)
+
+	result, err := f.service.Import(context.Background(), "user-123", models.ProfileImportRequest{Mode: models.ImportModeReplace, Inventory: sampleProfileInventory()})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Blueprints != (models.ProfileImportTally{Added: 1, Removed: 1}) || result.Materials != (models.ProfileImportTally{Added: 2, Unchanged: 1, Removed: 1}) {
+		t.Errorf("unexpected tallies %+v", result)
+	}
+
+	blueprints, _, materials := f.state(t)
+	if blueprints[testOldBlueprint] || !blueprints[testRhinoBlueprint] {
+		t.Errorf("expected only the listed blueprint, got %v", blueprints)
+	}
+	if _, ok := materials[testPlastids]; ok || materials[testFerrite] != 600 {
+		t.Errorf("expected unlisted materials removed, got %v", materials)
+	}
+}
+
+func TestProfileImportService_DryRun(t *testing.T) {
+	f := newProfileImportFixture(t)
+
+	result, err := f.service.Import(context.Background(), "user-123", models.ProfileImportRequest{Mode: models.ImportModeReplace, DryRun: true, Inventory: sampleProfileInventory()})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.DryRun || result.Blueprints.Added != 1 || result.Components.Added != 1 {
+		t.Errorf("expected the changes reported, got %+v", result)
+	}
+
+	blueprints, components, materials := f.state(t)
+	if len(blueprints) != 1 || len(components) != 0 || len(materials) != 2 || materials[testPlastids] != 10 {
+		t.Errorf("expected nothing written, got %v %v %v", blueprints, components, materials)
+	}
+}
+
+func TestProfileImportService_UndoesOnFailure(t *testing.T) {
+	f := newProfileImportFixture(t)
+	f.service.materialsService = &mocks.MockOwnedMaterialsService{
+		GetOwnedMaterialsFunc: func(ctx context.Context, userID string) (*models.OwnedMaterials, error) {
+			return &models.OwnedMaterials{UserID: userID}, nil
+		},
+		SetMaterialCountsFunc: func(ctx context.Context, userID string, req models.SetOwnedMaterialsRequest) error {
+			return errors.New("database down")
+		},
+	}
+
+	if _, err := f.service.Import(context.Background(), "user-123", models.ProfileImportRequest{Mode: models.ImportModeReplace, Inventory: sampleProfileInventory()}); err == nil {
+		t.Fatal("expected the materials error")
+	}
+
+	blueprints, components, _ := f.state(t)
+	if len(blueprints) != 1 || !blueprints[testOldBlueprint] {
+		t.Errorf("expected the blueprints restored, got %v", blueprints)
+	}
+	if len(components) != 0 {
+		t.Errorf("expected the components restored, got %v", components)
+	}
+}
+
+func TestProfileImportService_Errors(t *testing.T) {
+	ctx := context.Background()
+	f := newProfileImportFixture(t)
+
+	if _, err := f.service.Import(ctx, "user-123", models.ProfileImportRequest{Mode: "overwrite"}); !errors.Is(err, ErrInvalidImportMode) {
+		t.Errorf("expected ErrInvalidImportMode, got %v", err)
+	}
+	tooMany := models.ProfileInventory{Items: make([]models.InventoryCount, MaxProfileImportEntries+1)}
+	if _, err := f.service.Import(ctx, "user-123", models.ProfileImportRequest{Inventory: tooMany}); !errors.Is(err, ErrInventoryTooLarge) {
+		t.Errorf("expected ErrInventoryTooLarge, got %v", err)
+	}
+
+	blueprintsService := NewOwnedBlueprintsService(f.blueprints, f.items)
+	blueprintsService.SetMaxBlueprints(1)
+	f.service.blueprintsService = blueprintsService
+	if _, err := f.service.Import(ctx, "user-123", models.ProfileImportRequest{Inventory: sampleProfileInventory()}); !errors.Is(err, ErrTooManyBlueprints) {
+		t.Errorf("expected ErrTooManyBlueprints, got %v", err)
+	}
+	if _, _, materials := f.state(t); materials[testFerrite] != 0 {
+		t.Errorf("expected nothing written past the blueprint cap, got %v", materials)
+	}
+}
+
+func TestProfileImportService_Invalidate(t *testing.T) {
+	ctx := context.Background()
+	f := newProfileImportFixture(t)
+	inv := models.ProfileInventory{Items: []models.InventoryCount{{UniqueName: "/Lotus/Types/Items/MiscItems/Salvage", Count: 50}}}
+
+	result, _ := f.service.Import(ctx, "user-123", models.ProfileImportRequest{DryRun: true, Inventory: inv})
+	if result.Skipped.Unused != 1 {
+		t.Fatalf("expected Salvage unused, got %+v", result)
+	}
+
+	f.items.Add("primary", models.Item{UniqueName: "/Lotus/Weapons/Braton", Name: "Braton", Components: []models.Component{{UniqueName: "/Lotus/Types/Items/MiscItems/Salvage", ItemCount: 200}}})
+	result, _ = f.service.Import(ctx, "user-123", models.ProfileImportRequest{DryRun: true, Inventory: inv})
+	if result.Skipped.Unused != 1 {
+		t.Errorf("expected the cached index, got %+v", result)
+	}
+	f.service.Invalidate()
+	result, _ = f.service.Import(ctx, "user-123", models.ProfileImportRequest{DryRun: true, Inventory: inv})
+	if result.Materials.Added != 1 {
+		t.Errorf("expected the rebuilt index, got %+v", result)
+	}
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+)
+
+var ErrPublicWishlistNotFound = errors.New("public wishlist not found")
+
+// PublicWishlistService serves the read-only wishlists preloaded in kiosk
+// mode. materialResolver must read from a wishlist repository holding each
+// public wishlist under its ID as the user ID.
+type PublicWishlistService struct {
+	wishlists        []models.PublicWishlist
+	byID             map[string]int
+	materialResolver MaterialResolverInterface
+}
+
+func NewPublicWishlistService(wishlists []models.PublicWishlist, materialResolver MaterialResolverInterface) *PublicWishlistService {
+	byID := make(map[string]int, len(wishlists))
+	for i, wl := range wishlists {
+		byID[wl.ID] = i
+	}
+	return &PublicWishlistService{
+		wishlists:        wishlists,
+		byID:             byID,
+		materialResolver: materialResolver,
+	}
+}
+
+func (s *PublicWishlistService) List(ctx context.Context) ([]models.PublicWishlist, error) {
+	logger.Debug(ctx, "service: PublicWishlistService.List called", "count", len(s.wishlists))
+	return s.wishlists, nil
+}
+
+func (s *PublicWishlistService) Get(ctx context.Context, id string) (*models.PublicWishlist, error) {
+	logger.Debug(ctx, "service: PublicWishlistService.Get called", "id", id)
+
+	i, ok := s.byID[id]
+	if !ok {
+		logger.Warn(ctx, "service: PublicWishlistService.Get - not found", "id", id)
+		return nil, ErrPublicWishlistNotFound
+	}
+	wishlist := s.wishlists[i]
+	return &wishlist, nil
+}
+
+func (s *PublicWishlistService) GetMaterials(ctx context.Context, id string) (*models.MaterialsResponse, error) {
+	logger.Debug(ctx, "service: PublicWishlistService.GetMaterials called", "id", id)
+
+	if _, ok := s.byID[id]; !ok {
+		logger.Warn(ctx, "service: PublicWishlistService.GetMaterials - not found", "id", id)
+		return nil, ErrPublicWishlistNotFound
+	}
+	return s.materialResolver.GetMaterials(ctx, id)
+}
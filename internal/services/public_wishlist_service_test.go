@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository/memory"
+)
+
+func TestPublicWishlistService(t *testing.T) {
+	ctx := context.Background()
+
+	items := memory.NewItemRepository()
+	items.Add("resources", models.Item{UniqueName: "/Lotus/Forma", Name: "Forma"})
+
+	wishlists := []models.PublicWishlist{
+		{ID: "forma", Name: "Forma stack", Items: []models.WishlistItem{{UniqueName: "/Lotus/Forma", Quantity: 3}}},
+		{ID: "empty", Name: "Empty", Items: []models.WishlistItem{}},
+	}
+	wishlistRepo := memory.NewWishlistRepository()
+	for _, wl := range wishlists {
+		wishlistRepo.Create(ctx, &models.Wishlist{UserID: wl.ID, Items: wl.Items})
+	}
+	service := NewPublicWishlistService(wishlists, NewMaterialResolver(items, wishlistRepo, nil, nil))
+
+	list, err := service.List(ctx)
+	if err != nil || len(list) != 2 || list[0].ID != "forma" {
+		t.Errorf("unexpected list: %+v (err %v)", list, err)
+	}
+
+	wl, err := service.Get(ctx, "forma")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if wl.Name != "Forma stack" || len(wl.Items) != 1 {
+		t.Errorf("unexpected wishlist: %+v", wl)
+	}
+
+	if _, err := service.Get(ctx, "missing"); !errors.Is(err, ErrPublicWishlistNotFound) {
+		t.Errorf("expected ErrPublicWishlistNotFound, got %v", err)
+	}
+	if _, err := service.GetMaterials(ctx, "missing"); !errors.Is(err, ErrPublicWishlistNotFound) {
+		t.Errorf("expected ErrPublicWishlistNotFound, got %v", err)
+	}
+
+	materials, err := service.GetMaterials(ctx, "empty")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(materials.Materials) != 0 {
+		t.Errorf("expected no materials for empty wishlist, got %+v", materials.Materials)
+	}
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+)
+
+// QuantitySuggester works out how many of an intermediate craftable the rest
+// of a wishlist uses, to suggest as its quantity.
+type QuantitySuggester struct {
+	wishlistRepo repository.WishlistRepositoryInterface
+	itemRepo     repository.ItemRepositoryInterface
+}
+
+func NewQuantitySuggester(wishlistRepo repository.WishlistRepositoryInterface, itemRepo repository.ItemRepositoryInterface) *QuantitySuggester {
+	return &QuantitySuggester{wishlistRepo: wishlistRepo, itemRepo: itemRepo}
+}
+
+// SuggestQuantity counts uniqueName in the recipe trees of the user's other
+// wishlist items, the way MaterialResolver walks them: each copy after
+// component progress, crafts rounded up to whole builds. It returns nil when
+// no other item uses it.
+func (s *QuantitySuggester) SuggestQuantity(ctx context.Context, userID, uniqueName string) (*models.QuantitySuggestion, error) {
+	logger.Debug(ctx, "service: QuantitySuggester.SuggestQuantity called", "userID", userID, "uniqueName", uniqueName)
+
+	wishlist, err := s.wishlistRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "service: QuantitySuggester.SuggestQuantity - error fetching wishlist", "error", err)
+		return nil, err
+	}
+	if wishlist == nil {
+		return nil, nil
+	}
+
+	var others []models.WishlistItem
+	var uniqueNames []string
+	for _, wishlistItem := range wishlist.Items {
+		if wishlistItem.UniqueName != uniqueName {
+			others = append(others, wishlistItem)
+			uniqueNames = append(uniqueNames, wishlistItem.UniqueName)
+		}
+	}
+	if len(others) == 0 {
+		return nil, nil
+	}
+
+	items, err := s.itemRepo.FindByUniqueNames(ctx, uniqueNames)
+	if err != nil {
+		logger.Error(ctx, "service: QuantitySuggester.SuggestQuantity - error fetching items", "error", err)
+		return nil, err
+	}
+	for _, wishlistItem := range others {
+		if item, ok := items[wishlistItem.UniqueName]; ok && wishlistItem.RecipeID != "" {
+			if withRecipe, ok := item.WithRecipe(wishlistItem.RecipeID); ok {
+				items[wishlistItem.UniqueName] = withRecipe
+			}
+		}
+	}
+
+	counter := &usageCounter{
+		itemRepo:   s.itemRepo,
+		components: prefetchComponents(ctx, s.itemRepo, items),
+		target:     uniqueName,
+		ancestors:  make(map[string]bool),
+	}
+	suggestion := &models.QuantitySuggestion{UniqueName: uniqueName, UsedBy: []models.QuantityUsage{}}
+	for _, wishlistItem := range others {
+		item, ok := items[wishlistItem.UniqueName]
+		if !ok {
+			continue
+		}
+		done := completedComponents(item, wishlistItem)
+		count := 0
+		for i := 0; i < wishlistItem.Quantity; i++ {
+			count += counter.count(ctx, withoutCompleted(item, done), 1, 0)
+		}
+		if count > 0 {
+			suggestion.Suggested += count
+			suggestion.UsedBy = append(suggestion.UsedBy, models.QuantityUsage{UniqueName: item.UniqueName, Name: item.Name, Count: count})
+		}
+	}
+	if suggestion.Suggested == 0 {
+		logger.Debug(ctx, "service: QuantitySuggester.SuggestQuantity - not used by other items", "uniqueName", uniqueName)
+		return nil, nil
+	}
+
+	slices.SortFunc(suggestion.UsedBy, func(a, b models.QuantityUsage) int {
+		if c := cmp.Compare(b.Count, a.Count); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.UniqueName, b.UniqueName)
+	})
+	logger.Info(ctx, "service: QuantitySuggester.SuggestQuantity - completed", "uniqueName", uniqueName, "suggested", suggestion.Suggested, "usedBy", len(suggestion.UsedBy))
+	return suggestion, nil
+}
+
+// usageCounter counts how many of target a recipe tree uses. The target
+// itself is not expanded: its own components are what building it takes.
+type usageCounter struct {
+	itemRepo   repository.ItemRepositoryInterface
+	components map[string]*models.Item
+	target     string
+	// ancestors holds the uniqueNames on the path being expanded, to stop
+	// at cycles.
+	ancestors map[string]bool
+}
+
+func (c *usageCounter) count(ctx context.Context, item *models.Item, crafts, depth int) int {
+	if depth >= maxRecipeTreeDepth || c.ancestors[item.UniqueName] {
+		return 0
+	}
+	c.ancestors[item.UniqueName] = true
+	defer delete(c.ancestors, item.UniqueName)
+
+	total := 0
+	for _, component := range item.Components {
+		needed := component.ItemCount * crafts
+		if component.UniqueName == c.target {
+			total += needed
+			continue
+		}
+
+		componentItem, _ := findComponent(ctx, c.itemRepo, c.components, component.UniqueName)
+		buildQuantity := 1
+		if componentItem != nil && componentItem.BuildQuantity > 0 {
+			buildQuantity = componentItem.BuildQuantity
+		}
+		switch {
+		case len(component.Components) > 0:
+			// Embedded components are crafted in place, like MaterialResolver.
+			embedded := &models.Item{UniqueName: component.UniqueName, Components: component.Components}
+			total += c.count(ctx, embedded, ceilDiv(needed, buildQuantity), depth+1)
+		case componentItem != nil && len(componentItem.Components) > 0:
+			total += c.count(ctx, componentItem, ceilDiv(needed, buildQuantity), depth+1)
+		}
+	}
+	return total
+}
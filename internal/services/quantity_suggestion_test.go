@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository/memory"
+)
+
+// newQuantitySuggestionFixture wishlists an Orokin Catalyst next to two Ash,
+// one with its chassis already built, and two Bratons. The chassis takes
+// three catalysts and each Braton one; the catalyst blueprint builds two.
+func newQuantitySuggestionFixture(t *testing.T) (*memory.WishlistRepository, *memory.ItemRepository) {
+	t.Helper()
+
+	wishlists := memory.NewWishlistRepository()
+	err := wishlists.Create(context.Background(), &models.Wishlist{UserID: "user-123", Items: []models.WishlistItem{
+		{UniqueName: "/Lotus/Catalyst", Quantity: 1},
+		{UniqueName: "/Lotus/Ash", Quantity: 2, Progress: []models.ComponentProgress{
+			{UniqueName: "/Lotus/AshChassis", Status: models.ComponentBuilt, Count: 1},
+		}},
+		{UniqueName: "/Lotus/Braton", Quantity: 2},
+	}})
+	if err != nil {
+		t.Fatalf("failed to seed wishlist: %v", err)
+	}
+
+	items := memory.NewItemRepository()
+	items.Add("warframes", models.Item{UniqueName: "/Lotus/Ash", Name: "Ash", Components: []models.Component{
+		{UniqueName: "/Lotus/AshChassis", Name: "Chassis", ItemCount: 1},
+		{UniqueName: "/Lotus/Ferrite", Name: "Ferrite", ItemCount: 100},
+	}})
+	items.Add("primary", models.Item{UniqueName: "/Lotus/Braton", Name: "Braton", Components: []models.Component{
+		{UniqueName: "/Lotus/Catalyst", Name: "Orokin Catalyst", ItemCount: 1},
+	}})
+	items.Add("misc",
+		models.Item{UniqueName: "/Lotus/AshChassis", Name: "Ash Chassis", Components: []models.Component{
+			{UniqueName: "/Lotus/Catalyst", Name: "Orokin Catalyst", ItemCount: 3},
+		}},
+		models.Item{UniqueName: "/Lotus/Catalyst", Name: "Orokin Catalyst", BuildQuantity: 2, Components: []models.Component{
+			{UniqueName: "/Lotus/Ferrite", Name: "Ferrite", ItemCount: 500},
+		}},
+	)
+	return wishlists, items
+}
+
+func TestQuantitySuggester_SuggestQuantity(t *testing.T) {
+	wishlists, items := newQuantitySuggestionFixture(t)
+	suggester := NewQuantitySuggester(wishlists, items)
+
+	suggestion, err := suggester.SuggestQuantity(context.Background(), "user-123", "/Lotus/Catalyst")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if suggestion == nil {
+		t.Fatal("expected a suggestion")
+	}
+
+	// The built chassis leaves one to craft, and the suggestion counts
+	// catalysts used, not blueprints crafted.
+	expected := &models.QuantitySuggestion{
+		UniqueName: "/Lotus/Catalyst",
+		Suggested:  5,
+		UsedBy: []models.QuantityUsage{
+			{UniqueName: "/Lotus/Ash", Name: "Ash", Count: 3},
+			{UniqueName: "/Lotus/Braton", Name: "Braton", Count: 2},
+		},
+	}
+	if !reflect.DeepEqual(suggestion, expected) {
+		t.Errorf("expected %+v, got %+v", expected, suggestion)
+	}
+}
+
+func TestQuantitySuggester_SuggestQuantity_Unused(t *testing.T) {
+	wishlists, items := newQuantitySuggestionFixture(t)
+	suggester := NewQuantitySuggester(wishlists, items)
+
+	suggestion, err := suggester.SuggestQuantity(context.Background(), "user-123", "/Lotus/Braton")
+	if err != nil || suggestion != nil {
+		t.Errorf("expected no suggestion for an item nothing uses, got %+v, %v", suggestion, err)
+	}
+
+	suggestion, err = suggester.SuggestQuantity(context.Background(), "user-without-wishlist", "/Lotus/Catalyst")
+	if err != nil || suggestion != nil {
+		t.Errorf("expected no suggestion without a wishlist, got %+v, %v", suggestion, err)
+	}
+}
+
+func TestQuantitySuggester_SuggestQuantity_Error(t *testing.T) {
+	repoErr := errors.New("connection refused")
+	suggester := NewQuantitySuggester(&mocks.MockWishlistRepository{
+		GetByUserIDFunc: func(ctx context.Context, userID string) (*models.Wishlist, error) {
+			return nil, repoErr
+		},
+	}, &mocks.MockItemRepository{})
+
+	if _, err := suggester.SuggestQuantity(context.Background(), "user-123", "/Lotus/Catalyst"); !errors.Is(err, repoErr) {
+		t.Errorf("expected the repository error, got %v", err)
+	}
+}
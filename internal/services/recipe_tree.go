@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+)
+
+const (
+	// maxRecipeTreeDepth bounds how deep a recipe tree is expanded; real
+	// recipes are at most a handful of levels deep.
+	maxRecipeTreeDepth = 12
+	// maxRecipeTreeNodes bounds the size of a recipe tree response.
+	maxRecipeTreeNodes = 1000
+)
+
+// GetRecipeTree returns the crafting tree of the item, resolved the same way
+// MaterialResolver does: embedded nested components are expanded in place,
+// other components are looked up and expanded when they have a recipe, and
+// crafts account for buildQuantity. It returns nil when the item does not
+// exist.
+func (s *ItemService) GetRecipeTree(ctx context.Context, uniqueName string) (*models.RecipeTree, error) {
+	logger.Debug(ctx, "service: ItemService.GetRecipeTree called", "uniqueName", uniqueName)
+	item, err := s.repo.FindByUniqueName(ctx, uniqueName)
+	if err != nil {
+		logger.Error(ctx, "service: ItemService.GetRecipeTree - repository error", "error", err, "uniqueName", uniqueName)
+		return nil, err
+	}
+	if item == nil {
+		logger.Debug(ctx, "service: ItemService.GetRecipeTree - item not found", "uniqueName", uniqueName)
+		return nil, nil
+	}
+
+	builder := &recipeTreeBuilder{
+		itemRepo:   s.repo,
+		components: prefetchComponents(ctx, s.repo, map[string]*models.Item{item.UniqueName: item}),
+		ancestors:  make(map[string]bool),
+		tree:       &models.RecipeTree{},
+	}
+	builder.tree.Root = builder.add(ctx, recipeEntry{
+		uniqueName: item.UniqueName,
+		name:       item.Name,
+		imageName:  item.ImageName,
+		item:       item,
+	}, 0, 1)
+
+	logger.Debug(ctx, "service: ItemService.GetRecipeTree - completed", "uniqueName", uniqueName, "nodeCount", len(builder.tree.Nodes), "truncated", builder.tree.Truncated)
+	return builder.tree, nil
+}
+
+// recipeEntry is an item or component entry to add to a recipe tree.
+// embedded holds a component's nested components from the item data, which
+// take precedence over the looked-up item's own.
+type recipeEntry struct {
+	uniqueName string
+	name       string
+	imageName  string
+	item       *models.Item
+	embedded   []models.Component
+}
+
+type recipeTreeBuilder struct {
+	itemRepo   repository.ItemRepositoryInterface
+	components map[string]*models.Item
+	// ancestors holds the uniqueNames on the path from the root to the node
+	// being expanded, to detect cycles.
+	ancestors map[string]bool
+	tree      *models.RecipeTree
+}
+
+// add appends a node for entry, needed quantity times, followed by its
+// components, and returns the node's ID.
+func (b *recipeTreeBuilder) add(ctx context.Context, entry recipeEntry, depth, quantity int) string {
+	id := "n" + strconv.Itoa(len(b.tree.Nodes))
+	node := models.RecipeNode{
+		ID:            id,
+		UniqueName:    entry.uniqueName,
+		Name:          entry.name,
+		ImageName:     entry.imageName,
+		Depth:         depth,
+		Quantity:      quantity,
+		BuildQuantity: 1,
+		HasOwnPage:    entry.item != nil,
+	}
+
+	components := entry.embedded
+	if item := entry.item; item != nil {
+		if item.Name != "" {
+			node.Name = item.Name
+		}
+		if item.ImageName != "" {
+			node.ImageName = item.ImageName
+		}
+		if item.BuildQuantity > 0 {
+			node.BuildQuantity = item.BuildQuantity
+		}
+		node.BuildPrice = item.BuildPrice
+		node.BuildTime = item.BuildTime
+		if len(components) == 0 {
+			components = item.Components
+		}
+	}
+
+	index := len(b.tree.Nodes)
+	b.tree.Nodes = append(b.tree.Nodes, node)
+	if len(components) == 0 {
+		return id
+	}
+
+	crafts := ceilDiv(quantity, node.BuildQuantity)
+	b.tree.Nodes[index].Crafts = crafts
+	if b.ancestors[entry.uniqueName] {
+		// The cycle's first occurrence already accounts for its cost.
+		b.truncate(ctx, index, models.RecipeTruncatedCycle)
+		return id
+	}
+	b.tree.TotalCredits += node.BuildPrice * crafts
+
+	switch {
+	case depth >= maxRecipeTreeDepth:
+		b.truncate(ctx, index, models.RecipeTruncatedDepth)
+		return id
+	case len(b.tree.Nodes)+len(components) > maxRecipeTreeNodes:
+		b.truncate(ctx, index, models.RecipeTruncatedSize)
+		return id
+	}
+
+	b.ancestors[entry.uniqueName] = true
+	defer delete(b.ancestors, entry.uniqueName)
+
+	for _, component := range components {
+		child := recipeEntry{
+			uniqueName: component.UniqueName,
+			name:       component.Name,
+			imageName:  component.ImageName,
+			embedded:   component.Components,
+		}
+		item, err := findComponent(ctx, b.itemRepo, b.components, component.UniqueName)
+		if err != nil {
+			logger.Warn(ctx, "service: ItemService.GetRecipeTree - error fetching component", "uniqueName", component.UniqueName, "error", err)
+			b.tree.MarkDegraded(models.SectionRecipeTree, "some components could not be looked up")
+		}
+		child.item = item
+
+		childIndex := len(b.tree.Nodes)
+		childID := b.add(ctx, child, depth+1, component.ItemCount*crafts)
+		if err != nil && len(component.Components) == 0 {
+			// Without the item its recipe is unknown, so it is shown as a leaf.
+			b.truncate(ctx, childIndex, models.RecipeTruncatedUnavailable)
+		}
+		b.tree.Edges = append(b.tree.Edges, models.RecipeEdge{From: id, To: childID, ItemCount: component.ItemCount})
+	}
+	return id
+}
+
+func (b *recipeTreeBuilder) truncate(ctx context.Context, index int, reason string) {
+	node := &b.tree.Nodes[index]
+	logger.Debug(ctx, "service: ItemService.GetRecipeTree - truncating node", "uniqueName", node.UniqueName, "reason", reason)
+	node.Truncated = reason
+	b.tree.Truncated = true
+}
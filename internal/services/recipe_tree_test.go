@@ -0,0 +1,281 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+// recipeNodesByName indexes a tree's nodes by uniqueName, keeping the first
+// occurrence.
+func recipeNodesByName(tree *models.RecipeTree) map[string]models.RecipeNode {
+	nodes := make(map[string]models.RecipeNode)
+	for _, node := range tree.Nodes {
+		if _, ok := nodes[node.UniqueName]; !ok {
+			nodes[node.UniqueName] = node
+		}
+	}
+	return nodes
+}
+
+func TestItemService_GetRecipeTree(t *testing.T) {
+	warframe := &models.Item{
+		UniqueName: "/Lotus/Ash",
+		Name:       "Ash",
+		BuildPrice: 25000,
+		Components: []models.Component{
+			{UniqueName: "/Lotus/AshNeuroptics", Name: "Neuroptics", ItemCount: 1},
+			{UniqueName: "/Lotus/Injector", Name: "Detonite Injector", ItemCount: 3},
+			{UniqueName: "/Lotus/OrokinCell", Name: "Orokin Cell", ItemCount: 1},
+		},
+	}
+	neuroptics := &models.Item{
+		UniqueName: "/Lotus/AshNeuroptics",
+		Name:       "Ash Neuroptics",
+		BuildPrice: 15000,
+		BuildTime:  43200,
+		Components: []models.Component{
+			{UniqueName: "/Lotus/Ferrite", Name: "Ferrite", ItemCount: 100},
+		},
+	}
+	injector := &models.Item{
+		UniqueName:    "/Lotus/Injector",
+		Name:          "Detonite Injector",
+		BuildPrice:    1000,
+		BuildQuantity: 2,
+		Components: []models.Component{
+			{UniqueName: "/Lotus/Ferrite", Name: "Ferrite", ItemCount: 50},
+		},
+	}
+
+	service := NewItemService(newCatalogItemRepository(warframe, neuroptics, injector))
+	tree, err := service.GetRecipeTree(context.Background(), "/Lotus/Ash")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(tree.Nodes) != 6 || len(tree.Edges) != 5 {
+		t.Fatalf("expected 6 nodes and 5 edges, got %d and %d", len(tree.Nodes), len(tree.Edges))
+	}
+	if tree.Root != tree.Nodes[0].ID || tree.Nodes[0].UniqueName != "/Lotus/Ash" {
+		t.Errorf("expected root to be Ash, got %s (%+v)", tree.Root, tree.Nodes[0])
+	}
+	if tree.Truncated || tree.IsDegraded() {
+		t.Errorf("expected a complete tree, got truncated=%v degraded=%v", tree.Truncated, tree.Degraded)
+	}
+
+	nodes := recipeNodesByName(tree)
+	if n := nodes["/Lotus/Ash"]; n.Crafts != 1 || n.Quantity != 1 || n.Depth != 0 {
+		t.Errorf("unexpected root node %+v", n)
+	}
+	if n := nodes["/Lotus/AshNeuroptics"]; n.Name != "Ash Neuroptics" || n.Crafts != 1 || n.BuildTime != 43200 || !n.HasOwnPage || n.Depth != 1 {
+		t.Errorf("unexpected neuroptics node %+v", n)
+	}
+	// 3 injectors at 2 per craft take 2 crafts, each using 50 ferrite.
+	if n := nodes["/Lotus/Injector"]; n.Quantity != 3 || n.Crafts != 2 || n.BuildQuantity != 2 {
+		t.Errorf("unexpected injector node %+v", n)
+	}
+	if n := nodes["/Lotus/OrokinCell"]; n.Name != "Orokin Cell" || n.Crafts != 0 || n.HasOwnPage {
+		t.Errorf("unexpected orokin cell node %+v", n)
+	}
+
+	ferrite := map[string]int{}
+	for _, node := range tree.Nodes {
+		if node.UniqueName == "/Lotus/Ferrite" {
+			ferrite[node.ID] = node.Quantity
+		}
+	}
+	var quantities []int
+	for _, edge := range tree.Edges {
+		if q, ok := ferrite[edge.To]; ok {
+			quantities = append(quantities, q)
+		}
+	}
+	if fmt.Sprint(quantities) != "[100 100]" {
+		t.Errorf("expected ferrite nodes of 100 under each parent, got %v", quantities)
+	}
+
+	if expected := 25000 + 15000 + 2*1000; tree.TotalCredits != expected {
+		t.Errorf("expected %d credits, got %d", expected, tree.TotalCredits)
+	}
+}
+
+func TestItemService_GetRecipeTree_Edges(t *testing.T) {
+	root := &models.Item{
+		UniqueName: "/Lotus/Root",
+		Components: []models.Component{
+			{UniqueName: "/Lotus/A", ItemCount: 2},
+			{UniqueName: "/Lotus/B", ItemCount: 5},
+		},
+	}
+
+	service := NewItemService(newCatalogItemRepository(root))
+	tree, err := service.GetRecipeTree(context.Background(), "/Lotus/Root")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []models.RecipeEdge{
+		{From: "n0", To: "n1", ItemCount: 2},
+		{From: "n0", To: "n2", ItemCount: 5},
+	}
+	if fmt.Sprint(tree.Edges) != fmt.Sprint(expected) {
+		t.Errorf("expected edges %v, got %v", expected, tree.Edges)
+	}
+}
+
+func TestItemService_GetRecipeTree_EmbeddedComponents(t *testing.T) {
+	root := &models.Item{
+		UniqueName: "/Lotus/Root",
+		Name:       "Root",
+		Components: []models.Component{
+			{
+				UniqueName: "/Lotus/Part",
+				Name:       "Part",
+				ItemCount:  2,
+				Components: []models.Component{
+					{UniqueName: "/Lotus/Alloy", Name: "Alloy Plate", ItemCount: 10},
+				},
+			},
+		},
+	}
+
+	service := NewItemService(newCatalogItemRepository(root))
+	tree, err := service.GetRecipeTree(context.Background(), "/Lotus/Root")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	nodes := recipeNodesByName(tree)
+	if n := nodes["/Lotus/Part"]; n.Crafts != 2 || n.HasOwnPage {
+		t.Errorf("unexpected embedded part node %+v", n)
+	}
+	if n := nodes["/Lotus/Alloy"]; n.Quantity != 20 || n.Depth != 2 {
+		t.Errorf("expected 20 alloy plates at depth 2, got %+v", n)
+	}
+}
+
+func TestItemService_GetRecipeTree_CycleIsTruncated(t *testing.T) {
+	a := &models.Item{UniqueName: "/Lotus/A", BuildPrice: 100, Components: []models.Component{{UniqueName: "/Lotus/B", ItemCount: 1}}}
+	b := &models.Item{UniqueName: "/Lotus/B", BuildPrice: 10, Components: []models.Component{{UniqueName: "/Lotus/A", ItemCount: 1}}}
+
+	service := NewItemService(newCatalogItemRepository(a, b))
+	tree, err := service.GetRecipeTree(context.Background(), "/Lotus/A")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(tree.Nodes) != 3 {
+		t.Fatalf("expected A, B and a truncated A, got %+v", tree.Nodes)
+	}
+	if last := tree.Nodes[2]; last.UniqueName != "/Lotus/A" || last.Truncated != models.RecipeTruncatedCycle {
+		t.Errorf("expected the repeated A to be truncated as a cycle, got %+v", last)
+	}
+	if !tree.Truncated {
+		t.Error("expected the tree to be marked truncated")
+	}
+	if tree.TotalCredits != 110 {
+		t.Errorf("expected the cycle's cost to be counted once (110), got %d", tree.TotalCredits)
+	}
+}
+
+func TestItemService_GetRecipeTree_DepthIsTruncated(t *testing.T) {
+	var catalog []*models.Item
+	for i := 0; i <= maxRecipeTreeDepth+2; i++ {
+		catalog = append(catalog, &models.Item{
+			UniqueName: fmt.Sprintf("/Lotus/Level%d", i),
+			Components: []models.Component{{UniqueName: fmt.Sprintf("/Lotus/Level%d", i+1), ItemCount: 1}},
+		})
+	}
+
+	service := NewItemService(newCatalogItemRepository(catalog...))
+	tree, err := service.GetRecipeTree(context.Background(), "/Lotus/Level0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(tree.Nodes) != maxRecipeTreeDepth+1 {
+		t.Fatalf("expected %d nodes, got %d", maxRecipeTreeDepth+1, len(tree.Nodes))
+	}
+	if last := tree.Nodes[len(tree.Nodes)-1]; last.Depth != maxRecipeTreeDepth || last.Truncated != models.RecipeTruncatedDepth {
+		t.Errorf("expected the deepest node to be truncated by depth, got %+v", last)
+	}
+}
+
+func TestItemService_GetRecipeTree_SizeIsTruncated(t *testing.T) {
+	root := &models.Item{UniqueName: "/Lotus/Root"}
+	for i := 0; i < maxRecipeTreeNodes; i++ {
+		root.Components = append(root.Components, models.Component{UniqueName: fmt.Sprintf("/Lotus/Part%d", i), ItemCount: 1})
+	}
+
+	service := NewItemService(newCatalogItemRepository(root))
+	tree, err := service.GetRecipeTree(context.Background(), "/Lotus/Root")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(tree.Nodes) != 1 || tree.Nodes[0].Truncated != models.RecipeTruncatedSize {
+		t.Errorf("expected only the root, truncated by size, got %d nodes (%+v)", len(tree.Nodes), tree.Nodes[0])
+	}
+}
+
+func TestItemService_GetRecipeTree_ComponentLookupFailureDegrades(t *testing.T) {
+	root := &models.Item{
+		UniqueName: "/Lotus/Root",
+		Components: []models.Component{{UniqueName: "/Lotus/Part", Name: "Part", ItemCount: 2}},
+	}
+	service := NewItemService(&mocks.MockItemRepository{
+		FindByUniqueNameFunc: func(ctx context.Context, uniqueName string) (*models.Item, error) {
+			if uniqueName == "/Lotus/Root" {
+				return root, nil
+			}
+			return nil, errors.New("database error")
+		},
+		FindByUniqueNamesFunc: func(ctx context.Context, uniqueNames []string) (map[string]*models.Item, error) {
+			return nil, errors.New("database error")
+		},
+	})
+
+	tree, err := service.GetRecipeTree(context.Background(), "/Lotus/Root")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(tree.Nodes) != 2 {
+		t.Fatalf("expected root and part, got %+v", tree.Nodes)
+	}
+	if part := tree.Nodes[1]; part.Name != "Part" || part.Quantity != 2 || part.Truncated != models.RecipeTruncatedUnavailable {
+		t.Errorf("expected the part to be an unavailable leaf, got %+v", part)
+	}
+	if len(tree.Degraded) != 1 || tree.Degraded[0].Section != models.SectionRecipeTree {
+		t.Errorf("expected degraded recipe tree section, got %+v", tree.Degraded)
+	}
+}
+
+func TestItemService_GetRecipeTree_NotFound(t *testing.T) {
+	service := NewItemService(newCatalogItemRepository())
+
+	tree, err := service.GetRecipeTree(context.Background(), "/Lotus/Missing")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tree != nil {
+		t.Errorf("expected nil tree, got %+v", tree)
+	}
+}
+
+func TestItemService_GetRecipeTree_RepositoryError(t *testing.T) {
+	service := NewItemService(&mocks.MockItemRepository{
+		FindByUniqueNameFunc: func(ctx context.Context, uniqueName string) (*models.Item, error) {
+			return nil, errors.New("database error")
+		},
+	})
+
+	if _, err := service.GetRecipeTree(context.Background(), "/Lotus/Ash"); err == nil {
+		t.Error("expected error")
+	}
+}
@@ -0,0 +1,211 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+)
+
+// relicDropPattern matches the drop locations of Prime parts: "Axi A1 Relic"
+// for an intact relic, "Axi A1 Relic (Radiant)" for a refined one.
+var relicDropPattern = regexp.MustCompile(`^((\S+) \S+) Relic(?: \((Intact|Exceptional|Flawless|Radiant)\))?$`)
+
+// RelicResolver works out which relics to open for the Prime parts a
+// wishlist still needs, from the parts' drop tables. The relics collection
+// supplies each relic's item.
+type RelicResolver struct {
+	wishlistRepo repository.WishlistRepositoryInterface
+	itemRepo     repository.ItemRepositoryInterface
+	relicCatalog repository.RelicCatalogInterface
+}
+
+func NewRelicResolver(wishlistRepo repository.WishlistRepositoryInterface, itemRepo repository.ItemRepositoryInterface, relicCatalog repository.RelicCatalogInterface) *RelicResolver {
+	return &RelicResolver{wishlistRepo: wishlistRepo, itemRepo: itemRepo, relicCatalog: relicCatalog}
+}
+
+// GetRelicRequirements lists the relics dropping the tradable components of
+// the Prime items on the user's wishlist, leaving out components the item's
+// progress marks done. Relics covering more parts come first, ties going to
+// the higher combined best-refinement chance.
+func (r *RelicResolver) GetRelicRequirements(ctx context.Context, userID string) (*models.RelicRequirements, error) {
+	logger.Debug(ctx, "service: RelicResolver.GetRelicRequirements called", "userID", userID)
+
+	result := &models.RelicRequirements{
+		Relics:      []models.RelicRequirement{},
+		Unavailable: []models.PrimePart{},
+	}
+
+	wishlist, err := r.wishlistRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "service: RelicResolver.GetRelicRequirements - error fetching wishlist", "error", err)
+		return nil, err
+	}
+	if wishlist == nil || len(wishlist.Items) == 0 {
+		return result, nil
+	}
+
+	uniqueNames := make([]string, len(wishlist.Items))
+	for i, wi := range wishlist.Items {
+		uniqueNames[i] = wi.UniqueName
+	}
+	items, err := r.itemRepo.FindByUniqueNames(ctx, uniqueNames)
+	if err != nil {
+		logger.Error(ctx, "service: RelicResolver.GetRelicRequirements - error fetching items", "error", err)
+		return nil, err
+	}
+
+	byRelic := make(map[string]*models.RelicRequirement)
+	for _, wi := range wishlist.Items {
+		item := items[wi.UniqueName]
+		if item == nil || !item.IsPrime {
+			continue
+		}
+		for _, need := range neededPrimeParts(recipeOrDefault(item, wi.RecipeID), wi) {
+			if !addRelicDrops(byRelic, need.part, need.drops) {
+				result.Unavailable = append(result.Unavailable, need.part)
+			}
+		}
+	}
+	if len(byRelic) == 0 {
+		return result, nil
+	}
+
+	intactNames := make([]string, 0, len(byRelic))
+	for name := range byRelic {
+		intactNames = append(intactNames, name+" "+models.RelicIntact)
+	}
+	relicItems, err := r.relicCatalog.FindRelicsByNames(ctx, intactNames)
+	if err != nil {
+		logger.Error(ctx, "service: RelicResolver.GetRelicRequirements - error fetching relics", "error", err)
+		return nil, err
+	}
+
+	for name, relic := range byRelic {
+		if relicItem := relicItems[name+" "+models.RelicIntact]; relicItem != nil {
+			relic.UniqueName = relicItem.UniqueName
+			relic.ImageName = relicItem.ImageName
+		}
+		slices.SortFunc(relic.Parts, func(a, b models.RelicPart) int {
+			if c := cmp.Compare(bestRelicChance(b.Chances), bestRelicChance(a.Chances)); c != 0 {
+				return c
+			}
+			return strings.Compare(a.Name, b.Name)
+		})
+		result.Relics = append(result.Relics, *relic)
+	}
+	slices.SortFunc(result.Relics, func(a, b models.RelicRequirement) int {
+		if c := cmp.Compare(len(b.Parts), len(a.Parts)); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(combinedBestChance(b), combinedBestChance(a)); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	logger.Debug(ctx, "service: RelicResolver.GetRelicRequirements - completed", "relicCount", len(result.Relics), "unavailableCount", len(result.Unavailable))
+	return result, nil
+}
+
+type neededPrimePart struct {
+	part  models.PrimePart
+	drops []models.Drop
+}
+
+// neededPrimeParts returns the tradable components of item the wishlist
+// item still needs, in recipe order.
+func neededPrimeParts(item *models.Item, wishlistItem models.WishlistItem) []neededPrimePart {
+	required := componentRequirements(item, wishlistItem.Quantity)
+	done := completedComponents(item, wishlistItem)
+
+	var parts []neededPrimePart
+	seen := make(map[string]bool, len(item.Components))
+	for _, component := range item.Components {
+		if !component.Tradable || seen[component.UniqueName] {
+			continue
+		}
+		seen[component.UniqueName] = true
+		needed := required[component.UniqueName] - done[component.UniqueName]
+		if needed <= 0 {
+			continue
+		}
+		parts = append(parts, neededPrimePart{
+			part: models.PrimePart{
+				UniqueName:     component.UniqueName,
+				Name:           item.Name + " " + component.Name,
+				ItemUniqueName: item.UniqueName,
+				ItemName:       item.Name,
+				Needed:         needed,
+			},
+			drops: component.Drops,
+		})
+	}
+	return parts
+}
+
+// addRelicDrops files part under every relic among drops, reporting whether
+// any relic drops it.
+func addRelicDrops(byRelic map[string]*models.RelicRequirement, part models.PrimePart, drops []models.Drop) bool {
+	found := false
+	for _, drop := range drops {
+		match := relicDropPattern.FindStringSubmatch(drop.Location)
+		if match == nil {
+			continue
+		}
+		found = true
+		name, tier, refinement := match[1], match[2], match[3]
+		if refinement == "" {
+			refinement = models.RelicIntact
+		}
+
+		relic := byRelic[name]
+		if relic == nil {
+			relic = &models.RelicRequirement{Name: name, Tier: tier}
+			byRelic[name] = relic
+		}
+		i := slices.IndexFunc(relic.Parts, func(p models.RelicPart) bool { return p.UniqueName == part.UniqueName })
+		if i < 0 {
+			relic.Parts = append(relic.Parts, models.RelicPart{PrimePart: part})
+			i = len(relic.Parts) - 1
+		}
+		relicPart := &relic.Parts[i]
+		if relicPart.Rarity == "" || refinement == models.RelicIntact {
+			relicPart.Rarity = drop.Rarity
+		}
+		setRelicChance(&relicPart.Chances, refinement, drop.Chance)
+	}
+	return found
+}
+
+func setRelicChance(chances *models.RelicChances, refinement string, chance float64) {
+	switch refinement {
+	case models.RelicIntact:
+		chances.Intact = chance
+	case models.RelicExceptional:
+		chances.Exceptional = chance
+	case models.RelicFlawless:
+		chances.Flawless = chance
+	case models.RelicRadiant:
+		chances.Radiant = chance
+	}
+}
+
+// bestRelicChance is a part's chance at the refinement most likely to drop
+// it: Radiant for rare parts, Intact for common ones.
+func bestRelicChance(chances models.RelicChances) float64 {
+	return max(chances.Intact, chances.Exceptional, chances.Flawless, chances.Radiant)
+}
+
+func combinedBestChance(relic models.RelicRequirement) float64 {
+	total := 0.0
+	for _, part := range relic.Parts {
+		total += bestRelicChance(part.Chances)
+	}
+	return total
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository/memory"
+)
+
+// newRelicFixture wishlists two Ash Primes, with one chassis already built,
+// and a Braton. The blueprint drops from Axi I3 and Lith S3, the chassis and
+// systems only from Axi I3, and the neuroptics from no relic.
+func newRelicFixture(t *testing.T) (*memory.WishlistRepository, *memory.ItemRepository) {
+	t.Helper()
+
+	wishlists := memory.NewWishlistRepository()
+	err := wishlists.Create(context.Background(), &models.Wishlist{UserID: "user-123", Items: []models.WishlistItem{
+		{UniqueName: "/Lotus/AshPrime", Quantity: 2, Progress: []models.ComponentProgress{
+			{UniqueName: "/Lotus/AshPrimeChassis", Status: models.ComponentBuilt, Count: 1},
+		}},
+		{UniqueName: "/Lotus/Braton", Quantity: 1},
+	}})
+	if err != nil {
+		t.Fatalf("failed to seed wishlist: %v", err)
+	}
+
+	items := memory.NewItemRepository()
+	items.Add("warframes", models.Item{UniqueName: "/Lotus/AshPrime", Name: "Ash Prime", IsPrime: true, Components: []models.Component{
+		{UniqueName: "/Lotus/AshPrimeBlueprint", Name: "Blueprint", ItemCount: 1, Tradable: true, Drops: []models.Drop{
+			{Location: "Axi I3 Relic", Rarity: "Uncommon", Chance: 0.11},
+			{Location: "Axi I3 Relic (Radiant)", Rarity: "Uncommon", Chance: 0.2},
+			{Location: "Lith S3 Relic", Rarity: "Uncommon", Chance: 0.11},
+		}},
+		{UniqueName: "/Lotus/AshPrimeChassis", Name: "Chassis", ItemCount: 1, Tradable: true, Drops: []models.Drop{
+			{Location: "Axi I3 Relic (Exceptional)", Rarity: "Common", Chance: 0.2333},
+		}},
+		{UniqueName: "/Lotus/AshPrimeNeuroptics", Name: "Neuroptics", ItemCount: 1, Tradable: true},
+		{UniqueName: "/Lotus/AshPrimeSystems", Name: "Systems", ItemCount: 1, Tradable: true, Drops: []models.Drop{
+			{Location: "Axi I3 Relic", Rarity: "Rare", Chance: 0.02},
+			{Location: "Axi I3 Relic (Radiant)", Rarity: "Rare", Chance: 0.1},
+			{Location: "Venus/Kiliken", Chance: 0.01},
+		}},
+		{UniqueName: "/Lotus/OrokinCell", Name: "Orokin Cell", ItemCount: 1, Drops: []models.Drop{{Location: "Lith S3 Relic", Chance: 0.5}}},
+	}})
+	items.Add("primary", models.Item{UniqueName: "/Lotus/Braton", Name: "Braton", Components: []models.Component{
+		{UniqueName: "/Lotus/BratonBarrel", Name: "Barrel", ItemCount: 1, Tradable: true, Drops: []models.Drop{{Location: "Lith B1 Relic", Chance: 0.25}}},
+	}})
+	items.Add("relics",
+		models.Item{UniqueName: "/Lotus/Relics/AxiI3Intact", Name: "Axi I3 Intact", ImageName: "axi-intact.png"},
+		models.Item{UniqueName: "/Lotus/Relics/AxiI3Radiant", Name: "Axi I3 Radiant", ImageName: "axi-radiant.png"},
+	)
+	return wishlists, items
+}
+
+func TestRelicResolver_GetRelicRequirements(t *testing.T) {
+	wishlists, items := newRelicFixture(t)
+	resolver := NewRelicResolver(wishlists, items, items)
+
+	requirements, err := resolver.GetRelicRequirements(context.Background(), "user-123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(requirements.Relics) != 2 {
+		t.Fatalf("expected Axi I3 and Lith S3, got %+v", requirements.Relics)
+	}
+
+	axi := requirements.Relics[0]
+	if axi.Name != "Axi I3" || axi.Tier != "Axi" || axi.UniqueName != "/Lotus/Relics/AxiI3Intact" || axi.ImageName != "axi-intact.png" {
+		t.Errorf("unexpected first relic %+v", axi)
+	}
+	expectedParts := []struct {
+		uniqueName string
+		name       string
+		needed     int
+		rarity     string
+		chances    models.RelicChances
+	}{
+		{"/Lotus/AshPrimeChassis", "Ash Prime Chassis", 1, "Common", models.RelicChances{Exceptional: 0.2333}},
+		{"/Lotus/AshPrimeBlueprint", "Ash Prime Blueprint", 2, "Uncommon", models.RelicChances{Intact: 0.11, Radiant: 0.2}},
+		{"/Lotus/AshPrimeSystems", "Ash Prime Systems", 2, "Rare", models.RelicChances{Intact: 0.02, Radiant: 0.1}},
+	}
+	if len(axi.Parts) != len(expectedParts) {
+		t.Fatalf("expected %d Axi I3 parts, got %+v", len(expectedParts), axi.Parts)
+	}
+	for i, want := range expectedParts {
+		part := axi.Parts[i]
+		if part.UniqueName != want.uniqueName || part.Name != want.name || part.Needed != want.needed || part.Rarity != want.rarity || part.Chances != want.chances {
+			t.Errorf("part %d: expected %+v, got %+v", i, want, part)
+		}
+		if part.ItemUniqueName != "/Lotus/AshPrime" || part.ItemName != "Ash Prime" {
+			t.Errorf("part %d: expected Ash Prime as its item, got %+v", i, part.PrimePart)
+		}
+	}
+
+	lith := requirements.Relics[1]
+	if lith.Name != "Lith S3" || lith.UniqueName != "" || len(lith.Parts) != 1 || lith.Parts[0].UniqueName != "/Lotus/AshPrimeBlueprint" {
+		t.Errorf("expected Lith S3 with only the blueprint and no relic item, got %+v", lith)
+	}
+
+	if len(requirements.Unavailable) != 1 || requirements.Unavailable[0].UniqueName != "/Lotus/AshPrimeNeuroptics" || requirements.Unavailable[0].Needed != 2 {
+		t.Errorf("expected the neuroptics to be unavailable, got %+v", requirements.Unavailable)
+	}
+}
+
+func TestRelicResolver_GetRelicRequirements_CompletedPartsLeftOut(t *testing.T) {
+	wishlists, items := newRelicFixture(t)
+	ctx := context.Background()
+	err := wishlists.SetItemProgress(ctx, "user-123", "/Lotus/AshPrime", []models.ComponentProgress{
+		{UniqueName: "/Lotus/AshPrimeBlueprint", Status: models.ComponentAcquired, Count: 2},
+		{UniqueName: "/Lotus/AshPrimeChassis", Status: models.ComponentBuilt, Count: 2},
+		{UniqueName: "/Lotus/AshPrimeNeuroptics", Status: models.ComponentBuilt, Count: 2},
+	})
+	if err != nil {
+		t.Fatalf("failed to set progress: %v", err)
+	}
+
+	requirements, err := NewRelicResolver(wishlists, items, items).GetRelicRequirements(ctx, "user-123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(requirements.Relics) != 1 || len(requirements.Relics[0].Parts) != 1 || requirements.Relics[0].Parts[0].UniqueName != "/Lotus/AshPrimeSystems" {
+		t.Errorf("expected only Axi I3 for the systems, got %+v", requirements.Relics)
+	}
+	if len(requirements.Unavailable) != 0 {
+		t.Errorf("expected nothing unavailable, got %+v", requirements.Unavailable)
+	}
+}
+
+func TestRelicResolver_GetRelicRequirements_EmptyWishlist(t *testing.T) {
+	resolver := NewRelicResolver(memory.NewWishlistRepository(), memory.NewItemRepository(), &mocks.MockRelicCatalog{
+		FindRelicsByNamesFunc: func(ctx context.Context, names []string) (map[string]*models.Item, error) {
+			t.Error("relics should not be looked up")
+			return nil, nil
+		},
+	})
+
+	requirements, err := resolver.GetRelicRequirements(context.Background(), "user-123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requirements.Relics == nil || len(requirements.Relics) != 0 || requirements.Unavailable == nil || len(requirements.Unavailable) != 0 {
+		t.Errorf("expected empty lists, got %+v", requirements)
+	}
+}
+
+func TestRelicResolver_GetRelicRequirements_Errors(t *testing.T) {
+	dbErr := errors.New("database error")
+	wishlists, items := newRelicFixture(t)
+
+	tests := []struct {
+		name     string
+		resolver *RelicResolver
+	}{
+		{
+			name: "wishlist error",
+			resolver: NewRelicResolver(&mocks.MockWishlistRepository{
+				GetByUserIDFunc: func(ctx context.Context, userID string) (*models.Wishlist, error) { return nil, dbErr },
+			}, items, items),
+		},
+		{
+			name: "item error",
+			resolver: NewRelicResolver(wishlists, &mocks.MockItemRepository{
+				FindByUniqueNamesFunc: func(ctx context.Context, uniqueNames []string) (map[string]*models.Item, error) { return nil, dbErr },
+			}, items),
+		},
+		{
+			name: "relic error",
+			resolver: NewRelicResolver(wishlists, items, &mocks.MockRelicCatalog{
+				FindRelicsByNamesFunc: func(ctx context.Context, names []string) (map[string]*models.Item, error) { return nil, dbErr },
+			}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.resolver.GetRelicRequirements(context.Background(), "user-123"); !errors.Is(err, dbErr) {
+				t.Errorf("expected database error, got %v", err)
+			}
+		})
+	}
+}
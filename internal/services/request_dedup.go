@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+)
+
+// DedupedMaterialResolver shares one resolution between a user's identical
+// concurrent materials requests, such as the UI firing the same request
+// twice. Each caller gets its own copy of the response.
+//
+// A request made after a change must not join a resolution started before
+// it, so the services that change a user's materials invalidate through
+// InvalidatingCache.
+type DedupedMaterialResolver struct {
+	next    MaterialResolverInterface
+	flights flightGroup[*models.MaterialsResponse]
+}
+
+func NewDedupedMaterialResolver(next MaterialResolverInterface) *DedupedMaterialResolver {
+	return &DedupedMaterialResolver{next: next}
+}
+
+func (r *DedupedMaterialResolver) GetMaterials(ctx context.Context, userID string) (*models.MaterialsResponse, error) {
+	response, shared, err := r.flights.do(ctx, userID, func(ctx context.Context) (*models.MaterialsResponse, error) {
+		return r.next.GetMaterials(ctx, userID)
+	})
+	if err != nil || response == nil {
+		return response, err
+	}
+	if shared {
+		logger.Debug(ctx, "service: DedupedMaterialResolver.GetMaterials - shared in-flight resolution")
+	}
+	return cloneMaterialsResponse(response), nil
+}
+
+// InvalidatingCache returns a MaterialsCache that forwards to cache, which
+// may be nil when caching is disabled, and also makes invalidated users'
+// next requests start a new resolution.
+func (r *DedupedMaterialResolver) InvalidatingCache(cache MaterialsCache) MaterialsCache {
+	return &dedupedMaterialsCache{cache: cache, resolver: r}
+}
+
+type dedupedMaterialsCache struct {
+	cache    MaterialsCache
+	resolver *DedupedMaterialResolver
+}
+
+func (c *dedupedMaterialsCache) Get(ctx context.Context, key MaterialsCacheKey) (*models.MaterialsResponse, bool) {
+	if c.cache == nil {
+		return nil, false
+	}
+	return c.cache.Get(ctx, key)
+}
+
+func (c *dedupedMaterialsCache) Set(ctx context.Context, key MaterialsCacheKey, response *models.MaterialsResponse) {
+	if c.cache != nil {
+		c.cache.Set(ctx, key, response)
+	}
+}
+
+func (c *dedupedMaterialsCache) Generation(ctx context.Context, userID string) uint64 {
+	if c.cache == nil {
+		return 0
+	}
+	return c.cache.Generation(ctx, userID)
+}
+
+func (c *dedupedMaterialsCache) Invalidate(ctx context.Context, userID string) {
+	c.resolver.flights.forget(userID)
+	if c.cache != nil {
+		c.cache.Invalidate(ctx, userID)
+	}
+}
+
+func (c *dedupedMaterialsCache) Purge(ctx context.Context) {
+	c.resolver.flights.forgetAll()
+	if c.cache != nil {
+		c.cache.Purge(ctx)
+	}
+}
+
+// DedupedItemService shares one lookup between identical concurrent item
+// detail requests. Item data is the same for every user, so requests from
+// different users share too. Each caller gets its own copy of the item.
+type DedupedItemService struct {
+	ItemServiceInterface
+	flights flightGroup[*models.Item]
+}
+
+func NewDedupedItemService(next ItemServiceInterface) *DedupedItemService {
+	return &DedupedItemService{ItemServiceInterface: next}
+}
+
+func (s *DedupedItemService) GetByUniqueName(ctx context.Context, uniqueName string) (*models.Item, error) {
+	item, shared, err := s.flights.do(ctx, uniqueName, func(ctx context.Context) (*models.Item, error) {
+		return s.ItemServiceInterface.GetByUniqueName(ctx, uniqueName)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if shared {
+		logger.Debug(ctx, "service: DedupedItemService.GetByUniqueName - shared in-flight lookup", "uniqueName", uniqueName)
+	}
+	return item.Clone(), nil
+}
+
+// Forget makes the next request for every item start a new lookup, so none
+// started before a data sync is joined after it.
+func (s *DedupedItemService) Forget() {
+	s.flights.forgetAll()
+}
@@ -0,0 +1,272 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+// blockingMaterialResolver holds every resolution until release is closed.
+type blockingMaterialResolver struct {
+	calls   atomic.Int32
+	started chan struct{}
+	release chan struct{}
+	err     error
+}
+
+func newBlockingMaterialResolver() *blockingMaterialResolver {
+	return &blockingMaterialResolver{started: make(chan struct{}, 10), release: make(chan struct{})}
+}
+
+func (r *blockingMaterialResolver) GetMaterials(ctx context.Context, userID string) (*models.MaterialsResponse, error) {
+	r.calls.Add(1)
+	r.started <- struct{}{}
+	<-r.release
+	if r.err != nil {
+		return nil, r.err
+	}
+	return testMaterialsResponse(1000), nil
+}
+
+// getConcurrently calls get n times at once and returns the responses and
+// errors once all have returned. It gives the calls time to join the first
+// before releasing it.
+func getConcurrently(t *testing.T, n int, started <-chan struct{}, release chan<- struct{}, get func() (*models.MaterialsResponse, error)) ([]*models.MaterialsResponse, []error) {
+	t.Helper()
+	responses := make([]*models.MaterialsResponse, n)
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			responses[i], errs[i] = get()
+		}()
+	}
+	<-started
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	return responses, errs
+}
+
+func TestDedupedMaterialResolver_SharesConcurrentResolutions(t *testing.T) {
+	next := newBlockingMaterialResolver()
+	resolver := NewDedupedMaterialResolver(next)
+
+	responses, errs := getConcurrently(t, 5, next.started, next.release, func() (*models.MaterialsResponse, error) {
+		return resolver.GetMaterials(context.Background(), "user-123")
+	})
+
+	if calls := next.calls.Load(); calls != 1 {
+		t.Fatalf("expected one resolution, got %d", calls)
+	}
+	for i, err := range errs {
+		if err != nil || responses[i].TotalCredits != 1000 {
+			t.Fatalf("expected every caller to get the response, got %+v %v", responses[i], err)
+		}
+	}
+
+	// Callers get copies, so one changing its response leaves the others intact.
+	responses[0].Materials[0].TotalCount = 1
+	if responses[1].Materials[0].TotalCount != 100 {
+		t.Errorf("expected independent copies, got %+v", responses[1].Materials)
+	}
+
+	// Once it has returned, the next request resolves again.
+	next.release = make(chan struct{})
+	close(next.release)
+	if _, err := resolver.GetMaterials(context.Background(), "user-123"); err != nil || next.calls.Load() != 2 {
+		t.Errorf("expected a new resolution, got %d calls (%v)", next.calls.Load(), err)
+	}
+}
+
+func TestDedupedMaterialResolver_SharesErrors(t *testing.T) {
+	next := newBlockingMaterialResolver()
+	next.err = errors.New("database error")
+	resolver := NewDedupedMaterialResolver(next)
+
+	_, errs := getConcurrently(t, 3, next.started, next.release, func() (*models.MaterialsResponse, error) {
+		return resolver.GetMaterials(context.Background(), "user-123")
+	})
+
+	if calls := next.calls.Load(); calls != 1 {
+		t.Fatalf("expected one resolution, got %d", calls)
+	}
+	for _, err := range errs {
+		if !errors.Is(err, next.err) {
+			t.Errorf("expected the shared error, got %v", err)
+		}
+	}
+}
+
+func TestDedupedMaterialResolver_KeysByUser(t *testing.T) {
+	next := newBlockingMaterialResolver()
+	close(next.release)
+	resolver := NewDedupedMaterialResolver(next)
+
+	var wg sync.WaitGroup
+	for _, userID := range []string{"user-1", "user-2"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := resolver.GetMaterials(context.Background(), userID); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if calls := next.calls.Load(); calls != 2 {
+		t.Errorf("expected one resolution per user, got %d", calls)
+	}
+}
+
+func TestDedupedMaterialResolver_CanceledCallerLeavesOthers(t *testing.T) {
+	next := newBlockingMaterialResolver()
+	resolver := NewDedupedMaterialResolver(next)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := resolver.GetMaterials(ctx, "user-123")
+		first <- err
+	}()
+	<-next.started
+
+	second := make(chan error, 1)
+	go func() {
+		_, err := resolver.GetMaterials(context.Background(), "user-123")
+		second <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	cancel()
+	if err := <-first; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the canceled caller to stop waiting, got %v", err)
+	}
+	close(next.release)
+	if err := <-second; err != nil {
+		t.Errorf("expected the other caller to get the response, got %v", err)
+	}
+	if calls := next.calls.Load(); calls != 1 {
+		t.Errorf("expected one resolution, got %d", calls)
+	}
+}
+
+func TestDedupedMaterialResolver_InvalidateStartsNewResolution(t *testing.T) {
+	ctx := context.Background()
+	next := newBlockingMaterialResolver()
+	resolver := NewDedupedMaterialResolver(next)
+	lru, _ := newTestMaterialsCache(10)
+	cache := resolver.InvalidatingCache(lru)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		resolver.GetMaterials(ctx, "user-123")
+	}()
+	<-next.started
+
+	// A change while the first resolution is in flight must not be hidden
+	// from requests made after it.
+	key := MaterialsCacheKey{UserID: "user-123", UpdatedAt: materialsCacheTestTime}
+	lru.Set(ctx, key, testMaterialsResponse(500))
+	cache.Invalidate(ctx, "user-123")
+	if _, ok := lru.Get(ctx, key); ok {
+		t.Error("expected the cached entry to be invalidated too")
+	}
+
+	second := make(chan error, 1)
+	go func() {
+		_, err := resolver.GetMaterials(ctx, "user-123")
+		second <- err
+	}()
+	<-next.started
+	close(next.release)
+	<-done
+	if err := <-second; err != nil || next.calls.Load() != 2 {
+		t.Errorf("expected a second resolution, got %d calls (%v)", next.calls.Load(), err)
+	}
+
+	// Without a cache the wrapper only forgets resolutions.
+	uncached := resolver.InvalidatingCache(nil)
+	uncached.Set(ctx, key, testMaterialsResponse(500))
+	if _, ok := uncached.Get(ctx, key); ok {
+		t.Error("expected a miss without a cache")
+	}
+	uncached.Invalidate(ctx, "user-123")
+	uncached.Purge(ctx)
+}
+
+func TestDedupedItemService_SharesConcurrentLookups(t *testing.T) {
+	var calls atomic.Int32
+	started := make(chan struct{}, 10)
+	release := make(chan struct{})
+	next := &mocks.MockItemService{
+		GetByUniqueNameFunc: func(ctx context.Context, uniqueName string) (*models.Item, error) {
+			calls.Add(1)
+			started <- struct{}{}
+			<-release
+			return &models.Item{UniqueName: uniqueName, Name: "Ash Prime", Components: []models.Component{{UniqueName: "/Lotus/Chassis", ItemCount: 1}}}, nil
+		},
+		SearchFunc: func(ctx context.Context, params models.SearchParams) (*models.ItemSearchPage, error) {
+			return &models.ItemSearchPage{Total: 7}, nil
+		},
+	}
+	service := NewDedupedItemService(next)
+
+	items := make([]*models.Item, 4)
+	var wg sync.WaitGroup
+	for i := range items {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			item, err := service.GetByUniqueName(context.Background(), "/Lotus/AshPrime")
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			items[i] = item
+		}()
+	}
+	<-started
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Fatalf("expected one lookup, got %d", calls.Load())
+	}
+	items[0].Components[0].HasOwnPage = true
+	if items[1].Components[0].HasOwnPage {
+		t.Error("expected independent copies of the item")
+	}
+
+	if page, err := service.Search(context.Background(), models.SearchParams{}); err != nil || page.Total != 7 {
+		t.Errorf("expected other methods to pass through, got %+v %v", page, err)
+	}
+}
+
+func TestDedupedItemService_NotFound(t *testing.T) {
+	service := NewDedupedItemService(&mocks.MockItemService{})
+	item, err := service.GetByUniqueName(context.Background(), "/Lotus/Missing")
+	if item != nil || err != nil {
+		t.Errorf("expected a nil item, got %+v %v", item, err)
+	}
+}
+
+func TestFlightGroup_RecoversPanics(t *testing.T) {
+	var group flightGroup[int]
+	_, _, err := group.do(context.Background(), "key", func(ctx context.Context) (int, error) {
+		panic("boom")
+	})
+	if err == nil || err.Error() != "panic: boom" {
+		t.Errorf("expected the panic as an error, got %v", err)
+	}
+}
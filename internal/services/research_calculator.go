@@ -0,0 +1,159 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+)
+
+var ErrInvalidResearchRequest = errors.New("invalid research request")
+
+// ResearchCalculator totals clan dojo research costs. The item data carries
+// no separate research costs, so an item's recipe is taken as its research
+// cost for a ghost clan and scaled by the clan tier's multiplier.
+type ResearchCalculator struct {
+	itemRepo repository.ItemRepositoryInterface
+}
+
+func NewResearchCalculator(itemRepo repository.ItemRepositoryInterface) *ResearchCalculator {
+	return &ResearchCalculator{itemRepo: itemRepo}
+}
+
+// Calculate returns the research costs of the requested items at the
+// requested tier, grouped by lab in the order the labs first appear. An item
+// requested twice is counted once.
+func (c *ResearchCalculator) Calculate(ctx context.Context, req models.ResearchCostRequest) (*models.ResearchCost, error) {
+	logger.Debug(ctx, "service: ResearchCalculator.Calculate called", "tier", req.Tier, "itemCount", len(req.Items))
+
+	tier := req.Tier
+	if tier == "" {
+		tier = models.DefaultClanTier
+	}
+	multiplier, ok := models.ClanTierMultiplier(tier)
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown clan tier %q", ErrInvalidResearchRequest, req.Tier)
+	}
+	if len(req.Items) == 0 {
+		return nil, fmt.Errorf("%w: no items to research", ErrInvalidResearchRequest)
+	}
+	if len(req.Items) > models.MaxResearchItems {
+		return nil, fmt.Errorf("%w: at most %d items", ErrInvalidResearchRequest, models.MaxResearchItems)
+	}
+
+	var requested []models.ResearchItemRequest
+	var uniqueNames []string
+	seen := make(map[string]bool, len(req.Items))
+	for _, item := range req.Items {
+		uniqueName, err := models.CanonicalUniqueName(item.UniqueName)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidResearchRequest, err)
+		}
+		if seen[uniqueName] {
+			continue
+		}
+		seen[uniqueName] = true
+		requested = append(requested, models.ResearchItemRequest{UniqueName: uniqueName, Lab: item.Lab})
+		uniqueNames = append(uniqueNames, uniqueName)
+	}
+
+	items, err := c.itemRepo.FindByUniqueNames(ctx, uniqueNames)
+	if err != nil {
+		logger.Error(ctx, "service: ResearchCalculator.Calculate - error fetching items", "error", err)
+		return nil, err
+	}
+
+	result := &models.ResearchCost{
+		Tier:       tier,
+		Multiplier: multiplier,
+		Labs:       []models.ResearchLabCost{},
+		NotFound:   []string{},
+	}
+	labIndex := make(map[string]int)
+	labMaterials := make(map[string]map[string]*models.ResearchMaterial)
+	totalMaterials := make(map[string]*models.ResearchMaterial)
+	for _, r := range requested {
+		item, ok := items[r.UniqueName]
+		if !ok {
+			result.NotFound = append(result.NotFound, r.UniqueName)
+			continue
+		}
+
+		cost := researchItemCost(item, r.Lab, multiplier)
+		i, ok := labIndex[r.Lab]
+		if !ok {
+			i = len(result.Labs)
+			labIndex[r.Lab] = i
+			labMaterials[r.Lab] = make(map[string]*models.ResearchMaterial)
+			result.Labs = append(result.Labs, models.ResearchLabCost{Lab: r.Lab})
+		}
+		lab := &result.Labs[i]
+		lab.Items = append(lab.Items, cost)
+		lab.Credits += cost.Credits
+		result.Credits += cost.Credits
+		for _, m := range cost.Materials {
+			addResearchMaterial(labMaterials[r.Lab], m)
+			addResearchMaterial(totalMaterials, m)
+		}
+	}
+	for i := range result.Labs {
+		result.Labs[i].Materials = sortedResearchMaterials(labMaterials[result.Labs[i].Lab])
+	}
+	result.Materials = sortedResearchMaterials(totalMaterials)
+
+	logger.Info(ctx, "service: ResearchCalculator.Calculate - completed", "tier", tier, "labCount", len(result.Labs), "notFound", len(result.NotFound))
+	return result, nil
+}
+
+// researchItemCost scales the item's build price and direct recipe
+// components by multiplier. Components are not expanded: research consumes
+// them as they are. The item's own blueprint is what research grants, so it
+// is left out.
+func researchItemCost(item *models.Item, lab string, multiplier int) models.ResearchItemCost {
+	cost := models.ResearchItemCost{
+		UniqueName: item.UniqueName,
+		Name:       item.Name,
+		Lab:        lab,
+		Credits:    item.BuildPrice * multiplier,
+		Materials:  []models.ResearchMaterial{},
+	}
+	for _, component := range item.Components {
+		if component.ItemCount <= 0 || component.Name == "Blueprint" {
+			continue
+		}
+		cost.Materials = append(cost.Materials, models.ResearchMaterial{
+			UniqueName: component.UniqueName,
+			Name:       component.Name,
+			ImageName:  component.ImageName,
+			Count:      component.ItemCount * multiplier,
+		})
+	}
+	return cost
+}
+
+func addResearchMaterial(totals map[string]*models.ResearchMaterial, m models.ResearchMaterial) {
+	if total, ok := totals[m.UniqueName]; ok {
+		total.Count += m.Count
+		return
+	}
+	totals[m.UniqueName] = &m
+}
+
+func sortedResearchMaterials(totals map[string]*models.ResearchMaterial) []models.ResearchMaterial {
+	materials := make([]models.ResearchMaterial, 0, len(totals))
+	for _, m := range totals {
+		materials = append(materials, *m)
+	}
+	slices.SortFunc(materials, func(a, b models.ResearchMaterial) int {
+		if c := cmp.Compare(a.Name, b.Name); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.UniqueName, b.UniqueName)
+	})
+	return materials
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository/memory"
+)
+
+func newResearchFixture() *memory.ItemRepository {
+	items := memory.NewItemRepository()
+	items.Add("primary", models.Item{UniqueName: "/Lotus/Ogris", Name: "Ogris", BuildPrice: 25000, Components: []models.Component{
+		{UniqueName: "/Lotus/OgrisBlueprint", Name: "Blueprint", ItemCount: 1},
+		{UniqueName: "/Lotus/Nitain", Name: "Nitain Extract", ItemCount: 1},
+		{UniqueName: "/Lotus/Plastids", Name: "Plastids", ItemCount: 500},
+	}})
+	items.Add("melee", models.Item{UniqueName: "/Lotus/Jat", Name: "Jat Kittag", BuildPrice: 20000, Components: []models.Component{
+		{UniqueName: "/Lotus/Plastids", Name: "Plastids", ItemCount: 200},
+	}})
+	items.Add("gear", models.Item{UniqueName: "/Lotus/Spectre", Name: "Ice Spectre", BuildPrice: 1000, Components: []models.Component{
+		{UniqueName: "/Lotus/Cryotic", Name: "Cryotic", ItemCount: 50},
+	}})
+	return items
+}
+
+func TestResearchCalculator_Calculate(t *testing.T) {
+	calculator := NewResearchCalculator(newResearchFixture())
+
+	cost, err := calculator.Calculate(context.Background(), models.ResearchCostRequest{Tier: "storm", Items: []models.ResearchItemRequest{
+		{UniqueName: "/Lotus/Ogris", Lab: "Chem Lab"},
+		{UniqueName: "/Lotus/Spectre", Lab: "Orokin Lab"},
+		{UniqueName: "Lotus/Jat", Lab: "Chem Lab"},
+		{UniqueName: "/Lotus/Ogris", Lab: "Chem Lab"},
+		{UniqueName: "/Lotus/Gone", Lab: "Bio Lab"},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cost.Tier != "storm" || cost.Multiplier != 10 {
+		t.Errorf("expected storm at x10, got %s at x%d", cost.Tier, cost.Multiplier)
+	}
+	if cost.Credits != (25000+20000+1000)*10 {
+		t.Errorf("expected 460000 credits, got %d", cost.Credits)
+	}
+	expectedTotal := []models.ResearchMaterial{
+		{UniqueName: "/Lotus/Cryotic", Name: "Cryotic", Count: 500},
+		{UniqueName: "/Lotus/Nitain", Name: "Nitain Extract", Count: 10},
+		{UniqueName: "/Lotus/Plastids", Name: "Plastids", Count: 7000},
+	}
+	if !reflect.DeepEqual(cost.Materials, expectedTotal) {
+		t.Errorf("expected materials %+v, got %+v", expectedTotal, cost.Materials)
+	}
+	if !reflect.DeepEqual(cost.NotFound, []string{"/Lotus/Gone"}) {
+		t.Errorf("expected /Lotus/Gone not found, got %v", cost.NotFound)
+	}
+
+	if len(cost.Labs) != 2 || cost.Labs[0].Lab != "Chem Lab" || cost.Labs[1].Lab != "Orokin Lab" {
+		t.Fatalf("expected the chem and Orokin labs in request order, got %+v", cost.Labs)
+	}
+	chem := cost.Labs[0]
+	if len(chem.Items) != 2 || chem.Items[0].Name != "Ogris" || chem.Items[1].Name != "Jat Kittag" {
+		t.Errorf("expected the Ogris once and the Jat Kittag, got %+v", chem.Items)
+	}
+	if chem.Credits != 450000 || len(chem.Materials) != 2 || chem.Materials[1].Count != 7000 {
+		t.Errorf("unexpected chem lab totals %+v", chem)
+	}
+}
+
+func TestResearchCalculator_Calculate_DefaultTier(t *testing.T) {
+	calculator := NewResearchCalculator(newResearchFixture())
+
+	cost, err := calculator.Calculate(context.Background(), models.ResearchCostRequest{Items: []models.ResearchItemRequest{{UniqueName: "/Lotus/Spectre"}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cost.Tier != models.DefaultClanTier || cost.Multiplier != 1 || cost.Credits != 1000 || cost.Materials[0].Count != 50 {
+		t.Errorf("expected the ghost cost, got %+v", cost)
+	}
+	if len(cost.Labs) != 1 || cost.Labs[0].Lab != "" {
+		t.Errorf("expected one unlabelled lab, got %+v", cost.Labs)
+	}
+}
+
+func TestResearchCalculator_Calculate_Invalid(t *testing.T) {
+	calculator := NewResearchCalculator(newResearchFixture())
+
+	tests := []struct {
+		name string
+		req  models.ResearchCostRequest
+	}{
+		{name: "unknown tier", req: models.ResearchCostRequest{Tier: "galaxy", Items: []models.ResearchItemRequest{{UniqueName: "/Lotus/Ogris"}}}},
+		{name: "no items", req: models.ResearchCostRequest{Tier: "moon"}},
+		{name: "too many items", req: models.ResearchCostRequest{Items: make([]models.ResearchItemRequest, models.MaxResearchItems+1)}},
+		{name: "missing uniqueName", req: models.ResearchCostRequest{Items: []models.ResearchItemRequest{{Lab: "Chem Lab"}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := calculator.Calculate(context.Background(), tt.req); !errors.Is(err, ErrInvalidResearchRequest) {
+				t.Errorf("expected ErrInvalidResearchRequest, got %v", err)
+			}
+		})
+	}
+}
+
+func TestResearchCalculator_Calculate_RepositoryError(t *testing.T) {
+	repoErr := errors.New("connection refused")
+	calculator := NewResearchCalculator(&mocks.MockItemRepository{
+		FindByUniqueNamesFunc: func(ctx context.Context, uniqueNames []string) (map[string]*models.Item, error) {
+			return nil, repoErr
+		},
+	})
+
+	_, err := calculator.Calculate(context.Background(), models.ResearchCostRequest{Items: []models.ResearchItemRequest{{UniqueName: "/Lotus/Ogris"}}})
+	if !errors.Is(err, repoErr) {
+		t.Errorf("expected the repository error, got %v", err)
+	}
+}
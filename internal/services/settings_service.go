@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+)
+
+var (
+	ErrInvalidTimeZone          = errors.New("invalid time zone")
+	ErrInvalidDefaultQuantities = errors.New("invalid default quantities")
+	ErrInvalidMilestones        = errors.New("invalid milestones")
+	ErrInvalidMaterialsRounding = errors.New("invalid materials rounding")
+	ErrInvalidDigestTime        = errors.New("invalid digest time")
+)
+
+type SettingsService struct {
+	settingsRepo repository.SettingsRepositoryInterface
+	// materialsCache is optional; changing the materials rounding drops the
+	// user's cached materials.
+	materialsCache MaterialsCache
+}
+
+func NewSettingsService(settingsRepo repository.SettingsRepositoryInterface) *SettingsService {
+	return &SettingsService{settingsRepo: settingsRepo}
+}
+
+// SetMaterialsCache makes materials rounding changes invalidate the user's
+// entry in cache.
+func (s *SettingsService) SetMaterialsCache(cache MaterialsCache) {
+	s.materialsCache = cache
+}
+
+func (s *SettingsService) GetSettings(ctx context.Context, userID string) (*models.UserSettings, error) {
+	logger.Debug(ctx, "service: SettingsService.GetSettings called", "userID", userID)
+
+	settings, err := s.settingsRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "service: SettingsService.GetSettings - repository error", "error", err)
+		return nil, err
+	}
+
+	if settings == nil {
+		logger.Debug(ctx, "service: SettingsService.GetSettings - returning default settings for new user")
+		settings = &models.UserSettings{
+			UserID:    userID,
+			TimeZone:  models.DefaultTimeZone,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}
+	}
+	if settings.TimeZone == "" {
+		settings.TimeZone = models.DefaultTimeZone
+	}
+	if settings.MaterialsRounding == "" {
+		settings.MaterialsRounding = models.MaterialsRoundingStrict
+	}
+
+	logger.Debug(ctx, "service: SettingsService.GetSettings - completed", "timeZone", settings.TimeZone)
+	return settings, nil
+}
+
+func (s *SettingsService) UpdateSettings(ctx context.Context, userID string, req models.UpdateSettingsRequest) (*models.UserSettings, error) {
+	logger.Debug(ctx, "service: SettingsService.UpdateSettings called", "userID", userID)
+
+	settings, err := s.GetSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.TimeZone != nil {
+		if _, err := time.LoadLocation(*req.TimeZone); err != nil || *req.TimeZone == "" {
+			logger.Warn(ctx, "service: SettingsService.UpdateSettings - invalid time zone", "timeZone", *req.TimeZone)
+			return nil, ErrInvalidTimeZone
+		}
+		settings.TimeZone = *req.TimeZone
+	}
+
+	if req.DefaultQuantities != nil {
+		rules, err := validateDefaultQuantities(*req.DefaultQuantities)
+		if err != nil {
+			logger.Warn(ctx, "service: SettingsService.UpdateSettings - invalid default quantities", "error", err)
+			return nil, err
+		}
+		settings.DefaultQuantities = rules
+	}
+
+	if req.PublicWishlist != nil {
+		settings.PublicWishlist = *req.PublicWishlist
+	}
+
+	if req.MutedMilestones != nil {
+		for _, milestone := range *req.MutedMilestones {
+			if !slices.Contains(models.Milestones, milestone) {
+				logger.Warn(ctx, "service: SettingsService.UpdateSettings - unknown milestone", "milestone", milestone)
+				return nil, fmt.Errorf("%w: unknown milestone %q", ErrInvalidMilestones, milestone)
+			}
+		}
+		settings.MutedMilestones = dedupeStrings(*req.MutedMilestones)
+	}
+
+	if req.SkipStarterWishlist != nil {
+		settings.SkipStarterWishlist = *req.SkipStarterWishlist
+	}
+
+	if req.DigestTime != nil {
+		if _, err := time.Parse(models.DigestTimeLayout, *req.DigestTime); err != nil && *req.DigestTime != "" {
+			logger.Warn(ctx, "service: SettingsService.UpdateSettings - invalid digest time", "digestTime", *req.DigestTime)
+			return nil, fmt.Errorf("%w: must be HH:MM or empty", ErrInvalidDigestTime)
+		}
+		settings.DigestTime = *req.DigestTime
+	}
+
+	roundingChanged := false
+	if req.MaterialsRounding != nil {
+		if !slices.Contains(models.MaterialsRoundingModes, *req.MaterialsRounding) {
+			logger.Warn(ctx, "service: SettingsService.UpdateSettings - unknown materials rounding", "materialsRounding", *req.MaterialsRounding)
+			return nil, fmt.Errorf("%w: must be one of %s", ErrInvalidMaterialsRounding, strings.Join(models.MaterialsRoundingModes, ", "))
+		}
+		roundingChanged = settings.MaterialsRounding != *req.MaterialsRounding
+		settings.MaterialsRounding = *req.MaterialsRounding
+	}
+
+	if err := s.settingsRepo.Upsert(ctx, settings); err != nil {
+		logger.Error(ctx, "service: SettingsService.UpdateSettings - error saving settings", "error", err)
+		return nil, err
+	}
+	if roundingChanged {
+		invalidateMaterials(ctx, s.materialsCache, userID)
+	}
+
+	logger.Info(ctx, "service: SettingsService.UpdateSettings - settings updated successfully", "timeZone", settings.TimeZone)
+	return settings, nil
+}
+
+// validateDefaultQuantities returns the rules with their category and type
+// trimmed, or an error wrapping ErrInvalidDefaultQuantities that names the
+// problem.
+func validateDefaultQuantities(rules []models.DefaultQuantityRule) ([]models.DefaultQuantityRule, error) {
+	if len(rules) > models.MaxDefaultQuantityRules {
+		return nil, fmt.Errorf("%w: at most %d rules are allowed", ErrInvalidDefaultQuantities, models.MaxDefaultQuantityRules)
+	}
+
+	validated := make([]models.DefaultQuantityRule, 0, len(rules))
+	seen := make(map[string]bool)
+	for _, rule := range rules {
+		rule.Category = strings.TrimSpace(rule.Category)
+		rule.Type = strings.TrimSpace(rule.Type)
+		if rule.Category == "" {
+			return nil, fmt.Errorf("%w: category is required", ErrInvalidDefaultQuantities)
+		}
+		if rule.Quantity <= 0 {
+			return nil, fmt.Errorf("%w: quantity for %s must be greater than 0", ErrInvalidDefaultQuantities, rule.Category)
+		}
+		key := strings.ToLower(rule.Category + "/" + rule.Type)
+		if seen[key] {
+			return nil, fmt.Errorf("%w: duplicate rule for %s", ErrInvalidDefaultQuantities, strings.TrimSuffix(rule.Category+"/"+rule.Type, "/"))
+		}
+		seen[key] = true
+		validated = append(validated, rule)
+	}
+	return validated, nil
+}
+
+// defaultQuantity returns the quantity item is added with when a request
+// omits it, from userID's default quantity rules. Without a settings
+// repository, or when the settings cannot be read, it is 1: the rules are a
+// convenience and do not fail the addition.
+func defaultQuantity(ctx context.Context, settingsRepo repository.SettingsRepositoryInterface, userID string, item *models.Item) int {
+	if settingsRepo == nil {
+		return 1
+	}
+	settings, err := settingsRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		logger.Warn(ctx, "service: defaultQuantity - failed to get settings, using 1", "error", err)
+		return 1
+	}
+	return settings.DefaultQuantity(item)
+}
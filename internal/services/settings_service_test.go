@@ -0,0 +1,307 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestSettingsService_GetSettings(t *testing.T) {
+	tests := []struct {
+		name             string
+		mockReturn       *models.UserSettings
+		mockError        error
+		expectError      bool
+		expectedTimeZone string
+	}{
+		{
+			name:             "existing settings",
+			mockReturn:       &models.UserSettings{UserID: "user-123", TimeZone: "Europe/Berlin"},
+			expectedTimeZone: "Europe/Berlin",
+		},
+		{
+			name:             "no settings returns defaults",
+			mockReturn:       nil,
+			expectedTimeZone: models.DefaultTimeZone,
+		},
+		{
+			name:             "empty time zone falls back to default",
+			mockReturn:       &models.UserSettings{UserID: "user-123"},
+			expectedTimeZone: models.DefaultTimeZone,
+		},
+		{
+			name:        "repository error",
+			mockError:   errors.New("database error"),
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mocks.MockSettingsRepository{
+				GetByUserIDFunc: func(ctx context.Context, userID string) (*models.UserSettings, error) {
+					return tt.mockReturn, tt.mockError
+				},
+			}
+
+			service := NewSettingsService(mockRepo)
+			settings, err := service.GetSettings(context.Background(), "user-123")
+
+			if tt.expectError {
+				if err == nil {
+					t.Error("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if settings.TimeZone != tt.expectedTimeZone {
+				t.Errorf("expected time zone '%s', got '%s'", tt.expectedTimeZone, settings.TimeZone)
+			}
+		})
+	}
+}
+
+func TestSettingsService_UpdateSettings(t *testing.T) {
+	tests := []struct {
+		name          string
+		req           models.UpdateSettingsRequest
+		upsertError   error
+		expectedError error
+		expectUpsert  bool
+		expectedTZ    string
+	}{
+		{
+			name:         "valid time zone",
+			req:          models.UpdateSettingsRequest{TimeZone: strPtr("America/New_York")},
+			expectUpsert: true,
+			expectedTZ:   "America/New_York",
+		},
+		{
+			name:          "invalid time zone",
+			req:           models.UpdateSettingsRequest{TimeZone: strPtr("Mars/Olympus_Mons")},
+			expectedError: ErrInvalidTimeZone,
+		},
+		{
+			name:          "empty time zone",
+			req:           models.UpdateSettingsRequest{TimeZone: strPtr("")},
+			expectedError: ErrInvalidTimeZone,
+		},
+		{
+			name:         "no changes keeps existing",
+			req:          models.UpdateSettingsRequest{},
+			expectUpsert: true,
+			expectedTZ:   models.DefaultTimeZone,
+		},
+		{
+			name:          "repository error",
+			req:           models.UpdateSettingsRequest{TimeZone: strPtr("UTC")},
+			upsertError:   errors.New("database error"),
+			expectedError: errors.New("database error"),
+			expectUpsert:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			upsertCalled := false
+			mockRepo := &mocks.MockSettingsRepository{
+				UpsertFunc: func(ctx context.Context, settings *models.UserSettings) error {
+					upsertCalled = true
+					return tt.upsertError
+				},
+			}
+
+			service := NewSettingsService(mockRepo)
+			settings, err := service.UpdateSettings(context.Background(), "user-123", tt.req)
+
+			if tt.expectedError != nil {
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+				if errors.Is(tt.expectedError, ErrInvalidTimeZone) && !errors.Is(err, ErrInvalidTimeZone) {
+					t.Errorf("expected ErrInvalidTimeZone, got %v", err)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if settings.TimeZone != tt.expectedTZ {
+					t.Errorf("expected time zone '%s', got '%s'", tt.expectedTZ, settings.TimeZone)
+				}
+			}
+
+			if upsertCalled != tt.expectUpsert {
+				t.Errorf("expected upsert called %v, got %v", tt.expectUpsert, upsertCalled)
+			}
+		})
+	}
+}
+
+func TestSettingsService_UpdateSettings_PublicWishlist(t *testing.T) {
+	stored := &models.UserSettings{UserID: "user-123", TimeZone: "UTC", PublicWishlist: true}
+	mockRepo := &mocks.MockSettingsRepository{
+		GetByUserIDFunc: func(ctx context.Context, userID string) (*models.UserSettings, error) {
+			copied := *stored
+			return &copied, nil
+		},
+		UpsertFunc: func(ctx context.Context, settings *models.UserSettings) error {
+			stored = settings
+			return nil
+		},
+	}
+	service := NewSettingsService(mockRepo)
+
+	if _, err := service.UpdateSettings(context.Background(), "user-123", models.UpdateSettingsRequest{TimeZone: strPtr("Europe/Berlin")}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !stored.PublicWishlist {
+		t.Error("expected an unrelated update to keep the wishlist public")
+	}
+
+	private := false
+	if _, err := service.UpdateSettings(context.Background(), "user-123", models.UpdateSettingsRequest{PublicWishlist: &private}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored.PublicWishlist {
+		t.Error("expected the wishlist to be made private")
+	}
+}
+
+func TestSettingsService_UpdateSettings_MutedMilestones(t *testing.T) {
+	stored := &models.UserSettings{UserID: "user-123", TimeZone: "UTC"}
+	mockRepo := &mocks.MockSettingsRepository{
+		GetByUserIDFunc: func(ctx context.Context, userID string) (*models.UserSettings, error) {
+			copied := *stored
+			return &copied, nil
+		},
+		UpsertFunc: func(ctx context.Context, settings *models.UserSettings) error {
+			stored = settings
+			return nil
+		},
+	}
+	service := NewSettingsService(mockRepo)
+
+	muted := []string{models.MilestoneLastFoundryRun, models.MilestoneLastFoundryRun}
+	if _, err := service.UpdateSettings(context.Background(), "user-123", models.UpdateSettingsRequest{MutedMilestones: &muted}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(stored.MutedMilestones) != 1 || !stored.MilestoneMuted(models.MilestoneLastFoundryRun) || stored.MilestoneMuted(models.MilestoneMaterialsHalf) {
+		t.Errorf("expected only lastFoundryRun muted, got %v", stored.MutedMilestones)
+	}
+
+	unknown := []string{"everything"}
+	if _, err := service.UpdateSettings(context.Background(), "user-123", models.UpdateSettingsRequest{MutedMilestones: &unknown}); !errors.Is(err, ErrInvalidMilestones) {
+		t.Errorf("expected ErrInvalidMilestones, got %v", err)
+	}
+}
+
+func TestSettingsService_UpdateSettings_SkipStarterWishlist(t *testing.T) {
+	stored := &models.UserSettings{UserID: "user-123", TimeZone: "UTC"}
+	mockRepo := &mocks.MockSettingsRepository{
+		GetByUserIDFunc: func(ctx context.Context, userID string) (*models.UserSettings, error) {
+			copied := *stored
+			return &copied, nil
+		},
+		UpsertFunc: func(ctx context.Context, settings *models.UserSettings) error {
+			stored = settings
+			return nil
+		},
+	}
+	service := NewSettingsService(mockRepo)
+
+	skip := true
+	if _, err := service.UpdateSettings(context.Background(), "user-123", models.UpdateSettingsRequest{SkipStarterWishlist: &skip}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !stored.SkipStarterWishlist {
+		t.Error("expected the starter wishlist opt-out to be saved")
+	}
+
+	if _, err := service.UpdateSettings(context.Background(), "user-123", models.UpdateSettingsRequest{TimeZone: strPtr("Europe/Berlin")}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !stored.SkipStarterWishlist {
+		t.Error("expected an unrelated update to keep the opt-out")
+	}
+}
+
+func TestSettingsService_UpdateSettings_DigestTime(t *testing.T) {
+	stored := &models.UserSettings{UserID: "user-123", TimeZone: "UTC"}
+	mockRepo := &mocks.MockSettingsRepository{
+		GetByUserIDFunc: func(ctx context.Context, userID string) (*models.UserSettings, error) {
+			copied := *stored
+			return &copied, nil
+		},
+		UpsertFunc: func(ctx context.Context, settings *models.UserSettings) error {
+			stored = settings
+			return nil
+		},
+	}
+	service := NewSettingsService(mockRepo)
+
+	if _, err := service.UpdateSettings(context.Background(), "user-123", models.UpdateSettingsRequest{DigestTime: strPtr("08:15")}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hour, minute, ok := stored.DigestClock(); !ok || hour != 8 || minute != 15 {
+		t.Errorf("expected a digest at 08:15, got %q", stored.DigestTime)
+	}
+
+	for _, invalid := range []string{"8am", "24:00", "08:15:00"} {
+		if _, err := service.UpdateSettings(context.Background(), "user-123", models.UpdateSettingsRequest{DigestTime: strPtr(invalid)}); !errors.Is(err, ErrInvalidDigestTime) {
+			t.Errorf("%q: expected ErrInvalidDigestTime, got %v", invalid, err)
+		}
+	}
+
+	if _, err := service.UpdateSettings(context.Background(), "user-123", models.UpdateSettingsRequest{DigestTime: strPtr("")}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, _, ok := stored.DigestClock(); ok {
+		t.Errorf("expected the digest to be turned off, got %q", stored.DigestTime)
+	}
+}
+
+func TestSettingsService_UpdateSettings_MaterialsRounding(t *testing.T) {
+	stored := &models.UserSettings{UserID: "user-123", TimeZone: "UTC"}
+	mockRepo := &mocks.MockSettingsRepository{
+		GetByUserIDFunc: func(ctx context.Context, userID string) (*models.UserSettings, error) {
+			copied := *stored
+			return &copied, nil
+		},
+		UpsertFunc: func(ctx context.Context, settings *models.UserSettings) error {
+			stored = settings
+			return nil
+		},
+	}
+	service := NewSettingsService(mockRepo)
+
+	settings, err := service.GetSettings(context.Background(), "user-123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if settings.MaterialsRounding != models.MaterialsRoundingStrict {
+		t.Errorf("expected strict rounding by default, got %q", settings.MaterialsRounding)
+	}
+
+	if _, err := service.UpdateSettings(context.Background(), "user-123", models.UpdateSettingsRequest{MaterialsRounding: strPtr(models.MaterialsRoundingPooled)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !stored.PoolsCraftSurplus() {
+		t.Errorf("expected pooled rounding to be saved, got %q", stored.MaterialsRounding)
+	}
+
+	if _, err := service.UpdateSettings(context.Background(), "user-123", models.UpdateSettingsRequest{MaterialsRounding: strPtr("nearest")}); !errors.Is(err, ErrInvalidMaterialsRounding) {
+		t.Errorf("expected ErrInvalidMaterialsRounding, got %v", err)
+	}
+	if !stored.PoolsCraftSurplus() {
+		t.Errorf("expected a rejected update to keep pooled rounding, got %q", stored.MaterialsRounding)
+	}
+}
@@ -0,0 +1,229 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/graytonio/warframe-wishlist/internal/ids"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// maxShareLinkLabelLength bounds the label owners use to tell links
+	// apart.
+	maxShareLinkLabelLength = 100
+	// shareTokenBits is the randomness of a share token.
+	shareTokenBits = 128
+)
+
+var (
+	ErrShareLinkNotFound     = errors.New("share link not found")
+	ErrTooManyShareLinks     = fmt.Errorf("at most %d share links per user", models.MaxShareLinksPerUser)
+	ErrInvalidShareLinkLabel = fmt.Errorf("label must be at most %d characters", maxShareLinkLabelLength)
+	ErrSharedMaterialsHidden = errors.New("materials are hidden by this share link")
+)
+
+// ShareLinkService manages links that show a user's wishlist to anyone
+// holding them, and serves the view behind each link with the link's
+// permissions applied.
+type ShareLinkService struct {
+	shareLinkRepo    repository.ShareLinkRepositoryInterface
+	wishlistRepo     repository.WishlistRepositoryInterface
+	materialResolver MaterialResolverInterface
+	ids              ids.Generator
+	now              func() time.Time
+}
+
+func NewShareLinkService(shareLinkRepo repository.ShareLinkRepositoryInterface, wishlistRepo repository.WishlistRepositoryInterface, materialResolver MaterialResolverInterface) *ShareLinkService {
+	return &ShareLinkService{
+		shareLinkRepo:    shareLinkRepo,
+		wishlistRepo:     wishlistRepo,
+		materialResolver: materialResolver,
+		ids:              ids.Default,
+		now:              time.Now,
+	}
+}
+
+// SetIDGenerator sets the generator of share tokens.
+func (s *ShareLinkService) SetIDGenerator(gen ids.Generator) {
+	s.ids = gen
+}
+
+func (s *ShareLinkService) CreateLink(ctx context.Context, userID string, req models.CreateShareLinkRequest) (*models.ShareLink, error) {
+	logger.Debug(ctx, "service: ShareLinkService.CreateLink called", "userID", userID)
+
+	label := strings.TrimSpace(req.Label)
+	if utf8.RuneCountInString(label) > maxShareLinkLabelLength {
+		logger.Warn(ctx, "service: ShareLinkService.CreateLink - label too long")
+		return nil, ErrInvalidShareLinkLabel
+	}
+
+	existing, err := s.shareLinkRepo.ListByUser(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "service: ShareLinkService.CreateLink - error fetching links", "error", err)
+		return nil, err
+	}
+	if len(existing) >= models.MaxShareLinksPerUser {
+		logger.Warn(ctx, "service: ShareLinkService.CreateLink - too many links", "count", len(existing))
+		return nil, ErrTooManyShareLinks
+	}
+
+	token, err := s.ids.NewToken(shareTokenBits)
+	if err != nil {
+		logger.Error(ctx, "service: ShareLinkService.CreateLink - error generating token", "error", err)
+		return nil, err
+	}
+
+	permissions := req.Permissions
+	if permissions.HideQuantities {
+		permissions.HideMaterials = true
+	}
+	link := &models.ShareLink{
+		UserID:      userID,
+		Token:       token,
+		Label:       label,
+		Permissions: permissions,
+		CreatedAt:   s.now(),
+	}
+	if err := s.shareLinkRepo.Create(ctx, link); err != nil {
+		logger.Error(ctx, "service: ShareLinkService.CreateLink - error storing link", "error", err)
+		return nil, err
+	}
+
+	logger.Info(ctx, "service: ShareLinkService.CreateLink - link created", "id", link.ID.Hex(), "permissions", link.Permissions)
+	return link, nil
+}
+
+func (s *ShareLinkService) ListLinks(ctx context.Context, userID string) ([]models.ShareLink, error) {
+	logger.Debug(ctx, "service: ShareLinkService.ListLinks called", "userID", userID)
+
+	links, err := s.shareLinkRepo.ListByUser(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "service: ShareLinkService.ListLinks - error fetching links", "error", err)
+		return nil, err
+	}
+	return links, nil
+}
+
+// DeleteLink revokes one of the user's links; its URL stops working at once.
+func (s *ShareLinkService) DeleteLink(ctx context.Context, userID, linkID string) error {
+	logger.Debug(ctx, "service: ShareLinkService.DeleteLink called", "userID", userID, "linkID", linkID)
+
+	id, err := primitive.ObjectIDFromHex(linkID)
+	if err != nil {
+		logger.Warn(ctx, "service: ShareLinkService.DeleteLink - invalid link ID", "linkID", linkID)
+		return ErrShareLinkNotFound
+	}
+	deleted, err := s.shareLinkRepo.Delete(ctx, userID, id)
+	if err != nil {
+		logger.Error(ctx, "service: ShareLinkService.DeleteLink - error deleting link", "error", err)
+		return err
+	}
+	if !deleted {
+		logger.Warn(ctx, "service: ShareLinkService.DeleteLink - link not found", "linkID", linkID)
+		return ErrShareLinkNotFound
+	}
+
+	logger.Info(ctx, "service: ShareLinkService.DeleteLink - link deleted", "linkID", linkID)
+	return nil
+}
+
+// RevokeLink deletes the link with token, whichever user it belongs to.
+func (s *ShareLinkService) RevokeLink(ctx context.Context, token string) (*models.ShareLink, error) {
+	logger.Debug(ctx, "service: ShareLinkService.RevokeLink called")
+
+	link, err := s.link(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	deleted, err := s.shareLinkRepo.Delete(ctx, link.UserID, link.ID)
+	if err != nil {
+		logger.Error(ctx, "service: ShareLinkService.RevokeLink - error deleting link", "error", err)
+		return nil, err
+	}
+	if !deleted {
+		// Its owner deleted it in the meantime.
+		logger.Warn(ctx, "service: ShareLinkService.RevokeLink - link already deleted", "linkID", link.ID.Hex())
+		return nil, ErrShareLinkNotFound
+	}
+
+	logger.Info(ctx, "service: ShareLinkService.RevokeLink - link revoked", "linkID", link.ID.Hex(), "userID", link.UserID)
+	return link, nil
+}
+
+// GetView returns the wishlist behind token with the link's permissions
+// applied.
+func (s *ShareLinkService) GetView(ctx context.Context, token string) (*models.ShareLinkView, error) {
+	logger.Debug(ctx, "service: ShareLinkService.GetView called")
+
+	link, err := s.link(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	wishlist, err := s.wishlistRepo.GetByUserID(ctx, link.UserID)
+	if err != nil {
+		logger.Error(ctx, "service: ShareLinkService.GetView - error fetching wishlist", "error", err)
+		return nil, err
+	}
+
+	view := &models.ShareLinkView{Permissions: link.Permissions, Items: []models.ShareLinkViewItem{}}
+	if wishlist == nil {
+		return view, nil
+	}
+	view.UpdatedAt = wishlist.UpdatedAt
+	for _, item := range wishlist.Items {
+		viewItem := models.ShareLinkViewItem{UniqueName: item.UniqueName, AddedAt: item.AddedAt}
+		if !link.Permissions.HideQuantities {
+			quantity := item.Quantity
+			viewItem.Quantity = &quantity
+		}
+		if !link.Permissions.HideLinks {
+			viewItem.Links = item.Links
+			if viewItem.Links == nil {
+				viewItem.Links = []models.SourceLink{}
+			}
+		}
+		view.Items = append(view.Items, viewItem)
+	}
+
+	logger.Debug(ctx, "service: ShareLinkService.GetView - completed", "linkID", link.ID.Hex(), "itemCount", len(view.Items))
+	return view, nil
+}
+
+// GetMaterials returns the aggregated materials of the wishlist behind token,
+// unless the link hides them.
+func (s *ShareLinkService) GetMaterials(ctx context.Context, token string) (*models.MaterialsResponse, error) {
+	logger.Debug(ctx, "service: ShareLinkService.GetMaterials called")
+
+	link, err := s.link(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if link.Permissions.HideMaterials {
+		logger.Warn(ctx, "service: ShareLinkService.GetMaterials - materials hidden", "linkID", link.ID.Hex())
+		return nil, ErrSharedMaterialsHidden
+	}
+
+	return s.materialResolver.GetMaterials(ctx, link.UserID)
+}
+
+func (s *ShareLinkService) link(ctx context.Context, token string) (*models.ShareLink, error) {
+	link, err := s.shareLinkRepo.GetByToken(ctx, token)
+	if err != nil {
+		logger.Error(ctx, "service: ShareLinkService - error fetching link", "error", err)
+		return nil, err
+	}
+	if link == nil {
+		logger.Warn(ctx, "service: ShareLinkService - unknown share token")
+		return nil, ErrShareLinkNotFound
+	}
+	return link, nil
+}
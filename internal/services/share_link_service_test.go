@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository/memory"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// newShareLinkFixture returns a service over owner's wishlist, which holds
+// three Forma with a source link and one Orokin Catalyst. The material
+// resolver reports which user it resolved for.
+func newShareLinkFixture(t *testing.T) (*ShareLinkService, *[]string) {
+	t.Helper()
+
+	wishlists := memory.NewWishlistRepository()
+	wishlists.Seed(models.Wishlist{UserID: "owner", Items: []models.WishlistItem{
+		{UniqueName: "/Lotus/Forma", Quantity: 3, Links: []models.SourceLink{{URL: "https://example.com/build", Host: "example.com"}}},
+		{UniqueName: "/Lotus/Orokin", Quantity: 1},
+	}})
+
+	var resolved []string
+	resolver := &mocks.MockMaterialResolver{
+		GetMaterialsFunc: func(ctx context.Context, userID string) (*models.MaterialsResponse, error) {
+			resolved = append(resolved, userID)
+			return &models.MaterialsResponse{Materials: []models.MaterialRequirement{}, TotalCredits: 1000}, nil
+		},
+	}
+	return NewShareLinkService(memory.NewShareLinkRepository(), wishlists, resolver), &resolved
+}
+
+func TestShareLinkService_CreateLink(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newShareLinkFixture(t)
+
+	link, err := service.CreateLink(ctx, "owner", models.CreateShareLinkRequest{Label: "  clan  ", Permissions: models.ShareLinkPermissions{HideLinks: true}})
+	if err != nil {
+		t.Fatalf("CreateLink: %v", err)
+	}
+	if link.ID.IsZero() || len(link.Token) != 36 || link.Label != "clan" || link.UserID != "owner" {
+		t.Errorf("unexpected link %+v", link)
+	}
+	if link.Permissions != (models.ShareLinkPermissions{HideLinks: true}) {
+		t.Errorf("unexpected permissions %+v", link.Permissions)
+	}
+
+	other, err := service.CreateLink(ctx, "owner", models.CreateShareLinkRequest{Permissions: models.ShareLinkPermissions{HideQuantities: true}})
+	if err != nil {
+		t.Fatalf("CreateLink: %v", err)
+	}
+	if other.Token == link.Token {
+		t.Error("expected every link to get its own token")
+	}
+	if !other.Permissions.HideMaterials {
+		t.Error("expected hiding quantities to hide materials too")
+	}
+
+	links, err := service.ListLinks(ctx, "owner")
+	if err != nil || len(links) != 2 {
+		t.Errorf("expected 2 links, got %d (err %v)", len(links), err)
+	}
+}
+
+func TestShareLinkService_CreateLinkErrors(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newShareLinkFixture(t)
+
+	if _, err := service.CreateLink(ctx, "owner", models.CreateShareLinkRequest{Label: strings.Repeat("a", maxShareLinkLabelLength+1)}); !errors.Is(err, ErrInvalidShareLinkLabel) {
+		t.Errorf("expected ErrInvalidShareLinkLabel, got %v", err)
+	}
+
+	for i := 0; i < models.MaxShareLinksPerUser; i++ {
+		if _, err := service.CreateLink(ctx, "owner", models.CreateShareLinkRequest{}); err != nil {
+			t.Fatalf("CreateLink %d: %v", i, err)
+		}
+	}
+	if _, err := service.CreateLink(ctx, "owner", models.CreateShareLinkRequest{}); !errors.Is(err, ErrTooManyShareLinks) {
+		t.Errorf("expected ErrTooManyShareLinks, got %v", err)
+	}
+	if _, err := service.CreateLink(ctx, "other", models.CreateShareLinkRequest{}); err != nil {
+		t.Errorf("expected the limit to be per user, got %v", err)
+	}
+}
+
+func TestShareLinkService_GetView(t *testing.T) {
+	tests := []struct {
+		name          string
+		permissions   models.ShareLinkPermissions
+		wantQuantity  bool
+		wantLinks     bool
+		wantMaterials bool
+	}{
+		{name: "everything visible", wantQuantity: true, wantLinks: true, wantMaterials: true},
+		{name: "quantities hidden", permissions: models.ShareLinkPermissions{HideQuantities: true}, wantLinks: true},
+		{name: "links hidden", permissions: models.ShareLinkPermissions{HideLinks: true}, wantQuantity: true, wantMaterials: true},
+		{name: "materials hidden", permissions: models.ShareLinkPermissions{HideMaterials: true}, wantQuantity: true, wantLinks: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			service, resolved := newShareLinkFixture(t)
+			link, err := service.CreateLink(ctx, "owner", models.CreateShareLinkRequest{Permissions: tt.permissions})
+			if err != nil {
+				t.Fatalf("CreateLink: %v", err)
+			}
+
+			view, err := service.GetView(ctx, link.Token)
+			if err != nil {
+				t.Fatalf("GetView: %v", err)
+			}
+			if len(view.Items) != 2 || view.Items[0].UniqueName != "/Lotus/Forma" {
+				t.Fatalf("unexpected items %+v", view.Items)
+			}
+			forma, orokin := view.Items[0], view.Items[1]
+			if tt.wantQuantity {
+				if forma.Quantity == nil || *forma.Quantity != 3 {
+					t.Errorf("expected quantity 3, got %v", forma.Quantity)
+				}
+			} else if forma.Quantity != nil {
+				t.Errorf("expected quantity to be hidden, got %d", *forma.Quantity)
+			}
+			if tt.wantLinks {
+				if len(forma.Links) != 1 || orokin.Links == nil {
+					t.Errorf("expected links, got %+v and %+v", forma.Links, orokin.Links)
+				}
+			} else if forma.Links != nil || orokin.Links != nil {
+				t.Errorf("expected links to be hidden, got %+v", forma.Links)
+			}
+
+			materials, err := service.GetMaterials(ctx, link.Token)
+			if tt.wantMaterials {
+				if err != nil || materials.TotalCredits != 1000 || len(*resolved) != 1 || (*resolved)[0] != "owner" {
+					t.Errorf("expected the owner's materials, got %+v (err %v, resolved %v)", materials, err, *resolved)
+				}
+			} else if !errors.Is(err, ErrSharedMaterialsHidden) || len(*resolved) != 0 {
+				t.Errorf("expected ErrSharedMaterialsHidden without resolving, got %v (resolved %v)", err, *resolved)
+			}
+		})
+	}
+}
+
+func TestShareLinkService_UnknownToken(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newShareLinkFixture(t)
+
+	if _, err := service.GetView(ctx, "missing"); !errors.Is(err, ErrShareLinkNotFound) {
+		t.Errorf("expected ErrShareLinkNotFound from GetView, got %v", err)
+	}
+	if _, err := service.GetMaterials(ctx, "missing"); !errors.Is(err, ErrShareLinkNotFound) {
+		t.Errorf("expected ErrShareLinkNotFound from GetMaterials, got %v", err)
+	}
+}
+
+func TestShareLinkService_EmptyWishlist(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newShareLinkFixture(t)
+
+	link, err := service.CreateLink(ctx, "new-user", models.CreateShareLinkRequest{})
+	if err != nil {
+		t.Fatalf("CreateLink: %v", err)
+	}
+	view, err := service.GetView(ctx, link.Token)
+	if err != nil || view.Items == nil || len(view.Items) != 0 {
+		t.Errorf("expected an empty view, got %+v (err %v)", view, err)
+	}
+}
+
+func TestShareLinkService_DeleteLink(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newShareLinkFixture(t)
+
+	link, err := service.CreateLink(ctx, "owner", models.CreateShareLinkRequest{})
+	if err != nil {
+		t.Fatalf("CreateLink: %v", err)
+	}
+
+	if err := service.DeleteLink(ctx, "owner", "not-an-id"); !errors.Is(err, ErrShareLinkNotFound) {
+		t.Errorf("expected ErrShareLinkNotFound for an invalid ID, got %v", err)
+	}
+	if err := service.DeleteLink(ctx, "owner", primitive.NewObjectID().Hex()); !errors.Is(err, ErrShareLinkNotFound) {
+		t.Errorf("expected ErrShareLinkNotFound for an unknown ID, got %v", err)
+	}
+	if err := service.DeleteLink(ctx, "other", link.ID.Hex()); !errors.Is(err, ErrShareLinkNotFound) {
+		t.Errorf("expected ErrShareLinkNotFound for another user's link, got %v", err)
+	}
+	if err := service.DeleteLink(ctx, "owner", link.ID.Hex()); err != nil {
+		t.Fatalf("DeleteLink: %v", err)
+	}
+	if _, err := service.GetView(ctx, link.Token); !errors.Is(err, ErrShareLinkNotFound) {
+		t.Errorf("expected the deleted link to stop working, got %v", err)
+	}
+}
+
+func TestShareLinkService_RevokeLink(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newShareLinkFixture(t)
+
+	link, err := service.CreateLink(ctx, "owner", models.CreateShareLinkRequest{Label: "Discord"})
+	if err != nil {
+		t.Fatalf("CreateLink: %v", err)
+	}
+
+	if _, err := service.RevokeLink(ctx, "unknown-token"); !errors.Is(err, ErrShareLinkNotFound) {
+		t.Errorf("expected ErrShareLinkNotFound for an unknown token, got %v", err)
+	}
+	revoked, err := service.RevokeLink(ctx, link.Token)
+	if err != nil {
+		t.Fatalf("RevokeLink: %v", err)
+	}
+	if revoked.ID != link.ID || revoked.UserID != "owner" {
+		t.Errorf("expected the owner's link, got %+v", revoked)
+	}
+	if _, err := service.GetView(ctx, link.Token); !errors.Is(err, ErrShareLinkNotFound) {
+		t.Errorf("expected the revoked link to stop working, got %v", err)
+	}
+	if _, err := service.RevokeLink(ctx, link.Token); !errors.Is(err, ErrShareLinkNotFound) {
+		t.Errorf("expected ErrShareLinkNotFound revoking twice, got %v", err)
+	}
+}
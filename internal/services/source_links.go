@@ -0,0 +1,115 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+const (
+	MaxSourceLinksPerItem = 10
+	maxSourceLinkURLLen   = 2048
+	maxSourceLinkTitleLen = 200
+)
+
+var (
+	ErrInvalidLink  = errors.New("invalid link")
+	ErrTooManyLinks = fmt.Errorf("an item can have at most %d links", MaxSourceLinksPerItem)
+)
+
+// normalizeSourceLink validates a user-supplied reference URL and returns it
+// in canonical form along with its host. Only absolute http(s) URLs with an
+// ASCII host and no embedded credentials are accepted, so a stored link can
+// never be a javascript: or data: URL or disguise its destination.
+func normalizeSourceLink(raw string) (string, string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", "", fmt.Errorf("%w: url is required", ErrInvalidLink)
+	}
+	if len(raw) > maxSourceLinkURLLen {
+		return "", "", fmt.Errorf("%w: url must be at most %d characters", ErrInvalidLink, maxSourceLinkURLLen)
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: url could not be parsed", ErrInvalidLink)
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", "", fmt.Errorf("%w: url must use http or https", ErrInvalidLink)
+	}
+	if u.User != nil {
+		return "", "", fmt.Errorf("%w: url must not contain credentials", ErrInvalidLink)
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if host == "" {
+		return "", "", fmt.Errorf("%w: url must include a host", ErrInvalidLink)
+	}
+	for _, r := range host {
+		if r > unicode.MaxASCII {
+			return "", "", fmt.Errorf("%w: internationalized hosts must use their punycode (xn--) form", ErrInvalidLink)
+		}
+	}
+
+	port := u.Port()
+	u.Host = host
+	if strings.Contains(host, ":") {
+		// IPv6 literal; Hostname strips the brackets.
+		u.Host = "[" + host + "]"
+	}
+	if port != "" {
+		u.Host += ":" + port
+	}
+
+	return u.String(), host, nil
+}
+
+// buildSourceLinks normalizes reqs into the links stored on an item,
+// dropping repeated URLs. The error names the first invalid link by index.
+func buildSourceLinks(reqs []models.SourceLinkRequest) ([]models.SourceLink, error) {
+	links := make([]models.SourceLink, 0, len(reqs))
+	seen := make(map[string]bool, len(reqs))
+	for i, req := range reqs {
+		normalized, host, err := normalizeSourceLink(req.URL)
+		if err != nil {
+			return nil, fmt.Errorf("links[%d]: %w", i, err)
+		}
+		if seen[normalized] {
+			continue
+		}
+		seen[normalized] = true
+		links = append(links, models.SourceLink{
+			URL:   normalized,
+			Title: sanitizeLinkTitle(req.Title),
+			Host:  host,
+		})
+	}
+	if len(links) > MaxSourceLinksPerItem {
+		return nil, ErrTooManyLinks
+	}
+	return links, nil
+}
+
+// sanitizeLinkTitle strips control characters and surrounding whitespace and
+// caps the title length.
+func sanitizeLinkTitle(title string) string {
+	title = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, title)
+	title = strings.TrimSpace(title)
+
+	runes := []rune(title)
+	if len(runes) > maxSourceLinkTitleLen {
+		title = strings.TrimSpace(string(runes[:maxSourceLinkTitleLen]))
+	}
+	return title
+}
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"context"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// DefaultUsageWarningPercent is the share of a cap at which usage is flagged
+// when none is configured.
+const DefaultUsageWarningPercent = 80
+
+// UsageRepositories are the stores UsageService counts a user's data in.
+type UsageRepositories struct {
+	Wishlists         repository.WishlistRepositoryInterface
+	OwnedBlueprints   repository.OwnedBlueprintsRepositoryInterface
+	CustomItems       repository.CustomItemRepositoryInterface
+	Foundry           repository.FoundryRepositoryInterface
+	ShareLinks        repository.ShareLinkRepositoryInterface
+	Notifications     repository.NotificationRepositoryInterface
+	Workspaces        repository.WorkspaceRepositoryInterface
+	WorkspaceActivity repository.WorkspaceContributionRepositoryInterface
+}
+
+// UsageLimits are the configured per-user caps; 0 leaves a resource
+// uncapped. WarningPercent is the share of a cap at which usage is flagged.
+type UsageLimits struct {
+	MaxWishlistItems   int
+	MaxOwnedBlueprints int
+	WarningPercent     int
+}
+
+// UsageService reports how much a user stores against the per-user caps, so
+// clients can warn before a write is rejected.
+type UsageService struct {
+	repos     UsageRepositories
+	limits    map[string]int
+	warnAt    int
+	resources []string
+	counters  map[string]func(ctx context.Context, userID string) (int, error)
+}
+
+func NewUsageService(repos UsageRepositories, limits UsageLimits) *UsageService {
+	warnAt := limits.WarningPercent
+	if warnAt <= 0 || warnAt > 100 {
+		warnAt = DefaultUsageWarningPercent
+	}
+	s := &UsageService{
+		repos: repos,
+		limits: map[string]int{
+			models.UsageWishlistItems:        limits.MaxWishlistItems,
+			models.UsageWishlistBytes:        models.MaxDocumentBytes,
+			models.UsageOwnedBlueprints:      limits.MaxOwnedBlueprints,
+			models.UsageOwnedPartsBytes:      models.MaxDocumentBytes,
+			models.UsageCustomItems:          models.MaxCustomItemsPerUser,
+			models.UsageFoundryBuilds:        models.MaxFoundryBuilds,
+			models.UsageShareLinks:           models.MaxShareLinksPerUser,
+			models.UsageNotificationChannels: models.MaxNotificationChannels,
+			models.UsageWorkspaces:           models.MaxWorkspacesPerUser,
+		},
+		warnAt: warnAt,
+		resources: []string{
+			models.UsageWishlistItems,
+			models.UsageWishlistBytes,
+			models.UsageOwnedBlueprints,
+			models.UsageOwnedPartsBytes,
+			models.UsageCustomItems,
+			models.UsageFoundryBuilds,
+			models.UsageShareLinks,
+			models.UsageNotificationChannels,
+			models.UsageWorkspaces,
+			models.UsageActivityEntries,
+		},
+	}
+	s.counters = map[string]func(ctx context.Context, userID string) (int, error){
+		models.UsageWishlistItems:   s.countWishlistItems,
+		models.UsageWishlistBytes:   s.wishlistBytes,
+		models.UsageOwnedBlueprints: s.countOwnedBlueprints,
+		models.UsageOwnedPartsBytes: s.ownedPartsBytes,
+		models.UsageCustomItems: func(ctx context.Context, userID string) (int, error) {
+			items, err := repos.CustomItems.ListByUser(ctx, userID)
+			return len(items), err
+		},
+		models.UsageFoundryBuilds: func(ctx context.Context, userID string) (int, error) {
+			builds, err := repos.Foundry.ListByUser(ctx, userID)
+			return len(builds), err
+		},
+		models.UsageShareLinks: func(ctx context.Context, userID string) (int, error) {
+			links, err := repos.ShareLinks.ListByUser(ctx, userID)
+			return len(links), err
+		},
+		models.UsageNotificationChannels: func(ctx context.Context, userID string) (int, error) {
+			channels, err := repos.Notifications.ListChannels(ctx, userID)
+			return len(channels), err
+		},
+		models.UsageWorkspaces: func(ctx context.Context, userID string) (int, error) {
+			workspaces, err := repos.Workspaces.ListByMember(ctx, userID)
+			return len(workspaces), err
+		},
+		models.UsageActivityEntries: func(ctx context.Context, userID string) (int, error) {
+			return repos.WorkspaceActivity.CountByUser(ctx, userID)
+		},
+	}
+	return s
+}
+
+// GetUsage reports every resource the user stores.
+func (s *UsageService) GetUsage(ctx context.Context, userID string) (*models.UserUsage, error) {
+	logger.Debug(ctx, "service: UsageService.GetUsage called", "userID", userID)
+
+	usage := &models.UserUsage{UserID: userID, Resources: make([]models.ResourceUsage, 0, len(s.resources))}
+	for _, resource := range s.resources {
+		resourceUsage, err := s.measure(ctx, userID, resource)
+		if err != nil {
+			logger.Error(ctx, "service: UsageService.GetUsage - error measuring usage", "resource", resource, "error", err)
+			return nil, err
+		}
+		usage.Resources = append(usage.Resources, resourceUsage)
+	}
+	return usage, nil
+}
+
+// Warnings returns the given resources the user is close to the cap of.
+// Uncapped and unknown resources are never returned.
+func (s *UsageService) Warnings(ctx context.Context, userID string, resources ...string) ([]models.ResourceUsage, error) {
+	logger.Debug(ctx, "service: UsageService.Warnings called", "userID", userID, "resources", resources)
+
+	var warnings []models.ResourceUsage
+	for _, resource := range resources {
+		if s.limits[resource] <= 0 {
+			continue
+		}
+		resourceUsage, err := s.measure(ctx, userID, resource)
+		if err != nil {
+			logger.Error(ctx, "service: UsageService.Warnings - error measuring usage", "resource", resource, "error", err)
+			return nil, err
+		}
+		if resourceUsage.Warning {
+			warnings = append(warnings, resourceUsage)
+		}
+	}
+	return warnings, nil
+}
+
+func (s *UsageService) measure(ctx context.Context, userID, resource string) (models.ResourceUsage, error) {
+	used, err := s.counters[resource](ctx, userID)
+	if err != nil {
+		return models.ResourceUsage{}, err
+	}
+	limit := s.limits[resource]
+	return models.ResourceUsage{
+		Resource: resource,
+		Used:     used,
+		Limit:    limit,
+		Warning:  limit > 0 && used*100 >= limit*s.warnAt,
+	}, nil
+}
+
+func (s *UsageService) countWishlistItems(ctx context.Context, userID string) (int, error) {
+	wishlist, err := s.repos.Wishlists.GetByUserID(ctx, userID)
+	if err != nil || wishlist == nil {
+		return 0, err
+	}
+	return len(wishlist.Items), nil
+}
+
+func (s *UsageService) countOwnedBlueprints(ctx context.Context, userID string) (int, error) {
+	owned, err := s.repos.OwnedBlueprints.GetByUserID(ctx, userID)
+	if err != nil || owned == nil {
+		return 0, err
+	}
+	return len(owned.Blueprints), nil
+}
+
+// wishlistBytes approximates the stored size of the wishlist document by its
+// BSON encoding.
+func (s *UsageService) wishlistBytes(ctx context.Context, userID string) (int, error) {
+	wishlist, err := s.repos.Wishlists.GetByUserID(ctx, userID)
+	if err != nil || wishlist == nil {
+		return 0, err
+	}
+	return documentSize(wishlist)
+}
+
+func (s *UsageService) ownedPartsBytes(ctx context.Context, userID string) (int, error) {
+	owned, err := s.repos.OwnedBlueprints.GetByUserID(ctx, userID)
+	if err != nil || owned == nil {
+		return 0, err
+	}
+	return documentSize(owned)
+}
+
+func documentSize(document any) (int, error) {
+	data, err := bson.Marshal(document)
+	if err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository/memory"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newTestUsageService(wishlists *memory.WishlistRepository, activity *memory.WorkspaceContributionRepository, limits UsageLimits) *UsageService {
+	return NewUsageService(UsageRepositories{
+		Wishlists:         wishlists,
+		OwnedBlueprints:   memory.NewOwnedBlueprintsRepository(),
+		CustomItems:       memory.NewCustomItemRepository(),
+		Foundry:           memory.NewFoundryRepository(),
+		ShareLinks:        memory.NewShareLinkRepository(),
+		Notifications:     memory.NewNotificationRepository(),
+		Workspaces:        memory.NewWorkspaceRepository(),
+		WorkspaceActivity: activity,
+	}, limits)
+}
+
+func TestUsageService_GetUsage(t *testing.T) {
+	ctx := context.Background()
+	wishlists := memory.NewWishlistRepository()
+	activity := memory.NewWorkspaceContributionRepository()
+	service := newTestUsageService(wishlists, activity, UsageLimits{MaxWishlistItems: 10})
+
+	usage, err := service.GetUsage(ctx, "user-123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(usage.Resources) != 10 {
+		t.Fatalf("expected every resource reported, got %+v", usage.Resources)
+	}
+	for _, resource := range usage.Resources {
+		if resource.Used != 0 || resource.Warning {
+			t.Errorf("expected no usage for a new user, got %+v", resource)
+		}
+	}
+
+	items := make([]models.WishlistItem, 8)
+	for i := range items {
+		items[i] = models.WishlistItem{UniqueName: "/Lotus/Item" + string(rune('A'+i)), Quantity: 1, AddedAt: time.Now()}
+	}
+	wishlists.Create(ctx, &models.Wishlist{UserID: "user-123", Items: items})
+	activity.Create(ctx, &models.WorkspaceContributionEvent{WorkspaceID: primitive.NewObjectID(), UserID: "user-123", Kind: models.ContributionKindMaterial})
+
+	usage, _ = service.GetUsage(ctx, "user-123")
+	byResource := make(map[string]models.ResourceUsage)
+	for _, resource := range usage.Resources {
+		byResource[resource.Resource] = resource
+	}
+	if got := byResource[models.UsageWishlistItems]; got.Used != 8 || got.Limit != 10 || !got.Warning {
+		t.Errorf("expected 8 of 10 wishlist items flagged, got %+v", got)
+	}
+	if got := byResource[models.UsageWishlistBytes]; got.Used == 0 || got.Limit != models.MaxDocumentBytes || got.Warning {
+		t.Errorf("expected the wishlist document size measured, got %+v", got)
+	}
+	if got := byResource[models.UsageOwnedBlueprints]; got.Limit != 0 {
+		t.Errorf("expected an unconfigured cap to be 0, got %+v", got)
+	}
+	if got := byResource[models.UsageActivityEntries]; got.Used != 1 || got.Limit != 0 {
+		t.Errorf("expected one uncapped activity entry, got %+v", got)
+	}
+	if got := byResource[models.UsageCustomItems]; got.Limit != models.MaxCustomItemsPerUser {
+		t.Errorf("expected the fixed custom item cap, got %+v", got)
+	}
+}
+
+func TestUsageService_Warnings(t *testing.T) {
+	ctx := context.Background()
+	wishlists := memory.NewWishlistRepository()
+	service := newTestUsageService(wishlists, memory.NewWorkspaceContributionRepository(), UsageLimits{MaxWishlistItems: 4, WarningPercent: 50})
+	wishlists.Create(ctx, &models.Wishlist{UserID: "user-123", Items: []models.WishlistItem{{UniqueName: "/Lotus/A"}}})
+
+	warnings, err := service.Warnings(ctx, "user-123", models.UsageWishlistItems, models.UsageOwnedBlueprints, "unknown")
+	if err != nil || len(warnings) != 0 {
+		t.Fatalf("expected no warnings below half the cap, got %+v (err %v)", warnings, err)
+	}
+
+	wishlists.AddItem(ctx, "user-123", models.WishlistItem{UniqueName: "/Lotus/B"})
+	warnings, _ = service.Warnings(ctx, "user-123", models.UsageWishlistItems, models.UsageOwnedBlueprints)
+	if len(warnings) != 1 || warnings[0].Resource != models.UsageWishlistItems || warnings[0].Used != 2 || warnings[0].Limit != 4 {
+		t.Errorf("expected the wishlist flagged at half the cap, got %+v", warnings)
+	}
+
+	failing := NewUsageService(UsageRepositories{Wishlists: &mocks.MockWishlistRepository{
+		GetByUserIDFunc: func(ctx context.Context, userID string) (*models.Wishlist, error) {
+			return nil, errors.New("database down")
+		},
+	}}, UsageLimits{MaxWishlistItems: 4})
+	if _, err := failing.Warnings(ctx, "user-123", models.UsageWishlistItems); err == nil {
+		t.Error("expected the repository error")
+	}
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+)
+
+const (
+	// DefaultUserTraceDuration is how long a trace lasts when the request
+	// names no duration.
+	DefaultUserTraceDuration = time.Hour
+	// MaxUserTraceDuration bounds a trace so verbose logging is never left on
+	// for a user indefinitely.
+	MaxUserTraceDuration = 24 * time.Hour
+	// MaxUserTraceReasonLength bounds the stored reason.
+	MaxUserTraceReasonLength = 500
+	// userTraceRefreshInterval is how often IsTraced reloads the active
+	// traces, so a trace set on another instance applies within this long.
+	userTraceRefreshInterval = 30 * time.Second
+)
+
+var (
+	ErrInvalidTraceDuration = fmt.Errorf("durationMinutes must be between 1 and %d", int(MaxUserTraceDuration/time.Minute))
+	ErrTraceReasonTooLong   = fmt.Errorf("reason must be at most %d characters", MaxUserTraceReasonLength)
+	ErrUserTraceNotFound    = errors.New("no active trace for user")
+)
+
+// UserTraceService manages the time-limited per-user tracing that support
+// staff enable to debug one user's data issues. IsTraced is called on every
+// authenticated request, so it answers from an in-memory copy of the active
+// traces that is reloaded every userTraceRefreshInterval.
+type UserTraceService struct {
+	traceRepo repository.UserTraceRepositoryInterface
+	now       func() time.Time
+
+	mu       sync.Mutex
+	active   map[string]time.Time
+	loadedAt time.Time
+	loading  bool
+	// changed records an enable or disable made while a reload was in flight,
+	// whose result may predate it and is therefore discarded.
+	changed bool
+}
+
+func NewUserTraceService(traceRepo repository.UserTraceRepositoryInterface) *UserTraceService {
+	return &UserTraceService{
+		traceRepo: traceRepo,
+		now:       time.Now,
+		active:    make(map[string]time.Time),
+	}
+}
+
+func (s *UserTraceService) ListTraces(ctx context.Context) ([]models.UserTrace, error) {
+	logger.Debug(ctx, "service: UserTraceService.ListTraces called")
+
+	traces, err := s.traceRepo.ListActive(ctx, s.now())
+	if err != nil {
+		logger.Error(ctx, "service: UserTraceService.ListTraces - repository error", "error", err)
+		return nil, err
+	}
+	return traces, nil
+}
+
+// GetTrace returns the user's trace, or ErrUserTraceNotFound when none is
+// active.
+func (s *UserTraceService) GetTrace(ctx context.Context, userID string) (*models.UserTrace, error) {
+	logger.Debug(ctx, "service: UserTraceService.GetTrace called", "userID", userID)
+
+	trace, err := s.traceRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "service: UserTraceService.GetTrace - repository error", "error", err)
+		return nil, err
+	}
+	if !trace.Active(s.now()) {
+		return nil, ErrUserTraceNotFound
+	}
+	return trace, nil
+}
+
+// EnableTrace starts tracing the user's requests, replacing any trace that is
+// already set.
+func (s *UserTraceService) EnableTrace(ctx context.Context, userID string, req models.EnableUserTraceRequest) (*models.UserTrace, error) {
+	logger.Debug(ctx, "service: UserTraceService.EnableTrace called", "userID", userID, "durationMinutes", req.DurationMinutes)
+
+	duration := DefaultUserTraceDuration
+	if req.DurationMinutes != 0 {
+		duration = time.Duration(req.DurationMinutes) * time.Minute
+	}
+	if duration <= 0 || duration > MaxUserTraceDuration {
+		logger.Warn(ctx, "service: UserTraceService.EnableTrace - invalid duration", "durationMinutes", req.DurationMinutes)
+		return nil, ErrInvalidTraceDuration
+	}
+	reason := strings.TrimSpace(req.Reason)
+	if len(reason) > MaxUserTraceReasonLength {
+		logger.Warn(ctx, "service: UserTraceService.EnableTrace - reason too long", "length", len(reason))
+		return nil, ErrTraceReasonTooLong
+	}
+
+	now := s.now()
+	trace := &models.UserTrace{
+		UserID:    userID,
+		Until:     now.Add(duration),
+		Reason:    reason,
+		CreatedAt: now,
+	}
+	if err := s.traceRepo.Upsert(ctx, trace); err != nil {
+		logger.Error(ctx, "service: UserTraceService.EnableTrace - error saving trace", "error", err)
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.active[userID] = trace.Until
+	s.changed = true
+	s.mu.Unlock()
+
+	logger.Info(ctx, "service: UserTraceService.EnableTrace - trace enabled", "userID", userID, "until", trace.Until)
+	return trace, nil
+}
+
+func (s *UserTraceService) DisableTrace(ctx context.Context, userID string) error {
+	logger.Debug(ctx, "service: UserTraceService.DisableTrace called", "userID", userID)
+
+	if err := s.traceRepo.Delete(ctx, userID); err != nil {
+		logger.Error(ctx, "service: UserTraceService.DisableTrace - error deleting trace", "error", err)
+		return err
+	}
+
+	s.mu.Lock()
+	delete(s.active, userID)
+	s.changed = true
+	s.mu.Unlock()
+
+	logger.Info(ctx, "service: UserTraceService.DisableTrace - trace disabled", "userID", userID)
+	return nil
+}
+
+// IsTraced reports whether the user's requests are traced. Only the request
+// that finds the copy stale reloads it; concurrent requests use the copy they
+// find, and a failed reload keeps the previous one until the next interval.
+func (s *UserTraceService) IsTraced(ctx context.Context, userID string) bool {
+	now := s.now()
+
+	s.mu.Lock()
+	stale := !s.loading && now.Sub(s.loadedAt) >= userTraceRefreshInterval
+	if stale {
+		s.loading = true
+		s.changed = false
+		s.mu.Unlock()
+		s.reload(context.WithoutCancel(ctx), now)
+		s.mu.Lock()
+	}
+	until, ok := s.active[userID]
+	s.mu.Unlock()
+
+	return ok && now.Before(until)
+}
+
+func (s *UserTraceService) reload(ctx context.Context, now time.Time) {
+	traces, err := s.traceRepo.ListActive(ctx, now)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loading = false
+	if s.changed {
+		return
+	}
+	s.loadedAt = now
+	if err != nil {
+		logger.Warn(ctx, "service: UserTraceService.IsTraced - failed to reload traces, keeping previous", "error", err)
+		return
+	}
+
+	active := make(map[string]time.Time, len(traces))
+	for _, trace := range traces {
+		active[trace.UserID] = trace.Until
+	}
+	s.active = active
+}
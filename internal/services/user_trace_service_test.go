@@ -0,0 +1,219 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+var traceTestNow = time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+func newTestUserTraceService(repo *mocks.MockUserTraceRepository) (*UserTraceService, *time.Time) {
+	now := traceTestNow
+	service := NewUserTraceService(repo)
+	service.now = func() time.Time { return now }
+	return service, &now
+}
+
+func TestUserTraceService_EnableTrace(t *testing.T) {
+	tests := []struct {
+		name          string
+		req           models.EnableUserTraceRequest
+		mockError     error
+		expectedError error
+		expectError   bool
+		expectedUntil time.Time
+	}{
+		{name: "default duration", req: models.EnableUserTraceRequest{Reason: "  ticket 42  "}, expectedUntil: traceTestNow.Add(DefaultUserTraceDuration)},
+		{name: "explicit duration", req: models.EnableUserTraceRequest{DurationMinutes: 90}, expectedUntil: traceTestNow.Add(90 * time.Minute)},
+		{name: "maximum duration", req: models.EnableUserTraceRequest{DurationMinutes: 24 * 60}, expectedUntil: traceTestNow.Add(MaxUserTraceDuration)},
+		{name: "negative duration", req: models.EnableUserTraceRequest{DurationMinutes: -5}, expectedError: ErrInvalidTraceDuration},
+		{name: "duration too long", req: models.EnableUserTraceRequest{DurationMinutes: 24*60 + 1}, expectedError: ErrInvalidTraceDuration},
+		{name: "reason too long", req: models.EnableUserTraceRequest{Reason: strings.Repeat("x", MaxUserTraceReasonLength+1)}, expectedError: ErrTraceReasonTooLong},
+		{name: "repository error", mockError: errors.New("database error"), expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var saved *models.UserTrace
+			repo := &mocks.MockUserTraceRepository{
+				UpsertFunc: func(ctx context.Context, trace *models.UserTrace) error {
+					saved = trace
+					return tt.mockError
+				},
+				ListActiveFunc: func(ctx context.Context, now time.Time) ([]models.UserTrace, error) {
+					if saved == nil || tt.mockError != nil {
+						return []models.UserTrace{}, nil
+					}
+					return []models.UserTrace{*saved}, nil
+				},
+			}
+			service, _ := newTestUserTraceService(repo)
+
+			trace, err := service.EnableTrace(context.Background(), "user-123", tt.req)
+
+			if tt.expectedError != nil || tt.expectError {
+				if err == nil || (tt.expectedError != nil && !errors.Is(err, tt.expectedError)) {
+					t.Fatalf("expected error %v, got %v", tt.expectedError, err)
+				}
+				if service.IsTraced(context.Background(), "user-123") {
+					t.Error("expected a failed enable not to trace the user")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !trace.Until.Equal(tt.expectedUntil) || !trace.CreatedAt.Equal(traceTestNow) || trace != saved {
+				t.Errorf("unexpected trace: %+v", trace)
+			}
+			if trace.Reason != strings.TrimSpace(tt.req.Reason) {
+				t.Errorf("expected reason to be trimmed, got %q", trace.Reason)
+			}
+			if !service.IsTraced(context.Background(), "user-123") {
+				t.Error("expected the user to be traced immediately")
+			}
+		})
+	}
+}
+
+func TestUserTraceService_GetTrace(t *testing.T) {
+	tests := []struct {
+		name          string
+		stored        *models.UserTrace
+		mockError     error
+		expectedError error
+		expectError   bool
+	}{
+		{name: "active trace", stored: &models.UserTrace{UserID: "user-123", Until: traceTestNow.Add(time.Minute)}},
+		{name: "no trace", expectedError: ErrUserTraceNotFound},
+		{name: "expired trace", stored: &models.UserTrace{UserID: "user-123", Until: traceTestNow}, expectedError: ErrUserTraceNotFound},
+		{name: "repository error", mockError: errors.New("database error"), expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mocks.MockUserTraceRepository{
+				GetByUserIDFunc: func(ctx context.Context, userID string) (*models.UserTrace, error) {
+					return tt.stored, tt.mockError
+				},
+			}
+			service, _ := newTestUserTraceService(repo)
+
+			trace, err := service.GetTrace(context.Background(), "user-123")
+
+			if tt.expectedError != nil || tt.expectError {
+				if err == nil || (tt.expectedError != nil && !errors.Is(err, tt.expectedError)) {
+					t.Fatalf("expected error %v, got %v", tt.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if trace != tt.stored {
+				t.Errorf("expected the stored trace, got %+v", trace)
+			}
+		})
+	}
+}
+
+func TestUserTraceService_IsTraced(t *testing.T) {
+	t.Run("reloads active traces once per interval", func(t *testing.T) {
+		loads := 0
+		stored := []models.UserTrace{{UserID: "user-123", Until: traceTestNow.Add(time.Hour)}}
+		repo := &mocks.MockUserTraceRepository{
+			ListActiveFunc: func(ctx context.Context, now time.Time) ([]models.UserTrace, error) {
+				loads++
+				return stored, nil
+			},
+		}
+		service, now := newTestUserTraceService(repo)
+		ctx := context.Background()
+
+		if !service.IsTraced(ctx, "user-123") || service.IsTraced(ctx, "user-456") {
+			t.Fatal("expected only user-123 to be traced")
+		}
+		if loads != 1 {
+			t.Fatalf("expected one load, got %d", loads)
+		}
+
+		// A trace set on another instance is picked up after the interval.
+		stored = append(stored, models.UserTrace{UserID: "user-456", Until: traceTestNow.Add(time.Hour)})
+		*now = now.Add(userTraceRefreshInterval - time.Second)
+		if service.IsTraced(ctx, "user-456") || loads != 1 {
+			t.Fatalf("expected the cached copy within the interval, loads=%d", loads)
+		}
+		*now = now.Add(time.Second)
+		if !service.IsTraced(ctx, "user-456") || loads != 2 {
+			t.Errorf("expected a reload after the interval, loads=%d", loads)
+		}
+	})
+
+	t.Run("trace ends at its until time without a reload", func(t *testing.T) {
+		repo := &mocks.MockUserTraceRepository{
+			ListActiveFunc: func(ctx context.Context, now time.Time) ([]models.UserTrace, error) {
+				return []models.UserTrace{{UserID: "user-123", Until: traceTestNow.Add(time.Second)}}, nil
+			},
+		}
+		service, now := newTestUserTraceService(repo)
+
+		if !service.IsTraced(context.Background(), "user-123") {
+			t.Fatal("expected user to be traced")
+		}
+		*now = now.Add(time.Second)
+		if service.IsTraced(context.Background(), "user-123") {
+			t.Error("expected the trace to have ended")
+		}
+	})
+
+	t.Run("failed reload keeps previous traces", func(t *testing.T) {
+		fail := false
+		repo := &mocks.MockUserTraceRepository{
+			ListActiveFunc: func(ctx context.Context, now time.Time) ([]models.UserTrace, error) {
+				if fail {
+					return nil, errors.New("database error")
+				}
+				return []models.UserTrace{{UserID: "user-123", Until: traceTestNow.Add(time.Hour)}}, nil
+			},
+		}
+		service, now := newTestUserTraceService(repo)
+
+		service.IsTraced(context.Background(), "user-123")
+		fail = true
+		*now = now.Add(userTraceRefreshInterval)
+		if !service.IsTraced(context.Background(), "user-123") {
+			t.Error("expected the previous traces to be kept")
+		}
+	})
+
+	t.Run("disable applies immediately", func(t *testing.T) {
+		deleted := ""
+		repo := &mocks.MockUserTraceRepository{
+			ListActiveFunc: func(ctx context.Context, now time.Time) ([]models.UserTrace, error) {
+				return []models.UserTrace{{UserID: "user-123", Until: traceTestNow.Add(time.Hour)}}, nil
+			},
+			DeleteFunc: func(ctx context.Context, userID string) error {
+				deleted = userID
+				return nil
+			},
+		}
+		service, _ := newTestUserTraceService(repo)
+
+		service.IsTraced(context.Background(), "user-123")
+		if err := service.DisableTrace(context.Background(), "user-123"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if deleted != "user-123" {
+			t.Errorf("expected the stored trace to be deleted, got %q", deleted)
+		}
+		if service.IsTraced(context.Background(), "user-123") {
+			t.Error("expected the user to no longer be traced")
+		}
+	})
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+var ErrValidation = errors.New("request failed validation")
+
+// ValidationError collects every problem with a request, so a bulk request
+// can report all of its bad entries at once. It matches ErrValidation and
+// the error behind each problem, so errors.Is checks for those keep working.
+type ValidationError struct {
+	Problems []models.ValidationProblem
+	errs     []error
+}
+
+// Add records that field, of the entry for uniqueName if any, failed with
+// err.
+func (e *ValidationError) Add(field, uniqueName string, err error) {
+	e.Problems = append(e.Problems, models.ValidationProblem{
+		Field:      field,
+		UniqueName: uniqueName,
+		Reason:     err.Error(),
+	})
+	e.errs = append(e.errs, err)
+}
+
+// Err returns e if it has any problems and nil otherwise.
+func (e *ValidationError) Err() error {
+	if len(e.Problems) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Error is the first problem's reason, noting how many others there are.
+func (e *ValidationError) Error() string {
+	switch len(e.Problems) {
+	case 0:
+		return ErrValidation.Error()
+	case 1:
+		return e.Problems[0].Reason
+	default:
+		return fmt.Sprintf("%s (and %d more)", e.Problems[0].Reason, len(e.Problems)-1)
+	}
+}
+
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+func (e *ValidationError) Unwrap() []error {
+	return e.errs
+}
@@ -0,0 +1,35 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+func TestValidationError(t *testing.T) {
+	var problems ValidationError
+	if problems.Err() != nil {
+		t.Fatal("expected no error without problems")
+	}
+
+	problems.Add("items[0].status", "/Lotus/Powersuits/Ash", ErrInvalidMasteryStatus)
+	err := problems.Err()
+	if err == nil || err.Error() != ErrInvalidMasteryStatus.Error() {
+		t.Fatalf("expected the problem's reason, got %v", err)
+	}
+
+	problems.Add("items[1].uniqueName", "", models.ErrUniqueNameRequired)
+	err = problems.Err()
+	if err.Error() != ErrInvalidMasteryStatus.Error()+" (and 1 more)" {
+		t.Errorf("unexpected message %q", err.Error())
+	}
+	for _, target := range []error{ErrValidation, ErrInvalidMasteryStatus, models.ErrUniqueNameRequired} {
+		if !errors.Is(err, target) {
+			t.Errorf("expected the error to match %v", target)
+		}
+	}
+	if errors.Is(err, ErrItemNotFound) {
+		t.Error("expected the error not to match problems it does not have")
+	}
+}
@@ -0,0 +1,534 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+)
+
+const (
+	// MaxImportLines bounds both the non-blank lines of pasted text and the
+	// items of a confirm request.
+	MaxImportLines = 200
+	// MaxImportTextBytes bounds the pasted text.
+	MaxImportTextBytes = 64 * 1024
+	// MaxImportCandidates is how many candidates an ambiguous line lists.
+	MaxImportCandidates = 5
+)
+
+var (
+	ErrImportEmpty    = errors.New("nothing to import")
+	ErrImportTooLarge = fmt.Errorf("at most %d lines can be imported at once", MaxImportLines)
+)
+
+// importSkippedCollections hold star chart nodes and enemies, which share
+// names with real items (e.g. "Lua") but can never be wishlisted.
+var importSkippedCollections = map[string]bool{"node": true, "enemy": true}
+
+// importAliases expands shorthand common in players' notes, word by word.
+// Moderators add to and override them through ImportAliasService.
+var importAliases = map[string]string{
+	"bp":    "blueprint",
+	"bps":   "blueprint",
+	"p":     "prime",
+	"neuro": "neuroptics",
+	"sys":   "systems",
+}
+
+var (
+	importBulletPattern   = regexp.MustCompile(`^(?:[-*•+]|\d+[.)])\s+`)
+	importCheckboxPattern = regexp.MustCompile(`^\[[ xX]?\]\s*`)
+	importLeadingQuantity = regexp.MustCompile(`^(\d{1,4})\s*(?:[xX×]\s*)?\s(.+)$|^(\d{1,4})[xX×](.+)$`)
+	importTrailingXQty    = regexp.MustCompile(`^(.+?)\s*[xX×]\s*(\d{1,4})$`)
+	importTrailingQtyX    = regexp.MustCompile(`^(.+?)\s+(\d{1,4})\s*[xX×]$`)
+	importParenQuantity   = regexp.MustCompile(`^(.+?)\s*\((\d{1,4})\)$`)
+)
+
+// WishlistImportService turns a pasted list of item names into wishlist
+// additions in two steps: PreviewText resolves every line against the item
+// catalog without writing anything, and Confirm adds the items the user kept
+// through the wishlist service, so household approvals still apply.
+//
+// Names are resolved against an in-memory index of the catalog, built on
+// first use and dropped by Invalidate after a data sync. Stored aliases are
+// read on every preview, so moderators' edits apply immediately.
+type WishlistImportService struct {
+	catalog         repository.ItemCatalogInterface
+	wishlistService WishlistServiceInterface
+	aliasRepo       repository.ImportAliasRepositoryInterface
+
+	mu    sync.Mutex
+	index *importIndex
+}
+
+func NewWishlistImportService(catalog repository.ItemCatalogInterface, wishlistService WishlistServiceInterface) *WishlistImportService {
+	return &WishlistImportService{
+		catalog:         catalog,
+		wishlistService: wishlistService,
+	}
+}
+
+// SetAliasRepository adds the moderator-edited aliases to the built-in ones.
+func (s *WishlistImportService) SetAliasRepository(aliasRepo repository.ImportAliasRepositoryInterface) {
+	s.aliasRepo = aliasRepo
+}
+
+// Invalidate drops the name index so the next import rebuilds it from the
+// current item data.
+func (s *WishlistImportService) Invalidate() {
+	s.mu.Lock()
+	s.index = nil
+	s.mu.Unlock()
+}
+
+func (s *WishlistImportService) PreviewText(ctx context.Context, text string) (*models.ImportPreview, error) {
+	logger.Debug(ctx, "service: WishlistImportService.PreviewText called", "bytes", len(text))
+
+	if len(text) > MaxImportTextBytes {
+		logger.Warn(ctx, "service: WishlistImportService.PreviewText - text too large", "bytes", len(text))
+		return nil, ErrImportTooLarge
+	}
+
+	lines := parseImportLines(text)
+	if len(lines) == 0 {
+		logger.Warn(ctx, "service: WishlistImportService.PreviewText - no item names")
+		return nil, ErrImportEmpty
+	}
+	if len(lines) > MaxImportLines {
+		logger.Warn(ctx, "service: WishlistImportService.PreviewText - too many lines", "lineCount", len(lines))
+		return nil, ErrImportTooLarge
+	}
+
+	index, err := s.loadIndex(ctx)
+	if err != nil {
+		logger.Error(ctx, "service: WishlistImportService.PreviewText - error building name index", "error", err)
+		return nil, err
+	}
+
+	aliases := s.aliases(ctx)
+
+	preview := &models.ImportPreview{
+		Matched:   []models.ImportMatch{},
+		Ambiguous: []models.ImportAmbiguous{},
+		Unmatched: []models.ImportUnmatched{},
+	}
+	for _, line := range lines {
+		matchType, candidates := index.resolve(line.name, aliases)
+		switch {
+		case len(candidates) == 1:
+			preview.Matched = append(preview.Matched, models.ImportMatch{
+				Line:      line.number,
+				Input:     line.name,
+				Quantity:  line.quantity,
+				MatchType: matchType,
+				Item:      candidates[0],
+			})
+		case len(candidates) > 1:
+			preview.Ambiguous = append(preview.Ambiguous, models.ImportAmbiguous{
+				Line:       line.number,
+				Input:      line.name,
+				Quantity:   line.quantity,
+				Candidates: candidates,
+			})
+		default:
+			preview.Unmatched = append(preview.Unmatched, models.ImportUnmatched{
+				Line:  line.number,
+				Input: line.name,
+			})
+		}
+	}
+
+	logger.Info(ctx, "service: WishlistImportService.PreviewText - completed", "matched", len(preview.Matched), "ambiguous", len(preview.Ambiguous), "unmatched", len(preview.Unmatched))
+	return preview, nil
+}
+
+// Confirm adds every item in req and reports the outcome of each. Items that
+// are already in the wishlist, unknown, or held for approval do not stop the
+// others; any other error aborts, and retrying reports the items added before
+// it as already in the wishlist.
+func (s *WishlistImportService) Confirm(ctx context.Context, userID string, req models.ImportConfirmRequest) (*models.ImportConfirmResult, error) {
+	logger.Debug(ctx, "service: WishlistImportService.Confirm called", "userID", userID, "count", len(req.Items))
+
+	if len(req.Items) == 0 {
+		logger.Warn(ctx, "service: WishlistImportService.Confirm - no items")
+		return nil, ErrImportEmpty
+	}
+	if len(req.Items) > MaxImportLines {
+		logger.Warn(ctx, "service: WishlistImportService.Confirm - too many items", "count", len(req.Items))
+		return nil, ErrImportTooLarge
+	}
+
+	result := &models.ImportConfirmResult{Results: make([]models.ImportItemResult, 0, len(req.Items))}
+	for _, item := range req.Items {
+		quantity := item.Quantity
+		if quantity <= 0 {
+			quantity = 1
+		}
+		itemResult := models.ImportItemResult{UniqueName: item.UniqueName, Quantity: quantity}
+
+		uniqueName, err := models.CanonicalUniqueName(item.UniqueName)
+		if err != nil {
+			logger.Warn(ctx, "service: WishlistImportService.Confirm - invalid uniqueName", "uniqueName", item.UniqueName, "error", err)
+			itemResult.Status = models.ImportStatusInvalid
+			result.Results = append(result.Results, itemResult)
+			continue
+		}
+		itemResult.UniqueName = uniqueName
+
+		err = s.wishlistService.AddItem(ctx, userID, models.AddItemRequest{UniqueName: uniqueName, Quantity: quantity, AllowOwned: true})
+		var approvalErr *ApprovalRequiredError
+		switch {
+		case err == nil:
+			itemResult.Status = models.ImportStatusAdded
+		case errors.As(err, &approvalErr):
+			itemResult.Status = models.ImportStatusPendingApproval
+			itemResult.PendingChange = approvalErr.Change
+		case errors.Is(err, ErrItemAlreadyInWishlist):
+			itemResult.Status = models.ImportStatusAlreadyInWishlist
+		case errors.Is(err, ErrItemNotFound):
+			itemResult.Status = models.ImportStatusNotFound
+		default:
+			logger.Error(ctx, "service: WishlistImportService.Confirm - error adding item", "uniqueName", uniqueName, "error", err)
+			return nil, err
+		}
+		result.Results = append(result.Results, itemResult)
+	}
+
+	logger.Info(ctx, "service: WishlistImportService.Confirm - completed", "count", len(result.Results))
+	return result, nil
+}
+
+func (s *WishlistImportService) loadIndex(ctx context.Context) (*importIndex, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.index != nil {
+		return s.index, nil
+	}
+
+	index := &importIndex{byName: make(map[string][]models.ImportCandidate)}
+	err := s.catalog.ForEachItem(ctx, func(item models.Item) error {
+		if item.Archived || item.Name == "" || importSkippedCollections[item.Collection] {
+			return nil
+		}
+		index.add(item)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	index.finish()
+
+	logger.Info(ctx, "service: WishlistImportService - name index built", "nameCount", len(index.names))
+	s.index = index
+	return index, nil
+}
+
+// aliases returns the built-in aliases overridden by the stored ones. A
+// stored alias that cannot be read only makes matching less lenient, so the
+// preview falls back to the built-in aliases rather than failing.
+func (s *WishlistImportService) aliases(ctx context.Context) map[string]string {
+	if s.aliasRepo == nil {
+		return importAliases
+	}
+
+	stored, err := s.aliasRepo.List(ctx)
+	if err != nil {
+		logger.Warn(ctx, "service: WishlistImportService - error fetching import aliases; using the built-in ones", "error", err)
+		return importAliases
+	}
+
+	aliases := make(map[string]string, len(importAliases)+len(stored))
+	for word, expansion := range importAliases {
+		aliases[word] = expansion
+	}
+	for _, alias := range stored {
+		aliases[alias.Word] = alias.Expansion
+	}
+	return aliases
+}
+
+// importLine is one non-blank line of pasted text.
+type importLine struct {
+	number   int
+	name     string
+	quantity int
+}
+
+// parseImportLines extracts an item name and quantity from every line,
+// skipping blank lines and markdown headings. List bullets, numbering and
+// checkboxes are stripped; a quantity may be written as "2x Forma",
+// "2 x Forma", "2 Forma", "Forma x2", "Forma 2x" or "Forma (2)" and defaults
+// to 1.
+func parseImportLines(text string) []importLine {
+	var lines []importLine
+	for i, raw := range strings.Split(text, "\n") {
+		line := strings.TrimSpace(raw)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = importBulletPattern.ReplaceAllString(line, "")
+		line = strings.TrimSpace(importCheckboxPattern.ReplaceAllString(line, ""))
+
+		name, quantity := splitImportQuantity(line)
+		if name == "" {
+			continue
+		}
+		lines = append(lines, importLine{number: i + 1, name: name, quantity: quantity})
+	}
+	return lines
+}
+
+func splitImportQuantity(line string) (string, int) {
+	if m := importLeadingQuantity.FindStringSubmatch(line); m != nil {
+		if m[1] != "" {
+			return importQuantityResult(m[2], m[1])
+		}
+		return importQuantityResult(m[4], m[3])
+	}
+	for _, pattern := range []*regexp.Regexp{importTrailingXQty, importTrailingQtyX, importParenQuantity} {
+		if m := pattern.FindStringSubmatch(line); m != nil {
+			return importQuantityResult(m[1], m[2])
+		}
+	}
+	return line, 1
+}
+
+func importQuantityResult(name, digits string) (string, int) {
+	quantity, err := strconv.Atoi(digits)
+	if err != nil || quantity <= 0 {
+		quantity = 1
+	}
+	return strings.TrimSpace(name), quantity
+}
+
+// importIndex maps normalized item names to the items carrying them. Several
+// items can share a name, e.g. a component and its standalone resource.
+type importIndex struct {
+	byName map[string][]models.ImportCandidate
+	names  []string
+}
+
+func (x *importIndex) add(item models.Item) {
+	key := normalizeImportName(item.Name)
+	if key == "" {
+		return
+	}
+	x.byName[key] = append(x.byName[key], models.ImportCandidate{
+		UniqueName: item.UniqueName,
+		Name:       item.Name,
+		ImageName:  item.ImageName,
+	})
+}
+
+func (x *importIndex) finish() {
+	x.names = make([]string, 0, len(x.byName))
+	for name := range x.byName {
+		x.names = append(x.names, name)
+	}
+	sort.Strings(x.names)
+}
+
+// resolve looks input up by exact name, then with aliases expanded, then by
+// fuzzy match. It returns one candidate for a confident match, several for
+// an ambiguous one, and none when nothing is close.
+func (x *importIndex) resolve(input string, aliases map[string]string) (string, []models.ImportCandidate) {
+	key := normalizeImportName(input)
+	if key == "" {
+		return "", nil
+	}
+	if candidates := x.byName[key]; len(candidates) > 0 {
+		return models.ImportMatchExact, x.limit(candidates)
+	}
+
+	// Notes often list the blueprint or set of an item whose components are
+	// not wishlistable on their own, so fall back to the item itself.
+	expanded := expandImportAliases(key, aliases)
+	for _, alias := range []string{expanded, trimImportSuffix(expanded)} {
+		if alias == key {
+			continue
+		}
+		if candidates := x.byName[alias]; len(candidates) > 0 {
+			return models.ImportMatchAlias, x.limit(candidates)
+		}
+	}
+
+	return models.ImportMatchFuzzy, x.fuzzy(expanded)
+}
+
+type scoredImportName struct {
+	name  string
+	score int
+}
+
+// fuzzy scores every indexed name against key. Names within a small edit
+// distance score by that distance; names containing key as whole words score
+// after them, shortest first. A single best name is a match; a tie makes the
+// line ambiguous and lists the best few names.
+func (x *importIndex) fuzzy(key string) []models.ImportCandidate {
+	maxDistance := fuzzyImportDistance(key)
+	keyLen := len([]rune(key))
+
+	var scored []scoredImportName
+	for _, name := range x.names {
+		nameLen := len([]rune(name))
+		if nameLen-keyLen <= maxDistance && keyLen-nameLen <= maxDistance {
+			if d := boundedEditDistance(key, name, maxDistance); d <= maxDistance {
+				scored = append(scored, scoredImportName{name: name, score: d})
+				continue
+			}
+		}
+		if len(key) >= 3 && containsWords(name, key) {
+			scored = append(scored, scoredImportName{name: name, score: maxDistance + 1 + nameLen - keyLen})
+		}
+	}
+	if len(scored) == 0 {
+		return nil
+	}
+
+	sort.Slice(scored, func(i, j int) bool {
+		if scored[i].score != scored[j].score {
+			return scored[i].score < scored[j].score
+		}
+		return scored[i].name < scored[j].name
+	})
+
+	if len(scored) == 1 || scored[0].score < scored[1].score {
+		return x.limit(x.byName[scored[0].name])
+	}
+
+	var candidates []models.ImportCandidate
+	for _, s := range scored {
+		candidates = append(candidates, x.byName[s.name]...)
+		if len(candidates) >= MaxImportCandidates {
+			break
+		}
+	}
+	return x.limit(candidates)
+}
+
+func (x *importIndex) limit(candidates []models.ImportCandidate) []models.ImportCandidate {
+	if len(candidates) > MaxImportCandidates {
+		candidates = candidates[:MaxImportCandidates]
+	}
+	return append([]models.ImportCandidate(nil), candidates...)
+}
+
+// normalizeImportName lowercases name, drops apostrophes and turns any other
+// punctuation into word breaks, so "Ash's  Prime-BP" becomes "ashs prime bp".
+func normalizeImportName(name string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r == '\'' || r == '’':
+			continue
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			space = false
+			b.WriteRune(r)
+		default:
+			space = true
+		}
+	}
+	return b.String()
+}
+
+func expandImportAliases(key string, aliases map[string]string) string {
+	words := strings.Fields(key)
+	for i, word := range words {
+		if alias, ok := aliases[word]; ok {
+			words[i] = alias
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// trimImportSuffix drops a trailing "blueprint" or "set" word.
+func trimImportSuffix(key string) string {
+	for _, suffix := range []string{" blueprint", " set"} {
+		if trimmed, ok := strings.CutSuffix(key, suffix); ok {
+			return trimmed
+		}
+	}
+	return key
+}
+
+// fuzzyImportDistance allows roughly one typo per five characters, up to
+// three, and none for very short names where one edit changes the item.
+func fuzzyImportDistance(key string) int {
+	n := len([]rune(key))
+	switch {
+	case n < 4:
+		return 0
+	case n < 10:
+		return 1
+	case n < 15:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// containsWords reports whether key appears in name as a run of whole words.
+func containsWords(name, key string) bool {
+	for offset := 0; ; {
+		i := strings.Index(name[offset:], key)
+		if i < 0 {
+			return false
+		}
+		start, end := offset+i, offset+i+len(key)
+		if (start == 0 || name[start-1] == ' ') && (end == len(name) || name[end] == ' ') {
+			return true
+		}
+		offset = start + 1
+	}
+}
+
+// boundedEditDistance returns the edit distance between a and b, counting a
+// swap of adjacent characters as one edit, or bound+1 once it is known to
+// exceed bound.
+func boundedEditDistance(a, b string, bound int) int {
+	ra, rb := []rune(a), []rune(b)
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	prevMin := 0
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+			rowMin = min(rowMin, curr[j])
+		}
+		// A swap reaches back two rows, so stop only once both are over.
+		if rowMin > bound && prevMin > bound {
+			return bound + 1
+		}
+		prevMin = rowMin
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return prev[len(rb)]
+}
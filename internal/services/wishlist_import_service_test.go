@@ -0,0 +1,337 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository/memory"
+)
+
+// catalogFunc adapts a function to repository.ItemCatalogInterface.
+type catalogFunc func(ctx context.Context, fn func(item models.Item) error) error
+
+func (f catalogFunc) ForEachItem(ctx context.Context, fn func(item models.Item) error) error {
+	return f(ctx, fn)
+}
+
+func newImportCatalog() *memory.ItemRepository {
+	catalog := memory.NewItemRepository()
+	catalog.Add("warframes",
+		models.Item{UniqueName: "/Lotus/Powersuits/Rhino/Rhino", Name: "Rhino"},
+		models.Item{UniqueName: "/Lotus/Powersuits/Rhino/RhinoPrime", Name: "Rhino Prime"},
+		models.Item{UniqueName: "/Lotus/Powersuits/Ash/Ash", Name: "Ash"},
+	)
+	catalog.Add("primary",
+		models.Item{UniqueName: "/Lotus/Weapons/Soma", Name: "Soma"},
+		models.Item{UniqueName: "/Lotus/Weapons/SomaPrime", Name: "Soma Prime"},
+		models.Item{UniqueName: "/Lotus/Weapons/KuvaBramma", Name: "Kuva Bramma"},
+		models.Item{UniqueName: "/Lotus/Weapons/Braton", Name: "Braton"},
+		models.Item{UniqueName: "/Lotus/Weapons/MkBraton", Name: "MK1-Braton"},
+		models.Item{UniqueName: "/Lotus/Weapons/OldGun", Name: "Old Gun", Archived: true},
+	)
+	catalog.Add("misc",
+		models.Item{UniqueName: "/Lotus/StoreItems/Forma", Name: "Forma"},
+		models.Item{UniqueName: "/Lotus/Types/Forma", Name: "Forma"},
+	)
+	catalog.Add("node", models.Item{UniqueName: "SolNode1", Name: "Galatea"})
+	return catalog
+}
+
+func TestParseImportLines(t *testing.T) {
+	text := strings.Join([]string{
+		"# Frames",
+		"- [ ] Rhino Prime",
+		"",
+		"* 3x Forma",
+		"2 x Soma",
+		"4 Braton",
+		"1. Kuva Bramma x2",
+		"2) Ash 5x",
+		"[x] Soma Prime (3)",
+		"   Forma   ",
+		"Xaku",
+		"2 Xaku",
+	}, "\n")
+
+	expected := []importLine{
+		{number: 2, name: "Rhino Prime", quantity: 1},
+		{number: 4, name: "Forma", quantity: 3},
+		{number: 5, name: "Soma", quantity: 2},
+		{number: 6, name: "Braton", quantity: 4},
+		{number: 7, name: "Kuva Bramma", quantity: 2},
+		{number: 8, name: "Ash", quantity: 5},
+		{number: 9, name: "Soma Prime", quantity: 3},
+		{number: 10, name: "Forma", quantity: 1},
+		{number: 11, name: "Xaku", quantity: 1},
+		{number: 12, name: "Xaku", quantity: 2},
+	}
+
+	if got := parseImportLines(text); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+}
+
+func TestWishlistImportService_PreviewText(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		expectMatch   string
+		expectType    string
+		expectAmbig   int
+		expectNoMatch bool
+	}{
+		{name: "exact", input: "Rhino Prime", expectMatch: "/Lotus/Powersuits/Rhino/RhinoPrime", expectType: models.ImportMatchExact},
+		{name: "exact ignores case and punctuation", input: "mk1 braton", expectMatch: "/Lotus/Weapons/MkBraton", expectType: models.ImportMatchExact},
+		{name: "alias expands prime", input: "Soma P", expectMatch: "/Lotus/Weapons/SomaPrime", expectType: models.ImportMatchAlias},
+		{name: "alias drops blueprint", input: "Rhino Prime BP", expectMatch: "/Lotus/Powersuits/Rhino/RhinoPrime", expectType: models.ImportMatchAlias},
+		{name: "alias drops set", input: "Soma Prime Set", expectMatch: "/Lotus/Weapons/SomaPrime", expectType: models.ImportMatchAlias},
+		{name: "fuzzy typo", input: "Kuva Brama", expectMatch: "/Lotus/Weapons/KuvaBramma", expectType: models.ImportMatchFuzzy},
+		{name: "fuzzy swapped letters", input: "Rhnio Prime", expectMatch: "/Lotus/Powersuits/Rhino/RhinoPrime", expectType: models.ImportMatchFuzzy},
+		{name: "fuzzy whole words", input: "Bramma", expectMatch: "/Lotus/Weapons/KuvaBramma", expectType: models.ImportMatchFuzzy},
+		{name: "shared name is ambiguous", input: "Forma", expectAmbig: 2},
+		{name: "unknown item", input: "Braton Prime Receiver", expectNoMatch: true},
+		{name: "short names need an exact match", input: "Asj", expectNoMatch: true},
+		{name: "archived items are skipped", input: "Old Gun", expectNoMatch: true},
+		{name: "star chart nodes are skipped", input: "Galatea", expectNoMatch: true},
+	}
+
+	service := NewWishlistImportService(newImportCatalog(), &mocks.MockWishlistService{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			preview, err := service.PreviewText(context.Background(), "- 2x "+tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			switch {
+			case tt.expectMatch != "":
+				if len(preview.Matched) != 1 {
+					t.Fatalf("expected a match, got %+v", preview)
+				}
+				match := preview.Matched[0]
+				if match.Item.UniqueName != tt.expectMatch || match.MatchType != tt.expectType {
+					t.Errorf("expected %s (%s), got %s (%s)", tt.expectMatch, tt.expectType, match.Item.UniqueName, match.MatchType)
+				}
+				if match.Line != 1 || match.Input != tt.input || match.Quantity != 2 {
+					t.Errorf("unexpected line details: %+v", match)
+				}
+			case tt.expectAmbig > 0:
+				if len(preview.Ambiguous) != 1 || len(preview.Ambiguous[0].Candidates) != tt.expectAmbig {
+					t.Fatalf("expected %d candidates, got %+v", tt.expectAmbig, preview)
+				}
+			case tt.expectNoMatch:
+				if len(preview.Unmatched) != 1 || preview.Unmatched[0].Input != tt.input {
+					t.Fatalf("expected no match, got %+v", preview)
+				}
+			}
+		})
+	}
+}
+
+func TestWishlistImportService_PreviewTextLimits(t *testing.T) {
+	tooManyLines := strings.Repeat("Forma\n", MaxImportLines+1)
+
+	tests := []struct {
+		name          string
+		text          string
+		expectedError error
+	}{
+		{name: "empty", text: "", expectedError: ErrImportEmpty},
+		{name: "only blank lines and headings", text: "\n  \n# Wishlist\n", expectedError: ErrImportEmpty},
+		{name: "too many lines", text: tooManyLines, expectedError: ErrImportTooLarge},
+		{name: "too many bytes", text: strings.Repeat("a", MaxImportTextBytes+1), expectedError: ErrImportTooLarge},
+		{name: "blank lines do not count", text: strings.Repeat("\n", MaxImportLines+1) + "Forma"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewWishlistImportService(newImportCatalog(), &mocks.MockWishlistService{})
+			if _, err := service.PreviewText(context.Background(), tt.text); !errors.Is(err, tt.expectedError) {
+				t.Errorf("expected error %v, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestWishlistImportService_Index(t *testing.T) {
+	t.Run("built once and rebuilt after Invalidate", func(t *testing.T) {
+		builds := 0
+		catalog := newImportCatalog()
+		service := NewWishlistImportService(catalogFunc(func(ctx context.Context, fn func(item models.Item) error) error {
+			builds++
+			return catalog.ForEachItem(ctx, fn)
+		}), &mocks.MockWishlistService{})
+
+		for i := 0; i < 2; i++ {
+			if _, err := service.PreviewText(context.Background(), "Soma"); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		if builds != 1 {
+			t.Fatalf("expected the index to be built once, got %d", builds)
+		}
+
+		catalog.Add("primary", models.Item{UniqueName: "/Lotus/Weapons/Tenora", Name: "Tenora"})
+		service.Invalidate()
+		preview, err := service.PreviewText(context.Background(), "Tenora")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if builds != 2 || len(preview.Matched) != 1 {
+			t.Errorf("expected a rebuilt index to match the new item, builds=%d preview=%+v", builds, preview)
+		}
+	})
+
+	t.Run("catalog error", func(t *testing.T) {
+		service := NewWishlistImportService(catalogFunc(func(ctx context.Context, fn func(item models.Item) error) error {
+			return errors.New("database error")
+		}), &mocks.MockWishlistService{})
+
+		if _, err := service.PreviewText(context.Background(), "Soma"); err == nil {
+			t.Error("expected error but got none")
+		}
+	})
+}
+
+func TestWishlistImportService_Confirm(t *testing.T) {
+	change := &models.PendingChange{UniqueName: "/Lotus/Held", Quantity: 1}
+	addErrors := map[string]error{
+		"/Lotus/Existing": ErrItemAlreadyInWishlist,
+		"/Lotus/Missing":  ErrItemNotFound,
+		"/Lotus/Held":     &ApprovalRequiredError{Change: change},
+	}
+
+	var added []models.AddItemRequest
+	wishlistService := &mocks.MockWishlistService{
+		AddItemFunc: func(ctx context.Context, userID string, req models.AddItemRequest) error {
+			added = append(added, req)
+			return addErrors[req.UniqueName]
+		},
+	}
+
+	result, err := NewWishlistImportService(newImportCatalog(), wishlistService).Confirm(context.Background(), "user-123", models.ImportConfirmRequest{
+		Items: []models.AddItemRequest{
+			{UniqueName: "Lotus/New/", Quantity: 3},
+			{UniqueName: "/Lotus/Existing"},
+			{UniqueName: "/Lotus/Missing"},
+			{UniqueName: "/Lotus/Held"},
+			{UniqueName: "/Lotus/../Bad"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []models.ImportItemResult{
+		{UniqueName: "/Lotus/New", Quantity: 3, Status: models.ImportStatusAdded},
+		{UniqueName: "/Lotus/Existing", Quantity: 1, Status: models.ImportStatusAlreadyInWishlist},
+		{UniqueName: "/Lotus/Missing", Quantity: 1, Status: models.ImportStatusNotFound},
+		{UniqueName: "/Lotus/Held", Quantity: 1, Status: models.ImportStatusPendingApproval, PendingChange: change},
+		{UniqueName: "/Lotus/../Bad", Quantity: 1, Status: models.ImportStatusInvalid},
+	}
+	if !reflect.DeepEqual(result.Results, expected) {
+		t.Errorf("expected %+v, got %+v", expected, result.Results)
+	}
+	if len(added) != 4 || added[0].UniqueName != "/Lotus/New" {
+		t.Errorf("expected the four valid items to be added, got %+v", added)
+	}
+}
+
+func TestWishlistImportService_ConfirmErrors(t *testing.T) {
+	tooMany := make([]models.AddItemRequest, MaxImportLines+1)
+
+	tests := []struct {
+		name          string
+		items         []models.AddItemRequest
+		addError      error
+		expectedError error
+	}{
+		{name: "empty", items: nil, expectedError: ErrImportEmpty},
+		{name: "too many items", items: tooMany, expectedError: ErrImportTooLarge},
+		{name: "wishlist error aborts", items: []models.AddItemRequest{{UniqueName: "/Lotus/New"}}, addError: errors.New("database error")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wishlistService := &mocks.MockWishlistService{
+				AddItemFunc: func(ctx context.Context, userID string, req models.AddItemRequest) error {
+					return tt.addError
+				},
+			}
+
+			_, err := NewWishlistImportService(newImportCatalog(), wishlistService).Confirm(context.Background(), "user-123", models.ImportConfirmRequest{Items: tt.items})
+			if err == nil {
+				t.Fatal("expected error but got none")
+			}
+			if tt.expectedError != nil && !errors.Is(err, tt.expectedError) {
+				t.Errorf("expected error %v, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+// failingAliasRepository fails every read, as an unreachable database would.
+type failingAliasRepository struct {
+	*memory.ImportAliasRepository
+}
+
+func (failingAliasRepository) List(ctx context.Context) ([]models.ImportAlias, error) {
+	return nil, errors.New("database error")
+}
+
+func TestWishlistImportService_StoredAliases(t *testing.T) {
+	ctx := context.Background()
+	aliases := memory.NewImportAliasRepository()
+	service := NewWishlistImportService(newImportCatalog(), &mocks.MockWishlistService{})
+	service.SetAliasRepository(aliases)
+
+	preview, err := service.PreviewText(ctx, "kb")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(preview.Matched) != 0 {
+		t.Fatalf("expected no match before the alias is stored, got %+v", preview.Matched)
+	}
+
+	// Stored aliases apply to the next preview without rebuilding the index.
+	if err := aliases.Set(ctx, models.ImportAlias{Word: "kb", Expansion: "kuva bramma"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	preview, err = service.PreviewText(ctx, "kb\nsoma p")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(preview.Matched) != 2 || preview.Matched[0].Item.UniqueName != "/Lotus/Weapons/KuvaBramma" || preview.Matched[0].MatchType != models.ImportMatchAlias {
+		t.Fatalf("expected the stored alias to match, got %+v", preview.Matched)
+	}
+	if preview.Matched[1].Item.UniqueName != "/Lotus/Weapons/SomaPrime" {
+		t.Errorf("expected the built-in aliases to still apply, got %+v", preview.Matched[1])
+	}
+
+	// A stored alias overrides the built-in one of the same word.
+	if err := aliases.Set(ctx, models.ImportAlias{Word: "p", Expansion: "soma"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	preview, err = service.PreviewText(ctx, "p")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(preview.Matched) != 1 || preview.Matched[0].Item.UniqueName != "/Lotus/Weapons/Soma" {
+		t.Errorf("expected the override to apply, got %+v", preview)
+	}
+
+	// Unreadable aliases fall back to the built-in ones.
+	service.SetAliasRepository(failingAliasRepository{aliases})
+	preview, err = service.PreviewText(ctx, "soma p")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(preview.Matched) != 1 || preview.Matched[0].Item.UniqueName != "/Lotus/Weapons/SomaPrime" {
+		t.Errorf("expected the built-in aliases, got %+v", preview)
+	}
+}
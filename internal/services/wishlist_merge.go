@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+)
+
+var ErrMergeTooLarge = fmt.Errorf("a merge can list at most %d base items and %d items", MaxWishlistExportEntries, MaxWishlistExportEntries)
+
+// Merge applies a client's edit of the wishlist made against an earlier
+// revision, merging it three ways with what changed since: of base, the
+// client's items and the current wishlist, an item only one side changed
+// takes that side's quantity, or its removal. Where both changed it, the
+// union wins: the item stays, with the larger quantity. Items the client
+// never saw are left alone. Changes are written through the wishlist
+// service like an import's, and a change made between the merge reading the
+// wishlist and writing it is merged over as if made before.
+func (s *WishlistTransferService) Merge(ctx context.Context, userID string, req models.WishlistMergeRequest) (*models.WishlistMergeResult, error) {
+	logger.Debug(ctx, "service: WishlistTransferService.Merge called", "userID", userID, "itemCount", len(req.Items))
+
+	if len(req.Base) > MaxWishlistExportEntries || len(req.Items) > MaxWishlistExportEntries {
+		logger.Warn(ctx, "service: WishlistTransferService.Merge - merge too large", "baseCount", len(req.Base), "itemCount", len(req.Items))
+		return nil, ErrMergeTooLarge
+	}
+
+	wishlist, err := s.wishlistService.GetWishlist(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "service: WishlistTransferService.Merge - error fetching wishlist", "error", err)
+		return nil, err
+	}
+	theirs := make(map[string]int, len(wishlist.Items))
+	for _, item := range wishlist.Items {
+		theirs[item.UniqueName] = item.Quantity
+	}
+	// A wishlist unchanged since the client's revision is its own base.
+	base, baseOrder := theirs, []string(nil)
+	if !req.BaseRevision.Equal(wishlist.UpdatedAt) {
+		base, baseOrder = mergeSide(req.Base)
+	}
+
+	plan := &transferPlan{op: "Merge", result: &models.WishlistDocumentImportResult{Items: []models.ImportItemResult{}}}
+	result := &models.WishlistMergeResult{Conflicts: []string{}}
+	mine := make(map[string]int, len(req.Items))
+	var names []string
+	for _, item := range req.Items {
+		name, err := models.CanonicalUniqueName(item.UniqueName)
+		if err != nil || item.Quantity <= 0 {
+			plan.result.Items = append(plan.result.Items, models.ImportItemResult{UniqueName: item.UniqueName, Quantity: item.Quantity, Status: models.ImportStatusInvalid})
+			if err == nil {
+				// Whatever the client meant, it was not to remove the item.
+				if quantity, ok := base[name]; ok {
+					mine[name] = quantity
+				}
+			}
+			continue
+		}
+		if _, listed := mine[name]; listed {
+			continue
+		}
+		mine[name] = item.Quantity
+		names = append(names, name)
+	}
+	for _, name := range baseOrder {
+		if _, listed := mine[name]; !listed {
+			names = append(names, name)
+		}
+	}
+	if baseOrder == nil {
+		// The base is the wishlist: report the items the client removed.
+		for _, item := range wishlist.Items {
+			if _, listed := mine[item.UniqueName]; !listed {
+				names = append(names, item.UniqueName)
+			}
+		}
+	}
+
+	var additions []string
+	changes := make(map[string]transferChange, len(names))
+	for _, name := range names {
+		b, inBase := base[name]
+		m, inMine := mine[name]
+		t, inTheirs := theirs[name]
+
+		quantity, keep := t, inTheirs
+		switch {
+		case inMine == inBase && m == b:
+		case (inTheirs == inBase && t == b) || (inMine == inTheirs && m == t):
+			quantity, keep = m, inMine
+		default:
+			result.Conflicts = append(result.Conflicts, name)
+			quantity, keep = max(m, t), true
+		}
+
+		itemResult := models.ImportItemResult{UniqueName: name, Quantity: quantity, Status: models.ImportStatusUnchanged}
+		switch {
+		case keep && !inTheirs:
+			itemResult.Status = models.ImportStatusAdded
+			changes[name] = transferChange{add: true, quantity: quantity}
+			additions = append(additions, name)
+		case !keep && inTheirs:
+			itemResult.Quantity, itemResult.Status = 0, models.ImportStatusRemoved
+			changes[name] = transferChange{remove: true}
+		case keep && quantity != t:
+			itemResult.Status = models.ImportStatusUpdated
+			changes[name] = transferChange{quantity: quantity}
+		}
+		plan.result.Items = append(plan.result.Items, itemResult)
+	}
+
+	if len(additions) > 0 {
+		itemRepo, err := itemsForUser(ctx, s.itemRepo, s.customItemRepo, userID)
+		if err != nil {
+			logger.Error(ctx, "service: WishlistTransferService.Merge - error fetching custom items", "error", err)
+			return nil, err
+		}
+		catalog, err := itemRepo.FindByUniqueNames(ctx, additions)
+		if err != nil {
+			logger.Error(ctx, "service: WishlistTransferService.Merge - error finding items", "error", err)
+			return nil, err
+		}
+		for _, name := range additions {
+			if catalog[name] == nil {
+				delete(changes, name)
+			}
+		}
+	}
+	for i := range plan.result.Items {
+		itemResult := &plan.result.Items[i]
+		if itemResult.Status == models.ImportStatusInvalid || itemResult.Status == models.ImportStatusUnchanged {
+			continue
+		}
+		change, ok := changes[itemResult.UniqueName]
+		if !ok {
+			itemResult.Quantity, itemResult.Status = 0, models.ImportStatusNotFound
+			continue
+		}
+		change.index = i
+		plan.changes = append(plan.changes, change)
+	}
+
+	if err := s.apply(ctx, userID, plan); err != nil {
+		return nil, err
+	}
+	result.Items = plan.result.Items
+	if result.Wishlist, err = s.wishlistService.GetWishlist(ctx, userID); err != nil {
+		logger.Error(ctx, "service: WishlistTransferService.Merge - error fetching merged wishlist", "error", err)
+		return nil, err
+	}
+
+	logger.Info(ctx, "service: WishlistTransferService.Merge - completed", "changeCount", len(plan.changes), "conflictCount", len(result.Conflicts))
+	return result, nil
+}
+
+// mergeSide indexes the valid items of a merge's base by canonical name,
+// the first of each name winning, and returns the names in order.
+func mergeSide(items []models.WishlistMergeItem) (map[string]int, []string) {
+	side := make(map[string]int, len(items))
+	order := []string{}
+	for _, item := range items {
+		name, err := models.CanonicalUniqueName(item.UniqueName)
+		if err != nil || item.Quantity <= 0 {
+			continue
+		}
+		if _, ok := side[name]; !ok {
+			side[name] = item.Quantity
+			order = append(order, name)
+		}
+	}
+	return side, order
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+func quantities(wishlist *models.Wishlist) map[string]int {
+	byName := make(map[string]int, len(wishlist.Items))
+	for _, item := range wishlist.Items {
+		byName[item.UniqueName] = item.Quantity
+	}
+	return byName
+}
+
+func TestWishlistTransferService_MergeCurrentRevision(t *testing.T) {
+	f := newTransferFixture(t)
+	f.seed(t, "user1")
+	ctx := context.Background()
+	current, _ := f.wishlists.GetByUserID(ctx, "user1")
+
+	// The wishlist has not changed since the client's revision, so the
+	// client's items apply as they are, removals included.
+	result, err := f.service.Merge(ctx, "user1", models.WishlistMergeRequest{
+		BaseRevision: current.UpdatedAt,
+		Items: []models.WishlistMergeItem{
+			{UniqueName: "/Lotus/Weapons/Soma", Quantity: 4},
+			{UniqueName: "/Lotus/Types/Forma", Quantity: 2},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := statuses(result.Items)
+	want := map[string]string{
+		"/Lotus/Weapons/Soma":           models.ImportStatusUpdated,
+		"/Lotus/Types/Forma":            models.ImportStatusAdded,
+		"/Lotus/Powersuits/Rhino/Rhino": models.ImportStatusRemoved,
+	}
+	for name, status := range want {
+		if got[name] != status {
+			t.Errorf("%s: expected %s, got %s", name, status, got[name])
+		}
+	}
+	if len(result.Conflicts) != 0 {
+		t.Errorf("expected no conflicts, got %v", result.Conflicts)
+	}
+	merged := quantities(result.Wishlist)
+	if len(merged) != 2 || merged["/Lotus/Weapons/Soma"] != 4 || merged["/Lotus/Types/Forma"] != 2 {
+		t.Errorf("expected the client's items, got %v", merged)
+	}
+}
+
+func TestWishlistTransferService_MergeStaleRevision(t *testing.T) {
+	f := newTransferFixture(t)
+	f.seed(t, "user1")
+	ctx := context.Background()
+
+	// Since the client's revision, another device raised Rhino, removed Soma
+	// and added Ash.
+	current, _ := f.wishlists.GetByUserID(ctx, "user1")
+	current.Items = []models.WishlistItem{
+		{UniqueName: "/Lotus/Powersuits/Rhino/Rhino", Quantity: 3},
+		{UniqueName: "/Lotus/Powersuits/Ash/Ash", Quantity: 1},
+	}
+	if err := f.wishlists.Upsert(ctx, current); err != nil {
+		t.Fatalf("updating wishlist: %v", err)
+	}
+
+	result, err := f.service.Merge(ctx, "user1", models.WishlistMergeRequest{
+		BaseRevision: current.UpdatedAt.Add(-time.Minute),
+		Base: []models.WishlistMergeItem{
+			{UniqueName: "/Lotus/Powersuits/Rhino/Rhino", Quantity: 1},
+			{UniqueName: "/Lotus/Weapons/Soma", Quantity: 2},
+		},
+		Items: []models.WishlistMergeItem{
+			{UniqueName: "/Lotus/Powersuits/Rhino/Rhino", Quantity: 2},
+			{UniqueName: "/Lotus/Weapons/Soma", Quantity: 2},
+			{UniqueName: "/Lotus/Types/Forma", Quantity: 5},
+			{UniqueName: "/Lotus/Missing", Quantity: 1},
+			{UniqueName: "/Lotus/../Forma", Quantity: 1},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := statuses(result.Items)
+	want := map[string]string{
+		"/Lotus/Powersuits/Rhino/Rhino": models.ImportStatusUnchanged,
+		"/Lotus/Weapons/Soma":           models.ImportStatusUnchanged,
+		"/Lotus/Types/Forma":            models.ImportStatusAdded,
+		"/Lotus/Missing":                models.ImportStatusNotFound,
+		"/Lotus/../Forma":               models.ImportStatusInvalid,
+	}
+	for name, status := range want {
+		if got[name] != status {
+			t.Errorf("%s: expected %s, got %s", name, status, got[name])
+		}
+	}
+	if len(result.Conflicts) != 1 || result.Conflicts[0] != "/Lotus/Powersuits/Rhino/Rhino" {
+		t.Errorf("expected Rhino to conflict, got %v", result.Conflicts)
+	}
+
+	// Rhino keeps the larger quantity, Soma stays removed as the client left
+	// it alone, and Ash, which the client never saw, is untouched.
+	merged := quantities(result.Wishlist)
+	wantMerged := map[string]int{
+		"/Lotus/Powersuits/Rhino/Rhino": 3,
+		"/Lotus/Powersuits/Ash/Ash":     1,
+		"/Lotus/Types/Forma":            5,
+	}
+	if len(merged) != len(wantMerged) {
+		t.Fatalf("expected %v, got %v", wantMerged, merged)
+	}
+	for name, quantity := range wantMerged {
+		if merged[name] != quantity {
+			t.Errorf("%s: expected quantity %d, got %d", name, quantity, merged[name])
+		}
+	}
+}
+
+func TestWishlistTransferService_MergeRemovals(t *testing.T) {
+	f := newTransferFixture(t)
+	f.seed(t, "user1")
+	ctx := context.Background()
+
+	current, _ := f.wishlists.GetByUserID(ctx, "user1")
+	current.Items[0].Quantity = 4
+	if err := f.wishlists.Upsert(ctx, current); err != nil {
+		t.Fatalf("updating wishlist: %v", err)
+	}
+
+	// The client removed both items; Rhino was changed since, so it
+	// conflicts and stays, while Soma's removal goes through.
+	result, err := f.service.Merge(ctx, "user1", models.WishlistMergeRequest{
+		BaseRevision: current.UpdatedAt.Add(-time.Minute),
+		Base: []models.WishlistMergeItem{
+			{UniqueName: "/Lotus/Powersuits/Rhino/Rhino", Quantity: 1},
+			{UniqueName: "/Lotus/Weapons/Soma", Quantity: 2},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := statuses(result.Items)
+	if got["/Lotus/Weapons/Soma"] != models.ImportStatusRemoved || got["/Lotus/Powersuits/Rhino/Rhino"] != models.ImportStatusUnchanged {
+		t.Errorf("unexpected statuses %v", got)
+	}
+	if len(result.Conflicts) != 1 || result.Conflicts[0] != "/Lotus/Powersuits/Rhino/Rhino" {
+		t.Errorf("expected Rhino to conflict, got %v", result.Conflicts)
+	}
+	merged := quantities(result.Wishlist)
+	if len(merged) != 1 || merged["/Lotus/Powersuits/Rhino/Rhino"] != 4 {
+		t.Errorf("expected only Rhino at 4, got %v", merged)
+	}
+}
+
+func TestWishlistTransferService_MergeTooLarge(t *testing.T) {
+	f := newTransferFixture(t)
+	items := make([]models.WishlistMergeItem, MaxWishlistExportEntries+1)
+	_, err := f.service.Merge(context.Background(), "user1", models.WishlistMergeRequest{Items: items})
+	if !errors.Is(err, ErrMergeTooLarge) {
+		t.Errorf("expected ErrMergeTooLarge, got %v", err)
+	}
+}
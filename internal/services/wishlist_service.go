@@ -3,8 +3,10 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
+	"github.com/graytonio/warframe-wishlist/internal/hooks"
 	"github.com/graytonio/warframe-wishlist/internal/models"
 	"github.com/graytonio/warframe-wishlist/internal/repository"
 	"github.com/graytonio/warframe-wishlist/pkg/logger"
@@ -15,11 +17,43 @@ var (
 	ErrItemNotFound          = errors.New("item not found")
 	ErrItemNotInWishlist     = errors.New("item not in wishlist")
 	ErrInvalidQuantity       = errors.New("quantity must be greater than 0")
+	ErrRecipeNotFound        = errors.New("recipe not found")
+	ErrWishlistFull          = errors.New("wishlist item limit reached")
+	ErrItemAlreadyOwned      = errors.New("item already owned")
 )
 
+// ItemAlreadyOwnedError is returned when adding an item the user's mastery
+// profile says they already have. It matches ErrItemAlreadyOwned.
+type ItemAlreadyOwnedError struct {
+	Entry *models.MasteryEntry
+}
+
+func (e *ItemAlreadyOwnedError) Error() string {
+	return ErrItemAlreadyOwned.Error()
+}
+
+func (e *ItemAlreadyOwnedError) Is(target error) bool {
+	return target == ErrItemAlreadyOwned
+}
+
 type WishlistService struct {
 	wishlistRepo repository.WishlistRepositoryInterface
 	itemRepo     repository.ItemRepositoryInterface
+	// materialsCache is optional; item changes drop the user's cached
+	// materials response from it.
+	materialsCache MaterialsCache
+	// settingsRepo is optional; without it items added without a quantity
+	// get 1 instead of the user's default quantity.
+	settingsRepo repository.SettingsRepositoryInterface
+	// customItemRepo is optional; without it only game items can be added.
+	customItemRepo repository.CustomItemRepositoryInterface
+	// maxItems caps the items of one wishlist; 0 leaves it uncapped.
+	maxItems int
+	// masteryRepo is optional; without it AddItem never reports an item as
+	// already owned.
+	masteryRepo repository.MasteryRepositoryInterface
+	// hooks is optional; AddItem raises hooks.EventItemAddedToWishlist on it.
+	hooks *hooks.Registry
 }
 
 func NewWishlistService(wishlistRepo repository.WishlistRepositoryInterface, itemRepo repository.ItemRepositoryInterface) *WishlistService {
@@ -29,6 +63,40 @@ func NewWishlistService(wishlistRepo repository.WishlistRepositoryInterface, ite
 	}
 }
 
+// SetMaterialsCache makes item changes invalidate the user's entry in cache.
+func (s *WishlistService) SetMaterialsCache(cache MaterialsCache) {
+	s.materialsCache = cache
+}
+
+// SetSettingsRepository makes AddItem apply the user's default quantity rules
+// when a request omits the quantity.
+func (s *WishlistService) SetSettingsRepository(settingsRepo repository.SettingsRepositoryInterface) {
+	s.settingsRepo = settingsRepo
+}
+
+// SetCustomItemRepository lets users add their own custom items and shows
+// them in the expanded wishlist.
+func (s *WishlistService) SetCustomItemRepository(customItemRepo repository.CustomItemRepositoryInterface) {
+	s.customItemRepo = customItemRepo
+}
+
+// SetMaxItems makes AddItem reject items beyond max once the wishlist holds
+// that many; 0 leaves wishlists uncapped.
+func (s *WishlistService) SetMaxItems(max int) {
+	s.maxItems = max
+}
+
+// SetMasteryRepository makes AddItem reject items in the user's mastery
+// profile unless the request allows them.
+func (s *WishlistService) SetMasteryRepository(masteryRepo repository.MasteryRepositoryInterface) {
+	s.masteryRepo = masteryRepo
+}
+
+// SetHooks makes AddItem raise hooks.EventItemAddedToWishlist on registry.
+func (s *WishlistService) SetHooks(registry *hooks.Registry) {
+	s.hooks = registry
+}
+
 func (s *WishlistService) GetWishlist(ctx context.Context, userID string) (*models.Wishlist, error) {
 	logger.Debug(ctx, "service: WishlistService.GetWishlist called", "userID", userID)
 
@@ -48,15 +116,64 @@ func (s *WishlistService) GetWishlist(ctx context.Context, userID string) (*mode
 		}
 	}
 
+	if err := s.setCompletion(ctx, userID, wishlist.Items); err != nil {
+		return nil, err
+	}
+
 	logger.Debug(ctx, "service: WishlistService.GetWishlist - completed", "itemCount", len(wishlist.Items))
 	return wishlist, nil
 }
 
+// setCompletion fills in Completion for the items with component progress;
+// the rest are 0% complete without looking anything up.
+func (s *WishlistService) setCompletion(ctx context.Context, userID string, items []models.WishlistItem) error {
+	var uniqueNames []string
+	for _, wi := range items {
+		if len(wi.Progress) > 0 {
+			uniqueNames = append(uniqueNames, wi.UniqueName)
+		}
+	}
+	if len(uniqueNames) == 0 {
+		return nil
+	}
+
+	itemRepo, err := itemsForUser(ctx, s.itemRepo, s.customItemRepo, userID)
+	if err != nil {
+		logger.Error(ctx, "service: WishlistService.GetWishlist - error fetching custom items", "error", err)
+		return err
+	}
+	found, err := itemRepo.FindByUniqueNames(ctx, uniqueNames)
+	if err != nil {
+		logger.Error(ctx, "service: WishlistService.GetWishlist - error finding items", "error", err)
+		return err
+	}
+	for i := range items {
+		if item := found[items[i].UniqueName]; item != nil && len(items[i].Progress) > 0 {
+			items[i].Completion = itemCompletion(recipeOrDefault(item, items[i].RecipeID), items[i])
+		}
+	}
+	return nil
+}
+
+// recipeOrDefault returns item as crafted with recipeID, falling back to the
+// default recipe when a data sync dropped it, as MaterialResolver does.
+func recipeOrDefault(item *models.Item, recipeID string) *models.Item {
+	if withRecipe, ok := item.WithRecipe(recipeID); ok {
+		return withRecipe
+	}
+	return item
+}
+
 func (s *WishlistService) AddItem(ctx context.Context, userID string, req models.AddItemRequest) error {
 	logger.Debug(ctx, "service: WishlistService.AddItem called", "userID", userID, "uniqueName", req.UniqueName, "quantity", req.Quantity)
 
 	logger.Debug(ctx, "service: WishlistService.AddItem - validating item exists")
-	item, err := s.itemRepo.FindByUniqueName(ctx, req.UniqueName)
+	itemRepo, err := itemsForUser(ctx, s.itemRepo, s.customItemRepo, userID)
+	if err != nil {
+		logger.Error(ctx, "service: WishlistService.AddItem - error fetching custom items", "error", err)
+		return err
+	}
+	item, err := itemRepo.FindByUniqueName(ctx, req.UniqueName)
 	if err != nil {
 		logger.Error(ctx, "service: WishlistService.AddItem - error finding item", "error", err)
 		return err
@@ -66,6 +183,23 @@ func (s *WishlistService) AddItem(ctx context.Context, userID string, req models
 		return ErrItemNotFound
 	}
 
+	if s.masteryRepo != nil && !req.AllowOwned {
+		entry, err := s.masteryRepo.Get(ctx, userID, req.UniqueName)
+		if err != nil {
+			logger.Error(ctx, "service: WishlistService.AddItem - error checking mastery profile", "error", err)
+			return err
+		}
+		if entry != nil {
+			logger.Warn(ctx, "service: WishlistService.AddItem - item already owned", "uniqueName", req.UniqueName, "status", entry.Status)
+			return &ItemAlreadyOwnedError{Entry: entry}
+		}
+	}
+
+	quantity := req.Quantity
+	if quantity <= 0 {
+		quantity = defaultQuantity(ctx, s.settingsRepo, userID, item)
+	}
+
 	logger.Debug(ctx, "service: WishlistService.AddItem - fetching user wishlist")
 	wishlist, err := s.wishlistRepo.GetByUserID(ctx, userID)
 	if err != nil {
@@ -75,11 +209,6 @@ func (s *WishlistService) AddItem(ctx context.Context, userID string, req models
 
 	if wishlist == nil {
 		logger.Debug(ctx, "service: WishlistService.AddItem - creating new wishlist for user")
-		quantity := req.Quantity
-		if quantity <= 0 {
-			quantity = 1
-		}
-
 		wishlist = &models.Wishlist{
 			UserID: userID,
 			Items: []models.WishlistItem{
@@ -95,6 +224,8 @@ func (s *WishlistService) AddItem(ctx context.Context, userID string, req models
 			logger.Error(ctx, "service: WishlistService.AddItem - error creating wishlist", "error", err)
 			return err
 		}
+		invalidateMaterials(ctx, s.materialsCache, userID)
+		s.itemAdded(ctx, userID, req.UniqueName, quantity)
 		logger.Info(ctx, "service: WishlistService.AddItem - created new wishlist with item", "uniqueName", req.UniqueName)
 		return nil
 	}
@@ -105,10 +236,9 @@ func (s *WishlistService) AddItem(ctx context.Context, userID string, req models
 			return ErrItemAlreadyInWishlist
 		}
 	}
-
-	quantity := req.Quantity
-	if quantity <= 0 {
-		quantity = 1
+	if s.maxItems > 0 && len(wishlist.Items) >= s.maxItems {
+		logger.Warn(ctx, "service: WishlistService.AddItem - wishlist is full", "count", len(wishlist.Items), "max", s.maxItems)
+		return fmt.Errorf("%w: at most %d items", ErrWishlistFull, s.maxItems)
 	}
 
 	newItem := models.WishlistItem{
@@ -122,10 +252,20 @@ func (s *WishlistService) AddItem(ctx context.Context, userID string, req models
 		logger.Error(ctx, "service: WishlistService.AddItem - error adding item to wishlist", "error", err)
 		return err
 	}
+	invalidateMaterials(ctx, s.materialsCache, userID)
+	s.itemAdded(ctx, userID, req.UniqueName, quantity)
 	logger.Info(ctx, "service: WishlistService.AddItem - item added successfully", "uniqueName", req.UniqueName, "quantity", quantity)
 	return nil
 }
 
+func (s *WishlistService) itemAdded(ctx context.Context, userID, uniqueName string, quantity int) {
+	s.hooks.Emit(ctx, hooks.Event{
+		Name:   hooks.EventItemAddedToWishlist,
+		UserID: userID,
+		Data:   hooks.ItemAdded{UniqueName: uniqueName, Quantity: quantity},
+	})
+}
+
 func (s *WishlistService) RemoveItem(ctx context.Context, userID, uniqueName string) error {
 	logger.Debug(ctx, "service: WishlistService.RemoveItem called", "userID", userID, "uniqueName", uniqueName)
 
@@ -158,6 +298,7 @@ func (s *WishlistService) RemoveItem(ctx context.Context, userID, uniqueName str
 		logger.Error(ctx, "service: WishlistService.RemoveItem - error removing item", "error", err)
 		return err
 	}
+	invalidateMaterials(ctx, s.materialsCache, userID)
 	logger.Info(ctx, "service: WishlistService.RemoveItem - item removed successfully", "uniqueName", uniqueName)
 	return nil
 }
@@ -199,6 +340,207 @@ func (s *WishlistService) UpdateQuantity(ctx context.Context, userID, uniqueName
 		logger.Error(ctx, "service: WishlistService.UpdateQuantity - error updating quantity", "error", err)
 		return err
 	}
+	invalidateMaterials(ctx, s.materialsCache, userID)
 	logger.Info(ctx, "service: WishlistService.UpdateQuantity - quantity updated successfully", "uniqueName", uniqueName, "quantity", quantity)
 	return nil
 }
+
+// SetItemLinks replaces the source links on a wishlist item. Links are
+// validated and normalized; duplicates (by normalized URL) are dropped,
+// keeping the first title given. An empty list clears the item's links.
+func (s *WishlistService) SetItemLinks(ctx context.Context, userID, uniqueName string, reqs []models.SourceLinkRequest) ([]models.SourceLink, error) {
+	logger.Debug(ctx, "service: WishlistService.SetItemLinks called", "userID", userID, "uniqueName", uniqueName, "linkCount", len(reqs))
+
+	links, err := buildSourceLinks(reqs)
+	if err != nil {
+		logger.Warn(ctx, "service: WishlistService.SetItemLinks - invalid links", "error", err)
+		return nil, err
+	}
+
+	wishlist, err := s.wishlistRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "service: WishlistService.SetItemLinks - error fetching wishlist", "error", err)
+		return nil, err
+	}
+	if !containsItem(wishlist, uniqueName) {
+		logger.Warn(ctx, "service: WishlistService.SetItemLinks - item not in wishlist", "uniqueName", uniqueName)
+		return nil, ErrItemNotInWishlist
+	}
+
+	if err := s.wishlistRepo.SetItemLinks(ctx, userID, uniqueName, links); err != nil {
+		logger.Error(ctx, "service: WishlistService.SetItemLinks - error saving links", "error", err)
+		return nil, err
+	}
+	logger.Info(ctx, "service: WishlistService.SetItemLinks - links updated", "uniqueName", uniqueName, "linkCount", len(links))
+	return links, nil
+}
+
+// SetItemRecipe selects the recipe MaterialResolver uses for a wishlist item.
+// recipeID must name one of the item's alternate recipes; an empty recipeID
+// restores the default recipe.
+func (s *WishlistService) SetItemRecipe(ctx context.Context, userID, uniqueName, recipeID string) error {
+	logger.Debug(ctx, "service: WishlistService.SetItemRecipe called", "userID", userID, "uniqueName", uniqueName, "recipeID", recipeID)
+
+	wishlist, err := s.wishlistRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "service: WishlistService.SetItemRecipe - error fetching wishlist", "error", err)
+		return err
+	}
+	if !containsItem(wishlist, uniqueName) {
+		logger.Warn(ctx, "service: WishlistService.SetItemRecipe - item not in wishlist", "uniqueName", uniqueName)
+		return ErrItemNotInWishlist
+	}
+
+	if recipeID != "" {
+		itemRepo, err := itemsForUser(ctx, s.itemRepo, s.customItemRepo, userID)
+		if err != nil {
+			logger.Error(ctx, "service: WishlistService.SetItemRecipe - error fetching custom items", "error", err)
+			return err
+		}
+		item, err := itemRepo.FindByUniqueName(ctx, uniqueName)
+		if err != nil {
+			logger.Error(ctx, "service: WishlistService.SetItemRecipe - error finding item", "error", err)
+			return err
+		}
+		if item == nil {
+			logger.Warn(ctx, "service: WishlistService.SetItemRecipe - item not found", "uniqueName", uniqueName)
+			return ErrItemNotFound
+		}
+		if _, ok := item.WithRecipe(recipeID); !ok {
+			logger.Warn(ctx, "service: WishlistService.SetItemRecipe - recipe not found", "uniqueName", uniqueName, "recipeID", recipeID)
+			return ErrRecipeNotFound
+		}
+	}
+
+	if err := s.wishlistRepo.SetItemRecipe(ctx, userID, uniqueName, recipeID); err != nil {
+		logger.Error(ctx, "service: WishlistService.SetItemRecipe - error saving recipe", "error", err)
+		return err
+	}
+	invalidateMaterials(ctx, s.materialsCache, userID)
+	logger.Info(ctx, "service: WishlistService.SetItemRecipe - recipe updated", "uniqueName", uniqueName, "recipeID", recipeID)
+	return nil
+}
+
+// SetItemProgress replaces the component progress on a wishlist item. Each
+// entry must name one of the item's components under its current recipe; an
+// empty list clears the progress.
+func (s *WishlistService) SetItemProgress(ctx context.Context, userID, uniqueName string, reqs []models.ComponentProgressRequest) (*models.WishlistItem, error) {
+	logger.Debug(ctx, "service: WishlistService.SetItemProgress called", "userID", userID, "uniqueName", uniqueName, "componentCount", len(reqs))
+
+	wishlist, err := s.wishlistRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "service: WishlistService.SetItemProgress - error fetching wishlist", "error", err)
+		return nil, err
+	}
+	wishlistItem := findWishlistItem(wishlist, uniqueName)
+	if wishlistItem == nil {
+		logger.Warn(ctx, "service: WishlistService.SetItemProgress - item not in wishlist", "uniqueName", uniqueName)
+		return nil, ErrItemNotInWishlist
+	}
+
+	itemRepo, err := itemsForUser(ctx, s.itemRepo, s.customItemRepo, userID)
+	if err != nil {
+		logger.Error(ctx, "service: WishlistService.SetItemProgress - error fetching custom items", "error", err)
+		return nil, err
+	}
+	item, err := itemRepo.FindByUniqueName(ctx, uniqueName)
+	if err != nil {
+		logger.Error(ctx, "service: WishlistService.SetItemProgress - error finding item", "error", err)
+		return nil, err
+	}
+	if item == nil {
+		logger.Warn(ctx, "service: WishlistService.SetItemProgress - item not found", "uniqueName", uniqueName)
+		return nil, ErrItemNotFound
+	}
+	item = recipeOrDefault(item, wishlistItem.RecipeID)
+
+	progress, err := buildComponentProgress(item, *wishlistItem, reqs)
+	if err != nil {
+		logger.Warn(ctx, "service: WishlistService.SetItemProgress - invalid progress", "error", err)
+		return nil, err
+	}
+	if err := s.wishlistRepo.SetItemProgress(ctx, userID, uniqueName, progress); err != nil {
+		logger.Error(ctx, "service: WishlistService.SetItemProgress - error saving progress", "error", err)
+		return nil, err
+	}
+	invalidateMaterials(ctx, s.materialsCache, userID)
+
+	wishlistItem.Progress = progress
+	wishlistItem.Completion = itemCompletion(item, *wishlistItem)
+	logger.Info(ctx, "service: WishlistService.SetItemProgress - progress updated", "uniqueName", uniqueName, "componentCount", len(progress), "completion", wishlistItem.Completion)
+	return wishlistItem, nil
+}
+
+// GetExpandedWishlist returns the wishlist with each item's summary and
+// source links attached. Items removed from the game are flagged archived;
+// items missing from the game data entirely keep a nil summary.
+func (s *WishlistService) GetExpandedWishlist(ctx context.Context, userID string) (*models.ExpandedWishlist, error) {
+	logger.Debug(ctx, "service: WishlistService.GetExpandedWishlist called", "userID", userID)
+
+	wishlist, err := s.GetWishlist(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	uniqueNames := make([]string, len(wishlist.Items))
+	for i, wi := range wishlist.Items {
+		uniqueNames[i] = wi.UniqueName
+	}
+	itemRepo, err := itemsForUser(ctx, s.itemRepo, s.customItemRepo, userID)
+	if err != nil {
+		logger.Error(ctx, "service: WishlistService.GetExpandedWishlist - error fetching custom items", "error", err)
+		return nil, err
+	}
+	items, err := itemRepo.FindByUniqueNames(ctx, uniqueNames)
+	if err != nil {
+		logger.Error(ctx, "service: WishlistService.GetExpandedWishlist - error finding items", "error", err)
+		return nil, err
+	}
+
+	expanded := &models.ExpandedWishlist{
+		UserID:    wishlist.UserID,
+		Items:     make([]models.ExpandedWishlistItem, len(wishlist.Items)),
+		CreatedAt: wishlist.CreatedAt,
+		UpdatedAt: wishlist.UpdatedAt,
+	}
+	for i, wi := range wishlist.Items {
+		expanded.Items[i] = models.ExpandedWishlistItem{WishlistItem: wi}
+		if item := items[wi.UniqueName]; item != nil {
+			expanded.Items[i].Item = &models.ItemSearchResult{
+				UniqueName:  item.UniqueName,
+				Name:        item.Name,
+				Description: item.Description,
+				Category:    item.Category,
+				ImageName:   item.ImageName,
+				Archived:    item.Archived,
+			}
+		}
+	}
+
+	logger.Debug(ctx, "service: WishlistService.GetExpandedWishlist - completed", "itemCount", len(expanded.Items), "resolved", len(items))
+	return expanded, nil
+}
+
+func findWishlistItem(wishlist *models.Wishlist, uniqueName string) *models.WishlistItem {
+	if wishlist == nil {
+		return nil
+	}
+	for i := range wishlist.Items {
+		if wishlist.Items[i].UniqueName == uniqueName {
+			return &wishlist.Items[i]
+		}
+	}
+	return nil
+}
+
+func containsItem(wishlist *models.Wishlist, uniqueName string) bool {
+	if wishlist == nil {
+		return false
+	}
+	for _, wi := range wishlist.Items {
+		if wi.UniqueName == uniqueName {
+			return true
+		}
+	}
+	return false
+}
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/graytonio/warframe-wishlist/internal/hooks"
 	"github.com/graytonio/warframe-wishlist/internal/mocks"
 	"github.com/graytonio/warframe-wishlist/internal/models"
 )
@@ -423,3 +424,68 @@ func TestWishlistService_AddItem_WithTimestamp(t *testing.T) {
 		t.Error("AddedAt timestamp should be set to current time")
 	}
 }
+
+func TestWishlistService_AddItem_MaxItems(t *testing.T) {
+	wishlistRepo := &mocks.MockWishlistRepository{
+		GetByUserIDFunc: func(ctx context.Context, userID string) (*models.Wishlist, error) {
+			return &models.Wishlist{UserID: userID, Items: []models.WishlistItem{{UniqueName: "/Lotus/A"}, {UniqueName: "/Lotus/B"}}}, nil
+		},
+	}
+	itemRepo := &mocks.MockItemRepository{
+		FindByUniqueNameFunc: func(ctx context.Context, uniqueName string) (*models.Item, error) {
+			return &models.Item{UniqueName: uniqueName}, nil
+		},
+	}
+	service := NewWishlistService(wishlistRepo, itemRepo)
+
+	service.SetMaxItems(2)
+	err := service.AddItem(context.Background(), "user-123", models.AddItemRequest{UniqueName: "/Lotus/C", Quantity: 1})
+	if !errors.Is(err, ErrWishlistFull) {
+		t.Errorf("expected ErrWishlistFull, got %v", err)
+	}
+	if err := service.AddItem(context.Background(), "user-123", models.AddItemRequest{UniqueName: "/Lotus/A", Quantity: 1}); !errors.Is(err, ErrItemAlreadyInWishlist) {
+		t.Errorf("expected a duplicate to still report ErrItemAlreadyInWishlist, got %v", err)
+	}
+
+	service.SetMaxItems(3)
+	if err := service.AddItem(context.Background(), "user-123", models.AddItemRequest{UniqueName: "/Lotus/C", Quantity: 1}); err != nil {
+		t.Errorf("expected the add under the cap to succeed, got %v", err)
+	}
+}
+
+func TestWishlistService_AddItem_RaisesHook(t *testing.T) {
+	var events []hooks.Event
+	registry := hooks.NewRegistry()
+	registry.Register("recorder", hooks.EventItemAddedToWishlist, func(ctx context.Context, event hooks.Event) (any, error) {
+		events = append(events, event)
+		return nil, nil
+	})
+
+	wishlistRepo := &mocks.MockWishlistRepository{
+		GetByUserIDFunc: func(ctx context.Context, userID string) (*models.Wishlist, error) {
+			return &models.Wishlist{UserID: userID, Items: []models.WishlistItem{{UniqueName: "/Lotus/A"}}}, nil
+		},
+		AddItemFunc: func(ctx context.Context, userID string, item models.WishlistItem) error { return nil },
+	}
+	itemRepo := &mocks.MockItemRepository{
+		FindByUniqueNameFunc: func(ctx context.Context, uniqueName string) (*models.Item, error) {
+			return &models.Item{UniqueName: uniqueName}, nil
+		},
+	}
+	service := NewWishlistService(wishlistRepo, itemRepo)
+	service.SetHooks(registry)
+
+	if err := service.AddItem(context.Background(), "user-123", models.AddItemRequest{UniqueName: "/Lotus/B", Quantity: 2}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := service.AddItem(context.Background(), "user-123", models.AddItemRequest{UniqueName: "/Lotus/A", Quantity: 1}); !errors.Is(err, ErrItemAlreadyInWishlist) {
+		t.Fatalf("expected ErrItemAlreadyInWishlist, got %v", err)
+	}
+
+	if len(events) != 1 {
+		t.Fatalf("expected one event for the successful add, got %+v", events)
+	}
+	if added, ok := events[0].Data.(hooks.ItemAdded); !ok || events[0].UserID != "user-123" || added.UniqueName != "/Lotus/B" || added.Quantity != 2 {
+		t.Errorf("unexpected event %+v", events[0])
+	}
+}
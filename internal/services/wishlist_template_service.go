@@ -0,0 +1,387 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// maxTemplateNameLength bounds template names and variable labels.
+const maxTemplateNameLength = 100
+
+var (
+	ErrWishlistTemplateNotFound = errors.New("wishlist template not found")
+	ErrTooManyWishlistTemplates = fmt.Errorf("at most %d wishlist templates per user", models.MaxWishlistTemplates)
+	ErrInvalidWishlistTemplate  = errors.New("invalid wishlist template")
+	ErrInvalidTemplateVariables = errors.New("invalid template variables")
+)
+
+var templateVariableNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]{0,31}$`)
+
+// WishlistTemplateService keeps users' wishlist templates and applies them.
+// Applying a template expands every item's quantity with the values given
+// for its variables and adds the items through the import service, so
+// household approvals and per-item outcomes match a confirmed text import.
+type WishlistTemplateService struct {
+	templateRepo   repository.WishlistTemplateRepositoryInterface
+	itemRepo       repository.ItemRepositoryInterface
+	customItemRepo repository.CustomItemRepositoryInterface
+	importService  WishlistImportServiceInterface
+	now            func() time.Time
+}
+
+func NewWishlistTemplateService(templateRepo repository.WishlistTemplateRepositoryInterface, itemRepo repository.ItemRepositoryInterface, importService WishlistImportServiceInterface) *WishlistTemplateService {
+	return &WishlistTemplateService{
+		templateRepo:  templateRepo,
+		itemRepo:      itemRepo,
+		importService: importService,
+		now:           time.Now,
+	}
+}
+
+// SetCustomItemRepository lets templates include the user's custom items.
+func (s *WishlistTemplateService) SetCustomItemRepository(customItemRepo repository.CustomItemRepositoryInterface) {
+	s.customItemRepo = customItemRepo
+}
+
+// ListTemplates returns the user's templates, oldest first.
+func (s *WishlistTemplateService) ListTemplates(ctx context.Context, userID string) ([]models.WishlistTemplate, error) {
+	logger.Debug(ctx, "service: WishlistTemplateService.ListTemplates called", "userID", userID)
+
+	templates, err := s.templateRepo.ListByUser(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "service: WishlistTemplateService.ListTemplates - error fetching templates", "error", err)
+		return nil, err
+	}
+
+	logger.Debug(ctx, "service: WishlistTemplateService.ListTemplates - completed", "count", len(templates))
+	return templates, nil
+}
+
+// CreateTemplate stores a template after checking its variables and items.
+// Every bad field is reported in one ValidationError.
+func (s *WishlistTemplateService) CreateTemplate(ctx context.Context, userID string, req models.WishlistTemplateRequest) (*models.WishlistTemplate, error) {
+	logger.Debug(ctx, "service: WishlistTemplateService.CreateTemplate called", "userID", userID, "name", req.Name, "items", len(req.Items))
+
+	template, err := s.validateTemplate(ctx, userID, req)
+	if err != nil {
+		logger.Warn(ctx, "service: WishlistTemplateService.CreateTemplate - invalid template", "error", err)
+		return nil, err
+	}
+
+	existing, err := s.templateRepo.ListByUser(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "service: WishlistTemplateService.CreateTemplate - error fetching templates", "error", err)
+		return nil, err
+	}
+	if len(existing) >= models.MaxWishlistTemplates {
+		logger.Warn(ctx, "service: WishlistTemplateService.CreateTemplate - too many templates", "count", len(existing))
+		return nil, ErrTooManyWishlistTemplates
+	}
+
+	template.UserID = userID
+	template.CreatedAt = s.now()
+	if err := s.templateRepo.Create(ctx, template); err != nil {
+		logger.Error(ctx, "service: WishlistTemplateService.CreateTemplate - error storing template", "error", err)
+		return nil, err
+	}
+
+	logger.Info(ctx, "service: WishlistTemplateService.CreateTemplate - template created", "id", template.ID.Hex(), "items", len(template.Items))
+	return template, nil
+}
+
+// DeleteTemplate removes one of the user's templates.
+func (s *WishlistTemplateService) DeleteTemplate(ctx context.Context, userID, id string) error {
+	logger.Debug(ctx, "service: WishlistTemplateService.DeleteTemplate called", "userID", userID, "id", id)
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		logger.Warn(ctx, "service: WishlistTemplateService.DeleteTemplate - invalid template ID", "id", id)
+		return ErrWishlistTemplateNotFound
+	}
+	deleted, err := s.templateRepo.Delete(ctx, userID, objectID)
+	if err != nil {
+		logger.Error(ctx, "service: WishlistTemplateService.DeleteTemplate - error deleting template", "error", err)
+		return err
+	}
+	if !deleted {
+		logger.Warn(ctx, "service: WishlistTemplateService.DeleteTemplate - template not found", "id", id)
+		return ErrWishlistTemplateNotFound
+	}
+
+	logger.Info(ctx, "service: WishlistTemplateService.DeleteTemplate - template removed", "id", id)
+	return nil
+}
+
+// ApplyTemplate adds the template's items to the user's wishlist, with
+// quantities worked out from variables. A variable left out takes its
+// default. Unknown, missing or out of range variables, and quantities past
+// models.MaxTemplateQuantity, are reported in one ValidationError before
+// anything is added. Items whose quantity works out to 0 are skipped.
+func (s *WishlistTemplateService) ApplyTemplate(ctx context.Context, userID, id string, variables map[string]int) (*models.ImportConfirmResult, error) {
+	logger.Debug(ctx, "service: WishlistTemplateService.ApplyTemplate called", "userID", userID, "id", id, "variables", len(variables))
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		logger.Warn(ctx, "service: WishlistTemplateService.ApplyTemplate - invalid template ID", "id", id)
+		return nil, ErrWishlistTemplateNotFound
+	}
+	template, err := s.templateRepo.Get(ctx, userID, objectID)
+	if err != nil {
+		logger.Error(ctx, "service: WishlistTemplateService.ApplyTemplate - error fetching template", "error", err)
+		return nil, err
+	}
+	if template == nil {
+		logger.Warn(ctx, "service: WishlistTemplateService.ApplyTemplate - template not found", "id", id)
+		return nil, ErrWishlistTemplateNotFound
+	}
+
+	items, err := expandTemplate(template, variables)
+	if err != nil {
+		logger.Warn(ctx, "service: WishlistTemplateService.ApplyTemplate - invalid variables", "error", err)
+		return nil, err
+	}
+	if len(items) == 0 {
+		logger.Warn(ctx, "service: WishlistTemplateService.ApplyTemplate - every quantity is zero", "id", id)
+		return nil, ErrImportEmpty
+	}
+
+	result, err := s.importService.Confirm(ctx, userID, models.ImportConfirmRequest{Items: items})
+	if err != nil {
+		logger.Error(ctx, "service: WishlistTemplateService.ApplyTemplate - error adding items", "error", err)
+		return nil, err
+	}
+
+	logger.Info(ctx, "service: WishlistTemplateService.ApplyTemplate - template applied", "id", id, "count", len(result.Results))
+	return result, nil
+}
+
+// validateTemplate returns the template req describes, with names trimmed,
+// unique names canonical, variable maxima filled in and quantities
+// normalized.
+func (s *WishlistTemplateService) validateTemplate(ctx context.Context, userID string, req models.WishlistTemplateRequest) (*models.WishlistTemplate, error) {
+	var problems ValidationError
+	template := &models.WishlistTemplate{
+		Name:      strings.TrimSpace(req.Name),
+		Variables: make([]models.TemplateVariable, 0, len(req.Variables)),
+		Items:     make([]models.TemplateItem, 0, len(req.Items)),
+	}
+
+	switch {
+	case template.Name == "":
+		problems.Add("name", "", fmt.Errorf("%w: name is required", ErrInvalidWishlistTemplate))
+	case len([]rune(template.Name)) > maxTemplateNameLength:
+		problems.Add("name", "", fmt.Errorf("%w: name must have at most %d characters", ErrInvalidWishlistTemplate, maxTemplateNameLength))
+	}
+
+	if len(req.Variables) > models.MaxTemplateVariables {
+		problems.Add("variables", "", fmt.Errorf("%w: at most %d variables", ErrInvalidWishlistTemplate, models.MaxTemplateVariables))
+		return nil, problems.Err()
+	}
+	declared := make(map[string]bool, len(req.Variables))
+	for i, variable := range req.Variables {
+		variable.Name = strings.TrimSpace(variable.Name)
+		variable.Label = strings.TrimSpace(variable.Label)
+		if variable.Max == 0 {
+			variable.Max = models.MaxTemplateQuantity
+		}
+		err := validateTemplateVariable(variable, declared)
+		// A well named variable counts as declared even with a bad range,
+		// so the items using it aren't reported too.
+		if templateVariableNamePattern.MatchString(variable.Name) {
+			declared[variable.Name] = true
+		}
+		if err != nil {
+			problems.Add(fmt.Sprintf("variables[%d]", i), "", err)
+			continue
+		}
+		template.Variables = append(template.Variables, variable)
+	}
+
+	switch {
+	case len(req.Items) == 0:
+		problems.Add("items", "", fmt.Errorf("%w: at least one item is required", ErrInvalidWishlistTemplate))
+	case len(req.Items) > models.MaxTemplateItems:
+		problems.Add("items", "", fmt.Errorf("%w: at most %d items", ErrInvalidWishlistTemplate, models.MaxTemplateItems))
+		return nil, problems.Err()
+	}
+
+	itemRepo, err := itemsForUser(ctx, s.itemRepo, s.customItemRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool, len(req.Items))
+	for i, item := range req.Items {
+		uniqueName, err := models.CanonicalUniqueName(item.UniqueName)
+		if err != nil {
+			problems.Add(fmt.Sprintf("items[%d].uniqueName", i), item.UniqueName, err)
+			continue
+		}
+		if seen[uniqueName] {
+			problems.Add(fmt.Sprintf("items[%d].uniqueName", i), uniqueName, fmt.Errorf("%w: %s is listed more than once", ErrInvalidWishlistTemplate, uniqueName))
+			continue
+		}
+		seen[uniqueName] = true
+
+		found, err := itemRepo.FindByUniqueName(ctx, uniqueName)
+		if err != nil {
+			return nil, err
+		}
+		if found == nil {
+			problems.Add(fmt.Sprintf("items[%d].uniqueName", i), uniqueName, ErrItemNotFound)
+			continue
+		}
+
+		quantity, err := normalizeTemplateQuantity(item.Quantity, declared)
+		if err != nil {
+			problems.Add(fmt.Sprintf("items[%d].quantity", i), uniqueName, err)
+			continue
+		}
+		template.Items = append(template.Items, models.TemplateItem{UniqueName: uniqueName, Quantity: quantity})
+	}
+
+	if err := problems.Err(); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+func validateTemplateVariable(variable models.TemplateVariable, declared map[string]bool) error {
+	switch {
+	case !templateVariableNamePattern.MatchString(variable.Name):
+		return fmt.Errorf("%w: variable names are a letter followed by up to 31 letters, digits or underscores", ErrInvalidWishlistTemplate)
+	case declared[variable.Name]:
+		return fmt.Errorf("%w: variable %s is declared more than once", ErrInvalidWishlistTemplate, variable.Name)
+	case len([]rune(variable.Label)) > maxTemplateNameLength:
+		return fmt.Errorf("%w: label must have at most %d characters", ErrInvalidWishlistTemplate, maxTemplateNameLength)
+	case variable.Min < 0 || variable.Max > models.MaxTemplateQuantity || variable.Min > variable.Max:
+		return fmt.Errorf("%w: %s must range within 0 to %d", ErrInvalidWishlistTemplate, variable.Name, models.MaxTemplateQuantity)
+	case variable.Default != nil && (*variable.Default < variable.Min || *variable.Default > variable.Max):
+		return fmt.Errorf("%w: default of %s must be between %d and %d", ErrInvalidWishlistTemplate, variable.Name, variable.Min, variable.Max)
+	}
+	return nil
+}
+
+// normalizeTemplateQuantity checks a quantity expression against the
+// declared variables and returns it without spaces. An empty expression is
+// "1".
+func normalizeTemplateQuantity(expr string, declared map[string]bool) (string, error) {
+	factors, err := parseTemplateQuantity(expr)
+	if err != nil {
+		return "", err
+	}
+	for _, factor := range factors {
+		if _, err := strconv.Atoi(factor); err != nil && !declared[factor] {
+			return "", fmt.Errorf("%w: quantity uses undeclared variable %s", ErrInvalidWishlistTemplate, factor)
+		}
+	}
+	return strings.Join(factors, "*"), nil
+}
+
+// parseTemplateQuantity splits a quantity expression into its factors, each
+// a whole number up to models.MaxTemplateQuantity or a variable name.
+func parseTemplateQuantity(expr string) ([]string, error) {
+	if strings.TrimSpace(expr) == "" {
+		return []string{"1"}, nil
+	}
+	factors := strings.Split(expr, "*")
+	for i, factor := range factors {
+		factor = strings.TrimSpace(factor)
+		if n, err := strconv.Atoi(factor); err == nil {
+			if n < 0 || n > models.MaxTemplateQuantity {
+				return nil, fmt.Errorf("%w: quantity numbers must be between 0 and %d", ErrInvalidWishlistTemplate, models.MaxTemplateQuantity)
+			}
+			factor = strconv.Itoa(n)
+		} else if !templateVariableNamePattern.MatchString(factor) {
+			return nil, fmt.Errorf("%w: quantity must be whole numbers and variable names joined by *", ErrInvalidWishlistTemplate)
+		}
+		factors[i] = factor
+	}
+	return factors, nil
+}
+
+// expandTemplate returns the items template adds given variables, dropping
+// those whose quantity is 0.
+func expandTemplate(template *models.WishlistTemplate, variables map[string]int) ([]models.AddItemRequest, error) {
+	var problems ValidationError
+	values := make(map[string]int, len(template.Variables))
+	for _, variable := range template.Variables {
+		field := "variables." + variable.Name
+		value, ok := variables[variable.Name]
+		switch {
+		case !ok && variable.Default == nil:
+			problems.Add(field, "", fmt.Errorf("%w: %s is required", ErrInvalidTemplateVariables, variable.Name))
+			continue
+		case !ok:
+			value = *variable.Default
+		case value < variable.Min || value > variable.Max:
+			problems.Add(field, "", fmt.Errorf("%w: %s must be between %d and %d", ErrInvalidTemplateVariables, variable.Name, variable.Min, variable.Max))
+			continue
+		}
+		values[variable.Name] = value
+	}
+	for _, name := range slices.Sorted(maps.Keys(variables)) {
+		if !templateDeclares(template, name) {
+			problems.Add("variables."+name, "", fmt.Errorf("%w: the template has no variable %s", ErrInvalidTemplateVariables, name))
+		}
+	}
+	if err := problems.Err(); err != nil {
+		return nil, err
+	}
+
+	items := make([]models.AddItemRequest, 0, len(template.Items))
+	for i, item := range template.Items {
+		quantity, err := evaluateTemplateQuantity(item.Quantity, values)
+		if err != nil {
+			problems.Add(fmt.Sprintf("items[%d].quantity", i), item.UniqueName, err)
+			continue
+		}
+		if quantity == 0 {
+			continue
+		}
+		items = append(items, models.AddItemRequest{UniqueName: item.UniqueName, Quantity: quantity})
+	}
+	if err := problems.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func templateDeclares(template *models.WishlistTemplate, name string) bool {
+	return slices.ContainsFunc(template.Variables, func(variable models.TemplateVariable) bool {
+		return variable.Name == name
+	})
+}
+
+// evaluateTemplateQuantity multiplies out a stored quantity expression.
+func evaluateTemplateQuantity(expr string, values map[string]int) (int, error) {
+	factors, err := parseTemplateQuantity(expr)
+	if err != nil {
+		return 0, err
+	}
+	quantity := 1
+	for _, factor := range factors {
+		n, err := strconv.Atoi(factor)
+		if err != nil {
+			n = values[factor]
+		}
+		// Both factors are at most MaxTemplateQuantity, so the product
+		// cannot overflow before it is checked.
+		quantity *= n
+		if quantity > models.MaxTemplateQuantity {
+			return 0, fmt.Errorf("%w: quantity %s works out to more than %d", ErrInvalidTemplateVariables, expr, models.MaxTemplateQuantity)
+		}
+	}
+	return quantity, nil
+}
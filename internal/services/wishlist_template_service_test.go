@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository/memory"
+)
+
+// newTemplateFixture returns a template service whose applied items are
+// recorded in confirmed.
+func newTemplateFixture(confirmed *[]models.AddItemRequest) *WishlistTemplateService {
+	items := memory.NewItemRepository()
+	items.Add("misc",
+		models.Item{UniqueName: "/Lotus/Forma", Name: "Forma"},
+		models.Item{UniqueName: "/Lotus/Catalyst", Name: "Orokin Catalyst"},
+		models.Item{UniqueName: "/Lotus/Reactor", Name: "Orokin Reactor"},
+	)
+	importService := &mocks.MockWishlistImportService{
+		ConfirmFunc: func(ctx context.Context, userID string, req models.ImportConfirmRequest) (*models.ImportConfirmResult, error) {
+			*confirmed = append(*confirmed, req.Items...)
+			result := &models.ImportConfirmResult{}
+			for _, item := range req.Items {
+				result.Results = append(result.Results, models.ImportItemResult{UniqueName: item.UniqueName, Quantity: item.Quantity, Status: models.ImportStatusAdded})
+			}
+			return result, nil
+		},
+	}
+	return NewWishlistTemplateService(memory.NewWishlistTemplateRepository(), items, importService)
+}
+
+func newFrameTemplate() models.WishlistTemplateRequest {
+	return models.WishlistTemplateRequest{
+		Name: " New frame ",
+		Variables: []models.TemplateVariable{
+			{Name: "frames", Label: "Frames", Min: 1, Max: 10},
+			{Name: "forma", Label: "Forma per frame", Default: intPtr(3)},
+		},
+		Items: []models.TemplateItem{
+			{UniqueName: "/Lotus/Forma", Quantity: "frames * forma"},
+			{UniqueName: "/Lotus/Reactor", Quantity: "frames"},
+			{UniqueName: "/Lotus/Catalyst"},
+		},
+	}
+}
+
+func TestWishlistTemplateService_CreateAndApply(t *testing.T) {
+	var confirmed []models.AddItemRequest
+	service := newTemplateFixture(&confirmed)
+	ctx := context.Background()
+
+	template, err := service.CreateTemplate(ctx, "user-123", newFrameTemplate())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if template.Name != "New frame" || template.ID.IsZero() {
+		t.Errorf("expected a stored, trimmed template, got %+v", template)
+	}
+	if template.Items[0].Quantity != "frames*forma" || template.Items[2].Quantity != "1" {
+		t.Errorf("expected normalized quantities, got %+v", template.Items)
+	}
+	if template.Variables[1].Max != models.MaxTemplateQuantity {
+		t.Errorf("expected a missing max to be filled in, got %+v", template.Variables[1])
+	}
+
+	result, err := service.ApplyTemplate(ctx, "user-123", template.ID.Hex(), map[string]int{"frames": 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []models.AddItemRequest{
+		{UniqueName: "/Lotus/Forma", Quantity: 6},
+		{UniqueName: "/Lotus/Reactor", Quantity: 2},
+		{UniqueName: "/Lotus/Catalyst", Quantity: 1},
+	}
+	if !reflect.DeepEqual(confirmed, expected) {
+		t.Errorf("expected %+v to be added, got %+v", expected, confirmed)
+	}
+	if len(result.Results) != 3 {
+		t.Errorf("expected a result per item, got %+v", result.Results)
+	}
+
+	confirmed = nil
+	if _, err := service.ApplyTemplate(ctx, "user-123", template.ID.Hex(), map[string]int{"frames": 1, "forma": 0}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(confirmed) != 2 || confirmed[0].UniqueName != "/Lotus/Reactor" {
+		t.Errorf("expected an item expanding to 0 to be skipped, got %+v", confirmed)
+	}
+
+	if _, err := service.ApplyTemplate(ctx, "other-user", template.ID.Hex(), map[string]int{"frames": 1}); !errors.Is(err, ErrWishlistTemplateNotFound) {
+		t.Errorf("expected another user's template to be hidden, got %v", err)
+	}
+}
+
+func TestWishlistTemplateService_ApplyVariableErrors(t *testing.T) {
+	var confirmed []models.AddItemRequest
+	service := newTemplateFixture(&confirmed)
+	ctx := context.Background()
+
+	template, err := service.CreateTemplate(ctx, "user-123", newFrameTemplate())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		variables map[string]int
+		fields    []string
+	}{
+		{name: "missing required variable", variables: map[string]int{"forma": 2}, fields: []string{"variables.frames"}},
+		{name: "below min", variables: map[string]int{"frames": 0}, fields: []string{"variables.frames"}},
+		{name: "above max", variables: map[string]int{"frames": 11}, fields: []string{"variables.frames"}},
+		{name: "unknown variables", variables: map[string]int{"frames": 1, "zeta": 1, "alpha": 1}, fields: []string{"variables.alpha", "variables.zeta"}},
+		{name: "quantity too large", variables: map[string]int{"frames": 10, "forma": 5000}, fields: []string{"items[0].quantity"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.ApplyTemplate(ctx, "user-123", template.ID.Hex(), tt.variables)
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) || !errors.Is(err, ErrInvalidTemplateVariables) {
+				t.Fatalf("expected a template variables ValidationError, got %v", err)
+			}
+			var fields []string
+			for _, problem := range validationErr.Problems {
+				fields = append(fields, problem.Field)
+			}
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("expected problems with %v, got %v", tt.fields, fields)
+			}
+		})
+	}
+	if len(confirmed) != 0 {
+		t.Errorf("expected nothing added on a bad request, got %+v", confirmed)
+	}
+
+	if _, err := service.ApplyTemplate(ctx, "user-123", "not-an-id", nil); !errors.Is(err, ErrWishlistTemplateNotFound) {
+		t.Errorf("expected ErrWishlistTemplateNotFound for a malformed ID, got %v", err)
+	}
+}
+
+func TestWishlistTemplateService_CreateValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(req *models.WishlistTemplateRequest)
+		fields []string
+	}{
+		{name: "blank name", modify: func(req *models.WishlistTemplateRequest) { req.Name = " " }, fields: []string{"name"}},
+		{name: "bad variable name", modify: func(req *models.WishlistTemplateRequest) { req.Variables[0].Name = "2frames" }, fields: []string{"variables[0]", "items[0].quantity", "items[1].quantity"}},
+		{name: "duplicate variable", modify: func(req *models.WishlistTemplateRequest) { req.Variables[1].Name = "frames" }, fields: []string{"variables[1]", "items[0].quantity"}},
+		{name: "default out of range", modify: func(req *models.WishlistTemplateRequest) { req.Variables[0].Default = intPtr(20) }, fields: []string{"variables[0]"}},
+		{name: "min above max", modify: func(req *models.WishlistTemplateRequest) { req.Variables[0].Min = 20 }, fields: []string{"variables[0]"}},
+		{name: "no items", modify: func(req *models.WishlistTemplateRequest) { req.Items = nil }, fields: []string{"items"}},
+		{name: "unknown item", modify: func(req *models.WishlistTemplateRequest) { req.Items[1].UniqueName = "/Lotus/Missing" }, fields: []string{"items[1].uniqueName"}},
+		{name: "duplicate item", modify: func(req *models.WishlistTemplateRequest) { req.Items[2].UniqueName = "/Lotus/Forma" }, fields: []string{"items[2].uniqueName"}},
+		{name: "undeclared variable", modify: func(req *models.WishlistTemplateRequest) { req.Items[1].Quantity = "2*riven" }, fields: []string{"items[1].quantity"}},
+		{name: "malformed quantity", modify: func(req *models.WishlistTemplateRequest) { req.Items[1].Quantity = "frames+1" }, fields: []string{"items[1].quantity"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var confirmed []models.AddItemRequest
+			service := newTemplateFixture(&confirmed)
+			req := newFrameTemplate()
+			tt.modify(&req)
+
+			_, err := service.CreateTemplate(context.Background(), "user-123", req)
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("expected a ValidationError, got %v", err)
+			}
+			var fields []string
+			for _, problem := range validationErr.Problems {
+				fields = append(fields, problem.Field)
+			}
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("expected problems with %v, got %v", tt.fields, fields)
+			}
+			if templates, _ := service.ListTemplates(context.Background(), "user-123"); len(templates) != 0 {
+				t.Errorf("expected nothing stored, got %+v", templates)
+			}
+		})
+	}
+}
+
+func TestWishlistTemplateService_LimitsAndDelete(t *testing.T) {
+	var confirmed []models.AddItemRequest
+	service := newTemplateFixture(&confirmed)
+	ctx := context.Background()
+
+	var first *models.WishlistTemplate
+	for i := 0; i < models.MaxWishlistTemplates; i++ {
+		template, err := service.CreateTemplate(ctx, "user-123", newFrameTemplate())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if first == nil {
+			first = template
+		}
+	}
+	if _, err := service.CreateTemplate(ctx, "user-123", newFrameTemplate()); !errors.Is(err, ErrTooManyWishlistTemplates) {
+		t.Fatalf("expected ErrTooManyWishlistTemplates, got %v", err)
+	}
+
+	if err := service.DeleteTemplate(ctx, "other-user", first.ID.Hex()); !errors.Is(err, ErrWishlistTemplateNotFound) {
+		t.Errorf("expected another user's delete to miss, got %v", err)
+	}
+	if err := service.DeleteTemplate(ctx, "user-123", first.ID.Hex()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := service.DeleteTemplate(ctx, "user-123", first.ID.Hex()); !errors.Is(err, ErrWishlistTemplateNotFound) {
+		t.Errorf("expected ErrWishlistTemplateNotFound once deleted, got %v", err)
+	}
+	if _, err := service.CreateTemplate(ctx, "user-123", newFrameTemplate()); err != nil {
+		t.Errorf("expected room for a template after a delete, got %v", err)
+	}
+}
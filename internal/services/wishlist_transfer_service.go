@@ -0,0 +1,487 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+)
+
+// MaxWishlistExportEntries bounds both the items and the owned blueprints of
+// an imported document.
+const MaxWishlistExportEntries = 2000
+
+var (
+	ErrInvalidExportDocument    = errors.New("not a wishlist export document")
+	ErrUnsupportedExportVersion = fmt.Errorf("export documents newer than version %d are not supported", models.WishlistExportVersion)
+	ErrInvalidImportMode        = fmt.Errorf("mode must be %q or %q", models.ImportModeMerge, models.ImportModeReplace)
+	ErrExportTooLarge           = fmt.Errorf("an export document can list at most %d items and %d blueprints", MaxWishlistExportEntries, MaxWishlistExportEntries)
+)
+
+// WishlistTransferService exports a user's wishlist and owned blueprints as a
+// portable document and imports such a document back, merging it into or
+// replacing what the user has.
+//
+// An import is planned in full against the current state before anything is
+// written, so a dry run reports exactly what the import would do. Changes are
+// then applied through the wishlist and blueprint services, so household
+// approvals and materials cache invalidation still apply.
+type WishlistTransferService struct {
+	wishlistService   WishlistServiceInterface
+	blueprintsService OwnedBlueprintsServiceInterface
+	itemRepo          repository.ItemRepositoryInterface
+	// customItemRepo is optional; without it custom items in a document are
+	// reported not found.
+	customItemRepo repository.CustomItemRepositoryInterface
+	// materialResolver is optional; without it checklists list no materials.
+	materialResolver MaterialResolverInterface
+	now              func() time.Time
+}
+
+func NewWishlistTransferService(wishlistService WishlistServiceInterface, blueprintsService OwnedBlueprintsServiceInterface, itemRepo repository.ItemRepositoryInterface) *WishlistTransferService {
+	return &WishlistTransferService{
+		wishlistService:   wishlistService,
+		blueprintsService: blueprintsService,
+		itemRepo:          itemRepo,
+		now:               time.Now,
+	}
+}
+
+// SetCustomItemRepository lets imports restore the user's own custom items.
+// The custom items themselves are not part of the document.
+func (s *WishlistTransferService) SetCustomItemRepository(customItemRepo repository.CustomItemRepositoryInterface) {
+	s.customItemRepo = customItemRepo
+}
+
+// SetMaterialResolver makes checklists list the materials still needed.
+func (s *WishlistTransferService) SetMaterialResolver(materialResolver MaterialResolverInterface) {
+	s.materialResolver = materialResolver
+}
+
+func (s *WishlistTransferService) Export(ctx context.Context, userID string) (*models.WishlistExport, error) {
+	logger.Debug(ctx, "service: WishlistTransferService.Export called", "userID", userID)
+
+	wishlist, err := s.wishlistService.GetWishlist(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "service: WishlistTransferService.Export - error fetching wishlist", "error", err)
+		return nil, err
+	}
+	owned, err := s.blueprintsService.GetOwnedBlueprints(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "service: WishlistTransferService.Export - error fetching owned blueprints", "error", err)
+		return nil, err
+	}
+
+	doc := &models.WishlistExport{
+		Format:          models.WishlistExportFormat,
+		Version:         models.WishlistExportVersion,
+		ExportedAt:      s.now().UTC(),
+		Items:           make([]models.WishlistExportItem, 0, len(wishlist.Items)),
+		OwnedBlueprints: make([]string, 0, len(owned.Blueprints)),
+	}
+	for _, item := range wishlist.Items {
+		links := make([]models.SourceLinkRequest, 0, len(item.Links))
+		for _, link := range item.Links {
+			links = append(links, models.SourceLinkRequest{URL: link.URL, Title: link.Title})
+		}
+		doc.Items = append(doc.Items, models.WishlistExportItem{
+			UniqueName: item.UniqueName,
+			Quantity:   item.Quantity,
+			RecipeID:   item.RecipeID,
+			Links:      links,
+		})
+	}
+	for _, bp := range owned.Blueprints {
+		doc.OwnedBlueprints = append(doc.OwnedBlueprints, bp.UniqueName)
+	}
+
+	logger.Info(ctx, "service: WishlistTransferService.Export - completed", "itemCount", len(doc.Items), "blueprintCount", len(doc.OwnedBlueprints))
+	return doc, nil
+}
+
+// Checklist returns the user's wishlist as a printable checklist. Only the
+// materials with some still remaining are listed.
+func (s *WishlistTransferService) Checklist(ctx context.Context, userID string) (*models.WishlistChecklist, error) {
+	logger.Debug(ctx, "service: WishlistTransferService.Checklist called", "userID", userID)
+
+	wishlist, err := s.wishlistService.GetExpandedWishlist(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "service: WishlistTransferService.Checklist - error fetching wishlist", "error", err)
+		return nil, err
+	}
+
+	checklist := &models.WishlistChecklist{
+		GeneratedAt: s.now().UTC(),
+		Items:       make([]models.ChecklistItem, 0, len(wishlist.Items)),
+		Materials:   []models.MaterialRequirement{},
+	}
+	for _, item := range wishlist.Items {
+		name := item.UniqueName
+		if item.Item != nil && item.Item.Name != "" {
+			name = item.Item.Name
+		}
+		checklist.Items = append(checklist.Items, models.ChecklistItem{UniqueName: item.UniqueName, Name: name, Quantity: item.Quantity})
+	}
+
+	if s.materialResolver != nil && len(wishlist.Items) > 0 {
+		materials, err := s.materialResolver.GetMaterials(ctx, userID)
+		if err != nil {
+			logger.Error(ctx, "service: WishlistTransferService.Checklist - error resolving materials", "error", err)
+			return nil, err
+		}
+		if materials == nil {
+			materials = &models.MaterialsResponse{}
+		}
+		for _, material := range materials.Materials {
+			if material.Remaining > 0 {
+				checklist.Materials = append(checklist.Materials, material)
+			}
+		}
+		sort.Slice(checklist.Materials, func(i, j int) bool {
+			if checklist.Materials[i].Name != checklist.Materials[j].Name {
+				return checklist.Materials[i].Name < checklist.Materials[j].Name
+			}
+			return checklist.Materials[i].UniqueName < checklist.Materials[j].UniqueName
+		})
+		checklist.MaterialsTruncated = materials.Truncated
+	}
+
+	logger.Info(ctx, "service: WishlistTransferService.Checklist - completed", "itemCount", len(checklist.Items), "materialCount", len(checklist.Materials))
+	return checklist, nil
+}
+
+// Import applies req.Document to the user's wishlist and owned blueprints.
+// Items and blueprints the document lists are added, or updated to the
+// document's quantity, links and recipe; in replace mode everything else is
+// removed. Entries that are invalid or unknown are reported and skipped.
+// Additions held for approval are reported as pending; any other error aborts
+// the import part way, and running it again finishes it.
+func (s *WishlistTransferService) Import(ctx context.Context, userID string, req models.WishlistDocumentImportRequest) (*models.WishlistDocumentImportResult, error) {
+	logger.Debug(ctx, "service: WishlistTransferService.Import called", "userID", userID, "mode", req.Mode, "dryRun", req.DryRun)
+
+	mode := req.Mode
+	if mode == "" {
+		mode = models.ImportModeMerge
+	}
+	if mode != models.ImportModeMerge && mode != models.ImportModeReplace {
+		logger.Warn(ctx, "service: WishlistTransferService.Import - invalid mode", "mode", req.Mode)
+		return nil, ErrInvalidImportMode
+	}
+	doc := req.Document
+	if doc.Format != models.WishlistExportFormat || doc.Version < 1 {
+		logger.Warn(ctx, "service: WishlistTransferService.Import - not an export document", "format", doc.Format, "version", doc.Version)
+		return nil, ErrInvalidExportDocument
+	}
+	if doc.Version > models.WishlistExportVersion {
+		logger.Warn(ctx, "service: WishlistTransferService.Import - unsupported version", "version", doc.Version)
+		return nil, ErrUnsupportedExportVersion
+	}
+	if len(doc.Items) > MaxWishlistExportEntries || len(doc.OwnedBlueprints) > MaxWishlistExportEntries {
+		logger.Warn(ctx, "service: WishlistTransferService.Import - document too large", "itemCount", len(doc.Items), "blueprintCount", len(doc.OwnedBlueprints))
+		return nil, ErrExportTooLarge
+	}
+
+	plan, err := s.plan(ctx, userID, mode, doc)
+	if err != nil {
+		return nil, err
+	}
+	plan.result.DryRun = req.DryRun
+	if !req.DryRun {
+		if err := s.apply(ctx, userID, plan); err != nil {
+			return nil, err
+		}
+	}
+
+	logger.Info(ctx, "service: WishlistTransferService.Import - completed", "mode", mode, "dryRun", req.DryRun, "itemCount", len(plan.result.Items), "blueprintCount", len(plan.result.Blueprints))
+	return plan.result, nil
+}
+
+// transferPlan is an import, or a merge, worked out against the current
+// state. Each change points at the result entry it reports on; op names the
+// operation in logs.
+type transferPlan struct {
+	op               string
+	result           *models.WishlistDocumentImportResult
+	changes          []transferChange
+	addBlueprints    []string
+	removeBlueprints []string
+}
+
+type transferChange struct {
+	index int
+	add   bool
+	// quantity is the quantity to add or set, or 0 to leave it alone.
+	quantity  int
+	remove    bool
+	setLinks  bool
+	links     []models.SourceLinkRequest
+	setRecipe bool
+	recipeID  string
+}
+
+func (s *WishlistTransferService) plan(ctx context.Context, userID, mode string, doc models.WishlistExport) (*transferPlan, error) {
+	wishlist, err := s.wishlistService.GetWishlist(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "service: WishlistTransferService.Import - error fetching wishlist", "error", err)
+		return nil, err
+	}
+	owned, err := s.blueprintsService.GetOwnedBlueprints(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "service: WishlistTransferService.Import - error fetching owned blueprints", "error", err)
+		return nil, err
+	}
+
+	// Canonicalize every name once, then look the valid ones up together.
+	itemNames := make([]string, len(doc.Items))
+	blueprintNames := make([]string, len(doc.OwnedBlueprints))
+	var lookup []string
+	for i, item := range doc.Items {
+		if name, err := models.CanonicalUniqueName(item.UniqueName); err == nil {
+			itemNames[i] = name
+			lookup = append(lookup, name)
+		}
+	}
+	for i, raw := range doc.OwnedBlueprints {
+		if name, err := models.CanonicalUniqueName(raw); err == nil {
+			blueprintNames[i] = name
+			lookup = append(lookup, name)
+		}
+	}
+	catalog := map[string]*models.Item{}
+	if len(lookup) > 0 {
+		itemRepo, err := itemsForUser(ctx, s.itemRepo, s.customItemRepo, userID)
+		if err != nil {
+			logger.Error(ctx, "service: WishlistTransferService.Import - error fetching custom items", "error", err)
+			return nil, err
+		}
+		catalog, err = itemRepo.FindByUniqueNames(ctx, lookup)
+		if err != nil {
+			logger.Error(ctx, "service: WishlistTransferService.Import - error finding items", "error", err)
+			return nil, err
+		}
+	}
+
+	plan := &transferPlan{
+		op: "Import",
+		result: &models.WishlistDocumentImportResult{
+			Mode:       mode,
+			Items:      []models.ImportItemResult{},
+			Blueprints: []models.BlueprintImportResult{},
+		},
+	}
+	current := make(map[string]models.WishlistItem, len(wishlist.Items))
+	for _, item := range wishlist.Items {
+		current[item.UniqueName] = item
+	}
+
+	listed := make(map[string]bool, len(doc.Items))
+	for i, item := range doc.Items {
+		name := itemNames[i]
+		if name != "" {
+			if listed[name] {
+				continue
+			}
+			listed[name] = true
+		}
+
+		itemResult := models.ImportItemResult{UniqueName: item.UniqueName, Quantity: item.Quantity}
+		change, status := s.planItem(name, item, current, catalog)
+		if name != "" {
+			itemResult.UniqueName = name
+		}
+		itemResult.Status = status
+		if change != nil {
+			change.index = len(plan.result.Items)
+			plan.changes = append(plan.changes, *change)
+		}
+		plan.result.Items = append(plan.result.Items, itemResult)
+	}
+	if mode == models.ImportModeReplace {
+		for _, item := range wishlist.Items {
+			if listed[item.UniqueName] {
+				continue
+			}
+			plan.changes = append(plan.changes, transferChange{index: len(plan.result.Items), remove: true})
+			plan.result.Items = append(plan.result.Items, models.ImportItemResult{
+				UniqueName: item.UniqueName,
+				Quantity:   item.Quantity,
+				Status:     models.ImportStatusRemoved,
+			})
+		}
+	}
+
+	ownedSet := make(map[string]bool, len(owned.Blueprints))
+	for _, bp := range owned.Blueprints {
+		ownedSet[bp.UniqueName] = true
+	}
+	listedBlueprints := make(map[string]bool, len(doc.OwnedBlueprints))
+	for i, raw := range doc.OwnedBlueprints {
+		name := blueprintNames[i]
+		if name != "" {
+			if listedBlueprints[name] {
+				continue
+			}
+			listedBlueprints[name] = true
+		}
+		bpResult := models.BlueprintImportResult{UniqueName: raw}
+		item := catalog[name]
+		switch {
+		case name == "":
+			bpResult.Status = models.ImportStatusInvalid
+		case ownedSet[name]:
+			bpResult.UniqueName, bpResult.Status = name, models.ImportStatusUnchanged
+		case item == nil:
+			bpResult.UniqueName, bpResult.Status = name, models.ImportStatusNotFound
+		case item.ConsumeOnBuild:
+			bpResult.UniqueName, bpResult.Status = name, models.ImportStatusInvalid
+		default:
+			bpResult.UniqueName, bpResult.Status = name, models.ImportStatusAdded
+			plan.addBlueprints = append(plan.addBlueprints, name)
+		}
+		plan.result.Blueprints = append(plan.result.Blueprints, bpResult)
+	}
+	if mode == models.ImportModeReplace {
+		for _, bp := range owned.Blueprints {
+			if listedBlueprints[bp.UniqueName] {
+				continue
+			}
+			plan.removeBlueprints = append(plan.removeBlueprints, bp.UniqueName)
+			plan.result.Blueprints = append(plan.result.Blueprints, models.BlueprintImportResult{
+				UniqueName: bp.UniqueName,
+				Status:     models.ImportStatusRemoved,
+			})
+		}
+	}
+
+	return plan, nil
+}
+
+// planItem decides what importing one document item does. It returns nil
+// when nothing needs writing.
+func (s *WishlistTransferService) planItem(name string, item models.WishlistExportItem, current map[string]models.WishlistItem, catalog map[string]*models.Item) (*transferChange, string) {
+	if name == "" || item.Quantity <= 0 {
+		return nil, models.ImportStatusInvalid
+	}
+	catalogItem := catalog[name]
+	if catalogItem == nil {
+		return nil, models.ImportStatusNotFound
+	}
+	if _, ok := catalogItem.WithRecipe(item.RecipeID); !ok {
+		return nil, models.ImportStatusInvalid
+	}
+	links, err := buildSourceLinks(item.Links)
+	if err != nil {
+		return nil, models.ImportStatusInvalid
+	}
+
+	existing, ok := current[name]
+	if !ok {
+		return &transferChange{
+			add:       true,
+			quantity:  item.Quantity,
+			setLinks:  len(links) > 0,
+			links:     item.Links,
+			setRecipe: item.RecipeID != "",
+			recipeID:  item.RecipeID,
+		}, models.ImportStatusAdded
+	}
+
+	change := &transferChange{}
+	if existing.Quantity != item.Quantity {
+		change.quantity = item.Quantity
+	}
+	if !sameSourceLinks(existing.Links, links) {
+		change.setLinks, change.links = true, item.Links
+	}
+	if existing.RecipeID != item.RecipeID {
+		change.setRecipe, change.recipeID = true, item.RecipeID
+	}
+	if change.quantity == 0 && !change.setLinks && !change.setRecipe {
+		return nil, models.ImportStatusUnchanged
+	}
+	return change, models.ImportStatusUpdated
+}
+
+func (s *WishlistTransferService) apply(ctx context.Context, userID string, plan *transferPlan) error {
+	for _, change := range plan.changes {
+		itemResult := &plan.result.Items[change.index]
+		name := itemResult.UniqueName
+
+		if change.remove {
+			if err := s.wishlistService.RemoveItem(ctx, userID, name); err != nil && !errors.Is(err, ErrItemNotInWishlist) {
+				logger.Error(ctx, "service: WishlistTransferService."+plan.op+" - error removing item", "uniqueName", name, "error", err)
+				return err
+			}
+			continue
+		}
+
+		var err error
+		if change.add {
+			err = s.wishlistService.AddItem(ctx, userID, models.AddItemRequest{UniqueName: name, Quantity: change.quantity, AllowOwned: true})
+		} else if change.quantity > 0 {
+			err = s.wishlistService.UpdateQuantity(ctx, userID, name, change.quantity)
+		}
+		var approvalErr *ApprovalRequiredError
+		switch {
+		case err == nil:
+		case errors.As(err, &approvalErr):
+			itemResult.Status = models.ImportStatusPendingApproval
+			itemResult.PendingChange = approvalErr.Change
+			// A held addition is not in the wishlist yet, so there is
+			// nothing to attach links or a recipe to.
+			if change.add {
+				continue
+			}
+		case errors.Is(err, ErrItemAlreadyInWishlist):
+			itemResult.Status = models.ImportStatusAlreadyInWishlist
+			continue
+		default:
+			logger.Error(ctx, "service: WishlistTransferService."+plan.op+" - error writing item", "uniqueName", name, "error", err)
+			return err
+		}
+
+		if change.setLinks {
+			if _, err := s.wishlistService.SetItemLinks(ctx, userID, name, change.links); err != nil {
+				logger.Error(ctx, "service: WishlistTransferService."+plan.op+" - error setting links", "uniqueName", name, "error", err)
+				return err
+			}
+		}
+		if change.setRecipe {
+			if err := s.wishlistService.SetItemRecipe(ctx, userID, name, change.recipeID); err != nil {
+				logger.Error(ctx, "service: WishlistTransferService."+plan.op+" - error setting recipe", "uniqueName", name, "error", err)
+				return err
+			}
+		}
+	}
+
+	if len(plan.addBlueprints) > 0 {
+		if err := s.blueprintsService.BulkAddBlueprints(ctx, userID, models.BulkAddBlueprintsRequest{UniqueNames: plan.addBlueprints}); err != nil {
+			logger.Error(ctx, "service: WishlistTransferService."+plan.op+" - error adding blueprints", "error", err)
+			return err
+		}
+	}
+	for _, name := range plan.removeBlueprints {
+		if err := s.blueprintsService.RemoveBlueprint(ctx, userID, name); err != nil && !errors.Is(err, ErrBlueprintNotOwned) {
+			logger.Error(ctx, "service: WishlistTransferService."+plan.op+" - error removing blueprint", "uniqueName", name, "error", err)
+			return err
+		}
+	}
+	return nil
+}
+
+func sameSourceLinks(a, b []models.SourceLink) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].URL != b[i].URL || a[i].Title != b[i].Title {
+			return false
+		}
+	}
+	return true
+}
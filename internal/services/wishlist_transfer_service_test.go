@@ -0,0 +1,382 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/mocks"
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository/memory"
+)
+
+type transferFixture struct {
+	service    *WishlistTransferService
+	wishlists  *memory.WishlistRepository
+	blueprints *memory.OwnedBlueprintsRepository
+}
+
+func newTransferFixture(t *testing.T) *transferFixture {
+	t.Helper()
+	items := memory.NewItemRepository()
+	items.Add("warframes",
+		models.Item{UniqueName: "/Lotus/Powersuits/Rhino/Rhino", Name: "Rhino"},
+		models.Item{UniqueName: "/Lotus/Powersuits/Ash/Ash", Name: "Ash"},
+	)
+	items.Add("primary",
+		models.Item{UniqueName: "/Lotus/Weapons/Soma", Name: "Soma", AlternateRecipes: []models.Recipe{{ID: "alt"}}},
+	)
+	items.Add("misc",
+		models.Item{UniqueName: "/Lotus/Types/Forma", Name: "Forma"},
+		models.Item{UniqueName: "/Lotus/Types/FormaBlueprint", Name: "Forma Blueprint"},
+		models.Item{UniqueName: "/Lotus/Types/RhinoBlueprint", Name: "Rhino Blueprint"},
+		models.Item{UniqueName: "/Lotus/Types/OneShotBlueprint", Name: "One Shot Blueprint", ConsumeOnBuild: true},
+	)
+
+	wishlists := memory.NewWishlistRepository()
+	blueprints := memory.NewOwnedBlueprintsRepository()
+	service := NewWishlistTransferService(
+		NewWishlistService(wishlists, items),
+		NewOwnedBlueprintsService(blueprints, items),
+		items,
+	)
+	service.now = func() time.Time { return time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC) }
+	return &transferFixture{service: service, wishlists: wishlists, blueprints: blueprints}
+}
+
+func (f *transferFixture) seed(t *testing.T, userID string) {
+	t.Helper()
+	ctx := context.Background()
+	err := f.wishlists.Create(ctx, &models.Wishlist{
+		UserID: userID,
+		Items: []models.WishlistItem{
+			{UniqueName: "/Lotus/Powersuits/Rhino/Rhino", Quantity: 1},
+			{UniqueName: "/Lotus/Weapons/Soma", Quantity: 2, Links: []models.SourceLink{{URL: "https://example.com/soma", Title: "Build", Host: "example.com"}}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("seeding wishlist: %v", err)
+	}
+	err = f.blueprints.Create(ctx, &models.OwnedBlueprints{
+		UserID:     userID,
+		Blueprints: []models.OwnedBlueprint{{UniqueName: "/Lotus/Types/RhinoBlueprint"}},
+	})
+	if err != nil {
+		t.Fatalf("seeding blueprints: %v", err)
+	}
+}
+
+func statuses(results []models.ImportItemResult) map[string]string {
+	byName := make(map[string]string, len(results))
+	for _, r := range results {
+		byName[r.UniqueName] = r.Status
+	}
+	return byName
+}
+
+func blueprintStatuses(results []models.BlueprintImportResult) map[string]string {
+	byName := make(map[string]string, len(results))
+	for _, r := range results {
+		byName[r.UniqueName] = r.Status
+	}
+	return byName
+}
+
+func TestWishlistTransferService_Export(t *testing.T) {
+	f := newTransferFixture(t)
+	f.seed(t, "user1")
+
+	doc, err := f.service.Export(context.Background(), "user1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if doc.Format != models.WishlistExportFormat || doc.Version != models.WishlistExportVersion {
+		t.Errorf("unexpected header %q v%d", doc.Format, doc.Version)
+	}
+	if len(doc.Items) != 2 || doc.Items[1].Quantity != 2 || len(doc.Items[1].Links) != 1 {
+		t.Fatalf("unexpected items %+v", doc.Items)
+	}
+	if doc.Items[1].Links[0].URL != "https://example.com/soma" {
+		t.Errorf("expected link to be exported, got %+v", doc.Items[1].Links)
+	}
+	if len(doc.OwnedBlueprints) != 1 || doc.OwnedBlueprints[0] != "/Lotus/Types/RhinoBlueprint" {
+		t.Errorf("unexpected blueprints %v", doc.OwnedBlueprints)
+	}
+}
+
+func TestWishlistTransferService_Checklist(t *testing.T) {
+	f := newTransferFixture(t)
+	f.seed(t, "user1")
+	f.service.SetMaterialResolver(&mocks.MockMaterialResolver{
+		GetMaterialsFunc: func(ctx context.Context, userID string) (*models.MaterialsResponse, error) {
+			return &models.MaterialsResponse{
+				Materials: []models.MaterialRequirement{
+					{UniqueName: "/Lotus/Types/Items/MiscItems/OrokinCell", Name: "Orokin Cell", Remaining: 3},
+					{UniqueName: "/Lotus/Types/Items/MiscItems/Ferrite", Name: "Ferrite", Remaining: 0},
+					{UniqueName: "/Lotus/Types/Items/MiscItems/Alloy", Name: "Alloy Plate", Remaining: 500},
+				},
+				Truncated: true,
+			}, nil
+		},
+	})
+
+	checklist, err := f.service.Checklist(context.Background(), "user1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !checklist.GeneratedAt.Equal(time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected generatedAt %v", checklist.GeneratedAt)
+	}
+	if len(checklist.Items) != 2 || checklist.Items[0].Name != "Rhino" || checklist.Items[1].Name != "Soma" || checklist.Items[1].Quantity != 2 {
+		t.Fatalf("unexpected items %+v", checklist.Items)
+	}
+	if len(checklist.Materials) != 2 || checklist.Materials[0].Name != "Alloy Plate" || checklist.Materials[1].Name != "Orokin Cell" {
+		t.Errorf("expected the materials still needed sorted by name, got %+v", checklist.Materials)
+	}
+	if !checklist.MaterialsTruncated {
+		t.Error("expected truncation to be reported")
+	}
+
+	empty, err := f.service.Checklist(context.Background(), "nobody")
+	if err != nil || len(empty.Items) != 0 || len(empty.Materials) != 0 {
+		t.Errorf("expected an empty checklist, got %+v (err %v)", empty, err)
+	}
+
+	f.service.SetMaterialResolver(&mocks.MockMaterialResolver{
+		GetMaterialsFunc: func(ctx context.Context, userID string) (*models.MaterialsResponse, error) {
+			return nil, errors.New("resolver down")
+		},
+	})
+	if _, err := f.service.Checklist(context.Background(), "user1"); err == nil {
+		t.Error("expected the resolver error")
+	}
+}
+
+func TestWishlistTransferService_ExportImportRoundTrip(t *testing.T) {
+	f := newTransferFixture(t)
+	f.seed(t, "user1")
+	ctx := context.Background()
+
+	doc, err := f.service.Export(ctx, "user1")
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	result, err := f.service.Import(ctx, "user2", models.WishlistDocumentImportRequest{Document: *doc})
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if result.Mode != models.ImportModeMerge {
+		t.Errorf("expected merge by default, got %q", result.Mode)
+	}
+
+	copied, err := f.service.Export(ctx, "user2")
+	if err != nil {
+		t.Fatalf("export copy: %v", err)
+	}
+	if len(copied.Items) != 2 || copied.Items[1].Quantity != 2 || len(copied.Items[1].Links) != 1 || len(copied.OwnedBlueprints) != 1 {
+		t.Errorf("expected the copy to match the original, got %+v", copied)
+	}
+
+	again, err := f.service.Import(ctx, "user2", models.WishlistDocumentImportRequest{Document: *doc})
+	if err != nil {
+		t.Fatalf("second import: %v", err)
+	}
+	for name, status := range statuses(again.Items) {
+		if status != models.ImportStatusUnchanged {
+			t.Errorf("expected %s unchanged on a repeat import, got %s", name, status)
+		}
+	}
+}
+
+func TestWishlistTransferService_ImportMerge(t *testing.T) {
+	f := newTransferFixture(t)
+	f.seed(t, "user1")
+	ctx := context.Background()
+
+	doc := models.WishlistExport{
+		Format:  models.WishlistExportFormat,
+		Version: models.WishlistExportVersion,
+		Items: []models.WishlistExportItem{
+			{UniqueName: "/Lotus/Weapons/Soma", Quantity: 5, RecipeID: "alt"},
+			{UniqueName: "/Lotus/Types/Forma", Quantity: 3},
+			{UniqueName: "/Lotus/Types/Forma", Quantity: 9},
+			{UniqueName: "/Lotus/Missing", Quantity: 1},
+			{UniqueName: "/Lotus/Powersuits/Ash/Ash", Quantity: 0},
+			{UniqueName: "/Lotus/Powersuits/Rhino/Rhino", Quantity: 1, RecipeID: "nope"},
+			{UniqueName: "/Lotus/../Forma", Quantity: 1},
+		},
+		OwnedBlueprints: []string{"/Lotus/Types/FormaBlueprint", "/Lotus/Types/RhinoBlueprint", "/Lotus/Types/OneShotBlueprint"},
+	}
+	result, err := f.service.Import(ctx, "user1", models.WishlistDocumentImportRequest{Mode: models.ImportModeMerge, Document: doc})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := statuses(result.Items)
+	want := map[string]string{
+		"/Lotus/Weapons/Soma":           models.ImportStatusUpdated,
+		"/Lotus/Types/Forma":            models.ImportStatusAdded,
+		"/Lotus/Missing":                models.ImportStatusNotFound,
+		"/Lotus/Powersuits/Ash/Ash":     models.ImportStatusInvalid,
+		"/Lotus/Powersuits/Rhino/Rhino": models.ImportStatusInvalid,
+		"/Lotus/../Forma":               models.ImportStatusInvalid,
+	}
+	for name, status := range want {
+		if got[name] != status {
+			t.Errorf("%s: expected %s, got %s", name, status, got[name])
+		}
+	}
+	if len(result.Items) != 6 {
+		t.Errorf("expected the repeated Forma to be skipped, got %d results", len(result.Items))
+	}
+
+	bps := blueprintStatuses(result.Blueprints)
+	if bps["/Lotus/Types/FormaBlueprint"] != models.ImportStatusAdded ||
+		bps["/Lotus/Types/RhinoBlueprint"] != models.ImportStatusUnchanged ||
+		bps["/Lotus/Types/OneShotBlueprint"] != models.ImportStatusInvalid {
+		t.Errorf("unexpected blueprint statuses %v", bps)
+	}
+
+	wishlist, _ := f.wishlists.GetByUserID(ctx, "user1")
+	if len(wishlist.Items) != 3 {
+		t.Fatalf("expected Rhino kept unchanged and Forma added, got %+v", wishlist.Items)
+	}
+	soma := wishlist.Items[1]
+	if soma.Quantity != 5 || soma.RecipeID != "alt" || len(soma.Links) != 0 {
+		t.Errorf("expected Soma to take the document's quantity, recipe and links, got %+v", soma)
+	}
+	owned, _ := f.blueprints.GetByUserID(ctx, "user1")
+	if len(owned.Blueprints) != 2 {
+		t.Errorf("expected Forma Blueprint added, got %+v", owned.Blueprints)
+	}
+}
+
+func TestWishlistTransferService_ImportReplaceDryRun(t *testing.T) {
+	f := newTransferFixture(t)
+	f.seed(t, "user1")
+	ctx := context.Background()
+
+	req := models.WishlistDocumentImportRequest{
+		Mode:   models.ImportModeReplace,
+		DryRun: true,
+		Document: models.WishlistExport{
+			Format:          models.WishlistExportFormat,
+			Version:         models.WishlistExportVersion,
+			Items:           []models.WishlistExportItem{{UniqueName: "/Lotus/Types/Forma", Quantity: 1}},
+			OwnedBlueprints: []string{"/Lotus/Types/FormaBlueprint"},
+		},
+	}
+	result, err := f.service.Import(ctx, "user1", req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := statuses(result.Items)
+	if !result.DryRun || got["/Lotus/Types/Forma"] != models.ImportStatusAdded ||
+		got["/Lotus/Powersuits/Rhino/Rhino"] != models.ImportStatusRemoved || got["/Lotus/Weapons/Soma"] != models.ImportStatusRemoved {
+		t.Errorf("unexpected dry run result %+v", result)
+	}
+	if bps := blueprintStatuses(result.Blueprints); bps["/Lotus/Types/RhinoBlueprint"] != models.ImportStatusRemoved {
+		t.Errorf("expected Rhino Blueprint to be removed, got %v", bps)
+	}
+
+	wishlist, _ := f.wishlists.GetByUserID(ctx, "user1")
+	if len(wishlist.Items) != 2 {
+		t.Fatalf("dry run must not write, got %+v", wishlist.Items)
+	}
+
+	req.DryRun = false
+	if _, err := f.service.Import(ctx, "user1", req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wishlist, _ = f.wishlists.GetByUserID(ctx, "user1")
+	if len(wishlist.Items) != 1 || wishlist.Items[0].UniqueName != "/Lotus/Types/Forma" {
+		t.Errorf("expected only Forma after replace, got %+v", wishlist.Items)
+	}
+	owned, _ := f.blueprints.GetByUserID(ctx, "user1")
+	if len(owned.Blueprints) != 1 || owned.Blueprints[0].UniqueName != "/Lotus/Types/FormaBlueprint" {
+		t.Errorf("expected only Forma Blueprint after replace, got %+v", owned.Blueprints)
+	}
+}
+
+func TestWishlistTransferService_ImportHeldForApproval(t *testing.T) {
+	f := newTransferFixture(t)
+	change := &models.PendingChange{UniqueName: "/Lotus/Types/Forma"}
+	f.service.wishlistService = &mocks.MockWishlistService{
+		GetWishlistFunc: func(ctx context.Context, userID string) (*models.Wishlist, error) {
+			return &models.Wishlist{UserID: userID}, nil
+		},
+		AddItemFunc: func(ctx context.Context, userID string, req models.AddItemRequest) error {
+			return &ApprovalRequiredError{Change: change}
+		},
+		SetItemLinksFunc: func(ctx context.Context, userID, uniqueName string, links []models.SourceLinkRequest) ([]models.SourceLink, error) {
+			t.Error("links must not be set on an addition held for approval")
+			return nil, nil
+		},
+	}
+
+	result, err := f.service.Import(context.Background(), "member", models.WishlistDocumentImportRequest{
+		Document: models.WishlistExport{
+			Format:  models.WishlistExportFormat,
+			Version: models.WishlistExportVersion,
+			Items: []models.WishlistExportItem{{
+				UniqueName: "/Lotus/Types/Forma",
+				Quantity:   10,
+				Links:      []models.SourceLinkRequest{{URL: "https://example.com"}},
+			}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Items[0].Status != models.ImportStatusPendingApproval || result.Items[0].PendingChange != change {
+		t.Errorf("expected the addition to be pending approval, got %+v", result.Items[0])
+	}
+}
+
+func TestWishlistTransferService_ImportErrors(t *testing.T) {
+	valid := models.WishlistExport{Format: models.WishlistExportFormat, Version: models.WishlistExportVersion}
+
+	tests := []struct {
+		name     string
+		req      models.WishlistDocumentImportRequest
+		expected error
+	}{
+		{
+			name:     "unknown mode",
+			req:      models.WishlistDocumentImportRequest{Mode: "overwrite", Document: valid},
+			expected: ErrInvalidImportMode,
+		},
+		{
+			name:     "not an export",
+			req:      models.WishlistDocumentImportRequest{Document: models.WishlistExport{Version: 1}},
+			expected: ErrInvalidExportDocument,
+		},
+		{
+			name:     "newer version",
+			req:      models.WishlistDocumentImportRequest{Document: models.WishlistExport{Format: models.WishlistExportFormat, Version: models.WishlistExportVersion + 1}},
+			expected: ErrUnsupportedExportVersion,
+		},
+		{
+			name: "too many blueprints",
+			req: models.WishlistDocumentImportRequest{Document: models.WishlistExport{
+				Format:          models.WishlistExportFormat,
+				Version:         models.WishlistExportVersion,
+				OwnedBlueprints: make([]string, MaxWishlistExportEntries+1),
+			}},
+			expected: ErrExportTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newTransferFixture(t)
+			_, err := f.service.Import(context.Background(), "user1", tt.req)
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+)
+
+// WishlistVersionService fingerprints what a user's wishlist and materials
+// responses are built from, so a client polling them can be answered 304 Not
+// Modified before either is built. Equal versions mean equal responses; a
+// version changes whenever any of its inputs does.
+type WishlistVersionService struct {
+	wishlistRepo repository.WishlistRepositoryInterface
+	ownedBPRepo  repository.OwnedBlueprintsRepositoryInterface
+	// ownedMaterialsRepo, customItemRepo and settingsRepo are optional, as
+	// they are for MaterialResolver.
+	ownedMaterialsRepo repository.OwnedMaterialsRepositoryInterface
+	customItemRepo     repository.CustomItemRepositoryInterface
+	settingsRepo       repository.SettingsRepositoryInterface
+	// dataVersion returns the item data version, which item names, recipes
+	// and completion depend on.
+	dataVersion func() string
+}
+
+func NewWishlistVersionService(wishlistRepo repository.WishlistRepositoryInterface, ownedBPRepo repository.OwnedBlueprintsRepositoryInterface, ownedMaterialsRepo repository.OwnedMaterialsRepositoryInterface, dataVersion func() string) *WishlistVersionService {
+	return &WishlistVersionService{
+		wishlistRepo:       wishlistRepo,
+		ownedBPRepo:        ownedBPRepo,
+		ownedMaterialsRepo: ownedMaterialsRepo,
+		dataVersion:        dataVersion,
+	}
+}
+
+// SetCustomItemRepository makes MaterialsVersion follow the user's custom
+// items, for a MaterialResolver that resolves them.
+func (s *WishlistVersionService) SetCustomItemRepository(customItemRepo repository.CustomItemRepositoryInterface) {
+	s.customItemRepo = customItemRepo
+}
+
+// SetSettingsRepository makes MaterialsVersion follow the user's settings,
+// for a MaterialResolver that reads them.
+func (s *WishlistVersionService) SetSettingsRepository(settingsRepo repository.SettingsRepositoryInterface) {
+	s.settingsRepo = settingsRepo
+}
+
+// WishlistVersion versions GET /wishlist: the wishlist's and owned blueprints'
+// UpdatedAt, the latter for item completion, and the item data version. It
+// returns "" for a user without a wishlist, whose empty one is made up on
+// each request.
+func (s *WishlistVersionService) WishlistVersion(ctx context.Context, userID string) (string, error) {
+	logger.Debug(ctx, "service: WishlistVersionService.WishlistVersion called", "userID", userID)
+
+	var version strings.Builder
+	ok, err := s.writeWishlistVersion(ctx, userID, &version)
+	if err != nil || !ok {
+		return "", err
+	}
+	return version.String(), nil
+}
+
+// MaterialsVersion versions GET /wishlist/materials: WishlistVersion and the
+// owned materials, custom items and settings the resolution reads. It
+// returns "" for a user without a wishlist.
+func (s *WishlistVersionService) MaterialsVersion(ctx context.Context, userID string) (string, error) {
+	logger.Debug(ctx, "service: WishlistVersionService.MaterialsVersion called", "userID", userID)
+
+	var version strings.Builder
+	ok, err := s.writeWishlistVersion(ctx, userID, &version)
+	if err != nil || !ok {
+		return "", err
+	}
+
+	if s.ownedMaterialsRepo != nil {
+		materials, err := s.ownedMaterialsRepo.GetByUserID(ctx, userID)
+		if err != nil {
+			logger.Error(ctx, "service: WishlistVersionService.MaterialsVersion - error fetching owned materials", "error", err)
+			return "", err
+		}
+		version.WriteString(";materials")
+		for _, material := range materials {
+			version.WriteString("," + material.UniqueName + "=" + strconv.Itoa(material.Count))
+		}
+	}
+	if s.customItemRepo != nil {
+		items, err := s.customItemRepo.ListByUser(ctx, userID)
+		if err != nil {
+			logger.Error(ctx, "service: WishlistVersionService.MaterialsVersion - error fetching custom items", "error", err)
+			return "", err
+		}
+		version.WriteString(";custom")
+		for _, item := range items {
+			version.WriteString("," + item.UniqueName + "@" + versionTimestamp(item.UpdatedAt))
+		}
+	}
+	if s.settingsRepo != nil {
+		settings, err := s.settingsRepo.GetByUserID(ctx, userID)
+		if err != nil {
+			logger.Error(ctx, "service: WishlistVersionService.MaterialsVersion - error fetching settings", "error", err)
+			return "", err
+		}
+		updatedAt := time.Time{}
+		if settings != nil {
+			updatedAt = settings.UpdatedAt
+		}
+		version.WriteString(";settings@" + versionTimestamp(updatedAt))
+	}
+	return version.String(), nil
+}
+
+// writeWishlistVersion writes the WishlistVersion of userID to version and
+// reports whether the user has a wishlist.
+func (s *WishlistVersionService) writeWishlistVersion(ctx context.Context, userID string, version *strings.Builder) (bool, error) {
+	wishlist, err := s.wishlistRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "service: WishlistVersionService - error fetching wishlist", "error", err)
+		return false, err
+	}
+	if wishlist == nil {
+		return false, nil
+	}
+	version.WriteString("wishlist@" + versionTimestamp(wishlist.UpdatedAt))
+
+	blueprintsUpdatedAt := time.Time{}
+	if s.ownedBPRepo != nil {
+		ownedBP, err := s.ownedBPRepo.GetByUserID(ctx, userID)
+		if err != nil {
+			logger.Error(ctx, "service: WishlistVersionService - error fetching owned blueprints", "error", err)
+			return false, err
+		}
+		if ownedBP != nil {
+			blueprintsUpdatedAt = ownedBP.UpdatedAt
+		}
+	}
+	version.WriteString(";blueprints@" + versionTimestamp(blueprintsUpdatedAt))
+
+	if s.dataVersion != nil {
+		version.WriteString(";data@" + s.dataVersion())
+	}
+	return true, nil
+}
+
+func versionTimestamp(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository/memory"
+)
+
+// failingOwnedMaterialsRepository fails every GetByUserID, as an unreachable
+// owned-materials collection would.
+type failingOwnedMaterialsRepository struct {
+	*memory.OwnedMaterialsRepository
+}
+
+func (r failingOwnedMaterialsRepository) GetByUserID(ctx context.Context, userID string) ([]models.OwnedMaterial, error) {
+	return nil, errors.New("database error")
+}
+
+type versionFixture struct {
+	service     *WishlistVersionService
+	wishlists   *memory.WishlistRepository
+	ownedBP     *memory.OwnedBlueprintsRepository
+	materials   *memory.OwnedMaterialsRepository
+	customItems *memory.CustomItemRepository
+	settings    *memory.SettingsRepository
+	dataVersion string
+}
+
+func newVersionFixture(t *testing.T) *versionFixture {
+	t.Helper()
+	f := &versionFixture{
+		wishlists:   memory.NewWishlistRepository(),
+		ownedBP:     memory.NewOwnedBlueprintsRepository(),
+		materials:   memory.NewOwnedMaterialsRepository(),
+		customItems: memory.NewCustomItemRepository(),
+		settings:    memory.NewSettingsRepository(),
+		dataVersion: "v1",
+	}
+	f.service = NewWishlistVersionService(f.wishlists, f.ownedBP, f.materials, func() string { return f.dataVersion })
+	f.service.SetCustomItemRepository(f.customItems)
+	f.service.SetSettingsRepository(f.settings)
+	return f
+}
+
+func TestWishlistVersionService_NoWishlist(t *testing.T) {
+	f := newVersionFixture(t)
+	ctx := context.Background()
+
+	version, err := f.service.WishlistVersion(ctx, "user-123")
+	if err != nil || version != "" {
+		t.Errorf("WishlistVersion() = %q, %v; want empty", version, err)
+	}
+	version, err = f.service.MaterialsVersion(ctx, "user-123")
+	if err != nil || version != "" {
+		t.Errorf("MaterialsVersion() = %q, %v; want empty", version, err)
+	}
+}
+
+func TestWishlistVersionService_Changes(t *testing.T) {
+	ctx := context.Background()
+	userID := "user-123"
+
+	tests := []struct {
+		name             string
+		change           func(f *versionFixture) error
+		wishlistChanges  bool
+		materialsChanges bool
+	}{
+		{
+			name: "wishlist item added",
+			change: func(f *versionFixture) error {
+				return f.wishlists.AddItem(ctx, userID, models.WishlistItem{UniqueName: "/Lotus/Types/Forma", Quantity: 1})
+			},
+			wishlistChanges:  true,
+			materialsChanges: true,
+		},
+		{
+			name: "blueprint owned",
+			change: func(f *versionFixture) error {
+				return f.ownedBP.AddBlueprint(ctx, userID, models.OwnedBlueprint{UniqueName: "/Lotus/Types/FormaBlueprint", Quantity: 1})
+			},
+			wishlistChanges:  true,
+			materialsChanges: true,
+		},
+		{
+			name: "item data synced",
+			change: func(f *versionFixture) error {
+				f.dataVersion = "v2"
+				return nil
+			},
+			wishlistChanges:  true,
+			materialsChanges: true,
+		},
+		{
+			name: "material count changed",
+			change: func(f *versionFixture) error {
+				return f.materials.SetCounts(ctx, userID, map[string]int{"/Lotus/Types/Alloy": 300})
+			},
+			materialsChanges: true,
+		},
+		{
+			name: "custom item added",
+			change: func(f *versionFixture) error {
+				return f.customItems.Create(ctx, &models.CustomItem{UserID: userID, Name: "Dojo Garden", UpdatedAt: time.Now()})
+			},
+			materialsChanges: true,
+		},
+		{
+			name: "settings changed",
+			change: func(f *versionFixture) error {
+				return f.settings.Upsert(ctx, &models.UserSettings{UserID: userID, TimeZone: "Europe/Berlin"})
+			},
+			materialsChanges: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newVersionFixture(t)
+			if err := f.wishlists.Create(ctx, &models.Wishlist{UserID: userID}); err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+			if err := f.ownedBP.Create(ctx, &models.OwnedBlueprints{UserID: userID}); err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+			if err := f.materials.SetCounts(ctx, userID, map[string]int{"/Lotus/Types/Alloy": 100}); err != nil {
+				t.Fatalf("SetCounts() error = %v", err)
+			}
+
+			wishlistBefore, err := f.service.WishlistVersion(ctx, userID)
+			if err != nil || wishlistBefore == "" {
+				t.Fatalf("WishlistVersion() = %q, %v", wishlistBefore, err)
+			}
+			materialsBefore, err := f.service.MaterialsVersion(ctx, userID)
+			if err != nil || materialsBefore == "" {
+				t.Fatalf("MaterialsVersion() = %q, %v", materialsBefore, err)
+			}
+
+			// Memory repositories stamp changes with time.Now.
+			time.Sleep(time.Millisecond)
+			if err := tt.change(f); err != nil {
+				t.Fatalf("change error = %v", err)
+			}
+
+			wishlistAfter, _ := f.service.WishlistVersion(ctx, userID)
+			if changed := wishlistAfter != wishlistBefore; changed != tt.wishlistChanges {
+				t.Errorf("WishlistVersion changed = %v, want %v (%q -> %q)", changed, tt.wishlistChanges, wishlistBefore, wishlistAfter)
+			}
+			materialsAfter, _ := f.service.MaterialsVersion(ctx, userID)
+			if changed := materialsAfter != materialsBefore; changed != tt.materialsChanges {
+				t.Errorf("MaterialsVersion changed = %v, want %v (%q -> %q)", changed, tt.materialsChanges, materialsBefore, materialsAfter)
+			}
+		})
+	}
+}
+
+func TestWishlistVersionService_Error(t *testing.T) {
+	ctx := context.Background()
+	wishlists := memory.NewWishlistRepository()
+	if err := wishlists.Create(ctx, &models.Wishlist{UserID: "user-123"}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	service := NewWishlistVersionService(wishlists, memory.NewOwnedBlueprintsRepository(),
+		failingOwnedMaterialsRepository{memory.NewOwnedMaterialsRepository()}, nil)
+
+	if version, err := service.WishlistVersion(ctx, "user-123"); err != nil || version == "" {
+		t.Errorf("WishlistVersion() = %q, %v; want a version", version, err)
+	}
+	if version, err := service.MaterialsVersion(ctx, "user-123"); err == nil || version != "" {
+		t.Errorf("MaterialsVersion() = %q, %v; want an error", version, err)
+	}
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+)
+
+const (
+	DefaultWorkspaceActivityLimit = 50
+	MaxWorkspaceActivityLimit     = 200
+)
+
+var ErrWorkspaceMaterialNotNeeded = errors.New("the workspace does not need this material")
+
+// LogMaterialContribution records that the user contributed quantity of a
+// material toward the workspace's material goals. A negative quantity
+// corrects an earlier contribution and cannot take the user's total for the
+// material below zero. Any member may log their own contributions.
+func (s *WorkspaceService) LogMaterialContribution(ctx context.Context, userID, id string, req models.WorkspaceMaterialContributionRequest) (*models.WorkspaceContributionEvent, error) {
+	logger.Debug(ctx, "service: WorkspaceService.LogMaterialContribution called", "userID", userID, "id", id, "uniqueName", req.UniqueName, "quantity", req.Quantity)
+
+	if req.Quantity == 0 || req.Quantity > MaxWorkspaceQuantity || req.Quantity < -MaxWorkspaceQuantity {
+		return nil, fmt.Errorf("%w: quantity must be non-zero and at most %d either way", ErrInvalidWorkspace, MaxWorkspaceQuantity)
+	}
+	workspace, err := s.load(ctx, userID, id, models.WorkspaceRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := s.contributionRepo.MaterialTotals(ctx, workspace.ID)
+	if err != nil {
+		logger.Error(ctx, "service: WorkspaceService.LogMaterialContribution - error fetching totals", "error", err)
+		return nil, err
+	}
+	current := 0
+	for _, total := range totals {
+		if total.UniqueName == req.UniqueName && total.UserID == userID {
+			current = total.Quantity
+		}
+	}
+	if req.Quantity < 0 && current+req.Quantity < 0 {
+		logger.Warn(ctx, "service: WorkspaceService.LogMaterialContribution - correction exceeds contribution", "current", current, "quantity", req.Quantity)
+		return nil, fmt.Errorf("%w: you have contributed only %d of this material", ErrInvalidWorkspace, current)
+	}
+	if req.Quantity > 0 {
+		materials, err := s.materialResolver.GetMaterials(ctx, workspace.ID.Hex())
+		if err != nil {
+			logger.Error(ctx, "service: WorkspaceService.LogMaterialContribution - error resolving materials", "error", err)
+			return nil, err
+		}
+		if !hasMaterial(materials, req.UniqueName) {
+			logger.Warn(ctx, "service: WorkspaceService.LogMaterialContribution - material not needed", "uniqueName", req.UniqueName)
+			return nil, ErrWorkspaceMaterialNotNeeded
+		}
+	}
+
+	event := &models.WorkspaceContributionEvent{
+		WorkspaceID: workspace.ID,
+		UserID:      userID,
+		Kind:        models.ContributionKindMaterial,
+		UniqueName:  req.UniqueName,
+		Quantity:    req.Quantity,
+		CreatedAt:   s.now(),
+	}
+	if err := s.contributionRepo.Create(ctx, event); err != nil {
+		logger.Error(ctx, "service: WorkspaceService.LogMaterialContribution - error storing contribution", "error", err)
+		return nil, err
+	}
+
+	logger.Info(ctx, "service: WorkspaceService.LogMaterialContribution - contribution logged", "id", id, "uniqueName", req.UniqueName, "quantity", req.Quantity)
+	return event, nil
+}
+
+// ListActivity returns the workspace's latest contributions, newest first.
+// limit defaults to DefaultWorkspaceActivityLimit and is capped at
+// MaxWorkspaceActivityLimit.
+func (s *WorkspaceService) ListActivity(ctx context.Context, userID, id string, limit int) ([]models.WorkspaceContributionEvent, error) {
+	if limit <= 0 {
+		limit = DefaultWorkspaceActivityLimit
+	}
+	if limit > MaxWorkspaceActivityLimit {
+		limit = MaxWorkspaceActivityLimit
+	}
+	logger.Debug(ctx, "service: WorkspaceService.ListActivity called", "userID", userID, "id", id, "limit", limit)
+
+	workspace, err := s.load(ctx, userID, id, models.WorkspaceRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.contributionRepo.ListByWorkspace(ctx, workspace.ID, limit)
+	if err != nil {
+		logger.Error(ctx, "service: WorkspaceService.ListActivity - error fetching activity", "error", err)
+		return nil, err
+	}
+	return events, nil
+}
+
+// logItemContribution records a change to the user's contribution to an
+// item in the activity feed. The contribution itself is already stored, so
+// failing to record it is only logged.
+func (s *WorkspaceService) logItemContribution(ctx context.Context, workspace *models.Workspace, userID, uniqueName string, delta int) {
+	if delta == 0 {
+		return
+	}
+	event := &models.WorkspaceContributionEvent{
+		WorkspaceID: workspace.ID,
+		UserID:      userID,
+		Kind:        models.ContributionKindItem,
+		UniqueName:  uniqueName,
+		Quantity:    delta,
+		CreatedAt:   s.now(),
+	}
+	if err := s.contributionRepo.Create(ctx, event); err != nil {
+		logger.Warn(ctx, "service: WorkspaceService.SetContribution - error recording activity", "error", err)
+	}
+}
+
+// withMaterialContributions takes the members' material contributions off
+// the resolved materials.
+func withMaterialContributions(materials *models.MaterialsResponse, totals []models.WorkspaceContributionTotal) *models.WorkspaceMaterials {
+	byMaterial := make(map[string][]models.MemberContribution)
+	byMember := make(map[string]int)
+	for _, total := range totals {
+		byMaterial[total.UniqueName] = append(byMaterial[total.UniqueName], models.MemberContribution{UserID: total.UserID, Quantity: total.Quantity})
+		byMember[total.UserID] += total.Quantity
+	}
+
+	result := &models.WorkspaceMaterials{
+		Materials:       make([]models.WorkspaceMaterial, 0, len(materials.Materials)),
+		Members:         make([]models.MemberContribution, 0, len(byMember)),
+		TotalCredits:    materials.TotalCredits,
+		RushPlatinum:    materials.RushPlatinum,
+		Truncated:       materials.Truncated,
+		TruncatedReason: materials.TruncatedReason,
+		Degradation:     materials.Degradation,
+	}
+	for _, m := range materials.Materials {
+		material := models.WorkspaceMaterial{MaterialRequirement: m, Contributions: []models.MemberContribution{}}
+		for _, c := range byMaterial[m.UniqueName] {
+			material.Contributed += c.Quantity
+			material.Contributions = append(material.Contributions, c)
+		}
+		material.Remaining = max(m.Remaining-material.Contributed, 0)
+		result.Materials = append(result.Materials, material)
+	}
+	for userID, quantity := range byMember {
+		result.Members = append(result.Members, models.MemberContribution{UserID: userID, Quantity: quantity})
+	}
+	sort.Slice(result.Members, func(i, j int) bool {
+		if result.Members[i].Quantity != result.Members[j].Quantity {
+			return result.Members[i].Quantity > result.Members[j].Quantity
+		}
+		return result.Members[i].UserID < result.Members[j].UserID
+	})
+	return result
+}
+
+func hasMaterial(materials *models.MaterialsResponse, uniqueName string) bool {
+	for _, m := range materials.Materials {
+		if m.UniqueName == uniqueName {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,608 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	maxWorkspaceNameLength        = 100
+	maxWorkspaceDescriptionLength = 500
+	// MaxWorkspaceQuantity bounds item quantities and contributions, keeping
+	// material totals far from overflowing.
+	MaxWorkspaceQuantity = 1_000_000
+	// workspaceUpdateAttempts bounds how often a change is re-applied when
+	// another member changed the workspace in the meantime.
+	workspaceUpdateAttempts = 3
+)
+
+var (
+	ErrWorkspaceNotFound       = errors.New("workspace not found")
+	ErrWorkspaceForbidden      = errors.New("your workspace role does not allow this")
+	ErrTooManyWorkspaces       = fmt.Errorf("a user can be in at most %d workspaces", models.MaxWorkspacesPerUser)
+	ErrInvalidWorkspace        = errors.New("invalid workspace request")
+	ErrWorkspaceConflict       = errors.New("workspace is being changed by someone else, try again")
+	ErrWorkspaceMemberExists   = errors.New("user is already a workspace member")
+	ErrWorkspaceMemberNotFound = errors.New("workspace member not found")
+	ErrTooManyWorkspaceMembers = fmt.Errorf("a workspace can have at most %d members", models.MaxWorkspaceMembers)
+	ErrLastWorkspaceAdmin      = errors.New("a workspace needs at least one admin")
+	ErrWorkspaceItemExists     = errors.New("item already in workspace")
+	ErrWorkspaceItemNotFound   = errors.New("item not in workspace")
+	ErrTooManyWorkspaceItems   = fmt.Errorf("a workspace can have at most %d items", models.MaxWorkspaceItems)
+)
+
+// workspaceRoleRank orders the roles; each role may do everything the roles
+// below it may.
+var workspaceRoleRank = map[string]int{
+	models.WorkspaceRoleViewer: 1,
+	models.WorkspaceRoleEditor: 2,
+	models.WorkspaceRoleAdmin:  3,
+}
+
+// WorkspaceService manages the wishlists clans share. Only members see a
+// workspace; what they may change depends on their role, and every member
+// records their own contributions. Materials are resolved for what is still
+// needed once contributions are taken off, and every contribution is kept in
+// the workspace's activity feed.
+type WorkspaceService struct {
+	workspaceRepo    repository.WorkspaceRepositoryInterface
+	contributionRepo repository.WorkspaceContributionRepositoryInterface
+	itemRepo         repository.ItemRepositoryInterface
+	materialResolver *MaterialResolver
+	// publisher is optional; changes are sent live to the members viewing
+	// the workspace.
+	publisher WorkspacePublisher
+	now       func() time.Time
+}
+
+func NewWorkspaceService(workspaceRepo repository.WorkspaceRepositoryInterface, contributionRepo repository.WorkspaceContributionRepositoryInterface, itemRepo repository.ItemRepositoryInterface) *WorkspaceService {
+	return &WorkspaceService{
+		workspaceRepo:    workspaceRepo,
+		contributionRepo: contributionRepo,
+		itemRepo:         itemRepo,
+		materialResolver: NewMaterialResolver(itemRepo, &workspaceWishlists{workspaceRepo: workspaceRepo}, nil, nil),
+		now:              time.Now,
+	}
+}
+
+// SetPublisher makes changes publish a WorkspaceEvent to publisher, in the
+// room named by the workspace's hex ID.
+func (s *WorkspaceService) SetPublisher(publisher WorkspacePublisher) {
+	s.publisher = publisher
+}
+
+// SetMaterialLimits bounds workspace materials resolutions as
+// MaterialResolver.SetLimits does.
+func (s *WorkspaceService) SetMaterialLimits(limits MaterialLimits) {
+	s.materialResolver.SetLimits(limits)
+}
+
+func (s *WorkspaceService) List(ctx context.Context, userID string) ([]models.Workspace, error) {
+	logger.Debug(ctx, "service: WorkspaceService.List called", "userID", userID)
+
+	workspaces, err := s.workspaceRepo.ListByMember(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "service: WorkspaceService.List - error fetching workspaces", "error", err)
+		return nil, err
+	}
+	return workspaces, nil
+}
+
+// Get returns the workspace with id. Workspaces the user is not a member of
+// are reported as not found, so their IDs cannot be probed.
+func (s *WorkspaceService) Get(ctx context.Context, userID, id string) (*models.Workspace, error) {
+	logger.Debug(ctx, "service: WorkspaceService.Get called", "userID", userID, "id", id)
+
+	workspace, err := s.load(ctx, userID, id, models.WorkspaceRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	return workspace, nil
+}
+
+// Create creates a workspace with the user as its only member and admin.
+func (s *WorkspaceService) Create(ctx context.Context, userID string, req models.WorkspaceRequest) (*models.Workspace, error) {
+	logger.Debug(ctx, "service: WorkspaceService.Create called", "userID", userID)
+
+	name, description, err := validateWorkspaceRequest(req)
+	if err != nil {
+		logger.Warn(ctx, "service: WorkspaceService.Create - invalid request", "error", err)
+		return nil, err
+	}
+	if err := s.checkWorkspaceCount(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	workspace := &models.Workspace{
+		Name:        name,
+		Description: description,
+		Members:     []models.WorkspaceMember{{UserID: userID, Role: models.WorkspaceRoleAdmin, AddedAt: now}},
+		Items:       []models.WorkspaceItem{},
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.workspaceRepo.Create(ctx, workspace); err != nil {
+		logger.Error(ctx, "service: WorkspaceService.Create - error storing workspace", "error", err)
+		return nil, err
+	}
+
+	logger.Info(ctx, "service: WorkspaceService.Create - workspace created", "id", workspace.ID.Hex())
+	return workspace, nil
+}
+
+func (s *WorkspaceService) Update(ctx context.Context, userID, id string, req models.WorkspaceRequest) (*models.Workspace, error) {
+	logger.Debug(ctx, "service: WorkspaceService.Update called", "userID", userID, "id", id)
+
+	name, description, err := validateWorkspaceRequest(req)
+	if err != nil {
+		logger.Warn(ctx, "service: WorkspaceService.Update - invalid request", "error", err)
+		return nil, err
+	}
+	workspace, err := s.modify(ctx, userID, id, "Update", models.WorkspaceRoleAdmin, func(workspace *models.Workspace) error {
+		workspace.Name = name
+		workspace.Description = description
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.publish(workspace, userID, models.WorkspaceEvent{Type: models.WorkspaceEventUpdated})
+	return workspace, nil
+}
+
+func (s *WorkspaceService) Delete(ctx context.Context, userID, id string) error {
+	logger.Debug(ctx, "service: WorkspaceService.Delete called", "userID", userID, "id", id)
+
+	workspace, err := s.load(ctx, userID, id, models.WorkspaceRoleAdmin)
+	if err != nil {
+		return err
+	}
+	deleted, err := s.workspaceRepo.Delete(ctx, workspace.ID)
+	if err != nil {
+		logger.Error(ctx, "service: WorkspaceService.Delete - error deleting workspace", "error", err)
+		return err
+	}
+	if !deleted {
+		logger.Warn(ctx, "service: WorkspaceService.Delete - workspace deleted concurrently", "id", id)
+		return ErrWorkspaceNotFound
+	}
+	if err := s.contributionRepo.DeleteByWorkspace(ctx, workspace.ID); err != nil {
+		logger.Warn(ctx, "service: WorkspaceService.Delete - error deleting activity", "error", err)
+	}
+
+	logger.Info(ctx, "service: WorkspaceService.Delete - workspace deleted", "id", id)
+	workspace.UpdatedAt = s.now()
+	s.publish(workspace, userID, models.WorkspaceEvent{Type: models.WorkspaceEventDeleted})
+	return nil
+}
+
+func (s *WorkspaceService) AddMember(ctx context.Context, userID, id string, req models.WorkspaceMemberRequest) (*models.Workspace, error) {
+	logger.Debug(ctx, "service: WorkspaceService.AddMember called", "userID", userID, "id", id, "memberID", req.UserID)
+
+	memberID := strings.TrimSpace(req.UserID)
+	if memberID == "" {
+		return nil, fmt.Errorf("%w: userId is required", ErrInvalidWorkspace)
+	}
+	if !models.IsWorkspaceRole(req.Role) {
+		return nil, fmt.Errorf("%w: role must be admin, editor or viewer", ErrInvalidWorkspace)
+	}
+
+	workspace, err := s.modify(ctx, userID, id, "AddMember", models.WorkspaceRoleAdmin, func(workspace *models.Workspace) error {
+		if workspace.Member(memberID) != nil {
+			return ErrWorkspaceMemberExists
+		}
+		if len(workspace.Members) >= models.MaxWorkspaceMembers {
+			return ErrTooManyWorkspaceMembers
+		}
+		if err := s.checkWorkspaceCount(ctx, memberID); err != nil {
+			return err
+		}
+		workspace.Members = append(workspace.Members, models.WorkspaceMember{UserID: memberID, Role: req.Role, AddedAt: s.now()})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.publish(workspace, userID, models.WorkspaceEvent{Type: models.WorkspaceEventUpdated})
+	return workspace, nil
+}
+
+func (s *WorkspaceService) SetMemberRole(ctx context.Context, userID, id, memberID, role string) (*models.Workspace, error) {
+	logger.Debug(ctx, "service: WorkspaceService.SetMemberRole called", "userID", userID, "id", id, "memberID", memberID, "role", role)
+
+	if !models.IsWorkspaceRole(role) {
+		return nil, fmt.Errorf("%w: role must be admin, editor or viewer", ErrInvalidWorkspace)
+	}
+	workspace, err := s.modify(ctx, userID, id, "SetMemberRole", models.WorkspaceRoleAdmin, func(workspace *models.Workspace) error {
+		member := workspace.Member(memberID)
+		if member == nil {
+			return ErrWorkspaceMemberNotFound
+		}
+		if member.Role == models.WorkspaceRoleAdmin && role != models.WorkspaceRoleAdmin && countWorkspaceAdmins(workspace) == 1 {
+			return ErrLastWorkspaceAdmin
+		}
+		member.Role = role
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.publish(workspace, userID, models.WorkspaceEvent{Type: models.WorkspaceEventUpdated})
+	return workspace, nil
+}
+
+// RemoveMember removes memberID from the workspace. Admins may remove anyone
+// and every member may leave; their contributions stay on record.
+func (s *WorkspaceService) RemoveMember(ctx context.Context, userID, id, memberID string) (*models.Workspace, error) {
+	logger.Debug(ctx, "service: WorkspaceService.RemoveMember called", "userID", userID, "id", id, "memberID", memberID)
+
+	minRole := models.WorkspaceRoleAdmin
+	if memberID == userID {
+		minRole = models.WorkspaceRoleViewer
+	}
+	workspace, err := s.modify(ctx, userID, id, "RemoveMember", minRole, func(workspace *models.Workspace) error {
+		member := workspace.Member(memberID)
+		if member == nil {
+			return ErrWorkspaceMemberNotFound
+		}
+		if member.Role == models.WorkspaceRoleAdmin && countWorkspaceAdmins(workspace) == 1 {
+			return ErrLastWorkspaceAdmin
+		}
+		members := workspace.Members[:0]
+		for _, m := range workspace.Members {
+			if m.UserID != memberID {
+				members = append(members, m)
+			}
+		}
+		workspace.Members = members
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.publish(workspace, userID, models.WorkspaceEvent{Type: models.WorkspaceEventMemberRemoved, MemberID: memberID})
+	return workspace, nil
+}
+
+func (s *WorkspaceService) AddItem(ctx context.Context, userID, id string, req models.WorkspaceItemRequest) (*models.Workspace, error) {
+	logger.Debug(ctx, "service: WorkspaceService.AddItem called", "userID", userID, "id", id, "uniqueName", req.UniqueName, "quantity", req.Quantity)
+
+	quantity := req.Quantity
+	if quantity <= 0 {
+		quantity = 1
+	}
+	if quantity > MaxWorkspaceQuantity {
+		return nil, fmt.Errorf("%w: quantity must be at most %d", ErrInvalidWorkspace, MaxWorkspaceQuantity)
+	}
+	item, err := s.itemRepo.FindByUniqueName(ctx, req.UniqueName)
+	if err != nil {
+		logger.Error(ctx, "service: WorkspaceService.AddItem - error finding item", "error", err)
+		return nil, err
+	}
+	if item == nil {
+		logger.Warn(ctx, "service: WorkspaceService.AddItem - item not found", "uniqueName", req.UniqueName)
+		return nil, ErrItemNotFound
+	}
+
+	workspace, err := s.modify(ctx, userID, id, "AddItem", models.WorkspaceRoleEditor, func(workspace *models.Workspace) error {
+		if workspace.Item(req.UniqueName) != nil {
+			return ErrWorkspaceItemExists
+		}
+		if len(workspace.Items) >= models.MaxWorkspaceItems {
+			return ErrTooManyWorkspaceItems
+		}
+		workspace.Items = append(workspace.Items, models.WorkspaceItem{
+			UniqueName:    req.UniqueName,
+			Quantity:      quantity,
+			AddedBy:       userID,
+			AddedAt:       s.now(),
+			Contributions: []models.WorkspaceContribution{},
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.publishItem(workspace, userID, models.WorkspaceEventItemAdded, req.UniqueName)
+	return workspace, nil
+}
+
+func (s *WorkspaceService) UpdateItemQuantity(ctx context.Context, userID, id, uniqueName string, quantity int) (*models.Workspace, error) {
+	logger.Debug(ctx, "service: WorkspaceService.UpdateItemQuantity called", "userID", userID, "id", id, "uniqueName", uniqueName, "quantity", quantity)
+
+	if quantity <= 0 {
+		return nil, ErrInvalidQuantity
+	}
+	if quantity > MaxWorkspaceQuantity {
+		return nil, fmt.Errorf("%w: quantity must be at most %d", ErrInvalidWorkspace, MaxWorkspaceQuantity)
+	}
+	workspace, err := s.modify(ctx, userID, id, "UpdateItemQuantity", models.WorkspaceRoleEditor, func(workspace *models.Workspace) error {
+		item := workspace.Item(uniqueName)
+		if item == nil {
+			return ErrWorkspaceItemNotFound
+		}
+		item.Quantity = quantity
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.publishItem(workspace, userID, models.WorkspaceEventItemUpdated, uniqueName)
+	return workspace, nil
+}
+
+func (s *WorkspaceService) RemoveItem(ctx context.Context, userID, id, uniqueName string) (*models.Workspace, error) {
+	logger.Debug(ctx, "service: WorkspaceService.RemoveItem called", "userID", userID, "id", id, "uniqueName", uniqueName)
+
+	workspace, err := s.modify(ctx, userID, id, "RemoveItem", models.WorkspaceRoleEditor, func(workspace *models.Workspace) error {
+		if workspace.Item(uniqueName) == nil {
+			return ErrWorkspaceItemNotFound
+		}
+		items := workspace.Items[:0]
+		for _, item := range workspace.Items {
+			if item.UniqueName != uniqueName {
+				items = append(items, item)
+			}
+		}
+		workspace.Items = items
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.publish(workspace, userID, models.WorkspaceEvent{Type: models.WorkspaceEventItemRemoved, UniqueName: uniqueName})
+	return workspace, nil
+}
+
+// SetContribution records how many of the item the user has delivered; zero
+// clears their contribution. Any member may record their own; the change is
+// added to the activity feed.
+func (s *WorkspaceService) SetContribution(ctx context.Context, userID, id, uniqueName string, quantity int) (*models.Workspace, error) {
+	logger.Debug(ctx, "service: WorkspaceService.SetContribution called", "userID", userID, "id", id, "uniqueName", uniqueName, "quantity", quantity)
+
+	if quantity < 0 || quantity > MaxWorkspaceQuantity {
+		return nil, fmt.Errorf("%w: contribution must be between 0 and %d", ErrInvalidWorkspace, MaxWorkspaceQuantity)
+	}
+	previous := 0
+	workspace, err := s.modify(ctx, userID, id, "SetContribution", models.WorkspaceRoleViewer, func(workspace *models.Workspace) error {
+		item := workspace.Item(uniqueName)
+		if item == nil {
+			return ErrWorkspaceItemNotFound
+		}
+		previous = 0
+		contributions := item.Contributions[:0]
+		for _, c := range item.Contributions {
+			if c.UserID != userID {
+				contributions = append(contributions, c)
+			} else {
+				previous = c.Quantity
+			}
+		}
+		if quantity > 0 {
+			contributions = append(contributions, models.WorkspaceContribution{UserID: userID, Quantity: quantity, UpdatedAt: s.now()})
+		}
+		item.Contributions = contributions
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.logItemContribution(ctx, workspace, userID, uniqueName, quantity-previous)
+	s.publishItem(workspace, userID, models.WorkspaceEventItemUpdated, uniqueName)
+	return workspace, nil
+}
+
+// GetMaterials returns the materials for what the workspace still needs,
+// visible to every member, with what each member contributed toward them.
+func (s *WorkspaceService) GetMaterials(ctx context.Context, userID, id string) (*models.WorkspaceMaterials, error) {
+	logger.Debug(ctx, "service: WorkspaceService.GetMaterials called", "userID", userID, "id", id)
+
+	workspace, err := s.load(ctx, userID, id, models.WorkspaceRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	materials, err := s.materialResolver.GetMaterials(ctx, workspace.ID.Hex())
+	if err != nil {
+		return nil, err
+	}
+	totals, err := s.contributionRepo.MaterialTotals(ctx, workspace.ID)
+	if err != nil {
+		logger.Error(ctx, "service: WorkspaceService.GetMaterials - error fetching contributions", "error", err)
+		return nil, err
+	}
+	return withMaterialContributions(materials, totals), nil
+}
+
+// load returns the workspace with id, checking the user is a member holding
+// at least minRole.
+func (s *WorkspaceService) load(ctx context.Context, userID, id, minRole string) (*models.Workspace, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		logger.Warn(ctx, "service: WorkspaceService - invalid workspace ID", "id", id)
+		return nil, ErrWorkspaceNotFound
+	}
+	workspace, err := s.workspaceRepo.GetByID(ctx, objectID)
+	if err != nil {
+		logger.Error(ctx, "service: WorkspaceService - error fetching workspace", "error", err)
+		return nil, err
+	}
+	if workspace == nil {
+		logger.Warn(ctx, "service: WorkspaceService - workspace not found", "id", id)
+		return nil, ErrWorkspaceNotFound
+	}
+	member := workspace.Member(userID)
+	if member == nil {
+		logger.Warn(ctx, "service: WorkspaceService - user is not a member", "id", id, "userID", userID)
+		return nil, ErrWorkspaceNotFound
+	}
+	if workspaceRoleRank[member.Role] < workspaceRoleRank[minRole] {
+		logger.Warn(ctx, "service: WorkspaceService - role does not allow change", "id", id, "role", member.Role, "required", minRole)
+		return nil, ErrWorkspaceForbidden
+	}
+	return workspace, nil
+}
+
+// modify applies change to the workspace on behalf of a member holding at
+// least minRole, re-reading and re-applying it when another member changed
+// the workspace in between.
+func (s *WorkspaceService) modify(ctx context.Context, userID, id, name, minRole string, change func(workspace *models.Workspace) error) (*models.Workspace, error) {
+	for attempt := 1; attempt <= workspaceUpdateAttempts; attempt++ {
+		workspace, err := s.load(ctx, userID, id, minRole)
+		if err != nil {
+			return nil, err
+		}
+		if err := change(workspace); err != nil {
+			logger.Warn(ctx, "service: WorkspaceService."+name+" - change rejected", "error", err)
+			return nil, err
+		}
+		workspace.UpdatedAt = s.now()
+		updated, err := s.workspaceRepo.Update(ctx, workspace)
+		if err != nil {
+			logger.Error(ctx, "service: WorkspaceService."+name+" - error storing workspace", "error", err)
+			return nil, err
+		}
+		if updated {
+			logger.Info(ctx, "service: WorkspaceService."+name+" - workspace updated", "id", id, "version", workspace.Version)
+			return workspace, nil
+		}
+		logger.Debug(ctx, "service: WorkspaceService."+name+" - workspace changed concurrently, retrying", "attempt", attempt)
+	}
+
+	logger.Warn(ctx, "service: WorkspaceService."+name+" - giving up after concurrent changes", "id", id)
+	return nil, ErrWorkspaceConflict
+}
+
+// publish sends event, made by userID, to the members viewing workspace.
+func (s *WorkspaceService) publish(workspace *models.Workspace, userID string, event models.WorkspaceEvent) {
+	if s.publisher == nil {
+		return
+	}
+	event.UserID = userID
+	event.Version = workspace.Version
+	event.OccurredAt = workspace.UpdatedAt
+	s.publisher.Publish(workspace.ID.Hex(), event)
+}
+
+// publishItem publishes an item event carrying the item as it now stands.
+func (s *WorkspaceService) publishItem(workspace *models.Workspace, userID, eventType, uniqueName string) {
+	event := models.WorkspaceEvent{Type: eventType, UniqueName: uniqueName}
+	if item := workspace.Item(uniqueName); item != nil {
+		copied := *item
+		event.Item = &copied
+	}
+	s.publish(workspace, userID, event)
+}
+
+func (s *WorkspaceService) checkWorkspaceCount(ctx context.Context, userID string) error {
+	existing, err := s.workspaceRepo.ListByMember(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "service: WorkspaceService - error fetching workspaces", "error", err)
+		return err
+	}
+	if len(existing) >= models.MaxWorkspacesPerUser {
+		logger.Warn(ctx, "service: WorkspaceService - too many workspaces", "userID", userID, "count", len(existing))
+		return ErrTooManyWorkspaces
+	}
+	return nil
+}
+
+func validateWorkspaceRequest(req models.WorkspaceRequest) (string, string, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return "", "", fmt.Errorf("%w: name is required", ErrInvalidWorkspace)
+	}
+	if utf8.RuneCountInString(name) > maxWorkspaceNameLength {
+		return "", "", fmt.Errorf("%w: name must be at most %d characters", ErrInvalidWorkspace, maxWorkspaceNameLength)
+	}
+	description := strings.TrimSpace(req.Description)
+	if utf8.RuneCountInString(description) > maxWorkspaceDescriptionLength {
+		return "", "", fmt.Errorf("%w: description must be at most %d characters", ErrInvalidWorkspace, maxWorkspaceDescriptionLength)
+	}
+	return name, description, nil
+}
+
+func countWorkspaceAdmins(workspace *models.Workspace) int {
+	admins := 0
+	for _, m := range workspace.Members {
+		if m.Role == models.WorkspaceRoleAdmin {
+			admins++
+		}
+	}
+	return admins
+}
+
+// errWorkspaceWishlistsReadOnly is returned by every write to
+// workspaceWishlists; nothing should write through it.
+var errWorkspaceWishlistsReadOnly = errors.New("workspace wishlists are read-only")
+
+// workspaceWishlists presents each workspace as a wishlist keyed by its hex
+// ID, holding the quantity of each item still needed, so MaterialResolver can
+// resolve workspaces like user wishlists.
+type workspaceWishlists struct {
+	workspaceRepo repository.WorkspaceRepositoryInterface
+}
+
+func (w *workspaceWishlists) GetByUserID(ctx context.Context, id string) (*models.Wishlist, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, nil
+	}
+	workspace, err := w.workspaceRepo.GetByID(ctx, objectID)
+	if err != nil || workspace == nil {
+		return nil, err
+	}
+	wishlist := &models.Wishlist{UserID: id, Items: []models.WishlistItem{}, CreatedAt: workspace.CreatedAt, UpdatedAt: workspace.UpdatedAt}
+	for i := range workspace.Items {
+		item := &workspace.Items[i]
+		if remaining := item.Remaining(); remaining > 0 {
+			wishlist.Items = append(wishlist.Items, models.WishlistItem{UniqueName: item.UniqueName, Quantity: remaining, AddedAt: item.AddedAt})
+		}
+	}
+	return wishlist, nil
+}
+
+func (w *workspaceWishlists) Create(ctx context.Context, wishlist *models.Wishlist) error {
+	return errWorkspaceWishlistsReadOnly
+}
+
+func (w *workspaceWishlists) CreateIfAbsent(ctx context.Context, wishlist *models.Wishlist) (bool, error) {
+	return false, errWorkspaceWishlistsReadOnly
+}
+
+func (w *workspaceWishlists) AddItem(ctx context.Context, userID string, item models.WishlistItem) error {
+	return errWorkspaceWishlistsReadOnly
+}
+
+func (w *workspaceWishlists) RemoveItem(ctx context.Context, userID, uniqueName string) error {
+	return errWorkspaceWishlistsReadOnly
+}
+
+func (w *workspaceWishlists) UpdateItemQuantity(ctx context.Context, userID, uniqueName string, quantity int) error {
+	return errWorkspaceWishlistsReadOnly
+}
+
+func (w *workspaceWishlists) SetItemLinks(ctx context.Context, userID, uniqueName string, links []models.SourceLink) error {
+	return errWorkspaceWishlistsReadOnly
+}
+
+func (w *workspaceWishlists) SetItemRecipe(ctx context.Context, userID, uniqueName, recipeID string) error {
+	return errWorkspaceWishlistsReadOnly
+}
+
+func (w *workspaceWishlists) SetItemProgress(ctx context.Context, userID, uniqueName string, progress []models.ComponentProgress) error {
+	return errWorkspaceWishlistsReadOnly
+}
+
+func (w *workspaceWishlists) Upsert(ctx context.Context, wishlist *models.Wishlist) error {
+	return errWorkspaceWishlistsReadOnly
+}
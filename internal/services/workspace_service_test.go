@@ -0,0 +1,438 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/repository/memory"
+)
+
+// conflictingWorkspaceRepository reports a concurrent change for the first
+// conflicts updates, as a busy clan's simultaneous edits would.
+type conflictingWorkspaceRepository struct {
+	*memory.WorkspaceRepository
+	conflicts int
+}
+
+func (r *conflictingWorkspaceRepository) Update(ctx context.Context, workspace *models.Workspace) (bool, error) {
+	if r.conflicts > 0 {
+		r.conflicts--
+		return false, nil
+	}
+	return r.WorkspaceRepository.Update(ctx, workspace)
+}
+
+func newWorkspaceFixture(t *testing.T) (*WorkspaceService, *models.Workspace) {
+	t.Helper()
+	items := memory.NewItemRepository()
+	items.Add("misc",
+		models.Item{
+			UniqueName: "/Lotus/Types/Reactor",
+			Name:       "Orokin Reactor",
+			Components: []models.Component{{UniqueName: "/Lotus/Types/Alloy", Name: "Alloy Plate", ItemCount: 100}},
+		},
+		models.Item{UniqueName: "/Lotus/Types/Alloy", Name: "Alloy Plate"},
+	)
+	service := NewWorkspaceService(memory.NewWorkspaceRepository(), memory.NewWorkspaceContributionRepository(), items)
+	service.now = func() time.Time { return time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC) }
+
+	ctx := context.Background()
+	workspace, err := service.Create(ctx, "admin", models.WorkspaceRequest{Name: "  Dojo research  "})
+	if err != nil {
+		t.Fatalf("unexpected error creating workspace: %v", err)
+	}
+	id := workspace.ID.Hex()
+	for _, member := range []models.WorkspaceMemberRequest{
+		{UserID: "editor", Role: models.WorkspaceRoleEditor},
+		{UserID: "viewer", Role: models.WorkspaceRoleViewer},
+	} {
+		if workspace, err = service.AddMember(ctx, "admin", id, member); err != nil {
+			t.Fatalf("unexpected error adding %s: %v", member.UserID, err)
+		}
+	}
+	return service, workspace
+}
+
+func TestWorkspaceService_Create(t *testing.T) {
+	service, workspace := newWorkspaceFixture(t)
+	ctx := context.Background()
+
+	if workspace.Name != "Dojo research" {
+		t.Errorf("expected a trimmed name, got %q", workspace.Name)
+	}
+	if m := workspace.Member("admin"); m == nil || m.Role != models.WorkspaceRoleAdmin {
+		t.Errorf("expected the creator to be admin, got %+v", workspace.Members)
+	}
+
+	if _, err := service.Create(ctx, "admin", models.WorkspaceRequest{Name: "  "}); !errors.Is(err, ErrInvalidWorkspace) {
+		t.Errorf("expected ErrInvalidWorkspace for a blank name, got %v", err)
+	}
+
+	for i := 1; i < models.MaxWorkspacesPerUser; i++ {
+		if _, err := service.Create(ctx, "admin", models.WorkspaceRequest{Name: fmt.Sprintf("Workspace %d", i)}); err != nil {
+			t.Fatalf("unexpected error creating workspace %d: %v", i, err)
+		}
+	}
+	if _, err := service.Create(ctx, "admin", models.WorkspaceRequest{Name: "One too many"}); !errors.Is(err, ErrTooManyWorkspaces) {
+		t.Errorf("expected ErrTooManyWorkspaces, got %v", err)
+	}
+	if _, err := service.AddMember(ctx, "editor", workspace.ID.Hex(), models.WorkspaceMemberRequest{UserID: "x", Role: models.WorkspaceRoleViewer}); !errors.Is(err, ErrWorkspaceForbidden) {
+		t.Errorf("expected ErrWorkspaceForbidden for an editor adding members, got %v", err)
+	}
+}
+
+func TestWorkspaceService_OnlyMembersSeeTheWorkspace(t *testing.T) {
+	service, workspace := newWorkspaceFixture(t)
+	ctx := context.Background()
+	id := workspace.ID.Hex()
+
+	if _, err := service.Get(ctx, "viewer", id); err != nil {
+		t.Errorf("expected a viewer to see the workspace, got %v", err)
+	}
+	for _, tc := range []struct{ userID, id string }{
+		{"outsider", id},
+		{"admin", "not-an-id"},
+		{"admin", "000000000000000000000000"},
+	} {
+		if _, err := service.Get(ctx, tc.userID, tc.id); !errors.Is(err, ErrWorkspaceNotFound) {
+			t.Errorf("expected ErrWorkspaceNotFound for %s on %s, got %v", tc.userID, tc.id, err)
+		}
+	}
+	if _, err := service.GetMaterials(ctx, "outsider", id); !errors.Is(err, ErrWorkspaceNotFound) {
+		t.Errorf("expected materials to be hidden from non-members, got %v", err)
+	}
+
+	listed, err := service.List(ctx, "viewer")
+	if err != nil || len(listed) != 1 {
+		t.Errorf("expected the viewer's one workspace, got %v, %v", listed, err)
+	}
+}
+
+func TestWorkspaceService_Roles(t *testing.T) {
+	service, workspace := newWorkspaceFixture(t)
+	ctx := context.Background()
+	id := workspace.ID.Hex()
+	item := models.WorkspaceItemRequest{UniqueName: "/Lotus/Types/Reactor", Quantity: 3}
+
+	if _, err := service.AddItem(ctx, "viewer", id, item); !errors.Is(err, ErrWorkspaceForbidden) {
+		t.Errorf("expected viewers not to add items, got %v", err)
+	}
+	if _, err := service.AddItem(ctx, "editor", id, item); err != nil {
+		t.Fatalf("expected editors to add items, got %v", err)
+	}
+	if _, err := service.AddItem(ctx, "editor", id, item); !errors.Is(err, ErrWorkspaceItemExists) {
+		t.Errorf("expected ErrWorkspaceItemExists, got %v", err)
+	}
+	if _, err := service.AddItem(ctx, "editor", id, models.WorkspaceItemRequest{UniqueName: "/Lotus/Missing"}); !errors.Is(err, ErrItemNotFound) {
+		t.Errorf("expected ErrItemNotFound for an unknown item, got %v", err)
+	}
+	if _, err := service.Update(ctx, "editor", id, models.WorkspaceRequest{Name: "Renamed"}); !errors.Is(err, ErrWorkspaceForbidden) {
+		t.Errorf("expected editors not to rename the workspace, got %v", err)
+	}
+	if err := service.Delete(ctx, "editor", id); !errors.Is(err, ErrWorkspaceForbidden) {
+		t.Errorf("expected editors not to delete the workspace, got %v", err)
+	}
+
+	updated, err := service.SetMemberRole(ctx, "admin", id, "viewer", models.WorkspaceRoleEditor)
+	if err != nil {
+		t.Fatalf("unexpected error promoting viewer: %v", err)
+	}
+	if updated.Member("viewer").Role != models.WorkspaceRoleEditor {
+		t.Errorf("expected viewer to be promoted, got %+v", updated.Members)
+	}
+	if _, err := service.UpdateItemQuantity(ctx, "viewer", id, "/Lotus/Types/Reactor", 5); err != nil {
+		t.Errorf("expected the promoted member to change quantities, got %v", err)
+	}
+	if _, err := service.SetMemberRole(ctx, "admin", id, "viewer", "owner"); !errors.Is(err, ErrInvalidWorkspace) {
+		t.Errorf("expected ErrInvalidWorkspace for an unknown role, got %v", err)
+	}
+}
+
+func TestWorkspaceService_LastAdmin(t *testing.T) {
+	service, workspace := newWorkspaceFixture(t)
+	ctx := context.Background()
+	id := workspace.ID.Hex()
+
+	if _, err := service.SetMemberRole(ctx, "admin", id, "admin", models.WorkspaceRoleEditor); !errors.Is(err, ErrLastWorkspaceAdmin) {
+		t.Errorf("expected ErrLastWorkspaceAdmin demoting the only admin, got %v", err)
+	}
+	if _, err := service.RemoveMember(ctx, "admin", id, "admin"); !errors.Is(err, ErrLastWorkspaceAdmin) {
+		t.Errorf("expected ErrLastWorkspaceAdmin when the only admin leaves, got %v", err)
+	}
+
+	if _, err := service.RemoveMember(ctx, "editor", id, "viewer"); !errors.Is(err, ErrWorkspaceForbidden) {
+		t.Errorf("expected editors not to remove other members, got %v", err)
+	}
+	updated, err := service.RemoveMember(ctx, "viewer", id, "viewer")
+	if err != nil {
+		t.Fatalf("expected members to leave on their own, got %v", err)
+	}
+	if updated.Member("viewer") != nil {
+		t.Errorf("expected viewer to be gone, got %+v", updated.Members)
+	}
+	if _, err := service.RemoveMember(ctx, "admin", id, "viewer"); !errors.Is(err, ErrWorkspaceMemberNotFound) {
+		t.Errorf("expected ErrWorkspaceMemberNotFound, got %v", err)
+	}
+}
+
+func TestWorkspaceService_ContributionsAndMaterials(t *testing.T) {
+	service, workspace := newWorkspaceFixture(t)
+	ctx := context.Background()
+	id := workspace.ID.Hex()
+
+	if _, err := service.AddItem(ctx, "editor", id, models.WorkspaceItemRequest{UniqueName: "/Lotus/Types/Reactor", Quantity: 3}); err != nil {
+		t.Fatalf("unexpected error adding item: %v", err)
+	}
+	if _, err := service.SetContribution(ctx, "viewer", id, "/Lotus/Types/Reactor", 1); err != nil {
+		t.Fatalf("expected viewers to record contributions, got %v", err)
+	}
+	updated, err := service.SetContribution(ctx, "editor", id, "/Lotus/Types/Reactor", 1)
+	if err != nil {
+		t.Fatalf("unexpected error recording contribution: %v", err)
+	}
+	item := updated.Item("/Lotus/Types/Reactor")
+	if len(item.Contributions) != 2 || item.Contributed() != 2 || item.Remaining() != 1 {
+		t.Errorf("expected two contributions leaving 1, got %+v", item)
+	}
+
+	materials, err := service.GetMaterials(ctx, "viewer", id)
+	if err != nil {
+		t.Fatalf("unexpected error getting materials: %v", err)
+	}
+	if len(materials.Materials) != 1 || materials.Materials[0].TotalCount != 100 {
+		t.Errorf("expected materials for the one reactor still needed, got %+v", materials.Materials)
+	}
+
+	if _, err := service.SetContribution(ctx, "viewer", id, "/Lotus/Types/Reactor", 2); err != nil {
+		t.Fatalf("unexpected error recording contribution: %v", err)
+	}
+	materials, err = service.GetMaterials(ctx, "admin", id)
+	if err != nil {
+		t.Fatalf("unexpected error getting materials: %v", err)
+	}
+	if len(materials.Materials) != 0 {
+		t.Errorf("expected no materials once contributions cover the item, got %+v", materials.Materials)
+	}
+
+	updated, err = service.SetContribution(ctx, "viewer", id, "/Lotus/Types/Reactor", 0)
+	if err != nil {
+		t.Fatalf("unexpected error clearing contribution: %v", err)
+	}
+	if c := updated.Item("/Lotus/Types/Reactor").Contributions; len(c) != 1 || c[0].UserID != "editor" {
+		t.Errorf("expected only the editor's contribution to remain, got %+v", c)
+	}
+	if _, err := service.SetContribution(ctx, "viewer", id, "/Lotus/Types/Reactor", -1); !errors.Is(err, ErrInvalidWorkspace) {
+		t.Errorf("expected ErrInvalidWorkspace for a negative contribution, got %v", err)
+	}
+	if _, err := service.SetContribution(ctx, "viewer", id, "/Lotus/Types/Alloy", 1); !errors.Is(err, ErrWorkspaceItemNotFound) {
+		t.Errorf("expected ErrWorkspaceItemNotFound, got %v", err)
+	}
+
+	activity, err := service.ListActivity(ctx, "viewer", id, 0)
+	if err != nil {
+		t.Fatalf("unexpected error listing activity: %v", err)
+	}
+	var deltas []int
+	for _, event := range activity {
+		if event.Kind != models.ContributionKindItem {
+			t.Errorf("expected item contributions only, got %+v", event)
+		}
+		deltas = append(deltas, event.Quantity)
+	}
+	if fmt.Sprint(deltas) != "[-2 1 1 1]" {
+		t.Errorf("expected the changes newest first, got %v", deltas)
+	}
+}
+
+func TestWorkspaceService_RetriesConcurrentChanges(t *testing.T) {
+	service, workspace := newWorkspaceFixture(t)
+	ctx := context.Background()
+	id := workspace.ID.Hex()
+	repo := &conflictingWorkspaceRepository{WorkspaceRepository: service.workspaceRepo.(*memory.WorkspaceRepository)}
+	service.workspaceRepo = repo
+
+	repo.conflicts = workspaceUpdateAttempts - 1
+	if _, err := service.Update(ctx, "admin", id, models.WorkspaceRequest{Name: "Renamed"}); err != nil {
+		t.Fatalf("expected the change to be re-applied, got %v", err)
+	}
+
+	repo.conflicts = workspaceUpdateAttempts
+	if _, err := service.Update(ctx, "admin", id, models.WorkspaceRequest{Name: "Renamed again"}); !errors.Is(err, ErrWorkspaceConflict) {
+		t.Errorf("expected ErrWorkspaceConflict after every attempt conflicted, got %v", err)
+	}
+
+	got, err := service.Get(ctx, "admin", id)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Name != "Renamed" {
+		t.Errorf("expected the retried rename to be stored, got %q", got.Name)
+	}
+}
+
+func TestWorkspaceService_MaterialContributions(t *testing.T) {
+	service, workspace := newWorkspaceFixture(t)
+	ctx := context.Background()
+	id := workspace.ID.Hex()
+
+	if _, err := service.AddItem(ctx, "editor", id, models.WorkspaceItemRequest{UniqueName: "/Lotus/Types/Reactor", Quantity: 2}); err != nil {
+		t.Fatalf("unexpected error adding item: %v", err)
+	}
+	for _, c := range []struct {
+		userID   string
+		quantity int
+	}{{"viewer", 50}, {"editor", 80}, {"viewer", -20}} {
+		if _, err := service.LogMaterialContribution(ctx, c.userID, id, models.WorkspaceMaterialContributionRequest{UniqueName: "/Lotus/Types/Alloy", Quantity: c.quantity}); err != nil {
+			t.Fatalf("unexpected error logging %d for %s: %v", c.quantity, c.userID, err)
+		}
+	}
+
+	materials, err := service.GetMaterials(ctx, "admin", id)
+	if err != nil {
+		t.Fatalf("unexpected error getting materials: %v", err)
+	}
+	if len(materials.Materials) != 1 {
+		t.Fatalf("expected one material, got %+v", materials.Materials)
+	}
+	alloy := materials.Materials[0]
+	if alloy.TotalCount != 200 || alloy.Contributed != 110 || alloy.Remaining != 90 || len(alloy.Contributions) != 2 {
+		t.Errorf("expected 110 of 200 alloy contributed leaving 90, got %+v", alloy)
+	}
+	if len(materials.Members) != 2 || materials.Members[0] != (models.MemberContribution{UserID: "editor", Quantity: 80}) {
+		t.Errorf("expected the editor's 80 to lead the member totals, got %+v", materials.Members)
+	}
+
+	// Contributions beyond what is needed leave nothing remaining.
+	if _, err := service.LogMaterialContribution(ctx, "admin", id, models.WorkspaceMaterialContributionRequest{UniqueName: "/Lotus/Types/Alloy", Quantity: 500}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if materials, _ = service.GetMaterials(ctx, "admin", id); materials.Materials[0].Remaining != 0 {
+		t.Errorf("expected nothing remaining, got %+v", materials.Materials[0])
+	}
+
+	activity, err := service.ListActivity(ctx, "viewer", id, 0)
+	if err != nil {
+		t.Fatalf("unexpected error listing activity: %v", err)
+	}
+	if len(activity) != 4 || activity[0].UserID != "admin" || activity[0].Kind != models.ContributionKindMaterial {
+		t.Errorf("expected the four contributions newest first, got %+v", activity)
+	}
+	if activity, _ = service.ListActivity(ctx, "viewer", id, 2); len(activity) != 2 {
+		t.Errorf("expected the limit to apply, got %d events", len(activity))
+	}
+
+	if err := service.Delete(ctx, "admin", id); err != nil {
+		t.Fatalf("unexpected error deleting workspace: %v", err)
+	}
+	if events, _ := service.contributionRepo.ListByWorkspace(ctx, workspace.ID, 10); len(events) != 0 {
+		t.Errorf("expected the activity to be deleted with the workspace, got %+v", events)
+	}
+}
+
+func TestWorkspaceService_LogMaterialContribution_Errors(t *testing.T) {
+	tests := []struct {
+		name          string
+		userID        string
+		req           models.WorkspaceMaterialContributionRequest
+		expectedError error
+	}{
+		{name: "zero quantity", userID: "viewer", req: models.WorkspaceMaterialContributionRequest{UniqueName: "/Lotus/Types/Alloy"}, expectedError: ErrInvalidWorkspace},
+		{name: "too large", userID: "viewer", req: models.WorkspaceMaterialContributionRequest{UniqueName: "/Lotus/Types/Alloy", Quantity: MaxWorkspaceQuantity + 1}, expectedError: ErrInvalidWorkspace},
+		{name: "material not needed", userID: "viewer", req: models.WorkspaceMaterialContributionRequest{UniqueName: "/Lotus/Types/Ferrite", Quantity: 5}, expectedError: ErrWorkspaceMaterialNotNeeded},
+		{name: "correction below zero", userID: "viewer", req: models.WorkspaceMaterialContributionRequest{UniqueName: "/Lotus/Types/Alloy", Quantity: -11}, expectedError: ErrInvalidWorkspace},
+		{name: "not a member", userID: "stranger", req: models.WorkspaceMaterialContributionRequest{UniqueName: "/Lotus/Types/Alloy", Quantity: 5}, expectedError: ErrWorkspaceNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, workspace := newWorkspaceFixture(t)
+			ctx := context.Background()
+			id := workspace.ID.Hex()
+			if _, err := service.AddItem(ctx, "editor", id, models.WorkspaceItemRequest{UniqueName: "/Lotus/Types/Reactor"}); err != nil {
+				t.Fatalf("unexpected error adding item: %v", err)
+			}
+			if _, err := service.LogMaterialContribution(ctx, "viewer", id, models.WorkspaceMaterialContributionRequest{UniqueName: "/Lotus/Types/Alloy", Quantity: 10}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			_, err := service.LogMaterialContribution(ctx, tt.userID, id, tt.req)
+			if !errors.Is(err, tt.expectedError) {
+				t.Errorf("expected %v, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+// recordingPublisher keeps every event published, by room.
+type recordingPublisher struct {
+	rooms  []string
+	events []models.WorkspaceEvent
+}
+
+func (p *recordingPublisher) Publish(room string, event any) {
+	p.rooms = append(p.rooms, room)
+	p.events = append(p.events, event.(models.WorkspaceEvent))
+}
+
+func TestWorkspaceService_PublishesChanges(t *testing.T) {
+	ctx := context.Background()
+	service, workspace := newWorkspaceFixture(t)
+	publisher := &recordingPublisher{}
+	service.SetPublisher(publisher)
+	id := workspace.ID.Hex()
+
+	if _, err := service.AddItem(ctx, "editor", id, models.WorkspaceItemRequest{UniqueName: "/Lotus/Types/Reactor", Quantity: 2}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := service.SetContribution(ctx, "viewer", id, "/Lotus/Types/Reactor", 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Rejected changes publish nothing.
+	if _, err := service.AddItem(ctx, "viewer", id, models.WorkspaceItemRequest{UniqueName: "/Lotus/Types/Alloy"}); !errors.Is(err, ErrWorkspaceForbidden) {
+		t.Fatalf("expected ErrWorkspaceForbidden, got %v", err)
+	}
+	if _, err := service.RemoveItem(ctx, "editor", id, "/Lotus/Types/Reactor"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := service.RemoveMember(ctx, "admin", id, "viewer"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := service.Delete(ctx, "admin", id); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []struct {
+		eventType, userID string
+	}{
+		{models.WorkspaceEventItemAdded, "editor"},
+		{models.WorkspaceEventItemUpdated, "viewer"},
+		{models.WorkspaceEventItemRemoved, "editor"},
+		{models.WorkspaceEventMemberRemoved, "admin"},
+		{models.WorkspaceEventDeleted, "admin"},
+	}
+	if len(publisher.events) != len(expected) {
+		t.Fatalf("expected %d events, got %+v", len(expected), publisher.events)
+	}
+	for i, e := range expected {
+		event := publisher.events[i]
+		if event.Type != e.eventType || event.UserID != e.userID || publisher.rooms[i] != id {
+			t.Errorf("event %d: expected %s by %s in %s, got %+v in %s", i, e.eventType, e.userID, id, event, publisher.rooms[i])
+		}
+	}
+	if added := publisher.events[0]; added.Item == nil || added.Item.Quantity != 2 || added.Version == 0 {
+		t.Errorf("expected the added item and version, got %+v", added)
+	}
+	if updated := publisher.events[1]; updated.Item == nil || updated.Item.Contributed() != 1 {
+		t.Errorf("expected the item with its contribution, got %+v", updated.Item)
+	}
+	if removed := publisher.events[2]; removed.Item != nil || removed.UniqueName != "/Lotus/Types/Reactor" {
+		t.Errorf("expected only the removed item's name, got %+v", removed)
+	}
+	if publisher.events[3].MemberID != "viewer" {
+		t.Errorf("expected the removed member, got %+v", publisher.events[3])
+	}
+}
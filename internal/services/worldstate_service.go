@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+	"github.com/graytonio/warframe-wishlist/internal/worldstate"
+	"github.com/graytonio/warframe-wishlist/pkg/logger"
+)
+
+// worldStateFetchTimeout bounds each world state download.
+const worldStateFetchTimeout = 30 * time.Second
+
+// WorldStateService polls the game's world state and keeps the latest copy in
+// memory. Every instance polls on its own; nothing is stored.
+type WorldStateService struct {
+	source   string
+	interval time.Duration
+	fetch    func(ctx context.Context) (*models.WorldState, error)
+
+	mu    sync.RWMutex
+	state *models.WorldState
+}
+
+// NewWorldStateService returns a service that reads the world state from
+// source, a URL or local file, every interval once Run is called. An empty
+// source selects worldstate.DefaultSource.
+func NewWorldStateService(source string, interval time.Duration) *WorldStateService {
+	if source == "" {
+		source = worldstate.DefaultSource
+	}
+	client := &http.Client{Timeout: worldStateFetchTimeout}
+	return &WorldStateService{
+		source:   source,
+		interval: interval,
+		fetch: func(ctx context.Context) (*models.WorldState, error) {
+			return worldstate.Fetch(ctx, client, source)
+		},
+	}
+}
+
+// Run refreshes the world state now and then every interval until ctx is
+// done. Failures are logged and retried on the next tick.
+func (s *WorldStateService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		if err := s.Refresh(ctx); err != nil {
+			logger.Error(ctx, "service: WorldStateService.Run - refresh failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Refresh fetches the world state once. A failed fetch keeps the previous
+// state.
+func (s *WorldStateService) Refresh(ctx context.Context) error {
+	logger.Debug(ctx, "service: WorldStateService.Refresh called", "source", s.source)
+
+	state, err := s.fetch(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.state = state
+	s.mu.Unlock()
+
+	logger.Debug(ctx, "service: WorldStateService.Refresh - completed", "eventCount", len(state.Events))
+	return nil
+}
+
+// Current returns the last fetched world state, or nil before the first
+// successful fetch.
+func (s *WorldStateService) Current() *models.WorldState {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.state
+}
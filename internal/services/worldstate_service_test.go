@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+func TestWorldStateService_Refresh(t *testing.T) {
+	service := NewWorldStateService("", time.Minute)
+	if service.Current() != nil {
+		t.Fatal("expected no world state before the first fetch")
+	}
+
+	fetched := &models.WorldState{Events: []models.WorldStateEvent{{ID: "alert1"}}, FetchedAt: time.Now()}
+	service.fetch = func(ctx context.Context) (*models.WorldState, error) { return fetched, nil }
+	if err := service.Refresh(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if service.Current() != fetched {
+		t.Errorf("expected the fetched world state, got %+v", service.Current())
+	}
+
+	fetchErr := errors.New("unavailable")
+	service.fetch = func(ctx context.Context) (*models.WorldState, error) { return nil, fetchErr }
+	if err := service.Refresh(context.Background()); !errors.Is(err, fetchErr) {
+		t.Errorf("expected the fetch error, got %v", err)
+	}
+	if service.Current() != fetched {
+		t.Error("expected a failed fetch to keep the previous world state")
+	}
+}
+
+func TestWorldStateService_RunFetchesImmediately(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "worldState.json")
+	if err := os.WriteFile(path, []byte(`{"ActiveMissions": [{"_id": {"$oid": "f1"}, "Node": "SolNode4", "Modifier": "VoidT2"}]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	service := NewWorldStateService(path, time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		service.Run(ctx)
+		close(done)
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for service.Current() == nil && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	state := service.Current()
+	if state == nil || len(state.Events) != 1 || state.Events[0].RelicTier != "Meso" {
+		t.Errorf("expected the Meso fissure from the local file, got %+v", state)
+	}
+}
@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileStore_Put(t *testing.T) {
+	dir := t.TempDir()
+	store := NewFileStore(dir)
+
+	if err := store.Put(context.Background(), "exports/2026-10-17/popular_items.csv", "text/csv", []byte("a,b\n")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "exports", "2026-10-17", "popular_items.csv"))
+	if err != nil {
+		t.Fatalf("failed to read object: %v", err)
+	}
+	if string(data) != "a,b\n" {
+		t.Errorf("unexpected contents %q", data)
+	}
+}
+
+func TestFileStore_Put_RejectsEscapingKeys(t *testing.T) {
+	store := NewFileStore(t.TempDir())
+
+	for _, key := range []string{"", "../outside.csv", "a/../../outside.csv", "/etc/passwd"} {
+		if err := store.Put(context.Background(), key, "text/csv", nil); err == nil {
+			t.Errorf("expected error for key %q", key)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New(Config{Backend: "file", Dir: t.TempDir()}); err != nil {
+		t.Errorf("file: unexpected error: %v", err)
+	}
+	if _, err := New(Config{Backend: "s3", Bucket: "b", AccessKeyID: "k", SecretAccessKey: "s"}); err != nil {
+		t.Errorf("s3: unexpected error: %v", err)
+	}
+	if _, err := New(Config{Backend: "s3"}); err == nil {
+		t.Error("expected error for s3 without bucket")
+	}
+	if _, err := New(Config{Backend: "ftp"}); !errors.Is(err, ErrUnknownBackend) {
+		t.Errorf("expected ErrUnknownBackend, got %v", err)
+	}
+}
@@ -0,0 +1,46 @@
+// Package storage writes export files to object storage. FileStore writes to a
+// local directory; S3Store speaks the S3 API with SigV4 signing, which also
+// covers GCS (via its S3-compatible XML API and HMAC keys) and MinIO.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ObjectStore stores objects by key. Keys use "/" as the separator.
+type ObjectStore interface {
+	Put(ctx context.Context, key, contentType string, body []byte) error
+}
+
+var ErrUnknownBackend = errors.New("unknown storage backend")
+
+// Config selects and configures an ObjectStore.
+type Config struct {
+	Backend         string // "file" or "s3"
+	Dir             string // file: root directory
+	Endpoint        string // s3: e.g. https://s3.us-east-1.amazonaws.com or https://storage.googleapis.com
+	Region          string // s3: signing region; GCS accepts "auto"
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+}
+
+func New(cfg Config) (ObjectStore, error) {
+	switch strings.ToLower(cfg.Backend) {
+	case "file":
+		if cfg.Dir == "" {
+			return nil, errors.New("file storage requires a directory")
+		}
+		return NewFileStore(cfg.Dir), nil
+	case "s3":
+		if cfg.Bucket == "" || cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+			return nil, errors.New("s3 storage requires a bucket and credentials")
+		}
+		return NewS3Store(cfg.Endpoint, cfg.Region, cfg.Bucket, cfg.AccessKeyID, cfg.SecretAccessKey), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownBackend, cfg.Backend)
+	}
+}
@@ -0,0 +1,224 @@
+// Package worldstate reads the game's world state, from the official API or
+// a local copy, and extracts the alerts, invasions and void fissures that
+// reward items.
+package worldstate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+// DefaultSource is the official PC world state.
+const DefaultSource = "https://content.warframe.com/dynamic/worldState.php"
+
+// maxWorldStateSize bounds a downloaded world state; it is usually under 1 MB.
+const maxWorldStateSize = 16 << 20
+
+// relicTiers maps fissure modifiers to the relic tier they open.
+var relicTiers = map[string]string{
+	"VoidT1": "Lith",
+	"VoidT2": "Meso",
+	"VoidT3": "Neo",
+	"VoidT4": "Axi",
+	"VoidT5": "Requiem",
+	"VoidT6": "Omnia",
+}
+
+// Fetch reads and parses the world state from source, a URL or a local file.
+func Fetch(ctx context.Context, client *http.Client, source string) (*models.WorldState, error) {
+	data, err := read(ctx, client, source)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data, time.Now())
+}
+
+func read(ctx context.Context, client *http.Client, source string) ([]byte, error) {
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		return os.ReadFile(source)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download world state: status %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxWorldStateSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("download world state: %w", err)
+	}
+	if len(data) > maxWorldStateSize {
+		return nil, fmt.Errorf("download world state: larger than %d bytes", maxWorldStateSize)
+	}
+	return data, nil
+}
+
+// mongoDate is a date as the world state encodes it:
+// {"$date": {"$numberLong": "<unix millis>"}}.
+type mongoDate struct {
+	Date struct {
+		NumberLong string `json:"$numberLong"`
+	} `json:"$date"`
+}
+
+func (d mongoDate) time() time.Time {
+	millis, err := strconv.ParseInt(d.Date.NumberLong, 10, 64)
+	if err != nil || millis == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(millis).UTC()
+}
+
+type objectID struct {
+	OID string `json:"$oid"`
+}
+
+type countedItem struct {
+	ItemType  string `json:"ItemType"`
+	ItemCount int    `json:"ItemCount"`
+}
+
+// reward is an alert or invasion reward. Invasions without a reward for a
+// side, such as the Infested one, have an empty array instead.
+type reward struct {
+	Items        []string      `json:"items"`
+	CountedItems []countedItem `json:"countedItems"`
+}
+
+func (r *reward) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		*r = reward{}
+		return nil
+	}
+	type plain reward
+	return json.Unmarshal(data, (*plain)(r))
+}
+
+func (r reward) rewards() []models.WorldStateReward {
+	rewards := make([]models.WorldStateReward, 0, len(r.Items)+len(r.CountedItems))
+	for _, item := range r.Items {
+		rewards = append(rewards, models.WorldStateReward{UniqueName: ItemUniqueName(item), Count: 1})
+	}
+	for _, item := range r.CountedItems {
+		rewards = append(rewards, models.WorldStateReward{UniqueName: ItemUniqueName(item.ItemType), Count: max(item.ItemCount, 1)})
+	}
+	return rewards
+}
+
+type rawWorldState struct {
+	Alerts []struct {
+		ID          objectID  `json:"_id"`
+		Activation  mongoDate `json:"Activation"`
+		Expiry      mongoDate `json:"Expiry"`
+		MissionInfo struct {
+			MissionType   string `json:"missionType"`
+			Location      string `json:"location"`
+			MissionReward reward `json:"missionReward"`
+		} `json:"MissionInfo"`
+	} `json:"Alerts"`
+	Invasions []struct {
+		ID             objectID  `json:"_id"`
+		Node           string    `json:"Node"`
+		Completed      bool      `json:"Completed"`
+		Activation     mongoDate `json:"Activation"`
+		AttackerReward reward    `json:"AttackerReward"`
+		DefenderReward reward    `json:"DefenderReward"`
+	} `json:"Invasions"`
+	ActiveMissions []struct {
+		ID          objectID  `json:"_id"`
+		Node        string    `json:"Node"`
+		MissionType string    `json:"MissionType"`
+		Modifier    string    `json:"Modifier"`
+		Hard        bool      `json:"Hard"`
+		Activation  mongoDate `json:"Activation"`
+		Expiry      mongoDate `json:"Expiry"`
+	} `json:"ActiveMissions"`
+}
+
+// Parse extracts the events from a world state document. Completed
+// invasions and fissures of unknown tiers are left out.
+func Parse(data []byte, fetchedAt time.Time) (*models.WorldState, error) {
+	var raw rawWorldState
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid world state: %w", err)
+	}
+
+	state := &models.WorldState{Events: []models.WorldStateEvent{}, FetchedAt: fetchedAt}
+	for _, alert := range raw.Alerts {
+		state.Events = append(state.Events, models.WorldStateEvent{
+			ID:          alert.ID.OID,
+			Kind:        models.WorldStateAlert,
+			Node:        alert.MissionInfo.Location,
+			MissionType: MissionTypeName(alert.MissionInfo.MissionType),
+			Rewards:     alert.MissionInfo.MissionReward.rewards(),
+			Activation:  alert.Activation.time(),
+			Expiry:      alert.Expiry.time(),
+		})
+	}
+	for _, invasion := range raw.Invasions {
+		if invasion.Completed {
+			continue
+		}
+		state.Events = append(state.Events, models.WorldStateEvent{
+			ID:         invasion.ID.OID,
+			Kind:       models.WorldStateInvasion,
+			Node:       invasion.Node,
+			Rewards:    append(invasion.AttackerReward.rewards(), invasion.DefenderReward.rewards()...),
+			Activation: invasion.Activation.time(),
+		})
+	}
+	for _, mission := range raw.ActiveMissions {
+		tier, ok := relicTiers[mission.Modifier]
+		if !ok {
+			continue
+		}
+		state.Events = append(state.Events, models.WorldStateEvent{
+			ID:          mission.ID.OID,
+			Kind:        models.WorldStateFissure,
+			Node:        mission.Node,
+			MissionType: MissionTypeName(mission.MissionType),
+			RelicTier:   tier,
+			SteelPath:   mission.Hard,
+			Rewards:     []models.WorldStateReward{},
+			Activation:  mission.Activation.time(),
+			Expiry:      mission.Expiry.time(),
+		})
+	}
+	return state, nil
+}
+
+// ItemUniqueName maps a reward's store item path to the uniqueName of the
+// item it grants, which lacks the StoreItems segment.
+func ItemUniqueName(storeItem string) string {
+	return strings.Replace(storeItem, "/Lotus/StoreItems/", "/Lotus/", 1)
+}
+
+// MissionTypeName turns a mission type such as MT_MOBILE_DEFENSE into
+// "Mobile Defense".
+func MissionTypeName(missionType string) string {
+	words := strings.Split(strings.ToLower(strings.TrimPrefix(missionType, "MT_")), "_")
+	for i, word := range words {
+		if word != "" {
+			words[i] = strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,171 @@
+package worldstate
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/graytonio/warframe-wishlist/internal/models"
+)
+
+// sampleWorldState trims the official world state to the parts parsed: an
+// alert, an active and a completed invasion (one side rewarding nothing)
+// and a normal, a Steel Path and a non-relic fissure.
+const sampleWorldState = `{
+	"WorldSeed": "ignored",
+	"Alerts": [{
+		"_id": {"$oid": "alert1"},
+		"Activation": {"$date": {"$numberLong": "1700000000000"}},
+		"Expiry": {"$date": {"$numberLong": "1700003600000"}},
+		"MissionInfo": {
+			"missionType": "MT_MOBILE_DEFENSE",
+			"location": "SolNode27",
+			"missionReward": {
+				"credits": 10000,
+				"items": ["/Lotus/StoreItems/Types/Recipes/Components/OrokinCatalystBlueprint"],
+				"countedItems": [{"ItemType": "/Lotus/StoreItems/Types/Items/MiscItems/OrokinCell", "ItemCount": 3}]
+			}
+		}
+	}],
+	"Invasions": [{
+		"_id": {"$oid": "invasion1"},
+		"Node": "SolNode64",
+		"Completed": false,
+		"Activation": {"$date": {"$numberLong": "1700000000000"}},
+		"AttackerReward": [],
+		"DefenderReward": {"countedItems": [{"ItemType": "/Lotus/StoreItems/Types/Items/Research/ChemComponent", "ItemCount": 3}]}
+	}, {
+		"_id": {"$oid": "invasion2"},
+		"Node": "SolNode65",
+		"Completed": true,
+		"AttackerReward": {"countedItems": [{"ItemType": "/Lotus/StoreItems/Types/Items/Research/BioComponent", "ItemCount": 3}]},
+		"DefenderReward": []
+	}],
+	"ActiveMissions": [{
+		"_id": {"$oid": "fissure1"},
+		"Node": "SolNode203",
+		"MissionType": "MT_CAPTURE",
+		"Modifier": "VoidT4",
+		"Activation": {"$date": {"$numberLong": "1700000000000"}},
+		"Expiry": {"$date": {"$numberLong": "1700005400000"}}
+	}, {
+		"_id": {"$oid": "fissure2"},
+		"Node": "SolNode4",
+		"MissionType": "MT_EXTERMINATION",
+		"Modifier": "VoidT1",
+		"Hard": true,
+		"Expiry": {"$date": {"$numberLong": "1700005400000"}}
+	}, {
+		"_id": {"$oid": "unknown"},
+		"Node": "SolNode5",
+		"MissionType": "MT_SURVIVAL",
+		"Modifier": "SomethingElse"
+	}]
+}`
+
+func TestParse(t *testing.T) {
+	fetchedAt := time.Date(2023, 11, 14, 23, 0, 0, 0, time.UTC)
+	state, err := Parse([]byte(sampleWorldState), fetchedAt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !state.FetchedAt.Equal(fetchedAt) {
+		t.Errorf("expected fetchedAt %v, got %v", fetchedAt, state.FetchedAt)
+	}
+	if len(state.Events) != 4 {
+		t.Fatalf("expected an alert, an invasion and two fissures, got %+v", state.Events)
+	}
+
+	alert := state.Events[0]
+	if alert.Kind != models.WorldStateAlert || alert.ID != "alert1" || alert.Node != "SolNode27" || alert.MissionType != "Mobile Defense" {
+		t.Errorf("unexpected alert %+v", alert)
+	}
+	if !alert.Activation.Equal(time.UnixMilli(1700000000000)) || !alert.Expiry.Equal(time.UnixMilli(1700003600000)) {
+		t.Errorf("unexpected alert times %v - %v", alert.Activation, alert.Expiry)
+	}
+	expectedRewards := []models.WorldStateReward{
+		{UniqueName: "/Lotus/Types/Recipes/Components/OrokinCatalystBlueprint", Count: 1},
+		{UniqueName: "/Lotus/Types/Items/MiscItems/OrokinCell", Count: 3},
+	}
+	if len(alert.Rewards) != 2 || alert.Rewards[0] != expectedRewards[0] || alert.Rewards[1] != expectedRewards[1] {
+		t.Errorf("expected rewards %+v, got %+v", expectedRewards, alert.Rewards)
+	}
+
+	invasion := state.Events[1]
+	if invasion.Kind != models.WorldStateInvasion || invasion.ID != "invasion1" || !invasion.Expiry.IsZero() {
+		t.Errorf("unexpected invasion %+v", invasion)
+	}
+	if len(invasion.Rewards) != 1 || invasion.Rewards[0].UniqueName != "/Lotus/Types/Items/Research/ChemComponent" || invasion.Rewards[0].Count != 3 {
+		t.Errorf("expected the defender reward only, got %+v", invasion.Rewards)
+	}
+
+	axi, lith := state.Events[2], state.Events[3]
+	if axi.Kind != models.WorldStateFissure || axi.RelicTier != "Axi" || axi.MissionType != "Capture" || axi.SteelPath || len(axi.Rewards) != 0 {
+		t.Errorf("unexpected fissure %+v", axi)
+	}
+	if lith.RelicTier != "Lith" || !lith.SteelPath || !lith.Activation.IsZero() {
+		t.Errorf("unexpected Steel Path fissure %+v", lith)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	if _, err := Parse([]byte(`{"Alerts": {}}`), time.Now()); err == nil {
+		t.Error("expected an error for a malformed world state")
+	}
+}
+
+func TestMissionTypeName(t *testing.T) {
+	for input, want := range map[string]string{
+		"MT_MOBILE_DEFENSE": "Mobile Defense",
+		"MT_SURVIVAL":       "Survival",
+		"":                  "",
+	} {
+		if got := MissionTypeName(input); got != want {
+			t.Errorf("MissionTypeName(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestFetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(sampleWorldState))
+	}))
+	defer server.Close()
+
+	state, err := Fetch(context.Background(), server.Client(), server.URL+"/worldState.php")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(state.Events) != 4 || state.FetchedAt.IsZero() {
+		t.Errorf("unexpected world state %+v", state)
+	}
+
+	if _, err := Fetch(context.Background(), server.Client(), server.URL+"/missing"); err == nil || !strings.Contains(err.Error(), "status 404") {
+		t.Errorf("expected a status error, got %v", err)
+	}
+}
+
+func TestFetch_LocalFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "worldState.json")
+	if err := os.WriteFile(path, []byte(sampleWorldState), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	state, err := Fetch(context.Background(), http.DefaultClient, path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(state.Events) != 4 {
+		t.Errorf("expected 4 events, got %d", len(state.Events))
+	}
+}
@@ -2,10 +2,14 @@ package logger
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"log/slog"
 	"os"
 	"runtime"
 	"strings"
+	"sync/atomic"
 )
 
 type contextKey string
@@ -13,13 +17,29 @@ type contextKey string
 const (
 	RequestIDKey contextKey = "requestID"
 	UserIDKey    contextKey = "userID"
+	TraceKey     contextKey = "trace"
 )
 
 var (
 	defaultLogger *slog.Logger
 	debugMode     bool
+	// anonymizeKey is the HMAC key user IDs are hashed with; nil when
+	// anonymization is off.
+	anonymizeKey atomic.Pointer[[]byte]
 )
 
+// userIDKeys are the log attributes holding user IDs, hashed when
+// anonymization is on. A new attribute naming a user goes here too.
+var userIDKeys = map[string]bool{
+	"userID":    true,
+	"memberID":  true,
+	"managerID": true,
+	"ownerID":   true,
+	"claimerID": true,
+	"viewerID":  true,
+	"callerID":  true,
+}
+
 // Init initializes the global logger with the specified level.
 // When level is "debug", log messages include source file and line number.
 func Init(level string) {
@@ -43,19 +63,44 @@ func Init(level string) {
 	}
 
 	opts := &slog.HandlerOptions{
-		Level: logLevel,
+		Level:       logLevel,
+		ReplaceAttr: anonymizeAttr,
 	}
 
 	if debugMode {
 		opts.AddSource = true
 	}
 
-	handler := slog.NewJSONHandler(os.Stdout, opts)
+	// The JSON handler accepts every level; traceHandler applies logLevel
+	// except for traced requests.
+	opts.Level = slog.LevelDebug
+	handler := &traceHandler{Handler: slog.NewJSONHandler(os.Stdout, opts), level: logLevel}
 	defaultLogger = slog.New(handler)
 	slog.SetDefault(defaultLogger)
 }
 
+// traceHandler enforces the configured level, letting every level through for
+// traced requests so one user's requests can be debugged without enabling
+// debug logging for everyone.
+type traceHandler struct {
+	slog.Handler
+	level slog.Level
+}
+
+func (h *traceHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level || IsTraced(ctx)
+}
+
+func (h *traceHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &traceHandler{Handler: h.Handler.WithAttrs(attrs), level: h.level}
+}
+
+func (h *traceHandler) WithGroup(name string) slog.Handler {
+	return &traceHandler{Handler: h.Handler.WithGroup(name), level: h.level}
+}
+
 // WithContext creates a logger with context values (requestID, userID) attached.
+// Entries of traced requests also carry trace=true.
 func WithContext(ctx context.Context) *slog.Logger {
 	logger := defaultLogger
 	if logger == nil {
@@ -70,46 +115,51 @@ func WithContext(ctx context.Context) *slog.Logger {
 		logger = logger.With("userID", userID)
 	}
 
+	if IsTraced(ctx) {
+		logger = logger.With("trace", true)
+	}
+
 	return logger
 }
 
 // Debug logs at debug level with context.
 func Debug(ctx context.Context, msg string, args ...any) {
 	logger := WithContext(ctx)
-	if debugMode {
+	if debugMode || IsTraced(ctx) {
 		args = appendSource(args)
 	}
-	logger.Debug(msg, args...)
+	logger.DebugContext(ctx, msg, args...)
 }
 
 // Info logs at info level with context.
 func Info(ctx context.Context, msg string, args ...any) {
 	logger := WithContext(ctx)
-	if debugMode {
+	if debugMode || IsTraced(ctx) {
 		args = appendSource(args)
 	}
-	logger.Info(msg, args...)
+	logger.InfoContext(ctx, msg, args...)
 }
 
 // Warn logs at warn level with context.
 func Warn(ctx context.Context, msg string, args ...any) {
 	logger := WithContext(ctx)
-	if debugMode {
+	if debugMode || IsTraced(ctx) {
 		args = appendSource(args)
 	}
-	logger.Warn(msg, args...)
+	logger.WarnContext(ctx, msg, args...)
 }
 
 // Error logs at error level with context.
 func Error(ctx context.Context, msg string, args ...any) {
 	logger := WithContext(ctx)
-	if debugMode {
+	if debugMode || IsTraced(ctx) {
 		args = appendSource(args)
 	}
-	logger.Error(msg, args...)
+	logger.ErrorContext(ctx, msg, args...)
 }
 
-// appendSource adds caller file:line to log arguments when debug mode is enabled.
+// appendSource adds caller file:line to log arguments when debug mode is
+// enabled or the request is traced.
 func appendSource(args []any) []any {
 	_, file, line, ok := runtime.Caller(2)
 	if ok {
@@ -126,6 +176,55 @@ func appendSource(args []any) []any {
 	return args
 }
 
+// EnableAnonymization hashes user IDs with key and drops remote addresses from
+// all log output from now on; call it right after Init, before anything is
+// logged. The same key yields the same hash for a user on every instance and
+// across restarts, so one user's entries can still be correlated. An empty
+// key still hashes, but without a secret the hashes of known IDs can be
+// recomputed.
+func EnableAnonymization(key string) {
+	k := []byte(key)
+	anonymizeKey.Store(&k)
+}
+
+// DisableAnonymization turns anonymization back off.
+func DisableAnonymization() {
+	anonymizeKey.Store(nil)
+}
+
+// Anonymizing reports whether EnableAnonymization has been called.
+func Anonymizing() bool {
+	return anonymizeKey.Load() != nil
+}
+
+// HashID returns the anonymized form of a user ID: "anon-" and the first 16
+// hex characters of its HMAC-SHA256. The ID is returned unchanged when
+// anonymization is off, and an empty ID stays empty.
+func HashID(id string) string {
+	key := anonymizeKey.Load()
+	if key == nil || id == "" {
+		return id
+	}
+	mac := hmac.New(sha256.New, *key)
+	mac.Write([]byte(id))
+	return "anon-" + hex.EncodeToString(mac.Sum(nil))[:16]
+}
+
+// anonymizeAttr is the handler's ReplaceAttr: when anonymizing it hashes
+// user IDs and removes remote addresses.
+func anonymizeAttr(groups []string, a slog.Attr) slog.Attr {
+	if !Anonymizing() {
+		return a
+	}
+	switch {
+	case a.Key == "remoteAddr":
+		return slog.Attr{}
+	case userIDKeys[a.Key] && a.Value.Kind() == slog.KindString:
+		return slog.String(a.Key, HashID(a.Value.String()))
+	}
+	return a
+}
+
 // ContextWithRequestID adds a request ID to the context.
 func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
 	return context.WithValue(ctx, RequestIDKey, requestID)
@@ -136,6 +235,18 @@ func ContextWithUserID(ctx context.Context, userID string) context.Context {
 	return context.WithValue(ctx, UserIDKey, userID)
 }
 
+// ContextWithTrace marks the context's request as traced: it is logged at
+// every level, with caller, whatever the configured level.
+func ContextWithTrace(ctx context.Context) context.Context {
+	return context.WithValue(ctx, TraceKey, true)
+}
+
+// IsTraced reports whether the context's request is traced.
+func IsTraced(ctx context.Context) bool {
+	traced, _ := ctx.Value(TraceKey).(bool)
+	return traced
+}
+
 // GetRequestID retrieves the request ID from context.
 func GetRequestID(ctx context.Context) string {
 	if requestID, ok := ctx.Value(RequestIDKey).(string); ok {
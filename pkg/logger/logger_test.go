@@ -0,0 +1,168 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// useTestLogger points the package logger at a buffer at the given level for
+// the duration of the test.
+func useTestLogger(t *testing.T, level slog.Level) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	previous, previousDebug := defaultLogger, debugMode
+	defaultLogger = slog.New(&traceHandler{
+		Handler: slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug, ReplaceAttr: anonymizeAttr}),
+		level:   level,
+	})
+	debugMode = false
+	t.Cleanup(func() {
+		defaultLogger, debugMode = previous, previousDebug
+	})
+	return &buf
+}
+
+func decodeLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var entries []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("failed to decode log line %q: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestTraceHandler_UntracedRequestsUseConfiguredLevel(t *testing.T) {
+	buf := useTestLogger(t, slog.LevelInfo)
+	ctx := ContextWithUserID(context.Background(), "user-123")
+
+	Debug(ctx, "hidden")
+	Info(ctx, "shown")
+
+	entries := decodeLines(t, buf)
+	if len(entries) != 1 || entries[0]["msg"] != "shown" {
+		t.Fatalf("expected only the info entry, got %v", entries)
+	}
+	if _, ok := entries[0]["trace"]; ok {
+		t.Error("expected untraced entries to carry no trace attribute")
+	}
+	if _, ok := entries[0]["caller"]; ok {
+		t.Error("expected no caller outside debug mode")
+	}
+}
+
+func TestTraceHandler_TracedRequestsLogEveryLevel(t *testing.T) {
+	buf := useTestLogger(t, slog.LevelError)
+	ctx := ContextWithTrace(ContextWithUserID(context.Background(), "user-123"))
+
+	Debug(ctx, "debug entry")
+	Info(ctx, "info entry")
+
+	entries := decodeLines(t, buf)
+	if len(entries) != 2 {
+		t.Fatalf("expected both entries, got %v", entries)
+	}
+	for _, entry := range entries {
+		if entry["trace"] != true || entry["userID"] != "user-123" {
+			t.Errorf("expected trace and userID attributes, got %v", entry)
+		}
+		if _, ok := entry["caller"]; !ok {
+			t.Errorf("expected traced entries to carry the caller, got %v", entry)
+		}
+	}
+}
+
+func TestIsTraced(t *testing.T) {
+	if IsTraced(context.Background()) {
+		t.Error("expected a plain context not to be traced")
+	}
+	if !IsTraced(ContextWithTrace(context.Background())) {
+		t.Error("expected ContextWithTrace to mark the context as traced")
+	}
+}
+
+func TestHashID(t *testing.T) {
+	if got := HashID("user-123"); got != "user-123" {
+		t.Errorf("expected IDs to be unchanged without anonymization, got %q", got)
+	}
+
+	EnableAnonymization("secret")
+	t.Cleanup(DisableAnonymization)
+
+	hashed := HashID("user-123")
+	if !strings.HasPrefix(hashed, "anon-") || len(hashed) != len("anon-")+16 || strings.Contains(hashed, "user-123") {
+		t.Errorf("unexpected hash %q", hashed)
+	}
+	if HashID("user-123") != hashed {
+		t.Error("expected the same ID to hash the same way")
+	}
+	if HashID("user-456") == hashed {
+		t.Error("expected different IDs to hash differently")
+	}
+	if HashID("") != "" {
+		t.Error("expected an empty ID to stay empty")
+	}
+
+	EnableAnonymization("other-secret")
+	if HashID("user-123") == hashed {
+		t.Error("expected the hash to depend on the key")
+	}
+}
+
+func TestAnonymization_HashesUserIDsAndDropsRemoteAddresses(t *testing.T) {
+	buf := useTestLogger(t, slog.LevelInfo)
+	EnableAnonymization("secret")
+	t.Cleanup(DisableAnonymization)
+	ctx := ContextWithUserID(context.Background(), "user-123")
+
+	Info(ctx, "request", "memberID", "member-1", "managerID", "manager-1", "remoteAddr", "203.0.113.7:5000", "uniqueName", "/Lotus/Ash")
+
+	entries := decodeLines(t, buf)
+	if len(entries) != 1 {
+		t.Fatalf("expected one entry, got %v", entries)
+	}
+	entry := entries[0]
+	expected := map[string]string{"userID": HashID("user-123"), "memberID": HashID("member-1"), "managerID": HashID("manager-1"), "uniqueName": "/Lotus/Ash"}
+	for key, value := range expected {
+		if entry[key] != value {
+			t.Errorf("%s: expected %q, got %v", key, value, entry[key])
+		}
+	}
+	if _, ok := entry["remoteAddr"]; ok {
+		t.Errorf("expected remoteAddr to be dropped, got %v", entry)
+	}
+	if strings.Contains(buf.String(), "user-123") || strings.Contains(buf.String(), "203.0.113.7") {
+		t.Errorf("expected no raw user ID or address in %s", buf.String())
+	}
+}
+
+func TestAnonymization_HashesGiftClaimUserIDs(t *testing.T) {
+	buf := useTestLogger(t, slog.LevelInfo)
+	EnableAnonymization("secret")
+	t.Cleanup(DisableAnonymization)
+
+	Info(context.Background(), "claim", "ownerID", "owner-1", "claimerID", "claimer-1", "viewerID", "viewer-1", "callerID", "caller-1")
+
+	entries := decodeLines(t, buf)
+	if len(entries) != 1 {
+		t.Fatalf("expected one entry, got %v", entries)
+	}
+	for key, id := range map[string]string{"ownerID": "owner-1", "claimerID": "claimer-1", "viewerID": "viewer-1", "callerID": "caller-1"} {
+		if entries[0][key] != HashID(id) {
+			t.Errorf("%s: expected %q, got %v", key, HashID(id), entries[0][key])
+		}
+		if strings.Contains(buf.String(), id) {
+			t.Errorf("expected no raw %s in %s", key, buf.String())
+		}
+	}
+}
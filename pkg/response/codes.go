@@ -0,0 +1,169 @@
+package response
+
+// Code is the stable, machine-readable identifier of an error, for clients
+// to branch on instead of the status or the message. Codes must never change
+// once released; add a new one instead. An error without a code of its own
+// is coded by its status, e.g. "not_found".
+type Code string
+
+// Authentication.
+const (
+	CodeUnauthenticated      Code = "unauthenticated"
+	CodeMissingAuthorization Code = "missing_authorization"
+	CodeInvalidAuthorization Code = "invalid_authorization"
+	CodeInvalidToken         Code = "invalid_token"
+	CodeInvalidTokenClaims   Code = "invalid_token_claims"
+	CodeMissingUserID        Code = "missing_user_id"
+	CodeInvalidAdminToken    Code = "invalid_admin_token"
+	CodeInvalidSyncToken     Code = "invalid_sync_token"
+	CodeInvalidStaffToken    Code = "invalid_staff_token"
+	CodeInsufficientRole     Code = "insufficient_role"
+)
+
+// Requests.
+const (
+	CodeInvalidRequestBody Code = "invalid_request_body"
+	CodeValidationFailed   Code = "validation_failed"
+	CodeRequired           Code = "required"
+	CodeTooSmall           Code = "too_small"
+	CodeTooLarge           Code = "too_large"
+	CodeUniqueNameRequired Code = "unique_name_required"
+	CodeInvalidUniqueName  Code = "invalid_unique_name"
+	CodeInvalidQuantity    Code = "invalid_quantity"
+	CodeInvalidTimeZone    Code = "invalid_time_zone"
+	CodeInvalidDryRun      Code = "invalid_dry_run"
+	CodeInvalidSince       Code = "invalid_since"
+	CodeUnknownColumn      Code = "unknown_column"
+	CodeUnknownInclude     Code = "unknown_include"
+	CodeUnknownCategory    Code = "unknown_category"
+	CodeWebSocketRequired  Code = "websocket_required"
+	CodeNotWebSocket       Code = "not_websocket"
+
+	CodeInvalidIdempotencyKey    Code = "invalid_idempotency_key"
+	CodeIdempotencyKeyReused     Code = "idempotency_key_reused"
+	CodeIdempotencyKeyInProgress Code = "idempotency_key_in_progress"
+)
+
+// Serving.
+const (
+	CodeServerBusy               Code = "server_busy"
+	CodeReadOnly                 Code = "read_only"
+	CodePrimaryRegionUnavailable Code = "primary_region_unavailable"
+	CodeRegionLoop               Code = "region_loop"
+)
+
+// Items and the wishlist.
+const (
+	CodeItemNotFound             Code = "item_not_found"
+	CodeItemNotInWishlist        Code = "item_not_in_wishlist"
+	CodeItemAlreadyInWishlist    Code = "item_already_in_wishlist"
+	CodeItemAlreadyOwned         Code = "item_already_owned"
+	CodeRecipeNotFound           Code = "recipe_not_found"
+	CodeWishlistFull             Code = "wishlist_full"
+	CodeInvalidProgress          Code = "invalid_progress"
+	CodeInvalidLink              Code = "invalid_link"
+	CodeTooManyLinks             Code = "too_many_links"
+	CodeApprovalRequired         Code = "approval_required"
+	CodePublicWishlistNotFound   Code = "public_wishlist_not_found"
+	CodeItemRefreshRunning       Code = "item_refresh_running"
+	CodeItemRefreshUnavailable   Code = "item_refresh_unavailable"
+	CodeInvalidResearchRequest   Code = "invalid_research_request"
+	CodeInvalidDefaultQuantities Code = "invalid_default_quantities"
+	CodeInvalidMilestones        Code = "invalid_milestones"
+	CodeInvalidMaterialsRounding Code = "invalid_materials_rounding"
+)
+
+// Owned blueprints, components, materials and mastery.
+const (
+	CodeBlueprintNotFound          Code = "blueprint_not_found"
+	CodeBlueprintNotReusable       Code = "blueprint_not_reusable"
+	CodeBlueprintAlreadyOwned      Code = "blueprint_already_owned"
+	CodeBlueprintNotOwned          Code = "blueprint_not_owned"
+	CodeInvalidBlueprintQuantity   Code = "invalid_blueprint_quantity"
+	CodeTooManyBlueprints          Code = "too_many_blueprints"
+	CodeComponentNotOwned          Code = "component_not_owned"
+	CodeInvalidComponentCount      Code = "invalid_component_count"
+	CodeMaterialNotOwned           Code = "material_not_owned"
+	CodeInvalidMaterialCount       Code = "invalid_material_count"
+	CodeTooManyMaterials           Code = "too_many_materials"
+	CodeItemNotInMasteryProfile    Code = "item_not_in_mastery_profile"
+	CodeInvalidMasteryStatus       Code = "invalid_mastery_status"
+	CodeTooManyMasteryItems        Code = "too_many_mastery_items"
+	CodeInvalidFoundryBuild        Code = "invalid_foundry_build"
+	CodeFoundryBuildNotFound       Code = "foundry_build_not_found"
+	CodeTooManyFoundryBuilds       Code = "too_many_foundry_builds"
+	CodeInvalidWishlistTemplate    Code = "invalid_wishlist_template"
+	CodeInvalidTemplateVariables   Code = "invalid_template_variables"
+	CodeWishlistTemplateNotFound   Code = "wishlist_template_not_found"
+	CodeTooManyWishlistTemplates   Code = "too_many_wishlist_templates"
+	CodeInvalidCustomItem          Code = "invalid_custom_item"
+	CodeCustomItemNotFound         Code = "custom_item_not_found"
+	CodeTooManyCustomItems         Code = "too_many_custom_items"
+	CodeUnsupportedFormat          Code = "unsupported_format"
+	CodeInvalidInventory           Code = "invalid_inventory"
+	CodeInventoryTooLarge          Code = "inventory_too_large"
+	CodeImportEmpty                Code = "import_empty"
+	CodeImportTooLarge             Code = "import_too_large"
+	CodeInvalidExportDocument      Code = "invalid_export_document"
+	CodeUnsupportedExportVersion   Code = "unsupported_export_version"
+	CodeInvalidImportMode          Code = "invalid_import_mode"
+	CodeExportTooLarge             Code = "export_too_large"
+	CodeMergeTooLarge              Code = "merge_too_large"
+	CodeWorkspaceMaterialNotNeeded Code = "workspace_material_not_needed"
+)
+
+// Sharing, gifts and households.
+const (
+	CodeShareLinkNotFound     Code = "share_link_not_found"
+	CodeInvalidShareLinkLabel Code = "invalid_share_link_label"
+	CodeTooManyShareLinks     Code = "too_many_share_links"
+	CodeSharedMaterialsHidden Code = "shared_materials_hidden"
+	CodeWishlistNotShared     Code = "wishlist_not_shared"
+	CodeCannotClaimOwnItem    Code = "cannot_claim_own_item"
+	CodeInvalidClaimDays      Code = "invalid_claim_days"
+	CodeItemAlreadyClaimed    Code = "item_already_claimed"
+	CodeClaimNotFound         Code = "claim_not_found"
+	CodeManagerUserIDRequired Code = "manager_user_id_required"
+	CodeCannotManageSelf      Code = "cannot_manage_self"
+	CodeInvalidThreshold      Code = "invalid_threshold"
+	CodeAlreadyLinked         Code = "already_linked"
+	CodeHouseholdLinkNotFound Code = "household_link_not_found"
+	CodeHouseholdLinkActive   Code = "household_link_active"
+	CodeChangeNotFound        Code = "pending_change_not_found"
+	CodeChangeAlreadyDecided  Code = "pending_change_already_decided"
+)
+
+// Workspaces.
+const (
+	CodeWorkspaceNotFound       Code = "workspace_not_found"
+	CodeWorkspaceForbidden      Code = "workspace_forbidden"
+	CodeInvalidWorkspace        Code = "invalid_workspace"
+	CodeWorkspaceConflict       Code = "workspace_conflict"
+	CodeTooManyWorkspaces       Code = "too_many_workspaces"
+	CodeWorkspaceMemberExists   Code = "workspace_member_exists"
+	CodeWorkspaceMemberNotFound Code = "workspace_member_not_found"
+	CodeTooManyWorkspaceMembers Code = "too_many_workspace_members"
+	CodeLastWorkspaceAdmin      Code = "last_workspace_admin"
+	CodeWorkspaceItemExists     Code = "workspace_item_exists"
+	CodeWorkspaceItemNotFound   Code = "workspace_item_not_found"
+	CodeTooManyWorkspaceItems   Code = "too_many_workspace_items"
+)
+
+// Notifications, integrations and support.
+const (
+	CodeNotificationChannelNotFound Code = "notification_channel_not_found"
+	CodeInvalidNotificationChannel  Code = "invalid_notification_channel"
+	CodeTooManyNotificationChannels Code = "too_many_notification_channels"
+	CodePushNotConfigured           Code = "push_not_configured"
+	CodeIntegrationNotFound         Code = "integration_not_found"
+	CodeInvalidIntegration          Code = "invalid_integration"
+	CodeTooManyIntegrations         Code = "too_many_integrations"
+	CodeUserTraceNotFound           Code = "user_trace_not_found"
+	CodeInvalidTraceDuration        Code = "invalid_trace_duration"
+	CodeTraceReasonTooLong          Code = "trace_reason_too_long"
+)
+
+// Warnings.
+const (
+	CodeDeprecated Code = "deprecated"
+)
@@ -0,0 +1,305 @@
+package response
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	ContentTypeJSON    = "application/json"
+	ContentTypeHAL     = "application/hal+json"
+	ContentTypeMsgpack = "application/msgpack"
+	ContentTypeCBOR    = "application/cbor"
+)
+
+// encoding is a binary alternative to JSON that clients can ask for with the
+// Accept header.
+type encoding struct {
+	contentType string
+	encode      func(data interface{}) ([]byte, error)
+}
+
+var (
+	msgpackEncoding = &encoding{contentType: ContentTypeMsgpack, encode: marshalMsgpack}
+	cborEncoding    = &encoding{contentType: ContentTypeCBOR, encode: marshalCBOR}
+)
+
+// Negotiate is a middleware that lets clients ask for msgpack or CBOR instead
+// of JSON with the Accept header. Anything else, including a missing header or
+// one that accepts none of the supported types, gets JSON.
+func Negotiate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept")
+		if enc := negotiateEncoding(r.Header.Get("Accept")); enc != nil {
+			w = &encodingWriter{ResponseWriter: w, encoding: enc}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// encodingWriter carries the negotiated encoding to JSON.
+type encodingWriter struct {
+	http.ResponseWriter
+	encoding *encoding
+}
+
+func (w *encodingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// negotiateEncoding returns the binary encoding the Accept header prefers, or
+// nil if JSON should be used. Ties in quality go to the type listed first.
+func negotiateEncoding(accept string) *encoding {
+	var best *encoding
+	bestQ := 0.0
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		if q <= bestQ {
+			continue
+		}
+
+		var enc *encoding
+		switch mediaType {
+		case ContentTypeJSON, ContentTypeHAL, "*/*", "application/*":
+		case ContentTypeMsgpack, "application/x-msgpack":
+			enc = msgpackEncoding
+		case ContentTypeCBOR:
+			enc = cborEncoding
+		default:
+			continue
+		}
+		best, bestQ = enc, q
+	}
+	return best
+}
+
+// toGeneric round-trips data through JSON, so binary encodings use the same
+// field names, omitempty rules, and custom marshalers as JSON responses.
+func toGeneric(data interface{}) (interface{}, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// sortedKeys returns the keys of m in order, so encoded output is stable.
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// number classifies a JSON number as a signed integer, an unsigned integer
+// too large for int64, or a float.
+func number(n json.Number) (i int64, u uint64, f float64, kind byte) {
+	if i, err := n.Int64(); err == nil {
+		return i, 0, 0, 'i'
+	}
+	if u, err := strconv.ParseUint(n.String(), 10, 64); err == nil {
+		return 0, u, 0, 'u'
+	}
+	f, _ = n.Float64()
+	return 0, 0, f, 'f'
+}
+
+func marshalMsgpack(data interface{}) ([]byte, error) {
+	v, err := toGeneric(data)
+	if err != nil {
+		return nil, err
+	}
+	return appendMsgpack(nil, v)
+}
+
+func appendMsgpack(b []byte, v interface{}) ([]byte, error) {
+	var err error
+	switch v := v.(type) {
+	case nil:
+		b = append(b, 0xc0)
+	case bool:
+		if v {
+			b = append(b, 0xc3)
+		} else {
+			b = append(b, 0xc2)
+		}
+	case json.Number:
+		i, u, f, kind := number(v)
+		switch kind {
+		case 'i':
+			b = appendMsgpackInt(b, i)
+		case 'u':
+			b = binary.BigEndian.AppendUint64(append(b, 0xcf), u)
+		default:
+			b = binary.BigEndian.AppendUint64(append(b, 0xcb), math.Float64bits(f))
+		}
+	case string:
+		b = appendMsgpackHeader(b, len(v), 0xa0, 32, 0xd9, 0xda, 0xdb)
+		b = append(b, v...)
+	case []interface{}:
+		b = appendMsgpackHeader(b, len(v), 0x90, 16, 0, 0xdc, 0xdd)
+		for _, item := range v {
+			if b, err = appendMsgpack(b, item); err != nil {
+				return nil, err
+			}
+		}
+	case map[string]interface{}:
+		b = appendMsgpackHeader(b, len(v), 0x80, 16, 0, 0xde, 0xdf)
+		for _, k := range sortedKeys(v) {
+			b = appendMsgpackHeader(b, len(k), 0xa0, 32, 0xd9, 0xda, 0xdb)
+			b = append(b, k...)
+			if b, err = appendMsgpack(b, v[k]); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, fmt.Errorf("msgpack: unsupported type %T", v)
+	}
+	return b, nil
+}
+
+// appendMsgpackHeader writes a string, array, or map header: the fix form
+// when n < fixMax, otherwise the 8- (if the type has one), 16-, or 32-bit form.
+func appendMsgpackHeader(b []byte, n int, fix byte, fixMax int, code8, code16, code32 byte) []byte {
+	switch {
+	case n < fixMax:
+		return append(b, fix|byte(n))
+	case code8 != 0 && n <= math.MaxUint8:
+		return append(b, code8, byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, code16), uint16(n))
+	default:
+		return binary.BigEndian.AppendUint32(append(b, code32), uint32(n))
+	}
+}
+
+func appendMsgpackInt(b []byte, i int64) []byte {
+	switch {
+	case i >= 0 && i <= math.MaxInt8:
+		return append(b, byte(i))
+	case i >= -32 && i < 0:
+		return append(b, byte(i))
+	case i >= 0 && i <= math.MaxUint8:
+		return append(b, 0xcc, byte(i))
+	case i >= 0 && i <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, 0xcd), uint16(i))
+	case i >= 0 && i <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(b, 0xce), uint32(i))
+	case i >= 0:
+		return binary.BigEndian.AppendUint64(append(b, 0xcf), uint64(i))
+	case i >= math.MinInt8:
+		return append(b, 0xd0, byte(i))
+	case i >= math.MinInt16:
+		return binary.BigEndian.AppendUint16(append(b, 0xd1), uint16(i))
+	case i >= math.MinInt32:
+		return binary.BigEndian.AppendUint32(append(b, 0xd2), uint32(i))
+	default:
+		return binary.BigEndian.AppendUint64(append(b, 0xd3), uint64(i))
+	}
+}
+
+// CBOR major types (RFC 8949, section 3.1).
+const (
+	cborUnsigned = 0 << 5
+	cborNegative = 1 << 5
+	cborText     = 3 << 5
+	cborArray    = 4 << 5
+	cborMap      = 5 << 5
+)
+
+func marshalCBOR(data interface{}) ([]byte, error) {
+	v, err := toGeneric(data)
+	if err != nil {
+		return nil, err
+	}
+	return appendCBOR(nil, v)
+}
+
+func appendCBOR(b []byte, v interface{}) ([]byte, error) {
+	var err error
+	switch v := v.(type) {
+	case nil:
+		b = append(b, 0xf6)
+	case bool:
+		if v {
+			b = append(b, 0xf5)
+		} else {
+			b = append(b, 0xf4)
+		}
+	case json.Number:
+		i, u, f, kind := number(v)
+		switch {
+		case kind == 'u':
+			b = appendCBORHeader(b, cborUnsigned, u)
+		case kind == 'i' && i >= 0:
+			b = appendCBORHeader(b, cborUnsigned, uint64(i))
+		case kind == 'i':
+			b = appendCBORHeader(b, cborNegative, uint64(-1-i))
+		default:
+			b = binary.BigEndian.AppendUint64(append(b, 0xfb), math.Float64bits(f))
+		}
+	case string:
+		b = appendCBORHeader(b, cborText, uint64(len(v)))
+		b = append(b, v...)
+	case []interface{}:
+		b = appendCBORHeader(b, cborArray, uint64(len(v)))
+		for _, item := range v {
+			if b, err = appendCBOR(b, item); err != nil {
+				return nil, err
+			}
+		}
+	case map[string]interface{}:
+		b = appendCBORHeader(b, cborMap, uint64(len(v)))
+		for _, k := range sortedKeys(v) {
+			b = appendCBORHeader(b, cborText, uint64(len(k)))
+			b = append(b, k...)
+			if b, err = appendCBOR(b, v[k]); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, fmt.Errorf("cbor: unsupported type %T", v)
+	}
+	return b, nil
+}
+
+// appendCBORHeader writes a major type with its argument in the shortest form.
+func appendCBORHeader(b []byte, major byte, n uint64) []byte {
+	switch {
+	case n < 24:
+		return append(b, major|byte(n))
+	case n <= math.MaxUint8:
+		return append(b, major|24, byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, major|25), uint16(n))
+	case n <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(b, major|26), uint32(n))
+	default:
+		return binary.BigEndian.AppendUint64(append(b, major|27), n)
+	}
+}
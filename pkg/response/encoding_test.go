@@ -0,0 +1,293 @@
+package response
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		name     string
+		accept   string
+		expected *encoding
+	}{
+		{name: "empty", accept: "", expected: nil},
+		{name: "json", accept: "application/json", expected: nil},
+		{name: "any", accept: "*/*", expected: nil},
+		{name: "msgpack", accept: "application/msgpack", expected: msgpackEncoding},
+		{name: "legacy msgpack", accept: "application/x-msgpack", expected: msgpackEncoding},
+		{name: "cbor", accept: "application/cbor", expected: cborEncoding},
+		{name: "first of equal quality wins", accept: "application/cbor, application/msgpack, application/json", expected: cborEncoding},
+		{name: "json listed first wins", accept: "application/json, application/cbor", expected: nil},
+		{name: "hal is json", accept: "application/hal+json, application/msgpack;q=0.5", expected: nil},
+		{name: "higher quality wins", accept: "application/json;q=0.5, application/msgpack", expected: msgpackEncoding},
+		{name: "browser default", accept: "text/html,application/xhtml+xml,*/*;q=0.8", expected: nil},
+		{name: "q=0 is refused", accept: "application/msgpack;q=0", expected: nil},
+		{name: "unsupported falls back to json", accept: "application/xml", expected: nil},
+		{name: "malformed entries are skipped", accept: "application/msgpack;q=abc, ;;, application/cbor", expected: cborEncoding},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := negotiateEncoding(tt.accept); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestMarshalMsgpack(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     interface{}
+		expected string
+	}{
+		{name: "nil", data: nil, expected: "c0"},
+		{name: "bools", data: []bool{true, false}, expected: "92c3c2"},
+		{name: "positive fixint", data: 127, expected: "7f"},
+		{name: "negative fixint", data: -1, expected: "ff"},
+		{name: "uint8", data: 200, expected: "ccc8"},
+		{name: "uint16", data: 1000, expected: "cd03e8"},
+		{name: "uint32", data: 70000, expected: "ce00011170"},
+		{name: "uint64", data: uint64(math.MaxUint64), expected: "cfffffffffffffffff"},
+		{name: "uint64 from int", data: 5000000000, expected: "cf000000012a05f200"},
+		{name: "int8", data: -100, expected: "d09c"},
+		{name: "int16", data: -200, expected: "d1ff38"},
+		{name: "int32", data: -70000, expected: "d2fffeee90"},
+		{name: "int64", data: int64(math.MinInt64), expected: "d38000000000000000"},
+		{name: "float", data: 1.5, expected: "cb3ff8000000000000"},
+		{name: "fixstr", data: "a", expected: "a161"},
+		{name: "str8", data: strings.Repeat("a", 32), expected: "d920" + strings.Repeat("61", 32)},
+		{name: "map with sorted keys", data: map[string]interface{}{"b": []interface{}{true, nil, "x"}, "a": 1}, expected: "82a16101a16293c3c0a178"},
+		{name: "struct uses json tags", data: ErrorResponse{Error: "Not Found"}, expected: "82a4636f6465a0a56572726f72a94e6f7420466f756e64"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := marshalMsgpack(tt.data)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if hex.EncodeToString(got) != tt.expected {
+				t.Errorf("expected %s, got %x", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestMarshalMsgpack_Headers(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   interface{}
+		prefix string
+	}{
+		{name: "str16", data: strings.Repeat("a", 256), prefix: "da0100"},
+		{name: "array16", data: make([]int, 16), prefix: "dc0010"},
+		{name: "array32", data: make([]int, 70000), prefix: "dd00011170"},
+		{name: "map16", data: stringKeyedMap(16), prefix: "de0010"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := marshalMsgpack(tt.data)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !strings.HasPrefix(hex.EncodeToString(got), tt.prefix) {
+				t.Errorf("expected prefix %s, got %x", tt.prefix, got[:8])
+			}
+		})
+	}
+}
+
+// TestMarshalCBOR uses the examples from RFC 8949, appendix A.
+func TestMarshalCBOR(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     interface{}
+		expected string
+	}{
+		{name: "0", data: 0, expected: "00"},
+		{name: "23", data: 23, expected: "17"},
+		{name: "24", data: 24, expected: "1818"},
+		{name: "1000", data: 1000, expected: "1903e8"},
+		{name: "1000000", data: 1000000, expected: "1a000f4240"},
+		{name: "1000000000000", data: 1000000000000, expected: "1b000000e8d4a51000"},
+		{name: "max uint64", data: uint64(math.MaxUint64), expected: "1bffffffffffffffff"},
+		{name: "-1", data: -1, expected: "20"},
+		{name: "-100", data: -100, expected: "3863"},
+		{name: "-1000", data: -1000, expected: "3903e7"},
+		{name: "1.1", data: 1.1, expected: "fb3ff199999999999a"},
+		{name: "false", data: false, expected: "f4"},
+		{name: "true", data: true, expected: "f5"},
+		{name: "null", data: nil, expected: "f6"},
+		{name: "text", data: "IETF", expected: "6449455446"},
+		{name: "array", data: []int{1, 2, 3}, expected: "83010203"},
+		{name: "map", data: map[string]interface{}{"b": []int{2, 3}, "a": 1}, expected: "a26161016162820203"},
+		{name: "long array", data: make([]int, 25), expected: "9819" + strings.Repeat("00", 25)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := marshalCBOR(tt.data)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if hex.EncodeToString(got) != tt.expected {
+				t.Errorf("expected %s, got %x", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestBinaryEncodings_UnsupportedValues(t *testing.T) {
+	if _, err := marshalMsgpack(math.Inf(1)); err == nil {
+		t.Error("expected msgpack to report the JSON encoding error")
+	}
+	if _, err := marshalCBOR(make(chan int)); err == nil {
+		t.Error("expected cbor to report the JSON encoding error")
+	}
+	if _, err := appendMsgpack(nil, 1); err == nil {
+		t.Error("expected msgpack to reject a non-JSON value")
+	}
+	if _, err := appendCBOR(nil, []interface{}{1}); err == nil {
+		t.Error("expected cbor to reject a non-JSON value")
+	}
+	if _, err := appendMsgpack(nil, map[string]interface{}{"a": 1}); err == nil {
+		t.Error("expected msgpack to reject a nested non-JSON value")
+	}
+	if _, err := appendCBOR(nil, map[string]interface{}{"a": 1}); err == nil {
+		t.Error("expected cbor to reject a nested non-JSON value")
+	}
+	if _, err := appendMsgpack(nil, []interface{}{1}); err == nil {
+		t.Error("expected msgpack to reject a non-JSON array element")
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	data := map[string]int{"count": 2}
+
+	tests := []struct {
+		name        string
+		accept      string
+		contentType string
+		body        string
+	}{
+		{name: "json by default", accept: "", contentType: ContentTypeJSON, body: hex.EncodeToString([]byte("{\"count\":2}\n"))},
+		{name: "msgpack", accept: "application/msgpack", contentType: ContentTypeMsgpack, body: "81a5636f756e7402"},
+		{name: "cbor", accept: "application/cbor", contentType: ContentTypeCBOR, body: "a165636f756e7402"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := Negotiate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if err := JSON(w, http.StatusOK, data); err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/items", nil)
+			req.Header.Set("Accept", tt.accept)
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+
+			if ct := rr.Header().Get("Content-Type"); ct != tt.contentType {
+				t.Errorf("expected Content-Type %q, got %q", tt.contentType, ct)
+			}
+			if vary := rr.Header().Get("Vary"); vary != "Accept" {
+				t.Errorf("expected Vary: Accept, got %q", vary)
+			}
+			if got := hex.EncodeToString(rr.Body.Bytes()); got != tt.body {
+				t.Errorf("expected body %s, got %s", tt.body, got)
+			}
+		})
+	}
+}
+
+func TestNegotiate_NilData(t *testing.T) {
+	handler := Negotiate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		JSON(w, http.StatusAccepted, nil)
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/items", nil)
+	req.Header.Set("Accept", "application/cbor")
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusAccepted || rr.Body.Len() != 0 {
+		t.Errorf("expected empty %d response, got %d %q", http.StatusAccepted, rr.Code, rr.Body.String())
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != ContentTypeCBOR {
+		t.Errorf("expected Content-Type %q, got %q", ContentTypeCBOR, ct)
+	}
+}
+
+func TestNegotiate_EncodingErrorFallsBackToJSON(t *testing.T) {
+	handler := Negotiate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := JSON(w, http.StatusOK, math.NaN()); err == nil {
+			t.Error("expected an encoding error")
+		}
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/items", nil)
+	req.Header.Set("Accept", "application/msgpack")
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, rr.Code)
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != ContentTypeJSON {
+		t.Errorf("expected JSON error response, got Content-Type %q", ct)
+	}
+	var body ErrorResponse
+	if err := json.NewDecoder(bytes.NewReader(rr.Body.Bytes())).Decode(&body); err != nil {
+		t.Errorf("expected a JSON error body: %v", err)
+	}
+}
+
+func TestNegotiate_PrettyOnlyAffectsJSON(t *testing.T) {
+	handler := Pretty(Negotiate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		JSON(w, http.StatusOK, map[string]int{"count": 2})
+	})))
+	req := httptest.NewRequest(http.MethodGet, "/items?pretty=1", nil)
+	req.Header.Set("Accept", "application/msgpack")
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	if got := hex.EncodeToString(rr.Body.Bytes()); got != "81a5636f756e7402" {
+		t.Errorf("expected compact msgpack body, got %s", got)
+	}
+}
+
+func TestNegotiate_KeepsResponseController(t *testing.T) {
+	handler := Negotiate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := http.NewResponseController(w).Flush(); err != nil {
+			t.Errorf("expected flush to reach the underlying writer: %v", err)
+		}
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/items", nil)
+	req.Header.Set("Accept", "application/cbor")
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	if !rr.Flushed {
+		t.Error("expected the recorder to be flushed")
+	}
+}
+
+func stringKeyedMap(n int) map[string]int {
+	m := make(map[string]int, n)
+	for i := 0; i < n; i++ {
+		m[string(rune('a'+i))] = i
+	}
+	return m
+}
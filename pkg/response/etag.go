@@ -0,0 +1,59 @@
+package response
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// privateRevalidate lets clients keep a per-user response but makes them ask
+// whether it changed, with If-None-Match, before each use.
+const privateRevalidate = "private, no-cache"
+
+// WeakETag returns a weak entity tag for the representation of the content
+// identified by version that the response would be written in, so each
+// negotiated encoding has its own tag. variant distinguishes other
+// representations of the same content, such as CSV or HAL.
+func WeakETag(w http.ResponseWriter, version string, variant ...string) string {
+	contentType := ContentTypeJSON
+	if enc, ok := find[*encodingWriter](w); ok {
+		contentType = enc.encoding.contentType
+	}
+	sum := sha256.Sum256([]byte(strings.Join(append([]string{version, contentType}, variant...), "\x00")))
+	return `W/"` + hex.EncodeToString(sum[:12]) + `"`
+}
+
+// SetETag sets etag on the response and asks clients to revalidate before
+// reusing it. An empty etag sets nothing.
+func SetETag(w http.ResponseWriter, etag string) {
+	if etag == "" {
+		return
+	}
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", privateRevalidate)
+}
+
+// IfNoneMatch reports whether the request's If-None-Match, a list of entity
+// tags or "*", matches etag. Weak comparison ignores the W/ prefix of both.
+// An empty etag matches nothing.
+func IfNoneMatch(r *http.Request, etag string) bool {
+	header := r.Header.Get("If-None-Match")
+	if header == "" || etag == "" {
+		return false
+	}
+	opaque := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == opaque {
+			return true
+		}
+	}
+	return false
+}
+
+// NotModified writes a 304 carrying etag, for a request IfNoneMatch matched.
+func NotModified(w http.ResponseWriter, etag string) {
+	SetETag(w, etag)
+	w.WriteHeader(http.StatusNotModified)
+}
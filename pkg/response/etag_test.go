@@ -0,0 +1,86 @@
+package response
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWeakETag(t *testing.T) {
+	var jsonTag, msgpackTag, csvTag string
+	handler := Negotiate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("variant") {
+		case "csv":
+			csvTag = WeakETag(w, "v1", "csv")
+		default:
+			if r.Header.Get("Accept") == ContentTypeMsgpack {
+				msgpackTag = WeakETag(w, "v1")
+			} else {
+				jsonTag = WeakETag(w, "v1")
+			}
+		}
+	}))
+	for _, target := range []string{"/", "/?variant=csv"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	}
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept", ContentTypeMsgpack)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if !strings.HasPrefix(jsonTag, `W/"`) || !strings.HasSuffix(jsonTag, `"`) {
+		t.Errorf("expected a weak tag, got %s", jsonTag)
+	}
+	if jsonTag == msgpackTag || jsonTag == csvTag || msgpackTag == csvTag {
+		t.Errorf("expected a tag per representation, got %s, %s and %s", jsonTag, msgpackTag, csvTag)
+	}
+	if again := WeakETag(httptest.NewRecorder(), "v1"); again != jsonTag {
+		t.Errorf("expected a stable tag, got %s then %s", jsonTag, again)
+	}
+	if other := WeakETag(httptest.NewRecorder(), "v2"); other == jsonTag {
+		t.Errorf("expected another version to change the tag, got %s", other)
+	}
+}
+
+func TestIfNoneMatch(t *testing.T) {
+	const etag = `W/"abc"`
+
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		expected    bool
+	}{
+		{name: "no header", ifNoneMatch: "", expected: false},
+		{name: "same tag", ifNoneMatch: `W/"abc"`, expected: true},
+		{name: "strong form of the tag", ifNoneMatch: `"abc"`, expected: true},
+		{name: "one of a list", ifNoneMatch: `"old", W/"abc"`, expected: true},
+		{name: "wildcard", ifNoneMatch: "*", expected: true},
+		{name: "other tag", ifNoneMatch: `W/"old"`, expected: false},
+	}
+	if IfNoneMatch(httptest.NewRequest(http.MethodGet, "/", nil), "") {
+		t.Error("expected an empty tag to match nothing")
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+			rr := httptest.NewRecorder()
+
+			if got := IfNoneMatch(req, etag); got != tt.expected {
+				t.Fatalf("expected %v, got %v", tt.expected, got)
+			}
+			if !tt.expected {
+				return
+			}
+			NotModified(rr, etag)
+			if rr.Code != http.StatusNotModified || rr.Body.Len() != 0 {
+				t.Errorf("expected an empty 304, got %d %q", rr.Code, rr.Body.String())
+			}
+			if rr.Header().Get("ETag") != etag || rr.Header().Get("Cache-Control") != "private, no-cache" {
+				t.Errorf("expected the tag and revalidation, got %v", rr.Header())
+			}
+		})
+	}
+}
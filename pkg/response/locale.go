@@ -0,0 +1,123 @@
+package response
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// DefaultLanguage is the language error messages are written in, and the
+// last fallback for every request.
+const DefaultLanguage = "en"
+
+// Localize is a middleware that picks the language error messages are
+// written in from the Accept-Language header. Each range falls back from
+// its full tag to its primary language (pt-BR to pt), and a header naming
+// no catalog gets DefaultLanguage. Only Error bodies are translated; their
+// codes never are.
+func Localize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if lang := negotiateLanguage(r.Header.Get("Accept-Language")); lang != DefaultLanguage {
+			w = &localeWriter{ResponseWriter: w, lang: lang}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// localeWriter carries the negotiated language to Error.
+type localeWriter struct {
+	http.ResponseWriter
+	lang string
+}
+
+func (w *localeWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// negotiateLanguage returns the catalog language the Accept-Language header
+// prefers. Ties in quality go to the range listed first.
+func negotiateLanguage(header string) string {
+	best := DefaultLanguage
+	bestQ := 0.0
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			var err error
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		if q <= bestQ {
+			continue
+		}
+		if lang, ok := catalogLanguage(tag); ok {
+			best, bestQ = lang, q
+		}
+	}
+	return best
+}
+
+// catalogLanguage walks tag's fallback chain, dropping subtags from the
+// end, to the first language with a catalog.
+func catalogLanguage(tag string) (string, bool) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	for tag != "" && tag != "*" {
+		if tag == DefaultLanguage {
+			return tag, true
+		}
+		if _, ok := catalogs[tag]; ok {
+			return tag, true
+		}
+		i := strings.LastIndexByte(tag, '-')
+		if i < 0 {
+			break
+		}
+		tag = tag[:i]
+	}
+	return "", false
+}
+
+// localize returns the code for message and its text in lang. A message the
+// catalog knows takes its catalogued code and translation, unless code names
+// a different one; one of the form "known message: detail" keeps its detail
+// untranslated. Any other message is left as is, coded by code if set and by
+// its status otherwise.
+func localize(statusCode int, code Code, message, lang string) (Code, string) {
+	if known, text, ok := lookup(message, lang); ok && (code == "" || code == known) {
+		return known, text
+	}
+	if code == "" {
+		code = statusErrorCode(statusCode)
+	}
+	return code, message
+}
+
+func lookup(message, lang string) (Code, string, bool) {
+	if code, ok := messageCodes[message]; ok {
+		return code, translate(code, message, lang), true
+	}
+	if prefix, detail, ok := strings.Cut(message, ": "); ok {
+		if code, ok := messageCodes[prefix]; ok {
+			return code, translate(code, prefix, lang) + ": " + detail, true
+		}
+	}
+	return "", "", false
+}
+
+func translate(code Code, fallback, lang string) string {
+	if text, ok := catalogs[lang][code]; ok {
+		return text
+	}
+	return fallback
+}
+
+// statusErrorCode codes an uncatalogued error by its status, e.g.
+// "not_found".
+func statusErrorCode(statusCode int) Code {
+	text := http.StatusText(statusCode)
+	if text == "" {
+		return "error"
+	}
+	return Code(strings.ReplaceAll(strings.ToLower(strings.ReplaceAll(text, "-", " ")), " ", "_"))
+}
@@ -0,0 +1,115 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNegotiateLanguage(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected string
+	}{
+		{header: "", expected: "en"},
+		{header: "de", expected: "de"},
+		{header: "de-AT", expected: "de"},
+		{header: "pt-BR,pt;q=0.9,en;q=0.8", expected: "pt"},
+		{header: "zh-Hant-TW", expected: "en"},
+		{header: "ja, fr;q=0.5", expected: "fr"},
+		{header: "en-GB, de;q=0.9", expected: "en"},
+		{header: "fr;q=0.4, es;q=0.8", expected: "es"},
+		{header: "es;q=0.8, fr;q=0.8", expected: "es"},
+		{header: "*", expected: "en"},
+		{header: "de;q=bad, fr", expected: "fr"},
+		{header: "DE-de", expected: "de"},
+	}
+
+	for _, tt := range tests {
+		if got := negotiateLanguage(tt.header); got != tt.expected {
+			t.Errorf("negotiateLanguage(%q) = %q, want %q", tt.header, got, tt.expected)
+		}
+	}
+}
+
+func TestLocalize_Error(t *testing.T) {
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		status         int
+		message        string
+		code           Code
+		expected       string
+		language       string
+	}{
+		{name: "english", status: http.StatusUnauthorized, message: "user not authenticated", code: "unauthenticated", expected: "user not authenticated", language: "en"},
+		{name: "translated", acceptLanguage: "de-DE", status: http.StatusUnauthorized, message: "user not authenticated", code: "unauthenticated", expected: "Benutzer nicht angemeldet", language: "de"},
+		{name: "detail kept", acceptLanguage: "fr", status: http.StatusBadRequest, message: "invalid request body: unexpected EOF", code: "invalid_request_body", expected: "Corps de la requête invalide: unexpected EOF", language: "fr"},
+		{name: "uncatalogued", acceptLanguage: "es", status: http.StatusNotFound, message: "share link not found", code: "not_found", expected: "share link not found", language: "es"},
+		{name: "uncatalogued server error", acceptLanguage: "pt", status: http.StatusInternalServerError, message: "failed to get wishlist", code: "internal_server_error", expected: "failed to get wishlist", language: "pt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := Localize(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Error(w, tt.status, tt.message)
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			var body ErrorResponse
+			if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+				t.Fatalf("failed to decode body: %v", err)
+			}
+			if body.Code != tt.code || body.Message != tt.expected || body.Error != http.StatusText(tt.status) {
+				t.Errorf("expected code %q and message %q, got %+v", tt.code, tt.expected, body)
+			}
+			if rr.Header().Get("Content-Language") != tt.language || rr.Header().Get("Vary") != "Accept-Language" {
+				t.Errorf("unexpected headers %v", rr.Header())
+			}
+		})
+	}
+}
+
+func TestLocalize_LeavesSuccessBodiesAlone(t *testing.T) {
+	handler := Localize(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		JSON(w, http.StatusOK, map[string]string{"message": "item added to wishlist"})
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Language", "de")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if rr.Body.String() != "{\"message\":\"item added to wishlist\"}\n" || rr.Header().Get("Vary") != "" {
+		t.Errorf("expected the body untouched and cacheable, got %q %v", rr.Body.String(), rr.Header())
+	}
+}
+
+// TestCatalogsCoverKnownCodes keeps every catalog to the known codes, so a
+// typo cannot add a translation nothing uses.
+func TestCatalogsCoverKnownCodes(t *testing.T) {
+	known := map[Code]bool{}
+	for _, code := range messageCodes {
+		if known[code] {
+			t.Errorf("code %q is used by two messages", code)
+		}
+		known[code] = true
+	}
+	for lang, catalog := range catalogs {
+		for code := range catalog {
+			if !known[code] {
+				t.Errorf("%s catalog translates unknown code %q", lang, code)
+			}
+		}
+		for code := range known {
+			if _, ok := catalog[code]; !ok {
+				t.Errorf("%s catalog is missing %q", lang, code)
+			}
+		}
+	}
+}
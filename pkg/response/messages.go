@@ -0,0 +1,115 @@
+package response
+
+// messageCodes maps the English error messages handlers and middleware write
+// to their codes, for Error to find. Only messages listed here are
+// translated.
+var messageCodes = map[string]Code{
+	"user not authenticated":              CodeUnauthenticated,
+	"missing authorization header":        CodeMissingAuthorization,
+	"invalid authorization header format": CodeInvalidAuthorization,
+	"invalid token":                       CodeInvalidToken,
+	"invalid token claims":                CodeInvalidTokenClaims,
+	"missing user ID in token":            CodeMissingUserID,
+	"invalid admin token":                 CodeInvalidAdminToken,
+	"invalid request body":                CodeInvalidRequestBody,
+	"uniqueName is required":              CodeUniqueNameRequired,
+	"quantity must be greater than 0":     CodeInvalidQuantity,
+	"item not found":                      CodeItemNotFound,
+	"item not in wishlist":                CodeItemNotInWishlist,
+	"item already in wishlist":            CodeItemAlreadyInWishlist,
+	"item already owned":                  CodeItemAlreadyOwned,
+	"public wishlist not found":           CodePublicWishlistNotFound,
+	"invalid time zone":                   CodeInvalidTimeZone,
+	"server is busy, please retry later":  CodeServerBusy,
+	"this instance is read-only":          CodeReadOnly,
+	"request failed validation":           CodeValidationFailed,
+}
+
+// catalogs holds each language's translations by code. A code missing from
+// a catalog falls back to the English message.
+var catalogs = map[string]map[Code]string{
+	"de": {
+		"unauthenticated":           "Benutzer nicht angemeldet",
+		"missing_authorization":     "Authorization-Header fehlt",
+		"invalid_authorization":     "Ungültiges Format des Authorization-Headers",
+		"invalid_token":             "Ungültiges Token",
+		"invalid_token_claims":      "Ungültige Token-Claims",
+		"missing_user_id":           "Benutzer-ID fehlt im Token",
+		"invalid_admin_token":       "Ungültiges Admin-Token",
+		"invalid_request_body":      "Ungültiger Anfrageinhalt",
+		"unique_name_required":      "uniqueName ist erforderlich",
+		"invalid_quantity":          "Die Menge muss größer als 0 sein",
+		"item_not_found":            "Gegenstand nicht gefunden",
+		"item_not_in_wishlist":      "Gegenstand nicht auf der Wunschliste",
+		"item_already_in_wishlist":  "Gegenstand bereits auf der Wunschliste",
+		"item_already_owned":        "Gegenstand bereits im Besitz",
+		"public_wishlist_not_found": "Öffentliche Wunschliste nicht gefunden",
+		"invalid_time_zone":         "Ungültige Zeitzone",
+		"server_busy":               "Server ausgelastet, bitte später erneut versuchen",
+		"read_only":                 "Diese Instanz ist schreibgeschützt",
+		"validation_failed":         "Die Anfrage ist ungültig",
+	},
+	"es": {
+		"unauthenticated":           "Usuario no autenticado",
+		"missing_authorization":     "Falta la cabecera Authorization",
+		"invalid_authorization":     "Formato de cabecera Authorization no válido",
+		"invalid_token":             "Token no válido",
+		"invalid_token_claims":      "Claims del token no válidos",
+		"missing_user_id":           "Falta el ID de usuario en el token",
+		"invalid_admin_token":       "Token de administrador no válido",
+		"invalid_request_body":      "Cuerpo de la solicitud no válido",
+		"unique_name_required":      "uniqueName es obligatorio",
+		"invalid_quantity":          "La cantidad debe ser mayor que 0",
+		"item_not_found":            "Objeto no encontrado",
+		"item_not_in_wishlist":      "El objeto no está en la lista de deseos",
+		"item_already_in_wishlist":  "El objeto ya está en la lista de deseos",
+		"item_already_owned":        "Ya tienes este objeto",
+		"public_wishlist_not_found": "Lista de deseos pública no encontrada",
+		"invalid_time_zone":         "Zona horaria no válida",
+		"server_busy":               "El servidor está ocupado, inténtalo de nuevo más tarde",
+		"read_only":                 "Esta instancia es de solo lectura",
+		"validation_failed":         "La solicitud no es válida",
+	},
+	"fr": {
+		"unauthenticated":           "Utilisateur non authentifié",
+		"missing_authorization":     "En-tête Authorization manquant",
+		"invalid_authorization":     "Format de l'en-tête Authorization invalide",
+		"invalid_token":             "Jeton invalide",
+		"invalid_token_claims":      "Claims du jeton invalides",
+		"missing_user_id":           "Identifiant utilisateur absent du jeton",
+		"invalid_admin_token":       "Jeton d'administration invalide",
+		"invalid_request_body":      "Corps de la requête invalide",
+		"unique_name_required":      "uniqueName est obligatoire",
+		"invalid_quantity":          "La quantité doit être supérieure à 0",
+		"item_not_found":            "Objet introuvable",
+		"item_not_in_wishlist":      "L'objet n'est pas dans la liste de souhaits",
+		"item_already_in_wishlist":  "L'objet est déjà dans la liste de souhaits",
+		"item_already_owned":        "Vous possédez déjà cet objet",
+		"public_wishlist_not_found": "Liste de souhaits publique introuvable",
+		"invalid_time_zone":         "Fuseau horaire invalide",
+		"server_busy":               "Serveur occupé, veuillez réessayer plus tard",
+		"read_only":                 "Cette instance est en lecture seule",
+		"validation_failed":         "La requête est invalide",
+	},
+	"pt": {
+		"unauthenticated":           "Usuário não autenticado",
+		"missing_authorization":     "Cabeçalho Authorization ausente",
+		"invalid_authorization":     "Formato do cabeçalho Authorization inválido",
+		"invalid_token":             "Token inválido",
+		"invalid_token_claims":      "Claims do token inválidas",
+		"missing_user_id":           "ID do usuário ausente no token",
+		"invalid_admin_token":       "Token de administrador inválido",
+		"invalid_request_body":      "Corpo da requisição inválido",
+		"unique_name_required":      "uniqueName é obrigatório",
+		"invalid_quantity":          "A quantidade deve ser maior que 0",
+		"item_not_found":            "Item não encontrado",
+		"item_not_in_wishlist":      "O item não está na lista de desejos",
+		"item_already_in_wishlist":  "O item já está na lista de desejos",
+		"item_already_owned":        "Você já possui este item",
+		"public_wishlist_not_found": "Lista de desejos pública não encontrada",
+		"invalid_time_zone":         "Fuso horário inválido",
+		"server_busy":               "Servidor ocupado, tente novamente mais tarde",
+		"read_only":                 "Esta instância é somente leitura",
+		"validation_failed":         "A solicitação é inválida",
+	},
+}
@@ -0,0 +1,41 @@
+package response
+
+import (
+	"context"
+	"net/http"
+)
+
+// RequestIDHeader carries the request ID on every response.
+const RequestIDHeader = "X-Request-ID"
+
+// RequestIDs is a middleware that puts the request ID id finds in the
+// request's context on the response, in RequestIDHeader and in every error
+// body. Requests without one are left alone.
+func RequestIDs(id func(context.Context) string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if requestID := id(r.Context()); requestID != "" {
+				w.Header().Set(RequestIDHeader, requestID)
+				w = &requestIDWriter{ResponseWriter: w, id: requestID}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// requestIDWriter carries the request ID to WriteError.
+type requestIDWriter struct {
+	http.ResponseWriter
+	id string
+}
+
+func (w *requestIDWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func requestID(w http.ResponseWriter) string {
+	if w, ok := find[*requestIDWriter](w); ok {
+		return w.id
+	}
+	return ""
+}
@@ -1,30 +1,207 @@
 package response
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"strconv"
 )
 
+// ErrorResponse is every error body. Code is stable and untranslated, for
+// clients to match on; Message is for people, in the language Localize
+// picked. Fields names each field of the request at fault, for validation
+// failures. Detail optionally carries data about the failure, such as the
+// conflicting record. RequestID is the ID RequestIDs found for the request,
+// for users to quote when reporting the error.
 type ErrorResponse struct {
-	Error   string `json:"error"`
-	Message string `json:"message,omitempty"`
+	Error     string       `json:"error"`
+	Code      Code         `json:"code"`
+	Message   string       `json:"message,omitempty"`
+	Fields    []FieldError `json:"fields,omitempty"`
+	Detail    any          `json:"detail,omitempty"`
+	RequestID string       `json:"requestId,omitempty"`
 }
 
-func JSON(w http.ResponseWriter, statusCode int, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
+// FieldError is one field of a request that failed validation, e.g.
+// "materials[2].count". Its message is not translated.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    Code   `json:"code"`
+	Message string `json:"message"`
+}
+
+// encodeFailureBody is written when a response body cannot be encoded. It is
+// a constant but for the request ID, and marshalling a string cannot fail,
+// so reporting the failure cannot itself fail.
+func encodeFailureBody(w http.ResponseWriter) []byte {
+	body := `{"error":"Internal Server Error","code":"internal_server_error","message":"failed to encode response"`
+	if id := requestID(w); id != "" {
+		quoted, _ := json.Marshal(id)
+		body += `,"requestId":` + string(quoted)
+	}
+	return []byte(body + "}\n")
+}
+
+// JSON encodes data and writes it with the given status code, in msgpack or
+// CBOR instead when Negotiate picked one for the request. The body is encoded
+// before anything is written, so an encoding failure produces a 500 JSON error
+// response instead of a truncated body with the original status; the encoding
+// error is returned so callers can log it. A nil data writes only the status
+// and Content-Type header.
+func JSON(w http.ResponseWriter, statusCode int, data interface{}) error {
+	return write(w, statusCode, ContentTypeJSON, data)
+}
+
+// HAL is JSON for bodies carrying HAL _links, labelled application/hal+json
+// unless Negotiate picked a binary encoding.
+func HAL(w http.ResponseWriter, statusCode int, data interface{}) error {
+	return write(w, statusCode, ContentTypeHAL, data)
+}
+
+// write encodes data as JSON with the given content type, or in the encoding
+// Negotiate picked.
+func write(w http.ResponseWriter, statusCode int, contentType string, data interface{}) error {
+	enc, negotiated := find[*encodingWriter](w)
+	if negotiated {
+		contentType = enc.encoding.contentType
+	}
+	if data == nil {
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(statusCode)
+		return nil
+	}
+
+	var body []byte
+	var err error
+	if negotiated {
+		body, err = enc.encoding.encode(data)
+	} else {
+		body, err = marshalJSONWithWarnings(data, isPretty(w), warnings(w))
+	}
+	if err != nil {
+		w.Header().Set("Content-Type", ContentTypeJSON)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(encodeFailureBody(w))
+		return fmt.Errorf("response: encoding %T: %w", data, err)
+	}
+
+	w.Header().Set("Content-Type", contentType)
 	w.WriteHeader(statusCode)
-	if data != nil {
-		json.NewEncoder(w).Encode(data)
+	if _, err := w.Write(body); err != nil {
+		return fmt.Errorf("response: writing body: %w", err)
 	}
+	return nil
 }
 
-func Error(w http.ResponseWriter, statusCode int, message string) {
-	JSON(w, statusCode, ErrorResponse{
-		Error:   http.StatusText(statusCode),
-		Message: message,
-	})
+// marshalJSONWithWarnings is marshalJSON with warnings added to object
+// bodies.
+func marshalJSONWithWarnings(data interface{}, pretty bool, warnings []Warning) ([]byte, error) {
+	if len(warnings) == 0 {
+		return marshalJSON(data, pretty)
+	}
+	body, err := marshalJSON(data, false)
+	if err != nil {
+		return nil, err
+	}
+	if body, err = withWarnings(body, warnings); err != nil || !pretty {
+		return body, err
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, body, "", "  "); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func marshalJSON(data interface{}, pretty bool) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	if pretty {
+		enc.SetIndent("", "  ")
+	}
+	if err := enc.Encode(data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Error writes an error body for message, translated when Localize picked
+// a language other than DefaultLanguage.
+func Error(w http.ResponseWriter, statusCode int, message string) error {
+	return WriteError(w, statusCode, ErrorResponse{Message: message})
+}
+
+// ErrorCode is Error for a message with a code of its own, such as a
+// service error's.
+func ErrorCode(w http.ResponseWriter, statusCode int, code Code, message string) error {
+	return WriteError(w, statusCode, ErrorResponse{Code: code, Message: message})
+}
+
+// ErrorWithDetail is Error with detail added to the body. Detail is not
+// translated.
+func ErrorWithDetail(w http.ResponseWriter, statusCode int, message string, detail any) error {
+	return WriteError(w, statusCode, ErrorResponse{Message: message, Detail: detail})
+}
+
+// WriteError writes body, filling in its status text, its code if unset, its
+// translated message, and the request ID.
+func WriteError(w http.ResponseWriter, statusCode int, body ErrorResponse) error {
+	lang := DefaultLanguage
+	if locale, ok := find[*localeWriter](w); ok {
+		lang = locale.lang
+	}
+	body.Error = http.StatusText(statusCode)
+	body.Code, body.Message = localize(statusCode, body.Code, body.Message, lang)
+	body.RequestID = requestID(w)
+	w.Header().Add("Vary", "Accept-Language")
+	w.Header().Set("Content-Language", lang)
+	return JSON(w, statusCode, body)
 }
 
 func NoContent(w http.ResponseWriter) {
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// Pretty is a middleware that makes JSON indent response bodies when the
+// request has a truthy pretty query parameter, e.g. ?pretty=1.
+func Pretty(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if pretty, _ := strconv.ParseBool(r.URL.Query().Get("pretty")); pretty {
+			w = &prettyWriter{ResponseWriter: w}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// prettyWriter marks a response as pretty-printed. It implements Unwrap so
+// http.ResponseController and writers wrapping it keep working.
+type prettyWriter struct {
+	http.ResponseWriter
+}
+
+func (w *prettyWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func isPretty(w http.ResponseWriter) bool {
+	_, ok := find[*prettyWriter](w)
+	return ok
+}
+
+// find returns the first writer of type T in the chain starting at w and
+// following Unwrap, so middleware registered after Pretty or Negotiate may
+// wrap the writer again.
+func find[T http.ResponseWriter](w http.ResponseWriter) (T, bool) {
+	for {
+		if t, ok := w.(T); ok {
+			return t, true
+		}
+		u, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			var zero T
+			return zero, false
+		}
+		w = u.Unwrap()
+	}
+}
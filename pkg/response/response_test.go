@@ -0,0 +1,319 @@
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+)
+
+type failingWriter struct {
+	http.ResponseWriter
+}
+
+func (w failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+func TestJSON(t *testing.T) {
+	rr := httptest.NewRecorder()
+
+	if err := JSON(rr, http.StatusCreated, map[string]int{"count": 2}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if rr.Code != http.StatusCreated {
+		t.Errorf("expected status %d, got %d", http.StatusCreated, rr.Code)
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("expected application/json, got %q", ct)
+	}
+	if body := rr.Body.String(); body != "{\"count\":2}\n" {
+		t.Errorf("unexpected body %q", body)
+	}
+}
+
+func TestHAL(t *testing.T) {
+	rr := httptest.NewRecorder()
+
+	if err := HAL(rr, http.StatusOK, map[string]int{"count": 2}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if ct := rr.Header().Get("Content-Type"); ct != ContentTypeHAL {
+		t.Errorf("expected %s, got %q", ContentTypeHAL, ct)
+	}
+	if body := rr.Body.String(); body != "{\"count\":2}\n" {
+		t.Errorf("unexpected body %q", body)
+	}
+}
+
+func TestJSON_NilData(t *testing.T) {
+	rr := httptest.NewRecorder()
+
+	if err := JSON(rr, http.StatusAccepted, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if rr.Code != http.StatusAccepted {
+		t.Errorf("expected status %d, got %d", http.StatusAccepted, rr.Code)
+	}
+	if rr.Body.Len() != 0 {
+		t.Errorf("expected empty body, got %q", rr.Body.String())
+	}
+}
+
+func TestJSON_EncodingError(t *testing.T) {
+	tests := []struct {
+		name string
+		data interface{}
+	}{
+		{name: "unsupported type", data: map[string]interface{}{"ok": 1, "ch": make(chan int)}},
+		{name: "unsupported value", data: []float64{1, math.NaN()}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+
+			err := JSON(rr, http.StatusOK, tt.data)
+			if err == nil {
+				t.Fatal("expected an encoding error")
+			}
+
+			if rr.Code != http.StatusInternalServerError {
+				t.Errorf("expected status %d, got %d", http.StatusInternalServerError, rr.Code)
+			}
+			var body ErrorResponse
+			if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+				t.Fatalf("expected a complete JSON error body, got %q: %v", rr.Body.String(), err)
+			}
+			if body.Error != http.StatusText(http.StatusInternalServerError) || body.Message == "" {
+				t.Errorf("unexpected error body %+v", body)
+			}
+		})
+	}
+}
+
+func TestJSON_WriteError(t *testing.T) {
+	rr := httptest.NewRecorder()
+
+	if err := JSON(failingWriter{rr}, http.StatusOK, map[string]int{"count": 2}); err == nil {
+		t.Error("expected the write error to be returned")
+	}
+}
+
+func TestError(t *testing.T) {
+	rr := httptest.NewRecorder()
+
+	if err := Error(rr, http.StatusNotFound, "item not found"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, rr.Code)
+	}
+	var body ErrorResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode body: %v", err)
+	}
+	if body.Error != "Not Found" || body.Code != "item_not_found" || body.Message != "item not found" {
+		t.Errorf("unexpected error body %+v", body)
+	}
+}
+
+func TestErrorWithDetail(t *testing.T) {
+	rr := httptest.NewRecorder()
+
+	ErrorWithDetail(rr, http.StatusConflict, "item already owned", map[string]string{"status": "built"})
+
+	var body struct {
+		Code   string            `json:"code"`
+		Detail map[string]string `json:"detail"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode body: %v", err)
+	}
+	if rr.Code != http.StatusConflict || body.Code != "item_already_owned" || body.Detail["status"] != "built" {
+		t.Errorf("unexpected error %d %+v", rr.Code, body)
+	}
+
+	rr = httptest.NewRecorder()
+	Error(rr, http.StatusNotFound, "item not found")
+	if strings.Contains(rr.Body.String(), "detail") {
+		t.Errorf("expected detail to be omitted, got %q", rr.Body.String())
+	}
+}
+
+func TestError_OmitsEmptyMessage(t *testing.T) {
+	rr := httptest.NewRecorder()
+
+	Error(rr, http.StatusUnauthorized, "")
+
+	if strings.Contains(rr.Body.String(), "message") {
+		t.Errorf("expected message to be omitted, got %q", rr.Body.String())
+	}
+}
+
+func TestNoContent(t *testing.T) {
+	rr := httptest.NewRecorder()
+
+	NoContent(rr)
+
+	if rr.Code != http.StatusNoContent {
+		t.Errorf("expected status %d, got %d", http.StatusNoContent, rr.Code)
+	}
+	if rr.Body.Len() != 0 {
+		t.Errorf("expected empty body, got %q", rr.Body.String())
+	}
+}
+
+func TestPretty(t *testing.T) {
+	data := map[string]int{"count": 2}
+	handler := Pretty(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		JSON(w, http.StatusOK, data)
+	}))
+
+	tests := []struct {
+		name     string
+		target   string
+		expected string
+	}{
+		{name: "pretty=1", target: "/items?pretty=1", expected: "{\n  \"count\": 2\n}\n"},
+		{name: "pretty=true", target: "/items?pretty=true", expected: "{\n  \"count\": 2\n}\n"},
+		{name: "pretty=0", target: "/items?pretty=0", expected: "{\"count\":2}\n"},
+		{name: "invalid value", target: "/items?pretty=yes", expected: "{\"count\":2}\n"},
+		{name: "absent", target: "/items", expected: "{\"count\":2}\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			if body := rr.Body.String(); body != tt.expected {
+				t.Errorf("expected body %q, got %q", tt.expected, body)
+			}
+		})
+	}
+}
+
+func TestPretty_ThroughWrappedWriter(t *testing.T) {
+	handler := Pretty(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		JSON(ww, http.StatusOK, map[string]int{"count": 2})
+	}))
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/items?pretty=1", nil))
+
+	if body := rr.Body.String(); body != "{\n  \"count\": 2\n}\n" {
+		t.Errorf("expected indented body, got %q", body)
+	}
+}
+
+func TestPretty_KeepsResponseController(t *testing.T) {
+	handler := Pretty(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := http.NewResponseController(w).Flush(); err != nil {
+			t.Errorf("expected flush to reach the underlying writer: %v", err)
+		}
+	}))
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/items?pretty=1", nil))
+
+	if !rr.Flushed {
+		t.Error("expected the recorder to be flushed")
+	}
+}
+
+func TestErrorCode(t *testing.T) {
+	rr := httptest.NewRecorder()
+	ErrorCode(rr, http.StatusConflict, CodeWorkspaceConflict, "workspace is being changed by someone else, try again")
+
+	var body ErrorResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode body: %v", err)
+	}
+	if rr.Code != http.StatusConflict || body.Code != CodeWorkspaceConflict || body.Message != "workspace is being changed by someone else, try again" {
+		t.Errorf("unexpected error %d %+v", rr.Code, body)
+	}
+
+	// A catalogued message keeps its code when none is given.
+	rr = httptest.NewRecorder()
+	ErrorCode(rr, http.StatusNotFound, "", "item not found")
+	if !strings.Contains(rr.Body.String(), `"code":"item_not_found"`) {
+		t.Errorf("expected the catalogued code, got %q", rr.Body.String())
+	}
+}
+
+func TestWriteError_Fields(t *testing.T) {
+	rr := httptest.NewRecorder()
+	WriteError(rr, http.StatusUnprocessableEntity, ErrorResponse{
+		Message: "request failed validation",
+		Fields:  []FieldError{{Field: "items[0].quantity", Code: CodeInvalidQuantity, Message: "quantity must be greater than 0"}},
+	})
+
+	var body ErrorResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode body: %v", err)
+	}
+	if body.Error != "Unprocessable Entity" || body.Code != CodeValidationFailed || len(body.Fields) != 1 || body.Fields[0].Code != CodeInvalidQuantity {
+		t.Errorf("unexpected error body %+v", body)
+	}
+
+	rr = httptest.NewRecorder()
+	Error(rr, http.StatusNotFound, "item not found")
+	if strings.Contains(rr.Body.String(), "fields") {
+		t.Errorf("expected fields to be omitted, got %q", rr.Body.String())
+	}
+}
+
+func TestRequestIDs(t *testing.T) {
+	handler := chimiddleware.RequestID(RequestIDs(chimiddleware.GetReqID)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/broken" {
+			JSON(w, http.StatusOK, map[string]interface{}{"ch": make(chan int)})
+			return
+		}
+		Error(w, http.StatusNotFound, "item not found")
+	})))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(chimiddleware.RequestIDHeader, "req-42")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	var body ErrorResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode body: %v", err)
+	}
+	if body.RequestID != "req-42" || rr.Header().Get(RequestIDHeader) != "req-42" {
+		t.Errorf("expected request ID req-42 in the body and header, got %+v and %v", body, rr.Header())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/broken", nil)
+	req.Header.Set(chimiddleware.RequestIDHeader, `req-"43"`)
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	body = ErrorResponse{}
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode encoding failure body %q: %v", rr.Body.String(), err)
+	}
+	if body.Code != "internal_server_error" || body.RequestID != `req-"43"` {
+		t.Errorf("expected the request ID in the encoding failure body, got %+v", body)
+	}
+
+	rr = httptest.NewRecorder()
+	RequestIDs(chimiddleware.GetReqID)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Error(w, http.StatusNotFound, "item not found")
+	})).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	if strings.Contains(rr.Body.String(), "requestId") || rr.Header().Get(RequestIDHeader) != "" {
+		t.Errorf("expected no request ID without one in the context, got %q", rr.Body.String())
+	}
+}
@@ -0,0 +1,77 @@
+package response
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// WarningHeader carries each warning as "299 - \"message\"", the code for a
+// persistent miscellaneous warning.
+const WarningHeader = "Warning"
+
+// Warning is a notice about a request that otherwise succeeded, such as its
+// use of a deprecated parameter. ID names what the warning is about, for
+// clients to match on.
+type Warning struct {
+	Code    Code   `json:"code"`
+	ID      string `json:"id,omitempty"`
+	Message string `json:"message"`
+}
+
+// Warnings is a middleware that lets handlers add Warn's warnings to JSON
+// object bodies as a top-level "warnings" array.
+func Warnings(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(&warningWriter{ResponseWriter: w}, r)
+	})
+}
+
+// warningWriter collects the response's warnings for write.
+type warningWriter struct {
+	http.ResponseWriter
+	warnings []Warning
+}
+
+func (w *warningWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Warn adds warning to the response in WarningHeader and, for a JSON object
+// body under Warnings, in its "warnings" array. msgpack and CBOR bodies get
+// only the header. It must be called before the response is written.
+func Warn(w http.ResponseWriter, warning Warning) {
+	message := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(warning.Message)
+	w.Header().Add(WarningHeader, `299 - "`+message+`"`)
+	if ww, ok := find[*warningWriter](w); ok {
+		ww.warnings = append(ww.warnings, warning)
+	}
+}
+
+func warnings(w http.ResponseWriter) []Warning {
+	if ww, ok := find[*warningWriter](w); ok {
+		return ww.warnings
+	}
+	return nil
+}
+
+// withWarnings returns body, a compact JSON encoding, with warnings added as
+// the first key when it is an object. Other bodies are returned as they are.
+func withWarnings(body []byte, warnings []Warning) ([]byte, error) {
+	if len(warnings) == 0 || len(body) == 0 || body[0] != '{' {
+		return body, nil
+	}
+	encoded, err := json.Marshal(warnings)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	buf.WriteString(`{"warnings":`)
+	buf.Write(encoded)
+	if rest := bytes.TrimSpace(body[1:]); len(rest) > 0 && rest[0] != '}' {
+		buf.WriteByte(',')
+	}
+	buf.Write(body[1:])
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,67 @@
+package response
+
+import (
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWarnings(t *testing.T) {
+	warning := Warning{Code: CodeDeprecated, ID: "wishlist.bareItems", Message: `use "expand"`}
+
+	tests := []struct {
+		name     string
+		target   string
+		accept   string
+		data     interface{}
+		expected string
+	}{
+		{name: "object", target: "/wishlist", data: map[string]int{"count": 2}, expected: `{"warnings":[{"code":"deprecated","id":"wishlist.bareItems","message":"use \"expand\""}],"count":2}` + "\n"},
+		{name: "empty object", target: "/wishlist", data: struct{}{}, expected: `{"warnings":[{"code":"deprecated","id":"wishlist.bareItems","message":"use \"expand\""}]}` + "\n"},
+		{name: "array is left alone", target: "/wishlist", data: []int{1}, expected: "[1]\n"},
+		{name: "pretty", target: "/wishlist?pretty=1", data: map[string]int{"count": 2}, expected: "{\n  \"warnings\": [\n    {\n      \"code\": \"deprecated\",\n      \"id\": \"wishlist.bareItems\",\n      \"message\": \"use \\\"expand\\\"\"\n    }\n  ],\n  \"count\": 2\n}\n"},
+		{name: "msgpack gets the header only", target: "/wishlist", accept: "application/msgpack", data: map[string]int{"count": 2}, expected: "81a5636f756e7402"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := Pretty(Negotiate(Warnings(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Warn(w, warning)
+				JSON(w, http.StatusOK, tt.data)
+			}))))
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+
+			body := rr.Body.String()
+			if tt.accept != "" {
+				body = hex.EncodeToString(rr.Body.Bytes())
+			}
+			if body != tt.expected {
+				t.Errorf("expected body %q, got %q", tt.expected, body)
+			}
+			if got := rr.Header().Get(WarningHeader); got != `299 - "use \"expand\""` {
+				t.Errorf("unexpected %s header: %q", WarningHeader, got)
+			}
+		})
+	}
+}
+
+func TestWarn_WithoutMiddleware(t *testing.T) {
+	rr := httptest.NewRecorder()
+
+	Warn(rr, Warning{Code: CodeDeprecated, Message: "deprecated"})
+	JSON(rr, http.StatusOK, map[string]int{"count": 2})
+
+	if body := rr.Body.String(); body != "{\"count\":2}\n" {
+		t.Errorf("expected the body to be left alone, got %q", body)
+	}
+	if got := rr.Header().Get(WarningHeader); got != `299 - "deprecated"` {
+		t.Errorf("unexpected %s header: %q", WarningHeader, got)
+	}
+}
@@ -0,0 +1,240 @@
+// Package validate checks request bodies against the rules in their struct
+// tags, so handlers reject malformed requests the same way everywhere:
+//
+//	type AddItemRequest struct {
+//		UniqueName string `json:"uniqueName" validate:"required" code:"unique_name_required"`
+//		Quantity   int    `json:"quantity" validate:"min=0"`
+//	}
+//
+// The rules are:
+//
+//   - required: the value is not its zero value, and a string is not blank.
+//   - min=N, max=N: a number is at least or at most N; a string, slice or map
+//     has at least or at most N elements (runes, for a string).
+//   - dive: the rules after it apply to each element of a slice.
+//
+// Struct fields, and the elements of slices of structs, are checked against
+// their own tags. Fields are named by their JSON names, e.g.
+// "materials[2].count", so errors point at the request body, not the Go type.
+// A field's code tag overrides the code of every error it causes.
+package validate
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Rules.
+const (
+	RuleRequired = "required"
+	RuleMin      = "min"
+	RuleMax      = "max"
+)
+
+// FieldError is one broken rule. Code is the field's code tag, if it has
+// one.
+type FieldError struct {
+	Field   string
+	Rule    string
+	Code    string
+	Message string
+}
+
+// Errors is every rule a value broke, in field order.
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, fieldErr := range e {
+		messages[i] = fieldErr.Message
+	}
+	return "validation failed: " + strings.Join(messages, "; ")
+}
+
+// Struct checks v, a struct or pointer to one, and returns Errors if it
+// broke any rule. It panics on a malformed tag, since that is a programming
+// error.
+func Struct(v any) error {
+	value := reflect.Indirect(reflect.ValueOf(v))
+	if value.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("validate: Struct called with %T", v))
+	}
+	var errs Errors
+	checkStruct(value, "", &errs)
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+type field struct {
+	index int
+	name  string
+	code  string
+	rules []rule
+	// elemRules apply to each element, after dive.
+	elemRules []rule
+	dive      bool
+	// embedded fields are flattened, as encoding/json does.
+	embedded bool
+}
+
+type rule struct {
+	name  string
+	limit int
+}
+
+// fields caches each struct type's parsed tags.
+var fields sync.Map // reflect.Type -> []field
+
+func fieldsOf(t reflect.Type) []field {
+	if cached, ok := fields.Load(t); ok {
+		return cached.([]field)
+	}
+	var parsed []field
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		// Exported fields of embedded structs are promoted even when the
+		// struct type is not.
+		if !sf.IsExported() && !(sf.Anonymous && sf.Type.Kind() == reflect.Struct) {
+			continue
+		}
+		name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		f := field{index: i, name: name, code: sf.Tag.Get("code"), embedded: sf.Anonymous && name == ""}
+		if f.name == "" {
+			f.name = sf.Name
+		}
+		if tag := sf.Tag.Get("validate"); tag != "" {
+			for _, part := range strings.Split(tag, ",") {
+				if part == "dive" {
+					f.dive = true
+					continue
+				}
+				r := parseRule(t, sf.Name, part)
+				if f.dive {
+					f.elemRules = append(f.elemRules, r)
+				} else {
+					f.rules = append(f.rules, r)
+				}
+			}
+		}
+		parsed = append(parsed, f)
+	}
+	fields.Store(t, parsed)
+	return parsed
+}
+
+func parseRule(t reflect.Type, fieldName, part string) rule {
+	name, arg, hasArg := strings.Cut(part, "=")
+	switch name {
+	case RuleRequired:
+		if !hasArg {
+			return rule{name: name}
+		}
+	case RuleMin, RuleMax:
+		if limit, err := strconv.Atoi(arg); err == nil && hasArg {
+			return rule{name: name, limit: limit}
+		}
+	}
+	panic(fmt.Sprintf("validate: bad rule %q on %s.%s", part, t, fieldName))
+}
+
+func checkStruct(value reflect.Value, prefix string, errs *Errors) {
+	for _, f := range fieldsOf(value.Type()) {
+		path := prefix + f.name
+		fieldValue := value.Field(f.index)
+		if !check(fieldValue, path, f.code, f.rules, errs) {
+			continue
+		}
+		elems := reflect.Indirect(fieldValue)
+		switch elems.Kind() {
+		case reflect.Struct:
+			if f.embedded {
+				checkStruct(elems, prefix, errs)
+			} else {
+				checkStruct(elems, path+".", errs)
+			}
+		case reflect.Slice, reflect.Array:
+			for i := 0; i < elems.Len(); i++ {
+				elemPath := fmt.Sprintf("%s[%d]", path, i)
+				elem := elems.Index(i)
+				if f.dive && !check(elem, elemPath, f.code, f.elemRules, errs) {
+					continue
+				}
+				if elem = reflect.Indirect(elem); elem.Kind() == reflect.Struct {
+					checkStruct(elem, elemPath+".", errs)
+				}
+			}
+		}
+	}
+}
+
+// check applies rules to value, stopping at the first it breaks, and reports
+// whether it broke none.
+func check(value reflect.Value, path, code string, rules []rule, errs *Errors) bool {
+	for _, r := range rules {
+		if message := broken(value, r); message != "" {
+			*errs = append(*errs, FieldError{Field: path, Rule: r.name, Code: code, Message: path + " " + message})
+			return false
+		}
+	}
+	return true
+}
+
+// broken returns why value breaks r, or "" if it doesn't.
+func broken(value reflect.Value, r rule) string {
+	if r.name == RuleRequired {
+		if value.IsZero() || (value.Kind() == reflect.String && strings.TrimSpace(value.String()) == "") {
+			return "is required"
+		}
+		return ""
+	}
+
+	value = reflect.Indirect(value)
+	var size int
+	var unit string
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		size = int(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		size = int(value.Uint())
+	case reflect.Float32, reflect.Float64:
+		if f := value.Float(); (r.name == RuleMin && f < float64(r.limit)) || (r.name == RuleMax && f > float64(r.limit)) {
+			return bound(r, "")
+		}
+		return ""
+	case reflect.String:
+		size, unit = utf8.RuneCountInString(value.String()), " characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		size, unit = value.Len(), " entries"
+	default:
+		// A nil pointer has nothing to bound; required catches it.
+		return ""
+	}
+	if (r.name == RuleMin && size < r.limit) || (r.name == RuleMax && size > r.limit) {
+		return bound(r, unit)
+	}
+	return ""
+}
+
+// bound says value is out of r's range, in unit, which is empty for
+// numbers.
+func bound(r rule, unit string) string {
+	if r.name == RuleMin && unit == "" {
+		return fmt.Sprintf("must be at least %d", r.limit)
+	}
+	if r.name == RuleMin {
+		return fmt.Sprintf("must have at least %d%s", r.limit, unit)
+	}
+	if unit == "" {
+		return fmt.Sprintf("must be at most %d", r.limit)
+	}
+	return fmt.Sprintf("must have at most %d%s", r.limit, unit)
+}
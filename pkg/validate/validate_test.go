@@ -0,0 +1,89 @@
+package validate
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type testMaterial struct {
+	UniqueName string `json:"uniqueName" validate:"required" code:"unique_name_required"`
+	Count      int    `json:"count" validate:"min=0,max=999"`
+}
+
+type testBase struct {
+	Label string `json:"label" validate:"max=5"`
+}
+
+type testRequest struct {
+	testBase
+	Name      string         `json:"name,omitempty" validate:"required"`
+	Quantity  int            `json:"quantity" validate:"min=1"`
+	Tags      []string       `json:"tags" validate:"max=2,dive,required"`
+	Materials []testMaterial `json:"materials"`
+	Ignored   string         `json:"-" validate:"required"`
+	internal  string
+}
+
+func TestStruct_Valid(t *testing.T) {
+	req := testRequest{
+		Name:      "Soma Prime",
+		Quantity:  1,
+		Tags:      []string{"primary"},
+		Materials: []testMaterial{{UniqueName: "/Lotus/Ferrite", Count: 10}},
+	}
+	if err := Struct(&req); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestStruct_ReportsEveryField(t *testing.T) {
+	req := testRequest{
+		testBase:  testBase{Label: "too long"},
+		Name:      "   ",
+		Quantity:  0,
+		Tags:      []string{"ok", ""},
+		Materials: []testMaterial{{UniqueName: "/Lotus/Ferrite", Count: 1}, {Count: -1}},
+	}
+
+	var errs Errors
+	if !errors.As(Struct(req), &errs) {
+		t.Fatal("expected Errors")
+	}
+	want := Errors{
+		{Field: "label", Rule: RuleMax, Message: "label must have at most 5 characters"},
+		{Field: "name", Rule: RuleRequired, Message: "name is required"},
+		{Field: "quantity", Rule: RuleMin, Message: "quantity must be at least 1"},
+		{Field: "tags[1]", Rule: RuleRequired, Message: "tags[1] is required"},
+		{Field: "materials[1].uniqueName", Rule: RuleRequired, Code: "unique_name_required", Message: "materials[1].uniqueName is required"},
+		{Field: "materials[1].count", Rule: RuleMin, Message: "materials[1].count must be at least 0"},
+	}
+	if !reflect.DeepEqual(errs, want) {
+		t.Errorf("unexpected errors\n got %+v\nwant %+v", errs, want)
+	}
+}
+
+func TestStruct_StopsAtFirstBrokenRule(t *testing.T) {
+	req := testRequest{Name: "x", Quantity: 1, Tags: []string{"a", "", "c"}}
+
+	var errs Errors
+	if !errors.As(Struct(req), &errs) {
+		t.Fatal("expected Errors")
+	}
+	// Too many tags means the elements are not checked.
+	if len(errs) != 1 || errs[0].Field != "tags" || errs[0].Message != "tags must have at most 2 entries" {
+		t.Errorf("unexpected errors %+v", errs)
+	}
+}
+
+func TestStruct_PanicsOnBadTags(t *testing.T) {
+	type badRequest struct {
+		Count int `json:"count" validate:"min=lots"`
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for a malformed rule")
+		}
+	}()
+	_ = Struct(badRequest{})
+}
@@ -20,3 +20,10 @@ fi
 
 # Run the sync script
 .venv/bin/python sync_to_mongodb.py "$@"
+
+# Tell the API the item data changed so it can purge and re-warm its item cache
+if [ -n "${DATA_SYNC_WEBHOOK_URL:-}" ]; then
+    echo "Notifying API of data sync..."
+    curl -fsS -X POST -H "Authorization: Bearer ${DATA_SYNC_TOKEN:-}" "$DATA_SYNC_WEBHOOK_URL" \
+        || echo "Warning: data sync notification failed"
+fi
@@ -6,12 +6,15 @@ This script reads JSON files from the ./json directory and syncs them to MongoDB
 It handles:
 - Inserts: New items are added
 - Updates: Existing items are updated based on uniqueName
-- Deletes: Items in MongoDB that no longer exist in JSON are removed
+- Archives: Items in MongoDB that no longer exist in JSON are marked archived
+  (not deleted) so wishlists and owned blueprints that reference them still
+  resolve; an archived item that reappears is un-archived
 """
 
 import json
 import os
 import sys
+from datetime import datetime, timezone
 from pathlib import Path
 from typing import Any
 
@@ -54,7 +57,7 @@ def sync_collection(
     Uses uniqueName as the unique identifier for each document.
     Returns statistics about the sync operation.
     """
-    stats = {"inserted": 0, "updated": 0, "deleted": 0, "unchanged": 0}
+    stats = {"inserted": 0, "updated": 0, "archived": 0, "unchanged": 0}
 
     # Build a set of uniqueNames from the JSON data
     json_unique_names = set()
@@ -68,11 +71,13 @@ def sync_collection(
 
         json_unique_names.add(unique_name)
 
-        # Prepare upsert operation
+        # Prepare upsert operation, clearing any archived flag from a
+        # previous sync in case the item was re-added upstream
+        item = {k: v for k, v in item.items() if k not in ("archived", "archivedAt")}
         bulk_operations.append(
             UpdateOne(
                 {"uniqueName": unique_name},
-                {"$set": item},
+                {"$set": item, "$unset": {"archived": "", "archivedAt": ""}},
                 upsert=True
             )
         )
@@ -80,18 +85,21 @@ def sync_collection(
     if dry_run:
         # Count what would happen
         existing_docs = {
-            doc["uniqueName"]
-            for doc in collection.find({}, {"uniqueName": 1})
+            doc["uniqueName"]: doc.get("archived", False)
+            for doc in collection.find({}, {"uniqueName": 1, "archived": 1})
             if "uniqueName" in doc
         }
 
-        new_items = json_unique_names - existing_docs
-        to_delete = existing_docs - json_unique_names
-        to_update = existing_docs & json_unique_names
+        new_items = json_unique_names - existing_docs.keys()
+        to_archive = {
+            name for name, archived in existing_docs.items()
+            if name not in json_unique_names and not archived
+        }
+        to_update = existing_docs.keys() & json_unique_names
 
         stats["inserted"] = len(new_items)
         stats["updated"] = len(to_update)
-        stats["deleted"] = len(to_delete)
+        stats["archived"] = len(to_archive)
         return stats
 
     # Execute bulk upserts
@@ -101,17 +109,20 @@ def sync_collection(
         stats["updated"] = result.modified_count
         stats["unchanged"] = result.matched_count - result.modified_count
 
-    # Delete items no longer in JSON
+    # Archive items no longer in JSON instead of deleting them
     existing_unique_names = {
         doc["uniqueName"]
         for doc in collection.find({}, {"uniqueName": 1})
         if "uniqueName" in doc
     }
 
-    to_delete = existing_unique_names - json_unique_names
-    if to_delete:
-        delete_result = collection.delete_many({"uniqueName": {"$in": list(to_delete)}})
-        stats["deleted"] = delete_result.deleted_count
+    to_archive = existing_unique_names - json_unique_names
+    if to_archive:
+        archive_result = collection.update_many(
+            {"uniqueName": {"$in": list(to_archive)}, "archived": {"$ne": True}},
+            {"$set": {"archived": True, "archivedAt": datetime.now(timezone.utc)}}
+        )
+        stats["archived"] = archive_result.modified_count
 
     return stats
 
@@ -157,7 +168,7 @@ def sync_all(
             print(
                 f"inserted={stats['inserted']}, "
                 f"updated={stats['updated']}, "
-                f"deleted={stats['deleted']}, "
+                f"archived={stats['archived']}, "
                 f"unchanged={stats['unchanged']}"
             )
         except Exception as e:
@@ -172,7 +183,7 @@ def print_summary(stats: dict[str, dict[str, int]]) -> None:
     """Print a summary of all sync operations."""
     total_inserted = 0
     total_updated = 0
-    total_deleted = 0
+    total_archived = 0
     total_unchanged = 0
     errors = 0
 
@@ -182,7 +193,7 @@ def print_summary(stats: dict[str, dict[str, int]]) -> None:
         else:
             total_inserted += collection_stats.get("inserted", 0)
             total_updated += collection_stats.get("updated", 0)
-            total_deleted += collection_stats.get("deleted", 0)
+            total_archived += collection_stats.get("archived", 0)
             total_unchanged += collection_stats.get("unchanged", 0)
 
     print("\n" + "=" * 50)
@@ -191,7 +202,7 @@ def print_summary(stats: dict[str, dict[str, int]]) -> None:
     print(f"Collections processed: {len(stats)}")
     print(f"Total inserted: {total_inserted}")
     print(f"Total updated: {total_updated}")
-    print(f"Total deleted: {total_deleted}")
+    print(f"Total archived: {total_archived}")
     print(f"Total unchanged: {total_unchanged}")
     if errors:
         print(f"Errors: {errors}")